@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/deadletter"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adminRequeueLimit     int
+	adminSkillTop         int
+	adminExportOutput     string
+	adminExportPasswords  bool
+	adminImportIntoUserID string
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Operational maintenance commands",
+	Long: `Maintenance operations for operators: clearing cached crawl results, unsticking
+runs, resending failed webhook notifications, tidying the skill catalog, and moving
+users between deployments. Every subcommand connects to DATABASE_URL.`,
+}
+
+var adminPurgeCrawlCacheCmd = &cobra.Command{
+	Use:   "purge-crawl-cache <domain>",
+	Short: "Delete cached crawled pages for a domain and its subdomains",
+	Long: `Delete the crawled-page cache for a domain and its subdomains, so the next
+company research session fetches those pages again. Use this after a company
+redesigns its site or when cached pages hold bad content.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdminPurgeCrawlCache,
+}
+
+var adminResetRunCmd = &cobra.Command{
+	Use:   "reset-run <run-id>",
+	Short: "Fail a stuck queued or running run",
+	Long: `Mark a queued or running run that is no longer making progress as failed,
+along with its unfinished steps and any step jobs still waiting for a worker.
+Runs that already finished are left unchanged.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdminResetRun,
+}
+
+var adminRequeueWebhooksCmd = &cobra.Command{
+	Use:   "requeue-webhooks",
+	Short: "Resend webhook notifications whose delivery failed",
+	Long: `Resend notifications whose webhook delivery failed, oldest first, from the dead
+letter queue. Each goes to the user's current notification channel, so a user who
+has since fixed their webhook URL receives it there. Deliveries that fail again stay
+in the queue with their attempt count increased.`,
+	Args: cobra.NoArgs,
+	RunE: runAdminRequeueWebhooks,
+}
+
+var adminSkillStatsCmd = &cobra.Command{
+	Use:   "recompute-skill-stats",
+	Short: "Prune unused catalog skills and report skill usage",
+	Long: `Delete skills no bullet or user skill refers to any more, then count how many
+bullets use each remaining skill and print the most used.`,
+	Args: cobra.NoArgs,
+	RunE: runAdminSkillStats,
+}
+
+var adminExportUserCmd = &cobra.Command{
+	Use:   "export-user <user-id>",
+	Short: "Export a user and everything they own",
+	Long: `Write a user's account, experience bank, runs with their artifacts and
+application dates, and settings as JSON, for import-user on another deployment.
+The password hash is left out unless --include-password is given; it only works
+on a deployment with the same PASSWORD_PEPPER.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdminExportUser,
+}
+
+var adminImportUserCmd = &cobra.Command{
+	Use:   "import-user <file>",
+	Short: "Import a user exported from another deployment",
+	Long: `Load a file written by export-user (or downloaded from GET /v1/users/{id}/export)
+into this deployment, "-" reading standard input. Every row gets a new ID and the
+references between them are rewritten; skills are matched to this deployment's
+catalog by name, and links to shared job postings and company profiles are dropped.
+With --into the data is added to an existing account, for a user who has already
+registered here; otherwise a new account is created. Nothing is imported if any row
+fails, for example because the email address or a story ID is already taken.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdminImportUser,
+}
+
+func init() {
+	adminRequeueWebhooksCmd.Flags().IntVar(&adminRequeueLimit, "limit", 100, "Maximum deliveries to resend")
+	adminSkillStatsCmd.Flags().IntVar(&adminSkillTop, "top", 20, "Number of skills to print (0 for all)")
+	adminExportUserCmd.Flags().StringVarP(&adminExportOutput, "output", "o", "", "File to write (default standard output)")
+	adminExportUserCmd.Flags().BoolVar(&adminExportPasswords, "include-password", false, "Include the password hash")
+	adminImportUserCmd.Flags().StringVar(&adminImportIntoUserID, "into", "", "ID of an existing account to add the data to")
+	adminCmd.AddCommand(adminPurgeCrawlCacheCmd, adminResetRunCmd, adminRequeueWebhooksCmd, adminSkillStatsCmd,
+		adminExportUserCmd, adminImportUserCmd)
+	rootCmd.AddCommand(adminCmd)
+}
+
+// connectAdmin opens the database for an admin subcommand. The returned context is
+// canceled on SIGINT or SIGTERM.
+func connectAdmin() (context.Context, *db.DB, func(), error) {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		return nil, nil, nil, fmt.Errorf("DATABASE_URL environment variable is required")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	database, err := db.Connect(ctx, databaseURL)
+	if err != nil {
+		stop()
+		return nil, nil, nil, err
+	}
+	return ctx, database, func() { database.Close(); stop() }, nil
+}
+
+func runAdminPurgeCrawlCache(cmd *cobra.Command, args []string) error {
+	ctx, database, done, err := connectAdmin()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	purged, err := database.PurgeCrawledPages(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Purged %d cached pages for %s.\n", purged, args[0])
+	return nil
+}
+
+func runAdminResetRun(cmd *cobra.Command, args []string) error {
+	runID, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid run ID: %w", err)
+	}
+	ctx, database, done, err := connectAdmin()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	reset, err := database.ResetRun(ctx, runID)
+	if err != nil {
+		return err
+	}
+	if reset == nil {
+		return fmt.Errorf("run %s not found", runID)
+	}
+	out := cmd.OutOrStdout()
+	if !reset.Reset {
+		fmt.Fprintf(out, "Run %s is %s, not queued or running; nothing to reset.\n", runID, reset.PreviousStatus)
+		return nil
+	}
+	fmt.Fprintf(out, "Run %s reset from %s to failed (%d steps, %d step jobs failed).\n",
+		runID, reset.PreviousStatus, reset.Steps, reset.StepJobs)
+	return nil
+}
+
+func runAdminRequeueWebhooks(cmd *cobra.Command, _ []string) error {
+	if adminRequeueLimit < 1 {
+		return fmt.Errorf("--limit must be at least 1")
+	}
+	notifyCfg, err := config.NewNotificationConfig()
+	if err != nil {
+		return fmt.Errorf("failed to create notification config: %w", err)
+	}
+	ctx, database, done, err := connectAdmin()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	letters, err := database.ListDeadLetters(ctx, db.DeadLetterFilters{
+		Source: db.DeadLetterWebhook,
+		Status: db.DeadLetterPending,
+		Limit:  adminRequeueLimit,
+	})
+	if err != nil {
+		return err
+	}
+	notifier := notifications.New(notifyCfg)
+	out := cmd.OutOrStdout()
+	sent := 0
+	for i := range letters {
+		letter := &letters[i]
+		if _, err := deadletter.Retry(ctx, database, notifier, letter); err != nil {
+			fmt.Fprintf(out, "%s: %v\n", letter.RefKey, err)
+			continue
+		}
+		sent++
+	}
+	fmt.Fprintf(out, "Resent %d of %d failed webhook deliveries.\n", sent, len(letters))
+	return nil
+}
+
+func runAdminSkillStats(cmd *cobra.Command, _ []string) error {
+	if adminSkillTop < 0 {
+		return fmt.Errorf("--top must not be negative")
+	}
+	ctx, database, done, err := connectAdmin()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	pruned, err := database.PruneOrphanSkills(ctx)
+	if err != nil {
+		return err
+	}
+	usage, err := database.GetSkillUsageCount(ctx)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(usage))
+	for name := range usage {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if usage[names[i]] != usage[names[j]] {
+			return usage[names[i]] > usage[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if adminSkillTop > 0 && len(names) > adminSkillTop {
+		names = names[:adminSkillTop]
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Pruned %d unused skills; %d skills remain.\n", pruned, len(usage))
+	for _, name := range names {
+		fmt.Fprintf(out, "%6d  %s\n", usage[name], name)
+	}
+	return nil
+}
+
+func runAdminExportUser(cmd *cobra.Command, args []string) error {
+	userID, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %w", err)
+	}
+	ctx, database, done, err := connectAdmin()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	export, err := database.ExportUser(ctx, userID, adminExportPasswords)
+	if err != nil {
+		return err
+	}
+	if export == nil {
+		return fmt.Errorf("user %s not found", userID)
+	}
+
+	out := cmd.OutOrStdout()
+	if adminExportOutput != "" {
+		f, err := os.Create(adminExportOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", adminExportOutput, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if adminExportOutput != "" {
+		rows := 0
+		for _, table := range export.Tables {
+			rows += len(table.Rows)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Exported user %s (%d rows) to %s.\n", userID, rows, adminExportOutput)
+	}
+	return nil
+}
+
+func runAdminImportUser(cmd *cobra.Command, args []string) error {
+	var into *uuid.UUID
+	if adminImportIntoUserID != "" {
+		id, err := uuid.Parse(adminImportIntoUserID)
+		if err != nil {
+			return fmt.Errorf("invalid --into user ID: %w", err)
+		}
+		into = &id
+	}
+
+	in := cmd.InOrStdin()
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", args[0], err)
+		}
+		defer f.Close()
+		in = f
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return fmt.Errorf("failed to read export: %w", err)
+	}
+	var export db.UserExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return fmt.Errorf("invalid user export: %w", err)
+	}
+
+	ctx, database, done, err := connectAdmin()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	imported, err := database.ImportUser(ctx, &export, into)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Imported user %s as %s.\n", export.UserID, imported.UserID)
+	for _, table := range export.Tables {
+		if n, existing := imported.Rows[table.Name], imported.Existing[table.Name]; n > 0 || existing > 0 {
+			fmt.Fprintf(out, "%6d  %s", n, table.Name)
+			if existing > 0 {
+				fmt.Fprintf(out, " (%d already here)", existing)
+			}
+			fmt.Fprintln(out)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jonathan/resume-customizer/internal/perf"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark story ranking and plan selection on synthetic banks",
+	Long: `Measure RankStories and SelectPlan on synthetic experience banks of 1,000 to 5,000
+bullets and print the time, allocations, and memory of each call. Write CPU and heap
+profiles for go tool pprof with --cpuprofile and --memprofile.
+
+Save a run with --save and compare a later run on the same machine with --baseline;
+the command exits non-zero when an operation takes more time or allocations than its
+baseline by more than --tolerance.`,
+	Args: cobra.NoArgs,
+	RunE: runBench,
+}
+
+var (
+	benchSizes      []string
+	benchCPUProfile string
+	benchMemProfile string
+	benchSave       string
+	benchBaseline   string
+	benchTolerance  float64
+)
+
+func init() {
+	names := make([]string, 0, len(perf.Sizes))
+	for _, s := range perf.Sizes {
+		names = append(names, s.Name)
+	}
+	benchCmd.Flags().StringSliceVar(&benchSizes, "sizes", names, "Bank sizes to measure")
+	benchCmd.Flags().StringVar(&benchCPUProfile, "cpuprofile", "", "Write a CPU profile to this file")
+	benchCmd.Flags().StringVar(&benchMemProfile, "memprofile", "", "Write a heap profile to this file")
+	benchCmd.Flags().StringVar(&benchSave, "save", "", "Save the results as a baseline to this file")
+	benchCmd.Flags().StringVar(&benchBaseline, "baseline", "", "Compare the results with a saved baseline")
+	benchCmd.Flags().Float64Var(&benchTolerance, "tolerance", 0.25, "Slowdown or allocation growth allowed over the baseline (0.25 = 25%)")
+	rootCmd.AddCommand(benchCmd)
+}
+
+func runBench(_ *cobra.Command, _ []string) error {
+	sizes, err := perf.SizesByName(benchSizes)
+	if err != nil {
+		return err
+	}
+	if benchTolerance < 0 {
+		return fmt.Errorf("--tolerance must not be negative, got: %g", benchTolerance)
+	}
+	var baseline *perf.Report
+	if benchBaseline != "" {
+		if baseline, err = perf.LoadReport(benchBaseline); err != nil {
+			return err
+		}
+	}
+
+	if benchCPUProfile != "" {
+		f, err := os.Create(benchCPUProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+	}
+	report, err := perf.Run(sizes)
+	if benchCPUProfile != "" {
+		pprof.StopCPUProfile()
+	}
+	if err != nil {
+		return err
+	}
+	if benchMemProfile != "" {
+		if err := writeHeapProfile(benchMemProfile); err != nil {
+			return err
+		}
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "benchmark\tbullets\titerations\tms/op\tallocs/op\tMB/op\t")
+	for _, r := range report.Results {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%d\t%.2f\t\n", r.Name, r.Bullets, r.Iterations,
+			float64(r.NsPerOp)/1e6, r.AllocsPerOp, float64(r.BytesPerOp)/(1<<20))
+	}
+	_ = tw.Flush()
+
+	if benchSave != "" {
+		if err := perf.SaveReport(benchSave, report); err != nil {
+			return err
+		}
+	}
+	if baseline == nil {
+		return nil
+	}
+	if baseline.Platform != report.Platform || baseline.GoVersion != report.GoVersion {
+		fmt.Printf("note: baseline was saved with %s on %s; times may not be comparable\n", baseline.GoVersion, baseline.Platform)
+	}
+	regressions := perf.Compare(baseline, report, benchTolerance)
+	if len(regressions) == 0 {
+		fmt.Println("no regressions against", benchBaseline)
+		return nil
+	}
+	lines := make([]string, 0, len(regressions))
+	for _, r := range regressions {
+		lines = append(lines, "  "+r.String())
+	}
+	return fmt.Errorf("%d regressions against %s:\n%s", len(regressions), benchBaseline, strings.Join(lines, "\n"))
+}
+
+// writeHeapProfile writes the live heap after a collection, so it shows what the
+// measured calls retain as well as what they allocated
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create heap profile: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("failed to write heap profile: %w", err)
+	}
+	return nil
+}
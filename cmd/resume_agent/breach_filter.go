@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/jonathan/resume-customizer/internal/breach"
+	"github.com/spf13/cobra"
+)
+
+var breachFilterFalsePositiveRate float64
+
+var buildBreachFilterCmd = &cobra.Command{
+	Use:   "build-breach-filter <pwned-passwords.txt> <filter-file>",
+	Short: "Build the offline filter for the password breach check",
+	Long: `Build a Bloom filter of breached password hashes from a Pwned Passwords SHA-1
+download (one HASH:COUNT line per password). Point PASSWORD_BREACH_FILTER at the
+result so registration and password changes are still checked when the Pwned
+Passwords API is unreachable, or without the API at all. The filter's size grows
+with the number of hashes and falls with a higher --fp-rate.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBuildBreachFilter,
+}
+
+func init() {
+	buildBreachFilterCmd.Flags().Float64Var(&breachFilterFalsePositiveRate, "fp-rate", 0.001, "Share of unbreached passwords the filter wrongly rejects")
+	rootCmd.AddCommand(buildBreachFilterCmd)
+}
+
+func runBuildBreachFilter(_ *cobra.Command, args []string) error {
+	if breachFilterFalsePositiveRate <= 0 || breachFilterFalsePositiveRate >= 1 {
+		return fmt.Errorf("--fp-rate must be between 0 and 1, got: %g", breachFilterFalsePositiveRate)
+	}
+	filter, err := breach.BuildFilter(args[0], breachFilterFalsePositiveRate)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(args[1])
+	if err != nil {
+		return fmt.Errorf("failed to create filter file: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	size, err := filter.WriteTo(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write filter file: %w", err)
+	}
+	fmt.Printf("Wrote %s (%d bytes)\n", args[1], size)
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/toolchain"
+	"github.com/spf13/cobra"
+)
+
+var lintTemplateCmd = &cobra.Command{
+	Use:   "lint-template <file>",
+	Short: "Check a resume template before using it",
+	Long: `Check a LaTeX resume template for the placeholders resumes need, commands that
+are not allowed (shell escape, file access), packages that are used but not loaded or
+not installed, and page geometry that will not fit a one-page resume. Exits non-zero
+when the template has errors.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLintTemplate,
+}
+
+func init() {
+	rootCmd.AddCommand(lintTemplateCmd)
+}
+
+func runLintTemplate(_ *cobra.Command, args []string) error {
+	content, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+
+	var installed rendering.PackageChecker
+	if tools, err := toolchain.Default(); err == nil {
+		installed = tools.PackageInstalled
+	}
+
+	findings := rendering.LintTemplate(string(content), installed)
+	for _, f := range findings {
+		location := args[0]
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d", args[0], f.Line)
+		}
+		fmt.Printf("%s: %s: %s [%s]\n", location, f.Severity, f.Message, f.Rule)
+	}
+	if rendering.HasLintErrors(findings) {
+		return errors.New("template has errors")
+	}
+	if len(findings) == 0 {
+		fmt.Println("no problems found")
+	}
+	return nil
+}
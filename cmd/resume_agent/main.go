@@ -7,12 +7,24 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/logging"
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "resume_agent",
 	Short: "Resume Customizer HTTP API Server",
 	Long:  "Resume Customizer generates strictly formatted, one-page LaTeX resumes tailored to job postings and company brand voice via REST API.",
+	PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		// LOG_FORMAT and LOG_LEVEL choose JSON or human-readable logs for every command
+		cfg, err := config.NewLoggingConfig()
+		if err != nil {
+			return err
+		}
+		logging.Setup(cfg)
+		return nil
+	},
 }
 
 func main() {
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/rewriting"
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rewriteVariantCount int
+	rewriteKeywords     []string
+	rewriteTone         string
+)
+
+var rewriteBulletCmd = &cobra.Command{
+	Use:   "rewrite-bullet <bullet>",
+	Short: "Rewrite a single resume bullet a few ways",
+	Long: `Rewrite one bullet with the prompts runs use, printing two or three variants
+to choose from. Keywords and a tone steer the rewrites toward a job and company;
+without them the bullet is only tightened.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRewriteBullet,
+}
+
+func init() {
+	rewriteBulletCmd.Flags().IntVar(&rewriteVariantCount, "variants", rewriting.DefaultVariants, "Number of variants (2-3)")
+	rewriteBulletCmd.Flags().StringSliceVar(&rewriteKeywords, "keywords", nil, "Job keywords to align with, comma separated")
+	rewriteBulletCmd.Flags().StringVar(&rewriteTone, "tone", "", "Company tone to match, e.g. \"direct and technical\"")
+	rootCmd.AddCommand(rewriteBulletCmd)
+}
+
+func runRewriteBullet(_ *cobra.Command, args []string) error {
+	bullet := strings.TrimSpace(args[0])
+	if bullet == "" {
+		return fmt.Errorf("bullet is required")
+	}
+	if rewriteVariantCount < rewriting.MinVariants || rewriteVariantCount > rewriting.MaxVariants {
+		return fmt.Errorf("--variants must be between %d and %d", rewriting.MinVariants, rewriting.MaxVariants)
+	}
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" && llm.APIKeyRequired() {
+		return fmt.Errorf("GEMINI_API_KEY environment variable is required (or set LLM_PROVIDER=ollama for local models)")
+	}
+
+	var jobProfile *types.JobProfile
+	if len(rewriteKeywords) > 0 {
+		jobProfile = &types.JobProfile{Keywords: rewriteKeywords}
+	}
+	var companyProfile *types.CompanyProfile
+	if rewriteTone != "" {
+		companyProfile = &types.CompanyProfile{Tone: rewriteTone}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	variants, err := rewriting.RewriteVariants(ctx, bullet, jobProfile, companyProfile, rewriteVariantCount, apiKey)
+	if err != nil {
+		return err
+	}
+	for i, v := range variants {
+		fmt.Printf("%d. %s\n", i+1, v.FinalText)
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/jonathan/resume-customizer/internal/demo"
+	"github.com/jonathan/resume-customizer/internal/llm"
 	"github.com/jonathan/resume-customizer/internal/server"
 	"github.com/spf13/cobra"
 )
@@ -31,16 +33,31 @@ func runServe(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("DATABASE_URL environment variable is required")
 	}
 
+	// Demo mode runs on bundled data and recorded LLM responses, so no API key is needed
+	var fixtures *demo.Fixtures
+	if demo.Enabled() {
+		var err error
+		if fixtures, err = demo.Load(); err != nil {
+			return fmt.Errorf("failed to load demo data: %w", err)
+		}
+		fmt.Println("Demo mode: runs use bundled sample data and make no LLM or crawl requests")
+	}
+
 	// Get API key from environment
 	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		return fmt.Errorf("GEMINI_API_KEY environment variable is required")
+	if apiKey == "" && llm.APIKeyRequired() && fixtures == nil {
+		return fmt.Errorf("GEMINI_API_KEY environment variable is required (or set LLM_PROVIDER=ollama for local models, or DEMO_MODE=true)")
+	}
+
+	if err := checkToolchain(); err != nil {
+		return err
 	}
 
 	cfg := server.Config{
 		Port:        servePort,
 		DatabaseURL: databaseURL,
 		APIKey:      apiKey,
+		Demo:        fixtures,
 	}
 
 	srv, err := server.New(cfg)
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/star"
+	"github.com/spf13/cobra"
+)
+
+var (
+	starUserID string
+	starJobID  string
+)
+
+var starCmd = &cobra.Command{
+	Use:   "star",
+	Short: "Capture a new experience story in an interview",
+	Long: `Interview the user about one accomplishment, asking for the Situation, Task,
+Action, and Result in turn, then draft a story with bullets, metrics, and skills.
+The draft is shown for confirmation before it is written to the experience bank
+under the given job.`,
+	RunE: runStar,
+}
+
+func init() {
+	starCmd.Flags().StringVar(&starUserID, "user-id", "", "User whose experience bank receives the story")
+	starCmd.Flags().StringVar(&starJobID, "job-id", "", "Job the story is filed under")
+	_ = starCmd.MarkFlagRequired("user-id")
+	_ = starCmd.MarkFlagRequired("job-id")
+	rootCmd.AddCommand(starCmd)
+}
+
+func runStar(cmd *cobra.Command, _ []string) error {
+	userID, err := uuid.Parse(starUserID)
+	if err != nil {
+		return fmt.Errorf("invalid --user-id: %w", err)
+	}
+	jobID, err := uuid.Parse(starJobID)
+	if err != nil {
+		return fmt.Errorf("invalid --job-id: %w", err)
+	}
+
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		return fmt.Errorf("DATABASE_URL environment variable is required")
+	}
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" && llm.APIKeyRequired() {
+		return fmt.Errorf("GEMINI_API_KEY environment variable is required (or set LLM_PROVIDER=ollama for local models)")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	database, err := db.Connect(ctx, databaseURL)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	jobs, err := database.ListJobs(ctx, userID)
+	if err != nil {
+		return err
+	}
+	owned := false
+	for _, job := range jobs {
+		if job.ID == jobID {
+			owned = true
+			break
+		}
+	}
+	if !owned {
+		return errors.New("--job-id must be one of the user's jobs")
+	}
+
+	in := bufio.NewReader(cmd.InOrStdin())
+	out := cmd.OutOrStdout()
+
+	var answers star.Answers
+	for {
+		q, err := star.NextQuestion(ctx, &answers, apiKey)
+		if err != nil {
+			return err
+		}
+		if q == nil {
+			break
+		}
+		fmt.Fprintf(out, "\n%s\n> ", q.Question)
+		answer, err := readAnswer(in)
+		if err != nil {
+			return err
+		}
+		answers.Set(q.Stage, answer)
+	}
+
+	fmt.Fprintln(out, "\nDrafting your story...")
+	draft, err := star.DraftStory(ctx, &answers, apiKey)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n%s\n", draft.Title)
+	if draft.Description != "" {
+		fmt.Fprintf(out, "%s\n", draft.Description)
+	}
+	for _, b := range draft.Bullets {
+		fmt.Fprintf(out, "  - %s\n", b.Text)
+		if len(b.Skills) > 0 {
+			fmt.Fprintf(out, "    skills: %s\n", strings.Join(b.Skills, ", "))
+		}
+	}
+
+	fmt.Fprint(out, "\nSave this story to the experience bank? [y/N] ")
+	confirm, err := readAnswer(in)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(confirm, "y") && !strings.EqualFold(confirm, "yes") {
+		fmt.Fprintln(out, "Not saved.")
+		return nil
+	}
+
+	story, err := database.CreateStory(ctx, draft.StoryInput(userID, jobID))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Saved story %s with %d bullets.\n", story.StoryID, len(story.Bullets))
+	return nil
+}
+
+// readAnswer reads one line of input. A blank answer leaves its stage unanswered, so
+// the question is asked again.
+func readAnswer(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		return "", errors.New("input ended before the interview was complete")
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
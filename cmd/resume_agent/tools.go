@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jonathan/resume-customizer/internal/toolchain"
+	"github.com/spf13/cobra"
+)
+
+var toolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Show the external programs found on this machine",
+	Long: `List the LaTeX compiler, PDF page counters, and browser the server and workers
+will use, and which features are disabled because a program is missing. Programs are
+found on PATH and in the usual Linux, macOS, and Windows install locations; override
+them with LATEX_PATH, LATEX_ENGINE, CHROME_PATH, PDFINFO_PATH, and GHOSTSCRIPT_PATH.`,
+	RunE: runTools,
+}
+
+func init() {
+	rootCmd.AddCommand(toolsCmd)
+}
+
+func runTools(_ *cobra.Command, _ []string) error {
+	tools, err := toolchain.Default()
+	if err != nil {
+		return err
+	}
+	fmt.Print(tools)
+	for _, missing := range tools.Missing() {
+		fmt.Printf("missing:     %s\n", missing)
+	}
+	return nil
+}
+
+// checkToolchain fails on invalid toolchain overrides and logs the features that are
+// disabled because a program is missing
+func checkToolchain() error {
+	tools, err := toolchain.Default()
+	if err != nil {
+		return err
+	}
+	for _, missing := range tools.Missing() {
+		log.Printf("Warning: not found: %s", missing)
+	}
+	return nil
+}
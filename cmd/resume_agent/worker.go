@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/stepqueue"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workerSteps       string
+	workerConcurrency int
+	workerID          string
+	workerJobID       string
+)
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Run an out-of-process step worker",
+	Long: `Start a worker that executes heavy pipeline steps (company crawling, LaTeX
+compilation) claimed from the Postgres step queue. Set REMOTE_STEPS on the API
+server to dispatch those steps to workers.
+
+With --job-id the worker executes a single queued job and exits; this is how
+steps configured with the kubernetes backend run inside their Kubernetes Job.`,
+	RunE: runWorker,
+}
+
+func init() {
+	workerCmd.Flags().StringVar(&workerSteps, "steps", "", "Comma-separated steps to handle (default: all supported steps)")
+	workerCmd.Flags().IntVar(&workerConcurrency, "concurrency", 2, "Maximum jobs executed at once")
+	workerCmd.Flags().StringVar(&workerID, "id", "", "Worker identifier (default: hostname plus random suffix)")
+	workerCmd.Flags().StringVar(&workerJobID, "job-id", "", "Execute a single queued job by ID and exit")
+	rootCmd.AddCommand(workerCmd)
+}
+
+func runWorker(_ *cobra.Command, _ []string) error {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		return fmt.Errorf("DATABASE_URL environment variable is required")
+	}
+
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" && llm.APIKeyRequired() {
+		return fmt.Errorf("GEMINI_API_KEY environment variable is required (or set LLM_PROVIDER=ollama for local models)")
+	}
+
+	workerCfg, err := config.NewStepWorkerConfig()
+	if err != nil {
+		return fmt.Errorf("failed to create step worker config: %w", err)
+	}
+
+	if err := checkToolchain(); err != nil {
+		return err
+	}
+
+	handlers := stepqueue.Handlers(apiKey)
+	if workerSteps != "" {
+		selected := make(map[string]stepqueue.Handler)
+		for _, step := range strings.Split(workerSteps, ",") {
+			step = strings.TrimSpace(step)
+			handler, ok := handlers[step]
+			if !ok {
+				return fmt.Errorf("unsupported worker step: %s", step)
+			}
+			selected[step] = handler
+		}
+		handlers = selected
+	}
+
+	if workerID == "" {
+		host, _ := os.Hostname()
+		workerID = fmt.Sprintf("%s-%s", host, uuid.NewString()[:8])
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	database, err := db.Connect(ctx, databaseURL)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	worker := stepqueue.NewWorker(database, workerID, handlers, workerConcurrency, workerCfg.StaleAfter())
+	if workerJobID != "" {
+		jobID, err := uuid.Parse(workerJobID)
+		if err != nil {
+			return fmt.Errorf("invalid --job-id: %w", err)
+		}
+		slog.Info("Step worker executing job", "worker_id", workerID, "job_id", jobID)
+		return worker.RunJob(ctx, jobID)
+	}
+	slog.Info("Step worker started", "worker_id", workerID, "steps", worker.Steps(), "concurrency", workerConcurrency)
+
+	if err := worker.Run(ctx); err != nil {
+		return err
+	}
+	slog.Info("Step worker stopped", "worker_id", workerID)
+	return nil
+}
@@ -10,12 +10,37 @@ SQL_FILES=(
     "users.sql"
     "companies.sql"
     "company_profiles.sql"
+    "company_profile_versions.sql"
     "job_postings.sql"
+    "job_posting_duplicates.sql"
+    "requirement_weights.sql"
+    "keyword_sources.sql"
     "experience_bank.sql"
+    "experience_embeddings.sql"
+    "role_titles.sql"
+    "job_posting_versions.sql"
+    "github.sql"
     "pipeline_artifacts.sql"
     "research.sql"
     "resumes.sql"
+    "bullet_suggestions.sql"
+    "voice_notes.sql"
+    "company_preferences.sql"
+    "user_company_profiles.sql"
+    "run_recipes.sql"
+    "application_trackers.sql"
+    "resume_templates.sql"
     "run_steps.sql"
+    "shared_resumes.sql"
+    "workspaces.sql"
+    "step_jobs.sql"
+    "run_jobs.sql"
+    "dead_letters.sql"
+    "refresh_tokens.sql"
+    "email_verifications.sql"
+    "idempotency_keys.sql"
+    "webhooks.sql"
+    "prompt_rollouts.sql"
 )
 
 # Apply each SQL file to the resume database
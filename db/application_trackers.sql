@@ -0,0 +1,72 @@
+-- Application Trackers Schema
+-- Depends on: users.sql
+
+-- =============================================================================
+-- APPLICATION TRACKERS TABLE
+-- =============================================================================
+
+-- What a user knows about their applications to one company over a long search: free
+-- notes, the next thing they mean to do, and when. Users opted in to application
+-- reminders are reminded of the next action like a run's follow-up date.
+CREATE TABLE IF NOT EXISTS application_trackers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    company TEXT NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    next_action TEXT,                   -- e.g. "Send thank-you note to the hiring manager"
+    next_action_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- APPLICATION CONTACTS TABLE
+-- =============================================================================
+
+-- People at the company: recruiters, hiring managers, referrers
+CREATE TABLE IF NOT EXISTS application_contacts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tracker_id UUID NOT NULL REFERENCES application_trackers(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    role TEXT,                          -- e.g. "Recruiter"
+    email TEXT,
+    linkedin_url TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- APPLICATION INTERACTIONS TABLE
+-- =============================================================================
+
+-- The log of calls, emails, and interviews, optionally with one of the contacts
+CREATE TABLE IF NOT EXISTS application_interactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tracker_id UUID NOT NULL REFERENCES application_trackers(id) ON DELETE CASCADE,
+    contact_id UUID REFERENCES application_contacts(id) ON DELETE SET NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('email', 'call', 'message', 'interview', 'offer', 'rejection', 'note')),
+    occurred_at TIMESTAMPTZ NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+-- One tracker per company per user, whatever its capitalization
+CREATE UNIQUE INDEX IF NOT EXISTS idx_application_trackers_user_company
+    ON application_trackers(user_id, lower(company));
+CREATE INDEX IF NOT EXISTS idx_application_trackers_next_action
+    ON application_trackers(next_action_at) WHERE next_action_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_application_contacts_tracker ON application_contacts(tracker_id);
+CREATE INDEX IF NOT EXISTS idx_application_interactions_tracker
+    ON application_interactions(tracker_id, occurred_at DESC);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE application_trackers IS 'Per-company application notes and next action, reminded like run follow-up dates';
+COMMENT ON TABLE application_contacts IS 'Recruiters and other people a user deals with at a tracked company';
+COMMENT ON TABLE application_interactions IS 'Dated log of a user''s interactions with a tracked company';
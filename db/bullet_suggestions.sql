@@ -0,0 +1,39 @@
+-- Bullet Suggestions Schema
+-- Depends on: users.sql, experience_bank.sql (stories), resumes.sql (pipeline_runs)
+
+-- =============================================================================
+-- BULLET SUGGESTIONS TABLE
+-- =============================================================================
+
+-- Bullets drafted for job requirements the user's experience bank does not cover,
+-- waiting for the user to confirm and accept or dismiss. Never placed on a resume.
+CREATE TABLE IF NOT EXISTS bullet_suggestions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    run_id UUID REFERENCES pipeline_runs(id) ON DELETE SET NULL,  -- run that last suggested it
+    skill TEXT NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('hard_requirement', 'nice_to_have')),
+    level TEXT,
+    evidence TEXT,                      -- quote from the job posting
+    question TEXT NOT NULL,             -- e.g. 'Did you work with Kubernetes?'
+    text TEXT NOT NULL,                 -- drafted bullet with blanks to fill in
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'dismissed')),
+    story_id UUID REFERENCES stories(id) ON DELETE SET NULL,  -- story created on acceptance
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+-- One suggestion per skill per user, whatever its capitalization
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bullet_suggestions_user_skill ON bullet_suggestions(user_id, lower(skill));
+CREATE INDEX IF NOT EXISTS idx_bullet_suggestions_user_status ON bullet_suggestions(user_id, status);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE bullet_suggestions IS 'Bullets drafted for uncovered job requirements, pending review';
+COMMENT ON COLUMN bullet_suggestions.status IS 'pending until the user accepts it into the experience bank or dismisses it';
@@ -46,6 +46,9 @@ CREATE TABLE IF NOT EXISTS crawled_pages (
     is_permanent_failure BOOLEAN DEFAULT FALSE,  -- true = never retry (404, 410, etc.)
     retry_count INTEGER DEFAULT 0,  -- number of failed attempts
     retry_after TIMESTAMPTZ,        -- don't retry before this time
+    -- Provenance
+    source_type TEXT NOT NULL DEFAULT 'live',  -- 'live', or 'archive' for an Internet Archive snapshot
+    snapshot_url TEXT,              -- Wayback Machine snapshot the content came from
     -- Timestamps
     fetched_at TIMESTAMPTZ DEFAULT NOW(),
     expires_at TIMESTAMPTZ,         -- TTL for cache invalidation
@@ -54,6 +57,27 @@ CREATE TABLE IF NOT EXISTS crawled_pages (
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
 
+-- Migration: Add page provenance (if table already exists)
+-- ALTER TABLE crawled_pages ADD COLUMN IF NOT EXISTS source_type TEXT NOT NULL DEFAULT 'live';
+-- ALTER TABLE crawled_pages ADD COLUMN IF NOT EXISTS snapshot_url TEXT;
+
+-- =============================================================================
+-- COMPANY TECH STACK TABLE
+-- =============================================================================
+
+-- Technologies a company mentions in its job postings and engineering blog
+CREATE TABLE IF NOT EXISTS company_tech_stack (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    technology TEXT NOT NULL,       -- canonical name, e.g. 'Go', 'Kubernetes', 'Kafka'
+    category TEXT,                  -- 'language', 'infrastructure', 'cloud', 'data', 'database', ...
+    source TEXT NOT NULL,           -- 'job_posting' or 'blog'
+    mentions INTEGER NOT NULL DEFAULT 0,
+    first_seen_at TIMESTAMPTZ DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE(company_id, technology, source)
+);
+
 -- =============================================================================
 -- INDEXES
 -- =============================================================================
@@ -75,6 +99,9 @@ CREATE INDEX IF NOT EXISTS idx_crawled_pages_fetched ON crawled_pages(fetched_at
 CREATE INDEX IF NOT EXISTS idx_crawled_pages_status ON crawled_pages(fetch_status);
 CREATE INDEX IF NOT EXISTS idx_crawled_pages_permanent_fail ON crawled_pages(is_permanent_failure) WHERE is_permanent_failure = TRUE;
 
+-- Tech stack lookups
+CREATE INDEX IF NOT EXISTS idx_company_tech_stack_company ON company_tech_stack(company_id);
+
 -- =============================================================================
 -- COMMENTS
 -- =============================================================================
@@ -82,6 +109,7 @@ CREATE INDEX IF NOT EXISTS idx_crawled_pages_permanent_fail ON crawled_pages(is_
 COMMENT ON TABLE companies IS 'Canonical company records, deduplicated by normalized name';
 COMMENT ON TABLE company_domains IS 'All domains associated with a company';
 COMMENT ON TABLE crawled_pages IS 'Cached web pages with TTL for reuse';
+COMMENT ON TABLE company_tech_stack IS 'Technologies mentioned across a company''s postings and blog';
 
 COMMENT ON COLUMN companies.name_normalized IS 'Lowercase alphanumeric name for deduplication';
 COMMENT ON COLUMN crawled_pages.content_hash IS 'SHA-256 hash for detecting page changes';
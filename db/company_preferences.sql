@@ -0,0 +1,36 @@
+-- Company Preferences Schema
+-- Depends on: users.sql
+
+-- =============================================================================
+-- COMPANY PREFERENCES TABLE
+-- =============================================================================
+
+-- Named tailoring presets a user saves for a target company, so repeated applications
+-- to the same employer reuse them. A run started with a preset takes every option the
+-- request leaves unset from it.
+CREATE TABLE IF NOT EXISTS company_preferences (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    company TEXT NOT NULL,
+    name TEXT NOT NULL,
+    tone_override TEXT,                         -- replaces the researched company tone
+    pinned_bullets TEXT[] NOT NULL DEFAULT '{}', -- bullet IDs always placed on the resume
+    template TEXT,
+    section_order TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+-- One preset per name per company per user, whatever their capitalization
+CREATE UNIQUE INDEX IF NOT EXISTS idx_company_preferences_user_company_name
+    ON company_preferences(user_id, lower(company), lower(name));
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE company_preferences IS 'Named per-company tailoring presets merged into run options';
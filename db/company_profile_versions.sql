@@ -0,0 +1,55 @@
+-- Company Profile Version Archive Schema
+-- Depends on: companies.sql, company_profiles.sql
+-- Keeps every version of a company profile as it was generated, so regenerating a
+-- profile no longer loses the tone, values, and taboo phrases it replaced.
+
+-- =============================================================================
+-- COMPANY PROFILE VERSIONS
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS company_profile_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    profile_id UUID NOT NULL REFERENCES company_profiles(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    tone TEXT NOT NULL,
+    domain_context TEXT,
+    style_rules JSONB NOT NULL DEFAULT '[]',   -- ["Lead with impact", ...], highest priority first
+    taboo_phrases JSONB NOT NULL DEFAULT '[]', -- [{"phrase": "rockstar", "reason": "..."}, ...]
+    core_values JSONB NOT NULL DEFAULT '[]',   -- ["Customer obsession", ...], highest priority first
+    evidence_urls JSONB NOT NULL DEFAULT '[]', -- ["https://example.com/values", ...]
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(company_id, version)
+);
+
+-- Profiles generated before versions were kept start their history at their current version
+INSERT INTO company_profile_versions (company_id, profile_id, version, tone, domain_context,
+                                      style_rules, taboo_phrases, core_values, evidence_urls, created_at)
+SELECT p.company_id, p.id, COALESCE(p.version, 1), p.tone, p.domain_context,
+       COALESCE((SELECT jsonb_agg(r.rule_text ORDER BY r.priority DESC)
+                 FROM company_style_rules r WHERE r.profile_id = p.id), '[]'::jsonb),
+       COALESCE((SELECT jsonb_agg(jsonb_strip_nulls(jsonb_build_object('phrase', t.phrase, 'reason', t.reason))
+                                  ORDER BY t.created_at)
+                 FROM company_taboo_phrases t WHERE t.profile_id = p.id), '[]'::jsonb),
+       COALESCE((SELECT jsonb_agg(v.value_text ORDER BY v.priority DESC)
+                 FROM company_values v WHERE v.profile_id = p.id), '[]'::jsonb),
+       COALESCE((SELECT jsonb_agg(s.url ORDER BY s.created_at)
+                 FROM company_profile_sources s WHERE s.profile_id = p.id), '[]'::jsonb),
+       COALESCE(p.updated_at, NOW())
+FROM company_profiles p
+ON CONFLICT (company_id, version) DO NOTHING;
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_company_profile_versions_company ON company_profile_versions(company_id, version DESC);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE company_profile_versions IS 'Immutable snapshots of each generated company profile version';
+COMMENT ON COLUMN company_profile_versions.version IS 'Matches company_profiles.version when the snapshot was generated';
+COMMENT ON COLUMN company_profile_versions.taboo_phrases IS 'Phrases to avoid, with the reason when one was given';
+COMMENT ON COLUMN company_profile_versions.core_values IS 'Company values, highest priority first';
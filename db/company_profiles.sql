@@ -75,7 +75,7 @@ CREATE TABLE IF NOT EXISTS company_profile_sources (
     profile_id UUID NOT NULL REFERENCES company_profiles(id) ON DELETE CASCADE,
     crawled_page_id UUID REFERENCES crawled_pages(id) ON DELETE SET NULL,
     url TEXT NOT NULL,                  -- original URL (may not be in crawled_pages)
-    source_type TEXT,                   -- 'values', 'culture', 'about', 'engineering', etc.
+    source_type TEXT,                   -- 'values', 'culture', 'about', 'engineering', 'archive', etc.
     created_at TIMESTAMPTZ DEFAULT NOW()
 );
 
@@ -0,0 +1,38 @@
+-- Dead Letters Schema
+-- Depends on: users.sql, resumes.sql (pipeline_runs)
+
+-- =============================================================================
+-- DEAD LETTERS TABLE
+-- =============================================================================
+
+-- Background work that failed and will not be retried on its own: notifications whose
+-- webhook or email delivery failed, and step jobs a worker could not complete. Items
+-- wait here until an operator retries or discards them.
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source TEXT NOT NULL CHECK (source IN ('webhook', 'email', 'step_job')),
+    ref_key TEXT NOT NULL,              -- The failed item within its source, e.g. 'run_completed:<run id>' or a step job ID
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    run_id UUID REFERENCES pipeline_runs(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,             -- What a retry needs: the notification message, or the step job's step
+    error_message TEXT NOT NULL,        -- Error from the latest attempt
+    attempts INTEGER NOT NULL DEFAULT 1,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'retried', 'discarded')),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    last_failed_at TIMESTAMPTZ DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    UNIQUE (source, ref_key)
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_pending ON dead_letters(source, last_failed_at) WHERE status = 'pending';
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE dead_letters IS 'Failed background work held for an operator to retry or discard';
+COMMENT ON COLUMN dead_letters.status IS 'pending until retried or discarded; failing again after a retry makes it pending with one more attempt';
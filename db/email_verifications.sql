@@ -0,0 +1,41 @@
+-- Email Verifications Schema
+-- Depends on: users.sql (users)
+-- Verification links emailed at registration. Accounts cannot start pipeline runs until
+-- a link sent to their current address is followed.
+
+-- =============================================================================
+-- USERS COLUMNS
+-- =============================================================================
+
+-- Defaults to NOW() so existing accounts, and accounts created outside registration,
+-- count as verified; registration clears it until the link is followed
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ DEFAULT NOW();
+
+-- =============================================================================
+-- EMAIL VERIFICATIONS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS email_verifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_email_verifications_user ON email_verifications(user_id, created_at DESC);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE email_verifications IS 'Verification links sent by POST /v1/auth/register and POST /v1/auth/verify-email/resend';
+COMMENT ON COLUMN email_verifications.email IS 'Address the link was sent to; the link only verifies the account while this is still its address';
+COMMENT ON COLUMN email_verifications.token_hash IS 'Hex SHA-256 of the token; the token itself is never stored';
+COMMENT ON COLUMN users.email_verified_at IS 'When the user followed a verification link; NULL while verification is pending';
@@ -65,6 +65,19 @@ CREATE TABLE IF NOT EXISTS bullet_skills (
     PRIMARY KEY (bullet_id, skill_id)
 );
 
+-- =============================================================================
+-- USER SKILLS (Per-user self-assessment of catalog skills)
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS user_skills (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    skill_id UUID NOT NULL REFERENCES skills(id) ON DELETE CASCADE,
+    proficiency SMALLINT CHECK (proficiency BETWEEN 1 AND 5),  -- self-assessed, 1 (basic) to 5 (expert)
+    last_used DATE,                        -- self-reported; NULL derives it from the jobs using the skill
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (user_id, skill_id)
+);
+
 -- =============================================================================
 -- EDUCATION HIGHLIGHTS (Many-to-one: highlights -> education)
 -- =============================================================================
@@ -100,6 +113,9 @@ CREATE INDEX IF NOT EXISTS idx_bullets_evidence ON bullets(evidence_strength);
 CREATE INDEX IF NOT EXISTS idx_bullet_skills_skill ON bullet_skills(skill_id);
 CREATE INDEX IF NOT EXISTS idx_bullet_skills_bullet ON bullet_skills(bullet_id);
 
+-- User skills lookups
+CREATE INDEX IF NOT EXISTS idx_user_skills_skill ON user_skills(skill_id);
+
 -- Education highlights lookups
 CREATE INDEX IF NOT EXISTS idx_education_highlights_education ON education_highlights(education_id);
 
@@ -111,6 +127,7 @@ COMMENT ON TABLE skills IS 'Normalized skill catalog for experience matching';
 COMMENT ON TABLE stories IS 'Groups of related experience bullets (projects/initiatives)';
 COMMENT ON TABLE bullets IS 'Individual experience bullet points linked to stories';
 COMMENT ON TABLE bullet_skills IS 'Many-to-many relationship between bullets and skills';
+COMMENT ON TABLE user_skills IS 'Per-user proficiency and recency of catalog skills';
 COMMENT ON TABLE education_highlights IS 'Notable achievements for education entries';
 
 COMMENT ON COLUMN skills.name_normalized IS 'Lowercase skill name for case-insensitive matching';
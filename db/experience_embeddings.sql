@@ -0,0 +1,28 @@
+-- Experience Embeddings Schema
+-- Depends on: users.sql (experiences)
+-- Embedding vectors for experience bullets, used to rank stories by semantic similarity
+-- (RANKING_MODE=embeddings). Requires the pgvector extension (the pgvector/pgvector image).
+
+-- =============================================================================
+-- EXTENSIONS
+-- =============================================================================
+
+CREATE EXTENSION IF NOT EXISTS vector;
+
+-- =============================================================================
+-- EXPERIENCE EMBEDDING COLUMNS
+-- =============================================================================
+
+-- Dimensionless so vectors from any embedding model fit; they are only compared with
+-- vectors from the same model
+ALTER TABLE experiences ADD COLUMN IF NOT EXISTS embedding vector;
+ALTER TABLE experiences ADD COLUMN IF NOT EXISTS embedding_model TEXT;
+ALTER TABLE experiences ADD COLUMN IF NOT EXISTS embedding_text_md5 TEXT;
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON COLUMN experiences.embedding IS 'Embedding of bullet_text for semantic story ranking';
+COMMENT ON COLUMN experiences.embedding_model IS 'Embedding model that produced embedding';
+COMMENT ON COLUMN experiences.embedding_text_md5 IS 'md5 of the bullet_text that was embedded; a mismatch means the bullet was edited since';
@@ -0,0 +1,52 @@
+-- GitHub Integration Schema
+-- Depends on: users.sql, experience_bank.sql (stories)
+
+-- =============================================================================
+-- GITHUB ACCOUNTS TABLE
+-- =============================================================================
+
+-- The GitHub profile a user imports projects from (one per user)
+CREATE TABLE IF NOT EXISTS github_accounts (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    username TEXT NOT NULL,
+    token_sealed TEXT,                  -- AES-GCM encrypted access token; never returned by the API
+    last_synced_at TIMESTAMPTZ,         -- last repository fetch, for per-user rate limiting
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- PROJECT DRAFTS TABLE
+-- =============================================================================
+
+-- Bullets drafted from showcased repositories, waiting for the user to accept or dismiss
+CREATE TABLE IF NOT EXISTS project_drafts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    repo_name TEXT NOT NULL,
+    repo_url TEXT NOT NULL,
+    description TEXT,
+    language TEXT,
+    stars INTEGER NOT NULL DEFAULT 0,
+    skills JSONB DEFAULT '[]',          -- language and topics, linked on acceptance
+    text TEXT NOT NULL,                 -- drafted bullet
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'dismissed')),
+    story_id UUID REFERENCES stories(id) ON DELETE SET NULL,  -- story created on acceptance
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE(user_id, repo_url)
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_project_drafts_user_status ON project_drafts(user_id, status);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE github_accounts IS 'GitHub profiles users import project bullets from';
+COMMENT ON TABLE project_drafts IS 'Project bullets drafted from GitHub repositories, pending review';
+COMMENT ON COLUMN project_drafts.status IS 'pending until the user accepts it into the experience bank or dismisses it';
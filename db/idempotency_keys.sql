@@ -0,0 +1,33 @@
+-- Idempotency Keys Schema
+-- Depends on: users.sql (users)
+-- Responses to POST /v1/runs requests sent with an Idempotency-Key header, so a client
+-- retrying a request gets the original run back instead of a duplicate
+
+-- =============================================================================
+-- IDEMPOTENCY KEYS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,  -- Hex SHA-256 of the request body
+    status_code INTEGER,                -- NULL while the first request is in progress
+    response JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires_at);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE idempotency_keys IS 'Stored responses replayed for repeated requests with the same Idempotency-Key';
+COMMENT ON COLUMN idempotency_keys.status_code IS 'Status of the stored response; NULL while the request holding the key runs';
+COMMENT ON COLUMN idempotency_keys.expires_at IS 'After this the key may be reused for a new request; run garbage collection deletes expired keys';
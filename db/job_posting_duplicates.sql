@@ -0,0 +1,29 @@
+-- Job Posting De-duplication Schema
+-- Depends on: job_postings.sql
+-- Links postings of the same role found on several platforms (Greenhouse, LinkedIn, the
+-- company site) to one canonical posting, so runs for any of them share parsing and research.
+
+-- =============================================================================
+-- DUPLICATE POSTING COLUMNS
+-- =============================================================================
+
+ALTER TABLE job_postings ADD COLUMN IF NOT EXISTS normalized_hash TEXT;
+ALTER TABLE job_postings ADD COLUMN IF NOT EXISTS canonical_posting_id UUID REFERENCES job_postings(id) ON DELETE SET NULL;
+ALTER TABLE job_postings ADD COLUMN IF NOT EXISTS duplicate_match TEXT;      -- 'content_hash' or 'fuzzy'
+ALTER TABLE job_postings ADD COLUMN IF NOT EXISTS duplicate_similarity DOUBLE PRECISION; -- 0-1, 1 for content hash matches
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_job_postings_normalized_hash ON job_postings(normalized_hash);
+CREATE INDEX IF NOT EXISTS idx_job_postings_canonical ON job_postings(canonical_posting_id);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON COLUMN job_postings.normalized_hash IS 'SHA-256 of the cleaned text with case, punctuation, and spacing removed';
+COMMENT ON COLUMN job_postings.canonical_posting_id IS 'Posting this one duplicates; NULL for canonical postings';
+COMMENT ON COLUMN job_postings.duplicate_match IS 'How the duplicate was found: content_hash or fuzzy';
+COMMENT ON COLUMN job_postings.duplicate_similarity IS 'Text similarity to the canonical posting (0-1)';
@@ -0,0 +1,41 @@
+-- Job Posting Version Archive Schema
+-- Depends on: job_postings.sql, role_titles.sql
+-- Keeps each version of a posting that a re-fetch replaced, with the requirements and
+-- keywords parsed from it, so a company's hiring trends can be read over time.
+
+-- =============================================================================
+-- JOB POSTING VERSIONS
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS job_posting_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    posting_id UUID NOT NULL REFERENCES job_postings(id) ON DELETE CASCADE,
+    company_id UUID REFERENCES companies(id) ON DELETE SET NULL,
+    url TEXT NOT NULL,
+    role_title TEXT,
+    role_family TEXT,
+    role_level TEXT,
+    cleaned_text TEXT,
+    content_hash TEXT,
+    requirements JSONB NOT NULL DEFAULT '[]', -- [{"type": "hard", "skill": "Go"}, ...]
+    keywords JSONB NOT NULL DEFAULT '[]',     -- ["kubernetes", ...]
+    first_seen_at TIMESTAMPTZ NOT NULL,
+    superseded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_job_posting_versions_posting ON job_posting_versions(posting_id, superseded_at);
+CREATE INDEX IF NOT EXISTS idx_job_posting_versions_company ON job_posting_versions(company_id, role_family, first_seen_at);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE job_posting_versions IS 'Superseded versions of job postings, archived when a re-fetch changes their content';
+COMMENT ON COLUMN job_posting_versions.requirements IS 'Requirements parsed from this version; nice-to-haves have type nice_to_have';
+COMMENT ON COLUMN job_posting_versions.keywords IS 'Keywords extracted from this version, before any user changes';
+COMMENT ON COLUMN job_posting_versions.first_seen_at IS 'When this version was first fetched';
+COMMENT ON COLUMN job_posting_versions.superseded_at IS 'When a re-fetch replaced this version';
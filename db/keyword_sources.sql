@@ -0,0 +1,33 @@
+-- Keyword Sources Schema
+-- Depends on: users.sql, job_postings.sql
+-- Records where each job keyword came from and lets users add keywords the parser missed
+-- or hide ones it got wrong. Their changes apply only to their own runs.
+
+-- =============================================================================
+-- JOB KEYWORDS COLUMNS
+-- =============================================================================
+
+-- Set on rows a user added or hid; rows shared by everyone who sees the posting have none
+ALTER TABLE job_keywords ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+-- True when the user hid the shared keyword of the same name rather than adding one
+ALTER TABLE job_keywords ADD COLUMN IF NOT EXISTS excluded BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE job_keywords ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ DEFAULT NOW();
+
+-- Keywords stored before sources were tracked all came from the LLM parser
+UPDATE job_keywords SET source = 'llm' WHERE source IS NULL AND user_id IS NULL;
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+-- One row per user and keyword, so adding and hiding replace each other
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_keywords_user
+    ON job_keywords(job_profile_id, user_id, keyword_normalized) WHERE user_id IS NOT NULL;
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON COLUMN job_keywords.source IS 'Where the keyword came from: llm (parsed from the posting), jsonld (the posting''s structured data), or user';
+COMMENT ON COLUMN job_keywords.user_id IS 'User who added or hid the keyword; NULL for keywords shared by all users';
+COMMENT ON COLUMN job_keywords.excluded IS 'True when the user hid the shared keyword of the same name from their runs';
@@ -31,8 +31,142 @@ BEGIN
                    WHERE table_name = 'pipeline_runs' AND column_name = 'company_profile_id') THEN
         ALTER TABLE pipeline_runs ADD COLUMN company_profile_id UUID REFERENCES company_profiles(id) ON DELETE SET NULL;
     END IF;
+
+    -- Add priority if it doesn't exist (scheduling class: interactive, normal, bulk)
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns 
+                   WHERE table_name = 'pipeline_runs' AND column_name = 'priority') THEN
+        ALTER TABLE pipeline_runs ADD COLUMN priority VARCHAR(20) NOT NULL DEFAULT 'normal'
+            CHECK (priority IN ('interactive', 'normal', 'bulk'));
+    END IF;
+
+    -- Add application deadline and follow-up dates (calendar feed and reminders)
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns 
+                   WHERE table_name = 'pipeline_runs' AND column_name = 'deadline_at') THEN
+        ALTER TABLE pipeline_runs ADD COLUMN deadline_at TIMESTAMPTZ;
+        ALTER TABLE pipeline_runs ADD COLUMN follow_up_at TIMESTAMPTZ;
+    END IF;
+
+    -- Add abandoned_at (set when run garbage collection gives up on a never-executed run)
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns 
+                   WHERE table_name = 'pipeline_runs' AND column_name = 'abandoned_at') THEN
+        ALTER TABLE pipeline_runs ADD COLUMN abandoned_at TIMESTAMPTZ;
+    END IF;
+
+    -- Add run_type and source_run_id (cover-letter and refresh runs reuse a source run's artifacts)
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns 
+                   WHERE table_name = 'pipeline_runs' AND column_name = 'run_type') THEN
+        ALTER TABLE pipeline_runs ADD COLUMN run_type VARCHAR(20) NOT NULL DEFAULT 'full'
+            CHECK (run_type IN ('full', 'cover_letter', 'refresh'));
+        ALTER TABLE pipeline_runs ADD COLUMN source_run_id UUID REFERENCES pipeline_runs(id) ON DELETE SET NULL;
+    END IF;
+
+    -- Add max_pages (pages the resume may fill; steps executed one at a time default to it)
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns 
+                   WHERE table_name = 'pipeline_runs' AND column_name = 'max_pages') THEN
+        ALTER TABLE pipeline_runs ADD COLUMN max_pages INT CHECK (max_pages BETWEEN 1 AND 2);
+    END IF;
 END $$;
 
+CREATE INDEX IF NOT EXISTS idx_pipeline_runs_deadline ON pipeline_runs(deadline_at) WHERE deadline_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pipeline_runs_follow_up ON pipeline_runs(follow_up_at) WHERE follow_up_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pipeline_runs_abandoned ON pipeline_runs(abandoned_at) WHERE abandoned_at IS NOT NULL;
+
+-- =============================================================================
+-- ARTIFACTS UPDATES
+-- =============================================================================
+
+-- Binary artifacts such as the compiled resume PDF (resume_pdf)
+ALTER TABLE artifacts ADD COLUMN IF NOT EXISTS binary_content BYTEA;
+
+-- Each save of a run's step adds a version instead of overwriting the last one, so
+-- repair iterations and step reruns stay auditable
+ALTER TABLE artifacts ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE artifacts DROP CONSTRAINT IF EXISTS artifacts_run_id_step_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artifacts_run_step_version ON artifacts(run_id, step, version);
+
+-- =============================================================================
+-- ARTIFACT BLOBS TABLE
+-- =============================================================================
+
+-- Artifact payloads stored by content address. Runs often store the same posting text,
+-- research corpus, or template, so each distinct payload is kept once and artifacts
+-- refer to it by hash.
+CREATE TABLE IF NOT EXISTS artifact_blobs (
+    hash TEXT PRIMARY KEY,                 -- sha256 hex of "<format>:" followed by the payload
+    format TEXT NOT NULL CHECK (format IN ('json', 'text', 'binary')),
+    content JSONB,
+    text_content TEXT,
+    binary_content BYTEA,
+    size_bytes BIGINT NOT NULL,
+    ref_count INTEGER NOT NULL DEFAULT 0,  -- artifacts referring to the blob, kept by trigger
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    touched_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Artifacts saved before content addressing keep their payload inline (blob_hash NULL)
+ALTER TABLE artifacts ADD COLUMN IF NOT EXISTS blob_hash TEXT REFERENCES artifact_blobs(hash);
+
+-- Keep ref_count in step with the artifacts that refer to each blob, however the rows
+-- go away (overwrites, debug expiry, or deleting their run)
+CREATE OR REPLACE FUNCTION artifact_blob_refs() RETURNS trigger AS $$
+DECLARE
+    old_hash TEXT;
+    new_hash TEXT;
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        old_hash := OLD.blob_hash;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        new_hash := NEW.blob_hash;
+    END IF;
+    IF old_hash IS NOT DISTINCT FROM new_hash THEN
+        RETURN NULL;
+    END IF;
+    IF old_hash IS NOT NULL THEN
+        UPDATE artifact_blobs SET ref_count = ref_count - 1, touched_at = NOW() WHERE hash = old_hash;
+    END IF;
+    IF new_hash IS NOT NULL THEN
+        UPDATE artifact_blobs SET ref_count = ref_count + 1, touched_at = NOW() WHERE hash = new_hash;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS artifacts_blob_refs ON artifacts;
+CREATE TRIGGER artifacts_blob_refs
+    AFTER INSERT OR UPDATE OF blob_hash OR DELETE ON artifacts
+    FOR EACH ROW EXECUTE FUNCTION artifact_blob_refs();
+
+-- The latest version of each run's artifact for a step
+CREATE OR REPLACE VIEW latest_artifacts AS
+SELECT DISTINCT ON (run_id, step) *
+  FROM artifacts
+ ORDER BY run_id, step, version DESC;
+
+-- Move payloads stored inline into blobs. JSON is addressed by its canonical jsonb text,
+-- so it only deduplicates against other moved rows, not against new saves.
+INSERT INTO artifact_blobs (hash, format, content, text_content, binary_content, size_bytes)
+SELECT DISTINCT ON (hash) hash, format, content, text_content, binary_content, octet_length(payload)
+  FROM (SELECT encode(sha256(convert_to(format || ':', 'UTF8') || payload), 'hex') AS hash, *
+          FROM (SELECT CASE WHEN binary_content IS NOT NULL THEN 'binary'
+                            WHEN text_content IS NOT NULL THEN 'text'
+                            ELSE 'json' END AS format,
+                       COALESCE(binary_content, convert_to(text_content, 'UTF8'), convert_to(content::text, 'UTF8')) AS payload,
+                       content, text_content, binary_content
+                  FROM artifacts WHERE blob_hash IS NULL) inline
+         WHERE payload IS NOT NULL) hashed
+ON CONFLICT (hash) DO NOTHING;
+
+UPDATE artifacts a
+   SET blob_hash = encode(sha256(convert_to(
+           CASE WHEN a.binary_content IS NOT NULL THEN 'binary'
+                WHEN a.text_content IS NOT NULL THEN 'text'
+                ELSE 'json' END || ':', 'UTF8')
+           || COALESCE(a.binary_content, convert_to(a.text_content, 'UTF8'), convert_to(a.content::text, 'UTF8'))), 'hex'),
+       content = NULL, text_content = NULL, binary_content = NULL
+ WHERE a.blob_hash IS NULL
+   AND COALESCE(a.binary_content, convert_to(a.text_content, 'UTF8'), convert_to(a.content::text, 'UTF8')) IS NOT NULL;
+
 -- =============================================================================
 -- RUN RANKED STORIES TABLE
 -- =============================================================================
@@ -163,6 +297,10 @@ CREATE INDEX IF NOT EXISTS idx_pipeline_runs_job_posting ON pipeline_runs(job_po
 CREATE INDEX IF NOT EXISTS idx_pipeline_runs_job_profile ON pipeline_runs(job_profile_id);
 CREATE INDEX IF NOT EXISTS idx_pipeline_runs_company_profile ON pipeline_runs(company_profile_id);
 
+-- Artifact blobs
+CREATE INDEX IF NOT EXISTS idx_artifacts_blob ON artifacts(blob_hash);
+CREATE INDEX IF NOT EXISTS idx_artifact_blobs_unreferenced ON artifact_blobs(touched_at) WHERE ref_count = 0;
+
 -- Run ranked stories
 CREATE INDEX IF NOT EXISTS idx_run_ranked_run ON run_ranked_stories(run_id);
 CREATE INDEX IF NOT EXISTS idx_run_ranked_story ON run_ranked_stories(story_id);
@@ -190,6 +328,10 @@ CREATE INDEX IF NOT EXISTS idx_run_violations_severity ON run_violations(severit
 -- COMMENTS
 -- =============================================================================
 
+COMMENT ON TABLE artifact_blobs IS 'Content-addressed artifact payloads, stored once however many artifacts share them';
+COMMENT ON COLUMN artifact_blobs.ref_count IS 'Artifacts referring to the blob; unreferenced blobs are deleted by run garbage collection';
+COMMENT ON COLUMN artifact_blobs.touched_at IS 'Last stored or released; unreferenced blobs are kept for a grace period after it';
+COMMENT ON COLUMN artifacts.blob_hash IS 'Content address of the payload in artifact_blobs, or NULL when stored inline';
 COMMENT ON TABLE run_ranked_stories IS 'Ranked experience stories for each pipeline run';
 COMMENT ON TABLE run_resume_plans IS 'Resume plan configuration for each pipeline run';
 COMMENT ON TABLE run_selected_bullets IS 'Selected bullets for each pipeline run';
@@ -0,0 +1,37 @@
+-- Prompt Rollouts Schema
+-- Depends on: nothing
+
+-- =============================================================================
+-- PROMPT ROLLOUTS TABLE
+-- =============================================================================
+
+-- Trials of new prompt versions against the versions they replace, one per prompt file,
+-- key, and new version. Outcomes reported by every server are counted here, so a restart
+-- or another replica picks up the trial where it left off, and a version that was rolled
+-- back is not offered again.
+CREATE TABLE IF NOT EXISTS prompt_rollouts (
+    file TEXT NOT NULL,                 -- Prompt file, e.g. 'parsing.json'
+    prompt_key TEXT NOT NULL,           -- Prompt key without a version suffix
+    version INTEGER NOT NULL CHECK (version > 1),
+    previous_version INTEGER NOT NULL,  -- Version serving the prompt when the trial started
+    state TEXT NOT NULL DEFAULT 'trialing' CHECK (state IN ('trialing', 'promoted', 'rolled_back')),
+    share DOUBLE PRECISION NOT NULL,
+    window_size INTEGER NOT NULL,
+    tolerance DOUBLE PRECISION NOT NULL,
+    previous_samples INTEGER NOT NULL DEFAULT 0,
+    previous_parse_failures INTEGER NOT NULL DEFAULT 0,
+    previous_violations INTEGER NOT NULL DEFAULT 0,
+    next_samples INTEGER NOT NULL DEFAULT 0,
+    next_parse_failures INTEGER NOT NULL DEFAULT 0,
+    next_violations INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMPTZ,
+    PRIMARY KEY (file, prompt_key, version)
+);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE prompt_rollouts IS 'Prompt version trials and their sampled outcomes, shared by every server';
+COMMENT ON COLUMN prompt_rollouts.state IS 'trialing until both versions have window_size samples, then promoted or rolled_back for good';
@@ -0,0 +1,34 @@
+-- Refresh Tokens Schema
+-- Depends on: users.sql (users)
+-- Long-lived refresh tokens that exchange for new JWT access tokens
+
+-- =============================================================================
+-- REFRESH TOKENS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    replaced_by UUID REFERENCES refresh_tokens(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_id) WHERE revoked_at IS NULL;
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE refresh_tokens IS 'Refresh tokens issued at login; each is single-use and rotated by POST /v1/auth/refresh';
+COMMENT ON COLUMN refresh_tokens.family_id IS 'Shared by every token rotated from the same login, so a reused token revokes the whole chain';
+COMMENT ON COLUMN refresh_tokens.token_hash IS 'Hex SHA-256 of the token; the token itself is never stored';
+COMMENT ON COLUMN refresh_tokens.replaced_by IS 'Token issued when this one was rotated';
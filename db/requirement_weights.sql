@@ -0,0 +1,33 @@
+-- Requirement Weights Schema
+-- Depends on: users.sql, job_postings.sql
+-- Each user's importance weights for the requirements of a parsed job posting, applied
+-- when their runs for the posting (or its duplicates) rank stories.
+
+-- =============================================================================
+-- JOB REQUIREMENT WEIGHTS TABLE
+-- =============================================================================
+
+-- Keyed by requirement type and skill rather than requirement ID, so weights survive
+-- the posting being parsed again
+CREATE TABLE IF NOT EXISTS job_requirement_weights (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job_profile_id UUID NOT NULL REFERENCES job_profiles(id) ON DELETE CASCADE,
+    requirement_type TEXT NOT NULL,  -- 'hard' or 'nice_to_have'
+    skill_normalized TEXT NOT NULL,  -- lowercase skill
+    weight DOUBLE PRECISION NOT NULL CHECK (weight >= 0 AND weight <= 3),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (user_id, job_profile_id, requirement_type, skill_normalized)
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_job_requirement_weights_profile ON job_requirement_weights(job_profile_id);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE job_requirement_weights IS 'User-set importance weights for job requirements, used when ranking stories';
+COMMENT ON COLUMN job_requirement_weights.weight IS 'Replaces the default weight (1 for hard requirements, 0.5 for nice-to-haves); 0 ignores the requirement';
@@ -24,6 +24,9 @@ CREATE TABLE IF NOT EXISTS research_sessions (
     
     -- Corpus output
     corpus_text TEXT,                 -- Aggregated crawled text
+
+    -- Resumable crawl state (frontier, visited URLs, page hashes, signals)
+    state JSONB,
     
     -- Timestamps
     created_at TIMESTAMPTZ DEFAULT NOW(),
@@ -71,6 +74,13 @@ CREATE TABLE IF NOT EXISTS research_brand_signals (
     created_at TIMESTAMPTZ DEFAULT NOW()
 );
 
+-- =============================================================================
+-- MIGRATIONS
+-- =============================================================================
+
+-- Add resumable crawl state to research_sessions created before the column existed
+ALTER TABLE research_sessions ADD COLUMN IF NOT EXISTS state JSONB;
+
 -- =============================================================================
 -- INDEXES
 -- =============================================================================
@@ -99,6 +109,7 @@ COMMENT ON TABLE research_frontier IS 'URL queue for research crawling with prio
 COMMENT ON TABLE research_brand_signals IS 'Extracted brand signals from crawled pages';
 
 COMMENT ON COLUMN research_sessions.status IS 'pending, in_progress, completed, failed';
+COMMENT ON COLUMN research_sessions.state IS 'Crawl state snapshot the next session for the company resumes from';
 COMMENT ON COLUMN research_frontier.priority IS 'Crawl priority: 0.00-1.00, higher = more relevant';
 COMMENT ON COLUMN research_frontier.page_type IS 'values, culture, engineering, about, careers, press, other';
 COMMENT ON COLUMN research_frontier.status IS 'pending, fetched, skipped, failed';
@@ -0,0 +1,33 @@
+-- Resume Templates Schema
+-- Depends on: users.sql
+
+-- =============================================================================
+-- RESUME TEMPLATES TABLE
+-- =============================================================================
+
+-- LaTeX resume templates users upload, alongside the built-in templates embedded in the
+-- binary. A run selects one by name; runs render it from a copy written to local disk.
+-- Built-in names are reserved.
+CREATE TABLE IF NOT EXISTS resume_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,                         -- Lowercase letters, digits, '-' and '_'
+    content TEXT NOT NULL,                      -- The template source; passes the template lint
+    manifest JSONB NOT NULL DEFAULT '{}',       -- rendering.TemplateManifest: description, columns, max lines per page, style options
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+-- One template per name per user
+CREATE UNIQUE INDEX IF NOT EXISTS idx_resume_templates_user_name
+    ON resume_templates(user_id, name);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE resume_templates IS 'User-uploaded resume templates selected by name per run';
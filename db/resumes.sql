@@ -20,8 +20,9 @@ CREATE TABLE artifacts (
     category TEXT,          -- 'ingestion', 'experience', 'research', 'rewriting', 'validation'
     content JSONB,          -- for structured JSON data
     text_content TEXT,      -- for .txt/.tex files
+    version INTEGER NOT NULL DEFAULT 1,  -- each save of a step adds a version
     created_at TIMESTAMPTZ DEFAULT NOW(),
-    UNIQUE(run_id, step)
+    UNIQUE(run_id, step, version)
 );
 
 -- Indexes for common query patterns
@@ -0,0 +1,34 @@
+-- Role Titles Schema
+-- Depends on: users.sql (jobs), job_postings.sql (job_profiles)
+-- Normalized role titles for the user's jobs and parsed postings, so "Sr. SWE" and
+-- "Software Engineer III" group and match as the same role at the same level.
+
+-- =============================================================================
+-- ROLE TITLE COLUMNS
+-- =============================================================================
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS role_title_normalized TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS role_family TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS role_level TEXT;
+
+ALTER TABLE job_profiles ADD COLUMN IF NOT EXISTS role_title_normalized TEXT;
+ALTER TABLE job_profiles ADD COLUMN IF NOT EXISTS role_family TEXT;
+ALTER TABLE job_profiles ADD COLUMN IF NOT EXISTS role_level TEXT;
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_jobs_role_family ON jobs(role_family);
+CREATE INDEX IF NOT EXISTS idx_job_profiles_role_family ON job_profiles(role_family);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON COLUMN jobs.role_title_normalized IS 'role_title with abbreviations expanded and level words standardized, e.g. Senior Software Engineer';
+COMMENT ON COLUMN jobs.role_family IS 'role_title without level, team, or location, e.g. software engineer';
+COMMENT ON COLUMN jobs.role_level IS 'Level on the shared ladder (intern, junior, mid, senior, staff, principal, distinguished); NULL if the title names none';
+COMMENT ON COLUMN job_profiles.role_title_normalized IS 'role_title with abbreviations expanded and level words standardized';
+COMMENT ON COLUMN job_profiles.role_family IS 'role_title without level, team, or location';
+COMMENT ON COLUMN job_profiles.role_level IS 'Level on the shared ladder, read through the company''s leveling map when it has one';
@@ -0,0 +1,45 @@
+-- Run Jobs Schema
+-- Depends on: users.sql (users), resumes.sql (pipeline_runs)
+-- Queue for pipeline runs executed asynchronously by the server's run workers
+
+-- =============================================================================
+-- RUN JOBS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS run_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID NOT NULL UNIQUE REFERENCES pipeline_runs(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    priority SMALLINT NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    error_message TEXT,
+    worker_id VARCHAR(200),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Add the scheduling priority to run_jobs created before the column existed
+ALTER TABLE run_jobs ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 1;
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+DROP INDEX IF EXISTS idx_run_jobs_queued;
+CREATE INDEX IF NOT EXISTS idx_run_jobs_queued_priority ON run_jobs(priority DESC, created_at) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_run_jobs_user_queued ON run_jobs(user_id) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_run_jobs_running ON run_jobs(updated_at) WHERE status = 'running';
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE run_jobs IS 'Work queue for pipeline runs created with POST /v1/runs and "execute": true';
+COMMENT ON COLUMN run_jobs.payload IS 'Run options from the create request';
+COMMENT ON COLUMN run_jobs.priority IS 'Scheduling class: 0 bulk, 1 normal, 2 interactive; higher is claimed first';
+COMMENT ON COLUMN run_jobs.status IS 'queued, running, completed, failed';
+COMMENT ON COLUMN run_jobs.worker_id IS 'Identifier of the worker that claimed the job';
@@ -0,0 +1,38 @@
+-- Run Recipes Schema
+-- Depends on: users.sql
+
+-- =============================================================================
+-- RUN RECIPES TABLE
+-- =============================================================================
+
+-- Named bundles of run defaults a user saves for an application scenario, alongside the
+-- built-in recipes (new-grad, career-switcher, executive, academic). A user's recipe
+-- shadows a built-in recipe with the same name. A run started with a recipe takes every
+-- option the request and its company preset leave unset from it.
+CREATE TABLE IF NOT EXISTS run_recipes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT,
+    template TEXT,
+    max_bullets INTEGER CHECK (max_bullets IS NULL OR max_bullets > 0),
+    max_lines INTEGER CHECK (max_lines IS NULL OR max_lines > 0),
+    section_order TEXT[] NOT NULL DEFAULT '{}',
+    ranking_weights JSONB,                      -- {skill_overlap, keyword_overlap, evidence_strength, recency}
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+-- One recipe per name per user, whatever its capitalization
+CREATE UNIQUE INDEX IF NOT EXISTS idx_run_recipes_user_name
+    ON run_recipes(user_id, lower(name));
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE run_recipes IS 'User-defined run recipes merged into run options by name';
@@ -0,0 +1,84 @@
+-- Shared Resume Pages Schema
+-- Depends on: users.sql, resumes.sql (pipeline_runs)
+
+-- =============================================================================
+-- SHARED RESUMES TABLE
+-- =============================================================================
+
+-- Opt-in public page rendering one of the user's runs (one page per user)
+CREATE TABLE IF NOT EXISTS shared_resumes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    run_id UUID NOT NULL REFERENCES pipeline_runs(id) ON DELETE CASCADE,
+    slug TEXT NOT NULL UNIQUE,          -- random, unguessable URL segment
+    scope TEXT NOT NULL DEFAULT 'pdf' CHECK (scope IN ('pdf', 'artifacts', 'comments')),
+    expires_at TIMESTAMPTZ,             -- the link stops working after this; NULL never expires
+    revoked_at TIMESTAMPTZ,             -- the link was revoked; republishing issues a new slug
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    UNIQUE(user_id)
+);
+
+-- Migration: Add share link scopes, expiry, and revocation (if table already exists)
+-- ALTER TABLE shared_resumes ADD COLUMN IF NOT EXISTS scope TEXT NOT NULL DEFAULT 'pdf' CHECK (scope IN ('pdf', 'artifacts', 'comments'));
+-- ALTER TABLE shared_resumes ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+-- ALTER TABLE shared_resumes ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+
+-- =============================================================================
+-- SHARED RESUME VIEWS TABLE
+-- =============================================================================
+
+-- One row per page view or PDF download; no IP addresses or user agents are kept
+CREATE TABLE IF NOT EXISTS shared_resume_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shared_resume_id UUID NOT NULL REFERENCES shared_resumes(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('page', 'pdf')),
+    referrer_host TEXT,                 -- host of the Referer header, if any
+    viewed_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- SHARED RESUME COMMENTS TABLE
+-- =============================================================================
+
+-- Feedback left by recipients of a link with the 'comments' scope
+CREATE TABLE IF NOT EXISTS shared_resume_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shared_resume_id UUID NOT NULL REFERENCES shared_resumes(id) ON DELETE CASCADE,
+    author TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- SHARED RESUME ACCESS LOG TABLE
+-- =============================================================================
+
+-- Audit trail of every request made through a link, including refused ones, and of the
+-- owner's changes to it
+CREATE TABLE IF NOT EXISTS shared_resume_access_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shared_resume_id UUID NOT NULL REFERENCES shared_resumes(id) ON DELETE CASCADE,
+    action TEXT NOT NULL,               -- page, pdf, artifact, comment, published, access_changed, revoked, ...
+    outcome TEXT NOT NULL CHECK (outcome IN ('allowed', 'out_of_scope', 'expired', 'revoked')),
+    detail TEXT,                        -- e.g. the artifact step or the new scope
+    referrer_host TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_shared_resume_views_share ON shared_resume_views(shared_resume_id, viewed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_shared_resume_comments_share ON shared_resume_comments(shared_resume_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_shared_resume_access_log_share ON shared_resume_access_log(shared_resume_id, created_at DESC);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE shared_resumes IS 'Opt-in public resume pages served at /r/{slug}';
+COMMENT ON TABLE shared_resume_views IS 'View analytics for shared resume pages';
+COMMENT ON TABLE shared_resume_comments IS 'Recipient comments on shared resume pages';
+COMMENT ON TABLE shared_resume_access_log IS 'Audit trail of shared resume link access and changes';
@@ -0,0 +1,44 @@
+-- Step Jobs Schema
+-- Depends on: resumes.sql (pipeline_runs)
+-- Queue for heavy steps (crawling, LaTeX compilation) executed by out-of-process workers
+
+-- =============================================================================
+-- STEP JOBS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS step_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID REFERENCES pipeline_runs(id) ON DELETE CASCADE,
+    step VARCHAR(100) NOT NULL,
+    backend VARCHAR(20) NOT NULL DEFAULT 'worker',
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    result JSONB,
+    error_message TEXT,
+    worker_id VARCHAR(200),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+-- Run this ALTER TABLE statement if the table was created before the backend column:
+-- ALTER TABLE step_jobs ADD COLUMN IF NOT EXISTS backend VARCHAR(20) NOT NULL DEFAULT 'worker';
+
+CREATE INDEX IF NOT EXISTS idx_step_jobs_queued ON step_jobs(backend, step, created_at) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_step_jobs_running ON step_jobs(started_at) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_step_jobs_run_id ON step_jobs(run_id);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE step_jobs IS 'Work queue for pipeline steps executed by separate worker processes';
+COMMENT ON COLUMN step_jobs.status IS 'queued, running, completed, failed';
+COMMENT ON COLUMN step_jobs.backend IS 'worker (shared queue) or kubernetes (dedicated Job claims it by ID)';
+COMMENT ON COLUMN step_jobs.worker_id IS 'Identifier of the worker that claimed the job';
@@ -0,0 +1,32 @@
+-- User Company Profiles Schema
+-- Depends on: users.sql, companies.sql
+
+-- =============================================================================
+-- USER COMPANY PROFILES TABLE
+-- =============================================================================
+
+-- Company profiles a user entered by hand, for companies with nothing to crawl. Each
+-- stands in for the shared, crawled profile in that user's runs only.
+CREATE TABLE IF NOT EXISTS user_company_profiles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    tone TEXT NOT NULL,
+    domain_context TEXT,
+    company_values TEXT[] NOT NULL DEFAULT '{}',
+    style_rules TEXT[] NOT NULL DEFAULT '{}',
+    taboo_phrases TEXT[] NOT NULL DEFAULT '{}',
+    taboo_reasons JSONB NOT NULL DEFAULT '{}',  -- why a taboo phrase is avoided, by phrase
+    version INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    UNIQUE(user_id, company_id)
+);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE user_company_profiles IS 'Manual company profiles, each used in place of the crawled profile for its user''s runs';
+COMMENT ON COLUMN user_company_profiles.version IS 'Incremented when the user enters the profile again';
@@ -6,6 +6,9 @@ CREATE TABLE users (
     phone TEXT,
     password_hash TEXT NOT NULL DEFAULT '',
     password_set BOOLEAN DEFAULT FALSE,
+    redact_pii BOOLEAN NOT NULL DEFAULT TRUE,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    ranking_config JSONB,                       -- Story ranking weights for the user's runs; NULL uses the defaults
     created_at TIMESTAMPTZ DEFAULT NOW(),
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
@@ -16,6 +19,9 @@ CREATE TABLE users (
 -- ALTER TABLE users ADD COLUMN IF NOT EXISTS password_set BOOLEAN DEFAULT FALSE;
 -- ALTER TABLE users ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ DEFAULT NOW();
 -- UPDATE users SET password_set = FALSE WHERE password_hash = '';
+-- ALTER TABLE users ADD COLUMN IF NOT EXISTS redact_pii BOOLEAN NOT NULL DEFAULT TRUE;
+-- ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+-- ALTER TABLE users ADD COLUMN IF NOT EXISTS ranking_config JSONB;
 
 -- Jobs table (employment history)
 CREATE TABLE jobs (
@@ -60,3 +66,49 @@ CREATE INDEX idx_jobs_user ON jobs(user_id);
 CREATE INDEX idx_experiences_job ON experiences(job_id);
 CREATE INDEX idx_education_user ON education(user_id);
 CREATE INDEX idx_experiences_skills ON experiences USING GIN (skills);
+
+-- Domain crawl policies: never crawl a domain, or always prefer it during company research.
+-- Rows with a NULL user_id are global policies managed by admins; user rows override them.
+CREATE TABLE domain_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    domain TEXT NOT NULL,              -- Registrable domain or host, e.g. 'glassdoor.com'
+    action TEXT NOT NULL CHECK (action IN ('block', 'allow')),
+    reason TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_domain_policies_scope
+    ON domain_policies (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid), domain);
+
+-- Notification preferences: how a user hears about each event type.
+-- A missing row means the user is not notified ('none').
+CREATE TABLE notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL CHECK (event_type IN ('run_completed', 'weekly_digest', 'application_reminder', 'resume_health')),
+    channel TEXT NOT NULL CHECK (channel IN ('email', 'webhook', 'none')),
+    webhook_url TEXT,                  -- Required for the webhook channel
+    last_sent_at TIMESTAMPTZ,          -- Last delivery; schedules the next digest
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (user_id, event_type)
+);
+
+-- Delivered per-run notifications. Every replica sees run events; the first
+-- to insert a row here sends the notification.
+CREATE TABLE notification_deliveries (
+    event_type TEXT NOT NULL,
+    ref_id UUID NOT NULL,              -- Run or application tracker the notification is about
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    sent_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (event_type, ref_id)
+);
+
+-- Onboarding wizard progress. A missing row means the user has not started
+-- ('upload_resume').
+CREATE TABLE user_onboarding (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    state TEXT NOT NULL CHECK (state IN ('upload_resume', 'confirm_bank', 'contact_info', 'sample_run', 'completed')),
+    skipped JSONB NOT NULL DEFAULT '[]',  -- Steps passed over without finishing them
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
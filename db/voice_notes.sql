@@ -0,0 +1,34 @@
+-- Voice Notes Schema
+-- Depends on: users.sql
+
+-- =============================================================================
+-- VOICE NOTES TABLE
+-- =============================================================================
+
+-- Accomplishments users talked through instead of typing, transcribed and sorted into
+-- STAR interview answers. The recording itself is never stored. A note with a draft is
+-- ready to confirm as a story; one without resumes the interview at next_stage.
+CREATE TABLE IF NOT EXISTS voice_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source TEXT NOT NULL CHECK (source IN ('upload', 'webhook')),
+    transcript TEXT NOT NULL,
+    answers JSONB NOT NULL,             -- situation, task, action, result
+    draft JSONB,                        -- story drafted once every stage is answered
+    next_stage TEXT,                    -- first unanswered stage when there is no draft
+    next_question TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_voice_notes_user ON voice_notes(user_id, created_at DESC);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE voice_notes IS 'Transcribed voice notes sorted into STAR answers, awaiting confirmation as stories';
+COMMENT ON COLUMN voice_notes.source IS 'upload for recordings transcribed here, webhook for transcripts pushed by another service';
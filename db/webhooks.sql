@@ -0,0 +1,55 @@
+-- Webhooks Schema
+-- Depends on: users.sql, resumes.sql (pipeline_runs)
+-- Endpoints users register for run lifecycle events, and the log of deliveries to them
+
+-- =============================================================================
+-- WEBHOOKS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,               -- Signs each payload; kept to sign, never returned after creation
+    events TEXT[] NOT NULL,             -- run.created, step.completed, run.failed, run.completed
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- =============================================================================
+-- WEBHOOK DELIVERIES TABLE
+-- =============================================================================
+
+-- One row per event per webhook. Pending deliveries are claimed by any replica's
+-- delivery worker; failed attempts are retried with backoff until the attempts run out.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    run_id UUID REFERENCES pipeline_runs(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    event_key TEXT NOT NULL,            -- Identifies the event, so replicas that all see it enqueue it once
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    response_status INTEGER,            -- HTTP status of the latest attempt, if the endpoint answered
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    UNIQUE (webhook_id, event_key)
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_user ON webhooks(user_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE webhooks IS 'User endpoints that receive signed run lifecycle events';
+COMMENT ON TABLE webhook_deliveries IS 'Delivery log and retry queue for webhook events';
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'When a pending delivery is next due; claiming it pushes this out so a worker that dies releases it';
@@ -0,0 +1,84 @@
+-- Workspaces Schema
+-- Depends on: users.sql, resumes.sql (pipeline_runs)
+
+-- =============================================================================
+-- WORKSPACES TABLE
+-- =============================================================================
+
+-- A group of users, such as a coaching business and its clients, whose admins publish
+-- style rule packs every member's runs follow
+CREATE TABLE IF NOT EXISTS workspaces (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- WORKSPACE MEMBERS TABLE
+-- =============================================================================
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('admin', 'member')),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+-- =============================================================================
+-- WORKSPACE RULE PACKS TABLE
+-- =============================================================================
+
+-- Mandatory style rules merged into the company profile of every member's runs. Packs
+-- apply in descending priority; 'replace' packs drop the rules found by research.
+CREATE TABLE IF NOT EXISTS workspace_rule_packs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    mode TEXT NOT NULL DEFAULT 'append' CHECK (mode IN ('append', 'replace')),
+    priority INTEGER NOT NULL DEFAULT 0,
+    rules TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- RULE PACK APPLICATIONS TABLE
+-- =============================================================================
+
+-- Audit of the packs applied to each run, with the rules as they stood at the time
+CREATE TABLE IF NOT EXISTS rule_pack_applications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID NOT NULL REFERENCES pipeline_runs(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    pack_id UUID REFERENCES workspace_rule_packs(id) ON DELETE SET NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    pack_name TEXT NOT NULL,
+    mode TEXT NOT NULL,
+    rules TEXT[] NOT NULL DEFAULT '{}',  -- rules the pack added, after removing repeats
+    applied_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- =============================================================================
+-- INDEXES
+-- =============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user ON workspace_members(user_id);
+
+-- One pack per name per workspace, whatever its capitalization
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspace_rule_packs_workspace_name
+    ON workspace_rule_packs(workspace_id, lower(name));
+
+CREATE INDEX IF NOT EXISTS idx_rule_pack_applications_workspace
+    ON rule_pack_applications(workspace_id, applied_at DESC);
+CREATE INDEX IF NOT EXISTS idx_rule_pack_applications_run ON rule_pack_applications(run_id);
+
+-- =============================================================================
+-- COMMENTS
+-- =============================================================================
+
+COMMENT ON TABLE workspaces IS 'Groups of users sharing mandatory style rule packs';
+COMMENT ON TABLE workspace_rule_packs IS 'Workspace style rules merged into every member''s runs';
+COMMENT ON TABLE rule_pack_applications IS 'Audit of rule packs applied to each run';
@@ -0,0 +1,313 @@
+// Package apidoc generates an OpenAPI 3.1 document from a server's routes and the Go
+// types of their request and response bodies, so the published API description cannot
+// drift from the handlers it describes.
+package apidoc
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Version is the OpenAPI version of generated documents
+const Version = "3.1.0"
+
+// Info describes the API as a whole
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Param is a query parameter
+type Param struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// Operation is what a route's handler accepts and returns. Request and Response are
+// values of the body types (their zero values will do); nil means no JSON body.
+type Operation struct {
+	Summary     string
+	Description string
+	Tags        []string
+	Query       []Param
+	Request     any
+	Response    any
+	Status      int    // Success status; defaults to 200
+	ContentType string // Success media type when the body is not JSON, e.g. "application/pdf"
+}
+
+// Route is one route of the server's mux
+type Route struct {
+	Pattern     string // A net/http ServeMux pattern, e.g. "GET /v1/runs/{id}"
+	OperationID string // Defaults to one built from the pattern
+	Auth        bool   // Requires a bearer token
+	Operation
+}
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string                              `json:"openapi"`
+	Info       Info                                `json:"info"`
+	Paths      map[string]map[string]*APIOperation `json:"paths"`
+	Components Components                          `json:"components"`
+}
+
+// Components holds the schemas operations refer to, and the bearer token scheme
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme describes how requests authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// APIOperation is an operation as written in the document
+type APIOperation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path or query parameter as written in the document
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is a JSON request body
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is one response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is a body's schema for one media type
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+const (
+	bearerScheme = "bearerAuth"
+	errorSchema  = "Error"
+	jsonMedia    = "application/json"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^}.$]+)(?:\.\.\.)?\}`)
+
+// Generator builds documents, describing some types with fixed schemas
+type Generator struct {
+	info    Info
+	defined map[reflect.Type]*Schema
+}
+
+// NewGenerator returns a generator for documents describing an API with info
+func NewGenerator(info Info) *Generator {
+	return &Generator{info: info, defined: map[reflect.Type]*Schema{}}
+}
+
+// Define describes values of t with s instead of reading their fields, for types that
+// encode themselves, such as a date written as "2006-01-02"
+func (g *Generator) Define(t reflect.Type, s Schema) {
+	g.defined[t] = &s
+}
+
+// Generate writes the document for routes. Routes without a method, which match any
+// request method, are left out.
+func (g *Generator) Generate(routes []Route) (*Document, error) {
+	types := newSchemas(g.defined)
+	types.components[errorSchema] = &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"error": {Type: "string"}},
+		Required:   []string{"error"},
+	}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    g.info,
+		Paths:   map[string]map[string]*APIOperation{},
+		Components: Components{
+			Schemas:         types.components,
+			SecuritySchemes: map[string]SecurityScheme{bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"}},
+		},
+	}
+	ids := map[string]string{}
+	for _, route := range routes {
+		method, path, ok := ParsePattern(route.Pattern)
+		if !ok {
+			continue
+		}
+		item := doc.Paths[path]
+		if item == nil {
+			item = map[string]*APIOperation{}
+			doc.Paths[path] = item
+		}
+		key := strings.ToLower(method)
+		if item[key] != nil {
+			return nil, fmt.Errorf("route %q: %s %s is already documented", route.Pattern, method, path)
+		}
+
+		op := buildOperation(types, route, path)
+		if other, taken := ids[op.OperationID]; taken {
+			return nil, fmt.Errorf("route %q: operation ID %q is already used by %q", route.Pattern, op.OperationID, other)
+		}
+		ids[op.OperationID] = route.Pattern
+		item[key] = op
+	}
+	return doc, nil
+}
+
+func buildOperation(types *schemas, route Route, path string) *APIOperation {
+	op := &APIOperation{
+		OperationID: route.OperationID,
+		Summary:     route.Summary,
+		Description: route.Description,
+		Tags:        route.Tags,
+		Responses:   map[string]Response{},
+	}
+	if op.OperationID == "" {
+		op.OperationID = operationID(route.Pattern)
+	}
+	if op.Summary == "" {
+		op.Summary = Summarize(op.OperationID)
+	}
+	if op.Tags == nil {
+		op.Tags = []string{pathTag(path)}
+	}
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	for _, q := range route.Query {
+		op.Parameters = append(op.Parameters, Parameter{Name: q.Name, In: "query", Description: q.Description, Required: q.Required, Schema: &Schema{Type: "string"}})
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{jsonMedia: {Schema: types.of(reflect.TypeOf(route.Request))}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := Response{Description: http.StatusText(status)}
+	switch {
+	case route.ContentType != "":
+		success.Content = map[string]MediaType{route.ContentType: {}}
+	case route.Response != nil:
+		success.Content = map[string]MediaType{jsonMedia: {Schema: types.of(reflect.TypeOf(route.Response))}}
+	}
+	op.Responses[fmt.Sprint(status)] = success
+
+	errorBody := map[string]MediaType{jsonMedia: {Schema: &Schema{Ref: "#/components/schemas/" + errorSchema}}}
+	if route.Auth {
+		op.Security = []map[string][]string{{bearerScheme: {}}}
+		op.Responses["401"] = Response{Description: "Missing or invalid bearer token", Content: errorBody}
+	}
+	op.Responses["default"] = Response{Description: "Error", Content: errorBody}
+	return op
+}
+
+// ParsePattern splits a ServeMux pattern into its method and an OpenAPI path, dropping a
+// host and turning wildcards such as {path...} and the {$} anchor into path parameters
+// and nothing. ok is false for patterns without a method.
+func ParsePattern(pattern string) (method, path string, ok bool) {
+	method, rest, ok := strings.Cut(pattern, " ")
+	if !ok {
+		return "", "", false
+	}
+	rest = strings.TrimSpace(rest)
+	if i := strings.Index(rest, "/"); i > 0 {
+		rest = rest[i:] // Host-specific pattern
+	}
+	rest = strings.ReplaceAll(rest, "{$}", "")
+	rest = pathParamPattern.ReplaceAllString(rest, "{$1}")
+	if rest == "" {
+		rest = "/"
+	}
+	return method, rest, true
+}
+
+// operationID names an operation after its method and path, leaving out the version:
+// "GET /v1/runs/{id}/events" becomes "getRunsByIdEvents"
+func operationID(pattern string) string {
+	method, path, _ := ParsePattern(pattern)
+	id := strings.ToLower(method)
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "v1" {
+			continue
+		}
+		if param, ok := strings.CutPrefix(segment, "{"); ok {
+			segment = "by_" + strings.TrimSuffix(param, "}")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			id += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return id
+}
+
+// Summarize writes an operation ID as a sentence: "listRunSteps" becomes "List run steps"
+// and "getRunPDF" becomes "Get run PDF"
+func Summarize(id string) string {
+	var words []string
+	start := 0
+	for i := 1; i < len(id); i++ {
+		upper := unicode.IsUpper(rune(id[i]))
+		afterLower := !unicode.IsUpper(rune(id[i-1]))
+		beforeLower := i+1 < len(id) && unicode.IsLower(rune(id[i+1]))
+		if upper && (afterLower || beforeLower) {
+			words = append(words, id[start:i])
+			start = i
+		}
+	}
+	words = append(words, id[start:])
+	for i, w := range words {
+		if len(w) == 1 || strings.ToUpper(w) != w {
+			words[i] = strings.ToLower(w) // Acronyms such as "PDF" keep their case
+		}
+	}
+	if len(words[0]) > 0 {
+		words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// pathTag groups an operation by the first path segment after the version, so user and
+// run operations each appear together
+func pathTag(path string) string {
+	segments := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+	for _, s := range segments {
+		if s != "v1" && !strings.HasPrefix(s, "{") {
+			return s
+		}
+	}
+	return "default"
+}
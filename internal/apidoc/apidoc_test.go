@@ -0,0 +1,146 @@
+package apidoc
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDate struct{ time.Time }
+
+func (d testDate) MarshalJSON() ([]byte, error) { return json.Marshal(d.Format("2006-01-02")) }
+
+type testBase struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testRun struct {
+	testBase
+	Status  string            `json:"status" doc:"Pipeline status"`
+	Company *string           `json:"company,omitempty"`
+	Due     *testDate         `json:"due"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Steps   []testStep        `json:"steps"`
+	Parent  *testRun          `json:"parent,omitempty"`
+	Extra   json.RawMessage   `json:"extra,omitempty"`
+	Count   int64             `json:"count,string"`
+	secret  string
+	Skipped string `json:"-"`
+}
+
+type testStep struct {
+	Name string `json:"name" validate:"required"`
+	Note string `json:"note,omitempty"`
+}
+
+type testCreateRun struct {
+	JobURL string `json:"job_url"`
+}
+
+func TestGenerate(t *testing.T) {
+	g := NewGenerator(Info{Title: "Test API", Version: "1.0"})
+	g.Define(reflect.TypeFor[testDate](), Schema{Type: "string", Format: "date"})
+	doc, err := g.Generate([]Route{
+		{Pattern: "POST /v1/runs", OperationID: "createRun", Auth: true, Operation: Operation{
+			Request: testCreateRun{}, Response: testRun{}, Status: 201,
+		}},
+		{Pattern: "GET /v1/runs/{id}", Operation: Operation{
+			Response: testRun{}, Query: []Param{{Name: "fields", Description: "Fields to include"}},
+		}},
+		{Pattern: "GET /v1/runs/{id}/resume.pdf", OperationID: "getRunPDF", Operation: Operation{ContentType: "application/pdf"}},
+		{Pattern: "GET /{$}"},
+		{Pattern: "/ui/"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Len(t, doc.Paths, 4, "a pattern without a method is left out")
+	require.Contains(t, doc.Paths, "/")
+
+	create := doc.Paths["/v1/runs"]["post"]
+	require.NotNil(t, create)
+	assert.Equal(t, "Create run", create.Summary)
+	assert.Equal(t, []string{"runs"}, create.Tags)
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, create.Security)
+	assert.Contains(t, create.Responses, "201")
+	assert.Contains(t, create.Responses, "401")
+	assert.Equal(t, "#/components/schemas/testCreateRun", create.RequestBody.Content["application/json"].Schema.Ref)
+
+	get := doc.Paths["/v1/runs/{id}"]["get"]
+	require.NotNil(t, get)
+	assert.Equal(t, "getRunsById", get.OperationID)
+	assert.Nil(t, get.Security)
+	assert.NotContains(t, get.Responses, "401")
+	require.Len(t, get.Parameters, 2)
+	assert.Equal(t, Parameter{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}, get.Parameters[0])
+	assert.Equal(t, "query", get.Parameters[1].In)
+
+	pdf := doc.Paths["/v1/runs/{id}/resume.pdf"]["get"]
+	require.NotNil(t, pdf)
+	assert.Equal(t, "Get run PDF", pdf.Summary)
+	assert.Contains(t, pdf.Responses["200"].Content, "application/pdf")
+
+	run := doc.Components.Schemas["testRun"]
+	require.NotNil(t, run)
+	assert.ElementsMatch(t, []string{"id", "created_at", "status", "steps", "count"}, run.Required)
+	assert.NotContains(t, run.Properties, "secret")
+	assert.NotContains(t, run.Properties, "Skipped")
+	assert.Equal(t, &Schema{Type: "string", Format: "uuid"}, run.Properties["id"])
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, run.Properties["created_at"])
+	assert.Equal(t, "Pipeline status", run.Properties["status"].Description)
+	assert.Equal(t, []string{"string", "null"}, run.Properties["company"].Type)
+	assert.Equal(t, &Schema{Type: []string{"string", "null"}, Format: "date"}, run.Properties["due"])
+	assert.Equal(t, &Schema{Type: "string"}, run.Properties["labels"].AdditionalProperties)
+	assert.Equal(t, "#/components/schemas/testStep", run.Properties["steps"].Items.Ref)
+	assert.Equal(t, "#/components/schemas/testRun", run.Properties["parent"].AnyOf[0].Ref, "recursive types refer to their component")
+	assert.Equal(t, &Schema{}, run.Properties["extra"])
+	assert.Equal(t, &Schema{Type: "string"}, run.Properties["count"])
+	assert.Equal(t, []string{"name"}, doc.Components.Schemas["testStep"].Required)
+	assert.Contains(t, doc.Components.Schemas, "Error")
+
+	_, err = json.Marshal(doc)
+	require.NoError(t, err)
+}
+
+func TestGenerate_RejectsDuplicates(t *testing.T) {
+	g := NewGenerator(Info{Title: "Test API", Version: "1.0"})
+	_, err := g.Generate([]Route{{Pattern: "GET /v1/runs/"}, {Pattern: "GET /v1/runs/{$}"}})
+	assert.ErrorContains(t, err, "already documented")
+
+	_, err = g.Generate([]Route{{Pattern: "GET /v1/runs", OperationID: "list"}, {Pattern: "GET /v1/jobs", OperationID: "list"}})
+	assert.ErrorContains(t, err, "already used")
+}
+
+func TestParsePattern(t *testing.T) {
+	for pattern, want := range map[string][2]string{
+		"GET /v1/runs/{id}":          {"GET", "/v1/runs/{id}"},
+		"GET /{$}":                   {"GET", "/"},
+		"GET /files/{path...}":       {"GET", "/files/{path}"},
+		"POST example.com/v1/hooks":  {"POST", "/v1/hooks"},
+		"DELETE /v1/a/{x}/b/{y}/{$}": {"DELETE", "/v1/a/{x}/b/{y}/"},
+	} {
+		method, path, ok := ParsePattern(pattern)
+		assert.True(t, ok, pattern)
+		assert.Equal(t, want, [2]string{method, path}, pattern)
+	}
+	_, _, ok := ParsePattern("/ui/")
+	assert.False(t, ok)
+}
+
+func TestSummarize(t *testing.T) {
+	for id, want := range map[string]string{
+		"listRuns":           "List runs",
+		"getRunPDF":          "Get run PDF",
+		"getJobPostingByURL": "Get job posting by URL",
+		"syncGitHub":         "Sync git hub",
+		"health":             "Health",
+	} {
+		assert.Equal(t, want, Summarize(id), id)
+	}
+}
@@ -0,0 +1,175 @@
+// Package ats scores how well a resume covers a job's keywords and hard requirements,
+// matching terms the literal way applicant tracking systems do.
+package ats
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// maxSummaryMissing is how many missing terms a summary names before counting the rest
+const maxSummaryMissing = 5
+
+var (
+	htmlTagPattern   = regexp.MustCompile(`<[^>]*>`)
+	separatorPattern = regexp.MustCompile(`[\s\-_/]+`)
+)
+
+// Report is a resume's coverage of a job's keywords and hard requirements
+type Report struct {
+	Score               int      `json:"score"`                // Percent of all distinct terms covered, 0-100
+	KeywordCoverage     float64  `json:"keyword_coverage"`     // Fraction of keywords covered
+	RequirementCoverage float64  `json:"requirement_coverage"` // Fraction of hard requirements covered
+	Keywords            []Term   `json:"keywords"`
+	HardRequirements    []Term   `json:"hard_requirements"`
+	Missing             []string `json:"missing"` // Uncovered terms, hard requirements first
+}
+
+// Term is one keyword or hard requirement and how often the resume uses it
+type Term struct {
+	Term        string `json:"term"`
+	Covered     bool   `json:"covered"`
+	Occurrences int    `json:"occurrences"`
+}
+
+// ResumeText returns the visible text of a rendered resume, without LaTeX markup
+func ResumeText(tex string) string {
+	return html.UnescapeString(htmlTagPattern.ReplaceAllString(rendering.LaTeXToHTML(tex), " "))
+}
+
+// Analyze scores resumeText against the job's keywords and hard requirement skills. Terms
+// match case-insensitively on word boundaries, treating hyphens, underscores, and slashes
+// like spaces, so "real-time" covers "real time" but "Go" is not found in "good". A job
+// with no terms scores 100.
+func Analyze(resumeText string, job *types.JobProfile) *Report {
+	report := &Report{Keywords: []Term{}, HardRequirements: []Term{}, Missing: []string{}}
+	if job == nil {
+		job = &types.JobProfile{}
+	}
+	text := normalize(resumeText)
+
+	var skills []string
+	for _, req := range job.HardRequirements {
+		skills = append(skills, req.Skill)
+	}
+	report.HardRequirements = measure(text, skills)
+	report.Keywords = measure(text, job.Keywords)
+
+	// Each distinct term counts once towards the score and the missing list, hard
+	// requirements first since they can screen a resume out
+	counted := make(map[string]bool)
+	var total, covered int
+	for _, t := range append(append([]Term{}, report.HardRequirements...), report.Keywords...) {
+		key := normalize(t.Term)
+		if counted[key] {
+			continue
+		}
+		counted[key] = true
+		total++
+		if t.Covered {
+			covered++
+		} else {
+			report.Missing = append(report.Missing, t.Term)
+		}
+	}
+
+	report.KeywordCoverage = coverage(report.Keywords)
+	report.RequirementCoverage = coverage(report.HardRequirements)
+	report.Score = 100
+	if total > 0 {
+		report.Score = int(math.Round(100 * float64(covered) / float64(total)))
+	}
+	return report
+}
+
+// Summary is a one-line description of the report for run logs
+func (r *Report) Summary() string {
+	summary := fmt.Sprintf("ATS coverage %d%% (%d/%d keywords, %d/%d hard requirements)",
+		r.Score, coveredCount(r.Keywords), len(r.Keywords), coveredCount(r.HardRequirements), len(r.HardRequirements))
+	if len(r.Missing) == 0 {
+		return summary
+	}
+	missing := r.Missing
+	if len(missing) > maxSummaryMissing {
+		missing = missing[:maxSummaryMissing]
+	}
+	summary += "; missing " + strings.Join(missing, ", ")
+	if extra := len(r.Missing) - len(missing); extra > 0 {
+		summary += fmt.Sprintf(" and %d more", extra)
+	}
+	return summary
+}
+
+// normalize lowercases s and collapses whitespace and separators into single spaces
+func normalize(s string) string {
+	return strings.TrimSpace(separatorPattern.ReplaceAllString(strings.ToLower(s), " "))
+}
+
+// countTerm counts the occurrences of term in text that start and end on word boundaries.
+// Boundaries are only required next to letters and digits, so "c++" and ".net" still match.
+func countTerm(text, term string) int {
+	count := 0
+	for start := 0; start <= len(text)-len(term); {
+		i := strings.Index(text[start:], term)
+		if i < 0 {
+			break
+		}
+		i += start
+		end := i + len(term)
+		before := i == 0 || !isWordByte(text[i-1]) || !isWordByte(term[0])
+		after := end == len(text) || !isWordByte(text[end]) || !isWordByte(term[len(term)-1])
+		if before && after {
+			count++
+			start = end
+			continue
+		}
+		start = i + 1
+	}
+	return count
+}
+
+// measure counts each distinct, non-blank term in text, in the order given
+func measure(text string, terms []string) []Term {
+	measured := []Term{}
+	seen := make(map[string]bool)
+	for _, term := range terms {
+		key := normalize(term)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		occurrences := countTerm(text, key)
+		measured = append(measured, Term{Term: strings.TrimSpace(term), Covered: occurrences > 0, Occurrences: occurrences})
+	}
+	return measured
+}
+
+// isWordByte reports whether c is a lowercase letter or digit
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
+
+// coverage returns the fraction of terms covered, rounded to two places, or 1 for no terms
+func coverage(terms []Term) float64 {
+	if len(terms) == 0 {
+		return 1
+	}
+	return math.Round(100*float64(coveredCount(terms))/float64(len(terms))) / 100
+}
+
+// coveredCount counts the covered terms
+func coveredCount(terms []Term) int {
+	n := 0
+	for _, t := range terms {
+		if t.Covered {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,60 @@
+package ats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestAnalyze(t *testing.T) {
+	job := &types.JobProfile{
+		HardRequirements: []types.Requirement{{Skill: "Go"}, {Skill: "Kubernetes"}, {Skill: "C++"}},
+		Keywords:         []string{"go", "real-time", "CI/CD", "Terraform", "Terraform "},
+	}
+	text := "Built real time bidding in Go and C++ with a good CI CD pipeline; more Go services"
+
+	report := Analyze(text, job)
+
+	require.Len(t, report.HardRequirements, 3)
+	assert.Equal(t, Term{Term: "Go", Covered: true, Occurrences: 2}, report.HardRequirements[0], `"good" is not a match`)
+	assert.False(t, report.HardRequirements[1].Covered)
+	assert.True(t, report.HardRequirements[2].Covered)
+
+	require.Len(t, report.Keywords, 4, "repeated keywords are measured once")
+	assert.True(t, report.Keywords[1].Covered, "hyphens match spaces")
+	assert.True(t, report.Keywords[2].Covered, "slashes match spaces")
+
+	// go, kubernetes, c++, real-time, ci/cd, terraform: 4 of 6 distinct terms
+	assert.Equal(t, 67, report.Score)
+	assert.Equal(t, 0.67, report.RequirementCoverage)
+	assert.Equal(t, 0.75, report.KeywordCoverage)
+	assert.Equal(t, []string{"Kubernetes", "Terraform"}, report.Missing)
+	assert.Equal(t, "ATS coverage 67% (3/4 keywords, 2/3 hard requirements); missing Kubernetes, Terraform", report.Summary())
+}
+
+func TestAnalyze_NoTerms(t *testing.T) {
+	report := Analyze("anything", nil)
+	assert.Equal(t, 100, report.Score)
+	assert.Empty(t, report.Missing)
+	assert.NotNil(t, report.Keywords)
+}
+
+func TestResumeText(t *testing.T) {
+	tex := `\documentclass{article}
+\usepackage{kubernetes}
+\begin{document}
+\section{Experience}
+\begin{itemize}
+  \item Scaled \textbf{Kafka} pipelines R\&D % kubernetes in a comment
+\end{itemize}
+\end{document}`
+
+	text := ResumeText(tex)
+	assert.Contains(t, text, "Kafka")
+	assert.Contains(t, text, "R&D")
+	assert.NotContains(t, text, "textbf")
+	assert.NotContains(t, text, "kubernetes", "preamble and comments are not resume text")
+}
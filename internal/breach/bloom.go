@@ -0,0 +1,154 @@
+package breach
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// filterMagic starts every filter file, followed by the bits set per hash and the size in bits
+const filterMagic = "RCBF"
+
+// minFilterBits keeps tiny filters from reusing the same few bits for every hash
+const minFilterBits = 1024
+
+// Filter is a Bloom filter of password SHA-1 hashes, used to check passwords offline.
+// A miss means the password is not in the set; a hit is wrong at the filter's false
+// positive rate.
+type Filter struct {
+	k    uint32 // Bit positions per hash
+	m    uint64 // Filter size in bits
+	bits []byte
+}
+
+// NewFilter returns an empty filter sized for n hashes at the given false positive rate
+func NewFilter(n uint64, falsePositiveRate float64) *Filter {
+	n = max(n, 1)
+	bits := math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := uint32(max(math.Round(bits/float64(n)*math.Ln2), 1))
+	m := max(uint64(bits), minFilterBits)
+	return &Filter{k: k, m: m, bits: make([]byte, (m+7)/8)}
+}
+
+// Add adds a password's SHA-1 digest to the filter
+func (f *Filter) Add(digest [sha1.Size]byte) {
+	h1, h2 := digestHashes(digest)
+	for i := uint64(0); i < uint64(f.k); i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Contains reports whether a password's SHA-1 digest may have been added
+func (f *Filter) Contains(digest [sha1.Size]byte) bool {
+	h1, h2 := digestHashes(digest)
+	for i := uint64(0); i < uint64(f.k); i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// digestHashes splits a digest into the two hashes whose combinations pick the filter's
+// bits. SHA-1 output is uniform, so no further hashing is needed.
+func digestHashes(digest [sha1.Size]byte) (uint64, uint64) {
+	return binary.BigEndian.Uint64(digest[:8]), binary.BigEndian.Uint64(digest[8:16]) | 1
+}
+
+// WriteTo writes the filter in the format ReadFilter reads
+func (f *Filter) WriteTo(w io.Writer) (int64, error) {
+	header := make([]byte, len(filterMagic)+12)
+	copy(header, filterMagic)
+	binary.BigEndian.PutUint32(header[4:], f.k)
+	binary.BigEndian.PutUint64(header[8:], f.m)
+	n, err := w.Write(header)
+	if err != nil {
+		return int64(n), err
+	}
+	m, err := w.Write(f.bits)
+	return int64(n + m), err
+}
+
+// ReadFilter reads a filter written by WriteTo
+func ReadFilter(r io.Reader) (*Filter, error) {
+	header := make([]byte, len(filterMagic)+12)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read breach filter header: %w", err)
+	}
+	if string(header[:4]) != filterMagic {
+		return nil, errors.New("not a breach filter file")
+	}
+	f := &Filter{k: binary.BigEndian.Uint32(header[4:]), m: binary.BigEndian.Uint64(header[8:])}
+	if f.k == 0 || f.m == 0 {
+		return nil, errors.New("breach filter has no hashes or bits")
+	}
+	f.bits = make([]byte, (f.m+7)/8)
+	if _, err := io.ReadFull(r, f.bits); err != nil {
+		return nil, fmt.Errorf("failed to read breach filter bits: %w", err)
+	}
+	return f, nil
+}
+
+// LoadFilter reads a filter file
+func LoadFilter(path string) (*Filter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breach filter: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	return ReadFilter(bufio.NewReader(file))
+}
+
+// BuildFilter builds a filter from a Pwned Passwords SHA-1 download, one "HASH:COUNT" line
+// per password (the count is optional). The file is read twice: once to size the filter
+// and once to fill it.
+func BuildFilter(path string, falsePositiveRate float64) (*Filter, error) {
+	var n uint64
+	if err := eachHash(path, func([sha1.Size]byte) { n++ }); err != nil {
+		return nil, err
+	}
+	f := NewFilter(n, falsePositiveRate)
+	if err := eachHash(path, f.Add); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// eachHash calls fn with every hash in a Pwned Passwords file
+func eachHash(path string, fn func([sha1.Size]byte)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open password hashes: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		hash, _, _ := strings.Cut(text, ":")
+		var digest [sha1.Size]byte
+		if len(hash) != 2*sha1.Size {
+			return fmt.Errorf("line %d: not a SHA-1 hash: %q", line, hash)
+		}
+		if _, err := hex.Decode(digest[:], []byte(hash)); err != nil {
+			return fmt.Errorf("line %d: not a SHA-1 hash: %q", line, hash)
+		}
+		fn(digest)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read password hashes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Package breach checks whether a password has appeared in a known data breach, using
+// the Have I Been Pwned range API or an offline Bloom filter of breached password hashes.
+package breach
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the Have I Been Pwned Pwned Passwords API
+const DefaultAPIURL = "https://api.pwnedpasswords.com"
+
+// ErrUnavailable is returned when neither the API nor an offline filter could check a password
+var ErrUnavailable = errors.New("password breach check unavailable")
+
+// Checker looks passwords up in the Pwned Passwords range API, which is sent only the
+// first five hex characters of the password's SHA-1 hash (k-anonymity) and returns the
+// suffixes of every breached hash sharing them. When the API cannot be reached, or is
+// disabled, the offline filter answers instead.
+type Checker struct {
+	apiURL string // Empty checks offline only
+	filter *Filter
+	http   *http.Client
+}
+
+// NewChecker returns a checker for the API at apiURL, falling back to filter, which may be
+// nil. An empty apiURL checks against the filter alone.
+func NewChecker(apiURL string, filter *Filter, timeout time.Duration) *Checker {
+	return &Checker{
+		apiURL: strings.TrimRight(apiURL, "/"),
+		filter: filter,
+		http:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Breached reports whether password is a known breached password. It returns
+// ErrUnavailable, wrapping the API's error, when the API fails and there is no filter.
+func (c *Checker) Breached(ctx context.Context, password string) (bool, error) {
+	digest := sha1.Sum([]byte(password))
+	if c.apiURL != "" {
+		breached, err := c.lookup(ctx, digest)
+		if err == nil {
+			return breached, nil
+		}
+		if c.filter == nil {
+			return false, fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		slog.WarnContext(ctx, "Password breach API failed; using the offline filter", "error", err)
+	}
+	if c.filter == nil {
+		return false, ErrUnavailable
+	}
+	return c.filter.Contains(digest), nil
+}
+
+// lookup asks the range API whether digest is a breached hash. Responses are padded with
+// zero-count decoy suffixes, so their size does not reveal the prefix's bucket.
+func (c *Checker) lookup(ctx context.Context, digest [sha1.Size]byte) (bool, error) {
+	hash := strings.ToUpper(hex.EncodeToString(digest[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"/range/"+prefix, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create breach API request: %w", err)
+	}
+	req.Header.Set("Add-Padding", "true")
+	req.Header.Set("User-Agent", "resume-customizer")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("breach API request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("breach API returned %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(candidate, suffix) {
+			return strings.TrimSpace(count) != "0", nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read breach API response: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,112 @@
+package breach
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha1Hex(password string) string {
+	digest := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(digest[:]))
+}
+
+func TestChecker_API(t *testing.T) {
+	breached := sha1Hex("password123")
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+		fmt.Fprintf(w, "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n%s:2413945\r\n", breached[5:])
+		fmt.Fprintf(w, "%s:0\r\n", sha1Hex("padding-decoy")[5:])
+	}))
+	defer server.Close()
+
+	checker := NewChecker(server.URL, nil, time.Second)
+
+	isBreached, err := checker.Breached(context.Background(), "password123")
+	require.NoError(t, err)
+	assert.True(t, isBreached)
+	assert.Equal(t, "/range/"+breached[:5], paths[0], "only the hash prefix is sent")
+
+	isBreached, err = checker.Breached(context.Background(), "padding-decoy")
+	require.NoError(t, err)
+	assert.False(t, isBreached, "zero-count padding entries are not breaches")
+
+	isBreached, err = checker.Breached(context.Background(), "a long unique passphrase")
+	require.NoError(t, err)
+	assert.False(t, isBreached)
+}
+
+func TestChecker_FallsBackToFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewChecker(server.URL, nil, time.Second).Breached(context.Background(), "password123")
+	require.ErrorIs(t, err, ErrUnavailable)
+
+	filter := NewFilter(10, 0.001)
+	filter.Add(sha1.Sum([]byte("password123")))
+
+	for _, apiURL := range []string{server.URL, ""} {
+		checker := NewChecker(apiURL, filter, time.Second)
+		isBreached, err := checker.Breached(context.Background(), "password123")
+		require.NoError(t, err)
+		assert.True(t, isBreached)
+		isBreached, err = checker.Breached(context.Background(), "a long unique passphrase")
+		require.NoError(t, err)
+		assert.False(t, isBreached)
+	}
+}
+
+func TestBuildFilter(t *testing.T) {
+	passwords := []string{"password123", "qwerty", "letmein", "hunter2"}
+	var lines strings.Builder
+	for i, password := range passwords {
+		fmt.Fprintf(&lines, "%s:%d\n", sha1Hex(password), i+1)
+	}
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	require.NoError(t, os.WriteFile(path, []byte(lines.String()), 0o600))
+
+	filter, err := BuildFilter(path, 0.001)
+	require.NoError(t, err)
+
+	// The filter survives a write and read
+	var buf bytes.Buffer
+	_, err = filter.WriteTo(&buf)
+	require.NoError(t, err)
+	filter, err = ReadFilter(&buf)
+	require.NoError(t, err)
+
+	for _, password := range passwords {
+		assert.True(t, filter.Contains(sha1.Sum([]byte(password))), password)
+	}
+	misses := 0
+	for i := range 1000 {
+		if !filter.Contains(sha1.Sum([]byte(fmt.Sprintf("unbreached-%d", i)))) {
+			misses++
+		}
+	}
+	assert.Greater(t, misses, 990)
+
+	_, err = ReadFilter(strings.NewReader("not a filter"))
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("not-a-hash:1\n"), 0o600))
+	_, err = BuildFilter(path, 0.001)
+	assert.ErrorContains(t, err, "line 1")
+}
@@ -0,0 +1,67 @@
+// Package buildinfo reports the version, commit, and dependencies the running
+// binary was built from.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// Version and Commit are set at build time, e.g.
+//
+//	go build -ldflags "-X github.com/jonathan/resume-customizer/internal/buildinfo.Version=v1.4.0"
+//
+// Commit is only needed where the source has no .git directory (Docker builds);
+// otherwise the revision Go stamps into the binary is used.
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+// Info describes the running binary
+type Info struct {
+	Version      string            `json:"version"`
+	Commit       string            `json:"commit,omitempty"`
+	CommitTime   string            `json:"commit_time,omitempty"`
+	Modified     bool              `json:"modified,omitempty"` // Built from a tree with uncommitted changes
+	GoVersion    string            `json:"go_version"`
+	Dependencies map[string]string `json:"dependencies,omitempty"` // Module path -> version
+}
+
+var (
+	readOnce sync.Once
+	info     Info
+)
+
+// Read returns the binary's build information. It is computed once per process.
+func Read() Info {
+	readOnce.Do(func() {
+		info = Info{Version: Version, Commit: Commit, GoVersion: runtime.Version()}
+		bi, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				info.CommitTime = s.Value
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+		info.Dependencies = make(map[string]string, len(bi.Deps))
+		for _, dep := range bi.Deps {
+			version := dep.Version
+			if dep.Replace != nil {
+				version = "=> " + dep.Replace.Path + " " + dep.Replace.Version
+			}
+			info.Dependencies[dep.Path] = version
+		}
+	})
+	return info
+}
@@ -0,0 +1,119 @@
+// Package calendar renders application deadlines and follow-up dates as an iCalendar (.ics) feed.
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+const (
+	// eventDuration is the length of each calendar entry
+	eventDuration = 30 * time.Minute
+	// maxLineOctets is the RFC 5545 line length limit before folding
+	maxLineOctets = 75
+	icsTimeFormat = "20060102T150405Z"
+	// dueFormat writes a due date for people, in their own time zone
+	dueFormat = "Mon Jan 2, 15:04 MST"
+)
+
+// FormatDue writes a deadline or follow-up time in loc, e.g. "Tue Mar 10, 13:00 EDT"
+func FormatDue(at time.Time, loc *time.Location) string {
+	return at.In(loc).Format(dueFormat)
+}
+
+// Feed renders the deadline and follow-up dates of runs as an iCalendar document.
+// Each date becomes an event with a built-in alarm one day before. Event times are
+// UTC; loc is the owner's time zone, which calendar apps are told to display them in
+// and which event descriptions are written in.
+func Feed(name string, runs []db.Run, loc *time.Location, now time.Time) string {
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//resume-customizer//application deadlines//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+escapeText(name))
+	writeLine(&b, "X-WR-TIMEZONE:"+loc.String())
+
+	stamp := now.UTC().Format(icsTimeFormat)
+	for _, run := range runs {
+		if run.DeadlineAt != nil {
+			writeEvent(&b, run, db.ReminderDeadline, *run.DeadlineAt, loc, stamp)
+		}
+		if run.FollowUpAt != nil {
+			writeEvent(&b, run, db.ReminderFollowUp, *run.FollowUpAt, loc, stamp)
+		}
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// EventSummary describes a deadline or follow-up for a run, e.g. "Apply: SRE at Acme"
+func EventSummary(kind, company, roleTitle string) string {
+	verb := "Apply"
+	if kind == db.ReminderFollowUp {
+		verb = "Follow up"
+	}
+	if roleTitle == "" {
+		roleTitle = "Application"
+	}
+	if company == "" {
+		return fmt.Sprintf("%s: %s", verb, roleTitle)
+	}
+	return fmt.Sprintf("%s: %s at %s", verb, roleTitle, company)
+}
+
+// writeEvent writes one VEVENT
+func writeEvent(b *strings.Builder, run db.Run, kind string, at time.Time, loc *time.Location, stamp string) {
+	summary := EventSummary(kind, run.Company, run.RoleTitle)
+
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, fmt.Sprintf("UID:%s-%s@resume-customizer", run.ID, kind))
+	writeLine(b, "DTSTAMP:"+stamp)
+	writeLine(b, "DTSTART:"+at.UTC().Format(icsTimeFormat))
+	writeLine(b, "DTEND:"+at.Add(eventDuration).UTC().Format(icsTimeFormat))
+	writeLine(b, "SUMMARY:"+escapeText(summary))
+	description := "Due: " + FormatDue(at, loc)
+	if run.JobURL != "" {
+		writeLine(b, "URL:"+run.JobURL)
+		description += "\nJob posting: " + run.JobURL
+	}
+	writeLine(b, "DESCRIPTION:"+escapeText(description))
+	writeLine(b, "BEGIN:VALARM")
+	writeLine(b, "ACTION:DISPLAY")
+	writeLine(b, "TRIGGER:-P1D")
+	writeLine(b, "DESCRIPTION:"+escapeText(summary))
+	writeLine(b, "END:VALARM")
+	writeLine(b, "END:VEVENT")
+}
+
+// escapeText escapes an iCalendar TEXT value
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeLine writes a content line, folding it at 75 octets without splitting UTF-8 sequences
+func writeLine(b *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // Continuation lines start with a space
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// isRuneStart reports whether c begins a UTF-8 sequence
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestFeed(t *testing.T) {
+	deadline := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+	followUp := time.Date(2026, 3, 24, 9, 30, 0, 0, time.UTC)
+	run := db.Run{
+		ID:         uuid.MustParse("11111111-2222-3333-4444-555555555555"),
+		Company:    "Acme, Inc.",
+		RoleTitle:  "Backend Engineer",
+		JobURL:     "https://jobs.acme.com/123",
+		DeadlineAt: &deadline,
+		FollowUpAt: &followUp,
+	}
+
+	feed := Feed("Jane's applications", []db.Run{run, {ID: uuid.New(), Company: "NoDates"}},
+		time.UTC, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.True(t, strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(feed, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(feed, "BEGIN:VEVENT"), "runs without dates are skipped")
+	unfolded := strings.ReplaceAll(feed, "\r\n ", "")
+	assert.Contains(t, unfolded, "UID:11111111-2222-3333-4444-555555555555-application_deadline@resume-customizer\r\n")
+	assert.Contains(t, feed, "DTSTART:20260310T170000Z\r\n")
+	assert.Contains(t, feed, "DTEND:20260310T173000Z\r\n")
+	assert.Contains(t, feed, `SUMMARY:Apply: Backend Engineer at Acme\, Inc.`)
+	assert.Contains(t, feed, `SUMMARY:Follow up: Backend Engineer at Acme\, Inc.`)
+	assert.Contains(t, feed, "DTSTAMP:20260301T000000Z\r\n")
+	assert.Contains(t, feed, "TRIGGER:-P1D\r\n")
+	assert.Contains(t, feed, "X-WR-TIMEZONE:UTC\r\n")
+}
+
+func TestFeed_UserTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	deadline := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+	run := db.Run{ID: uuid.New(), Company: "Acme", DeadlineAt: &deadline}
+
+	feed := Feed("Applications", []db.Run{run}, loc, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.Contains(t, feed, "X-WR-TIMEZONE:America/New_York\r\n")
+	assert.Contains(t, feed, "DTSTART:20260310T170000Z\r\n", "event times stay in UTC")
+	assert.Contains(t, feed, `DESCRIPTION:Due: Tue Mar 10\, 13:00 EDT`)
+}
+
+func TestWriteLine_Folds(t *testing.T) {
+	var b strings.Builder
+	writeLine(&b, "SUMMARY:"+strings.Repeat("é", 60))
+
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+		assert.True(t, strings.ToValidUTF8(line, "?") == line, "fold split a UTF-8 sequence")
+	}
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("é", 60)+"\r\n", unfolded)
+}
+
+func TestEventSummary(t *testing.T) {
+	assert.Equal(t, "Apply: SRE at Acme", EventSummary(db.ReminderDeadline, "Acme", "SRE"))
+	assert.Equal(t, "Follow up: Application", EventSummary(db.ReminderFollowUp, "", ""))
+}
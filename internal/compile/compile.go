@@ -0,0 +1,105 @@
+// Package compile turns rendered LaTeX into a PDF with the toolchain's LaTeX compiler:
+// pdflatex, latexmk, or tectonic, as chosen by LATEX_ENGINE and LATEX_PATH.
+package compile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/toolchain"
+)
+
+// Timeout bounds a single compilation. latexmk may run the engine several times, and
+// tectonic downloads missing packages on first use.
+const Timeout = 60 * time.Second
+
+// Result is a compiled PDF and the compiler's output
+type Result struct {
+	PDF    []byte
+	Log    string
+	Engine string // config.LaTeXEngine* value of the compiler used
+}
+
+// Error is a failed or partial compilation. Errors from a missing compiler wrap
+// toolchain.ErrUnavailable.
+type Error struct {
+	Message string
+	Log     string // Compiler output, if it ran
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// PDF compiles LaTeX source into a PDF. When the compiler reports errors but still
+// writes a PDF, as LaTeX often does, both the Result and an *Error are returned so
+// callers can decide whether an imperfect PDF is usable.
+func PDF(ctx context.Context, latex string) (*Result, error) {
+	tools, err := toolchain.Default()
+	if err != nil {
+		return nil, &Error{Message: "invalid toolchain configuration", Err: err}
+	}
+	return run(ctx, tools.LaTeX, latex)
+}
+
+// run compiles latex with compiler in a scratch directory
+func run(ctx context.Context, compiler *toolchain.LaTeX, latex string) (*Result, error) {
+	if compiler == nil {
+		return nil, &Error{
+			Message: "no LaTeX compiler found; install TeX Live, MiKTeX, or tectonic, or set LATEX_PATH",
+			Err:     toolchain.ErrUnavailable,
+		}
+	}
+
+	workDir, err := os.MkdirTemp("", "latex-compile-*")
+	if err != nil {
+		return nil, &Error{Message: "failed to create working directory", Err: err}
+	}
+	defer func() { _ = os.RemoveAll(workDir) }()
+
+	texPath := filepath.Join(workDir, "resume.tex")
+	if err := os.WriteFile(texPath, []byte(latex), 0644); err != nil {
+		return nil, &Error{Message: "failed to write LaTeX source", Err: err}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+
+	var output strings.Builder
+	cmd := exec.CommandContext(ctx, compiler.Path, compiler.Args(texPath, workDir)...)
+	cmd.Dir = workDir
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		runErr = fmt.Errorf("timed out after %s", Timeout)
+	}
+
+	pdf, err := os.ReadFile(filepath.Join(workDir, "resume.pdf"))
+	if err != nil {
+		if runErr == nil {
+			runErr = err
+		}
+		return nil, &Error{Message: "LaTeX compilation failed: no PDF was produced", Log: output.String(), Err: runErr}
+	}
+
+	result := &Result{PDF: pdf, Log: output.String(), Engine: compiler.Engine}
+	if runErr != nil {
+		return result, &Error{Message: "LaTeX compilation reported errors (PDF may be incomplete)", Log: result.Log, Err: runErr}
+	}
+	return result, nil
+}
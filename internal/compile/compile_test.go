@@ -0,0 +1,65 @@
+package compile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/toolchain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCompiler writes a pdflatex stand-in that runs script with the output
+// directory as $3, matching the arguments LaTeX.Args passes pdflatex
+func fakeCompiler(t *testing.T, script string) *toolchain.LaTeX {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake compiler is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "pdflatex")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+	return &toolchain.LaTeX{Engine: config.LaTeXEnginePdflatex, Path: path}
+}
+
+func TestRun_ProducesPDF(t *testing.T) {
+	compiler := fakeCompiler(t, `echo "Output written"; printf '%%PDF-1.5' > "$3/resume.pdf"`)
+
+	result, err := run(context.Background(), compiler, `\documentclass{article}`)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("%PDF-1.5"), result.PDF)
+	assert.Contains(t, result.Log, "Output written")
+	assert.Equal(t, config.LaTeXEnginePdflatex, result.Engine)
+}
+
+func TestRun_PartialPDF(t *testing.T) {
+	compiler := fakeCompiler(t, `printf '%%PDF-1.5' > "$3/resume.pdf"; echo "! Undefined control sequence."; exit 1`)
+
+	result, err := run(context.Background(), compiler, `\badmacro`)
+	require.Error(t, err)
+	require.NotNil(t, result, "a PDF written despite errors is still returned")
+	assert.NotEmpty(t, result.PDF)
+
+	var compileErr *Error
+	require.True(t, errors.As(err, &compileErr))
+	assert.Contains(t, compileErr.Log, "Undefined control sequence")
+}
+
+func TestRun_NoPDF(t *testing.T) {
+	compiler := fakeCompiler(t, `echo "Emergency stop."; exit 1`)
+
+	result, err := run(context.Background(), compiler, `\badmacro`)
+	assert.Nil(t, result)
+	var compileErr *Error
+	require.True(t, errors.As(err, &compileErr))
+	assert.Contains(t, compileErr.Log, "Emergency stop.")
+}
+
+func TestRun_NoCompiler(t *testing.T) {
+	_, err := run(context.Background(), nil, `\documentclass{article}`)
+	assert.ErrorIs(t, err, toolchain.ErrUnavailable)
+}
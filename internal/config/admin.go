@@ -0,0 +1,54 @@
+// Package config provides admin configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// AdminConfig lists the users who manage server-wide settings such as global domain crawl policies
+// and who may enable debug mode on any user's run.
+type AdminConfig struct {
+	// UserIDs are the admin users
+	UserIDs map[uuid.UUID]bool
+}
+
+// NewAdminConfig creates a new admin configuration from environment variables.
+// It reads ADMIN_USER_IDS (comma-separated UUIDs, default: none), falling back to the
+// older DEBUG_ADMIN_USER_IDS when ADMIN_USER_IDS is empty.
+func NewAdminConfig() (*AdminConfig, error) {
+	name := "ADMIN_USER_IDS"
+	if os.Getenv(name) == "" {
+		name = "DEBUG_ADMIN_USER_IDS"
+	}
+	ids, err := parseUserIDs(name, os.Getenv(name))
+	if err != nil {
+		return nil, err
+	}
+	return &AdminConfig{UserIDs: ids}, nil
+}
+
+// IsAdmin reports whether the user may manage server-wide settings and debug any run.
+func (c *AdminConfig) IsAdmin(userID uuid.UUID) bool {
+	return c != nil && c.UserIDs[userID]
+}
+
+// parseUserIDs parses a comma-separated list of user UUIDs from the named variable
+func parseUserIDs(name, value string) (map[uuid.UUID]bool, error) {
+	ids := make(map[uuid.UUID]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := uuid.Parse(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q: %v", name, part, err)
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
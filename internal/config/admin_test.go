@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAdminConfig_DefaultValues(t *testing.T) {
+	t.Setenv("ADMIN_USER_IDS", "")
+	t.Setenv("DEBUG_ADMIN_USER_IDS", "")
+
+	cfg, err := NewAdminConfig()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.UserIDs)
+	assert.False(t, cfg.IsAdmin(uuid.New()))
+}
+
+func TestNewAdminConfig_UserIDs(t *testing.T) {
+	a := uuid.New()
+	b := uuid.New()
+	t.Setenv("ADMIN_USER_IDS", a.String()+", "+b.String()+",")
+
+	cfg, err := NewAdminConfig()
+	require.NoError(t, err)
+	assert.True(t, cfg.IsAdmin(a))
+	assert.True(t, cfg.IsAdmin(b))
+	assert.False(t, cfg.IsAdmin(uuid.New()))
+}
+
+func TestNewAdminConfig_DebugAdminFallback(t *testing.T) {
+	a := uuid.New()
+	t.Setenv("ADMIN_USER_IDS", "")
+	t.Setenv("DEBUG_ADMIN_USER_IDS", a.String())
+
+	t.Run("used when ADMIN_USER_IDS is empty", func(t *testing.T) {
+		cfg, err := NewAdminConfig()
+		require.NoError(t, err)
+		assert.True(t, cfg.IsAdmin(a))
+	})
+
+	t.Run("ignored when ADMIN_USER_IDS is set", func(t *testing.T) {
+		b := uuid.New()
+		t.Setenv("ADMIN_USER_IDS", b.String())
+
+		cfg, err := NewAdminConfig()
+		require.NoError(t, err)
+		assert.True(t, cfg.IsAdmin(b))
+		assert.False(t, cfg.IsAdmin(a))
+	})
+}
+
+func TestNewAdminConfig_InvalidUserID(t *testing.T) {
+	t.Setenv("ADMIN_USER_IDS", "not-a-uuid")
+
+	_, err := NewAdminConfig()
+	assert.Error(t, err)
+}
+
+func TestAdminConfig_NilIsAdmin(t *testing.T) {
+	var cfg *AdminConfig
+	assert.False(t, cfg.IsAdmin(uuid.New()))
+}
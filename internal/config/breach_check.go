@@ -0,0 +1,75 @@
+// Package config provides password breach check configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// BreachCheckConfig holds settings for rejecting passwords found in known data breaches.
+type BreachCheckConfig struct {
+	// Enabled turns the check on for registration and password changes
+	Enabled bool
+	// APIURL is the Pwned Passwords API base URL. Empty checks against FilterPath only.
+	APIURL string
+	// FilterPath is an offline Bloom filter of breached password hashes, used when the API
+	// is unreachable or disabled (see `resume_agent build-breach-filter`)
+	FilterPath string
+	// TimeoutSeconds bounds each API request
+	TimeoutSeconds int
+}
+
+// NewBreachCheckConfig creates a new breach check configuration from environment variables.
+// It reads PASSWORD_BREACH_CHECK (default: true), PASSWORD_BREACH_API_URL (default:
+// api.pwnedpasswords.com; set empty to check offline only), PASSWORD_BREACH_FILTER
+// (default: none), and PASSWORD_BREACH_TIMEOUT_SECONDS (default: 3).
+func NewBreachCheckConfig() (*BreachCheckConfig, error) {
+	config := &BreachCheckConfig{
+		Enabled:        true,
+		APIURL:         "https://api.pwnedpasswords.com",
+		FilterPath:     os.Getenv("PASSWORD_BREACH_FILTER"),
+		TimeoutSeconds: 3,
+	}
+
+	if v := os.Getenv("PASSWORD_BREACH_CHECK"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PASSWORD_BREACH_CHECK: %v", err)
+		}
+		config.Enabled = enabled
+	}
+	if v, ok := os.LookupEnv("PASSWORD_BREACH_API_URL"); ok {
+		config.APIURL = v
+	}
+	if v := os.Getenv("PASSWORD_BREACH_TIMEOUT_SECONDS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PASSWORD_BREACH_TIMEOUT_SECONDS: %v", err)
+		}
+		config.TimeoutSeconds = n
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *BreachCheckConfig) normalize() error {
+	if c.TimeoutSeconds < 1 {
+		return fmt.Errorf("PASSWORD_BREACH_TIMEOUT_SECONDS must be at least 1, got: %d", c.TimeoutSeconds)
+	}
+	if c.Enabled && c.APIURL == "" && c.FilterPath == "" {
+		return fmt.Errorf("PASSWORD_BREACH_CHECK needs PASSWORD_BREACH_API_URL or PASSWORD_BREACH_FILTER")
+	}
+	return nil
+}
+
+// Timeout returns the API request timeout as a duration.
+func (c *BreachCheckConfig) Timeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
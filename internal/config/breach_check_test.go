@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearBreachCheckEnv(t *testing.T) {
+	for _, key := range []string{"PASSWORD_BREACH_CHECK", "PASSWORD_BREACH_API_URL", "PASSWORD_BREACH_FILTER", "PASSWORD_BREACH_TIMEOUT_SECONDS"} {
+		t.Setenv(key, "") // Restored after the test
+		require.NoError(t, os.Unsetenv(key))
+	}
+}
+
+func TestNewBreachCheckConfig_DefaultValues(t *testing.T) {
+	clearBreachCheckEnv(t)
+
+	cfg, err := NewBreachCheckConfig()
+	require.NoError(t, err)
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, "https://api.pwnedpasswords.com", cfg.APIURL)
+	assert.Empty(t, cfg.FilterPath)
+	assert.Equal(t, 3*time.Second, cfg.Timeout())
+}
+
+func TestNewBreachCheckConfig_OfflineOnly(t *testing.T) {
+	clearBreachCheckEnv(t)
+	t.Setenv("PASSWORD_BREACH_API_URL", "")
+	t.Setenv("PASSWORD_BREACH_FILTER", "/data/pwned.bloom")
+
+	cfg, err := NewBreachCheckConfig()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.APIURL)
+	assert.Equal(t, "/data/pwned.bloom", cfg.FilterPath)
+}
+
+func TestNewBreachCheckConfig_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"non-boolean check", map[string]string{"PASSWORD_BREACH_CHECK": "sometimes"}},
+		{"zero timeout", map[string]string{"PASSWORD_BREACH_TIMEOUT_SECONDS": "0"}},
+		{"nothing to check against", map[string]string{"PASSWORD_BREACH_API_URL": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearBreachCheckEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := NewBreachCheckConfig()
+			assert.Error(t, err)
+		})
+	}
+
+	// Disabled needs nothing to check against
+	clearBreachCheckEnv(t)
+	t.Setenv("PASSWORD_BREACH_CHECK", "false")
+	t.Setenv("PASSWORD_BREACH_API_URL", "")
+	_, err := NewBreachCheckConfig()
+	assert.NoError(t, err)
+}
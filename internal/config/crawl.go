@@ -0,0 +1,112 @@
+// Package config provides research crawl budget configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// CrawlConfig holds the default depth, scope, and budget limits for company research crawls.
+// Individual runs may override any of them.
+type CrawlConfig struct {
+	// MaxPages is the number of pages processed per research session
+	MaxPages int
+	// MaxDepth is how many links away from a seed URL the crawler may follow (0 = seeds only)
+	MaxDepth int
+	// SameDomainOnly restricts crawling to the company's own domains
+	SameDomainOnly bool
+	// MaxBytes caps the total HTML downloaded per session. Zero means unlimited.
+	MaxBytes int64
+	// ArchiveFallback reads dead (404) or bot-blocked pages from their latest Internet Archive snapshot
+	ArchiveFallback bool
+	// ProfileMaxAgeDays is how old a company profile another run researched may be and still be
+	// reused instead of crawling again. Zero means every run crawls.
+	ProfileMaxAgeDays int
+}
+
+// NewCrawlConfig creates a new crawl configuration from environment variables.
+// It reads CRAWL_MAX_PAGES (default: 5), CRAWL_MAX_DEPTH (default: 1),
+// CRAWL_SAME_DOMAIN_ONLY (default: true), CRAWL_MAX_BYTES (default: 5242880, 0 for unlimited),
+// CRAWL_ARCHIVE_FALLBACK (default: false), and CRAWL_PROFILE_MAX_AGE_DAYS (default: 30).
+func NewCrawlConfig() (*CrawlConfig, error) {
+	config := &CrawlConfig{
+		MaxPages:       5,
+		MaxDepth:       1,
+		SameDomainOnly: true,
+		MaxBytes:       5 << 20,
+
+		ProfileMaxAgeDays: 30,
+	}
+
+	if v := os.Getenv("CRAWL_MAX_PAGES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRAWL_MAX_PAGES: %v", err)
+		}
+		config.MaxPages = n
+	}
+
+	if v := os.Getenv("CRAWL_MAX_DEPTH"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRAWL_MAX_DEPTH: %v", err)
+		}
+		config.MaxDepth = n
+	}
+
+	if v := os.Getenv("CRAWL_SAME_DOMAIN_ONLY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRAWL_SAME_DOMAIN_ONLY: %v", err)
+		}
+		config.SameDomainOnly = b
+	}
+
+	if v := os.Getenv("CRAWL_MAX_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRAWL_MAX_BYTES: %v", err)
+		}
+		config.MaxBytes = n
+	}
+
+	if v := os.Getenv("CRAWL_ARCHIVE_FALLBACK"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRAWL_ARCHIVE_FALLBACK: %v", err)
+		}
+		config.ArchiveFallback = b
+	}
+
+	if v := os.Getenv("CRAWL_PROFILE_MAX_AGE_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRAWL_PROFILE_MAX_AGE_DAYS: %v", err)
+		}
+		config.ProfileMaxAgeDays = n
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *CrawlConfig) normalize() error {
+	if c.MaxPages < 1 {
+		return fmt.Errorf("CRAWL_MAX_PAGES must be at least 1, got: %d", c.MaxPages)
+	}
+	if c.MaxDepth < 0 {
+		return fmt.Errorf("CRAWL_MAX_DEPTH must not be negative, got: %d", c.MaxDepth)
+	}
+	if c.MaxBytes < 0 {
+		return fmt.Errorf("CRAWL_MAX_BYTES must not be negative, got: %d", c.MaxBytes)
+	}
+	if c.ProfileMaxAgeDays < 0 {
+		return fmt.Errorf("CRAWL_PROFILE_MAX_AGE_DAYS must not be negative, got: %d", c.ProfileMaxAgeDays)
+	}
+	return nil
+}
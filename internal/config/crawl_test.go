@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearCrawlEnv(t *testing.T) {
+	t.Setenv("CRAWL_MAX_PAGES", "")
+	t.Setenv("CRAWL_MAX_DEPTH", "")
+	t.Setenv("CRAWL_SAME_DOMAIN_ONLY", "")
+	t.Setenv("CRAWL_MAX_BYTES", "")
+	t.Setenv("CRAWL_ARCHIVE_FALLBACK", "")
+	t.Setenv("CRAWL_PROFILE_MAX_AGE_DAYS", "")
+}
+
+func TestNewCrawlConfig_DefaultValues(t *testing.T) {
+	clearCrawlEnv(t)
+
+	cfg, err := NewCrawlConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 5, cfg.MaxPages)
+	assert.Equal(t, 1, cfg.MaxDepth)
+	assert.True(t, cfg.SameDomainOnly)
+	assert.Equal(t, int64(5<<20), cfg.MaxBytes)
+	assert.False(t, cfg.ArchiveFallback)
+	assert.Equal(t, 30, cfg.ProfileMaxAgeDays)
+}
+
+func TestNewCrawlConfig_CustomValues(t *testing.T) {
+	clearCrawlEnv(t)
+	t.Setenv("CRAWL_MAX_PAGES", "12")
+	t.Setenv("CRAWL_MAX_DEPTH", "0")
+	t.Setenv("CRAWL_SAME_DOMAIN_ONLY", "false")
+	t.Setenv("CRAWL_MAX_BYTES", "0")
+	t.Setenv("CRAWL_ARCHIVE_FALLBACK", "true")
+	t.Setenv("CRAWL_PROFILE_MAX_AGE_DAYS", "0")
+
+	cfg, err := NewCrawlConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 12, cfg.MaxPages)
+	assert.Equal(t, 0, cfg.MaxDepth)
+	assert.False(t, cfg.SameDomainOnly)
+	assert.Equal(t, int64(0), cfg.MaxBytes)
+	assert.True(t, cfg.ArchiveFallback)
+	assert.Equal(t, 0, cfg.ProfileMaxAgeDays)
+}
+
+func TestNewCrawlConfig_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		val  string
+	}{
+		{"non-numeric pages", "CRAWL_MAX_PAGES", "abc"},
+		{"zero pages", "CRAWL_MAX_PAGES", "0"},
+		{"negative depth", "CRAWL_MAX_DEPTH", "-1"},
+		{"bad bool", "CRAWL_SAME_DOMAIN_ONLY", "maybe"},
+		{"negative bytes", "CRAWL_MAX_BYTES", "-5"},
+		{"bad archive bool", "CRAWL_ARCHIVE_FALLBACK", "sometimes"},
+		{"negative profile age", "CRAWL_PROFILE_MAX_AGE_DAYS", "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearCrawlEnv(t)
+			t.Setenv(tt.key, tt.val)
+
+			_, err := NewCrawlConfig()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Package config provides dead letter alerting configuration functionality.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+// DeadLetterConfig controls alerting on failed background work held for operators.
+type DeadLetterConfig struct {
+	// AlertThreshold is the number of pending dead letters at which an alert is raised.
+	// Zero disables alerting.
+	AlertThreshold int
+	// AlertWebhookURL receives a JSON alert when the threshold is crossed (optional;
+	// alerts are always logged)
+	AlertWebhookURL string
+}
+
+// NewDeadLetterConfig creates a new dead letter configuration from environment variables.
+// It reads DLQ_ALERT_THRESHOLD (default: 25, 0 to disable) and DLQ_ALERT_WEBHOOK_URL
+// (default: none).
+func NewDeadLetterConfig() (*DeadLetterConfig, error) {
+	config := &DeadLetterConfig{
+		AlertThreshold:  25,
+		AlertWebhookURL: os.Getenv("DLQ_ALERT_WEBHOOK_URL"),
+	}
+
+	if v := os.Getenv("DLQ_ALERT_THRESHOLD"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DLQ_ALERT_THRESHOLD: %v", err)
+		}
+		config.AlertThreshold = n
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *DeadLetterConfig) normalize() error {
+	if c.AlertThreshold < 0 {
+		return fmt.Errorf("DLQ_ALERT_THRESHOLD must not be negative, got: %d", c.AlertThreshold)
+	}
+	if c.AlertWebhookURL != "" {
+		u, err := url.Parse(c.AlertWebhookURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("DLQ_ALERT_WEBHOOK_URL must be an http(s) URL, got: %q", c.AlertWebhookURL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeadLetterConfig_DefaultValues(t *testing.T) {
+	t.Setenv("DLQ_ALERT_THRESHOLD", "")
+	t.Setenv("DLQ_ALERT_WEBHOOK_URL", "")
+
+	cfg, err := NewDeadLetterConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 25, cfg.AlertThreshold)
+	assert.Empty(t, cfg.AlertWebhookURL)
+}
+
+func TestNewDeadLetterConfig_CustomValues(t *testing.T) {
+	t.Setenv("DLQ_ALERT_THRESHOLD", "0")
+	t.Setenv("DLQ_ALERT_WEBHOOK_URL", "https://alerts.example.com/hook")
+
+	cfg, err := NewDeadLetterConfig()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.AlertThreshold, "zero disables alerting")
+	assert.Equal(t, "https://alerts.example.com/hook", cfg.AlertWebhookURL)
+}
+
+func TestNewDeadLetterConfig_InvalidValues(t *testing.T) {
+	tests := map[string][2]string{
+		"non-numeric threshold": {"many", ""},
+		"negative threshold":    {"-1", ""},
+		"webhook without host":  {"", "https://"},
+		"webhook bad scheme":    {"", "ftp://alerts.example.com"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("DLQ_ALERT_THRESHOLD", tt[0])
+			t.Setenv("DLQ_ALERT_WEBHOOK_URL", tt[1])
+			_, err := NewDeadLetterConfig()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Package config provides debug mode configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DebugConfig holds configuration for per-run debug capture of raw LLM prompts and responses.
+type DebugConfig struct {
+	// RetentionHours is how long debug artifacts are kept before cleanup
+	RetentionHours int
+}
+
+// NewDebugConfig creates a new debug configuration from environment variables.
+// It reads DEBUG_RETENTION_HOURS (default: 72). Debug admins come from AdminConfig.
+func NewDebugConfig() (*DebugConfig, error) {
+	config := &DebugConfig{
+		RetentionHours: 72,
+	}
+
+	if retentionStr := os.Getenv("DEBUG_RETENTION_HOURS"); retentionStr != "" {
+		hours, err := strconv.Atoi(retentionStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DEBUG_RETENTION_HOURS: %v", err)
+		}
+		config.RetentionHours = hours
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *DebugConfig) normalize() error {
+	if c.RetentionHours < 1 {
+		return fmt.Errorf("DEBUG_RETENTION_HOURS must be at least 1 hour, got: %d", c.RetentionHours)
+	}
+	return nil
+}
+
+// Retention returns the debug artifact retention period as a duration.
+func (c *DebugConfig) Retention() time.Duration {
+	return time.Duration(c.RetentionHours) * time.Hour
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDebugConfig_DefaultValues(t *testing.T) {
+	t.Setenv("DEBUG_RETENTION_HOURS", "")
+
+	cfg, err := NewDebugConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 72, cfg.RetentionHours)
+	assert.Equal(t, 72*time.Hour, cfg.Retention())
+}
+
+func TestNewDebugConfig_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention string
+	}{
+		{name: "non-numeric retention", retention: "abc"},
+		{name: "zero retention", retention: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEBUG_RETENTION_HOURS", tt.retention)
+
+			_, err := NewDebugConfig()
+			assert.Error(t, err)
+		})
+	}
+}
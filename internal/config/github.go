@@ -0,0 +1,74 @@
+// Package config provides GitHub integration configuration functionality.
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GitHubConfig holds settings for importing project bullets from users' GitHub profiles.
+type GitHubConfig struct {
+	// APIURL is the GitHub API base URL (empty uses api.github.com)
+	APIURL string
+	// SyncIntervalMinutes is the minimum time between two syncs of one user's repositories
+	SyncIntervalMinutes int
+	// TokenKey encrypts stored access tokens. Without it users cannot save a token and
+	// syncs use GitHub's unauthenticated API.
+	TokenKey []byte
+}
+
+// NewGitHubConfig creates a new GitHub configuration from environment variables.
+// It reads GITHUB_API_URL (default: api.github.com), GITHUB_SYNC_INTERVAL_MINUTES
+// (default: 60), and GITHUB_TOKEN_KEY (base64-encoded 32-byte key, default: none).
+func NewGitHubConfig() (*GitHubConfig, error) {
+	config := &GitHubConfig{
+		APIURL:              os.Getenv("GITHUB_API_URL"),
+		SyncIntervalMinutes: 60,
+	}
+
+	if v := os.Getenv("GITHUB_SYNC_INTERVAL_MINUTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GITHUB_SYNC_INTERVAL_MINUTES: %v", err)
+		}
+		config.SyncIntervalMinutes = n
+	}
+
+	if v := os.Getenv("GITHUB_TOKEN_KEY"); v != "" {
+		key, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GITHUB_TOKEN_KEY: %v", err)
+		}
+		config.TokenKey = key
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *GitHubConfig) normalize() error {
+	if c.SyncIntervalMinutes < 1 {
+		return fmt.Errorf("GITHUB_SYNC_INTERVAL_MINUTES must be at least 1 minute, got: %d", c.SyncIntervalMinutes)
+	}
+	if c.TokenKey != nil && len(c.TokenKey) != 32 {
+		return fmt.Errorf("GITHUB_TOKEN_KEY must decode to 32 bytes, got: %d", len(c.TokenKey))
+	}
+	return nil
+}
+
+// TokensEnabled reports whether access tokens can be stored.
+func (c *GitHubConfig) TokensEnabled() bool {
+	return c != nil && len(c.TokenKey) > 0
+}
+
+// SyncInterval returns the minimum time between syncs as a duration.
+func (c *GitHubConfig) SyncInterval() time.Duration {
+	return time.Duration(c.SyncIntervalMinutes) * time.Minute
+}
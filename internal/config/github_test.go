@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearGitHubEnv(t *testing.T) {
+	for _, key := range []string{"GITHUB_API_URL", "GITHUB_SYNC_INTERVAL_MINUTES", "GITHUB_TOKEN_KEY"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestNewGitHubConfig_DefaultValues(t *testing.T) {
+	clearGitHubEnv(t)
+
+	cfg, err := NewGitHubConfig()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.APIURL)
+	assert.Equal(t, time.Hour, cfg.SyncInterval())
+	assert.False(t, cfg.TokensEnabled())
+}
+
+func TestNewGitHubConfig_CustomValues(t *testing.T) {
+	clearGitHubEnv(t)
+	t.Setenv("GITHUB_API_URL", "http://localhost:9000")
+	t.Setenv("GITHUB_SYNC_INTERVAL_MINUTES", "15")
+	t.Setenv("GITHUB_TOKEN_KEY", base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+
+	cfg, err := NewGitHubConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:9000", cfg.APIURL)
+	assert.Equal(t, 15*time.Minute, cfg.SyncInterval())
+	assert.True(t, cfg.TokensEnabled())
+}
+
+func TestNewGitHubConfig_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"non-numeric interval", map[string]string{"GITHUB_SYNC_INTERVAL_MINUTES": "soon"}},
+		{"zero interval", map[string]string{"GITHUB_SYNC_INTERVAL_MINUTES": "0"}},
+		{"key not base64", map[string]string{"GITHUB_TOKEN_KEY": "not base64!"}},
+		{"short key", map[string]string{"GITHUB_TOKEN_KEY": base64.StdEncoding.EncodeToString([]byte("short"))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearGitHubEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := NewGitHubConfig()
+			assert.Error(t, err)
+		})
+	}
+}
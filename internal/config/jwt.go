@@ -9,12 +9,14 @@ import (
 
 // JWTConfig holds configuration for JWT token generation and validation.
 type JWTConfig struct {
-	Secret          string
-	ExpirationHours int
+	Secret                 string
+	ExpirationHours        int
+	RefreshExpirationHours int
 }
 
 // NewJWTConfig creates a new JWT configuration from environment variables.
-// It reads JWT_SECRET (required) and JWT_EXPIRATION_HOURS (default: 24).
+// It reads JWT_SECRET (required), JWT_EXPIRATION_HOURS (default: 24), and
+// JWT_REFRESH_EXPIRATION_HOURS (default: 720).
 func NewJWTConfig() (*JWTConfig, error) {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
@@ -31,9 +33,20 @@ func NewJWTConfig() (*JWTConfig, error) {
 		return nil, fmt.Errorf("invalid JWT_EXPIRATION_HOURS: %v", err)
 	}
 
+	refreshStr := os.Getenv("JWT_REFRESH_EXPIRATION_HOURS")
+	if refreshStr == "" {
+		refreshStr = "720" // default: 30 days
+	}
+
+	refreshHours, err := strconv.Atoi(refreshStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_REFRESH_EXPIRATION_HOURS: %v", err)
+	}
+
 	config := &JWTConfig{
-		Secret:          secret,
-		ExpirationHours: expirationHours,
+		Secret:                 secret,
+		ExpirationHours:        expirationHours,
+		RefreshExpirationHours: refreshHours,
 	}
 
 	if err := config.normalize(); err != nil {
@@ -51,5 +64,9 @@ func (c *JWTConfig) normalize() error {
 	if c.ExpirationHours < 1 {
 		return fmt.Errorf("JWT_EXPIRATION_HOURS must be at least 1 hour, got: %d", c.ExpirationHours)
 	}
+	if c.RefreshExpirationHours < c.ExpirationHours {
+		return fmt.Errorf("JWT_REFRESH_EXPIRATION_HOURS must be at least JWT_EXPIRATION_HOURS (%d), got: %d",
+			c.ExpirationHours, c.RefreshExpirationHours)
+	}
 	return nil
 }
@@ -34,6 +34,7 @@ func TestNewJWTConfig_DefaultValues(t *testing.T) {
 	require.NotNil(t, cfg)
 	assert.Equal(t, "test-secret-key", cfg.Secret)
 	assert.Equal(t, 24, cfg.ExpirationHours, "should use default expiration of 24 hours")
+	assert.Equal(t, 720, cfg.RefreshExpirationHours, "should use default refresh expiration of 30 days")
 }
 
 func TestNewJWTConfig_CustomExpiration(t *testing.T) {
@@ -261,3 +262,21 @@ func TestNewJWTConfig_EnvironmentVariableHandling(t *testing.T) {
 	assert.Equal(t, "my-secret-key-123", cfg.Secret)
 	assert.Equal(t, 36, cfg.ExpirationHours)
 }
+
+func TestNewJWTConfig_RefreshExpiration(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-key")
+	t.Setenv("JWT_EXPIRATION_HOURS", "24")
+
+	t.Setenv("JWT_REFRESH_EXPIRATION_HOURS", "336")
+	cfg, err := NewJWTConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 336, cfg.RefreshExpirationHours)
+
+	for _, value := range []string{"invalid", "12"} {
+		t.Setenv("JWT_REFRESH_EXPIRATION_HOURS", value)
+		cfg, err := NewJWTConfig()
+		require.Error(t, err, value)
+		assert.Nil(t, cfg)
+		assert.Contains(t, err.Error(), "JWT_REFRESH_EXPIRATION_HOURS")
+	}
+}
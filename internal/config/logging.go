@@ -0,0 +1,55 @@
+// Package config provides logging configuration functionality.
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Log output formats
+const (
+	LogFormatText = "text" // Human-readable lines for local development
+	LogFormatJSON = "json" // One JSON object per line for log collectors
+)
+
+// LoggingConfig selects how the server, workers, and pipeline write their logs.
+type LoggingConfig struct {
+	// Format is LogFormatText or LogFormatJSON
+	Format string
+	// Level is the least severe level written; slog.LevelDebug adds verbose pipeline output
+	Level slog.Level
+}
+
+// NewLoggingConfig creates a new logging configuration from environment variables.
+// It reads LOG_FORMAT (default: text) and LOG_LEVEL (default: info).
+func NewLoggingConfig() (*LoggingConfig, error) {
+	config := &LoggingConfig{
+		Format: LogFormatText,
+		Level:  slog.LevelInfo,
+	}
+
+	if v := os.Getenv("LOG_FORMAT"); v != "" {
+		config.Format = strings.ToLower(v)
+	}
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		if err := config.Level.UnmarshalText([]byte(v)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL: %v", err)
+		}
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *LoggingConfig) normalize() error {
+	if c.Format != LogFormatText && c.Format != LogFormatJSON {
+		return fmt.Errorf("LOG_FORMAT must be %q or %q, got: %q", LogFormatText, LogFormatJSON, c.Format)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLoggingConfig_DefaultValues(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "")
+	t.Setenv("LOG_LEVEL", "")
+
+	cfg, err := NewLoggingConfig()
+	require.NoError(t, err)
+	assert.Equal(t, LogFormatText, cfg.Format)
+	assert.Equal(t, slog.LevelInfo, cfg.Level)
+}
+
+func TestNewLoggingConfig_CustomValues(t *testing.T) {
+	t.Setenv("LOG_FORMAT", "JSON")
+	t.Setenv("LOG_LEVEL", "debug")
+
+	cfg, err := NewLoggingConfig()
+	require.NoError(t, err)
+	assert.Equal(t, LogFormatJSON, cfg.Format)
+	assert.Equal(t, slog.LevelDebug, cfg.Level)
+}
+
+func TestNewLoggingConfig_InvalidValues(t *testing.T) {
+	tests := map[string][2]string{
+		"unknown format": {"xml", ""},
+		"unknown level":  {"", "loud"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("LOG_FORMAT", tt[0])
+			t.Setenv("LOG_LEVEL", tt[1])
+
+			_, err := NewLoggingConfig()
+			assert.Error(t, err)
+		})
+	}
+}
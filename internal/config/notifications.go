@@ -0,0 +1,153 @@
+// Package config provides notification delivery configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mailers that deliver email
+const (
+	MailerSMTP    = "smtp"    // Send through SMTPHost
+	MailerConsole = "console" // Print to the server log instead of sending (local development)
+)
+
+// NotificationConfig holds SMTP settings for email notifications and the digest schedule.
+type NotificationConfig struct {
+	// Mailer selects how email is delivered: MailerSMTP or MailerConsole
+	Mailer string
+	// SMTPHost is the mail server. Empty disables email delivery.
+	SMTPHost string
+	// SMTPPort is the mail server port
+	SMTPPort int
+	// SMTPUsername and SMTPPassword authenticate with the mail server (optional)
+	SMTPUsername string
+	SMTPPassword string
+	// FromAddress is the sender of notification emails
+	FromAddress string
+	// DigestIntervalHours is how often a user receives the activity digest and resume health report
+	DigestIntervalHours int
+	// ReminderLeadHours is how long before an application deadline or follow-up date the reminder is sent
+	ReminderLeadHours int
+	// AllowPrivateWebhooks lets webhooks reach loopback, private, and link-local addresses
+	// (local development only)
+	AllowPrivateWebhooks bool
+	// VerificationTTLHours is how long an email verification link stays valid
+	VerificationTTLHours int
+}
+
+// NewNotificationConfig creates a new notification configuration from environment variables.
+// It reads MAILER (default: smtp), SMTP_HOST (default: none, email disabled unless MAILER=console),
+// SMTP_PORT (default: 587), SMTP_USERNAME, SMTP_PASSWORD, NOTIFY_FROM_ADDRESS (required with SMTP_HOST),
+// DIGEST_INTERVAL_HOURS (default: 168), REMINDER_LEAD_HOURS (default: 24), WEBHOOK_ALLOW_PRIVATE
+// (default: false), and EMAIL_VERIFICATION_TTL_HOURS (default: 48).
+func NewNotificationConfig() (*NotificationConfig, error) {
+	config := &NotificationConfig{
+		Mailer:               MailerSMTP,
+		SMTPHost:             os.Getenv("SMTP_HOST"),
+		SMTPPort:             587,
+		SMTPUsername:         os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:         os.Getenv("SMTP_PASSWORD"),
+		FromAddress:          os.Getenv("NOTIFY_FROM_ADDRESS"),
+		DigestIntervalHours:  168,
+		ReminderLeadHours:    24,
+		VerificationTTLHours: 48,
+	}
+
+	if v := os.Getenv("MAILER"); v != "" {
+		config.Mailer = strings.ToLower(strings.TrimSpace(v))
+	}
+
+	if v := os.Getenv("SMTP_PORT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SMTP_PORT: %v", err)
+		}
+		config.SMTPPort = n
+	}
+
+	if v := os.Getenv("DIGEST_INTERVAL_HOURS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DIGEST_INTERVAL_HOURS: %v", err)
+		}
+		config.DigestIntervalHours = n
+	}
+
+	if v := os.Getenv("REMINDER_LEAD_HOURS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REMINDER_LEAD_HOURS: %v", err)
+		}
+		config.ReminderLeadHours = n
+	}
+
+	if v := os.Getenv("WEBHOOK_ALLOW_PRIVATE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WEBHOOK_ALLOW_PRIVATE: %v", err)
+		}
+		config.AllowPrivateWebhooks = b
+	}
+
+	if v := os.Getenv("EMAIL_VERIFICATION_TTL_HOURS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EMAIL_VERIFICATION_TTL_HOURS: %v", err)
+		}
+		config.VerificationTTLHours = n
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *NotificationConfig) normalize() error {
+	if c.Mailer != MailerSMTP && c.Mailer != MailerConsole {
+		return fmt.Errorf("MAILER must be %q or %q, got: %q", MailerSMTP, MailerConsole, c.Mailer)
+	}
+	if c.SMTPPort < 1 || c.SMTPPort > 65535 {
+		return fmt.Errorf("SMTP_PORT must be between 1 and 65535, got: %d", c.SMTPPort)
+	}
+	if c.SMTPHost != "" && c.FromAddress == "" {
+		return fmt.Errorf("NOTIFY_FROM_ADDRESS is required when SMTP_HOST is set")
+	}
+	if c.DigestIntervalHours < 1 {
+		return fmt.Errorf("DIGEST_INTERVAL_HOURS must be at least 1 hour, got: %d", c.DigestIntervalHours)
+	}
+	if c.ReminderLeadHours < 1 {
+		return fmt.Errorf("REMINDER_LEAD_HOURS must be at least 1 hour, got: %d", c.ReminderLeadHours)
+	}
+	if c.VerificationTTLHours < 1 {
+		return fmt.Errorf("EMAIL_VERIFICATION_TTL_HOURS must be at least 1 hour, got: %d", c.VerificationTTLHours)
+	}
+	return nil
+}
+
+// EmailEnabled reports whether email can be delivered: an SMTP server is configured, or
+// the console mailer prints it.
+func (c *NotificationConfig) EmailEnabled() bool {
+	return c != nil && (c.SMTPHost != "" || c.Mailer == MailerConsole)
+}
+
+// DigestInterval returns the digest period as a duration.
+func (c *NotificationConfig) DigestInterval() time.Duration {
+	return time.Duration(c.DigestIntervalHours) * time.Hour
+}
+
+// ReminderLead returns how far ahead of a date its reminder is sent.
+func (c *NotificationConfig) ReminderLead() time.Duration {
+	return time.Duration(c.ReminderLeadHours) * time.Hour
+}
+
+// VerificationTTL returns how long an email verification link stays valid.
+func (c *NotificationConfig) VerificationTTL() time.Duration {
+	return time.Duration(c.VerificationTTLHours) * time.Hour
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearNotificationEnv(t *testing.T) {
+	for _, key := range []string{"SMTP_HOST", "SMTP_PORT", "SMTP_USERNAME", "SMTP_PASSWORD", "NOTIFY_FROM_ADDRESS", "DIGEST_INTERVAL_HOURS", "REMINDER_LEAD_HOURS", "MAILER", "EMAIL_VERIFICATION_TTL_HOURS"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestNewNotificationConfig_DefaultValues(t *testing.T) {
+	clearNotificationEnv(t)
+
+	cfg, err := NewNotificationConfig()
+	require.NoError(t, err)
+	assert.False(t, cfg.EmailEnabled())
+	assert.Equal(t, 587, cfg.SMTPPort)
+	assert.Equal(t, 7*24*time.Hour, cfg.DigestInterval())
+	assert.Equal(t, 24*time.Hour, cfg.ReminderLead())
+	assert.Equal(t, MailerSMTP, cfg.Mailer)
+	assert.Equal(t, 48*time.Hour, cfg.VerificationTTL())
+}
+
+func TestNewNotificationConfig_ConsoleMailer(t *testing.T) {
+	clearNotificationEnv(t)
+	t.Setenv("MAILER", "Console")
+	t.Setenv("EMAIL_VERIFICATION_TTL_HOURS", "2")
+
+	cfg, err := NewNotificationConfig()
+	require.NoError(t, err)
+	assert.Equal(t, MailerConsole, cfg.Mailer)
+	assert.True(t, cfg.EmailEnabled())
+	assert.Equal(t, 2*time.Hour, cfg.VerificationTTL())
+}
+
+func TestNewNotificationConfig_CustomValues(t *testing.T) {
+	clearNotificationEnv(t)
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("NOTIFY_FROM_ADDRESS", "digest@example.com")
+	t.Setenv("DIGEST_INTERVAL_HOURS", "24")
+	t.Setenv("REMINDER_LEAD_HOURS", "48")
+
+	cfg, err := NewNotificationConfig()
+	require.NoError(t, err)
+	assert.True(t, cfg.EmailEnabled())
+	assert.Equal(t, 2525, cfg.SMTPPort)
+	assert.Equal(t, "digest@example.com", cfg.FromAddress)
+	assert.Equal(t, 24*time.Hour, cfg.DigestInterval())
+	assert.Equal(t, 48*time.Hour, cfg.ReminderLead())
+}
+
+func TestNewNotificationConfig_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{"non-numeric port", map[string]string{"SMTP_PORT": "abc"}},
+		{"port out of range", map[string]string{"SMTP_PORT": "70000"}},
+		{"host without sender", map[string]string{"SMTP_HOST": "smtp.example.com"}},
+		{"zero interval", map[string]string{"DIGEST_INTERVAL_HOURS": "0"}},
+		{"zero reminder lead", map[string]string{"REMINDER_LEAD_HOURS": "0"}},
+		{"unknown mailer", map[string]string{"MAILER": "sendgrid"}},
+		{"zero verification TTL", map[string]string{"EMAIL_VERIFICATION_TTL_HOURS": "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearNotificationEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := NewNotificationConfig()
+			assert.Error(t, err)
+		})
+	}
+}
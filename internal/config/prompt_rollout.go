@@ -0,0 +1,103 @@
+// Package config provides prompt rollout configuration functionality.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PromptRolloutConfig lists new prompt versions to trial against the versions they
+// replace, and when to roll them back.
+type PromptRolloutConfig struct {
+	// Rollouts are "<file>:<key>@v<N>" entries, e.g. "parsing.json:extract-job-profile@v2"
+	Rollouts []string
+	// Share is the fraction of calls the new version serves while it is trialed
+	Share float64
+	// Window is how many responses from each version are compared
+	Window int
+	// Tolerance is how much higher the new version's failure rate may be than the
+	// previous version's before it is rolled back
+	Tolerance float64
+	// AlertWebhookURL receives a JSON alert when a rollout is rolled back (optional;
+	// rollbacks are always logged)
+	AlertWebhookURL string
+}
+
+// NewPromptRolloutConfig creates a new prompt rollout configuration from environment
+// variables. It reads PROMPT_ROLLOUTS (comma-separated, default: none),
+// PROMPT_ROLLOUT_SHARE (default: 0.5), PROMPT_ROLLOUT_WINDOW (default: 50),
+// PROMPT_ROLLOUT_TOLERANCE (default: 0.05), and PROMPT_ROLLOUT_ALERT_WEBHOOK_URL
+// (default: none).
+func NewPromptRolloutConfig() (*PromptRolloutConfig, error) {
+	config := &PromptRolloutConfig{
+		Share:           0.5,
+		Window:          50,
+		Tolerance:       0.05,
+		AlertWebhookURL: os.Getenv("PROMPT_ROLLOUT_ALERT_WEBHOOK_URL"),
+	}
+
+	for _, part := range strings.Split(os.Getenv("PROMPT_ROLLOUTS"), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			config.Rollouts = append(config.Rollouts, part)
+		}
+	}
+
+	if v := os.Getenv("PROMPT_ROLLOUT_SHARE"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PROMPT_ROLLOUT_SHARE: %v", err)
+		}
+		config.Share = f
+	}
+
+	if v := os.Getenv("PROMPT_ROLLOUT_WINDOW"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PROMPT_ROLLOUT_WINDOW: %v", err)
+		}
+		config.Window = n
+	}
+
+	if v := os.Getenv("PROMPT_ROLLOUT_TOLERANCE"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PROMPT_ROLLOUT_TOLERANCE: %v", err)
+		}
+		config.Tolerance = f
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *PromptRolloutConfig) normalize() error {
+	for _, rollout := range c.Rollouts {
+		file, key, found := strings.Cut(rollout, ":")
+		if !found || file == "" || !strings.Contains(key, "@v") {
+			return fmt.Errorf("PROMPT_ROLLOUTS entries must look like file.json:key@v2, got: %q", rollout)
+		}
+	}
+	if c.Share <= 0 || c.Share >= 1 {
+		return fmt.Errorf("PROMPT_ROLLOUT_SHARE must be between 0 and 1, got: %g", c.Share)
+	}
+	if c.Window < 1 {
+		return fmt.Errorf("PROMPT_ROLLOUT_WINDOW must be at least 1, got: %d", c.Window)
+	}
+	if c.Tolerance < 0 || c.Tolerance >= 1 {
+		return fmt.Errorf("PROMPT_ROLLOUT_TOLERANCE must be at least 0 and less than 1, got: %g", c.Tolerance)
+	}
+	if c.AlertWebhookURL != "" {
+		u, err := url.Parse(c.AlertWebhookURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("PROMPT_ROLLOUT_ALERT_WEBHOOK_URL must be an http(s) URL, got: %q", c.AlertWebhookURL)
+		}
+	}
+	return nil
+}
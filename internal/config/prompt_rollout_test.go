@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setPromptRolloutEnv(t *testing.T, rollouts, share, window, tolerance, webhook string) {
+	t.Helper()
+	t.Setenv("PROMPT_ROLLOUTS", rollouts)
+	t.Setenv("PROMPT_ROLLOUT_SHARE", share)
+	t.Setenv("PROMPT_ROLLOUT_WINDOW", window)
+	t.Setenv("PROMPT_ROLLOUT_TOLERANCE", tolerance)
+	t.Setenv("PROMPT_ROLLOUT_ALERT_WEBHOOK_URL", webhook)
+}
+
+func TestNewPromptRolloutConfig_DefaultValues(t *testing.T) {
+	setPromptRolloutEnv(t, "", "", "", "", "")
+
+	cfg, err := NewPromptRolloutConfig()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Rollouts)
+	assert.Equal(t, 0.5, cfg.Share)
+	assert.Equal(t, 50, cfg.Window)
+	assert.Equal(t, 0.05, cfg.Tolerance)
+	assert.Empty(t, cfg.AlertWebhookURL)
+}
+
+func TestNewPromptRolloutConfig_CustomValues(t *testing.T) {
+	setPromptRolloutEnv(t, "parsing.json:extract-job-profile@v2, voice.json:extract-brand-voice@v3,",
+		"0.1", "200", "0", "https://alerts.example.com/hook")
+
+	cfg, err := NewPromptRolloutConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"parsing.json:extract-job-profile@v2", "voice.json:extract-brand-voice@v3"}, cfg.Rollouts)
+	assert.Equal(t, 0.1, cfg.Share)
+	assert.Equal(t, 200, cfg.Window)
+	assert.Zero(t, cfg.Tolerance)
+	assert.Equal(t, "https://alerts.example.com/hook", cfg.AlertWebhookURL)
+}
+
+func TestNewPromptRolloutConfig_InvalidValues(t *testing.T) {
+	tests := map[string][5]string{
+		"rollout without file":    {"extract-job-profile@v2", "", "", "", ""},
+		"rollout without version": {"parsing.json:extract-job-profile", "", "", "", ""},
+		"non-numeric share":       {"", "half", "", "", ""},
+		"share of one":            {"", "1", "", "", ""},
+		"zero window":             {"", "", "0", "", ""},
+		"negative tolerance":      {"", "", "", "-0.1", ""},
+		"webhook bad scheme":      {"", "", "", "", "ftp://alerts.example.com"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setPromptRolloutEnv(t, tt[0], tt[1], tt[2], tt[3], tt[4])
+			_, err := NewPromptRolloutConfig()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Package config provides story ranking configuration functionality.
+package config
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+)
+
+// Story ranking modes
+const (
+	// RankingModeKeyword ranks stories by skill and keyword overlap alone
+	RankingModeKeyword = "keyword"
+	// RankingModeEmbeddings blends the keyword score with the cosine similarity of
+	// embedding vectors for the job's requirements and the stories' bullets
+	RankingModeEmbeddings = "embeddings"
+)
+
+// RankingConfig holds configuration for story ranking.
+type RankingConfig struct {
+	// Mode is RankingModeKeyword or RankingModeEmbeddings
+	Mode string
+	// SemanticWeight is the share of a story's relevance taken from its semantic score
+	// in embeddings mode; the rest comes from the keyword score
+	SemanticWeight float64
+}
+
+// NewRankingConfig creates a new ranking configuration from environment variables.
+// It reads RANKING_MODE (default: keyword) and RANKING_SEMANTIC_WEIGHT (default: 0.5,
+// between 0 and 1).
+func NewRankingConfig() (*RankingConfig, error) {
+	config := &RankingConfig{
+		Mode:           RankingModeKeyword,
+		SemanticWeight: 0.5,
+	}
+
+	if v := os.Getenv("RANKING_MODE"); v != "" {
+		config.Mode = v
+	}
+
+	if v := os.Getenv("RANKING_SEMANTIC_WEIGHT"); v != "" {
+		w, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RANKING_SEMANTIC_WEIGHT: %v", err)
+		}
+		config.SemanticWeight = w
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *RankingConfig) normalize() error {
+	if c.Mode != RankingModeKeyword && c.Mode != RankingModeEmbeddings {
+		return fmt.Errorf("RANKING_MODE must be %q or %q, got: %q", RankingModeKeyword, RankingModeEmbeddings, c.Mode)
+	}
+	if math.IsNaN(c.SemanticWeight) || c.SemanticWeight < 0 || c.SemanticWeight > 1 {
+		return fmt.Errorf("RANKING_SEMANTIC_WEIGHT must be between 0 and 1, got: %v", c.SemanticWeight)
+	}
+	return nil
+}
+
+// Semantic reports whether stories are ranked with embeddings.
+func (c *RankingConfig) Semantic() bool {
+	return c.Mode == RankingModeEmbeddings && c.SemanticWeight > 0
+}
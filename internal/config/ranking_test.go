@@ -0,0 +1,64 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearRankingEnv(t *testing.T) {
+	t.Setenv("RANKING_MODE", "")
+	t.Setenv("RANKING_SEMANTIC_WEIGHT", "")
+}
+
+func TestNewRankingConfig_DefaultValues(t *testing.T) {
+	clearRankingEnv(t)
+
+	cfg, err := NewRankingConfig()
+	require.NoError(t, err)
+	assert.Equal(t, RankingModeKeyword, cfg.Mode)
+	assert.Equal(t, 0.5, cfg.SemanticWeight)
+	assert.False(t, cfg.Semantic())
+}
+
+func TestNewRankingConfig_CustomValues(t *testing.T) {
+	clearRankingEnv(t)
+	t.Setenv("RANKING_MODE", "embeddings")
+	t.Setenv("RANKING_SEMANTIC_WEIGHT", "0.7")
+
+	cfg, err := NewRankingConfig()
+	require.NoError(t, err)
+	assert.Equal(t, RankingModeEmbeddings, cfg.Mode)
+	assert.Equal(t, 0.7, cfg.SemanticWeight)
+	assert.True(t, cfg.Semantic())
+
+	t.Setenv("RANKING_SEMANTIC_WEIGHT", "0")
+	cfg, err = NewRankingConfig()
+	require.NoError(t, err)
+	assert.False(t, cfg.Semantic(), "a zero weight leaves ranking keyword-only")
+}
+
+func TestNewRankingConfig_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		val  string
+	}{
+		{name: "unknown mode", key: "RANKING_MODE", val: "vibes"},
+		{name: "non-numeric weight", key: "RANKING_SEMANTIC_WEIGHT", val: "half"},
+		{name: "negative weight", key: "RANKING_SEMANTIC_WEIGHT", val: "-0.1"},
+		{name: "weight above one", key: "RANKING_SEMANTIC_WEIGHT", val: "1.5"},
+		{name: "NaN weight", key: "RANKING_SEMANTIC_WEIGHT", val: "NaN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearRankingEnv(t)
+			t.Setenv(tt.key, tt.val)
+
+			_, err := NewRankingConfig()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package config provides run garbage collection configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RunGCConfig controls garbage collection of runs that were created but never executed.
+type RunGCConfig struct {
+	// AbandonDays is how long a run may go without step activity before it is marked abandoned
+	AbandonDays int
+	// RetentionDays is how long an abandoned run is kept before it is deleted
+	RetentionDays int
+}
+
+// NewRunGCConfig creates a new run garbage collection configuration from environment variables.
+// It reads RUN_GC_ABANDON_DAYS (default: 7) and RUN_GC_RETENTION_DAYS (default: 30).
+func NewRunGCConfig() (*RunGCConfig, error) {
+	config := &RunGCConfig{
+		AbandonDays:   7,
+		RetentionDays: 30,
+	}
+
+	for name, field := range map[string]*int{
+		"RUN_GC_ABANDON_DAYS":   &config.AbandonDays,
+		"RUN_GC_RETENTION_DAYS": &config.RetentionDays,
+	} {
+		if value := os.Getenv(name); value != "" {
+			days, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", name, err)
+			}
+			*field = days
+		}
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *RunGCConfig) normalize() error {
+	if c.AbandonDays < 1 {
+		return fmt.Errorf("RUN_GC_ABANDON_DAYS must be at least 1 day, got: %d", c.AbandonDays)
+	}
+	if c.RetentionDays < 0 {
+		return fmt.Errorf("RUN_GC_RETENTION_DAYS must not be negative, got: %d", c.RetentionDays)
+	}
+	return nil
+}
+
+// AbandonAfter returns how long a run may be idle before it is marked abandoned.
+func (c *RunGCConfig) AbandonAfter() time.Duration {
+	return time.Duration(c.AbandonDays) * 24 * time.Hour
+}
+
+// Retention returns how long abandoned runs are kept before deletion.
+func (c *RunGCConfig) Retention() time.Duration {
+	return time.Duration(c.RetentionDays) * 24 * time.Hour
+}
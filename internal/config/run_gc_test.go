@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRunGCConfig_DefaultValues(t *testing.T) {
+	t.Setenv("RUN_GC_ABANDON_DAYS", "")
+	t.Setenv("RUN_GC_RETENTION_DAYS", "")
+
+	cfg, err := NewRunGCConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, cfg.AbandonAfter())
+	assert.Equal(t, 30*24*time.Hour, cfg.Retention())
+}
+
+func TestNewRunGCConfig_CustomValues(t *testing.T) {
+	t.Setenv("RUN_GC_ABANDON_DAYS", "2")
+	t.Setenv("RUN_GC_RETENTION_DAYS", "0")
+
+	cfg, err := NewRunGCConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.AbandonDays)
+	assert.Zero(t, cfg.Retention(), "abandoned runs may be deleted on the next pass")
+}
+
+func TestNewRunGCConfig_InvalidValues(t *testing.T) {
+	tests := map[string][2]string{
+		"non-numeric abandon": {"soon", ""},
+		"zero abandon":        {"0", ""},
+		"negative retention":  {"", "-1"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("RUN_GC_ABANDON_DAYS", tt[0])
+			t.Setenv("RUN_GC_RETENTION_DAYS", tt[1])
+			_, err := NewRunGCConfig()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Package config provides background run worker configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RunWorkerConfig holds configuration for the workers that execute queued pipeline runs.
+type RunWorkerConfig struct {
+	// Workers is how many queued runs this server executes at once. Zero leaves
+	// queued runs to other replicas.
+	Workers int
+	// StaleSeconds is how long a claimed run may go without a heartbeat before it is
+	// requeued (worker presumed dead)
+	StaleSeconds int
+	// MaxAttempts caps how many times a run is claimed before a stale run is failed
+	MaxAttempts int
+}
+
+// NewRunWorkerConfig creates a new run worker configuration from environment variables.
+// It reads RUN_WORKERS (default: 2, 0 to disable), RUN_JOB_STALE_SECONDS (default: 900),
+// and RUN_JOB_MAX_ATTEMPTS (default: 3).
+func NewRunWorkerConfig() (*RunWorkerConfig, error) {
+	config := &RunWorkerConfig{
+		Workers:      2,
+		StaleSeconds: 900,
+		MaxAttempts:  3,
+	}
+
+	if v := os.Getenv("RUN_WORKERS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RUN_WORKERS: %v", err)
+		}
+		config.Workers = n
+	}
+
+	if v := os.Getenv("RUN_JOB_STALE_SECONDS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RUN_JOB_STALE_SECONDS: %v", err)
+		}
+		config.StaleSeconds = n
+	}
+
+	if v := os.Getenv("RUN_JOB_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RUN_JOB_MAX_ATTEMPTS: %v", err)
+		}
+		config.MaxAttempts = n
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *RunWorkerConfig) normalize() error {
+	if c.Workers < 0 {
+		return fmt.Errorf("RUN_WORKERS must not be negative, got: %d", c.Workers)
+	}
+	if c.StaleSeconds < 1 {
+		return fmt.Errorf("RUN_JOB_STALE_SECONDS must be at least 1, got: %d", c.StaleSeconds)
+	}
+	if c.MaxAttempts < 1 {
+		return fmt.Errorf("RUN_JOB_MAX_ATTEMPTS must be at least 1, got: %d", c.MaxAttempts)
+	}
+	return nil
+}
+
+// StaleAfter returns how long a running job may go without a heartbeat before being requeued.
+func (c *RunWorkerConfig) StaleAfter() time.Duration {
+	return time.Duration(c.StaleSeconds) * time.Second
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearRunWorkerEnv(t *testing.T) {
+	t.Setenv("RUN_WORKERS", "")
+	t.Setenv("RUN_JOB_STALE_SECONDS", "")
+	t.Setenv("RUN_JOB_MAX_ATTEMPTS", "")
+}
+
+func TestNewRunWorkerConfig_DefaultValues(t *testing.T) {
+	clearRunWorkerEnv(t)
+
+	cfg, err := NewRunWorkerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.Workers)
+	assert.Equal(t, 900*time.Second, cfg.StaleAfter())
+	assert.Equal(t, 3, cfg.MaxAttempts)
+}
+
+func TestNewRunWorkerConfig_CustomValues(t *testing.T) {
+	clearRunWorkerEnv(t)
+	t.Setenv("RUN_WORKERS", "0")
+	t.Setenv("RUN_JOB_STALE_SECONDS", "120")
+	t.Setenv("RUN_JOB_MAX_ATTEMPTS", "1")
+
+	cfg, err := NewRunWorkerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.Workers)
+	assert.Equal(t, 2*time.Minute, cfg.StaleAfter())
+	assert.Equal(t, 1, cfg.MaxAttempts)
+}
+
+func TestNewRunWorkerConfig_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		val  string
+	}{
+		{name: "non-numeric workers", key: "RUN_WORKERS", val: "many"},
+		{name: "negative workers", key: "RUN_WORKERS", val: "-1"},
+		{name: "zero stale", key: "RUN_JOB_STALE_SECONDS", val: "0"},
+		{name: "zero attempts", key: "RUN_JOB_MAX_ATTEMPTS", val: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearRunWorkerEnv(t)
+			t.Setenv(tt.key, tt.val)
+
+			_, err := NewRunWorkerConfig()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Package config provides run scheduler configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SchedulerConfig holds configuration for the server's pipeline run scheduler.
+type SchedulerConfig struct {
+	// MaxConcurrentRuns is the size of the run worker pool shared by all users
+	MaxConcurrentRuns int
+	// MaxConcurrentRunsPerUser caps how many pool slots one user may hold at once,
+	// so a bulk submission cannot occupy the whole pool
+	MaxConcurrentRunsPerUser int
+	// MaxQueuedRunsPerUser caps how many of one user's runs may wait for a slot;
+	// further submissions go to the admission backlog. Zero means unlimited.
+	MaxQueuedRunsPerUser int
+	// MaxAdmissionBacklog caps how many runs, across all users, may wait for room
+	// in their user's queue; beyond it submissions are rejected with 429. Zero
+	// rejects as soon as a user's queue is full.
+	MaxAdmissionBacklog int
+	// RunEstimateSeconds seeds the estimated wait reported to queued runs until
+	// enough runs have finished to measure their duration
+	RunEstimateSeconds int
+}
+
+// NewSchedulerConfig creates a new scheduler configuration from environment variables.
+// It reads MAX_CONCURRENT_RUNS (default: 8), MAX_CONCURRENT_RUNS_PER_USER (default: 2),
+// MAX_QUEUED_RUNS_PER_USER (default: 10, 0 for unlimited), RUN_ADMISSION_BACKLOG
+// (default: 50), and RUN_ESTIMATE_SECONDS (default: 120).
+func NewSchedulerConfig() (*SchedulerConfig, error) {
+	config := &SchedulerConfig{
+		MaxConcurrentRuns:        8,
+		MaxConcurrentRunsPerUser: 2,
+		MaxQueuedRunsPerUser:     10,
+		MaxAdmissionBacklog:      50,
+		RunEstimateSeconds:       120,
+	}
+
+	if v := os.Getenv("MAX_CONCURRENT_RUNS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_CONCURRENT_RUNS: %v", err)
+		}
+		config.MaxConcurrentRuns = n
+	}
+
+	if v := os.Getenv("MAX_CONCURRENT_RUNS_PER_USER"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_CONCURRENT_RUNS_PER_USER: %v", err)
+		}
+		config.MaxConcurrentRunsPerUser = n
+	}
+
+	if v := os.Getenv("MAX_QUEUED_RUNS_PER_USER"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAX_QUEUED_RUNS_PER_USER: %v", err)
+		}
+		config.MaxQueuedRunsPerUser = n
+	}
+
+	if v := os.Getenv("RUN_ADMISSION_BACKLOG"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RUN_ADMISSION_BACKLOG: %v", err)
+		}
+		config.MaxAdmissionBacklog = n
+	}
+
+	if v := os.Getenv("RUN_ESTIMATE_SECONDS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RUN_ESTIMATE_SECONDS: %v", err)
+		}
+		config.RunEstimateSeconds = n
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *SchedulerConfig) normalize() error {
+	if c.MaxConcurrentRuns < 1 {
+		return fmt.Errorf("MAX_CONCURRENT_RUNS must be at least 1, got: %d", c.MaxConcurrentRuns)
+	}
+	if c.MaxConcurrentRunsPerUser < 1 {
+		return fmt.Errorf("MAX_CONCURRENT_RUNS_PER_USER must be at least 1, got: %d", c.MaxConcurrentRunsPerUser)
+	}
+	if c.MaxQueuedRunsPerUser < 0 {
+		return fmt.Errorf("MAX_QUEUED_RUNS_PER_USER must not be negative, got: %d", c.MaxQueuedRunsPerUser)
+	}
+	if c.MaxAdmissionBacklog < 0 {
+		return fmt.Errorf("RUN_ADMISSION_BACKLOG must not be negative, got: %d", c.MaxAdmissionBacklog)
+	}
+	if c.RunEstimateSeconds < 1 {
+		return fmt.Errorf("RUN_ESTIMATE_SECONDS must be at least 1, got: %d", c.RunEstimateSeconds)
+	}
+	return nil
+}
+
+// RunEstimate returns the initial run duration used for wait estimates
+func (c *SchedulerConfig) RunEstimate() time.Duration {
+	return time.Duration(c.RunEstimateSeconds) * time.Second
+}
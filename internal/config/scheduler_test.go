@@ -0,0 +1,71 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearSchedulerEnv(t *testing.T) {
+	t.Setenv("MAX_CONCURRENT_RUNS", "")
+	t.Setenv("MAX_CONCURRENT_RUNS_PER_USER", "")
+	t.Setenv("MAX_QUEUED_RUNS_PER_USER", "")
+	t.Setenv("RUN_ADMISSION_BACKLOG", "")
+	t.Setenv("RUN_ESTIMATE_SECONDS", "")
+}
+
+func TestNewSchedulerConfig_DefaultValues(t *testing.T) {
+	clearSchedulerEnv(t)
+
+	cfg, err := NewSchedulerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 8, cfg.MaxConcurrentRuns)
+	assert.Equal(t, 2, cfg.MaxConcurrentRunsPerUser)
+	assert.Equal(t, 10, cfg.MaxQueuedRunsPerUser)
+	assert.Equal(t, 50, cfg.MaxAdmissionBacklog)
+	assert.Equal(t, 2*time.Minute, cfg.RunEstimate())
+}
+
+func TestNewSchedulerConfig_CustomValues(t *testing.T) {
+	clearSchedulerEnv(t)
+	t.Setenv("MAX_CONCURRENT_RUNS", "16")
+	t.Setenv("MAX_CONCURRENT_RUNS_PER_USER", "3")
+	t.Setenv("MAX_QUEUED_RUNS_PER_USER", "0")
+	t.Setenv("RUN_ADMISSION_BACKLOG", "0")
+	t.Setenv("RUN_ESTIMATE_SECONDS", "45")
+
+	cfg, err := NewSchedulerConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 16, cfg.MaxConcurrentRuns)
+	assert.Equal(t, 3, cfg.MaxConcurrentRunsPerUser)
+	assert.Equal(t, 0, cfg.MaxQueuedRunsPerUser)
+	assert.Equal(t, 0, cfg.MaxAdmissionBacklog)
+	assert.Equal(t, 45*time.Second, cfg.RunEstimate())
+}
+
+func TestNewSchedulerConfig_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		val  string
+	}{
+		{"non-numeric pool size", "MAX_CONCURRENT_RUNS", "many"},
+		{"zero pool size", "MAX_CONCURRENT_RUNS", "0"},
+		{"zero per-user cap", "MAX_CONCURRENT_RUNS_PER_USER", "0"},
+		{"non-numeric per-user cap", "MAX_CONCURRENT_RUNS_PER_USER", "two"},
+		{"negative queue limit", "MAX_QUEUED_RUNS_PER_USER", "-1"},
+		{"negative backlog", "RUN_ADMISSION_BACKLOG", "-1"},
+		{"non-numeric run estimate", "RUN_ESTIMATE_SECONDS", "soon"},
+		{"zero run estimate", "RUN_ESTIMATE_SECONDS", "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearSchedulerEnv(t)
+			t.Setenv(tt.key, tt.val)
+			_, err := NewSchedulerConfig()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package config provides latency SLO configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SLOConfig sets the end-to-end latency budget of a run and the share of runs and
+// steps expected to meet their budgets. Per-step budgets live in the step registry.
+type SLOConfig struct {
+	// RunBudgetSeconds is how long a run should take from creation to completion
+	RunBudgetSeconds int
+	// Target is the fraction of executions that should finish within budget; the SLO
+	// report flags steps below it as slipping
+	Target float64
+}
+
+// NewSLOConfig creates a new SLO configuration from environment variables.
+// It reads RUN_LATENCY_BUDGET_SECONDS (default: 600) and SLO_TARGET (default: 0.95).
+func NewSLOConfig() (*SLOConfig, error) {
+	config := &SLOConfig{
+		RunBudgetSeconds: 600,
+		Target:           0.95,
+	}
+
+	if v := os.Getenv("RUN_LATENCY_BUDGET_SECONDS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RUN_LATENCY_BUDGET_SECONDS: %v", err)
+		}
+		config.RunBudgetSeconds = n
+	}
+
+	if v := os.Getenv("SLO_TARGET"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SLO_TARGET: %v", err)
+		}
+		config.Target = f
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *SLOConfig) normalize() error {
+	if c.RunBudgetSeconds < 1 {
+		return fmt.Errorf("RUN_LATENCY_BUDGET_SECONDS must be at least 1, got: %d", c.RunBudgetSeconds)
+	}
+	if c.Target <= 0 || c.Target > 1 {
+		return fmt.Errorf("SLO_TARGET must be greater than 0 and at most 1, got: %g", c.Target)
+	}
+	return nil
+}
+
+// RunBudget returns the end-to-end latency budget of a run.
+func (c *SLOConfig) RunBudget() time.Duration {
+	return time.Duration(c.RunBudgetSeconds) * time.Second
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSLOConfig_DefaultValues(t *testing.T) {
+	t.Setenv("RUN_LATENCY_BUDGET_SECONDS", "")
+	t.Setenv("SLO_TARGET", "")
+
+	cfg, err := NewSLOConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, cfg.RunBudget())
+	assert.Equal(t, 0.95, cfg.Target)
+}
+
+func TestNewSLOConfig_CustomValues(t *testing.T) {
+	t.Setenv("RUN_LATENCY_BUDGET_SECONDS", "300")
+	t.Setenv("SLO_TARGET", "0.99")
+
+	cfg, err := NewSLOConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, cfg.RunBudget())
+	assert.Equal(t, 0.99, cfg.Target)
+}
+
+func TestNewSLOConfig_InvalidValues(t *testing.T) {
+	tests := map[string][2]string{
+		"non-numeric budget": {"fast", ""},
+		"zero budget":        {"0", ""},
+		"non-numeric target": {"", "most"},
+		"zero target":        {"", "0"},
+		"target over one":    {"", "95"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("RUN_LATENCY_BUDGET_SECONDS", tt[0])
+			t.Setenv("SLO_TARGET", tt[1])
+			_, err := NewSLOConfig()
+			assert.Error(t, err)
+		})
+	}
+}
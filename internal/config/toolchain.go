@@ -0,0 +1,86 @@
+// Package config provides external toolchain configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LaTeX engines the toolchain knows how to drive.
+const (
+	LaTeXEnginePdflatex = "pdflatex"
+	LaTeXEngineLatexmk  = "latexmk"
+	LaTeXEngineTectonic = "tectonic"
+)
+
+// ToolchainConfig overrides discovery of the external programs used to compile resumes,
+// count PDF pages, and render JavaScript-heavy pages. Empty fields are discovered from
+// PATH and the platform's usual install locations.
+type ToolchainConfig struct {
+	// LaTeXEngine is the engine LaTeXPath runs, or the only engine to look for
+	LaTeXEngine string
+	// LaTeXPath is the LaTeX compiler executable
+	LaTeXPath string
+	// BrowserPath is a Chrome, Chromium, or Edge executable for headless rendering
+	BrowserPath string
+	// PDFInfoPath is the poppler pdfinfo executable
+	PDFInfoPath string
+	// PDFToPPMPath is the poppler pdftoppm executable, used for thumbnails
+	PDFToPPMPath string
+	// PDFToTextPath is the poppler pdftotext executable, used to import resume PDFs
+	PDFToTextPath string
+	// GhostscriptPath is the Ghostscript console executable
+	GhostscriptPath string
+}
+
+// NewToolchainConfig creates a new toolchain configuration from environment variables.
+// It reads LATEX_ENGINE, LATEX_PATH, CHROME_PATH, PDFINFO_PATH, PDFTOPPM_PATH,
+// PDFTOTEXT_PATH, and GHOSTSCRIPT_PATH.
+// When LATEX_PATH is set without LATEX_ENGINE the engine is inferred from the file name.
+func NewToolchainConfig() (*ToolchainConfig, error) {
+	config := &ToolchainConfig{
+		LaTeXEngine:     strings.ToLower(strings.TrimSpace(os.Getenv("LATEX_ENGINE"))),
+		LaTeXPath:       strings.TrimSpace(os.Getenv("LATEX_PATH")),
+		BrowserPath:     strings.TrimSpace(os.Getenv("CHROME_PATH")),
+		PDFInfoPath:     strings.TrimSpace(os.Getenv("PDFINFO_PATH")),
+		PDFToPPMPath:    strings.TrimSpace(os.Getenv("PDFTOPPM_PATH")),
+		PDFToTextPath:   strings.TrimSpace(os.Getenv("PDFTOTEXT_PATH")),
+		GhostscriptPath: strings.TrimSpace(os.Getenv("GHOSTSCRIPT_PATH")),
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration and infers the engine of an explicit compiler.
+func (c *ToolchainConfig) normalize() error {
+	if c.LaTeXEngine == "" && c.LaTeXPath != "" {
+		c.LaTeXEngine = LaTeXEngineFromPath(c.LaTeXPath)
+		if c.LaTeXEngine == "" {
+			return fmt.Errorf("LATEX_ENGINE is required when LATEX_PATH does not name pdflatex, latexmk, or tectonic: %s", c.LaTeXPath)
+		}
+	}
+	switch c.LaTeXEngine {
+	case "", LaTeXEnginePdflatex, LaTeXEngineLatexmk, LaTeXEngineTectonic:
+	default:
+		return fmt.Errorf("LATEX_ENGINE must be pdflatex, latexmk, or tectonic, got: %s", c.LaTeXEngine)
+	}
+	return nil
+}
+
+// LaTeXEngineFromPath returns the engine an executable path names, or "" if unknown.
+// Windows paths and .exe suffixes are recognized on every platform.
+func LaTeXEngineFromPath(path string) string {
+	name := strings.ToLower(filepath.Base(strings.ReplaceAll(path, `\`, "/")))
+	name = strings.TrimSuffix(name, ".exe")
+	switch name {
+	case LaTeXEnginePdflatex, LaTeXEngineLatexmk, LaTeXEngineTectonic:
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setToolchainEnv(t *testing.T, engine, latex string) {
+	t.Helper()
+	t.Setenv("LATEX_ENGINE", engine)
+	t.Setenv("LATEX_PATH", latex)
+	t.Setenv("CHROME_PATH", "")
+	t.Setenv("PDFINFO_PATH", "")
+	t.Setenv("PDFTOPPM_PATH", "")
+	t.Setenv("PDFTOTEXT_PATH", "")
+	t.Setenv("GHOSTSCRIPT_PATH", "")
+}
+
+func TestNewToolchainConfig_DefaultValues(t *testing.T) {
+	setToolchainEnv(t, "", "")
+
+	cfg, err := NewToolchainConfig()
+	require.NoError(t, err)
+	assert.Equal(t, &ToolchainConfig{}, cfg, "everything is discovered")
+}
+
+func TestNewToolchainConfig_InfersEngine(t *testing.T) {
+	setToolchainEnv(t, "", `C:\Program Files\MiKTeX\miktex\bin\x64\LATEXMK.EXE`)
+
+	cfg, err := NewToolchainConfig()
+	require.NoError(t, err)
+	assert.Equal(t, LaTeXEngineLatexmk, cfg.LaTeXEngine)
+
+	setToolchainEnv(t, "Tectonic", "")
+	cfg, err = NewToolchainConfig()
+	require.NoError(t, err)
+	assert.Equal(t, LaTeXEngineTectonic, cfg.LaTeXEngine)
+}
+
+func TestNewToolchainConfig_InvalidValues(t *testing.T) {
+	tests := map[string][2]string{
+		"unknown engine":        {"xelatex", ""},
+		"unrecognized compiler": {"", "/opt/tex/bin/mytex"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setToolchainEnv(t, tt[0], tt[1])
+			_, err := NewToolchainConfig()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Package config provides voice note transcription configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// TranscriptionConfig holds settings for transcribing uploaded voice notes.
+type TranscriptionConfig struct {
+	// URL is an OpenAI-compatible audio transcription endpoint, such as
+	// https://api.openai.com/v1/audio/transcriptions or a self-hosted Whisper server.
+	// Without it audio uploads are rejected and only transcripts can be submitted.
+	URL string
+	// APIKey is sent as a bearer token to URL when set
+	APIKey string
+	// Model is the transcription model name sent with each request
+	Model string
+	// MaxUploadMB is the largest accepted audio upload in megabytes
+	MaxUploadMB int
+}
+
+// NewTranscriptionConfig creates a new transcription configuration from environment
+// variables. It reads TRANSCRIPTION_URL (default: none), TRANSCRIPTION_API_KEY
+// (default: none), TRANSCRIPTION_MODEL (default: whisper-1), and VOICE_NOTE_MAX_MB
+// (default: 25).
+func NewTranscriptionConfig() (*TranscriptionConfig, error) {
+	config := &TranscriptionConfig{
+		URL:         os.Getenv("TRANSCRIPTION_URL"),
+		APIKey:      os.Getenv("TRANSCRIPTION_API_KEY"),
+		Model:       os.Getenv("TRANSCRIPTION_MODEL"),
+		MaxUploadMB: 25,
+	}
+	if config.Model == "" {
+		config.Model = "whisper-1"
+	}
+
+	if v := os.Getenv("VOICE_NOTE_MAX_MB"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VOICE_NOTE_MAX_MB: %v", err)
+		}
+		config.MaxUploadMB = n
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *TranscriptionConfig) normalize() error {
+	if c.MaxUploadMB < 1 {
+		return fmt.Errorf("VOICE_NOTE_MAX_MB must be at least 1, got: %d", c.MaxUploadMB)
+	}
+	return nil
+}
+
+// Enabled reports whether audio uploads can be transcribed.
+func (c *TranscriptionConfig) Enabled() bool {
+	return c != nil && c.URL != ""
+}
+
+// MaxUploadBytes returns the largest accepted audio upload in bytes.
+func (c *TranscriptionConfig) MaxUploadBytes() int64 {
+	return int64(c.MaxUploadMB) << 20
+}
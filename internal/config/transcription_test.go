@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearTranscriptionEnv(t *testing.T) {
+	for _, key := range []string{"TRANSCRIPTION_URL", "TRANSCRIPTION_API_KEY", "TRANSCRIPTION_MODEL", "VOICE_NOTE_MAX_MB"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestNewTranscriptionConfig_DefaultValues(t *testing.T) {
+	clearTranscriptionEnv(t)
+
+	cfg, err := NewTranscriptionConfig()
+	require.NoError(t, err)
+	assert.False(t, cfg.Enabled())
+	assert.Equal(t, "whisper-1", cfg.Model)
+	assert.Equal(t, int64(25<<20), cfg.MaxUploadBytes())
+}
+
+func TestNewTranscriptionConfig_CustomValues(t *testing.T) {
+	clearTranscriptionEnv(t)
+	t.Setenv("TRANSCRIPTION_URL", "http://localhost:9000/v1/audio/transcriptions")
+	t.Setenv("TRANSCRIPTION_API_KEY", "secret")
+	t.Setenv("TRANSCRIPTION_MODEL", "large-v3")
+	t.Setenv("VOICE_NOTE_MAX_MB", "5")
+
+	cfg, err := NewTranscriptionConfig()
+	require.NoError(t, err)
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, "secret", cfg.APIKey)
+	assert.Equal(t, "large-v3", cfg.Model)
+	assert.Equal(t, int64(5<<20), cfg.MaxUploadBytes())
+}
+
+func TestNewTranscriptionConfig_InvalidValues(t *testing.T) {
+	for _, v := range []string{"big", "0"} {
+		t.Run(v, func(t *testing.T) {
+			clearTranscriptionEnv(t)
+			t.Setenv("VOICE_NOTE_MAX_MB", v)
+			_, err := NewTranscriptionConfig()
+			assert.Error(t, err)
+		})
+	}
+}
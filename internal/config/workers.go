@@ -0,0 +1,98 @@
+// Package config provides out-of-process step worker configuration functionality.
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StepWorkerConfig holds configuration for running heavy steps in separate worker processes.
+type StepWorkerConfig struct {
+	// RemoteSteps are executed by workers instead of in the API/pipeline process
+	RemoteSteps map[string]bool
+	// TimeoutSeconds bounds how long the pipeline waits for a remote step
+	TimeoutSeconds int
+	// StaleSeconds is how long a claimed job may run before it is requeued (worker presumed dead)
+	StaleSeconds int
+}
+
+// NewStepWorkerConfig creates a new step worker configuration from environment variables.
+// It reads REMOTE_STEPS (comma-separated step names, default: none),
+// REMOTE_STEP_TIMEOUT_SECONDS (default: 600), and REMOTE_STEP_STALE_SECONDS (default: 900).
+func NewStepWorkerConfig() (*StepWorkerConfig, error) {
+	config := &StepWorkerConfig{
+		RemoteSteps:    make(map[string]bool),
+		TimeoutSeconds: 600,
+		StaleSeconds:   900,
+	}
+
+	if stepsStr := os.Getenv("REMOTE_STEPS"); stepsStr != "" {
+		for _, part := range strings.Split(stepsStr, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				config.RemoteSteps[part] = true
+			}
+		}
+	}
+
+	if timeoutStr := os.Getenv("REMOTE_STEP_TIMEOUT_SECONDS"); timeoutStr != "" {
+		seconds, err := strconv.Atoi(timeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REMOTE_STEP_TIMEOUT_SECONDS: %v", err)
+		}
+		config.TimeoutSeconds = seconds
+	}
+
+	if staleStr := os.Getenv("REMOTE_STEP_STALE_SECONDS"); staleStr != "" {
+		seconds, err := strconv.Atoi(staleStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REMOTE_STEP_STALE_SECONDS: %v", err)
+		}
+		config.StaleSeconds = seconds
+	}
+
+	if err := config.normalize(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// normalize validates the configuration.
+func (c *StepWorkerConfig) normalize() error {
+	if c.TimeoutSeconds < 1 {
+		return fmt.Errorf("REMOTE_STEP_TIMEOUT_SECONDS must be at least 1, got: %d", c.TimeoutSeconds)
+	}
+	if c.StaleSeconds < 1 {
+		return fmt.Errorf("REMOTE_STEP_STALE_SECONDS must be at least 1, got: %d", c.StaleSeconds)
+	}
+	return nil
+}
+
+// IsRemote reports whether the step should be dispatched to a worker.
+func (c *StepWorkerConfig) IsRemote(step string) bool {
+	return c != nil && c.RemoteSteps[step]
+}
+
+// Steps returns the remote step names in sorted order.
+func (c *StepWorkerConfig) Steps() []string {
+	steps := make([]string, 0, len(c.RemoteSteps))
+	for step := range c.RemoteSteps {
+		steps = append(steps, step)
+	}
+	sort.Strings(steps)
+	return steps
+}
+
+// Timeout returns the remote step wait timeout as a duration.
+func (c *StepWorkerConfig) Timeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// StaleAfter returns how long a running job may go without a heartbeat before being requeued.
+func (c *StepWorkerConfig) StaleAfter() time.Duration {
+	return time.Duration(c.StaleSeconds) * time.Second
+}
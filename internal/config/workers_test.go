@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearStepWorkerEnv(t *testing.T) {
+	t.Setenv("REMOTE_STEPS", "")
+	t.Setenv("REMOTE_STEP_TIMEOUT_SECONDS", "")
+	t.Setenv("REMOTE_STEP_STALE_SECONDS", "")
+}
+
+func TestNewStepWorkerConfig_DefaultValues(t *testing.T) {
+	clearStepWorkerEnv(t)
+
+	cfg, err := NewStepWorkerConfig()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.RemoteSteps)
+	assert.False(t, cfg.IsRemote("research_company"))
+	assert.Equal(t, 600*time.Second, cfg.Timeout())
+	assert.Equal(t, 900*time.Second, cfg.StaleAfter())
+}
+
+func TestNewStepWorkerConfig_RemoteSteps(t *testing.T) {
+	clearStepWorkerEnv(t)
+	t.Setenv("REMOTE_STEPS", "validate_latex, research_company,")
+
+	cfg, err := NewStepWorkerConfig()
+	require.NoError(t, err)
+	assert.True(t, cfg.IsRemote("research_company"))
+	assert.True(t, cfg.IsRemote("validate_latex"))
+	assert.False(t, cfg.IsRemote("rewrite_bullets"))
+	assert.Equal(t, []string{"research_company", "validate_latex"}, cfg.Steps())
+}
+
+func TestNewStepWorkerConfig_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		stale   string
+	}{
+		{name: "non-numeric timeout", timeout: "abc"},
+		{name: "zero timeout", timeout: "0"},
+		{name: "non-numeric stale", stale: "soon"},
+		{name: "negative stale", stale: "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearStepWorkerEnv(t)
+			t.Setenv("REMOTE_STEP_TIMEOUT_SECONDS", tt.timeout)
+			t.Setenv("REMOTE_STEP_STALE_SECONDS", tt.stale)
+
+			_, err := NewStepWorkerConfig()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStepWorkerConfig_NilIsLocal(t *testing.T) {
+	var cfg *StepWorkerConfig
+	assert.False(t, cfg.IsRemote("research_company"))
+}
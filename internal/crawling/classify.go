@@ -18,7 +18,7 @@ type ClassifiedLink struct {
 
 // ClassifyLinks classifies URLs into categories using LLM
 func ClassifyLinks(ctx context.Context, links []string, apiKey string) ([]ClassifiedLink, error) {
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, &ClassificationError{Message: "API key is required"}
 	}
 
@@ -20,6 +20,10 @@ const (
 	MaxPagesLimit = 15
 	// DefaultRateLimitDelay is the delay between HTTP requests
 	DefaultRateLimitDelay = 1 * time.Second
+	// MaxCorpusBytes caps the text of a crawled corpus; pages past it are not fetched
+	MaxCorpusBytes = 2 << 20
+	// corpusSeparator separates the pages of a corpus
+	corpusSeparator = "\n\n---\n\n"
 )
 
 // CrawlOptions configures the crawl with optional database caching.
@@ -34,6 +38,10 @@ type CrawlOptions struct {
 	APIKey string
 	// MaxPages to crawl
 	MaxPages int
+	// DomainPolicy blocks domains from being crawled (optional)
+	DomainPolicy *fetch.DomainPolicy
+	// ArchiveFallback reads dead or bot-blocked pages from the Internet Archive (optional)
+	ArchiveFallback bool
 }
 
 // CrawlBrandCorpus crawls a company website and builds a text corpus.
@@ -91,10 +99,13 @@ func CrawlBrandCorpusWithCache(ctx context.Context, seedURLs []string, opts *Cra
 		if opts.CacheTTL > 0 {
 			config.CacheTTL = opts.CacheTTL
 		}
+		config.Policy = opts.DomainPolicy
+		config.ArchiveFallback = opts.ArchiveFallback
 		cachedFetcher = fetch.NewCachedFetcher(opts.Database, config)
 	}
 
 	var corpusParts []string
+	corpusBytes := 0
 	sources := make([]types.Source, 0)
 	visited := make(map[string]bool)
 	allLinks := make([]string, 0)
@@ -108,7 +119,16 @@ func CrawlBrandCorpusWithCache(ctx context.Context, seedURLs []string, opts *Cra
 			}
 			return result.Result, nil
 		}
-		return fetch.URL(ctx, pageURL, nil)
+		if blocked, reason := opts.DomainPolicy.Blocked(pageURL); blocked {
+			return nil, &fetch.Error{URL: pageURL, Message: "URL skipped: " + reason}
+		}
+		result, err := fetch.URL(ctx, pageURL, nil)
+		if opts.ArchiveFallback && fetch.NeedsArchive(result, err) {
+			if archived, archiveErr := fetch.Archived(ctx, pageURL, nil); archiveErr == nil {
+				return archived, nil
+			}
+		}
+		return result, err
 	}
 
 	// Phase 1: Fetch all seeds first
@@ -131,10 +151,12 @@ func CrawlBrandCorpusWithCache(ctx context.Context, seedURLs []string, opts *Cra
 			cleanedText := ingestion.CleanText(text)
 			hash := computeHash(cleanedText)
 			corpusParts = append(corpusParts, cleanedText)
+			corpusBytes += len(cleanedText) + len(corpusSeparator)
 			sources = append(sources, types.Source{
-				URL:       seed,
-				Timestamp: time.Now().UTC().Format(time.RFC3339),
-				Hash:      hash,
+				URL:        seed,
+				Timestamp:  time.Now().UTC().Format(time.RFC3339),
+				Hash:       hash,
+				SourceType: sourceType(result),
 			})
 		}
 
@@ -145,11 +167,10 @@ func CrawlBrandCorpusWithCache(ctx context.Context, seedURLs []string, opts *Cra
 		}
 	}
 
-	// If we've reached maxPages just with seeds, return early
-	if len(sources) >= maxPages {
-		corpus := strings.Join(corpusParts, "\n\n---\n\n")
+	// If we've reached maxPages or the corpus cap just with seeds, return early
+	if len(sources) >= maxPages || corpusBytes >= MaxCorpusBytes {
 		return &types.CompanyCorpus{
-			Corpus:  corpus,
+			Corpus:  joinCorpus(corpusParts),
 			Sources: sources,
 		}, nil
 	}
@@ -178,6 +199,9 @@ func CrawlBrandCorpusWithCache(ctx context.Context, seedURLs []string, opts *Cra
 				selectedURLs := selectPages(classified, maxPages-len(sources), validSeeds[0])
 
 				for _, pageURL := range selectedURLs {
+					if corpusBytes >= MaxCorpusBytes {
+						break
+					}
 					if visited[pageURL] {
 						continue
 					}
@@ -198,10 +222,12 @@ func CrawlBrandCorpusWithCache(ctx context.Context, seedURLs []string, opts *Cra
 						cleanedText := ingestion.CleanText(text)
 						hash := computeHash(cleanedText)
 						corpusParts = append(corpusParts, cleanedText)
+						corpusBytes += len(cleanedText) + len(corpusSeparator)
 						sources = append(sources, types.Source{
-							URL:       pageURL,
-							Timestamp: time.Now().UTC().Format(time.RFC3339),
-							Hash:      hash,
+							URL:        pageURL,
+							Timestamp:  time.Now().UTC().Format(time.RFC3339),
+							Hash:       hash,
+							SourceType: sourceType(result),
 						})
 					}
 				}
@@ -209,15 +235,22 @@ func CrawlBrandCorpusWithCache(ctx context.Context, seedURLs []string, opts *Cra
 		}
 	}
 
-	// Concatenate corpus with separators
-	corpus := strings.Join(corpusParts, "\n\n---\n\n")
-
 	return &types.CompanyCorpus{
-		Corpus:  corpus,
+		Corpus:  joinCorpus(corpusParts),
 		Sources: sources,
 	}, nil
 }
 
+// joinCorpus concatenates page texts with separators, cutting the result off at
+// MaxCorpusBytes with a truncation marker
+func joinCorpus(parts []string) string {
+	corpus, truncated := fetch.TruncateText(strings.Join(parts, corpusSeparator), MaxCorpusBytes)
+	if truncated {
+		fetch.RecordCorpusCapped()
+	}
+	return corpus
+}
+
 // selectPages selects pages to crawl based on classification
 func selectPages(classified []ClassifiedLink, maxPages int, homepageURL string) []string {
 	// Prioritize categories: values, careers, press (one each minimum)
@@ -277,3 +310,11 @@ func computeHash(content string) string {
 	hash := sha256.Sum256([]byte(content))
 	return hex.EncodeToString(hash[:])
 }
+
+// sourceType marks a source read from an Internet Archive snapshot
+func sourceType(result *fetch.Result) string {
+	if result.Archive != nil {
+		return db.PageSourceArchive
+	}
+	return ""
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/jonathan/resume-customizer/internal/fetch"
@@ -154,6 +155,17 @@ func TestCrawlBrandCorpus_HomepageOnly(t *testing.T) {
 }
 
 // Helper function
+func TestJoinCorpus_CapsSize(t *testing.T) {
+	assert.Equal(t, "a"+corpusSeparator+"b", joinCorpus([]string{"a", "b"}))
+
+	before := fetch.Stats().CorpusCapped
+	page := strings.Repeat("x", MaxCorpusBytes/2)
+	corpus := joinCorpus([]string{page, page, page})
+	assert.True(t, strings.HasPrefix(corpus, page+corpusSeparator))
+	assert.True(t, strings.HasSuffix(corpus, fmt.Sprintf("[truncated at %d bytes]", MaxCorpusBytes)))
+	assert.Equal(t, before+1, fetch.Stats().CorpusCapped)
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// resetRunReason is recorded on steps and step jobs failed by ResetRun
+const resetRunReason = "reset by an operator"
+
+// PurgeCrawledPages deletes cached pages fetched from domain or any of its subdomains,
+// so the next research session fetches them again. Returns the number of pages deleted.
+func (db *DB) PurgeCrawledPages(ctx context.Context, domain string) (int64, error) {
+	domain, err := NormalizePolicyDomain(domain)
+	if err != nil {
+		return 0, err
+	}
+	tag, err := db.pool.Exec(ctx,
+		`WITH pages AS (
+		     SELECT id, lower(substring(url FROM '^[a-zA-Z][a-zA-Z0-9+.-]*://(?:[^@/]*@)?([^/:?#]+)')) AS host
+		       FROM crawled_pages
+		 )
+		 DELETE FROM crawled_pages
+		  WHERE id IN (SELECT id FROM pages WHERE host = $1 OR host LIKE '%.' || $1)`,
+		domain,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge crawled pages: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ResetRun fails a queued or running run that is no longer making progress, along
+// with its unfinished steps and step jobs, so it can be retried or cleaned up. It
+// returns nil if the run does not exist; a run that already finished is left alone
+// and reported with Reset false.
+func (db *DB) ResetRun(ctx context.Context, runID uuid.UUID) (*RunReset, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	reset := RunReset{RunID: runID}
+	err = tx.QueryRow(ctx,
+		`SELECT status FROM pipeline_runs WHERE id = $1 FOR UPDATE`, runID,
+	).Scan(&reset.PreviousStatus)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get run: %w", err)
+	}
+	if reset.PreviousStatus != "queued" && reset.PreviousStatus != "running" {
+		return &reset, nil
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE pipeline_runs SET status = 'failed', completed_at = NOW() WHERE id = $1`, runID,
+	); err != nil {
+		return nil, fmt.Errorf("failed to reset run: %w", err)
+	}
+	tag, err := tx.Exec(ctx,
+		`UPDATE run_steps SET status = $2, error_message = $3, completed_at = NOW(), updated_at = NOW()
+		 WHERE run_id = $1 AND status IN ($4, $5)`,
+		runID, StepStatusFailed, resetRunReason, StepStatusPending, StepStatusInProgress,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset run steps: %w", err)
+	}
+	reset.Steps = tag.RowsAffected()
+	rows, err := tx.Query(ctx,
+		`UPDATE step_jobs SET status = 'failed', error_message = $2, completed_at = NOW(), updated_at = NOW()
+		 WHERE run_id = $1 AND status IN ('queued', 'running')
+		 RETURNING id`,
+		runID, resetRunReason,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset step jobs: %w", err)
+	}
+	var jobIDs []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		jobIDs = append(jobIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to reset step jobs: %w", err)
+	}
+	reset.StepJobs = int64(len(jobIDs))
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit run reset: %w", err)
+	}
+	reset.Reset = true
+
+	// Wake anything still waiting on the run or its jobs
+	for _, id := range jobIDs {
+		if err := db.Notify(ctx, StepJobDoneChannel, id.String()); err != nil {
+			return nil, err
+		}
+	}
+	db.publishRunEvent(ctx, RunEvent{Type: RunEventRunCompleted, RunID: runID, Status: "failed"})
+	return &reset, nil
+}
+
+// PruneOrphanSkills deletes catalog skills no bullet or user skill refers to any more,
+// so skill usage counts only cover skills still in someone's experience bank. Returns
+// the number of skills deleted.
+func (db *DB) PruneOrphanSkills(ctx context.Context) (int64, error) {
+	tag, err := db.pool.Exec(ctx,
+		`DELETE FROM skills s
+		 WHERE NOT EXISTS (SELECT 1 FROM bullet_skills bs WHERE bs.skill_id = s.id)
+		   AND NOT EXISTS (SELECT 1 FROM user_skills us WHERE us.skill_id = s.id)`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune skills: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetRunDates sets or clears the application deadline and follow-up date of a run.
+// Reminders already sent for the run are forgotten so a moved date reminds again.
+func (db *DB) SetRunDates(ctx context.Context, runID uuid.UUID, deadlineAt, followUpAt *time.Time) error {
+	tag, err := db.pool.Exec(ctx,
+		`UPDATE pipeline_runs SET deadline_at = $2, follow_up_at = $3 WHERE id = $1`,
+		runID, deadlineAt, followUpAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set run dates: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("run not found: %s", runID)
+	}
+
+	_, err = db.pool.Exec(ctx,
+		`DELETE FROM notification_deliveries WHERE ref_id = $1 AND event_type IN ($2, $3)`,
+		runID, ReminderDeadline, ReminderFollowUp,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reset run reminders: %w", err)
+	}
+	return nil
+}
+
+// ListApplicationDates returns the user's runs that have a deadline or follow-up date
+func (db *DB) ListApplicationDates(ctx context.Context, userID uuid.UUID) ([]Run, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, company, role_title, job_url, status, user_id, priority, created_at, completed_at,
+		        deadline_at, follow_up_at
+		 FROM pipeline_runs
+		 WHERE user_id = $1 AND (deadline_at IS NOT NULL OR follow_up_at IS NOT NULL)
+		 ORDER BY LEAST(deadline_at, follow_up_at)`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list application dates: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []Run
+	for rows.Next() {
+		var run Run
+		if err := rows.Scan(&run.ID, &run.Company, &run.RoleTitle, &run.JobURL, &run.Status, &run.UserID, &run.Priority, &run.CreatedAt, &run.CompletedAt,
+			&run.DeadlineAt, &run.FollowUpAt); err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// ListDueReminders returns deadlines, follow-up dates, and tracker next actions falling
+// within lead from now for users opted in to application reminders, skipping reminders
+// already sent
+func (db *DB) ListDueReminders(ctx context.Context, lead time.Duration) ([]ApplicationReminder, error) {
+	now := time.Now()
+	rows, err := db.pool.Query(ctx,
+		`WITH due AS (
+		     SELECT r.user_id, r.id AS ref_id, COALESCE(r.company, '') AS company, COALESCE(r.role_title, '') AS role_title,
+		            COALESCE(r.job_url, '') AS job_url, '' AS action, k.kind, k.due_at
+		     FROM pipeline_runs r
+		     CROSS JOIN LATERAL (VALUES ($1::text, r.deadline_at), ($2::text, r.follow_up_at)) AS k(kind, due_at)
+		     WHERE k.due_at > $4 AND k.due_at <= $5
+		     UNION ALL
+		     SELECT t.user_id, t.id, t.company, '', '', COALESCE(t.next_action, ''), $6::text, t.next_action_at
+		     FROM application_trackers t
+		     WHERE t.next_action_at > $4 AND t.next_action_at <= $5
+		 )
+		 SELECT u.id, u.name, u.email, u.timezone, p.channel, p.webhook_url, p.last_sent_at,
+		        due.ref_id, due.company, due.role_title, due.job_url, due.action, due.kind, due.due_at
+		 FROM due
+		 JOIN notification_preferences p ON p.user_id = due.user_id AND p.event_type = $3 AND p.channel <> 'none'
+		 JOIN users u ON u.id = due.user_id
+		 WHERE NOT EXISTS (
+		     SELECT 1 FROM notification_deliveries d WHERE d.event_type = due.kind AND d.ref_id = due.ref_id)
+		 ORDER BY due.due_at`,
+		ReminderDeadline, ReminderFollowUp, NotificationReminder, now, now.Add(lead), ReminderNextAction,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []ApplicationReminder
+	for rows.Next() {
+		var rem ApplicationReminder
+		r := &rem.Recipient
+		if err := rows.Scan(&r.UserID, &r.Name, &r.Email, &r.Timezone, &r.Channel, &r.WebhookURL, &r.LastSentAt,
+			&rem.RefID, &rem.Company, &rem.RoleTitle, &rem.JobURL, &rem.Action, &rem.Kind, &rem.DueAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, rem)
+	}
+	return reminders, rows.Err()
+}
@@ -0,0 +1,242 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const applicationTrackerColumns = `t.id, t.user_id, t.company, t.notes, t.next_action, t.next_action_at,
+	(SELECT max(i.occurred_at) FROM application_interactions i WHERE i.tracker_id = t.id),
+	t.created_at, t.updated_at`
+
+const applicationContactColumns = `id, tracker_id, name, role, email, linkedin_url, created_at, updated_at`
+
+const applicationInteractionColumns = `id, tracker_id, contact_id, kind, occurred_at, notes, created_at`
+
+func scanApplicationTracker(row pgx.Row) (*ApplicationTracker, error) {
+	var t ApplicationTracker
+	err := row.Scan(&t.ID, &t.UserID, &t.Company, &t.Notes, &t.NextAction, &t.NextActionAt,
+		&t.LastInteractionAt, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func scanApplicationContact(row pgx.Row) (*ApplicationContact, error) {
+	var c ApplicationContact
+	err := row.Scan(&c.ID, &c.TrackerID, &c.Name, &c.Role, &c.Email, &c.LinkedInURL, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func scanApplicationInteraction(row pgx.Row) (*ApplicationInteraction, error) {
+	var i ApplicationInteraction
+	err := row.Scan(&i.ID, &i.TrackerID, &i.ContactID, &i.Kind, &i.OccurredAt, &i.Notes, &i.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// UpsertApplicationTracker creates a tracker, or replaces the notes and next action of the
+// user's tracker for the same company. A reminder already sent for the next action is
+// forgotten so a new or moved one reminds again.
+func (db *DB) UpsertApplicationTracker(ctx context.Context, userID uuid.UUID, input *ApplicationTrackerInput) (*ApplicationTracker, error) {
+	var id uuid.UUID
+	err := db.pool.QueryRow(ctx,
+		`INSERT INTO application_trackers (user_id, company, notes, next_action, next_action_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id, lower(company)) DO UPDATE SET
+		     company = EXCLUDED.company,
+		     notes = EXCLUDED.notes,
+		     next_action = EXCLUDED.next_action,
+		     next_action_at = EXCLUDED.next_action_at,
+		     updated_at = NOW()
+		 RETURNING id`,
+		userID, input.Company, input.Notes, nullIfEmpty(input.NextAction), input.NextActionAt,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save application tracker: %w", err)
+	}
+
+	_, err = db.pool.Exec(ctx,
+		`DELETE FROM notification_deliveries WHERE ref_id = $1 AND event_type = $2`,
+		id, ReminderNextAction,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset next action reminder: %w", err)
+	}
+	return db.GetApplicationTracker(ctx, id)
+}
+
+// ListApplicationTrackers returns the user's trackers, soonest next action first and then
+// by company
+func (db *DB) ListApplicationTrackers(ctx context.Context, userID uuid.UUID) ([]ApplicationTracker, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+applicationTrackerColumns+` FROM application_trackers t
+		 WHERE t.user_id = $1
+		 ORDER BY t.next_action_at NULLS LAST, lower(t.company)`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list application trackers: %w", err)
+	}
+	defer rows.Close()
+
+	trackers := []ApplicationTracker{}
+	for rows.Next() {
+		t, err := scanApplicationTracker(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan application tracker: %w", err)
+		}
+		trackers = append(trackers, *t)
+	}
+	return trackers, rows.Err()
+}
+
+// GetApplicationTracker returns a tracker by ID, or nil if not found
+func (db *DB) GetApplicationTracker(ctx context.Context, id uuid.UUID) (*ApplicationTracker, error) {
+	t, err := scanApplicationTracker(db.pool.QueryRow(ctx,
+		`SELECT `+applicationTrackerColumns+` FROM application_trackers t WHERE t.id = $1`, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get application tracker: %w", err)
+	}
+	return t, nil
+}
+
+// DeleteApplicationTracker deletes a tracker with its contacts and interactions
+func (db *DB) DeleteApplicationTracker(ctx context.Context, id uuid.UUID) error {
+	result, err := db.pool.Exec(ctx, `DELETE FROM application_trackers WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete application tracker: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("application tracker not found: %s", id)
+	}
+	return nil
+}
+
+// ListApplicationContacts returns a tracker's contacts, by name
+func (db *DB) ListApplicationContacts(ctx context.Context, trackerID uuid.UUID) ([]ApplicationContact, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+applicationContactColumns+` FROM application_contacts
+		 WHERE tracker_id = $1 ORDER BY lower(name), created_at`,
+		trackerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list application contacts: %w", err)
+	}
+	defer rows.Close()
+
+	contacts := []ApplicationContact{}
+	for rows.Next() {
+		c, err := scanApplicationContact(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan application contact: %w", err)
+		}
+		contacts = append(contacts, *c)
+	}
+	return contacts, rows.Err()
+}
+
+// CreateApplicationContact adds a contact to a tracker
+func (db *DB) CreateApplicationContact(ctx context.Context, trackerID uuid.UUID, input *ApplicationContactInput) (*ApplicationContact, error) {
+	c, err := scanApplicationContact(db.pool.QueryRow(ctx,
+		`INSERT INTO application_contacts (tracker_id, name, role, email, linkedin_url)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+applicationContactColumns,
+		trackerID, input.Name, nullIfEmpty(input.Role), nullIfEmpty(input.Email), nullIfEmpty(input.LinkedInURL),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create application contact: %w", err)
+	}
+	return c, nil
+}
+
+// UpdateApplicationContact replaces one of a tracker's contacts. Returns nil if the
+// tracker has no such contact.
+func (db *DB) UpdateApplicationContact(ctx context.Context, trackerID, contactID uuid.UUID, input *ApplicationContactInput) (*ApplicationContact, error) {
+	c, err := scanApplicationContact(db.pool.QueryRow(ctx,
+		`UPDATE application_contacts
+		 SET name = $3, role = $4, email = $5, linkedin_url = $6, updated_at = NOW()
+		 WHERE id = $2 AND tracker_id = $1
+		 RETURNING `+applicationContactColumns,
+		trackerID, contactID, input.Name, nullIfEmpty(input.Role), nullIfEmpty(input.Email), nullIfEmpty(input.LinkedInURL),
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update application contact: %w", err)
+	}
+	return c, nil
+}
+
+// DeleteApplicationContact deletes one of a tracker's contacts; interactions with them
+// stay in the log. Reports whether the tracker had the contact.
+func (db *DB) DeleteApplicationContact(ctx context.Context, trackerID, contactID uuid.UUID) (bool, error) {
+	result, err := db.pool.Exec(ctx,
+		`DELETE FROM application_contacts WHERE id = $2 AND tracker_id = $1`, trackerID, contactID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete application contact: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// ListApplicationInteractions returns a tracker's interaction log, newest first
+func (db *DB) ListApplicationInteractions(ctx context.Context, trackerID uuid.UUID) ([]ApplicationInteraction, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+applicationInteractionColumns+` FROM application_interactions
+		 WHERE tracker_id = $1 ORDER BY occurred_at DESC, created_at DESC`,
+		trackerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list application interactions: %w", err)
+	}
+	defer rows.Close()
+
+	interactions := []ApplicationInteraction{}
+	for rows.Next() {
+		i, err := scanApplicationInteraction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan application interaction: %w", err)
+		}
+		interactions = append(interactions, *i)
+	}
+	return interactions, rows.Err()
+}
+
+// CreateApplicationInteraction logs an interaction on a tracker
+func (db *DB) CreateApplicationInteraction(ctx context.Context, trackerID uuid.UUID, input *ApplicationInteractionInput) (*ApplicationInteraction, error) {
+	i, err := scanApplicationInteraction(db.pool.QueryRow(ctx,
+		`INSERT INTO application_interactions (tracker_id, contact_id, kind, occurred_at, notes)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+applicationInteractionColumns,
+		trackerID, input.ContactID, input.Kind, input.OccurredAt, input.Notes,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create application interaction: %w", err)
+	}
+	return i, nil
+}
+
+// DeleteApplicationInteraction deletes an entry from a tracker's interaction log. Reports
+// whether the tracker had the entry.
+func (db *DB) DeleteApplicationInteraction(ctx context.Context, trackerID, interactionID uuid.UUID) (bool, error) {
+	result, err := db.pool.Exec(ctx,
+		`DELETE FROM application_interactions WHERE id = $2 AND tracker_id = $1`, trackerID, interactionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete application interaction: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
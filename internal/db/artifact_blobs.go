@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArtifactBlobGrace is how long a blob no artifact refers to is kept before it is deleted,
+// so a save that is about to refer to it again never finds it gone
+const ArtifactBlobGrace = time.Hour
+
+// artifactsWithBlobs joins artifacts to their content-addressed payloads. Artifacts saved
+// before content addressing keep their payload inline, so reads fall back to their own columns.
+const artifactsWithBlobs = `artifacts a LEFT JOIN artifact_blobs b ON b.hash = a.blob_hash`
+
+// latestArtifactsWithBlobs is artifactsWithBlobs over the latest version of each run's
+// artifact for a step
+const latestArtifactsWithBlobs = `latest_artifacts a LEFT JOIN artifact_blobs b ON b.hash = a.blob_hash`
+
+// artifactVersionAttempts bounds the saves that retry after a concurrent save of the same
+// run and step took the version they were about to add
+const artifactVersionAttempts = 5
+
+// Payload columns of artifactsWithBlobs, one per content format
+const (
+	artifactJSON   = `COALESCE(b.content, a.content)`
+	artifactText   = `COALESCE(b.text_content, a.text_content)`
+	artifactBinary = `COALESCE(b.binary_content, a.binary_content)`
+)
+
+// ArtifactStorage compares the artifact bytes runs refer to with the bytes stored for them
+type ArtifactStorage struct {
+	Artifacts         int64 `json:"artifacts"`
+	Blobs             int64 `json:"blobs"`
+	LogicalBytes      int64 `json:"logical_bytes"`      // Payload bytes summed over every artifact
+	StoredBytes       int64 `json:"stored_bytes"`       // Payload bytes actually stored, each blob once
+	InlineBytes       int64 `json:"inline_bytes"`       // Payload bytes of artifacts saved before content addressing
+	UnreferencedBytes int64 `json:"unreferenced_bytes"` // Blob bytes awaiting garbage collection
+}
+
+// artifactBlobHash returns the content address of an artifact payload. The format is part
+// of the address so the same bytes saved as text and as binary stay apart.
+func artifactBlobHash(format string, data []byte) string {
+	h := sha256.New()
+	h.Write([]byte(format + ":"))
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// saveArtifactBlob stores a run's artifact for a step by content address: the payload is
+// written once per distinct hash and the artifact refers to it. Each save adds the next
+// version of the artifact rather than replacing the last. Reference counts are kept by
+// the artifacts_blob_refs trigger.
+func (db *DB) saveArtifactBlob(ctx context.Context, runID uuid.UUID, step, category, format string, data []byte) error {
+	var jsonContent, binaryContent []byte
+	var textContent *string
+	switch format {
+	case ContentFormatJSON:
+		jsonContent = data
+	case ContentFormatText:
+		text := string(data)
+		textContent = &text
+	default:
+		binaryContent = data
+	}
+
+	hash := artifactBlobHash(format, data)
+	for range artifactVersionAttempts {
+		result, err := db.pool.Exec(ctx,
+			`WITH blob AS (
+			     INSERT INTO artifact_blobs (hash, format, content, text_content, binary_content, size_bytes)
+			     VALUES ($4, $5, $6, $7, $8, $9)
+			     ON CONFLICT (hash) DO UPDATE SET touched_at = NOW()
+			     RETURNING hash
+			 )
+			 INSERT INTO artifacts (run_id, step, category, blob_hash, version)
+			 SELECT $1::uuid, $2::text, $3::text, hash,
+			        COALESCE((SELECT MAX(version) FROM artifacts WHERE run_id = $1 AND step = $2), 0) + 1
+			 FROM blob
+			 ON CONFLICT (run_id, step, version) DO NOTHING`,
+			runID, step, category, hash, format, jsonContent, textContent, binaryContent, len(data),
+		)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("concurrent saves kept taking the next version")
+}
+
+// DeleteUnreferencedArtifactBlobs deletes blobs no artifact has referred to for longer than
+// grace. Returns the number of blobs deleted.
+func (db *DB) DeleteUnreferencedArtifactBlobs(ctx context.Context, grace time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-grace)
+	result, err := db.pool.Exec(ctx,
+		`DELETE FROM artifact_blobs b
+		 WHERE b.ref_count <= 0 AND b.touched_at < $1
+		   AND NOT EXISTS (SELECT 1 FROM artifacts a WHERE a.blob_hash = b.hash)`,
+		cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete unreferenced artifact blobs: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// GetArtifactStorage reports how much artifact storage content addressing saves
+func (db *DB) GetArtifactStorage(ctx context.Context) (*ArtifactStorage, error) {
+	var s ArtifactStorage
+	err := db.pool.QueryRow(ctx,
+		`SELECT (SELECT COUNT(*) FROM artifacts),
+		        (SELECT COUNT(*) FROM artifact_blobs),
+		        (SELECT COALESCE(SUM(b.size_bytes), 0) FROM artifacts a JOIN artifact_blobs b ON b.hash = a.blob_hash),
+		        (SELECT COALESCE(SUM(size_bytes), 0) FROM artifact_blobs),
+		        (SELECT COALESCE(SUM(octet_length(`+artifactBytes+`)), 0) FROM `+artifactsWithBlobs+` WHERE a.blob_hash IS NULL),
+		        (SELECT COALESCE(SUM(size_bytes), 0) FROM artifact_blobs WHERE ref_count <= 0)`,
+	).Scan(&s.Artifacts, &s.Blobs, &s.LogicalBytes, &s.StoredBytes, &s.InlineBytes, &s.UnreferencedBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artifact storage: %w", err)
+	}
+	// Inline payloads count toward both totals
+	s.LogicalBytes += s.InlineBytes
+	s.StoredBytes += s.InlineBytes
+	return &s, nil
+}
@@ -0,0 +1,15 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifactBlobHash(t *testing.T) {
+	text := artifactBlobHash(ContentFormatText, []byte("posting"))
+	assert.Len(t, text, 64)
+	assert.Equal(t, text, artifactBlobHash(ContentFormatText, []byte("posting")), "equal payloads share a blob")
+	assert.NotEqual(t, text, artifactBlobHash(ContentFormatText, []byte("posting v2")))
+	assert.NotEqual(t, text, artifactBlobHash(ContentFormatBinary, []byte("posting")), "formats are stored apart")
+}
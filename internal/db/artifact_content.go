@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Artifact content formats, by the column an artifact's payload is stored in
+const (
+	ContentFormatBinary = "binary" // binary_content, e.g. PDFs
+	ContentFormatText   = "text"   // text_content, e.g. raw HTML and corpus text
+	ContentFormatJSON   = "json"   // content
+)
+
+// artifactChunkSize is how many bytes of an artifact each database read fetches
+const artifactChunkSize = 1 << 20
+
+// artifactBytes is an artifact's content as bytes, whichever column it is stored in.
+// Text is counted in UTF-8 bytes so ranges line up with what clients receive.
+const artifactBytes = `COALESCE(` + artifactBinary + `, convert_to(` + artifactText + `, 'UTF8'), convert_to(` + artifactJSON + `::text, 'UTF8'))`
+
+// ErrArtifactChanged is returned when an artifact is deleted while its content is read
+var ErrArtifactChanged = errors.New("artifact changed while it was being read")
+
+// ArtifactContent is an artifact's stored bytes, read from the database a chunk at a time
+// as they are needed instead of loaded whole. Its SectionReader makes it an io.ReadSeeker
+// and io.ReaderAt, so it can be passed to http.ServeContent to answer range requests.
+type ArtifactContent struct {
+	ID        uuid.UUID
+	RunID     uuid.UUID
+	Step      string
+	Category  string
+	Format    string // binary, text, or json
+	Size      int64  // In bytes
+	CreatedAt time.Time
+	*io.SectionReader
+}
+
+// OpenArtifactContent returns a reader over an artifact's content, or nil if the
+// artifact does not exist or has no content. Chunks are read with ctx.
+func (db *DB) OpenArtifactContent(ctx context.Context, artifactID uuid.UUID) (*ArtifactContent, error) {
+	return db.openArtifactContent(ctx, artifactsWithBlobs, `a.id = $1`, artifactID)
+}
+
+// OpenRunArtifactContent returns a reader over the content of the latest version of a
+// run's artifact for a step, or nil if the run has no such artifact. Chunks are read with ctx.
+func (db *DB) OpenRunArtifactContent(ctx context.Context, runID uuid.UUID, step string) (*ArtifactContent, error) {
+	return db.openArtifactContent(ctx, latestArtifactsWithBlobs, `a.run_id = $1 AND a.step = $2`, runID, step)
+}
+
+func (db *DB) openArtifactContent(ctx context.Context, from, where string, args ...any) (*ArtifactContent, error) {
+	var c ArtifactContent
+	var category *string
+	err := db.pool.QueryRow(ctx,
+		`SELECT a.id, a.run_id, a.step, a.category,
+		        CASE WHEN `+artifactBinary+` IS NOT NULL THEN 'binary'
+		             WHEN `+artifactText+` IS NOT NULL THEN 'text'
+		             WHEN `+artifactJSON+` IS NOT NULL THEN 'json'
+		             ELSE '' END,
+		        COALESCE(octet_length(`+artifactBytes+`), 0), a.created_at
+		 FROM `+from+` WHERE `+where,
+		args...,
+	).Scan(&c.ID, &c.RunID, &c.Step, &category, &c.Format, &c.Size, &c.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open artifact content: %w", err)
+	}
+	if c.Format == "" {
+		return nil, nil
+	}
+	if category != nil {
+		c.Category = *category
+	}
+
+	id, createdAt := c.ID, c.CreatedAt
+	chunks := &artifactChunkReader{read: func(offset int64, length int) ([]byte, error) {
+		var chunk []byte
+		err := db.pool.QueryRow(ctx,
+			`SELECT substring(`+artifactBytes+` FROM $2 FOR $3)
+			 FROM `+artifactsWithBlobs+` WHERE a.id = $1 AND a.created_at = $4`,
+			id, offset+1, length, createdAt,
+		).Scan(&chunk)
+		if err != nil {
+			// The row is gone, so its bytes can no longer be read consistently
+			if err == pgx.ErrNoRows {
+				return nil, ErrArtifactChanged
+			}
+			return nil, fmt.Errorf("failed to read artifact content: %w", err)
+		}
+		return chunk, nil
+	}}
+	c.SectionReader = io.NewSectionReader(chunks, 0, c.Size)
+	return &c, nil
+}
+
+// artifactChunkReader reads an artifact's bytes a chunk at a time, keeping the last chunk
+// so small sequential reads share a query
+type artifactChunkReader struct {
+	read func(offset int64, length int) ([]byte, error) // Fewer bytes than length only at the end
+
+	chunk       []byte
+	chunkOffset int64
+}
+
+// ReadAt implements io.ReaderAt
+func (r *artifactChunkReader) ReadAt(p []byte, off int64) (int, error) {
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos < r.chunkOffset || pos >= r.chunkOffset+int64(len(r.chunk)) {
+			chunk, err := r.read(pos, max(len(p)-n, artifactChunkSize))
+			if err != nil {
+				return n, err
+			}
+			if len(chunk) == 0 {
+				return n, io.EOF
+			}
+			r.chunk, r.chunkOffset = chunk, pos
+		}
+		n += copy(p[n:], r.chunk[pos-r.chunkOffset:])
+	}
+	return n, nil
+}
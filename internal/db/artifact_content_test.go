@@ -0,0 +1,47 @@
+package db
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactChunkReader(t *testing.T) {
+	data := strings.Repeat("0123456789", artifactChunkSize/5) // Two chunks
+	reads := 0
+	chunks := &artifactChunkReader{read: func(offset int64, length int) ([]byte, error) {
+		reads++
+		end := min(offset+int64(length), int64(len(data)))
+		if offset >= end {
+			return nil, nil
+		}
+		return []byte(data[offset:end]), nil
+	}}
+	content := io.NewSectionReader(chunks, 0, int64(len(data)))
+
+	got, err := io.ReadAll(content)
+	require.NoError(t, err)
+	assert.Equal(t, data, string(got))
+	assert.Equal(t, 2, reads, "sequential reads share chunks")
+
+	buf := make([]byte, 4)
+	n, err := content.ReadAt(buf, int64(len(data))-2)
+	assert.Equal(t, 2, n)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, "89", string(buf[:n]))
+
+	_, err = content.Seek(15, io.SeekStart)
+	require.NoError(t, err)
+	n, err = content.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "5678", string(buf[:n]))
+}
+
+func TestArtifactChunkReader_Error(t *testing.T) {
+	chunks := &artifactChunkReader{read: func(int64, int) ([]byte, error) { return nil, ErrArtifactChanged }}
+	_, err := chunks.ReadAt(make([]byte, 10), 0)
+	assert.ErrorIs(t, err, ErrArtifactChanged)
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArtifactVersion is one saved version of a run's artifact for a step
+type ArtifactVersion struct {
+	ID        uuid.UUID `json:"id"`
+	Version   int       `json:"version"`
+	Category  string    `json:"category"`
+	SizeBytes int64     `json:"size_bytes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListArtifactVersions lists every saved version of a run's artifact for a step, oldest
+// first. The list is empty if the step saved nothing.
+func (db *DB) ListArtifactVersions(ctx context.Context, runID uuid.UUID, step string) ([]ArtifactVersion, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT a.id, a.version, COALESCE(a.category, ''),
+		        COALESCE(octet_length(`+artifactBytes+`), 0), a.created_at
+		 FROM `+artifactsWithBlobs+`
+		 WHERE a.run_id = $1 AND a.step = $2
+		 ORDER BY a.version`,
+		runID, step,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []ArtifactVersion{}
+	for rows.Next() {
+		var v ArtifactVersion
+		if err := rows.Scan(&v.ID, &v.Version, &v.Category, &v.SizeBytes, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan artifact version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+	}
+	return versions, nil
+}
+
+// GetArtifactVersion retrieves one version of a run's artifact for a step, or nil if
+// the step never saved that version
+func (db *DB) GetArtifactVersion(ctx context.Context, runID uuid.UUID, step string, version int) (*Artifact, error) {
+	return db.getArtifact(ctx, `a.run_id = $1 AND a.step = $2 AND a.version = $3`, runID, step, version)
+}
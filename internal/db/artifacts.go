@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jonathan/resume-customizer/internal/types"
@@ -137,3 +138,17 @@ func (db *DB) GetViolationsByRunID(ctx context.Context, runID uuid.UUID) (*types
 	}
 	return &violations, nil
 }
+
+// DeleteExpiredDebugArtifacts removes debug-mode artifacts older than the retention period.
+// Returns the number of artifacts deleted.
+func (db *DB) DeleteExpiredDebugArtifacts(ctx context.Context, retention time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-retention)
+	result, err := db.pool.Exec(ctx,
+		`DELETE FROM artifacts WHERE category = $1 AND created_at < $2`,
+		CategoryDebug, cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired debug artifacts: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -158,29 +158,42 @@ func (db *DB) ListCompanyDomains(ctx context.Context, companyID uuid.UUID) ([]Co
 	return domains, nil
 }
 
-// ListCompaniesWithProfiles returns companies that have research profiles, with pagination
-func (db *DB) ListCompaniesWithProfiles(ctx context.Context, limit, offset int) ([]Company, int, error) {
+// ListCompaniesWithProfiles returns up to limit companies that have research profiles,
+// by name unless page sorts over CompanySortKeys, and the number of such companies
+func (db *DB) ListCompaniesWithProfiles(ctx context.Context, limit int, page Page) ([]Company, int, error) {
+	const hasProfile = `EXISTS (SELECT 1 FROM company_profiles cp WHERE cp.company_id = c.id)`
+
 	// Get total count
 	var total int
 	err := db.pool.QueryRow(ctx,
-		`SELECT COUNT(DISTINCT c.id)
-		 FROM companies c
-		 INNER JOIN company_profiles cp ON cp.company_id = c.id`,
+		`SELECT COUNT(*) FROM companies c WHERE `+hasProfile,
 	).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count companies: %w", err)
 	}
 
 	// Get companies
-	rows, err := db.pool.Query(ctx,
-		`SELECT DISTINCT c.id, c.name, c.name_normalized, c.domain, c.industry, 
+	query := `SELECT c.id, c.name, c.name_normalized, c.domain, c.industry,
 		        c.created_at, c.updated_at
 		 FROM companies c
-		 INNER JOIN company_profiles cp ON cp.company_id = c.id
-		 ORDER BY c.name
-		 LIMIT $1 OFFSET $2`,
-		limit, offset,
-	)
+		 WHERE ` + hasProfile
+	args := []any{}
+	orderBy := " ORDER BY c.name"
+	if page.Sort != "" {
+		cond, pageOrder, pageArgs, err := pageClause(page, CompanySortKeys, "c.id", 1)
+		if err != nil {
+			return nil, 0, err
+		}
+		if cond != "" {
+			query += " AND " + cond
+			args = append(args, pageArgs...)
+		}
+		orderBy = pageOrder
+	}
+	args = append(args, limit)
+	query += orderBy + fmt.Sprintf(" LIMIT $%d", len(args))
+
+	rows, err := db.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to list companies: %w", err)
 	}
@@ -209,12 +222,12 @@ func (db *DB) GetCrawledPageByID(ctx context.Context, id uuid.UUID) (*CrawledPag
 	err := db.pool.QueryRow(ctx,
 		`SELECT id, company_id, url, page_type, raw_html, parsed_text, content_hash, 
 		        http_status, fetch_status, error_message, is_permanent_failure, retry_count, retry_after,
-		        fetched_at, expires_at, last_accessed_at, created_at, updated_at
+		        fetched_at, expires_at, last_accessed_at, created_at, updated_at, source_type, snapshot_url
 		 FROM crawled_pages WHERE id = $1`,
 		id,
 	).Scan(&p.ID, &p.CompanyID, &p.URL, &p.PageType, &p.RawHTML, &p.ParsedText, &p.ContentHash,
 		&p.HTTPStatus, &p.FetchStatus, &p.ErrorMessage, &p.IsPermanentFailure, &p.RetryCount, &p.RetryAfter,
-		&p.FetchedAt, &p.ExpiresAt, &p.LastAccessedAt, &p.CreatedAt, &p.UpdatedAt)
+		&p.FetchedAt, &p.ExpiresAt, &p.LastAccessedAt, &p.CreatedAt, &p.UpdatedAt, &p.SourceType, &p.SnapshotURL)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -230,12 +243,12 @@ func (db *DB) GetCrawledPageByURL(ctx context.Context, pageURL string) (*Crawled
 	err := db.pool.QueryRow(ctx,
 		`SELECT id, company_id, url, page_type, raw_html, parsed_text, content_hash, 
 		        http_status, fetch_status, error_message, is_permanent_failure, retry_count, retry_after,
-		        fetched_at, expires_at, last_accessed_at, created_at, updated_at
+		        fetched_at, expires_at, last_accessed_at, created_at, updated_at, source_type, snapshot_url
 		 FROM crawled_pages WHERE url = $1`,
 		pageURL,
 	).Scan(&p.ID, &p.CompanyID, &p.URL, &p.PageType, &p.RawHTML, &p.ParsedText, &p.ContentHash,
 		&p.HTTPStatus, &p.FetchStatus, &p.ErrorMessage, &p.IsPermanentFailure, &p.RetryCount, &p.RetryAfter,
-		&p.FetchedAt, &p.ExpiresAt, &p.LastAccessedAt, &p.CreatedAt, &p.UpdatedAt)
+		&p.FetchedAt, &p.ExpiresAt, &p.LastAccessedAt, &p.CreatedAt, &p.UpdatedAt, &p.SourceType, &p.SnapshotURL)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -319,12 +332,16 @@ func (db *DB) UpsertCrawledPage(ctx context.Context, page *CrawledPage) error {
 	if fetchStatus == "" {
 		fetchStatus = FetchStatusSuccess
 	}
+	sourceType := page.SourceType
+	if sourceType == "" {
+		sourceType = PageSourceLive
+	}
 
 	err := db.pool.QueryRow(ctx,
 		`INSERT INTO crawled_pages (company_id, url, page_type, raw_html, parsed_text, content_hash, 
 		                            http_status, fetch_status, error_message, is_permanent_failure, 
-		                            retry_count, fetched_at, expires_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 0, NOW(), $11)
+		                            retry_count, fetched_at, expires_at, source_type, snapshot_url)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 0, NOW(), $11, $12, $13)
 		 ON CONFLICT (url) DO UPDATE SET
 		     company_id = COALESCE($1, crawled_pages.company_id),
 		     page_type = COALESCE($3, crawled_pages.page_type),
@@ -339,10 +356,13 @@ func (db *DB) UpsertCrawledPage(ctx context.Context, page *CrawledPage) error {
 		     retry_after = NULL,
 		     fetched_at = NOW(),
 		     expires_at = $11,
+		     source_type = $12,
+		     snapshot_url = $13,
 		     updated_at = NOW()
 		 RETURNING id, fetched_at, created_at, updated_at`,
 		page.CompanyID, page.URL, page.PageType, page.RawHTML, page.ParsedText, contentHash,
 		page.HTTPStatus, fetchStatus, page.ErrorMessage, page.IsPermanentFailure, expiresAt,
+		sourceType, page.SnapshotURL,
 	).Scan(&page.ID, &page.FetchedAt, &page.CreatedAt, &page.UpdatedAt)
 
 	if err != nil {
@@ -404,7 +424,7 @@ func (db *DB) ListCrawledPagesByCompany(ctx context.Context, companyID uuid.UUID
 	rows, err := db.pool.Query(ctx,
 		`SELECT id, company_id, url, page_type, parsed_text, content_hash, 
 		        http_status, fetch_status, error_message, is_permanent_failure, retry_count, retry_after,
-		        fetched_at, expires_at, last_accessed_at, created_at, updated_at
+		        fetched_at, expires_at, last_accessed_at, created_at, updated_at, source_type, snapshot_url
 		 FROM crawled_pages 
 		 WHERE company_id = $1
 		 ORDER BY fetched_at DESC`,
@@ -421,7 +441,7 @@ func (db *DB) ListCrawledPagesByCompany(ctx context.Context, companyID uuid.UUID
 		// Note: raw_html intentionally omitted (large field, use GetCrawledPageByID if needed)
 		if err := rows.Scan(&p.ID, &p.CompanyID, &p.URL, &p.PageType, &p.ParsedText, &p.ContentHash,
 			&p.HTTPStatus, &p.FetchStatus, &p.ErrorMessage, &p.IsPermanentFailure, &p.RetryCount, &p.RetryAfter,
-			&p.FetchedAt, &p.ExpiresAt, &p.LastAccessedAt, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			&p.FetchedAt, &p.ExpiresAt, &p.LastAccessedAt, &p.CreatedAt, &p.UpdatedAt, &p.SourceType, &p.SnapshotURL); err != nil {
 			return nil, fmt.Errorf("failed to scan page: %w", err)
 		}
 		pages = append(pages, p)
@@ -436,7 +456,7 @@ func (db *DB) ListFreshPagesByCompany(ctx context.Context, companyID uuid.UUID,
 	rows, err := db.pool.Query(ctx,
 		`SELECT id, company_id, url, page_type, parsed_text, content_hash, 
 		        http_status, fetch_status, error_message, is_permanent_failure, retry_count, retry_after,
-		        fetched_at, expires_at, last_accessed_at, created_at, updated_at
+		        fetched_at, expires_at, last_accessed_at, created_at, updated_at, source_type, snapshot_url
 		 FROM crawled_pages 
 		 WHERE company_id = $1 AND fetched_at > $2 AND fetch_status = $3
 		 ORDER BY fetched_at DESC`,
@@ -453,7 +473,7 @@ func (db *DB) ListFreshPagesByCompany(ctx context.Context, companyID uuid.UUID,
 		// Note: raw_html intentionally omitted (large field)
 		if err := rows.Scan(&p.ID, &p.CompanyID, &p.URL, &p.PageType, &p.ParsedText, &p.ContentHash,
 			&p.HTTPStatus, &p.FetchStatus, &p.ErrorMessage, &p.IsPermanentFailure, &p.RetryCount, &p.RetryAfter,
-			&p.FetchedAt, &p.ExpiresAt, &p.LastAccessedAt, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			&p.FetchedAt, &p.ExpiresAt, &p.LastAccessedAt, &p.CreatedAt, &p.UpdatedAt, &p.SourceType, &p.SnapshotURL); err != nil {
 			return nil, fmt.Errorf("failed to scan page: %w", err)
 		}
 		pages = append(pages, p)
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const companyPreferenceColumns = `id, user_id, company, name, tone_override, pinned_bullets, template,
+	section_order, created_at, updated_at`
+
+func scanCompanyPreference(row pgx.Row) (*CompanyPreference, error) {
+	var p CompanyPreference
+	err := row.Scan(&p.ID, &p.UserID, &p.Company, &p.Name, &p.ToneOverride, &p.PinnedBullets,
+		&p.Template, &p.SectionOrder, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// UpsertCompanyPreference creates a preset, or replaces the user's preset with the same
+// company and name
+func (db *DB) UpsertCompanyPreference(ctx context.Context, userID uuid.UUID, input *CompanyPreferenceInput) (*CompanyPreference, error) {
+	pinned := input.PinnedBullets
+	if pinned == nil {
+		pinned = []string{}
+	}
+	order := input.SectionOrder
+	if order == nil {
+		order = []string{}
+	}
+	p, err := scanCompanyPreference(db.pool.QueryRow(ctx,
+		`INSERT INTO company_preferences (user_id, company, name, tone_override, pinned_bullets, template, section_order)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (user_id, lower(company), lower(name)) DO UPDATE SET
+		     company = EXCLUDED.company,
+		     name = EXCLUDED.name,
+		     tone_override = EXCLUDED.tone_override,
+		     pinned_bullets = EXCLUDED.pinned_bullets,
+		     template = EXCLUDED.template,
+		     section_order = EXCLUDED.section_order,
+		     updated_at = NOW()
+		 RETURNING `+companyPreferenceColumns,
+		userID, input.Company, input.Name, nullIfEmpty(input.ToneOverride), pinned,
+		nullIfEmpty(input.Template), order,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save company preference: %w", err)
+	}
+	return p, nil
+}
+
+// ListCompanyPreferences returns the user's presets, ordered by company and name. A
+// non-empty company lists only that company's presets, whatever its capitalization.
+func (db *DB) ListCompanyPreferences(ctx context.Context, userID uuid.UUID, company string) ([]CompanyPreference, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+companyPreferenceColumns+` FROM company_preferences
+		 WHERE user_id = $1 AND ($2 = '' OR lower(company) = lower($2))
+		 ORDER BY lower(company), lower(name)`,
+		userID, company,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list company preferences: %w", err)
+	}
+	defer rows.Close()
+
+	prefs := []CompanyPreference{}
+	for rows.Next() {
+		p, err := scanCompanyPreference(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan company preference: %w", err)
+		}
+		prefs = append(prefs, *p)
+	}
+	return prefs, rows.Err()
+}
+
+// GetCompanyPreference returns a preset by ID, or nil if not found
+func (db *DB) GetCompanyPreference(ctx context.Context, id uuid.UUID) (*CompanyPreference, error) {
+	p, err := scanCompanyPreference(db.pool.QueryRow(ctx,
+		`SELECT `+companyPreferenceColumns+` FROM company_preferences WHERE id = $1`, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get company preference: %w", err)
+	}
+	return p, nil
+}
+
+// DeleteCompanyPreference deletes a preset
+func (db *DB) DeleteCompanyPreference(ctx context.Context, id uuid.UUID) error {
+	result, err := db.pool.Exec(ctx, `DELETE FROM company_preferences WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete company preference: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("company preference not found: %s", id)
+	}
+	return nil
+}
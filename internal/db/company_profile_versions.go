@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// -----------------------------------------------------------------------------
+// Company Profile Version Methods
+// -----------------------------------------------------------------------------
+
+// insertProfileVersion keeps the profile version input generated, so regenerating the
+// profile later does not lose it
+func insertProfileVersion(ctx context.Context, tx pgx.Tx, p *CompanyProfile, input *ProfileCreateInput) error {
+	taboo := make([]VersionTabooPhrase, 0, len(input.TabooPhrases))
+	for _, t := range input.TabooPhrases {
+		taboo = append(taboo, VersionTabooPhrase{Phrase: t.Phrase, Reason: t.Reason})
+	}
+	urls := make([]string, 0, len(input.EvidenceURLs))
+	for _, source := range input.EvidenceURLs {
+		urls = append(urls, source.URL)
+	}
+	styleRules, _ := json.Marshal(nonNilStrings(input.StyleRules))
+	tabooPhrases, _ := json.Marshal(taboo)
+	values, _ := json.Marshal(nonNilStrings(input.Values))
+	evidenceURLs, _ := json.Marshal(urls)
+
+	_, err := tx.Exec(ctx,
+		`INSERT INTO company_profile_versions (company_id, profile_id, version, tone, domain_context,
+		                                       style_rules, taboo_phrases, core_values, evidence_urls)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		p.CompanyID, p.ID, p.Version, p.Tone, p.DomainContext,
+		styleRules, tabooPhrases, values, evidenceURLs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert company profile version: %w", err)
+	}
+	return nil
+}
+
+// ListCompanyProfileVersions returns every kept version of a company's profile, newest first
+func (db *DB) ListCompanyProfileVersions(ctx context.Context, companyID uuid.UUID) ([]CompanyProfileVersion, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, company_id, profile_id, version, tone, domain_context,
+		        style_rules, taboo_phrases, core_values, evidence_urls, created_at
+		 FROM company_profile_versions
+		 WHERE company_id = $1
+		 ORDER BY version DESC`,
+		companyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list company profile versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []CompanyProfileVersion
+	for rows.Next() {
+		var v CompanyProfileVersion
+		var styleRules, tabooPhrases, values, evidenceURLs []byte
+		if err := rows.Scan(&v.ID, &v.CompanyID, &v.ProfileID, &v.Version, &v.Tone, &v.DomainContext,
+			&styleRules, &tabooPhrases, &values, &evidenceURLs, &v.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan company profile version: %w", err)
+		}
+		v.StyleRules, v.TabooPhrases, v.Values, v.EvidenceURLs = []string{}, []VersionTabooPhrase{}, []string{}, []string{}
+		for _, column := range []struct {
+			data []byte
+			into any
+		}{{styleRules, &v.StyleRules}, {tabooPhrases, &v.TabooPhrases}, {values, &v.Values}, {evidenceURLs, &v.EvidenceURLs}} {
+			if err := json.Unmarshal(column.data, column.into); err != nil {
+				return nil, fmt.Errorf("failed to parse company profile version %d: %w", v.Version, err)
+			}
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// DiffProfileVersions returns what changed from one profile version to another. List
+// entries are matched ignoring case and surrounding space, so a regeneration that only
+// recapitalizes a value does not report it as replaced; taboo phrases are matched by phrase.
+func DiffProfileVersions(from, to *CompanyProfileVersion) *ProfileVersionDiff {
+	diff := &ProfileVersionDiff{
+		CompanyID:    to.CompanyID,
+		From:         from.Version,
+		To:           to.Version,
+		Values:       diffProfileList(from.Values, to.Values),
+		TabooPhrases: diffProfileList(tabooPhraseTexts(from.TabooPhrases), tabooPhraseTexts(to.TabooPhrases)),
+		StyleRules:   diffProfileList(from.StyleRules, to.StyleRules),
+		EvidenceURLs: diffProfileList(from.EvidenceURLs, to.EvidenceURLs),
+	}
+	if from.Tone != to.Tone {
+		diff.Tone = &ProfileTextChange{From: from.Tone, To: to.Tone}
+	}
+	if fromContext, toContext := stringOrEmpty(from.DomainContext), stringOrEmpty(to.DomainContext); fromContext != toContext {
+		diff.DomainContext = &ProfileTextChange{From: fromContext, To: toContext}
+	}
+	diff.Changed = diff.Tone != nil || diff.DomainContext != nil
+	for _, list := range []ProfileListChange{diff.Values, diff.TabooPhrases, diff.StyleRules, diff.EvidenceURLs} {
+		diff.Changed = diff.Changed || len(list.Added) > 0 || len(list.Removed) > 0
+	}
+	return diff
+}
+
+// diffProfileList returns the entries of to missing from from, and of from missing from to,
+// each in its list's order
+func diffProfileList(from, to []string) ProfileListChange {
+	key := func(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+	inFrom, inTo := map[string]bool{}, map[string]bool{}
+	for _, s := range from {
+		inFrom[key(s)] = true
+	}
+	for _, s := range to {
+		inTo[key(s)] = true
+	}
+
+	change := ProfileListChange{Added: []string{}, Removed: []string{}}
+	for _, s := range to {
+		if !inFrom[key(s)] {
+			change.Added = append(change.Added, s)
+		}
+	}
+	for _, s := range from {
+		if !inTo[key(s)] {
+			change.Removed = append(change.Removed, s)
+		}
+	}
+	return change
+}
+
+// tabooPhraseTexts returns the phrases of a version's taboo phrases
+func tabooPhraseTexts(phrases []VersionTabooPhrase) []string {
+	texts := make([]string, len(phrases))
+	for i, p := range phrases {
+		texts[i] = p.Phrase
+	}
+	return texts
+}
+
+// stringOrEmpty returns *s, or "" when s is nil
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// nonNilStrings returns s, or an empty slice when s is nil, so it encodes as a JSON array
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffProfileVersions(t *testing.T) {
+	companyID := uuid.New()
+	fintech := "FinTech"
+	from := &CompanyProfileVersion{
+		CompanyID:     companyID,
+		Version:       1,
+		Tone:          "direct and technical",
+		DomainContext: &fintech,
+		Values:        []string{"Customer obsession", "Bias for action"},
+		TabooPhrases:  []VersionTabooPhrase{{Phrase: "rockstar", Reason: "exclusionary"}},
+		StyleRules:    []string{"Lead with impact"},
+		EvidenceURLs:  []string{"https://example.com/values"},
+	}
+	to := &CompanyProfileVersion{
+		CompanyID:    companyID,
+		Version:      3,
+		Tone:         "warm and inclusive",
+		Values:       []string{"customer obsession ", "Earn trust"},
+		TabooPhrases: []VersionTabooPhrase{{Phrase: "Rockstar"}, {Phrase: "ninja"}},
+		StyleRules:   []string{"Lead with impact"},
+		EvidenceURLs: []string{"https://example.com/values"},
+	}
+
+	diff := DiffProfileVersions(from, to)
+	assert.True(t, diff.Changed)
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 3, diff.To)
+	assert.Equal(t, &ProfileTextChange{From: "direct and technical", To: "warm and inclusive"}, diff.Tone)
+	assert.Equal(t, &ProfileTextChange{From: "FinTech", To: ""}, diff.DomainContext)
+	assert.Equal(t, ProfileListChange{Added: []string{"Earn trust"}, Removed: []string{"Bias for action"}}, diff.Values,
+		"case and spacing changes are not reported")
+	assert.Equal(t, ProfileListChange{Added: []string{"ninja"}, Removed: []string{}}, diff.TabooPhrases)
+	assert.Empty(t, diff.StyleRules.Added)
+	assert.Empty(t, diff.StyleRules.Removed)
+
+	same := DiffProfileVersions(from, from)
+	assert.False(t, same.Changed)
+	assert.Nil(t, same.Tone)
+	assert.Nil(t, same.DomainContext)
+}
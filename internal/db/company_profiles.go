@@ -175,6 +175,10 @@ func (db *DB) CreateCompanyProfile(ctx context.Context, input *ProfileCreateInpu
 		}
 	}
 
+	if err := insertProfileVersion(ctx, tx, &p, input); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -230,7 +234,7 @@ func (db *DB) loadProfileRelations(ctx context.Context, p *CompanyProfile) error
 
 	// Load taboo phrases
 	rows, err = db.pool.Query(ctx,
-		`SELECT phrase FROM company_taboo_phrases WHERE profile_id = $1`,
+		`SELECT phrase, reason FROM company_taboo_phrases WHERE profile_id = $1`,
 		p.ID,
 	)
 	if err != nil {
@@ -239,10 +243,17 @@ func (db *DB) loadProfileRelations(ctx context.Context, p *CompanyProfile) error
 	defer rows.Close()
 	for rows.Next() {
 		var phrase string
-		if err := rows.Scan(&phrase); err != nil {
+		var reason *string
+		if err := rows.Scan(&phrase, &reason); err != nil {
 			return err
 		}
 		p.TabooPhrases = append(p.TabooPhrases, phrase)
+		if reason != nil && *reason != "" {
+			if p.TabooReasons == nil {
+				p.TabooReasons = make(map[string]string)
+			}
+			p.TabooReasons[phrase] = *reason
+		}
 	}
 
 	// Load values
@@ -112,6 +112,21 @@ func TestIntegration_CreateCompanyProfile(t *testing.T) {
 		if len(updated.StyleRules) != 3 {
 			t.Errorf("StyleRules not replaced, got %d", len(updated.StyleRules))
 		}
+
+		versions, err := db.ListCompanyProfileVersions(ctx, company.ID)
+		if err != nil {
+			t.Fatalf("ListCompanyProfileVersions failed: %v", err)
+		}
+		if len(versions) != 2 || versions[0].Version != updated.Version {
+			t.Fatalf("Expected both versions newest first, got %d", len(versions))
+		}
+		if versions[1].Tone == "warm and inclusive" || len(versions[1].Values) == 0 {
+			t.Errorf("Earlier version should keep its own tone and values, got %+v", versions[1])
+		}
+		diff := DiffProfileVersions(&versions[1], &versions[0])
+		if diff.Tone == nil || len(diff.Values.Added) != 1 {
+			t.Errorf("Expected tone and values to change, got %+v", diff)
+		}
 	})
 }
 
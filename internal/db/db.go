@@ -5,9 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jonathan/resume-customizer/internal/types"
 )
@@ -19,7 +21,13 @@ type DB struct {
 
 // Connect establishes a connection pool to the database
 func Connect(ctx context.Context, databaseURL string) (*DB, error) {
-	pool, err := pgxpool.New(ctx, databaseURL)
+	config, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+	}
+	config.AfterConnect = scanTimestampsInUTC
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -33,6 +41,17 @@ func Connect(ctx context.Context, databaseURL string) (*DB, error) {
 	return &DB{pool: pool}, nil
 }
 
+// scanTimestampsInUTC makes timestamptz columns scan into UTC times rather than the
+// server's local zone, so API responses are the same wherever the server runs
+func scanTimestampsInUTC(_ context.Context, conn *pgx.Conn) error {
+	conn.TypeMap().RegisterType(&pgtype.Type{
+		Name:  "timestamptz",
+		OID:   pgtype.TimestamptzOID,
+		Codec: &pgtype.TimestamptzCodec{ScanLocation: time.UTC},
+	})
+	return nil
+}
+
 // Close closes the connection pool
 func (db *DB) Close() {
 	if db.pool != nil {
@@ -65,6 +84,34 @@ func (db *DB) CreateRun(ctx context.Context, company, roleTitle, jobURL string)
 	return id, nil
 }
 
+// CreateQueuedRun creates a run record for a run still waiting for a scheduler slot.
+// StartRun moves it to 'running' once the slot is granted.
+func (db *DB) CreateQueuedRun(ctx context.Context, jobURL string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := db.pool.QueryRow(ctx,
+		`INSERT INTO pipeline_runs (company, role_title, job_url, status)
+		 VALUES ('', '', $1, 'queued')
+		 RETURNING id`,
+		jobURL,
+	).Scan(&id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create run: %w", err)
+	}
+	return id, nil
+}
+
+// StartRun marks a queued run as running
+func (db *DB) StartRun(ctx context.Context, runID uuid.UUID) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE pipeline_runs SET status = 'running' WHERE id = $1 AND status = 'queued'`,
+		runID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start run: %w", err)
+	}
+	return nil
+}
+
 // UpdateRunCompanyAndRole updates the company and role title for an existing run
 func (db *DB) UpdateRunCompanyAndRole(ctx context.Context, runID uuid.UUID, company, roleTitle string) error {
 	_, err := db.pool.Exec(ctx,
@@ -79,6 +126,101 @@ func (db *DB) UpdateRunCompanyAndRole(ctx context.Context, runID uuid.UUID, comp
 	return nil
 }
 
+// SetRunUserID associates a pipeline run with the user who owns it. Giving a run its
+// first owner publishes a run_created event.
+func (db *DB) SetRunUserID(ctx context.Context, runID, userID uuid.UUID) error {
+	var hadOwner bool
+	err := db.pool.QueryRow(ctx,
+		`WITH prev AS (SELECT user_id FROM pipeline_runs WHERE id = $2)
+		 UPDATE pipeline_runs SET user_id = $1 WHERE id = $2
+		 RETURNING (SELECT user_id IS NOT NULL FROM prev)`,
+		userID, runID,
+	).Scan(&hadOwner)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set run user: %w", err)
+	}
+	if !hadOwner {
+		db.publishRunEvent(ctx, RunEvent{Type: RunEventRunCreated, RunID: runID})
+	}
+	return nil
+}
+
+// SetRunPriority records the scheduling priority a run was submitted with
+func (db *DB) SetRunPriority(ctx context.Context, runID uuid.UUID, priority string) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE pipeline_runs SET priority = $1 WHERE id = $2`,
+		priority, runID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set run priority: %w", err)
+	}
+	return nil
+}
+
+// SetRunMaxPages records the pages a run's resume may fill, for steps executed one at a time
+func (db *DB) SetRunMaxPages(ctx context.Context, runID uuid.UUID, maxPages int) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE pipeline_runs SET max_pages = $1 WHERE id = $2`,
+		maxPages, runID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set run max pages: %w", err)
+	}
+	return nil
+}
+
+// SetRunSource records a run's type and the run whose artifacts it reuses, and gives
+// it that run's company, role, and job URL
+func (db *DB) SetRunSource(ctx context.Context, runID uuid.UUID, runType string, sourceRunID uuid.UUID) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE pipeline_runs r
+		 SET run_type = $2, source_run_id = src.id, company = src.company, role_title = src.role_title, job_url = src.job_url
+		 FROM pipeline_runs src
+		 WHERE r.id = $1 AND src.id = $3`,
+		runID, runType, sourceRunID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set run source: %w", err)
+	}
+	return nil
+}
+
+// GetLatestResumeRun returns the user's most recent run that rendered a resume, or nil
+// if there is none
+func (db *DB) GetLatestResumeRun(ctx context.Context, userID uuid.UUID) (*Run, error) {
+	var runID uuid.UUID
+	err := db.pool.QueryRow(ctx,
+		`SELECT r.id FROM pipeline_runs r
+		 WHERE r.user_id = $1
+		   AND EXISTS (SELECT 1 FROM artifacts a WHERE a.run_id = r.id AND a.step = $2)
+		 ORDER BY r.created_at DESC LIMIT 1`,
+		userID, StepResumeTex,
+	).Scan(&runID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest resume run: %w", err)
+	}
+	return db.GetRun(ctx, runID)
+}
+
+// CountUserRunsSince returns how many runs a user has started since the given time
+func (db *DB) CountUserRunsSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	var count int
+	err := db.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM pipeline_runs WHERE user_id = $1 AND created_at >= $2`,
+		userID, since,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count user runs: %w", err)
+	}
+	return count, nil
+}
+
 // CompleteRun marks a pipeline run as completed
 func (db *DB) CompleteRun(ctx context.Context, runID uuid.UUID, status string) error {
 	_, err := db.pool.Exec(ctx,
@@ -88,6 +230,7 @@ func (db *DB) CompleteRun(ctx context.Context, runID uuid.UUID, status string) e
 	if err != nil {
 		return fmt.Errorf("failed to complete run: %w", err)
 	}
+	db.publishRunEvent(ctx, RunEvent{Type: RunEventRunCompleted, RunID: runID, Status: status})
 	return nil
 }
 
@@ -98,37 +241,36 @@ func (db *DB) SaveArtifact(ctx context.Context, runID uuid.UUID, step, category
 		return fmt.Errorf("failed to marshal artifact: %w", err)
 	}
 
-	_, err = db.pool.Exec(ctx,
-		`INSERT INTO artifacts (run_id, step, category, content)
-		 VALUES ($1, $2, $3, $4)
-		 ON CONFLICT (run_id, step) DO UPDATE SET category = $3, content = $4, created_at = NOW()`,
-		runID, step, category, jsonBytes,
-	)
-	if err != nil {
+	if err := db.saveArtifactBlob(ctx, runID, step, category, ContentFormatJSON, jsonBytes); err != nil {
 		return fmt.Errorf("failed to save artifact %s: %w", step, err)
 	}
+	db.publishRunEvent(ctx, RunEvent{Type: RunEventArtifactSaved, RunID: runID, Step: step, Category: category})
 	return nil
 }
 
 // SaveTextArtifact stores a text artifact (like .tex or .txt files) for a pipeline run
 func (db *DB) SaveTextArtifact(ctx context.Context, runID uuid.UUID, step, category, text string) error {
-	_, err := db.pool.Exec(ctx,
-		`INSERT INTO artifacts (run_id, step, category, text_content)
-		 VALUES ($1, $2, $3, $4)
-		 ON CONFLICT (run_id, step) DO UPDATE SET category = $3, text_content = $4, created_at = NOW()`,
-		runID, step, category, text,
-	)
-	if err != nil {
+	if err := db.saveArtifactBlob(ctx, runID, step, category, ContentFormatText, []byte(text)); err != nil {
 		return fmt.Errorf("failed to save text artifact %s: %w", step, err)
 	}
+	db.publishRunEvent(ctx, RunEvent{Type: RunEventArtifactSaved, RunID: runID, Step: step, Category: category})
 	return nil
 }
 
-// GetArtifact retrieves a JSON artifact by run ID and step
+// SaveBinaryArtifact stores a binary artifact (like a compiled PDF) for a pipeline run
+func (db *DB) SaveBinaryArtifact(ctx context.Context, runID uuid.UUID, step, category string, data []byte) error {
+	if err := db.saveArtifactBlob(ctx, runID, step, category, ContentFormatBinary, data); err != nil {
+		return fmt.Errorf("failed to save binary artifact %s: %w", step, err)
+	}
+	db.publishRunEvent(ctx, RunEvent{Type: RunEventArtifactSaved, RunID: runID, Step: step, Category: category})
+	return nil
+}
+
+// GetArtifact retrieves the latest version of a JSON artifact by run ID and step
 func (db *DB) GetArtifact(ctx context.Context, runID uuid.UUID, step string) ([]byte, error) {
 	var content []byte
 	err := db.pool.QueryRow(ctx,
-		`SELECT content FROM artifacts WHERE run_id = $1 AND step = $2`,
+		`SELECT `+artifactJSON+` FROM `+latestArtifactsWithBlobs+` WHERE a.run_id = $1 AND a.step = $2`,
 		runID, step,
 	).Scan(&content)
 	if err != nil {
@@ -140,11 +282,11 @@ func (db *DB) GetArtifact(ctx context.Context, runID uuid.UUID, step string) ([]
 	return content, nil
 }
 
-// GetTextArtifact retrieves a text artifact by run ID and step
+// GetTextArtifact retrieves the latest version of a text artifact by run ID and step
 func (db *DB) GetTextArtifact(ctx context.Context, runID uuid.UUID, step string) (string, error) {
 	var text string
 	err := db.pool.QueryRow(ctx,
-		`SELECT text_content FROM artifacts WHERE run_id = $1 AND step = $2`,
+		`SELECT `+artifactText+` FROM `+latestArtifactsWithBlobs+` WHERE a.run_id = $1 AND a.step = $2`,
 		runID, step,
 	).Scan(&text)
 	if err != nil {
@@ -156,14 +298,56 @@ func (db *DB) GetTextArtifact(ctx context.Context, runID uuid.UUID, step string)
 	return text, nil
 }
 
+// GetBinaryArtifact retrieves the latest version of a binary artifact by run ID and step,
+// or nil if none was saved
+func (db *DB) GetBinaryArtifact(ctx context.Context, runID uuid.UUID, step string) ([]byte, error) {
+	var data []byte
+	err := db.pool.QueryRow(ctx,
+		`SELECT `+artifactBinary+` FROM `+latestArtifactsWithBlobs+` WHERE a.run_id = $1 AND a.step = $2`,
+		runID, step,
+	).Scan(&data)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get binary artifact %s: %w", step, err)
+	}
+	return data, nil
+}
+
+// CopyRunArtifacts copies the latest version of each of the given artifacts from one
+// run to another, where it becomes version 1. The copies share their blobs with the
+// originals. Returns the artifacts that were copied; the source may lack some.
+func (db *DB) CopyRunArtifacts(ctx context.Context, fromRunID, toRunID uuid.UUID, steps []string) ([]string, error) {
+	rows, err := db.pool.Query(ctx,
+		`INSERT INTO artifacts (run_id, step, category, content, text_content, binary_content, blob_hash, version)
+		 SELECT $2, step, category, content, text_content, binary_content, blob_hash, 1
+		   FROM latest_artifacts
+		  WHERE run_id = $1 AND step = ANY($3)
+		 ON CONFLICT (run_id, step, version) DO NOTHING
+		 RETURNING step`,
+		fromRunID, toRunID, steps,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy artifacts: %w", err)
+	}
+	copied, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy artifacts: %w", err)
+	}
+	return copied, nil
+}
+
 // GetRun retrieves a pipeline run by ID
 func (db *DB) GetRun(ctx context.Context, runID uuid.UUID) (*Run, error) {
 	var run Run
 	err := db.pool.QueryRow(ctx,
-		`SELECT id, company, role_title, job_url, status, created_at, completed_at
+		`SELECT id, company, role_title, job_url, status, user_id, priority, created_at, completed_at,
+		        deadline_at, follow_up_at, run_type, source_run_id, max_pages
 		 FROM pipeline_runs WHERE id = $1`,
 		runID,
-	).Scan(&run.ID, &run.Company, &run.RoleTitle, &run.JobURL, &run.Status, &run.CreatedAt, &run.CompletedAt)
+	).Scan(&run.ID, &run.Company, &run.RoleTitle, &run.JobURL, &run.Status, &run.UserID, &run.Priority, &run.CreatedAt, &run.CompletedAt,
+		&run.DeadlineAt, &run.FollowUpAt, &run.RunType, &run.SourceRunID, &run.MaxPages)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -201,6 +385,7 @@ type Artifact struct {
 	ID          uuid.UUID `json:"id"`
 	RunID       uuid.UUID `json:"run_id"`
 	Step        string    `json:"step"`
+	Version     int       `json:"version"`
 	Category    string    `json:"category"`
 	Content     any       `json:"content,omitempty"`
 	TextContent string    `json:"text_content,omitempty"`
@@ -208,16 +393,21 @@ type Artifact struct {
 
 // GetArtifactByID retrieves an artifact by its UUID
 func (db *DB) GetArtifactByID(ctx context.Context, artifactID uuid.UUID) (*Artifact, error) {
+	return db.getArtifact(ctx, `a.id = $1`, artifactID)
+}
+
+// getArtifact retrieves the artifact matching where, or nil if there is none
+func (db *DB) getArtifact(ctx context.Context, where string, args ...any) (*Artifact, error) {
 	var artifact Artifact
 	var contentBytes []byte
 	var textContent *string
 	var category *string
 
 	err := db.pool.QueryRow(ctx,
-		`SELECT id, run_id, step, category, content, text_content
-		 FROM artifacts WHERE id = $1`,
-		artifactID,
-	).Scan(&artifact.ID, &artifact.RunID, &artifact.Step, &category, &contentBytes, &textContent)
+		`SELECT a.id, a.run_id, a.step, a.version, a.category, `+artifactJSON+`, `+artifactText+`
+		 FROM `+artifactsWithBlobs+` WHERE `+where,
+		args...,
+	).Scan(&artifact.ID, &artifact.RunID, &artifact.Step, &artifact.Version, &category, &contentBytes, &textContent)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -247,6 +437,7 @@ type RunFilters struct {
 	Status  string
 	UserID  *uuid.UUID // Optional user ID filter
 	Limit   int
+	Page    Page // Sort over RunSortKeys; newest first when unset
 }
 
 // ListRunsFiltered retrieves runs with optional filters
@@ -255,7 +446,8 @@ func (db *DB) ListRunsFiltered(ctx context.Context, filters RunFilters) ([]Run,
 		filters.Limit = 50
 	}
 
-	query := `SELECT id, company, role_title, job_url, status, user_id, created_at, completed_at
+	query := `SELECT id, company, role_title, job_url, status, user_id, priority, created_at, completed_at,
+		deadline_at, follow_up_at, run_type, source_run_id
 		FROM pipeline_runs WHERE 1=1`
 	args := []any{}
 	argNum := 1
@@ -276,7 +468,21 @@ func (db *DB) ListRunsFiltered(ctx context.Context, filters RunFilters) ([]Run,
 		argNum++
 	}
 
-	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d", argNum)
+	orderBy := " ORDER BY created_at DESC"
+	if filters.Page.Sort != "" {
+		cond, pageOrder, pageArgs, err := pageClause(filters.Page, RunSortKeys, "id", argNum)
+		if err != nil {
+			return nil, err
+		}
+		if cond != "" {
+			query += " AND " + cond
+			args = append(args, pageArgs...)
+			argNum += len(pageArgs)
+		}
+		orderBy = pageOrder
+	}
+
+	query += orderBy + fmt.Sprintf(" LIMIT $%d", argNum)
 	args = append(args, filters.Limit)
 
 	rows, err := db.pool.Query(ctx, query, args...)
@@ -288,7 +494,8 @@ func (db *DB) ListRunsFiltered(ctx context.Context, filters RunFilters) ([]Run,
 	var runs []Run
 	for rows.Next() {
 		var run Run
-		if err := rows.Scan(&run.ID, &run.Company, &run.RoleTitle, &run.JobURL, &run.Status, &run.UserID, &run.CreatedAt, &run.CompletedAt); err != nil {
+		if err := rows.Scan(&run.ID, &run.Company, &run.RoleTitle, &run.JobURL, &run.Status, &run.UserID, &run.Priority, &run.CreatedAt, &run.CompletedAt,
+			&run.DeadlineAt, &run.FollowUpAt, &run.RunType, &run.SourceRunID); err != nil {
 			return nil, fmt.Errorf("failed to scan run: %w", err)
 		}
 		runs = append(runs, run)
@@ -331,9 +538,9 @@ func (db *DB) CreateUser(ctx context.Context, name, email, phone string) (uuid.U
 func (db *DB) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
 	var u User
 	err := db.pool.QueryRow(ctx,
-		`SELECT id, name, email, phone, password_hash, password_set, created_at, updated_at FROM users WHERE id = $1`,
+		`SELECT id, name, email, phone, password_hash, password_set, redact_pii, timezone, created_at, updated_at, email_verified_at FROM users WHERE id = $1`,
 		id,
-	).Scan(&u.ID, &u.Name, &u.Email, &u.Phone, &u.PasswordHash, &u.PasswordSet, &u.CreatedAt, &u.UpdatedAt)
+	).Scan(&u.ID, &u.Name, &u.Email, &u.Phone, &u.PasswordHash, &u.PasswordSet, &u.RedactPII, &u.Timezone, &u.CreatedAt, &u.UpdatedAt, &u.EmailVerifiedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -355,6 +562,30 @@ func (db *DB) UpdateUser(ctx context.Context, u *User) error {
 	return nil
 }
 
+// SetUserRedactPII sets whether the user's contact details are masked in prompts sent to external LLMs
+func (db *DB) SetUserRedactPII(ctx context.Context, userID uuid.UUID, enabled bool) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE users SET redact_pii = $1, updated_at = NOW() WHERE id = $2`,
+		enabled, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update redaction setting: %w", err)
+	}
+	return nil
+}
+
+// SetUserTimezone sets the IANA time zone the user's emails and reports are written in
+func (db *DB) SetUserTimezone(ctx context.Context, userID uuid.UUID, timezone string) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE users SET timezone = $1, updated_at = NOW() WHERE id = $2`,
+		timezone, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update timezone: %w", err)
+	}
+	return nil
+}
+
 // DeleteUser deletes a user (cascades to jobs/education)
 func (db *DB) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	cmd, err := db.pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
@@ -371,9 +602,9 @@ func (db *DB) DeleteUser(ctx context.Context, id uuid.UUID) error {
 func (db *DB) GetUserByEmail(ctx context.Context, email string) (*User, error) {
 	var u User
 	err := db.pool.QueryRow(ctx,
-		`SELECT id, name, email, phone, password_hash, password_set, created_at, updated_at FROM users WHERE email = $1`,
+		`SELECT id, name, email, phone, password_hash, password_set, redact_pii, timezone, created_at, updated_at, email_verified_at FROM users WHERE email = $1`,
 		email,
-	).Scan(&u.ID, &u.Name, &u.Email, &u.Phone, &u.PasswordHash, &u.PasswordSet, &u.CreatedAt, &u.UpdatedAt)
+	).Scan(&u.ID, &u.Name, &u.Email, &u.Phone, &u.PasswordHash, &u.PasswordSet, &u.RedactPII, &u.Timezone, &u.CreatedAt, &u.UpdatedAt, &u.EmailVerifiedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -414,12 +645,15 @@ func (db *DB) CheckEmailExists(ctx context.Context, email string) (bool, error)
 
 // CreateJob creates a new job entry
 func (db *DB) CreateJob(ctx context.Context, job *Job) (uuid.UUID, error) {
+	job.setNormalizedRole()
 	var id uuid.UUID
 	err := db.pool.QueryRow(ctx,
-		`INSERT INTO jobs (user_id, company, role_title, location, employment_type, start_date, end_date)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`INSERT INTO jobs (user_id, company, role_title, location, employment_type, start_date, end_date,
+		                   role_title_normalized, role_family, role_level)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		 RETURNING id`,
 		job.UserID, job.Company, job.RoleTitle, job.Location, job.EmploymentType, job.StartDate, job.EndDate,
+		nullIfEmpty(job.RoleTitleNormalized), nullIfEmpty(job.RoleFamily), nullIfEmpty(job.RoleLevel),
 	).Scan(&id)
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("failed to create job: %w", err)
@@ -430,7 +664,8 @@ func (db *DB) CreateJob(ctx context.Context, job *Job) (uuid.UUID, error) {
 // ListJobs retrieves all jobs for a user
 func (db *DB) ListJobs(ctx context.Context, userID uuid.UUID) ([]Job, error) {
 	rows, err := db.pool.Query(ctx,
-		`SELECT id, user_id, company, role_title, location, employment_type, start_date, end_date, created_at
+		`SELECT id, user_id, company, role_title, location, employment_type, start_date, end_date, created_at,
+		        COALESCE(role_title_normalized, ''), COALESCE(role_family, ''), COALESCE(role_level, '')
 		 FROM jobs WHERE user_id = $1 ORDER BY start_date DESC`,
 		userID,
 	)
@@ -442,7 +677,8 @@ func (db *DB) ListJobs(ctx context.Context, userID uuid.UUID) ([]Job, error) {
 	var jobs []Job
 	for rows.Next() {
 		var j Job
-		if err := rows.Scan(&j.ID, &j.UserID, &j.Company, &j.RoleTitle, &j.Location, &j.EmploymentType, &j.StartDate, &j.EndDate, &j.CreatedAt); err != nil {
+		if err := rows.Scan(&j.ID, &j.UserID, &j.Company, &j.RoleTitle, &j.Location, &j.EmploymentType, &j.StartDate, &j.EndDate, &j.CreatedAt,
+			&j.RoleTitleNormalized, &j.RoleFamily, &j.RoleLevel); err != nil {
 			return nil, fmt.Errorf("failed to scan job: %w", err)
 		}
 		jobs = append(jobs, j)
@@ -452,10 +688,13 @@ func (db *DB) ListJobs(ctx context.Context, userID uuid.UUID) ([]Job, error) {
 
 // UpdateJob updates a job entry
 func (db *DB) UpdateJob(ctx context.Context, job *Job) error {
+	job.setNormalizedRole()
 	_, err := db.pool.Exec(ctx,
-		`UPDATE jobs SET company = $1, role_title = $2, location = $3, employment_type = $4, start_date = $5, end_date = $6
+		`UPDATE jobs SET company = $1, role_title = $2, location = $3, employment_type = $4, start_date = $5, end_date = $6,
+		                 role_title_normalized = $8, role_family = $9, role_level = $10
 		 WHERE id = $7`,
 		job.Company, job.RoleTitle, job.Location, job.EmploymentType, job.StartDate, job.EndDate, job.ID,
+		nullIfEmpty(job.RoleTitleNormalized), nullIfEmpty(job.RoleFamily), nullIfEmpty(job.RoleLevel),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update job: %w", err)
@@ -613,10 +852,12 @@ func (db *DB) DeleteEducation(ctx context.Context, id uuid.UUID) error {
 type ArtifactSummary struct {
 	ID        uuid.UUID `json:"id"`
 	Step      string    `json:"step"`
+	Version   int       `json:"version"`
 	Category  string    `json:"category"`
 	CreatedAt string    `json:"created_at"`
 	HasJSON   bool      `json:"has_json"`
 	HasText   bool      `json:"has_text"`
+	HasBinary bool      `json:"has_binary"`
 }
 
 // ArtifactFilters holds optional filters for listing artifacts
@@ -626,30 +867,31 @@ type ArtifactFilters struct {
 	Category string
 }
 
-// ListArtifacts retrieves artifacts with optional filters
+// ListArtifacts retrieves the latest version of artifacts with optional filters
 func (db *DB) ListArtifacts(ctx context.Context, filters ArtifactFilters) ([]ArtifactSummary, error) {
-	query := `SELECT id, step, COALESCE(category, ''), created_at, 
-		      content IS NOT NULL as has_json, text_content IS NOT NULL as has_text
-		FROM artifacts WHERE 1=1`
+	query := `SELECT a.id, a.step, a.version, COALESCE(a.category, ''), a.created_at,
+		      ` + artifactJSON + ` IS NOT NULL as has_json, ` + artifactText + ` IS NOT NULL as has_text,
+		      ` + artifactBinary + ` IS NOT NULL as has_binary
+		FROM ` + latestArtifactsWithBlobs + ` WHERE 1=1`
 	args := []any{}
 	argNum := 1
 
 	if filters.RunID != uuid.Nil {
-		query += fmt.Sprintf(" AND run_id = $%d", argNum)
+		query += fmt.Sprintf(" AND a.run_id = $%d", argNum)
 		args = append(args, filters.RunID)
 		argNum++
 	}
 	if filters.Step != "" {
-		query += fmt.Sprintf(" AND step = $%d", argNum)
+		query += fmt.Sprintf(" AND a.step = $%d", argNum)
 		args = append(args, filters.Step)
 		argNum++
 	}
 	if filters.Category != "" {
-		query += fmt.Sprintf(" AND category = $%d", argNum)
+		query += fmt.Sprintf(" AND a.category = $%d", argNum)
 		args = append(args, filters.Category)
 	}
 
-	query += " ORDER BY created_at ASC"
+	query += " ORDER BY a.created_at ASC"
 
 	rows, err := db.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -661,7 +903,7 @@ func (db *DB) ListArtifacts(ctx context.Context, filters ArtifactFilters) ([]Art
 	for rows.Next() {
 		var a ArtifactSummary
 		var createdAt any
-		if err := rows.Scan(&a.ID, &a.Step, &a.Category, &createdAt, &a.HasJSON, &a.HasText); err != nil {
+		if err := rows.Scan(&a.ID, &a.Step, &a.Version, &a.Category, &createdAt, &a.HasJSON, &a.HasText, &a.HasBinary); err != nil {
 			return nil, fmt.Errorf("failed to scan artifact: %w", err)
 		}
 		if t, ok := createdAt.(interface{ String() string }); ok {
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// deadLetterColumns lists the columns scanned by scanDeadLetter, in order
+const deadLetterColumns = `id, source, ref_key, user_id, run_id, payload, error_message, attempts,
+	status, created_at, last_failed_at, resolved_at`
+
+// scanDeadLetter scans a row selected with deadLetterColumns
+func scanDeadLetter(row pgx.Row) (*DeadLetter, error) {
+	var d DeadLetter
+	err := row.Scan(&d.ID, &d.Source, &d.RefKey, &d.UserID, &d.RunID, &d.Payload, &d.ErrorMessage,
+		&d.Attempts, &d.Status, &d.CreatedAt, &d.LastFailedAt, &d.ResolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// RecordDeadLetter stores failed background work. A failure already on record for
+// the same source and ref key gets the new payload and error, one more attempt, and
+// becomes pending again.
+func (db *DB) RecordDeadLetter(ctx context.Context, input *DeadLetterInput) (*DeadLetter, error) {
+	payloadJSON, err := json.Marshal(input.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal dead letter payload: %w", err)
+	}
+	d, err := scanDeadLetter(db.pool.QueryRow(ctx,
+		`INSERT INTO dead_letters (source, ref_key, user_id, run_id, payload, error_message)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (source, ref_key) DO UPDATE
+		 SET payload = EXCLUDED.payload, error_message = EXCLUDED.error_message,
+		     attempts = dead_letters.attempts + 1, status = 'pending',
+		     last_failed_at = NOW(), resolved_at = NULL
+		 RETURNING `+deadLetterColumns,
+		input.Source, input.RefKey, input.UserID, input.RunID, payloadJSON, input.ErrorMessage,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record dead letter: %w", err)
+	}
+	return d, nil
+}
+
+// GetDeadLetter returns a dead letter, or nil if it does not exist
+func (db *DB) GetDeadLetter(ctx context.Context, id uuid.UUID) (*DeadLetter, error) {
+	d, err := scanDeadLetter(db.pool.QueryRow(ctx,
+		`SELECT `+deadLetterColumns+` FROM dead_letters WHERE id = $1`, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get dead letter: %w", err)
+	}
+	return d, nil
+}
+
+// ListDeadLetters returns dead letters matching filters, oldest failure first
+func (db *DB) ListDeadLetters(ctx context.Context, filters DeadLetterFilters) ([]DeadLetter, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+deadLetterColumns+` FROM dead_letters
+		 WHERE ($1 = '' OR source = $1) AND ($2 = '' OR status = $2)
+		 ORDER BY last_failed_at
+		 LIMIT $3`,
+		filters.Source, filters.Status, filters.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := []DeadLetter{}
+	for rows.Next() {
+		d, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, *d)
+	}
+	return letters, rows.Err()
+}
+
+// CountPendingDeadLetters returns how many dead letters await an operator, by source
+func (db *DB) CountPendingDeadLetters(ctx context.Context) (map[string]int, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT source, COUNT(*) FROM dead_letters WHERE status = 'pending' GROUP BY source`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(DeadLetterSources))
+	for _, source := range DeadLetterSources {
+		counts[source] = 0
+	}
+	for rows.Next() {
+		var source string
+		var n int
+		if err := rows.Scan(&source, &n); err != nil {
+			return nil, err
+		}
+		counts[source] = n
+	}
+	return counts, rows.Err()
+}
+
+// ResolveDeadLetter marks a pending dead letter retried or discarded. It returns nil
+// if the dead letter does not exist or is no longer pending.
+func (db *DB) ResolveDeadLetter(ctx context.Context, id uuid.UUID, status string) (*DeadLetter, error) {
+	if status != DeadLetterRetried && status != DeadLetterDiscarded {
+		return nil, fmt.Errorf("invalid dead letter resolution %q", status)
+	}
+	d, err := scanDeadLetter(db.pool.QueryRow(ctx,
+		`UPDATE dead_letters SET status = $2, resolved_at = NOW()
+		 WHERE id = $1 AND status = 'pending'
+		 RETURNING `+deadLetterColumns,
+		id, status,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to resolve dead letter: %w", err)
+	}
+	return d, nil
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// NormalizePolicyDomain reduces a domain or URL to the lowercase host a policy matches on
+func NormalizePolicyDomain(domain string) (string, error) {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if strings.Contains(domain, "://") {
+		parsed, err := url.Parse(domain)
+		if err != nil {
+			return "", fmt.Errorf("invalid domain %q: %w", domain, err)
+		}
+		domain = parsed.Hostname()
+	}
+	domain = strings.TrimPrefix(strings.TrimSuffix(domain, "."), "www.")
+	if !strings.Contains(domain, ".") || strings.ContainsAny(domain, "/ :?#") {
+		return "", fmt.Errorf("invalid domain %q", domain)
+	}
+	return domain, nil
+}
+
+// UpsertDomainPolicy creates a domain policy, replacing any existing policy
+// for the same domain and owner
+func (db *DB) UpsertDomainPolicy(ctx context.Context, input *DomainPolicyInput) (*DomainPolicy, error) {
+	if input.Action != DomainPolicyBlock && input.Action != DomainPolicyAllow {
+		return nil, fmt.Errorf("invalid domain policy action %q", input.Action)
+	}
+	domain, err := NormalizePolicyDomain(input.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var p DomainPolicy
+	err = db.pool.QueryRow(ctx,
+		`INSERT INTO domain_policies (user_id, domain, action, reason)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (COALESCE(user_id, '00000000-0000-0000-0000-000000000000'::uuid), domain)
+		 DO UPDATE SET action = EXCLUDED.action, reason = EXCLUDED.reason
+		 RETURNING id, user_id, domain, action, reason, created_at`,
+		input.UserID, domain, input.Action, nullIfEmpty(input.Reason),
+	).Scan(&p.ID, &p.UserID, &p.Domain, &p.Action, &p.Reason, &p.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save domain policy: %w", err)
+	}
+	return &p, nil
+}
+
+// ListDomainPolicies returns the global policies plus, when userID is set, that user's policies
+func (db *DB) ListDomainPolicies(ctx context.Context, userID *uuid.UUID) ([]DomainPolicy, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, user_id, domain, action, reason, created_at
+		 FROM domain_policies
+		 WHERE user_id IS NULL OR user_id = $1
+		 ORDER BY domain, user_id NULLS FIRST`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list domain policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []DomainPolicy
+	for rows.Next() {
+		var p DomainPolicy
+		if err := rows.Scan(&p.ID, &p.UserID, &p.Domain, &p.Action, &p.Reason, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan domain policy: %w", err)
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+// DeleteDomainPolicy deletes a policy owned by userID, or a global policy when userID is nil
+func (db *DB) DeleteDomainPolicy(ctx context.Context, id uuid.UUID, userID *uuid.UUID) error {
+	cmd, err := db.pool.Exec(ctx,
+		`DELETE FROM domain_policies WHERE id = $1 AND user_id IS NOT DISTINCT FROM $2`,
+		id, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete domain policy: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("domain policy not found: %s", id)
+	}
+	return nil
+}
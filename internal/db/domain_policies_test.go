@@ -0,0 +1,36 @@
+package db
+
+import "testing"
+
+func TestNormalizePolicyDomain(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "glassdoor.com", want: "glassdoor.com"},
+		{input: "  WWW.Glassdoor.com ", want: "glassdoor.com"},
+		{input: "https://careers.acme.com/jobs?x=1", want: "careers.acme.com"},
+		{input: "acme.com.", want: "acme.com"},
+		{input: "localhost", wantErr: true},
+		{input: "acme.com/about", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizePolicyDomain(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NormalizePolicyDomain(%q) expected error, got %q", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NormalizePolicyDomain(%q) unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizePolicyDomain(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
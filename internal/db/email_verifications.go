@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// -----------------------------------------------------------------------------
+// Email Verification Methods
+// -----------------------------------------------------------------------------
+
+const emailVerificationColumns = `id, user_id, email, token_hash, expires_at, used_at, created_at`
+
+// scanEmailVerification scans an email_verifications row selected with emailVerificationColumns
+func scanEmailVerification(row pgx.Row) (*EmailVerification, error) {
+	var v EmailVerification
+	if err := row.Scan(&v.ID, &v.UserID, &v.Email, &v.TokenHash, &v.ExpiresAt, &v.UsedAt, &v.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// CreateEmailVerification stores a verification link sent to email and marks the user
+// unverified until a link is followed
+func (db *DB) CreateEmailVerification(ctx context.Context, userID uuid.UUID, email, tokenHash string, expiresAt time.Time) (*EmailVerification, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET email_verified_at = NULL WHERE id = $1`, userID); err != nil {
+		return nil, fmt.Errorf("failed to create email verification: %w", err)
+	}
+	v, err := scanEmailVerification(tx.QueryRow(ctx,
+		`INSERT INTO email_verifications (user_id, email, token_hash, expires_at)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+emailVerificationColumns,
+		userID, email, tokenHash, expiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email verification: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit email verification: %w", err)
+	}
+	return v, nil
+}
+
+// VerifyEmail spends the verification link with hash tokenHash and marks its user
+// verified. Returns nil for unknown, expired, and already used links, and for links sent
+// to an address the user has since changed.
+func (db *DB) VerifyEmail(ctx context.Context, tokenHash string) (*EmailVerification, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	v, err := scanEmailVerification(tx.QueryRow(ctx,
+		`UPDATE email_verifications SET used_at = NOW()
+		 WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		 RETURNING `+emailVerificationColumns,
+		tokenHash,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE users SET email_verified_at = NOW(), updated_at = NOW() WHERE id = $1 AND email = $2`,
+		v.UserID, v.Email,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify email: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit email verification: %w", err)
+	}
+	return v, nil
+}
+
+// LatestEmailVerification returns the verification link most recently sent to a user,
+// or nil if none was
+func (db *DB) LatestEmailVerification(ctx context.Context, userID uuid.UUID) (*EmailVerification, error) {
+	v, err := scanEmailVerification(db.pool.QueryRow(ctx,
+		`SELECT `+emailVerificationColumns+` FROM email_verifications
+		 WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1`,
+		userID,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get email verification: %w", err)
+	}
+	return v, nil
+}
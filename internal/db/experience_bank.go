@@ -106,6 +106,57 @@ func (db *DB) ListSkills(ctx context.Context, category string) ([]Skill, error)
 	return skills, nil
 }
 
+// UpsertUserSkill records the user's self-assessment of a skill, adding the skill to
+// the catalog if needed. Nil proficiency or lastUsed clears that field.
+func (db *DB) UpsertUserSkill(ctx context.Context, userID uuid.UUID, skillName string, proficiency *int, lastUsed *Date) (*UserSkill, error) {
+	skill, err := db.FindOrCreateSkill(ctx, skillName)
+	if err != nil {
+		return nil, err
+	}
+
+	us := UserSkill{Skill: skill.Name}
+	err = db.pool.QueryRow(ctx,
+		`INSERT INTO user_skills (user_id, skill_id, proficiency, last_used)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (user_id, skill_id) DO UPDATE SET
+		     proficiency = EXCLUDED.proficiency,
+		     last_used = EXCLUDED.last_used,
+		     updated_at = NOW()
+		 RETURNING user_id, skill_id, proficiency, last_used, updated_at`,
+		userID, skill.ID, proficiency, lastUsed,
+	).Scan(&us.UserID, &us.SkillID, &us.Proficiency, &us.LastUsed, &us.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save user skill: %w", err)
+	}
+	return &us, nil
+}
+
+// ListUserSkills returns the user's skill self-assessments, ordered by skill name
+func (db *DB) ListUserSkills(ctx context.Context, userID uuid.UUID) ([]UserSkill, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT us.user_id, us.skill_id, s.name, us.proficiency, us.last_used, us.updated_at
+		 FROM user_skills us
+		 JOIN skills s ON s.id = us.skill_id
+		 WHERE us.user_id = $1
+		 ORDER BY s.name_normalized`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user skills: %w", err)
+	}
+	defer rows.Close()
+
+	skills := []UserSkill{}
+	for rows.Next() {
+		var us UserSkill
+		if err := rows.Scan(&us.UserID, &us.SkillID, &us.Skill, &us.Proficiency, &us.LastUsed, &us.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user skill: %w", err)
+		}
+		skills = append(skills, us)
+	}
+	return skills, rows.Err()
+}
+
 // -----------------------------------------------------------------------------
 // Story Methods
 // -----------------------------------------------------------------------------
@@ -123,9 +174,23 @@ func (db *DB) CreateStory(ctx context.Context, input *StoryCreateInput) (*Story,
 		}
 	}()
 
+	story, err := createStory(ctx, tx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return story, nil
+}
+
+// createStory upserts a story and replaces its bullets within tx
+func createStory(ctx context.Context, tx pgx.Tx, input *StoryCreateInput) (*Story, error) {
 	// Insert story
 	var story Story
-	err = tx.QueryRow(ctx,
+	err := tx.QueryRow(ctx,
 		`INSERT INTO stories (story_id, user_id, job_id, title, description)
 		 VALUES ($1, $2, $3, $4, $5)
 		 ON CONFLICT (story_id) DO UPDATE SET
@@ -215,10 +280,6 @@ func (db *DB) CreateStory(ctx context.Context, input *StoryCreateInput) (*Story,
 		story.Bullets = append(story.Bullets, bullet)
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
 	return &story, nil
 }
 
@@ -269,16 +330,33 @@ func (db *DB) GetStoryByStoryID(ctx context.Context, storyID string) (*Story, er
 	return &story, nil
 }
 
-// ListStoriesByUser retrieves all stories for a user
-func (db *DB) ListStoriesByUser(ctx context.Context, userID uuid.UUID) ([]Story, error) {
-	rows, err := db.pool.Query(ctx,
-		`SELECT s.id, s.story_id, s.user_id, s.job_id, s.title, s.description, 
+// ListStoriesByUser retrieves a user's stories: all of them, newest first, for a zero
+// limit and Page, else up to limit ordered over StorySortKeys
+func (db *DB) ListStoriesByUser(ctx context.Context, userID uuid.UUID, limit int, page Page) ([]Story, error) {
+	query := `SELECT s.id, s.story_id, s.user_id, s.job_id, s.title, s.description,
 		        s.created_at, s.updated_at
 		 FROM stories s
-		 WHERE s.user_id = $1
-		 ORDER BY s.created_at DESC`,
-		userID,
-	)
+		 WHERE s.user_id = $1`
+	args := []any{userID}
+	orderBy := " ORDER BY s.created_at DESC"
+	if page.Sort != "" {
+		cond, pageOrder, pageArgs, err := pageClause(page, StorySortKeys, "s.id", len(args)+1)
+		if err != nil {
+			return nil, err
+		}
+		if cond != "" {
+			query += " AND " + cond
+			args = append(args, pageArgs...)
+		}
+		orderBy = pageOrder
+	}
+	query += orderBy
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := db.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list stories: %w", err)
 	}
@@ -537,18 +615,36 @@ func (db *DB) GetSkillUsageCount(ctx context.Context) (map[string]int, error) {
 	return usage, nil
 }
 
-// ListSkillsByUserID retrieves all unique skills used by bullets in stories belonging to a user
-func (db *DB) ListSkillsByUserID(ctx context.Context, userID uuid.UUID) ([]Skill, error) {
-	rows, err := db.pool.Query(ctx,
-		`SELECT DISTINCT s.id, s.name, s.name_normalized, s.category, s.created_at
+// ListSkillsByUserID retrieves the unique skills used by bullets in stories belonging to a
+// user: all of them by name for a zero limit and Page, else up to limit ordered over
+// SkillSortKeys
+func (db *DB) ListSkillsByUserID(ctx context.Context, userID uuid.UUID, limit int, page Page) ([]Skill, error) {
+	query := `SELECT DISTINCT s.id, s.name, s.name_normalized, s.category, s.created_at
 		 FROM skills s
 		 JOIN bullet_skills bs ON bs.skill_id = s.id
 		 JOIN bullets b ON b.id = bs.bullet_id
 		 JOIN stories st ON st.id = b.story_id
-		 WHERE st.user_id = $1
-		 ORDER BY s.name_normalized`,
-		userID,
-	)
+		 WHERE st.user_id = $1`
+	args := []any{userID}
+	orderBy := " ORDER BY s.name_normalized"
+	if page.Sort != "" {
+		cond, pageOrder, pageArgs, err := pageClause(page, SkillSortKeys, "s.id", len(args)+1)
+		if err != nil {
+			return nil, err
+		}
+		if cond != "" {
+			query += " AND " + cond
+			args = append(args, pageArgs...)
+		}
+		orderBy = pageOrder
+	}
+	query += orderBy
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := db.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list skills by user: %w", err)
 	}
@@ -772,15 +868,19 @@ func (db *DB) findOrCreateJobForStory(ctx context.Context, userID uuid.UUID, com
 	}
 
 	// Create new job
+	normalized := normalizeRole(role, company)
 	err = db.pool.QueryRow(ctx,
-		`INSERT INTO jobs (user_id, company, role_title, start_date, end_date)
-		 VALUES ($1, $2, $3, $4, $5)
+		`INSERT INTO jobs (user_id, company, role_title, start_date, end_date,
+		                   role_title_normalized, role_family, role_level)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		 RETURNING id, user_id, company, role_title, start_date, end_date, created_at`,
 		userID, company, role, start, end,
+		nullIfEmpty(normalized.Title), nullIfEmpty(normalized.Family), nullIfEmpty(normalized.Level),
 	).Scan(&job.ID, &job.UserID, &job.Company, &job.RoleTitle, &job.StartDate, &job.EndDate, &job.CreatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create job: %w", err)
 	}
+	job.setNormalizedRole()
 
 	return &job, nil
 }
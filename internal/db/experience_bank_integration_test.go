@@ -255,7 +255,7 @@ func TestIntegration_Story_CRUD(t *testing.T) {
 	})
 
 	t.Run("list stories by user", func(t *testing.T) {
-		stories, err := db.ListStoriesByUser(ctx, user.ID)
+		stories, err := db.ListStoriesByUser(ctx, user.ID, 0, Page{})
 		if err != nil {
 			t.Fatalf("ListStoriesByUser failed: %v", err)
 		}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// -----------------------------------------------------------------------------
+// Experience Embedding Methods
+// -----------------------------------------------------------------------------
+
+// GetExperienceEmbeddings returns the stored embeddings of the given experience bullets
+// made by model, keyed by experience ID. Bullets edited since they were embedded, or
+// embedded by another model, are left out.
+func (db *DB) GetExperienceEmbeddings(ctx context.Context, ids []uuid.UUID, model string) (map[uuid.UUID][]float32, error) {
+	embeddings := make(map[uuid.UUID][]float32)
+	if len(ids) == 0 {
+		return embeddings, nil
+	}
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, embedding::text FROM experiences
+		 WHERE id = ANY($1) AND embedding IS NOT NULL
+		   AND embedding_model = $2 AND embedding_text_md5 = md5(bullet_text)`,
+		ids, model,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get experience embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var text string
+		if err := rows.Scan(&id, &text); err != nil {
+			return nil, fmt.Errorf("failed to scan experience embedding: %w", err)
+		}
+		vec, err := ParseVector(text)
+		if err != nil {
+			return nil, fmt.Errorf("experience %s: %w", id, err)
+		}
+		embeddings[id] = vec
+	}
+	return embeddings, rows.Err()
+}
+
+// SaveExperienceEmbedding stores the embedding model made of an experience bullet's text
+func (db *DB) SaveExperienceEmbedding(ctx context.Context, id uuid.UUID, model, text string, embedding []float32) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE experiences
+		 SET embedding = $2::vector, embedding_model = $3, embedding_text_md5 = md5($4)
+		 WHERE id = $1`,
+		id, FormatVector(embedding), model, text,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save experience embedding: %w", err)
+	}
+	return nil
+}
+
+// FormatVector writes a vector in pgvector's text form, such as [0.1,-0.2,0.3]
+func FormatVector(v []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(x), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// ParseVector reads a vector in pgvector's text form
+func ParseVector(s string) ([]float32, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("invalid vector %q", s)
+	}
+	s = strings.TrimSpace(s[1 : len(s)-1])
+	if s == "" {
+		return []float32{}, nil
+	}
+	parts := strings.Split(s, ",")
+	v := make([]float32, len(parts))
+	for i, part := range parts {
+		x, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vector component %q: %w", part, err)
+		}
+		v[i] = float32(x)
+	}
+	return v, nil
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatVector(t *testing.T) {
+	assert.Equal(t, "[0.1,-2,300000]", FormatVector([]float32{0.1, -2, 3e5}))
+	assert.Equal(t, "[]", FormatVector(nil))
+}
+
+func TestParseVector(t *testing.T) {
+	v, err := ParseVector("[0.1,-2, 300000]")
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.1, -2, 3e5}, v)
+
+	v, err = ParseVector(FormatVector([]float32{0.123456789, 1e-9}))
+	require.NoError(t, err)
+	assert.Equal(t, []float32{0.123456789, 1e-9}, v, "float32 values round-trip")
+
+	v, err = ParseVector("[]")
+	require.NoError(t, err)
+	assert.Empty(t, v)
+
+	for _, bad := range []string{"", "0.1,0.2", "[0.1,x]", "[0.1,]"} {
+		_, err := ParseVector(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,245 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const githubAccountColumns = `user_id, username, token_sealed, last_synced_at, created_at, updated_at`
+
+func scanGitHubAccount(row pgx.Row) (*GitHubAccount, error) {
+	var a GitHubAccount
+	if err := row.Scan(&a.UserID, &a.Username, &a.TokenSealed, &a.LastSyncedAt, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	a.HasToken = a.TokenSealed != nil
+	return &a, nil
+}
+
+// UpsertGitHubAccount links the user's GitHub profile. A nil tokenSealed keeps the
+// stored token when the username is unchanged and clears it otherwise.
+func (db *DB) UpsertGitHubAccount(ctx context.Context, userID uuid.UUID, username string, tokenSealed *string) (*GitHubAccount, error) {
+	a, err := scanGitHubAccount(db.pool.QueryRow(ctx,
+		`INSERT INTO github_accounts (user_id, username, token_sealed)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id) DO UPDATE SET
+		     username = EXCLUDED.username,
+		     token_sealed = CASE
+		         WHEN EXCLUDED.token_sealed IS NOT NULL THEN EXCLUDED.token_sealed
+		         WHEN github_accounts.username = EXCLUDED.username THEN github_accounts.token_sealed
+		     END,
+		     updated_at = NOW()
+		 RETURNING `+githubAccountColumns,
+		userID, username, tokenSealed,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save github account: %w", err)
+	}
+	return a, nil
+}
+
+// GetGitHubAccount returns the user's linked GitHub profile, or nil if none
+func (db *DB) GetGitHubAccount(ctx context.Context, userID uuid.UUID) (*GitHubAccount, error) {
+	a, err := scanGitHubAccount(db.pool.QueryRow(ctx,
+		`SELECT `+githubAccountColumns+` FROM github_accounts WHERE user_id = $1`, userID,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get github account: %w", err)
+	}
+	return a, nil
+}
+
+// DeleteGitHubAccount unlinks the user's GitHub profile and forgets its token.
+// Drafts already made are kept.
+func (db *DB) DeleteGitHubAccount(ctx context.Context, userID uuid.UUID) error {
+	cmd, err := db.pool.Exec(ctx, `DELETE FROM github_accounts WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete github account: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("github account not found for user: %s", userID)
+	}
+	return nil
+}
+
+// ClaimGitHubSync records a sync of the user's repositories if none happened within
+// minInterval. It returns false, and when the next sync is allowed, if one did; the
+// check and update are one statement so concurrent requests cannot both sync.
+func (db *DB) ClaimGitHubSync(ctx context.Context, userID uuid.UUID, minInterval time.Duration) (bool, time.Time, error) {
+	var claimed bool
+	var next time.Time
+	err := db.pool.QueryRow(ctx,
+		`WITH claim AS (
+		     UPDATE github_accounts SET last_synced_at = NOW()
+		     WHERE user_id = $1
+		       AND (last_synced_at IS NULL OR last_synced_at <= NOW() - make_interval(secs => $2))
+		     RETURNING last_synced_at
+		 )
+		 SELECT EXISTS (SELECT 1 FROM claim),
+		        COALESCE((SELECT last_synced_at FROM github_accounts WHERE user_id = $1), NOW())
+		            + make_interval(secs => $2)`,
+		userID, minInterval.Seconds(),
+	).Scan(&claimed, &next)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("failed to claim github sync: %w", err)
+	}
+	return claimed, next, nil
+}
+
+const projectDraftColumns = `id, user_id, repo_name, repo_url, description, language, stars, skills,
+	text, status, story_id, created_at, updated_at`
+
+func scanProjectDraft(row pgx.Row) (*ProjectDraft, error) {
+	var d ProjectDraft
+	err := row.Scan(&d.ID, &d.UserID, &d.RepoName, &d.RepoURL, &d.Description, &d.Language, &d.Stars,
+		&d.Skills, &d.Text, &d.Status, &d.StoryID, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// UpsertProjectDrafts saves drafts for the user's repositories. Pending drafts of a
+// repository already seen are refreshed; accepted or dismissed ones are left as reviewed.
+func (db *DB) UpsertProjectDrafts(ctx context.Context, userID uuid.UUID, drafts []ProjectDraftInput) error {
+	batch := &pgx.Batch{}
+	for _, d := range drafts {
+		batch.Queue(
+			`INSERT INTO project_drafts (user_id, repo_name, repo_url, description, language, stars, skills, text)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			 ON CONFLICT (user_id, repo_url) DO UPDATE SET
+			     repo_name = EXCLUDED.repo_name,
+			     description = EXCLUDED.description,
+			     language = EXCLUDED.language,
+			     stars = EXCLUDED.stars,
+			     skills = EXCLUDED.skills,
+			     text = EXCLUDED.text,
+			     updated_at = NOW()
+			 WHERE project_drafts.status = 'pending'`,
+			userID, d.RepoName, d.RepoURL, nullIfEmpty(d.Description), nullIfEmpty(d.Language),
+			d.Stars, StringArray(d.Skills), d.Text,
+		)
+	}
+	if err := db.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to save project drafts: %w", err)
+	}
+	return nil
+}
+
+// ListProjectDrafts returns the user's drafts, newest first. An empty status lists all.
+func (db *DB) ListProjectDrafts(ctx context.Context, userID uuid.UUID, status string) ([]ProjectDraft, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+projectDraftColumns+` FROM project_drafts
+		 WHERE user_id = $1 AND ($2 = '' OR status = $2)
+		 ORDER BY updated_at DESC, stars DESC`,
+		userID, status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project drafts: %w", err)
+	}
+	defer rows.Close()
+
+	drafts := []ProjectDraft{}
+	for rows.Next() {
+		d, err := scanProjectDraft(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project draft: %w", err)
+		}
+		drafts = append(drafts, *d)
+	}
+	return drafts, rows.Err()
+}
+
+// GetProjectDraft returns a draft by ID, or nil if not found
+func (db *DB) GetProjectDraft(ctx context.Context, id uuid.UUID) (*ProjectDraft, error) {
+	d, err := scanProjectDraft(db.pool.QueryRow(ctx,
+		`SELECT `+projectDraftColumns+` FROM project_drafts WHERE id = $1`, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get project draft: %w", err)
+	}
+	return d, nil
+}
+
+// AcceptProjectDraft adds a pending draft to the experience bank as a story under
+// jobID, with text (the reviewed bullet) as its only bullet. It returns nil if the
+// draft is no longer pending.
+func (db *DB) AcceptProjectDraft(ctx context.Context, id, jobID uuid.UUID, text string) (*ProjectDraft, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	d, err := scanProjectDraft(tx.QueryRow(ctx,
+		`SELECT `+projectDraftColumns+` FROM project_drafts
+		 WHERE id = $1 AND status = 'pending' FOR UPDATE`, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get project draft: %w", err)
+	}
+
+	input := &StoryCreateInput{
+		StoryID: "github-" + d.ID.String(),
+		UserID:  d.UserID,
+		JobID:   jobID,
+		Title:   d.RepoName,
+		Bullets: []BulletCreateInput{{
+			BulletID: "github-" + d.ID.String(),
+			Text:     text,
+			Skills:   d.Skills,
+		}},
+	}
+	if d.Description != nil {
+		input.Description = *d.Description
+	}
+	story, err := createStory(ctx, tx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err = scanProjectDraft(tx.QueryRow(ctx,
+		`UPDATE project_drafts SET status = 'accepted', text = $2, story_id = $3, updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+projectDraftColumns,
+		id, text, story.ID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept project draft: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return d, nil
+}
+
+// DismissProjectDraft marks a pending draft as dismissed so later syncs leave it alone.
+// It returns nil if the draft is no longer pending.
+func (db *DB) DismissProjectDraft(ctx context.Context, id uuid.UUID) (*ProjectDraft, error) {
+	d, err := scanProjectDraft(db.pool.QueryRow(ctx,
+		`UPDATE project_drafts SET status = 'dismissed', updated_at = NOW()
+		 WHERE id = $1 AND status = 'pending'
+		 RETURNING `+projectDraftColumns, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to dismiss project draft: %w", err)
+	}
+	return d, nil
+}
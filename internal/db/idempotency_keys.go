@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// -----------------------------------------------------------------------------
+// Idempotency Key Methods
+// -----------------------------------------------------------------------------
+
+// ClaimIdempotencyKey claims key for a request by the user whose body hashes to
+// requestHash, holding it for ttl. A key that expired, or whose request has been in
+// progress for longer than stale, is claimed again. Returns nil when the claim
+// succeeded, or the key's current holder when it did not.
+func (db *DB) ClaimIdempotencyKey(ctx context.Context, userID uuid.UUID, key, requestHash string, ttl, stale time.Duration) (*IdempotencyKey, error) {
+	now := time.Now()
+	var claimed bool
+	err := db.pool.QueryRow(ctx,
+		`INSERT INTO idempotency_keys (user_id, idempotency_key, request_hash, created_at, expires_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id, idempotency_key) DO UPDATE
+		    SET request_hash = EXCLUDED.request_hash, status_code = NULL, response = NULL,
+		        created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		  WHERE idempotency_keys.expires_at <= $4
+		     OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $6)
+		 RETURNING TRUE`,
+		userID, key, requestHash, now, now.Add(ttl), now.Add(-stale),
+	).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	holder := IdempotencyKey{UserID: userID, Key: key}
+	err = db.pool.QueryRow(ctx,
+		`SELECT request_hash, status_code, response, created_at, expires_at
+		 FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2`,
+		userID, key,
+	).Scan(&holder.RequestHash, &holder.StatusCode, &holder.Response, &holder.CreatedAt, &holder.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return &holder, nil
+}
+
+// CompleteIdempotencyKey stores the response to the request holding key, to be replayed
+// for repeats until the key expires
+func (db *DB) CompleteIdempotencyKey(ctx context.Context, userID uuid.UUID, key string, statusCode int, response json.RawMessage) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE idempotency_keys SET status_code = $3, response = $4
+		 WHERE user_id = $1 AND idempotency_key = $2`,
+		userID, key, statusCode, response,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey deletes a key whose request failed without a response worth
+// replaying, so the client can retry with it
+func (db *DB) ReleaseIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) error {
+	_, err := db.pool.Exec(ctx,
+		`DELETE FROM idempotency_keys
+		 WHERE user_id = $1 AND idempotency_key = $2 AND status_code IS NULL`,
+		userID, key,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys deletes keys past their expiry. Returns the number deleted.
+func (db *DB) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	result, err := db.pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
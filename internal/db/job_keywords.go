@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// -----------------------------------------------------------------------------
+// Keyword Override Methods
+// -----------------------------------------------------------------------------
+
+// GetUserKeywords returns a job profile's keywords as userID sees them: the shared
+// keywords they have not hidden, and the keywords they added
+func (db *DB) GetUserKeywords(ctx context.Context, profileID, userID uuid.UUID) ([]JobKeyword, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT k.id, k.job_profile_id, k.keyword, k.keyword_normalized, k.source, k.created_at
+		 FROM job_keywords k
+		 WHERE k.job_profile_id = $1 AND NOT k.excluded
+		   AND (k.user_id = $2 OR (k.user_id IS NULL AND NOT EXISTS (
+		        SELECT 1 FROM job_keywords u
+		        WHERE u.job_profile_id = k.job_profile_id AND u.user_id = $2
+		          AND u.keyword_normalized = k.keyword_normalized)))
+		 ORDER BY k.keyword`,
+		profileID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keywords: %w", err)
+	}
+	defer rows.Close()
+
+	var keywords []JobKeyword
+	for rows.Next() {
+		var k JobKeyword
+		if err := rows.Scan(&k.ID, &k.JobProfileID, &k.Keyword, &k.KeywordNormalized,
+			&k.Source, &k.CreatedAt); err != nil {
+			return nil, err
+		}
+		keywords = append(keywords, k)
+	}
+	return keywords, rows.Err()
+}
+
+// AddUserKeyword adds a keyword to a job profile for userID's runs, replacing any earlier
+// change they made to the same keyword
+func (db *DB) AddUserKeyword(ctx context.Context, profileID, userID uuid.UUID, keyword string) error {
+	_, err := db.pool.Exec(ctx,
+		`INSERT INTO job_keywords (job_profile_id, user_id, keyword, keyword_normalized, source)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (job_profile_id, user_id, keyword_normalized) WHERE user_id IS NOT NULL DO UPDATE SET
+		     keyword = $3,
+		     source = $5,
+		     excluded = FALSE,
+		     updated_at = NOW()`,
+		profileID, userID, keyword, NormalizeKeyword(keyword), KeywordSourceUser,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add keyword: %w", err)
+	}
+	return nil
+}
+
+// RemoveUserKeyword removes a keyword from userID's view of a job profile. A keyword they
+// added is deleted; a shared keyword is hidden from their runs only. Returns false if neither
+// had the keyword.
+func (db *DB) RemoveUserKeyword(ctx context.Context, profileID, userID uuid.UUID, keyword string) (bool, error) {
+	normalized := NormalizeKeyword(keyword)
+	var shared bool
+	err := db.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM job_keywords
+		                WHERE job_profile_id = $1 AND user_id IS NULL AND keyword_normalized = $2)`,
+		profileID, normalized,
+	).Scan(&shared)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove keyword: %w", err)
+	}
+
+	if !shared {
+		tag, err := db.pool.Exec(ctx,
+			`DELETE FROM job_keywords
+			 WHERE job_profile_id = $1 AND user_id = $2 AND keyword_normalized = $3 AND NOT excluded`,
+			profileID, userID, normalized,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to remove keyword: %w", err)
+		}
+		return tag.RowsAffected() > 0, nil
+	}
+
+	tag, err := db.pool.Exec(ctx,
+		`INSERT INTO job_keywords (job_profile_id, user_id, keyword, keyword_normalized, source, excluded)
+		 VALUES ($1, $2, $3, $4, $5, TRUE)
+		 ON CONFLICT (job_profile_id, user_id, keyword_normalized) WHERE user_id IS NOT NULL DO UPDATE SET
+		     excluded = TRUE,
+		     updated_at = NOW()
+		 WHERE NOT job_keywords.excluded`,
+		profileID, userID, keyword, normalized, KeywordSourceUser,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove keyword: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetPostingKeywordOverrides returns userID's keyword changes for the job profiles of
+// every posting in a canonical posting's group, least recently made first so later
+// changes to the same keyword win
+func (db *DB) GetPostingKeywordOverrides(ctx context.Context, userID, canonicalID uuid.UUID) ([]KeywordOverride, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT k.keyword, k.excluded
+		 FROM job_keywords k
+		 JOIN job_profiles jp ON jp.id = k.job_profile_id
+		 JOIN job_postings p ON p.id = jp.posting_id
+		 WHERE k.user_id = $1 AND COALESCE(p.canonical_posting_id, p.id) = $2
+		 ORDER BY k.updated_at`,
+		userID, canonicalID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keyword overrides: %w", err)
+	}
+	defer rows.Close()
+
+	var overrides []KeywordOverride
+	for rows.Next() {
+		var o KeywordOverride
+		if err := rows.Scan(&o.Keyword, &o.Excluded); err != nil {
+			return nil, fmt.Errorf("failed to scan keyword override: %w", err)
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, rows.Err()
+}
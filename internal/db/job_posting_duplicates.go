@@ -0,0 +1,308 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// DuplicatePostingThreshold is the PostingSimilarity at or above which two postings are
+// treated as the same role
+const DuplicatePostingThreshold = 0.85
+
+// maxDuplicateCandidates bounds how many recent canonical postings are compared by text
+const maxDuplicateCandidates = 500
+
+// postingShingleSize is the number of words in each shingle compared by PostingSimilarity
+const postingShingleSize = 3
+
+// minPostingShingles is the fewest shingles a posting needs to be fuzzy matched; shorter
+// texts are too generic to tell roles apart
+const minPostingShingles = 20
+
+// -----------------------------------------------------------------------------
+// Duplicate Posting Methods
+// -----------------------------------------------------------------------------
+
+// LinkDuplicatePosting records whether posting duplicates another posting, returning the
+// match or nil when posting is canonical. A posting whose normalized text hashes the same
+// as an earlier posting's is a content hash match; otherwise the most similar recent
+// canonical posting at the same company (or any company when either is unknown) is a fuzzy
+// match when PostingSimilarity reaches DuplicatePostingThreshold. Postings that duplicated
+// posting are moved to its canonical posting, so groups never chain.
+func (db *DB) LinkDuplicatePosting(ctx context.Context, posting *JobPosting, cleanedText string) (*PostingDuplicate, error) {
+	normalizedHash := HashJobContent(NormalizePostingText(cleanedText))
+
+	dup, err := db.findDuplicateByHash(ctx, posting.ID, normalizedHash)
+	if err != nil {
+		return nil, err
+	}
+	if dup == nil {
+		if dup, err = db.findDuplicateByText(ctx, posting.ID, posting.CompanyID, cleanedText); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var canonicalID *uuid.UUID
+	var match *string
+	var similarity *float64
+	if dup != nil {
+		canonicalID, match, similarity = &dup.CanonicalID, &dup.Match, &dup.Similarity
+		if _, err := tx.Exec(ctx,
+			`UPDATE job_postings SET canonical_posting_id = $2, updated_at = NOW()
+			 WHERE canonical_posting_id = $1`,
+			posting.ID, dup.CanonicalID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to move duplicate postings: %w", err)
+		}
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE job_postings
+		 SET normalized_hash = $2, canonical_posting_id = $3, duplicate_match = $4,
+		     duplicate_similarity = $5, updated_at = NOW()
+		 WHERE id = $1`,
+		posting.ID, normalizedHash, canonicalID, match, similarity,
+	); err != nil {
+		return nil, fmt.Errorf("failed to link duplicate posting: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	posting.CanonicalPostingID, posting.DuplicateMatch, posting.DuplicateSimilarity = canonicalID, match, similarity
+	return dup, nil
+}
+
+// findDuplicateByHash returns the canonical posting of an earlier posting whose normalized
+// text hashes to normalizedHash
+func (db *DB) findDuplicateByHash(ctx context.Context, postingID uuid.UUID, normalizedHash string) (*PostingDuplicate, error) {
+	dup := PostingDuplicate{PostingID: postingID, Match: DuplicateMatchContentHash, Similarity: 1}
+	err := db.pool.QueryRow(ctx,
+		`SELECT c.id, c.url
+		 FROM job_postings p
+		 JOIN job_postings c ON c.id = COALESCE(p.canonical_posting_id, p.id)
+		 WHERE p.normalized_hash = $2 AND p.id <> $1 AND c.id <> $1
+		 ORDER BY p.created_at
+		 LIMIT 1`,
+		postingID, normalizedHash,
+	).Scan(&dup.CanonicalID, &dup.CanonicalURL)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find duplicate posting: %w", err)
+	}
+	return &dup, nil
+}
+
+// findDuplicateByText returns the recent canonical posting most similar to text, if it
+// reaches DuplicatePostingThreshold
+func (db *DB) findDuplicateByText(ctx context.Context, postingID uuid.UUID, companyID *uuid.UUID, text string) (*PostingDuplicate, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, url, cleaned_text
+		 FROM job_postings
+		 WHERE id <> $1 AND canonical_posting_id IS NULL
+		   AND fetch_status = 'success' AND cleaned_text IS NOT NULL
+		   AND ($2::uuid IS NULL OR company_id IS NULL OR company_id = $2)
+		 ORDER BY created_at DESC
+		 LIMIT $3`,
+		postingID, companyID, maxDuplicateCandidates,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list duplicate candidates: %w", err)
+	}
+	defer rows.Close()
+
+	shingles := postingShingles(text)
+	var best *PostingDuplicate
+	for rows.Next() {
+		var id uuid.UUID
+		var url, candidate string
+		if err := rows.Scan(&id, &url, &candidate); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate candidate: %w", err)
+		}
+		similarity := shingleOverlap(shingles, postingShingles(candidate))
+		if similarity >= DuplicatePostingThreshold && (best == nil || similarity > best.Similarity) {
+			best = &PostingDuplicate{PostingID: postingID, CanonicalID: id, CanonicalURL: url, Match: DuplicateMatchFuzzy, Similarity: similarity}
+		}
+	}
+	return best, rows.Err()
+}
+
+// ListDuplicatePostings returns the postings linked to a canonical posting, oldest first
+func (db *DB) ListDuplicatePostings(ctx context.Context, canonicalID uuid.UUID) ([]JobPosting, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, company_id, url, role_title, platform, content_hash, fetch_status,
+		        fetched_at, expires_at, created_at, updated_at,
+		        canonical_posting_id, duplicate_match, duplicate_similarity
+		 FROM job_postings
+		 WHERE canonical_posting_id = $1
+		 ORDER BY created_at`,
+		canonicalID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list duplicate postings: %w", err)
+	}
+	defer rows.Close()
+
+	var postings []JobPosting
+	for rows.Next() {
+		var p JobPosting
+		if err := rows.Scan(&p.ID, &p.CompanyID, &p.URL, &p.RoleTitle, &p.Platform,
+			&p.ContentHash, &p.FetchStatus, &p.FetchedAt, &p.ExpiresAt,
+			&p.CreatedAt, &p.UpdatedAt,
+			&p.CanonicalPostingID, &p.DuplicateMatch, &p.DuplicateSimilarity); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate posting: %w", err)
+		}
+		postings = append(postings, p)
+	}
+	return postings, rows.Err()
+}
+
+// SetRunJobPosting links a run to the job posting it was created from
+func (db *DB) SetRunJobPosting(ctx context.Context, runID, postingID uuid.UUID) error {
+	tag, err := db.pool.Exec(ctx,
+		`UPDATE pipeline_runs SET job_posting_id = $2 WHERE id = $1`,
+		runID, postingID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set run job posting: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("run not found: %s", runID)
+	}
+	return nil
+}
+
+// GetRunJobPosting returns the job posting a run was created from, or nil if none is linked
+func (db *DB) GetRunJobPosting(ctx context.Context, runID uuid.UUID) (*JobPosting, error) {
+	var postingID *uuid.UUID
+	err := db.pool.QueryRow(ctx,
+		`SELECT job_posting_id FROM pipeline_runs WHERE id = $1`,
+		runID,
+	).Scan(&postingID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get run job posting: %w", err)
+	}
+	if postingID == nil {
+		return nil, nil
+	}
+	return db.GetJobPostingByID(ctx, *postingID)
+}
+
+// FindParsedPostingRun returns the latest run that parsed a job profile for any posting in
+// a canonical posting's group, or uuid.Nil when none has
+func (db *DB) FindParsedPostingRun(ctx context.Context, canonicalID uuid.UUID) (uuid.UUID, error) {
+	var runID uuid.UUID
+	err := db.pool.QueryRow(ctx,
+		`SELECT r.id
+		 FROM pipeline_runs r
+		 JOIN job_postings p ON p.id = r.job_posting_id
+		 JOIN artifacts a ON a.run_id = r.id AND a.step = $2
+		 WHERE COALESCE(p.canonical_posting_id, p.id) = $1
+		 ORDER BY r.created_at DESC
+		 LIMIT 1`,
+		canonicalID, StepJobProfile,
+	).Scan(&runID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, fmt.Errorf("failed to find parsed posting run: %w", err)
+	}
+	return runID, nil
+}
+
+// ListPostingRuns returns the user's runs for any posting in a canonical posting's group,
+// newest first
+func (db *DB) ListPostingRuns(ctx context.Context, canonicalID, userID uuid.UUID) ([]Run, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT r.id, r.company, r.role_title, r.job_url, r.status, r.user_id, r.priority,
+		        r.created_at, r.completed_at, r.deadline_at, r.follow_up_at
+		 FROM pipeline_runs r
+		 JOIN job_postings p ON p.id = r.job_posting_id
+		 WHERE COALESCE(p.canonical_posting_id, p.id) = $1 AND r.user_id = $2
+		 ORDER BY r.created_at DESC`,
+		canonicalID, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posting runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []Run
+	for rows.Next() {
+		var run Run
+		if err := rows.Scan(&run.ID, &run.Company, &run.RoleTitle, &run.JobURL, &run.Status, &run.UserID, &run.Priority,
+			&run.CreatedAt, &run.CompletedAt, &run.DeadlineAt, &run.FollowUpAt); err != nil {
+			return nil, fmt.Errorf("failed to scan run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// -----------------------------------------------------------------------------
+// Text Matching
+// -----------------------------------------------------------------------------
+
+// NormalizePostingText lowercases text and reduces it to words separated by single
+// spaces, so formatting differences between job boards don't change its hash
+func NormalizePostingText(text string) string {
+	return strings.Join(postingWords(text), " ")
+}
+
+// PostingSimilarity scores how much of the shorter posting's text appears in the longer
+// one (0-1), comparing overlapping runs of words. Job boards wrap the same description in
+// different boilerplate, so containment is used rather than symmetric overlap. Texts too
+// short to tell roles apart score 0.
+func PostingSimilarity(a, b string) float64 {
+	return shingleOverlap(postingShingles(a), postingShingles(b))
+}
+
+// postingWords splits text into lowercase runs of letters and digits
+func postingWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// postingShingles returns the set of postingShingleSize-word runs in text
+func postingShingles(text string) map[string]struct{} {
+	words := postingWords(text)
+	shingles := make(map[string]struct{})
+	for i := 0; i+postingShingleSize <= len(words); i++ {
+		shingles[strings.Join(words[i:i+postingShingleSize], " ")] = struct{}{}
+	}
+	return shingles
+}
+
+// shingleOverlap returns the share of the smaller shingle set found in the larger one
+func shingleOverlap(a, b map[string]struct{}) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(a) < minPostingShingles {
+		return 0
+	}
+	shared := 0
+	for s := range a {
+		if _, ok := b[s]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a))
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPostingDescription = `Senior Backend Engineer, Payments. You will design and operate the
+services that move money for millions of merchants, own the reliability of our ledger, and mentor
+engineers across the team. We are looking for five or more years building distributed systems in
+Go or Java, experience with Kafka and PostgreSQL at scale, and a track record of leading projects
+from design through launch.`
+
+func TestNormalizePostingText(t *testing.T) {
+	assert.Equal(t, "senior engineer go k8s", NormalizePostingText("  Senior Engineer —\n\nGo / K8s!  "))
+	assert.Equal(t,
+		HashJobContent(NormalizePostingText("About the role:\n  Build APIs.")),
+		HashJobContent(NormalizePostingText("ABOUT THE ROLE  Build APIs")),
+		"formatting differences hash the same")
+}
+
+func TestPostingSimilarity(t *testing.T) {
+	linkedIn := "Easy Apply. Show more. " + testPostingDescription + " Show less. Report this job."
+	assert.Equal(t, 1.0, PostingSimilarity(testPostingDescription, linkedIn), "boilerplate around the same text")
+	assert.Equal(t, 1.0, PostingSimilarity(linkedIn, testPostingDescription), "symmetric")
+
+	other := `Staff Frontend Engineer, Growth. You will build the onboarding experience in React and
+TypeScript, run experiments with product and design, and raise the bar for accessibility and
+performance across our web apps. Experience shipping consumer products is a plus.`
+	assert.Less(t, PostingSimilarity(testPostingDescription, other), 0.1)
+
+	edited := testPostingDescription + " Salary range: $180,000 to $220,000. Hybrid in New York."
+	assert.GreaterOrEqual(t, PostingSimilarity(testPostingDescription, edited), DuplicatePostingThreshold)
+
+	assert.Equal(t, 0.0, PostingSimilarity("Software Engineer", "Software Engineer"), "too short to compare")
+}
+
+func TestJobPosting_CanonicalID(t *testing.T) {
+	p := &JobPosting{ID: uuid.New()}
+	assert.Equal(t, p.ID, p.CanonicalID())
+
+	canonical := uuid.New()
+	p.CanonicalPostingID = &canonical
+	assert.Equal(t, canonical, p.CanonicalID())
+}
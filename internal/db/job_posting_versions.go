@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// -----------------------------------------------------------------------------
+// Posting Version Methods
+// -----------------------------------------------------------------------------
+
+// versionContentSQL selects the requirements, keywords, and first-seen time of a posting's
+// current version, for a posting aliased p whose profile is aliased jp
+const versionContentSQL = `
+	COALESCE((SELECT jsonb_agg(jsonb_build_object('type', r.requirement_type, 'skill', r.skill)
+	                           ORDER BY r.requirement_type, r.ordinal)
+	          FROM job_requirements r WHERE r.job_profile_id = jp.id), '[]'::jsonb),
+	COALESCE((SELECT jsonb_agg(k.keyword ORDER BY k.keyword)
+	          FROM job_keywords k WHERE k.job_profile_id = jp.id AND k.user_id IS NULL), '[]'::jsonb),
+	COALESCE((SELECT MAX(v.superseded_at) FROM job_posting_versions v WHERE v.posting_id = p.id), p.created_at)`
+
+// archivePostingVersion archives the stored posting at url, with what was parsed from it,
+// when a fetch is about to replace it with content hashing to contentHash
+func archivePostingVersion(ctx context.Context, tx pgx.Tx, url, contentHash string) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO job_posting_versions (posting_id, company_id, url, role_title, role_family, role_level,
+		                                   cleaned_text, content_hash, requirements, keywords, first_seen_at)
+		 SELECT p.id, p.company_id, p.url, COALESCE(jp.role_title, p.role_title), jp.role_family, jp.role_level,
+		        p.cleaned_text, p.content_hash,`+versionContentSQL+`
+		 FROM job_postings p
+		 LEFT JOIN job_profiles jp ON jp.posting_id = p.id
+		 WHERE p.url = $1 AND p.cleaned_text IS NOT NULL AND p.content_hash IS DISTINCT FROM $2`,
+		url, contentHash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to archive job posting: %w", err)
+	}
+	return nil
+}
+
+// ListPostingVersions returns the archived versions of a posting, most recently replaced first
+func (db *DB) ListPostingVersions(ctx context.Context, postingID uuid.UUID) ([]PostingVersion, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, posting_id, company_id, url, role_title, role_family, role_level, content_hash,
+		        cleaned_text, requirements, keywords, first_seen_at, superseded_at
+		 FROM job_posting_versions
+		 WHERE posting_id = $1
+		 ORDER BY superseded_at DESC`,
+		postingID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posting versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []PostingVersion
+	for rows.Next() {
+		var v PostingVersion
+		var requirementsJSON, keywordsJSON []byte
+		if err := rows.Scan(&v.ID, &v.PostingID, &v.CompanyID, &v.URL, &v.RoleTitle, &v.RoleFamily,
+			&v.RoleLevel, &v.ContentHash, &v.CleanedText, &requirementsJSON, &keywordsJSON,
+			&v.FirstSeenAt, &v.SupersededAt); err != nil {
+			return nil, fmt.Errorf("failed to scan posting version: %w", err)
+		}
+		if err := v.unmarshalContent(requirementsJSON, keywordsJSON); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// ListCompanyPostingHistory returns every parsed version of a company's canonical postings,
+// archived and current, oldest first. A non-empty roleFamily keeps only versions of that
+// role family (see titles.Normalize). Cleaned text is left out.
+func (db *DB) ListCompanyPostingHistory(ctx context.Context, companyID uuid.UUID, roleFamily string) ([]PostingVersion, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT * FROM (
+		     SELECT v.id, v.posting_id, v.company_id, v.url, v.role_title, v.role_family, v.role_level,
+		            v.content_hash, v.requirements, v.keywords, v.first_seen_at, v.superseded_at
+		     FROM job_posting_versions v
+		     JOIN job_postings p ON p.id = v.posting_id
+		     WHERE v.company_id = $1 AND p.canonical_posting_id IS NULL
+		       AND ($2 = '' OR v.role_family = $2)
+		       AND (jsonb_array_length(v.requirements) > 0 OR jsonb_array_length(v.keywords) > 0)
+		     UNION ALL
+		     SELECT NULL, p.id, p.company_id, p.url, jp.role_title, jp.role_family, jp.role_level,
+		            p.content_hash,`+versionContentSQL+`, NULL
+		     FROM job_postings p
+		     JOIN job_profiles jp ON jp.posting_id = p.id
+		     WHERE p.company_id = $1 AND p.canonical_posting_id IS NULL
+		       AND ($2 = '' OR jp.role_family = $2)
+		 ) versions
+		 ORDER BY first_seen_at`,
+		companyID, roleFamily,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posting history: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []PostingVersion
+	for rows.Next() {
+		var v PostingVersion
+		var requirementsJSON, keywordsJSON []byte
+		if err := rows.Scan(&v.ID, &v.PostingID, &v.CompanyID, &v.URL, &v.RoleTitle, &v.RoleFamily,
+			&v.RoleLevel, &v.ContentHash, &requirementsJSON, &keywordsJSON,
+			&v.FirstSeenAt, &v.SupersededAt); err != nil {
+			return nil, fmt.Errorf("failed to scan posting version: %w", err)
+		}
+		if err := v.unmarshalContent(requirementsJSON, keywordsJSON); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// unmarshalContent parses a version's requirements and keywords columns
+func (v *PostingVersion) unmarshalContent(requirementsJSON, keywordsJSON []byte) error {
+	v.Requirements, v.Keywords = []VersionRequirement{}, []string{}
+	if err := json.Unmarshal(requirementsJSON, &v.Requirements); err != nil {
+		return fmt.Errorf("failed to parse version requirements: %w", err)
+	}
+	if err := json.Unmarshal(keywordsJSON, &v.Keywords); err != nil {
+		return fmt.Errorf("failed to parse version keywords: %w", err)
+	}
+	return nil
+}
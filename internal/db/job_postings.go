@@ -24,13 +24,15 @@ func (db *DB) GetJobPostingByURL(ctx context.Context, url string) (*JobPosting,
 		`SELECT id, company_id, url, role_title, platform, raw_html, cleaned_text,
 		        content_hash, about_company, admin_info, extracted_links,
 		        http_status, fetch_status, error_message, fetched_at, expires_at,
-		        last_accessed_at, created_at, updated_at
+		        last_accessed_at, created_at, updated_at,
+		        canonical_posting_id, duplicate_match, duplicate_similarity
 		 FROM job_postings WHERE url = $1`,
 		url,
 	).Scan(&p.ID, &p.CompanyID, &p.URL, &p.RoleTitle, &p.Platform, &p.RawHTML,
 		&p.CleanedText, &p.ContentHash, &p.AboutCompany, &adminInfoJSON, &linksJSON,
 		&p.HTTPStatus, &p.FetchStatus, &p.ErrorMessage, &p.FetchedAt, &p.ExpiresAt,
-		&p.LastAccessed, &p.CreatedAt, &p.UpdatedAt)
+		&p.LastAccessed, &p.CreatedAt, &p.UpdatedAt,
+		&p.CanonicalPostingID, &p.DuplicateMatch, &p.DuplicateSimilarity)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -58,13 +60,15 @@ func (db *DB) GetJobPostingByID(ctx context.Context, id uuid.UUID) (*JobPosting,
 		`SELECT id, company_id, url, role_title, platform, raw_html, cleaned_text,
 		        content_hash, about_company, admin_info, extracted_links,
 		        http_status, fetch_status, error_message, fetched_at, expires_at,
-		        last_accessed_at, created_at, updated_at
+		        last_accessed_at, created_at, updated_at,
+		        canonical_posting_id, duplicate_match, duplicate_similarity
 		 FROM job_postings WHERE id = $1`,
 		id,
 	).Scan(&p.ID, &p.CompanyID, &p.URL, &p.RoleTitle, &p.Platform, &p.RawHTML,
 		&p.CleanedText, &p.ContentHash, &p.AboutCompany, &adminInfoJSON, &linksJSON,
 		&p.HTTPStatus, &p.FetchStatus, &p.ErrorMessage, &p.FetchedAt, &p.ExpiresAt,
-		&p.LastAccessed, &p.CreatedAt, &p.UpdatedAt)
+		&p.LastAccessed, &p.CreatedAt, &p.UpdatedAt,
+		&p.CanonicalPostingID, &p.DuplicateMatch, &p.DuplicateSimilarity)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -104,7 +108,8 @@ func (db *DB) GetFreshJobPosting(ctx context.Context, url string) (*JobPosting,
 	return posting, nil
 }
 
-// UpsertJobPosting creates or updates a job posting
+// UpsertJobPosting creates or updates a job posting. When the fetched content differs from
+// the stored posting, the stored version is archived first (see ListPostingVersions).
 func (db *DB) UpsertJobPosting(ctx context.Context, input *JobPostingCreateInput) (*JobPosting, error) {
 	var p JobPosting
 
@@ -130,7 +135,17 @@ func (db *DB) UpsertJobPosting(ctx context.Context, input *JobPostingCreateInput
 	// Set expiry
 	expiresAt := time.Now().Add(DefaultJobPostingCacheTTL)
 
-	err = db.pool.QueryRow(ctx,
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := archivePostingVersion(ctx, tx, input.URL, contentHash); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx,
 		`INSERT INTO job_postings (company_id, url, role_title, platform, raw_html, 
 		                           cleaned_text, content_hash, about_company, admin_info,
 		                           extracted_links, http_status, fetch_status, fetched_at, expires_at)
@@ -161,6 +176,9 @@ func (db *DB) UpsertJobPosting(ctx context.Context, input *JobPostingCreateInput
 	if err != nil {
 		return nil, fmt.Errorf("failed to upsert job posting: %w", err)
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
 	return &p, nil
 }
@@ -216,8 +234,9 @@ func (db *DB) ListJobPostingsByCompany(ctx context.Context, companyID uuid.UUID)
 type ListJobPostingsOptions struct {
 	Platform  *string    // Filter by platform (greenhouse, lever, etc.)
 	CompanyID *uuid.UUID // Filter by company
+	Canonical bool       // Leave out postings that duplicate another posting
 	Limit     int        // Pagination limit
-	Offset    int        // Pagination offset
+	Page      Page       // Sort over JobPostingSortKeys; newest first when unset
 }
 
 // ListJobPostings lists job postings with optional filters and pagination
@@ -239,6 +258,10 @@ func (db *DB) ListJobPostings(ctx context.Context, opts ListJobPostingsOptions)
 		argIndex++
 	}
 
+	if opts.Canonical {
+		conditions = append(conditions, "canonical_posting_id IS NULL")
+	}
+
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
@@ -252,6 +275,22 @@ func (db *DB) ListJobPostings(ctx context.Context, opts ListJobPostingsOptions)
 		return nil, 0, fmt.Errorf("failed to count job postings: %w", err)
 	}
 
+	// Start the page after its cursor
+	orderBy := " ORDER BY created_at DESC"
+	if opts.Page.Sort != "" {
+		cond, pageOrder, pageArgs, err := pageClause(opts.Page, JobPostingSortKeys, "id", argIndex)
+		if err != nil {
+			return nil, 0, err
+		}
+		if cond != "" {
+			conditions = append(conditions, cond)
+			whereClause = "WHERE " + strings.Join(conditions, " AND ")
+			args = append(args, pageArgs...)
+			argIndex += len(pageArgs)
+		}
+		orderBy = pageOrder
+	}
+
 	// Get postings
 	limit := opts.Limit
 	if limit <= 0 {
@@ -261,21 +300,16 @@ func (db *DB) ListJobPostings(ctx context.Context, opts ListJobPostingsOptions)
 		limit = 100
 	}
 
-	offset := opts.Offset
-	if offset < 0 {
-		offset = 0
-	}
-
-	args = append(args, limit, offset)
+	args = append(args, limit)
 	query := fmt.Sprintf(
 		`SELECT id, company_id, url, role_title, platform, cleaned_text,
 		        content_hash, about_company, admin_info, extracted_links,
 		        http_status, fetch_status, error_message, fetched_at, expires_at,
-		        last_accessed_at, created_at, updated_at
-		 FROM job_postings %s
-		 ORDER BY created_at DESC
-		 LIMIT $%d OFFSET $%d`,
-		whereClause, argIndex, argIndex+1,
+		        last_accessed_at, created_at, updated_at,
+		        canonical_posting_id, duplicate_match, duplicate_similarity
+		 FROM job_postings %s%s
+		 LIMIT $%d`,
+		whereClause, orderBy, argIndex,
 	)
 
 	rows, err := db.pool.Query(ctx, query, args...)
@@ -295,6 +329,7 @@ func (db *DB) ListJobPostings(ctx context.Context, opts ListJobPostingsOptions)
 			&p.CleanedText, &p.ContentHash, &p.AboutCompany, &adminInfoJSON, &linksJSON,
 			&p.HTTPStatus, &p.FetchStatus, &p.ErrorMessage, &p.FetchedAt, &p.ExpiresAt,
 			&p.LastAccessed, &p.CreatedAt, &p.UpdatedAt,
+			&p.CanonicalPostingID, &p.DuplicateMatch, &p.DuplicateSimilarity,
 		)
 		if err != nil {
 			return nil, 0, err
@@ -331,14 +366,16 @@ func (db *DB) GetJobProfileByPostingID(ctx context.Context, postingID uuid.UUID)
 		        eval_latency, eval_reliability, eval_ownership, eval_scale, eval_collaboration,
 		        eval_signals_raw, education_min_degree, education_preferred_fields,
 		        education_is_required, education_evidence, parsed_at, parser_version,
-		        created_at, updated_at
+		        created_at, updated_at,
+		        COALESCE(role_title_normalized, ''), COALESCE(role_family, ''), COALESCE(role_level, '')
 		 FROM job_profiles WHERE posting_id = $1`,
 		postingID,
 	).Scan(&p.ID, &p.PostingID, &p.CompanyName, &p.RoleTitle,
 		&p.EvalLatency, &p.EvalReliability, &p.EvalOwnership, &p.EvalScale, &p.EvalCollaboration,
 		&evalSignalsJSON, &p.EducationMinDegree, &eduFieldsJSON,
 		&p.EducationIsRequired, &p.EducationEvidence, &p.ParsedAt, &p.ParserVersion,
-		&p.CreatedAt, &p.UpdatedAt)
+		&p.CreatedAt, &p.UpdatedAt,
+		&p.RoleTitleNormalized, &p.RoleFamily, &p.RoleLevel)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -372,14 +409,16 @@ func (db *DB) GetJobProfileByID(ctx context.Context, id uuid.UUID) (*JobProfile,
 		        eval_latency, eval_reliability, eval_ownership, eval_scale, eval_collaboration,
 		        eval_signals_raw, education_min_degree, education_preferred_fields,
 		        education_is_required, education_evidence, parsed_at, parser_version,
-		        created_at, updated_at
+		        created_at, updated_at,
+		        COALESCE(role_title_normalized, ''), COALESCE(role_family, ''), COALESCE(role_level, '')
 		 FROM job_profiles WHERE id = $1`,
 		id,
 	).Scan(&p.ID, &p.PostingID, &p.CompanyName, &p.RoleTitle,
 		&p.EvalLatency, &p.EvalReliability, &p.EvalOwnership, &p.EvalScale, &p.EvalCollaboration,
 		&evalSignalsJSON, &p.EducationMinDegree, &eduFieldsJSON,
 		&p.EducationIsRequired, &p.EducationEvidence, &p.ParsedAt, &p.ParserVersion,
-		&p.CreatedAt, &p.UpdatedAt)
+		&p.CreatedAt, &p.UpdatedAt,
+		&p.RoleTitleNormalized, &p.RoleFamily, &p.RoleLevel)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, nil
@@ -424,13 +463,15 @@ func (db *DB) CreateJobProfile(ctx context.Context, input *JobProfileCreateInput
 	}
 
 	// Insert or update profile
+	role := normalizeRole(input.RoleTitle, input.CompanyName)
 	var p JobProfile
 	err = tx.QueryRow(ctx,
 		`INSERT INTO job_profiles (posting_id, company_name, role_title,
 		                           eval_latency, eval_reliability, eval_ownership, eval_scale, eval_collaboration,
 		                           eval_signals_raw, education_min_degree, education_preferred_fields,
-		                           education_is_required, education_evidence, parser_version, parsed_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW())
+		                           education_is_required, education_evidence, parser_version, parsed_at,
+		                           role_title_normalized, role_family, role_level)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW(), $15, $16, $17)
 		 ON CONFLICT (posting_id) DO UPDATE SET
 		     company_name = $2,
 		     role_title = $3,
@@ -446,21 +487,26 @@ func (db *DB) CreateJobProfile(ctx context.Context, input *JobProfileCreateInput
 		     education_evidence = $13,
 		     parser_version = $14,
 		     parsed_at = NOW(),
+		     role_title_normalized = $15,
+		     role_family = $16,
+		     role_level = $17,
 		     updated_at = NOW()
 		 RETURNING id, posting_id, company_name, role_title, parsed_at, created_at, updated_at`,
 		input.PostingID, input.CompanyName, input.RoleTitle,
 		input.EvalLatency, input.EvalReliability, input.EvalOwnership, input.EvalScale, input.EvalCollaboration,
 		evalSignalsJSON, nullIfEmpty(input.EducationMinDegree), eduFieldsJSON,
 		input.EducationIsRequired, nullIfEmpty(input.EducationEvidence), nullIfEmpty(input.ParserVersion),
+		nullIfEmpty(role.Title), nullIfEmpty(role.Family), nullIfEmpty(role.Level),
 	).Scan(&p.ID, &p.PostingID, &p.CompanyName, &p.RoleTitle, &p.ParsedAt, &p.CreatedAt, &p.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create job profile: %w", err)
 	}
+	p.RoleTitleNormalized, p.RoleFamily, p.RoleLevel = role.Title, role.Family, role.Level
 
 	// Clear existing related data (for upsert)
 	_, _ = tx.Exec(ctx, "DELETE FROM job_responsibilities WHERE job_profile_id = $1", p.ID)
 	_, _ = tx.Exec(ctx, "DELETE FROM job_requirements WHERE job_profile_id = $1", p.ID)
-	_, _ = tx.Exec(ctx, "DELETE FROM job_keywords WHERE job_profile_id = $1", p.ID)
+	_, _ = tx.Exec(ctx, "DELETE FROM job_keywords WHERE job_profile_id = $1 AND user_id IS NULL", p.ID)
 
 	// Insert responsibilities
 	for i, resp := range input.Responsibilities {
@@ -498,12 +544,12 @@ func (db *DB) CreateJobProfile(ctx context.Context, input *JobProfileCreateInput
 		}
 	}
 
-	// Insert keywords
+	// Insert keywords; keywords users added or hid are kept
 	for _, kw := range input.Keywords {
 		_, err = tx.Exec(ctx,
-			`INSERT INTO job_keywords (job_profile_id, keyword, keyword_normalized)
-			 VALUES ($1, $2, $3)`,
-			p.ID, kw, NormalizeKeyword(kw),
+			`INSERT INTO job_keywords (job_profile_id, keyword, keyword_normalized, source)
+			 VALUES ($1, $2, $3, $4)`,
+			p.ID, kw, NormalizeKeyword(kw), KeywordSourceLLM,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert keyword: %w", err)
@@ -586,7 +632,7 @@ func (db *DB) loadJobProfileRelations(ctx context.Context, p *JobProfile) error
 
 	// Load keywords
 	rows, err = db.pool.Query(ctx,
-		`SELECT keyword FROM job_keywords WHERE job_profile_id = $1`,
+		`SELECT keyword FROM job_keywords WHERE job_profile_id = $1 AND user_id IS NULL`,
 		p.ID,
 	)
 	if err != nil {
@@ -666,12 +712,12 @@ func (db *DB) GetResponsibilitiesByProfileID(ctx context.Context, profileID uuid
 	return responsibilities, nil
 }
 
-// GetKeywordsByProfileID retrieves all keywords for a job profile
+// GetKeywordsByProfileID retrieves the keywords shared by everyone who sees a job profile
 func (db *DB) GetKeywordsByProfileID(ctx context.Context, profileID uuid.UUID) ([]JobKeyword, error) {
 	rows, err := db.pool.Query(ctx,
 		`SELECT id, job_profile_id, keyword, keyword_normalized, source, created_at
 		 FROM job_keywords
-		 WHERE job_profile_id = $1
+		 WHERE job_profile_id = $1 AND user_id IS NULL
 		 ORDER BY keyword`,
 		profileID,
 	)
@@ -737,7 +783,7 @@ func (db *DB) FindJobsByKeyword(ctx context.Context, keyword string) ([]JobProfi
 		        jp.parsed_at, jp.created_at
 		 FROM job_profiles jp
 		 JOIN job_keywords jk ON jk.job_profile_id = jp.id
-		 WHERE jk.keyword_normalized = $1
+		 WHERE jk.keyword_normalized = $1 AND jk.user_id IS NULL
 		 ORDER BY jp.created_at DESC`,
 		normalized,
 	)
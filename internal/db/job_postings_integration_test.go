@@ -739,3 +739,73 @@ func TestIntegration_JobPosting_WithAdminInfo(t *testing.T) {
 		t.Errorf("ExtractedLinks count = %d, want 2", len(retrieved.ExtractedLinks))
 	}
 }
+
+func TestIntegration_JobPosting_LinkDuplicate(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	company, err := db.FindOrCreateCompany(ctx, "Duplicate Posting Test Corp")
+	if err != nil {
+		t.Fatalf("Failed to create company: %v", err)
+	}
+	defer cleanupCompany(t, db, company.ID)
+
+	upsert := func(url, text string) *JobPosting {
+		posting, err := db.UpsertJobPosting(ctx, &JobPostingCreateInput{
+			URL:         url + uuid.New().String(),
+			CompanyID:   &company.ID,
+			CleanedText: text,
+			HTTPStatus:  200,
+		})
+		if err != nil {
+			t.Fatalf("UpsertJobPosting failed: %v", err)
+		}
+		return posting
+	}
+
+	canonical := upsert("https://boards.greenhouse.io/dupcorp/jobs/", testPostingDescription)
+	if dup, err := db.LinkDuplicatePosting(ctx, canonical, testPostingDescription); err != nil || dup != nil {
+		t.Fatalf("first posting: dup = %+v, err = %v; want canonical", dup, err)
+	}
+
+	t.Run("content hash match", func(t *testing.T) {
+		text := "SENIOR BACKEND ENGINEER — PAYMENTS\n" + testPostingDescription[len("Senior Backend Engineer, Payments."):]
+		posting := upsert("https://dupcorp.com/careers/", text)
+		dup, err := db.LinkDuplicatePosting(ctx, posting, text)
+		if err != nil {
+			t.Fatalf("LinkDuplicatePosting failed: %v", err)
+		}
+		if dup == nil || dup.CanonicalID != canonical.ID || dup.Match != DuplicateMatchContentHash {
+			t.Fatalf("dup = %+v, want content hash match of %s", dup, canonical.ID)
+		}
+	})
+
+	t.Run("fuzzy match", func(t *testing.T) {
+		text := "Easy Apply. " + testPostingDescription + " Show less."
+		posting := upsert("https://www.linkedin.com/jobs/view/", text)
+		dup, err := db.LinkDuplicatePosting(ctx, posting, text)
+		if err != nil {
+			t.Fatalf("LinkDuplicatePosting failed: %v", err)
+		}
+		if dup == nil || dup.CanonicalID != canonical.ID || dup.Match != DuplicateMatchFuzzy {
+			t.Fatalf("dup = %+v, want fuzzy match of %s", dup, canonical.ID)
+		}
+
+		stored, err := db.GetJobPostingByID(ctx, posting.ID)
+		if err != nil {
+			t.Fatalf("GetJobPostingByID failed: %v", err)
+		}
+		if stored.CanonicalID() != canonical.ID {
+			t.Errorf("CanonicalID() = %s, want %s", stored.CanonicalID(), canonical.ID)
+		}
+	})
+
+	duplicates, err := db.ListDuplicatePostings(ctx, canonical.ID)
+	if err != nil {
+		t.Fatalf("ListDuplicatePostings failed: %v", err)
+	}
+	if len(duplicates) != 2 {
+		t.Errorf("len(duplicates) = %d, want 2", len(duplicates))
+	}
+}
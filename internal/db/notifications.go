@@ -0,0 +1,226 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// digestRecentRuns is how many completed runs a digest lists
+const digestRecentRuns = 10
+
+// targetedCompaniesCTE selects the companies a user ($1) has run the pipeline against
+const targetedCompaniesCTE = `WITH targeted AS (
+	SELECT DISTINCT c.id
+	FROM companies c
+	JOIN pipeline_runs r ON c.name_normalized = lower(regexp_replace(r.company, '[^a-zA-Z0-9]', '', 'g'))
+	WHERE r.user_id = $1
+)`
+
+// ValidateNotificationPreference checks the event type, channel, and webhook URL of a preference
+func ValidateNotificationPreference(input *NotificationPreferenceInput) error {
+	switch input.EventType {
+	case NotificationRunCompleted, NotificationWeeklyDigest, NotificationReminder, NotificationResumeHealth:
+	default:
+		return fmt.Errorf("invalid notification event type %q", input.EventType)
+	}
+	switch input.Channel {
+	case NotificationEmail, NotificationNone:
+	case NotificationWebhook:
+		u, err := url.Parse(input.WebhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("webhook_url must be an https URL for the webhook channel")
+		}
+	default:
+		return fmt.Errorf("invalid notification channel %q", input.Channel)
+	}
+	return nil
+}
+
+// DefaultNotificationPreferences fills in 'none' for event types without a stored preference
+func DefaultNotificationPreferences(userID uuid.UUID, stored []NotificationPreference) []NotificationPreference {
+	byType := make(map[string]NotificationPreference, len(stored))
+	for _, p := range stored {
+		byType[p.EventType] = p
+	}
+	prefs := make([]NotificationPreference, 0, len(NotificationEventTypes))
+	for _, eventType := range NotificationEventTypes {
+		p, ok := byType[eventType]
+		if !ok {
+			p = NotificationPreference{UserID: userID, EventType: eventType, Channel: NotificationNone}
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs
+}
+
+// UpsertNotificationPreference sets how a user is notified about an event type
+func (db *DB) UpsertNotificationPreference(ctx context.Context, input *NotificationPreferenceInput) (*NotificationPreference, error) {
+	if err := ValidateNotificationPreference(input); err != nil {
+		return nil, err
+	}
+	var webhookURL *string
+	if input.Channel == NotificationWebhook {
+		webhookURL = &input.WebhookURL
+	}
+
+	var p NotificationPreference
+	err := db.pool.QueryRow(ctx,
+		`INSERT INTO notification_preferences (user_id, event_type, channel, webhook_url)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (user_id, event_type)
+		 DO UPDATE SET channel = EXCLUDED.channel, webhook_url = EXCLUDED.webhook_url, updated_at = NOW()
+		 RETURNING user_id, event_type, channel, webhook_url, last_sent_at, updated_at`,
+		input.UserID, input.EventType, input.Channel, webhookURL,
+	).Scan(&p.UserID, &p.EventType, &p.Channel, &p.WebhookURL, &p.LastSentAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save notification preference: %w", err)
+	}
+	return &p, nil
+}
+
+// ListNotificationPreferences returns a user's stored notification preferences
+func (db *DB) ListNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT user_id, event_type, channel, webhook_url, last_sent_at, updated_at
+		 FROM notification_preferences
+		 WHERE user_id = $1
+		 ORDER BY event_type`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	var prefs []NotificationPreference
+	for rows.Next() {
+		var p NotificationPreference
+		if err := rows.Scan(&p.UserID, &p.EventType, &p.Channel, &p.WebhookURL, &p.LastSentAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification preference: %w", err)
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs, rows.Err()
+}
+
+// GetNotificationRecipient returns where to notify a user about an event type,
+// or nil when the user has not opted in
+func (db *DB) GetNotificationRecipient(ctx context.Context, userID uuid.UUID, eventType string) (*NotificationRecipient, error) {
+	recipients, err := db.queryRecipients(ctx,
+		`SELECT u.id, u.name, u.email, u.timezone, p.channel, p.webhook_url, p.last_sent_at
+		 FROM notification_preferences p
+		 JOIN users u ON u.id = p.user_id
+		 WHERE p.user_id = $1 AND p.event_type = $2 AND p.channel <> 'none'`,
+		userID, eventType,
+	)
+	if err != nil || len(recipients) == 0 {
+		return nil, err
+	}
+	return &recipients[0], nil
+}
+
+// ListDueDigestRecipients returns users opted in to a periodic event type (the weekly
+// digest or resume health report) whose last one was sent at least interval ago (or never)
+func (db *DB) ListDueDigestRecipients(ctx context.Context, eventType string, interval time.Duration) ([]NotificationRecipient, error) {
+	return db.queryRecipients(ctx,
+		`SELECT u.id, u.name, u.email, u.timezone, p.channel, p.webhook_url, p.last_sent_at
+		 FROM notification_preferences p
+		 JOIN users u ON u.id = p.user_id
+		 WHERE p.event_type = $1 AND p.channel <> 'none'
+		   AND (p.last_sent_at IS NULL OR p.last_sent_at <= $2)
+		 ORDER BY p.last_sent_at NULLS FIRST`,
+		eventType, time.Now().Add(-interval),
+	)
+}
+
+// queryRecipients runs a recipient query
+func (db *DB) queryRecipients(ctx context.Context, query string, args ...any) ([]NotificationRecipient, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []NotificationRecipient
+	for rows.Next() {
+		var r NotificationRecipient
+		if err := rows.Scan(&r.UserID, &r.Name, &r.Email, &r.Timezone, &r.Channel, &r.WebhookURL, &r.LastSentAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notification recipient: %w", err)
+		}
+		recipients = append(recipients, r)
+	}
+	return recipients, rows.Err()
+}
+
+// ClaimDigest marks the user's digest or report of eventType as sent at the given time
+// if it is still due. It returns false when another replica already claimed it.
+func (db *DB) ClaimDigest(ctx context.Context, eventType string, userID uuid.UUID, interval time.Duration, at time.Time) (bool, error) {
+	tag, err := db.pool.Exec(ctx,
+		`UPDATE notification_preferences SET last_sent_at = $3
+		 WHERE user_id = $1 AND event_type = $2 AND (last_sent_at IS NULL OR last_sent_at <= $4)`,
+		userID, eventType, at, at.Add(-interval),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ClaimNotification records a notification about refID (a run, or an application
+// tracker for next action reminders) under a delivery key.
+// It returns false when the notification was already claimed, so each is sent once.
+func (db *DB) ClaimNotification(ctx context.Context, eventType string, refID, userID uuid.UUID) (bool, error) {
+	tag, err := db.pool.Exec(ctx,
+		`INSERT INTO notification_deliveries (event_type, ref_id, user_id)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (event_type, ref_id) DO NOTHING`,
+		eventType, refID, userID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim notification: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetDigestStats summarizes a user's completed runs, and new postings and profile
+// refreshes at the companies they targeted, since the given time
+func (db *DB) GetDigestStats(ctx context.Context, userID uuid.UUID, since time.Time) (*DigestStats, error) {
+	stats := &DigestStats{Since: since, RecentRuns: []DigestRun{}}
+
+	err := db.pool.QueryRow(ctx,
+		targetedCompaniesCTE+`
+		 SELECT
+		   (SELECT COUNT(*) FROM pipeline_runs WHERE user_id = $1 AND status = 'completed' AND completed_at >= $2),
+		   (SELECT COUNT(*) FROM company_profiles WHERE company_id IN (SELECT id FROM targeted) AND updated_at >= $2),
+		   (SELECT COUNT(*) FROM job_postings WHERE company_id IN (SELECT id FROM targeted) AND created_at >= $2)`,
+		userID, since,
+	).Scan(&stats.RunsCompleted, &stats.ProfilesRefreshed, &stats.PostingsMatched)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest stats: %w", err)
+	}
+
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, COALESCE(company, ''), COALESCE(role_title, ''), completed_at
+		 FROM pipeline_runs
+		 WHERE user_id = $1 AND status = 'completed' AND completed_at >= $2
+		 ORDER BY completed_at DESC
+		 LIMIT $3`,
+		userID, since, digestRecentRuns,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list digest runs: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var run DigestRun
+		if err := rows.Scan(&run.RunID, &run.Company, &run.RoleTitle, &run.CompletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan digest run: %w", err)
+		}
+		stats.RecentRuns = append(stats.RecentRuns, run)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateNotificationPreference(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   NotificationPreferenceInput
+		wantErr bool
+	}{
+		{name: "email digest", input: NotificationPreferenceInput{EventType: NotificationWeeklyDigest, Channel: NotificationEmail}},
+		{name: "none", input: NotificationPreferenceInput{EventType: NotificationRunCompleted, Channel: NotificationNone}},
+		{name: "webhook", input: NotificationPreferenceInput{EventType: NotificationRunCompleted, Channel: NotificationWebhook, WebhookURL: "https://hooks.example.com/x"}},
+		{name: "webhook without url", input: NotificationPreferenceInput{EventType: NotificationRunCompleted, Channel: NotificationWebhook}, wantErr: true},
+		{name: "webhook bad scheme", input: NotificationPreferenceInput{EventType: NotificationRunCompleted, Channel: NotificationWebhook, WebhookURL: "ftp://example.com"}, wantErr: true},
+		{name: "webhook plain http", input: NotificationPreferenceInput{EventType: NotificationRunCompleted, Channel: NotificationWebhook, WebhookURL: "http://hooks.example.com/x"}, wantErr: true},
+		{name: "unknown event", input: NotificationPreferenceInput{EventType: "daily_digest", Channel: NotificationEmail}, wantErr: true},
+		{name: "unknown channel", input: NotificationPreferenceInput{EventType: NotificationWeeklyDigest, Channel: "sms"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateNotificationPreference(&tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ValidateNotificationPreference() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDefaultNotificationPreferences(t *testing.T) {
+	userID := uuid.New()
+	stored := []NotificationPreference{{UserID: userID, EventType: NotificationWeeklyDigest, Channel: NotificationEmail}}
+
+	prefs := DefaultNotificationPreferences(userID, stored)
+	if len(prefs) != len(NotificationEventTypes) {
+		t.Fatalf("got %d preferences, want %d", len(prefs), len(NotificationEventTypes))
+	}
+	if prefs[0].EventType != NotificationRunCompleted || prefs[0].Channel != NotificationNone {
+		t.Errorf("run_completed preference = %+v, want channel none", prefs[0])
+	}
+	if prefs[1].EventType != NotificationWeeklyDigest || prefs[1].Channel != NotificationEmail {
+		t.Errorf("weekly_digest preference = %+v, want channel email", prefs[1])
+	}
+	if prefs[2].EventType != NotificationReminder || prefs[2].Channel != NotificationNone {
+		t.Errorf("application_reminder preference = %+v, want channel none", prefs[2])
+	}
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// listenerBuffer is how many undelivered notifications a Listener holds before dropping.
+// Consumers are expected to re-check state periodically, so drops only add latency.
+const listenerBuffer = 64
+
+// Notify sends a Postgres NOTIFY on channel with payload
+func (db *DB) Notify(ctx context.Context, channel, payload string) error {
+	if _, err := db.pool.Exec(ctx, `SELECT pg_notify($1, $2)`, channel, payload); err != nil {
+		return fmt.Errorf("failed to notify %s: %w", channel, err)
+	}
+	return nil
+}
+
+// Listener receives Postgres notifications for a single channel on a dedicated connection
+type Listener struct {
+	// C delivers notification payloads; it is closed when the listener stops
+	C <-chan string
+
+	conn   *pgxpool.Conn
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Listen subscribes to channel. The listener holds a pooled connection until Close
+// is called or ctx is canceled.
+func (db *DB) Listen(ctx context.Context, channel string) (*Listener, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire listener connection: %w", err)
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan string, listenerBuffer)
+	l := &Listener{C: ch, conn: conn, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(l.done)
+		defer close(ch)
+		for {
+			n, err := conn.Conn().WaitForNotification(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case ch <- n.Payload:
+			default: // Drop rather than block the connection
+			}
+		}
+	}()
+
+	return l, nil
+}
+
+// Close stops the listener and returns its connection to the pool
+func (l *Listener) Close() {
+	if l == nil {
+		return
+	}
+	l.cancel()
+	<-l.done
+	// An interrupted wait leaves the connection unusable; closing it makes the pool discard it
+	_ = l.conn.Conn().Close(context.Background())
+	l.conn.Release()
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const onboardingColumns = `user_id, state, skipped, updated_at, completed_at`
+
+func scanOnboarding(row pgx.Row) (*Onboarding, error) {
+	var o Onboarding
+	if err := row.Scan(&o.UserID, &o.State, &o.Skipped, &o.UpdatedAt, &o.CompletedAt); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// GetOnboarding returns the user's onboarding progress, or nil if they have not started
+func (db *DB) GetOnboarding(ctx context.Context, userID uuid.UUID) (*Onboarding, error) {
+	o, err := scanOnboarding(db.pool.QueryRow(ctx,
+		`SELECT `+onboardingColumns+` FROM user_onboarding WHERE user_id = $1`, userID,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get onboarding: %w", err)
+	}
+	return o, nil
+}
+
+// TransitionOnboarding moves the user's onboarding from one state to another, recording
+// the steps skipped so far and, if finished, when the wizard was completed. It returns
+// nil if the state is no longer from, so concurrent transitions cannot both apply. A user
+// without progress is simply moved to the new state; callers pass the initial state as
+// from for them.
+func (db *DB) TransitionOnboarding(ctx context.Context, userID uuid.UUID, from, to string, skipped []string, finished bool) (*Onboarding, error) {
+	o, err := scanOnboarding(db.pool.QueryRow(ctx,
+		`INSERT INTO user_onboarding (user_id, state, skipped, completed_at)
+		 VALUES ($1, $3, $4, CASE WHEN $5 THEN NOW() END)
+		 ON CONFLICT (user_id) DO UPDATE SET
+		     state = EXCLUDED.state,
+		     skipped = EXCLUDED.skipped,
+		     completed_at = EXCLUDED.completed_at,
+		     updated_at = NOW()
+		 WHERE user_onboarding.state = $2
+		 RETURNING `+onboardingColumns,
+		userID, from, to, StringArray(skipped), finished,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update onboarding: %w", err)
+	}
+	return o, nil
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// SortKey is a column a list can be sorted by
+type SortKey struct {
+	Column string // SQL expression, qualified as the list's query needs; never NULL
+	Type   string // SQL type cursor values are cast to
+}
+
+// SortKeys maps the names a list accepts in its sort parameter to their columns
+type SortKeys map[string]SortKey
+
+// Sort keys of the paginated lists
+var (
+	RunSortKeys = SortKeys{
+		"created_at": {Column: "created_at", Type: "timestamptz"},
+		"company":    {Column: "company", Type: "text"},
+		"role_title": {Column: "role_title", Type: "text"},
+		"status":     {Column: "status", Type: "text"},
+	}
+	StorySortKeys = SortKeys{
+		"created_at": {Column: "s.created_at", Type: "timestamptz"},
+		"updated_at": {Column: "s.updated_at", Type: "timestamptz"},
+		"title":      {Column: "COALESCE(s.title, '')", Type: "text"},
+	}
+	SkillSortKeys = SortKeys{
+		"name":       {Column: "s.name_normalized", Type: "text"},
+		"created_at": {Column: "s.created_at", Type: "timestamptz"},
+	}
+	CompanySortKeys = SortKeys{
+		"name":       {Column: "c.name", Type: "text"},
+		"created_at": {Column: "c.created_at", Type: "timestamptz"},
+	}
+	JobPostingSortKeys = SortKeys{
+		"created_at": {Column: "created_at", Type: "timestamptz"},
+		"fetched_at": {Column: "fetched_at", Type: "timestamptz"},
+	}
+)
+
+// Cursor is a row's position in a sorted list: its sort value, as text Postgres can
+// cast to the sort key's type, and its ID, which breaks ties
+type Cursor struct {
+	Value string
+	ID    uuid.UUID
+}
+
+// Page orders a list by a sort key, then by ID, and starts it after a cursor. A zero
+// Page leaves the list in its usual order.
+type Page struct {
+	Sort  string  // Name in the list's SortKeys
+	Desc  bool    // Descending order
+	After *Cursor // Last row of the previous page; nil for the first page
+}
+
+// pageClause returns the condition that starts page after its cursor ("" on the first
+// page) and the ORDER BY for page, over keys, with idColumn breaking ties. Arguments are
+// numbered from argNum.
+func pageClause(page Page, keys SortKeys, idColumn string, argNum int) (cond, orderBy string, args []any, err error) {
+	key, ok := keys[page.Sort]
+	if !ok {
+		return "", "", nil, fmt.Errorf("unknown sort key %q", page.Sort)
+	}
+	dir, cmp := "ASC", ">"
+	if page.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	orderBy = fmt.Sprintf(" ORDER BY %s %s, %s %s", key.Column, dir, idColumn, dir)
+	if page.After == nil {
+		return "", orderBy, nil, nil
+	}
+	cond = fmt.Sprintf("(%s, %s) %s ($%d::text::%s, $%d)", key.Column, idColumn, cmp, argNum, key.Type, argNum+1)
+	return cond, orderBy, []any{page.After.Value, page.After.ID}, nil
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPageClause(t *testing.T) {
+	cond, orderBy, args, err := pageClause(Page{Sort: "name"}, CompanySortKeys, "c.id", 1)
+	if err != nil {
+		t.Fatalf("pageClause() error = %v", err)
+	}
+	if cond != "" || len(args) != 0 {
+		t.Errorf("first page: cond = %q, args = %v, want none", cond, args)
+	}
+	if want := " ORDER BY c.name ASC, c.id ASC"; orderBy != want {
+		t.Errorf("orderBy = %q, want %q", orderBy, want)
+	}
+
+	id := uuid.New()
+	cond, orderBy, args, err = pageClause(Page{Sort: "created_at", Desc: true, After: &Cursor{Value: "2026-03-10T17:00:00Z", ID: id}}, RunSortKeys, "id", 3)
+	if err != nil {
+		t.Fatalf("pageClause() error = %v", err)
+	}
+	if want := "(created_at, id) < ($3::text::timestamptz, $4)"; cond != want {
+		t.Errorf("cond = %q, want %q", cond, want)
+	}
+	if want := " ORDER BY created_at DESC, id DESC"; orderBy != want {
+		t.Errorf("orderBy = %q, want %q", orderBy, want)
+	}
+	if len(args) != 2 || args[0] != "2026-03-10T17:00:00Z" || args[1] != id {
+		t.Errorf("args = %v", args)
+	}
+
+	if _, _, _, err := pageClause(Page{Sort: "priority"}, RunSortKeys, "id", 1); err == nil {
+		t.Error("pageClause() accepted an unknown sort key")
+	}
+}
@@ -558,6 +558,58 @@ func TestIntegration_CascadeDelete(t *testing.T) {
 	}
 }
 
+func TestIntegration_ArtifactBlobs_Deduplicate(t *testing.T) {
+	db := getTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	first, second := createTestRun(t, db, ctx), createTestRun(t, db, ctx)
+	defer cleanupTestRun(t, db, second)
+	text := "Senior Engineer " + uuid.NewString()
+	hash := artifactBlobHash(ContentFormatText, []byte(text))
+
+	for _, runID := range []uuid.UUID{first, second} {
+		if err := db.SaveTextArtifact(ctx, runID, "job_posting", "ingestion", text); err != nil {
+			t.Fatalf("Failed to save artifact: %v", err)
+		}
+	}
+	refCount := func() int {
+		var n int
+		if err := db.pool.QueryRow(ctx, "SELECT ref_count FROM artifact_blobs WHERE hash = $1", hash).Scan(&n); err != nil {
+			t.Fatalf("Failed to get blob: %v", err)
+		}
+		return n
+	}
+	if n := refCount(); n != 2 {
+		t.Errorf("Expected both runs to share one blob, got ref_count %d", n)
+	}
+	got, err := db.GetTextArtifact(ctx, second, "job_posting")
+	if err != nil || got != text {
+		t.Fatalf("Expected the shared text back, got %q (%v)", got, err)
+	}
+
+	// Overwriting and deleting release the blob
+	if err := db.SaveTextArtifact(ctx, second, "job_posting", "ingestion", text+" (edited)"); err != nil {
+		t.Fatalf("Failed to overwrite artifact: %v", err)
+	}
+	cleanupTestRun(t, db, first)
+	if n := refCount(); n != 0 {
+		t.Errorf("Expected the blob to be unreferenced, got ref_count %d", n)
+	}
+
+	if _, err := db.DeleteUnreferencedArtifactBlobs(ctx, 0); err != nil {
+		t.Fatalf("Failed to delete blobs: %v", err)
+	}
+	var exists bool
+	_ = db.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM artifact_blobs WHERE hash = $1)", hash).Scan(&exists)
+	if exists {
+		t.Error("Unreferenced blob should be deleted")
+	}
+	if got, _ := db.GetTextArtifact(ctx, second, "job_posting"); got != text+" (edited)" {
+		t.Errorf("Referenced blob should be kept, got %q", got)
+	}
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// promptRolloutColumns lists the columns scanned by scanPromptRollout, in order
+const promptRolloutColumns = `file, prompt_key, version, previous_version, state, share, window_size, tolerance,
+	previous_samples, previous_parse_failures, previous_violations,
+	next_samples, next_parse_failures, next_violations, started_at, decided_at`
+
+// scanPromptRollout scans a row selected with promptRolloutColumns
+func scanPromptRollout(row pgx.Row) (*PromptRollout, error) {
+	var r PromptRollout
+	err := row.Scan(&r.File, &r.PromptKey, &r.Version, &r.PreviousVersion, &r.State, &r.Share, &r.WindowSize, &r.Tolerance,
+		&r.PreviousSamples, &r.PreviousParseFailures, &r.PreviousViolations,
+		&r.NextSamples, &r.NextParseFailures, &r.NextViolations, &r.StartedAt, &r.DecidedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Conditions under which a reported outcome counts toward a rollout: it is still
+// trialing and the version that served the call ($4) has room left in its window
+const (
+	countsForPrevious = `(state = 'trialing' AND $4 = previous_version AND previous_samples < window_size)`
+	countsForNext     = `(state = 'trialing' AND $4 = version AND next_samples < window_size)`
+)
+
+// StartPromptRollout records a prompt rollout, or returns the one already recorded for
+// the same file, key, and version with its settings updated. Its state and outcomes are
+// kept, so a rollout that was decided stays decided.
+func (db *DB) StartPromptRollout(ctx context.Context, input *PromptRolloutInput) (*PromptRollout, error) {
+	r, err := scanPromptRollout(db.pool.QueryRow(ctx,
+		`INSERT INTO prompt_rollouts (file, prompt_key, version, previous_version, share, window_size, tolerance)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (file, prompt_key, version) DO UPDATE
+		 SET share = EXCLUDED.share, window_size = EXCLUDED.window_size, tolerance = EXCLUDED.tolerance
+		 RETURNING `+promptRolloutColumns,
+		input.File, input.PromptKey, input.Version, input.PreviousVersion, input.Share, input.WindowSize, input.Tolerance,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start prompt rollout: %w", err)
+	}
+	return r, nil
+}
+
+// GetPromptRollout returns the rollout of a prompt version, or nil if it was never started
+func (db *DB) GetPromptRollout(ctx context.Context, file, key string, version int) (*PromptRollout, error) {
+	r, err := scanPromptRollout(db.pool.QueryRow(ctx,
+		`SELECT `+promptRolloutColumns+` FROM prompt_rollouts
+		 WHERE file = $1 AND prompt_key = $2 AND version = $3`,
+		file, key, version,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get prompt rollout: %w", err)
+	}
+	return r, nil
+}
+
+// GetPromotedPromptVersion returns the newest version of a prompt a rollout promoted,
+// or 1 when none has been
+func (db *DB) GetPromotedPromptVersion(ctx context.Context, file, key string) (int, error) {
+	var version int
+	err := db.pool.QueryRow(ctx,
+		`SELECT COALESCE(MAX(version), 1) FROM prompt_rollouts
+		 WHERE file = $1 AND prompt_key = $2 AND state = $3`,
+		file, key, PromptRolloutPromoted,
+	).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get promoted prompt version: %w", err)
+	}
+	return version, nil
+}
+
+// RecordPromptRolloutOutcome counts the outcome of a call servedVersion answered toward
+// the rollout of version, and returns the rollout. Outcomes after the version's window
+// is full, or after the rollout is decided, are not counted.
+func (db *DB) RecordPromptRolloutOutcome(ctx context.Context, file, key string, version, servedVersion int, outcome string) (*PromptRollout, error) {
+	r, err := scanPromptRollout(db.pool.QueryRow(ctx,
+		`UPDATE prompt_rollouts SET
+		     previous_samples = previous_samples + `+countsForPrevious+`::int,
+		     previous_parse_failures = previous_parse_failures + (`+countsForPrevious+` AND $5 = '`+PromptOutcomeParseFailure+`')::int,
+		     previous_violations = previous_violations + (`+countsForPrevious+` AND $5 = '`+PromptOutcomeViolation+`')::int,
+		     next_samples = next_samples + `+countsForNext+`::int,
+		     next_parse_failures = next_parse_failures + (`+countsForNext+` AND $5 = '`+PromptOutcomeParseFailure+`')::int,
+		     next_violations = next_violations + (`+countsForNext+` AND $5 = '`+PromptOutcomeViolation+`')::int
+		 WHERE file = $1 AND prompt_key = $2 AND version = $3
+		 RETURNING `+promptRolloutColumns,
+		file, key, version, servedVersion, outcome,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record prompt rollout outcome: %w", err)
+	}
+	return r, nil
+}
+
+// DecidePromptRollout promotes or rolls back a rollout whose versions both have a full
+// window. Only the first server to decide it gets true, so only one raises an alert.
+func (db *DB) DecidePromptRollout(ctx context.Context, file, key string, version int, state string) (bool, error) {
+	tag, err := db.pool.Exec(ctx,
+		`UPDATE prompt_rollouts SET state = $4, decided_at = NOW()
+		 WHERE file = $1 AND prompt_key = $2 AND version = $3 AND state = $5
+		   AND previous_samples >= window_size AND next_samples >= window_size`,
+		file, key, version, state, PromptRolloutTrialing,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to decide prompt rollout: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
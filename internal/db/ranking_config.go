@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetUserRankingConfig returns the story ranking weights (ranking.Weights) a user saved for
+// their runs, or nil if they saved none
+func (db *DB) GetUserRankingConfig(ctx context.Context, userID uuid.UUID) (json.RawMessage, error) {
+	var config []byte
+	err := db.pool.QueryRow(ctx,
+		`SELECT ranking_config FROM users WHERE id = $1`,
+		userID,
+	).Scan(&config)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ranking config: %w", err)
+	}
+	if len(config) == 0 {
+		return nil, nil
+	}
+	return config, nil
+}
+
+// SetUserRankingConfig saves a user's story ranking weights; nil clears them
+func (db *DB) SetUserRankingConfig(ctx context.Context, userID uuid.UUID, config json.RawMessage) error {
+	var value []byte
+	if len(config) > 0 {
+		value = config
+	}
+	_, err := db.pool.Exec(ctx,
+		`UPDATE users SET ranking_config = $1, updated_at = NOW() WHERE id = $2`,
+		value, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update ranking config: %w", err)
+	}
+	return nil
+}
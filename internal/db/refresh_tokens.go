@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// -----------------------------------------------------------------------------
+// Refresh Token Methods
+// -----------------------------------------------------------------------------
+
+const refreshTokenColumns = `id, user_id, family_id, token_hash, expires_at, revoked_at, replaced_by, created_at`
+
+// scanRefreshToken scans a refresh_tokens row selected with refreshTokenColumns
+func scanRefreshToken(row pgx.Row) (*RefreshToken, error) {
+	var t RefreshToken
+	if err := row.Scan(&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt,
+		&t.RevokedAt, &t.ReplacedBy, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateRefreshToken stores a refresh token that starts a new family, as issued at login
+func (db *DB) CreateRefreshToken(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) (*RefreshToken, error) {
+	row := db.pool.QueryRow(ctx,
+		`INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		 VALUES ($1, gen_random_uuid(), $2, $3)
+		 RETURNING `+refreshTokenColumns,
+		userID, tokenHash, expiresAt,
+	)
+	t, err := scanRefreshToken(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	return t, nil
+}
+
+// RotateRefreshToken exchanges the token with hash tokenHash for a new token in the
+// same family. Each token is accepted once: presenting one that was already rotated
+// or revoked means it leaked, so the whole family is revoked and Reused is set.
+// Unknown and expired tokens are rejected without side effects.
+func (db *DB) RotateRefreshToken(ctx context.Context, tokenHash, newTokenHash string, expiresAt time.Time) (*RefreshTokenRotation, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	current, err := scanRefreshToken(tx.QueryRow(ctx,
+		`SELECT `+refreshTokenColumns+` FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`,
+		tokenHash,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return &RefreshTokenRotation{}, nil
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if current.RevokedAt != nil {
+		if _, err := tx.Exec(ctx,
+			`UPDATE refresh_tokens SET revoked_at = NOW()
+			 WHERE family_id = $1 AND revoked_at IS NULL`,
+			current.FamilyID,
+		); err != nil {
+			return nil, fmt.Errorf("failed to revoke refresh token family: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return &RefreshTokenRotation{Reused: true}, nil
+	}
+	if !current.ExpiresAt.After(time.Now()) {
+		return &RefreshTokenRotation{}, nil
+	}
+
+	next, err := scanRefreshToken(tx.QueryRow(ctx,
+		`INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+refreshTokenColumns,
+		current.UserID, current.FamilyID, newTokenHash, expiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW(), replaced_by = $2 WHERE id = $1`,
+		current.ID, next.ID,
+	); err != nil {
+		return nil, fmt.Errorf("failed to revoke rotated refresh token: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &RefreshTokenRotation{Token: next}, nil
+}
+
+// RevokeRefreshTokenFamily revokes the token with hash tokenHash along with every
+// token rotated from the same login, as on logout. Unknown tokens are ignored.
+func (db *DB) RevokeRefreshTokenFamily(ctx context.Context, tokenHash string) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW()
+		 WHERE revoked_at IS NULL
+		   AND family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1)`,
+		tokenHash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes all of a user's refresh tokens, signing them out
+// everywhere once their access tokens expire
+func (db *DB) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke user refresh tokens: %w", err)
+	}
+	return nil
+}
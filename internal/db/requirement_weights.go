@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// -----------------------------------------------------------------------------
+// Requirement Weight Methods
+// -----------------------------------------------------------------------------
+
+// GetJobRequirement retrieves a requirement by its ID, with userID's weight for it
+func (db *DB) GetJobRequirement(ctx context.Context, id, userID uuid.UUID) (*JobRequirement, error) {
+	var r JobRequirement
+	err := db.pool.QueryRow(ctx,
+		`SELECT r.id, r.job_profile_id, r.requirement_type, r.skill, r.level, r.evidence,
+		        r.ordinal, r.created_at, w.weight
+		 FROM job_requirements r
+		 LEFT JOIN job_requirement_weights w
+		        ON w.user_id = $2 AND w.job_profile_id = r.job_profile_id
+		       AND w.requirement_type = r.requirement_type AND w.skill_normalized = LOWER(TRIM(r.skill))
+		 WHERE r.id = $1`,
+		id, userID,
+	).Scan(&r.ID, &r.JobProfileID, &r.RequirementType, &r.Skill, &r.Level, &r.Evidence,
+		&r.Ordinal, &r.CreatedAt, &r.Weight)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get requirement: %w", err)
+	}
+	return &r, nil
+}
+
+// SetRequirementWeight sets userID's weight for a requirement, or clears it when weight
+// is nil so the default weight applies again
+func (db *DB) SetRequirementWeight(ctx context.Context, userID uuid.UUID, req *JobRequirement, weight *float64) error {
+	var err error
+	if weight == nil {
+		_, err = db.pool.Exec(ctx,
+			`DELETE FROM job_requirement_weights
+			 WHERE user_id = $1 AND job_profile_id = $2 AND requirement_type = $3 AND skill_normalized = $4`,
+			userID, req.JobProfileID, req.RequirementType, NormalizeKeyword(req.Skill),
+		)
+	} else {
+		_, err = db.pool.Exec(ctx,
+			`INSERT INTO job_requirement_weights (user_id, job_profile_id, requirement_type, skill_normalized, weight)
+			 VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (user_id, job_profile_id, requirement_type, skill_normalized) DO UPDATE SET
+			     weight = $5,
+			     updated_at = NOW()`,
+			userID, req.JobProfileID, req.RequirementType, NormalizeKeyword(req.Skill), *weight,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set requirement weight: %w", err)
+	}
+	return nil
+}
+
+// GetPostingRequirementWeights returns userID's requirement weights for the job profiles
+// of every posting in a canonical posting's group, least recently set first so later
+// weights for the same requirement win
+func (db *DB) GetPostingRequirementWeights(ctx context.Context, userID, canonicalID uuid.UUID) ([]RequirementWeight, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT w.requirement_type, w.skill_normalized, w.weight
+		 FROM job_requirement_weights w
+		 JOIN job_profiles jp ON jp.id = w.job_profile_id
+		 JOIN job_postings p ON p.id = jp.posting_id
+		 WHERE w.user_id = $1 AND COALESCE(p.canonical_posting_id, p.id) = $2
+		 ORDER BY w.updated_at`,
+		userID, canonicalID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requirement weights: %w", err)
+	}
+	defer rows.Close()
+
+	var weights []RequirementWeight
+	for rows.Next() {
+		var w RequirementWeight
+		if err := rows.Scan(&w.RequirementType, &w.Skill, &w.Weight); err != nil {
+			return nil, fmt.Errorf("failed to scan requirement weight: %w", err)
+		}
+		weights = append(weights, w)
+	}
+	return weights, rows.Err()
+}
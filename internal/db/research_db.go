@@ -129,6 +129,40 @@ func (db *DB) UpdateResearchSessionProgress(ctx context.Context, id uuid.UUID, p
 	return nil
 }
 
+// SaveResearchState stores the resumable crawl state on a research session
+func (db *DB) SaveResearchState(ctx context.Context, id uuid.UUID, state any) error {
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal research state: %w", err)
+	}
+	_, err = db.pool.Exec(ctx,
+		`UPDATE research_sessions SET state = $1 WHERE id = $2`,
+		stateJSON, id)
+	if err != nil {
+		return fmt.Errorf("failed to save research state: %w", err)
+	}
+	return nil
+}
+
+// GetLatestResearchState returns the crawl state of the company's most recent
+// completed session that has one, or nil if there is none
+func (db *DB) GetLatestResearchState(ctx context.Context, companyID uuid.UUID) ([]byte, error) {
+	var state []byte
+	err := db.pool.QueryRow(ctx,
+		`SELECT state FROM research_sessions
+		 WHERE company_id = $1 AND status = $2 AND state IS NOT NULL
+		 ORDER BY completed_at DESC LIMIT 1`,
+		companyID, ResearchStatusCompleted,
+	).Scan(&state)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get research state: %w", err)
+	}
+	return state, nil
+}
+
 // ListResearchSessionsByCompany retrieves all research sessions for a company
 func (db *DB) ListResearchSessionsByCompany(ctx context.Context, companyID uuid.UUID) ([]ResearchSession, error) {
 	rows, err := db.pool.Query(ctx,
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resume health report bounds
+const (
+	StaleBulletAge       = 365 * 24 * time.Hour // Bullets not updated for this long are stale
+	healthTargetWindow   = 180 * 24 * time.Hour // Runs this recent define the target roles
+	healthMissingSkills  = 3                    // Missing skills suggested per role family
+	healthMissingPerRole = 10                   // Missing skills listed per role family
+)
+
+// GetResumeHealth reviews userID's experience bank against the job profiles of the
+// runs they started within the last 180 days
+func (db *DB) GetResumeHealth(ctx context.Context, userID uuid.UUID, now time.Time) (*ResumeHealth, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT COALESCE(NULLIF(jp.role_family, ''), lower(jp.role_title)),
+		        jr.id, jr.job_profile_id, jr.requirement_type, jr.skill, jr.level, jr.evidence, jr.ordinal, jr.created_at
+		 FROM job_profiles jp
+		 JOIN job_requirements jr ON jr.job_profile_id = jp.id
+		 WHERE jp.posting_id IN (
+		     SELECT job_posting_id FROM pipeline_runs
+		     WHERE user_id = $1 AND job_posting_id IS NOT NULL AND created_at >= $2
+		 )
+		 ORDER BY jp.id, jr.requirement_type, jr.ordinal`,
+		userID, now.Add(-healthTargetWindow),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target requirements: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []TargetRequirement
+	for rows.Next() {
+		var t TargetRequirement
+		if err := rows.Scan(&t.RoleFamily, &t.ID, &t.JobProfileID, &t.RequirementType, &t.Skill,
+			&t.Level, &t.Evidence, &t.Ordinal, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan target requirement: %w", err)
+		}
+		targets = append(targets, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get target requirements: %w", err)
+	}
+
+	bullets, err := db.listEvidenceBullets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	health := BuildResumeHealth(targets, bullets, now)
+	health.UserID = userID
+	return health, nil
+}
+
+// BuildResumeHealth reviews bullets against the requirements of the postings a user
+// targeted. Each role family's requirements are classified as in BuildSkillGap, and
+// bullets are flagged when they have not been updated within StaleBulletAge or carry
+// no metric (neither a metrics field nor a number in the text). Suggestions come
+// first from the skills the most postings require without evidence.
+func BuildResumeHealth(targets []TargetRequirement, bullets []EvidenceBullet, now time.Time) *ResumeHealth {
+	health := &ResumeHealth{
+		GeneratedAt:    now,
+		Bullets:        len(bullets),
+		Roles:          []RoleCoverage{},
+		StaleBullets:   []HealthBullet{},
+		MissingMetrics: []HealthBullet{},
+		Suggestions:    []string{},
+	}
+
+	var families []string
+	byFamily := make(map[string][]TargetRequirement)
+	for _, t := range targets {
+		if t.RoleFamily == "" {
+			continue
+		}
+		if _, ok := byFamily[t.RoleFamily]; !ok {
+			families = append(families, t.RoleFamily)
+		}
+		byFamily[t.RoleFamily] = append(byFamily[t.RoleFamily], t)
+	}
+	for _, family := range families {
+		health.Roles = append(health.Roles, roleCoverage(family, byFamily[family], bullets))
+	}
+	sort.SliceStable(health.Roles, func(i, j int) bool {
+		return health.Roles[i].Postings > health.Roles[j].Postings
+	})
+
+	staleBefore := now.Add(-StaleBulletAge)
+	for _, b := range bullets {
+		flagged := HealthBullet{BulletID: b.BulletID, StoryID: b.StoryID, Text: b.Text, UpdatedAt: b.UpdatedAt}
+		if b.UpdatedAt.Before(staleBefore) {
+			health.StaleBullets = append(health.StaleBullets, flagged)
+		}
+		if strings.TrimSpace(b.Metrics) == "" && !strings.ContainsAny(b.Text, "0123456789") {
+			health.MissingMetrics = append(health.MissingMetrics, flagged)
+		}
+	}
+
+	health.Suggestions = healthSuggestions(health)
+	return health
+}
+
+// roleCoverage classifies the distinct skills a role family's postings require,
+// listing the missing ones by how many postings require them
+func roleCoverage(family string, targets []TargetRequirement, bullets []EvidenceBullet) RoleCoverage {
+	profiles := make(map[uuid.UUID]bool)
+	postingsBySkill := make(map[string]map[uuid.UUID]bool)
+	var requirements []JobRequirement
+	for _, t := range targets {
+		profiles[t.JobProfileID] = true
+		skill := NormalizeSkillName(t.Skill)
+		if postingsBySkill[skill] == nil {
+			postingsBySkill[skill] = make(map[uuid.UUID]bool)
+		}
+		postingsBySkill[skill][t.JobProfileID] = true
+		requirements = append(requirements, t.JobRequirement)
+	}
+
+	gap := BuildSkillGap(requirements, bullets)
+	coverage := RoleCoverage{
+		RoleFamily:   family,
+		Postings:     len(profiles),
+		Requirements: len(gap.Matched) + len(gap.Partial) + len(gap.Missing),
+		Matched:      len(gap.Matched),
+		Partial:      len(gap.Partial),
+		Missing:      []MissingSkill{},
+	}
+	if coverage.Requirements > 0 {
+		coverage.Coverage = math.Round(float64(coverage.Matched)/float64(coverage.Requirements)*100) / 100
+	}
+
+	for _, entry := range gap.Missing {
+		postings := len(postingsBySkill[NormalizeSkillName(entry.Skill)])
+		coverage.Missing = append(coverage.Missing, MissingSkill{Skill: entry.Skill, Postings: postings})
+	}
+	sort.SliceStable(coverage.Missing, func(i, j int) bool {
+		return coverage.Missing[i].Postings > coverage.Missing[j].Postings
+	})
+	if len(coverage.Missing) > healthMissingPerRole {
+		coverage.Missing = coverage.Missing[:healthMissingPerRole]
+	}
+	return coverage
+}
+
+// healthSuggestions turns a report's findings into improvements, most valuable first
+func healthSuggestions(health *ResumeHealth) []string {
+	suggestions := []string{}
+	if len(health.Roles) == 0 {
+		suggestions = append(suggestions, "Tailor a resume to a job posting to see how your experience bank covers the roles you target.")
+	}
+	for _, role := range health.Roles {
+		for i, missing := range role.Missing {
+			if i == healthMissingSkills {
+				break
+			}
+			suggestions = append(suggestions, fmt.Sprintf("Add a bullet showing %s, required by %d of your %d %s postings.",
+				missing.Skill, missing.Postings, role.Postings, role.RoleFamily))
+		}
+	}
+	if n := len(health.MissingMetrics); n > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("Quantify %d %s with a metric such as time saved, scale, or revenue.", n, bulletsNoun(n)))
+	}
+	if n := len(health.StaleBullets); n > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("Review %d %s not updated in over a year.", n, bulletsNoun(n)))
+	}
+	return suggestions
+}
+
+func bulletsNoun(n int) string {
+	if n == 1 {
+		return "bullet"
+	}
+	return "bullets"
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildResumeHealth(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	backend1, backend2, data := uuid.New(), uuid.New(), uuid.New()
+	target := func(family string, profile uuid.UUID, skill string) TargetRequirement {
+		return TargetRequirement{RoleFamily: family, JobRequirement: JobRequirement{
+			ID: uuid.New(), JobProfileID: profile, RequirementType: "hard", Skill: skill,
+		}}
+	}
+	targets := []TargetRequirement{
+		target("software engineer", backend1, "Go"),
+		target("software engineer", backend1, "Kafka"),
+		target("software engineer", backend2, "Go"),
+		target("software engineer", backend2, "Kubernetes"),
+		target("software engineer", backend2, "Kafka"),
+		target("data engineer", data, "Spark"),
+	}
+	bullets := []EvidenceBullet{
+		{BulletID: "b1", StoryID: "s1", Text: "Built Go services handling 2M requests a day", Skills: []string{"go"}, UpdatedAt: now.AddDate(0, -1, 0)},
+		{BulletID: "b2", StoryID: "s1", Text: "Mentored new engineers", UpdatedAt: now.AddDate(-2, 0, 0)},
+		{BulletID: "b3", StoryID: "s2", Text: "Cut build times", Metrics: "40%", UpdatedAt: now},
+	}
+
+	health := BuildResumeHealth(targets, bullets, now)
+
+	if health.Bullets != 3 || len(health.Roles) != 2 {
+		t.Fatalf("health = %+v, want 3 bullets and 2 roles", health)
+	}
+	role := health.Roles[0]
+	if role.RoleFamily != "software engineer" || role.Postings != 2 || role.Requirements != 3 || role.Matched != 1 || role.Coverage != 0.33 {
+		t.Errorf("Roles[0] = %+v, want software engineer with 1 of 3 skills matched across 2 postings", role)
+	}
+	if len(role.Missing) != 2 || role.Missing[0] != (MissingSkill{Skill: "Kafka", Postings: 2}) || role.Missing[1].Skill != "Kubernetes" {
+		t.Errorf("Missing = %+v, want Kafka (2 postings) before Kubernetes", role.Missing)
+	}
+	if health.Roles[1].RoleFamily != "data engineer" || health.Roles[1].Coverage != 0 {
+		t.Errorf("Roles[1] = %+v, want data engineer with no coverage", health.Roles[1])
+	}
+
+	if len(health.StaleBullets) != 1 || health.StaleBullets[0].BulletID != "b2" {
+		t.Errorf("StaleBullets = %+v, want b2", health.StaleBullets)
+	}
+	// b1 has a number in its text and b3 a metrics field
+	if len(health.MissingMetrics) != 1 || health.MissingMetrics[0].BulletID != "b2" {
+		t.Errorf("MissingMetrics = %+v, want b2", health.MissingMetrics)
+	}
+
+	if len(health.Suggestions) != 5 || !strings.Contains(health.Suggestions[0], "Kafka, required by 2 of your 2 software engineer postings") {
+		t.Errorf("Suggestions = %q, want Kafka first", health.Suggestions)
+	}
+}
+
+func TestBuildResumeHealthNoTargets(t *testing.T) {
+	health := BuildResumeHealth(nil, nil, time.Now())
+	if len(health.Roles) != 0 || len(health.Suggestions) != 1 {
+		t.Errorf("health = %+v, want no roles and a suggestion to tailor a resume", health)
+	}
+}
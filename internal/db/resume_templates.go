@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const resumeTemplateColumns = `id, user_id, name, content, manifest, created_at, updated_at`
+
+func scanResumeTemplate(row pgx.Row) (*ResumeTemplate, error) {
+	var t ResumeTemplate
+	var manifest []byte
+	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.Content, &manifest, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	t.Manifest = manifest
+	return &t, nil
+}
+
+// UpsertResumeTemplate stores a template, replacing the user's template with the same name
+func (db *DB) UpsertResumeTemplate(ctx context.Context, userID uuid.UUID, name, content string, manifest json.RawMessage) (*ResumeTemplate, error) {
+	if len(manifest) == 0 {
+		manifest = json.RawMessage(`{}`)
+	}
+	t, err := scanResumeTemplate(db.pool.QueryRow(ctx,
+		`INSERT INTO resume_templates (user_id, name, content, manifest)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (user_id, name) DO UPDATE SET
+		     content = EXCLUDED.content,
+		     manifest = EXCLUDED.manifest,
+		     updated_at = NOW()
+		 RETURNING `+resumeTemplateColumns,
+		userID, name, content, []byte(manifest),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save resume template: %w", err)
+	}
+	return t, nil
+}
+
+// ListResumeTemplates returns the user's templates, ordered by name
+func (db *DB) ListResumeTemplates(ctx context.Context, userID uuid.UUID) ([]ResumeTemplate, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+resumeTemplateColumns+` FROM resume_templates WHERE user_id = $1 ORDER BY name`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resume templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := []ResumeTemplate{}
+	for rows.Next() {
+		t, err := scanResumeTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan resume template: %w", err)
+		}
+		templates = append(templates, *t)
+	}
+	return templates, rows.Err()
+}
+
+// GetResumeTemplateByName returns the user's template with the given name, or nil if
+// not found
+func (db *DB) GetResumeTemplateByName(ctx context.Context, userID uuid.UUID, name string) (*ResumeTemplate, error) {
+	t, err := scanResumeTemplate(db.pool.QueryRow(ctx,
+		`SELECT `+resumeTemplateColumns+` FROM resume_templates WHERE user_id = $1 AND name = $2`,
+		userID, name,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get resume template: %w", err)
+	}
+	return t, nil
+}
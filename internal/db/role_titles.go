@@ -0,0 +1,23 @@
+package db
+
+import "github.com/jonathan/resume-customizer/internal/titles"
+
+// normalizedRole holds the normalized role title columns stored with jobs and job profiles
+type normalizedRole struct {
+	Title  string
+	Family string
+	Level  string
+}
+
+// normalizeRole normalizes a role title held at company, reading level codes through the
+// company's leveling map
+func normalizeRole(roleTitle, company string) normalizedRole {
+	t := titles.NormalizeAt(roleTitle, company)
+	return normalizedRole{Title: t.Canonical, Family: t.Family, Level: t.Level.String()}
+}
+
+// setNormalizedRole fills a job's normalized role fields from its title and company
+func (j *Job) setNormalizedRole() {
+	role := normalizeRole(j.RoleTitle, j.Company)
+	j.RoleTitleNormalized, j.RoleFamily, j.RoleLevel = role.Title, role.Family, role.Level
+}
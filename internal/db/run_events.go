@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jonathan/resume-customizer/internal/logging"
+)
+
+// maxRunEventError caps the error text carried by a run event. JSON escaping can
+// grow text several times over, so this keeps events well under the NOTIFY limit;
+// the full error stays in the database.
+const maxRunEventError = 1000
+
+// -----------------------------------------------------------------------------
+// Run Event Methods
+// -----------------------------------------------------------------------------
+
+// PublishRunEvent notifies RunEventsChannel listeners on every replica.
+// At is set to the current time when zero, and long errors are truncated.
+func (db *DB) PublishRunEvent(ctx context.Context, event RunEvent) error {
+	if event.At.IsZero() {
+		event.At = time.Now().UTC()
+	}
+	if event.Error != nil && len(*event.Error) > maxRunEventError {
+		truncated := truncateUTF8(*event.Error, maxRunEventError) + "…"
+		event.Error = &truncated
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return db.Notify(ctx, RunEventsChannel, string(payload))
+}
+
+// publishRunEvent publishes event after a successful write. Events are
+// best-effort: the write already succeeded, and consumers re-read state from
+// the database, so a failed notification only delays them.
+func (db *DB) publishRunEvent(ctx context.Context, event RunEvent) {
+	if err := db.PublishRunEvent(ctx, event); err != nil {
+		slog.WarnContext(logging.WithRunID(ctx, event.RunID), "failed to publish run event", "event", event.Type, "error", err)
+	}
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a character
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,21 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateUTF8(t *testing.T) {
+	if got := truncateUTF8("short", 10); got != "short" {
+		t.Errorf("truncateUTF8() = %q, want unchanged", got)
+	}
+	// "é" is two bytes; cutting inside it must back off to the previous character
+	if got := truncateUTF8("aé", 2); got != "a" {
+		t.Errorf("truncateUTF8() = %q, want %q", got, "a")
+	}
+
+	long := strings.Repeat("<stderr>", 10000)
+	if got := truncateUTF8(long, maxRunEventError); len(got) != maxRunEventError {
+		t.Errorf("truncateUTF8() length = %d, want %d", len(got), maxRunEventError)
+	}
+}
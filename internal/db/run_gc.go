@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MarkAbandonedRuns marks runs that are still queued or running but have had no step
+// activity or new artifacts for idle as abandoned. Returns the number of runs marked.
+func (db *DB) MarkAbandonedRuns(ctx context.Context, idle time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-idle)
+	result, err := db.pool.Exec(ctx,
+		`UPDATE pipeline_runs r SET status = $1, abandoned_at = NOW()
+		 WHERE r.status IN ('queued', 'running') AND r.created_at < $2
+		   AND NOT EXISTS (SELECT 1 FROM run_steps s WHERE s.run_id = r.id AND s.updated_at >= $2)
+		   AND NOT EXISTS (SELECT 1 FROM artifacts a WHERE a.run_id = r.id AND a.created_at >= $2)`,
+		RunStatusAbandoned, cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark abandoned runs: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// DeleteAbandonedRuns deletes runs that have been abandoned for longer than the retention
+// period, along with their steps and artifacts, then the artifact blobs nothing refers to
+// any more. Runs behind a shared resume link, and runs with step activity since they were
+// marked, are kept.
+func (db *DB) DeleteAbandonedRuns(ctx context.Context, retention time.Duration) (*ReclaimedRuns, error) {
+	cutoff := time.Now().Add(-retention)
+	var reclaimed ReclaimedRuns
+	err := db.pool.QueryRow(ctx,
+		`WITH doomed AS (
+		     SELECT r.id FROM pipeline_runs r
+		      WHERE r.status = $1 AND r.abandoned_at < $2
+		        AND NOT EXISTS (SELECT 1 FROM run_steps s WHERE s.run_id = r.id AND s.updated_at >= r.abandoned_at)
+		        AND NOT EXISTS (SELECT 1 FROM shared_resumes sr WHERE sr.run_id = r.id)
+		 ), deleted AS (
+		     DELETE FROM pipeline_runs WHERE id IN (SELECT id FROM doomed) RETURNING id
+		 )
+		 SELECT (SELECT COUNT(*) FROM deleted),
+		        (SELECT COUNT(*) FROM run_steps WHERE run_id IN (SELECT id FROM doomed)),
+		        (SELECT COUNT(*) FROM artifacts WHERE run_id IN (SELECT id FROM doomed))`,
+		RunStatusAbandoned, cutoff,
+	).Scan(&reclaimed.Runs, &reclaimed.Steps, &reclaimed.Artifacts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete abandoned runs: %w", err)
+	}
+	if reclaimed.Blobs, err = db.DeleteUnreferencedArtifactBlobs(ctx, ArtifactBlobGrace); err != nil {
+		return &reclaimed, err
+	}
+	return &reclaimed, nil
+}
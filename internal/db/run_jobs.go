@@ -0,0 +1,214 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// -----------------------------------------------------------------------------
+// Run Job Queue Methods
+// -----------------------------------------------------------------------------
+
+const runJobColumns = `id, run_id, user_id, payload, priority, status, error_message, worker_id,
+	attempts, created_at, started_at, completed_at`
+
+// scanRunJob scans a run_jobs row selected with runJobColumns
+func scanRunJob(row pgx.Row) (*RunJob, error) {
+	var job RunJob
+	var payload []byte
+	if err := row.Scan(&job.ID, &job.RunID, &job.UserID, &payload, &job.Priority, &job.Status, &job.ErrorMessage,
+		&job.WorkerID, &job.Attempts, &job.CreatedAt, &job.StartedAt, &job.CompletedAt); err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+	return &job, nil
+}
+
+// EnqueueRunJob queues a run for the run workers at a scheduling priority (higher is
+// claimed first) and notifies RunJobsChannel. A run is queued at most once.
+func (db *DB) EnqueueRunJob(ctx context.Context, runID, userID uuid.UUID, priority int, payload any) (uuid.UUID, error) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to marshal run job payload: %w", err)
+	}
+
+	var id uuid.UUID
+	err = db.pool.QueryRow(ctx,
+		`INSERT INTO run_jobs (run_id, user_id, payload, priority)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id`,
+		runID, userID, payloadJSON, priority,
+	).Scan(&id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to enqueue run job: %w", err)
+	}
+	return id, db.Notify(ctx, RunJobsChannel, id.String())
+}
+
+// ClaimRunJob atomically claims the oldest queued run job of the highest priority.
+// Concurrent workers, in this process or another replica, never claim the same job
+// (FOR UPDATE SKIP LOCKED). Returns nil if no job is available.
+func (db *DB) ClaimRunJob(ctx context.Context, workerID string) (*RunJob, error) {
+	row := db.pool.QueryRow(ctx,
+		`UPDATE run_jobs
+		 SET status = 'running', worker_id = $1, attempts = attempts + 1,
+		     started_at = NOW(), updated_at = NOW()
+		 WHERE id = (
+		     SELECT id FROM run_jobs
+		     WHERE status = 'queued'
+		     ORDER BY priority DESC, created_at
+		     FOR UPDATE SKIP LOCKED
+		     LIMIT 1
+		 )
+		 RETURNING `+runJobColumns,
+		workerID,
+	)
+	job, err := scanRunJob(row)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim run job: %w", err)
+	}
+	return job, nil
+}
+
+// CountQueuedRunJobs returns how many of a user's run jobs wait to be claimed
+func (db *DB) CountQueuedRunJobs(ctx context.Context, userID uuid.UUID) (int, error) {
+	var n int
+	err := db.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM run_jobs WHERE user_id = $1 AND status = 'queued'`,
+		userID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count queued run jobs: %w", err)
+	}
+	return n, nil
+}
+
+// GetRunJobByRun retrieves the job that executes a run, or nil if the run was not queued
+func (db *DB) GetRunJobByRun(ctx context.Context, runID uuid.UUID) (*RunJob, error) {
+	row := db.pool.QueryRow(ctx, `SELECT `+runJobColumns+` FROM run_jobs WHERE run_id = $1`, runID)
+	job, err := scanRunJob(row)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get run job: %w", err)
+	}
+	return job, nil
+}
+
+// CompleteRunJob marks a run job completed
+func (db *DB) CompleteRunJob(ctx context.Context, id uuid.UUID) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE run_jobs
+		 SET status = 'completed', completed_at = NOW(), updated_at = NOW()
+		 WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete run job: %w", err)
+	}
+	return nil
+}
+
+// FailRunJob records a run job failure. Jobs that already finished keep their outcome.
+func (db *DB) FailRunJob(ctx context.Context, id uuid.UUID, errMsg string) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE run_jobs
+		 SET status = 'failed', error_message = $2, completed_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status IN ('queued', 'running')`,
+		id, errMsg,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fail run job: %w", err)
+	}
+	return nil
+}
+
+// ReleaseRunJob returns a running job to the queue, as a worker does when it shuts
+// down mid-run, and notifies RunJobsChannel so another worker picks it up
+func (db *DB) ReleaseRunJob(ctx context.Context, id uuid.UUID) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE run_jobs
+		 SET status = 'queued', worker_id = NULL, started_at = NULL, updated_at = NOW()
+		 WHERE id = $1 AND status = 'running'`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release run job: %w", err)
+	}
+	return db.Notify(ctx, RunJobsChannel, id.String())
+}
+
+// HeartbeatRunJob marks a running job as still alive so it is not requeued as stale
+func (db *DB) HeartbeatRunJob(ctx context.Context, id uuid.UUID) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE run_jobs SET updated_at = NOW() WHERE id = $1 AND status = 'running'`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to heartbeat run job: %w", err)
+	}
+	return nil
+}
+
+// RequeueStaleRunJobs recovers run jobs whose worker has not sent a heartbeat for
+// staleAfter, as happens when a server crashes mid-run. Jobs with attempts left are
+// queued again; the rest are failed along with their runs, so a run that keeps
+// crashing its worker is not retried forever. Returns the number of jobs requeued.
+func (db *DB) RequeueStaleRunJobs(ctx context.Context, staleAfter time.Duration, maxAttempts int) (int64, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx,
+		`UPDATE run_jobs
+		 SET status = 'failed', error_message = 'worker stopped responding', completed_at = NOW(), updated_at = NOW()
+		 WHERE status = 'running' AND attempts >= $2 AND updated_at < NOW() - make_interval(secs => $1)
+		 RETURNING run_id`,
+		staleAfter.Seconds(), maxAttempts,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail stale run jobs: %w", err)
+	}
+	failed, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail stale run jobs: %w", err)
+	}
+	if len(failed) > 0 {
+		if _, err := tx.Exec(ctx,
+			`UPDATE pipeline_runs SET status = 'failed', completed_at = NOW()
+			 WHERE id = ANY($1) AND status IN ('queued', 'running')`,
+			failed,
+		); err != nil {
+			return 0, fmt.Errorf("failed to fail runs of stale run jobs: %w", err)
+		}
+	}
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE run_jobs
+		 SET status = 'queued', worker_id = NULL, started_at = NULL, updated_at = NOW()
+		 WHERE status = 'running' AND updated_at < NOW() - make_interval(secs => $1)`,
+		staleAfter.Seconds(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale run jobs: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for _, runID := range failed {
+		db.publishRunEvent(ctx, RunEvent{Type: RunEventRunCompleted, RunID: runID, Status: "failed"})
+	}
+	return tag.RowsAffected(), nil
+}
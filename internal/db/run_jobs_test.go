@@ -0,0 +1,26 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunJobDone(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{RunJobStatusQueued, false},
+		{RunJobStatusRunning, false},
+		{RunJobStatusCompleted, true},
+		{RunJobStatusFailed, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			job := &RunJob{Status: tt.status}
+			assert.Equal(t, tt.want, job.Done())
+		})
+	}
+}
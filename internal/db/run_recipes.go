@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const runRecipeColumns = `id, user_id, name, description, template, max_bullets, max_lines,
+	section_order, ranking_weights, created_at, updated_at`
+
+func scanRunRecipe(row pgx.Row) (*RunRecipe, error) {
+	var r RunRecipe
+	var weights []byte
+	err := row.Scan(&r.ID, &r.UserID, &r.Name, &r.Description, &r.Template, &r.MaxBullets,
+		&r.MaxLines, &r.SectionOrder, &weights, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(weights) > 0 {
+		r.RankingWeights = weights
+	}
+	return &r, nil
+}
+
+// nullIfZero returns nil for zero, so unset limits are stored as NULL
+func nullIfZero(n int) *int {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
+
+// UpsertRunRecipe creates a recipe, or replaces the user's recipe with the same name
+func (db *DB) UpsertRunRecipe(ctx context.Context, userID uuid.UUID, input *RunRecipeInput) (*RunRecipe, error) {
+	order := input.SectionOrder
+	if order == nil {
+		order = []string{}
+	}
+	var weights []byte
+	if len(input.RankingWeights) > 0 {
+		weights = input.RankingWeights
+	}
+	r, err := scanRunRecipe(db.pool.QueryRow(ctx,
+		`INSERT INTO run_recipes (user_id, name, description, template, max_bullets, max_lines, section_order, ranking_weights)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (user_id, lower(name)) DO UPDATE SET
+		     name = EXCLUDED.name,
+		     description = EXCLUDED.description,
+		     template = EXCLUDED.template,
+		     max_bullets = EXCLUDED.max_bullets,
+		     max_lines = EXCLUDED.max_lines,
+		     section_order = EXCLUDED.section_order,
+		     ranking_weights = EXCLUDED.ranking_weights,
+		     updated_at = NOW()
+		 RETURNING `+runRecipeColumns,
+		userID, input.Name, nullIfEmpty(input.Description), nullIfEmpty(input.Template),
+		nullIfZero(input.MaxBullets), nullIfZero(input.MaxLines), order, weights,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save run recipe: %w", err)
+	}
+	return r, nil
+}
+
+// ListRunRecipes returns the user's recipes, ordered by name
+func (db *DB) ListRunRecipes(ctx context.Context, userID uuid.UUID) ([]RunRecipe, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+runRecipeColumns+` FROM run_recipes WHERE user_id = $1 ORDER BY lower(name)`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list run recipes: %w", err)
+	}
+	defer rows.Close()
+
+	recipes := []RunRecipe{}
+	for rows.Next() {
+		r, err := scanRunRecipe(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan run recipe: %w", err)
+		}
+		recipes = append(recipes, *r)
+	}
+	return recipes, rows.Err()
+}
+
+// GetRunRecipe returns a recipe by ID, or nil if not found
+func (db *DB) GetRunRecipe(ctx context.Context, id uuid.UUID) (*RunRecipe, error) {
+	r, err := scanRunRecipe(db.pool.QueryRow(ctx,
+		`SELECT `+runRecipeColumns+` FROM run_recipes WHERE id = $1`, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get run recipe: %w", err)
+	}
+	return r, nil
+}
+
+// GetRunRecipeByName returns the user's recipe with the given name, whatever its
+// capitalization, or nil if not found
+func (db *DB) GetRunRecipeByName(ctx context.Context, userID uuid.UUID, name string) (*RunRecipe, error) {
+	r, err := scanRunRecipe(db.pool.QueryRow(ctx,
+		`SELECT `+runRecipeColumns+` FROM run_recipes WHERE user_id = $1 AND lower(name) = lower($2)`,
+		userID, name,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get run recipe: %w", err)
+	}
+	return r, nil
+}
+
+// DeleteRunRecipe deletes a recipe
+func (db *DB) DeleteRunRecipe(ctx context.Context, id uuid.UUID) error {
+	result, err := db.pool.Exec(ctx, `DELETE FROM run_recipes WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete run recipe: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("run recipe not found: %s", id)
+	}
+	return nil
+}
@@ -43,6 +43,14 @@ func (db *DB) CreateRunStep(ctx context.Context, runID uuid.UUID, input *RunStep
 		_ = json.Unmarshal(parametersJSON, &step.Parameters)
 	}
 
+	db.publishRunEvent(ctx, RunEvent{
+		Type:     RunEventStepStatus,
+		RunID:    runID,
+		Step:     step.Step,
+		Category: step.Category,
+		Status:   step.Status,
+	})
+
 	return &step, nil
 }
 
@@ -165,6 +173,35 @@ func (db *DB) UpdateRunStepStatus(ctx context.Context, runID uuid.UUID, stepName
 		return fmt.Errorf("failed to update run step status: %w", err)
 	}
 
+	db.publishRunEvent(ctx, RunEvent{
+		Type:       RunEventStepStatus,
+		RunID:      runID,
+		Step:       stepName,
+		Category:   currentStep.Category,
+		Status:     status,
+		ArtifactID: artifactID,
+		Error:      errorMsg,
+	})
+
+	return nil
+}
+
+// UpdateRunStepParameters replaces the stored parameters of a run step
+func (db *DB) UpdateRunStepParameters(ctx context.Context, runID uuid.UUID, stepName string, params map[string]interface{}) error {
+	parametersJSON, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal step parameters: %w", err)
+	}
+	tag, err := db.pool.Exec(ctx,
+		`UPDATE run_steps SET parameters = $1, updated_at = NOW() WHERE run_id = $2 AND step = $3`,
+		parametersJSON, runID, stepName,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update run step parameters: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("step not found: %s", stepName)
+	}
 	return nil
 }
 
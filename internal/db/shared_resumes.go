@@ -0,0 +1,263 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// sharedReferrerLimit is how many referring hosts the view stats list
+const sharedReferrerLimit = 10
+
+const sharedResumeColumns = `id, user_id, run_id, slug, scope, expires_at, revoked_at, created_at, updated_at`
+
+func scanSharedResume(row pgx.Row) (*SharedResume, error) {
+	var s SharedResume
+	err := row.Scan(&s.ID, &s.UserID, &s.RunID, &s.Slug, &s.Scope, &s.ExpiresAt, &s.RevokedAt,
+		&s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UpsertSharedResume publishes a run as the user's shared resume page with the given
+// scope and expiry. An existing page keeps its slug so links already handed out point at
+// the new run, unless it was revoked: a revoked link stays dead and the page gets
+// input.Slug instead.
+func (db *DB) UpsertSharedResume(ctx context.Context, input *SharedResumeInput) (*SharedResume, error) {
+	s, err := scanSharedResume(db.pool.QueryRow(ctx,
+		`INSERT INTO shared_resumes (user_id, run_id, slug, scope, expires_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id)
+		 DO UPDATE SET run_id = EXCLUDED.run_id,
+		     slug = CASE WHEN shared_resumes.revoked_at IS NULL THEN shared_resumes.slug ELSE EXCLUDED.slug END,
+		     scope = EXCLUDED.scope,
+		     expires_at = EXCLUDED.expires_at,
+		     revoked_at = NULL,
+		     updated_at = NOW()
+		 RETURNING `+sharedResumeColumns,
+		input.UserID, input.RunID, input.Slug, input.Scope, input.ExpiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save shared resume: %w", err)
+	}
+	return s, nil
+}
+
+// UpdateSharedResumeAccess changes the scope and expiry of the user's shared resume
+// page, or returns nil if they have none. A nil expiresAt makes the link never expire.
+func (db *DB) UpdateSharedResumeAccess(ctx context.Context, userID uuid.UUID, scope string, expiresAt *time.Time) (*SharedResume, error) {
+	s, err := scanSharedResume(db.pool.QueryRow(ctx,
+		`UPDATE shared_resumes SET scope = $2, expires_at = $3, updated_at = NOW()
+		 WHERE user_id = $1
+		 RETURNING `+sharedResumeColumns,
+		userID, scope, expiresAt,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update shared resume access: %w", err)
+	}
+	return s, nil
+}
+
+// RevokeSharedResume revokes the link to the user's shared resume page, keeping its
+// view history and access log, or returns nil if they have no page. Revoking an already
+// revoked link keeps the original revocation time.
+func (db *DB) RevokeSharedResume(ctx context.Context, userID uuid.UUID) (*SharedResume, error) {
+	s, err := scanSharedResume(db.pool.QueryRow(ctx,
+		`UPDATE shared_resumes SET revoked_at = COALESCE(revoked_at, NOW()), updated_at = NOW()
+		 WHERE user_id = $1
+		 RETURNING `+sharedResumeColumns,
+		userID,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to revoke shared resume: %w", err)
+	}
+	return s, nil
+}
+
+// GetSharedResume returns the user's shared resume page, or nil if they have none
+func (db *DB) GetSharedResume(ctx context.Context, userID uuid.UUID) (*SharedResume, error) {
+	return db.getSharedResume(ctx, `user_id = $1`, userID)
+}
+
+// GetSharedResumeBySlug returns the shared resume page with the given slug, or nil if
+// none. Revoked and expired pages are returned; callers decide how to refuse them.
+func (db *DB) GetSharedResumeBySlug(ctx context.Context, slug string) (*SharedResume, error) {
+	return db.getSharedResume(ctx, `slug = $1`, slug)
+}
+
+func (db *DB) getSharedResume(ctx context.Context, where string, arg any) (*SharedResume, error) {
+	s, err := scanSharedResume(db.pool.QueryRow(ctx,
+		`SELECT `+sharedResumeColumns+` FROM shared_resumes WHERE `+where,
+		arg,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get shared resume: %w", err)
+	}
+	return s, nil
+}
+
+// DeleteSharedResume unpublishes the user's shared resume page and drops its view
+// history, comments, and access log
+func (db *DB) DeleteSharedResume(ctx context.Context, userID uuid.UUID) error {
+	cmd, err := db.pool.Exec(ctx, `DELETE FROM shared_resumes WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete shared resume: %w", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return fmt.Errorf("shared resume not found for user: %s", userID)
+	}
+	return nil
+}
+
+// RecordSharedResumeView records one page view or PDF download of a shared resume.
+// referrerHost is empty when the request carried no Referer.
+func (db *DB) RecordSharedResumeView(ctx context.Context, sharedID uuid.UUID, kind, referrerHost string) error {
+	var referrer *string
+	if referrerHost != "" {
+		referrer = &referrerHost
+	}
+	_, err := db.pool.Exec(ctx,
+		`INSERT INTO shared_resume_views (shared_resume_id, kind, referrer_host) VALUES ($1, $2, $3)`,
+		sharedID, kind, referrer,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record shared resume view: %w", err)
+	}
+	return nil
+}
+
+// GetSharedResumeStats summarizes the views of a shared resume page
+func (db *DB) GetSharedResumeStats(ctx context.Context, sharedID uuid.UUID) (*SharedResumeStats, error) {
+	stats := SharedResumeStats{Referrers: []ReferrerCount{}}
+	err := db.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FILTER (WHERE kind = $2), COUNT(*) FILTER (WHERE kind = $3), MAX(viewed_at)
+		 FROM shared_resume_views WHERE shared_resume_id = $1`,
+		sharedID, SharedViewPage, SharedViewPDF,
+	).Scan(&stats.PageViews, &stats.PDFDownloads, &stats.LastViewedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shared resume stats: %w", err)
+	}
+
+	rows, err := db.pool.Query(ctx,
+		`SELECT referrer_host, COUNT(*) AS views
+		 FROM shared_resume_views
+		 WHERE shared_resume_id = $1 AND referrer_host IS NOT NULL
+		 GROUP BY referrer_host
+		 ORDER BY views DESC, referrer_host
+		 LIMIT $2`,
+		sharedID, sharedReferrerLimit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared resume referrers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rc ReferrerCount
+		if err := rows.Scan(&rc.Host, &rc.Views); err != nil {
+			return nil, fmt.Errorf("failed to scan referrer: %w", err)
+		}
+		stats.Referrers = append(stats.Referrers, rc)
+	}
+	return &stats, rows.Err()
+}
+
+// CreateSharedResumeComment stores a recipient's comment on a shared resume page
+func (db *DB) CreateSharedResumeComment(ctx context.Context, sharedID uuid.UUID, author, body string) (*SharedResumeComment, error) {
+	c := SharedResumeComment{SharedResumeID: sharedID, Author: author, Body: body}
+	err := db.pool.QueryRow(ctx,
+		`INSERT INTO shared_resume_comments (shared_resume_id, author, body) VALUES ($1, $2, $3)
+		 RETURNING id, created_at`,
+		sharedID, author, body,
+	).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save shared resume comment: %w", err)
+	}
+	return &c, nil
+}
+
+// ListSharedResumeComments returns the comments left on a shared resume page, newest first
+func (db *DB) ListSharedResumeComments(ctx context.Context, sharedID uuid.UUID) ([]SharedResumeComment, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, shared_resume_id, author, body, created_at FROM shared_resume_comments
+		 WHERE shared_resume_id = $1
+		 ORDER BY created_at DESC`,
+		sharedID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared resume comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []SharedResumeComment{}
+	for rows.Next() {
+		var c SharedResumeComment
+		if err := rows.Scan(&c.ID, &c.SharedResumeID, &c.Author, &c.Body, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan shared resume comment: %w", err)
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+// RecordSharedResumeAccess adds an entry to a shared resume's access log
+func (db *DB) RecordSharedResumeAccess(ctx context.Context, input *SharedResumeAccessInput) error {
+	var detail, referrer *string
+	if input.Detail != "" {
+		detail = &input.Detail
+	}
+	if input.ReferrerHost != "" {
+		referrer = &input.ReferrerHost
+	}
+	_, err := db.pool.Exec(ctx,
+		`INSERT INTO shared_resume_access_log (shared_resume_id, action, outcome, detail, referrer_host)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		input.SharedResumeID, input.Action, input.Outcome, detail, referrer,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record shared resume access: %w", err)
+	}
+	return nil
+}
+
+// ListSharedResumeAccess returns a shared resume's most recent access log entries,
+// newest first
+func (db *DB) ListSharedResumeAccess(ctx context.Context, sharedID uuid.UUID, limit int) ([]SharedResumeAccess, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, shared_resume_id, action, outcome, detail, referrer_host, created_at
+		 FROM shared_resume_access_log
+		 WHERE shared_resume_id = $1
+		 ORDER BY created_at DESC
+		 LIMIT $2`,
+		sharedID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared resume access: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []SharedResumeAccess{}
+	for rows.Next() {
+		var a SharedResumeAccess
+		if err := rows.Scan(&a.ID, &a.SharedResumeID, &a.Action, &a.Outcome, &a.Detail,
+			&a.ReferrerHost, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan shared resume access: %w", err)
+		}
+		entries = append(entries, a)
+	}
+	return entries, rows.Err()
+}
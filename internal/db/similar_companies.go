@@ -0,0 +1,197 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Weights of each signal in a company similarity score
+const (
+	similarityIndustryWeight = 0.4
+	similarityTechWeight     = 0.35
+	similarityValuesWeight   = 0.25
+)
+
+// CompanyFingerprint holds the signals companies are compared on
+type CompanyFingerprint struct {
+	Company      Company
+	IndustryTags []string // From companies.industry and the profile's domain context
+	TechStack    []string
+	Values       []string
+	HasProfile   bool // Whether cached research (a voice profile) exists
+}
+
+// SimilarCompany is a peer company and why it was suggested
+type SimilarCompany struct {
+	Company            Company  `json:"company"`
+	Score              float64  `json:"score"` // 0.0-1.0
+	SharedIndustryTags []string `json:"shared_industry_tags"`
+	SharedTechStack    []string `json:"shared_tech_stack"`
+	SharedValues       []string `json:"shared_values"` // Value keywords both companies use
+	HasProfile         bool     `json:"has_profile"`
+}
+
+// ListCompanyFingerprints returns the similarity signals of every company with research
+// (a voice profile or a tech stack)
+func (db *DB) ListCompanyFingerprints(ctx context.Context) ([]CompanyFingerprint, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT c.id, c.name, c.name_normalized, c.domain, c.industry, c.created_at, c.updated_at,
+		        cp.id IS NOT NULL, COALESCE(cp.domain_context, ''),
+		        COALESCE((SELECT array_agg(v.value_text ORDER BY v.priority DESC)
+		                  FROM company_values v WHERE v.profile_id = cp.id), '{}'),
+		        COALESCE((SELECT array_agg(DISTINCT t.technology)
+		                  FROM company_tech_stack t WHERE t.company_id = c.id), '{}')
+		 FROM companies c
+		 LEFT JOIN company_profiles cp ON cp.company_id = c.id
+		 WHERE cp.id IS NOT NULL
+		    OR EXISTS (SELECT 1 FROM company_tech_stack t WHERE t.company_id = c.id)
+		 ORDER BY c.name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list company fingerprints: %w", err)
+	}
+	defer rows.Close()
+
+	var fingerprints []CompanyFingerprint
+	for rows.Next() {
+		var f CompanyFingerprint
+		var domainContext string
+		c := &f.Company
+		if err := rows.Scan(&c.ID, &c.Name, &c.NameNormalized, &c.Domain, &c.Industry, &c.CreatedAt, &c.UpdatedAt,
+			&f.HasProfile, &domainContext, &f.Values, &f.TechStack); err != nil {
+			return nil, fmt.Errorf("failed to scan company fingerprint: %w", err)
+		}
+		industry := ""
+		if c.Industry != nil {
+			industry = *c.Industry
+		}
+		f.IndustryTags = IndustryTags(industry, domainContext)
+		fingerprints = append(fingerprints, f)
+	}
+	return fingerprints, rows.Err()
+}
+
+// tagSeparator splits industry descriptions such as "FinTech, consumer finance / lending"
+var tagSeparator = regexp.MustCompile(`\s*(?:,|;|/|\band\b|&)\s*`)
+
+// IndustryTags splits industry descriptions into lowercase, deduplicated tags
+func IndustryTags(descriptions ...string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, desc := range descriptions {
+		for _, tag := range tagSeparator.Split(strings.ToLower(desc), -1) {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// valueStopwords are words too common in value statements to indicate similarity
+var valueStopwords = map[string]bool{
+	"with": true, "that": true, "this": true, "from": true, "your": true, "their": true,
+	"what": true, "when": true, "we're": true, "they": true, "have": true, "about": true,
+	"into": true, "more": true, "over": true, "every": true, "always": true,
+}
+
+// valueKeywords reduces value statements to their distinctive lowercase words
+func valueKeywords(values []string) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, word := range strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r == '\'' || r == '-')
+		}) {
+			word = strings.Trim(word, "'-")
+			if len(word) < 4 || valueStopwords[word] || seen[word] {
+				continue
+			}
+			seen[word] = true
+			keywords = append(keywords, word)
+		}
+	}
+	return keywords
+}
+
+// overlap returns the items of a also in b (case-insensitive, in a's order) and their Jaccard similarity
+func overlap(a, b []string) ([]string, float64) {
+	setA := make(map[string]bool, len(a))
+	for _, item := range a {
+		setA[strings.ToLower(item)] = true
+	}
+	setB := make(map[string]bool, len(b))
+	for _, item := range b {
+		setB[strings.ToLower(item)] = true
+	}
+
+	shared := []string{}
+	added := make(map[string]bool)
+	for _, item := range a {
+		key := strings.ToLower(item)
+		if setB[key] && !added[key] {
+			added[key] = true
+			shared = append(shared, item)
+		}
+	}
+	union := len(setA) + len(setB) - len(shared)
+	if union == 0 {
+		return shared, 0
+	}
+	return shared, float64(len(shared)) / float64(union)
+}
+
+// RankSimilarCompanies scores candidates against target by shared industry tags, tech stack,
+// and value keywords, returning up to limit companies with a positive score, most similar first
+func RankSimilarCompanies(target CompanyFingerprint, candidates []CompanyFingerprint, limit int) []SimilarCompany {
+	targetValues := valueKeywords(target.Values)
+
+	similar := []SimilarCompany{}
+	for _, c := range candidates {
+		if c.Company.ID == target.Company.ID {
+			continue
+		}
+		industry, industryScore := overlap(target.IndustryTags, c.IndustryTags)
+		tech, techScore := overlap(target.TechStack, c.TechStack)
+		values, valuesScore := overlap(targetValues, valueKeywords(c.Values))
+
+		score := similarityIndustryWeight*industryScore + similarityTechWeight*techScore + similarityValuesWeight*valuesScore
+		if score <= 0 {
+			continue
+		}
+		similar = append(similar, SimilarCompany{
+			Company:            c.Company,
+			Score:              score,
+			SharedIndustryTags: industry,
+			SharedTechStack:    tech,
+			SharedValues:       values,
+			HasProfile:         c.HasProfile,
+		})
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		return similar[i].Score > similar[j].Score
+	})
+	if limit > 0 && len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar
+}
+
+// FindCompanyFingerprint returns the fingerprint of companyID, or nil if it has no research
+func FindCompanyFingerprint(fingerprints []CompanyFingerprint, companyID uuid.UUID) *CompanyFingerprint {
+	for i := range fingerprints {
+		if fingerprints[i].Company.ID == companyID {
+			return &fingerprints[i]
+		}
+	}
+	return nil
+}
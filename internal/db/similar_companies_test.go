@@ -0,0 +1,59 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestIndustryTags(t *testing.T) {
+	got := IndustryTags("FinTech", "FinTech, consumer finance / lending and payments")
+	want := []string{"fintech", "consumer finance", "lending", "payments"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IndustryTags() = %v, want %v", got, want)
+	}
+	if tags := IndustryTags("", ""); len(tags) != 0 {
+		t.Errorf("IndustryTags() of empty input = %v, want none", tags)
+	}
+}
+
+func TestRankSimilarCompanies(t *testing.T) {
+	fingerprint := func(name string, industry, tech, values []string) CompanyFingerprint {
+		return CompanyFingerprint{
+			Company:      Company{ID: uuid.New(), Name: name},
+			IndustryTags: industry,
+			TechStack:    tech,
+			Values:       values,
+			HasProfile:   true,
+		}
+	}
+	target := fingerprint("Affirm", []string{"fintech", "lending"}, []string{"Go", "Kafka", "AWS"}, []string{"Customer obsession"})
+	peer := fingerprint("Klarna", []string{"fintech", "payments"}, []string{"Kafka", "AWS"}, []string{"Obsession with customers"})
+	loose := fingerprint("Stripe", []string{"payments"}, []string{"Ruby", "AWS"}, nil)
+	unrelated := fingerprint("Pixar", []string{"animation"}, []string{"C++"}, []string{"Storytelling"})
+
+	similar := RankSimilarCompanies(target, []CompanyFingerprint{target, unrelated, loose, peer}, 10)
+	if len(similar) != 2 {
+		t.Fatalf("RankSimilarCompanies() returned %d companies, want 2", len(similar))
+	}
+	if similar[0].Company.Name != "Klarna" || similar[1].Company.Name != "Stripe" {
+		t.Errorf("order = %s, %s; want Klarna, Stripe", similar[0].Company.Name, similar[1].Company.Name)
+	}
+	if !reflect.DeepEqual(similar[0].SharedIndustryTags, []string{"fintech"}) {
+		t.Errorf("SharedIndustryTags = %v", similar[0].SharedIndustryTags)
+	}
+	if !reflect.DeepEqual(similar[0].SharedTechStack, []string{"Kafka", "AWS"}) {
+		t.Errorf("SharedTechStack = %v", similar[0].SharedTechStack)
+	}
+	if !reflect.DeepEqual(similar[0].SharedValues, []string{"obsession"}) {
+		t.Errorf("SharedValues = %v", similar[0].SharedValues)
+	}
+	if similar[0].Score <= similar[1].Score || similar[0].Score > 1 {
+		t.Errorf("scores = %v, %v", similar[0].Score, similar[1].Score)
+	}
+
+	if limited := RankSimilarCompanies(target, []CompanyFingerprint{peer, loose}, 1); len(limited) != 1 {
+		t.Errorf("limit 1 returned %d companies", len(limited))
+	}
+}
@@ -0,0 +1,222 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Skill gap evidence match kinds, strongest first
+const (
+	SkillMatchTagged  = "tagged"        // The bullet is tagged with the required skill
+	SkillMatchRelated = "related_skill" // The bullet is tagged with a narrower or broader form of it
+	SkillMatchText    = "text"          // The bullet's text mentions it without tagging it
+)
+
+// SkillGap classifies a job profile's requirements by the evidence a user's experience
+// bank has for them
+type SkillGap struct {
+	PostingID    uuid.UUID       `json:"posting_id"`
+	JobProfileID uuid.UUID       `json:"job_profile_id"`
+	Matched      []SkillGapEntry `json:"matched"` // A bullet is tagged with the skill
+	Partial      []SkillGapEntry `json:"partial"` // Only related tags or text mentions
+	Missing      []SkillGapEntry `json:"missing"` // No evidence at all
+}
+
+// SkillGapEntry is one requirement and the bullets that provide evidence for it
+type SkillGapEntry struct {
+	RequirementID   uuid.UUID       `json:"requirement_id"`
+	RequirementType string          `json:"requirement_type"` // 'hard' or 'nice_to_have'
+	Skill           string          `json:"skill"`
+	Level           *string         `json:"level,omitempty"`
+	Evidence        []SkillEvidence `json:"evidence"`
+}
+
+// SkillEvidence is a bullet that provides evidence for a required skill
+type SkillEvidence struct {
+	BulletID string `json:"bullet_id"`
+	StoryID  string `json:"story_id"`
+	Text     string `json:"text"`
+	Match    string `json:"match"` // SkillMatchTagged, SkillMatchRelated, or SkillMatchText
+}
+
+// EvidenceBullet is a user's bullet with its normalized skill tags
+type EvidenceBullet struct {
+	BulletID  string
+	StoryID   string
+	Text      string
+	Skills    []string // Normalized (NormalizeSkillName)
+	Metrics   string
+	UpdatedAt time.Time
+}
+
+// GetSkillGap compares the requirements of a posting's job profile with the skills tagged
+// on userID's bullets. Returns nil if the posting has no job profile.
+func (db *DB) GetSkillGap(ctx context.Context, userID, postingID uuid.UUID) (*SkillGap, error) {
+	profile, err := db.GetJobProfileByPostingID(ctx, postingID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, nil
+	}
+	requirements, err := db.GetRequirementsByProfileID(ctx, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+	bullets, err := db.listEvidenceBullets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	gap := BuildSkillGap(requirements, bullets)
+	gap.PostingID = postingID
+	gap.JobProfileID = profile.ID
+	return gap, nil
+}
+
+// listEvidenceBullets returns userID's bullets with their skill tags, in bank order
+func (db *DB) listEvidenceBullets(ctx context.Context, userID uuid.UUID) ([]EvidenceBullet, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT b.bullet_id, st.story_id, b.text, COALESCE(b.metrics, ''), COALESCE(b.updated_at, b.created_at, NOW()),
+		        COALESCE(array_agg(sk.name_normalized) FILTER (WHERE sk.id IS NOT NULL), '{}')
+		 FROM bullets b
+		 JOIN stories st ON st.id = b.story_id
+		 LEFT JOIN bullet_skills bs ON bs.bullet_id = b.id
+		 LEFT JOIN skills sk ON sk.id = bs.skill_id
+		 WHERE st.user_id = $1
+		 GROUP BY b.id, st.story_id
+		 ORDER BY st.story_id, b.ordinal`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bullet skills: %w", err)
+	}
+	defer rows.Close()
+
+	var bullets []EvidenceBullet
+	for rows.Next() {
+		var b EvidenceBullet
+		if err := rows.Scan(&b.BulletID, &b.StoryID, &b.Text, &b.Metrics, &b.UpdatedAt, &b.Skills); err != nil {
+			return nil, fmt.Errorf("failed to scan bullet skills: %w", err)
+		}
+		bullets = append(bullets, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get bullet skills: %w", err)
+	}
+	return bullets, nil
+}
+
+// BuildSkillGap classifies each requirement: matched when a bullet is tagged with the
+// skill (after synonyms, so "Golang" matches a "go" tag), partial when bullets are only
+// tagged with a related skill ("AWS" for "AWS Lambda") or mention it in their text, and
+// missing otherwise. Evidence lists the bullets of the strongest kind found, and repeated
+// skills are listed once, under their first requirement.
+func BuildSkillGap(requirements []JobRequirement, bullets []EvidenceBullet) *SkillGap {
+	gap := &SkillGap{Matched: []SkillGapEntry{}, Partial: []SkillGapEntry{}, Missing: []SkillGapEntry{}}
+	seen := make(map[string]bool)
+	for _, req := range requirements {
+		skill := NormalizeSkillName(req.Skill)
+		if skill == "" || seen[skill] {
+			continue
+		}
+		seen[skill] = true
+
+		entry := SkillGapEntry{
+			RequirementID:   req.ID,
+			RequirementType: req.RequirementType,
+			Skill:           req.Skill,
+			Level:           req.Level,
+			Evidence:        []SkillEvidence{},
+		}
+		best := ""
+		for _, b := range bullets {
+			match := skillEvidenceMatch(skill, req.Skill, b)
+			if match == "" || matchRank(match) < matchRank(best) {
+				continue
+			}
+			if matchRank(match) > matchRank(best) {
+				best = match
+				entry.Evidence = entry.Evidence[:0]
+			}
+			entry.Evidence = append(entry.Evidence, SkillEvidence{BulletID: b.BulletID, StoryID: b.StoryID, Text: b.Text, Match: match})
+		}
+
+		switch best {
+		case SkillMatchTagged:
+			gap.Matched = append(gap.Matched, entry)
+		case "":
+			gap.Missing = append(gap.Missing, entry)
+		default:
+			gap.Partial = append(gap.Partial, entry)
+		}
+	}
+	return gap
+}
+
+// skillEvidenceMatch returns how bullet b provides evidence for a normalized skill, or ""
+func skillEvidenceMatch(skill, rawSkill string, b EvidenceBullet) string {
+	match := ""
+	for _, tag := range b.Skills {
+		if tag == skill {
+			return SkillMatchTagged
+		}
+		if containsWords(tag, skill) || containsWords(skill, tag) {
+			match = SkillMatchRelated
+		}
+	}
+	if match == "" && (mentions(b.Text, skill) || mentions(b.Text, rawSkill)) {
+		match = SkillMatchText
+	}
+	return match
+}
+
+// matchRank orders match kinds from no match (0) to tagged
+func matchRank(match string) int {
+	switch match {
+	case SkillMatchTagged:
+		return 3
+	case SkillMatchRelated:
+		return 2
+	case SkillMatchText:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// containsWords reports whether every word of part appears in whole, which has more words
+func containsWords(whole, part string) bool {
+	wholeWords, partWords := strings.Fields(whole), strings.Fields(part)
+	if len(partWords) == 0 || len(partWords) >= len(wholeWords) {
+		return false
+	}
+	for _, w := range partWords {
+		found := false
+		for _, candidate := range wholeWords {
+			if candidate == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// mentions reports whether text contains skill as a whole word, ignoring case
+func mentions(text, skill string) bool {
+	skill = strings.TrimSpace(skill)
+	if skill == "" {
+		return false
+	}
+	pattern := `(?i)(^|[^\pL\pN])` + regexp.QuoteMeta(skill) + `($|[^\pL\pN])`
+	return regexp.MustCompile(pattern).MatchString(text)
+}
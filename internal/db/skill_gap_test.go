@@ -0,0 +1,56 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildSkillGap(t *testing.T) {
+	requirement := func(reqType, skill string) JobRequirement {
+		return JobRequirement{ID: uuid.New(), RequirementType: reqType, Skill: skill}
+	}
+	requirements := []JobRequirement{
+		requirement("hard", "Golang"),
+		requirement("hard", "AWS Lambda"),
+		requirement("hard", "Terraform"),
+		requirement("nice_to_have", "Rust"),
+		requirement("nice_to_have", "Go"), // Repeats Golang
+	}
+	bullets := []EvidenceBullet{
+		{BulletID: "b1", StoryID: "s1", Text: "Built services in Go", Skills: []string{"go", "aws"}},
+		{BulletID: "b2", StoryID: "s1", Text: "Provisioned infrastructure with Terraform modules", Skills: []string{"go"}},
+		{BulletID: "b3", StoryID: "s2", Text: "Trusted by every team", Skills: nil},
+	}
+
+	gap := BuildSkillGap(requirements, bullets)
+
+	if len(gap.Matched) != 1 || gap.Matched[0].Skill != "Golang" {
+		t.Fatalf("Matched = %+v, want Golang only", gap.Matched)
+	}
+	if ev := gap.Matched[0].Evidence; len(ev) != 2 || ev[0].BulletID != "b1" || ev[1].BulletID != "b2" || ev[0].Match != SkillMatchTagged {
+		t.Errorf("Golang evidence = %+v, want tagged b1 and b2", ev)
+	}
+
+	if len(gap.Partial) != 2 {
+		t.Fatalf("Partial = %+v, want AWS Lambda and Terraform", gap.Partial)
+	}
+	if p := gap.Partial[0]; p.Skill != "AWS Lambda" || len(p.Evidence) != 1 || p.Evidence[0].Match != SkillMatchRelated {
+		t.Errorf("Partial[0] = %+v, want AWS Lambda from the related aws tag", p)
+	}
+	if p := gap.Partial[1]; p.Skill != "Terraform" || len(p.Evidence) != 1 || p.Evidence[0].BulletID != "b2" || p.Evidence[0].Match != SkillMatchText {
+		t.Errorf("Partial[1] = %+v, want Terraform from b2's text", p)
+	}
+
+	// "Rust" must not match "Trusted"
+	if len(gap.Missing) != 1 || gap.Missing[0].Skill != "Rust" || len(gap.Missing[0].Evidence) != 0 {
+		t.Errorf("Missing = %+v, want Rust with no evidence", gap.Missing)
+	}
+}
+
+func TestBuildSkillGapEmpty(t *testing.T) {
+	gap := BuildSkillGap(nil, nil)
+	if gap.Matched == nil || gap.Partial == nil || gap.Missing == nil {
+		t.Errorf("BuildSkillGap() = %+v, want empty, non-nil lists", gap)
+	}
+}
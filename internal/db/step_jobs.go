@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// -----------------------------------------------------------------------------
+// Step Job Queue Methods
+// -----------------------------------------------------------------------------
+
+const stepJobColumns = `id, run_id, step, backend, payload, status, result, error_message,
+	worker_id, attempts, created_at, started_at, completed_at`
+
+// scanStepJob scans a step_jobs row selected with stepJobColumns
+func scanStepJob(row pgx.Row) (*StepJob, error) {
+	var job StepJob
+	var payload, result []byte
+	if err := row.Scan(&job.ID, &job.RunID, &job.Step, &job.Backend, &payload, &job.Status, &result,
+		&job.ErrorMessage, &job.WorkerID, &job.Attempts, &job.CreatedAt, &job.StartedAt, &job.CompletedAt); err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+	if len(result) > 0 {
+		job.Result = result
+	}
+	return &job, nil
+}
+
+// EnqueueStepJob queues a step and notifies listeners on StepJobsChannel.
+// backend is "worker" for the shared queue; other backends claim jobs by ID.
+// runID may be uuid.Nil for work not tied to a pipeline run.
+func (db *DB) EnqueueStepJob(ctx context.Context, runID uuid.UUID, step, backend string, payload any) (uuid.UUID, error) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to marshal step job payload: %w", err)
+	}
+
+	var runIDArg *uuid.UUID
+	if runID != uuid.Nil {
+		runIDArg = &runID
+	}
+
+	var id uuid.UUID
+	err = db.pool.QueryRow(ctx,
+		`INSERT INTO step_jobs (run_id, step, backend, payload)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id`,
+		runIDArg, step, backend, payloadJSON,
+	).Scan(&id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to enqueue step job: %w", err)
+	}
+
+	if backend != StepJobBackendWorker {
+		return id, nil // Not claimable from the shared queue
+	}
+	if err := db.Notify(ctx, StepJobsChannel, step); err != nil {
+		return id, err
+	}
+	return id, nil
+}
+
+// ClaimStepJob atomically claims the oldest queued shared-queue job for one of the given steps.
+// Concurrent workers never claim the same job (FOR UPDATE SKIP LOCKED).
+// Returns nil if no job is available.
+func (db *DB) ClaimStepJob(ctx context.Context, workerID string, steps []string) (*StepJob, error) {
+	row := db.pool.QueryRow(ctx,
+		`UPDATE step_jobs
+		 SET status = 'running', worker_id = $1, attempts = attempts + 1,
+		     started_at = NOW(), updated_at = NOW()
+		 WHERE id = (
+		     SELECT id FROM step_jobs
+		     WHERE status = 'queued' AND backend = 'worker' AND step = ANY($2)
+		     ORDER BY created_at
+		     FOR UPDATE SKIP LOCKED
+		     LIMIT 1
+		 )
+		 RETURNING `+stepJobColumns,
+		workerID, steps,
+	)
+	job, err := scanStepJob(row)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim step job: %w", err)
+	}
+	return job, nil
+}
+
+// ClaimStepJobByID claims a specific queued job, as done by a dedicated
+// Kubernetes Job. Returns nil if the job is missing or already claimed.
+func (db *DB) ClaimStepJobByID(ctx context.Context, id uuid.UUID, workerID string) (*StepJob, error) {
+	row := db.pool.QueryRow(ctx,
+		`UPDATE step_jobs
+		 SET status = 'running', worker_id = $2, attempts = attempts + 1,
+		     started_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status = 'queued'
+		 RETURNING `+stepJobColumns,
+		id, workerID,
+	)
+	job, err := scanStepJob(row)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim step job %s: %w", id, err)
+	}
+	return job, nil
+}
+
+// GetStepJob retrieves a step job by ID
+func (db *DB) GetStepJob(ctx context.Context, id uuid.UUID) (*StepJob, error) {
+	row := db.pool.QueryRow(ctx, `SELECT `+stepJobColumns+` FROM step_jobs WHERE id = $1`, id)
+	job, err := scanStepJob(row)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get step job: %w", err)
+	}
+	return job, nil
+}
+
+// CompleteStepJob stores a job's result and notifies StepJobDoneChannel
+func (db *DB) CompleteStepJob(ctx context.Context, id uuid.UUID, result any) error {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal step job result: %w", err)
+	}
+
+	_, err = db.pool.Exec(ctx,
+		`UPDATE step_jobs
+		 SET status = 'completed', result = $2, completed_at = NOW(), updated_at = NOW()
+		 WHERE id = $1`,
+		id, resultJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete step job: %w", err)
+	}
+	return db.Notify(ctx, StepJobDoneChannel, id.String())
+}
+
+// FailStepJob records a job failure and notifies StepJobDoneChannel.
+// Jobs that already finished keep their outcome.
+func (db *DB) FailStepJob(ctx context.Context, id uuid.UUID, errMsg string) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE step_jobs
+		 SET status = 'failed', error_message = $2, completed_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND status IN ('queued', 'running')`,
+		id, errMsg,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to fail step job: %w", err)
+	}
+	return db.Notify(ctx, StepJobDoneChannel, id.String())
+}
+
+// HeartbeatStepJob marks a running job as still alive so it is not requeued as stale
+func (db *DB) HeartbeatStepJob(ctx context.Context, id uuid.UUID) error {
+	_, err := db.pool.Exec(ctx,
+		`UPDATE step_jobs SET updated_at = NOW() WHERE id = $1 AND status = 'running'`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to heartbeat step job: %w", err)
+	}
+	return nil
+}
+
+// RequeueStaleStepJobs returns shared-queue jobs whose worker has not sent a heartbeat
+// for staleAfter to the queue, recovering work from crashed workers. Returns the
+// number of jobs requeued.
+func (db *DB) RequeueStaleStepJobs(ctx context.Context, staleAfter time.Duration) (int64, error) {
+	tag, err := db.pool.Exec(ctx,
+		`UPDATE step_jobs
+		 SET status = 'queued', worker_id = NULL, started_at = NULL, updated_at = NOW()
+		 WHERE status = 'running' AND backend = 'worker' AND updated_at < NOW() - make_interval(secs => $1)`,
+		staleAfter.Seconds(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale step jobs: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// RequeueStepJob returns a failed shared-queue job to the queue so a worker runs it
+// again, and notifies StepJobsChannel. It returns false if the job does not exist,
+// has not failed, or belongs to a backend that claims jobs by ID.
+func (db *DB) RequeueStepJob(ctx context.Context, id uuid.UUID) (bool, error) {
+	var step string
+	err := db.pool.QueryRow(ctx,
+		`UPDATE step_jobs
+		 SET status = 'queued', worker_id = NULL, error_message = NULL, started_at = NULL,
+		     completed_at = NULL, updated_at = NOW()
+		 WHERE id = $1 AND status = 'failed' AND backend = $2
+		 RETURNING step`,
+		id, StepJobBackendWorker,
+	).Scan(&step)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to requeue step job: %w", err)
+	}
+	return true, db.Notify(ctx, StepJobsChannel, step)
+}
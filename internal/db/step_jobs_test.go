@@ -0,0 +1,33 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepJobStatusConstants(t *testing.T) {
+	assert.Equal(t, "queued", StepJobStatusQueued)
+	assert.Equal(t, "running", StepJobStatusRunning)
+	assert.Equal(t, "completed", StepJobStatusCompleted)
+	assert.Equal(t, "failed", StepJobStatusFailed)
+}
+
+func TestStepJobDone(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{StepJobStatusQueued, false},
+		{StepJobStatusRunning, false},
+		{StepJobStatusCompleted, true},
+		{StepJobStatusFailed, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			job := &StepJob{Status: tt.status}
+			assert.Equal(t, tt.want, job.Done())
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LatencyBuckets are the bucket widths ListLatencyStats accepts
+var LatencyBuckets = []string{"hour", "day", "week"}
+
+// ListLatencyStats returns p50/p95 durations of completed steps, and of completed runs
+// as LatencyRun, since a time: one row per step and bucket, plus one per step with a
+// nil Bucket for the whole window. budgetsMs maps step names to latency budgets;
+// executions of steps without one are never counted over budget.
+func (db *DB) ListLatencyStats(ctx context.Context, since time.Time, bucket string, budgetsMs map[string]int64) ([]LatencyStats, error) {
+	steps := make([]string, 0, len(budgetsMs))
+	budgets := make([]int64, 0, len(budgetsMs))
+	for step, ms := range budgetsMs {
+		steps = append(steps, step)
+		budgets = append(budgets, ms)
+	}
+
+	rows, err := db.pool.Query(ctx,
+		`WITH budgets AS (
+		     SELECT * FROM unnest($3::text[], $4::bigint[]) AS b(step, budget_ms)
+		 ), samples AS (
+		     SELECT step::text AS step, completed_at, duration_ms::bigint AS duration_ms FROM run_steps
+		      WHERE status = 'completed' AND duration_ms IS NOT NULL AND completed_at >= $1
+		     UNION ALL
+		     SELECT $5::text, completed_at, (EXTRACT(EPOCH FROM completed_at - created_at) * 1000)::bigint
+		       FROM pipeline_runs
+		      WHERE status = 'completed' AND completed_at >= $1
+		 )
+		 SELECT s.step, date_trunc($2, s.completed_at AT TIME ZONE 'UTC') AS bucket, COUNT(*),
+		        percentile_cont(0.5) WITHIN GROUP (ORDER BY s.duration_ms),
+		        percentile_cont(0.95) WITHIN GROUP (ORDER BY s.duration_ms),
+		        COUNT(*) FILTER (WHERE s.duration_ms > b.budget_ms)
+		   FROM samples s LEFT JOIN budgets b ON b.step = s.step
+		  GROUP BY GROUPING SETS ((s.step, date_trunc($2, s.completed_at AT TIME ZONE 'UTC')), (s.step))
+		  ORDER BY s.step, bucket NULLS FIRST`,
+		since, bucket, steps, budgets, LatencyRun,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list latency stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []LatencyStats{}
+	for rows.Next() {
+		var st LatencyStats
+		var p50, p95 float64
+		if err := rows.Scan(&st.Step, &st.Bucket, &st.Count, &p50, &p95, &st.OverBudget); err != nil {
+			return nil, fmt.Errorf("failed to scan latency stats: %w", err)
+		}
+		st.P50Ms, st.P95Ms = int64(p50), int64(p95)
+		if st.Bucket != nil {
+			utc := st.Bucket.UTC()
+			st.Bucket = &utc
+		}
+		stats = append(stats, st)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const bulletSuggestionColumns = `id, user_id, run_id, skill, source, level, evidence, question,
+	text, status, story_id, created_at, updated_at`
+
+func scanBulletSuggestion(row pgx.Row) (*BulletSuggestion, error) {
+	var s BulletSuggestion
+	err := row.Scan(&s.ID, &s.UserID, &s.RunID, &s.Skill, &s.Source, &s.Level, &s.Evidence,
+		&s.Question, &s.Text, &s.Status, &s.StoryID, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// UpsertBulletSuggestions queues suggestions from a run for the user's review. Pending
+// suggestions for a skill already queued are refreshed; accepted or dismissed ones are
+// left as reviewed.
+func (db *DB) UpsertBulletSuggestions(ctx context.Context, userID, runID uuid.UUID, suggestions []BulletSuggestionInput) error {
+	batch := &pgx.Batch{}
+	for _, s := range suggestions {
+		batch.Queue(
+			`INSERT INTO bullet_suggestions (user_id, run_id, skill, source, level, evidence, question, text)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			 ON CONFLICT (user_id, lower(skill)) DO UPDATE SET
+			     run_id = EXCLUDED.run_id,
+			     source = EXCLUDED.source,
+			     level = EXCLUDED.level,
+			     evidence = EXCLUDED.evidence,
+			     question = EXCLUDED.question,
+			     text = EXCLUDED.text,
+			     updated_at = NOW()
+			 WHERE bullet_suggestions.status = 'pending'`,
+			userID, runID, s.Skill, s.Source, nullIfEmpty(s.Level), nullIfEmpty(s.Evidence), s.Question, s.Text,
+		)
+	}
+	if err := db.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to save bullet suggestions: %w", err)
+	}
+	return nil
+}
+
+// ListBulletSuggestions returns the user's suggestions, newest first. An empty status lists all.
+func (db *DB) ListBulletSuggestions(ctx context.Context, userID uuid.UUID, status string) ([]BulletSuggestion, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+bulletSuggestionColumns+` FROM bullet_suggestions
+		 WHERE user_id = $1 AND ($2 = '' OR status = $2)
+		 ORDER BY updated_at DESC, source, skill`,
+		userID, status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bullet suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []BulletSuggestion{}
+	for rows.Next() {
+		s, err := scanBulletSuggestion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan bullet suggestion: %w", err)
+		}
+		suggestions = append(suggestions, *s)
+	}
+	return suggestions, rows.Err()
+}
+
+// GetBulletSuggestion returns a suggestion by ID, or nil if not found
+func (db *DB) GetBulletSuggestion(ctx context.Context, id uuid.UUID) (*BulletSuggestion, error) {
+	s, err := scanBulletSuggestion(db.pool.QueryRow(ctx,
+		`SELECT `+bulletSuggestionColumns+` FROM bullet_suggestions WHERE id = $1`, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get bullet suggestion: %w", err)
+	}
+	return s, nil
+}
+
+// AcceptBulletSuggestion adds a pending suggestion the user confirmed to the experience
+// bank as a story under jobID, with text (the bullet as the user completed it) as its
+// only bullet. It returns nil if the suggestion is no longer pending.
+func (db *DB) AcceptBulletSuggestion(ctx context.Context, id, jobID uuid.UUID, text string) (*BulletSuggestion, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	s, err := scanBulletSuggestion(tx.QueryRow(ctx,
+		`SELECT `+bulletSuggestionColumns+` FROM bullet_suggestions
+		 WHERE id = $1 AND status = 'pending' FOR UPDATE`, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get bullet suggestion: %w", err)
+	}
+
+	story, err := createStory(ctx, tx, &StoryCreateInput{
+		StoryID: "suggestion-" + s.ID.String(),
+		UserID:  s.UserID,
+		JobID:   jobID,
+		Title:   s.Skill,
+		Bullets: []BulletCreateInput{{
+			BulletID: "suggestion-" + s.ID.String(),
+			Text:     text,
+			Skills:   []string{s.Skill},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s, err = scanBulletSuggestion(tx.QueryRow(ctx,
+		`UPDATE bullet_suggestions SET status = 'accepted', text = $2, story_id = $3, updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+bulletSuggestionColumns,
+		id, text, story.ID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept bullet suggestion: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return s, nil
+}
+
+// DismissBulletSuggestion marks a pending suggestion as dismissed so later runs do not
+// ask again. It returns nil if the suggestion is no longer pending.
+func (db *DB) DismissBulletSuggestion(ctx context.Context, id uuid.UUID) (*BulletSuggestion, error) {
+	s, err := scanBulletSuggestion(db.pool.QueryRow(ctx,
+		`UPDATE bullet_suggestions SET status = 'dismissed', updated_at = NOW()
+		 WHERE id = $1 AND status = 'pending'
+		 RETURNING `+bulletSuggestionColumns, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to dismiss bullet suggestion: %w", err)
+	}
+	return s, nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// RecordCompanyTechStack adds technology mentions from one source to a company's tech stack
+func (db *DB) RecordCompanyTechStack(ctx context.Context, companyID uuid.UUID, source string, mentions []TechMentionInput) error {
+	if source != TechSourceJobPosting && source != TechSourceBlog {
+		return fmt.Errorf("invalid tech stack source %q", source)
+	}
+	for _, m := range mentions {
+		if m.Technology == "" || m.Mentions <= 0 {
+			continue
+		}
+		_, err := db.pool.Exec(ctx,
+			`INSERT INTO company_tech_stack (company_id, technology, category, source, mentions)
+			 VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (company_id, technology, source) DO UPDATE SET
+				mentions = company_tech_stack.mentions + EXCLUDED.mentions,
+				category = COALESCE(EXCLUDED.category, company_tech_stack.category),
+				last_seen_at = NOW()`,
+			companyID, m.Technology, nullIfEmpty(m.Category), source, m.Mentions,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record tech stack: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListCompanyTechStack returns a company's technologies, most-mentioned first across all sources.
+// A limit of zero returns every technology.
+func (db *DB) ListCompanyTechStack(ctx context.Context, companyID uuid.UUID, limit int) ([]CompanyTechnology, error) {
+	query := `SELECT technology, MAX(category), SUM(mentions)::int,
+			array_agg(DISTINCT source ORDER BY source), MAX(last_seen_at)
+		 FROM company_tech_stack
+		 WHERE company_id = $1
+		 GROUP BY technology
+		 ORDER BY SUM(mentions) DESC, technology`
+	args := []any{companyID}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tech stack: %w", err)
+	}
+	defer rows.Close()
+
+	var stack []CompanyTechnology
+	for rows.Next() {
+		var t CompanyTechnology
+		if err := rows.Scan(&t.Technology, &t.Category, &t.Mentions, &t.Sources, &t.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tech stack: %w", err)
+		}
+		stack = append(stack, t)
+	}
+	return stack, rows.Err()
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Application interaction kinds
+const (
+	InteractionEmail     = "email"
+	InteractionCall      = "call"
+	InteractionMessage   = "message"
+	InteractionInterview = "interview"
+	InteractionOffer     = "offer"
+	InteractionRejection = "rejection"
+	InteractionNote      = "note"
+)
+
+// InteractionKinds lists every interaction kind
+var InteractionKinds = []string{InteractionEmail, InteractionCall, InteractionMessage, InteractionInterview,
+	InteractionOffer, InteractionRejection, InteractionNote}
+
+// ApplicationTracker holds a user's notes on their applications to one company and the
+// next action they plan there
+type ApplicationTracker struct {
+	ID                uuid.UUID  `json:"id"`
+	UserID            uuid.UUID  `json:"user_id"`
+	Company           string     `json:"company"`
+	Notes             string     `json:"notes"`
+	NextAction        *string    `json:"next_action,omitempty"`
+	NextActionAt      *time.Time `json:"next_action_at,omitempty"`
+	LastInteractionAt *time.Time `json:"last_interaction_at,omitempty"` // Most recent logged interaction
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// ApplicationTrackerInput is a tracker to create or replace
+type ApplicationTrackerInput struct {
+	Company      string
+	Notes        string
+	NextAction   string
+	NextActionAt *time.Time
+}
+
+// ApplicationContact is a person at a tracked company
+type ApplicationContact struct {
+	ID          uuid.UUID `json:"id"`
+	TrackerID   uuid.UUID `json:"tracker_id"`
+	Name        string    `json:"name"`
+	Role        *string   `json:"role,omitempty"` // e.g. "Recruiter"
+	Email       *string   `json:"email,omitempty"`
+	LinkedInURL *string   `json:"linkedin_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ApplicationContactInput is a contact to create or replace
+type ApplicationContactInput struct {
+	Name        string
+	Role        string
+	Email       string
+	LinkedInURL string
+}
+
+// ApplicationInteraction is an entry in a tracker's interaction log
+type ApplicationInteraction struct {
+	ID         uuid.UUID  `json:"id"`
+	TrackerID  uuid.UUID  `json:"tracker_id"`
+	ContactID  *uuid.UUID `json:"contact_id,omitempty"`
+	Kind       string     `json:"kind"`
+	OccurredAt time.Time  `json:"occurred_at"`
+	Notes      string     `json:"notes"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ApplicationInteractionInput is an interaction to log
+type ApplicationInteractionInput struct {
+	ContactID  *uuid.UUID
+	Kind       string
+	OccurredAt time.Time
+	Notes      string
+}
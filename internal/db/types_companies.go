@@ -21,6 +21,28 @@ type Company struct {
 	UpdatedAt      time.Time `json:"updated_at"`
 }
 
+// CompanyTechnology is a technology in a company's tech stack, aggregated across sources
+type CompanyTechnology struct {
+	Technology string    `json:"technology"`
+	Category   *string   `json:"category,omitempty"`
+	Mentions   int       `json:"mentions"`
+	Sources    []string  `json:"sources"` // 'job_posting', 'blog'
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// TechMentionInput records how often a source mentioned a technology
+type TechMentionInput struct {
+	Technology string
+	Category   string
+	Mentions   int
+}
+
+// Tech stack source constants
+const (
+	TechSourceJobPosting = "job_posting"
+	TechSourceBlog       = "blog" // Engineering blog and other researched company pages
+)
+
 // CompanyDomain represents a domain associated with a company
 type CompanyDomain struct {
 	ID         uuid.UUID `json:"id"`
@@ -46,6 +68,9 @@ type CrawledPage struct {
 	IsPermanentFailure bool       `json:"is_permanent_failure"`
 	RetryCount         int        `json:"retry_count"`
 	RetryAfter         *time.Time `json:"retry_after,omitempty"`
+	// Provenance
+	SourceType  string  `json:"source_type"` // 'live' or 'archive'
+	SnapshotURL *string `json:"snapshot_url,omitempty"`
 	// Timestamps
 	FetchedAt      time.Time  `json:"fetched_at"`
 	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
@@ -74,6 +99,12 @@ const (
 	FetchStatusBlocked  = "blocked"   // 403/429 - blocked by server
 )
 
+// PageSource constants for where a crawled page's content came from
+const (
+	PageSourceLive    = "live"    // Fetched from the site itself
+	PageSourceArchive = "archive" // Internet Archive snapshot of a dead or blocked page
+)
+
 // DomainType constants for company domains
 const (
 	DomainTypePrimary           = "primary"
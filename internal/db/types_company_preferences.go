@@ -0,0 +1,31 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CompanyPreference is a named tailoring preset a user saved for a target company
+type CompanyPreference struct {
+	ID            uuid.UUID `json:"id"`
+	UserID        uuid.UUID `json:"user_id"`
+	Company       string    `json:"company"`
+	Name          string    `json:"name"`
+	ToneOverride  *string   `json:"tone_override,omitempty"` // Replaces the researched company tone
+	PinnedBullets []string  `json:"pinned_bullets"`          // Bullet IDs always placed on the resume
+	Template      *string   `json:"template,omitempty"`
+	SectionOrder  []string  `json:"section_order"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CompanyPreferenceInput is a preset to create or replace
+type CompanyPreferenceInput struct {
+	Company       string
+	Name          string
+	ToneOverride  string
+	PinnedBullets []string
+	Template      string
+	SectionOrder  []string
+}
@@ -9,14 +9,20 @@ import (
 // DefaultProfileCacheTTL is how long before a profile is considered stale
 const DefaultProfileCacheTTL = 30 * 24 * time.Hour // 30 days
 
+// ProfileSourceManual marks a profile a user entered by hand for a company with nothing
+// to crawl
+const ProfileSourceManual = "manual"
+
 // CompanyProfile represents a summarized company voice/style
 type CompanyProfile struct {
 	ID             uuid.UUID  `json:"id"`
 	CompanyID      uuid.UUID  `json:"company_id"`
+	UserID         *uuid.UUID `json:"user_id,omitempty"` // Owner of a manual profile; crawled profiles are shared
 	Company        *Company   `json:"company,omitempty"` // joined
 	Tone           string     `json:"tone"`
 	DomainContext  *string    `json:"domain_context,omitempty"`
-	SourceCorpus   *string    `json:"-"` // Don't serialize (large)
+	SourceCorpus   *string    `json:"-"`                     // Don't serialize (large)
+	SourceType     string     `json:"source_type,omitempty"` // ProfileSourceManual, or empty for crawled profiles
 	Version        int        `json:"version"`
 	LastVerifiedAt *time.Time `json:"last_verified_at,omitempty"`
 	CreatedAt      time.Time  `json:"created_at"`
@@ -27,6 +33,9 @@ type CompanyProfile struct {
 	TabooPhrases []string `json:"taboo_phrases,omitempty"`
 	Values       []string `json:"values,omitempty"`
 	EvidenceURLs []string `json:"evidence_urls,omitempty"`
+
+	// TabooReasons maps taboo phrases to why they are avoided, for phrases stored with a reason
+	TabooReasons map[string]string `json:"taboo_reasons,omitempty"`
 }
 
 // CompanyStyleRule represents a writing style rule
@@ -97,6 +106,7 @@ const (
 	SourceTypeCareers     = "careers"
 	SourceTypeEngineering = "engineering"
 	SourceTypeBlog        = "blog"
+	SourceTypeArchive     = "archive" // Internet Archive snapshot of a dead or blocked page
 )
 
 // ProfileCreateInput is used when creating a new company profile
@@ -136,3 +146,51 @@ func (p *CompanyProfile) IsStale(maxAge time.Duration) bool {
 func (p *CompanyProfile) NeedsUpdate(currentVersion int) bool {
 	return p.Version < currentVersion
 }
+
+// CompanyProfileVersion is a company profile as one generation left it, kept when the
+// profile is regenerated
+type CompanyProfileVersion struct {
+	ID            uuid.UUID            `json:"id"`
+	CompanyID     uuid.UUID            `json:"company_id"`
+	ProfileID     uuid.UUID            `json:"profile_id"`
+	Version       int                  `json:"version"`
+	Tone          string               `json:"tone"`
+	DomainContext *string              `json:"domain_context,omitempty"`
+	StyleRules    []string             `json:"style_rules"`
+	TabooPhrases  []VersionTabooPhrase `json:"taboo_phrases"`
+	Values        []string             `json:"values"`
+	EvidenceURLs  []string             `json:"evidence_urls"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+// VersionTabooPhrase is a taboo phrase as one profile version had it
+type VersionTabooPhrase struct {
+	Phrase string `json:"phrase"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ProfileVersionDiff is what changed in a company profile between two versions
+type ProfileVersionDiff struct {
+	CompanyID     uuid.UUID          `json:"company_id"`
+	From          int                `json:"from"`
+	To            int                `json:"to"`
+	Changed       bool               `json:"changed"`
+	Tone          *ProfileTextChange `json:"tone,omitempty"`           // nil when unchanged
+	DomainContext *ProfileTextChange `json:"domain_context,omitempty"` // nil when unchanged
+	Values        ProfileListChange  `json:"values"`
+	TabooPhrases  ProfileListChange  `json:"taboo_phrases"`
+	StyleRules    ProfileListChange  `json:"style_rules"`
+	EvidenceURLs  ProfileListChange  `json:"evidence_urls"`
+}
+
+// ProfileTextChange is a profile field's value in the two versions compared
+type ProfileTextChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ProfileListChange is the entries of a profile list one version added or removed
+type ProfileListChange struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Dead letter sources: the background work a dead letter came from
+const (
+	DeadLetterWebhook = "webhook"  // Notification whose webhook delivery failed
+	DeadLetterEmail   = "email"    // Notification whose email delivery failed
+	DeadLetterStepJob = "step_job" // Step job a worker could not complete
+)
+
+// DeadLetterSources lists every dead letter source
+var DeadLetterSources = []string{DeadLetterWebhook, DeadLetterEmail, DeadLetterStepJob}
+
+// Dead letter statuses
+const (
+	DeadLetterPending   = "pending"
+	DeadLetterRetried   = "retried"
+	DeadLetterDiscarded = "discarded"
+)
+
+// DeadLetter is failed background work held for an operator to retry or discard
+type DeadLetter struct {
+	ID           uuid.UUID       `json:"id"`
+	Source       string          `json:"source"`
+	RefKey       string          `json:"ref_key"`
+	UserID       *uuid.UUID      `json:"user_id,omitempty"`
+	RunID        *uuid.UUID      `json:"run_id,omitempty"`
+	Payload      json.RawMessage `json:"payload"`
+	ErrorMessage string          `json:"error_message"`
+	Attempts     int             `json:"attempts"`
+	Status       string          `json:"status"`
+	CreatedAt    time.Time       `json:"created_at"`
+	LastFailedAt time.Time       `json:"last_failed_at"`
+	ResolvedAt   *time.Time      `json:"resolved_at,omitempty"`
+}
+
+// DeadLetterInput is the input for recording a failure. Recording the same source and
+// RefKey again counts another attempt and makes the dead letter pending again.
+type DeadLetterInput struct {
+	Source       string
+	RefKey       string
+	UserID       *uuid.UUID
+	RunID        *uuid.UUID
+	Payload      any
+	ErrorMessage string
+}
+
+// DeadLetterFilters narrows a dead letter listing
+type DeadLetterFilters struct {
+	Source string // Empty for every source
+	Status string // Empty for every status
+	Limit  int
+}
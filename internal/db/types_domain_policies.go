@@ -0,0 +1,32 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain policy actions
+const (
+	DomainPolicyBlock = "block" // Never crawl the domain
+	DomainPolicyAllow = "allow" // Always crawl and prefer the domain
+)
+
+// DomainPolicy is a crawl rule for a domain and its subdomains.
+// Policies without a UserID are global and managed by admins.
+type DomainPolicy struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	Domain    string     `json:"domain"`
+	Action    string     `json:"action"`
+	Reason    *string    `json:"reason,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// DomainPolicyInput is the input for creating or replacing a domain policy
+type DomainPolicyInput struct {
+	UserID *uuid.UUID
+	Domain string
+	Action string
+	Reason string
+}
@@ -0,0 +1,19 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerification is a verification link sent to a user's email address. Only the
+// SHA-256 of the link's token is kept.
+type EmailVerification struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Email     string     `json:"email"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
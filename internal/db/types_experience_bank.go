@@ -69,6 +69,22 @@ type Bullet struct {
 	Skills []string `json:"skills,omitempty"`
 }
 
+// Skill proficiency bounds for self-assessment
+const (
+	MinProficiency = 1 // Basic familiarity
+	MaxProficiency = 5 // Expert
+)
+
+// UserSkill is a user's self-assessment of a catalog skill
+type UserSkill struct {
+	UserID      uuid.UUID `json:"user_id"`
+	SkillID     uuid.UUID `json:"skill_id"`
+	Skill       string    `json:"skill"`
+	Proficiency *int      `json:"proficiency,omitempty"` // MinProficiency to MaxProficiency
+	LastUsed    *Date     `json:"last_used,omitempty"`   // Self-reported; first day of the month
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 // BulletSkill represents the many-to-many relationship
 type BulletSkill struct {
 	BulletID uuid.UUID `json:"bullet_id"`
@@ -107,9 +123,9 @@ type BulletCreateInput struct {
 
 // ExperienceBankImportInput matches the experience_bank.json structure
 type ExperienceBankImportInput struct {
-	UserID    uuid.UUID
-	Stories   []StoryImportInput
-	Education []EducationImportInput
+	UserID    uuid.UUID              `json:"-"`
+	Stories   []StoryImportInput     `json:"stories"`
+	Education []EducationImportInput `json:"education"`
 }
 
 // StoryImportInput matches the story structure in experience_bank.json
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Review statuses of project drafts and bullet suggestions
+const (
+	DraftStatusPending   = "pending"   // Waiting for review
+	DraftStatusAccepted  = "accepted"  // Added to the experience bank
+	DraftStatusDismissed = "dismissed" // Rejected; later syncs and runs leave it alone
+)
+
+// GitHubAccount is the GitHub profile a user imports project bullets from
+type GitHubAccount struct {
+	UserID       uuid.UUID  `json:"user_id"`
+	Username     string     `json:"username"`
+	TokenSealed  *string    `json:"-"` // Encrypted access token
+	HasToken     bool       `json:"has_token"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ProjectDraft is a project bullet drafted from a GitHub repository, pending review
+type ProjectDraft struct {
+	ID          uuid.UUID   `json:"id"`
+	UserID      uuid.UUID   `json:"user_id"`
+	RepoName    string      `json:"repo_name"`
+	RepoURL     string      `json:"repo_url"`
+	Description *string     `json:"description,omitempty"`
+	Language    *string     `json:"language,omitempty"`
+	Stars       int         `json:"stars"`
+	Skills      StringArray `json:"skills"`
+	Text        string      `json:"text"`
+	Status      string      `json:"status"`
+	StoryID     *uuid.UUID  `json:"story_id,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// ProjectDraftInput is one drafted repository to save
+type ProjectDraftInput struct {
+	RepoName    string
+	RepoURL     string
+	Description string
+	Language    string
+	Stars       int
+	Skills      []string
+	Text        string
+}
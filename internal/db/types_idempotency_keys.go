@@ -0,0 +1,20 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey is a request key a user sent with a request, and the response stored
+// for it once the request finished
+type IdempotencyKey struct {
+	UserID      uuid.UUID       `json:"user_id"`
+	Key         string          `json:"key"`
+	RequestHash string          `json:"-"`
+	StatusCode  *int            `json:"status_code,omitempty"` // nil while the request is in progress
+	Response    json.RawMessage `json:"response,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
@@ -28,6 +28,19 @@ const (
 	RequirementTypeNiceToHave = "nice_to_have"
 )
 
+// KeywordSource constants: where a job keyword came from
+const (
+	KeywordSourceLLM    = "llm"    // Parsed from the posting text
+	KeywordSourceJSONLD = "jsonld" // The posting's schema.org JobPosting data
+	KeywordSourceUser   = "user"   // Added by a user for their own runs
+)
+
+// DuplicateMatch constants: how a posting was found to duplicate its canonical posting
+const (
+	DuplicateMatchContentHash = "content_hash"
+	DuplicateMatchFuzzy       = "fuzzy"
+)
+
 // EducationDegree constants
 const (
 	DegreeNone      = "none"
@@ -52,6 +65,11 @@ type JobPosting struct {
 	AdminInfo      *AdminInfo `json:"admin_info,omitempty"`
 	ExtractedLinks []string   `json:"extracted_links,omitempty"`
 
+	// De-duplication (see LinkDuplicatePosting)
+	CanonicalPostingID  *uuid.UUID `json:"canonical_posting_id,omitempty"`
+	DuplicateMatch      *string    `json:"duplicate_match,omitempty"`
+	DuplicateSimilarity *float64   `json:"duplicate_similarity,omitempty"`
+
 	// Caching
 	HTTPStatus   *int       `json:"http_status,omitempty"`
 	FetchStatus  string     `json:"fetch_status"`
@@ -75,6 +93,15 @@ type AdminInfo struct {
 	EmploymentType *string `json:"employment_type,omitempty"` // 'full_time', 'contract', etc.
 }
 
+// PostingDuplicate records that a posting duplicates a canonical posting
+type PostingDuplicate struct {
+	PostingID    uuid.UUID `json:"posting_id"`
+	CanonicalID  uuid.UUID `json:"canonical_posting_id"`
+	CanonicalURL string    `json:"canonical_url"`
+	Match        string    `json:"match"`      // DuplicateMatchContentHash or DuplicateMatchFuzzy
+	Similarity   float64   `json:"similarity"` // 1 for content hash matches
+}
+
 // JobProfile represents a parsed/structured job profile
 type JobProfile struct {
 	ID        uuid.UUID   `json:"id"`
@@ -85,6 +112,11 @@ type JobProfile struct {
 	CompanyName string `json:"company_name"`
 	RoleTitle   string `json:"role_title"`
 
+	// Normalized from RoleTitle when the profile is saved
+	RoleTitleNormalized string `json:"role_title_normalized,omitempty"`
+	RoleFamily          string `json:"role_family,omitempty"`
+	RoleLevel           string `json:"role_level,omitempty"`
+
 	// Evaluation signals
 	EvalLatency       bool                   `json:"eval_latency"`
 	EvalReliability   bool                   `json:"eval_reliability"`
@@ -132,9 +164,22 @@ type JobRequirement struct {
 	Level           *string   `json:"level,omitempty"`
 	Evidence        *string   `json:"evidence,omitempty"`
 	Ordinal         int       `json:"ordinal"`
+	Weight          *float64  `json:"weight,omitempty"` // The caller's weight, when loaded for a user
 	CreatedAt       time.Time `json:"created_at"`
 }
 
+// MaxRequirementWeight is the largest importance weight a user can give a requirement
+const MaxRequirementWeight = 3.0
+
+// RequirementWeight is a user's importance weight for a job requirement. It replaces the
+// requirement's default weight when ranking stories (1 for hard requirements, 0.5 for
+// nice-to-haves); 0 ignores the requirement.
+type RequirementWeight struct {
+	RequirementType string  `json:"requirement_type"`
+	Skill           string  `json:"skill"` // Normalized with NormalizeKeyword
+	Weight          float64 `json:"weight"`
+}
+
 // JobKeyword represents an extracted keyword
 type JobKeyword struct {
 	ID                uuid.UUID `json:"id"`
@@ -145,6 +190,37 @@ type JobKeyword struct {
 	CreatedAt         time.Time `json:"created_at"`
 }
 
+// KeywordOverride is a user's change to a job profile's keywords: a keyword they added,
+// or a shared keyword they hid from their runs when Excluded is set
+type KeywordOverride struct {
+	Keyword  string `json:"keyword"`
+	Excluded bool   `json:"excluded"`
+}
+
+// PostingVersion is one version of a job posting's content with the requirements and
+// keywords parsed from it: an archived version a re-fetch replaced, or the current one
+type PostingVersion struct {
+	ID           *uuid.UUID           `json:"id,omitempty"` // Archived versions only
+	PostingID    uuid.UUID            `json:"posting_id"`
+	CompanyID    *uuid.UUID           `json:"company_id,omitempty"`
+	URL          string               `json:"url"`
+	RoleTitle    *string              `json:"role_title,omitempty"`
+	RoleFamily   *string              `json:"role_family,omitempty"`
+	RoleLevel    *string              `json:"role_level,omitempty"`
+	ContentHash  *string              `json:"content_hash,omitempty"`
+	CleanedText  *string              `json:"cleaned_text,omitempty"`
+	Requirements []VersionRequirement `json:"requirements"`
+	Keywords     []string             `json:"keywords"`
+	FirstSeenAt  time.Time            `json:"first_seen_at"`
+	SupersededAt *time.Time           `json:"superseded_at,omitempty"` // nil for the current version
+}
+
+// VersionRequirement is a requirement as parsed from one version of a posting
+type VersionRequirement struct {
+	Type  string `json:"type"` // 'hard' or 'nice_to_have'
+	Skill string `json:"skill"`
+}
+
 // JobPostingCreateInput is used when creating a new job posting
 type JobPostingCreateInput struct {
 	URL          string
@@ -196,6 +272,14 @@ func (p *JobPosting) IsFresh() bool {
 	return time.Now().Before(*p.ExpiresAt)
 }
 
+// CanonicalID returns the ID of the posting this one duplicates, or its own ID
+func (p *JobPosting) CanonicalID() uuid.UUID {
+	if p.CanonicalPostingID != nil {
+		return *p.CanonicalPostingID
+	}
+	return p.ID
+}
+
 // IsExpired returns true if the posting has expired
 func (p *JobPosting) IsExpired() bool {
 	return !p.IsFresh()
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification event types
+const (
+	NotificationRunCompleted = "run_completed"        // A pipeline run owned by the user finished
+	NotificationWeeklyDigest = "weekly_digest"        // Summary of new postings, profile refreshes, and runs
+	NotificationReminder     = "application_reminder" // An application deadline or follow-up date is near
+	NotificationResumeHealth = "resume_health"        // Periodic review of the experience bank against target roles
+)
+
+// NotificationEventTypes lists every event type a user can set a preference for
+var NotificationEventTypes = []string{NotificationRunCompleted, NotificationWeeklyDigest, NotificationReminder, NotificationResumeHealth}
+
+// Application reminder kinds, also the delivery keys that make each reminder fire once
+const (
+	ReminderDeadline   = "application_deadline"
+	ReminderFollowUp   = "application_follow_up"
+	ReminderNextAction = "application_next_action" // The next action on an application tracker
+)
+
+// Notification channels
+const (
+	NotificationEmail   = "email"
+	NotificationWebhook = "webhook"
+	NotificationNone    = "none"
+)
+
+// NotificationPreference is how a user is notified about one event type
+type NotificationPreference struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	EventType  string     `json:"event_type"`
+	Channel    string     `json:"channel"`
+	WebhookURL *string    `json:"webhook_url,omitempty"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// NotificationPreferenceInput is the input for setting a notification preference
+type NotificationPreferenceInput struct {
+	UserID     uuid.UUID
+	EventType  string
+	Channel    string
+	WebhookURL string
+}
+
+// NotificationRecipient is a user and the channel a notification should be delivered on
+type NotificationRecipient struct {
+	UserID     uuid.UUID
+	Name       string
+	Email      string
+	Timezone   string // IANA name; times in messages are written in it
+	Channel    string
+	WebhookURL *string
+	LastSentAt *time.Time
+}
+
+// Location returns the recipient's time zone
+func (r *NotificationRecipient) Location() *time.Location {
+	return LoadTimezone(r.Timezone)
+}
+
+// DigestStats summarizes a user's activity since the previous digest
+type DigestStats struct {
+	Since             time.Time   `json:"since"`
+	RunsCompleted     int         `json:"runs_completed"`
+	ProfilesRefreshed int         `json:"profiles_refreshed"` // Voice profiles regenerated for companies the user targeted
+	PostingsMatched   int         `json:"postings_matched"`   // New job postings at companies the user targeted
+	RecentRuns        []DigestRun `json:"recent_runs"`
+}
+
+// DigestRun is a completed run listed in a digest
+type DigestRun struct {
+	RunID       uuid.UUID `json:"run_id"`
+	Company     string    `json:"company"`
+	RoleTitle   string    `json:"role_title"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ApplicationReminder is an upcoming deadline or follow-up date on one of a user's runs,
+// or the next action on one of their application trackers
+type ApplicationReminder struct {
+	Recipient NotificationRecipient
+	RefID     uuid.UUID // The run, or the tracker for ReminderNextAction
+	Company   string
+	RoleTitle string
+	JobURL    string
+	Action    string // The tracker's next action
+	Kind      string // ReminderDeadline, ReminderFollowUp, or ReminderNextAction
+	DueAt     time.Time
+}
@@ -0,0 +1,16 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Onboarding is a user's progress through the first-run wizard
+type Onboarding struct {
+	UserID      uuid.UUID   `json:"user_id"`
+	State       string      `json:"state"`
+	Skipped     StringArray `json:"skipped"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+}
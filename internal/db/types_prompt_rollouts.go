@@ -0,0 +1,49 @@
+package db
+
+import "time"
+
+// Prompt rollout outcomes: how a response to a trialed prompt turned out
+const (
+	PromptOutcomeOK           = "ok"
+	PromptOutcomeParseFailure = "parse_failure"
+	PromptOutcomeViolation    = "violation"
+)
+
+// Prompt rollout states
+const (
+	PromptRolloutTrialing   = "trialing"
+	PromptRolloutPromoted   = "promoted"
+	PromptRolloutRolledBack = "rolled_back"
+)
+
+// PromptRollout is a trial of a new prompt version against the version it replaces,
+// with the outcomes every server has reported for each
+type PromptRollout struct {
+	File                  string     `json:"file"`
+	PromptKey             string     `json:"prompt_key"`
+	Version               int        `json:"version"`
+	PreviousVersion       int        `json:"previous_version"`
+	State                 string     `json:"state"`
+	Share                 float64    `json:"share"`
+	WindowSize            int        `json:"window_size"`
+	Tolerance             float64    `json:"tolerance"`
+	PreviousSamples       int        `json:"previous_samples"`
+	PreviousParseFailures int        `json:"previous_parse_failures"`
+	PreviousViolations    int        `json:"previous_violations"`
+	NextSamples           int        `json:"next_samples"`
+	NextParseFailures     int        `json:"next_parse_failures"`
+	NextViolations        int        `json:"next_violations"`
+	StartedAt             time.Time  `json:"started_at"`
+	DecidedAt             *time.Time `json:"decided_at,omitempty"`
+}
+
+// PromptRolloutInput starts a prompt rollout
+type PromptRolloutInput struct {
+	File            string
+	PromptKey       string
+	Version         int
+	PreviousVersion int
+	Share           float64
+	WindowSize      int
+	Tolerance       float64
+}
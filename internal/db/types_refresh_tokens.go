@@ -0,0 +1,28 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is a stored refresh token. Only the SHA-256 of the token is kept.
+type RefreshToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	FamilyID   uuid.UUID  `json:"family_id"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy *uuid.UUID `json:"replaced_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// RefreshTokenRotation is the outcome of RotateRefreshToken
+type RefreshTokenRotation struct {
+	// Token is the newly issued token, or nil if the presented token was rejected
+	Token *RefreshToken
+	// Reused reports that an already rotated or revoked token was presented, so
+	// its family was revoked
+	Reused bool
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResumeHealth reviews a user's experience bank: how well it covers the roles they
+// target, which bullets have gone stale or lack metrics, and what to improve first
+type ResumeHealth struct {
+	UserID         uuid.UUID      `json:"user_id"`
+	GeneratedAt    time.Time      `json:"generated_at"`
+	Bullets        int            `json:"bullets"`
+	Roles          []RoleCoverage `json:"roles"`           // Target role families, most targeted first
+	StaleBullets   []HealthBullet `json:"stale_bullets"`   // Not updated within StaleBulletAge
+	MissingMetrics []HealthBullet `json:"missing_metrics"` // No metric and no number in the text
+	Suggestions    []string       `json:"suggestions"`
+}
+
+// RoleCoverage is how well the bank covers the requirements of the postings a user
+// targeted in one role family
+type RoleCoverage struct {
+	RoleFamily   string         `json:"role_family"`
+	Postings     int            `json:"postings"`     // Job profiles of the user's recent runs in the family
+	Requirements int            `json:"requirements"` // Distinct skills they require
+	Matched      int            `json:"matched"`      // Skills a bullet is tagged with
+	Partial      int            `json:"partial"`      // Skills with only related tags or text mentions
+	Coverage     float64        `json:"coverage"`     // Share of requirements matched, 0-1
+	Missing      []MissingSkill `json:"missing"`      // Skills without evidence, most required first
+}
+
+// MissingSkill is a required skill the bank has no evidence for
+type MissingSkill struct {
+	Skill    string `json:"skill"`
+	Postings int    `json:"postings"` // Postings in the role family that require it
+}
+
+// HealthBullet is a bullet a health report flags
+type HealthBullet struct {
+	BulletID  string    `json:"bullet_id"`
+	StoryID   string    `json:"story_id"`
+	Text      string    `json:"text"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TargetRequirement is a requirement of a job profile from one of a user's runs
+type TargetRequirement struct {
+	RoleFamily string
+	JobRequirement
+}
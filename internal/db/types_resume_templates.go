@@ -0,0 +1,19 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResumeTemplate is a LaTeX resume template a user uploaded
+type ResumeTemplate struct {
+	ID        uuid.UUID       `json:"id"`
+	UserID    uuid.UUID       `json:"user_id"`
+	Name      string          `json:"name"`
+	Content   string          `json:"content"`
+	Manifest  json.RawMessage `json:"manifest"` // rendering.TemplateManifest
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
@@ -14,14 +14,56 @@ type Run struct {
 	JobURL      string     `json:"job_url"`
 	Status      string     `json:"status"`
 	UserID      *uuid.UUID `json:"user_id,omitempty"` // Nullable for backward compatibility
+	Priority    string     `json:"priority"`
+	RunType     string     `json:"run_type"`                // What the run generates (RunType*)
+	SourceRunID *uuid.UUID `json:"source_run_id,omitempty"` // Run whose artifacts a cover-letter or refresh run reuses
+	MaxPages    *int       `json:"max_pages,omitempty"`     // Pages the resume may fill, when set at creation
 	CreatedAt   time.Time  `json:"created_at"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	DeadlineAt  *time.Time `json:"deadline_at,omitempty"`  // Application deadline for the job
+	FollowUpAt  *time.Time `json:"follow_up_at,omitempty"` // When to follow up on the application
+}
+
+// Run priority constants (scheduling classes, highest first)
+const (
+	RunPriorityInteractive = "interactive"
+	RunPriorityNormal      = "normal"
+	RunPriorityBulk        = "bulk"
+)
+
+// Run type constants: a full run generates a resume from the posting; the others
+// reuse a source run's artifacts and execute a subset of the steps
+const (
+	RunTypeFull        = "full"
+	RunTypeCoverLetter = "cover_letter" // Writes a cover letter from the source run's bullets
+	RunTypeRefresh     = "refresh"      // Re-renders the source run's plan, e.g. against an updated template
+)
+
+// RunStatusAbandoned marks a run that was created but never executed (see MarkAbandonedRuns)
+const RunStatusAbandoned = "abandoned"
+
+// RunReset reports what ResetRun changed
+type RunReset struct {
+	RunID          uuid.UUID `json:"run_id"`
+	PreviousStatus string    `json:"previous_status"`
+	Reset          bool      `json:"reset"`     // False when the run had already finished
+	Steps          int64     `json:"steps"`     // Unfinished steps marked failed
+	StepJobs       int64     `json:"step_jobs"` // Queued or running step jobs marked failed
+}
+
+// ReclaimedRuns counts the rows removed by one DeleteAbandonedRuns pass
+type ReclaimedRuns struct {
+	Runs      int64 `json:"runs"`
+	Steps     int64 `json:"steps"`     // run_steps rows removed with the runs
+	Artifacts int64 `json:"artifacts"` // artifacts rows removed with the runs
+	Blobs     int64 `json:"blobs"`     // artifact_blobs no artifact refers to any more
 }
 
 // ArtifactStep constants for known artifact types
 const (
 	// Pipeline lifecycle
-	StepRunStarted = "run_started"
+	StepRunStarted     = "run_started"
+	StepRunEnvironment = "run_environment" // Build, template, and feature flags the run executed with
 
 	// Ingestion phase
 	StepJobPosting   = "job_posting"
@@ -30,22 +72,41 @@ const (
 	StepEducationReq = "education_requirements"
 
 	// Experience branch
-	StepExperienceBank  = "experience_bank"
-	StepRankedStories   = "ranked_stories"
-	StepEducationScores = "education_scores"
-	StepResumePlan      = "resume_plan"
-	StepSelectedBullets = "selected_bullets"
+	StepExperienceBank    = "experience_bank"
+	StepRankedStories     = "ranked_stories"
+	StepEducationScores   = "education_scores"
+	StepResumePlan        = "resume_plan"
+	StepSelectedBullets   = "selected_bullets"
+	StepBulletSuggestions = "bullet_suggestions" // Drafts for requirements the bank does not cover
 
 	// Research branch
 	StepResearchSession = "research_session"
 	StepCompanyCorpus   = "company_corpus"
 	StepSources         = "sources"
 	StepCompanyProfile  = "company_profile"
+	StepTechStack       = "tech_stack"
+	StepResearchReport  = "research_report" // Markdown account of the URLs research considered and the excerpts it kept
 
 	// Final steps
-	StepRewrittenBullets = "rewritten_bullets"
-	StepResumeTex        = "resume_tex"
-	StepViolations       = "violations"
+	StepRewrittenBullets  = "rewritten_bullets"
+	StepResumeTex         = "resume_tex"
+	StepViolations        = "violations"
+	StepReadabilityReport = "readability_report"
+	StepATSReport         = "ats_report"       // Coverage of the job's keywords and hard requirements
+	StepResumeThumbnail   = "resume_thumbnail" // Base64 PNG of page one
+	StepResumePDF         = "resume_pdf"       // Compiled PDF, stored as binary content
+	StepResumeDOCX        = "resume_docx"      // Word document, stored as binary content
+	StepChangeReport      = "change_report"    // Changes since the user's last resume for the role family
+	StepCoverLetter       = "cover_letter"     // Cover letter text, written by cover-letter runs
+
+	// Debug mode
+	StepDebugLLMExchanges = "debug_llm_exchanges"
+
+	// PII redaction
+	StepRedactionReport = "redaction_report"
+
+	// Prompts cut to fit their step's LLM context budget
+	StepPromptTruncations = "prompt_truncations"
 )
 
 // Category constants for grouping artifacts by pipeline phase
@@ -56,4 +117,7 @@ const (
 	CategoryResearch   = "research"
 	CategoryRewriting  = "rewriting"
 	CategoryValidation = "validation"
+	CategoryDebug      = "debug"
+	CategoryPlugin     = "plugin"
+	CategoryPrivacy    = "privacy"
 )
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RunEventsChannel is notified with a JSON-encoded RunEvent whenever a run,
+// one of its steps, or one of its artifacts changes
+const RunEventsChannel = "run_events"
+
+// RunEvent type constants
+const (
+	RunEventRunCreated    = "run_created" // The run was assigned its owner
+	RunEventStepStatus    = "step_status"
+	RunEventArtifactSaved = "artifact_saved"
+	RunEventRunCompleted  = "run_completed"
+)
+
+// RunEvent describes a change to a pipeline run. Events are small by design
+// (Postgres limits NOTIFY payloads to 8000 bytes); consumers fetch details
+// such as artifact content on demand.
+type RunEvent struct {
+	Type       string     `json:"type"`
+	RunID      uuid.UUID  `json:"run_id"`
+	Step       string     `json:"step,omitempty"`
+	Category   string     `json:"category,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	ArtifactID *uuid.UUID `json:"artifact_id,omitempty"`
+	Error      *string    `json:"error,omitempty"`
+	At         time.Time  `json:"at"`
+}
+
+// Terminal reports whether the event ends the run
+func (e RunEvent) Terminal() bool {
+	return e.Type == RunEventRunCompleted
+}
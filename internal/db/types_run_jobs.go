@@ -0,0 +1,40 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RunJob status constants
+const (
+	RunJobStatusQueued    = "queued"
+	RunJobStatusRunning   = "running"
+	RunJobStatusCompleted = "completed"
+	RunJobStatusFailed    = "failed"
+)
+
+// RunJobsChannel is notified with the job ID when a run job is enqueued
+const RunJobsChannel = "run_jobs"
+
+// RunJob is a pipeline run waiting for, or being executed by, a run worker
+type RunJob struct {
+	ID           uuid.UUID       `json:"id"`
+	RunID        uuid.UUID       `json:"run_id"`
+	UserID       *uuid.UUID      `json:"user_id,omitempty"`
+	Payload      json.RawMessage `json:"payload"`
+	Priority     int             `json:"priority"` // Scheduling class (scheduler.Priority); higher is claimed first
+	Status       string          `json:"status"`
+	ErrorMessage *string         `json:"error_message,omitempty"`
+	WorkerID     *string         `json:"worker_id,omitempty"`
+	Attempts     int             `json:"attempts"`
+	CreatedAt    time.Time       `json:"created_at"`
+	StartedAt    *time.Time      `json:"started_at,omitempty"`
+	CompletedAt  *time.Time      `json:"completed_at,omitempty"`
+}
+
+// Done reports whether the job reached a terminal status
+func (j *RunJob) Done() bool {
+	return j.Status == RunJobStatusCompleted || j.Status == RunJobStatusFailed
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RunRecipe is a named bundle of run defaults a user saved for an application scenario
+type RunRecipe struct {
+	ID             uuid.UUID       `json:"id"`
+	UserID         uuid.UUID       `json:"user_id"`
+	Name           string          `json:"name"`
+	Description    *string         `json:"description,omitempty"`
+	Template       *string         `json:"template,omitempty"`
+	MaxBullets     *int            `json:"max_bullets,omitempty"`
+	MaxLines       *int            `json:"max_lines,omitempty"`
+	SectionOrder   []string        `json:"section_order"`
+	RankingWeights json.RawMessage `json:"ranking_weights,omitempty"` // ranking.Weights
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// RunRecipeInput is a recipe to create or replace. Zero fields are stored unset.
+type RunRecipeInput struct {
+	Name           string
+	Description    string
+	Template       string
+	MaxBullets     int
+	MaxLines       int
+	SectionOrder   []string
+	RankingWeights json.RawMessage
+}
@@ -23,6 +23,7 @@ const (
 	StepCategoryResearch   = "research"
 	StepCategoryRewriting  = "rewriting"
 	StepCategoryValidation = "validation"
+	StepCategoryPlugin     = "plugin"
 )
 
 // RunStep represents a single step execution for a pipeline run
@@ -42,6 +43,20 @@ type RunStep struct {
 	UpdatedAt    time.Time              `json:"updated_at"`
 }
 
+// LatencyRun is the LatencyStats step name for whole runs, from creation to completion
+const LatencyRun = "run"
+
+// LatencyStats summarizes how long the successful executions of one step (or whole
+// runs) took. Bucket is nil for the totals over the whole window.
+type LatencyStats struct {
+	Step       string     `json:"step"`
+	Bucket     *time.Time `json:"bucket,omitempty"`
+	Count      int        `json:"count"`
+	P50Ms      int64      `json:"p50_ms"`
+	P95Ms      int64      `json:"p95_ms"`
+	OverBudget int        `json:"over_budget"` // Executions slower than the step's budget
+}
+
 // RunStepInput represents input for creating/updating a run step
 type RunStepInput struct {
 	Step       string
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shared resume view kinds
+const (
+	SharedViewPage = "page" // The HTML page was opened
+	SharedViewPDF  = "pdf"  // The PDF was downloaded
+)
+
+// Shared resume link scopes, from narrowest to widest
+const (
+	SharedScopePDF       = "pdf"       // The final resume only: its page, PDF, and thumbnail
+	SharedScopeArtifacts = "artifacts" // Also the run's other artifacts
+	SharedScopeComments  = "comments"  // Also lets recipients leave comments
+)
+
+// Shared resume access log outcomes
+const (
+	SharedAccessAllowed    = "allowed"
+	SharedAccessOutOfScope = "out_of_scope" // The link's scope does not cover the request
+	SharedAccessExpired    = "expired"
+	SharedAccessRevoked    = "revoked"
+)
+
+// Shared resume access log actions taken by the owner; recipient requests use the view
+// kinds and SharedActionArtifacts, SharedActionArtifact, and SharedActionComment
+const (
+	SharedActionPublished     = "published"
+	SharedActionAccessChanged = "access_changed"
+	SharedActionRevoked       = "revoked"
+	SharedActionArtifacts     = "artifacts" // The artifact list was read
+	SharedActionArtifact      = "artifact"  // One artifact was read
+	SharedActionComment       = "comment"   // A comment was left
+	SharedActionThumbnail     = "thumbnail" // The share card thumbnail was fetched
+)
+
+// SharedResume is a user's opt-in public resume page, served at /r/{slug}
+type SharedResume struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	RunID     uuid.UUID  `json:"run_id"`
+	Slug      string     `json:"slug"`
+	Scope     string     `json:"scope"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// SharedResumeInput is a run to publish as the user's shared resume page
+type SharedResumeInput struct {
+	UserID    uuid.UUID
+	RunID     uuid.UUID
+	Slug      string // Used for a new page, or to replace the link of a revoked one
+	Scope     string
+	ExpiresAt *time.Time
+}
+
+// SharedScopeAllows reports whether a link with scope grants access needing required
+func SharedScopeAllows(scope, required string) bool {
+	rank := map[string]int{SharedScopePDF: 0, SharedScopeArtifacts: 1, SharedScopeComments: 2}
+	have, ok := rank[scope]
+	return ok && have >= rank[required]
+}
+
+// SharedResumeComment is a comment a recipient left on a shared resume page
+type SharedResumeComment struct {
+	ID             uuid.UUID `json:"id"`
+	SharedResumeID uuid.UUID `json:"shared_resume_id"`
+	Author         string    `json:"author"`
+	Body           string    `json:"body"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SharedResumeAccess is one entry in a shared resume's access log
+type SharedResumeAccess struct {
+	ID             uuid.UUID `json:"id"`
+	SharedResumeID uuid.UUID `json:"shared_resume_id"`
+	Action         string    `json:"action"`
+	Outcome        string    `json:"outcome"`
+	Detail         *string   `json:"detail,omitempty"`
+	ReferrerHost   *string   `json:"referrer_host,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SharedResumeAccessInput is an access log entry to record
+type SharedResumeAccessInput struct {
+	SharedResumeID uuid.UUID
+	Action         string
+	Outcome        string
+	Detail         string
+	ReferrerHost   string
+}
+
+// SharedResumeStats summarizes the views of a shared resume page
+type SharedResumeStats struct {
+	PageViews    int             `json:"page_views"`
+	PDFDownloads int             `json:"pdf_downloads"`
+	LastViewedAt *time.Time      `json:"last_viewed_at,omitempty"`
+	Referrers    []ReferrerCount `json:"referrers"`
+}
+
+// ReferrerCount is the number of views that arrived from one referring host
+type ReferrerCount struct {
+	Host  string `json:"host"`
+	Views int    `json:"views"`
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StepJob status constants
+const (
+	StepJobStatusQueued    = "queued"
+	StepJobStatusRunning   = "running"
+	StepJobStatusCompleted = "completed"
+	StepJobStatusFailed    = "failed"
+)
+
+// StepJob backends. Jobs for other backends are claimed by ID, not from the shared queue.
+const (
+	StepJobBackendWorker     = "worker"
+	StepJobBackendKubernetes = "kubernetes"
+)
+
+// Notification channels used by the step job queue
+const (
+	// StepJobsChannel is notified with the step name when a job is enqueued
+	StepJobsChannel = "step_jobs"
+	// StepJobDoneChannel is notified with the job ID when a job completes or fails
+	StepJobDoneChannel = "step_job_done"
+)
+
+// StepJob is a unit of step work executed by an out-of-process worker
+type StepJob struct {
+	ID           uuid.UUID       `json:"id"`
+	RunID        *uuid.UUID      `json:"run_id,omitempty"`
+	Step         string          `json:"step"`
+	Backend      string          `json:"backend"`
+	Payload      json.RawMessage `json:"payload"`
+	Status       string          `json:"status"`
+	Result       json.RawMessage `json:"result,omitempty"`
+	ErrorMessage *string         `json:"error_message,omitempty"`
+	WorkerID     *string         `json:"worker_id,omitempty"`
+	Attempts     int             `json:"attempts"`
+	CreatedAt    time.Time       `json:"created_at"`
+	StartedAt    *time.Time      `json:"started_at,omitempty"`
+	CompletedAt  *time.Time      `json:"completed_at,omitempty"`
+}
+
+// Done reports whether the job reached a terminal status
+func (j *StepJob) Done() bool {
+	return j.Status == StepJobStatusCompleted || j.Status == StepJobStatusFailed
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BulletSuggestion is a bullet drafted for a job requirement the user's experience bank
+// does not cover, pending review. Its status is one of the DraftStatus* values.
+type BulletSuggestion struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	RunID     *uuid.UUID `json:"run_id,omitempty"` // Run that last suggested it
+	Skill     string     `json:"skill"`
+	Source    string     `json:"source"` // hard_requirement or nice_to_have
+	Level     *string    `json:"level,omitempty"`
+	Evidence  *string    `json:"evidence,omitempty"`
+	Question  string     `json:"question"`
+	Text      string     `json:"text"`
+	Status    string     `json:"status"`
+	StoryID   *uuid.UUID `json:"story_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// BulletSuggestionInput is one drafted suggestion to save
+type BulletSuggestionInput struct {
+	Skill    string
+	Source   string
+	Level    string
+	Evidence string
+	Question string
+	Text     string
+}
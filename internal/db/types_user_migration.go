@@ -0,0 +1,38 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserExportFormat identifies user export documents
+const UserExportFormat = "resume-customizer/user-export"
+
+// UserExportVersion is the layout version of user export documents ImportUser reads
+const UserExportVersion = 1
+
+// UserExport is a user's account and everything they own, table by table, for moving
+// them to another deployment with ImportUser
+type UserExport struct {
+	Format     string          `json:"format"`
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	UserID     uuid.UUID       `json:"user_id"`
+	Tables     []ExportedTable `json:"tables"` // In import order
+}
+
+// ExportedTable holds one table's rows of a user export, each a JSON object of the
+// row's columns
+type ExportedTable struct {
+	Name string            `json:"name"`
+	Rows []json.RawMessage `json:"rows"`
+}
+
+// UserImport reports what ImportUser added
+type UserImport struct {
+	UserID   uuid.UUID      `json:"user_id"`            // The user's ID on this deployment
+	Rows     map[string]int `json:"rows"`               // Rows inserted, by table
+	Existing map[string]int `json:"existing,omitempty"` // Rows this deployment already had, by table
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"time"
+	_ "time/tzdata" // The runtime image has no zoneinfo; embed it for LoadTimezone
 
 	"github.com/google/uuid"
 )
@@ -17,8 +18,33 @@ type User struct {
 	Phone        string    `json:"phone,omitempty"`
 	PasswordHash string    `json:"-" db:"password_hash"` // Never serialize to JSON
 	PasswordSet  bool      `json:"password_set" db:"password_set"`
+	RedactPII    bool      `json:"redact_pii" db:"redact_pii"` // Mask contact details in prompts to external LLMs
+	Timezone     string    `json:"timezone"`                   // IANA name, e.g. "America/New_York"; emails and reports use it
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	// EmailVerifiedAt is nil while a registration's email verification is pending
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+}
+
+// DefaultTimezone is the time zone of users who have not chosen one
+const DefaultTimezone = "UTC"
+
+// Location returns the user's time zone
+func (u *User) Location() *time.Location {
+	return LoadTimezone(u.Timezone)
+}
+
+// LoadTimezone returns the time zone named by an IANA name, falling back to UTC when
+// the name is empty or unknown so a bad stored value never blocks an email or report
+func LoadTimezone(name string) *time.Location {
+	if name == "" || name == "Local" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
 }
 
 // Job represents an employment history entry
@@ -32,6 +58,11 @@ type Job struct {
 	StartDate      *Date     `json:"start_date,omitempty"`
 	EndDate        *Date     `json:"end_date,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
+
+	// Normalized from RoleTitle when the job is saved
+	RoleTitleNormalized string `json:"role_title_normalized,omitempty"` // e.g. "Senior Software Engineer"
+	RoleFamily          string `json:"role_family,omitempty"`           // e.g. "software engineer"
+	RoleLevel           string `json:"role_level,omitempty"`            // e.g. "senior"
 }
 
 // Experience represents a bullet point within a job
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Voice note sources
+const (
+	VoiceNoteSourceUpload  = "upload"  // Recording uploaded and transcribed by the server
+	VoiceNoteSourceWebhook = "webhook" // Transcript pushed by an external service
+)
+
+// VoiceNote is a transcribed voice note sorted into STAR interview answers. Answers and
+// Draft hold star.Answers and star.Draft JSON.
+type VoiceNote struct {
+	ID           uuid.UUID       `json:"id"`
+	UserID       uuid.UUID       `json:"user_id"`
+	Source       string          `json:"source"`
+	Transcript   string          `json:"transcript"`
+	Answers      json.RawMessage `json:"answers"`
+	Draft        json.RawMessage `json:"draft,omitempty"`         // Set once every stage is answered
+	NextStage    *string         `json:"next_stage,omitempty"`    // First unanswered stage when there is no draft
+	NextQuestion *string         `json:"next_question,omitempty"` // Question for NextStage
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// VoiceNoteInput is a processed voice note to save
+type VoiceNoteInput struct {
+	UserID       uuid.UUID
+	Source       string
+	Transcript   string
+	Answers      json.RawMessage
+	Draft        json.RawMessage
+	NextStage    string
+	NextQuestion string
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook event names
+const (
+	WebhookRunCreated    = "run.created"
+	WebhookStepCompleted = "step.completed"
+	WebhookRunFailed     = "run.failed"
+	WebhookRunCompleted  = "run.completed"
+)
+
+// WebhookEvents lists every webhook event
+var WebhookEvents = []string{WebhookRunCreated, WebhookStepCompleted, WebhookRunFailed, WebhookRunCompleted}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // Attempts ran out
+)
+
+// Webhook is an endpoint a user registered for run lifecycle events
+type Webhook struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookInput is the input for registering a webhook
+type WebhookInput struct {
+	UserID uuid.UUID
+	URL    string
+	Secret string
+	Events []string
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a webhook
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	RunID          *uuid.UUID      `json:"run_id,omitempty"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"` // Set while pending
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+
+	// URL and Secret are the webhook's, loaded for delivery
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookAttempt is the outcome of one delivery attempt
+type WebhookAttempt struct {
+	ResponseStatus int        // 0 when the endpoint did not answer
+	Error          string     // Empty when delivered
+	RetryAt        *time.Time // When to try again after a failure; nil gives up
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Workspace member roles
+const (
+	WorkspaceRoleAdmin  = "admin"  // Manages members and publishes rule packs
+	WorkspaceRoleMember = "member" // Runs follow the workspace's rule packs
+)
+
+// Workspace is a group of users sharing mandatory style rule packs
+type Workspace struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Role      string     `json:"role,omitempty"` // The listing user's role, when listed for a user
+}
+
+// WorkspaceMember is a user's membership in a workspace
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RulePack is a workspace's published set of style rules
+type RulePack struct {
+	ID          uuid.UUID  `json:"id"`
+	WorkspaceID uuid.UUID  `json:"workspace_id"`
+	Name        string     `json:"name"`
+	Mode        string     `json:"mode"` // rulepacks.ModeAppend or rulepacks.ModeReplace
+	Priority    int        `json:"priority"`
+	Rules       []string   `json:"rules"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// RulePackInput is a rule pack to create or replace
+type RulePackInput struct {
+	Name     string
+	Mode     string
+	Priority int
+	Rules    []string
+}
+
+// RulePackApplication records a rule pack applied to a run
+type RulePackApplication struct {
+	ID          uuid.UUID  `json:"id"`
+	RunID       uuid.UUID  `json:"run_id"`
+	WorkspaceID uuid.UUID  `json:"workspace_id"`
+	PackID      *uuid.UUID `json:"pack_id,omitempty"` // Unset once the pack is deleted
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	PackName    string     `json:"pack_name"`
+	Mode        string     `json:"mode"`
+	Rules       []string   `json:"rules"`
+	AppliedAt   time.Time  `json:"applied_at"`
+}
+
+// RulePackApplicationInput is a rule pack application to record
+type RulePackApplicationInput struct {
+	WorkspaceID uuid.UUID
+	PackID      uuid.UUID
+	PackName    string
+	Mode        string
+	Rules       []string
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const userCompanyProfileColumns = `id, user_id, company_id, tone, domain_context, company_values, style_rules,
+	taboo_phrases, taboo_reasons, version, created_at, updated_at`
+
+func scanUserCompanyProfile(row pgx.Row) (*CompanyProfile, error) {
+	var p CompanyProfile
+	var userID uuid.UUID
+	var reasons []byte
+	err := row.Scan(&p.ID, &userID, &p.CompanyID, &p.Tone, &p.DomainContext, &p.Values, &p.StyleRules,
+		&p.TabooPhrases, &reasons, &p.Version, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(reasons, &p.TabooReasons); err != nil {
+		return nil, fmt.Errorf("failed to decode taboo reasons: %w", err)
+	}
+	if len(p.TabooReasons) == 0 {
+		p.TabooReasons = nil
+	}
+	p.UserID = &userID
+	p.SourceType = ProfileSourceManual
+	return &p, nil
+}
+
+// UpsertUserCompanyProfile stores a company profile the user entered by hand, replacing
+// their earlier one for the company. The shared, crawled profile is left alone.
+func (db *DB) UpsertUserCompanyProfile(ctx context.Context, userID uuid.UUID, input *ProfileCreateInput) (*CompanyProfile, error) {
+	phrases := []string{}
+	reasons := map[string]string{}
+	for _, taboo := range input.TabooPhrases {
+		phrases = append(phrases, taboo.Phrase)
+		if taboo.Reason != "" {
+			reasons[taboo.Phrase] = taboo.Reason
+		}
+	}
+	reasonsJSON, err := json.Marshal(reasons)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode taboo reasons: %w", err)
+	}
+	values := input.Values
+	if values == nil {
+		values = []string{}
+	}
+	rules := input.StyleRules
+	if rules == nil {
+		rules = []string{}
+	}
+
+	p, err := scanUserCompanyProfile(db.pool.QueryRow(ctx,
+		`INSERT INTO user_company_profiles (user_id, company_id, tone, domain_context, company_values,
+		                                    style_rules, taboo_phrases, taboo_reasons)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 ON CONFLICT (user_id, company_id) DO UPDATE SET
+		     tone = EXCLUDED.tone,
+		     domain_context = EXCLUDED.domain_context,
+		     company_values = EXCLUDED.company_values,
+		     style_rules = EXCLUDED.style_rules,
+		     taboo_phrases = EXCLUDED.taboo_phrases,
+		     taboo_reasons = EXCLUDED.taboo_reasons,
+		     version = user_company_profiles.version + 1,
+		     updated_at = NOW()
+		 RETURNING `+userCompanyProfileColumns,
+		userID, input.CompanyID, input.Tone, nullIfEmpty(input.DomainContext), values, rules, phrases, reasonsJSON,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save user company profile: %w", err)
+	}
+	return p, nil
+}
+
+// GetUserCompanyProfile returns the company profile the user entered by hand for the
+// company, or nil if they have not entered one
+func (db *DB) GetUserCompanyProfile(ctx context.Context, userID, companyID uuid.UUID) (*CompanyProfile, error) {
+	p, err := scanUserCompanyProfile(db.pool.QueryRow(ctx,
+		`SELECT `+userCompanyProfileColumns+` FROM user_company_profiles
+		 WHERE user_id = $1 AND company_id = $2`,
+		userID, companyID,
+	))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user company profile: %w", err)
+	}
+	return p, nil
+}
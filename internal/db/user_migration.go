@@ -0,0 +1,462 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// userRuns selects the IDs of the user's runs; $1 is the user ID
+const userRuns = `run_id IN (SELECT id FROM pipeline_runs WHERE user_id = $1)`
+
+// userBullets selects the IDs of the bullets in the user's stories; $1 is the user ID
+const userBullets = `SELECT b.id FROM bullets b JOIN stories s ON s.id = b.story_id WHERE s.user_id = $1`
+
+// userTrackers selects rows of the user's application trackers; $1 is the user ID
+const userTrackers = `tracker_id IN (SELECT id FROM application_trackers WHERE user_id = $1)`
+
+// migrationTable describes how a table's rows belong to a user and refer to other rows
+type migrationTable struct {
+	name    string
+	where   string            // Selects the user's rows; $1 is the user ID
+	refs    map[string]string // Columns holding IDs of other exported rows, by the table they refer to
+	clear   []string          // Columns referring to data shared by every user, which is not exported
+	derived []string          // Columns this deployment maintains itself, left out of exports
+	// naturalKey is set for catalog tables: rows are matched to this deployment's rows by
+	// the column and only inserted when missing
+	naturalKey string
+	// keepExisting skips rows whose key the deployment already has, such as settings
+	// of a user being imported into an existing account
+	keepExisting bool
+}
+
+// migrationTables are the tables of a user export, parents before the rows referring to
+// them. Left out: sign-in tokens and verification links, shared resume links and their
+// logs, GitHub tokens (sealed with this deployment's key), workspace memberships, queued
+// work, notification delivery logs, the user's changes to shared job profiles, and their
+// manual company profiles, which refer to companies this deployment may not have.
+var migrationTables = []migrationTable{
+	{name: "users", where: "id = $1"},
+	{name: "skills", naturalKey: "name_normalized",
+		where: "id IN (SELECT skill_id FROM bullet_skills WHERE bullet_id IN (" + userBullets + ")" +
+			" UNION SELECT skill_id FROM user_skills WHERE user_id = $1)"},
+	{name: "jobs", where: "user_id = $1", refs: map[string]string{"user_id": "users"}},
+	{name: "experiences", where: "job_id IN (SELECT id FROM jobs WHERE user_id = $1)",
+		refs: map[string]string{"job_id": "jobs"}},
+	{name: "education", where: "user_id = $1", refs: map[string]string{"user_id": "users"}},
+	{name: "education_highlights", where: "education_id IN (SELECT id FROM education WHERE user_id = $1)",
+		refs: map[string]string{"education_id": "education"}},
+	{name: "stories", where: "user_id = $1", refs: map[string]string{"user_id": "users", "job_id": "jobs"}},
+	{name: "bullets", where: "story_id IN (SELECT id FROM stories WHERE user_id = $1)",
+		refs: map[string]string{"story_id": "stories", "job_id": "jobs"}},
+	{name: "bullet_skills", where: "bullet_id IN (" + userBullets + ")",
+		refs: map[string]string{"bullet_id": "bullets", "skill_id": "skills"}},
+	{name: "user_skills", where: "user_id = $1", keepExisting: true,
+		refs: map[string]string{"user_id": "users", "skill_id": "skills"}},
+	{name: "pipeline_runs", where: "user_id = $1", refs: map[string]string{"user_id": "users"},
+		clear: []string{"job_posting_id", "job_profile_id", "company_profile_id"}},
+	// Blobs are addressed by content, so ones this deployment already stores are reused.
+	// Imports check each blob's address against its content (see addressBlobs).
+	{name: "artifact_blobs", where: "hash IN (SELECT blob_hash FROM artifacts WHERE " + userRuns + ")",
+		keepExisting: true, derived: []string{"ref_count"}},
+	{name: "artifacts", where: userRuns, refs: map[string]string{"run_id": "pipeline_runs", "blob_hash": "artifact_blobs"}},
+	{name: "run_steps", where: userRuns, refs: map[string]string{"run_id": "pipeline_runs", "artifact_id": "artifacts"}},
+	{name: "run_checkpoints", where: userRuns, refs: map[string]string{"run_id": "pipeline_runs"}},
+	{name: "run_ranked_stories", where: userRuns, refs: map[string]string{"run_id": "pipeline_runs", "story_id": "stories"}},
+	{name: "run_resume_plans", where: userRuns, refs: map[string]string{"run_id": "pipeline_runs"}},
+	{name: "run_selected_bullets", where: userRuns, refs: map[string]string{
+		"run_id": "pipeline_runs", "plan_id": "run_resume_plans", "bullet_id": "bullets", "story_id": "stories"}},
+	{name: "run_rewritten_bullets", where: userRuns, refs: map[string]string{
+		"run_id": "pipeline_runs", "selected_bullet_id": "run_selected_bullets"}},
+	{name: "run_violations", where: userRuns, refs: map[string]string{"run_id": "pipeline_runs"}},
+	{name: "company_preferences", where: "user_id = $1", refs: map[string]string{"user_id": "users"}},
+	{name: "run_recipes", where: "user_id = $1", refs: map[string]string{"user_id": "users"}},
+	{name: "application_trackers", where: "user_id = $1", refs: map[string]string{"user_id": "users"}},
+	{name: "application_contacts", where: userTrackers, refs: map[string]string{"tracker_id": "application_trackers"}},
+	{name: "application_interactions", where: userTrackers, refs: map[string]string{
+		"tracker_id": "application_trackers", "contact_id": "application_contacts"}},
+	{name: "resume_templates", where: "user_id = $1", refs: map[string]string{"user_id": "users"}},
+	{name: "voice_notes", where: "user_id = $1", refs: map[string]string{"user_id": "users"}},
+	{name: "bullet_suggestions", where: "user_id = $1", refs: map[string]string{
+		"user_id": "users", "run_id": "pipeline_runs", "story_id": "stories"}},
+	{name: "project_drafts", where: "user_id = $1", keepExisting: true,
+		refs: map[string]string{"user_id": "users", "story_id": "stories"}},
+	{name: "domain_policies", where: "user_id = $1", keepExisting: true, refs: map[string]string{"user_id": "users"}},
+	{name: "notification_preferences", where: "user_id = $1", keepExisting: true, refs: map[string]string{"user_id": "users"}},
+	{name: "user_onboarding", where: "user_id = $1", keepExisting: true, refs: map[string]string{"user_id": "users"}},
+}
+
+// userCredentialColumns are left out of exports unless credentials are included
+var userCredentialColumns = []string{"password_hash", "password_set"}
+
+// -----------------------------------------------------------------------------
+// User Migration Methods
+// -----------------------------------------------------------------------------
+
+// ExportUser returns a user's account, experience bank, runs with their artifacts and
+// application dates, and settings, for ImportUser on another deployment. The password
+// hash is left out unless includeCredentials is set; it only verifies on a deployment
+// with the same PASSWORD_PEPPER. Returns nil if the user does not exist.
+func (db *DB) ExportUser(ctx context.Context, userID uuid.UUID, includeCredentials bool) (*UserExport, error) {
+	tx, err := db.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	export := &UserExport{Format: UserExportFormat, Version: UserExportVersion, ExportedAt: time.Now().UTC(), UserID: userID}
+	for _, table := range migrationTables {
+		rows, err := exportRows(ctx, tx, table, userID)
+		if err != nil {
+			return nil, err
+		}
+		if len(table.derived) > 0 {
+			for i := range rows {
+				if rows[i], err = dropColumns(rows[i], table.derived); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if table.name == "users" {
+			if len(rows) == 0 {
+				return nil, nil
+			}
+			if !includeCredentials {
+				if rows[0], err = dropColumns(rows[0], userCredentialColumns); err != nil {
+					return nil, err
+				}
+			}
+		}
+		export.Tables = append(export.Tables, ExportedTable{Name: table.name, Rows: rows})
+	}
+	return export, nil
+}
+
+// exportRows returns the user's rows of a table as JSON objects
+func exportRows(ctx context.Context, tx pgx.Tx, table migrationTable, userID uuid.UUID) ([]json.RawMessage, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT to_jsonb(t) FROM `+pgx.Identifier{table.name}.Sanitize()+` t WHERE `+table.where,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export %s: %w", table.name, err)
+	}
+	defer rows.Close()
+
+	result := []json.RawMessage{}
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", table.name, err)
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// ImportUser adds the user in an export from another deployment, giving every row a new
+// ID and rewriting the references between them. Catalog skills are matched by name, and
+// references to data every user shares, such as job postings, are cleared. With into
+// set, the rows are added to that existing account instead of a new one, and settings
+// it already has are kept. Nothing is imported if any row fails, for example because an
+// account already has the email address or a story ID.
+func (db *DB) ImportUser(ctx context.Context, export *UserExport, into *uuid.UUID) (*UserImport, error) {
+	if export.Format != UserExportFormat {
+		return nil, fmt.Errorf("not a user export (format %q)", export.Format)
+	}
+	if export.Version < 1 || export.Version > UserExportVersion {
+		return nil, fmt.Errorf("unsupported user export version %d (this server reads up to %d)", export.Version, UserExportVersion)
+	}
+	exported := make(map[string][]json.RawMessage, len(export.Tables))
+	for _, t := range export.Tables {
+		if findMigrationTable(t.Name) == nil {
+			return nil, fmt.Errorf("user export has unknown table %q", t.Name)
+		}
+		exported[t.Name] = t.Rows
+	}
+	ids := map[string]string{} // Exported IDs to the IDs they were given here
+	blobs, err := addressBlobs(exported["artifact_blobs"], ids)
+	if err != nil {
+		return nil, err
+	}
+	exported["artifact_blobs"] = blobs
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result := &UserImport{Rows: map[string]int{}, Existing: map[string]int{}}
+	if into != nil {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, *into).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to get user: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("user %s not found", *into)
+		}
+		ids[export.UserID.String()] = into.String()
+		result.UserID = *into
+	}
+
+	for _, table := range migrationTables {
+		rows := exported[table.name]
+		if len(rows) == 0 || (table.name == "users" && into != nil) {
+			continue
+		}
+		columns, err := tableColumns(ctx, tx, table.name)
+		if err != nil {
+			return nil, err
+		}
+		for _, raw := range rows {
+			var row map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &row); err != nil {
+				return nil, fmt.Errorf("invalid %s row: %w", table.name, err)
+			}
+			if table.naturalKey != "" {
+				found, err := matchNaturalKey(ctx, tx, table, row, ids)
+				if err != nil {
+					return nil, err
+				}
+				if found {
+					result.Existing[table.name]++
+					continue
+				}
+			}
+			if err := remapRow(table, row, ids); err != nil {
+				return nil, err
+			}
+			inserted, err := insertRow(ctx, tx, table, row, columns)
+			if err != nil {
+				return nil, err
+			}
+			if !inserted {
+				result.Existing[table.name]++
+				continue
+			}
+			result.Rows[table.name]++
+			if table.name == "users" {
+				result.UserID, _ = uuid.Parse(ids[export.UserID.String()])
+			}
+		}
+	}
+	if result.UserID == uuid.Nil {
+		return nil, fmt.Errorf("user export has no user")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return result, nil
+}
+
+// addressBlobs recomputes the address of each exported artifact blob from its content,
+// so an export can't store a payload under another payload's address, where this
+// deployment's saves of that payload would reuse it. Text and binary blobs must match the
+// hash they were exported with. JSON payloads come back from Postgres reformatted, so
+// their saved hash can't be checked; they are stored under the hash of the content as
+// exported instead. ids records the address each exported hash was given.
+func addressBlobs(rows []json.RawMessage, ids map[string]string) ([]json.RawMessage, error) {
+	addressed := make([]json.RawMessage, 0, len(rows))
+	for _, raw := range rows {
+		var row map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &row); err != nil {
+			return nil, fmt.Errorf("invalid artifact_blobs row: %w", err)
+		}
+		var hash, format string
+		if err := json.Unmarshal(row["hash"], &hash); err != nil {
+			return nil, fmt.Errorf("invalid artifact_blobs row: hash: %w", err)
+		}
+		if err := json.Unmarshal(row["format"], &format); err != nil {
+			return nil, fmt.Errorf("invalid artifact_blobs row %s: format: %w", hash, err)
+		}
+		data, err := blobPayload(format, row)
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact_blobs row %s: %w", hash, err)
+		}
+		address := artifactBlobHash(format, data)
+		if format != ContentFormatJSON && address != hash {
+			return nil, fmt.Errorf("artifact blob %s does not match its content", hash)
+		}
+		ids[hash] = address
+		row["hash"], _ = json.Marshal(address)
+		row["size_bytes"], _ = json.Marshal(len(data))
+		encoded, err := json.Marshal(row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode artifact_blobs row: %w", err)
+		}
+		addressed = append(addressed, encoded)
+	}
+	return addressed, nil
+}
+
+// blobPayload returns the bytes an exported artifact blob stores in its format's column
+func blobPayload(format string, row map[string]json.RawMessage) ([]byte, error) {
+	switch format {
+	case ContentFormatJSON:
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, row["content"]); err != nil {
+			return nil, fmt.Errorf("content: %w", err)
+		}
+		return compact.Bytes(), nil
+	case ContentFormatText:
+		var text string
+		if err := json.Unmarshal(row["text_content"], &text); err != nil {
+			return nil, fmt.Errorf("text_content: %w", err)
+		}
+		return []byte(text), nil
+	case ContentFormatBinary:
+		// Postgres writes bytea to JSON as \x followed by hex digits
+		var encoded string
+		if err := json.Unmarshal(row["binary_content"], &encoded); err != nil {
+			return nil, fmt.Errorf("binary_content: %w", err)
+		}
+		data, err := hex.DecodeString(strings.TrimPrefix(encoded, `\x`))
+		if err != nil {
+			return nil, fmt.Errorf("binary_content: %w", err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+// findMigrationTable returns the export table named name, or nil
+func findMigrationTable(name string) *migrationTable {
+	for i := range migrationTables {
+		if migrationTables[i].name == name {
+			return &migrationTables[i]
+		}
+	}
+	return nil
+}
+
+// tableColumns returns the columns a table has on this deployment
+func tableColumns(ctx context.Context, tx pgx.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT column_name FROM information_schema.columns
+		 WHERE table_schema = current_schema() AND table_name = $1`,
+		table,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s columns: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan %s column: %w", table, err)
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// matchNaturalKey maps a catalog row to this deployment's row with the same natural key,
+// reporting whether there was one
+func matchNaturalKey(ctx context.Context, tx pgx.Tx, table migrationTable, row map[string]json.RawMessage, ids map[string]string) (bool, error) {
+	var key, oldID string
+	if err := json.Unmarshal(row[table.naturalKey], &key); err != nil {
+		return false, fmt.Errorf("invalid %s row: %s: %w", table.name, table.naturalKey, err)
+	}
+	if err := json.Unmarshal(row["id"], &oldID); err != nil {
+		return false, fmt.Errorf("invalid %s row: id: %w", table.name, err)
+	}
+	var id uuid.UUID
+	err := tx.QueryRow(ctx,
+		`SELECT id FROM `+pgx.Identifier{table.name}.Sanitize()+` WHERE `+pgx.Identifier{table.naturalKey}.Sanitize()+` = $1`,
+		key,
+	).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to match %s: %w", table.name, err)
+	}
+	ids[oldID] = id.String()
+	return true, nil
+}
+
+// remapRow gives an exported row a new ID, recorded in ids, and rewrites its references
+// to other exported rows. References to rows that were not exported, and to data every
+// user shares, are cleared.
+func remapRow(table migrationTable, row map[string]json.RawMessage, ids map[string]string) error {
+	if raw, ok := row["id"]; ok {
+		var oldID string
+		if err := json.Unmarshal(raw, &oldID); err != nil {
+			return fmt.Errorf("invalid %s row: id: %w", table.name, err)
+		}
+		newID := uuid.NewString()
+		ids[oldID] = newID
+		row["id"], _ = json.Marshal(newID)
+	}
+	for column := range table.refs {
+		raw, ok := row[column]
+		if !ok || string(raw) == "null" {
+			continue
+		}
+		var oldID string
+		if err := json.Unmarshal(raw, &oldID); err != nil {
+			return fmt.Errorf("invalid %s row: %s: %w", table.name, column, err)
+		}
+		if newID, ok := ids[oldID]; ok {
+			row[column], _ = json.Marshal(newID)
+		} else {
+			row[column] = json.RawMessage("null")
+		}
+	}
+	for _, column := range table.clear {
+		if _, ok := row[column]; ok {
+			row[column] = json.RawMessage("null")
+		}
+	}
+	return nil
+}
+
+// insertRow inserts the columns of row this deployment has, reporting false when the
+// table keeps existing rows and already had one with the same key
+func insertRow(ctx context.Context, tx pgx.Tx, table migrationTable, row map[string]json.RawMessage, columns map[string]bool) (bool, error) {
+	var names []string
+	for name := range row {
+		if columns[name] {
+			names = append(names, pgx.Identifier{name}.Sanitize())
+		}
+	}
+	data, err := json.Marshal(row)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode %s row: %w", table.name, err)
+	}
+	list := strings.Join(names, ", ")
+	tableName := pgx.Identifier{table.name}.Sanitize()
+	query := `INSERT INTO ` + tableName + ` (` + list + `) SELECT ` + list +
+		` FROM jsonb_populate_record(NULL::` + tableName + `, $1::jsonb)`
+	if table.keepExisting {
+		query += ` ON CONFLICT DO NOTHING`
+	}
+	tag, err := tx.Exec(ctx, query, string(data))
+	if err != nil {
+		return false, fmt.Errorf("failed to import %s: %w", table.name, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// dropColumns removes columns from an exported row
+func dropColumns(raw json.RawMessage, columns []string) (json.RawMessage, error) {
+	var row map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &row); err != nil {
+		return nil, fmt.Errorf("invalid exported row: %w", err)
+	}
+	for _, column := range columns {
+		delete(row, column)
+	}
+	return json.Marshal(row)
+}
@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemapRow(t *testing.T) {
+	runs := *findMigrationTable("pipeline_runs")
+	user, run, posting := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	newUser := uuid.NewString()
+	ids := map[string]string{user: newUser}
+
+	var row map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "`+run+`", "user_id": "`+user+`",
+		"job_posting_id": "`+posting+`", "company": "Acme", "deadline_at": "2026-03-01T00:00:00Z"}`), &row))
+	require.NoError(t, remapRow(runs, row, ids))
+
+	newRun := ids[run]
+	require.NotEmpty(t, newRun)
+	assert.NotEqual(t, run, newRun)
+	assert.JSONEq(t, `"`+newRun+`"`, string(row["id"]))
+	assert.JSONEq(t, `"`+newUser+`"`, string(row["user_id"]))
+	assert.JSONEq(t, `null`, string(row["job_posting_id"]), "shared postings are not exported")
+	assert.JSONEq(t, `"Acme"`, string(row["company"]))
+
+	suggestions := *findMigrationTable("bullet_suggestions")
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "`+uuid.NewString()+`", "user_id": "`+user+`",
+		"run_id": "`+run+`", "story_id": "`+uuid.NewString()+`"}`), &row))
+	require.NoError(t, remapRow(suggestions, row, ids))
+	assert.JSONEq(t, `"`+newRun+`"`, string(row["run_id"]), "references follow the rows they refer to")
+	assert.JSONEq(t, `null`, string(row["story_id"]), "references to rows not exported are cleared")
+}
+
+func TestMigrationTables_ParentsFirst(t *testing.T) {
+	seen := map[string]bool{}
+	for _, table := range migrationTables {
+		for column, parent := range table.refs {
+			assert.True(t, seen[parent], "%s.%s refers to %s, which is imported later", table.name, column, parent)
+		}
+		assert.False(t, seen[table.name], "%s is listed twice", table.name)
+		seen[table.name] = true
+	}
+}
+
+func TestDropColumns(t *testing.T) {
+	row, err := dropColumns(json.RawMessage(`{"id": "x", "password_hash": "$2a$...", "password_set": true}`), userCredentialColumns)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id": "x"}`, string(row))
+
+	row, err = dropColumns(json.RawMessage(`{"hash": "ab12", "format": "text", "ref_count": 3}`), findMigrationTable("artifact_blobs").derived)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"hash": "ab12", "format": "text"}`, string(row), "reference counts are rebuilt on import")
+}
+
+func TestImportUser_RejectsInvalidExports(t *testing.T) {
+	db := &DB{}
+	for name, export := range map[string]*UserExport{
+		"wrong format":  {Format: "jsonresume", Version: 1},
+		"newer version": {Format: UserExportFormat, Version: UserExportVersion + 1},
+		"unknown table": {Format: UserExportFormat, Version: UserExportVersion, Tables: []ExportedTable{{Name: "pg_authid"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := db.ImportUser(context.Background(), export, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// blobRow is an exported artifact_blobs row holding payload in its format's column
+func blobRow(hash, format, column, payload string) json.RawMessage {
+	return json.RawMessage(`{"hash": "` + hash + `", "format": "` + format + `", "` + column + `": ` + payload + `, "size_bytes": 1}`)
+}
+
+func TestAddressBlobs(t *testing.T) {
+	text := artifactBlobHash(ContentFormatText, []byte("Staff SRE at Acme"))
+	binary := artifactBlobHash(ContentFormatBinary, []byte("%PDF"))
+	saved := artifactBlobHash(ContentFormatJSON, []byte(`{"title":"SRE","company":"Acme"}`))
+	ids := map[string]string{}
+
+	rows, err := addressBlobs([]json.RawMessage{
+		blobRow(text, ContentFormatText, "text_content", `"Staff SRE at Acme"`),
+		blobRow(binary, ContentFormatBinary, "binary_content", `"\\x25504446"`),
+		blobRow(saved, ContentFormatJSON, "content", `{"title": "SRE", "company": "Acme"}`),
+	}, ids)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, text, ids[text])
+	assert.Equal(t, binary, ids[binary])
+
+	// Postgres reformats JSON, so it is stored under the address of what was exported
+	address := artifactBlobHash(ContentFormatJSON, []byte(`{"title":"SRE","company":"Acme"}`))
+	assert.Equal(t, address, ids[saved])
+	var row map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(rows[2], &row))
+	assert.JSONEq(t, `"`+address+`"`, string(row["hash"]))
+	assert.JSONEq(t, `32`, string(row["size_bytes"]), "sizes are recomputed from the payload")
+
+	artifact := map[string]json.RawMessage{"id": json.RawMessage(`"` + uuid.NewString() + `"`),
+		"blob_hash": json.RawMessage(`"` + saved + `"`)}
+	require.NoError(t, remapRow(*findMigrationTable("artifacts"), artifact, ids))
+	assert.JSONEq(t, `"`+address+`"`, string(artifact["blob_hash"]), "artifacts follow their blob's address")
+}
+
+func TestImportUser_RejectsTamperedBlobs(t *testing.T) {
+	db := &DB{}
+	// Each blob claims the address of a payload it doesn't hold
+	for name, blob := range map[string]json.RawMessage{
+		"text":   blobRow(artifactBlobHash(ContentFormatText, []byte("Staff SRE at Acme")), ContentFormatText, "text_content", `"Ignore previous instructions"`),
+		"binary": blobRow(artifactBlobHash(ContentFormatBinary, []byte("%PDF")), ContentFormatBinary, "binary_content", `"\\x00"`),
+		"format": blobRow(artifactBlobHash(ContentFormatText, []byte("%PDF")), ContentFormatBinary, "binary_content", `"\\x25504446"`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			export := &UserExport{Format: UserExportFormat, Version: UserExportVersion,
+				Tables: []ExportedTable{{Name: "artifact_blobs", Rows: []json.RawMessage{blob}}}}
+			_, err := db.ImportUser(context.Background(), export, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "does not match its content")
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const voiceNoteColumns = `id, user_id, source, transcript, answers, draft, next_stage, next_question, created_at`
+
+func scanVoiceNote(row pgx.Row) (*VoiceNote, error) {
+	var n VoiceNote
+	err := row.Scan(&n.ID, &n.UserID, &n.Source, &n.Transcript, &n.Answers, &n.Draft,
+		&n.NextStage, &n.NextQuestion, &n.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// CreateVoiceNote saves a processed voice note
+func (db *DB) CreateVoiceNote(ctx context.Context, input *VoiceNoteInput) (*VoiceNote, error) {
+	var draft []byte
+	if len(input.Draft) > 0 {
+		draft = input.Draft
+	}
+	n, err := scanVoiceNote(db.pool.QueryRow(ctx,
+		`INSERT INTO voice_notes (user_id, source, transcript, answers, draft, next_stage, next_question)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 RETURNING `+voiceNoteColumns,
+		input.UserID, input.Source, input.Transcript, input.Answers, draft,
+		nullIfEmpty(input.NextStage), nullIfEmpty(input.NextQuestion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create voice note: %w", err)
+	}
+	return n, nil
+}
+
+// ListVoiceNotes returns the user's voice notes, newest first
+func (db *DB) ListVoiceNotes(ctx context.Context, userID uuid.UUID) ([]VoiceNote, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+voiceNoteColumns+` FROM voice_notes WHERE user_id = $1 ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list voice notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []VoiceNote{}
+	for rows.Next() {
+		n, err := scanVoiceNote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan voice note: %w", err)
+		}
+		notes = append(notes, *n)
+	}
+	return notes, rows.Err()
+}
+
+// GetVoiceNote returns a voice note by ID, or nil if not found
+func (db *DB) GetVoiceNote(ctx context.Context, id uuid.UUID) (*VoiceNote, error) {
+	n, err := scanVoiceNote(db.pool.QueryRow(ctx,
+		`SELECT `+voiceNoteColumns+` FROM voice_notes WHERE id = $1`, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get voice note: %w", err)
+	}
+	return n, nil
+}
+
+// DeleteVoiceNote deletes a voice note
+func (db *DB) DeleteVoiceNote(ctx context.Context, id uuid.UUID) error {
+	result, err := db.pool.Exec(ctx, `DELETE FROM voice_notes WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete voice note: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("voice note not found: %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// -----------------------------------------------------------------------------
+// Webhook Methods
+// -----------------------------------------------------------------------------
+
+// ValidateWebhook checks a webhook's URL and events, subscribing it to every event when
+// none are given
+func ValidateWebhook(input *WebhookInput) error {
+	u, err := url.Parse(input.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("url must be an https URL")
+	}
+	if input.Secret == "" {
+		return fmt.Errorf("secret is required")
+	}
+	if len(input.Events) == 0 {
+		input.Events = slices.Clone(WebhookEvents)
+	}
+	for _, event := range input.Events {
+		if !slices.Contains(WebhookEvents, event) {
+			return fmt.Errorf("invalid webhook event %q", event)
+		}
+	}
+	slices.Sort(input.Events)
+	input.Events = slices.Compact(input.Events)
+	return nil
+}
+
+const webhookColumns = `id, user_id, url, secret, events, created_at`
+
+// scanWebhook scans a webhooks row selected with webhookColumns
+func scanWebhook(row pgx.Row) (*Webhook, error) {
+	var w Webhook
+	if err := row.Scan(&w.ID, &w.UserID, &w.URL, &w.Secret, &w.Events, &w.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// CreateWebhook registers a webhook
+func (db *DB) CreateWebhook(ctx context.Context, input *WebhookInput) (*Webhook, error) {
+	w, err := scanWebhook(db.pool.QueryRow(ctx,
+		`INSERT INTO webhooks (user_id, url, secret, events)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+webhookColumns,
+		input.UserID, input.URL, input.Secret, input.Events,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return w, nil
+}
+
+// GetWebhook retrieves one of the user's webhooks, or nil if the user has no such webhook
+func (db *DB) GetWebhook(ctx context.Context, id, userID uuid.UUID) (*Webhook, error) {
+	w, err := scanWebhook(db.pool.QueryRow(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE id = $1 AND user_id = $2`,
+		id, userID,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return w, nil
+}
+
+// ListWebhooks lists the user's webhooks, oldest first
+func (db *DB) ListWebhooks(ctx context.Context, userID uuid.UUID) ([]Webhook, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE user_id = $1 ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, *w)
+	}
+	return webhooks, rows.Err()
+}
+
+// DeleteWebhook deletes one of the user's webhooks and its deliveries. Returns false if
+// the user has no such webhook.
+func (db *DB) DeleteWebhook(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	result, err := db.pool.Exec(ctx, `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// -----------------------------------------------------------------------------
+// Webhook Delivery Methods
+// -----------------------------------------------------------------------------
+
+const webhookDeliveryColumns = `d.id, d.webhook_id, d.run_id, d.event, d.payload, d.status, d.attempts,
+	d.next_attempt_at, d.response_status, d.last_error, d.created_at, d.delivered_at`
+
+// scanWebhookDelivery scans a webhook_deliveries row selected with
+// webhookDeliveryColumns, followed by the columns in extra
+func scanWebhookDelivery(row pgx.Row, extra ...any) (*WebhookDelivery, error) {
+	var d WebhookDelivery
+	var nextAttemptAt time.Time
+	dest := append([]any{&d.ID, &d.WebhookID, &d.RunID, &d.Event, &d.Payload, &d.Status, &d.Attempts,
+		&nextAttemptAt, &d.ResponseStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if d.Status == WebhookDeliveryPending {
+		d.NextAttemptAt = &nextAttemptAt
+	}
+	return &d, nil
+}
+
+// EnqueueWebhookDeliveries queues payload for each of the user's webhooks subscribed to
+// event. eventKey identifies the event: an event already queued under the same key is
+// not queued again, so every replica can enqueue the events it observes. Returns the
+// number of deliveries queued.
+func (db *DB) EnqueueWebhookDeliveries(ctx context.Context, userID uuid.UUID, runID *uuid.UUID, event, eventKey string, payload any) (int64, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+	result, err := db.pool.Exec(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, run_id, event, event_key, payload)
+		 SELECT id, $2, $3, $4, $5 FROM webhooks WHERE user_id = $1 AND $3 = ANY(events)
+		 ON CONFLICT (webhook_id, event_key) DO NOTHING`,
+		userID, runID, event, eventKey, data,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// ClaimWebhookDeliveries claims up to limit pending deliveries that are due, with their
+// webhook's URL and secret, oldest first. Each is held for lease: one not recorded by
+// then, as when the claiming server dies, is due again.
+func (db *DB) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]WebhookDelivery, error) {
+	rows, err := db.pool.Query(ctx,
+		`WITH due AS (
+		     SELECT id FROM webhook_deliveries
+		      WHERE status = 'pending' AND next_attempt_at <= NOW()
+		      ORDER BY next_attempt_at
+		      LIMIT $1
+		      FOR UPDATE SKIP LOCKED
+		 )
+		 UPDATE webhook_deliveries d SET next_attempt_at = $2
+		   FROM due, webhooks w
+		  WHERE d.id = due.id AND w.id = d.webhook_id
+		 RETURNING `+webhookDeliveryColumns+`, w.url, w.secret`,
+		limit, time.Now().Add(lease),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		var url, secret string
+		d, err := scanWebhookDelivery(rows, &url, &secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		d.URL, d.Secret = url, secret
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordWebhookAttempt records an attempt at a delivery: delivered when the attempt has
+// no error, else pending until attempt.RetryAt, or failed when there is no retry
+func (db *DB) RecordWebhookAttempt(ctx context.Context, id uuid.UUID, attempt *WebhookAttempt) error {
+	status := WebhookDeliveryDelivered
+	if attempt.Error != "" {
+		status = WebhookDeliveryFailed
+		if attempt.RetryAt != nil {
+			status = WebhookDeliveryPending
+		}
+	}
+	var responseStatus *int
+	if attempt.ResponseStatus != 0 {
+		responseStatus = &attempt.ResponseStatus
+	}
+	_, err := db.pool.Exec(ctx,
+		`UPDATE webhook_deliveries
+		 SET attempts = attempts + 1, status = $2, response_status = $3, last_error = $4,
+		     next_attempt_at = COALESCE($5, next_attempt_at),
+		     delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() END
+		 WHERE id = $1`,
+		id, status, responseStatus, nullIfEmpty(attempt.Error), attempt.RetryAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries lists a webhook's deliveries, newest first
+func (db *DB) ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]WebhookDelivery, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries d
+		 WHERE d.webhook_id = $1 ORDER BY d.created_at DESC LIMIT $2`,
+		webhookID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidateWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   WebhookInput
+		wantErr bool
+	}{
+		{name: "some events", input: WebhookInput{URL: "https://hooks.example.com/x", Secret: "s", Events: []string{WebhookRunFailed}}},
+		{name: "plain http", input: WebhookInput{URL: "http://hooks.example.com/x", Secret: "s"}, wantErr: true},
+		{name: "no url", input: WebhookInput{Secret: "s"}, wantErr: true},
+		{name: "no secret", input: WebhookInput{URL: "https://hooks.example.com/x"}, wantErr: true},
+		{name: "unknown event", input: WebhookInput{URL: "https://hooks.example.com/x", Secret: "s", Events: []string{"run.deleted"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateWebhook(&tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ValidateWebhook() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	input := WebhookInput{URL: "https://hooks.example.com/x", Secret: "s", Events: []string{WebhookRunFailed, WebhookRunCreated, WebhookRunFailed}}
+	if err := ValidateWebhook(&input); err != nil {
+		t.Fatalf("ValidateWebhook() error = %v", err)
+	}
+	if want := []string{WebhookRunCreated, WebhookRunFailed}; !slices.Equal(input.Events, want) {
+		t.Errorf("events = %v, want %v", input.Events, want)
+	}
+
+	input = WebhookInput{URL: "https://hooks.example.com/x", Secret: "s"}
+	if err := ValidateWebhook(&input); err != nil {
+		t.Fatalf("ValidateWebhook() error = %v", err)
+	}
+	if len(input.Events) != len(WebhookEvents) {
+		t.Errorf("events = %v, want every event", input.Events)
+	}
+}
@@ -0,0 +1,280 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const rulePackColumns = `id, workspace_id, name, mode, priority, rules, created_by, created_at, updated_at`
+
+func scanRulePack(row pgx.Row) (*RulePack, error) {
+	var p RulePack
+	err := row.Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Mode, &p.Priority, &p.Rules,
+		&p.CreatedBy, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CreateWorkspace creates a workspace with its creator as its first admin
+func (db *DB) CreateWorkspace(ctx context.Context, name string, createdBy uuid.UUID) (*Workspace, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var ws Workspace
+	err = tx.QueryRow(ctx,
+		`INSERT INTO workspaces (name, created_by) VALUES ($1, $2)
+		 RETURNING id, name, created_by, created_at`,
+		name, createdBy,
+	).Scan(&ws.ID, &ws.Name, &ws.CreatedBy, &ws.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)`,
+		ws.ID, createdBy, WorkspaceRoleAdmin,
+	); err != nil {
+		return nil, fmt.Errorf("failed to add workspace admin: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit workspace: %w", err)
+	}
+	ws.Role = WorkspaceRoleAdmin
+	return &ws, nil
+}
+
+// ListWorkspacesForUser returns the workspaces the user belongs to, with the user's role,
+// ordered by name
+func (db *DB) ListWorkspacesForUser(ctx context.Context, userID uuid.UUID) ([]Workspace, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT w.id, w.name, w.created_by, w.created_at, m.role
+		 FROM workspaces w JOIN workspace_members m ON m.workspace_id = w.id
+		 WHERE m.user_id = $1
+		 ORDER BY lower(w.name), w.created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	workspaces := []Workspace{}
+	for rows.Next() {
+		var ws Workspace
+		if err := rows.Scan(&ws.ID, &ws.Name, &ws.CreatedBy, &ws.CreatedAt, &ws.Role); err != nil {
+			return nil, fmt.Errorf("failed to scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, ws)
+	}
+	return workspaces, rows.Err()
+}
+
+// GetWorkspaceMember returns the user's membership in a workspace, or nil if the user
+// is not a member
+func (db *DB) GetWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) (*WorkspaceMember, error) {
+	var m WorkspaceMember
+	err := db.pool.QueryRow(ctx,
+		`SELECT workspace_id, user_id, role, created_at FROM workspace_members
+		 WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceID, userID,
+	).Scan(&m.WorkspaceID, &m.UserID, &m.Role, &m.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get workspace member: %w", err)
+	}
+	return &m, nil
+}
+
+// ListWorkspaceMembers returns a workspace's members, admins first
+func (db *DB) ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceMember, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT workspace_id, user_id, role, created_at FROM workspace_members
+		 WHERE workspace_id = $1
+		 ORDER BY role = 'admin' DESC, created_at`,
+		workspaceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []WorkspaceMember{}
+	for rows.Next() {
+		var m WorkspaceMember
+		if err := rows.Scan(&m.WorkspaceID, &m.UserID, &m.Role, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workspace member: %w", err)
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// SetWorkspaceMember adds a user to a workspace, or changes their role if they already
+// belong to it
+func (db *DB) SetWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID, role string) (*WorkspaceMember, error) {
+	var m WorkspaceMember
+	err := db.pool.QueryRow(ctx,
+		`INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)
+		 ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = EXCLUDED.role
+		 RETURNING workspace_id, user_id, role, created_at`,
+		workspaceID, userID, role,
+	).Scan(&m.WorkspaceID, &m.UserID, &m.Role, &m.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set workspace member: %w", err)
+	}
+	return &m, nil
+}
+
+// RemoveWorkspaceMember removes a user from a workspace
+func (db *DB) RemoveWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) error {
+	result, err := db.pool.Exec(ctx,
+		`DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`,
+		workspaceID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove workspace member: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("workspace member not found: %s", userID)
+	}
+	return nil
+}
+
+// UpsertRulePack creates a rule pack, or replaces the workspace's pack with the same name
+func (db *DB) UpsertRulePack(ctx context.Context, workspaceID, createdBy uuid.UUID, input *RulePackInput) (*RulePack, error) {
+	p, err := scanRulePack(db.pool.QueryRow(ctx,
+		`INSERT INTO workspace_rule_packs (workspace_id, name, mode, priority, rules, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (workspace_id, lower(name)) DO UPDATE SET
+		     name = EXCLUDED.name,
+		     mode = EXCLUDED.mode,
+		     priority = EXCLUDED.priority,
+		     rules = EXCLUDED.rules,
+		     updated_at = NOW()
+		 RETURNING `+rulePackColumns,
+		workspaceID, input.Name, input.Mode, input.Priority, input.Rules, createdBy,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save rule pack: %w", err)
+	}
+	return p, nil
+}
+
+// ListRulePacks returns a workspace's rule packs in the order they apply
+func (db *DB) ListRulePacks(ctx context.Context, workspaceID uuid.UUID) ([]RulePack, error) {
+	return db.queryRulePacks(ctx,
+		`SELECT `+rulePackColumns+` FROM workspace_rule_packs
+		 WHERE workspace_id = $1
+		 ORDER BY priority DESC, lower(name)`,
+		workspaceID,
+	)
+}
+
+// ListRulePacksForUser returns the rule packs of every workspace the user belongs to
+func (db *DB) ListRulePacksForUser(ctx context.Context, userID uuid.UUID) ([]RulePack, error) {
+	return db.queryRulePacks(ctx,
+		`SELECT `+rulePackColumns+` FROM workspace_rule_packs
+		 WHERE workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1)
+		 ORDER BY priority DESC, lower(name)`,
+		userID,
+	)
+}
+
+func (db *DB) queryRulePacks(ctx context.Context, query string, args ...any) ([]RulePack, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rule packs: %w", err)
+	}
+	defer rows.Close()
+
+	packs := []RulePack{}
+	for rows.Next() {
+		p, err := scanRulePack(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rule pack: %w", err)
+		}
+		packs = append(packs, *p)
+	}
+	return packs, rows.Err()
+}
+
+// GetRulePack returns a rule pack by ID, or nil if not found
+func (db *DB) GetRulePack(ctx context.Context, id uuid.UUID) (*RulePack, error) {
+	p, err := scanRulePack(db.pool.QueryRow(ctx,
+		`SELECT `+rulePackColumns+` FROM workspace_rule_packs WHERE id = $1`, id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get rule pack: %w", err)
+	}
+	return p, nil
+}
+
+// DeleteRulePack deletes a rule pack; its audit records are kept
+func (db *DB) DeleteRulePack(ctx context.Context, id uuid.UUID) error {
+	result, err := db.pool.Exec(ctx, `DELETE FROM workspace_rule_packs WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete rule pack: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("rule pack not found: %s", id)
+	}
+	return nil
+}
+
+// RecordRulePackApplications records the rule packs applied to a run
+func (db *DB) RecordRulePackApplications(ctx context.Context, runID uuid.UUID, userID *uuid.UUID, applied []RulePackApplicationInput) error {
+	for _, a := range applied {
+		rules := a.Rules
+		if rules == nil {
+			rules = []string{}
+		}
+		if _, err := db.pool.Exec(ctx,
+			`INSERT INTO rule_pack_applications (run_id, workspace_id, pack_id, user_id, pack_name, mode, rules)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			runID, a.WorkspaceID, a.PackID, userID, a.PackName, a.Mode, rules,
+		); err != nil {
+			return fmt.Errorf("failed to record rule pack application: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListRulePackApplications returns a workspace's most recent rule pack applications,
+// newest first
+func (db *DB) ListRulePackApplications(ctx context.Context, workspaceID uuid.UUID, limit int) ([]RulePackApplication, error) {
+	rows, err := db.pool.Query(ctx,
+		`SELECT id, run_id, workspace_id, pack_id, user_id, pack_name, mode, rules, applied_at
+		 FROM rule_pack_applications
+		 WHERE workspace_id = $1
+		 ORDER BY applied_at DESC
+		 LIMIT $2`,
+		workspaceID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rule pack applications: %w", err)
+	}
+	defer rows.Close()
+
+	applications := []RulePackApplication{}
+	for rows.Next() {
+		var a RulePackApplication
+		if err := rows.Scan(&a.ID, &a.RunID, &a.WorkspaceID, &a.PackID, &a.UserID, &a.PackName,
+			&a.Mode, &a.Rules, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rule pack application: %w", err)
+		}
+		applications = append(applications, a)
+	}
+	return applications, rows.Err()
+}
@@ -0,0 +1,193 @@
+// Package deadletter records background work that failed for good and retries it on
+// an operator's request.
+//
+// Notifications whose webhook or email delivery failed, and step jobs a worker could
+// not complete, are not retried automatically. They are kept as dead letters until an
+// operator retries or discards them:
+//   - A notification is resent to the user's current channel for its event, so a user
+//     who has fixed their webhook URL receives it there. If the user has since turned
+//     the notification off, the dead letter is discarded instead.
+//   - A step job is returned to the shared worker queue.
+//
+// A retry that fails again leaves the dead letter pending with one more attempt.
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+)
+
+// ErrNotRetryable is returned by Retry for dead letters that cannot be retried, such as
+// step jobs that have been deleted or that a dedicated backend executes
+var ErrNotRetryable = errors.New("dead letter cannot be retried")
+
+// Store is the subset of db.DB used to retry dead letters
+type Store interface {
+	GetNotificationRecipient(ctx context.Context, userID uuid.UUID, eventType string) (*db.NotificationRecipient, error)
+	RecordDeadLetter(ctx context.Context, input *db.DeadLetterInput) (*db.DeadLetter, error)
+	ResolveDeadLetter(ctx context.Context, id uuid.UUID, status string) (*db.DeadLetter, error)
+	RequeueStepJob(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// Sender delivers notifications; *notifications.Notifier implements it
+type Sender interface {
+	Send(ctx context.Context, recipient *db.NotificationRecipient, msg notifications.Message) error
+}
+
+// Notification returns the dead letter input for a notification that could not be
+// delivered to recipient. deliveryKey identifies the notification among the user's, e.g.
+// "run_completed:<run id>".
+func Notification(recipient *db.NotificationRecipient, deliveryKey string, runID *uuid.UUID, msg notifications.Message, sendErr error) *db.DeadLetterInput {
+	source := db.DeadLetterWebhook
+	if recipient.Channel == db.NotificationEmail {
+		source = db.DeadLetterEmail
+	}
+	userID := recipient.UserID
+	return &db.DeadLetterInput{
+		Source:       source,
+		RefKey:       deliveryKey,
+		UserID:       &userID,
+		RunID:        runID,
+		Payload:      msg,
+		ErrorMessage: sendErr.Error(),
+	}
+}
+
+// StepJobPayload is the payload of a step job dead letter
+type StepJobPayload struct {
+	Step     string `json:"step"`
+	WorkerID string `json:"worker_id,omitempty"`
+}
+
+// StepJob returns the dead letter input for a step job that failed on a worker
+func StepJob(job *db.StepJob, workerID string, jobErr error) *db.DeadLetterInput {
+	return &db.DeadLetterInput{
+		Source:       db.DeadLetterStepJob,
+		RefKey:       job.ID.String(),
+		RunID:        job.RunID,
+		Payload:      StepJobPayload{Step: job.Step, WorkerID: workerID},
+		ErrorMessage: jobErr.Error(),
+	}
+}
+
+// Retry retries a pending dead letter and returns it as resolved. When the retry
+// fails, the failure is recorded on the dead letter, which stays pending, and the
+// error is returned.
+func Retry(ctx context.Context, store Store, sender Sender, letter *db.DeadLetter) (*db.DeadLetter, error) {
+	switch letter.Source {
+	case db.DeadLetterWebhook, db.DeadLetterEmail:
+		return retryNotification(ctx, store, sender, letter)
+	case db.DeadLetterStepJob:
+		return retryStepJob(ctx, store, letter)
+	default:
+		return nil, fmt.Errorf("%w: unknown source %q", ErrNotRetryable, letter.Source)
+	}
+}
+
+// retryNotification resends a notification to the user's current channel
+func retryNotification(ctx context.Context, store Store, sender Sender, letter *db.DeadLetter) (*db.DeadLetter, error) {
+	var msg notifications.Message
+	if err := json.Unmarshal(letter.Payload, &msg); err != nil || msg.Event == "" || letter.UserID == nil {
+		return nil, fmt.Errorf("%w: stored notification is incomplete", ErrNotRetryable)
+	}
+	recipient, err := store.GetNotificationRecipient(ctx, *letter.UserID, msg.Event)
+	if err != nil {
+		return nil, err
+	}
+	if recipient == nil {
+		// The user turned this notification off; nothing is owed any more
+		return resolve(ctx, store, letter, db.DeadLetterDiscarded)
+	}
+
+	if sendErr := sender.Send(ctx, recipient, msg); sendErr != nil {
+		_, err := store.RecordDeadLetter(ctx, &db.DeadLetterInput{
+			Source:       letter.Source,
+			RefKey:       letter.RefKey,
+			UserID:       letter.UserID,
+			RunID:        letter.RunID,
+			Payload:      msg,
+			ErrorMessage: sendErr.Error(),
+		})
+		return nil, errors.Join(sendErr, err)
+	}
+	return resolve(ctx, store, letter, db.DeadLetterRetried)
+}
+
+// retryStepJob returns a failed step job to the worker queue
+func retryStepJob(ctx context.Context, store Store, letter *db.DeadLetter) (*db.DeadLetter, error) {
+	jobID, err := uuid.Parse(letter.RefKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid step job ID %q", ErrNotRetryable, letter.RefKey)
+	}
+	requeued, err := store.RequeueStepJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !requeued {
+		return nil, fmt.Errorf("%w: step job %s is gone, not failed, or not on the shared queue", ErrNotRetryable, jobID)
+	}
+	return resolve(ctx, store, letter, db.DeadLetterRetried)
+}
+
+// resolve marks a dead letter retried or discarded, falling back to the dead letter as
+// given if another caller resolved it first
+func resolve(ctx context.Context, store Store, letter *db.DeadLetter, status string) (*db.DeadLetter, error) {
+	resolved, err := store.ResolveDeadLetter(ctx, letter.ID, status)
+	if err != nil {
+		return nil, err
+	}
+	if resolved == nil {
+		return letter, nil
+	}
+	return resolved, nil
+}
+
+// Alarm raises an alert when the number of pending dead letters reaches a threshold.
+// It fires once per crossing and rearms when the count drops back below.
+type Alarm struct {
+	threshold int
+	firing    bool
+}
+
+// NewAlarm creates an alarm for threshold pending dead letters; zero never fires
+func NewAlarm(threshold int) *Alarm {
+	return &Alarm{threshold: threshold}
+}
+
+// Check reports whether depth newly reached the threshold
+func (a *Alarm) Check(depth int) bool {
+	if a.threshold <= 0 {
+		return false
+	}
+	if depth < a.threshold {
+		a.firing = false
+		return false
+	}
+	if a.firing {
+		return false
+	}
+	a.firing = true
+	return true
+}
+
+// AlertMessage is the notification sent when the alarm fires
+func AlertMessage(depth map[string]int, threshold int) notifications.Message {
+	total := 0
+	for _, n := range depth {
+		total += n
+	}
+	return notifications.Message{
+		Event:   "dead_letter_alert",
+		Subject: fmt.Sprintf("%d failed background items are waiting for an operator", total),
+		Body: fmt.Sprintf("The dead letter queue holds %d pending items (alert threshold %d): "+
+			"%d webhooks, %d emails, %d step jobs. Review them at GET /v1/admin/dead-letters.",
+			total, threshold, depth[db.DeadLetterWebhook], depth[db.DeadLetterEmail], depth[db.DeadLetterStepJob]),
+		Data: map[string]any{"pending": depth, "total": total, "threshold": threshold},
+	}
+}
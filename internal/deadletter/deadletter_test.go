@@ -0,0 +1,127 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+)
+
+// fakeStore keeps one user's notification preference and the step jobs that can be requeued
+type fakeStore struct {
+	recipient *db.NotificationRecipient
+	failedJob uuid.UUID
+	recorded  []*db.DeadLetterInput
+	resolved  map[uuid.UUID]string
+}
+
+func (f *fakeStore) GetNotificationRecipient(_ context.Context, _ uuid.UUID, _ string) (*db.NotificationRecipient, error) {
+	return f.recipient, nil
+}
+
+func (f *fakeStore) RecordDeadLetter(_ context.Context, input *db.DeadLetterInput) (*db.DeadLetter, error) {
+	f.recorded = append(f.recorded, input)
+	return &db.DeadLetter{Source: input.Source, RefKey: input.RefKey, Status: db.DeadLetterPending}, nil
+}
+
+func (f *fakeStore) ResolveDeadLetter(_ context.Context, id uuid.UUID, status string) (*db.DeadLetter, error) {
+	if f.resolved == nil {
+		f.resolved = make(map[uuid.UUID]string)
+	}
+	f.resolved[id] = status
+	return &db.DeadLetter{ID: id, Status: status}, nil
+}
+
+func (f *fakeStore) RequeueStepJob(_ context.Context, id uuid.UUID) (bool, error) {
+	return id == f.failedJob, nil
+}
+
+// fakeSender records deliveries, failing them while err is set
+type fakeSender struct {
+	err  error
+	sent []notifications.Message
+}
+
+func (f *fakeSender) Send(_ context.Context, _ *db.NotificationRecipient, msg notifications.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func notificationLetter(t *testing.T, user uuid.UUID) *db.DeadLetter {
+	t.Helper()
+	payload, err := json.Marshal(notifications.Message{Event: db.NotificationRunCompleted, Subject: "done"})
+	require.NoError(t, err)
+	return &db.DeadLetter{ID: uuid.New(), Source: db.DeadLetterWebhook, RefKey: "run_completed:x",
+		UserID: &user, Payload: payload, Status: db.DeadLetterPending}
+}
+
+func TestRetry_Notification(t *testing.T) {
+	user := uuid.New()
+	store := &fakeStore{recipient: &db.NotificationRecipient{UserID: user, Channel: db.NotificationWebhook}}
+	sender := &fakeSender{err: errors.New("webhook returned status 502")}
+	letter := notificationLetter(t, user)
+
+	_, err := Retry(context.Background(), store, sender, letter)
+	require.Error(t, err)
+	require.Len(t, store.recorded, 1, "a failed retry counts another attempt")
+	assert.Equal(t, letter.RefKey, store.recorded[0].RefKey)
+	assert.Empty(t, store.resolved)
+
+	sender.err = nil
+	resolved, err := Retry(context.Background(), store, sender, letter)
+	require.NoError(t, err)
+	assert.Equal(t, db.DeadLetterRetried, resolved.Status)
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "done", sender.sent[0].Subject)
+}
+
+func TestRetry_NotificationTurnedOff(t *testing.T) {
+	store := &fakeStore{}
+	sender := &fakeSender{}
+
+	resolved, err := Retry(context.Background(), store, sender, notificationLetter(t, uuid.New()))
+	require.NoError(t, err)
+	assert.Equal(t, db.DeadLetterDiscarded, resolved.Status)
+	assert.Empty(t, sender.sent)
+}
+
+func TestRetry_StepJob(t *testing.T) {
+	store := &fakeStore{failedJob: uuid.New()}
+
+	resolved, err := Retry(context.Background(), store, nil,
+		&db.DeadLetter{ID: uuid.New(), Source: db.DeadLetterStepJob, RefKey: store.failedJob.String()})
+	require.NoError(t, err)
+	assert.Equal(t, db.DeadLetterRetried, resolved.Status)
+
+	_, err = Retry(context.Background(), store, nil,
+		&db.DeadLetter{ID: uuid.New(), Source: db.DeadLetterStepJob, RefKey: uuid.NewString()})
+	assert.ErrorIs(t, err, ErrNotRetryable)
+}
+
+func TestNotification_SourceFollowsChannel(t *testing.T) {
+	recipient := &db.NotificationRecipient{UserID: uuid.New(), Channel: db.NotificationEmail}
+	input := Notification(recipient, "weekly_digest:x", nil, notifications.Message{}, errors.New("smtp down"))
+	assert.Equal(t, db.DeadLetterEmail, input.Source)
+	assert.Equal(t, "smtp down", input.ErrorMessage)
+}
+
+func TestAlarm(t *testing.T) {
+	a := NewAlarm(3)
+	assert.False(t, a.Check(2))
+	assert.True(t, a.Check(3))
+	assert.False(t, a.Check(5), "fires once per crossing")
+	assert.False(t, a.Check(1))
+	assert.True(t, a.Check(4), "rearms below the threshold")
+
+	assert.False(t, NewAlarm(0).Check(100), "zero disables the alarm")
+}
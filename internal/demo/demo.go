@@ -0,0 +1,98 @@
+// Package demo bundles canned data for running the product with no API keys or network
+// access: a sample experience bank, a sample job posting, pre-crawled company research,
+// and recorded LLM responses (a cassette) for the prompts a run over that data makes.
+package demo
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/research"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+//go:embed data/*
+var dataFiles embed.FS
+
+// JobURL is where the bundled job posting claims to come from; it is never fetched
+const JobURL = "https://northwindpay.example/careers/senior-backend-engineer"
+
+// APIKey stands in for a provider key in demo runs so key checks pass; every LLM call
+// is answered by the cassette and the key is never sent anywhere
+const APIKey = "demo"
+
+// Fixtures is the canned data a demo run uses in place of user input and external calls
+type Fixtures struct {
+	JobPosting string
+	JobURL     string
+	Cassette   *llm.Cassette // Recorded LLM responses
+
+	bank     []byte // Sample experience bank JSON
+	research []byte // Pre-crawled research session JSON
+}
+
+// Enabled reports whether DEMO_MODE is set to a true value
+func Enabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("DEMO_MODE"))
+	return enabled
+}
+
+// Load reads the bundled fixtures
+func Load() (*Fixtures, error) {
+	f := &Fixtures{JobURL: JobURL}
+	var err error
+	if f.bank, err = dataFiles.ReadFile("data/experience_bank.json"); err != nil {
+		return nil, fmt.Errorf("failed to read demo experience bank: %w", err)
+	}
+	if f.research, err = dataFiles.ReadFile("data/research_session.json"); err != nil {
+		return nil, fmt.Errorf("failed to read demo research: %w", err)
+	}
+	posting, err := dataFiles.ReadFile("data/job_posting.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read demo job posting: %w", err)
+	}
+	f.JobPosting = string(posting)
+
+	cassette, err := dataFiles.ReadFile("data/cassette.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read demo cassette: %w", err)
+	}
+	if f.Cassette, err = llm.LoadCassette(cassette); err != nil {
+		return nil, err
+	}
+
+	// Decode once up front so a broken bundle fails at startup rather than mid-run
+	if _, err := f.ExperienceBank(); err != nil {
+		return nil, err
+	}
+	if _, err := f.ResearchSession(""); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// ExperienceBank returns a fresh copy of the sample experience bank
+func (f *Fixtures) ExperienceBank() (*types.ExperienceBank, error) {
+	var bank types.ExperienceBank
+	if err := json.Unmarshal(f.bank, &bank); err != nil {
+		return nil, fmt.Errorf("failed to parse demo experience bank: %w", err)
+	}
+	return &bank, nil
+}
+
+// ResearchSession returns a fresh copy of the pre-crawled research, attributed to
+// company when it is set
+func (f *Fixtures) ResearchSession(company string) (*research.Session, error) {
+	var session research.Session
+	if err := json.Unmarshal(f.research, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse demo research: %w", err)
+	}
+	if company != "" {
+		session.Company = company
+	}
+	return &session, nil
+}
@@ -0,0 +1,39 @@
+package demo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	f, err := Load()
+	require.NoError(t, err)
+	assert.Contains(t, f.JobPosting, "Northwind Pay")
+	assert.Positive(t, f.Cassette.Len())
+
+	// Every call returns an independent copy, so runs cannot leak edits into each other
+	bank, err := f.ExperienceBank()
+	require.NoError(t, err)
+	require.NotEmpty(t, bank.Stories)
+	bank.Stories[0].Bullets = nil
+	again, err := f.ExperienceBank()
+	require.NoError(t, err)
+	assert.NotEmpty(t, again.Stories[0].Bullets)
+
+	session, err := f.ResearchSession("Northwind")
+	require.NoError(t, err)
+	assert.Equal(t, "Northwind", session.Company)
+	assert.NotEmpty(t, session.Corpus)
+	assert.Len(t, session.ToSources(), len(session.CrawledURLs))
+}
+
+func TestEnabled(t *testing.T) {
+	t.Setenv("DEMO_MODE", "true")
+	assert.True(t, Enabled())
+	t.Setenv("DEMO_MODE", "0")
+	assert.False(t, Enabled())
+	t.Setenv("DEMO_MODE", "")
+	assert.False(t, Enabled())
+}
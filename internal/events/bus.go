@@ -0,0 +1,151 @@
+// Package events fans out pipeline run events published through Postgres
+// LISTEN/NOTIFY, so consumers on any replica react to step and artifact
+// changes without polling the run_steps table.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// subscriberBuffer is how many undelivered events a Subscription holds before dropping
+const subscriberBuffer = 32
+
+// reconnectDelay is how long the bus waits before re-subscribing after the listener stops
+const reconnectDelay = 2 * time.Second
+
+// listenFunc opens a notification stream; stop releases it
+type listenFunc func(ctx context.Context) (notifications <-chan string, stop func(), err error)
+
+// Bus holds one LISTEN connection per process and delivers RunEvents to subscribers
+type Bus struct {
+	listen listenFunc
+
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+// Subscription receives events for one run, or for all runs when RunID is uuid.Nil
+type Subscription struct {
+	// C delivers events; it is closed by Close
+	C <-chan db.RunEvent
+
+	RunID uuid.UUID
+	ch    chan db.RunEvent
+	bus   *Bus
+	once  sync.Once
+}
+
+// NewBus creates a bus backed by database's RunEventsChannel. Call Run to start it.
+// A nil database gives a process-local bus fed only by Deliver.
+func NewBus(database *db.DB) *Bus {
+	if database == nil {
+		return newBus(nil)
+	}
+	return newBus(func(ctx context.Context) (<-chan string, func(), error) {
+		l, err := database.Listen(ctx, db.RunEventsChannel)
+		if err != nil {
+			return nil, nil, err
+		}
+		return l.C, l.Close, nil
+	})
+}
+
+func newBus(listen listenFunc) *Bus {
+	return &Bus{listen: listen, subs: make(map[*Subscription]struct{})}
+}
+
+// Run receives notifications until ctx is canceled, re-subscribing if the
+// connection is lost. Events published while disconnected are missed, so
+// consumers should read current state when they subscribe.
+func (b *Bus) Run(ctx context.Context) {
+	if b.listen == nil {
+		return
+	}
+	for ctx.Err() == nil {
+		notifications, stop, err := b.listen(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.WarnContext(ctx, "failed to listen for run events", "error", err)
+			}
+		} else {
+			b.consume(ctx, notifications)
+			stop()
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// consume dispatches notifications until the stream closes or ctx is canceled
+func (b *Bus) consume(ctx context.Context, notifications <-chan string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case payload, ok := <-notifications:
+			if !ok {
+				return
+			}
+			b.dispatch(payload)
+		}
+	}
+}
+
+// dispatch decodes a notification payload and delivers it to matching subscribers
+func (b *Bus) dispatch(payload string) {
+	var event db.RunEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		slog.Warn("ignoring malformed run event", "error", err)
+		return
+	}
+	b.Deliver(event)
+}
+
+// Deliver sends event to this process's subscribers only. Events written
+// through the database reach every replica via Postgres instead.
+func (b *Bus) Deliver(event db.RunEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		if sub.RunID != uuid.Nil && sub.RunID != event.RunID {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default: // Slow consumer; drop rather than stall every other subscriber
+		}
+	}
+}
+
+// Subscribe registers a subscription for runID (uuid.Nil for all runs).
+// Callers must Close the subscription when done.
+func (b *Bus) Subscribe(runID uuid.UUID) *Subscription {
+	ch := make(chan db.RunEvent, subscriberBuffer)
+	sub := &Subscription{C: ch, RunID: runID, ch: ch, bus: b}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Close unregisters the subscription and closes C. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		s.bus.mu.Unlock()
+		close(s.ch)
+	})
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func payload(t *testing.T, event db.RunEvent) string {
+	t.Helper()
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func receive(t *testing.T, sub *Subscription) db.RunEvent {
+	t.Helper()
+	select {
+	case event := <-sub.C:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return db.RunEvent{}
+	}
+}
+
+func TestBus_FiltersByRun(t *testing.T) {
+	bus := newBus(nil)
+	runA, runB := uuid.New(), uuid.New()
+
+	subA := bus.Subscribe(runA)
+	defer subA.Close()
+	subAll := bus.Subscribe(uuid.Nil)
+	defer subAll.Close()
+
+	bus.dispatch(payload(t, db.RunEvent{Type: db.RunEventStepStatus, RunID: runB, Step: "parse_job", Status: db.StepStatusCompleted}))
+	bus.dispatch(payload(t, db.RunEvent{Type: db.RunEventArtifactSaved, RunID: runA, Step: "job_profile"}))
+	bus.dispatch("not json")
+
+	got := receive(t, subA)
+	assert.Equal(t, db.RunEventArtifactSaved, got.Type)
+	assert.Equal(t, runA, got.RunID)
+	assert.Empty(t, subA.C)
+
+	assert.Equal(t, runB, receive(t, subAll).RunID)
+	assert.Equal(t, runA, receive(t, subAll).RunID)
+}
+
+func TestBus_DropsForSlowSubscribers(t *testing.T) {
+	bus := newBus(nil)
+	runID := uuid.New()
+	sub := bus.Subscribe(runID)
+	defer sub.Close()
+
+	for i := 0; i < subscriberBuffer+10; i++ {
+		bus.dispatch(payload(t, db.RunEvent{Type: db.RunEventStepStatus, RunID: runID}))
+	}
+	assert.Len(t, sub.C, subscriberBuffer)
+}
+
+func TestNewBus_LocalOnly(t *testing.T) {
+	bus := NewBus(nil)
+	bus.Run(context.Background()) // Returns immediately without a database
+
+	runID := uuid.New()
+	sub := bus.Subscribe(runID)
+	defer sub.Close()
+	bus.Deliver(db.RunEvent{Type: db.RunEventStepStatus, RunID: runID, Step: "render_latex"})
+	assert.Equal(t, "render_latex", receive(t, sub).Step)
+}
+
+func TestSubscription_CloseIsIdempotent(t *testing.T) {
+	bus := newBus(nil)
+	sub := bus.Subscribe(uuid.Nil)
+	sub.Close()
+	sub.Close()
+
+	_, ok := <-sub.C
+	assert.False(t, ok)
+	bus.dispatch(payload(t, db.RunEvent{Type: db.RunEventRunCompleted, RunID: uuid.New()}))
+}
+
+func TestBus_RunReconnects(t *testing.T) {
+	var attempts atomic.Int32
+	streams := make(chan chan string, 2)
+	bus := newBus(func(ctx context.Context) (<-chan string, func(), error) {
+		if attempts.Add(1) == 1 {
+			return nil, nil, errors.New("connection refused")
+		}
+		ch := make(chan string, 1)
+		streams <- ch
+		return ch, func() {}, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		bus.Run(ctx)
+		close(done)
+	}()
+
+	runID := uuid.New()
+	sub := bus.Subscribe(runID)
+	defer sub.Close()
+
+	var stream chan string
+	select {
+	case stream = <-streams:
+	case <-time.After(3 * reconnectDelay):
+		t.Fatal("bus did not reconnect")
+	}
+	stream <- payload(t, db.RunEvent{Type: db.RunEventRunCompleted, RunID: runID, Status: "completed"})
+	assert.True(t, receive(t, sub).Terminal())
+
+	cancel()
+	<-done
+}
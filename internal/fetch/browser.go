@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/chromedp/chromedp"
+	"github.com/jonathan/resume-customizer/internal/toolchain"
 )
 
 // MinContentLength is the minimum extracted text length to consider HTTP fetch successful.
@@ -23,8 +24,16 @@ func ShouldUseBrowser(extractedText string) bool {
 
 // WithBrowser renders a page in a headless browser and returns the rendered HTML.
 // This is useful for JavaScript-heavy pages that don't render content on initial load.
-// Requires Chrome/Chromium to be installed on the system.
+// Requires Chrome, Chromium, or Edge; without one the error wraps toolchain.ErrUnavailable.
 func WithBrowser(ctx context.Context, url string, timeout time.Duration, verbose bool) (string, error) {
+	tools, err := toolchain.Default()
+	if err != nil {
+		return "", err
+	}
+	if tools.Browser == "" {
+		return "", fmt.Errorf("browser rendering skipped: no Chrome, Chromium, or Edge found (set CHROME_PATH): %w", toolchain.ErrUnavailable)
+	}
+
 	if verbose {
 		log.Printf("[BROWSER] Starting headless browser for: %s", url)
 	}
@@ -32,6 +41,7 @@ func WithBrowser(ctx context.Context, url string, timeout time.Duration, verbose
 	// Create browser context with timeout
 	allocCtx, cancel := chromedp.NewExecAllocator(ctx,
 		append(chromedp.DefaultExecAllocatorOptions[:],
+			chromedp.ExecPath(tools.Browser),
 			chromedp.Flag("headless", true),
 			chromedp.Flag("disable-gpu", true),
 			chromedp.Flag("no-sandbox", true),
@@ -50,7 +60,7 @@ func WithBrowser(ctx context.Context, url string, timeout time.Duration, verbose
 	var html string
 
 	// Navigate, wait for page to be ready, then extract HTML
-	err := chromedp.Run(browserCtx,
+	err = chromedp.Run(browserCtx,
 		chromedp.Navigate(url),
 		// Wait for the page to load - use a combination of strategies
 		chromedp.WaitReady("body"),
@@ -75,6 +85,9 @@ func WithBrowser(ctx context.Context, url string, timeout time.Duration, verbose
 		log.Printf("[BROWSER] Rendered HTML: %d bytes", len(html))
 	}
 
+	// The DOM is already in memory here, but the cap still bounds what is parsed and stored
+	html, truncated := TruncateHTML(html, DefaultMaxBodyBytes)
+	recordPage(min(len(html), DefaultMaxBodyBytes), truncated)
 	return html, nil
 }
 
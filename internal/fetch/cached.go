@@ -15,7 +15,9 @@ type CachedFetcher struct {
 	db        *db.DB
 	options   *Options
 	cacheTTL  time.Duration
-	skipCache bool // For testing or forcing fresh fetches
+	skipCache bool          // For testing or forcing fresh fetches
+	policy    *DomainPolicy // Blocked domains are never fetched
+	archive   bool          // Fall back to Internet Archive snapshots for dead or blocked pages
 }
 
 // CachedFetcherConfig holds configuration for the cached fetcher.
@@ -23,6 +25,9 @@ type CachedFetcherConfig struct {
 	CacheTTL  time.Duration
 	SkipCache bool
 	Options   *Options
+	Policy    *DomainPolicy // Optional: Per-domain blocklist/allowlist
+	// ArchiveFallback serves dead (404) or bot-blocked pages from their latest Internet Archive snapshot
+	ArchiveFallback bool
 }
 
 // DefaultCachedFetcherConfig returns sensible defaults.
@@ -50,6 +55,8 @@ func NewCachedFetcher(database *db.DB, config *CachedFetcherConfig) *CachedFetch
 		options:   config.Options,
 		cacheTTL:  config.CacheTTL,
 		skipCache: config.SkipCache,
+		policy:    config.Policy,
+		archive:   config.ArchiveFallback,
 	}
 }
 
@@ -69,6 +76,15 @@ func (f *CachedFetcher) Fetch(ctx context.Context, urlStr string) (*CachedResult
 // FetchWithCompany retrieves a URL with optional company association.
 // This allows the cached page to be linked to a company for later retrieval.
 func (f *CachedFetcher) FetchWithCompany(ctx context.Context, urlStr string, companyID *uuid.UUID, pageType *string) (*CachedResult, error) {
+	// Step 0: Never fetch domains blocked by policy
+	if blocked, reason := f.policy.Blocked(urlStr); blocked {
+		return nil, &Error{
+			URL:       urlStr,
+			Message:   "URL skipped: " + reason,
+			Retryable: false,
+		}
+	}
+
 	// Step 1: Check if URL should be skipped (permanent failure or backoff)
 	if !f.skipCache && f.db != nil {
 		shouldSkip, reason, err := f.db.ShouldSkipURL(ctx, urlStr)
@@ -91,22 +107,33 @@ func (f *CachedFetcher) FetchWithCompany(ctx context.Context, urlStr string, com
 			return nil, fmt.Errorf("failed to check cache: %w", err)
 		}
 		if cached != nil {
-			// Return cached content
+			// Return cached content. Pages cached before the body size cap may exceed it.
+			html, truncated := TruncateHTML(derefString(cached.RawHTML), DefaultMaxBodyBytes)
+			result := &Result{
+				URL:        cached.URL,
+				HTML:       html,
+				Text:       derefString(cached.ParsedText),
+				StatusCode: derefInt(cached.HTTPStatus),
+				Truncated:  truncated,
+			}
+			if cached.SourceType == db.PageSourceArchive && cached.SnapshotURL != nil {
+				result.Archive = &Snapshot{URL: *cached.SnapshotURL}
+			}
 			return &CachedResult{
-				Result: &Result{
-					URL:        cached.URL,
-					HTML:       derefString(cached.RawHTML),
-					Text:       derefString(cached.ParsedText),
-					StatusCode: derefInt(cached.HTTPStatus),
-				},
+				Result:    result,
 				FromCache: true,
 				PageID:    cached.ID,
 			}, nil
 		}
 	}
 
-	// Step 3: Fetch fresh content
+	// Step 3: Fetch fresh content, falling back to an archived snapshot when enabled
 	result, err := URL(ctx, urlStr, f.options)
+	if f.archive && NeedsArchive(result, err) {
+		if archived, archiveErr := Archived(ctx, urlStr, f.options); archiveErr == nil {
+			result, err = archived, nil
+		}
+	}
 	if err != nil {
 		// Record failure in database
 		if f.db != nil {
@@ -124,17 +151,22 @@ func (f *CachedFetcher) FetchWithCompany(ctx context.Context, urlStr string, com
 	text, _ := ExtractMainText(result.HTML, DefaultTextSelectors())
 	result.Text = text
 
-	// Step 5: Store in cache
+	// Step 5: Store in cache, without the scripts and styles nothing reads back
 	if f.db != nil {
+		storedHTML := SanitizeHTML(result.HTML)
 		page := &db.CrawledPage{
 			CompanyID:   companyID,
 			URL:         urlStr,
 			PageType:    pageType,
-			RawHTML:     &result.HTML,
+			RawHTML:     &storedHTML,
 			ParsedText:  &result.Text,
 			HTTPStatus:  &result.StatusCode,
 			FetchStatus: db.FetchStatusSuccess,
 		}
+		if result.Archive != nil {
+			page.SourceType = db.PageSourceArchive
+			page.SnapshotURL = &result.Archive.URL
+		}
 		if err := f.db.UpsertCrawledPage(ctx, page); err != nil {
 			// Log but don't fail - the fetch succeeded
 			// In production, this should log the error
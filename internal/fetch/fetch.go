@@ -5,7 +5,7 @@ package fetch
 import (
 	"context"
 	"fmt"
-	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
@@ -36,14 +36,17 @@ type Result struct {
 	Text        string
 	ContentType string
 	StatusCode  int
+	Archive     *Snapshot // Set when the content came from an Internet Archive snapshot
+	Truncated   bool      // HTML was cut off at the body size cap and ends with a marker
 }
 
 // Error represents an error during URL fetching.
 type Error struct {
-	URL       string
-	Message   string
-	Cause     error
-	Retryable bool // Whether this error is retryable
+	URL        string
+	Message    string
+	Cause      error
+	Retryable  bool // Whether this error is retryable
+	StatusCode int  // HTTP status, when the server responded
 }
 
 func (e *Error) Error() string {
@@ -65,6 +68,7 @@ type Options struct {
 	MaxRetries     int           // Maximum number of retry attempts (0 = no retries)
 	InitialBackoff time.Duration // Initial backoff duration
 	MaxBackoff     time.Duration // Maximum backoff duration
+	MaxBodyBytes   int64         // Response bytes read before the rest is dropped (0 = DefaultMaxBodyBytes)
 }
 
 // DefaultOptions returns sensible defaults for fetching.
@@ -75,6 +79,7 @@ func DefaultOptions() *Options {
 		MaxRetries:     DefaultMaxRetries,
 		InitialBackoff: DefaultInitialBackoff,
 		MaxBackoff:     DefaultMaxBackoff,
+		MaxBodyBytes:   DefaultMaxBodyBytes,
 	}
 }
 
@@ -221,8 +226,12 @@ func fetchOnce(ctx context.Context, urlStr string, opts *Options) (*Result, erro
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	// Read response body
-	bodyBytes, err := io.ReadAll(resp.Body)
+	// Read the response body up to the cap; the rest is never buffered
+	maxBody := opts.MaxBodyBytes
+	if maxBody <= 0 {
+		maxBody = DefaultMaxBodyBytes
+	}
+	bodyBytes, truncated, err := readCapped(resp.Body, maxBody)
 	if err != nil {
 		return nil, &Error{
 			URL:       urlStr,
@@ -231,28 +240,37 @@ func fetchOnce(ctx context.Context, urlStr string, opts *Options) (*Result, erro
 			Retryable: true, // Read errors can be transient
 		}
 	}
+	recordPage(len(bodyBytes), truncated)
+
+	html := string(bodyBytes)
+	if truncated {
+		html = cutUTF8(html, len(html)) + fmt.Sprintf(htmlTruncationMarker, maxBody)
+		slog.WarnContext(ctx, "Fetched page truncated", "url", urlStr, "max_bytes", maxBody)
+	}
 
 	result := &Result{
 		URL:         urlStr,
-		HTML:        string(bodyBytes),
+		HTML:        html,
 		ContentType: resp.Header.Get("Content-Type"),
 		StatusCode:  resp.StatusCode,
+		Truncated:   truncated,
 	}
 
 	// Check for non-success status
 	if resp.StatusCode != http.StatusOK {
 		retryable := isRetryableStatusCode(resp.StatusCode)
 		return result, &Error{
-			URL:       urlStr,
-			Message:   fmt.Sprintf("HTTP status %d", resp.StatusCode),
-			Retryable: retryable,
+			URL:        urlStr,
+			Message:    fmt.Sprintf("HTTP status %d", resp.StatusCode),
+			Retryable:  retryable,
+			StatusCode: resp.StatusCode,
 		}
 	}
 
 	return result, nil
 }
 
-// ExtractMainText parses HTML and returns the main body text.
+// ExtractMainText parses HTML and returns the main body text, at most MaxTextBytes of it.
 // It removes noise elements using noiseSelectors, then finds content using contentSelectors.
 // If no content selectors match, it falls back to the body element.
 func ExtractMainText(html string, contentSelectors []string, noiseSelectors ...string) (string, error) {
@@ -290,6 +308,10 @@ func ExtractMainText(html string, contentSelectors []string, noiseSelectors ...s
 	text := mainContent.Text()
 	text = cleanWhitespace(text)
 
+	text, capped := TruncateText(text, MaxTextBytes)
+	if capped {
+		recordTextCapped()
+	}
 	return text, nil
 }
 
@@ -0,0 +1,120 @@
+package fetch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"unicode/utf8"
+)
+
+// Size caps keep one huge page from exhausting a worker's memory: bodies are read
+// through a limit rather than whole, and text extracted from them is capped again.
+const (
+	// DefaultMaxBodyBytes is how much of a response body a fetch reads
+	DefaultMaxBodyBytes = 5 << 20
+	// MaxTextBytes is how much text ExtractMainText returns from one page
+	MaxTextBytes = 512 << 10
+)
+
+// htmlTruncationMarker ends HTML cut off at a size cap, so stored pages say they are partial
+const htmlTruncationMarker = "\n<!-- truncated at %d bytes -->\n"
+
+// textTruncationMarker ends text cut off at a size cap
+const textTruncationMarker = "\n[truncated at %d bytes]"
+
+// readCapped reads at most limit bytes of r, reporting whether more remained. Only
+// limit+1 bytes are ever buffered, however long r is.
+func readCapped(r io.Reader, limit int64) ([]byte, bool, error) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if n <= limit {
+		return buf.Bytes(), false, nil
+	}
+	return buf.Bytes()[:limit], true, nil
+}
+
+// TruncateHTML cuts html to at most limit bytes, not counting the marker it then
+// appends, and reports whether it was cut. Cuts fall on a UTF-8 boundary.
+func TruncateHTML(html string, limit int) (string, bool) {
+	if len(html) <= limit {
+		return html, false
+	}
+	return cutUTF8(html, limit) + fmt.Sprintf(htmlTruncationMarker, limit), true
+}
+
+// TruncateText cuts text to at most limit bytes, not counting the marker it then
+// appends, and reports whether it was cut. Cuts fall on a UTF-8 boundary.
+func TruncateText(text string, limit int) (string, bool) {
+	if len(text) <= limit {
+		return text, false
+	}
+	return cutUTF8(text, limit) + fmt.Sprintf(textTruncationMarker, limit), true
+}
+
+// cutUTF8 returns the longest prefix of s of at most limit bytes that does not end
+// inside a multi-byte character
+func cutUTF8(s string, limit int) string {
+	s = s[:limit]
+	for i := len(s) - 1; i >= 0 && i >= len(s)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(s[i]) {
+			if !utf8.FullRuneInString(s[i:]) {
+				return s[:i]
+			}
+			break
+		}
+	}
+	return s
+}
+
+// SizeStats counts the pages fetched since the process started and how many were cut
+// off at a size cap
+type SizeStats struct {
+	Pages        int64 `json:"pages"`
+	Bytes        int64 `json:"bytes"`         // Bytes kept across all pages
+	Truncated    int64 `json:"truncated"`     // Pages cut off at DefaultMaxBodyBytes or Options.MaxBodyBytes
+	LargestPage  int64 `json:"largest_page"`  // Bytes kept from the largest page
+	TextCapped   int64 `json:"text_capped"`   // Pages whose extracted text was cut at MaxTextBytes
+	CorpusCapped int64 `json:"corpus_capped"` // Corpora cut off at their cap
+}
+
+var (
+	sizeStatsMu sync.Mutex
+	sizeStats   SizeStats
+)
+
+// Stats returns the page size counters
+func Stats() SizeStats {
+	sizeStatsMu.Lock()
+	defer sizeStatsMu.Unlock()
+	return sizeStats
+}
+
+// recordPage counts a fetched page of n bytes
+func recordPage(n int, truncated bool) {
+	sizeStatsMu.Lock()
+	defer sizeStatsMu.Unlock()
+	sizeStats.Pages++
+	sizeStats.Bytes += int64(n)
+	sizeStats.LargestPage = max(sizeStats.LargestPage, int64(n))
+	if truncated {
+		sizeStats.Truncated++
+	}
+}
+
+// recordTextCapped counts a page whose extracted text was cut off
+func recordTextCapped() {
+	sizeStatsMu.Lock()
+	defer sizeStatsMu.Unlock()
+	sizeStats.TextCapped++
+}
+
+// RecordCorpusCapped counts a corpus cut off at its cap
+func RecordCorpusCapped() {
+	sizeStatsMu.Lock()
+	defer sizeStatsMu.Unlock()
+	sizeStats.CorpusCapped++
+}
@@ -0,0 +1,75 @@
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCapped(t *testing.T) {
+	data, truncated, err := readCapped(strings.NewReader("abcdef"), 6)
+	require.NoError(t, err)
+	assert.False(t, truncated, "a body exactly at the cap is whole")
+	assert.Equal(t, "abcdef", string(data))
+
+	data, truncated, err = readCapped(strings.NewReader("abcdefg"), 6)
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Equal(t, "abcdef", string(data))
+}
+
+func TestTruncateText(t *testing.T) {
+	text, truncated := TruncateText("short", 10)
+	assert.False(t, truncated)
+	assert.Equal(t, "short", text)
+
+	// "é" is two bytes, so a cut after 4 bytes would split the third one
+	text, truncated = TruncateText("ééééé", 5)
+	assert.True(t, truncated)
+	assert.Equal(t, "éé\n[truncated at 5 bytes]", text)
+	assert.True(t, utf8.ValidString(text))
+}
+
+func TestTruncateHTML(t *testing.T) {
+	html, truncated := TruncateHTML("<p>hello world</p>", 8)
+	assert.True(t, truncated)
+	assert.Equal(t, "<p>hello\n<!-- truncated at 8 bytes -->\n", html)
+}
+
+func TestURL_TruncatesLargeBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html><body>" + strings.Repeat("x", 1000) + "</body></html>"))
+	}))
+	defer server.Close()
+
+	before := Stats()
+	opts := DefaultOptions()
+	opts.MaxBodyBytes = 100
+	result, err := URL(context.Background(), server.URL, opts)
+	require.NoError(t, err)
+	assert.True(t, result.Truncated)
+	assert.True(t, strings.HasPrefix(result.HTML, "<html><body>xxx"))
+	assert.True(t, strings.HasSuffix(result.HTML, "<!-- truncated at 100 bytes -->\n"))
+	assert.Len(t, result.HTML, 100+len("\n<!-- truncated at 100 bytes -->\n"))
+
+	after := Stats()
+	assert.Equal(t, before.Pages+1, after.Pages)
+	assert.Equal(t, before.Truncated+1, after.Truncated)
+	assert.Equal(t, before.Bytes+100, after.Bytes)
+}
+
+func TestExtractMainText_CapsText(t *testing.T) {
+	before := Stats()
+	text, err := ExtractMainText("<html><body><main>"+strings.Repeat("word ", MaxTextBytes)+"</main></body></html>", DefaultTextSelectors())
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(text), MaxTextBytes+len("\n[truncated at 524288 bytes]"))
+	assert.True(t, strings.HasSuffix(text, "[truncated at 524288 bytes]"))
+	assert.Equal(t, before.TextCapped+1, Stats().TextCapped)
+}
@@ -0,0 +1,85 @@
+// Package fetch - policy.go applies per-domain crawl policies (blocklist/allowlist).
+package fetch
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// DomainRule is a single crawl rule for a domain and its subdomains
+type DomainRule struct {
+	Domain     string `json:"domain"`
+	Action     string `json:"action"` // db.DomainPolicyBlock or db.DomainPolicyAllow
+	Reason     string `json:"reason,omitempty"`
+	UserScoped bool   `json:"user_scoped,omitempty"` // Set by a user rather than an admin
+}
+
+// DomainPolicy is the set of domain rules that apply to one user's crawls.
+// A nil *DomainPolicy allows everything and prefers nothing.
+type DomainPolicy struct {
+	Rules []DomainRule `json:"rules"`
+}
+
+// NewDomainPolicy builds a policy from global and user policy rows
+func NewDomainPolicy(policies []db.DomainPolicy) *DomainPolicy {
+	p := &DomainPolicy{Rules: make([]DomainRule, 0, len(policies))}
+	for _, row := range policies {
+		p.Rules = append(p.Rules, DomainRule{
+			Domain:     row.Domain,
+			Action:     row.Action,
+			Reason:     derefString(row.Reason),
+			UserScoped: row.UserID != nil,
+		})
+	}
+	return p
+}
+
+// Match returns the rule that governs urlStr, or nil. The most specific domain
+// wins, and a user's rule wins over a global rule for the same domain.
+func (p *DomainPolicy) Match(urlStr string) *DomainRule {
+	if p == nil || len(p.Rules) == 0 {
+		return nil
+	}
+	parsed, err := url.Parse(urlStr)
+	if err != nil {
+		return nil
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	if host == "" {
+		return nil
+	}
+
+	var best *DomainRule
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if host != rule.Domain && !strings.HasSuffix(host, "."+rule.Domain) {
+			continue
+		}
+		if best == nil || len(rule.Domain) > len(best.Domain) ||
+			(len(rule.Domain) == len(best.Domain) && rule.UserScoped && !best.UserScoped) {
+			best = rule
+		}
+	}
+	return best
+}
+
+// Blocked reports whether urlStr must never be crawled, with the rule's reason
+func (p *DomainPolicy) Blocked(urlStr string) (bool, string) {
+	rule := p.Match(urlStr)
+	if rule == nil || rule.Action != db.DomainPolicyBlock {
+		return false, ""
+	}
+	reason := "blocked domain " + rule.Domain
+	if rule.Reason != "" {
+		reason += " (" + rule.Reason + ")"
+	}
+	return true, reason
+}
+
+// Preferred reports whether urlStr is on an allowlisted domain
+func (p *DomainPolicy) Preferred(urlStr string) bool {
+	rule := p.Match(urlStr)
+	return rule != nil && rule.Action == db.DomainPolicyAllow
+}
@@ -0,0 +1,67 @@
+package fetch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDomainPolicy(t *testing.T) {
+	userID := uuid.New()
+	p := NewDomainPolicy([]db.DomainPolicy{
+		{Domain: "glassdoor.com", Action: db.DomainPolicyBlock, Reason: strPtr("aggregator")},
+		{Domain: "acme.dev", Action: db.DomainPolicyAllow, UserID: &userID},
+	})
+
+	assert.Equal(t, []DomainRule{
+		{Domain: "glassdoor.com", Action: db.DomainPolicyBlock, Reason: "aggregator"},
+		{Domain: "acme.dev", Action: db.DomainPolicyAllow, UserScoped: true},
+	}, p.Rules)
+}
+
+func TestDomainPolicy_Match(t *testing.T) {
+	p := &DomainPolicy{Rules: []DomainRule{
+		{Domain: "glassdoor.com", Action: db.DomainPolicyBlock, Reason: "aggregator"},
+		{Domain: "acme.com", Action: db.DomainPolicyAllow},
+		{Domain: "community.acme.com", Action: db.DomainPolicyBlock},
+		{Domain: "medium.com", Action: db.DomainPolicyBlock},
+		{Domain: "medium.com", Action: db.DomainPolicyAllow, UserScoped: true},
+	}}
+
+	blocked, reason := p.Blocked("https://www.glassdoor.com/Reviews/acme")
+	assert.True(t, blocked)
+	assert.Equal(t, "blocked domain glassdoor.com (aggregator)", reason)
+
+	assert.True(t, p.Preferred("https://blog.acme.com/culture"))
+	blocked, _ = p.Blocked("https://community.acme.com/t/123")
+	assert.True(t, blocked, "the most specific domain wins")
+
+	assert.True(t, p.Preferred("https://medium.com/acme-eng"), "a user's rule overrides a global rule")
+
+	assert.Nil(t, p.Match("https://notacme.com/values"), "suffix matches require a dot boundary")
+	assert.Nil(t, p.Match("not a url"))
+}
+
+func TestDomainPolicy_Nil(t *testing.T) {
+	var p *DomainPolicy
+	blocked, _ := p.Blocked("https://glassdoor.com")
+	assert.False(t, blocked)
+	assert.False(t, p.Preferred("https://acme.com"))
+}
+
+func TestCachedFetcher_BlockedDomain(t *testing.T) {
+	f := NewCachedFetcher(nil, &CachedFetcherConfig{
+		Policy: &DomainPolicy{Rules: []DomainRule{{Domain: "glassdoor.com", Action: db.DomainPolicyBlock}}},
+	})
+
+	_, err := f.Fetch(context.Background(), "https://www.glassdoor.com/Reviews/acme")
+	require.Error(t, err)
+	var fetchErr *Error
+	require.ErrorAs(t, err, &fetchErr)
+	assert.False(t, fetchErr.Retryable)
+	assert.Contains(t, fetchErr.Message, "blocked domain glassdoor.com")
+}
@@ -0,0 +1,124 @@
+package fetch
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// unsafeElements are dropped with everything inside them when HTML is sanitized: they
+// run code, embed other documents, restyle the page, or redirect it
+var unsafeElements = map[string]bool{
+	"script": true, "style": true, "iframe": true, "frame": true, "frameset": true,
+	"object": true, "embed": true, "applet": true, "base": true, "link": true,
+	"template": true, "portal": true,
+}
+
+// urlAttributes hold URLs, which are dropped when they use a scheme that runs code
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true, "poster": true,
+	"data": true, "background": true, "xlink:href": true, "cite": true, "srcset": true,
+}
+
+// SanitizeHTML returns html without scripts, styles, frames, plugins, event handler and
+// style attributes, or javascript:, vbscript:, and non-image data: URLs, so a stored
+// page is safe to hand to a frontend. Text, links, and comments such as the truncation
+// marker are kept.
+func SanitizeHTML(htmlContent string) string {
+	z := html.NewTokenizer(strings.NewReader(htmlContent))
+	var sb strings.Builder
+	sb.Grow(len(htmlContent))
+	skip := "" // Unsafe element being dropped, until its end tag
+	depth := 0
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return sb.String() // End of input, or a remainder the tokenizer cannot read
+		}
+		tok := z.Token()
+
+		if skip != "" {
+			switch {
+			case tt == html.StartTagToken && tok.Data == skip:
+				depth++
+			case tt == html.EndTagToken && tok.Data == skip:
+				if depth--; depth == 0 {
+					skip = ""
+				}
+			}
+			continue
+		}
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if unsafeElements[tok.Data] || isRefreshMeta(tok) {
+				if tt == html.StartTagToken && !isVoidElement(tok.Data) {
+					skip, depth = tok.Data, 1
+				}
+				continue
+			}
+			tok.Attr = safeAttributes(tok)
+		case html.EndTagToken:
+			if unsafeElements[tok.Data] {
+				continue
+			}
+		}
+		sb.WriteString(tok.String())
+	}
+}
+
+// safeAttributes returns the attributes of tok that cannot run code or restyle the page
+func safeAttributes(tok html.Token) []html.Attribute {
+	attrs := tok.Attr[:0]
+	for _, a := range tok.Attr {
+		key := strings.ToLower(a.Key)
+		if strings.HasPrefix(key, "on") || key == "style" || key == "srcdoc" ||
+			(urlAttributes[key] && hasUnsafeURL(a.Val, tok.Data)) {
+			continue
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+// hasUnsafeURL reports whether an attribute value is a URL with a scheme that runs code.
+// Images may use data: URLs of image types.
+func hasUnsafeURL(val, element string) bool {
+	// Browsers ignore whitespace and control characters inside the scheme
+	scheme := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(val))
+	switch {
+	case strings.HasPrefix(scheme, "javascript:"), strings.HasPrefix(scheme, "vbscript:"):
+		return true
+	case strings.HasPrefix(scheme, "data:"):
+		return element != "img" || !strings.HasPrefix(scheme, "data:image/") || strings.HasPrefix(scheme, "data:image/svg")
+	}
+	return false
+}
+
+// isRefreshMeta reports whether tok is a <meta http-equiv> tag, which can redirect the page
+func isRefreshMeta(tok html.Token) bool {
+	if tok.Data != "meta" {
+		return false
+	}
+	for _, a := range tok.Attr {
+		if strings.EqualFold(a.Key, "http-equiv") {
+			return true
+		}
+	}
+	return false
+}
+
+// isVoidElement reports whether an element never has an end tag
+func isVoidElement(name string) bool {
+	switch name {
+	case "area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr":
+		return true
+	}
+	return false
+}
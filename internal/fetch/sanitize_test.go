@@ -0,0 +1,34 @@
+package fetch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	page := `<!DOCTYPE html><html><head><title>Careers</title>
+<meta http-equiv="refresh" content="0;url=https://evil.example"><base href="https://evil.example/">
+<link rel="stylesheet" href="/site.css"><style>body { display: none }</style>
+<script>alert(1)</script><script src="/app.js"></script></head>
+<body onload="steal()"><h1 style="color:red">We value <b>ownership</b></h1>
+<iframe src="https://ads.example"><p>fallback</p></iframe>
+<object data="x.swf"><object data="y.swf"></object><p>inner</p></object>
+<a href="javascript:alert(1)">bad</a> <a href=" JaVa&#x09;script:alert(1)">bad too</a> <a href="/jobs">Open roles</a>
+<img src="data:image/png;base64,AAAA" onerror="steal()"> <img src="data:text/html,<script>alert(1)</script>">
+<svg><a xlink:href="javascript:alert(1)">x</a></svg>
+<!-- truncated at 5242880 bytes --></body></html>`
+
+	out := SanitizeHTML(page)
+	for _, unsafe := range []string{"<script", "alert", "<style", "display: none", "<iframe", "fallback", "<object", "inner",
+		"onload", "onerror", "style=", "javascript", "http-equiv", "<base", "<link", "data:text/html"} {
+		assert.NotContains(t, out, unsafe)
+	}
+	for _, kept := range []string{"<title>Careers</title>", "<h1>We value <b>ownership</b></h1>", `<a href="/jobs">Open roles</a>`,
+		`<img src="data:image/png;base64,AAAA">`, "<!-- truncated at 5242880 bytes -->", "<!DOCTYPE html>"} {
+		assert.Contains(t, out, kept)
+	}
+
+	assert.Equal(t, "plain text &amp; more", SanitizeHTML("plain text &amp; more"))
+	assert.Empty(t, SanitizeHTML(""))
+}
@@ -0,0 +1,137 @@
+// Package fetch - wayback.go falls back to Internet Archive snapshots for dead or blocked pages.
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WaybackAvailabilityURL is the Internet Archive endpoint that returns a URL's closest snapshot
+var WaybackAvailabilityURL = "https://archive.org/wayback/available"
+
+// waybackTimestampLayout is the format of Wayback Machine snapshot timestamps
+const waybackTimestampLayout = "20060102150405"
+
+// Snapshot identifies an Internet Archive capture of a page
+type Snapshot struct {
+	URL       string    `json:"url"` // Snapshot URL on web.archive.org
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// botChallengeMarkers are strings found on bot-protection interstitials served with a 200 status
+var botChallengeMarkers = []string{
+	"<title>Just a moment...</title>",
+	"Attention Required! | Cloudflare",
+	"/cdn-cgi/challenge-platform/",
+	"cf-browser-verification",
+	"_Incapsula_Resource",
+	"px-captcha",
+	"Please verify you are a human",
+}
+
+// IsBotChallenge reports whether html is a bot-protection page rather than the requested content
+func IsBotChallenge(html string) bool {
+	for _, marker := range botChallengeMarkers {
+		if strings.Contains(html, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorStatusCode returns the HTTP status carried by a fetch error, or 0
+func ErrorStatusCode(err error) int {
+	for err != nil {
+		var fetchErr *Error
+		if !errors.As(err, &fetchErr) {
+			return 0
+		}
+		if fetchErr.StatusCode != 0 {
+			return fetchErr.StatusCode
+		}
+		err = fetchErr.Cause
+	}
+	return 0
+}
+
+// NeedsArchive reports whether a fetch outcome means the page is dead (404, 410, 451)
+// or bot-blocked (403, 429, 503, or a challenge page), so an archived copy should be tried
+func NeedsArchive(result *Result, err error) bool {
+	if err == nil {
+		return result != nil && IsBotChallenge(result.HTML)
+	}
+	switch ErrorStatusCode(err) {
+	case http.StatusForbidden, http.StatusNotFound, http.StatusGone, http.StatusTooManyRequests,
+		http.StatusUnavailableForLegalReasons, http.StatusServiceUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// availabilityResponse is the Wayback availability API response
+type availabilityResponse struct {
+	ArchivedSnapshots struct {
+		Closest *struct {
+			Available bool   `json:"available"`
+			URL       string `json:"url"`
+			Timestamp string `json:"timestamp"`
+			Status    string `json:"status"`
+		} `json:"closest"`
+	} `json:"archived_snapshots"`
+}
+
+// LatestSnapshot returns the most recent successful Internet Archive capture of urlStr
+func LatestSnapshot(ctx context.Context, urlStr string, opts *Options) (*Snapshot, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	lookup := WaybackAvailabilityURL + "?url=" + url.QueryEscape(urlStr)
+	result, err := URL(ctx, lookup, opts)
+	if err != nil {
+		return nil, fmt.Errorf("wayback lookup failed: %w", err)
+	}
+
+	var resp availabilityResponse
+	if err := json.Unmarshal([]byte(result.HTML), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse wayback response: %w", err)
+	}
+	closest := resp.ArchivedSnapshots.Closest
+	if closest == nil || !closest.Available || closest.URL == "" || (closest.Status != "" && closest.Status != "200") {
+		return nil, &Error{URL: urlStr, Message: "no archived snapshot available"}
+	}
+
+	timestamp, err := time.Parse(waybackTimestampLayout, closest.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wayback timestamp %q: %w", closest.Timestamp, err)
+	}
+	return &Snapshot{URL: strings.Replace(closest.URL, "http://web.archive.org/", "https://web.archive.org/", 1), Timestamp: timestamp}, nil
+}
+
+// rawSnapshotURL returns the snapshot URL that serves the original page without the Wayback toolbar
+func rawSnapshotURL(s *Snapshot) string {
+	ts := s.Timestamp.Format(waybackTimestampLayout)
+	return strings.Replace(s.URL, "/web/"+ts+"/", "/web/"+ts+"id_/", 1)
+}
+
+// Archived fetches the most recent Internet Archive snapshot of urlStr.
+// The result's URL is the original URL; Archive records the snapshot used.
+func Archived(ctx context.Context, urlStr string, opts *Options) (*Result, error) {
+	snapshot, err := LatestSnapshot(ctx, urlStr, opts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := URL(ctx, rawSnapshotURL(snapshot), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch archived snapshot: %w", err)
+	}
+	result.URL = urlStr
+	result.Archive = snapshot
+	return result, nil
+}
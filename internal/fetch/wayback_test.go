@@ -0,0 +1,74 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withWayback points the availability API at a test server for the duration of a test
+func withWayback(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	original := WaybackAvailabilityURL
+	WaybackAvailabilityURL = server.URL + "/wayback/available"
+	t.Cleanup(func() { WaybackAvailabilityURL = original })
+	return server
+}
+
+func TestArchived_FetchesRawSnapshot(t *testing.T) {
+	var server *httptest.Server
+	server = withWayback(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wayback/available":
+			assert.Equal(t, "https://acme.com/values", r.URL.Query().Get("url"))
+			_, _ = fmt.Fprintf(w, `{"archived_snapshots":{"closest":{"available":true,"status":"200","timestamp":"20250102030405","url":"%s/web/20250102030405/https://acme.com/values"}}}`, server.URL)
+		case "/web/20250102030405id_/https://acme.com/values":
+			_, _ = w.Write([]byte("<html><body><h1>Our values</h1></body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	result, err := Archived(context.Background(), "https://acme.com/values", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://acme.com/values", result.URL)
+	assert.Contains(t, result.HTML, "Our values")
+	require.NotNil(t, result.Archive)
+	assert.Equal(t, server.URL+"/web/20250102030405/https://acme.com/values", result.Archive.URL)
+	assert.Equal(t, 2025, result.Archive.Timestamp.Year())
+}
+
+func TestLatestSnapshot_NoneAvailable(t *testing.T) {
+	withWayback(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"archived_snapshots":{}}`))
+	})
+
+	_, err := LatestSnapshot(context.Background(), "https://acme.com/gone", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no archived snapshot")
+}
+
+func TestNeedsArchive(t *testing.T) {
+	assert.True(t, NeedsArchive(nil, &Error{Message: "HTTP status 404", StatusCode: http.StatusNotFound}))
+	assert.True(t, NeedsArchive(nil, fmt.Errorf("wrapped: %w", &Error{StatusCode: http.StatusForbidden})))
+	assert.False(t, NeedsArchive(nil, &Error{Message: "timeout", Retryable: true}))
+	assert.False(t, NeedsArchive(&Result{HTML: "<html><body>Hello</body></html>"}, nil))
+	assert.True(t, NeedsArchive(&Result{HTML: "<html><head><title>Just a moment...</title></head></html>"}, nil))
+}
+
+func TestURL_ErrorCarriesStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	_, err := URL(context.Background(), server.URL, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusGone, ErrorStatusCode(err))
+}
@@ -0,0 +1,55 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DraftBullet writes a first-draft project bullet for repo. Drafts only restate what
+// the repository itself claims, so the user must review them before they are banked.
+func DraftBullet(repo Repo) string {
+	var b strings.Builder
+	b.WriteString("Built ")
+	b.WriteString(repo.Name)
+	if repo.Language != "" {
+		b.WriteString(", a " + repo.Language + " project")
+	}
+	if desc := strings.TrimRight(strings.TrimSpace(repo.Description), "."); desc != "" {
+		b.WriteString(" (" + lowerFirst(desc) + ")")
+	}
+	if repo.Stars > 0 {
+		fmt.Fprintf(&b, ", earning %d GitHub %s", repo.Stars, plural(repo.Stars, "star", "stars"))
+	}
+	b.WriteString(".")
+	return b.String()
+}
+
+// Skills returns the skills a repository demonstrates: its primary language and topics
+func Skills(repo Repo) []string {
+	var skills []string
+	seen := make(map[string]bool)
+	for _, s := range append([]string{repo.Language}, repo.Topics...) {
+		key := strings.ToLower(s)
+		if s == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		skills = append(skills, s)
+	}
+	return skills
+}
+
+// lowerFirst lowercases a leading capital unless the first word is an acronym
+func lowerFirst(s string) string {
+	if len(s) > 1 && s[0] >= 'A' && s[0] <= 'Z' && !(s[1] >= 'A' && s[1] <= 'Z') {
+		return strings.ToLower(s[:1]) + s[1:]
+	}
+	return s
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,272 @@
+// Package github reads a user's showcased repositories from the GitHub API and drafts
+// project bullets from them for the experience bank.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DefaultAPIURL is the public GitHub REST and GraphQL API
+const DefaultAPIURL = "https://api.github.com"
+
+// maxRepos is how many repositories are drafted per sync; GitHub profiles pin at most six
+const maxRepos = 6
+
+// usernamePattern matches valid GitHub logins
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9]|-[A-Za-z0-9]){0,38}$`)
+
+// ValidUsername reports whether name is a syntactically valid GitHub login
+func ValidUsername(name string) bool {
+	return usernamePattern.MatchString(name)
+}
+
+// ErrUserNotFound is returned when the GitHub user does not exist
+var ErrUserNotFound = errors.New("github user not found")
+
+// RateLimitError is returned when GitHub refuses a request for exceeding its rate limit
+type RateLimitError struct {
+	Reset time.Time // When requests will be accepted again
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("github rate limit exceeded until %s", e.Reset.UTC().Format(time.RFC3339))
+}
+
+// Repo is a repository showcased on a GitHub profile
+type Repo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url"`
+	Language    string   `json:"language,omitempty"`
+	Stars       int      `json:"stars"`
+	Topics      []string `json:"topics,omitempty"`
+}
+
+// Client calls the GitHub API. Without a token only public data is read, under
+// GitHub's lower unauthenticated rate limit.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewClient returns a client for the API at baseURL (DefaultAPIURL when empty)
+// authenticating with token, which may be empty
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIURL
+	}
+	return &Client{
+		baseURL: baseURL,
+		token:   token,
+		http:    &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// ShowcasedRepos returns the repositories pinned on username's profile. Pinned items are
+// only exposed through the GraphQL API, which requires a token; without one, or when
+// nothing is pinned, the user's most-starred original repositories are used instead.
+func (c *Client) ShowcasedRepos(ctx context.Context, username string) ([]Repo, error) {
+	if c.token != "" {
+		repos, err := c.pinnedRepos(ctx, username)
+		if err != nil || len(repos) > 0 {
+			return repos, err
+		}
+	}
+	return c.topRepos(ctx, username)
+}
+
+const pinnedQuery = `query($login: String!) {
+  user(login: $login) {
+    pinnedItems(first: 6, types: REPOSITORY) {
+      nodes {
+        ... on Repository {
+          name
+          description
+          url
+          stargazerCount
+          primaryLanguage { name }
+          repositoryTopics(first: 10) { nodes { topic { name } } }
+        }
+      }
+    }
+  }
+}`
+
+// pinnedRepos reads the profile's pinned repositories through GraphQL
+func (c *Client) pinnedRepos(ctx context.Context, username string) ([]Repo, error) {
+	body, err := json.Marshal(map[string]any{
+		"query":     pinnedQuery,
+		"variables": map[string]string{"login": username},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			User *struct {
+				PinnedItems struct {
+					Nodes []struct {
+						Name            string `json:"name"`
+						Description     string `json:"description"`
+						URL             string `json:"url"`
+						StargazerCount  int    `json:"stargazerCount"`
+						PrimaryLanguage *struct {
+							Name string `json:"name"`
+						} `json:"primaryLanguage"`
+						RepositoryTopics struct {
+							Nodes []struct {
+								Topic struct {
+									Name string `json:"name"`
+								} `json:"topic"`
+							} `json:"nodes"`
+						} `json:"repositoryTopics"`
+					} `json:"nodes"`
+				} `json:"pinnedItems"`
+			} `json:"user"`
+		} `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/graphql", body, &resp); err != nil {
+		return nil, err
+	}
+	for _, e := range resp.Errors {
+		if e.Type == "NOT_FOUND" {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("github graphql error: %s", e.Message)
+	}
+	if resp.Data.User == nil {
+		return nil, ErrUserNotFound
+	}
+
+	var repos []Repo
+	for _, n := range resp.Data.User.PinnedItems.Nodes {
+		if n.Name == "" {
+			continue // pinned gist or other non-repository item
+		}
+		repo := Repo{Name: n.Name, Description: n.Description, URL: n.URL, Stars: n.StargazerCount}
+		if n.PrimaryLanguage != nil {
+			repo.Language = n.PrimaryLanguage.Name
+		}
+		for _, t := range n.RepositoryTopics.Nodes {
+			repo.Topics = append(repo.Topics, t.Topic.Name)
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// topRepos returns the user's most-starred public repositories that are not forks or archived
+func (c *Client) topRepos(ctx context.Context, username string) ([]Repo, error) {
+	var resp []struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		HTMLURL     string   `json:"html_url"`
+		Language    string   `json:"language"`
+		Stars       int      `json:"stargazers_count"`
+		Topics      []string `json:"topics"`
+		Fork        bool     `json:"fork"`
+		Archived    bool     `json:"archived"`
+	}
+	path := "/users/" + url.PathEscape(username) + "/repos?type=owner&sort=pushed&per_page=100"
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	var repos []Repo
+	for _, r := range resp {
+		if r.Fork || r.Archived {
+			continue
+		}
+		repos = append(repos, Repo{
+			Name:        r.Name,
+			Description: r.Description,
+			URL:         r.HTMLURL,
+			Language:    r.Language,
+			Stars:       r.Stars,
+			Topics:      r.Topics,
+		})
+	}
+	sort.SliceStable(repos, func(i, j int) bool { return repos[i].Stars > repos[j].Stars })
+	if len(repos) > maxRepos {
+		repos = repos[:maxRepos]
+	}
+	return repos, nil
+}
+
+// do sends one API request and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, body []byte, out any) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create github request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("github request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := rateLimited(resp); err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrUserNotFound
+	case resp.StatusCode == http.StatusUnauthorized:
+		return errors.New("github rejected the access token")
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("github returned %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode github response: %w", err)
+	}
+	return nil
+}
+
+// rateLimited returns a RateLimitError when resp is GitHub refusing the request for its
+// primary (X-RateLimit-*) or secondary (Retry-After) rate limit
+func rateLimited(resp *http.Response) error {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return &RateLimitError{Reset: time.Now().Add(time.Duration(secs) * time.Second)}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset := time.Now().Add(time.Minute)
+		if epoch, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			reset = time.Unix(epoch, 0)
+		}
+		return &RateLimitError{Reset: reset}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{Reset: time.Now().Add(time.Minute)}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowcasedRepos_Pinned(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var body struct {
+			Variables map[string]string `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "octocat", body.Variables["login"])
+		_, _ = w.Write([]byte(`{"data":{"user":{"pinnedItems":{"nodes":[
+			{"name":"linguist","description":"Language detection","url":"https://github.com/octocat/linguist",
+			 "stargazerCount":12,"primaryLanguage":{"name":"Ruby"},
+			 "repositoryTopics":{"nodes":[{"topic":{"name":"syntax"}}]}},
+			{}
+		]}}}}`))
+	}))
+	defer srv.Close()
+
+	repos, err := NewClient(srv.URL, "secret").ShowcasedRepos(context.Background(), "octocat")
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+	assert.Equal(t, Repo{
+		Name:        "linguist",
+		Description: "Language detection",
+		URL:         "https://github.com/octocat/linguist",
+		Language:    "Ruby",
+		Stars:       12,
+		Topics:      []string{"syntax"},
+	}, repos[0])
+}
+
+func TestShowcasedRepos_UnauthenticatedFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/octocat/repos", r.URL.Path)
+		assert.Empty(t, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[
+			{"name":"small","html_url":"u1","stargazers_count":1},
+			{"name":"forked","html_url":"u2","stargazers_count":99,"fork":true},
+			{"name":"big","html_url":"u3","stargazers_count":50,"language":"Go"},
+			{"name":"old","html_url":"u4","stargazers_count":80,"archived":true}
+		]`))
+	}))
+	defer srv.Close()
+
+	repos, err := NewClient(srv.URL, "").ShowcasedRepos(context.Background(), "octocat")
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	assert.Equal(t, "big", repos[0].Name)
+	assert.Equal(t, "small", repos[1].Name)
+}
+
+func TestShowcasedRepos_Errors(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/missing/repos" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "")
+	_, err := client.ShowcasedRepos(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	_, err = client.ShowcasedRepos(context.Background(), "busy")
+	var rateErr *RateLimitError
+	require.True(t, errors.As(err, &rateErr))
+	assert.True(t, rateErr.Reset.Equal(reset))
+}
+
+func TestValidUsername(t *testing.T) {
+	assert.True(t, ValidUsername("octocat"))
+	assert.True(t, ValidUsername("mona-lisa"))
+	assert.False(t, ValidUsername("-leading"))
+	assert.False(t, ValidUsername("double--dash"))
+	assert.False(t, ValidUsername("../etc"))
+	assert.False(t, ValidUsername(""))
+}
+
+func TestDraftBullet(t *testing.T) {
+	assert.Equal(t, "Built linguist, a Ruby project (language detection for source files), earning 12 GitHub stars.",
+		DraftBullet(Repo{Name: "linguist", Language: "Ruby", Description: "Language detection for source files.", Stars: 12}))
+	assert.Equal(t, "Built dotfiles (CLI setup), earning 1 GitHub star.",
+		DraftBullet(Repo{Name: "dotfiles", Description: "CLI setup", Stars: 1}))
+	assert.Equal(t, "Built scratch.", DraftBullet(Repo{Name: "scratch"}))
+}
+
+func TestSkills(t *testing.T) {
+	assert.Equal(t, []string{"Go", "kubernetes"}, Skills(Repo{Language: "Go", Topics: []string{"kubernetes", "go"}}))
+	assert.Empty(t, Skills(Repo{}))
+}
+
+func TestSealToken(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	sealed, err := SealToken(key, "ghp_secret")
+	require.NoError(t, err)
+	assert.NotContains(t, sealed, "ghp_secret")
+
+	token, err := OpenToken(key, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_secret", token)
+
+	_, err = OpenToken([]byte("fedcba9876543210fedcba9876543210"), sealed)
+	assert.Error(t, err)
+}
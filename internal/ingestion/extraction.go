@@ -33,7 +33,7 @@ type ExtractedContent struct {
 // ExtractWithLLM uses LLM to separate core content from administrative metadata.
 // It uses the generic JobRequirementsSchema for consistent extraction.
 func ExtractWithLLM(ctx context.Context, text string, apiKey string) (*ExtractedContent, error) {
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, fmt.Errorf("API key required for LLM extraction")
 	}
 
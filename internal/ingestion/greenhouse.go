@@ -0,0 +1,184 @@
+package ingestion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/fetch"
+)
+
+// greenhouseAPIBase is the Greenhouse Job Board API root; tests point it at a local server
+var greenhouseAPIBase = "https://boards-api.greenhouse.io/v1/boards"
+
+// greenhouseJob is the subset of a Job Board API job response the ingester uses
+type greenhouseJob struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	CompanyName string `json:"company_name"`
+	AbsoluteURL string `json:"absolute_url"`
+	UpdatedAt   string `json:"updated_at"`
+	Content     string `json:"content"` // HTML, entity-escaped once more
+	Location    struct {
+		Name string `json:"name"`
+	} `json:"location"`
+	Departments []struct {
+		Name string `json:"name"`
+	} `json:"departments"`
+	Offices []struct {
+		Name string `json:"name"`
+	} `json:"offices"`
+	PayInputRanges []struct {
+		Title        string `json:"title"`
+		MinCents     int64  `json:"min_cents"`
+		MaxCents     int64  `json:"max_cents"`
+		CurrencyType string `json:"currency_type"`
+	} `json:"pay_input_ranges"`
+}
+
+// ParseGreenhouseURL returns the board token and job ID of a Greenhouse job URL, such as
+// boards.greenhouse.io/{board}/jobs/{id}, job-boards.greenhouse.io/{board}/jobs/{id}, or
+// boards.greenhouse.io/embed/job_app?for={board}&token={id}
+func ParseGreenhouseURL(urlStr string) (board, jobID string, ok bool) {
+	parsed, err := url.Parse(urlStr)
+	if err != nil || !strings.HasSuffix(strings.ToLower(parsed.Hostname()), "greenhouse.io") {
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	switch {
+	case len(parts) >= 3 && parts[0] != "embed" && parts[1] == "jobs":
+		board, jobID = parts[0], parts[2]
+	case len(parts) >= 2 && parts[0] == "embed":
+		board, jobID = parsed.Query().Get("for"), parsed.Query().Get("token")
+	case len(parts) >= 1:
+		// Board pages link jobs as boards.greenhouse.io/{board}?gh_jid={id}
+		board, jobID = parts[0], parsed.Query().Get("gh_jid")
+	}
+	if board == "" || !isGreenhouseJobID(jobID) {
+		return "", "", false
+	}
+	return board, jobID, true
+}
+
+// isGreenhouseJobID reports whether id is a numeric Greenhouse job ID
+func isGreenhouseJobID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// fetchGreenhouseJob fetches one job from the public Job Board API
+func fetchGreenhouseJob(ctx context.Context, board, jobID string) (*greenhouseJob, error) {
+	apiURL := fmt.Sprintf("%s/%s/jobs/%s?pay_transparency=true", greenhouseAPIBase, url.PathEscape(board), jobID)
+	result, err := fetch.URL(ctx, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var job greenhouseJob
+	if err := json.Unmarshal([]byte(result.HTML), &job); err != nil {
+		return nil, fmt.Errorf("failed to parse Greenhouse job %s/%s: %w", board, jobID, err)
+	}
+	if job.Title == "" || job.Content == "" {
+		return nil, fmt.Errorf("greenhouse job %s/%s has no title or content", board, jobID)
+	}
+	return &job, nil
+}
+
+// ingestFromGreenhouse builds the cleaned text and metadata for a Greenhouse job from the
+// Job Board API, which carries the title, location, and pay ranges as fields rather than
+// leaving them to scraping
+func ingestFromGreenhouse(ctx context.Context, urlStr, board, jobID string) (string, *Metadata, error) {
+	job, err := fetchGreenhouseJob(ctx, board, jobID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	body, links, err := CleanHTML(html.UnescapeString(job.Content))
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrContentExtractionFailed, err)
+	}
+	info := job.adminInfo()
+
+	var sb strings.Builder
+	sb.WriteString(job.Title + "\n")
+	if loc := info["Location"]; loc != "" {
+		sb.WriteString("Location: " + loc + "\n")
+	}
+	sb.WriteString("\n" + body)
+	cleanedText := CleanText(sb.String())
+
+	metadata := NewMetadata(cleanedText, urlStr)
+	metadata.Platform = string(fetch.PlatformGreenhouse)
+	metadata.RoleTitle = strings.TrimSpace(job.Title)
+	metadata.Company = strings.TrimSpace(job.CompanyName)
+	metadata.AdminInfo = info
+	metadata.ExtractedLinks = links
+	return cleanedText, metadata, nil
+}
+
+// adminInfo returns the job's structured administrative fields
+func (j *greenhouseJob) adminInfo() map[string]string {
+	info := map[string]string{}
+	if loc := strings.TrimSpace(j.Location.Name); loc != "" {
+		info["Location"] = loc
+	}
+	var departments, offices, pay []string
+	for _, d := range j.Departments {
+		departments = append(departments, d.Name)
+	}
+	for _, o := range j.Offices {
+		offices = append(offices, o.Name)
+	}
+	for _, r := range j.PayInputRanges {
+		pay = append(pay, formatPayRange(r.MinCents, r.MaxCents, r.CurrencyType, r.Title))
+	}
+	if len(departments) > 0 {
+		info["Department"] = strings.Join(departments, ", ")
+	}
+	if len(offices) > 0 {
+		info["Office"] = strings.Join(offices, ", ")
+	}
+	if len(pay) > 0 {
+		info["Salary"] = strings.Join(pay, "; ")
+	}
+	if len(info) == 0 {
+		return nil
+	}
+	return info
+}
+
+// formatPayRange renders a pay range such as "USD 150,000 - 200,000 (Base Salary)"
+func formatPayRange(minCents, maxCents int64, currency, title string) string {
+	s := fmt.Sprintf("%s %s - %s", currency, groupThousands(minCents/100), groupThousands(maxCents/100))
+	if title != "" {
+		s += " (" + title + ")"
+	}
+	return strings.TrimSpace(s)
+}
+
+// groupThousands formats n with comma thousands separators
+func groupThousands(n int64) string {
+	digits := fmt.Sprint(n)
+	if n < 0 {
+		return "-" + groupThousands(-n)
+	}
+	var sb strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(d)
+	}
+	return sb.String()
+}
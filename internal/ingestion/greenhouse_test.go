@@ -0,0 +1,118 @@
+package ingestion
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const greenhouseJobJSON = `{
+  "id": 4012345,
+  "title": "Senior Backend Engineer",
+  "company_name": "Acme",
+  "absolute_url": "https://boards.greenhouse.io/acme/jobs/4012345",
+  "updated_at": "2026-09-01T12:00:00-04:00",
+  "location": {"name": "Remote - US"},
+  "departments": [{"id": 1, "name": "Engineering"}],
+  "offices": [{"id": 2, "name": "New York"}],
+  "pay_input_ranges": [{"title": "Base Salary", "min_cents": 15000000, "max_cents": 20000000, "currency_type": "USD"}],
+  "content": "&lt;h3&gt;About the role&lt;/h3&gt;&lt;p&gt;Build payment APIs in Go &amp;amp; Postgres.&lt;/p&gt;&lt;ul&gt;&lt;li&gt;5+ years of backend experience&lt;/li&gt;&lt;/ul&gt;&lt;p&gt;&lt;a href=&quot;https://acme.com/about&quot;&gt;About Acme&lt;/a&gt;&lt;/p&gt;"
+}`
+
+// withGreenhouseAPI points the ingester at a local Job Board API for the test
+func withGreenhouseAPI(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	orig := greenhouseAPIBase
+	greenhouseAPIBase = server.URL + "/v1/boards"
+	t.Cleanup(func() { greenhouseAPIBase = orig })
+}
+
+func TestParseGreenhouseURL(t *testing.T) {
+	tests := []struct {
+		url       string
+		board, id string
+		ok        bool
+	}{
+		{"https://boards.greenhouse.io/acme/jobs/4012345", "acme", "4012345", true},
+		{"https://job-boards.greenhouse.io/acme/jobs/4012345?gh_src=abc", "acme", "4012345", true},
+		{"https://boards.greenhouse.io/embed/job_app?for=acme&token=4012345", "acme", "4012345", true},
+		{"https://boards.greenhouse.io/acme?gh_jid=4012345", "acme", "4012345", true},
+		{"https://boards.greenhouse.io/acme", "", "", false},
+		{"https://boards.greenhouse.io/acme/jobs/not-a-number", "", "", false},
+		{"https://jobs.lever.co/acme/4012345", "", "", false},
+		{"https://acme.com/careers?gh_jid=4012345", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			board, id, ok := ParseGreenhouseURL(tt.url)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.board, board)
+			assert.Equal(t, tt.id, id)
+		})
+	}
+}
+
+func TestIngestFromGreenhouse(t *testing.T) {
+	var gotPath string
+	withGreenhouseAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte(greenhouseJobJSON))
+	})
+
+	url := "https://boards.greenhouse.io/acme/jobs/4012345"
+	text, metadata, err := ingestFromGreenhouse(context.Background(), url, "acme", "4012345")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/v1/boards/acme/jobs/4012345", gotPath)
+	assert.Contains(t, text, "Senior Backend Engineer\nLocation: Remote - US")
+	assert.Contains(t, text, "Build payment APIs in Go & Postgres.")
+	assert.NotContains(t, text, "&lt;", "content is unescaped before its tags are stripped")
+	assert.NotContains(t, text, "<p>")
+
+	assert.Equal(t, url, metadata.URL)
+	assert.Equal(t, "greenhouse", metadata.Platform)
+	assert.Equal(t, "Senior Backend Engineer", metadata.RoleTitle)
+	assert.Equal(t, "Acme", metadata.Company)
+	assert.Equal(t, map[string]string{
+		"Location":   "Remote - US",
+		"Department": "Engineering",
+		"Office":     "New York",
+		"Salary":     "USD 150,000 - 200,000 (Base Salary)",
+	}, metadata.AdminInfo)
+	assert.Equal(t, []string{"https://acme.com/about"}, metadata.ExtractedLinks)
+}
+
+func TestIngestFromGreenhouse_NotFound(t *testing.T) {
+	withGreenhouseAPI(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, _, err := ingestFromGreenhouse(context.Background(), "https://boards.greenhouse.io/acme/jobs/1", "acme", "1")
+	assert.Error(t, err)
+}
+
+func TestIngestFromURL_UsesGreenhouseAPI(t *testing.T) {
+	withGreenhouseAPI(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(greenhouseJobJSON))
+	})
+
+	text, metadata, err := IngestFromURL(context.Background(), "https://boards.greenhouse.io/acme/jobs/4012345", "", false, false)
+	require.NoError(t, err)
+	assert.Contains(t, text, "5+ years of backend experience")
+	assert.Equal(t, "Senior Backend Engineer", metadata.RoleTitle)
+}
+
+func TestMergeAdminInfo(t *testing.T) {
+	merged := mergeAdminInfo(
+		map[string]string{"Location": "Remote - US"},
+		map[string]string{"Location": "Remote", "Clearance": "None"},
+	)
+	assert.Equal(t, map[string]string{"Location": "Remote - US", "Clearance": "None"}, merged)
+	assert.Equal(t, map[string]string{"Clearance": "None"}, mergeAdminInfo(nil, map[string]string{"Clearance": "None"}))
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/jonathan/resume-customizer/internal/llm"
 )
 
 // CleanHTML extracts meaningful text and links from HTML content
@@ -167,25 +168,9 @@ func IngestFromFile(ctx context.Context, path string, apiKey string) (string, *M
 		cleanedText = CleanText(string(content))
 	}
 
-	// If API key is provided, use LLM to separate core content from metadata
-	if apiKey != "" {
-		extracted, err := ExtractWithLLM(ctx, cleanedText, apiKey)
-		if err == nil {
-			// Success! Use extracted content
-			var sb strings.Builder
-			sb.WriteString("Requirements:\n")
-			for _, req := range extracted.Requirements {
-				sb.WriteString("- " + req + "\n")
-			}
-			sb.WriteString("\nResponsibilities:\n")
-			for _, resp := range extracted.Responsibilities {
-				sb.WriteString("- " + resp + "\n")
-			}
-			cleanedText = sb.String()
-			adminInfo = extracted.AdminInfo
-		} else {
-			return "", nil, fmt.Errorf("LLM extraction failed: %w", err)
-		}
+	cleanedText, adminInfo, err = extractCoreContent(ctx, cleanedText, apiKey)
+	if err != nil {
+		return "", nil, err
 	}
 
 	metadata := NewMetadata(cleanedText, "")
@@ -251,3 +236,39 @@ func IngestFromFile(ctx context.Context, path string, apiKey string) (string, *M
 
 	return cleanedText, metadata, nil
 }
+
+// IngestFromText cleans job posting text that is already in memory, such as a bundled
+// sample posting, and returns cleaned text with metadata attributed to url
+func IngestFromText(ctx context.Context, content string, url string, apiKey string) (string, *Metadata, error) {
+	cleanedText, adminInfo, err := extractCoreContent(ctx, CleanText(content), apiKey)
+	if err != nil {
+		return "", nil, err
+	}
+	metadata := NewMetadata(cleanedText, url)
+	metadata.AdminInfo = adminInfo
+	return cleanedText, metadata, nil
+}
+
+// extractCoreContent uses an LLM, when one is available, to separate the requirements
+// and responsibilities from administrative metadata. Without an LLM the text is returned
+// unchanged.
+func extractCoreContent(ctx context.Context, cleanedText string, apiKey string) (string, map[string]string, error) {
+	if apiKey == "" && llm.APIKeyRequired() {
+		return cleanedText, nil, nil
+	}
+	extracted, err := ExtractWithLLM(ctx, cleanedText, apiKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("LLM extraction failed: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Requirements:\n")
+	for _, req := range extracted.Requirements {
+		sb.WriteString("- " + req + "\n")
+	}
+	sb.WriteString("\nResponsibilities:\n")
+	for _, resp := range extracted.Responsibilities {
+		sb.WriteString("- " + resp + "\n")
+	}
+	return sb.String(), extracted.AdminInfo, nil
+}
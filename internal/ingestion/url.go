@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/jonathan/resume-customizer/internal/fetch"
+	"github.com/jonathan/resume-customizer/internal/llm"
 )
 
 var (
@@ -21,6 +22,7 @@ var (
 // IngestFromURL fetches content from a URL, extracts text, cleans it, and returns cleaned text with metadata.
 // It uses platform detection to apply platform-specific selectors for better content extraction.
 // If apiKey is provided, it uses LLM to extract structured job requirements.
+// Greenhouse jobs are read from the public Job Board API instead, when it answers.
 // If useBrowser is true, falls back to headless browser for SPA sites with insufficient content.
 // If verbose is true, logs detailed information about the extraction process.
 func IngestFromURL(ctx context.Context, urlStr string, apiKey string, useBrowser bool, verbose bool) (string, *Metadata, error) {
@@ -31,6 +33,62 @@ func IngestFromURL(ctx context.Context, urlStr string, apiKey string, useBrowser
 		log.Printf("[VERBOSE] Detected platform: %s", platform)
 	}
 
+	// Greenhouse jobs come from the public Job Board API, falling back to scraping the page
+	var cleanedText string
+	var metadata *Metadata
+	if board, jobID, ok := ParseGreenhouseURL(urlStr); ok {
+		var err error
+		cleanedText, metadata, err = ingestFromGreenhouse(ctx, urlStr, board, jobID)
+		if err != nil {
+			if verbose {
+				log.Printf("[VERBOSE] Greenhouse API failed: %v, scraping the page", err)
+			}
+			metadata = nil
+		} else if verbose {
+			log.Printf("[VERBOSE] Fetched Greenhouse job %s/%s from the API: %d chars", board, jobID, len(cleanedText))
+		}
+	}
+	if metadata == nil {
+		var err error
+		cleanedText, metadata, err = scrapeURL(ctx, urlStr, platform, useBrowser, verbose)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	// If an LLM is available, use it to extract structured content
+	if apiKey != "" || !llm.APIKeyRequired() {
+		if verbose {
+			log.Printf("[VERBOSE] Calling LLM for structured extraction...")
+		}
+		extracted, err := ExtractWithLLM(ctx, cleanedText, apiKey)
+		if err == nil {
+			if verbose {
+				log.Printf("[VERBOSE] LLM extraction successful")
+				log.Printf("[VERBOSE] Team context: %d chars", len(extracted.TeamContext))
+				log.Printf("[VERBOSE] Requirements: %d items", len(extracted.Requirements))
+				log.Printf("[VERBOSE] Responsibilities: %d items", len(extracted.Responsibilities))
+				log.Printf("[VERBOSE] Nice to have: %d items", len(extracted.NiceToHave))
+			}
+			// Format extracted content with team context
+			cleanedText = FormatExtractedContent(extracted)
+			metadata.AdminInfo = mergeAdminInfo(metadata.AdminInfo, extracted.AdminInfo)
+			if metadata.Company == "" {
+				metadata.Company = extracted.Company
+			}
+			metadata.AboutCompany = extracted.AboutCompany
+		} else {
+			if verbose {
+				log.Printf("[VERBOSE] LLM extraction failed: %v, using cleaned text", err)
+			}
+		}
+	}
+
+	return cleanedText, metadata, nil
+}
+
+// scrapeURL fetches a page and extracts its cleaned text with the platform's selectors
+func scrapeURL(ctx context.Context, urlStr string, platform fetch.Platform, useBrowser bool, verbose bool) (string, *Metadata, error) {
 	// Fetch HTML using the generic fetch package
 	result, err := fetch.URL(ctx, urlStr, nil)
 	if err != nil {
@@ -97,34 +155,21 @@ func IngestFromURL(ctx context.Context, urlStr string, apiKey string, useBrowser
 	metadata := NewMetadata(cleanedText, urlStr)
 	metadata.Platform = string(platform)
 	metadata.ExtractedLinks = links
+	return cleanedText, metadata, nil
+}
 
-	// If API key is provided, use LLM to extract structured content
-	if apiKey != "" {
-		if verbose {
-			log.Printf("[VERBOSE] Calling LLM for structured extraction...")
-		}
-		extracted, err := ExtractWithLLM(ctx, cleanedText, apiKey)
-		if err == nil {
-			if verbose {
-				log.Printf("[VERBOSE] LLM extraction successful")
-				log.Printf("[VERBOSE] Team context: %d chars", len(extracted.TeamContext))
-				log.Printf("[VERBOSE] Requirements: %d items", len(extracted.Requirements))
-				log.Printf("[VERBOSE] Responsibilities: %d items", len(extracted.Responsibilities))
-				log.Printf("[VERBOSE] Nice to have: %d items", len(extracted.NiceToHave))
-			}
-			// Format extracted content with team context
-			cleanedText = FormatExtractedContent(extracted)
-			metadata.AdminInfo = extracted.AdminInfo
-			metadata.Company = extracted.Company
-			metadata.AboutCompany = extracted.AboutCompany
-		} else {
-			if verbose {
-				log.Printf("[VERBOSE] LLM extraction failed: %v, using cleaned text", err)
-			}
+// mergeAdminInfo returns the structured fields a job board supplied, filled in with the
+// ones the LLM extracted for keys the board left out
+func mergeAdminInfo(structured, extracted map[string]string) map[string]string {
+	if len(structured) == 0 {
+		return extracted
+	}
+	for k, v := range extracted {
+		if _, ok := structured[k]; !ok {
+			structured[k] = v
 		}
 	}
-
-	return cleanedText, metadata, nil
+	return structured
 }
 
 // FormatExtractedContent formats the structured extraction as readable text.
@@ -0,0 +1,20 @@
+package interchange
+
+import "fmt"
+
+// FormatError reports a document that is not valid in its format
+type FormatError struct {
+	Message string
+	Cause   error
+}
+
+func (e *FormatError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("format error: %s: %v", e.Message, e.Cause)
+	}
+	return fmt.Sprintf("format error: %s", e.Message)
+}
+
+func (e *FormatError) Unwrap() error {
+	return e.Cause
+}
@@ -0,0 +1,329 @@
+// Package interchange converts experience banks to and from resume formats used by other
+// tools, so users can bring existing data in and take theirs elsewhere.
+package interchange
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/skills"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// FormatJSONResume names the JSON Resume format (https://jsonresume.org/schema)
+const FormatJSONResume = "jsonresume"
+
+// jsonResumeSchemaURL is the schema exported documents declare
+const jsonResumeSchemaURL = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+// JSONResume is the subset of a JSON Resume document the experience bank can hold
+type JSONResume struct {
+	Schema    string                `json:"$schema,omitempty"`
+	Basics    *JSONResumeBasics     `json:"basics,omitempty"`
+	Work      []JSONResumeWork      `json:"work,omitempty"`
+	Education []JSONResumeEducation `json:"education,omitempty"`
+	Skills    []JSONResumeSkill     `json:"skills,omitempty"`
+}
+
+// JSONResumeBasics holds the candidate's name and contact details
+type JSONResumeBasics struct {
+	Name  string `json:"name,omitempty"`
+	Label string `json:"label,omitempty"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+// JSONResumeWork is one position; its highlights become the story's bullets
+type JSONResumeWork struct {
+	Name       string   `json:"name,omitempty"` // Company
+	Position   string   `json:"position,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"` // Empty while the position is current
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+// JSONResumeEducation is one degree or course of study
+type JSONResumeEducation struct {
+	Institution string   `json:"institution,omitempty"`
+	Area        string   `json:"area,omitempty"`      // Field of study
+	StudyType   string   `json:"studyType,omitempty"` // e.g. "Bachelor", "Master of Science"
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Score       string   `json:"score,omitempty"`
+	Courses     []string `json:"courses,omitempty"`
+}
+
+// JSONResumeSkill is a named skill, optionally a group of keywords sharing one level
+type JSONResumeSkill struct {
+	Name     string   `json:"name,omitempty"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// jsonResumeSections are the JSON Resume sections the experience bank has no place for
+var jsonResumeSections = []string{
+	"volunteer", "awards", "certificates", "publications", "languages",
+	"interests", "references", "projects",
+}
+
+// jsonResumeDate matches the ISO 8601 dates JSON Resume allows: YYYY, YYYY-MM, or YYYY-MM-DD
+var jsonResumeDate = regexp.MustCompile(`^(\d{4})(?:-(\d{2}))?(?:-\d{2})?$`)
+
+// skillLevels maps common JSON Resume skill levels to proficiencies from 1 to 5
+var skillLevels = map[string]int{
+	"beginner":     1,
+	"basic":        1,
+	"novice":       1,
+	"elementary":   2,
+	"intermediate": 3,
+	"proficient":   3,
+	"advanced":     4,
+	"expert":       5,
+	"master":       5,
+}
+
+// Degree abbreviations, without periods or spaces
+var (
+	bachelorAbbreviations = []string{"ba", "bs", "bsc", "beng", "bfa", "bba"}
+	masterAbbreviations   = []string{"ma", "ms", "msc", "meng", "mfa", "mba", "mphil"}
+)
+
+// proficiencyLevels names proficiencies from 1 to 5 when exporting
+var proficiencyLevels = []string{"", "Beginner", "Elementary", "Intermediate", "Advanced", "Expert"}
+
+// ParseJSONResume decodes a JSON Resume document. It also returns the sections present
+// that the experience bank cannot hold, such as awards and projects, so callers can say
+// they were left out.
+func ParseJSONResume(data []byte) (*JSONResume, []string, error) {
+	var resume JSONResume
+	if err := json.Unmarshal(data, &resume); err != nil {
+		return nil, nil, &FormatError{Message: "invalid JSON Resume document", Cause: err}
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, nil, &FormatError{Message: "invalid JSON Resume document", Cause: err}
+	}
+	var ignored []string
+	for _, name := range jsonResumeSections {
+		if raw, ok := sections[name]; ok && !isEmptyJSON(raw) {
+			ignored = append(ignored, name)
+		}
+	}
+	return &resume, ignored, nil
+}
+
+// isEmptyJSON reports whether raw is null or an empty array or object
+func isEmptyJSON(raw json.RawMessage) bool {
+	switch string(bytes.TrimSpace(raw)) {
+	case "null", "[]", "{}":
+		return true
+	}
+	return false
+}
+
+// FromJSONResume converts a JSON Resume document to an experience bank. Each position
+// becomes a story whose bullets are its highlights (or its summary when it has none),
+// tagged with the technologies they mention. Positions without a company or role and
+// education without an institution are skipped.
+func FromJSONResume(resume *JSONResume) (*types.ExperienceBank, error) {
+	bank := &types.ExperienceBank{Stories: []types.Story{}}
+	for i, work := range resume.Work {
+		company, role := strings.TrimSpace(work.Name), strings.TrimSpace(work.Position)
+		if company == "" || role == "" {
+			continue
+		}
+		start, err := bankDate(work.StartDate, fmt.Sprintf("work[%d].startDate", i))
+		if err != nil {
+			return nil, err
+		}
+		end, err := bankDate(work.EndDate, fmt.Sprintf("work[%d].endDate", i))
+		if err != nil {
+			return nil, err
+		}
+
+		texts := work.Highlights
+		if len(texts) == 0 && strings.TrimSpace(work.Summary) != "" {
+			texts = []string{work.Summary}
+		}
+		storyID := fmt.Sprintf("work_%d", i+1)
+		story := types.Story{ID: storyID, Company: company, Role: role, StartDate: start, EndDate: end, Bullets: []types.Bullet{}}
+		for _, text := range texts {
+			text = strings.TrimSpace(text)
+			if text == "" {
+				continue
+			}
+			story.Bullets = append(story.Bullets, types.Bullet{
+				ID:               fmt.Sprintf("%s_bullet_%d", storyID, len(story.Bullets)+1),
+				Text:             text,
+				Skills:           skills.TechNames(skills.DetectTechStack(text)),
+				LengthChars:      len(text),
+				EvidenceStrength: "medium",
+				RiskFlags:        []string{},
+			})
+		}
+		bank.Stories = append(bank.Stories, story)
+	}
+
+	for i, edu := range resume.Education {
+		school := strings.TrimSpace(edu.Institution)
+		if school == "" {
+			continue
+		}
+		start, err := bankDate(edu.StartDate, fmt.Sprintf("education[%d].startDate", i))
+		if err != nil {
+			return nil, err
+		}
+		end, err := bankDate(edu.EndDate, fmt.Sprintf("education[%d].endDate", i))
+		if err != nil {
+			return nil, err
+		}
+		bank.Education = append(bank.Education, types.Education{
+			ID:         fmt.Sprintf("education_%d", i+1),
+			School:     school,
+			Degree:     degreeCode(edu.StudyType),
+			Field:      strings.TrimSpace(edu.Area),
+			StartDate:  start,
+			EndDate:    end,
+			GPA:        strings.TrimSpace(edu.Score),
+			Highlights: edu.Courses,
+		})
+	}
+
+	seen := make(map[string]bool)
+	for _, skill := range resume.Skills {
+		names := skill.Keywords
+		if len(names) == 0 {
+			names = []string{skill.Name}
+		}
+		proficiency := skillProficiency(skill.Level)
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[strings.ToLower(name)] {
+				continue
+			}
+			seen[strings.ToLower(name)] = true
+			bank.SkillProfile = append(bank.SkillProfile, types.SkillAssessment{Skill: name, Proficiency: proficiency})
+		}
+	}
+	return bank, nil
+}
+
+// ToJSONResume converts an experience bank to a JSON Resume document with basics as
+// its contact details. Skill self-assessments become one skill entry each.
+func ToJSONResume(bank *types.ExperienceBank, basics *JSONResumeBasics) *JSONResume {
+	resume := &JSONResume{Schema: jsonResumeSchemaURL, Basics: basics, Work: []JSONResumeWork{}}
+	for _, story := range bank.Stories {
+		work := JSONResumeWork{
+			Name:       story.Company,
+			Position:   story.Role,
+			StartDate:  story.StartDate,
+			Highlights: make([]string, 0, len(story.Bullets)),
+		}
+		if !strings.EqualFold(story.EndDate, "present") {
+			work.EndDate = story.EndDate
+		}
+		for _, bullet := range story.Bullets {
+			work.Highlights = append(work.Highlights, bullet.Text)
+		}
+		resume.Work = append(resume.Work, work)
+	}
+	for _, edu := range bank.Education {
+		resume.Education = append(resume.Education, JSONResumeEducation{
+			Institution: edu.School,
+			Area:        edu.Field,
+			StudyType:   studyType(edu.Degree),
+			StartDate:   edu.StartDate,
+			EndDate:     edu.EndDate,
+			Score:       edu.GPA,
+			Courses:     edu.Highlights,
+		})
+	}
+	for _, assessment := range bank.SkillProfile {
+		skill := JSONResumeSkill{Name: assessment.Skill}
+		if assessment.Proficiency > 0 && assessment.Proficiency < len(proficiencyLevels) {
+			skill.Level = proficiencyLevels[assessment.Proficiency]
+		}
+		resume.Skills = append(resume.Skills, skill)
+	}
+	return resume
+}
+
+// bankDate converts a JSON Resume date to the experience bank's YYYY-MM. Dates with
+// only a year start in January.
+func bankDate(date, field string) (string, error) {
+	date = strings.TrimSpace(date)
+	if date == "" {
+		return "", nil
+	}
+	m := jsonResumeDate.FindStringSubmatch(date)
+	if m == nil {
+		return "", &FormatError{Message: fmt.Sprintf("%s must be an ISO 8601 date (YYYY, YYYY-MM, or YYYY-MM-DD), got %q", field, date)}
+	}
+	month := m[2]
+	if month == "" {
+		month = "01"
+	}
+	if n, _ := strconv.Atoi(month); n < 1 || n > 12 {
+		return "", &FormatError{Message: fmt.Sprintf("%s has an invalid month: %q", field, date)}
+	}
+	return m[1] + "-" + month, nil
+}
+
+// degreeCode maps a JSON Resume study type to the experience bank's degree codes
+func degreeCode(studyType string) string {
+	s := strings.ToLower(strings.TrimSpace(studyType))
+	s = strings.NewReplacer(".", "", " ", "").Replace(s)
+	switch {
+	case s == "":
+		return ""
+	case strings.Contains(s, "phd") || strings.Contains(s, "doctor"):
+		return "phd"
+	case strings.Contains(s, "master") || slices.Contains(masterAbbreviations, s):
+		return "master"
+	case strings.Contains(s, "bachelor") || slices.Contains(bachelorAbbreviations, s):
+		return "bachelor"
+	case strings.Contains(s, "associate"):
+		return "associate"
+	default:
+		return "other"
+	}
+}
+
+// studyType names a degree code for JSON Resume
+func studyType(degree string) string {
+	switch strings.ToLower(degree) {
+	case "bachelor":
+		return "Bachelor"
+	case "master":
+		return "Master"
+	case "phd":
+		return "PhD"
+	case "associate":
+		return "Associate"
+	case "other":
+		return ""
+	default:
+		return degree
+	}
+}
+
+// skillProficiency reads a JSON Resume skill level, which is free text: a known word
+// such as "Advanced", or a number from 1 to 5. Anything else is left unassessed.
+func skillProficiency(level string) int {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if n, ok := skillLevels[level]; ok {
+		return n
+	}
+	if n, err := strconv.Atoi(level); err == nil && n >= 1 && n <= 5 {
+		return n
+	}
+	return 0
+}
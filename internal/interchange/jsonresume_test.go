@@ -0,0 +1,128 @@
+package interchange
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+const sampleJSONResume = `{
+  "basics": {"name": "Jane Doe", "email": "jane@example.com"},
+  "work": [
+    {
+      "name": "Acme Corp",
+      "position": "Senior Engineer",
+      "startDate": "2020-03-15",
+      "highlights": ["Moved billing to Kafka and PostgreSQL", "  ", "Mentored four engineers"]
+    },
+    {"name": "Startup", "position": "Engineer", "startDate": "2017", "endDate": "2020-02", "summary": "Built the first API in Go"},
+    {"name": "No Role"}
+  ],
+  "education": [
+    {"institution": "State University", "area": "Computer Science", "studyType": "B.S.", "endDate": "2017-05", "score": "3.8"},
+    {"institution": "Tech Institute", "studyType": "Master of Engineering"}
+  ],
+  "skills": [
+    {"name": "Backend", "level": "Advanced", "keywords": ["Go", "Kafka"]},
+    {"name": "Rust", "level": "2"},
+    {"name": "go", "level": "Expert"}
+  ],
+  "awards": [{"title": "Hackathon winner"}],
+  "projects": [],
+  "volunteer": null
+}`
+
+func TestParseJSONResume(t *testing.T) {
+	resume, ignored, err := ParseJSONResume([]byte(sampleJSONResume))
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", resume.Basics.Name)
+	assert.Len(t, resume.Work, 3)
+	assert.Equal(t, []string{"awards"}, ignored, "empty sections are not reported")
+
+	_, _, err = ParseJSONResume([]byte(`{"work": "not a list"}`))
+	var formatErr *FormatError
+	assert.ErrorAs(t, err, &formatErr)
+}
+
+func TestFromJSONResume(t *testing.T) {
+	resume, _, err := ParseJSONResume([]byte(sampleJSONResume))
+	require.NoError(t, err)
+	bank, err := FromJSONResume(resume)
+	require.NoError(t, err)
+
+	require.Len(t, bank.Stories, 2, "positions without a role are skipped")
+	acme := bank.Stories[0]
+	assert.Equal(t, "Acme Corp", acme.Company)
+	assert.Equal(t, "Senior Engineer", acme.Role)
+	assert.Equal(t, "2020-03", acme.StartDate)
+	assert.Empty(t, acme.EndDate)
+	require.Len(t, acme.Bullets, 2, "blank highlights are dropped")
+	assert.Equal(t, "Moved billing to Kafka and PostgreSQL", acme.Bullets[0].Text)
+	assert.Equal(t, []string{"Kafka", "PostgreSQL"}, acme.Bullets[0].Skills)
+	assert.Equal(t, "medium", acme.Bullets[0].EvidenceStrength)
+	assert.Equal(t, len(acme.Bullets[1].Text), acme.Bullets[1].LengthChars)
+
+	startup := bank.Stories[1]
+	assert.Equal(t, "2017-01", startup.StartDate)
+	assert.Equal(t, "2020-02", startup.EndDate)
+	require.Len(t, startup.Bullets, 1, "the summary stands in for missing highlights")
+	assert.Equal(t, "Built the first API in Go", startup.Bullets[0].Text)
+
+	require.Len(t, bank.Education, 2)
+	assert.Equal(t, "bachelor", bank.Education[0].Degree)
+	assert.Equal(t, "Computer Science", bank.Education[0].Field)
+	assert.Equal(t, "3.8", bank.Education[0].GPA)
+	assert.Equal(t, "master", bank.Education[1].Degree)
+
+	assert.Equal(t, []types.SkillAssessment{
+		{Skill: "Go", Proficiency: 4},
+		{Skill: "Kafka", Proficiency: 4},
+		{Skill: "Rust", Proficiency: 2},
+	}, bank.SkillProfile, "keywords are the skills, and repeats keep the first level")
+}
+
+func TestFromJSONResume_InvalidDate(t *testing.T) {
+	_, err := FromJSONResume(&JSONResume{Work: []JSONResumeWork{{Name: "Acme", Position: "Engineer", StartDate: "March 2020"}}})
+	var formatErr *FormatError
+	require.ErrorAs(t, err, &formatErr)
+	assert.Contains(t, err.Error(), "work[0].startDate")
+
+	_, err = FromJSONResume(&JSONResume{Education: []JSONResumeEducation{{Institution: "State", EndDate: "2017-13"}}})
+	assert.ErrorAs(t, err, &formatErr)
+}
+
+func TestToJSONResume(t *testing.T) {
+	bank := &types.ExperienceBank{
+		Stories: []types.Story{{
+			Company: "Acme Corp", Role: "Senior Engineer", StartDate: "2020-03", EndDate: "present",
+			Bullets: []types.Bullet{{Text: "Moved billing to Kafka"}},
+		}},
+		Education:    []types.Education{{School: "State University", Degree: "bachelor", Field: "Computer Science", EndDate: "2017-05"}},
+		SkillProfile: []types.SkillAssessment{{Skill: "Go", Proficiency: 5}, {Skill: "Rust"}},
+	}
+	resume := ToJSONResume(bank, &JSONResumeBasics{Name: "Jane Doe"})
+
+	data, err := json.Marshal(resume)
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Contains(t, doc["$schema"], "jsonresume")
+	work := doc["work"].([]any)[0].(map[string]any)
+	assert.Equal(t, "Acme Corp", work["name"])
+	assert.Equal(t, "2020-03", work["startDate"])
+	assert.NotContains(t, work, "endDate", "current positions have no end date")
+	assert.Equal(t, []any{"Moved billing to Kafka"}, work["highlights"])
+	assert.Equal(t, "Bachelor", doc["education"].([]any)[0].(map[string]any)["studyType"])
+	assert.Equal(t, []JSONResumeSkill{{Name: "Go", Level: "Expert"}, {Name: "Rust"}}, resume.Skills)
+
+	// Round trip
+	back, err := FromJSONResume(resume)
+	require.NoError(t, err)
+	assert.Equal(t, "Moved billing to Kafka", back.Stories[0].Bullets[0].Text)
+	assert.Equal(t, "bachelor", back.Education[0].Degree)
+	assert.Equal(t, 5, back.SkillProfile[0].Proficiency)
+}
@@ -0,0 +1,223 @@
+// Package kubejob launches pipeline step executions as Kubernetes Jobs.
+//
+// Each Job runs `resume_agent worker --job-id <id>`, which claims the queued
+// step job, executes it, and uploads the resulting artifact to the step queue
+// before exiting. The launcher talks to the Kubernetes API directly over REST
+// using the pod's service account, so no client library is required.
+package kubejob
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/stepqueue"
+)
+
+// ServiceAccountDir is where Kubernetes mounts the pod's service account credentials
+const ServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// DefaultTTLSeconds is how long finished Jobs are kept before Kubernetes deletes them
+const DefaultTTLSeconds = 3600
+
+// Config holds the settings used to create Jobs
+type Config struct {
+	APIServer      string // Base URL of the Kubernetes API server
+	Token          string // Bearer token for the API server
+	Namespace      string // Namespace Jobs are created in
+	Image          string // Default container image when a step doesn't set one
+	Secret         string // Secret exposed to the container as env (DATABASE_URL, GEMINI_API_KEY)
+	ServiceAccount string // Optional service account for the Job's pod
+	TTLSeconds     int    // ttlSecondsAfterFinished for created Jobs
+	HTTPClient     *http.Client
+}
+
+// ConfigFromEnv builds an in-cluster configuration from the pod's service account
+// and the K8S_JOB_* environment variables
+func ConfigFromEnv() (*Config, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("kubernetes backend requires running in-cluster (KUBERNETES_SERVICE_HOST is not set)")
+	}
+
+	token, err := os.ReadFile(filepath.Join(ServiceAccountDir, "token"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account token: %w", err)
+	}
+	caCert, err := os.ReadFile(filepath.Join(ServiceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("invalid service account CA certificate")
+	}
+
+	namespace := os.Getenv("K8S_JOB_NAMESPACE")
+	if namespace == "" {
+		data, err := os.ReadFile(filepath.Join(ServiceAccountDir, "namespace"))
+		if err != nil {
+			return nil, fmt.Errorf("K8S_JOB_NAMESPACE is not set and the pod namespace is unavailable: %w", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+
+	ttl := DefaultTTLSeconds
+	if v := os.Getenv("K8S_JOB_TTL_SECONDS"); v != "" {
+		ttl, err = strconv.Atoi(v)
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("K8S_JOB_TTL_SECONDS must be a non-negative integer, got: %s", v)
+		}
+	}
+
+	return &Config{
+		APIServer:      "https://" + net.JoinHostPort(host, port),
+		Token:          strings.TrimSpace(string(token)),
+		Namespace:      namespace,
+		Image:          os.Getenv("K8S_JOB_IMAGE"),
+		Secret:         os.Getenv("K8S_JOB_SECRET"),
+		ServiceAccount: os.Getenv("K8S_JOB_SERVICE_ACCOUNT"),
+		TTLSeconds:     ttl,
+		HTTPClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}},
+		},
+	}, nil
+}
+
+// Launcher creates one Kubernetes Job per step execution. It implements stepqueue.Launcher.
+type Launcher struct {
+	cfg *Config
+}
+
+// NewLauncher creates a launcher from cfg
+func NewLauncher(cfg *Config) *Launcher {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Launcher{cfg: cfg}
+}
+
+// Launch creates the Job for req
+func (l *Launcher) Launch(ctx context.Context, req stepqueue.LaunchRequest) error {
+	job, err := l.jobManifest(req)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job manifest: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/apis/batch/v1/namespaces/%s/jobs", strings.TrimRight(l.cfg.APIServer, "/"), l.cfg.Namespace)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if l.cfg.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+l.cfg.Token)
+	}
+
+	resp, err := l.cfg.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("kubernetes API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// jobName derives a DNS-1123 compliant Job name from the step and job ID
+func jobName(req stepqueue.LaunchRequest) string {
+	step := strings.ReplaceAll(req.Step, "_", "-")
+	return fmt.Sprintf("step-%s-%s", step, req.JobID.String()[:8])
+}
+
+// jobManifest builds the batch/v1 Job for req
+func (l *Launcher) jobManifest(req stepqueue.LaunchRequest) (map[string]any, error) {
+	image := req.Spec.Image
+	if image == "" {
+		image = l.cfg.Image
+	}
+	if image == "" {
+		return nil, fmt.Errorf("no image configured for step %s (set image or K8S_JOB_IMAGE)", req.Step)
+	}
+
+	labels := map[string]string{
+		"app.kubernetes.io/name":      "resume-customizer",
+		"app.kubernetes.io/component": "step-job",
+		"resume-customizer/step":      req.Step,
+		"resume-customizer/job-id":    req.JobID.String(),
+	}
+
+	container := map[string]any{
+		"name":  "step",
+		"image": image,
+		"args":  []string{"worker", "--job-id", req.JobID.String(), "--steps", req.Step},
+	}
+	if resources := resourceList(req.Spec.CPU, req.Spec.Memory); len(resources) > 0 {
+		container["resources"] = map[string]any{"requests": resources, "limits": resources}
+	}
+	if l.cfg.Secret != "" {
+		container["envFrom"] = []map[string]any{{"secretRef": map[string]string{"name": l.cfg.Secret}}}
+	}
+
+	podSpec := map[string]any{
+		"restartPolicy": "Never",
+		"containers":    []map[string]any{container},
+	}
+	if l.cfg.ServiceAccount != "" {
+		podSpec["serviceAccountName"] = l.cfg.ServiceAccount
+	}
+
+	jobSpec := map[string]any{
+		"backoffLimit":            0, // Retries belong to the step queue, not Kubernetes
+		"ttlSecondsAfterFinished": l.cfg.TTLSeconds,
+		"template": map[string]any{
+			"metadata": map[string]any{"labels": labels},
+			"spec":     podSpec,
+		},
+	}
+	if req.Spec.TimeoutSeconds > 0 {
+		jobSpec["activeDeadlineSeconds"] = req.Spec.TimeoutSeconds
+	}
+
+	return map[string]any{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]any{
+			"name":      jobName(req),
+			"namespace": l.cfg.Namespace,
+			"labels":    labels,
+		},
+		"spec": jobSpec,
+	}, nil
+}
+
+// resourceList builds a Kubernetes ResourceList from optional quantities
+func resourceList(cpu, memory string) map[string]string {
+	resources := make(map[string]string)
+	if cpu != "" {
+		resources["cpu"] = cpu
+	}
+	if memory != "" {
+		resources["memory"] = memory
+	}
+	return resources
+}
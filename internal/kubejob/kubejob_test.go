@@ -0,0 +1,82 @@
+package kubejob
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/stepqueue"
+)
+
+func TestLauncher_Launch(t *testing.T) {
+	var gotPath, gotAuth string
+	var job map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &job)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	l := NewLauncher(&Config{APIServer: srv.URL, Token: "tok", Namespace: "resumes", Image: "default:1", Secret: "resume-env", TTLSeconds: 60})
+	jobID := uuid.MustParse("0b7e6d1c-1111-2222-3333-444455556666")
+	err := l.Launch(context.Background(), stepqueue.LaunchRequest{
+		JobID: jobID,
+		Step:  "validate_latex",
+		Spec:  steps.ExecutionSpec{Backend: steps.BackendKubernetes, CPU: "500m", Memory: "1Gi", TimeoutSeconds: 300},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/apis/batch/v1/namespaces/resumes/jobs", gotPath)
+	assert.Equal(t, "Bearer tok", gotAuth)
+
+	metadata := job["metadata"].(map[string]any)
+	assert.Equal(t, "step-validate-latex-0b7e6d1c", metadata["name"])
+
+	spec := job["spec"].(map[string]any)
+	assert.EqualValues(t, 0, spec["backoffLimit"])
+	assert.EqualValues(t, 300, spec["activeDeadlineSeconds"])
+	assert.EqualValues(t, 60, spec["ttlSecondsAfterFinished"])
+
+	podSpec := spec["template"].(map[string]any)["spec"].(map[string]any)
+	assert.Equal(t, "Never", podSpec["restartPolicy"])
+	container := podSpec["containers"].([]any)[0].(map[string]any)
+	assert.Equal(t, "default:1", container["image"])
+	assert.Equal(t, []any{"worker", "--job-id", jobID.String(), "--steps", "validate_latex"}, container["args"])
+	limits := container["resources"].(map[string]any)["limits"].(map[string]any)
+	assert.Equal(t, "500m", limits["cpu"])
+	assert.Equal(t, "1Gi", limits["memory"])
+	assert.Contains(t, container, "envFrom")
+}
+
+func TestLauncher_LaunchErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	req := stepqueue.LaunchRequest{JobID: uuid.New(), Step: "research_company"}
+
+	err := NewLauncher(&Config{APIServer: srv.URL, Namespace: "ns"}).Launch(context.Background(), req)
+	assert.ErrorContains(t, err, "no image configured")
+
+	req.Spec.Image = "custom:2"
+	err = NewLauncher(&Config{APIServer: srv.URL, Namespace: "ns"}).Launch(context.Background(), req)
+	assert.ErrorContains(t, err, "403")
+}
+
+func TestConfigFromEnv_RequiresCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, err := ConfigFromEnv()
+	assert.Error(t, err)
+}
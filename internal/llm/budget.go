@@ -0,0 +1,216 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// TruncationStrategy says which part of a prompt over its context budget is cut
+type TruncationStrategy string
+
+// Truncation strategies
+const (
+	TruncateMiddle TruncationStrategy = "middle" // Keep the start (instructions) and end (output format) (default)
+	TruncateEnd    TruncationStrategy = "end"    // Keep the start
+	TruncateStart  TruncationStrategy = "start"  // Keep the end
+	TruncateNone   TruncationStrategy = "none"   // Fail with a context length error, so batching callers can split
+)
+
+// ValidTruncationStrategies lists the accepted strategy names
+var ValidTruncationStrategies = []TruncationStrategy{TruncateMiddle, TruncateEnd, TruncateStart, TruncateNone}
+
+// promptTruncationMarker replaces the part of a prompt cut to fit its budget
+const promptTruncationMarker = "\n[... %d tokens truncated ...]\n"
+
+// Budget bounds every LLM call made for one pipeline step
+type Budget struct {
+	Step             string             // Step the budget belongs to, named in errors and truncation records
+	Timeout          time.Duration      // Per call; 0 means no limit beyond the caller's context
+	MaxContextTokens int                // Prompt budget in estimated tokens; 0 means unlimited
+	Truncation       TruncationStrategy // How an oversized prompt is fitted; empty means TruncateMiddle
+}
+
+// IsZero reports whether the budget leaves calls unbounded
+func (b Budget) IsZero() bool {
+	return b.Timeout <= 0 && b.MaxContextTokens <= 0
+}
+
+// Truncation records one prompt cut to fit its step's context budget
+type Truncation struct {
+	Step         string             `json:"step"`
+	Strategy     TruncationStrategy `json:"strategy"`
+	Tier         ModelTier          `json:"tier"`
+	PromptTokens int                `json:"prompt_tokens"` // Estimated tokens before truncation
+	BudgetTokens int                `json:"budget_tokens"`
+	CutTokens    int                `json:"cut_tokens"`
+	At           time.Time          `json:"at"`
+}
+
+// TruncationLog collects the truncations made during one pipeline run
+type TruncationLog struct {
+	mu          sync.Mutex
+	truncations []Truncation
+}
+
+// Record appends a truncation
+func (l *TruncationLog) Record(t Truncation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.truncations = append(l.truncations, t)
+}
+
+// Truncations returns a copy of all recorded truncations in call order
+func (l *TruncationLog) Truncations() []Truncation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]Truncation, len(l.truncations))
+	copy(out, l.truncations)
+	return out
+}
+
+// budgetKey and truncationLogKey are the context keys for the step budget and run's truncation log
+type (
+	budgetKey        struct{}
+	truncationLogKey struct{}
+)
+
+// WithBudget returns a context that causes clients created by NewClient to bound
+// every call by the given budget
+func WithBudget(ctx context.Context, b Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// BudgetFromContext returns the step budget attached to ctx, if any
+func BudgetFromContext(ctx context.Context) (Budget, bool) {
+	b, ok := ctx.Value(budgetKey{}).(Budget)
+	return b, ok
+}
+
+// WithTruncationLog returns a context whose budgeted clients record truncations to log
+func WithTruncationLog(ctx context.Context, log *TruncationLog) context.Context {
+	return context.WithValue(ctx, truncationLogKey{}, log)
+}
+
+// TruncationLogFromContext returns the truncation log attached to ctx, if any
+func TruncationLogFromContext(ctx context.Context) *TruncationLog {
+	log, _ := ctx.Value(truncationLogKey{}).(*TruncationLog)
+	return log
+}
+
+// budgetClient wraps a Client and fits each call into its step's budget
+type budgetClient struct {
+	Client
+	budget Budget
+	log    *TruncationLog
+}
+
+// GenerateContent fits the prompt and delegates under the budget's timeout
+func (c *budgetClient) GenerateContent(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	return c.call(ctx, prompt, tier, c.Client.GenerateContent)
+}
+
+// GenerateJSON fits the prompt and delegates under the budget's timeout
+func (c *budgetClient) GenerateJSON(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	return c.call(ctx, prompt, tier, c.Client.GenerateJSON)
+}
+
+func (c *budgetClient) call(ctx context.Context, prompt string, tier ModelTier, generate func(context.Context, string, ModelTier) (string, error)) (string, error) {
+	prompt, err := c.fit(ctx, prompt, tier)
+	if err != nil {
+		return "", err
+	}
+	if c.budget.Timeout <= 0 {
+		return generate(ctx, prompt, tier)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, c.budget.Timeout)
+	defer cancel()
+	res, err := generate(callCtx, prompt, tier)
+	// Only the budget's own deadline is reported as a timeout; the caller's is theirs to explain
+	if err != nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return "", fmt.Errorf("LLM call exceeded the %s step's %s timeout: %w", c.budget.Step, c.budget.Timeout, err)
+	}
+	return res, err
+}
+
+// fit returns prompt cut to the budget's context tokens by its truncation strategy
+func (c *budgetClient) fit(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	limit := c.budget.MaxContextTokens
+	tokens := EstimateTokens(prompt)
+	if limit <= 0 || tokens <= limit {
+		return prompt, nil
+	}
+	strategy := c.budget.Truncation
+	if strategy == "" {
+		strategy = TruncateMiddle
+	}
+	if strategy == TruncateNone {
+		return "", fmt.Errorf("prompt exceeds the %s step's context length budget (~%d tokens, budget %d)", c.budget.Step, tokens, limit)
+	}
+
+	fitted := TruncatePrompt(prompt, limit, strategy)
+	t := Truncation{
+		Step:         c.budget.Step,
+		Strategy:     strategy,
+		Tier:         tier,
+		PromptTokens: tokens,
+		BudgetTokens: limit,
+		CutTokens:    tokens - EstimateTokens(fitted),
+		At:           time.Now().UTC(),
+	}
+	if c.log != nil {
+		c.log.Record(t)
+	}
+	slog.WarnContext(ctx, "Truncated prompt to fit the step's context budget",
+		"strategy", strategy, "prompt_tokens", t.PromptTokens, "budget_tokens", limit)
+	return fitted, nil
+}
+
+// TruncatePrompt cuts prompt to about maxTokens estimated tokens, marker included,
+// keeping the parts strategy names. Cuts fall on a UTF-8 boundary.
+func TruncatePrompt(prompt string, maxTokens int, strategy TruncationStrategy) string {
+	cut := EstimateTokens(prompt) - maxTokens
+	if cut <= 0 {
+		return prompt
+	}
+	marker := fmt.Sprintf(promptTruncationMarker, cut)
+	keep := max((maxTokens-1)*4-len(marker), 0) // Bytes, inverting EstimateTokens
+
+	switch strategy {
+	case TruncateEnd:
+		return prefixUTF8(prompt, keep) + marker
+	case TruncateStart:
+		return marker + suffixUTF8(prompt, keep)
+	default:
+		head := keep / 2
+		return prefixUTF8(prompt, head) + marker + suffixUTF8(prompt, keep-head)
+	}
+}
+
+// prefixUTF8 returns the longest prefix of s of at most n bytes that ends on a rune boundary
+func prefixUTF8(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// suffixUTF8 returns the longest suffix of s of at most n bytes that starts on a rune boundary
+func suffixUTF8(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	i := len(s) - n
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+	return s[i:]
+}
@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowClient blocks until its context is done
+type slowClient struct {
+	fakeClient
+}
+
+func (s *slowClient) GenerateContent(ctx context.Context, _ string, _ ModelTier) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestTruncatePrompt(t *testing.T) {
+	prompt := "INSTRUCTIONS " + strings.Repeat("é corpus ", 2000) + " FORMAT"
+
+	for _, strategy := range []TruncationStrategy{TruncateMiddle, TruncateEnd, TruncateStart} {
+		t.Run(string(strategy), func(t *testing.T) {
+			out := TruncatePrompt(prompt, 500, strategy)
+			assert.LessOrEqual(t, EstimateTokens(out), 500)
+			assert.True(t, utf8.ValidString(out))
+			assert.Contains(t, out, "tokens truncated ...]")
+			assert.Equal(t, strategy != TruncateStart, strings.HasPrefix(out, "INSTRUCTIONS "))
+			assert.Equal(t, strategy != TruncateEnd, strings.HasSuffix(out, " FORMAT"))
+		})
+	}
+
+	assert.Equal(t, "short", TruncatePrompt("short", 500, TruncateMiddle))
+}
+
+func TestBudgetClient_TruncatesAndRecords(t *testing.T) {
+	inner := &promptCapture{}
+	log := &TruncationLog{}
+	client := &budgetClient{Client: inner, budget: Budget{Step: "summarize_voice", MaxContextTokens: 100}, log: log}
+
+	_, err := client.GenerateContent(context.Background(), strings.Repeat("x", 2000), TierAdvanced)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, EstimateTokens(inner.prompt), 100)
+
+	truncations := log.Truncations()
+	require.Len(t, truncations, 1)
+	assert.Equal(t, "summarize_voice", truncations[0].Step)
+	assert.Equal(t, TruncateMiddle, truncations[0].Strategy)
+	assert.Equal(t, TierAdvanced, truncations[0].Tier)
+	assert.Equal(t, 501, truncations[0].PromptTokens)
+	assert.Equal(t, 100, truncations[0].BudgetTokens)
+	assert.Positive(t, truncations[0].CutTokens)
+
+	_, err = client.GenerateJSON(context.Background(), "fits", TierLite)
+	require.NoError(t, err)
+	assert.Equal(t, "fits", inner.prompt)
+	assert.Len(t, log.Truncations(), 1)
+}
+
+func TestBudgetClient_NoneFailsWithContextLengthError(t *testing.T) {
+	inner := &promptCapture{}
+	client := &budgetClient{Client: inner, budget: Budget{Step: "rewrite_bullets", MaxContextTokens: 10, Truncation: TruncateNone}}
+
+	_, err := client.GenerateJSON(context.Background(), strings.Repeat("x", 200), TierAdvanced)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "context length", "batching callers split on this")
+	assert.Empty(t, inner.prompt, "the provider must not be called")
+}
+
+func TestBudgetClient_Timeout(t *testing.T) {
+	client := &budgetClient{Client: &slowClient{}, budget: Budget{Step: "parse_job", Timeout: 10 * time.Millisecond}}
+
+	_, err := client.GenerateContent(context.Background(), "prompt", TierLite)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeded the parse_job step's 10ms timeout")
+
+	// The caller's own deadline is not blamed on the budget
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	client.budget.Timeout = time.Minute
+	_, err = client.GenerateContent(ctx, "prompt", TierLite)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotContains(t, err.Error(), "step's")
+}
+
+func TestNewClient_AppliesBudget(t *testing.T) {
+	cfg := &Config{Provider: ProviderOllama, BaseURL: "http://localhost:11434"}
+
+	client, err := NewClient(WithBudget(context.Background(), Budget{Step: "parse_job", Timeout: time.Second}), cfg, "")
+	require.NoError(t, err)
+	_, budgeted := client.(*budgetClient)
+	assert.True(t, budgeted)
+
+	client, err = NewClient(WithBudget(context.Background(), Budget{Step: "load_experience"}), cfg, "")
+	require.NoError(t, err)
+	_, budgeted = client.(*budgetClient)
+	assert.False(t, budgeted, "a zero budget is not applied")
+}
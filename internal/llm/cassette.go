@@ -0,0 +1,126 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotRecorded is returned by a cassette client for a prompt it has no response for
+var ErrNotRecorded = errors.New("no recorded response for prompt")
+
+// Cassette replays recorded LLM responses instead of calling a provider, so runs can be
+// demoed and tested with no API key or network access. Its format is the list of
+// exchanges stored in a debug run's debug_llm_exchanges artifact, so any debug run
+// can be saved as a cassette.
+//
+// A prompt is answered by the exchange with the same prompt, or failing that by the
+// exchange with the longest prompt the request starts with. Hand-written exchanges
+// can therefore hold just the opening of a prompt template to answer every prompt
+// built from it.
+type Cassette struct {
+	exchanges []Exchange
+}
+
+// NewCassette creates a cassette that replays exchanges
+func NewCassette(exchanges []Exchange) *Cassette {
+	return &Cassette{exchanges: exchanges}
+}
+
+// LoadCassette parses a JSON array of exchanges
+func LoadCassette(data []byte) (*Cassette, error) {
+	var exchanges []Exchange
+	if err := json.Unmarshal(data, &exchanges); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette: %w", err)
+	}
+	for i, ex := range exchanges {
+		if ex.Prompt == "" {
+			return nil, fmt.Errorf("cassette exchange %d has no prompt", i+1)
+		}
+	}
+	return NewCassette(exchanges), nil
+}
+
+// Len returns the number of recorded exchanges
+func (c *Cassette) Len() int {
+	return len(c.exchanges)
+}
+
+// Lookup returns the exchange that answers prompt. JSON and text requests only match
+// exchanges recorded the same way.
+func (c *Cassette) Lookup(prompt string, isJSON bool) (Exchange, bool) {
+	best := -1
+	for i, ex := range c.exchanges {
+		if ex.JSON != isJSON {
+			continue
+		}
+		if ex.Prompt == prompt {
+			return ex, true
+		}
+		if strings.HasPrefix(prompt, ex.Prompt) && (best < 0 || len(ex.Prompt) > len(c.exchanges[best].Prompt)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Exchange{}, false
+	}
+	return c.exchanges[best], true
+}
+
+// cassetteKey is the context key for the replay cassette
+type cassetteKey struct{}
+
+// WithCassette returns a context that causes clients created by NewClient to replay
+// responses from c instead of calling the configured provider.
+func WithCassette(ctx context.Context, c *Cassette) context.Context {
+	return context.WithValue(ctx, cassetteKey{}, c)
+}
+
+// CassetteFromContext returns the replay cassette attached to ctx, if any
+func CassetteFromContext(ctx context.Context) *Cassette {
+	c, _ := ctx.Value(cassetteKey{}).(*Cassette)
+	return c
+}
+
+// cassetteClient implements Client by replaying a cassette
+type cassetteClient struct {
+	cassette *Cassette
+	config   *Config
+}
+
+// GenerateContent returns the recorded text response for prompt
+func (c *cassetteClient) GenerateContent(_ context.Context, prompt string, _ ModelTier) (string, error) {
+	return c.replay(prompt, false)
+}
+
+// GenerateJSON returns the recorded JSON response for prompt
+func (c *cassetteClient) GenerateJSON(_ context.Context, prompt string, _ ModelTier) (string, error) {
+	res, err := c.replay(prompt, true)
+	if err != nil {
+		return "", err
+	}
+	return cleanJSONBlock(res), nil
+}
+
+// GetModel returns the model the cassette stands in for
+func (c *cassetteClient) GetModel(tier ModelTier) string {
+	return c.config.GetModel(tier)
+}
+
+// Close is a no-op
+func (c *cassetteClient) Close() error {
+	return nil
+}
+
+func (c *cassetteClient) replay(prompt string, isJSON bool) (string, error) {
+	ex, ok := c.cassette.Lookup(prompt, isJSON)
+	if !ok {
+		return "", fmt.Errorf("%w: %.80q", ErrNotRecorded, prompt)
+	}
+	if ex.Error != "" {
+		return "", errors.New(ex.Error)
+	}
+	return ex.Response, nil
+}
@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCassette_Lookup(t *testing.T) {
+	c := NewCassette([]Exchange{
+		{Prompt: "Rewrite the following", Response: "generic"},
+		{Prompt: "Rewrite the following resume bullet", Response: "bullet"},
+		{Prompt: "Rewrite the following resume bullet: Shipped it", Response: "exact"},
+		{Prompt: "Rewrite the following", JSON: true, Response: `{"json":true}`},
+	})
+
+	ex, ok := c.Lookup("Rewrite the following resume bullet: Shipped it", false)
+	require.True(t, ok)
+	assert.Equal(t, "exact", ex.Response)
+
+	ex, ok = c.Lookup("Rewrite the following resume bullet: Fixed bugs", false)
+	require.True(t, ok)
+	assert.Equal(t, "bullet", ex.Response, "longest recorded prefix wins")
+
+	ex, ok = c.Lookup("Rewrite the following cover letter", true)
+	require.True(t, ok)
+	assert.Equal(t, `{"json":true}`, ex.Response, "JSON requests match JSON exchanges")
+
+	_, ok = c.Lookup("Summarize the company", false)
+	assert.False(t, ok)
+}
+
+func TestLoadCassette(t *testing.T) {
+	c, err := LoadCassette([]byte(`[{"sequence":1,"tier":"lite","json":true,"prompt":"Extract","response":"{}"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.Len())
+
+	_, err = LoadCassette([]byte(`[{"response":"orphan"}]`))
+	assert.Error(t, err)
+	_, err = LoadCassette([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestNewClient_ReplaysCassette(t *testing.T) {
+	c := NewCassette([]Exchange{
+		{Prompt: "Parse", JSON: true, Response: "```json\n{\"ok\":true}\n```"},
+		{Prompt: "Fail", Error: "quota exceeded"},
+	})
+	rec := NewRecorder(nil)
+	ctx := WithRecorder(WithCassette(context.Background(), c), rec)
+
+	// No API key is needed for the default (Gemini) provider
+	client, err := NewClient(ctx, DefaultGeminiConfig(), "")
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	res, err := client.GenerateJSON(ctx, "Parse this posting", TierLite)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, res)
+
+	_, err = client.GenerateContent(ctx, "Fail now", TierAdvanced)
+	assert.EqualError(t, err, "quota exceeded")
+
+	_, err = client.GenerateContent(ctx, "Unknown prompt", TierAdvanced)
+	assert.True(t, errors.Is(err, ErrNotRecorded))
+
+	assert.Len(t, rec.Exchanges(), 3, "replayed calls are still recorded")
+}
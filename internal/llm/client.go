@@ -21,22 +21,63 @@ type Client interface {
 	Close() error
 }
 
-// NewClient creates a new LLM client based on configuration
+// NewClient creates a new LLM client based on configuration.
+// If ctx carries a debug Recorder (see WithRecorder), the returned client records every call.
+// If ctx carries a step Selection (see WithSelection), calls are routed to the selected model.
+// If ctx carries a PII Masker (see WithMasker) and the provider is external, prompts are masked.
+// If ctx carries a Cassette (see WithCassette), responses are replayed and no provider is called.
+// If ctx carries a step Budget (see WithBudget), calls are timed out and prompts fitted to it.
 func NewClient(ctx context.Context, config *Config, apiKey string) (Client, error) {
 	if config == nil {
 		config = DefaultConfig()
 	}
+	sel, routed := SelectionFromContext(ctx)
+	routed = routed && !sel.IsZero()
+	if routed && sel.Model != "" {
+		config = config.WithModel(tierPinned, sel.Model)
+	}
 
-	switch config.Provider {
-	case ProviderGemini:
-		return NewGeminiClient(ctx, config, apiKey)
-	// case ProviderOpenAI:
+	cassette := CassetteFromContext(ctx)
+	var client Client
+	var err error
+	switch {
+	case cassette != nil:
+		client = &cassetteClient{cassette: cassette, config: config}
+	case config.Provider == ProviderGemini:
+		client, err = NewGeminiClient(ctx, config, apiKey)
+	case config.Provider == ProviderOllama, config.Provider == ProviderLlamaCpp:
+		client, err = NewLocalClient(config)
+	// case config.Provider == ProviderOpenAI:
 	//     return NewOpenAIClient(ctx, config, apiKey)
-	// case ProviderAnthropic:
+	// case config.Provider == ProviderAnthropic:
 	//     return NewClaudeClient(ctx, config, apiKey)
 	default:
-		return NewGeminiClient(ctx, config, apiKey)
+		client, err = NewGeminiClient(ctx, config, apiKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Replayed responses never leave the process, so there is nothing to mask
+	if m := MaskerFromContext(ctx); m != nil && cassette == nil {
+		if rec, ok := m.(providerRecorder); ok {
+			rec.UseProvider(string(config.Provider), config.IsLocal())
+		}
+		if !config.IsLocal() {
+			client = &maskingClient{Client: client, masker: m}
+		}
+	}
+	if routed {
+		client = &routedClient{Client: client, sel: sel}
+	}
+	if rec := RecorderFromContext(ctx); rec != nil {
+		client = &recordingClient{Client: client, rec: rec}
+	}
+	// Outermost, so debug recordings and cassettes see the prompt actually sent
+	if b, ok := BudgetFromContext(ctx); ok && !b.IsZero() {
+		client = &budgetClient{Client: client, budget: b, log: TruncationLogFromContext(ctx)}
 	}
+	return client, nil
 }
 
 // GeminiClient implements Client for Google Gemini
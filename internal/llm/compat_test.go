@@ -0,0 +1,87 @@
+package llm_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/parsing"
+	"github.com/jonathan/resume-customizer/internal/rewriting"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Local model compatibility suite. It runs the real parsing and rewriting code
+// against a live local server and is skipped unless LOCAL_LLM_COMPAT_URL is set:
+//
+//	LOCAL_LLM_COMPAT_URL=http://localhost:11434 LOCAL_LLM_MODEL=llama3.1:8b \
+//	  go test ./internal/llm -run TestLocalCompat -v
+//
+// Set LLM_PROVIDER=llamacpp to test a llama.cpp server instead of Ollama.
+
+const compatJobPosting = `Acme Corp is hiring a Senior Backend Engineer.
+You will design and operate Go microservices on Kubernetes.
+Requirements: 5+ years of Go, PostgreSQL, distributed systems.
+Nice to have: Kafka, Terraform.`
+
+func setupCompat(t *testing.T) context.Context {
+	t.Helper()
+	url := os.Getenv("LOCAL_LLM_COMPAT_URL")
+	if url == "" {
+		t.Skip("LOCAL_LLM_COMPAT_URL not set; skipping local model compatibility suite")
+	}
+	if os.Getenv("LLM_PROVIDER") != string(llm.ProviderLlamaCpp) {
+		t.Setenv("LLM_PROVIDER", string(llm.ProviderOllama))
+	}
+	t.Setenv("LOCAL_LLM_URL", url)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestLocalCompat_JSON(t *testing.T) {
+	ctx := setupCompat(t)
+	client, err := llm.NewClient(ctx, llm.DefaultConfig(), "")
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	res, err := client.GenerateJSON(ctx, `Return a JSON object with a "skills" array listing the skills in: "Go, PostgreSQL and Kafka".`, llm.TierLite)
+	require.NoError(t, err)
+	var parsed struct {
+		Skills []string `json:"skills"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(res), &parsed), "response: %s", res)
+	assert.NotEmpty(t, parsed.Skills)
+}
+
+func TestLocalCompat_ParseJobProfile(t *testing.T) {
+	ctx := setupCompat(t)
+
+	profile, err := parsing.ParseJobProfile(ctx, compatJobPosting, "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, profile.RoleTitle)
+	assert.NotEmpty(t, profile.HardRequirements)
+}
+
+func TestLocalCompat_RewriteBullets(t *testing.T) {
+	ctx := setupCompat(t)
+	selected := &types.SelectedBullets{Bullets: []types.SelectedBullet{
+		{ID: "b1", StoryID: "s1", Text: "Built a Go service that processed 2M events per day", LengthChars: 52},
+		{ID: "b2", StoryID: "s1", Text: "Cut PostgreSQL query latency by 40% with new indexes", LengthChars: 52},
+	}}
+	jobProfile := &types.JobProfile{Keywords: []string{"Go", "PostgreSQL", "distributed systems"}}
+
+	rewritten, err := rewriting.RewriteBullets(ctx, selected, jobProfile, nil, "")
+	require.NoError(t, err)
+	require.Len(t, rewritten.Bullets, 2)
+	for i, bullet := range rewritten.Bullets {
+		assert.Equal(t, selected.Bullets[i].ID, bullet.OriginalBulletID)
+		assert.NotEmpty(t, bullet.FinalText)
+	}
+}
@@ -2,6 +2,11 @@
 // This package enables easy switching between model tiers and future multi-provider support.
 package llm
 
+import (
+	"os"
+	"strconv"
+)
+
 // ModelTier represents the complexity/capability level of a model
 type ModelTier string
 
@@ -25,17 +30,108 @@ const (
 	ProviderOpenAI Provider = "openai"
 	// ProviderAnthropic is the Anthropic/Claude provider (future)
 	ProviderAnthropic Provider = "anthropic"
+	// ProviderOllama is a local Ollama server; no data leaves the machine
+	ProviderOllama Provider = "ollama"
+	// ProviderLlamaCpp is a local llama.cpp server (llama-server)
+	ProviderLlamaCpp Provider = "llamacpp"
+)
+
+// Default local server addresses and model
+const (
+	DefaultOllamaURL   = "http://localhost:11434"
+	DefaultLlamaCppURL = "http://localhost:8080"
+	DefaultLocalModel  = "llama3.1:8b"
+)
+
+// Default embedding models; EMBEDDING_MODEL overrides them
+const (
+	DefaultGeminiEmbeddingModel = "text-embedding-004"
+	DefaultLocalEmbeddingModel  = "nomic-embed-text"
 )
 
+// Capabilities describe what a model can do, so prompts can be adjusted for
+// smaller local models
+type Capabilities struct {
+	ContextTokens int  // Prompt budget in tokens; 0 means unknown (no check)
+	JSONMode      bool // The server can constrain output to JSON
+}
+
 // Config holds the model configuration for the application
 type Config struct {
-	Provider Provider
-	Models   map[ModelTier]string
+	Provider       Provider
+	Models         map[ModelTier]string
+	EmbeddingModel string       // Model that embeds text for semantic ranking (see NewEmbedder)
+	BaseURL        string       // Server address for local providers
+	Capabilities   Capabilities // Only used by local providers
 }
 
-// DefaultConfig returns the default configuration (currently Gemini)
+// DefaultConfig returns the configuration selected by LLM_PROVIDER: Gemini
+// (the default), or a local Ollama or llama.cpp server
 func DefaultConfig() *Config {
-	return DefaultGeminiConfig()
+	switch Provider(os.Getenv("LLM_PROVIDER")) {
+	case ProviderOllama, ProviderLlamaCpp:
+		return DefaultLocalConfig()
+	default:
+		return DefaultGeminiConfig()
+	}
+}
+
+// DefaultLocalConfig returns the local model configuration from environment variables.
+// LOCAL_LLM_MODEL (default: llama3.1:8b) serves every tier unless LOCAL_LLM_MODEL_LITE,
+// LOCAL_LLM_MODEL_STANDARD, or LOCAL_LLM_MODEL_ADVANCED override it.
+func DefaultLocalConfig() *Config {
+	provider := Provider(os.Getenv("LLM_PROVIDER"))
+	baseURL := DefaultOllamaURL
+	if provider == ProviderLlamaCpp {
+		baseURL = DefaultLlamaCppURL
+	} else {
+		provider = ProviderOllama
+	}
+	if v := os.Getenv("LOCAL_LLM_URL"); v != "" {
+		baseURL = v
+	}
+
+	model := os.Getenv("LOCAL_LLM_MODEL")
+	if model == "" {
+		model = DefaultLocalModel
+	}
+	models := map[ModelTier]string{TierLite: model, TierStandard: model, TierAdvanced: model}
+	for tier, key := range map[ModelTier]string{
+		TierLite:     "LOCAL_LLM_MODEL_LITE",
+		TierStandard: "LOCAL_LLM_MODEL_STANDARD",
+		TierAdvanced: "LOCAL_LLM_MODEL_ADVANCED",
+	} {
+		if v := os.Getenv(key); v != "" {
+			models[tier] = v
+		}
+	}
+
+	caps := Capabilities{ContextTokens: 8192, JSONMode: true}
+	if n, err := strconv.Atoi(os.Getenv("LOCAL_LLM_CONTEXT_TOKENS")); err == nil && n >= 0 {
+		caps.ContextTokens = n
+	}
+	if b, err := strconv.ParseBool(os.Getenv("LOCAL_LLM_JSON_MODE")); err == nil {
+		caps.JSONMode = b
+	}
+
+	return &Config{
+		Provider:       provider,
+		Models:         models,
+		EmbeddingModel: embeddingModel(DefaultLocalEmbeddingModel),
+		BaseURL:        baseURL,
+		Capabilities:   caps,
+	}
+}
+
+// IsLocal reports whether the provider runs on the user's own machine
+func (c *Config) IsLocal() bool {
+	return c.Provider == ProviderOllama || c.Provider == ProviderLlamaCpp
+}
+
+// APIKeyRequired reports whether the configured provider (see DefaultConfig)
+// needs an API key. Local providers never do.
+func APIKeyRequired() bool {
+	return !DefaultConfig().IsLocal()
 }
 
 // DefaultGeminiConfig returns the default Gemini configuration
@@ -47,7 +143,16 @@ func DefaultGeminiConfig() *Config {
 			TierStandard: "gemini-2.5-flash",
 			TierAdvanced: "gemini-2.5-pro",
 		},
+		EmbeddingModel: embeddingModel(DefaultGeminiEmbeddingModel),
+	}
+}
+
+// embeddingModel returns EMBEDDING_MODEL, or def when it is unset
+func embeddingModel(def string) string {
+	if v := os.Getenv("EMBEDDING_MODEL"); v != "" {
+		return v
 	}
+	return def
 }
 
 // GetModel returns the model name for a given tier
@@ -68,8 +173,10 @@ func (c *Config) GetModel(tier ModelTier) string {
 // WithModel returns a new Config with a specific model for a tier
 func (c *Config) WithModel(tier ModelTier, model string) *Config {
 	newConfig := &Config{
-		Provider: c.Provider,
-		Models:   make(map[ModelTier]string),
+		Provider:     c.Provider,
+		Models:       make(map[ModelTier]string),
+		BaseURL:      c.BaseURL,
+		Capabilities: c.Capabilities,
 	}
 	for k, v := range c.Models {
 		newConfig.Models[k] = v
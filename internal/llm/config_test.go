@@ -62,3 +62,39 @@ func TestProviderConstants(t *testing.T) {
 	assert.Equal(t, Provider("openai"), ProviderOpenAI)
 	assert.Equal(t, Provider("anthropic"), ProviderAnthropic)
 }
+
+func TestDefaultConfig_LocalProvider(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", "ollama")
+	t.Setenv("LOCAL_LLM_URL", "")
+	t.Setenv("LOCAL_LLM_MODEL", "qwen2.5:14b")
+	t.Setenv("LOCAL_LLM_MODEL_LITE", "qwen2.5:3b")
+	t.Setenv("LOCAL_LLM_MODEL_STANDARD", "")
+	t.Setenv("LOCAL_LLM_MODEL_ADVANCED", "")
+	t.Setenv("LOCAL_LLM_CONTEXT_TOKENS", "32768")
+	t.Setenv("LOCAL_LLM_JSON_MODE", "false")
+
+	config := DefaultConfig()
+	assert.Equal(t, ProviderOllama, config.Provider)
+	assert.True(t, config.IsLocal())
+	assert.Equal(t, DefaultOllamaURL, config.BaseURL)
+	assert.Equal(t, "qwen2.5:3b", config.GetModel(TierLite))
+	assert.Equal(t, "qwen2.5:14b", config.GetModel(TierAdvanced))
+	assert.Equal(t, Capabilities{ContextTokens: 32768, JSONMode: false}, config.Capabilities)
+	assert.False(t, APIKeyRequired())
+}
+
+func TestDefaultConfig_LlamaCpp(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", "llamacpp")
+	t.Setenv("LOCAL_LLM_URL", "")
+	t.Setenv("LOCAL_LLM_MODEL", "")
+
+	config := DefaultConfig()
+	assert.Equal(t, ProviderLlamaCpp, config.Provider)
+	assert.Equal(t, DefaultLlamaCppURL, config.BaseURL)
+	assert.Equal(t, DefaultLocalModel, config.GetModel(TierStandard))
+}
+
+func TestAPIKeyRequired_Gemini(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", "")
+	assert.True(t, APIKeyRequired())
+}
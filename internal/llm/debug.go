@@ -0,0 +1,112 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Exchange is a single recorded prompt/response pair captured in debug mode
+type Exchange struct {
+	Sequence   int       `json:"sequence"`
+	Tier       ModelTier `json:"tier"`
+	Model      string    `json:"model"`
+	JSON       bool      `json:"json"`
+	Prompt     string    `json:"prompt"`
+	Response   string    `json:"response,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Recorder collects raw prompts and responses for a single pipeline run.
+// All text is passed through the scrub function before it is stored, so the
+// recorder never holds unredacted PII.
+type Recorder struct {
+	mu        sync.Mutex
+	scrub     func(string) string
+	exchanges []Exchange
+}
+
+// NewRecorder creates a recorder that applies scrub to every prompt, response, and error.
+// A nil scrub function stores text unchanged.
+func NewRecorder(scrub func(string) string) *Recorder {
+	if scrub == nil {
+		scrub = func(s string) string { return s }
+	}
+	return &Recorder{scrub: scrub}
+}
+
+// Record appends an exchange after scrubbing its text fields
+func (r *Recorder) Record(ex Exchange) {
+	ex.Prompt = r.scrub(ex.Prompt)
+	ex.Response = r.scrub(ex.Response)
+	ex.Error = r.scrub(ex.Error)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ex.Sequence = len(r.exchanges) + 1
+	r.exchanges = append(r.exchanges, ex)
+}
+
+// Exchanges returns a copy of all recorded exchanges in call order
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Exchange, len(r.exchanges))
+	copy(out, r.exchanges)
+	return out
+}
+
+// recorderKey is the context key for the debug recorder
+type recorderKey struct{}
+
+// WithRecorder returns a context that causes clients created by NewClient to
+// record every call to the given recorder.
+func WithRecorder(ctx context.Context, rec *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, rec)
+}
+
+// RecorderFromContext returns the debug recorder attached to ctx, if any
+func RecorderFromContext(ctx context.Context) *Recorder {
+	rec, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return rec
+}
+
+// recordingClient wraps a Client and records each call to a Recorder
+type recordingClient struct {
+	Client
+	rec *Recorder
+}
+
+// GenerateContent delegates to the wrapped client and records the exchange
+func (c *recordingClient) GenerateContent(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	start := time.Now()
+	res, err := c.Client.GenerateContent(ctx, prompt, tier)
+	c.record(tier, false, prompt, res, err, start)
+	return res, err
+}
+
+// GenerateJSON delegates to the wrapped client and records the exchange
+func (c *recordingClient) GenerateJSON(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	start := time.Now()
+	res, err := c.Client.GenerateJSON(ctx, prompt, tier)
+	c.record(tier, true, prompt, res, err, start)
+	return res, err
+}
+
+func (c *recordingClient) record(tier ModelTier, isJSON bool, prompt, response string, err error, start time.Time) {
+	ex := Exchange{
+		Tier:       tier,
+		Model:      c.GetModel(tier),
+		JSON:       isJSON,
+		Prompt:     prompt,
+		Response:   response,
+		StartedAt:  start.UTC(),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		ex.Error = err.Error()
+	}
+	c.rec.Record(ex)
+}
@@ -0,0 +1,92 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient is a minimal Client used to exercise wrappers
+type fakeClient struct {
+	response string
+	err      error
+}
+
+func (f *fakeClient) GenerateContent(_ context.Context, _ string, _ ModelTier) (string, error) {
+	return f.response, f.err
+}
+
+func (f *fakeClient) GenerateJSON(_ context.Context, _ string, _ ModelTier) (string, error) {
+	return f.response, f.err
+}
+
+func (f *fakeClient) GetModel(tier ModelTier) string {
+	return DefaultConfig().GetModel(tier)
+}
+
+func (f *fakeClient) Close() error { return nil }
+
+func TestRecorder_ScrubsAndSequences(t *testing.T) {
+	rec := NewRecorder(func(s string) string {
+		return strings.ReplaceAll(s, "secret", "[X]")
+	})
+
+	rec.Record(Exchange{Prompt: "a secret prompt", Response: "secret reply"})
+	rec.Record(Exchange{Prompt: "second", Error: "secret failure"})
+
+	got := rec.Exchanges()
+	require.Len(t, got, 2)
+	assert.Equal(t, 1, got[0].Sequence)
+	assert.Equal(t, "a [X] prompt", got[0].Prompt)
+	assert.Equal(t, "[X] reply", got[0].Response)
+	assert.Equal(t, 2, got[1].Sequence)
+	assert.Equal(t, "[X] failure", got[1].Error)
+}
+
+func TestRecorder_NilScrub(t *testing.T) {
+	rec := NewRecorder(nil)
+	rec.Record(Exchange{Prompt: "unchanged"})
+	assert.Equal(t, "unchanged", rec.Exchanges()[0].Prompt)
+}
+
+func TestRecorderFromContext(t *testing.T) {
+	assert.Nil(t, RecorderFromContext(context.Background()))
+
+	rec := NewRecorder(nil)
+	ctx := WithRecorder(context.Background(), rec)
+	assert.Same(t, rec, RecorderFromContext(ctx))
+}
+
+func TestRecordingClient_RecordsCalls(t *testing.T) {
+	rec := NewRecorder(nil)
+	client := &recordingClient{Client: &fakeClient{response: `{"ok":true}`}, rec: rec}
+
+	res, err := client.GenerateJSON(context.Background(), "give json", TierLite)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, res)
+
+	got := rec.Exchanges()
+	require.Len(t, got, 1)
+	assert.True(t, got[0].JSON)
+	assert.Equal(t, TierLite, got[0].Tier)
+	assert.Equal(t, "gemini-2.5-flash-lite", got[0].Model)
+	assert.Equal(t, "give json", got[0].Prompt)
+	assert.False(t, got[0].StartedAt.IsZero())
+}
+
+func TestRecordingClient_RecordsErrors(t *testing.T) {
+	rec := NewRecorder(nil)
+	client := &recordingClient{Client: &fakeClient{err: errors.New("quota exceeded")}, rec: rec}
+
+	_, err := client.GenerateContent(context.Background(), "prompt", TierAdvanced)
+	require.Error(t, err)
+
+	got := rec.Exchanges()
+	require.Len(t, got, 1)
+	assert.False(t, got[0].JSON)
+	assert.Equal(t, "quota exceeded", got[0].Error)
+}
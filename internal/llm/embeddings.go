@@ -0,0 +1,192 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+// geminiEmbedBatchSize is the most texts Gemini embeds in one request
+const geminiEmbedBatchSize = 100
+
+// ErrEmbeddingsUnavailable is returned by NewEmbedder when no embedding model is
+// configured, or when ctx replays a cassette, which records no embeddings
+var ErrEmbeddingsUnavailable = errors.New("embeddings are not available")
+
+// Embedder turns text into vectors whose cosine similarity measures how close their
+// meanings are
+type Embedder interface {
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model names the embedding model. Vectors from different models cannot be compared.
+	Model() string
+	// Close releases any resources held by the embedder
+	Close() error
+}
+
+// NewEmbedder creates an embedder for config's provider and EmbeddingModel. Like
+// NewClient, it masks PII in texts sent to external providers when ctx carries a Masker.
+func NewEmbedder(ctx context.Context, config *Config, apiKey string) (Embedder, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if config.EmbeddingModel == "" || CassetteFromContext(ctx) != nil {
+		return nil, ErrEmbeddingsUnavailable
+	}
+
+	var embedder Embedder
+	switch config.Provider {
+	case ProviderOllama, ProviderLlamaCpp:
+		local, err := NewLocalClient(config)
+		if err != nil {
+			return nil, err
+		}
+		embedder = &localEmbedder{client: local}
+	default:
+		if apiKey == "" {
+			return nil, fmt.Errorf("API key is required")
+		}
+		client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+		}
+		embedder = &geminiEmbedder{client: client, model: config.EmbeddingModel}
+	}
+
+	if m := MaskerFromContext(ctx); m != nil && !config.IsLocal() {
+		embedder = &maskingEmbedder{Embedder: embedder, masker: m}
+	}
+	return embedder, nil
+}
+
+// geminiEmbedder embeds text with a Gemini embedding model
+type geminiEmbedder struct {
+	client *genai.Client
+	model  string
+}
+
+// Embed embeds texts in batches of geminiEmbedBatchSize
+func (e *geminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	model := e.client.EmbeddingModel(e.model)
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += geminiEmbedBatchSize {
+		end := min(start+geminiEmbedBatchSize, len(texts))
+		batch := model.NewBatch()
+		for _, text := range texts[start:end] {
+			batch.AddContent(genai.Text(text))
+		}
+		res, err := model.BatchEmbedContents(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("embedding with %s failed: %w", e.model, err)
+		}
+		if len(res.Embeddings) != end-start {
+			return nil, fmt.Errorf("embedding with %s returned %d vectors for %d texts", e.model, len(res.Embeddings), end-start)
+		}
+		for _, emb := range res.Embeddings {
+			vectors = append(vectors, emb.Values)
+		}
+	}
+	return vectors, nil
+}
+
+// Model returns the embedding model name
+func (e *geminiEmbedder) Model() string { return e.model }
+
+// Close closes the Gemini client
+func (e *geminiEmbedder) Close() error { return e.client.Close() }
+
+// localEmbedder embeds text through a local server's OpenAI-compatible embeddings endpoint
+type localEmbedder struct {
+	client *LocalClient
+}
+
+// embeddingsRequest is an OpenAI-compatible embeddings request
+type embeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingsResponse is the subset of an OpenAI-compatible embeddings response we use
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed embeds texts in one request
+func (e *localEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	model := e.Model()
+	body, err := json.Marshal(embeddingsRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	url := strings.TrimSuffix(e.client.config.BaseURL, "/") + "/v1/embeddings"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("local embedding request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local embedding response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("local embedding model %s returned %d: %s", model, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var parsed embeddingsResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid local embedding response: %w", err)
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range parsed.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("local embedding response has out-of-range index %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("local embedding response is missing text %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// Model returns the embedding model name
+func (e *localEmbedder) Model() string { return e.client.config.EmbeddingModel }
+
+// Close releases nothing; the HTTP client holds no resources to free
+func (e *localEmbedder) Close() error { return nil }
+
+// maskingEmbedder masks PII in texts before they are embedded by an external provider
+type maskingEmbedder struct {
+	Embedder
+	masker Masker
+}
+
+// Embed masks each text and delegates
+func (e *maskingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	masked := make([]string, len(texts))
+	for i, text := range texts {
+		masked[i] = e.masker.Mask(text)
+	}
+	return e.Embedder.Embed(ctx, masked)
+}
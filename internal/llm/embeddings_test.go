@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEmbeddingServer serves OpenAI-compatible embeddings, returning each input's length
+// as a one-dimensional vector in reverse order to check index handling
+func fakeEmbeddingServer(t *testing.T, requests *[]embeddingsRequest) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/embeddings", r.URL.Path)
+		var req embeddingsRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+		if req.Model == "broken" {
+			http.Error(w, "model not found", http.StatusNotFound)
+			return
+		}
+		data := make([]map[string]any, 0, len(req.Input))
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			data = append(data, map[string]any{"index": i, "embedding": []float32{float32(len(req.Input[i]))}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLocalEmbedder(t *testing.T) {
+	var requests []embeddingsRequest
+	cfg := localTestConfig(fakeEmbeddingServer(t, &requests).URL)
+	cfg.EmbeddingModel = "nomic-embed-text"
+
+	embedder, err := NewEmbedder(context.Background(), cfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, "nomic-embed-text", embedder.Model())
+
+	vectors, err := embedder.Embed(context.Background(), []string{"Go", "Kubernetes"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{2}, {10}}, vectors)
+	require.Len(t, requests, 1)
+	assert.Equal(t, embeddingsRequest{Model: "nomic-embed-text", Input: []string{"Go", "Kubernetes"}}, requests[0])
+
+	cfg.EmbeddingModel = "broken"
+	_, err = embedder.Embed(context.Background(), []string{"Go"})
+	assert.ErrorContains(t, err, "returned 404")
+}
+
+func TestNewEmbedder_Unavailable(t *testing.T) {
+	cfg := localTestConfig("http://localhost:11434")
+	_, err := NewEmbedder(context.Background(), cfg, "")
+	assert.ErrorIs(t, err, ErrEmbeddingsUnavailable, "no embedding model configured")
+
+	cfg.EmbeddingModel = "nomic-embed-text"
+	ctx := WithCassette(context.Background(), NewCassette(nil))
+	_, err = NewEmbedder(ctx, cfg, "")
+	assert.ErrorIs(t, err, ErrEmbeddingsUnavailable, "replays record no embeddings")
+
+	_, err = NewEmbedder(context.Background(), &Config{Provider: ProviderGemini, EmbeddingModel: DefaultGeminiEmbeddingModel}, "")
+	assert.ErrorContains(t, err, "API key is required")
+}
+
+func TestMaskingEmbedder(t *testing.T) {
+	var requests []embeddingsRequest
+	cfg := localTestConfig(fakeEmbeddingServer(t, &requests).URL)
+	cfg.EmbeddingModel = "nomic-embed-text"
+	local, err := NewEmbedder(context.Background(), cfg, "")
+	require.NoError(t, err)
+
+	embedder := &maskingEmbedder{Embedder: local, masker: stubMasker{}}
+	_, err = embedder.Embed(context.Background(), []string{"Jane led the migration"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"[PII_NAME_1] led the migration"}, requests[0].Input)
+
+	// Local embedders are never wrapped: no data leaves the machine
+	ctx := WithMasker(context.Background(), stubMasker{})
+	local, err = NewEmbedder(ctx, cfg, "")
+	require.NoError(t, err)
+	_, isMasking := local.(*maskingEmbedder)
+	assert.False(t, isMasking)
+}
+
+func TestDefaultConfig_EmbeddingModel(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", "")
+	t.Setenv("EMBEDDING_MODEL", "")
+	assert.Equal(t, DefaultGeminiEmbeddingModel, DefaultConfig().EmbeddingModel)
+
+	t.Setenv("LLM_PROVIDER", "ollama")
+	assert.Equal(t, DefaultLocalEmbeddingModel, DefaultConfig().EmbeddingModel)
+
+	t.Setenv("EMBEDDING_MODEL", "mxbai-embed-large")
+	assert.Equal(t, "mxbai-embed-large", DefaultConfig().EmbeddingModel)
+}
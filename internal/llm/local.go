@@ -0,0 +1,222 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// localSystemPrompt keeps smaller local models on task
+const localSystemPrompt = "You are a precise assistant for tailoring resumes. Follow the requested output format exactly."
+
+// localJSONInstruction is appended to JSON prompts for servers without a JSON mode
+const localJSONInstruction = "\n\nRespond with a single valid JSON value and nothing else: no explanation, no markdown, no code blocks."
+
+// localOutputReserve is the share of the context window kept free for the response
+const localOutputReserve = 4
+
+// LocalClient implements Client for a local Ollama or llama.cpp server through
+// their OpenAI-compatible chat completions endpoint. Requests never leave the
+// configured server, and there is no fallback to hosted models.
+type LocalClient struct {
+	http   *http.Client
+	config *Config
+}
+
+// NewLocalClient creates a client for the server at config.BaseURL
+func NewLocalClient(config *Config) (*LocalClient, error) {
+	if config.BaseURL == "" {
+		return nil, fmt.Errorf("local model server URL is required")
+	}
+	return &LocalClient{
+		http:   &http.Client{Timeout: 5 * time.Minute},
+		config: config,
+	}, nil
+}
+
+// chatRequest is an OpenAI-compatible chat completion request
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []chatMessage   `json:"messages"`
+	Temperature    float64         `json:"temperature"`
+	Stream         bool            `json:"stream"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+// chatResponse is the subset of an OpenAI-compatible chat completion response we use
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// GenerateContent generates text content using the specified model tier
+func (c *LocalClient) GenerateContent(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	return c.generate(ctx, prompt, tier, false)
+}
+
+// GenerateJSON generates JSON content using the specified model tier
+func (c *LocalClient) GenerateJSON(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	res, err := c.generate(ctx, prompt, tier, true)
+	if err != nil {
+		return "", err
+	}
+	return extractJSON(cleanJSONBlock(res)), nil
+}
+
+// GetModel returns the model name for a tier
+func (c *LocalClient) GetModel(tier ModelTier) string {
+	return c.config.GetModel(tier)
+}
+
+// Close releases resources held by the client
+func (c *LocalClient) Close() error {
+	c.http.CloseIdleConnections()
+	return nil
+}
+
+// generate tries each distinct local model in the tier's fallback chain
+func (c *LocalClient) generate(ctx context.Context, prompt string, tier ModelTier, isJSON bool) (string, error) {
+	prompt, err := c.adaptPrompt(prompt, isJSON)
+	if err != nil {
+		return "", err
+	}
+
+	var lastErr error
+	tried := make(map[string]bool)
+	for _, t := range localFallbackTiers(tier) {
+		model := c.config.GetModel(t)
+		if model == "" || tried[model] {
+			continue
+		}
+		tried[model] = true
+
+		res, err := c.complete(ctx, model, prompt, isJSON)
+		if err == nil {
+			return res, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if lastErr == nil {
+		return "", fmt.Errorf("no local model configured for tier %s", tier)
+	}
+	return "", fmt.Errorf("all local models failed, last error: %w", lastErr)
+}
+
+// adaptPrompt adjusts a prompt to the model's capabilities. Prompts that would
+// not leave room for a response fail with a context length error, so callers
+// that batch work (e.g. bullet rewriting) can split it.
+func (c *LocalClient) adaptPrompt(prompt string, isJSON bool) (string, error) {
+	caps := c.config.Capabilities
+	if isJSON && !caps.JSONMode {
+		prompt += localJSONInstruction
+	}
+	if caps.ContextTokens > 0 {
+		budget := caps.ContextTokens - caps.ContextTokens/localOutputReserve
+		if tokens := EstimateTokens(prompt); tokens > budget {
+			return "", fmt.Errorf("prompt exceeds the local model's context length (~%d tokens, budget %d)", tokens, budget)
+		}
+	}
+	return prompt, nil
+}
+
+// complete sends one chat completion request
+func (c *LocalClient) complete(ctx context.Context, model, prompt string, isJSON bool) (string, error) {
+	reqBody := chatRequest{
+		Model: model,
+		Messages: []chatMessage{
+			{Role: "system", Content: localSystemPrompt},
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.1,
+	}
+	if isJSON && c.config.Capabilities.JSONMode {
+		reqBody.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	url := strings.TrimSuffix(c.config.BaseURL, "/") + "/v1/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("local model request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read local model response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("local model %s returned %d: %s", model, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var parsed chatResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return "", fmt.Errorf("invalid local model response: %w", err)
+	}
+	if len(parsed.Choices) == 0 || strings.TrimSpace(parsed.Choices[0].Message.Content) == "" {
+		return "", fmt.Errorf("no content in local model response")
+	}
+	return parsed.Choices[0].Message.Content, nil
+}
+
+// localFallbackTiers is the tier order tried for a request. Unlike the hosted
+// chain there is no "safety" model, since that would send data off the machine.
+func localFallbackTiers(tier ModelTier) []ModelTier {
+	switch tier {
+	case TierAdvanced:
+		return []ModelTier{TierAdvanced, TierStandard, TierLite}
+	case TierStandard:
+		return []ModelTier{TierStandard, TierLite}
+	default:
+		return []ModelTier{tier, TierLite}
+	}
+}
+
+// EstimateTokens roughly estimates a prompt's token count (about 4 characters per token)
+func EstimateTokens(text string) int {
+	return len(text)/4 + 1
+}
+
+// extractJSON trims any text a model wrote around the outermost JSON object or array
+func extractJSON(text string) string {
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return text
+	}
+	closer := byte('}')
+	if text[start] == '[' {
+		closer = ']'
+	}
+	end := strings.LastIndexByte(text, closer)
+	if end < start {
+		return text
+	}
+	return text[start : end+1]
+}
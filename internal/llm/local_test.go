@@ -0,0 +1,144 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLocalServer serves OpenAI-compatible chat completions, recording requests.
+// Models named "broken" fail with a 500.
+type fakeLocalServer struct {
+	requests []chatRequest
+	reply    string
+}
+
+func (f *fakeLocalServer) start(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/chat/completions", r.URL.Path)
+		var req chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		f.requests = append(f.requests, req)
+		if req.Model == "broken" {
+			http.Error(w, "model not found", http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": f.reply}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func localTestConfig(url string) *Config {
+	return &Config{
+		Provider:     ProviderOllama,
+		Models:       map[ModelTier]string{TierLite: "small", TierStandard: "small", TierAdvanced: "large"},
+		BaseURL:      url,
+		Capabilities: Capabilities{ContextTokens: 1000, JSONMode: true},
+	}
+}
+
+func TestLocalClient_GenerateContent(t *testing.T) {
+	fake := &fakeLocalServer{reply: "Rewritten bullet"}
+	client, err := NewLocalClient(localTestConfig(fake.start(t).URL))
+	require.NoError(t, err)
+
+	res, err := client.GenerateContent(context.Background(), "rewrite this", TierAdvanced)
+	require.NoError(t, err)
+	assert.Equal(t, "Rewritten bullet", res)
+
+	require.Len(t, fake.requests, 1)
+	req := fake.requests[0]
+	assert.Equal(t, "large", req.Model)
+	assert.Nil(t, req.ResponseFormat)
+	require.Len(t, req.Messages, 2)
+	assert.Equal(t, "system", req.Messages[0].Role)
+	assert.Equal(t, "rewrite this", req.Messages[1].Content)
+}
+
+func TestLocalClient_GenerateJSON_JSONMode(t *testing.T) {
+	fake := &fakeLocalServer{reply: "Sure! ```json\n{\"company\": \"Acme\"}\n```"}
+	client, err := NewLocalClient(localTestConfig(fake.start(t).URL))
+	require.NoError(t, err)
+
+	res, err := client.GenerateJSON(context.Background(), "parse this", TierLite)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"company": "Acme"}`, res)
+	require.NotNil(t, fake.requests[0].ResponseFormat)
+	assert.Equal(t, "json_object", fake.requests[0].ResponseFormat.Type)
+	assert.Equal(t, "parse this", fake.requests[0].Messages[1].Content)
+}
+
+func TestLocalClient_GenerateJSON_WithoutJSONMode(t *testing.T) {
+	fake := &fakeLocalServer{reply: `{"ok": true}`}
+	cfg := localTestConfig(fake.start(t).URL)
+	cfg.Capabilities.JSONMode = false
+	client, err := NewLocalClient(cfg)
+	require.NoError(t, err)
+
+	_, err = client.GenerateJSON(context.Background(), "parse this", TierLite)
+	require.NoError(t, err)
+	assert.Nil(t, fake.requests[0].ResponseFormat)
+	assert.True(t, strings.HasSuffix(fake.requests[0].Messages[1].Content, localJSONInstruction),
+		"prompt is adjusted when the server can't enforce JSON")
+}
+
+func TestLocalClient_ContextOverflow(t *testing.T) {
+	fake := &fakeLocalServer{reply: "unused"}
+	client, err := NewLocalClient(localTestConfig(fake.start(t).URL))
+	require.NoError(t, err)
+
+	_, err = client.GenerateContent(context.Background(), strings.Repeat("word ", 1000), TierLite)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "context length")
+	assert.Empty(t, fake.requests, "oversized prompts are never sent")
+}
+
+func TestLocalClient_FallsBackToDistinctModels(t *testing.T) {
+	fake := &fakeLocalServer{reply: "ok"}
+	cfg := localTestConfig(fake.start(t).URL)
+	cfg.Models[TierAdvanced] = "broken"
+	client, err := NewLocalClient(cfg)
+	require.NoError(t, err)
+
+	res, err := client.GenerateContent(context.Background(), "hi", TierAdvanced)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", res)
+	// broken, then small once (standard and lite share a model)
+	require.Len(t, fake.requests, 2)
+	assert.Equal(t, "small", fake.requests[1].Model)
+}
+
+func TestLocalClient_AllModelsFail(t *testing.T) {
+	fake := &fakeLocalServer{}
+	cfg := localTestConfig(fake.start(t).URL)
+	cfg.Models = map[ModelTier]string{TierLite: "broken"}
+	client, err := NewLocalClient(cfg)
+	require.NoError(t, err)
+
+	_, err = client.GenerateContent(context.Background(), "hi", TierLite)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+}
+
+func TestNewClient_LocalProviderNeedsNoAPIKey(t *testing.T) {
+	client, err := NewClient(context.Background(), localTestConfig("http://localhost:1"), "")
+	require.NoError(t, err)
+	_, ok := client.(*LocalClient)
+	assert.True(t, ok)
+}
+
+func TestExtractJSON(t *testing.T) {
+	assert.Equal(t, `{"a": 1}`, extractJSON(`Here you go: {"a": 1} Thanks!`))
+	assert.Equal(t, `[1, 2]`, extractJSON(`result: [1, 2]`))
+	assert.Equal(t, `plain`, extractJSON(`plain`))
+}
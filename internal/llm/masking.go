@@ -0,0 +1,50 @@
+package llm
+
+import "context"
+
+// Masker hides PII in prompts before they leave the process and puts it back
+// into generated text (see redact.Masker)
+type Masker interface {
+	Mask(text string) string
+	Restore(text string) string
+}
+
+// providerRecorder is implemented by maskers that report which providers a run's
+// clients were created for (see redact.Masker.UseProvider)
+type providerRecorder interface {
+	UseProvider(provider string, local bool)
+}
+
+// maskerKey is the context key for the PII masker
+type maskerKey struct{}
+
+// WithMasker returns a context that causes clients created by NewClient for
+// external providers to mask every prompt and restore every response.
+// Local providers never see the masker because no data leaves the machine.
+func WithMasker(ctx context.Context, m Masker) context.Context {
+	return context.WithValue(ctx, maskerKey{}, m)
+}
+
+// MaskerFromContext returns the PII masker attached to ctx, if any
+func MaskerFromContext(ctx context.Context) Masker {
+	m, _ := ctx.Value(maskerKey{}).(Masker)
+	return m
+}
+
+// maskingClient wraps a Client and masks PII on the way out and back in
+type maskingClient struct {
+	Client
+	masker Masker
+}
+
+// GenerateContent masks the prompt, delegates, and restores the response
+func (c *maskingClient) GenerateContent(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	res, err := c.Client.GenerateContent(ctx, c.masker.Mask(prompt), tier)
+	return c.masker.Restore(res), err
+}
+
+// GenerateJSON masks the prompt, delegates, and restores the response
+func (c *maskingClient) GenerateJSON(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	res, err := c.Client.GenerateJSON(ctx, c.masker.Mask(prompt), tier)
+	return c.masker.Restore(res), err
+}
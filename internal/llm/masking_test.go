@@ -0,0 +1,57 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubMasker is a trivial Masker that swaps a single secret for a token
+type stubMasker struct{}
+
+func (stubMasker) Mask(s string) string    { return strings.ReplaceAll(s, "Jane", "[PII_NAME_1]") }
+func (stubMasker) Restore(s string) string { return strings.ReplaceAll(s, "[PII_NAME_1]", "Jane") }
+
+// promptCapture records the last prompt it was sent and echoes it back
+type promptCapture struct {
+	fakeClient
+	prompt string
+}
+
+func (p *promptCapture) GenerateContent(_ context.Context, prompt string, _ ModelTier) (string, error) {
+	p.prompt = prompt
+	return prompt, nil
+}
+
+func (p *promptCapture) GenerateJSON(_ context.Context, prompt string, _ ModelTier) (string, error) {
+	p.prompt = prompt
+	return prompt, nil
+}
+
+func TestMaskingClient(t *testing.T) {
+	inner := &promptCapture{}
+	client := &maskingClient{Client: inner, masker: stubMasker{}}
+
+	res, err := client.GenerateContent(context.Background(), "Rewrite for Jane", TierLite)
+	assert.NoError(t, err)
+	assert.Equal(t, "Rewrite for [PII_NAME_1]", inner.prompt)
+	assert.Equal(t, "Rewrite for Jane", res)
+
+	res, err = client.GenerateJSON(context.Background(), `{"name":"Jane"}`, TierLite)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"[PII_NAME_1]"}`, inner.prompt)
+	assert.Equal(t, `{"name":"Jane"}`, res)
+}
+
+func TestNewClient_LocalProviderSkipsMasker(t *testing.T) {
+	ctx := WithMasker(context.Background(), stubMasker{})
+	assert.NotNil(t, MaskerFromContext(ctx))
+	assert.Nil(t, MaskerFromContext(context.Background()))
+
+	client, err := NewClient(ctx, &Config{Provider: ProviderOllama, BaseURL: "http://localhost:11434"}, "")
+	assert.NoError(t, err)
+	_, masked := client.(*maskingClient)
+	assert.False(t, masked)
+}
@@ -0,0 +1,193 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// tierPinned is the synthetic tier a pinned model is registered under, so the
+// client's default fallback chain (pinned, lite, safety) still applies
+const tierPinned ModelTier = "pinned"
+
+// Selection is the model choice applied to every LLM call made for one pipeline step
+type Selection struct {
+	Tier      ModelTier // Replaces the tier requested by the caller when set
+	Model     string    // Pins a specific provider model; takes precedence over Tier
+	Downgrade bool      // Use the next cheaper tier because the user's quota is low
+}
+
+// IsZero reports whether the selection leaves the caller's tier unchanged
+func (s Selection) IsZero() bool {
+	return s == Selection{}
+}
+
+// resolve maps the tier requested by a caller to the tier actually used
+func (s Selection) resolve(requested ModelTier) ModelTier {
+	tier := requested
+	if s.Tier != "" {
+		tier = s.Tier
+	}
+	if s.Downgrade {
+		// A pinned model is usually the premium choice, so low quota falls back to tiers
+		return cheaperTier(tier)
+	}
+	if s.Model != "" {
+		return tierPinned
+	}
+	return tier
+}
+
+// cheaperTier returns the next cheaper tier (lite stays lite)
+func cheaperTier(tier ModelTier) ModelTier {
+	switch tier {
+	case TierAdvanced:
+		return TierStandard
+	default:
+		return TierLite
+	}
+}
+
+// selectionKey is the context key for the step's model selection
+type selectionKey struct{}
+
+// WithSelection returns a context that causes clients created by NewClient to
+// route every call through the given selection.
+func WithSelection(ctx context.Context, sel Selection) context.Context {
+	return context.WithValue(ctx, selectionKey{}, sel)
+}
+
+// SelectionFromContext returns the model selection attached to ctx, if any
+func SelectionFromContext(ctx context.Context) (Selection, bool) {
+	sel, ok := ctx.Value(selectionKey{}).(Selection)
+	return sel, ok
+}
+
+// routedClient wraps a Client and rewrites the requested tier per its Selection
+type routedClient struct {
+	Client
+	sel Selection
+}
+
+// GenerateContent delegates to the wrapped client using the routed tier
+func (c *routedClient) GenerateContent(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	return c.Client.GenerateContent(ctx, prompt, c.sel.resolve(tier))
+}
+
+// GenerateJSON delegates to the wrapped client using the routed tier
+func (c *routedClient) GenerateJSON(ctx context.Context, prompt string, tier ModelTier) (string, error) {
+	return c.Client.GenerateJSON(ctx, prompt, c.sel.resolve(tier))
+}
+
+// GetModel returns the model a call at tier is routed to
+func (c *routedClient) GetModel(tier ModelTier) string {
+	return c.Client.GetModel(c.sel.resolve(tier))
+}
+
+// RoutingQuota configures automatic downgrades for users close to their run quota
+type RoutingQuota struct {
+	DailyRuns      int `json:"daily_runs"`      // Runs per user per 24 hours; 0 disables downgrades
+	DowngradeBelow int `json:"downgrade_below"` // Downgrade once fewer than this many runs remain
+}
+
+// Routing maps pipeline steps to model tiers or specific models, e.g. a lite
+// model for keyword extraction and the advanced tier for rewriting
+type Routing struct {
+	Steps map[string]string `json:"steps"` // Step name -> tier name ("lite", "standard", "advanced") or model name
+	Quota RoutingQuota      `json:"quota"`
+}
+
+// LoadRouting reads a JSON routing config from path
+func LoadRouting(path string) (*Routing, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model routing config: %w", err)
+	}
+	var r Routing
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("invalid model routing config %s: %w", path, err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid model routing config %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// Validate checks that every route names a model and the quota is consistent
+func (r *Routing) Validate() error {
+	for step, route := range r.Steps {
+		if route == "" {
+			return fmt.Errorf("step %s: empty model", step)
+		}
+	}
+	if r.Quota.DailyRuns < 0 || r.Quota.DowngradeBelow < 0 {
+		return fmt.Errorf("quota values must not be negative")
+	}
+	if r.Quota.DowngradeBelow > r.Quota.DailyRuns {
+		return fmt.Errorf("quota.downgrade_below (%d) exceeds quota.daily_runs (%d)",
+			r.Quota.DowngradeBelow, r.Quota.DailyRuns)
+	}
+	return nil
+}
+
+// LowQuota reports whether a user who has started usedToday runs in the last
+// 24 hours should have their steps downgraded to cheaper models
+func (r *Routing) LowQuota(usedToday int) bool {
+	if r == nil || r.Quota.DailyRuns == 0 {
+		return false
+	}
+	return r.Quota.DailyRuns-usedToday < r.Quota.DowngradeBelow
+}
+
+// Select returns the model selection for a step. A non-empty override (a tier
+// name or a model the routing already uses) replaces the configured route.
+func (r *Routing) Select(step, override string, downgrade bool) (Selection, error) {
+	route := override
+	if override != "" {
+		if !r.allows(override) {
+			return Selection{}, fmt.Errorf("unknown model %q (must be a tier or a configured model)", override)
+		}
+	} else if r != nil {
+		route = r.Steps[step]
+	}
+	sel := parseRoute(route)
+	sel.Downgrade = downgrade
+	return sel, nil
+}
+
+// allows reports whether a model override names a tier or a known model
+func (r *Routing) allows(model string) bool {
+	if isTier(ModelTier(model)) {
+		return true
+	}
+	for _, m := range DefaultConfig().Models {
+		if m == model {
+			return true
+		}
+	}
+	if r != nil {
+		for _, m := range r.Steps {
+			if m == model {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseRoute interprets a route as a tier name or, failing that, a model name
+func parseRoute(route string) Selection {
+	switch {
+	case route == "":
+		return Selection{}
+	case isTier(ModelTier(route)):
+		return Selection{Tier: ModelTier(route)}
+	default:
+		return Selection{Model: route}
+	}
+}
+
+func isTier(tier ModelTier) bool {
+	return tier == TierLite || tier == TierStandard || tier == TierAdvanced
+}
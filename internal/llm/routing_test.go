@@ -0,0 +1,146 @@
+package llm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tierClient records the tier each call is made with
+type tierClient struct {
+	fakeClient
+	config *Config
+	tiers  []ModelTier
+}
+
+func (c *tierClient) GenerateContent(_ context.Context, _ string, tier ModelTier) (string, error) {
+	c.tiers = append(c.tiers, tier)
+	return "", nil
+}
+
+func (c *tierClient) GetModel(tier ModelTier) string {
+	return c.config.GetModel(tier)
+}
+
+func TestSelection_Resolve(t *testing.T) {
+	tests := []struct {
+		name      string
+		sel       Selection
+		requested ModelTier
+		want      ModelTier
+	}{
+		{"zero keeps caller tier", Selection{}, TierAdvanced, TierAdvanced},
+		{"tier replaces caller tier", Selection{Tier: TierLite}, TierAdvanced, TierLite},
+		{"model pins", Selection{Model: "custom"}, TierLite, tierPinned},
+		{"downgrade advanced", Selection{Downgrade: true}, TierAdvanced, TierStandard},
+		{"downgrade standard", Selection{Downgrade: true}, TierStandard, TierLite},
+		{"downgrade lite stays lite", Selection{Downgrade: true}, TierLite, TierLite},
+		{"downgrade routed tier", Selection{Tier: TierAdvanced, Downgrade: true}, TierLite, TierStandard},
+		{"downgrade ignores pinned model", Selection{Model: "custom", Downgrade: true}, TierAdvanced, TierStandard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.sel.resolve(tt.requested))
+		})
+	}
+}
+
+func TestRoutedClient(t *testing.T) {
+	inner := &tierClient{config: DefaultConfig().WithModel(tierPinned, "custom-model")}
+	client := &routedClient{Client: inner, sel: Selection{Model: "custom-model"}}
+
+	_, err := client.GenerateContent(context.Background(), "prompt", TierLite)
+	require.NoError(t, err)
+	assert.Equal(t, []ModelTier{tierPinned}, inner.tiers)
+	assert.Equal(t, "custom-model", client.GetModel(TierLite))
+}
+
+func TestRouting_Select(t *testing.T) {
+	r := &Routing{Steps: map[string]string{
+		"parse_job":       "lite",
+		"rewrite_bullets": "gemini-2.5-pro",
+	}}
+
+	sel, err := r.Select("parse_job", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, Selection{Tier: TierLite}, sel)
+
+	sel, err = r.Select("rewrite_bullets", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, Selection{Model: "gemini-2.5-pro", Downgrade: true}, sel)
+
+	sel, err = r.Select("rank_stories", "", false)
+	require.NoError(t, err)
+	assert.True(t, sel.IsZero())
+
+	// Overrides may name a tier or a model the routing already knows about
+	sel, err = r.Select("parse_job", "advanced", false)
+	require.NoError(t, err)
+	assert.Equal(t, Selection{Tier: TierAdvanced}, sel)
+
+	sel, err = r.Select("parse_job", "gemini-2.5-flash", false)
+	require.NoError(t, err)
+	assert.Equal(t, Selection{Model: "gemini-2.5-flash"}, sel)
+
+	_, err = r.Select("parse_job", "gpt-unknown", false)
+	assert.Error(t, err)
+}
+
+func TestRouting_NilSelect(t *testing.T) {
+	var r *Routing
+	sel, err := r.Select("parse_job", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, Selection{Downgrade: true}, sel)
+
+	sel, err = r.Select("parse_job", "standard", false)
+	require.NoError(t, err)
+	assert.Equal(t, Selection{Tier: TierStandard}, sel)
+	assert.False(t, r.LowQuota(1000))
+}
+
+func TestRouting_LowQuota(t *testing.T) {
+	r := &Routing{Quota: RoutingQuota{DailyRuns: 10, DowngradeBelow: 3}}
+	assert.False(t, r.LowQuota(0))
+	assert.False(t, r.LowQuota(7))
+	assert.True(t, r.LowQuota(8))
+	assert.True(t, r.LowQuota(12))
+
+	disabled := &Routing{}
+	assert.False(t, disabled.LowQuota(100))
+}
+
+func TestLoadRouting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "routing.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"steps": {"parse_job": "lite", "rewrite_bullets": "advanced"},
+		"quota": {"daily_runs": 20, "downgrade_below": 5}
+	}`), 0o644))
+
+	r, err := LoadRouting(path)
+	require.NoError(t, err)
+	assert.Equal(t, "lite", r.Steps["parse_job"])
+	assert.Equal(t, 20, r.Quota.DailyRuns)
+
+	bad := filepath.Join(dir, "bad.json")
+	require.NoError(t, os.WriteFile(bad, []byte(`{"quota": {"daily_runs": 2, "downgrade_below": 5}}`), 0o644))
+	_, err = LoadRouting(bad)
+	assert.Error(t, err)
+
+	_, err = LoadRouting(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestSelectionFromContext(t *testing.T) {
+	_, ok := SelectionFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := WithSelection(context.Background(), Selection{Tier: TierLite})
+	sel, ok := SelectionFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, TierLite, sel.Tier)
+}
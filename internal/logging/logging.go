@@ -0,0 +1,141 @@
+// Package logging configures the structured logger shared by the server, step workers,
+// and pipeline, and carries the IDs that tie log lines to a request or run.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+)
+
+// Correlation field names, the same in JSON and text output
+const (
+	KeyRequestID = "request_id"
+	KeyRunID     = "run_id"
+	KeyUserID    = "user_id"
+	KeyStep      = "step"
+)
+
+// verbose is where human-readable verbose output such as the pipeline's summary boxes
+// goes. It is discarded unless logs are text at debug level, so JSON logs stay parseable.
+var verbose io.Writer = io.Discard
+
+// New returns a logger writing cfg's format to w. Every record is annotated with the
+// correlation IDs carried by the context it is logged with.
+func New(w io.Writer, cfg *config.LoggingConfig) *slog.Logger {
+	var handler slog.Handler
+	if cfg.Format == config.LogFormatJSON {
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: cfg.Level})
+	} else {
+		handler = newTextHandler(w, cfg.Level)
+	}
+	return slog.New(contextHandler{handler})
+}
+
+// Setup makes a logger for cfg writing to stdout the default, so slog's top-level
+// functions and the standard log package both write through it
+func Setup(cfg *config.LoggingConfig) {
+	slog.SetDefault(New(os.Stdout, cfg))
+	verbose = io.Discard
+	if cfg.Format == config.LogFormatText && cfg.Level <= slog.LevelDebug {
+		verbose = os.Stdout
+	}
+}
+
+// Verbose returns where human-readable verbose output should be written
+func Verbose() io.Writer {
+	return verbose
+}
+
+// fieldsKey is the context key for fields
+type fieldsKey struct{}
+
+// fields are the correlation IDs a context carries
+type fields struct {
+	requestID string
+	runID     uuid.UUID
+	userID    uuid.UUID
+	step      string
+}
+
+func fieldsFrom(ctx context.Context) fields {
+	if ctx == nil {
+		return fields{}
+	}
+	f, _ := ctx.Value(fieldsKey{}).(fields)
+	return f
+}
+
+// WithRequestID returns a context whose log lines carry the API request's ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	f := fieldsFrom(ctx)
+	f.requestID = requestID
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// WithRunID returns a context whose log lines carry the run's ID
+func WithRunID(ctx context.Context, runID uuid.UUID) context.Context {
+	f := fieldsFrom(ctx)
+	f.runID = runID
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// WithUserID returns a context whose log lines carry the ID of the user acting
+func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	f := fieldsFrom(ctx)
+	f.userID = userID
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// WithStep returns a context whose log lines carry the pipeline step running
+func WithStep(ctx context.Context, step string) context.Context {
+	f := fieldsFrom(ctx)
+	f.step = step
+	return context.WithValue(ctx, fieldsKey{}, f)
+}
+
+// RequestID returns the request ID ctx carries, or "" if it carries none
+func RequestID(ctx context.Context) string {
+	return fieldsFrom(ctx).requestID
+}
+
+// attrs returns the correlation IDs ctx carries as log attributes
+func (f fields) attrs() []slog.Attr {
+	var attrs []slog.Attr
+	if f.requestID != "" {
+		attrs = append(attrs, slog.String(KeyRequestID, f.requestID))
+	}
+	if f.runID != uuid.Nil {
+		attrs = append(attrs, slog.String(KeyRunID, f.runID.String()))
+	}
+	if f.userID != uuid.Nil {
+		attrs = append(attrs, slog.String(KeyUserID, f.userID.String()))
+	}
+	if f.step != "" {
+		attrs = append(attrs, slog.String(KeyStep, f.step))
+	}
+	return attrs
+}
+
+// contextHandler adds the correlation IDs in a record's context to the record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(fieldsFrom(ctx).attrs()...)
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
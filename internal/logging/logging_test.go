@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+)
+
+func correlatedContext() (context.Context, uuid.UUID, uuid.UUID) {
+	runID, userID := uuid.New(), uuid.New()
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithUserID(ctx, userID)
+	ctx = WithRunID(ctx, runID)
+	ctx = WithStep(ctx, "rank_stories")
+	return ctx, runID, userID
+}
+
+func TestNew_JSONCarriesCorrelationIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, &config.LoggingConfig{Format: config.LogFormatJSON, Level: slog.LevelInfo})
+	ctx, runID, userID := correlatedContext()
+
+	logger.WarnContext(ctx, "Failed to start step tracking", KeyError, errors.New("timeout"))
+	logger.DebugContext(ctx, "not written below the configured level")
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line), "one JSON object: %s", buf.String())
+	assert.Equal(t, "WARN", line["level"])
+	assert.Equal(t, "Failed to start step tracking", line["msg"])
+	assert.Equal(t, "timeout", line[KeyError])
+	assert.Equal(t, "req-1", line[KeyRequestID])
+	assert.Equal(t, runID.String(), line[KeyRunID])
+	assert.Equal(t, userID.String(), line[KeyUserID])
+	assert.Equal(t, "rank_stories", line[KeyStep])
+}
+
+func TestNew_TextIsHumanReadable(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, &config.LoggingConfig{Format: config.LogFormatText, Level: slog.LevelDebug})
+	ctx, runID, _ := correlatedContext()
+
+	logger.InfoContext(ctx, "Step 4/12: Ranking stories...")
+	logger.WarnContext(ctx, "Failed to start step tracking", KeyError, errors.New("timeout"))
+	logger.DebugContext(context.Background(), "Connected to database", "pool", 4)
+	logger.WithGroup("fetch").Info("Fetched", "url", "https://example.com/a b")
+
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 4)
+	assert.Contains(t, string(lines[0]), " [rank_stories] Step 4/12: Ranking stories... request_id=req-1 run_id="+runID.String())
+	assert.Contains(t, string(lines[1]), " [rank_stories] Warning: Failed to start step tracking: timeout request_id=req-1")
+	assert.Contains(t, string(lines[2]), " [VERBOSE] Connected to database pool=4")
+	assert.Contains(t, string(lines[3]), ` Fetched fetch.url="https://example.com/a b"`)
+}
+
+func TestWithFields_DoNotLeakToParent(t *testing.T) {
+	parent := WithRequestID(context.Background(), "req-1")
+	child := WithStep(parent, "render_latex")
+
+	assert.Equal(t, "req-1", RequestID(child))
+	assert.Equal(t, []slog.Attr{slog.String(KeyRequestID, "req-1")}, fieldsFrom(parent).attrs())
+	assert.Empty(t, RequestID(context.Background()))
+}
@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// KeyError is the attribute name errors are logged under. Text output puts the error
+// after the message, the way the pipeline's messages always read.
+const KeyError = "error"
+
+// levelPrefixes start human-readable lines below or above info level
+var levelPrefixes = map[slog.Level]string{
+	slog.LevelDebug: "[VERBOSE] ",
+	slog.LevelWarn:  "Warning: ",
+	slog.LevelError: "Error: ",
+}
+
+// textHandler writes one human-readable line per record:
+//
+//	2026/01/02 15:04:05 [rank_stories] Warning: Failed to start step tracking: timeout run_id=...
+//
+// The step a line was logged in prefixes it, in place of the fixed-width branch
+// prefixes the pipeline used to print, and other attributes follow as key=value pairs.
+type textHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	level  slog.Leveler
+	attrs  []slog.Attr
+	prefix string // Group names, each followed by a dot
+}
+
+func newTextHandler(w io.Writer, level slog.Leveler) *textHandler {
+	return &textHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	var step, errText string
+	var pairs []string
+	add := func(prefix string, a slog.Attr) {
+		a.Value = a.Value.Resolve()
+		switch {
+		case a.Equal(slog.Attr{}):
+		case prefix == "" && a.Key == KeyStep:
+			step = a.Value.String()
+		case prefix == "" && a.Key == KeyError:
+			errText = a.Value.String()
+		default:
+			pairs = appendPairs(pairs, prefix+a.Key, a.Value)
+		}
+	}
+	for _, a := range h.attrs {
+		add("", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		add(h.prefix, a)
+		return true
+	})
+
+	var b strings.Builder
+	if !r.Time.IsZero() {
+		b.WriteString(r.Time.Format("2006/01/02 15:04:05 "))
+	}
+	if step != "" {
+		fmt.Fprintf(&b, "[%s] ", step)
+	}
+	b.WriteString(levelPrefix(r.Level))
+	b.WriteString(r.Message)
+	if errText != "" {
+		b.WriteString(": ")
+		b.WriteString(errText)
+	}
+	for _, pair := range pairs {
+		b.WriteByte(' ')
+		b.WriteString(pair)
+	}
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		clone.attrs = append(clone.attrs, a)
+	}
+	return &clone
+}
+
+func (h *textHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// levelPrefix returns the prefix for a level, using the nearest standard level at or below it
+func levelPrefix(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return levelPrefixes[slog.LevelError]
+	case level >= slog.LevelWarn:
+		return levelPrefixes[slog.LevelWarn]
+	case level >= slog.LevelInfo:
+		return ""
+	default:
+		return levelPrefixes[slog.LevelDebug]
+	}
+}
+
+// appendPairs appends key=value pairs for a value, flattening groups into dotted keys
+func appendPairs(pairs []string, key string, v slog.Value) []string {
+	if v.Kind() != slog.KindGroup {
+		return append(pairs, key+"="+quoteValue(v.String()))
+	}
+	for _, a := range v.Group() {
+		pairs = appendPairs(pairs, key+"."+a.Key, a.Value.Resolve())
+	}
+	return pairs
+}
+
+// quoteValue quotes values that would otherwise be ambiguous in a key=value line
+func quoteValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\n\r") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,105 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+)
+
+// Email is a plain-text email ready for delivery
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email. Notifications and account emails such as verification links
+// go through the same mailer.
+type Mailer interface {
+	SendEmail(ctx context.Context, email Email) error
+}
+
+// NewMailer returns the mailer cfg selects: the console mailer writing to log when
+// cfg.Mailer is "console", otherwise SMTP
+func NewMailer(cfg *config.NotificationConfig, log io.Writer) Mailer {
+	if cfg != nil && cfg.Mailer == config.MailerConsole {
+		return NewConsoleMailer(log)
+	}
+	return NewSMTPMailer(cfg)
+}
+
+// SMTPMailer sends email through the configured SMTP server
+type SMTPMailer struct {
+	cfg      *config.NotificationConfig
+	sendMail sendMailFunc
+}
+
+// NewSMTPMailer creates a mailer for cfg's SMTP server
+func NewSMTPMailer(cfg *config.NotificationConfig) *SMTPMailer {
+	if cfg == nil {
+		cfg = &config.NotificationConfig{}
+	}
+	return &SMTPMailer{cfg: cfg, sendMail: smtp.SendMail}
+}
+
+// SendEmail sends email as a plain-text message
+func (m *SMTPMailer) SendEmail(_ context.Context, email Email) error {
+	if m.cfg.SMTPHost == "" {
+		return fmt.Errorf("email is not configured (set SMTP_HOST)")
+	}
+	if email.To == "" {
+		return fmt.Errorf("recipient has no email address")
+	}
+
+	var auth smtp.Auth
+	if m.cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", m.cfg.SMTPUsername, m.cfg.SMTPPassword, m.cfg.SMTPHost)
+	}
+
+	addr := m.cfg.SMTPHost + ":" + strconv.Itoa(m.cfg.SMTPPort)
+	if err := m.sendMail(addr, auth, m.cfg.FromAddress, []string{email.To}, formatEmail(m.cfg.FromAddress, email)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// ConsoleMailer writes email to a log instead of sending it, so links in account emails
+// can be followed during local development without a mail server
+type ConsoleMailer struct {
+	mu  sync.Mutex
+	log io.Writer
+}
+
+// NewConsoleMailer creates a mailer that writes each email to log
+func NewConsoleMailer(log io.Writer) *ConsoleMailer {
+	return &ConsoleMailer{log: log}
+}
+
+// SendEmail writes email to the log
+func (m *ConsoleMailer) SendEmail(_ context.Context, email Email) error {
+	if email.To == "" {
+		return fmt.Errorf("recipient has no email address")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := fmt.Fprintf(m.log, "----- email to %s -----\nSubject: %s\n\n%s\n-----\n", email.To, email.Subject, email.Body)
+	return err
+}
+
+// formatEmail writes email as an RFC 5322 message from the given sender
+func formatEmail(from string, email Email) []byte {
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", from)
+	fmt.Fprintf(&body, "To: %s\r\n", email.To)
+	fmt.Fprintf(&body, "Subject: %s\r\n", encodeHeader(email.Subject))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	body.WriteString(strings.ReplaceAll(email.Body, "\n", "\r\n"))
+	return []byte(body.String())
+}
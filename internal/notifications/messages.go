@@ -0,0 +1,167 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/calendar"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// RunCompletedMessage builds the notification sent when a user's pipeline run finishes
+func RunCompletedMessage(run *db.Run) Message {
+	role := run.RoleTitle
+	if role == "" {
+		role = "your target role"
+	}
+	company := run.Company
+	if company == "" {
+		company = "an unknown company"
+	}
+
+	return Message{
+		Event:   db.NotificationRunCompleted,
+		Subject: fmt.Sprintf("Your resume for %s at %s is ready", role, company),
+		Body: fmt.Sprintf("Your tailored resume for %s at %s has finished (run %s, status: %s).\n",
+			role, company, run.ID, run.Status),
+		Data: run,
+	}
+}
+
+// ReminderMessage builds the notification for an upcoming application deadline, follow-up
+// date, or tracker next action, giving the due time in the recipient's time zone
+func ReminderMessage(reminder *db.ApplicationReminder) Message {
+	due := calendar.FormatDue(reminder.DueAt, reminder.Recipient.Location())
+	if reminder.Kind == db.ReminderNextAction {
+		return nextActionMessage(reminder, due)
+	}
+	summary := calendar.EventSummary(reminder.Kind, reminder.Company, reminder.RoleTitle)
+
+	var body strings.Builder
+	if reminder.Kind == db.ReminderFollowUp {
+		fmt.Fprintf(&body, "Time to follow up on your application: %s.\n", summary)
+	} else {
+		fmt.Fprintf(&body, "Your application deadline is coming up: %s.\n", summary)
+	}
+	fmt.Fprintf(&body, "Due: %s\n", due)
+	if reminder.JobURL != "" {
+		fmt.Fprintf(&body, "Job posting: %s\n", reminder.JobURL)
+	}
+
+	return Message{
+		Event:   db.NotificationReminder,
+		Subject: fmt.Sprintf("Reminder: %s (%s)", summary, due),
+		Body:    body.String(),
+		Data: map[string]any{
+			"run_id": reminder.RefID,
+			"kind":   reminder.Kind,
+			"due_at": reminder.DueAt,
+		},
+	}
+}
+
+// nextActionMessage builds the reminder for the next action on an application tracker
+func nextActionMessage(reminder *db.ApplicationReminder, due string) Message {
+	action := reminder.Action
+	if action == "" {
+		action = "Next step"
+	}
+	return Message{
+		Event:   db.NotificationReminder,
+		Subject: fmt.Sprintf("Reminder: %s at %s (%s)", action, reminder.Company, due),
+		Body:    fmt.Sprintf("Your next step on your application to %s is coming up: %s.\nDue: %s\n", reminder.Company, action, due),
+		Data: map[string]any{
+			"tracker_id": reminder.RefID,
+			"company":    reminder.Company,
+			"kind":       reminder.Kind,
+			"due_at":     reminder.DueAt,
+		},
+	}
+}
+
+// DigestMessage builds the periodic activity digest for a user, writing dates in loc
+func DigestMessage(name string, stats *db.DigestStats, loc *time.Location) Message {
+	var body strings.Builder
+	if name != "" {
+		fmt.Fprintf(&body, "Hi %s,\n\n", name)
+	}
+	fmt.Fprintf(&body, "Here is your activity since %s:\n\n", stats.Since.In(loc).Format("Jan 2, 2006"))
+	fmt.Fprintf(&body, "- %s matched at companies you targeted\n", plural(stats.PostingsMatched, "new posting"))
+	fmt.Fprintf(&body, "- %s refreshed\n", plural(stats.ProfilesRefreshed, "company profile"))
+	fmt.Fprintf(&body, "- %s completed\n", plural(stats.RunsCompleted, "run"))
+
+	if len(stats.RecentRuns) > 0 {
+		body.WriteString("\nRecent runs:\n")
+		for _, run := range stats.RecentRuns {
+			fmt.Fprintf(&body, "- %s at %s (%s)\n", run.RoleTitle, run.Company, run.CompletedAt.In(loc).Format("Jan 2"))
+		}
+	}
+
+	subject := fmt.Sprintf("Your resume digest: %s, %s",
+		plural(stats.PostingsMatched, "new posting"), plural(stats.RunsCompleted, "run"))
+	return Message{
+		Event:   db.NotificationWeeklyDigest,
+		Subject: subject,
+		Body:    body.String(),
+		Data:    stats,
+	}
+}
+
+// ResumeHealthMessage builds the periodic resume health report for a user
+func ResumeHealthMessage(name string, health *db.ResumeHealth) Message {
+	var body strings.Builder
+	if name != "" {
+		fmt.Fprintf(&body, "Hi %s,\n\n", name)
+	}
+	fmt.Fprintf(&body, "Here is how your experience bank of %s is doing:\n\n", plural(health.Bullets, "bullet"))
+	for _, role := range health.Roles {
+		fmt.Fprintf(&body, "- %s: %.0f%% of %s covered across %s\n", role.RoleFamily,
+			role.Coverage*100, plural(role.Requirements, "required skill"), plural(role.Postings, "posting"))
+	}
+	fmt.Fprintf(&body, "- %s not updated in over a year\n", plural(len(health.StaleBullets), "bullet"))
+	fmt.Fprintf(&body, "- %s without a metric\n", plural(len(health.MissingMetrics), "bullet"))
+
+	if len(health.Suggestions) > 0 {
+		body.WriteString("\nSuggested improvements:\n")
+		for _, suggestion := range health.Suggestions {
+			fmt.Fprintf(&body, "- %s\n", suggestion)
+		}
+	}
+
+	subject := "Your resume health report"
+	if len(health.Roles) > 0 {
+		top := health.Roles[0]
+		subject = fmt.Sprintf("Your resume health report: %.0f%% %s coverage", top.Coverage*100, top.RoleFamily)
+	}
+	return Message{
+		Event:   db.NotificationResumeHealth,
+		Subject: subject,
+		Body:    body.String(),
+		Data:    health,
+	}
+}
+
+// plural formats a count with a naively pluralized noun
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// VerificationEmail builds the email asking a newly registered user to confirm their
+// address by following link, which stays valid for validFor
+func VerificationEmail(to, name, link string, validFor time.Duration) Email {
+	greeting := "Hi"
+	if name != "" {
+		greeting = "Hi " + name
+	}
+	return Email{
+		To:      to,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("%s,\n\nConfirm this is your email address to start tailoring resumes:\n\n%s\n\n"+
+			"The link expires in %d hours. If you did not create an account, ignore this email.\n",
+			greeting, link, int(validFor.Hours())),
+	}
+}
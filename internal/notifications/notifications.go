@@ -0,0 +1,173 @@
+// Package notifications delivers user notifications by email or webhook.
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// webhookTimeout bounds a single webhook delivery
+const webhookTimeout = 10 * time.Second
+
+// Message is a notification ready for delivery on any channel
+type Message struct {
+	Event   string `json:"event"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// sendMailFunc matches smtp.SendMail so tests can capture outgoing mail
+type sendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// Notifier delivers messages to recipients on their preferred channel
+type Notifier struct {
+	cfg      *config.NotificationConfig
+	client   *http.Client
+	mailer   Mailer
+	lookupIP func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// New creates a notifier. Email delivery requires cfg.SMTPHost or the console mailer;
+// webhooks always work.
+// Webhooks may only reach public addresses unless cfg.AllowPrivateWebhooks is set.
+func New(cfg *config.NotificationConfig) *Notifier {
+	if cfg == nil {
+		cfg = &config.NotificationConfig{}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.AllowPrivateWebhooks {
+		// Check the address actually dialed, after DNS, so a hostname can't be
+		// re-pointed at an internal service. A proxy would hide that address.
+		dialer := &net.Dialer{Timeout: webhookTimeout, Control: publicDialControl}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil
+	}
+	return &Notifier{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: webhookTimeout, Transport: transport},
+		mailer:   NewMailer(cfg, log.Writer()),
+		lookupIP: net.DefaultResolver.LookupIPAddr,
+	}
+}
+
+// CheckWebhookURL resolves a webhook URL's host and rejects it when any of its
+// addresses is loopback, private, or link-local. Delivery checks again when dialing.
+func (n *Notifier) CheckWebhookURL(ctx context.Context, rawURL string) error {
+	if n.cfg.AllowPrivateWebhooks {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("webhook_url is not a valid URL")
+	}
+	addrs, err := n.lookupIP(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("webhook_url host %s cannot be resolved", u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return fmt.Errorf("webhook_url host %s resolves to a non-public address", u.Hostname())
+		}
+	}
+	return nil
+}
+
+// publicIP reports whether ip is routable on the public internet
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// publicDialControl refuses connections to non-public addresses
+func publicDialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("webhook address %s is not public", host)
+	}
+	return nil
+}
+
+// Send delivers msg to the recipient's channel. Recipients on the "none" channel are skipped.
+func (n *Notifier) Send(ctx context.Context, recipient *db.NotificationRecipient, msg Message) error {
+	switch recipient.Channel {
+	case db.NotificationEmail:
+		return n.sendEmail(ctx, recipient.Email, msg)
+	case db.NotificationWebhook:
+		if recipient.WebhookURL == nil || *recipient.WebhookURL == "" {
+			return fmt.Errorf("webhook channel has no URL for user %s", recipient.UserID)
+		}
+		return n.sendWebhook(ctx, *recipient.WebhookURL, msg)
+	case db.NotificationNone, "":
+		return nil
+	default:
+		return fmt.Errorf("unknown notification channel: %s", recipient.Channel)
+	}
+}
+
+// sendEmail sends msg as a plain-text email
+func (n *Notifier) sendEmail(ctx context.Context, to string, msg Message) error {
+	if !n.cfg.EmailEnabled() {
+		return fmt.Errorf("email notifications are not configured (set SMTP_HOST)")
+	}
+	return n.mailer.SendEmail(ctx, Email{To: to, Subject: msg.Subject, Body: msg.Body})
+}
+
+// encodeHeader folds line breaks out of a header value and encodes non-ASCII text,
+// so a company or role name can't inject extra headers
+func encodeHeader(value string) string {
+	value = strings.Join(strings.FieldsFunc(value, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+	return mime.QEncoding.Encode("utf-8", value)
+}
+
+// sendWebhook POSTs msg as JSON to url
+func (n *Notifier) sendWebhook(ctx context.Context, url string, msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+	_, err = n.PostWebhook(ctx, url, payload, nil)
+	return err
+}
+
+// PostWebhook POSTs a JSON payload to url with header added, under the same address
+// checks as notification webhooks. It returns the response status, or 0 when the
+// endpoint did not answer, and an error unless the status is 2xx.
+func (n *Notifier) PostWebhook(ctx context.Context, url string, payload []byte, header http.Header) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,230 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// allowPrivate lets webhooks reach httptest servers on loopback
+var allowPrivate = &config.NotificationConfig{AllowPrivateWebhooks: true}
+
+func TestSend_Webhook(t *testing.T) {
+	var got Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	url := srv.URL
+	recipient := &db.NotificationRecipient{UserID: uuid.New(), Channel: db.NotificationWebhook, WebhookURL: &url}
+	err := New(allowPrivate).Send(context.Background(), recipient, Message{Event: db.NotificationRunCompleted, Subject: "done"})
+	require.NoError(t, err)
+	assert.Equal(t, db.NotificationRunCompleted, got.Event)
+	assert.Equal(t, "done", got.Subject)
+}
+
+func TestSend_WebhookErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	url := srv.URL
+	recipient := &db.NotificationRecipient{Channel: db.NotificationWebhook, WebhookURL: &url}
+	err := New(allowPrivate).Send(context.Background(), recipient, Message{})
+	assert.ErrorContains(t, err, "status 500")
+}
+
+func TestSend_WebhookPrivateAddress(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	url := srv.URL
+	recipient := &db.NotificationRecipient{Channel: db.NotificationWebhook, WebhookURL: &url}
+	err := New(nil).Send(context.Background(), recipient, Message{})
+	assert.ErrorContains(t, err, "not public")
+	assert.False(t, called)
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	n := New(nil)
+	n.lookupIP = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "hooks.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+		case "internal.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.0.0.5")}}, nil
+		}
+		return nil, fmt.Errorf("no such host")
+	}
+
+	assert.NoError(t, n.CheckWebhookURL(context.Background(), "https://hooks.example.com/x"))
+	for _, url := range []string{
+		"https://internal.example.com/x",
+		"https://127.0.0.1/x",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/x",
+		"https://unknown.example.com/x",
+	} {
+		assert.Error(t, n.CheckWebhookURL(context.Background(), url), url)
+	}
+	assert.NoError(t, New(allowPrivate).CheckWebhookURL(context.Background(), "https://127.0.0.1/x"))
+}
+
+func TestSend_Email(t *testing.T) {
+	n := New(&config.NotificationConfig{SMTPHost: "smtp.example.com", SMTPPort: 587, FromAddress: "noreply@example.com"})
+	var addr string
+	var to []string
+	var raw string
+	n.mailer.(*SMTPMailer).sendMail = func(a string, _ smtp.Auth, _ string, rcpt []string, msg []byte) error {
+		addr, to, raw = a, rcpt, string(msg)
+		return nil
+	}
+
+	recipient := &db.NotificationRecipient{Channel: db.NotificationEmail, Email: "jane@example.com"}
+	require.NoError(t, n.Send(context.Background(), recipient, Message{Subject: "Hello", Body: "line one\nline two"}))
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.Equal(t, []string{"jane@example.com"}, to)
+	assert.Contains(t, raw, "Subject: Hello\r\n")
+	assert.Contains(t, raw, "line one\r\nline two")
+}
+
+func TestSend_EmailSubjectInjection(t *testing.T) {
+	n := New(&config.NotificationConfig{SMTPHost: "smtp.example.com", SMTPPort: 587, FromAddress: "noreply@example.com"})
+	var raw string
+	n.mailer.(*SMTPMailer).sendMail = func(_ string, _ smtp.Auth, _ string, _ []string, msg []byte) error {
+		raw = string(msg)
+		return nil
+	}
+
+	recipient := &db.NotificationRecipient{Channel: db.NotificationEmail, Email: "jane@example.com"}
+	require.NoError(t, n.Send(context.Background(), recipient, Message{Subject: "Ready\r\nBcc: attacker@example.com"}))
+	assert.Contains(t, raw, "Subject: Ready Bcc: attacker@example.com\r\n")
+	assert.NotContains(t, raw, "\r\nBcc:")
+
+	require.NoError(t, n.Send(context.Background(), recipient, Message{Subject: "Résumé ready"}))
+	assert.Contains(t, raw, "Subject: =?utf-8?q?R=C3=A9sum=C3=A9_ready?=\r\n")
+}
+
+func TestSend_EmailDisabled(t *testing.T) {
+	recipient := &db.NotificationRecipient{Channel: db.NotificationEmail, Email: "jane@example.com"}
+	err := New(nil).Send(context.Background(), recipient, Message{})
+	assert.ErrorContains(t, err, "SMTP_HOST")
+}
+
+func TestSend_ConsoleMailer(t *testing.T) {
+	var out strings.Builder
+	n := New(&config.NotificationConfig{Mailer: config.MailerConsole})
+	n.mailer = NewConsoleMailer(&out)
+
+	recipient := &db.NotificationRecipient{Channel: db.NotificationEmail, Email: "jane@example.com"}
+	require.NoError(t, n.Send(context.Background(), recipient, Message{Subject: "Hello", Body: "line one"}))
+	assert.Contains(t, out.String(), "email to jane@example.com")
+	assert.Contains(t, out.String(), "Subject: Hello\n\nline one")
+}
+
+func TestSend_None(t *testing.T) {
+	recipient := &db.NotificationRecipient{Channel: db.NotificationNone}
+	assert.NoError(t, New(nil).Send(context.Background(), recipient, Message{}))
+}
+
+func TestDigestMessage(t *testing.T) {
+	stats := &db.DigestStats{
+		Since:             time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		RunsCompleted:     2,
+		ProfilesRefreshed: 1,
+		PostingsMatched:   0,
+		RecentRuns: []db.DigestRun{
+			{Company: "Acme", RoleTitle: "Backend Engineer", CompletedAt: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	msg := DigestMessage("Jane", stats, time.UTC)
+	assert.Equal(t, db.NotificationWeeklyDigest, msg.Event)
+	assert.Equal(t, "Your resume digest: 0 new postings, 2 runs", msg.Subject)
+	assert.Contains(t, msg.Body, "Hi Jane,")
+	assert.Contains(t, msg.Body, "since Mar 1, 2026")
+	assert.Contains(t, msg.Body, "- 1 company profile refreshed")
+	assert.Contains(t, msg.Body, "- Backend Engineer at Acme (Mar 3)")
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	stats.RecentRuns[0].CompletedAt = time.Date(2026, 3, 3, 20, 0, 0, 0, time.UTC)
+	msg = DigestMessage("Jane", stats, tokyo)
+	assert.Contains(t, msg.Body, "- Backend Engineer at Acme (Mar 4)", "dates are in the user's time zone")
+}
+
+func TestResumeHealthMessage(t *testing.T) {
+	health := &db.ResumeHealth{
+		Bullets: 12,
+		Roles: []db.RoleCoverage{
+			{RoleFamily: "software engineer", Postings: 3, Requirements: 8, Matched: 5, Coverage: 0.63},
+		},
+		StaleBullets:   []db.HealthBullet{{BulletID: "b2"}},
+		MissingMetrics: []db.HealthBullet{{BulletID: "b2"}, {BulletID: "b7"}},
+		Suggestions:    []string{"Add a bullet showing Kafka, required by 2 of your 3 software engineer postings."},
+	}
+
+	msg := ResumeHealthMessage("Jane", health)
+	assert.Equal(t, db.NotificationResumeHealth, msg.Event)
+	assert.Equal(t, "Your resume health report: 63% software engineer coverage", msg.Subject)
+	assert.Contains(t, msg.Body, "experience bank of 12 bullets")
+	assert.Contains(t, msg.Body, "- software engineer: 63% of 8 required skills covered across 3 postings")
+	assert.Contains(t, msg.Body, "- 1 bullet not updated in over a year")
+	assert.Contains(t, msg.Body, "- 2 bullets without a metric")
+	assert.Contains(t, msg.Body, "- Add a bullet showing Kafka")
+}
+
+func TestRunCompletedMessage(t *testing.T) {
+	run := &db.Run{ID: uuid.New(), Company: "Acme", RoleTitle: "SRE", Status: "completed"}
+	msg := RunCompletedMessage(run)
+	assert.Equal(t, "Your resume for SRE at Acme is ready", msg.Subject)
+	assert.Contains(t, msg.Body, run.ID.String())
+}
+
+func TestReminderMessage(t *testing.T) {
+	reminder := &db.ApplicationReminder{
+		RefID:     uuid.New(),
+		Company:   "Acme",
+		RoleTitle: "SRE",
+		JobURL:    "https://jobs.acme.com/1",
+		Kind:      db.ReminderFollowUp,
+		DueAt:     time.Date(2026, 3, 24, 9, 30, 0, 0, time.UTC),
+	}
+
+	msg := ReminderMessage(reminder)
+	assert.Equal(t, db.NotificationReminder, msg.Event)
+	assert.Equal(t, "Reminder: Follow up: SRE at Acme (Tue Mar 24, 09:30 UTC)", msg.Subject)
+	assert.Contains(t, msg.Body, "Time to follow up")
+	assert.Contains(t, msg.Body, "https://jobs.acme.com/1")
+
+	reminder.Recipient.Timezone = "America/Los_Angeles"
+	msg = ReminderMessage(reminder)
+	assert.Equal(t, "Reminder: Follow up: SRE at Acme (Tue Mar 24, 02:30 PDT)", msg.Subject)
+
+	reminder.Kind = db.ReminderNextAction
+	reminder.Action = "Send thank-you note"
+	msg = ReminderMessage(reminder)
+	assert.Equal(t, "Reminder: Send thank-you note at Acme (Tue Mar 24, 02:30 PDT)", msg.Subject)
+	assert.Equal(t, reminder.RefID, msg.Data.(map[string]any)["tracker_id"])
+}
@@ -0,0 +1,106 @@
+// Package onboarding defines the first-run wizard a new user is guided through: upload a
+// resume, confirm the experience bank parsed from it, set contact info, and try a sample run.
+package onboarding
+
+// Wizard steps, in order, and the final state
+const (
+	StepUploadResume = "upload_resume" // Import a resume so jobs and bullets exist
+	StepConfirmBank  = "confirm_bank"  // Review the parsed experience bank
+	StepContactInfo  = "contact_info"  // Set the name and email shown on resumes
+	StepSampleRun    = "sample_run"    // Tailor a resume to one job posting
+	StateCompleted   = "completed"
+)
+
+// Steps lists the wizard steps in order
+var Steps = []string{StepUploadResume, StepConfirmBank, StepContactInfo, StepSampleRun}
+
+// Initial is the state of a user who has not started onboarding
+const Initial = StepUploadResume
+
+// Step statuses reported by Progress
+const (
+	StatusDone    = "done"
+	StatusCurrent = "current"
+	StatusPending = "pending"
+	StatusSkipped = "skipped"
+)
+
+// skippable holds the steps a user may skip; the rest must be finished
+var skippable = map[string]bool{StepSampleRun: true}
+
+// Valid reports whether state is a wizard step or the completed state
+func Valid(state string) bool {
+	return state == StateCompleted || index(state) >= 0
+}
+
+// Next returns the state after state, or false if state is completed or unknown
+func Next(state string) (string, bool) {
+	i := index(state)
+	switch {
+	case i < 0:
+		return "", false
+	case i == len(Steps)-1:
+		return StateCompleted, true
+	default:
+		return Steps[i+1], true
+	}
+}
+
+// Prev returns the step before state, or false at the first step. A completed wizard
+// cannot go back.
+func Prev(state string) (string, bool) {
+	i := index(state)
+	if i <= 0 {
+		return "", false
+	}
+	return Steps[i-1], true
+}
+
+// Skippable reports whether the user may skip state without finishing it
+func Skippable(state string) bool {
+	return skippable[state]
+}
+
+// StepStatus is one step of the wizard as shown to the user
+type StepStatus struct {
+	Step      string `json:"step"`
+	Status    string `json:"status"` // done, skipped, current, or pending
+	Skippable bool   `json:"skippable"`
+}
+
+// Progress lists every step with its status for a user in state. Steps in skipped
+// were passed over rather than finished.
+func Progress(state string, skipped []string) []StepStatus {
+	current := index(state)
+	if state == StateCompleted {
+		current = len(Steps)
+	}
+	wasSkipped := make(map[string]bool, len(skipped))
+	for _, s := range skipped {
+		wasSkipped[s] = true
+	}
+
+	progress := make([]StepStatus, len(Steps))
+	for i, step := range Steps {
+		status := StatusPending
+		switch {
+		case i < current && wasSkipped[step]:
+			status = StatusSkipped
+		case i < current:
+			status = StatusDone
+		case i == current:
+			status = StatusCurrent
+		}
+		progress[i] = StepStatus{Step: step, Status: status, Skippable: Skippable(step)}
+	}
+	return progress
+}
+
+func index(state string) int {
+	for i, step := range Steps {
+		if step == state {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,49 @@
+package onboarding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransitions(t *testing.T) {
+	state := Initial
+	var visited []string
+	for {
+		visited = append(visited, state)
+		next, ok := Next(state)
+		if !ok {
+			break
+		}
+		state = next
+	}
+	assert.Equal(t, []string{StepUploadResume, StepConfirmBank, StepContactInfo, StepSampleRun, StateCompleted}, visited)
+
+	prev, ok := Prev(StepContactInfo)
+	assert.True(t, ok)
+	assert.Equal(t, StepConfirmBank, prev)
+	_, ok = Prev(StepUploadResume)
+	assert.False(t, ok)
+	_, ok = Prev(StateCompleted)
+	assert.False(t, ok)
+	_, ok = Next("bogus")
+	assert.False(t, ok)
+
+	assert.True(t, Valid(StateCompleted))
+	assert.False(t, Valid("bogus"))
+	assert.True(t, Skippable(StepSampleRun))
+	assert.False(t, Skippable(StepConfirmBank))
+}
+
+func TestProgress(t *testing.T) {
+	assert.Equal(t, []StepStatus{
+		{Step: StepUploadResume, Status: StatusDone},
+		{Step: StepConfirmBank, Status: StatusDone},
+		{Step: StepContactInfo, Status: StatusCurrent},
+		{Step: StepSampleRun, Status: StatusPending, Skippable: true},
+	}, Progress(StepContactInfo, nil))
+
+	progress := Progress(StateCompleted, []string{StepSampleRun})
+	assert.Equal(t, StatusDone, progress[2].Status)
+	assert.Equal(t, StatusSkipped, progress[3].Status)
+}
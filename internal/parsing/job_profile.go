@@ -14,7 +14,7 @@ import (
 
 // ParseJobProfile extracts a structured JobProfile from cleaned job posting text
 func ParseJobProfile(ctx context.Context, cleanedText string, apiKey string) (*types.JobProfile, error) {
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, &APICallError{Message: "API key is required"}
 	}
 
@@ -29,8 +29,8 @@ func ParseJobProfile(ctx context.Context, cleanedText string, apiKey string) (*t
 	}
 	defer func() { _ = client.Close() }()
 
-	// Construct extraction prompt
-	prompt := buildExtractionPrompt(cleanedText)
+	// Construct extraction prompt; how the response turns out is reported to any prompt rollout
+	prompt, sel := buildExtractionPrompt(cleanedText)
 
 	// Use TierAdvanced for structured job parsing (requires reasoning)
 	responseText, err := client.GenerateContent(ctx, prompt, llm.TierAdvanced)
@@ -47,23 +47,27 @@ func ParseJobProfile(ctx context.Context, cleanedText string, apiKey string) (*t
 	// Parse JSON response
 	profile, err := parseJSONResponse(responseText)
 	if err != nil {
+		sel.Report(ctx, prompts.OutcomeParseFailure)
 		return nil, err
 	}
 
 	// Post-process the profile
 	if err := postProcessProfile(profile); err != nil {
+		sel.Report(ctx, prompts.OutcomeViolation)
 		return nil, err
 	}
 
+	sel.Report(ctx, prompts.OutcomeOK)
 	return profile, nil
 }
 
-// buildExtractionPrompt constructs the prompt for structured extraction
-func buildExtractionPrompt(jobText string) string {
-	template := prompts.MustGet("parsing.json", "extract-job-profile")
-	return prompts.Format(template, map[string]string{
+// buildExtractionPrompt constructs the prompt for structured extraction from the
+// prompt version in service
+func buildExtractionPrompt(jobText string) (string, prompts.Selection) {
+	sel := prompts.MustSelect("parsing.json", "extract-job-profile")
+	return prompts.Format(sel.Text, map[string]string{
 		"JobText": jobText,
-	})
+	}), sel
 }
 
 // cleanJSONBlock removes markdown code block wrappers from JSON
@@ -145,7 +149,7 @@ func postProcessProfile(profile *types.JobProfile) error {
 // ExtractEducationRequirements extracts education requirements from job posting text.
 // This is called separately from ParseJobProfile to allow for graceful degradation.
 func ExtractEducationRequirements(ctx context.Context, jobText string, apiKey string) (*types.EducationRequirements, error) {
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, &APICallError{Message: "API key is required"}
 	}
 
@@ -243,7 +243,7 @@ func TestPostProcessProfile(t *testing.T) {
 
 func TestBuildExtractionPrompt(t *testing.T) {
 	jobText := "We are looking for a Senior Engineer with Go experience."
-	prompt := buildExtractionPrompt(jobText)
+	prompt, _ := buildExtractionPrompt(jobText)
 
 	// Verify prompt contains key elements
 	assert.Contains(t, prompt, jobText, "should include job text")
@@ -0,0 +1,196 @@
+package perf
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/selection"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Input is what the measured steps read, prepared once per bank size
+type Input struct {
+	Size    Size
+	Profile *types.JobProfile
+	Bank    *types.ExperienceBank
+	Ranked  *types.RankedStories // Selection's input, ranked once up front
+	Budget  types.SpaceBudget
+}
+
+// NewInput builds the synthetic bank and job profile for size and ranks the bank
+func NewInput(size Size) (*Input, error) {
+	in := &Input{Size: size, Profile: JobProfile(), Bank: Bank(size, 1), Budget: DefaultBudget}
+	ranked, err := ranking.RankStories(in.Profile, in.Bank)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank %s bank: %w", size.Name, err)
+	}
+	in.Ranked = ranked
+	return in, nil
+}
+
+// Operation is one measured step
+type Operation struct {
+	Name string
+	Run  func(in *Input) error
+}
+
+// Operations are the steps measured by the benchmarks and the harness
+var Operations = []Operation{
+	{Name: "RankStories", Run: func(in *Input) error {
+		_, err := ranking.RankStories(in.Profile, in.Bank)
+		return err
+	}},
+	{Name: "SelectPlan", Run: func(in *Input) error {
+		budget := in.Budget
+		_, err := selection.SelectPlan(in.Ranked, in.Profile, in.Bank, &budget)
+		return err
+	}},
+}
+
+// Result is one operation measured on one bank size
+type Result struct {
+	Name        string `json:"name"` // Operation/size, as benchmarks name it
+	Bullets     int    `json:"bullets"`
+	Iterations  int    `json:"iterations"`
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp int64  `json:"allocs_per_op"`
+	BytesPerOp  int64  `json:"bytes_per_op"`
+}
+
+// Report is one harness run, saved as a baseline for later runs to be compared with
+type Report struct {
+	GoVersion string   `json:"go_version"`
+	Platform  string   `json:"platform"`
+	Results   []Result `json:"results"`
+}
+
+// Run measures every operation on each size, each for about a second
+func Run(sizes []Size) (*Report, error) {
+	report := &Report{GoVersion: runtime.Version(), Platform: runtime.GOOS + "/" + runtime.GOARCH}
+	for _, size := range sizes {
+		in, err := NewInput(size)
+		if err != nil {
+			return nil, err
+		}
+		for _, op := range Operations {
+			// Fail before timing rather than benchmarking an error path
+			if err := op.Run(in); err != nil {
+				return nil, fmt.Errorf("%s failed on the %s bank: %w", op.Name, size.Name, err)
+			}
+			res := testing.Benchmark(func(b *testing.B) {
+				b.ReportAllocs()
+				for range b.N {
+					_ = op.Run(in)
+				}
+			})
+			report.Results = append(report.Results, Result{
+				Name:        op.Name + "/" + size.Name,
+				Bullets:     size.Bullets(),
+				Iterations:  res.N,
+				NsPerOp:     res.NsPerOp(),
+				AllocsPerOp: res.AllocsPerOp(),
+				BytesPerOp:  res.AllocedBytesPerOp(),
+			})
+		}
+	}
+	return report, nil
+}
+
+// Regression is a result worse than its baseline by more than the tolerance
+type Regression struct {
+	Name     string  `json:"name"`
+	Metric   string  `json:"metric"` // ns/op or allocs/op
+	Baseline int64   `json:"baseline"`
+	Current  int64   `json:"current"`
+	Ratio    float64 `json:"ratio"` // Current over baseline
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s %s: %d -> %d (%.2fx)", r.Name, r.Metric, r.Baseline, r.Current, r.Ratio)
+}
+
+// Compare returns the results in current that take more time or allocations than in
+// baseline by more than tolerance (0.25 allows 25% more). Results missing from either
+// report are not compared. Times only compare meaningfully on the machine the
+// baseline was saved on; allocations compare anywhere.
+func Compare(baseline, current *Report, tolerance float64) []Regression {
+	base := make(map[string]Result, len(baseline.Results))
+	for _, r := range baseline.Results {
+		base[r.Name] = r
+	}
+	var regressions []Regression
+	for _, cur := range current.Results {
+		old, ok := base[cur.Name]
+		if !ok {
+			continue
+		}
+		for _, m := range []struct {
+			metric        string
+			before, after int64
+		}{
+			{"ns/op", old.NsPerOp, cur.NsPerOp},
+			{"allocs/op", old.AllocsPerOp, cur.AllocsPerOp},
+		} {
+			if m.before <= 0 {
+				continue
+			}
+			ratio := float64(m.after) / float64(m.before)
+			if ratio > 1+tolerance {
+				regressions = append(regressions, Regression{Name: cur.Name, Metric: m.metric, Baseline: m.before, Current: m.after, Ratio: ratio})
+			}
+		}
+	}
+	return regressions
+}
+
+// LoadReport reads a report saved with SaveReport
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return &report, nil
+}
+
+// SaveReport writes report as indented JSON
+func SaveReport(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// SizesByName returns the default sizes with the given names, in the order given
+func SizesByName(names []string) ([]Size, error) {
+	sizes := make([]Size, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, s := range Sizes {
+			if s.Name == name {
+				sizes = append(sizes, s)
+				found = true
+				break
+			}
+		}
+		if !found {
+			valid := make([]string, 0, len(Sizes))
+			for _, s := range Sizes {
+				valid = append(valid, s.Name)
+			}
+			return nil, fmt.Errorf("unknown bank size %q (must be one of %v)", name, valid)
+		}
+	}
+	return sizes, nil
+}
@@ -0,0 +1,98 @@
+package perf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// allocsPerBullet caps each operation's allocations on the 1k bank. Allocation counts
+// do not depend on the machine, so unlike timings they can fail the build; raise a cap
+// deliberately when a change needs more.
+var allocsPerBullet = map[string]float64{
+	"RankStories": 40,
+	"SelectPlan":  320,
+}
+
+func benchmarkOperation(b *testing.B, name string) {
+	var op Operation
+	for _, o := range Operations {
+		if o.Name == name {
+			op = o
+		}
+	}
+	for _, size := range Sizes {
+		b.Run(size.Name, func(b *testing.B) {
+			in, err := NewInput(size)
+			require.NoError(b, err)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				if err := op.Run(in); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRankStories(b *testing.B) {
+	benchmarkOperation(b, "RankStories")
+}
+
+func BenchmarkSelectPlan(b *testing.B) {
+	benchmarkOperation(b, "SelectPlan")
+}
+
+func TestAllocationBudgets(t *testing.T) {
+	in, err := NewInput(Sizes[0])
+	require.NoError(t, err)
+	for _, op := range Operations {
+		require.NoError(t, op.Run(in))
+		allocs := testing.AllocsPerRun(1, func() { _ = op.Run(in) })
+		limit := allocsPerBullet[op.Name] * float64(in.Size.Bullets())
+		assert.LessOrEqual(t, allocs, limit, "%s allocations on the %s bank", op.Name, in.Size.Name)
+	}
+}
+
+func TestBank(t *testing.T) {
+	size := Size{Name: "small", Stories: 10, BulletsPerStory: 3}
+	bank := Bank(size, 7)
+	require.Len(t, bank.Stories, 10)
+	assert.Len(t, bank.Stories[9].Bullets, 3)
+	assert.Equal(t, bank, Bank(size, 7), "the same seed builds the same bank")
+	assert.NotEqual(t, bank, Bank(size, 8))
+
+	in, err := NewInput(size)
+	require.NoError(t, err)
+	assert.Len(t, in.Ranked.Ranked, 10)
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Report{Results: []Result{
+		{Name: "SelectPlan/1k", NsPerOp: 100, AllocsPerOp: 1000},
+		{Name: "RankStories/1k", NsPerOp: 100, AllocsPerOp: 1000},
+		{Name: "SelectPlan/5k", NsPerOp: 100, AllocsPerOp: 1000},
+	}}
+	current := &Report{Results: []Result{
+		{Name: "SelectPlan/1k", NsPerOp: 150, AllocsPerOp: 1100},
+		{Name: "RankStories/1k", NsPerOp: 120, AllocsPerOp: 2000},
+		{Name: "SelectPlan/2k", NsPerOp: 900, AllocsPerOp: 9000},
+	}}
+
+	assert.Equal(t, []Regression{
+		{Name: "SelectPlan/1k", Metric: "ns/op", Baseline: 100, Current: 150, Ratio: 1.5},
+		{Name: "RankStories/1k", Metric: "allocs/op", Baseline: 1000, Current: 2000, Ratio: 2},
+	}, Compare(baseline, current, 0.25))
+	assert.Empty(t, Compare(baseline, current, 1), "within tolerance")
+}
+
+func TestSizesByName(t *testing.T) {
+	sizes, err := SizesByName([]string{"5k", "1k"})
+	require.NoError(t, err)
+	assert.Equal(t, []Size{Sizes[2], Sizes[0]}, sizes)
+
+	_, err = SizesByName([]string{"10m"})
+	assert.Error(t, err)
+}
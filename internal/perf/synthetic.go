@@ -0,0 +1,111 @@
+// Package perf measures the ranking and selection steps over synthetic experience banks
+// far larger than a typical user's, so slowdowns show up before real banks grow into
+// them. It backs the ranking and selection benchmarks and `resume_agent bench`.
+package perf
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Size is the shape of a synthetic experience bank
+type Size struct {
+	Name            string `json:"name"`
+	Stories         int    `json:"stories"`
+	BulletsPerStory int    `json:"bullets_per_story"`
+}
+
+// Bullets returns the bank's total bullet count
+func (s Size) Bullets() int {
+	return s.Stories * s.BulletsPerStory
+}
+
+// Sizes are the banks measured by default. Selection tries every subset of a story's
+// bullets, so stories keep a realistic handful of bullets and banks grow by story count.
+var Sizes = []Size{
+	{Name: "1k", Stories: 200, BulletsPerStory: 5},
+	{Name: "2k", Stories: 400, BulletsPerStory: 5},
+	{Name: "5k", Stories: 1000, BulletsPerStory: 5},
+}
+
+// DefaultBudget is the space budget runs use unless a request sets its own
+var DefaultBudget = types.SpaceBudget{MaxBullets: 25, MaxLines: 35}
+
+// skillPool is drawn from for bullets and requirements; the job asks for some of them,
+// so banks mix matching and unrelated experience
+var skillPool = []string{
+	"Go", "Python", "Java", "TypeScript", "Rust", "C++", "Kotlin", "Ruby", "Scala", "SQL",
+	"Postgres", "MySQL", "Redis", "Kafka", "RabbitMQ", "Elasticsearch", "Cassandra", "DynamoDB",
+	"Kubernetes", "Docker", "Terraform", "AWS", "GCP", "Azure", "Linux", "gRPC", "GraphQL",
+	"React", "Spark", "Airflow", "Prometheus", "Grafana", "CI/CD", "Microservices",
+	"Distributed Systems", "Machine Learning", "Security", "Networking", "Observability", "Testing",
+}
+
+var (
+	verbs    = []string{"Built", "Led", "Designed", "Migrated", "Scaled", "Reduced", "Automated", "Launched", "Optimized", "Mentored"}
+	subjects = []string{"the billing pipeline", "a search service", "the deploy tooling", "an event bus", "the data warehouse", "a rate limiter", "the on-call rotation", "a feature store"}
+	results  = []string{"cutting p99 latency 40%", "saving $120K a year", "for 3M daily users", "with zero downtime", "halving build times", "across 12 teams"}
+	strength = []string{"high", "medium", "low"}
+)
+
+// Bank returns a synthetic experience bank of the given size. The same size and seed
+// always produce the same bank.
+func Bank(size Size, seed uint64) *types.ExperienceBank {
+	rng := rand.New(rand.NewPCG(seed, uint64(size.Bullets())))
+	bank := &types.ExperienceBank{Stories: make([]types.Story, 0, size.Stories)}
+	for i := range size.Stories {
+		year := 2024 - i%20
+		story := types.Story{
+			ID:        fmt.Sprintf("story_%04d", i),
+			Company:   fmt.Sprintf("Company %d", i%50),
+			Role:      "Software Engineer",
+			StartDate: fmt.Sprintf("%d-%02d", year-2, 1+rng.IntN(12)),
+			EndDate:   fmt.Sprintf("%d-%02d", year, 1+rng.IntN(12)),
+			Bullets:   make([]types.Bullet, 0, size.BulletsPerStory),
+		}
+		for j := range size.BulletsPerStory {
+			text := fmt.Sprintf("%s %s %s", pick(rng, verbs), pick(rng, subjects), pick(rng, results))
+			skills := make([]string, 0, 4)
+			for range 1 + rng.IntN(4) {
+				skills = append(skills, pick(rng, skillPool))
+			}
+			story.Bullets = append(story.Bullets, types.Bullet{
+				ID:               fmt.Sprintf("%s_b%d", story.ID, j),
+				Text:             text,
+				Skills:           skills,
+				LengthChars:      60 + rng.IntN(120),
+				EvidenceStrength: pick(rng, strength),
+				RiskFlags:        []string{},
+			})
+		}
+		bank.Stories = append(bank.Stories, story)
+	}
+	return bank
+}
+
+// JobProfile returns a synthetic job profile asking for a fixed subset of the skills
+// banks are drawn from
+func JobProfile() *types.JobProfile {
+	profile := &types.JobProfile{
+		Company:          "Benchmark Corp",
+		RoleTitle:        "Senior Software Engineer",
+		Responsibilities: []string{"Design and scale distributed systems", "Reduce latency and cost"},
+		Keywords:         []string{"latency", "scale", "pipeline", "migration"},
+		EvalSignals:      &types.EvalSignals{Latency: true, Scale: true},
+	}
+	for i, skill := range skillPool[:16] {
+		req := types.Requirement{Skill: skill, Evidence: "synthetic"}
+		if i < 8 {
+			profile.HardRequirements = append(profile.HardRequirements, req)
+		} else {
+			profile.NiceToHaves = append(profile.NiceToHaves, req)
+		}
+	}
+	return profile
+}
+
+func pick(rng *rand.Rand, options []string) string {
+	return options[rng.IntN(len(options))]
+}
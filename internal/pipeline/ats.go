@@ -0,0 +1,25 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/ats"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// reportATSCoverage scores the final resume's coverage of the job's keywords and hard
+// requirements, and stores the report as a run artifact
+func reportATSCoverage(ctx context.Context, database *db.DB, runID uuid.UUID, opts *RunOptions, jobProfile *types.JobProfile, resumeTex string) {
+	report := ats.Analyze(ats.ResumeText(resumeTex), jobProfile)
+	slog.InfoContext(ctx, report.Summary())
+	if database != nil && runID != uuid.Nil {
+		if err := database.SaveArtifact(ctx, runID, db.StepATSReport, db.CategoryValidation, report); err != nil {
+			slog.WarnContext(ctx, "Failed to save ATS report", "error", err)
+		}
+	}
+	emitProgress(opts, db.StepATSReport, db.CategoryValidation, report.Summary(), report)
+}
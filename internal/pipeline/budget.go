@@ -0,0 +1,59 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+// warnOverLatencyBudget logs a successful step execution that took longer than the
+// step's latency budget. Durations themselves are recorded in run_steps.
+func warnOverLatencyBudget(ctx context.Context, step string, elapsed time.Duration) {
+	budget := steps.StepRegistry[step].LatencyBudget
+	if budget > 0 && elapsed > budget {
+		slog.WarnContext(ctx, "Step exceeded its latency budget",
+			"duration_ms", elapsed.Milliseconds(), "budget_ms", budget.Milliseconds())
+	}
+}
+
+// TruncationArtifact is the stored payload listing prompts cut to fit their step's
+// context budget, so a weaker output can be traced to the input it lost
+type TruncationArtifact struct {
+	Truncations []llm.Truncation `json:"truncations"`
+}
+
+// withTruncationLog attaches a log to ctx that records every prompt the run's
+// budgeted LLM clients truncate
+func withTruncationLog(ctx context.Context) (context.Context, *llm.TruncationLog) {
+	log := &llm.TruncationLog{}
+	return llm.WithTruncationLog(ctx, log), log
+}
+
+// saveTruncations persists the run's truncations, appended to any an earlier
+// step-by-step execution stored. Runs that truncated nothing store nothing.
+func saveTruncations(ctx context.Context, database *db.DB, runID uuid.UUID, log *llm.TruncationLog) {
+	if database == nil || runID == uuid.Nil || log == nil {
+		return
+	}
+	truncations := log.Truncations()
+	if len(truncations) == 0 {
+		return
+	}
+
+	// Use a fresh context so a timed-out or cancelled run still records what it cut
+	ctx = context.WithoutCancel(ctx)
+	var artifact TruncationArtifact
+	if _, err := loadArtifact(ctx, database, runID, db.StepPromptTruncations, &artifact); err != nil {
+		slog.WarnContext(ctx, "Failed to load prompt truncations", "error", err)
+	}
+	artifact.Truncations = append(artifact.Truncations, truncations...)
+	if err := database.SaveArtifact(ctx, runID, db.StepPromptTruncations, db.CategoryLifecycle, artifact); err != nil {
+		slog.WarnContext(ctx, "Failed to save prompt truncations", "error", err)
+	}
+}
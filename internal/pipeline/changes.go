@@ -0,0 +1,90 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/resumediff"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// previousRunsScanned bounds how far back reportChanges looks for an earlier resume
+const previousRunsScanned = 50
+
+// reportChanges compares the final resume with the user's most recent completed
+// resume for the same role family and stores what changed as a run artifact. Runs
+// without an owner or an earlier resume for the role family have no report.
+func reportChanges(ctx context.Context, database *db.DB, runID uuid.UUID, opts *RunOptions, current *resumediff.Resume) {
+	if database == nil || runID == uuid.Nil || opts.UserID == nil {
+		return
+	}
+	family := resumediff.RoleFamily(current.RoleTitle)
+	if family == "" {
+		return
+	}
+
+	previous, err := previousResume(ctx, database, *opts.UserID, runID, family)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load previous resume", "error", err)
+		return
+	}
+	if previous == nil {
+		slog.InfoContext(ctx, "No earlier resume for role family; skipping change report", "role_family", family)
+		return
+	}
+
+	report := resumediff.Compare(previous, current)
+	summary := fmt.Sprintf("Changes since %s (%s): %d added, %d removed, %d reworded bullets",
+		previous.RoleTitle, previous.Company, len(report.Added), len(report.Removed), len(report.Reworded))
+	slog.InfoContext(ctx, summary)
+	if err := database.SaveArtifact(ctx, runID, db.StepChangeReport, db.CategoryValidation, report); err != nil {
+		slog.WarnContext(ctx, "Failed to save change report", "error", err)
+	}
+	emitProgress(opts, db.StepChangeReport, db.CategoryValidation, summary, report)
+}
+
+// previousResume returns the user's most recent completed resume, other than runID,
+// whose role is in family, or nil if there is none
+func previousResume(ctx context.Context, database *db.DB, userID, runID uuid.UUID, family string) (*resumediff.Resume, error) {
+	runs, err := database.ListRunsFiltered(ctx, db.RunFilters{
+		UserID: &userID,
+		Status: "completed",
+		Limit:  previousRunsScanned,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs {
+		if run.ID == runID || resumediff.RoleFamily(run.RoleTitle) != family {
+			continue
+		}
+		content, err := database.GetArtifact(ctx, run.ID, db.StepRewrittenBullets)
+		if err != nil {
+			return nil, err
+		}
+		if content == nil {
+			continue // Failed before rewriting
+		}
+		var bullets types.RewrittenBullets
+		if err := json.Unmarshal(content, &bullets); err != nil {
+			return nil, fmt.Errorf("failed to decode bullets of run %s: %w", run.ID, err)
+		}
+		latex, err := database.GetTextArtifact(ctx, run.ID, db.StepResumeTex)
+		if err != nil {
+			return nil, err
+		}
+		return &resumediff.Resume{
+			RunID:     run.ID,
+			RoleTitle: run.RoleTitle,
+			Company:   run.Company,
+			Bullets:   &bullets,
+			LaTeX:     latex,
+		}, nil
+	}
+	return nil, nil
+}
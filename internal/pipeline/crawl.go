@@ -0,0 +1,32 @@
+package pipeline
+
+import (
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/research"
+)
+
+// resolveCrawlLimits returns the crawl limits from options, else the CRAWL_* defaults
+func resolveCrawlLimits(opts *RunOptions) (*research.CrawlLimits, error) {
+	if opts.Crawl != nil {
+		return opts.Crawl, nil
+	}
+	cfg, err := config.NewCrawlConfig()
+	if err != nil {
+		return nil, err
+	}
+	return CrawlLimitsFromConfig(cfg), nil
+}
+
+// CrawlLimitsFromConfig converts crawl configuration defaults to research crawl limits
+func CrawlLimitsFromConfig(cfg *config.CrawlConfig) *research.CrawlLimits {
+	return &research.CrawlLimits{
+		MaxPages:       cfg.MaxPages,
+		MaxDepth:       cfg.MaxDepth,
+		SameDomainOnly: cfg.SameDomainOnly,
+		MaxBytes:       cfg.MaxBytes,
+
+		ArchiveFallback: cfg.ArchiveFallback,
+
+		ProfileMaxAgeDays: cfg.ProfileMaxAgeDays,
+	}
+}
@@ -0,0 +1,35 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/research"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCrawlLimits_FromOptions(t *testing.T) {
+	limits := &research.CrawlLimits{MaxPages: 20, MaxDepth: 3}
+	got, err := resolveCrawlLimits(&RunOptions{Crawl: limits})
+	require.NoError(t, err)
+	assert.Same(t, limits, got)
+}
+
+func TestResolveCrawlLimits_FromEnv(t *testing.T) {
+	t.Setenv("CRAWL_MAX_PAGES", "12")
+	t.Setenv("CRAWL_MAX_DEPTH", "2")
+	t.Setenv("CRAWL_SAME_DOMAIN_ONLY", "false")
+	t.Setenv("CRAWL_MAX_BYTES", "0")
+	t.Setenv("CRAWL_PROFILE_MAX_AGE_DAYS", "7")
+
+	got, err := resolveCrawlLimits(&RunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &research.CrawlLimits{MaxPages: 12, MaxDepth: 2, SameDomainOnly: false, MaxBytes: 0, ProfileMaxAgeDays: 7}, got)
+}
+
+func TestResolveCrawlLimits_InvalidEnv(t *testing.T) {
+	t.Setenv("CRAWL_MAX_PAGES", "0")
+
+	_, err := resolveCrawlLimits(&RunOptions{})
+	assert.Error(t, err)
+}
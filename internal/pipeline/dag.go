@@ -0,0 +1,184 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+// DefaultWorkers is the number of steps that may run concurrently when
+// neither RunOptions.Workers nor PIPELINE_WORKERS is set
+const DefaultWorkers = 4
+
+// dagTask is a unit of work in the step dependency graph
+type dagTask struct {
+	Name string
+	Deps []string
+	Run  func(ctx context.Context) error
+}
+
+// resolveWorkers returns the worker count from options, then PIPELINE_WORKERS, then the default
+func resolveWorkers(opts *RunOptions) int {
+	if opts.Workers > 0 {
+		return opts.Workers
+	}
+	if v := os.Getenv("PIPELINE_WORKERS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return DefaultWorkers
+}
+
+// registryDeps returns the registry dependencies (required and optional) of a step
+// that are part of the current graph. Dependencies outside the graph are assumed
+// to have already run.
+func registryDeps(name string, inGraph map[string]bool) []string {
+	def, ok := steps.StepRegistry[name]
+	if !ok {
+		return nil
+	}
+	var deps []string
+	for _, dep := range append(append([]string{}, def.Dependencies...), def.Optional...) {
+		if inGraph[dep] {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// validateDAG checks that every dependency exists and that the graph is acyclic
+func validateDAG(tasks []dagTask) error {
+	byName := make(map[string]dagTask, len(tasks))
+	for _, t := range tasks {
+		if _, dup := byName[t.Name]; dup {
+			return fmt.Errorf("duplicate task: %s", t.Name)
+		}
+		byName[t.Name] = t
+	}
+	for _, t := range tasks {
+		for _, dep := range t.Deps {
+			if _, ok := byName[dep]; !ok {
+				return fmt.Errorf("task %s depends on unknown task %s", t.Name, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(tasks))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("dependency cycle detected at task %s", name)
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		for _, dep := range byName[name].Deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		return nil
+	}
+	for _, t := range tasks {
+		if err := visit(t.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runDAG executes tasks respecting their dependencies, running up to workers
+// ready tasks at a time. The first error cancels the context passed to the
+// remaining tasks and is returned once in-flight tasks finish.
+func runDAG(ctx context.Context, tasks []dagTask, workers int) error {
+	if err := validateDAG(tasks); err != nil {
+		return err
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	remaining := make(map[string]int, len(tasks))
+	dependents := make(map[string][]string)
+	byName := make(map[string]dagTask, len(tasks))
+	var ready []string
+	for _, t := range tasks {
+		byName[t.Name] = t
+		remaining[t.Name] = len(t.Deps)
+		for _, dep := range t.Deps {
+			dependents[dep] = append(dependents[dep], t.Name)
+		}
+		if len(t.Deps) == 0 {
+			ready = append(ready, t.Name)
+		}
+	}
+	sort.Strings(ready)
+
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result)
+	var wg sync.WaitGroup
+	running := 0
+	finished := 0
+	var firstErr error
+
+	for finished < len(tasks) {
+		// Launch as many ready tasks as the worker budget allows
+		for firstErr == nil && running < workers && len(ready) > 0 {
+			name := ready[0]
+			ready = ready[1:]
+			running++
+			wg.Add(1)
+			go func(t dagTask) {
+				defer wg.Done()
+				results <- result{name: t.Name, err: t.Run(ctx)}
+			}(byName[name])
+		}
+
+		if running == 0 {
+			break // Nothing in flight and nothing launchable (error already recorded)
+		}
+
+		res := <-results
+		running--
+		finished++
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", res.name, res.err)
+				cancel()
+			}
+			continue
+		}
+
+		var unlocked []string
+		for _, dependent := range dependents[res.name] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				unlocked = append(unlocked, dependent)
+			}
+		}
+		sort.Strings(unlocked)
+		ready = append(ready, unlocked...)
+	}
+
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,197 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunDAG_RespectsDependencies(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func(context.Context) error {
+		return func(_ context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	tasks := []dagTask{
+		{Name: "c", Deps: []string{"a", "b"}, Run: record("c")},
+		{Name: "a", Run: record("a")},
+		{Name: "b", Deps: []string{"a"}, Run: record("b")},
+		{Name: "d", Deps: []string{"c"}, Run: record("d")},
+	}
+
+	require.NoError(t, runDAG(context.Background(), tasks, 4))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, order)
+}
+
+func TestRunDAG_LimitsConcurrency(t *testing.T) {
+	var active, peak int32
+	work := func(_ context.Context) error {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		return nil
+	}
+
+	var tasks []dagTask
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		tasks = append(tasks, dagTask{Name: name, Run: work})
+	}
+
+	require.NoError(t, runDAG(context.Background(), tasks, 2))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak))
+}
+
+func TestRunDAG_IndependentTasksRunInParallel(t *testing.T) {
+	// Both tasks block until the other has started, so this deadlocks unless they overlap
+	var started sync.WaitGroup
+	started.Add(2)
+	wait := func(_ context.Context) error {
+		started.Done()
+		started.Wait()
+		return nil
+	}
+
+	tasks := []dagTask{
+		{Name: "experience", Run: wait},
+		{Name: "research", Run: wait},
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- runDAG(context.Background(), tasks, 2) }()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("independent tasks did not run concurrently")
+	}
+}
+
+func TestRunDAG_ErrorStopsDependentsAndCancels(t *testing.T) {
+	boom := errors.New("boom")
+	var dependentRan atomic.Bool
+	var sawCancel atomic.Bool
+
+	tasks := []dagTask{
+		{Name: "fails", Run: func(_ context.Context) error { return boom }},
+		{Name: "slow", Run: func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				sawCancel.Store(true)
+			case <-time.After(2 * time.Second):
+			}
+			return nil
+		}},
+		{Name: "after", Deps: []string{"fails"}, Run: func(_ context.Context) error {
+			dependentRan.Store(true)
+			return nil
+		}},
+	}
+
+	err := runDAG(context.Background(), tasks, 2)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, boom)
+	assert.Contains(t, err.Error(), "fails:")
+	assert.False(t, dependentRan.Load())
+	assert.True(t, sawCancel.Load())
+}
+
+func TestValidateDAG(t *testing.T) {
+	noop := func(_ context.Context) error { return nil }
+
+	tests := []struct {
+		name    string
+		tasks   []dagTask
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			tasks: []dagTask{{Name: "a", Run: noop}, {Name: "b", Deps: []string{"a"}, Run: noop}},
+		},
+		{
+			name:    "unknown dependency",
+			tasks:   []dagTask{{Name: "a", Deps: []string{"missing"}, Run: noop}},
+			wantErr: "unknown task missing",
+		},
+		{
+			name:    "duplicate",
+			tasks:   []dagTask{{Name: "a", Run: noop}, {Name: "a", Run: noop}},
+			wantErr: "duplicate task",
+		},
+		{
+			name: "cycle",
+			tasks: []dagTask{
+				{Name: "a", Deps: []string{"c"}, Run: noop},
+				{Name: "b", Deps: []string{"a"}, Run: noop},
+				{Name: "c", Deps: []string{"b"}, Run: noop},
+			},
+			wantErr: "cycle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDAG(tt.tasks)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRegistryDeps(t *testing.T) {
+	inGraph := map[string]bool{"load_experience": true, "extract_education": true, "rank_stories": true}
+
+	// parse_job is outside the graph and treated as already complete
+	assert.ElementsMatch(t, []string{"load_experience", "extract_education"}, registryDeps("score_education", inGraph))
+	assert.Nil(t, registryDeps("not_a_step", inGraph))
+}
+
+func TestStepGraph_IsValid(t *testing.T) {
+	pr := &pipelineRun{opts: &RunOptions{}}
+	tasks := pr.stepGraph()
+
+	require.NoError(t, validateDAG(tasks))
+
+	deps := make(map[string][]string)
+	for _, task := range tasks {
+		deps[task.Name] = task.Deps
+	}
+	assert.Empty(t, deps["research_company"], "research should start immediately")
+	assert.Equal(t, []string{"research_company"}, deps["summarize_voice"])
+	assert.Contains(t, deps["select_plan"], "score_education")
+	assert.Equal(t, []string{"load_experience"}, deps["suggest_bullets"])
+}
+
+func TestResolveWorkers(t *testing.T) {
+	t.Setenv("PIPELINE_WORKERS", "")
+	assert.Equal(t, DefaultWorkers, resolveWorkers(&RunOptions{}))
+
+	t.Setenv("PIPELINE_WORKERS", "7")
+	assert.Equal(t, 7, resolveWorkers(&RunOptions{}))
+	assert.Equal(t, 2, resolveWorkers(&RunOptions{Workers: 2}))
+
+	t.Setenv("PIPELINE_WORKERS", "nope")
+	assert.Equal(t, DefaultWorkers, resolveWorkers(&RunOptions{}))
+}
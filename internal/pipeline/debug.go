@@ -0,0 +1,43 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/redact"
+)
+
+// DebugArtifact is the stored payload for a debug-mode run
+type DebugArtifact struct {
+	Redacted  bool           `json:"redacted"`
+	Exchanges []llm.Exchange `json:"exchanges"`
+}
+
+// withDebugRecorder attaches an LLM recorder to ctx that scrubs the candidate's
+// contact details and other detectable PII from everything it captures
+func withDebugRecorder(ctx context.Context, opts *RunOptions) (context.Context, *llm.Recorder) {
+	scrubber := redact.New(opts.CandidateName, opts.CandidateEmail, opts.CandidatePhone)
+	recorder := llm.NewRecorder(scrubber.Scrub)
+	return llm.WithRecorder(ctx, recorder), recorder
+}
+
+// saveDebugExchanges persists recorded LLM exchanges as a debug artifact.
+// It is called on both success and failure so bad outputs can be diagnosed.
+func saveDebugExchanges(ctx context.Context, database *db.DB, runID uuid.UUID, recorder *llm.Recorder) {
+	if database == nil || runID == uuid.Nil || recorder == nil {
+		return
+	}
+
+	artifact := DebugArtifact{
+		Redacted:  true,
+		Exchanges: recorder.Exchanges(),
+	}
+	// Use a fresh context so a cancelled run still stores its debug trail
+	if err := database.SaveArtifact(context.WithoutCancel(ctx), runID, db.StepDebugLLMExchanges, db.CategoryDebug, artifact); err != nil {
+		slog.WarnContext(ctx, "Failed to save debug artifact", "error", err)
+	}
+}
@@ -0,0 +1,43 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/redact"
+)
+
+func TestWithDebugRecorder_ScrubsCandidateDetails(t *testing.T) {
+	opts := &RunOptions{
+		CandidateName:  "Jane Doe",
+		CandidateEmail: "jane@example.com",
+		CandidatePhone: "555-867-5309",
+	}
+
+	ctx, recorder := withDebugRecorder(context.Background(), opts)
+	require.NotNil(t, recorder)
+	assert.Same(t, recorder, llm.RecorderFromContext(ctx))
+
+	recorder.Record(llm.Exchange{
+		Prompt:   "Rewrite for Jane Doe (jane@example.com, 555-867-5309)",
+		Response: "Jane Doe shipped the feature",
+	})
+
+	got := recorder.Exchanges()
+	require.Len(t, got, 1)
+	assert.NotContains(t, got[0].Prompt, "Jane")
+	assert.NotContains(t, got[0].Prompt, "jane@example.com")
+	assert.NotContains(t, got[0].Prompt, "867-5309")
+	assert.Contains(t, got[0].Response, redact.LiteralPlaceholder)
+}
+
+func TestSaveDebugExchanges_NoDatabase(_ *testing.T) {
+	// Must be a no-op without a database or run ID
+	saveDebugExchanges(context.Background(), nil, uuid.New(), llm.NewRecorder(nil))
+	saveDebugExchanges(context.Background(), nil, uuid.Nil, nil)
+}
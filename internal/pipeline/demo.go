@@ -0,0 +1,25 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/jonathan/resume-customizer/internal/demo"
+	"github.com/jonathan/resume-customizer/internal/llm"
+)
+
+// withDemo points a demo run at the bundled data: the sample posting and experience
+// bank replace the caller's, and ctx answers every LLM call from the recorded cassette
+// so no API key is used and nothing leaves the process
+func withDemo(ctx context.Context, opts *RunOptions) (context.Context, error) {
+	bank, err := opts.Demo.ExperienceBank()
+	if err != nil {
+		return ctx, err
+	}
+	opts.ExperienceData = bank
+	opts.JobURL = opts.Demo.JobURL
+	opts.JobPath = ""
+	opts.JobText = ""
+	opts.CompanySeedURL = ""
+	opts.APIKey = demo.APIKey
+	return llm.WithCassette(ctx, opts.Demo.Cassette), nil
+}
@@ -0,0 +1,48 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/demo"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestRunPipeline_Demo(t *testing.T) {
+	fixtures, err := demo.Load()
+	require.NoError(t, err)
+	t.Setenv("LLM_PROVIDER", "")
+	t.Setenv("GEMINI_API_KEY", "")
+	t.Setenv("GOOGLE_SEARCH_API_KEY", "")
+
+	var events []ProgressEvent
+	opts := RunOptions{
+		JobURL:         "https://unreachable.invalid/job", // Ignored in demo mode
+		CandidateName:  "Demo Candidate",
+		CandidateEmail: "demo@example.com",
+		TemplatePath:   "../../templates/one_page_resume.tex",
+		MaxBullets:     25,
+		MaxLines:       35,
+		Workers:        1,
+		Demo:           fixtures,
+		OnProgress:     func(e ProgressEvent) { events = append(events, e) },
+	}
+	require.NoError(t, RunPipeline(context.Background(), opts))
+
+	byStep := make(map[string]ProgressEvent)
+	for _, e := range events {
+		byStep[e.Step] = e
+	}
+	profile, ok := byStep[db.StepJobProfile].Content.(*types.JobProfile)
+	require.True(t, ok)
+	assert.Equal(t, "Northwind Pay", profile.Company)
+
+	voice, ok := byStep[db.StepCompanyProfile].Content.(*types.CompanyProfile)
+	require.True(t, ok)
+	assert.Contains(t, voice.EvidenceURLs, "https://northwindpay.example/values")
+	assert.Contains(t, byStep, db.StepRewrittenBullets)
+}
@@ -0,0 +1,25 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jonathan/resume-customizer/internal/fetch"
+)
+
+// loadDomainPolicy returns the global and run owner's domain crawl policies,
+// or nil when there are none (or no database)
+func (p *pipelineRun) loadDomainPolicy(ctx context.Context) *fetch.DomainPolicy {
+	if p.database == nil {
+		return nil
+	}
+	policies, err := p.database.ListDomainPolicies(ctx, p.opts.UserID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load domain policies", "error", err)
+		return nil
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+	return fetch.NewDomainPolicy(policies)
+}
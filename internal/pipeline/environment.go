@@ -0,0 +1,119 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/buildinfo"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/research"
+	"github.com/jonathan/resume-customizer/internal/stepqueue"
+)
+
+// Environment is the run_environment artifact: what the server was running when a
+// run executed, so support can reproduce issues reported against older outputs
+type Environment struct {
+	CapturedAt time.Time       `json:"captured_at"`
+	Server     buildinfo.Info  `json:"server"`
+	Template   TemplateInfo    `json:"template"`
+	LLM        EnvironmentLLM  `json:"llm"`
+	Features   map[string]bool `json:"features"`
+	// Crawl limits and model routing in effect after defaults were applied
+	Crawl        *research.CrawlLimits      `json:"crawl,omitempty"`
+	ModelRouting *llm.Routing               `json:"model_routing,omitempty"`
+	StepBackends map[string]string          `json:"step_backends,omitempty"` // Steps not executed in-process
+	StepBudgets  map[string]steps.LLMBudget `json:"step_budgets,omitempty"`  // Timeout and context budget of steps that call LLMs
+	Plugins      []string                   `json:"plugins,omitempty"`
+}
+
+// TemplateInfo identifies the LaTeX template a run rendered with
+type TemplateInfo struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256,omitempty"`
+	Error  string `json:"error,omitempty"` // Why the template could not be hashed
+}
+
+// EnvironmentLLM records the LLM provider and the model serving each tier
+type EnvironmentLLM struct {
+	Provider string            `json:"provider"`
+	Models   map[string]string `json:"models"`
+}
+
+// captureEnvironment snapshots the build, configuration, and feature flags of a run.
+// remote may be nil when every step runs in-process.
+func captureEnvironment(opts *RunOptions, remote *stepqueue.Dispatcher) *Environment {
+	env := &Environment{
+		CapturedAt: time.Now().UTC(),
+		Server:     buildinfo.Read(),
+		Template:   templateInfo(opts.TemplatePath),
+		Features: map[string]bool{
+			"debug":            opts.Debug,
+			"demo":             opts.Demo != nil,
+			"redact_pii":       opts.RedactPII,
+			"model_downgrade":  opts.ModelDowngrade,
+			"use_browser":      opts.UseBrowser,
+			"archive_fallback": opts.Crawl != nil && opts.Crawl.ArchiveFallback,
+		},
+		Crawl:        opts.Crawl,
+		ModelRouting: opts.ModelRouting,
+	}
+
+	config := llm.DefaultConfig()
+	env.LLM = EnvironmentLLM{Provider: string(config.Provider), Models: make(map[string]string, len(config.Models))}
+	for tier, model := range config.Models {
+		env.LLM.Models[string(tier)] = model
+	}
+
+	for name := range steps.StepRegistry {
+		if backend := remote.Backend(name); backend != steps.BackendLocal {
+			if env.StepBackends == nil {
+				env.StepBackends = make(map[string]string)
+			}
+			env.StepBackends[name] = backend
+		}
+		if budget := steps.StepRegistry[name].LLM; budget != (steps.LLMBudget{}) {
+			if env.StepBudgets == nil {
+				env.StepBudgets = make(map[string]steps.LLMBudget)
+			}
+			env.StepBudgets[name] = budget
+		}
+	}
+	for name := range steps.Plugins {
+		env.Plugins = append(env.Plugins, name)
+	}
+	sort.Strings(env.Plugins)
+	return env
+}
+
+// templateInfo hashes the template file so a changed template can be told apart
+// from the one a run used, even at the same path
+func templateInfo(path string) TemplateInfo {
+	info := TemplateInfo{Path: path}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	sum := sha256.Sum256(content)
+	info.SHA256 = hex.EncodeToString(sum[:])
+	return info
+}
+
+// saveEnvironment persists the run's environment snapshot
+func saveEnvironment(ctx context.Context, database *db.DB, runID uuid.UUID, env *Environment) {
+	if database == nil || runID == uuid.Nil {
+		return
+	}
+	if err := database.SaveArtifact(ctx, runID, db.StepRunEnvironment, db.CategoryLifecycle, env); err != nil {
+		slog.WarnContext(ctx, "Failed to save run environment", "error", err)
+	}
+}
@@ -0,0 +1,52 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/demo"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/research"
+)
+
+func TestCaptureEnvironment(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", "")
+	template := filepath.Join(t.TempDir(), "resume.tex")
+	require.NoError(t, os.WriteFile(template, []byte(`\documentclass{article}`), 0o600))
+
+	opts := &RunOptions{
+		TemplatePath: template,
+		RedactPII:    true,
+		Demo:         &demo.Fixtures{},
+		Crawl:        &research.CrawlLimits{MaxPages: 3, ArchiveFallback: true},
+		ModelRouting: &llm.Routing{Steps: map[string]string{"rewrite_bullets": "advanced"}},
+	}
+	env := captureEnvironment(opts, nil)
+
+	assert.NotEmpty(t, env.Server.Version)
+	assert.NotEmpty(t, env.Server.GoVersion)
+	assert.Equal(t, template, env.Template.Path)
+	assert.Equal(t, "9f1c4eec1f3f6ab16d74135508a6667bb4ec027155c506bad8dfca807899f0a8", env.Template.SHA256)
+	assert.Equal(t, string(llm.ProviderGemini), env.LLM.Provider)
+	assert.NotEmpty(t, env.LLM.Models)
+	assert.Equal(t, map[string]bool{
+		"debug": false, "demo": true, "redact_pii": true, "model_downgrade": false,
+		"use_browser": false, "archive_fallback": true,
+	}, env.Features)
+	assert.Same(t, opts.Crawl, env.Crawl)
+	assert.Same(t, opts.ModelRouting, env.ModelRouting)
+	assert.Empty(t, env.StepBackends, "every step runs in-process without a dispatcher")
+	assert.Equal(t, steps.StepRegistry["rewrite_bullets"].LLM, env.StepBudgets["rewrite_bullets"])
+	assert.NotContains(t, env.StepBudgets, "load_experience", "only steps that call LLMs have budgets")
+}
+
+func TestTemplateInfo_Missing(t *testing.T) {
+	info := templateInfo(filepath.Join(t.TempDir(), "missing.tex"))
+	assert.Empty(t, info.SHA256)
+	assert.Contains(t, info.Error, "no such file")
+}
@@ -0,0 +1,547 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/experience"
+	"github.com/jonathan/resume-customizer/internal/ingestion"
+	"github.com/jonathan/resume-customizer/internal/kubejob"
+	"github.com/jonathan/resume-customizer/internal/observability"
+	"github.com/jonathan/resume-customizer/internal/parsing"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/research"
+	"github.com/jonathan/resume-customizer/internal/selection"
+	"github.com/jonathan/resume-customizer/internal/stepqueue"
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/jonathan/resume-customizer/internal/validation"
+	"github.com/jonathan/resume-customizer/internal/voice"
+)
+
+// pipelineRun carries the inputs and intermediate results of the step graph.
+// Each result field is written by exactly one step and read only by steps that
+// depend on it, so the DAG executor's ordering makes additional locking unnecessary.
+type pipelineRun struct {
+	opts        *RunOptions
+	database    *db.DB
+	runID       uuid.UUID
+	printer     *observability.Printer
+	jobProfile  *types.JobProfile
+	jobMetadata *ingestion.Metadata
+	cleanedText string
+	remote      *stepqueue.Dispatcher // nil when every step runs in-process
+
+	// Step outputs
+	educationRequirements *types.EducationRequirements
+	experienceBank        *types.ExperienceBank
+	rankedStories         *types.RankedStories
+	selectedEducation     []types.Education
+	resumePlan            *types.ResumePlan
+	selectedBullets       *types.SelectedBullets
+	companyCorpus         *types.CompanyCorpus
+	companyProfile        *types.CompanyProfile
+	rewrittenBullets      *types.RewrittenBullets
+	resumeTex             string
+	lineMap               *rendering.LineBulletMap // nil when the rendered LaTeX can't be traced to bullets
+	violations            *types.Violations
+}
+
+// stepGraph returns the steps that run between job parsing and rewriting.
+// Dependencies come from steps.StepRegistry, so adding a step to the registry
+// and to this list is enough for it to be scheduled in parallel where possible.
+func (p *pipelineRun) stepGraph() []dagTask {
+	runs := map[string]func(context.Context) error{
+		"extract_education":   p.extractEducation,
+		"load_experience":     p.loadExperience,
+		"rank_stories":        p.rankStories,
+		"score_education":     p.scoreEducation,
+		"select_plan":         p.selectPlan,
+		"materialize_bullets": p.materializeBullets,
+		"suggest_bullets":     p.suggestBullets,
+		"research_company":    p.researchCompany,
+		"summarize_voice":     p.summarizeVoice,
+	}
+
+	inGraph := make(map[string]bool, len(runs))
+	for name := range runs {
+		inGraph[name] = true
+	}
+
+	tasks := make([]dagTask, 0, len(runs))
+	for name, run := range runs {
+		tasks = append(tasks, dagTask{
+			Name: name,
+			Deps: registryDeps(name, inGraph),
+			Run:  p.routedTask(name, run),
+		})
+	}
+	return tasks
+}
+
+// extractEducation extracts education requirements from the job posting (non-fatal)
+func (p *pipelineRun) extractEducation(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 2a/12: Extracting education requirements...")
+	if err := startStep(ctx, p.database, p.runID, db.StepEducationReq); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	eduReq, err := parsing.ExtractEducationRequirements(ctx, p.cleanedText, p.opts.APIKey)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to extract education requirements", "error", err)
+		_ = failStep(ctx, p.database, p.runID, db.StepEducationReq, err)
+		return nil
+	}
+	p.educationRequirements = eduReq
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepEducationReq, db.CategoryIngestion, eduReq)
+		_ = completeStep(ctx, p.database, p.runID, db.StepEducationReq, nil)
+	}
+	return nil
+}
+
+// loadExperience validates and normalizes the injected experience bank
+func (p *pipelineRun) loadExperience(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 3/12: Loading and normalizing experience bank...")
+	if err := startStep(ctx, p.database, p.runID, db.StepExperienceBank); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	if p.opts.ExperienceData == nil {
+		err := fmt.Errorf("experience data is missing (legacy file path support removed)")
+		_ = failStep(ctx, p.database, p.runID, db.StepExperienceBank, err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "Using provided experience data (from DB)...")
+	experienceBank := p.opts.ExperienceData
+
+	if err := experience.NormalizeExperienceBank(experienceBank); err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepExperienceBank, err)
+		return fmt.Errorf("normalizing experience bank failed: %w", err)
+	}
+	emitProgress(p.opts, db.StepExperienceBank, db.CategoryExperience,
+		fmt.Sprintf("Loaded %d stories with %d total bullets", len(experienceBank.Stories), countBullets(experienceBank)), nil)
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepExperienceBank, db.CategoryExperience, experienceBank)
+		_ = completeStep(ctx, p.database, p.runID, db.StepExperienceBank, nil)
+	}
+	p.experienceBank = experienceBank
+	return nil
+}
+
+// rankStories ranks experience stories against the job profile
+func (p *pipelineRun) rankStories(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 4/12: Ranking stories...")
+	if err := startStep(ctx, p.database, p.runID, db.StepRankedStories); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	// Requirement weights the user set for the posting shift which skills count most
+	jobProfile := p.weightedJobProfile(ctx)
+	rankedStories, err := ranking.RankStoriesWeighted(jobProfile, p.experienceBank, p.opts.RankingWeights)
+	if err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepRankedStories, err)
+		return fmt.Errorf("ranking stories failed: %w", err)
+	}
+	// Blend in semantic similarity to the job when ranking with embeddings
+	p.applySemanticRanking(ctx, rankedStories)
+	// Favor stories using technologies the company is known to use
+	ranking.ApplyTechStackBias(rankedStories, p.experienceBank, p.companyTechStack(ctx))
+	// Favor stories from roles like the one posted, whatever each company calls it
+	ranking.ApplyRoleTitleBias(rankedStories, p.experienceBank, jobProfile)
+	// Keep stories built on rusty skills from leading when the job needs those skills in depth
+	ranking.ApplySkillFreshness(rankedStories, p.experienceBank, jobProfile, time.Now())
+	if p.opts.Verbose {
+		p.printer.PrintRankedStories(rankedStories)
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepRankedStories, db.CategoryExperience, rankedStories)
+		_ = completeStep(ctx, p.database, p.runID, db.StepRankedStories, nil)
+	}
+	emitProgress(p.opts, db.StepRankedStories, db.CategoryExperience, "Ranked stories by relevance", rankedStories)
+	p.rankedStories = rankedStories
+	return nil
+}
+
+// scoreEducation selects relevant education entries (non-fatal; falls back to all education)
+func (p *pipelineRun) scoreEducation(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 4a/12: Scoring education relevance...")
+	if err := startStep(ctx, p.database, p.runID, db.StepEducationScores); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	eduScores, err := ranking.ScoreEducation(ctx, p.experienceBank.Education, p.educationRequirements, p.cleanedText, p.opts.APIKey)
+	if err != nil {
+		slog.WarnContext(ctx, "Education scoring failed, including all education", "error", err)
+		p.selectedEducation = p.experienceBank.Education
+		_ = failStep(ctx, p.database, p.runID, db.StepEducationScores, err)
+		return nil
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepEducationScores, db.CategoryExperience, eduScores)
+		_ = completeStep(ctx, p.database, p.runID, db.StepEducationScores, nil)
+	}
+	p.selectedEducation = IncludedEducation(p.experienceBank, eduScores)
+	return nil
+}
+
+// IncludedEducation returns the bank's education entries scored as included
+func IncludedEducation(bank *types.ExperienceBank, scores []ranking.EducationScore) []types.Education {
+	var selected []types.Education
+	for _, score := range scores {
+		if score.Included {
+			for _, edu := range bank.Education {
+				if edu.ID == score.EducationID {
+					selected = append(selected, edu)
+				}
+			}
+		}
+	}
+	return selected
+}
+
+// selectPlan chooses the resume plan within the space budget
+func (p *pipelineRun) selectPlan(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 5/12: Selecting optimum resume plan...")
+	if err := startStep(ctx, p.database, p.runID, db.StepResumePlan); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	spaceBudget := &types.SpaceBudget{
+		MaxBullets: p.opts.MaxBullets,
+		MaxLines:   p.opts.MaxLines,
+	}
+	resumePlan, err := selection.SelectPlan(p.rankedStories, p.jobProfile, p.experienceBank, spaceBudget)
+	if err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepResumePlan, err)
+		return fmt.Errorf("selecting plan failed: %w", err)
+	}
+	if len(p.opts.PinnedBullets) > 0 {
+		missing, err := selection.PinBullets(resumePlan, p.jobProfile, p.experienceBank, p.opts.PinnedBullets)
+		if err != nil {
+			_ = failStep(ctx, p.database, p.runID, db.StepResumePlan, err)
+			return fmt.Errorf("pinning bullets failed: %w", err)
+		}
+		if len(missing) > 0 {
+			slog.WarnContext(ctx, "Pinned bullets not in the experience bank", "bullets", strings.Join(missing, ", "))
+		}
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepResumePlan, db.CategoryExperience, resumePlan)
+		_ = completeStep(ctx, p.database, p.runID, db.StepResumePlan, nil)
+	}
+	p.resumePlan = resumePlan
+	return nil
+}
+
+// materializeBullets resolves the plan into concrete bullets
+func (p *pipelineRun) materializeBullets(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 6/12: Materializing selected bullets...")
+	if err := startStep(ctx, p.database, p.runID, db.StepSelectedBullets); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	selectedBullets, err := selection.MaterializeBullets(p.resumePlan, p.experienceBank)
+	if err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepSelectedBullets, err)
+		return fmt.Errorf("materializing bullets failed: %w", err)
+	}
+	if p.opts.Verbose {
+		p.printer.PrintSelectedBullets(selectedBullets)
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepSelectedBullets, db.CategoryExperience, selectedBullets)
+		_ = completeStep(ctx, p.database, p.runID, db.StepSelectedBullets, nil)
+	}
+	emitProgress(p.opts, db.StepSelectedBullets, db.CategoryExperience,
+		fmt.Sprintf("Selected %d bullets for resume", len(selectedBullets.Bullets)), selectedBullets)
+	p.selectedBullets = selectedBullets
+	return nil
+}
+
+// researchCompany discovers and crawls company pages to build a voice corpus
+func (p *pipelineRun) researchCompany(ctx context.Context) error {
+	opts := p.opts
+	jobProfile := p.jobProfile
+	jobMetadata := p.jobMetadata
+
+	slog.InfoContext(ctx, "Step 7/12: Researching company voice...")
+	if err := startStep(ctx, p.database, p.runID, db.StepSources); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	// Determine seeds and company info for research
+	var seeds []string
+	if jobMetadata != nil && len(jobMetadata.ExtractedLinks) > 0 {
+		seeds = append(seeds, jobMetadata.ExtractedLinks...)
+	}
+
+	// Build initial corpus from "About Company" section if available
+	initialCorpus := ""
+	if jobMetadata != nil && jobMetadata.AboutCompany != "" {
+		initialCorpus = "## About the Company\n" + jobMetadata.AboutCompany + "\n\n"
+	}
+
+	companyName := p.researchCompanyName()
+	companyDomain := ""
+
+	// A fresh profile another run researched stands in for crawling again
+	if shared := p.sharedCompanyProfile(ctx, companyName); shared != nil {
+		slog.InfoContext(ctx, "Reusing shared company research", "company", companyName,
+			"last_verified_at", shared.LastVerifiedAt, "sources", len(shared.EvidenceURLs))
+		companyCorpus := sharedCorpus(shared)
+		if p.database != nil && p.runID != uuid.Nil {
+			_ = p.database.SaveArtifact(ctx, p.runID, db.StepSources, db.CategoryResearch, companyCorpus.Sources)
+			_ = p.database.SaveTextArtifact(ctx, p.runID, db.StepCompanyCorpus, db.CategoryResearch, companyCorpus.Corpus)
+			_ = completeStep(ctx, p.database, p.runID, db.StepSources, nil)
+		}
+		p.companyCorpus = companyCorpus
+		return nil
+	}
+
+	// If Google Search API keys are present, try discovery
+	googleKey := os.Getenv("GOOGLE_SEARCH_API_KEY")
+	googleCX := os.Getenv("GOOGLE_SEARCH_CX")
+
+	if googleKey == "" || googleCX == "" {
+		slog.DebugContext(ctx, "Google Search API keys not found in environment",
+			"google_search_api_key", googleKey != "", "google_search_cx", googleCX != "")
+	}
+
+	if googleKey != "" && googleCX != "" && opts.Demo == nil {
+		if opts.Verbose {
+			slog.DebugContext(ctx, "Using Google Search for discovery...")
+		}
+		researcher, err := research.NewResearcher(ctx, googleKey, googleCX)
+		if err == nil {
+			// 1. Discover website if not provided
+			companyWebsite := opts.CompanySeedURL
+			if companyWebsite == "" && companyName != "" {
+				website, err := researcher.DiscoverCompanyWebsite(ctx, jobProfile)
+				if err != nil {
+					slog.WarnContext(ctx, "Failed to discover company website", "error", err)
+				} else if website != "" {
+					slog.InfoContext(ctx, "Discovered company website", "url", website)
+					companyWebsite = website
+				}
+			}
+
+			// Extract domain for research
+			if companyWebsite != "" {
+				companyDomain = research.ExtractDomain(companyWebsite)
+				seeds = append(seeds, companyWebsite)
+			}
+
+			// 2. Find voice seeds (About, Culture, Values pages)
+			if companyWebsite != "" || companyName != "" {
+				discoveredSeeds, err := researcher.FindVoiceSeeds(ctx, companyName, companyWebsite)
+				if err != nil {
+					slog.WarnContext(ctx, "Failed to find voice seeds", "error", err)
+				} else if len(discoveredSeeds) > 0 {
+					slog.InfoContext(ctx, "Discovered additional voice seeds", "seeds", len(discoveredSeeds))
+					seeds = append(seeds, discoveredSeeds...)
+				}
+			}
+		} else {
+			slog.WarnContext(ctx, "Failed to initialize researcher", "error", err)
+		}
+	}
+
+	// Add user-provided company seed if set (not already in seeds)
+	if opts.CompanySeedURL != "" {
+		found := false
+		for _, s := range seeds {
+			if s == opts.CompanySeedURL {
+				found = true
+				break
+			}
+		}
+		if !found {
+			seeds = append(seeds, opts.CompanySeedURL)
+		}
+		// Ensure domain is set
+		if companyDomain == "" {
+			companyDomain = research.ExtractDomain(opts.CompanySeedURL)
+		}
+	}
+
+	if len(seeds) == 0 && opts.Demo == nil {
+		return fmt.Errorf("no company seed URL provided and discovery failed. Set GOOGLE_SEARCH_API_KEY and GOOGLE_SEARCH_CX env vars for auto-discovery, or provide --company-seed")
+	}
+
+	slog.InfoContext(ctx, "Researching company voice with LLM-guided crawling...", "seeds", seeds)
+
+	// Use research module for smarter LLM-filtered crawling
+	// Resume from the company's previous session so only new or changed pages are processed
+	var prior *research.Session
+	if p.sharesResearch() {
+		prior = p.loadResearchState(ctx, companyName)
+	}
+	if prior != nil {
+		slog.InfoContext(ctx, "Resuming research from prior session",
+			"pages_crawled", len(prior.CrawledURLs), "pages_queued", len(prior.Frontier))
+	}
+	researchSession, err := p.runResearch(ctx, stepqueue.ResearchJob{
+		SeedURLs:      seeds,
+		Company:       companyName,
+		Domain:        companyDomain,
+		InitialCorpus: initialCorpus,
+		Verbose:       opts.Verbose,
+		UseBrowser:    opts.UseBrowser,
+		Prior:         prior,
+
+		MaxPages:       opts.Crawl.MaxPages,
+		MaxDepth:       opts.Crawl.MaxDepth,
+		SameDomainOnly: opts.Crawl.SameDomainOnly,
+		MaxBytes:       opts.Crawl.MaxBytes,
+		DomainPolicy:   p.loadDomainPolicy(ctx),
+
+		ArchiveFallback: opts.Crawl.ArchiveFallback,
+	})
+	if err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepSources, err)
+		return fmt.Errorf("research failed: %w", err)
+	}
+	if p.sharesResearch() {
+		p.saveResearchState(ctx, companyName, companyDomain, researchSession)
+	}
+	p.recordTechStack(ctx, p.techStackCompany(ctx, companyName), db.TechSourceBlog, researchSession.Corpus)
+
+	// Build corpus from research session
+	companyCorpus := &types.CompanyCorpus{
+		Corpus:  researchSession.Corpus,
+		Sources: researchSession.ToSources(),
+	}
+
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepSources, db.CategoryResearch, companyCorpus.Sources)
+		_ = p.database.SaveTextArtifact(ctx, p.runID, db.StepCompanyCorpus, db.CategoryResearch, companyCorpus.Corpus)
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepResearchSession, db.CategoryResearch, researchSession)
+		_ = p.database.SaveTextArtifact(ctx, p.runID, db.StepResearchReport, db.CategoryResearch, researchSession.Report())
+		_ = completeStep(ctx, p.database, p.runID, db.StepSources, nil)
+	}
+	p.companyCorpus = companyCorpus
+	return nil
+}
+
+// summarizeVoice derives the company voice profile from the research corpus
+func (p *pipelineRun) summarizeVoice(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 8/12: Summarizing company voice...")
+	if err := startStep(ctx, p.database, p.runID, db.StepCompanyProfile); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	companyName := p.researchCompanyName()
+	var companyProfile *types.CompanyProfile
+	if shared := p.sharedCompanyProfile(ctx, companyName); shared != nil {
+		slog.InfoContext(ctx, "Reusing shared company voice profile", "company", companyName)
+		companyProfile = voice.FromStoredProfile(shared)
+		companyProfile.Company = companyName
+	} else {
+		var err error
+		companyProfile, err = voice.SummarizeVoice(ctx, p.companyCorpus.Corpus, p.companyCorpus.Sources, p.opts.APIKey)
+		if err != nil {
+			_ = failStep(ctx, p.database, p.runID, db.StepCompanyProfile, err)
+			return fmt.Errorf("summarizing voice failed: %w", err)
+		}
+		p.publishCompanyProfile(ctx, companyName, companyProfile)
+	}
+	if p.opts.ToneOverride != "" {
+		companyProfile.Tone = p.opts.ToneOverride
+	}
+	if err := p.applyRulePacks(ctx, companyProfile); err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepCompanyProfile, err)
+		return fmt.Errorf("applying workspace rule packs failed: %w", err)
+	}
+	if p.opts.Verbose {
+		p.printer.PrintCompanyProfile(companyProfile)
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepCompanyProfile, db.CategoryResearch, companyProfile)
+		_ = completeStep(ctx, p.database, p.runID, db.StepCompanyProfile, nil)
+	}
+	emitProgress(p.opts, db.StepCompanyProfile, db.CategoryResearch,
+		fmt.Sprintf("Analyzed company voice: %s", companyProfile.Company), companyProfile)
+	p.companyProfile = companyProfile
+	return nil
+}
+
+// runResearch crawls company pages in-process or, when configured, on a worker.
+// Demo runs use the bundled pre-crawled pages instead.
+func (p *pipelineRun) runResearch(ctx context.Context, job stepqueue.ResearchJob) (*research.Session, error) {
+	if p.opts.Demo != nil {
+		slog.InfoContext(ctx, "Using pre-crawled demo research...")
+		return p.opts.Demo.ResearchSession(job.Company)
+	}
+	if p.remote.Remote(stepqueue.StepResearchCompany) {
+		slog.InfoContext(ctx, "Dispatching crawl to remote backend...", "backend", p.remote.Backend(stepqueue.StepResearchCompany))
+		var session research.Session
+		if err := p.remote.Run(ctx, p.runID, stepqueue.StepResearchCompany, job, &session); err != nil {
+			return nil, err
+		}
+		return &session, nil
+	}
+	return research.RunResearch(ctx, research.RunResearchOptions{
+		SeedURLs:      job.SeedURLs,
+		Company:       job.Company,
+		Domain:        job.Domain,
+		InitialCorpus: job.InitialCorpus,
+		APIKey:        p.opts.APIKey,
+		Verbose:       job.Verbose,
+		UseBrowser:    job.UseBrowser,
+		Prior:         job.Prior,
+
+		MaxPages:       job.MaxPages,
+		MaxDepth:       job.MaxDepth,
+		SameDomainOnly: job.SameDomainOnly,
+		MaxBytes:       job.MaxBytes,
+		DomainPolicy:   job.DomainPolicy,
+
+		ArchiveFallback: job.ArchiveFallback,
+	})
+}
+
+// kubernetesLauncher returns a Kubernetes Job launcher when any step is configured
+// with the kubernetes backend, or nil otherwise
+func kubernetesLauncher() (stepqueue.Launcher, error) {
+	for _, def := range steps.StepRegistry {
+		if def.Execution.Backend != steps.BackendKubernetes {
+			continue
+		}
+		cfg, err := kubejob.ConfigFromEnv()
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubernetes job configuration: %w", err)
+		}
+		return kubejob.NewLauncher(cfg), nil
+	}
+	return nil, nil
+}
+
+// validate compiles and checks the LaTeX in-process or, when configured, on a worker
+func (p *pipelineRun) validate(ctx context.Context, latex string, maxPages, maxCharsPerLine int, opts *validation.Options) (*types.Violations, error) {
+	if p.remote.Remote(stepqueue.StepValidateLaTeX) {
+		slog.InfoContext(ctx, "Dispatching LaTeX validation to remote backend...", "backend", p.remote.Backend(stepqueue.StepValidateLaTeX))
+		var violations types.Violations
+		job := stepqueue.ValidateJob{
+			LaTeX:           latex,
+			CompanyProfile:  p.companyProfile,
+			MaxPages:        maxPages,
+			MaxCharsPerLine: maxCharsPerLine,
+			Options:         opts,
+		}
+		if err := p.remote.Run(ctx, p.runID, stepqueue.StepValidateLaTeX, job, &violations); err != nil {
+			return nil, err
+		}
+		return &violations, nil
+	}
+	return validation.ValidateFromContent(latex, p.companyProfile, maxPages, maxCharsPerLine, opts)
+}
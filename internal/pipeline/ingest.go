@@ -0,0 +1,89 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/parsing"
+)
+
+// ingestPosting ingests the job posting for a run that already exists. RunPipeline
+// ingests before it has a run, so it only uses this through the step API.
+func (p *pipelineRun) ingestPosting(ctx context.Context) error {
+	if err := startStep(ctx, p.database, p.runID, db.StepJobPosting); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	cleanedText, jobMetadata, err := ingestJob(ctx, p.opts)
+	if err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepJobPosting, err)
+		return err
+	}
+	posting := recordPosting(ctx, p.database, p.opts, cleanedText, jobMetadata)
+	linkRunPosting(ctx, p.database, p.runID, posting, p.opts.UserID)
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveTextArtifact(ctx, p.runID, db.StepJobPosting, db.CategoryIngestion, cleanedText)
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepJobMetadata, db.CategoryIngestion, jobMetadata)
+		_ = completeStep(ctx, p.database, p.runID, db.StepJobPosting, nil)
+	}
+	emitProgress(p.opts, db.StepJobPosting, db.CategoryIngestion,
+		fmt.Sprintf("Ingested and cleaned job posting from %s", p.opts.JobURL), nil)
+	p.cleanedText, p.jobMetadata = cleanedText, jobMetadata
+	return nil
+}
+
+// parseJob parses the ingested posting into a job profile and names the run after it
+func (p *pipelineRun) parseJob(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 2/12: Parsing job profile...")
+	if err := startStep(ctx, p.database, p.runID, db.StepJobProfile); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	// The same role posted on another job board reuses that posting's parse
+	posting := p.runPosting(ctx)
+	jobProfile := reusedJobProfile(ctx, p.database, posting, p.cleanedText)
+	if jobProfile == nil {
+		var err error
+		jobProfile, err = parsing.ParseJobProfile(ctx, p.cleanedText, p.opts.APIKey)
+		if err != nil {
+			_ = failStep(ctx, p.database, p.runID, db.StepJobProfile, err)
+			return fmt.Errorf("job parsing failed: %w", err)
+		}
+	}
+	// A title read from the job board's API beats one the LLM picked out of the text
+	if p.jobMetadata != nil && p.jobMetadata.RoleTitle != "" {
+		jobProfile.RoleTitle = p.jobMetadata.RoleTitle
+	}
+	if p.opts.Verbose {
+		p.printer.PrintJobProfile(jobProfile)
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		if err := p.database.UpdateRunCompanyAndRole(ctx, p.runID, jobProfile.Company, jobProfile.RoleTitle); err != nil {
+			slog.WarnContext(ctx, "Failed to update run company/role", "error", err)
+		}
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepJobProfile, db.CategoryIngestion, jobProfile)
+		saveJobProfile(ctx, p.database, posting, jobProfile)
+		_ = completeStep(ctx, p.database, p.runID, db.StepJobProfile, nil)
+	}
+	emitProgress(p.opts, db.StepJobProfile, db.CategoryIngestion,
+		fmt.Sprintf("Parsed job profile: %s at %s", jobProfile.RoleTitle, jobProfile.Company), jobProfile)
+	p.jobProfile = jobProfile
+	return nil
+}
+
+// runPosting returns the job posting linked to the run when the posting was ingested
+func (p *pipelineRun) runPosting(ctx context.Context) *db.JobPosting {
+	if p.database == nil || p.runID == uuid.Nil {
+		return nil
+	}
+	posting, err := p.database.GetRunJobPosting(ctx, p.runID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load run job posting", "error", err)
+		return nil
+	}
+	return posting
+}
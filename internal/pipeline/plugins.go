@@ -0,0 +1,150 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+// runPluginSteps executes registered plugin steps after the built-in pipeline.
+// Plugins that depend on each other are ordered by the DAG executor; independent
+// plugins run concurrently. Plugin failures are reported but never fail the run.
+func runPluginSteps(ctx context.Context, database *db.DB, runID uuid.UUID, opts *RunOptions) {
+	if len(steps.Plugins) == 0 {
+		return
+	}
+	if database == nil || runID == uuid.Nil {
+		slog.InfoContext(ctx, "Skipping plugin steps: plugins exchange artifacts through the database", "plugins", len(steps.Plugins))
+		return
+	}
+
+	tasks := pluginTasks(steps.Plugins, func(p *steps.Plugin) func(context.Context) error {
+		return func(ctx context.Context) error {
+			return runPluginStep(ctx, database, runID, opts, p, nil)
+		}
+	})
+	if err := runDAG(ctx, tasks, resolveWorkers(opts)); err != nil {
+		slog.WarnContext(ctx, "Plugin steps stopped early", "error", err)
+	}
+}
+
+// pluginTasks builds DAG tasks for plugins, wiring dependencies between plugins.
+// Dependencies on built-in steps are already satisfied when plugins run.
+func pluginTasks(plugins map[string]*steps.Plugin, run func(*steps.Plugin) func(context.Context) error) []dagTask {
+	inGraph := make(map[string]bool, len(plugins))
+	for name := range plugins {
+		inGraph[name] = true
+	}
+
+	names := make([]string, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tasks := make([]dagTask, 0, len(names))
+	for _, name := range names {
+		tasks = append(tasks, dagTask{
+			Name: name,
+			Deps: registryDeps(name, inGraph),
+			Run:  run(plugins[name]),
+		})
+	}
+	return tasks
+}
+
+// runPluginStep gathers the plugin's input artifacts, invokes it with the step's
+// validated params (nil in a full pipeline run), and saves its output
+func runPluginStep(ctx context.Context, database *db.DB, runID uuid.UUID, opts *RunOptions, p *steps.Plugin, params map[string]interface{}) error {
+	name := p.Manifest.Name
+	ctx = logging.WithStep(ctx, name)
+	slog.InfoContext(ctx, "Running plugin step...")
+	if err := startStep(ctx, database, runID, name); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	inputs, err := pluginInputs(ctx, database, runID, p.InputSteps())
+	if err != nil {
+		_ = failStep(ctx, database, runID, name, err)
+		return err
+	}
+
+	resp, err := p.Run(ctx, pluginRequest(runID, name, params, inputs))
+	if err != nil {
+		_ = failStep(ctx, database, runID, name, err)
+		return err
+	}
+
+	for artifact, content := range resp.Artifacts {
+		if err := database.SaveArtifact(ctx, runID, artifact, db.CategoryPlugin, content); err != nil {
+			_ = failStep(ctx, database, runID, name, err)
+			return err
+		}
+	}
+	_ = completeStep(ctx, database, runID, name, nil)
+
+	message := resp.Message
+	if message == "" {
+		message = fmt.Sprintf("Plugin %s completed", name)
+	}
+	emitProgress(opts, name, db.CategoryPlugin, message, nil)
+	return nil
+}
+
+// pluginRequest builds the document written to a plugin's stdin
+func pluginRequest(runID uuid.UUID, step string, params map[string]interface{}, inputs map[string]json.RawMessage) *steps.PluginRequest {
+	return &steps.PluginRequest{
+		RunID:  runID.String(),
+		Step:   step,
+		Params: params,
+		Inputs: inputs,
+	}
+}
+
+// pluginInputs loads artifacts for the given step names. Built-in steps are
+// translated to the artifact they store; text artifacts are passed as JSON strings.
+// Missing artifacts (e.g. skipped optional steps) are omitted.
+func pluginInputs(ctx context.Context, database *db.DB, runID uuid.UUID, stepNames []string) (map[string]json.RawMessage, error) {
+	artifactFor := make(map[string]string, len(stepNameMap))
+	for artifact, step := range stepNameMap {
+		artifactFor[step] = artifact
+	}
+
+	inputs := make(map[string]json.RawMessage, len(stepNames))
+	for _, step := range stepNames {
+		artifact := step
+		if mapped, ok := artifactFor[step]; ok {
+			artifact = mapped
+		}
+
+		content, err := database.GetArtifact(ctx, runID, artifact)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load input %s: %w", step, err)
+		}
+		if len(content) > 0 {
+			inputs[step] = content
+			continue
+		}
+
+		text, err := database.GetTextArtifact(ctx, runID, artifact)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load input %s: %w", step, err)
+		}
+		if text != "" {
+			encoded, err := json.Marshal(text)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode input %s: %w", step, err)
+			}
+			inputs[step] = encoded
+		}
+	}
+	return inputs, nil
+}
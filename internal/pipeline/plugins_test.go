@@ -0,0 +1,78 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+func TestPluginTasks_WiresPluginDependencies(t *testing.T) {
+	original := steps.StepRegistry
+	t.Cleanup(func() { steps.StepRegistry = original })
+	steps.StepRegistry = map[string]steps.StepDefinition{
+		"validate_latex": original["validate_latex"],
+		"publish":        {Name: "publish", Dependencies: []string{"validate_latex"}},
+		"notify":         {Name: "notify", Dependencies: []string{"publish"}},
+	}
+	plugins := map[string]*steps.Plugin{
+		"publish": {Manifest: steps.PluginManifest{Name: "publish"}},
+		"notify":  {Manifest: steps.PluginManifest{Name: "notify"}},
+	}
+
+	var order []string
+	tasks := pluginTasks(plugins, func(p *steps.Plugin) func(context.Context) error {
+		return func(_ context.Context) error {
+			order = append(order, p.Manifest.Name)
+			return nil
+		}
+	})
+
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "notify", tasks[0].Name)
+	assert.Equal(t, []string{"publish"}, tasks[0].Deps)
+	assert.Empty(t, tasks[1].Deps, "built-in dependencies are complete before plugins run")
+
+	require.NoError(t, runDAG(context.Background(), tasks, 1))
+	assert.Equal(t, []string{"publish", "notify"}, order)
+}
+
+func TestRunPluginSteps_NoDatabase(_ *testing.T) {
+	original := steps.Plugins
+	defer func() { steps.Plugins = original }()
+	steps.Plugins = map[string]*steps.Plugin{"publish": {Manifest: steps.PluginManifest{Name: "publish"}}}
+
+	// Must be a no-op without a database or run ID
+	runPluginSteps(context.Background(), nil, uuid.Nil, &RunOptions{})
+}
+
+func TestPluginRequest_PassesParams(t *testing.T) {
+	dir := t.TempDir()
+	// Echo the request's params back inside a declared artifact
+	script := `#!/bin/sh
+read -r line
+params=$(echo "$line" | sed 's/.*"params":\({[^}]*}\).*/\1/')
+printf '{"artifacts":{"echo_out":%s}}' "$params"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "echo.sh"), []byte(script), 0o700)) //nolint:gosec // test executable
+	p := &steps.Plugin{
+		Manifest: steps.PluginManifest{Name: "echo", Command: []string{"./echo.sh"}, Artifacts: []string{"echo_out"}},
+		Dir:      dir,
+	}
+
+	params := map[string]interface{}{"tone": "warm", "limit": float64(3)}
+	inputs := map[string]json.RawMessage{"rewrite_bullets": json.RawMessage(`{"bullets":[]}`)}
+	resp, err := p.Run(context.Background(), pluginRequest(uuid.New(), "echo", params, inputs))
+	require.NoError(t, err)
+
+	var echoed map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Artifacts["echo_out"], &echoed))
+	assert.Equal(t, params, echoed)
+}
@@ -0,0 +1,236 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/ingestion"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// recordPosting stores the ingested posting and links it to the canonical posting it
+// duplicates, so the same role found on several job boards shares one parse. It returns
+// nil without a database or a URL to key the posting by, and for demo runs.
+func recordPosting(ctx context.Context, database *db.DB, opts *RunOptions, cleanedText string, metadata *ingestion.Metadata) *db.JobPosting {
+	if database == nil || opts.JobURL == "" || opts.Demo != nil {
+		return nil
+	}
+	input := &db.JobPostingCreateInput{
+		URL:         opts.JobURL,
+		Platform:    db.DetectPlatform(opts.JobURL),
+		CleanedText: cleanedText,
+		HTTPStatus:  http.StatusOK,
+	}
+	if metadata != nil {
+		input.AboutCompany = metadata.AboutCompany
+		input.Links = metadata.ExtractedLinks
+	}
+	posting, err := database.UpsertJobPosting(ctx, input)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to store job posting", "error", err)
+		return nil
+	}
+
+	dup, err := database.LinkDuplicatePosting(ctx, posting, cleanedText)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to check for duplicate postings", "error", err)
+	} else if dup != nil {
+		slog.InfoContext(ctx, "Posting duplicates an earlier posting",
+			"canonical_url", dup.CanonicalURL, "match", dup.Match, "similarity", dup.Similarity)
+	}
+	return posting
+}
+
+// reusedJobProfile returns the job profile an earlier run parsed from the posting or one of
+// its duplicates. A profile parsed from text that has since changed is not reused.
+func reusedJobProfile(ctx context.Context, database *db.DB, posting *db.JobPosting, cleanedText string) *types.JobProfile {
+	if database == nil || posting == nil {
+		return nil
+	}
+	runID, err := database.FindParsedPostingRun(ctx, posting.CanonicalID())
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to look up earlier parses of this posting", "error", err)
+		return nil
+	}
+	if runID == uuid.Nil {
+		return nil
+	}
+	parsedText, err := database.GetTextArtifact(ctx, runID, db.StepJobPosting)
+	if err != nil || db.PostingSimilarity(parsedText, cleanedText) < db.DuplicatePostingThreshold {
+		return nil
+	}
+	profile, err := database.GetJobProfileByRunID(ctx, runID)
+	if err != nil || profile == nil {
+		return nil
+	}
+	slog.InfoContext(ctx, "Reusing job profile parsed by an earlier run", "parsed_by_run_id", runID)
+	return profile
+}
+
+// linkRunPosting records the posting a run was created from and notes the owner's
+// earlier runs for the same role on any job board
+func linkRunPosting(ctx context.Context, database *db.DB, runID uuid.UUID, posting *db.JobPosting, userID *uuid.UUID) {
+	if database == nil || runID == uuid.Nil || posting == nil {
+		return
+	}
+	if err := database.SetRunJobPosting(ctx, runID, posting.ID); err != nil {
+		slog.WarnContext(ctx, "Failed to link run to job posting", "error", err)
+		return
+	}
+	if userID == nil {
+		return
+	}
+	runs, err := database.ListPostingRuns(ctx, posting.CanonicalID(), *userID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to list earlier runs for this posting", "error", err)
+		return
+	}
+	for _, run := range runs {
+		if run.ID != runID {
+			slog.InfoContext(ctx, "Note: You already have other runs for this role",
+				"other_runs", len(runs)-1, "latest_run_id", run.ID, "latest_url", run.JobURL, "latest_status", run.Status)
+			return
+		}
+	}
+}
+
+// saveJobProfile stores the parsed profile as the posting's job profile, so its owner can
+// weight the requirements through the API before stories are ranked
+func saveJobProfile(ctx context.Context, database *db.DB, posting *db.JobPosting, profile *types.JobProfile) {
+	if database == nil || posting == nil || profile == nil {
+		return
+	}
+	input := &db.JobProfileCreateInput{
+		PostingID:        posting.ID,
+		CompanyName:      profile.Company,
+		RoleTitle:        profile.RoleTitle,
+		Responsibilities: profile.Responsibilities,
+		HardRequirements: requirementInputs(profile.HardRequirements),
+		NiceToHaves:      requirementInputs(profile.NiceToHaves),
+		Keywords:         profile.Keywords,
+	}
+	if s := profile.EvalSignals; s != nil {
+		input.EvalLatency, input.EvalReliability, input.EvalOwnership = s.Latency, s.Reliability, s.Ownership
+		input.EvalScale, input.EvalCollaboration = s.Scale, s.Collaboration
+	}
+	if e := profile.EducationRequirements; e != nil {
+		input.EducationMinDegree, input.EducationPreferredFields = e.MinDegree, e.PreferredFields
+		input.EducationIsRequired, input.EducationEvidence = e.IsRequired, e.Evidence
+	}
+	if _, err := database.CreateJobProfile(ctx, input); err != nil {
+		slog.WarnContext(ctx, "Failed to store job profile for posting", "error", err)
+	}
+}
+
+// requirementInputs converts parsed requirements for CreateJobProfile
+func requirementInputs(reqs []types.Requirement) []db.RequirementInput {
+	inputs := make([]db.RequirementInput, len(reqs))
+	for i, req := range reqs {
+		inputs[i] = db.RequirementInput{Skill: req.Skill, Level: req.Level, Evidence: req.Evidence}
+	}
+	return inputs
+}
+
+// weightedJobProfile returns the job profile with the requirement weights and keyword
+// changes the run's owner made for its posting or the posting's duplicates, or the profile
+// itself when they made none
+func (p *pipelineRun) weightedJobProfile(ctx context.Context) *types.JobProfile {
+	if p.database == nil || p.opts.UserID == nil {
+		return p.jobProfile
+	}
+	posting := p.runPosting(ctx)
+	if posting == nil {
+		return p.jobProfile
+	}
+	profile := p.jobProfile
+	weights, err := p.database.GetPostingRequirementWeights(ctx, *p.opts.UserID, posting.CanonicalID())
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load requirement weights", "error", err)
+	} else {
+		var applied int
+		profile, applied = applyRequirementWeights(profile, weights)
+		if applied > 0 {
+			slog.InfoContext(ctx, "Applying requirement weights set for this posting", "weights", applied)
+		}
+	}
+	overrides, err := p.database.GetPostingKeywordOverrides(ctx, *p.opts.UserID, posting.CanonicalID())
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load keyword changes", "error", err)
+	} else if len(overrides) > 0 {
+		profile = applyKeywordOverrides(profile, overrides)
+		slog.InfoContext(ctx, "Applying keyword changes made for this posting", "keyword_changes", len(overrides))
+	}
+	return profile
+}
+
+// applyRequirementWeights returns a copy of profile whose requirements carry the matching
+// weights, and how many requirements were weighted. Later weights for the same requirement win.
+func applyRequirementWeights(profile *types.JobProfile, weights []db.RequirementWeight) (*types.JobProfile, int) {
+	if len(weights) == 0 {
+		return profile, 0
+	}
+	byKey := make(map[string]float64, len(weights))
+	for _, w := range weights {
+		byKey[w.RequirementType+"\x00"+w.Skill] = w.Weight
+	}
+
+	applied := 0
+	weigh := func(reqs []types.Requirement, requirementType string) []types.Requirement {
+		out := make([]types.Requirement, len(reqs))
+		for i, req := range reqs {
+			out[i] = req
+			if weight, ok := byKey[requirementType+"\x00"+db.NormalizeKeyword(req.Skill)]; ok {
+				out[i].Weight = &weight
+				applied++
+			}
+		}
+		return out
+	}
+	weighted := *profile
+	weighted.HardRequirements = weigh(profile.HardRequirements, db.RequirementTypeHard)
+	weighted.NiceToHaves = weigh(profile.NiceToHaves, db.RequirementTypeNiceToHave)
+	return &weighted, applied
+}
+
+// applyKeywordOverrides returns a copy of profile without the keywords the user hid and
+// with the ones they added, which are also listed as its user keywords. Later changes to
+// the same keyword win.
+func applyKeywordOverrides(profile *types.JobProfile, overrides []db.KeywordOverride) *types.JobProfile {
+	if len(overrides) == 0 {
+		return profile
+	}
+	final := make(map[string]db.KeywordOverride, len(overrides))
+	for _, o := range overrides {
+		final[db.NormalizeKeyword(o.Keyword)] = o
+	}
+
+	changed := *profile
+	changed.Keywords = make([]string, 0, len(profile.Keywords)+len(overrides))
+	changed.UserKeywords = nil
+	seen := make(map[string]bool)
+	for _, keyword := range profile.Keywords {
+		normalized := db.NormalizeKeyword(keyword)
+		if o, ok := final[normalized]; ok && o.Excluded {
+			continue
+		}
+		seen[normalized] = true
+		changed.Keywords = append(changed.Keywords, keyword)
+	}
+	for _, o := range overrides {
+		normalized := db.NormalizeKeyword(o.Keyword)
+		if final[normalized] != o || o.Excluded {
+			continue
+		}
+		delete(final, normalized) // The same change may be recorded for several postings
+		if !seen[normalized] {
+			seen[normalized] = true
+			changed.Keywords = append(changed.Keywords, o.Keyword)
+		}
+		changed.UserKeywords = append(changed.UserKeywords, o.Keyword)
+	}
+	return &changed
+}
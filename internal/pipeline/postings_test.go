@@ -0,0 +1,55 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRequirementWeights(t *testing.T) {
+	profile := &types.JobProfile{
+		HardRequirements: []types.Requirement{{Skill: "Go"}, {Skill: " Kafka "}},
+		NiceToHaves:      []types.Requirement{{Skill: "Kafka"}, {Skill: "Rust"}},
+	}
+
+	weighted, applied := applyRequirementWeights(profile, []db.RequirementWeight{
+		{RequirementType: db.RequirementTypeHard, Skill: "kafka", Weight: 1.5},
+		{RequirementType: db.RequirementTypeHard, Skill: "kafka", Weight: 3}, // Set later
+		{RequirementType: db.RequirementTypeNiceToHave, Skill: "rust", Weight: 0},
+		{RequirementType: db.RequirementTypeNiceToHave, Skill: "elixir", Weight: 2},
+	})
+	assert.Equal(t, 2, applied)
+	assert.Nil(t, weighted.HardRequirements[0].Weight)
+	require.NotNil(t, weighted.HardRequirements[1].Weight)
+	assert.Equal(t, 3.0, *weighted.HardRequirements[1].Weight)
+	assert.Nil(t, weighted.NiceToHaves[0].Weight, "weights apply to one requirement type")
+	require.NotNil(t, weighted.NiceToHaves[1].Weight)
+	assert.Equal(t, 0.0, *weighted.NiceToHaves[1].Weight)
+
+	assert.Nil(t, profile.HardRequirements[1].Weight, "the run's job profile is not modified")
+
+	same, applied := applyRequirementWeights(profile, nil)
+	assert.Same(t, profile, same)
+	assert.Zero(t, applied)
+}
+
+func TestApplyKeywordOverrides(t *testing.T) {
+	profile := &types.JobProfile{Keywords: []string{"Go", "Kafka", "Agile", "kafka"}}
+
+	changed := applyKeywordOverrides(profile, []db.KeywordOverride{
+		{Keyword: "agile", Excluded: true},
+		{Keyword: "Terraform"},
+		{Keyword: "Go"},
+		{Keyword: "gRPC"},
+		{Keyword: "grpc", Excluded: true}, // Hidden later
+		{Keyword: "Terraform"},            // Recorded for a duplicate posting too
+	})
+	assert.Equal(t, []string{"Go", "Kafka", "kafka", "Terraform"}, changed.Keywords)
+	assert.Equal(t, []string{"Terraform", "Go"}, changed.UserKeywords)
+	assert.Equal(t, []string{"Go", "Kafka", "Agile", "kafka"}, profile.Keywords, "the run's job profile is not modified")
+
+	assert.Same(t, profile, applyKeywordOverrides(profile, nil))
+}
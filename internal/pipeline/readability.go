@@ -0,0 +1,25 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/readability"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// reportReadability scores the resume's final bullets for reading level and jargon
+// against the company's tone, and stores the report as a run artifact
+func reportReadability(ctx context.Context, database *db.DB, runID uuid.UUID, opts *RunOptions, bullets *types.RewrittenBullets, profile *types.CompanyProfile) {
+	report := readability.Analyze(bullets, profile)
+	slog.InfoContext(ctx, "Readability: "+report.Summary())
+	if database != nil && runID != uuid.Nil {
+		if err := database.SaveArtifact(ctx, runID, db.StepReadabilityReport, db.CategoryValidation, report); err != nil {
+			slog.WarnContext(ctx, "Failed to save readability report", "error", err)
+		}
+	}
+	emitProgress(opts, db.StepReadabilityReport, db.CategoryValidation, "Readability: "+report.Summary(), report)
+}
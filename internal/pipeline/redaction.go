@@ -0,0 +1,44 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/redact"
+)
+
+// withPIIMasker attaches a masker to ctx so prompts sent to external LLM
+// providers carry placeholders instead of the candidate's contact details
+func withPIIMasker(ctx context.Context, opts *RunOptions) (context.Context, *redact.Masker) {
+	masker := redact.NewMasker(opts.CandidateName, opts.CandidateEmail, opts.CandidatePhone)
+	return llm.WithMasker(ctx, masker), masker
+}
+
+// redactionReport summarizes a run's masking. The provider is the one the run's
+// LLM clients were actually created for; config is only used when the run never
+// created a client. Masking is disabled for local providers because prompts
+// never leave the machine.
+func redactionReport(masker *redact.Masker, config *llm.Config) redact.Report {
+	report := masker.Report()
+	if report.Provider == "" {
+		report.Provider = string(config.Provider)
+		report.Enabled = !config.IsLocal()
+	}
+	return report
+}
+
+// saveRedactionReport persists the redaction report artifact when the run ends
+func saveRedactionReport(ctx context.Context, database *db.DB, runID uuid.UUID, masker *redact.Masker) {
+	if database == nil || runID == uuid.Nil || masker == nil {
+		return
+	}
+
+	report := redactionReport(masker, llm.DefaultConfig())
+	if err := database.SaveArtifact(context.WithoutCancel(ctx), runID, db.StepRedactionReport, db.CategoryPrivacy, report); err != nil {
+		slog.WarnContext(ctx, "Failed to save redaction report", "error", err)
+	}
+}
@@ -0,0 +1,63 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/redact"
+)
+
+func TestWithPIIMasker_MasksCandidateDetails(t *testing.T) {
+	opts := &RunOptions{
+		CandidateName:  "Jane Doe",
+		CandidateEmail: "jane@example.com",
+		CandidatePhone: "555-867-5309",
+	}
+
+	ctx, masker := withPIIMasker(context.Background(), opts)
+	require.NotNil(t, masker)
+	assert.Same(t, masker, llm.MaskerFromContext(ctx))
+
+	masked := masker.Mask("Jane Doe (jane@example.com, 555-867-5309)")
+	assert.NotContains(t, masked, "Jane")
+	assert.NotContains(t, masked, "jane@example.com")
+	assert.NotContains(t, masked, "867-5309")
+	assert.Equal(t, "Jane Doe (jane@example.com, 555-867-5309)", masker.Restore(masked))
+}
+
+func TestRedactionReport_Provider(t *testing.T) {
+	masker := redact.NewMasker("Jane Doe", "", "")
+	masker.Mask("Jane Doe")
+
+	report := redactionReport(masker, &llm.Config{Provider: llm.ProviderGemini})
+	assert.True(t, report.Enabled)
+	assert.Equal(t, "gemini", report.Provider)
+	assert.Equal(t, 1, report.Masked[redact.KindName])
+
+	report = redactionReport(masker, &llm.Config{Provider: llm.ProviderOllama})
+	assert.False(t, report.Enabled)
+}
+
+func TestRedactionReport_EffectiveProvider(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", string(llm.ProviderOllama))
+	ctx, masker := withPIIMasker(context.Background(), &RunOptions{CandidateName: "Jane Doe"})
+	client, err := llm.NewClient(ctx, nil, "")
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	// The run's clients were local even though the fallback config is external
+	report := redactionReport(masker, &llm.Config{Provider: llm.ProviderGemini})
+	assert.False(t, report.Enabled)
+	assert.Equal(t, "ollama", report.Provider)
+}
+
+func TestSaveRedactionReport_NoDatabase(_ *testing.T) {
+	// Must be a no-op without a database or run ID
+	saveRedactionReport(context.Background(), nil, uuid.New(), redact.NewMasker("", "", ""))
+	saveRedactionReport(context.Background(), nil, uuid.Nil, nil)
+}
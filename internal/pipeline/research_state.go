@@ -0,0 +1,78 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/research"
+)
+
+// loadResearchState returns the company's most recent research session so the
+// crawl can resume from it, or nil when there is none (or no database)
+func (p *pipelineRun) loadResearchState(ctx context.Context, companyName string) *research.Session {
+	if p.database == nil || companyName == "" {
+		return nil
+	}
+	company, err := p.database.FindOrCreateCompany(ctx, companyName)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to look up company for research state", "error", err)
+		return nil
+	}
+	raw, err := p.database.GetLatestResearchState(ctx, company.ID)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load prior research state", "error", err)
+		return nil
+	}
+	return decodeResearchState(ctx, raw)
+}
+
+// decodeResearchState parses a stored research session, ignoring unreadable state
+func decodeResearchState(ctx context.Context, raw []byte) *research.Session {
+	if len(raw) == 0 {
+		return nil
+	}
+	var session research.Session
+	if err := json.Unmarshal(raw, &session); err != nil {
+		slog.WarnContext(ctx, "Ignoring unreadable research state", "error", err)
+		return nil
+	}
+	return &session
+}
+
+// saveResearchState records the finished session keyed by company so the next run resumes from it
+func (p *pipelineRun) saveResearchState(ctx context.Context, companyName, domain string, session *research.Session) {
+	if p.database == nil || companyName == "" || session == nil {
+		return
+	}
+	company, err := p.database.FindOrCreateCompany(ctx, companyName)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to look up company for research state", "error", err)
+		return
+	}
+
+	input := &db.ResearchSessionInput{
+		CompanyID:   &company.ID,
+		CompanyName: companyName,
+		Domain:      domain,
+	}
+	if p.runID != uuid.Nil {
+		input.RunID = &p.runID
+	}
+	row, err := p.database.CreateResearchSession(ctx, input)
+	if err == nil {
+		err = p.database.UpdateResearchSessionProgress(ctx, row.ID, len(session.CrawledURLs), session.Corpus)
+	}
+	if err == nil {
+		err = p.database.SaveResearchState(ctx, row.ID, session)
+	}
+	if err == nil {
+		err = p.database.UpdateResearchSessionStatus(ctx, row.ID, db.ResearchStatusCompleted, "")
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to save research state", "error", err)
+	}
+}
@@ -0,0 +1,28 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/research"
+)
+
+func TestDecodeResearchState(t *testing.T) {
+	assert.Nil(t, decodeResearchState(context.Background(), nil))
+	assert.Nil(t, decodeResearchState(context.Background(), []byte("not json")))
+
+	session := decodeResearchState(context.Background(), []byte(`{"company":"Acme","crawled_urls":["https://acme.com/values"],"pages":{"https://acme.com/values":{"hash":"abc"}}}`))
+	require.NotNil(t, session)
+	assert.Equal(t, "Acme", session.Company)
+	assert.Equal(t, "abc", session.Pages["https://acme.com/values"].Hash)
+}
+
+func TestResearchState_NoDatabase(t *testing.T) {
+	p := &pipelineRun{opts: &RunOptions{}}
+	assert.Nil(t, p.loadResearchState(context.Background(), "Acme"))
+	// Must be a no-op without a database
+	p.saveResearchState(context.Background(), "Acme", "acme.com", &research.Session{})
+}
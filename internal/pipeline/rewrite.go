@@ -0,0 +1,198 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/repair"
+	"github.com/jonathan/resume-customizer/internal/rewriting"
+	"github.com/jonathan/resume-customizer/internal/validation"
+)
+
+// Resume limits enforced by validation and the repair loop
+const (
+	MaxResumePages      = 2   // Most pages RunOptions.MaxPages may allow
+	maxCharsPerLine     = 200 // Two lines
+	maxRepairIterations = 5
+)
+
+// resumePages returns the pages the resume may fill: MaxPages, or one when unset
+func (o *RunOptions) resumePages() int {
+	if o.MaxPages < 1 {
+		return 1
+	}
+	return min(o.MaxPages, MaxResumePages)
+}
+
+// rewriteBullets rewrites the selected bullets in the company's voice (requires both branches)
+func (p *pipelineRun) rewriteBullets(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 9/12: Rewriting bullets to match voice...")
+	if err := startStep(ctx, p.database, p.runID, db.StepRewrittenBullets); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	rewrittenBullets, err := rewriting.RewriteBullets(ctx, p.selectedBullets, p.jobProfile, p.companyProfile, p.opts.APIKey)
+	if err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepRewrittenBullets, err)
+		return fmt.Errorf("rewriting bullets failed: %w", err)
+	}
+	if p.opts.Verbose {
+		p.printer.PrintRewrittenBullets(rewrittenBullets)
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepRewrittenBullets, db.CategoryRewriting, rewrittenBullets)
+		_ = completeStep(ctx, p.database, p.runID, db.StepRewrittenBullets, nil)
+	}
+	emitProgress(p.opts, db.StepRewrittenBullets, db.CategoryRewriting,
+		fmt.Sprintf("Rewritten %d bullets", len(rewrittenBullets.Bullets)), nil)
+	p.rewrittenBullets = rewrittenBullets
+	return nil
+}
+
+// writeCoverLetter writes a cover letter from the rewritten bullets in the company's voice
+func (p *pipelineRun) writeCoverLetter(ctx context.Context) error {
+	slog.InfoContext(ctx, "Writing cover letter...")
+	if err := startStep(ctx, p.database, p.runID, db.StepCoverLetter); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	letter, err := rewriting.WriteCoverLetter(ctx, p.rewrittenBullets, p.jobProfile, p.companyProfile, p.opts.CandidateName, p.opts.APIKey)
+	if err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepCoverLetter, err)
+		return fmt.Errorf("writing cover letter failed: %w", err)
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveTextArtifact(ctx, p.runID, db.StepCoverLetter, db.CategoryRewriting, letter)
+		_ = completeStep(ctx, p.database, p.runID, db.StepCoverLetter, nil)
+	}
+	emitProgress(p.opts, db.StepCoverLetter, db.CategoryRewriting, "Wrote cover letter", nil)
+	return nil
+}
+
+// renderLaTeX renders the plan and rewritten bullets into the LaTeX template
+func (p *pipelineRun) renderLaTeX(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 10/12: Rendering LaTeX resume...")
+	if err := startStep(ctx, p.database, p.runID, db.StepResumeTex); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	latex, lineMap, err := p.render()
+	if err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepResumeTex, err)
+		return fmt.Errorf("rendering latex failed: %w", err)
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveTextArtifact(ctx, p.runID, db.StepResumeTex, db.CategoryValidation, latex)
+		_ = completeStep(ctx, p.database, p.runID, db.StepResumeTex, nil)
+	}
+	emitProgress(p.opts, db.StepResumeTex, db.CategoryValidation, "Rendered LaTeX resume", nil)
+	p.resumeTex, p.lineMap = latex, lineMap
+	return nil
+}
+
+// render produces the LaTeX for the current plan and bullets
+func (p *pipelineRun) render() (string, *rendering.LineBulletMap, error) {
+	return rendering.RenderStyledLaTeX(p.resumePlan, p.rewrittenBullets, p.opts.TemplatePath, p.opts.Style,
+		p.opts.CandidateName, p.opts.CandidateEmail, p.opts.CandidatePhone, p.experienceBank, p.selectedEducation)
+}
+
+// validateLaTeX compiles the LaTeX and checks page, line, and style constraints
+func (p *pipelineRun) validateLaTeX(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 11/12: Validating LaTeX constraints...")
+	if err := startStep(ctx, p.database, p.runID, db.StepViolations); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	// Create validation options with line-to-bullet mapping
+	var validationOpts *validation.Options
+	if p.lineMap != nil {
+		// Compute forbidden phrase mapping from rewritten bullets
+		forbiddenPhraseMap := rewriting.CheckForbiddenPhrasesInBullets(p.rewrittenBullets, p.companyProfile)
+
+		validationOpts = &validation.Options{
+			LineToBulletMap:    p.lineMap.LineToBullet,
+			Bullets:            p.rewrittenBullets,
+			Plan:               p.resumePlan,
+			ForbiddenPhraseMap: forbiddenPhraseMap,
+		}
+	}
+
+	violations, err := p.validate(ctx, p.resumeTex, p.opts.resumePages(), maxCharsPerLine, validationOpts)
+	if err != nil {
+		_ = failStep(ctx, p.database, p.runID, db.StepViolations, err)
+		return fmt.Errorf("validating latex failed: %w", err)
+	}
+	if p.opts.Verbose {
+		p.printer.PrintViolations(violations)
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepViolations, db.CategoryValidation, violations)
+		_ = completeStep(ctx, p.database, p.runID, db.StepViolations, nil)
+	}
+	p.violations = violations
+	return nil
+}
+
+// repairViolations proposes and applies fixes until the resume validates or the
+// iteration limit is reached, replacing the plan, bullets, and LaTeX with the result
+func (p *pipelineRun) repairViolations(ctx context.Context) error {
+	if p.violations == nil || len(p.violations.Violations) == 0 {
+		slog.InfoContext(ctx, "Step 12/12: Validation passed! No repairs needed.")
+		return nil
+	}
+	slog.InfoContext(ctx, "Step 12/12: Violations found, entering repair loop...", "violations", len(p.violations.Violations))
+	if err := startStep(ctx, p.database, p.runID, "repair_violations"); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	candidateInfo := repair.CandidateInfo{
+		Name:  p.opts.CandidateName,
+		Email: p.opts.CandidateEmail,
+		Phone: p.opts.CandidatePhone,
+	}
+	finalPlan, finalBullets, finalLaTeX, finalViolations, iterations, err := repair.RunRepairLoop(
+		ctx,
+		p.resumePlan,
+		p.rewrittenBullets,
+		p.violations,
+		p.rankedStories,
+		p.jobProfile,
+		p.companyProfile,
+		p.experienceBank,
+		p.opts.TemplatePath,
+		p.opts.Style,
+		candidateInfo,
+		p.selectedEducation,
+		p.opts.resumePages(),
+		maxCharsPerLine,
+		maxRepairIterations,
+		p.opts.APIKey,
+	)
+	if err != nil {
+		_ = failStep(ctx, p.database, p.runID, "repair_violations", err)
+		return fmt.Errorf("repair loop failed: %w", err)
+	}
+
+	// Save the final artifacts as new versions; the ones their steps produced stay as
+	// earlier versions so the artifact diff can show what the repair loop changed
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepResumePlan, db.CategoryExperience, finalPlan)
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepRewrittenBullets, db.CategoryRewriting, finalBullets)
+		_ = p.database.SaveTextArtifact(ctx, p.runID, db.StepResumeTex, db.CategoryValidation, finalLaTeX)
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepViolations, db.CategoryValidation, finalViolations)
+		_ = completeStep(ctx, p.database, p.runID, "repair_violations", nil)
+	}
+
+	if finalViolations != nil && len(finalViolations.Violations) > 0 {
+		slog.WarnContext(ctx, "Repair loop finished but violations remain", "iterations", iterations, "violations", len(finalViolations.Violations))
+	} else {
+		slog.InfoContext(ctx, "Successfully repaired all violations", "iterations", iterations)
+	}
+	p.resumePlan, p.rewrittenBullets, p.resumeTex, p.violations = finalPlan, finalBullets, finalLaTeX, finalViolations
+	return nil
+}
@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+// resolveModelRouting returns the routing from options, then MODEL_ROUTING_CONFIG, or nil
+func resolveModelRouting(opts *RunOptions) (*llm.Routing, error) {
+	if opts.ModelRouting != nil {
+		return opts.ModelRouting, nil
+	}
+	path := os.Getenv("MODEL_ROUTING_CONFIG")
+	if path == "" {
+		return nil, nil
+	}
+	routing, err := llm.LoadRouting(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateModelRouting(routing); err != nil {
+		return nil, err
+	}
+	return routing, nil
+}
+
+// ValidateModelRouting checks that every routed step exists in the step registry
+func ValidateModelRouting(routing *llm.Routing) error {
+	if routing == nil {
+		return nil
+	}
+	for step := range routing.Steps {
+		if _, ok := steps.StepRegistry[step]; !ok {
+			return fmt.Errorf("model routing config: unknown step %s", step)
+		}
+	}
+	return nil
+}
+
+// withStepModel returns a context whose LLM clients use the model routed to step
+// (a registry step name), downgraded when the user's quota is low, and are bounded
+// by the step's LLM budget
+func withStepModel(ctx context.Context, opts *RunOptions, step string) context.Context {
+	ctx = steps.WithLLMBudget(ctx, step)
+	sel, err := opts.ModelRouting.Select(step, "", opts.ModelDowngrade)
+	if err != nil || sel.IsZero() {
+		return ctx
+	}
+	return llm.WithSelection(ctx, sel)
+}
+
+// routedTask wraps a step graph task so its LLM calls use the step's routed model, its
+// log lines name the step, and a run over the step's latency budget is logged
+func (p *pipelineRun) routedTask(step string, run func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		ctx = logging.WithStep(ctx, step)
+		start := time.Now()
+		if err := run(withStepModel(ctx, p.opts, step)); err != nil {
+			return err
+		}
+		warnOverLatencyBudget(ctx, step, time.Since(start))
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+)
+
+func TestValidateModelRouting(t *testing.T) {
+	assert.NoError(t, ValidateModelRouting(nil))
+	assert.NoError(t, ValidateModelRouting(&llm.Routing{Steps: map[string]string{"rewrite_bullets": "advanced"}}))
+	assert.Error(t, ValidateModelRouting(&llm.Routing{Steps: map[string]string{"no_such_step": "lite"}}))
+}
+
+func TestWithStepModel(t *testing.T) {
+	opts := &RunOptions{ModelRouting: &llm.Routing{Steps: map[string]string{"parse_job": "lite"}}}
+
+	sel, ok := llm.SelectionFromContext(withStepModel(context.Background(), opts, "parse_job"))
+	require.True(t, ok)
+	assert.Equal(t, llm.TierLite, sel.Tier)
+
+	// Unrouted steps keep the callers' tiers
+	_, ok = llm.SelectionFromContext(withStepModel(context.Background(), opts, "rank_stories"))
+	assert.False(t, ok)
+
+	// Low quota downgrades every step, routed or not
+	opts.ModelDowngrade = true
+	sel, ok = llm.SelectionFromContext(withStepModel(context.Background(), opts, "rank_stories"))
+	require.True(t, ok)
+	assert.True(t, sel.Downgrade)
+}
+
+func TestResolveModelRouting(t *testing.T) {
+	t.Setenv("MODEL_ROUTING_CONFIG", "")
+	routing, err := resolveModelRouting(&RunOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, routing)
+
+	t.Setenv("MODEL_ROUTING_CONFIG", "/nonexistent/routing.json")
+	_, err = resolveModelRouting(&RunOptions{})
+	assert.Error(t, err)
+
+	want := &llm.Routing{}
+	routing, err = resolveModelRouting(&RunOptions{ModelRouting: want})
+	require.NoError(t, err)
+	assert.Same(t, want, routing)
+}
@@ -0,0 +1,58 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rulepacks"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// applyRulePacks merges the style rule packs of the run owner's workspaces into profile
+// and records which were applied. The packs are mandatory, so failing to load them fails
+// the step rather than running without them.
+func (p *pipelineRun) applyRulePacks(ctx context.Context, profile *types.CompanyProfile) error {
+	if p.database == nil || p.opts.UserID == nil {
+		return nil
+	}
+	stored, err := p.database.ListRulePacksForUser(ctx, *p.opts.UserID)
+	if err != nil {
+		return err
+	}
+	packs := make([]rulepacks.Pack, 0, len(stored))
+	for _, pack := range stored {
+		packs = append(packs, rulepacks.Pack{
+			ID:          pack.ID,
+			WorkspaceID: pack.WorkspaceID,
+			Name:        pack.Name,
+			Mode:        pack.Mode,
+			Priority:    pack.Priority,
+			Rules:       pack.Rules,
+		})
+	}
+
+	applied := rulepacks.Apply(profile, packs)
+	if len(applied) == 0 {
+		return nil
+	}
+	slog.InfoContext(ctx, "Applied workspace rule packs to the company style rules", "rule_packs", len(applied))
+	if p.runID == uuid.Nil {
+		return nil
+	}
+	records := make([]db.RulePackApplicationInput, 0, len(applied))
+	for _, a := range applied {
+		records = append(records, db.RulePackApplicationInput{
+			WorkspaceID: a.WorkspaceID,
+			PackID:      a.PackID,
+			PackName:    a.Name,
+			Mode:        a.Mode,
+			Rules:       a.Rules,
+		})
+	}
+	if err := p.database.RecordRulePackApplications(ctx, p.runID, p.opts.UserID, records); err != nil {
+		slog.WarnContext(ctx, "Failed to record applied rule packs", "error", err)
+	}
+	return nil
+}
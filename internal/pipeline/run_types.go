@@ -0,0 +1,146 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+// RunTypeStore is the storage PrepareRunType works through; *db.DB implements it
+type RunTypeStore interface {
+	steps.Client
+	CopyRunArtifacts(ctx context.Context, fromRunID, toRunID uuid.UUID, steps []string) ([]string, error)
+	CreateRunStep(ctx context.Context, runID uuid.UUID, input *db.RunStepInput) (*db.RunStep, error)
+}
+
+// SourceArtifactsError reports artifacts a run type needs that its source run lacks
+type SourceArtifactsError struct {
+	RunType string
+	Missing []string
+}
+
+func (e *SourceArtifactsError) Error() string {
+	return fmt.Sprintf("source run has no %s artifact needed by a %s run", strings.Join(e.Missing, ", "), e.RunType)
+}
+
+// runTypeInputs returns the artifacts the steps of runType read that none of them
+// produce: those the steps require, then those they load when present
+func runTypeInputs(runType string) (required, optional []string) {
+	subset := steps.RunTypes[runType]
+	seen := make(map[string]bool)
+	for artifact, step := range stepNameMap {
+		if slices.Contains(subset, step) {
+			seen[artifact] = true // Produced within the run
+		}
+	}
+	add := func(dst *[]string, artifacts []string) {
+		for _, artifact := range artifacts {
+			if !seen[artifact] {
+				seen[artifact] = true
+				*dst = append(*dst, artifact)
+			}
+		}
+	}
+	for _, step := range subset {
+		add(&required, stepSpecs[step].inputs)
+	}
+	for _, step := range subset {
+		add(&optional, stepSpecs[step].optional)
+	}
+	return required, optional
+}
+
+// PrepareRunType readies a run of a type other than full: it copies the inputs of the
+// type's steps from sourceRunID and records the steps that produced them as completed,
+// so the type's steps find their dependencies met. It fails with a SourceArtifactsError
+// when the source run lacks an input the steps require.
+func PrepareRunType(ctx context.Context, store RunTypeStore, runID, sourceRunID uuid.UUID, runType string) error {
+	if _, ok := steps.RunTypes[runType]; !ok {
+		return fmt.Errorf("unknown run type: %s", runType)
+	}
+	required, optional := runTypeInputs(runType)
+	copied, err := store.CopyRunArtifacts(ctx, sourceRunID, runID, slices.Concat(required, optional))
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, artifact := range required {
+		if !slices.Contains(copied, artifact) {
+			missing = append(missing, artifact)
+		}
+	}
+	if len(missing) > 0 {
+		return &SourceArtifactsError{RunType: runType, Missing: missing}
+	}
+
+	slices.Sort(copied)
+	for _, artifact := range copied {
+		step, ok := stepNameMap[artifact]
+		if !ok {
+			continue
+		}
+		existing, err := store.GetRunStep(ctx, runID, step)
+		if err != nil {
+			return fmt.Errorf("failed to check step %s: %w", step, err)
+		}
+		if existing != nil {
+			continue
+		}
+		if _, err := store.CreateRunStep(ctx, runID, &db.RunStepInput{
+			Step:       step,
+			Category:   stepCategoryMap[artifact],
+			Status:     db.StepStatusCompleted,
+			Parameters: map[string]interface{}{"reused_from": sourceRunID.String()},
+		}); err != nil {
+			return fmt.Errorf("failed to record reused step %s: %w", step, err)
+		}
+	}
+	return nil
+}
+
+// runStepSubset executes the steps of opts.RunType, in order, on the run prepared by
+// PrepareRunType, stores the resume PDF if the run rendered a resume, and marks the
+// run completed
+func runStepSubset(ctx context.Context, opts RunOptions) error {
+	subset, ok := steps.RunTypes[opts.RunType]
+	if !ok {
+		return fmt.Errorf("unknown run type: %s", opts.RunType)
+	}
+	if opts.ExistingRunID == nil || opts.DatabaseURL == "" {
+		return errors.New(opts.RunType + " runs need a database and an existing run")
+	}
+	runID := *opts.ExistingRunID
+
+	database, err := db.Connect(ctx, opts.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.Close()
+
+	slog.InfoContext(ctx, "Executing run type steps...", "run_type", opts.RunType, "steps", subset)
+	env := StepEnv{Database: database, Options: opts}
+	for _, name := range subset {
+		executor, err := NewStepExecutor(name, env)
+		if err != nil {
+			return err
+		}
+		if _, err := executor.Execute(logging.WithStep(ctx, name), runID, nil); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	if latex, err := database.GetTextArtifact(ctx, runID, db.StepResumeTex); err == nil && latex != "" {
+		saveResumePDF(ctx, database, runID, &opts, latex)
+	}
+	_ = database.CompleteRun(ctx, runID, "completed")
+	return nil
+}
@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runTypeStore copies whichever requested artifacts the source has and records steps
+type runTypeStore struct {
+	source []string
+	steps  map[string]*db.RunStep
+}
+
+func (s *runTypeStore) GetRunStep(_ context.Context, _ uuid.UUID, stepName string) (*db.RunStep, error) {
+	return s.steps[stepName], nil
+}
+
+func (s *runTypeStore) CopyRunArtifacts(_ context.Context, _, _ uuid.UUID, steps []string) ([]string, error) {
+	var copied []string
+	for _, step := range steps {
+		if slices.Contains(s.source, step) {
+			copied = append(copied, step)
+		}
+	}
+	return copied, nil
+}
+
+func (s *runTypeStore) CreateRunStep(_ context.Context, runID uuid.UUID, input *db.RunStepInput) (*db.RunStep, error) {
+	step := &db.RunStep{RunID: runID, Step: input.Step, Category: input.Category, Status: input.Status, Parameters: input.Parameters}
+	s.steps[input.Step] = step
+	return step, nil
+}
+
+func TestRunTypeInputs(t *testing.T) {
+	required, optional := runTypeInputs(db.RunTypeCoverLetter)
+	assert.Equal(t, []string{db.StepJobProfile, db.StepRewrittenBullets, db.StepCompanyProfile}, required)
+	assert.Empty(t, optional)
+
+	// Refresh runs render, validate, and repair again, so they reuse neither the LaTeX nor the violations
+	required, optional = runTypeInputs(db.RunTypeRefresh)
+	assert.Equal(t, []string{
+		db.StepExperienceBank, db.StepResumePlan, db.StepRewrittenBullets, db.StepCompanyProfile,
+		db.StepJobProfile, db.StepRankedStories,
+	}, required)
+	assert.Equal(t, []string{db.StepEducationScores, db.StepEducationReq}, optional)
+}
+
+func TestPrepareRunType(t *testing.T) {
+	ctx := context.Background()
+	runID, sourceID := uuid.New(), uuid.New()
+
+	store := &runTypeStore{
+		source: []string{db.StepJobProfile, db.StepRewrittenBullets, db.StepCompanyProfile, db.StepResumeTex},
+		steps:  map[string]*db.RunStep{},
+	}
+	require.NoError(t, PrepareRunType(ctx, store, runID, sourceID, db.RunTypeCoverLetter))
+	require.Len(t, store.steps, 3)
+	for _, name := range []string{"parse_job", "rewrite_bullets", "summarize_voice"} {
+		step := store.steps[name]
+		require.NotNil(t, step, name)
+		assert.Equal(t, db.StepStatusCompleted, step.Status)
+		assert.Equal(t, sourceID.String(), step.Parameters["reused_from"])
+	}
+	assert.Equal(t, db.StepCategoryRewriting, store.steps["rewrite_bullets"].Category)
+
+	// A source without the bullets can't seed a refresh
+	store = &runTypeStore{source: []string{db.StepJobProfile}, steps: map[string]*db.RunStep{}}
+	err := PrepareRunType(ctx, store, runID, sourceID, db.RunTypeRefresh)
+	var srcErr *SourceArtifactsError
+	require.ErrorAs(t, err, &srcErr)
+	assert.Contains(t, srcErr.Missing, db.StepRewrittenBullets)
+	assert.NotContains(t, srcErr.Missing, db.StepEducationScores, "optional inputs may be missing")
+
+	assert.ErrorContains(t, PrepareRunType(ctx, store, runID, sourceID, db.RunTypeFull), "unknown run type")
+}
@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// applySemanticRanking blends each story's semantic similarity to the job into its
+// relevance when RANKING_MODE=embeddings. Without an embedding model, or if embedding
+// fails, the keyword ranking stands.
+func (p *pipelineRun) applySemanticRanking(ctx context.Context, ranked *types.RankedStories) {
+	cfg, err := config.NewRankingConfig()
+	if err != nil {
+		slog.WarnContext(ctx, "Invalid ranking configuration, ranking by keywords only", "error", err)
+		return
+	}
+	if !cfg.Semantic() {
+		return
+	}
+	embedder, err := llm.NewEmbedder(ctx, llm.DefaultConfig(), p.opts.APIKey)
+	if err != nil {
+		slog.WarnContext(ctx, "Semantic ranking unavailable, ranking by keywords only", "error", err)
+		return
+	}
+	defer func() { _ = embedder.Close() }()
+
+	job, bullets, err := p.embedStories(ctx, embedder)
+	if err != nil {
+		slog.WarnContext(ctx, "Semantic ranking failed, ranking by keywords only", "error", err)
+		return
+	}
+	ranking.ApplySemanticScores(ranked, p.experienceBank, job, bullets, cfg.SemanticWeight)
+}
+
+// embedStories embeds the job's requirements and every bullet in the experience bank,
+// returning the job's vector and the bullets' vectors by bullet ID. Embeddings stored
+// for unchanged experience bullets are reused, and new ones are stored.
+func (p *pipelineRun) embedStories(ctx context.Context, embedder llm.Embedder) ([]float32, map[string][]float32, error) {
+	bullets := make(map[string][]float32)
+	if p.database != nil {
+		var ids []uuid.UUID
+		for _, story := range p.experienceBank.Stories {
+			for _, bullet := range story.Bullets {
+				if id, err := uuid.Parse(bullet.ID); err == nil {
+					ids = append(ids, id)
+				}
+			}
+		}
+		stored, err := p.database.GetExperienceEmbeddings(ctx, ids, embedder.Model())
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to load stored embeddings", "error", err)
+		}
+		for id, vec := range stored {
+			bullets[id.String()] = vec
+		}
+	}
+
+	texts := []string{ranking.JobEmbeddingText(p.jobProfile)}
+	var missing []types.Bullet
+	for _, story := range p.experienceBank.Stories {
+		for _, bullet := range story.Bullets {
+			if _, ok := bullets[bullet.ID]; ok || bullet.Text == "" {
+				continue
+			}
+			missing = append(missing, bullet)
+			texts = append(texts, bullet.Text)
+		}
+	}
+
+	vectors, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, nil, fmt.Errorf("embedder returned %d vectors for %d texts", len(vectors), len(texts))
+	}
+	store := p.database != nil
+	for i, bullet := range missing {
+		vec := vectors[i+1]
+		bullets[bullet.ID] = vec
+		id, err := uuid.Parse(bullet.ID)
+		if !store || err != nil {
+			continue
+		}
+		if err := p.database.SaveExperienceEmbedding(ctx, id, embedder.Model(), bullet.Text, vec); err != nil {
+			// Likely the schema lacks the embedding columns; don't repeat the warning per bullet
+			slog.WarnContext(ctx, "Failed to store bullet embeddings", "error", err)
+			store = false
+		}
+	}
+	return vectors[0], bullets, nil
+}
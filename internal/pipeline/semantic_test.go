@@ -0,0 +1,77 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// fakeEmbedder embeds texts with fixed vectors, recording what it was asked to embed
+type fakeEmbedder struct {
+	vectors map[string][]float32
+	err     error
+	calls   [][]string
+}
+
+func (f *fakeEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	f.calls = append(f.calls, texts)
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = f.vectors[text]
+	}
+	return out, nil
+}
+
+func (f *fakeEmbedder) Model() string { return "fake-embed" }
+func (f *fakeEmbedder) Close() error  { return nil }
+
+func TestEmbedStories(t *testing.T) {
+	p := &pipelineRun{
+		opts:       &RunOptions{},
+		jobProfile: &types.JobProfile{RoleTitle: "SRE"},
+		experienceBank: &types.ExperienceBank{Stories: []types.Story{
+			{ID: "s1", Bullets: []types.Bullet{{ID: "b1", Text: "Ran Kubernetes"}, {ID: "b2"}}},
+			{ID: "s2", Bullets: []types.Bullet{{ID: "b3", Text: "Baked bread"}}},
+		}},
+	}
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"SRE":            {1, 0},
+		"Ran Kubernetes": {0.9, 0.1},
+		"Baked bread":    {0, 1},
+	}}
+
+	job, bullets, err := p.embedStories(context.Background(), embedder)
+	require.NoError(t, err)
+	assert.Equal(t, []float32{1, 0}, job)
+	assert.Equal(t, map[string][]float32{"b1": {0.9, 0.1}, "b3": {0, 1}}, bullets, "bullets without text are skipped")
+	require.Len(t, embedder.calls, 1)
+	assert.Equal(t, []string{"SRE", "Ran Kubernetes", "Baked bread"}, embedder.calls[0], "one request for the job and all bullets")
+}
+
+func TestEmbedStories_Error(t *testing.T) {
+	p := &pipelineRun{
+		opts:           &RunOptions{},
+		jobProfile:     &types.JobProfile{RoleTitle: "SRE"},
+		experienceBank: &types.ExperienceBank{},
+	}
+	_, _, err := p.embedStories(context.Background(), &fakeEmbedder{err: errors.New("quota exceeded")})
+	assert.ErrorContains(t, err, "quota exceeded")
+}
+
+func TestApplySemanticRanking_KeywordMode(t *testing.T) {
+	t.Setenv("RANKING_MODE", "keyword")
+	p := &pipelineRun{opts: &RunOptions{}, experienceBank: &types.ExperienceBank{}}
+	ranked := &types.RankedStories{Ranked: []types.RankedStory{{StoryID: "s1", RelevanceScore: 0.4}}}
+
+	p.applySemanticRanking(context.Background(), ranked)
+	assert.InDelta(t, 0.4, ranked.Ranked[0].RelevanceScore, 1e-9)
+	assert.Nil(t, ranked.Ranked[0].SemanticScore)
+}
@@ -0,0 +1,137 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/fetch"
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/jonathan/resume-customizer/internal/voice"
+)
+
+// researchCompanyName returns the company a run researches: the parsed company, else the
+// one the job board named, else the one in the posting's URL
+func (p *pipelineRun) researchCompanyName() string {
+	name := ""
+	if p.jobProfile != nil {
+		name = p.jobProfile.Company
+	}
+	if name == "" && p.jobMetadata != nil && p.jobMetadata.Company != "" {
+		name = p.jobMetadata.Company
+	}
+	if name == "" && p.jobMetadata != nil && p.jobMetadata.URL != "" {
+		name = fetch.ExtractCompanyFromURL(p.jobMetadata.URL)
+	}
+	return name
+}
+
+// sharesResearch reports whether the run may reuse and publish company research shared
+// across users. Private runs and demo runs keep to themselves.
+func (p *pipelineRun) sharesResearch() bool {
+	return p.database != nil && p.opts.Demo == nil && (p.opts.Crawl == nil || !p.opts.Crawl.Private)
+}
+
+// sharedCompanyProfile returns the company profile the run's owner entered by hand, or
+// the one another run researched when it is fresh enough to reuse instead of crawling,
+// or nil when this run must research the company
+func (p *pipelineRun) sharedCompanyProfile(ctx context.Context, companyName string) *db.CompanyProfile {
+	limits := p.opts.Crawl
+	if p.database == nil || p.opts.Demo != nil || companyName == "" || (limits != nil && limits.Refresh) {
+		return nil
+	}
+	// Private runs and runs with reuse disabled still use the owner's manual profile
+	reuseShared := p.sharesResearch() && limits != nil && limits.ProfileMaxAgeDays > 0
+	if p.opts.UserID == nil && !reuseShared {
+		return nil
+	}
+	company, err := p.database.GetCompanyByNormalizedName(ctx, db.NormalizeName(companyName))
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to look up company for shared research", "error", err)
+		return nil
+	}
+	if company == nil {
+		return nil
+	}
+	// A manual profile was entered because the company has nothing to crawl
+	if p.opts.UserID != nil {
+		profile, err := p.database.GetUserCompanyProfile(ctx, *p.opts.UserID, company.ID)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to load manual company profile", "error", err)
+		} else if profile != nil {
+			return profile
+		}
+	}
+	if !reuseShared {
+		return nil
+	}
+	maxAge := time.Duration(limits.ProfileMaxAgeDays) * 24 * time.Hour
+	profile, err := p.database.GetFreshCompanyProfile(ctx, company.ID, maxAge)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load shared company profile", "error", err)
+		return nil
+	}
+	// Without its corpus the profile cannot stand in for the research step
+	if profile == nil || profile.SourceCorpus == nil || *profile.SourceCorpus == "" {
+		return nil
+	}
+	return profile
+}
+
+// sharedCorpus rebuilds a research corpus from a shared profile and the pages it cites.
+// Manual profiles cite no pages; their corpus restates the profile.
+func sharedCorpus(profile *db.CompanyProfile) *types.CompanyCorpus {
+	if profile.SourceCorpus == nil || *profile.SourceCorpus == "" {
+		return &types.CompanyCorpus{Corpus: manualCorpus(profile)}
+	}
+	corpus := &types.CompanyCorpus{Corpus: *profile.SourceCorpus}
+	for _, url := range profile.EvidenceURLs {
+		corpus.Sources = append(corpus.Sources, types.Source{URL: url})
+	}
+	return corpus
+}
+
+// manualCorpus writes a profile entered by hand out as text, for steps that read the
+// research corpus
+func manualCorpus(profile *db.CompanyProfile) string {
+	var sb strings.Builder
+	sb.WriteString("## Company Profile\n")
+	sb.WriteString("Tone: " + profile.Tone + "\n")
+	if profile.DomainContext != nil && *profile.DomainContext != "" {
+		sb.WriteString("Domain: " + *profile.DomainContext + "\n")
+	}
+	for _, section := range []struct {
+		title string
+		items []string
+	}{
+		{"Values", profile.Values},
+		{"Style Rules", profile.StyleRules},
+	} {
+		if len(section.items) == 0 {
+			continue
+		}
+		sb.WriteString("\n## " + section.title + "\n")
+		for _, item := range section.items {
+			sb.WriteString("- " + item + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// publishCompanyProfile stores the run's summarized voice as the company's shared
+// profile so other users' runs can skip the crawl
+func (p *pipelineRun) publishCompanyProfile(ctx context.Context, companyName string, profile *types.CompanyProfile) {
+	if !p.sharesResearch() || companyName == "" || p.companyCorpus == nil {
+		return
+	}
+	company, err := p.database.FindOrCreateCompany(ctx, companyName)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to look up company for shared research", "error", err)
+		return
+	}
+	if err := voice.StoreProfile(ctx, p.database, company.ID, p.companyCorpus.Corpus, p.companyCorpus.Sources, profile); err != nil {
+		slog.WarnContext(ctx, "Failed to share company profile", "error", err)
+	}
+}
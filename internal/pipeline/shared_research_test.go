@@ -0,0 +1,74 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/demo"
+	"github.com/jonathan/resume-customizer/internal/ingestion"
+	"github.com/jonathan/resume-customizer/internal/research"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestResearchCompanyName(t *testing.T) {
+	p := &pipelineRun{
+		jobProfile:  &types.JobProfile{},
+		jobMetadata: &ingestion.Metadata{URL: "https://boards.greenhouse.io/acmeco/jobs/1"},
+	}
+	assert.Equal(t, "acmeco", p.researchCompanyName())
+
+	p.jobMetadata.Company = "Acme Co"
+	assert.Equal(t, "Acme Co", p.researchCompanyName())
+
+	p.jobProfile.Company = "Acme"
+	assert.Equal(t, "Acme", p.researchCompanyName())
+}
+
+func TestSharesResearch(t *testing.T) {
+	database := &db.DB{}
+	assert.False(t, (&pipelineRun{opts: &RunOptions{}}).sharesResearch(), "nothing to share without a database")
+	assert.True(t, (&pipelineRun{opts: &RunOptions{}, database: database}).sharesResearch())
+	assert.True(t, (&pipelineRun{opts: &RunOptions{Crawl: &research.CrawlLimits{Refresh: true}}, database: database}).sharesResearch())
+	assert.False(t, (&pipelineRun{opts: &RunOptions{Crawl: &research.CrawlLimits{Private: true}}, database: database}).sharesResearch())
+	assert.False(t, (&pipelineRun{opts: &RunOptions{Demo: &demo.Fixtures{}}, database: database}).sharesResearch())
+}
+
+func TestSharedCompanyProfile_SkippedWithoutLookup(t *testing.T) {
+	// Each of these returns before touching the (unconnected) database. Runs with an
+	// owner would still look for the owner's manual profile.
+	database := &db.DB{}
+	for name, crawl := range map[string]*research.CrawlLimits{
+		"refresh":        {Refresh: true, ProfileMaxAgeDays: 30},
+		"private":        {Private: true, ProfileMaxAgeDays: 30},
+		"reuse disabled": {ProfileMaxAgeDays: 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := &pipelineRun{opts: &RunOptions{Crawl: crawl}, database: database}
+			assert.Nil(t, p.sharedCompanyProfile(context.Background(), "Acme"))
+		})
+	}
+	p := &pipelineRun{opts: &RunOptions{Crawl: &research.CrawlLimits{ProfileMaxAgeDays: 30}}, database: database}
+	assert.Nil(t, p.sharedCompanyProfile(context.Background(), ""))
+}
+
+func TestSharedCorpus(t *testing.T) {
+	corpus := "## About\nWe build rockets."
+	got := sharedCorpus(&db.CompanyProfile{SourceCorpus: &corpus, EvidenceURLs: []string{"https://acme.com/about"}})
+	assert.Equal(t, corpus, got.Corpus)
+	assert.Equal(t, []types.Source{{URL: "https://acme.com/about"}}, got.Sources)
+}
+
+func TestSharedCorpus_Manual(t *testing.T) {
+	domain := "Industrial farming equipment"
+	got := sharedCorpus(&db.CompanyProfile{
+		SourceType:    db.ProfileSourceManual,
+		Tone:          "plain and practical",
+		DomainContext: &domain,
+		Values:        []string{"Reliability"},
+	})
+	assert.Equal(t, "## Company Profile\nTone: plain and practical\nDomain: Industrial farming equipment\n\n## Values\n- Reliability\n", got.Corpus)
+	assert.Empty(t, got.Sources)
+}
@@ -0,0 +1,453 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/observability"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/rewriting"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// StepEnv is what a step executor needs besides the request's parameters
+type StepEnv struct {
+	Database *db.DB
+	// Options supplies the API key, candidate details, model routing, and defaults
+	// that step parameters override. ExperienceData is only read by load_experience.
+	Options RunOptions
+}
+
+// stepSpec describes how the step API runs a built-in step: the artifacts loaded
+// into the pipelineRun before it runs and the pipelineRun method that runs it
+type stepSpec struct {
+	inputs   []string // Artifacts the step requires
+	optional []string // Artifacts loaded when present (their steps are non-fatal or skippable)
+	run      func(*pipelineRun, context.Context) error
+}
+
+// stepSpecs covers every built-in step in steps.StepRegistry
+var stepSpecs = map[string]stepSpec{
+	"ingest_job": {
+		run: (*pipelineRun).ingestPosting,
+	},
+	"parse_job": {
+		inputs: []string{db.StepJobPosting},
+		run:    (*pipelineRun).parseJob,
+	},
+	"extract_education": {
+		inputs: []string{db.StepJobPosting},
+		run:    (*pipelineRun).extractEducation,
+	},
+	"load_experience": {
+		run: (*pipelineRun).loadExperience,
+	},
+	"rank_stories": {
+		inputs: []string{db.StepJobProfile, db.StepExperienceBank},
+		run:    (*pipelineRun).rankStories,
+	},
+	"score_education": {
+		inputs:   []string{db.StepJobPosting, db.StepExperienceBank},
+		optional: []string{db.StepEducationReq},
+		run:      (*pipelineRun).scoreEducation,
+	},
+	"select_plan": {
+		inputs: []string{db.StepJobProfile, db.StepExperienceBank, db.StepRankedStories},
+		run:    (*pipelineRun).selectPlan,
+	},
+	"materialize_bullets": {
+		inputs: []string{db.StepExperienceBank, db.StepResumePlan},
+		run:    (*pipelineRun).materializeBullets,
+	},
+	"suggest_bullets": {
+		inputs: []string{db.StepJobProfile, db.StepExperienceBank},
+		run:    (*pipelineRun).suggestBullets,
+	},
+	"research_company": {
+		inputs:   []string{db.StepJobProfile},
+		optional: []string{db.StepJobMetadata},
+		run:      (*pipelineRun).researchCompany,
+	},
+	"summarize_voice": {
+		inputs: []string{db.StepSources, db.StepCompanyCorpus},
+		run:    (*pipelineRun).summarizeVoice,
+	},
+	"rewrite_bullets": {
+		inputs:   []string{db.StepJobProfile, db.StepSelectedBullets, db.StepCompanyProfile},
+		optional: []string{db.StepEducationReq},
+		run:      (*pipelineRun).rewriteBullets,
+	},
+	"render_latex": {
+		inputs:   []string{db.StepExperienceBank, db.StepResumePlan, db.StepRewrittenBullets},
+		optional: []string{db.StepEducationScores},
+		run:      (*pipelineRun).renderLaTeX,
+	},
+	"validate_latex": {
+		inputs:   []string{db.StepExperienceBank, db.StepResumePlan, db.StepRewrittenBullets, db.StepResumeTex, db.StepCompanyProfile},
+		optional: []string{db.StepEducationScores},
+		run:      (*pipelineRun).validateRenderedLaTeX,
+	},
+	"repair_violations": {
+		inputs: []string{
+			db.StepJobProfile, db.StepExperienceBank, db.StepRankedStories, db.StepResumePlan,
+			db.StepRewrittenBullets, db.StepCompanyProfile, db.StepViolations,
+		},
+		optional: []string{db.StepEducationReq, db.StepEducationScores},
+		run:      (*pipelineRun).repairViolations,
+	},
+	"write_cover_letter": {
+		inputs: []string{db.StepJobProfile, db.StepRewrittenBullets, db.StepCompanyProfile},
+		run:    (*pipelineRun).writeCoverLetter,
+	},
+}
+
+// stepExecutor runs one step of a stored run for POST /v1/runs/{run_id}/steps/{step_name},
+// loading the outputs of earlier steps from the run's artifacts
+type stepExecutor struct {
+	def    steps.StepDefinition
+	env    StepEnv
+	spec   stepSpec
+	plugin *steps.Plugin // Set for plugin steps, which have no spec
+}
+
+// NewStepExecutor returns the executor for a built-in or plugin step
+func NewStepExecutor(name string, env StepEnv) (steps.StepExecutor, error) {
+	def, ok := steps.StepRegistry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown step: %s", name)
+	}
+	if env.Database == nil {
+		return nil, errors.New("step executors require a database")
+	}
+	e := &stepExecutor{def: def, env: env}
+	if spec, ok := stepSpecs[name]; ok {
+		e.spec = spec
+	} else if p, ok := steps.Plugins[name]; ok {
+		e.plugin = p
+	} else {
+		return nil, fmt.Errorf("step %s has no executor", name)
+	}
+	return e, nil
+}
+
+// Name returns the step's registry name
+func (e *stepExecutor) Name() string {
+	return e.def.Name
+}
+
+// Category returns the step's category
+func (e *stepExecutor) Category() string {
+	return e.def.Category
+}
+
+// Dependencies returns the steps that must complete first
+func (e *stepExecutor) Dependencies() []string {
+	return e.def.Dependencies
+}
+
+// ValidateDependencies checks that the step's dependencies completed for the run
+func (e *stepExecutor) ValidateDependencies(ctx context.Context, client steps.Client, runID uuid.UUID) error {
+	return steps.ValidateDependencies(ctx, client, runID, e.def.Name)
+}
+
+// Execute runs the step with parameters already checked by steps.ValidateParams.
+// The step records its own status and artifacts; a step that fails without
+// stopping a full pipeline run (e.g. extract_education) is reported as an error here.
+func (e *stepExecutor) Execute(ctx context.Context, runID uuid.UUID, params map[string]interface{}) (*steps.StepResult, error) {
+	start := time.Now()
+	name := e.def.Name
+
+	opts := e.env.Options
+	routing, err := resolveModelRouting(&opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model routing configuration: %w", err)
+	}
+	opts.ModelRouting = routing
+	crawl, err := resolveCrawlLimits(&opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid crawl configuration: %w", err)
+	}
+	opts.Crawl = crawl
+	ctx = applyStepParams(ctx, &opts, params)
+	if name == "validate_latex" || name == "repair_violations" {
+		opts.TemplatePath = e.renderedTemplate(ctx, runID, opts.TemplatePath)
+	}
+	if _, ok := params["resume_pages"]; !ok && (name == "validate_latex" || name == "repair_violations") {
+		opts.MaxPages = e.validatedPages(ctx, runID, name, opts.MaxPages)
+	}
+	if err := resolveTemplate(ctx, e.env.Database, &opts); err != nil {
+		return nil, err
+	}
+
+	if opts.Demo != nil {
+		if ctx, err = withDemo(ctx, &opts); err != nil {
+			return nil, fmt.Errorf("invalid demo data: %w", err)
+		}
+	}
+	if opts.RedactPII {
+		ctx, _ = withPIIMasker(ctx, &opts)
+	}
+	override, _ := params["model"].(string)
+	downgraded, _ := params["model_downgraded"].(bool)
+	sel, err := opts.ModelRouting.Select(name, override, opts.ModelDowngrade || downgraded)
+	if err != nil {
+		return nil, err
+	}
+	if !sel.IsZero() {
+		ctx = llm.WithSelection(ctx, sel)
+	}
+	ctx = steps.WithLLMBudget(ctx, name)
+	ctx, truncations := withTruncationLog(ctx)
+	defer saveTruncations(ctx, e.env.Database, runID, truncations)
+
+	if e.plugin != nil {
+		err = runPluginStep(ctx, e.env.Database, runID, &opts, e.plugin, params)
+	} else {
+		err = e.run(ctx, runID, &opts)
+	}
+	if err == nil {
+		err = stepFailure(ctx, e.env.Database, runID, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	elapsed := time.Since(start)
+	warnOverLatencyBudget(ctx, name, elapsed)
+	return &steps.StepResult{
+		Step:     name,
+		Status:   db.StepStatusCompleted,
+		Duration: elapsed.Milliseconds(),
+	}, nil
+}
+
+// run loads the step's inputs into a pipelineRun and runs the step
+func (e *stepExecutor) run(ctx context.Context, runID uuid.UUID, opts *RunOptions) error {
+	p := &pipelineRun{
+		opts:     opts,
+		database: e.env.Database,
+		runID:    runID,
+		printer:  observability.NewPrinter(logging.Verbose()),
+	}
+	if err := p.loadInputs(ctx, e.spec.inputs, true); err != nil {
+		_ = failStep(ctx, p.database, runID, e.def.Name, err)
+		return err
+	}
+	if err := p.loadInputs(ctx, e.spec.optional, false); err != nil {
+		_ = failStep(ctx, p.database, runID, e.def.Name, err)
+		return err
+	}
+	if p.experienceBank != nil && p.selectedEducation == nil {
+		p.selectedEducation = p.experienceBank.Education // score_education failed or was skipped
+	}
+	if p.jobProfile != nil && p.educationRequirements != nil {
+		p.jobProfile.EducationRequirements = p.educationRequirements
+	}
+	if e.def.Name == "research_company" || e.def.Name == "validate_latex" {
+		remote, err := newDispatcher(p.database)
+		if err != nil {
+			return err
+		}
+		p.remote = remote
+	}
+	return e.spec.run(p, ctx)
+}
+
+// renderedTemplate returns the template render_latex ran with, so validation and
+// repair re-render with the same one, else fallback
+func (e *stepExecutor) renderedTemplate(ctx context.Context, runID uuid.UUID, fallback string) string {
+	step, err := e.env.Database.GetRunStep(ctx, runID, "render_latex")
+	if err == nil && step != nil {
+		if template, ok := step.Parameters["template"].(string); ok && template != "" {
+			return template
+		}
+	}
+	return fallback
+}
+
+// validatedPages returns the page limit a step holds the resume to when it is given no
+// resume_pages: for repair, the one validate_latex checked against, so it aims for the
+// same one; else the max_pages the run was created with; else fallback
+func (e *stepExecutor) validatedPages(ctx context.Context, runID uuid.UUID, name string, fallback int) int {
+	if name == "repair_violations" {
+		step, err := e.env.Database.GetRunStep(ctx, runID, "validate_latex")
+		if err == nil && step != nil {
+			if pages, ok := intParam(step.Parameters, "resume_pages"); ok {
+				return pages
+			}
+		}
+	}
+	run, err := e.env.Database.GetRun(ctx, runID)
+	if err == nil && run != nil && run.MaxPages != nil {
+		return *run.MaxPages
+	}
+	return fallback
+}
+
+// applyStepParams overrides run options with a step's parameters. batch_size is
+// carried on the returned context.
+func applyStepParams(ctx context.Context, opts *RunOptions, params map[string]interface{}) context.Context {
+	if v, ok := intParam(params, "max_bullets"); ok {
+		opts.MaxBullets = v
+	}
+	if v, ok := intParam(params, "max_lines"); ok {
+		opts.MaxLines = v
+	}
+	if v, ok := intParam(params, "resume_pages"); ok {
+		opts.MaxPages = v
+	}
+	if v, ok := params["template"].(string); ok && v != "" {
+		opts.TemplatePath = v
+	}
+	if v, ok := params["job_text"].(string); ok && v != "" {
+		opts.JobText = v
+	}
+
+	if opts.Crawl != nil {
+		crawl := *opts.Crawl
+		if v, ok := intParam(params, "max_pages"); ok {
+			crawl.MaxPages = v
+		}
+		if v, ok := intParam(params, "max_depth"); ok {
+			crawl.MaxDepth = v
+		}
+		if v, ok := params["same_domain_only"].(bool); ok {
+			crawl.SameDomainOnly = v
+		}
+		opts.Crawl = &crawl
+	}
+
+	if v, ok := intParam(params, "batch_size"); ok {
+		ctx = rewriting.WithBatchSize(ctx, v)
+	}
+	return ctx
+}
+
+// intParam reads an integer parameter, which decodes from JSON as float64
+func intParam(params map[string]interface{}, name string) (int, bool) {
+	switch v := params[name].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// loadInputs reads the given artifacts into the pipelineRun. Missing artifacts are
+// an error when required and skipped otherwise.
+func (p *pipelineRun) loadInputs(ctx context.Context, artifacts []string, required bool) error {
+	for _, artifact := range artifacts {
+		found, err := p.loadInput(ctx, artifact)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", artifact, err)
+		}
+		if !found && required {
+			return fmt.Errorf("run has no %s artifact", artifact)
+		}
+	}
+	return nil
+}
+
+// loadInput reads one artifact into the field the step methods read it from
+func (p *pipelineRun) loadInput(ctx context.Context, artifact string) (bool, error) {
+	switch artifact {
+	case db.StepJobPosting:
+		return loadTextArtifact(ctx, p.database, p.runID, artifact, &p.cleanedText)
+	case db.StepResumeTex:
+		return loadTextArtifact(ctx, p.database, p.runID, artifact, &p.resumeTex)
+	case db.StepCompanyCorpus:
+		if p.companyCorpus == nil {
+			p.companyCorpus = &types.CompanyCorpus{}
+		}
+		return loadTextArtifact(ctx, p.database, p.runID, artifact, &p.companyCorpus.Corpus)
+	case db.StepSources:
+		if p.companyCorpus == nil {
+			p.companyCorpus = &types.CompanyCorpus{}
+		}
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.companyCorpus.Sources)
+	case db.StepJobMetadata:
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.jobMetadata)
+	case db.StepJobProfile:
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.jobProfile)
+	case db.StepEducationReq:
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.educationRequirements)
+	case db.StepExperienceBank:
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.experienceBank)
+	case db.StepRankedStories:
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.rankedStories)
+	case db.StepEducationScores:
+		var scores []ranking.EducationScore
+		found, err := loadArtifact(ctx, p.database, p.runID, artifact, &scores)
+		if found && p.experienceBank != nil {
+			p.selectedEducation = IncludedEducation(p.experienceBank, scores)
+		}
+		return found, err
+	case db.StepResumePlan:
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.resumePlan)
+	case db.StepSelectedBullets:
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.selectedBullets)
+	case db.StepCompanyProfile:
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.companyProfile)
+	case db.StepRewrittenBullets:
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.rewrittenBullets)
+	case db.StepViolations:
+		return loadArtifact(ctx, p.database, p.runID, artifact, &p.violations)
+	default:
+		return false, fmt.Errorf("no loader for artifact %s", artifact)
+	}
+}
+
+// loadArtifact decodes a JSON artifact into out, reporting whether it exists
+func loadArtifact[T any](ctx context.Context, database *db.DB, runID uuid.UUID, artifact string, out *T) (bool, error) {
+	content, err := database.GetArtifact(ctx, runID, artifact)
+	if err != nil || len(content) == 0 {
+		return false, err
+	}
+	if err := json.Unmarshal(content, out); err != nil {
+		return false, fmt.Errorf("invalid %s artifact: %w", artifact, err)
+	}
+	return true, nil
+}
+
+// loadTextArtifact reads a text artifact into out, reporting whether it exists
+func loadTextArtifact(ctx context.Context, database *db.DB, runID uuid.UUID, artifact string, out *string) (bool, error) {
+	text, err := database.GetTextArtifact(ctx, runID, artifact)
+	if err != nil || text == "" {
+		return false, err
+	}
+	*out = text
+	return true, nil
+}
+
+// validateRenderedLaTeX validates a stored render. Bullet-level feedback needs the
+// render's line map, which is recovered by rendering the stored plan and bullets
+// again; it is only used if that reproduces the stored LaTeX exactly.
+func (p *pipelineRun) validateRenderedLaTeX(ctx context.Context) error {
+	if latex, lineMap, err := p.render(); err == nil && latex == p.resumeTex {
+		p.lineMap = lineMap
+	}
+	return p.validateLaTeX(ctx)
+}
+
+// stepFailure returns the error recorded on a step that marked itself failed
+// without returning one
+func stepFailure(ctx context.Context, database *db.DB, runID uuid.UUID, name string) error {
+	step, err := database.GetRunStep(ctx, runID, name)
+	if err != nil || step == nil || step.Status != db.StepStatusFailed {
+		return err
+	}
+	if step.ErrorMessage != nil {
+		return errors.New(*step.ErrorMessage)
+	}
+	return fmt.Errorf("step %s failed", name)
+}
@@ -0,0 +1,90 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/research"
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepSpecs_CoverRegistry(t *testing.T) {
+	for name := range steps.StepRegistry {
+		if _, plugin := steps.Plugins[name]; plugin {
+			continue
+		}
+		spec, ok := stepSpecs[name]
+		if assert.True(t, ok, "built-in step %s has no executor", name) {
+			assert.NotNil(t, spec.run, name)
+		}
+	}
+}
+
+func TestNewStepExecutor(t *testing.T) {
+	_, err := NewStepExecutor("nonexistent_step", StepEnv{Database: &db.DB{}})
+	assert.ErrorContains(t, err, "unknown step")
+
+	_, err = NewStepExecutor("ingest_job", StepEnv{})
+	assert.ErrorContains(t, err, "require a database")
+
+	e, err := NewStepExecutor("select_plan", StepEnv{Database: &db.DB{}})
+	require.NoError(t, err)
+	assert.Equal(t, "select_plan", e.Name())
+	assert.Equal(t, db.StepCategoryExperience, e.Category())
+	assert.Equal(t, steps.StepRegistry["select_plan"].Dependencies, e.Dependencies())
+}
+
+func TestApplyStepParams(t *testing.T) {
+	defaults := &research.CrawlLimits{MaxPages: 10, MaxDepth: 2, SameDomainOnly: true}
+	opts := &RunOptions{MaxBullets: 25, MaxLines: 35, TemplatePath: "templates/one_page_resume.tex", Crawl: defaults}
+
+	ctx := applyStepParams(context.Background(), opts, map[string]interface{}{
+		"max_bullets":      float64(12), // As decoded from JSON
+		"max_lines":        20,
+		"template":         "templates/two_column.tex",
+		"job_text":         "Staff SRE at Acme",
+		"max_pages":        float64(4),
+		"resume_pages":     float64(2),
+		"same_domain_only": false,
+		"model":            "lite",
+	})
+	assert.NotNil(t, ctx)
+	assert.Equal(t, 12, opts.MaxBullets)
+	assert.Equal(t, 20, opts.MaxLines)
+	assert.Equal(t, "templates/two_column.tex", opts.TemplatePath)
+	assert.Equal(t, "Staff SRE at Acme", opts.JobText)
+	assert.Equal(t, 2, opts.MaxPages)
+	assert.Equal(t, research.CrawlLimits{MaxPages: 4, MaxDepth: 2}, *opts.Crawl)
+	assert.Equal(t, 10, defaults.MaxPages, "the server's crawl defaults are not modified")
+}
+
+func TestApplyStepParams_Empty(t *testing.T) {
+	opts := &RunOptions{MaxBullets: 25, TemplatePath: "templates/one_page_resume.tex"}
+	applyStepParams(context.Background(), opts, nil)
+	assert.Equal(t, 25, opts.MaxBullets)
+	assert.Equal(t, "templates/one_page_resume.tex", opts.TemplatePath)
+	assert.Nil(t, opts.Crawl)
+}
+
+func TestResumePages(t *testing.T) {
+	assert.Equal(t, 1, (&RunOptions{}).resumePages())
+	assert.Equal(t, 2, (&RunOptions{MaxPages: 2}).resumePages())
+	assert.Equal(t, MaxResumePages, (&RunOptions{MaxPages: 5}).resumePages())
+
+	// Step parameters allow exactly the pages the pipeline does
+	for _, step := range []string{"validate_latex", "repair_violations"} {
+		assert.Equal(t, float64(MaxResumePages), *steps.StepRegistry[step].Params["resume_pages"].Maximum, step)
+	}
+}
+
+func TestIncludedEducation(t *testing.T) {
+	bank := &types.ExperienceBank{Education: []types.Education{{ID: "bs"}, {ID: "ms"}}}
+	scores := []ranking.EducationScore{{EducationID: "bs", Included: false}, {EducationID: "ms", Included: true}}
+	assert.Equal(t, []types.Education{{ID: "ms"}}, IncludedEducation(bank, scores))
+	assert.Empty(t, IncludedEducation(bank, nil))
+}
@@ -0,0 +1,52 @@
+package steps
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+)
+
+// LLMBudget bounds the LLM calls a step makes, so one slow summarization or
+// oversized prompt cannot hold a run for minutes
+type LLMBudget struct {
+	TimeoutSeconds   int    `json:"timeout_seconds,omitempty"`    // Per LLM call
+	MaxContextTokens int    `json:"max_context_tokens,omitempty"` // Estimated prompt tokens
+	Truncation       string `json:"truncation,omitempty"`         // How prompts over MaxContextTokens are cut (see llm.TruncationStrategy)
+}
+
+// Validate checks the limits and truncation strategy
+func (b LLMBudget) Validate() error {
+	if b.TimeoutSeconds < 0 {
+		return fmt.Errorf("llm.timeout_seconds must not be negative, got: %d", b.TimeoutSeconds)
+	}
+	if b.MaxContextTokens < 0 {
+		return fmt.Errorf("llm.max_context_tokens must not be negative, got: %d", b.MaxContextTokens)
+	}
+	if b.Truncation != "" && !slices.Contains(llm.ValidTruncationStrategies, llm.TruncationStrategy(b.Truncation)) {
+		return fmt.Errorf("unknown llm.truncation %q (must be one of %v)", b.Truncation, llm.ValidTruncationStrategies)
+	}
+	return nil
+}
+
+// Budget converts the budget for step's LLM clients
+func (b LLMBudget) Budget(step string) llm.Budget {
+	return llm.Budget{
+		Step:             step,
+		Timeout:          time.Duration(b.TimeoutSeconds) * time.Second,
+		MaxContextTokens: b.MaxContextTokens,
+		Truncation:       llm.TruncationStrategy(b.Truncation),
+	}
+}
+
+// WithLLMBudget returns a context whose LLM clients are bounded by step's budget
+// in StepRegistry. Steps without a budget leave ctx unchanged.
+func WithLLMBudget(ctx context.Context, step string) context.Context {
+	def, ok := StepRegistry[step]
+	if !ok || def.LLM == (LLMBudget{}) {
+		return ctx
+	}
+	return llm.WithBudget(ctx, def.LLM.Budget(step))
+}
@@ -0,0 +1,49 @@
+package steps
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+)
+
+func TestLLMBudget_Validate(t *testing.T) {
+	assert.NoError(t, LLMBudget{}.Validate())
+	assert.NoError(t, LLMBudget{TimeoutSeconds: 30, MaxContextTokens: 8000, Truncation: "end"}.Validate())
+	assert.Error(t, LLMBudget{TimeoutSeconds: -1}.Validate())
+	assert.Error(t, LLMBudget{MaxContextTokens: -1}.Validate())
+	assert.ErrorContains(t, LLMBudget{Truncation: "random"}.Validate(), "unknown llm.truncation")
+
+	for name, def := range StepRegistry {
+		assert.NoError(t, def.LLM.Validate(), name)
+	}
+}
+
+func TestWithLLMBudget(t *testing.T) {
+	isolateRegistry(t)
+
+	ctx := WithLLMBudget(context.Background(), "summarize_voice")
+	budget, ok := llm.BudgetFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "summarize_voice", budget.Step)
+	assert.Equal(t, 90*time.Second, budget.Timeout)
+	assert.Equal(t, llm.TruncateMiddle, budget.Truncation)
+
+	_, ok = llm.BudgetFromContext(WithLLMBudget(context.Background(), "load_experience"))
+	assert.False(t, ok, "steps without LLM calls have no budget")
+
+	require.NoError(t, ApplyExecutionConfig(map[string]ExecutionSpec{
+		"summarize_voice": {LLM: &LLMBudget{TimeoutSeconds: 20, MaxContextTokens: 4000, Truncation: "start"}},
+	}))
+	budget, _ = llm.BudgetFromContext(WithLLMBudget(context.Background(), "summarize_voice"))
+	assert.Equal(t, 20*time.Second, budget.Timeout)
+	assert.Equal(t, 4000, budget.MaxContextTokens)
+	assert.Equal(t, llm.TruncateStart, budget.Truncation)
+
+	err := ApplyExecutionConfig(map[string]ExecutionSpec{"parse_job": {LLM: &LLMBudget{Truncation: "sideways"}}})
+	assert.ErrorContains(t, err, "step execution config for parse_job")
+}
@@ -0,0 +1,102 @@
+package steps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+)
+
+// Execution backends for a step
+const (
+	BackendLocal      = "local"      // In the pipeline process (default)
+	BackendWorker     = "worker"     // Postgres step queue, claimed by `resume_agent worker`
+	BackendKubernetes = "kubernetes" // One Kubernetes Job per execution
+)
+
+// Kubernetes resource quantities such as "500m", "2", "512Mi", or "1Gi"
+var quantityPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(m|k|M|G|T|Ki|Mi|Gi|Ti)?$`)
+
+// ExecutionSpec configures where and with what resources a step executes
+type ExecutionSpec struct {
+	Backend        string `json:"backend,omitempty"`
+	Image          string `json:"image,omitempty"`  // Container image (kubernetes); defaults to K8S_JOB_IMAGE
+	CPU            string `json:"cpu,omitempty"`    // CPU request and limit, e.g. "500m"
+	Memory         string `json:"memory,omitempty"` // Memory request and limit, e.g. "1Gi"
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+
+	LLM                  *LLMBudget `json:"llm,omitempty"`                    // Replaces the step's LLM budget in StepRegistry when set
+	LatencyBudgetSeconds int        `json:"latency_budget_seconds,omitempty"` // Replaces the step's latency budget when set
+}
+
+// BackendOrDefault returns the configured backend, defaulting to BackendLocal
+func (e ExecutionSpec) BackendOrDefault() string {
+	if e.Backend == "" {
+		return BackendLocal
+	}
+	return e.Backend
+}
+
+// Validate checks the backend name and resource quantities
+func (e ExecutionSpec) Validate() error {
+	switch e.BackendOrDefault() {
+	case BackendLocal, BackendWorker, BackendKubernetes:
+	default:
+		return fmt.Errorf("unknown backend %q", e.Backend)
+	}
+	if e.CPU != "" && !quantityPattern.MatchString(e.CPU) {
+		return fmt.Errorf("invalid cpu quantity %q", e.CPU)
+	}
+	if e.Memory != "" && !quantityPattern.MatchString(e.Memory) {
+		return fmt.Errorf("invalid memory quantity %q", e.Memory)
+	}
+	if e.TimeoutSeconds < 0 {
+		return fmt.Errorf("timeout_seconds must not be negative, got: %d", e.TimeoutSeconds)
+	}
+	if e.LatencyBudgetSeconds < 0 {
+		return fmt.Errorf("latency_budget_seconds must not be negative, got: %d", e.LatencyBudgetSeconds)
+	}
+	if e.LLM != nil {
+		return e.LLM.Validate()
+	}
+	return nil
+}
+
+// LoadExecutionConfig reads a JSON object mapping step names to ExecutionSpecs
+// and applies it to StepRegistry. Like plugin loading, it runs once at startup.
+func LoadExecutionConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read step execution config: %w", err)
+	}
+	var specs map[string]ExecutionSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return fmt.Errorf("invalid step execution config %s: %w", path, err)
+	}
+	return ApplyExecutionConfig(specs)
+}
+
+// ApplyExecutionConfig validates and applies per-step execution specs to StepRegistry
+func ApplyExecutionConfig(specs map[string]ExecutionSpec) error {
+	for step, spec := range specs {
+		if _, ok := StepRegistry[step]; !ok {
+			return fmt.Errorf("step execution config: unknown step %s", step)
+		}
+		if err := spec.Validate(); err != nil {
+			return fmt.Errorf("step execution config for %s: %w", step, err)
+		}
+	}
+	for step, spec := range specs {
+		def := StepRegistry[step]
+		def.Execution = spec
+		if spec.LLM != nil {
+			def.LLM = *spec.LLM
+		}
+		if spec.LatencyBudgetSeconds > 0 {
+			def.LatencyBudget = time.Duration(spec.LatencyBudgetSeconds) * time.Second
+		}
+		StepRegistry[step] = def
+	}
+	return nil
+}
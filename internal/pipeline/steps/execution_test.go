@@ -0,0 +1,62 @@
+package steps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    ExecutionSpec
+		wantErr bool
+	}{
+		{"zero value is local", ExecutionSpec{}, false},
+		{"kubernetes with resources", ExecutionSpec{Backend: BackendKubernetes, CPU: "500m", Memory: "1Gi", TimeoutSeconds: 600}, false},
+		{"fractional cpu", ExecutionSpec{Backend: BackendWorker, CPU: "1.5"}, false},
+		{"unknown backend", ExecutionSpec{Backend: "lambda"}, true},
+		{"bad cpu", ExecutionSpec{Backend: BackendKubernetes, CPU: "lots"}, true},
+		{"bad memory", ExecutionSpec{Backend: BackendKubernetes, Memory: "1 GB"}, true},
+		{"negative timeout", ExecutionSpec{TimeoutSeconds: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Equal(t, BackendLocal, ExecutionSpec{}.BackendOrDefault())
+}
+
+func TestApplyExecutionConfig(t *testing.T) {
+	isolateRegistry(t)
+
+	err := ApplyExecutionConfig(map[string]ExecutionSpec{"no_such_step": {Backend: BackendWorker}})
+	assert.ErrorContains(t, err, "unknown step")
+
+	err = ApplyExecutionConfig(map[string]ExecutionSpec{
+		"validate_latex":   {Backend: BackendKubernetes, Memory: "2Gi"},
+		"research_company": {Backend: "nope"},
+	})
+	require.Error(t, err)
+	assert.Empty(t, StepRegistry["validate_latex"].Execution.Backend, "invalid config must not be partially applied")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "execution.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"validate_latex": {"backend": "kubernetes", "image": "latex:1", "memory": "2Gi"}}`), 0o600))
+	require.NoError(t, LoadExecutionConfig(path))
+
+	spec := StepRegistry["validate_latex"].Execution
+	assert.Equal(t, BackendKubernetes, spec.Backend)
+	assert.Equal(t, "latex:1", spec.Image)
+	assert.Equal(t, "2Gi", spec.Memory)
+}
@@ -0,0 +1,202 @@
+package steps
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Parameter types a step may declare, named as in JSON Schema
+const (
+	ParamString  = "string"
+	ParamInteger = "integer"
+	ParamNumber  = "number"
+	ParamBoolean = "boolean"
+)
+
+// ParamSpec describes one parameter accepted by POST /v1/runs/{run_id}/steps/{step_name}
+type ParamSpec struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Enum        []string `json:"enum,omitempty"`    // Allowed values of a string parameter
+	Minimum     *float64 `json:"minimum,omitempty"` // Inclusive bounds of a numeric parameter
+	Maximum     *float64 `json:"maximum,omitempty"`
+}
+
+// CommonParams are accepted by every step, in addition to its own Params
+var CommonParams = map[string]ParamSpec{
+	"model": {
+		Type:        ParamString,
+		Description: "Overrides the step's routed model with a tier (lite, standard, advanced) or a configured model",
+	},
+}
+
+// bound returns a pointer for ParamSpec minimums and maximums
+func bound(n float64) *float64 {
+	return &n
+}
+
+// Validate checks that a spec has a known type and consistent constraints
+func (p ParamSpec) Validate() error {
+	switch p.Type {
+	case ParamString, ParamInteger, ParamNumber, ParamBoolean:
+	default:
+		return fmt.Errorf("unknown type %q", p.Type)
+	}
+	if len(p.Enum) > 0 && p.Type != ParamString {
+		return fmt.Errorf("enum is only supported for string parameters")
+	}
+	if (p.Minimum != nil || p.Maximum != nil) && p.Type != ParamInteger && p.Type != ParamNumber {
+		return fmt.Errorf("minimum and maximum are only supported for numeric parameters")
+	}
+	if p.Minimum != nil && p.Maximum != nil && *p.Minimum > *p.Maximum {
+		return fmt.Errorf("minimum %v exceeds maximum %v", *p.Minimum, *p.Maximum)
+	}
+	return nil
+}
+
+// ParamIssue is one problem with a step's parameters
+type ParamIssue struct {
+	Param   string `json:"parameter"`
+	Message string `json:"message"`
+}
+
+// ParamError reports every problem found in a step's parameters
+type ParamError struct {
+	Step   string
+	Issues []ParamIssue
+}
+
+func (e *ParamError) Error() string {
+	msgs := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		msgs[i] = fmt.Sprintf("parameters.%s %s", issue.Param, issue.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ParamSchema returns every parameter a step accepts, including CommonParams. Steps
+// without a schema (plugins that declare no parameters) return nil.
+func ParamSchema(stepName string) map[string]ParamSpec {
+	def, ok := StepRegistry[stepName]
+	if !ok || def.Params == nil {
+		return nil
+	}
+	schema := make(map[string]ParamSpec, len(def.Params)+len(CommonParams))
+	for name, spec := range CommonParams {
+		schema[name] = spec
+	}
+	for name, spec := range def.Params {
+		schema[name] = spec
+	}
+	return schema
+}
+
+// ValidateParams checks request parameters against a step's schema, returning a
+// *ParamError listing unknown parameters, missing required ones, and values of the
+// wrong type or out of range. Steps without a schema only have CommonParams checked.
+func ValidateParams(stepName string, params map[string]interface{}) error {
+	if _, ok := StepRegistry[stepName]; !ok {
+		return fmt.Errorf("unknown step: %s", stepName)
+	}
+	schema := ParamSchema(stepName)
+	strict := schema != nil
+	if !strict {
+		schema = CommonParams
+	}
+
+	var issues []ParamIssue
+	for _, name := range sortedKeys(params) {
+		spec, ok := schema[name]
+		if !ok {
+			if strict {
+				issues = append(issues, ParamIssue{Param: name, Message: unknownParamMessage(schema)})
+			}
+			continue
+		}
+		if msg := spec.check(params[name]); msg != "" {
+			issues = append(issues, ParamIssue{Param: name, Message: msg})
+		}
+	}
+	for _, name := range sortedKeys(schema) {
+		if _, present := params[name]; schema[name].Required && !present {
+			issues = append(issues, ParamIssue{Param: name, Message: "is required"})
+		}
+	}
+
+	if len(issues) > 0 {
+		return &ParamError{Step: stepName, Issues: issues}
+	}
+	return nil
+}
+
+// check returns why a decoded JSON value doesn't satisfy the spec, or ""
+func (p ParamSpec) check(value interface{}) string {
+	switch p.Type {
+	case ParamString:
+		s, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if len(p.Enum) > 0 && !contains(p.Enum, s) {
+			return fmt.Sprintf("must be one of %s, got %q", strings.Join(p.Enum, ", "), s)
+		}
+	case ParamBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean"
+		}
+	case ParamInteger, ParamNumber:
+		n, ok := number(value)
+		if !ok {
+			return "must be a number"
+		}
+		if p.Type == ParamInteger && n != math.Trunc(n) {
+			return fmt.Sprintf("must be an integer, got %v", n)
+		}
+		if p.Minimum != nil && n < *p.Minimum {
+			return fmt.Sprintf("must be at least %v, got %v", *p.Minimum, n)
+		}
+		if p.Maximum != nil && n > *p.Maximum {
+			return fmt.Sprintf("must not exceed %v, got %v", *p.Maximum, n)
+		}
+	}
+	return ""
+}
+
+// number converts the numeric types a parameter map may hold to float64
+func number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func unknownParamMessage(schema map[string]ParamSpec) string {
+	return "is not a parameter of this step; accepted: " + strings.Join(sortedKeys(schema), ", ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
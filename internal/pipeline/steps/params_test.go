@@ -0,0 +1,92 @@
+package steps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepRegistryParams(t *testing.T) {
+	for name, def := range StepRegistry {
+		require.NotNil(t, def.Params, "built-in step %s should declare its parameters", name)
+		for param, spec := range def.Params {
+			assert.NoError(t, spec.Validate(), "%s.%s", name, param)
+			_, common := CommonParams[param]
+			assert.False(t, common, "%s.%s shadows a common parameter", name, param)
+		}
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    string
+		params  map[string]interface{}
+		wantErr []ParamIssue
+	}{
+		{"no parameters", "select_plan", nil, nil},
+		{"valid", "select_plan", map[string]interface{}{"model": "advanced", "max_bullets": float64(12)}, nil},
+		{"go integers", "research_company", map[string]interface{}{"max_depth": 0, "same_domain_only": true}, nil},
+		{"unknown", "parse_job", map[string]interface{}{"max_bullets": float64(3)},
+			[]ParamIssue{{Param: "max_bullets", Message: "is not a parameter of this step; accepted: model"}}},
+		{"wrong types", "research_company", map[string]interface{}{"max_pages": "3", "same_domain_only": "yes", "model": float64(1)},
+			[]ParamIssue{
+				{Param: "max_pages", Message: "must be a number"},
+				{Param: "model", Message: "must be a string"},
+				{Param: "same_domain_only", Message: "must be a boolean"},
+			}},
+		{"fraction", "rewrite_bullets", map[string]interface{}{"batch_size": 2.5},
+			[]ParamIssue{{Param: "batch_size", Message: "must be an integer, got 2.5"}}},
+		{"out of range", "select_plan", map[string]interface{}{"max_bullets": float64(0), "max_lines": float64(500)},
+			[]ParamIssue{
+				{Param: "max_bullets", Message: "must be at least 1, got 0"},
+				{Param: "max_lines", Message: "must not exceed 200, got 500"},
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateParams(tt.step, tt.params)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			var perr *ParamError
+			require.ErrorAs(t, err, &perr)
+			assert.Equal(t, tt.step, perr.Step)
+			assert.Equal(t, tt.wantErr, perr.Issues)
+		})
+	}
+}
+
+func TestValidateParams_Plugins(t *testing.T) {
+	isolateRegistry(t)
+
+	require.NoError(t, RegisterPlugin(&Plugin{Manifest: PluginManifest{Name: "freeform", Command: []string{"x"}}}))
+	require.NoError(t, RegisterPlugin(&Plugin{Manifest: PluginManifest{Name: "typed", Command: []string{"x"},
+		Parameters: map[string]ParamSpec{
+			"tone":    {Type: ParamString, Required: true, Enum: []string{"formal", "casual"}},
+			"weights": {Type: ParamNumber, Minimum: bound(0), Maximum: bound(1)},
+		}}}))
+
+	assert.NoError(t, ValidateParams("freeform", map[string]interface{}{"anything": []interface{}{1, 2}}),
+		"plugins without a schema receive any parameters")
+	assert.EqualError(t, ValidateParams("freeform", map[string]interface{}{"model": true}),
+		"parameters.model must be a string", "common parameters are still checked")
+
+	assert.NoError(t, ValidateParams("typed", map[string]interface{}{"tone": "formal", "weights": 0.5}))
+	assert.EqualError(t, ValidateParams("typed", map[string]interface{}{"tone": "snarky"}),
+		`parameters.tone must be one of formal, casual, got "snarky"`)
+	assert.EqualError(t, ValidateParams("typed", nil), "parameters.tone is required")
+
+	assert.Equal(t, []string{"model", "tone", "weights"}, sortedKeys(ParamSchema("typed")))
+	assert.Nil(t, ParamSchema("freeform"))
+}
+
+func TestParamSpecValidate(t *testing.T) {
+	assert.Error(t, ParamSpec{Type: ParamBoolean, Enum: []string{"yes"}}.Validate())
+	assert.Error(t, ParamSpec{Type: ParamString, Minimum: bound(1)}.Validate())
+	assert.Error(t, ParamSpec{Type: ParamInteger, Minimum: bound(5), Maximum: bound(1)}.Validate())
+	assert.NoError(t, ParamSpec{Type: ParamNumber, Minimum: bound(0)}.Validate())
+}
@@ -0,0 +1,292 @@
+package steps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	dbpkg "github.com/jonathan/resume-customizer/internal/db"
+)
+
+// DefaultPluginTimeout bounds a plugin invocation when the manifest sets no timeout
+const DefaultPluginTimeout = 2 * time.Minute
+
+// maxPluginOutput caps how much stdout a plugin may produce
+const maxPluginOutput = 10 << 20
+
+var pluginNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// basePluginEnv is the environment every plugin receives. Anything else, including
+// API keys and database credentials, must be requested by name in the manifest.
+var basePluginEnv = []string{"PATH", "HOME", "LANG", "TMPDIR"}
+
+// PluginManifest describes a custom step implemented by an external executable.
+// Manifests are JSON files; the executable speaks the subprocess protocol:
+// a PluginRequest is written to stdin and a PluginResponse is read from stdout.
+type PluginManifest struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description,omitempty"`
+	Category       string   `json:"category,omitempty"` // Defaults to "plugin"
+	Command        []string `json:"command"`            // argv; relative paths resolve against the manifest directory
+	Dependencies   []string `json:"dependencies,omitempty"`
+	Optional       []string `json:"optional,omitempty"`
+	Inputs         []string `json:"inputs,omitempty"`    // Extra artifact steps passed to the plugin besides its dependencies
+	Artifacts      []string `json:"artifacts,omitempty"` // Artifact steps the plugin may produce (defaults to its name)
+	Env            []string `json:"env,omitempty"`       // Server environment variables passed through to the plugin
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+	// Parameters the step accepts besides CommonParams; without them any are passed through
+	Parameters map[string]ParamSpec `json:"parameters,omitempty"`
+}
+
+// Plugin is a registered custom step
+type Plugin struct {
+	Manifest PluginManifest
+	Dir      string // Directory the manifest was loaded from
+}
+
+// PluginRequest is the JSON document written to a plugin's stdin
+type PluginRequest struct {
+	RunID  string                     `json:"run_id"`
+	Step   string                     `json:"step"`
+	Params map[string]interface{}     `json:"params,omitempty"`
+	Inputs map[string]json.RawMessage `json:"inputs"` // Artifact content keyed by step name
+}
+
+// PluginResponse is the JSON document a plugin writes to stdout
+type PluginResponse struct {
+	Artifacts map[string]json.RawMessage `json:"artifacts,omitempty"`
+	Message   string                     `json:"message,omitempty"`
+	Error     string                     `json:"error,omitempty"`
+}
+
+// Plugins holds registered plugin steps keyed by step name.
+// Like StepRegistry, it is populated at startup and read-only afterwards.
+var Plugins = map[string]*Plugin{}
+
+// Timeout returns the plugin's execution timeout
+func (p *Plugin) Timeout() time.Duration {
+	if p.Manifest.TimeoutSeconds > 0 {
+		return time.Duration(p.Manifest.TimeoutSeconds) * time.Second
+	}
+	return DefaultPluginTimeout
+}
+
+// InputSteps returns the artifact steps passed to the plugin: its dependencies,
+// optional dependencies, and declared inputs, deduplicated in that order
+func (p *Plugin) InputSteps() []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, list := range [][]string{p.Manifest.Dependencies, p.Manifest.Optional, p.Manifest.Inputs} {
+		for _, step := range list {
+			if !seen[step] {
+				seen[step] = true
+				out = append(out, step)
+			}
+		}
+	}
+	return out
+}
+
+// LoadPluginManifests reads every *.json manifest in dir
+func LoadPluginManifests(dir string) ([]*Plugin, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plugin manifests: %w", err)
+	}
+	sort.Strings(paths)
+
+	plugins := make([]*Plugin, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plugin manifest %s: %w", path, err)
+		}
+		var manifest PluginManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid plugin manifest %s: %w", path, err)
+		}
+		plugins = append(plugins, &Plugin{Manifest: manifest, Dir: filepath.Dir(path)})
+	}
+	return plugins, nil
+}
+
+// LoadPlugins loads and registers all plugin manifests in dir. Plugins may depend
+// on built-in steps and on each other; manifests are registered once their
+// dependencies are known, so file order does not matter.
+func LoadPlugins(dir string) error {
+	plugins, err := LoadPluginManifests(dir)
+	if err != nil {
+		return err
+	}
+
+	pending := plugins
+	for len(pending) > 0 {
+		var next []*Plugin
+		for _, p := range pending {
+			if !dependenciesKnown(p.Manifest) {
+				next = append(next, p)
+				continue
+			}
+			if err := RegisterPlugin(p); err != nil {
+				return err
+			}
+		}
+		if len(next) == len(pending) {
+			// No progress: report the first plugin's problem
+			return RegisterPlugin(next[0])
+		}
+		pending = next
+	}
+	return nil
+}
+
+// dependenciesKnown reports whether all of a manifest's dependencies are registered
+func dependenciesKnown(m PluginManifest) bool {
+	for _, dep := range append(append([]string{}, m.Dependencies...), m.Optional...) {
+		if _, ok := StepRegistry[dep]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// RegisterPlugin validates a plugin and adds it to StepRegistry and Plugins
+func RegisterPlugin(p *Plugin) error {
+	m := &p.Manifest
+	if !pluginNamePattern.MatchString(m.Name) {
+		return fmt.Errorf("invalid plugin name %q: must match %s", m.Name, pluginNamePattern)
+	}
+	if _, exists := StepRegistry[m.Name]; exists {
+		return fmt.Errorf("plugin %s: step already registered", m.Name)
+	}
+	if len(m.Command) == 0 || m.Command[0] == "" {
+		return fmt.Errorf("plugin %s: command is required", m.Name)
+	}
+	for _, dep := range append(append([]string{}, m.Dependencies...), m.Optional...) {
+		if _, ok := StepRegistry[dep]; !ok {
+			return fmt.Errorf("plugin %s: unknown dependency %s", m.Name, dep)
+		}
+	}
+	if m.Category == "" {
+		m.Category = dbpkg.StepCategoryPlugin
+	}
+	if len(m.Artifacts) == 0 {
+		m.Artifacts = []string{m.Name}
+	}
+	for _, artifact := range m.Artifacts {
+		// Namespacing artifacts under the plugin name keeps them from overwriting pipeline output
+		if artifact != m.Name && !strings.HasPrefix(artifact, m.Name+"_") {
+			return fmt.Errorf("plugin %s: artifact %q must be named %s or %s_*", m.Name, artifact, m.Name, m.Name)
+		}
+	}
+
+	for _, name := range sortedKeys(m.Parameters) {
+		if _, common := CommonParams[name]; common {
+			return fmt.Errorf("plugin %s: parameter %s is reserved", m.Name, name)
+		}
+		if err := m.Parameters[name].Validate(); err != nil {
+			return fmt.Errorf("plugin %s: parameter %s: %w", m.Name, name, err)
+		}
+	}
+
+	StepRegistry[m.Name] = StepDefinition{
+		Name:         m.Name,
+		Category:     m.Category,
+		Dependencies: append([]string{}, m.Dependencies...),
+		Optional:     append([]string{}, m.Optional...),
+		Params:       m.Parameters,
+	}
+	Plugins[m.Name] = p
+	return nil
+}
+
+// Run executes the plugin subprocess with req on stdin and parses its response.
+// A non-zero exit, malformed output, a reported error, or an undeclared artifact
+// are all returned as errors.
+func (p *Plugin) Run(ctx context.Context, req *PluginRequest) (*PluginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout())
+	defer cancel()
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal plugin request: %w", err)
+	}
+
+	name := p.Manifest.Command[0]
+	if !filepath.IsAbs(name) && strings.ContainsRune(name, filepath.Separator) {
+		name = filepath.Join(p.Dir, name)
+	}
+	cmd := exec.CommandContext(ctx, name, p.Manifest.Command[1:]...)
+	cmd.Dir = p.Dir
+	cmd.Env = p.environ()
+	cmd.WaitDelay = time.Second // Don't block on grandchildren that inherit stdout after a kill
+	cmd.Stdin = bytes.NewReader(payload)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &limitedBuffer{buf: &stdout, limit: maxPluginOutput}
+	cmd.Stderr = &limitedBuffer{buf: &stderr, limit: 64 << 10}
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("plugin %s timed out after %s", p.Manifest.Name, p.Timeout())
+		}
+		return nil, fmt.Errorf("plugin %s failed: %w: %s", p.Manifest.Name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var resp PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return nil, fmt.Errorf("plugin %s returned invalid JSON: %w", p.Manifest.Name, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s reported error: %s", p.Manifest.Name, resp.Error)
+	}
+
+	declared := make(map[string]bool, len(p.Manifest.Artifacts))
+	for _, a := range p.Manifest.Artifacts {
+		declared[a] = true
+	}
+	for step := range resp.Artifacts {
+		if !declared[step] {
+			return nil, fmt.Errorf("plugin %s produced undeclared artifact %s", p.Manifest.Name, step)
+		}
+	}
+	return &resp, nil
+}
+
+// environ returns the allowlisted environment for the plugin subprocess. It is never
+// nil, since a nil cmd.Env would inherit the whole server environment.
+func (p *Plugin) environ() []string {
+	env := []string{}
+	for _, list := range [][]string{basePluginEnv, p.Manifest.Env} {
+		for _, key := range list {
+			if value, ok := os.LookupEnv(key); ok {
+				env = append(env, key+"="+value)
+			}
+		}
+	}
+	return env
+}
+
+// limitedBuffer discards writes past limit so a misbehaving plugin can't exhaust memory
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (l *limitedBuffer) Write(p []byte) (int, error) {
+	if room := l.limit - l.buf.Len(); room > 0 {
+		if len(p) > room {
+			l.buf.Write(p[:room])
+		} else {
+			l.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,195 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbpkg "github.com/jonathan/resume-customizer/internal/db"
+)
+
+// isolateRegistry restores StepRegistry and Plugins after the test
+func isolateRegistry(t *testing.T) {
+	t.Helper()
+	registry := make(map[string]StepDefinition, len(StepRegistry))
+	for k, v := range StepRegistry {
+		registry[k] = v
+	}
+	plugins := make(map[string]*Plugin, len(Plugins))
+	for k, v := range Plugins {
+		plugins[k] = v
+	}
+	t.Cleanup(func() {
+		StepRegistry = registry
+		Plugins = plugins
+	})
+}
+
+func writeManifest(t *testing.T, dir string, m PluginManifest) {
+	t.Helper()
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, m.Name+".json"), data, 0o600))
+}
+
+func writeScript(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o700)) //nolint:gosec // test executable
+	return path
+}
+
+func TestRegisterPlugin(t *testing.T) {
+	isolateRegistry(t)
+
+	p := &Plugin{Manifest: PluginManifest{
+		Name:         "portfolio_update",
+		Command:      []string{"./update.sh"},
+		Dependencies: []string{"render_latex"},
+	}}
+	require.NoError(t, RegisterPlugin(p))
+
+	def, ok := StepRegistry["portfolio_update"]
+	require.True(t, ok)
+	assert.Equal(t, dbpkg.StepCategoryPlugin, def.Category)
+	assert.Equal(t, []string{"render_latex"}, def.Dependencies)
+	assert.Equal(t, []string{"portfolio_update"}, p.Manifest.Artifacts)
+	assert.Same(t, p, Plugins["portfolio_update"])
+}
+
+func TestRegisterPlugin_Invalid(t *testing.T) {
+	isolateRegistry(t)
+
+	tests := []struct {
+		name     string
+		manifest PluginManifest
+		wantErr  string
+	}{
+		{"bad name", PluginManifest{Name: "Bad-Name", Command: []string{"x"}}, "invalid plugin name"},
+		{"builtin collision", PluginManifest{Name: "parse_job", Command: []string{"x"}}, "already registered"},
+		{"no command", PluginManifest{Name: "no_cmd"}, "command is required"},
+		{"unknown dep", PluginManifest{Name: "orphan", Command: []string{"x"}, Dependencies: []string{"nope"}}, "unknown dependency"},
+		{"unscoped artifact", PluginManifest{Name: "leaky", Command: []string{"x"}, Artifacts: []string{"resume_tex"}}, "must be named"},
+		{"reserved parameter", PluginManifest{Name: "shadow", Command: []string{"x"},
+			Parameters: map[string]ParamSpec{"model": {Type: ParamString}}}, "reserved"},
+		{"bad parameter", PluginManifest{Name: "typo", Command: []string{"x"},
+			Parameters: map[string]ParamSpec{"depth": {Type: "int"}}}, "parameter depth: unknown type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterPlugin(&Plugin{Manifest: tt.manifest})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadPlugins_ResolvesPluginDependencies(t *testing.T) {
+	isolateRegistry(t)
+	dir := t.TempDir()
+
+	// "a_notify" sorts first but depends on "b_publish"
+	writeManifest(t, dir, PluginManifest{Name: "a_notify", Command: []string{"true"}, Dependencies: []string{"b_publish"}})
+	writeManifest(t, dir, PluginManifest{Name: "b_publish", Command: []string{"true"}, Dependencies: []string{"validate_latex"}})
+
+	require.NoError(t, LoadPlugins(dir))
+	assert.Contains(t, StepRegistry, "a_notify")
+	assert.Contains(t, StepRegistry, "b_publish")
+}
+
+func TestLoadPlugins_UnknownDependency(t *testing.T) {
+	isolateRegistry(t)
+	dir := t.TempDir()
+	writeManifest(t, dir, PluginManifest{Name: "lonely", Command: []string{"true"}, Dependencies: []string{"missing_step"}})
+
+	err := LoadPlugins(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown dependency missing_step")
+}
+
+func TestPluginInputSteps(t *testing.T) {
+	p := &Plugin{Manifest: PluginManifest{
+		Dependencies: []string{"render_latex"},
+		Optional:     []string{"summarize_voice"},
+		Inputs:       []string{"parse_job", "render_latex"},
+	}}
+	assert.Equal(t, []string{"render_latex", "summarize_voice", "parse_job"}, p.InputSteps())
+}
+
+func TestPluginRun(t *testing.T) {
+	dir := t.TempDir()
+	// Echo the step name back inside a declared artifact
+	writeScript(t, dir, "echo.sh", `read -r line
+step=$(echo "$line" | sed 's/.*"step":"\([^"]*\)".*/\1/')
+printf '{"artifacts":{"echo_out":{"step":"%s"}},"message":"ok"}' "$step"
+`)
+	p := &Plugin{
+		Manifest: PluginManifest{Name: "echo", Command: []string{"./echo.sh"}, Artifacts: []string{"echo_out"}},
+		Dir:      dir,
+	}
+
+	resp, err := p.Run(context.Background(), &PluginRequest{RunID: "r1", Step: "echo"})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.Message)
+	assert.JSONEq(t, `{"step":"echo"}`, string(resp.Artifacts["echo_out"]))
+}
+
+func TestPluginRun_Environment(t *testing.T) {
+	t.Setenv("PLUGIN_TEST_SECRET", "hunter2")
+	t.Setenv("PLUGIN_TEST_ALLOWED", "visible")
+	dir := t.TempDir()
+	writeScript(t, dir, "env.sh", `printf '{"artifacts":{"env":{"secret":"%s","allowed":"%s"}}}' "$PLUGIN_TEST_SECRET" "$PLUGIN_TEST_ALLOWED"`+"\n")
+	p := &Plugin{
+		Manifest: PluginManifest{Name: "env", Command: []string{"./env.sh"}, Artifacts: []string{"env"}, Env: []string{"PLUGIN_TEST_ALLOWED"}},
+		Dir:      dir,
+	}
+
+	resp, err := p.Run(context.Background(), &PluginRequest{Step: "env"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"secret":"","allowed":"visible"}`, string(resp.Artifacts["env"]))
+}
+
+func TestPluginRun_Errors(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "fail.sh", "echo broken >&2\nexit 3\n")
+	writeScript(t, dir, "reported.sh", `echo '{"error":"no portfolio configured"}'`+"\n")
+	writeScript(t, dir, "garbage.sh", "echo not-json\n")
+	writeScript(t, dir, "undeclared.sh", `echo '{"artifacts":{"resume_tex":"x"}}'`+"\n")
+	writeScript(t, dir, "slow.sh", "sleep 5\n")
+
+	tests := []struct {
+		script  string
+		timeout int
+		wantErr string
+	}{
+		{"fail.sh", 0, "broken"},
+		{"reported.sh", 0, "no portfolio configured"},
+		{"garbage.sh", 0, "invalid JSON"},
+		{"undeclared.sh", 0, "undeclared artifact"},
+		{"slow.sh", 1, "timed out"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.script, func(t *testing.T) {
+			p := &Plugin{
+				Manifest: PluginManifest{Name: "p", Command: []string{"./" + tt.script}, Artifacts: []string{"p"}, TimeoutSeconds: tt.timeout},
+				Dir:      dir,
+			}
+			_, err := p.Run(context.Background(), &PluginRequest{Step: "p"})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPluginTimeout(t *testing.T) {
+	assert.Equal(t, DefaultPluginTimeout, (&Plugin{}).Timeout())
+	assert.Equal(t, 30*time.Second, (&Plugin{Manifest: PluginManifest{TimeoutSeconds: 30}}).Timeout())
+}
@@ -5,6 +5,8 @@ package steps
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	dbpkg "github.com/jonathan/resume-customizer/internal/db"
@@ -17,10 +19,14 @@ type Client interface {
 
 // StepDefinition defines metadata for a pipeline step
 type StepDefinition struct {
-	Name         string
-	Category     string
-	Dependencies []string
-	Optional     []string
+	Name          string
+	Category      string
+	Dependencies  []string
+	Optional      []string
+	Params        map[string]ParamSpec // Step-specific parameters besides CommonParams; nil accepts any
+	Execution     ExecutionSpec        // Where the step runs; zero value means in-process
+	LLM           LLMBudget            // Timeout and context budget for the step's LLM calls; zero value is unbounded
+	LatencyBudget time.Duration        // How long a successful execution should take (see the SLO report); zero means no budget
 }
 
 // StepExecutor defines the interface for executing pipeline steps
@@ -42,6 +48,10 @@ type StepResult struct {
 	Metadata   map[string]interface{}
 }
 
+// resumePagesParam is the page limit validation and repair hold the resume to
+// (pipeline.MaxResumePages at most)
+var resumePagesParam = ParamSpec{Type: ParamInteger, Description: "Pages the resume may fill", Minimum: bound(1), Maximum: bound(2)}
+
 // StepRegistry holds all step definitions
 var StepRegistry = map[string]StepDefinition{
 	"ingest_job": {
@@ -49,87 +59,190 @@ var StepRegistry = map[string]StepDefinition{
 		Category:     dbpkg.StepCategoryIngestion,
 		Dependencies: []string{},
 		Optional:     []string{},
+		Params: map[string]ParamSpec{
+			"job_text": {Type: ParamString, Description: "Posting text to ingest instead of fetching the run's job_url"},
+		},
+		LatencyBudget: 30 * time.Second,
 	},
 	"parse_job": {
-		Name:         "parse_job",
-		Category:     dbpkg.StepCategoryIngestion,
-		Dependencies: []string{"ingest_job"},
-		Optional:     []string{},
+		Name:          "parse_job",
+		Category:      dbpkg.StepCategoryIngestion,
+		Dependencies:  []string{"ingest_job"},
+		Optional:      []string{},
+		Params:        map[string]ParamSpec{},
+		LLM:           LLMBudget{TimeoutSeconds: 90, MaxContextTokens: 64000, Truncation: "middle"},
+		LatencyBudget: 60 * time.Second,
 	},
 	"extract_education": {
-		Name:         "extract_education",
-		Category:     dbpkg.StepCategoryIngestion,
-		Dependencies: []string{"parse_job"},
-		Optional:     []string{},
+		Name:          "extract_education",
+		Category:      dbpkg.StepCategoryIngestion,
+		Dependencies:  []string{"parse_job"},
+		Optional:      []string{},
+		Params:        map[string]ParamSpec{},
+		LLM:           LLMBudget{TimeoutSeconds: 60, MaxContextTokens: 32000, Truncation: "middle"},
+		LatencyBudget: 30 * time.Second,
 	},
 	"load_experience": {
-		Name:         "load_experience",
-		Category:     dbpkg.StepCategoryExperience,
-		Dependencies: []string{},
-		Optional:     []string{},
+		Name:          "load_experience",
+		Category:      dbpkg.StepCategoryExperience,
+		Dependencies:  []string{},
+		Optional:      []string{},
+		Params:        map[string]ParamSpec{},
+		LatencyBudget: 5 * time.Second,
 	},
 	"rank_stories": {
-		Name:         "rank_stories",
-		Category:     dbpkg.StepCategoryExperience,
-		Dependencies: []string{"parse_job", "load_experience"},
-		Optional:     []string{},
+		Name:          "rank_stories",
+		Category:      dbpkg.StepCategoryExperience,
+		Dependencies:  []string{"parse_job", "load_experience"},
+		Optional:      []string{"research_company"}, // Tech stack bias includes the company's blog
+		Params:        map[string]ParamSpec{},
+		LatencyBudget: 30 * time.Second,
 	},
 	"score_education": {
-		Name:         "score_education",
-		Category:     dbpkg.StepCategoryExperience,
-		Dependencies: []string{"parse_job", "load_experience"},
-		Optional:     []string{},
+		Name:          "score_education",
+		Category:      dbpkg.StepCategoryExperience,
+		Dependencies:  []string{"parse_job", "load_experience"},
+		Optional:      []string{"extract_education"},
+		Params:        map[string]ParamSpec{},
+		LatencyBudget: 20 * time.Second,
 	},
 	"select_plan": {
 		Name:         "select_plan",
 		Category:     dbpkg.StepCategoryExperience,
 		Dependencies: []string{"rank_stories"},
 		Optional:     []string{"score_education"},
+		Params: map[string]ParamSpec{
+			"max_bullets": {Type: ParamInteger, Description: "Maximum bullets selected across all stories", Minimum: bound(1), Maximum: bound(100)},
+			"max_lines":   {Type: ParamInteger, Description: "Maximum rendered lines the plan may fill", Minimum: bound(1), Maximum: bound(200)},
+		},
+		LatencyBudget: 5 * time.Second,
 	},
 	"materialize_bullets": {
-		Name:         "materialize_bullets",
-		Category:     dbpkg.StepCategoryExperience,
-		Dependencies: []string{"select_plan"},
-		Optional:     []string{},
+		Name:          "materialize_bullets",
+		Category:      dbpkg.StepCategoryExperience,
+		Dependencies:  []string{"select_plan"},
+		Optional:      []string{},
+		Params:        map[string]ParamSpec{},
+		LatencyBudget: 5 * time.Second,
+	},
+	"suggest_bullets": {
+		Name:          "suggest_bullets",
+		Category:      dbpkg.StepCategoryExperience,
+		Dependencies:  []string{"parse_job", "load_experience"},
+		Optional:      []string{},
+		Params:        map[string]ParamSpec{},
+		LLM:           LLMBudget{TimeoutSeconds: 90, MaxContextTokens: 32000, Truncation: "middle"},
+		LatencyBudget: 60 * time.Second,
 	},
 	"research_company": {
 		Name:         "research_company",
 		Category:     dbpkg.StepCategoryResearch,
 		Dependencies: []string{"parse_job"},
 		Optional:     []string{},
+		Params: map[string]ParamSpec{
+			"max_pages":        {Type: ParamInteger, Description: "Pages processed in the research session", Minimum: bound(1)},
+			"max_depth":        {Type: ParamInteger, Description: "Link hops from a seed URL the crawler may follow", Minimum: bound(0)},
+			"same_domain_only": {Type: ParamBoolean, Description: "Restrict crawling to the company's own domains"},
+		},
+		LLM:           LLMBudget{TimeoutSeconds: 60, MaxContextTokens: 32000, Truncation: "middle"}, // Per-page filtering and moderation calls
+		LatencyBudget: 180 * time.Second,
 	},
 	"summarize_voice": {
-		Name:         "summarize_voice",
-		Category:     dbpkg.StepCategoryResearch,
-		Dependencies: []string{"research_company"},
-		Optional:     []string{},
+		Name:          "summarize_voice",
+		Category:      dbpkg.StepCategoryResearch,
+		Dependencies:  []string{"research_company"},
+		Optional:      []string{},
+		Params:        map[string]ParamSpec{},
+		LLM:           LLMBudget{TimeoutSeconds: 90, MaxContextTokens: 64000, Truncation: "middle"},
+		LatencyBudget: 60 * time.Second,
 	},
 	"rewrite_bullets": {
 		Name:         "rewrite_bullets",
 		Category:     dbpkg.StepCategoryRewriting,
 		Dependencies: []string{"materialize_bullets", "summarize_voice"},
 		Optional:     []string{},
+		Params: map[string]ParamSpec{
+			"batch_size": {Type: ParamInteger, Description: "Bullets rewritten per LLM call", Minimum: bound(1), Maximum: bound(50)},
+		},
+		LLM:           LLMBudget{TimeoutSeconds: 120, MaxContextTokens: 32000, Truncation: "none"}, // Oversized batches are split rather than cut
+		LatencyBudget: 120 * time.Second,
 	},
 	"render_latex": {
 		Name:         "render_latex",
 		Category:     dbpkg.StepCategoryValidation,
 		Dependencies: []string{"rewrite_bullets"},
 		Optional:     []string{},
+		Params: map[string]ParamSpec{
+			"template": {Type: ParamString, Description: "Path of the LaTeX template to render with"},
+		},
+		LatencyBudget: 10 * time.Second,
 	},
 	"validate_latex": {
 		Name:         "validate_latex",
 		Category:     dbpkg.StepCategoryValidation,
 		Dependencies: []string{"render_latex"},
 		Optional:     []string{},
+		Params: map[string]ParamSpec{
+			"resume_pages": resumePagesParam,
+		},
+		LatencyBudget: 60 * time.Second,
 	},
 	"repair_violations": {
 		Name:         "repair_violations",
 		Category:     dbpkg.StepCategoryValidation,
 		Dependencies: []string{"validate_latex"},
 		Optional:     []string{},
+		Params: map[string]ParamSpec{
+			"resume_pages": resumePagesParam, // Defaults to the limit validate_latex checked
+		},
+		LLM:           LLMBudget{TimeoutSeconds: 120, MaxContextTokens: 32000, Truncation: "none"},
+		LatencyBudget: 120 * time.Second,
+	},
+	"write_cover_letter": {
+		Name:          "write_cover_letter",
+		Category:      dbpkg.StepCategoryRewriting,
+		Dependencies:  []string{"rewrite_bullets", "summarize_voice"},
+		Optional:      []string{},
+		Params:        map[string]ParamSpec{},
+		LLM:           LLMBudget{TimeoutSeconds: 90, MaxContextTokens: 32000, Truncation: "middle"},
+		LatencyBudget: 60 * time.Second,
 	},
 }
 
+// RunTypes holds the steps each run type other than a full run executes, in order.
+// Their dependencies are met by steps reused from the run's source run. A full run
+// may execute any step.
+var RunTypes = map[string][]string{
+	dbpkg.RunTypeCoverLetter: {"write_cover_letter"},
+	dbpkg.RunTypeRefresh:     {"render_latex", "validate_latex", "repair_violations"},
+}
+
+// ValidRunType reports whether runType names a kind of run; empty stands for a full run
+func ValidRunType(runType string) bool {
+	_, ok := RunTypes[runType]
+	return ok || runType == "" || runType == dbpkg.RunTypeFull
+}
+
+// InRunType reports whether a run of runType executes the step
+func InRunType(runType, stepName string) bool {
+	subset, ok := RunTypes[runType]
+	return !ok || slices.Contains(subset, stepName)
+}
+
+// FilterRunType returns the steps in stepNames a run of runType executes
+func FilterRunType(runType string, stepNames []string) []string {
+	if _, ok := RunTypes[runType]; !ok {
+		return stepNames
+	}
+	filtered := []string{}
+	for _, name := range stepNames {
+		if InRunType(runType, name) {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
+
 // DependencyError represents a dependency validation error
 type DependencyError struct {
 	Step                string
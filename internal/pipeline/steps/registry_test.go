@@ -16,10 +16,10 @@ func TestStepRegistry(t *testing.T) {
 	expectedSteps := []string{
 		"ingest_job", "parse_job", "extract_education",
 		"load_experience", "rank_stories", "score_education",
-		"select_plan", "materialize_bullets",
+		"select_plan", "materialize_bullets", "suggest_bullets",
 		"research_company", "summarize_voice",
 		"rewrite_bullets", "render_latex", "validate_latex",
-		"repair_violations",
+		"repair_violations", "write_cover_letter",
 	}
 
 	for _, stepName := range expectedSteps {
@@ -33,9 +33,9 @@ func TestStepRegistry(t *testing.T) {
 func TestStepRegistryCategories(t *testing.T) {
 	categories := map[string][]string{
 		dbpkg.StepCategoryIngestion:  {"ingest_job", "parse_job", "extract_education"},
-		dbpkg.StepCategoryExperience: {"load_experience", "rank_stories", "score_education", "select_plan", "materialize_bullets"},
+		dbpkg.StepCategoryExperience: {"load_experience", "rank_stories", "score_education", "select_plan", "materialize_bullets", "suggest_bullets"},
 		dbpkg.StepCategoryResearch:   {"research_company", "summarize_voice"},
-		dbpkg.StepCategoryRewriting:  {"rewrite_bullets"},
+		dbpkg.StepCategoryRewriting:  {"rewrite_bullets", "write_cover_letter"},
 		dbpkg.StepCategoryValidation: {"render_latex", "validate_latex", "repair_violations"},
 	}
 
@@ -48,6 +48,29 @@ func TestStepRegistryCategories(t *testing.T) {
 	}
 }
 
+func TestRunTypes(t *testing.T) {
+	for runType, subset := range RunTypes {
+		assert.True(t, ValidRunType(runType))
+		for _, stepName := range subset {
+			_, ok := StepRegistry[stepName]
+			assert.True(t, ok, "%s runs unknown step %s", runType, stepName)
+		}
+	}
+	assert.True(t, ValidRunType(""))
+	assert.True(t, ValidRunType(dbpkg.RunTypeFull))
+	assert.False(t, ValidRunType("resume"))
+
+	assert.True(t, InRunType(dbpkg.RunTypeFull, "ingest_job"))
+	assert.True(t, InRunType("", "write_cover_letter"))
+	assert.False(t, InRunType(dbpkg.RunTypeCoverLetter, "render_latex"))
+	assert.True(t, InRunType(dbpkg.RunTypeRefresh, "render_latex"))
+
+	available := []string{"ingest_job", "load_experience", "write_cover_letter"}
+	assert.Equal(t, []string{"write_cover_letter"}, FilterRunType(dbpkg.RunTypeCoverLetter, available))
+	assert.Equal(t, []string{}, FilterRunType(dbpkg.RunTypeRefresh, available))
+	assert.Equal(t, available, FilterRunType(dbpkg.RunTypeFull, available))
+}
+
 func TestDependencyError(t *testing.T) {
 	err := &DependencyError{
 		Step:                "test_step",
@@ -0,0 +1,47 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/suggestions"
+)
+
+// suggestBullets drafts bullets, phrased as questions, for requirements the experience
+// bank does not cover and queues them for the owner's review (non-fatal). Suggestions
+// only reach the bank when the user accepts them and never go on this run's resume.
+func (p *pipelineRun) suggestBullets(ctx context.Context) error {
+	slog.InfoContext(ctx, "Step 3a/12: Suggesting bullets for uncovered requirements...")
+	if err := startStep(ctx, p.database, p.runID, db.StepBulletSuggestions); err != nil {
+		slog.WarnContext(ctx, "Failed to start step tracking", "error", err)
+	}
+
+	report := suggestions.Suggest(p.jobProfile, p.experienceBank)
+	if p.database != nil && p.runID != uuid.Nil {
+		if p.opts.UserID != nil && len(report.Suggestions) > 0 {
+			inputs := make([]db.BulletSuggestionInput, 0, len(report.Suggestions))
+			for _, s := range report.Suggestions {
+				inputs = append(inputs, db.BulletSuggestionInput{
+					Skill:    s.Skill,
+					Source:   s.Source,
+					Level:    s.Level,
+					Evidence: s.Evidence,
+					Question: s.Question,
+					Text:     s.Draft,
+				})
+			}
+			if err := p.database.UpsertBulletSuggestions(ctx, *p.opts.UserID, p.runID, inputs); err != nil {
+				slog.WarnContext(ctx, "Failed to queue bullet suggestions", "error", err)
+				_ = failStep(ctx, p.database, p.runID, db.StepBulletSuggestions, err)
+				return nil
+			}
+		}
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepBulletSuggestions, db.CategoryExperience, report)
+		_ = completeStep(ctx, p.database, p.runID, db.StepBulletSuggestions, nil)
+	}
+	emitProgress(p.opts, db.StepBulletSuggestions, db.CategoryExperience, "Bullet suggestions: "+report.Summary(), report)
+	return nil
+}
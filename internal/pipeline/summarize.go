@@ -0,0 +1,44 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/parsing"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// SummarizePosting ingests and parses a job posting into a job profile without creating
+// a run, for triaging postings before tailoring a resume to one. Nothing is stored.
+// Education requirements are included when they can be extracted.
+func SummarizePosting(ctx context.Context, opts RunOptions) (*types.JobProfile, error) {
+	if opts.Demo != nil {
+		var err error
+		if ctx, err = withDemo(ctx, &opts); err != nil {
+			return nil, fmt.Errorf("invalid demo data: %w", err)
+		}
+	}
+
+	cleanedText, jobMetadata, err := ingestJob(logging.WithStep(ctx, "ingest_job"), &opts)
+	if err != nil {
+		return nil, err
+	}
+	jobProfile, err := parsing.ParseJobProfile(withStepModel(logging.WithStep(ctx, "parse_job"), &opts, "parse_job"), cleanedText, opts.APIKey)
+	if err != nil {
+		return nil, fmt.Errorf("job parsing failed: %w", err)
+	}
+	// A title read from the job board's API beats one the LLM picked out of the text
+	if jobMetadata != nil && jobMetadata.RoleTitle != "" {
+		jobProfile.RoleTitle = jobMetadata.RoleTitle
+	}
+
+	eduCtx := withStepModel(logging.WithStep(ctx, "extract_education"), &opts, "extract_education")
+	if eduReq, err := parsing.ExtractEducationRequirements(eduCtx, cleanedText, opts.APIKey); err != nil {
+		slog.WarnContext(ctx, "Failed to extract education requirements", "error", err)
+	} else {
+		jobProfile.EducationRequirements = eduReq
+	}
+	return jobProfile, nil
+}
@@ -0,0 +1,96 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/skills"
+)
+
+// techStackSize is how many technologies bias ranking and appear in the run summary
+const techStackSize = 10
+
+// techStackCompany returns the named company's record, or nil without a database or
+// when the company is unknown. It never creates one; research records the company.
+func (p *pipelineRun) techStackCompany(ctx context.Context, name string) *db.Company {
+	if p.database == nil || name == "" {
+		return nil
+	}
+	company, err := p.database.GetCompanyByNormalizedName(ctx, db.NormalizeName(name))
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to look up company for tech stack", "error", err)
+		return nil
+	}
+	return company
+}
+
+// recordTechStack stores technologies detected in text under the given source
+func (p *pipelineRun) recordTechStack(ctx context.Context, company *db.Company, source, text string) []skills.Technology {
+	detected := skills.DetectTechStack(text)
+	if company == nil || len(detected) == 0 {
+		return detected
+	}
+	mentions := make([]db.TechMentionInput, 0, len(detected))
+	for _, tech := range detected {
+		mentions = append(mentions, db.TechMentionInput{Technology: tech.Name, Category: tech.Category, Mentions: tech.Mentions})
+	}
+	if err := p.database.RecordCompanyTechStack(ctx, company.ID, source, mentions); err != nil {
+		slog.WarnContext(ctx, "Failed to record tech stack", "error", err)
+	}
+	return detected
+}
+
+// storedTechStack returns the company's stack across postings and blog pages, or fallback
+// when it cannot be read
+func (p *pipelineRun) storedTechStack(ctx context.Context, company *db.Company, fallback []skills.Technology) []string {
+	if company != nil {
+		stack, err := p.database.ListCompanyTechStack(ctx, company.ID, techStackSize)
+		if err == nil && len(stack) > 0 {
+			names := make([]string, 0, len(stack))
+			for _, tech := range stack {
+				names = append(names, tech.Technology)
+			}
+			return names
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to load tech stack", "error", err)
+		}
+	}
+	names := skills.TechNames(fallback)
+	if len(names) > techStackSize {
+		names = names[:techStackSize]
+	}
+	return names
+}
+
+// companyTechStack records the technologies in the job posting and returns the company's
+// tech stack, including those seen on its blog by this run's research and earlier runs,
+// most-mentioned first
+func (p *pipelineRun) companyTechStack(ctx context.Context) []string {
+	company := p.techStackCompany(ctx, p.jobProfile.Company)
+	detected := p.recordTechStack(ctx, company, db.TechSourceJobPosting, p.cleanedText)
+	return p.storedTechStack(ctx, company, detected)
+}
+
+// summarizeTechStack reports the company's tech stack once research has recorded its blog mentions
+func (p *pipelineRun) summarizeTechStack(ctx context.Context) {
+	company := p.techStackCompany(ctx, p.jobProfile.Company)
+	var corpus string
+	if p.companyCorpus != nil {
+		corpus = p.companyCorpus.Corpus
+	}
+	stack := p.storedTechStack(ctx, company, skills.DetectTechStack(p.cleanedText, corpus))
+	if len(stack) == 0 {
+		return
+	}
+
+	summary := skills.FormatTechStack(stack, 5)
+	slog.InfoContext(ctx, "Tech stack: "+summary)
+	if p.database != nil && p.runID != uuid.Nil {
+		_ = p.database.SaveArtifact(ctx, p.runID, db.StepTechStack, db.CategoryResearch, stack)
+	}
+	emitProgress(p.opts, db.StepTechStack, db.CategoryResearch, "Company tech stack: "+summary, stack)
+}
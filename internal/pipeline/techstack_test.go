@@ -0,0 +1,49 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestCompanyTechStack_NoDatabase(t *testing.T) {
+	p := &pipelineRun{
+		opts:        &RunOptions{},
+		jobProfile:  &types.JobProfile{Company: "Acme"},
+		cleanedText: "You will build Go services on Kubernetes and Kafka. Golang experience required.",
+	}
+	assert.Equal(t, []string{"Go", "Kafka", "Kubernetes"}, p.companyTechStack(context.Background()))
+}
+
+func TestSummarizeTechStack_IncludesResearchCorpus(t *testing.T) {
+	var events []ProgressEvent
+	p := &pipelineRun{
+		opts:          &RunOptions{OnProgress: func(e ProgressEvent) { events = append(events, e) }},
+		jobProfile:    &types.JobProfile{Company: "Acme"},
+		cleanedText:   "Experience with Go required.",
+		companyCorpus: &types.CompanyCorpus{Corpus: "Our blog: scaling Kafka and more Kafka."},
+	}
+
+	p.summarizeTechStack(context.Background())
+
+	require.Len(t, events, 1)
+	assert.Equal(t, db.StepTechStack, events[0].Step)
+	assert.Equal(t, "Company tech stack: they use Kafka, Go", events[0].Message)
+	assert.Equal(t, []string{"Kafka", "Go"}, events[0].Content)
+}
+
+func TestSummarizeTechStack_NothingDetected(t *testing.T) {
+	var events []ProgressEvent
+	p := &pipelineRun{
+		opts:        &RunOptions{OnProgress: func(e ProgressEvent) { events = append(events, e) }},
+		jobProfile:  &types.JobProfile{Company: "Acme"},
+		cleanedText: "We value kindness.",
+	}
+	p.summarizeTechStack(context.Background())
+	assert.Empty(t, events)
+}
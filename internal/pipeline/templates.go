@@ -0,0 +1,27 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/templates"
+)
+
+// resolveTemplate replaces a registry template name in opts with the path of its local
+// copy. Template file paths are left as they are.
+func resolveTemplate(ctx context.Context, database *db.DB, opts *RunOptions) error {
+	if !templates.IsName(opts.TemplatePath) {
+		return nil
+	}
+	var store templates.Store
+	if database != nil {
+		store = database
+	}
+	resolved, err := templates.Resolve(ctx, store, opts.UserID, opts.TemplatePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve template: %w", err)
+	}
+	opts.TemplatePath = resolved.Path
+	return nil
+}
@@ -0,0 +1,84 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/compile"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/toolchain"
+	"github.com/jonathan/resume-customizer/internal/validation"
+)
+
+// saveResumePDF compiles the final resume, stores the PDF as the resume_pdf artifact,
+// and renders its thumbnail. A missing compiler only skips both.
+func saveResumePDF(ctx context.Context, database *db.DB, runID uuid.UUID, opts *RunOptions, latex string) {
+	result, err := compile.PDF(ctx, latex)
+	if result == nil {
+		if errors.Is(err, toolchain.ErrUnavailable) {
+			slog.InfoContext(ctx, "Skipping resume PDF", "error", err)
+		} else {
+			slog.WarnContext(ctx, "Failed to compile resume PDF", "error", err)
+		}
+		return
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Resume PDF compiled with errors", "error", err)
+	}
+	if database != nil && runID != uuid.Nil {
+		if err := database.SaveBinaryArtifact(ctx, runID, db.StepResumePDF, db.CategoryValidation, result.PDF); err != nil {
+			slog.WarnContext(ctx, "Failed to save resume PDF", "error", err)
+		}
+	}
+	emitProgress(opts, db.StepResumePDF, db.CategoryValidation,
+		fmt.Sprintf("Compiled resume PDF with %s (%d KB)", result.Engine, (len(result.PDF)+1023)/1024), nil)
+
+	saveThumbnail(ctx, database, runID, opts, result.PDF)
+}
+
+// saveResumeDOCX renders the final plan and bullets as a Word document and stores it as
+// the resume_docx artifact
+func (p *pipelineRun) saveResumeDOCX(ctx context.Context) {
+	docx, err := rendering.RenderDOCX(p.resumePlan, p.rewrittenBullets, p.opts.Style,
+		p.opts.CandidateName, p.opts.CandidateEmail, p.opts.CandidatePhone, p.experienceBank, p.selectedEducation)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to render resume DOCX", "error", err)
+		return
+	}
+	if p.database != nil && p.runID != uuid.Nil {
+		if err := p.database.SaveBinaryArtifact(ctx, p.runID, db.StepResumeDOCX, db.CategoryValidation, docx); err != nil {
+			slog.WarnContext(ctx, "Failed to save resume DOCX", "error", err)
+			return
+		}
+	}
+	emitProgress(p.opts, db.StepResumeDOCX, db.CategoryValidation,
+		fmt.Sprintf("Rendered resume DOCX (%d KB)", (len(docx)+1023)/1024), nil)
+}
+
+// saveThumbnail stores a PNG of the resume's first page, base64-encoded, for list
+// views and share cards. A missing renderer only skips it.
+func saveThumbnail(ctx context.Context, database *db.DB, runID uuid.UUID, opts *RunOptions, pdf []byte) {
+	png, err := validation.ThumbnailFromPDF(pdf, validation.ThumbnailWidth)
+	if err != nil {
+		if errors.Is(err, toolchain.ErrUnavailable) {
+			slog.InfoContext(ctx, "Skipping resume thumbnail", "error", err)
+		} else {
+			slog.WarnContext(ctx, "Failed to render resume thumbnail", "error", err)
+		}
+		return
+	}
+	if database != nil && runID != uuid.Nil {
+		encoded := base64.StdEncoding.EncodeToString(png)
+		if err := database.SaveTextArtifact(ctx, runID, db.StepResumeThumbnail, db.CategoryValidation, encoded); err != nil {
+			slog.WarnContext(ctx, "Failed to save resume thumbnail", "error", err)
+			return
+		}
+	}
+	emitProgress(opts, db.StepResumeThumbnail, db.CategoryValidation, "Rendered resume thumbnail", nil)
+}
@@ -0,0 +1,458 @@
+// Package prompts - rollout.go rolls out new prompt versions with an automatic rollback.
+//
+// A prompt file may hold newer versions of a prompt under "<key>@v<N>" keys, e.g.
+// "extract-job-profile@v2"; the plain key is version 1. Get always returns version 1.
+// Callers that report how each response went use Select instead, which returns the
+// version currently serving the prompt.
+//
+// StartRollout puts a new version ("green") in service next to the current one
+// ("blue"), splitting calls between them. Once both have a full sample window of
+// reported outcomes, their failure rates (JSON parse failures plus violations) are
+// compared: if green's is higher by more than the tolerance, the prompt reverts to blue
+// and the rollback handlers are called to raise an alert; otherwise green takes all
+// calls.
+//
+// With a RolloutStore, rollouts and their outcomes are kept in the database, so every
+// server counts toward the same windows and a restart resumes a trial rather than
+// starting it over or offering a rolled back version again. Only prompts listed in
+// reportingPrompts, whose callers report every outcome, can be rolled out.
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// Outcome is how a response to a prompt turned out
+type Outcome int
+
+const (
+	// OutcomeOK is a response that parsed and passed validation
+	OutcomeOK Outcome = iota
+	// OutcomeParseFailure is a response that was not valid JSON
+	OutcomeParseFailure
+	// OutcomeViolation is a response that parsed but broke the prompt's rules
+	OutcomeViolation
+)
+
+// storeValue is how the outcome is recorded in a RolloutStore
+func (o Outcome) storeValue() string {
+	switch o {
+	case OutcomeParseFailure:
+		return db.PromptOutcomeParseFailure
+	case OutcomeViolation:
+		return db.PromptOutcomeViolation
+	}
+	return db.PromptOutcomeOK
+}
+
+// Rollout states
+const (
+	RolloutTrialing   = db.PromptRolloutTrialing
+	RolloutPromoted   = db.PromptRolloutPromoted
+	RolloutRolledBack = db.PromptRolloutRolledBack
+)
+
+// RolloutStore keeps rollouts and their outcomes; *db.DB implements it
+type RolloutStore interface {
+	StartPromptRollout(ctx context.Context, input *db.PromptRolloutInput) (*db.PromptRollout, error)
+	GetPromptRollout(ctx context.Context, file, key string, version int) (*db.PromptRollout, error)
+	GetPromotedPromptVersion(ctx context.Context, file, key string) (int, error)
+	RecordPromptRolloutOutcome(ctx context.Context, file, key string, version, servedVersion int, outcome string) (*db.PromptRollout, error)
+	DecidePromptRollout(ctx context.Context, file, key string, version int, state string) (bool, error)
+}
+
+// reportingPrompts are the prompts, as "<file>/<key>", whose callers use Select and
+// Report every outcome. A rollout of any other prompt would never be decided.
+var reportingPrompts = map[string]bool{
+	"parsing.json/extract-job-profile": true,
+}
+
+// RolloutConfig controls how a new prompt version is trialed
+type RolloutConfig struct {
+	Share     float64 // Fraction of calls served by the new version while trialing, between 0 and 1
+	Window    int     // Reported outcomes needed from each version before they are compared
+	Tolerance float64 // How much higher the new version's failure rate may be before it is rolled back
+}
+
+// VersionStats counts the outcomes reported for one prompt version
+type VersionStats struct {
+	Version       int `json:"version"`
+	Samples       int `json:"samples"`
+	ParseFailures int `json:"parse_failures"`
+	Violations    int `json:"violations"`
+}
+
+// FailureRate is the share of samples that failed to parse or broke the prompt's rules
+func (v VersionStats) FailureRate() float64 {
+	if v.Samples == 0 {
+		return 0
+	}
+	return float64(v.ParseFailures+v.Violations) / float64(v.Samples)
+}
+
+// RolloutStatus describes a rollout and, once decided, its outcome
+type RolloutStatus struct {
+	File     string       `json:"file"`
+	Key      string       `json:"key"`
+	State    string       `json:"state"`
+	Previous VersionStats `json:"previous"`
+	Next     VersionStats `json:"next"`
+}
+
+// Selection is the prompt version chosen for one call
+type Selection struct {
+	Text    string
+	Version int
+	rollout *rollout
+}
+
+// Report records how the response to the selected prompt turned out. It is a no-op
+// for prompts with no rollout in progress.
+func (s Selection) Report(ctx context.Context, outcome Outcome) {
+	if s.rollout != nil {
+		s.rollout.report(ctx, s.Version, outcome)
+	}
+}
+
+// rollout is the state of one prompt's trial
+type rollout struct {
+	mu       sync.Mutex
+	store    RolloutStore // nil keeps the rollout in memory
+	file     string
+	key      string
+	cfg      RolloutConfig
+	state    string
+	previous VersionStats
+	next     VersionStats
+	served   [2]int // Calls handed to the previous and the next version while trialing
+}
+
+var (
+	rollouts   = make(map[string]*rollout)
+	onRollback []func(RolloutStatus)
+	rolloutsMu sync.RWMutex
+)
+
+// versionKey returns the key a prompt version is stored under
+func versionKey(key string, version int) string {
+	if version <= 1 {
+		return key
+	}
+	return key + "@v" + strconv.Itoa(version)
+}
+
+// ParseVersionedKey splits "<key>@v<N>" into the key and version. A key without a
+// version suffix is version 1.
+func ParseVersionedKey(s string) (string, int, error) {
+	key, suffix, found := strings.Cut(s, "@v")
+	if !found {
+		return s, 1, nil
+	}
+	version, err := strconv.Atoi(suffix)
+	if err != nil || version < 1 || key == "" {
+		return "", 0, fmt.Errorf("invalid prompt version %q", s)
+	}
+	return key, version, nil
+}
+
+// StartRollout trials version of a prompt against the version currently serving it.
+// The version must exist in the prompt file, and the prompt must report its outcomes.
+// With a store, a rollout of the same version that was already started is resumed,
+// keeping its outcomes and, if it was decided, its decision; store may be nil to keep
+// the rollout in memory only.
+func StartRollout(ctx context.Context, store RolloutStore, filename, key string, version int, cfg RolloutConfig) error {
+	if !reportingPrompts[filename+"/"+key] {
+		return fmt.Errorf("prompt %s in %s does not report its outcomes, so it cannot be rolled out", key, filename)
+	}
+	if cfg.Share <= 0 || cfg.Share >= 1 {
+		return fmt.Errorf("rollout share must be between 0 and 1, got: %g", cfg.Share)
+	}
+	if cfg.Window < 1 {
+		return fmt.Errorf("rollout window must be at least 1, got: %d", cfg.Window)
+	}
+	if _, err := Get(filename, versionKey(key, version)); err != nil {
+		return err
+	}
+
+	rolloutsMu.Lock()
+	defer rolloutsMu.Unlock()
+	r := &rollout{
+		store:    store,
+		file:     filename,
+		key:      key,
+		cfg:      cfg,
+		state:    RolloutTrialing,
+		previous: VersionStats{Version: 1},
+		next:     VersionStats{Version: version},
+	}
+	if current, ok := rollouts[filename+"/"+key]; ok {
+		r.previous.Version = current.serving()
+	}
+
+	if store != nil {
+		existing, err := store.GetPromptRollout(ctx, filename, key, version)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			if r.previous.Version, err = store.GetPromotedPromptVersion(ctx, filename, key); err != nil {
+				return err
+			}
+		}
+		if existing == nil && version == r.previous.Version {
+			return fmt.Errorf("prompt %s in %s is already at version %d", key, filename, version)
+		}
+		stored, err := store.StartPromptRollout(ctx, &db.PromptRolloutInput{
+			File:            filename,
+			PromptKey:       key,
+			Version:         version,
+			PreviousVersion: r.previous.Version,
+			Share:           cfg.Share,
+			WindowSize:      cfg.Window,
+			Tolerance:       cfg.Tolerance,
+		})
+		if err != nil {
+			return err
+		}
+		r.sync(stored)
+	} else if version == r.previous.Version {
+		return fmt.Errorf("prompt %s in %s is already at version %d", key, filename, version)
+	}
+
+	rollouts[filename+"/"+key] = r
+	slog.Info("Prompt rollout started", "file", filename, "key", key, "from", r.previous.Version, "to", version,
+		"state", r.state, "share", cfg.Share, "window", cfg.Window)
+	return nil
+}
+
+// OnRollback registers fn to be called when a rollout is rolled back
+func OnRollback(fn func(RolloutStatus)) {
+	rolloutsMu.Lock()
+	defer rolloutsMu.Unlock()
+	onRollback = append(onRollback, fn)
+}
+
+// Rollouts returns the status of every rollout, refreshed from the store so outcomes
+// other servers reported are included
+func Rollouts(ctx context.Context) []RolloutStatus {
+	rolloutsMu.RLock()
+	list := make([]*rollout, 0, len(rollouts))
+	for _, r := range rollouts {
+		list = append(list, r)
+	}
+	rolloutsMu.RUnlock()
+
+	statuses := make([]RolloutStatus, 0, len(list))
+	for _, r := range list {
+		if r.store != nil {
+			stored, err := r.store.GetPromptRollout(ctx, r.file, r.key, r.next.Version)
+			if err != nil {
+				slog.WarnContext(ctx, "Failed to refresh prompt rollout", "file", r.file, "key", r.key, "error", err)
+			} else if stored != nil {
+				r.mu.Lock()
+				r.sync(stored)
+				r.mu.Unlock()
+			}
+		}
+		r.mu.Lock()
+		statuses = append(statuses, r.status())
+		r.mu.Unlock()
+	}
+	return statuses
+}
+
+// ResetRollouts removes every rollout and rollback handler. Useful for testing.
+func ResetRollouts() {
+	rolloutsMu.Lock()
+	rollouts = make(map[string]*rollout)
+	onRollback = nil
+	rolloutsMu.Unlock()
+}
+
+// Select returns the version of a prompt that should serve the next call
+func Select(filename, key string) (Selection, error) {
+	rolloutsMu.RLock()
+	r := rollouts[filename+"/"+key]
+	rolloutsMu.RUnlock()
+
+	version := 1
+	if r != nil {
+		version = r.pick()
+	}
+	text, err := Get(filename, versionKey(key, version))
+	if err != nil {
+		return Selection{}, err
+	}
+	return Selection{Text: text, Version: version, rollout: r}, nil
+}
+
+// MustSelect is Select, panicking if the prompt is not found
+func MustSelect(filename, key string) Selection {
+	sel, err := Select(filename, key)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load prompt: %v", err))
+	}
+	return sel
+}
+
+// serving returns the version serving every call once the rollout is decided
+func (r *rollout) serving() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state == RolloutPromoted {
+		return r.next.Version
+	}
+	return r.previous.Version
+}
+
+// pick chooses a version for a call. While trialing, calls are spread so the new
+// version serves cfg.Share of them.
+func (r *rollout) pick() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch r.state {
+	case RolloutPromoted:
+		return r.next.Version
+	case RolloutRolledBack:
+		return r.previous.Version
+	}
+	total := r.served[0] + r.served[1] + 1
+	if float64(r.served[1]) < r.cfg.Share*float64(total) {
+		r.served[1]++
+		return r.next.Version
+	}
+	r.served[0]++
+	return r.previous.Version
+}
+
+// report records an outcome and decides the rollout once both versions have a full window
+func (r *rollout) report(ctx context.Context, version int, outcome Outcome) {
+	if r.store != nil {
+		r.reportToStore(ctx, version, outcome)
+		return
+	}
+
+	r.mu.Lock()
+	if r.state != RolloutTrialing {
+		r.mu.Unlock()
+		return
+	}
+	stats := &r.previous
+	if version == r.next.Version {
+		stats = &r.next
+	}
+	if stats.Samples >= r.cfg.Window {
+		r.mu.Unlock()
+		return
+	}
+	stats.Samples++
+	switch outcome {
+	case OutcomeParseFailure:
+		stats.ParseFailures++
+	case OutcomeViolation:
+		stats.Violations++
+	}
+	if !r.full() {
+		r.mu.Unlock()
+		return
+	}
+	r.state = r.decision()
+	status := r.status()
+	r.mu.Unlock()
+	r.announce(status)
+}
+
+// reportToStore records an outcome in the store, which every server shares, and
+// decides the rollout once both versions have a full window. Only the server whose
+// decision is recorded announces it.
+func (r *rollout) reportToStore(ctx context.Context, version int, outcome Outcome) {
+	stored, err := r.store.RecordPromptRolloutOutcome(ctx, r.file, r.key, r.next.Version, version, outcome.storeValue())
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to record prompt rollout outcome", "file", r.file, "key", r.key, "error", err)
+		return
+	}
+	r.mu.Lock()
+	r.sync(stored)
+	if r.state != RolloutTrialing || !r.full() {
+		r.mu.Unlock()
+		return
+	}
+	state := r.decision()
+	r.mu.Unlock()
+
+	decided, err := r.store.DecidePromptRollout(ctx, r.file, r.key, r.next.Version, state)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to record prompt rollout decision", "file", r.file, "key", r.key, "error", err)
+		return
+	}
+	if !decided {
+		return
+	}
+	r.mu.Lock()
+	r.state = state
+	status := r.status()
+	r.mu.Unlock()
+	r.announce(status)
+}
+
+// full reports whether both versions have a full window of outcomes; r.mu must be held
+func (r *rollout) full() bool {
+	return r.previous.Samples >= r.cfg.Window && r.next.Samples >= r.cfg.Window
+}
+
+// decision is the state a rollout with full windows moves to; r.mu must be held
+func (r *rollout) decision() string {
+	if r.next.FailureRate() > r.previous.FailureRate()+r.cfg.Tolerance {
+		return RolloutRolledBack
+	}
+	return RolloutPromoted
+}
+
+// sync copies a stored rollout's state and outcomes; r.mu must be held
+func (r *rollout) sync(stored *db.PromptRollout) {
+	r.state = stored.State
+	r.cfg = RolloutConfig{Share: stored.Share, Window: stored.WindowSize, Tolerance: stored.Tolerance}
+	r.previous = VersionStats{
+		Version:       stored.PreviousVersion,
+		Samples:       stored.PreviousSamples,
+		ParseFailures: stored.PreviousParseFailures,
+		Violations:    stored.PreviousViolations,
+	}
+	r.next = VersionStats{
+		Version:       stored.Version,
+		Samples:       stored.NextSamples,
+		ParseFailures: stored.NextParseFailures,
+		Violations:    stored.NextViolations,
+	}
+}
+
+// announce logs a decided rollout and, if it was rolled back, calls the rollback handlers
+func (r *rollout) announce(status RolloutStatus) {
+	if status.State == RolloutPromoted {
+		slog.Info("Prompt rollout promoted", "file", r.file, "key", r.key, "version", status.Next.Version,
+			"failure_rate", status.Next.FailureRate(), "previous_failure_rate", status.Previous.FailureRate())
+		return
+	}
+	slog.Error("ALERT: prompt rollout rolled back", "file", r.file, "key", r.key,
+		"from", status.Next.Version, "to", status.Previous.Version,
+		"failure_rate", status.Next.FailureRate(), "previous_failure_rate", status.Previous.FailureRate(),
+		"window", r.cfg.Window)
+	rolloutsMu.RLock()
+	handlers := onRollback
+	rolloutsMu.RUnlock()
+	for _, fn := range handlers {
+		fn(status)
+	}
+}
+
+// status returns the rollout's status; r.mu must be held
+func (r *rollout) status() RolloutStatus {
+	return RolloutStatus{File: r.file, Key: r.key, State: r.state, Previous: r.previous, Next: r.next}
+}
@@ -0,0 +1,278 @@
+package prompts
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withVersionedPrompts caches a prompt file holding two versions of "greet", whose
+// outcomes the tests report
+func withVersionedPrompts(t *testing.T) {
+	t.Helper()
+	ClearCache()
+	ResetRollouts()
+	cacheMu.Lock()
+	cache["rollout.json"] = map[string]string{"greet": "Hello v1", "greet@v2": "Hello v2"}
+	cacheMu.Unlock()
+	reportingPrompts["rollout.json/greet"] = true
+	t.Cleanup(func() {
+		ClearCache()
+		ResetRollouts()
+		delete(reportingPrompts, "rollout.json/greet")
+	})
+}
+
+// memoryStore is a RolloutStore that keeps rollouts in a map, as the database would
+type memoryStore struct {
+	mu       sync.Mutex
+	rollouts map[string]*db.PromptRollout
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{rollouts: make(map[string]*db.PromptRollout)}
+}
+
+func (m *memoryStore) id(file, key string, version int) string {
+	return file + "/" + versionKey(key, version)
+}
+
+func (m *memoryStore) StartPromptRollout(_ context.Context, in *db.PromptRolloutInput) (*db.PromptRollout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.rollouts[m.id(in.File, in.PromptKey, in.Version)]
+	if !ok {
+		r = &db.PromptRollout{File: in.File, PromptKey: in.PromptKey, Version: in.Version,
+			PreviousVersion: in.PreviousVersion, State: db.PromptRolloutTrialing}
+		m.rollouts[m.id(in.File, in.PromptKey, in.Version)] = r
+	}
+	r.Share, r.WindowSize, r.Tolerance = in.Share, in.WindowSize, in.Tolerance
+	copied := *r
+	return &copied, nil
+}
+
+func (m *memoryStore) GetPromptRollout(_ context.Context, file, key string, version int) (*db.PromptRollout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.rollouts[m.id(file, key, version)]
+	if !ok {
+		return nil, nil
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (m *memoryStore) GetPromotedPromptVersion(_ context.Context, file, key string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	promoted := 1
+	for _, r := range m.rollouts {
+		if r.File == file && r.PromptKey == key && r.State == db.PromptRolloutPromoted {
+			promoted = max(promoted, r.Version)
+		}
+	}
+	return promoted, nil
+}
+
+func (m *memoryStore) RecordPromptRolloutOutcome(_ context.Context, file, key string, version, servedVersion int, outcome string) (*db.PromptRollout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := m.rollouts[m.id(file, key, version)]
+	samples, parseFailures, violations := &r.PreviousSamples, &r.PreviousParseFailures, &r.PreviousViolations
+	if servedVersion == r.Version {
+		samples, parseFailures, violations = &r.NextSamples, &r.NextParseFailures, &r.NextViolations
+	}
+	if r.State == db.PromptRolloutTrialing && *samples < r.WindowSize {
+		*samples++
+		switch outcome {
+		case db.PromptOutcomeParseFailure:
+			*parseFailures++
+		case db.PromptOutcomeViolation:
+			*violations++
+		}
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (m *memoryStore) DecidePromptRollout(_ context.Context, file, key string, version int, state string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := m.rollouts[m.id(file, key, version)]
+	if r.State != db.PromptRolloutTrialing || r.PreviousSamples < r.WindowSize || r.NextSamples < r.WindowSize {
+		return false, nil
+	}
+	r.State = state
+	return true, nil
+}
+
+// serve selects the prompt n times, reporting outcome for each version
+func serve(t *testing.T, n int, outcome map[int]Outcome) map[int]int {
+	t.Helper()
+	served := make(map[int]int)
+	for range n {
+		sel, err := Select("rollout.json", "greet")
+		require.NoError(t, err)
+		assert.Equal(t, sel.Version == 2, sel.Text == "Hello v2")
+		served[sel.Version]++
+		sel.Report(context.Background(), outcome[sel.Version])
+	}
+	return served
+}
+
+func TestParseVersionedKey(t *testing.T) {
+	key, version, err := ParseVersionedKey("extract-job-profile@v3")
+	require.NoError(t, err)
+	assert.Equal(t, "extract-job-profile", key)
+	assert.Equal(t, 3, version)
+
+	key, version, err = ParseVersionedKey("extract-job-profile")
+	require.NoError(t, err)
+	assert.Equal(t, "extract-job-profile", key)
+	assert.Equal(t, 1, version)
+
+	for _, bad := range []string{"greet@v", "greet@v0", "greet@vx", "@v2"} {
+		_, _, err := ParseVersionedKey(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestSelect_NoRollout(t *testing.T) {
+	withVersionedPrompts(t)
+
+	sel, err := Select("rollout.json", "greet")
+	require.NoError(t, err)
+	assert.Equal(t, 1, sel.Version)
+	assert.Equal(t, "Hello v1", sel.Text)
+	sel.Report(context.Background(), OutcomeParseFailure) // No rollout to report to
+}
+
+func TestStartRollout_Invalid(t *testing.T) {
+	withVersionedPrompts(t)
+	cfg := RolloutConfig{Share: 0.5, Window: 10}
+
+	assert.Error(t, StartRollout(context.Background(), nil, "rollout.json", "greet", 3, cfg), "missing version")
+	assert.Error(t, StartRollout(context.Background(), nil, "rollout.json", "greet", 1, cfg), "already serving")
+	assert.Error(t, StartRollout(context.Background(), nil, "rollout.json", "greet", 2, RolloutConfig{Share: 1, Window: 10}))
+	assert.Error(t, StartRollout(context.Background(), nil, "rollout.json", "greet", 2, RolloutConfig{Share: 0.5}))
+
+	err := StartRollout(context.Background(), nil, "parsing.json", "extract-skills", 2, cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not report its outcomes")
+}
+
+func TestRollout_Promotes(t *testing.T) {
+	withVersionedPrompts(t)
+	require.NoError(t, StartRollout(context.Background(), nil, "rollout.json", "greet", 2, RolloutConfig{Share: 0.5, Window: 4, Tolerance: 0.1}))
+	OnRollback(func(RolloutStatus) { t.Error("rollout should not be rolled back") })
+
+	// Calls are split evenly while trialing
+	served := serve(t, 8, map[int]Outcome{1: OutcomeOK, 2: OutcomeOK})
+	assert.Equal(t, map[int]int{1: 4, 2: 4}, served)
+
+	status := Rollouts(context.Background())
+	require.Len(t, status, 1)
+	assert.Equal(t, RolloutPromoted, status[0].State)
+	assert.Equal(t, map[int]int{2: 5}, serve(t, 5, nil))
+}
+
+func TestRollout_RollsBackAndAlerts(t *testing.T) {
+	withVersionedPrompts(t)
+	require.NoError(t, StartRollout(context.Background(), nil, "rollout.json", "greet", 2, RolloutConfig{Share: 0.25, Window: 4, Tolerance: 0.1}))
+	var alerts []RolloutStatus
+	OnRollback(func(s RolloutStatus) { alerts = append(alerts, s) })
+
+	// Version 2 fails to parse on every call and serves one call in four
+	for i := 0; len(alerts) == 0 && i < 100; i++ {
+		sel, err := Select("rollout.json", "greet")
+		require.NoError(t, err)
+		if sel.Version == 2 {
+			sel.Report(context.Background(), OutcomeParseFailure)
+		} else {
+			sel.Report(context.Background(), OutcomeOK)
+		}
+	}
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, RolloutRolledBack, alerts[0].State)
+	assert.Equal(t, 2, alerts[0].Next.Version)
+	assert.Equal(t, 4, alerts[0].Next.ParseFailures)
+	assert.InDelta(t, 1.0, alerts[0].Next.FailureRate(), 0.001)
+	assert.InDelta(t, 0.0, alerts[0].Previous.FailureRate(), 0.001)
+
+	// Every call is back on the previous version
+	assert.Equal(t, map[int]int{1: 5}, serve(t, 5, nil))
+}
+
+func TestRollout_ViolationsCount(t *testing.T) {
+	withVersionedPrompts(t)
+	require.NoError(t, StartRollout(context.Background(), nil, "rollout.json", "greet", 2, RolloutConfig{Share: 0.5, Window: 2, Tolerance: 0.1}))
+
+	serve(t, 4, map[int]Outcome{1: OutcomeOK, 2: OutcomeViolation})
+
+	status := Rollouts(context.Background())
+	require.Len(t, status, 1)
+	assert.Equal(t, RolloutRolledBack, status[0].State)
+	assert.Equal(t, 2, status[0].Next.Violations)
+}
+
+func TestRollout_StoreSurvivesRestart(t *testing.T) {
+	withVersionedPrompts(t)
+	ctx := context.Background()
+	store := newMemoryStore()
+	cfg := RolloutConfig{Share: 0.5, Window: 4, Tolerance: 0.1}
+	require.NoError(t, StartRollout(ctx, store, "rollout.json", "greet", 2, cfg))
+
+	// Half the window is filled before the server restarts
+	serve(t, 4, map[int]Outcome{1: OutcomeOK, 2: OutcomeParseFailure})
+	ResetRollouts()
+	require.NoError(t, StartRollout(ctx, store, "rollout.json", "greet", 2, cfg))
+
+	status := Rollouts(ctx)
+	require.Len(t, status, 1)
+	assert.Equal(t, RolloutTrialing, status[0].State)
+	assert.Equal(t, 2, status[0].Next.Samples)
+
+	var alerts []RolloutStatus
+	OnRollback(func(s RolloutStatus) { alerts = append(alerts, s) })
+	serve(t, 4, map[int]Outcome{1: OutcomeOK, 2: OutcomeParseFailure})
+	require.Len(t, alerts, 1)
+	assert.Equal(t, 4, alerts[0].Next.ParseFailures)
+
+	// A restart keeps the rolled back version out of service and doesn't alert again
+	ResetRollouts()
+	require.NoError(t, StartRollout(ctx, store, "rollout.json", "greet", 2, cfg))
+	OnRollback(func(RolloutStatus) { t.Error("rollback should not be announced again") })
+	assert.Equal(t, map[int]int{1: 5}, serve(t, 5, map[int]Outcome{1: OutcomeOK}))
+	assert.Equal(t, RolloutRolledBack, Rollouts(ctx)[0].State)
+}
+
+func TestRollout_StoreSharedByServers(t *testing.T) {
+	withVersionedPrompts(t)
+	ctx := context.Background()
+	store := newMemoryStore()
+	require.NoError(t, StartRollout(ctx, store, "rollout.json", "greet", 2, RolloutConfig{Share: 0.5, Window: 2}))
+
+	// Another server reports through the same store
+	other := &rollout{store: store, file: "rollout.json", key: "greet", next: VersionStats{Version: 2}}
+	other.report(ctx, 1, OutcomeOK)
+	other.report(ctx, 2, OutcomeOK)
+
+	status := Rollouts(ctx)
+	require.Len(t, status, 1)
+	assert.Equal(t, 1, status[0].Previous.Samples)
+	assert.Equal(t, 1, status[0].Next.Samples)
+
+	serve(t, 2, map[int]Outcome{1: OutcomeOK, 2: OutcomeOK})
+	assert.Equal(t, RolloutPromoted, Rollouts(ctx)[0].State)
+
+	// Once promoted, the promoted version is what a newer rollout trials against
+	promoted, err := store.GetPromotedPromptVersion(ctx, "rollout.json", "greet")
+	require.NoError(t, err)
+	assert.Equal(t, 2, promoted)
+}
@@ -0,0 +1,181 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// ISO/IEC 18004 annex I: "01234567" at 1-M
+	data := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	want := []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}
+	assert.Equal(t, want, rsRemainder(data, rsGenerator(10)))
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	c := newCode(7)
+	c.drawFormatBits(0)
+	c.drawVersionBits()
+
+	// Level M, mask 0, read from the top-left copy (bit 14 first)
+	var format strings.Builder
+	for i := 14; i >= 0; i-- {
+		format.WriteString(moduleBit(c, formatPosition(i)))
+	}
+	assert.Equal(t, "101010000010010", format.String())
+
+	// Version 7, read from the bottom-left block (bit 17 first)
+	var version strings.Builder
+	for i := 17; i >= 0; i-- {
+		version.WriteString(moduleBit(c, [2]int{c.Size - 11 + i%3, i / 3}))
+	}
+	assert.Equal(t, "000111110010010100", version.String())
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	for _, content := range []string{
+		"hi",
+		"https://resume.example.com/r/AbCdEfGhIjKlMnOpQrStUv",
+		strings.Repeat("x", 120),
+		strings.Repeat("y", 213),
+	} {
+		c, err := Encode(content)
+		require.NoError(t, err)
+		assert.Equal(t, 17+4*c.Version, c.Size)
+		assert.Equal(t, content, decode(t, c), "version %d", c.Version)
+	}
+}
+
+func TestEncode_TooLong(t *testing.T) {
+	_, err := Encode(strings.Repeat("z", 214))
+	assert.Error(t, err)
+}
+
+func TestPNG(t *testing.T) {
+	data, err := PNG("https://resume.example.com/r/abc", 4)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	c, _ := Encode("https://resume.example.com/r/abc")
+	side := (c.Size + 2*quietZone) * 4
+	assert.Equal(t, side, img.Bounds().Dx())
+	r, _, _, _ := img.At(0, 0).RGBA()
+	assert.NotZero(t, r, "quiet zone is white")
+	r, _, _, _ = img.At(quietZone*4, quietZone*4).RGBA()
+	assert.Zero(t, r, "finder corner is dark")
+}
+
+func moduleBit(c *Code, pos [2]int) string {
+	if c.Dark(pos[0], pos[1]) {
+		return "1"
+	}
+	return "0"
+}
+
+// formatPosition returns the row, col of format bit i in the top-left copy
+func formatPosition(i int) [2]int {
+	switch {
+	case i <= 5:
+		return [2]int{i, 8}
+	case i == 6:
+		return [2]int{7, 8}
+	case i == 7:
+		return [2]int{8, 8}
+	case i == 8:
+		return [2]int{8, 7}
+	default:
+		return [2]int{8, 14 - i}
+	}
+}
+
+// decode reads a symbol back: the mask from the format bits, the codewords in
+// placement order, and the byte-mode payload from the de-interleaved data blocks
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+	format := 0
+	for i := 14; i >= 0; i-- {
+		format <<= 1
+		if moduleBit(c, formatPosition(i)) == "1" {
+			format |= 1
+		}
+	}
+	format ^= 0x5412
+	require.Equal(t, eccLevelM, format>>13, "error correction level")
+	mask := (format >> 10) & 7
+
+	clone := newCode(c.Version)
+	clone.drawFunctionPatterns()
+	for row := range c.modules {
+		copy(clone.modules[row], c.modules[row])
+	}
+	clone.applyMask(mask)
+
+	var bits []bool
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			row := vert
+			if (right+1)&2 == 0 {
+				row = c.Size - 1 - vert
+			}
+			for col := right; col >= right-1; col-- {
+				if !clone.function[row][col] {
+					bits = append(bits, clone.modules[row][col])
+				}
+			}
+		}
+	}
+	codewords := bitBuffer(bits[:len(bits)/8*8]).bytes()
+
+	layout := layoutM[c.Version]
+	blocks := make([][]byte, len(layout.blocks))
+	i := 0
+	for pos := 0; pos < layout.blocks[len(layout.blocks)-1]; pos++ {
+		for b, n := range layout.blocks {
+			if pos < n {
+				blocks[b] = append(blocks[b], codewords[i])
+				i++
+			}
+		}
+	}
+	var data []byte
+	for b, block := range blocks {
+		var ec []byte
+		for j := 0; j < layout.ecPerBlock; j++ {
+			ec = append(ec, codewords[i+j*len(blocks)+b])
+		}
+		require.Equal(t, rsRemainder(block, rsGenerator(layout.ecPerBlock)), ec, "block %d error correction", b)
+		data = append(data, block...)
+	}
+
+	var stream bitBuffer
+	for _, b := range data {
+		stream.append(int(b), 8)
+	}
+	read := func(n int) int {
+		v := 0
+		for _, bit := range stream[:n] {
+			v <<= 1
+			if bit {
+				v |= 1
+			}
+		}
+		stream = stream[n:]
+		return v
+	}
+	require.Equal(t, 0b0100, read(4), "byte mode")
+	length := read(headerBits(c.Version) - 4)
+	out := make([]byte, length)
+	for j := range out {
+		out[j] = byte(read(8))
+	}
+	return string(out)
+}
@@ -49,7 +49,7 @@ func ScoreEducation(
 
 	scores := make([]EducationScore, len(education))
 	hasRules := requirements != nil && (requirements.MinDegree != "" || len(requirements.PreferredFields) > 0)
-	hasAPIKey := apiKey != ""
+	hasAPIKey := apiKey != "" || !llm.APIKeyRequired()
 
 	for i, edu := range education {
 		score := EducationScore{
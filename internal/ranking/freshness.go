@@ -0,0 +1,171 @@
+package ranking
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/parsing"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Relevance removed per rusty skill a story leans on, and the most a story can lose
+const (
+	rustySkillPenalty    = 0.05
+	maxRustySkillPenalty = 0.15
+)
+
+// Thresholds below which a skill counts as rusty
+const (
+	rustyMaxProficiency = 2       // Self-assessed 1 or 2 out of 5
+	rustyAfter          = 3 * 365 // Days since the skill was last used
+)
+
+// minDepthYears is the years of experience from which a requirement demands hands-on depth
+const minDepthYears = 3
+
+var (
+	yearsPattern = regexp.MustCompile(`(\d+)\s*\+?\s*(?:years?|yrs?)`)
+	depthPattern = regexp.MustCompile(`\b(?:expert|advanced|deep|hands-on|strong|proficien\w*|mastery)\b`)
+)
+
+// ApplySkillFreshness demotes stories that lean on skills the job needs in depth but the
+// user is rusty in, so they do not lead the resume, and re-sorts them. A skill is rusty
+// if the user rates it weak or last used it more than three years before now. Each such
+// skill costs the story rustySkillPenalty, up to maxRustySkillPenalty.
+func ApplySkillFreshness(ranked *types.RankedStories, bank *types.ExperienceBank, jobProfile *types.JobProfile, now time.Time) {
+	if ranked == nil || bank == nil || jobProfile == nil {
+		return
+	}
+
+	demanding := make(map[string]bool)
+	for _, req := range jobProfile.HardRequirements {
+		if DemandsDepth(req) {
+			demanding[SkillKey(req.Skill)] = true
+		}
+	}
+	if len(demanding) == 0 {
+		return
+	}
+
+	rusty := rustySkills(bank, now)
+	if len(rusty) == 0 {
+		return
+	}
+
+	storySkills := make(map[string][]string, len(bank.Stories))
+	for _, story := range bank.Stories {
+		for _, bullet := range story.Bullets {
+			storySkills[story.ID] = append(storySkills[story.ID], bullet.Skills...)
+		}
+	}
+
+	for i := range ranked.Ranked {
+		story := &ranked.Ranked[i]
+		var matches []string
+		seen := make(map[string]bool)
+		for _, skill := range storySkills[story.StoryID] {
+			key := SkillKey(skill)
+			if seen[key] || !demanding[key] || !rusty[key] {
+				continue
+			}
+			seen[key] = true
+			matches = append(matches, skill)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		penalty := rustySkillPenalty * float64(len(matches))
+		if penalty > maxRustySkillPenalty {
+			penalty = maxRustySkillPenalty
+		}
+		story.RelevanceScore -= penalty
+		if story.RelevanceScore < 0 {
+			story.RelevanceScore = 0
+		}
+		story.RustySkills = matches
+		story.Notes += fmt.Sprintf(". Rusty in skills needed in depth (%s)", strings.Join(matches, ", "))
+	}
+
+	sort.SliceStable(ranked.Ranked, func(i, j int) bool {
+		return ranked.Ranked[i].RelevanceScore > ranked.Ranked[j].RelevanceScore
+	})
+}
+
+// DemandsDepth reports whether a requirement asks for hands-on depth rather than
+// familiarity: several years of experience, or words like "expert" or "hands-on"
+func DemandsDepth(req types.Requirement) bool {
+	level := strings.ToLower(req.Level)
+	if m := yearsPattern.FindStringSubmatch(level); m != nil {
+		if years, err := strconv.Atoi(m[1]); err == nil && years >= minDepthYears {
+			return true
+		}
+	}
+	return depthPattern.MatchString(level) || depthPattern.MatchString(strings.ToLower(req.Evidence))
+}
+
+// SkillLastUsed derives when each skill in the bank was last used from the stories that
+// mention it, keyed by normalized lowercase skill name. Stories without an end date are
+// ongoing and count as now; stories with unparseable dates are ignored.
+func SkillLastUsed(bank *types.ExperienceBank, now time.Time) map[string]time.Time {
+	lastUsed := make(map[string]time.Time)
+	if bank == nil {
+		return lastUsed
+	}
+	for _, story := range bank.Stories {
+		end := now
+		if story.EndDate != "" && !strings.EqualFold(story.EndDate, "present") {
+			parsed, err := time.Parse("2006-01", story.EndDate)
+			if err != nil {
+				continue
+			}
+			end = parsed
+		}
+		for _, bullet := range story.Bullets {
+			for _, skill := range bullet.Skills {
+				key := SkillKey(skill)
+				if key != "" && end.After(lastUsed[key]) {
+					lastUsed[key] = end
+				}
+			}
+		}
+	}
+	return lastUsed
+}
+
+// rustySkills returns the normalized names of skills the user is rusty in. The
+// self-reported last use overrides the one derived from the stories.
+func rustySkills(bank *types.ExperienceBank, now time.Time) map[string]bool {
+	lastUsed := SkillLastUsed(bank, now)
+	rusty := make(map[string]bool)
+	for _, a := range bank.SkillProfile {
+		key := SkillKey(a.Skill)
+		if key == "" {
+			continue
+		}
+		if a.Proficiency > 0 && a.Proficiency <= rustyMaxProficiency {
+			rusty[key] = true
+		}
+		if a.LastUsed != "" {
+			if parsed, err := time.Parse("2006-01", a.LastUsed); err == nil {
+				lastUsed[key] = parsed
+			}
+		}
+	}
+	cutoff := now.AddDate(0, 0, -rustyAfter)
+	for key, last := range lastUsed {
+		if last.Before(cutoff) {
+			rusty[key] = true
+		}
+	}
+	return rusty
+}
+
+// SkillKey is the normalized lowercase name skills are matched by
+func SkillKey(skill string) string {
+	return strings.ToLower(parsing.NormalizeSkillName(skill))
+}
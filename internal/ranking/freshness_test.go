@@ -0,0 +1,85 @@
+package ranking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDemandsDepth(t *testing.T) {
+	assert.True(t, DemandsDepth(types.Requirement{Skill: "Go", Level: "5+ years"}))
+	assert.True(t, DemandsDepth(types.Requirement{Skill: "Go", Level: "3 yrs"}))
+	assert.True(t, DemandsDepth(types.Requirement{Skill: "Go", Level: "Expert"}))
+	assert.True(t, DemandsDepth(types.Requirement{Skill: "Go", Evidence: "Hands-on experience building services in Go"}))
+	assert.False(t, DemandsDepth(types.Requirement{Skill: "Go", Level: "1+ years"}))
+	assert.False(t, DemandsDepth(types.Requirement{Skill: "Go", Evidence: "Familiarity with Go"}))
+}
+
+func TestSkillLastUsed(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	bank := &types.ExperienceBank{Stories: []types.Story{
+		{ID: "old", EndDate: "2018-06", Bullets: []types.Bullet{{Skills: []string{"Java", "golang"}}}},
+		{ID: "current", Bullets: []types.Bullet{{Skills: []string{"Go"}}}},
+		{ID: "bad", EndDate: "someday", Bullets: []types.Bullet{{Skills: []string{"Rust"}}}},
+	}}
+
+	lastUsed := SkillLastUsed(bank, now)
+	assert.Equal(t, time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC), lastUsed["java"])
+	assert.Equal(t, now, lastUsed["go"])
+	assert.NotContains(t, lastUsed, "rust")
+}
+
+func TestApplySkillFreshness(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	bank := &types.ExperienceBank{
+		Stories: []types.Story{
+			{ID: "java_story", EndDate: "2019-01", Bullets: []types.Bullet{{Skills: []string{"Java"}}}},
+			{ID: "k8s_story", EndDate: "2025-12", Bullets: []types.Bullet{{Skills: []string{"Kubernetes", "Python"}}}},
+			{ID: "go_story", Bullets: []types.Bullet{{Skills: []string{"Go"}}}},
+		},
+		SkillProfile: []types.SkillAssessment{
+			{Skill: "Kubernetes", Proficiency: 2},
+			{Skill: "Python", LastUsed: "2020-03"},
+		},
+	}
+	job := &types.JobProfile{HardRequirements: []types.Requirement{
+		{Skill: "Java", Level: "5+ years"},
+		{Skill: "Kubernetes", Evidence: "Hands-on with Kubernetes in production"},
+		{Skill: "Python", Level: "expert"},
+		{Skill: "Go", Level: "5+ years"},
+	}}
+	ranked := &types.RankedStories{Ranked: []types.RankedStory{
+		{StoryID: "k8s_story", RelevanceScore: 0.78},
+		{StoryID: "java_story", RelevanceScore: 0.75},
+		{StoryID: "go_story", RelevanceScore: 0.72},
+	}}
+
+	ApplySkillFreshness(ranked, bank, job, now)
+
+	require.Len(t, ranked.Ranked, 3)
+	assert.Equal(t, "go_story", ranked.Ranked[0].StoryID)
+	assert.Empty(t, ranked.Ranked[0].RustySkills)
+	assert.Equal(t, "java_story", ranked.Ranked[1].StoryID)
+	assert.InDelta(t, 0.7, ranked.Ranked[1].RelevanceScore, 1e-9)
+	assert.Equal(t, []string{"Java"}, ranked.Ranked[1].RustySkills)
+	assert.Equal(t, "k8s_story", ranked.Ranked[2].StoryID)
+	assert.InDelta(t, 0.68, ranked.Ranked[2].RelevanceScore, 1e-9)
+	assert.Equal(t, []string{"Kubernetes", "Python"}, ranked.Ranked[2].RustySkills)
+	assert.Contains(t, ranked.Ranked[2].Notes, "Rusty in skills needed in depth (Kubernetes, Python)")
+}
+
+func TestApplySkillFreshness_IgnoresFamiliarityRequirements(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	bank := &types.ExperienceBank{Stories: []types.Story{
+		{ID: "java_story", EndDate: "2015-01", Bullets: []types.Bullet{{Skills: []string{"Java"}}}},
+	}}
+	job := &types.JobProfile{HardRequirements: []types.Requirement{{Skill: "Java", Level: "familiarity"}}}
+	ranked := &types.RankedStories{Ranked: []types.RankedStory{{StoryID: "java_story", RelevanceScore: 0.6}}}
+
+	ApplySkillFreshness(ranked, bank, job, now)
+	assert.Equal(t, 0.6, ranked.Ranked[0].RelevanceScore)
+	assert.Empty(t, ranked.Ranked[0].RustySkills)
+}
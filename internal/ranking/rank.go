@@ -21,7 +21,19 @@ const (
 // RankStories ranks experience stories against a job profile using heuristic scoring only.
 // This is the original deterministic ranking function maintained for backward compatibility.
 func RankStories(jobProfile *types.JobProfile, experienceBank *types.ExperienceBank) (*types.RankedStories, error) {
-	return rankStoriesHeuristic(jobProfile, experienceBank)
+	return rankStoriesHeuristic(jobProfile, experienceBank, DefaultWeights)
+}
+
+// RankStoriesWeighted ranks experience stories with heuristic scoring, weighting the
+// scoring components by weights instead of DefaultWeights. Nil weights use the defaults.
+func RankStoriesWeighted(jobProfile *types.JobProfile, experienceBank *types.ExperienceBank, weights *Weights) (*types.RankedStories, error) {
+	if weights == nil {
+		return RankStories(jobProfile, experienceBank)
+	}
+	if err := weights.Validate(); err != nil {
+		return nil, err
+	}
+	return rankStoriesHeuristic(jobProfile, experienceBank, *weights)
 }
 
 // RankStoriesWithLLM ranks experience stories using hybrid heuristic + LLM scoring.
@@ -37,13 +49,13 @@ func RankStoriesWithLLM(ctx context.Context, jobProfile *types.JobProfile, exper
 	// Compute heuristic scores for all stories first
 	rankedStories := make([]types.RankedStory, 0, len(experienceBank.Stories))
 	for _, story := range experienceBank.Stories {
-		rankedStory := computeHeuristicScore(&story, jobProfile, skillTargets)
+		rankedStory := computeHeuristicScore(&story, jobProfile, skillTargets, DefaultWeights)
 		rankedStories = append(rankedStories, rankedStory)
 	}
 
-	// Attempt LLM scoring if API key is provided
+	// Attempt LLM scoring when a provider is available (local providers need no key)
 	var llmScores map[string]*LLMScoreResult
-	if apiKey != "" {
+	if apiKey != "" || !llm.APIKeyRequired() {
 		config := llm.DefaultConfig()
 		client, err := llm.NewClient(ctx, config, apiKey)
 		if err == nil {
@@ -87,7 +99,7 @@ func RankStoriesWithLLM(ctx context.Context, jobProfile *types.JobProfile, exper
 }
 
 // rankStoriesHeuristic performs heuristic-only ranking (internal implementation).
-func rankStoriesHeuristic(jobProfile *types.JobProfile, experienceBank *types.ExperienceBank) (*types.RankedStories, error) {
+func rankStoriesHeuristic(jobProfile *types.JobProfile, experienceBank *types.ExperienceBank, weights Weights) (*types.RankedStories, error) {
 	// Build skill targets from job profile
 	skillTargets, err := skills.BuildSkillTargets(jobProfile)
 	if err != nil {
@@ -97,7 +109,7 @@ func rankStoriesHeuristic(jobProfile *types.JobProfile, experienceBank *types.Ex
 	// Score each story
 	rankedStories := make([]types.RankedStory, 0, len(experienceBank.Stories))
 	for _, story := range experienceBank.Stories {
-		rankedStory := computeHeuristicScore(&story, jobProfile, skillTargets)
+		rankedStory := computeHeuristicScore(&story, jobProfile, skillTargets, weights)
 		rankedStory.RelevanceScore = rankedStory.HeuristicScore
 		rankedStories = append(rankedStories, rankedStory)
 	}
@@ -111,17 +123,18 @@ func rankStoriesHeuristic(jobProfile *types.JobProfile, experienceBank *types.Ex
 }
 
 // computeHeuristicScore calculates the heuristic score for a single story.
-func computeHeuristicScore(story *types.Story, jobProfile *types.JobProfile, skillTargets *types.SkillTargets) types.RankedStory {
+func computeHeuristicScore(story *types.Story, jobProfile *types.JobProfile, skillTargets *types.SkillTargets, weights Weights) types.RankedStory {
 	skillOverlap, matchedSkills := computeSkillOverlapScore(story, skillTargets)
 	keywordOverlap := computeKeywordOverlapScore(story, jobProfile)
 	evidenceStrength := computeEvidenceStrengthScore(story)
 	recency := computeRecencyScore(story)
 
 	// Calculate weighted heuristic score
-	heuristicScore := (skillOverlapWeight * skillOverlap) +
-		(keywordOverlapWeight * keywordOverlap) +
-		(evidenceStrengthWeight * evidenceStrength) +
-		(recencyWeight * recency)
+	heuristicScore := ((weights.SkillOverlap * skillOverlap) +
+		(weights.KeywordOverlap * keywordOverlap) +
+		(weights.EvidenceStrength * evidenceStrength) +
+		(weights.Recency * recency)) / weights.sum()
+	heuristicScore += weights.MetricBonus * computeMetricShare(story)
 
 	// Ensure score is in valid range
 	if heuristicScore > 1.0 {
@@ -359,7 +359,7 @@ func TestComputeHeuristicScore_Integration(t *testing.T) {
 		},
 	}
 
-	result := computeHeuristicScore(story, jobProfile, skillTargets)
+	result := computeHeuristicScore(story, jobProfile, skillTargets, DefaultWeights)
 
 	// HeuristicScore should be populated
 	assert.Greater(t, result.HeuristicScore, 0.0)
@@ -373,3 +373,67 @@ func TestComputeHeuristicScore_Integration(t *testing.T) {
 	// Notes should be generated
 	assert.NotEmpty(t, result.Notes)
 }
+
+func TestRankStoriesWeighted(t *testing.T) {
+	jobProfile := &types.JobProfile{
+		HardRequirements: []types.Requirement{{Skill: "Go", Evidence: "Required"}},
+	}
+	experienceBank := &types.ExperienceBank{
+		Stories: []types.Story{
+			{
+				ID:        "old_go",
+				StartDate: "2012-01",
+				EndDate:   "2014-01",
+				Bullets:   []types.Bullet{{Skills: []string{"Go"}, Text: "Wrote Go services", EvidenceStrength: "high"}},
+			},
+			{
+				ID:        "recent_python",
+				StartDate: "2024-01",
+				EndDate:   "present",
+				Bullets:   []types.Bullet{{Skills: []string{"Python"}, Text: "Wrote Python services", EvidenceStrength: "high"}},
+			},
+		},
+	}
+
+	ranked, err := RankStoriesWeighted(jobProfile, experienceBank, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "old_go", ranked.Ranked[0].StoryID, "default weights favor the skill match")
+
+	ranked, err = RankStoriesWeighted(jobProfile, experienceBank, &Weights{Recency: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "recent_python", ranked.Ranked[0].StoryID, "recency-only weights favor the recent story")
+	assert.LessOrEqual(t, ranked.Ranked[0].RelevanceScore, 1.0, "scores stay normalized")
+
+	_, err = RankStoriesWeighted(jobProfile, experienceBank, &Weights{})
+	assert.Error(t, err)
+	_, err = RankStoriesWeighted(jobProfile, experienceBank, &Weights{SkillOverlap: 1, Recency: -0.5})
+	assert.Error(t, err)
+}
+
+func TestRankStoriesWeightedMetricBonus(t *testing.T) {
+	jobProfile := &types.JobProfile{
+		HardRequirements: []types.Requirement{{Skill: "Go", Evidence: "Required"}},
+	}
+	experienceBank := &types.ExperienceBank{
+		Stories: []types.Story{
+			{
+				ID:      "plain",
+				Bullets: []types.Bullet{{Skills: []string{"Go"}, Text: "Wrote Go services", EvidenceStrength: "high"}},
+			},
+			{
+				ID:      "quantified",
+				Bullets: []types.Bullet{{Skills: []string{"Go"}, Text: "Wrote Go services", Metrics: "40% faster", EvidenceStrength: "high"}},
+			},
+		},
+	}
+
+	ranked, err := RankStoriesWeighted(jobProfile, experienceBank, &Weights{SkillOverlap: 1, Recency: 1, MetricBonus: 0.2})
+	require.NoError(t, err)
+	assert.Equal(t, "quantified", ranked.Ranked[0].StoryID)
+	assert.InDelta(t, 0.2, ranked.Ranked[0].RelevanceScore-ranked.Ranked[1].RelevanceScore, 1e-9)
+
+	_, err = RankStoriesWeighted(jobProfile, experienceBank, &Weights{SkillOverlap: 1, MetricBonus: MaxMetricBonus + 0.1})
+	assert.Error(t, err)
+	_, err = RankStoriesWeighted(jobProfile, experienceBank, &Weights{MetricBonus: 0.2})
+	assert.Error(t, err, "the bonus alone is not a ranking")
+}
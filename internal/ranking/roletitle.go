@@ -0,0 +1,57 @@
+package ranking
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jonathan/resume-customizer/internal/titles"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Relevance added to a story held in the posting's role family, and added again when the
+// story's level is within one of the posting's
+const (
+	roleFamilyBoost = 0.05
+	roleLevelBoost  = 0.03
+)
+
+// ApplyRoleTitleBias boosts stories from roles in the same family as the posting's, after
+// normalizing both titles ("Sr. SWE" and "Software Engineer III" are the same role), and
+// re-sorts them. Stories at a comparable level gain a little more.
+func ApplyRoleTitleBias(ranked *types.RankedStories, bank *types.ExperienceBank, jobProfile *types.JobProfile) {
+	if ranked == nil || bank == nil || jobProfile == nil {
+		return
+	}
+	target := titles.NormalizeAt(jobProfile.RoleTitle, jobProfile.Company)
+	if target.Family == "" {
+		return
+	}
+
+	storyTitles := make(map[string]titles.Title, len(bank.Stories))
+	for _, story := range bank.Stories {
+		storyTitles[story.ID] = titles.NormalizeAt(story.Role, story.Company)
+	}
+
+	for i := range ranked.Ranked {
+		story := &ranked.Ranked[i]
+		held, ok := storyTitles[story.StoryID]
+		if !ok || !titles.SameRole(held, target) {
+			continue
+		}
+		boost := roleFamilyBoost
+		if held.Level != titles.LevelUnknown && target.Level != titles.LevelUnknown &&
+			held.Level >= target.Level-1 && held.Level <= target.Level+1 {
+			boost += roleLevelBoost
+		}
+		story.RelevanceScore += boost
+		if story.RelevanceScore > 1.0 {
+			story.RelevanceScore = 1.0
+		}
+		story.RoleMatch = held.Canonical
+		story.Notes += fmt.Sprintf(". Same role as the posting (%s)", held.Canonical)
+	}
+
+	sort.SliceStable(ranked.Ranked, func(i, j int) bool {
+		return ranked.Ranked[i].RelevanceScore > ranked.Ranked[j].RelevanceScore
+	})
+}
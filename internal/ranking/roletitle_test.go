@@ -0,0 +1,43 @@
+package ranking
+
+import (
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRoleTitleBias(t *testing.T) {
+	bank := &types.ExperienceBank{Stories: []types.Story{
+		{ID: "pm_story", Company: "Acme", Role: "Product Manager"},
+		{ID: "swe_story", Company: "Google", Role: "Software Engineer, L5"},
+		{ID: "junior_story", Company: "Startup", Role: "Software Developer I"},
+	}}
+	ranked := &types.RankedStories{Ranked: []types.RankedStory{
+		{StoryID: "pm_story", RelevanceScore: 0.7},
+		{StoryID: "junior_story", RelevanceScore: 0.66},
+		{StoryID: "swe_story", RelevanceScore: 0.6},
+	}}
+
+	ApplyRoleTitleBias(ranked, bank, &types.JobProfile{Company: "Beta", RoleTitle: "Sr. SWE"})
+
+	require.Len(t, ranked.Ranked, 3)
+	assert.Equal(t, "junior_story", ranked.Ranked[0].StoryID)
+	assert.InDelta(t, 0.71, ranked.Ranked[0].RelevanceScore, 1e-9)
+	assert.Equal(t, "Junior Software Engineer", ranked.Ranked[0].RoleMatch)
+	assert.Equal(t, "pm_story", ranked.Ranked[1].StoryID)
+	assert.Empty(t, ranked.Ranked[1].RoleMatch)
+	assert.Equal(t, "swe_story", ranked.Ranked[2].StoryID)
+	assert.InDelta(t, 0.68, ranked.Ranked[2].RelevanceScore, 1e-9)
+	assert.Contains(t, ranked.Ranked[2].Notes, "Same role as the posting (Senior Software Engineer)")
+}
+
+func TestApplyRoleTitleBias_NoRoleWords(t *testing.T) {
+	bank := &types.ExperienceBank{Stories: []types.Story{{ID: "s1", Role: "Senior"}}}
+	ranked := &types.RankedStories{Ranked: []types.RankedStory{{StoryID: "s1", RelevanceScore: 0.5}}}
+
+	ApplyRoleTitleBias(ranked, bank, &types.JobProfile{RoleTitle: "Staff"})
+
+	assert.InDelta(t, 0.5, ranked.Ranked[0].RelevanceScore, 1e-9)
+}
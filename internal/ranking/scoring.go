@@ -2,6 +2,8 @@
 package ranking
 
 import (
+	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -9,13 +11,56 @@ import (
 	"github.com/jonathan/resume-customizer/internal/types"
 )
 
-// Default weights for scoring components
-const (
-	skillOverlapWeight     = 0.5
-	keywordOverlapWeight   = 0.2
-	evidenceStrengthWeight = 0.2
-	recencyWeight          = 0.1
-)
+// Weights sets how much each scoring component counts toward a story's heuristic score.
+// They are relative: a story's score divides by their sum, so it stays between 0 and 1.
+// MetricBonus is added on top, scaled by the share of the story's bullets with metrics.
+type Weights struct {
+	SkillOverlap     float64 `json:"skill_overlap"`
+	KeywordOverlap   float64 `json:"keyword_overlap"`
+	EvidenceStrength float64 `json:"evidence_strength"`
+	Recency          float64 `json:"recency"`
+	MetricBonus      float64 `json:"metric_bonus,omitempty"` // 0 to MaxMetricBonus
+}
+
+// MaxMetricBonus is the largest bonus a story can earn for quantified bullets
+const MaxMetricBonus = 0.5
+
+// DefaultWeights are the weights used when a run sets none
+var DefaultWeights = Weights{
+	SkillOverlap:     0.5,
+	KeywordOverlap:   0.2,
+	EvidenceStrength: 0.2,
+	Recency:          0.1,
+}
+
+// Validate checks that no weight is negative and at least one is positive
+func (w Weights) Validate() error {
+	for _, c := range []struct {
+		name  string
+		value float64
+	}{
+		{"skill_overlap", w.SkillOverlap},
+		{"keyword_overlap", w.KeywordOverlap},
+		{"evidence_strength", w.EvidenceStrength},
+		{"recency", w.Recency},
+		{"metric_bonus", w.MetricBonus},
+	} {
+		if c.value < 0 || math.IsNaN(c.value) || math.IsInf(c.value, 0) {
+			return fmt.Errorf("ranking weight %s must be a non-negative number", c.name)
+		}
+	}
+	if w.MetricBonus > MaxMetricBonus {
+		return fmt.Errorf("ranking weight metric_bonus must be at most %g", MaxMetricBonus)
+	}
+	if w.sum() == 0 {
+		return fmt.Errorf("at least one ranking weight must be positive")
+	}
+	return nil
+}
+
+func (w Weights) sum() float64 {
+	return w.SkillOverlap + w.KeywordOverlap + w.EvidenceStrength + w.Recency
+}
 
 // computeSkillOverlapScore calculates the skill overlap score between a story and skill targets.
 // Returns the score (0-1) and list of matched skill names.
@@ -67,7 +112,12 @@ func computeSkillOverlapScore(story *types.Story, skillTargets *types.SkillTarge
 	return score, matchedSkills
 }
 
+// userKeywordWeight is how much more a keyword the user added counts toward keyword
+// overlap than one parsed from the posting
+const userKeywordWeight = 2.0
+
 // computeKeywordOverlapScore calculates keyword overlap score by matching job keywords against story text.
+// Keywords the user added count userKeywordWeight times as much as the others.
 func computeKeywordOverlapScore(story *types.Story, jobProfile *types.JobProfile) float64 {
 	if len(jobProfile.Keywords) == 0 {
 		return 0.0
@@ -81,18 +131,28 @@ func computeKeywordOverlapScore(story *types.Story, jobProfile *types.JobProfile
 	}
 	storyTextLower := strings.ToLower(storyText.String())
 
-	// Count keyword matches (case-insensitive)
-	matches := 0
+	userKeywords := make(map[string]bool, len(jobProfile.UserKeywords))
+	for _, keyword := range jobProfile.UserKeywords {
+		userKeywords[strings.ToLower(strings.TrimSpace(keyword))] = true
+	}
+
+	// Sum the weights of matching keywords (case-insensitive)
+	matched, total := 0.0, 0.0
 	for _, keyword := range jobProfile.Keywords {
 		keywordLower := strings.ToLower(keyword)
+		weight := 1.0
+		if userKeywords[strings.TrimSpace(keywordLower)] {
+			weight = userKeywordWeight
+		}
+		total += weight
 		// Simple substring matching (could be enhanced with word boundary checks)
 		if strings.Contains(storyTextLower, keywordLower) {
-			matches++
+			matched += weight
 		}
 	}
 
-	// Normalize by number of keywords
-	score := float64(matches) / float64(len(jobProfile.Keywords))
+	// Normalize by total keyword weight
+	score := matched / total
 	if score > 1.0 {
 		score = 1.0
 	}
@@ -125,6 +185,20 @@ func computeEvidenceStrengthScore(story *types.Story) float64 {
 	return totalScore / float64(len(story.Bullets))
 }
 
+// computeMetricShare returns the fraction of a story's bullets that carry metrics (0-1)
+func computeMetricShare(story *types.Story) float64 {
+	if len(story.Bullets) == 0 {
+		return 0.0
+	}
+	withMetrics := 0
+	for _, bullet := range story.Bullets {
+		if strings.TrimSpace(bullet.Metrics) != "" {
+			withMetrics++
+		}
+	}
+	return float64(withMetrics) / float64(len(story.Bullets))
+}
+
 // computeRecencyScore calculates a recency score based on story start date.
 // Returns 0.5 as default if date parsing fails (neutral score).
 func computeRecencyScore(story *types.Story) float64 {
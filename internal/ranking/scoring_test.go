@@ -147,6 +147,25 @@ func TestComputeKeywordOverlapScore_PartialMatches(t *testing.T) {
 	assert.InDelta(t, 1.0/3.0, score, 0.01)
 }
 
+func TestComputeKeywordOverlapScore_UserKeywords(t *testing.T) {
+	story := &types.Story{
+		ID: "story_010",
+		Bullets: []types.Bullet{
+			{Text: "Worked with microservices"},
+		},
+	}
+
+	jobProfile := &types.JobProfile{
+		Keywords:     []string{"microservices", "distributed systems", "cloud"},
+		UserKeywords: []string{"Microservices"},
+	}
+
+	score := computeKeywordOverlapScore(story, jobProfile)
+
+	// The user's keyword counts double: 2 out of 4
+	assert.InDelta(t, 0.5, score, 0.01)
+}
+
 func TestComputeKeywordOverlapScore_NoMatches(t *testing.T) {
 	story := &types.Story{
 		ID: "story_008",
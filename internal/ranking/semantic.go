@@ -0,0 +1,97 @@
+package ranking
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// JobEmbeddingText is the text embedded to stand for what a job asks for: its role,
+// responsibilities, requirements with their evidence, and keywords
+func JobEmbeddingText(jobProfile *types.JobProfile) string {
+	if jobProfile == nil {
+		return ""
+	}
+	var lines []string
+	if jobProfile.RoleTitle != "" {
+		lines = append(lines, jobProfile.RoleTitle)
+	}
+	lines = append(lines, jobProfile.Responsibilities...)
+	for _, reqs := range [][]types.Requirement{jobProfile.HardRequirements, jobProfile.NiceToHaves} {
+		for _, req := range reqs {
+			line := req.Skill
+			if req.Evidence != "" {
+				line += ": " + req.Evidence
+			}
+			lines = append(lines, line)
+		}
+	}
+	if len(jobProfile.Keywords) > 0 {
+		lines = append(lines, strings.Join(jobProfile.Keywords, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, or 0 when their
+// lengths differ or either is zero
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// ApplySemanticScores blends each story's relevance with its semantic score and re-sorts
+// them. A story's semantic score is the cosine similarity between job, the embedding of
+// JobEmbeddingText, and its closest bullet's embedding in bullets (keyed by bullet ID),
+// floored at 0. Its relevance becomes (1-weight) times the keyword score plus weight
+// times the semantic score. Stories without embedded bullets keep their keyword score.
+func ApplySemanticScores(ranked *types.RankedStories, bank *types.ExperienceBank, job []float32, bullets map[string][]float32, weight float64) {
+	if ranked == nil || bank == nil || len(job) == 0 || weight <= 0 {
+		return
+	}
+	weight = math.Min(weight, 1)
+
+	semantic := make(map[string]float64, len(bank.Stories))
+	for _, story := range bank.Stories {
+		best, found := 0.0, false
+		for _, bullet := range story.Bullets {
+			vec, ok := bullets[bullet.ID]
+			if !ok {
+				continue
+			}
+			found = true
+			best = math.Max(best, CosineSimilarity(job, vec))
+		}
+		if found {
+			semantic[story.ID] = best
+		}
+	}
+
+	for i := range ranked.Ranked {
+		story := &ranked.Ranked[i]
+		score, ok := semantic[story.StoryID]
+		if !ok {
+			continue
+		}
+		story.SemanticScore = &score
+		story.RelevanceScore = (1-weight)*story.RelevanceScore + weight*score
+		story.Notes += fmt.Sprintf(". Semantic similarity %.2f", score)
+	}
+
+	sort.SliceStable(ranked.Ranked, func(i, j int) bool {
+		return ranked.Ranked[i].RelevanceScore > ranked.Ranked[j].RelevanceScore
+	})
+}
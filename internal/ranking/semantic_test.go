@@ -0,0 +1,80 @@
+package ranking
+
+import (
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	assert.InDelta(t, 1.0, CosineSimilarity([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	assert.InDelta(t, 0.0, CosineSimilarity([]float32{1, 0}, []float32{0, 3}), 1e-9)
+	assert.InDelta(t, -1.0, CosineSimilarity([]float32{1, 0}, []float32{-1, 0}), 1e-9)
+	assert.Zero(t, CosineSimilarity([]float32{1, 0}, []float32{1, 0, 0}), "mismatched dimensions")
+	assert.Zero(t, CosineSimilarity([]float32{0, 0}, []float32{1, 0}), "zero vector")
+	assert.Zero(t, CosineSimilarity(nil, nil))
+}
+
+func TestJobEmbeddingText(t *testing.T) {
+	text := JobEmbeddingText(&types.JobProfile{
+		RoleTitle:        "Staff SRE",
+		Responsibilities: []string{"Own the paging rotation"},
+		HardRequirements: []types.Requirement{{Skill: "Kubernetes", Evidence: "Run clusters at scale"}},
+		NiceToHaves:      []types.Requirement{{Skill: "Go"}},
+		Keywords:         []string{"reliability", "SLOs"},
+	})
+	assert.Equal(t, "Staff SRE\nOwn the paging rotation\nKubernetes: Run clusters at scale\nGo\nreliability, SLOs", text)
+	assert.Empty(t, JobEmbeddingText(nil))
+}
+
+func TestApplySemanticScores(t *testing.T) {
+	bank := &types.ExperienceBank{Stories: []types.Story{
+		{ID: "keyword_story", Bullets: []types.Bullet{{ID: "b1"}}},
+		{ID: "semantic_story", Bullets: []types.Bullet{{ID: "b2"}, {ID: "b3"}}},
+		{ID: "unembedded_story", Bullets: []types.Bullet{{ID: "b4"}}},
+	}}
+	ranked := &types.RankedStories{Ranked: []types.RankedStory{
+		{StoryID: "keyword_story", RelevanceScore: 0.8, Notes: "Strong skill match (Go)"},
+		{StoryID: "semantic_story", RelevanceScore: 0.4},
+		{StoryID: "unembedded_story", RelevanceScore: 0.5},
+	}}
+	job := []float32{1, 0}
+	bullets := map[string][]float32{
+		"b1": {-1, 0}, // Opposite: floored at 0
+		"b2": {0, 1},  // Unrelated
+		"b3": {1, 0},  // Same direction; the story's best bullet
+	}
+
+	ApplySemanticScores(ranked, bank, job, bullets, 0.5)
+
+	require.Len(t, ranked.Ranked, 3)
+	assert.Equal(t, "semantic_story", ranked.Ranked[0].StoryID)
+	assert.InDelta(t, 0.7, ranked.Ranked[0].RelevanceScore, 1e-9)
+	require.NotNil(t, ranked.Ranked[0].SemanticScore)
+	assert.InDelta(t, 1.0, *ranked.Ranked[0].SemanticScore, 1e-9)
+
+	assert.Equal(t, "unembedded_story", ranked.Ranked[1].StoryID)
+	assert.InDelta(t, 0.5, ranked.Ranked[1].RelevanceScore, 1e-9)
+	assert.Nil(t, ranked.Ranked[1].SemanticScore)
+
+	assert.Equal(t, "keyword_story", ranked.Ranked[2].StoryID)
+	assert.InDelta(t, 0.4, ranked.Ranked[2].RelevanceScore, 1e-9)
+	assert.Contains(t, ranked.Ranked[2].Notes, "Semantic similarity 0.00")
+}
+
+func TestApplySemanticScores_NoOp(t *testing.T) {
+	bank := &types.ExperienceBank{Stories: []types.Story{{ID: "s1", Bullets: []types.Bullet{{ID: "b1"}}}}}
+	bullets := map[string][]float32{"b1": {1, 0}}
+	for name, apply := range map[string]func(*types.RankedStories){
+		"no job vector": func(r *types.RankedStories) { ApplySemanticScores(r, bank, nil, bullets, 0.5) },
+		"zero weight":   func(r *types.RankedStories) { ApplySemanticScores(r, bank, []float32{0, 1}, bullets, 0) },
+	} {
+		ranked := &types.RankedStories{Ranked: []types.RankedStory{{StoryID: "s1", RelevanceScore: 0.6}}}
+		apply(ranked)
+		assert.InDelta(t, 0.6, ranked.Ranked[0].RelevanceScore, 1e-9, name)
+		assert.Nil(t, ranked.Ranked[0].SemanticScore, name)
+	}
+	ApplySemanticScores(nil, bank, []float32{1}, bullets, 0.5)
+}
@@ -0,0 +1,63 @@
+package ranking
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/skills"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Relevance added per story skill in the company's tech stack, and the most a story can gain
+const (
+	techStackBoostPerMatch = 0.05
+	maxTechStackBoost      = 0.15
+)
+
+// ApplyTechStackBias boosts stories whose skills match the company's tech stack and re-sorts them.
+// A story gains techStackBoostPerMatch for each distinct matching skill, up to maxTechStackBoost.
+func ApplyTechStackBias(ranked *types.RankedStories, bank *types.ExperienceBank, stack []string) {
+	if ranked == nil || bank == nil || len(stack) == 0 {
+		return
+	}
+
+	storySkills := make(map[string][]string, len(bank.Stories))
+	for _, story := range bank.Stories {
+		for _, bullet := range story.Bullets {
+			storySkills[story.ID] = append(storySkills[story.ID], bullet.Skills...)
+		}
+	}
+
+	for i := range ranked.Ranked {
+		story := &ranked.Ranked[i]
+		var matches []string
+		seen := make(map[string]bool)
+		for _, skill := range storySkills[story.StoryID] {
+			key := strings.ToLower(skill)
+			if seen[key] || !skills.InTechStack(skill, stack) {
+				continue
+			}
+			seen[key] = true
+			matches = append(matches, skill)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		boost := techStackBoostPerMatch * float64(len(matches))
+		if boost > maxTechStackBoost {
+			boost = maxTechStackBoost
+		}
+		story.RelevanceScore += boost
+		if story.RelevanceScore > 1.0 {
+			story.RelevanceScore = 1.0
+		}
+		story.TechStackMatches = matches
+		story.Notes += fmt.Sprintf(". Matches company stack (%s)", strings.Join(matches, ", "))
+	}
+
+	sort.SliceStable(ranked.Ranked, func(i, j int) bool {
+		return ranked.Ranked[i].RelevanceScore > ranked.Ranked[j].RelevanceScore
+	})
+}
@@ -0,0 +1,52 @@
+package ranking
+
+import (
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTechStackBias(t *testing.T) {
+	bank := &types.ExperienceBank{
+		Stories: []types.Story{
+			{ID: "python_story", Bullets: []types.Bullet{{Skills: []string{"Python"}}}},
+			{ID: "go_story", Bullets: []types.Bullet{{Skills: []string{"golang", "Kafka"}}, {Skills: []string{"Kafka"}}}},
+		},
+	}
+	ranked := &types.RankedStories{Ranked: []types.RankedStory{
+		{StoryID: "python_story", RelevanceScore: 0.6, Notes: "Strong skill match (Python)"},
+		{StoryID: "go_story", RelevanceScore: 0.55, Notes: "Weak skill match (Go)"},
+	}}
+
+	ApplyTechStackBias(ranked, bank, []string{"Go", "Kubernetes", "Kafka"})
+
+	require.Len(t, ranked.Ranked, 2)
+	assert.Equal(t, "go_story", ranked.Ranked[0].StoryID)
+	assert.InDelta(t, 0.65, ranked.Ranked[0].RelevanceScore, 1e-9)
+	assert.Equal(t, []string{"golang", "Kafka"}, ranked.Ranked[0].TechStackMatches)
+	assert.Contains(t, ranked.Ranked[0].Notes, "Matches company stack (golang, Kafka)")
+	assert.InDelta(t, 0.6, ranked.Ranked[1].RelevanceScore, 1e-9)
+	assert.Empty(t, ranked.Ranked[1].TechStackMatches)
+}
+
+func TestApplyTechStackBias_CapsBoost(t *testing.T) {
+	bank := &types.ExperienceBank{Stories: []types.Story{
+		{ID: "s1", Bullets: []types.Bullet{{Skills: []string{"Go", "Kafka", "Kubernetes", "AWS", "Redis"}}}},
+	}}
+	ranked := &types.RankedStories{Ranked: []types.RankedStory{{StoryID: "s1", RelevanceScore: 0.95}}}
+
+	ApplyTechStackBias(ranked, bank, []string{"Go", "Kafka", "Kubernetes", "AWS", "Redis"})
+	assert.Equal(t, 1.0, ranked.Ranked[0].RelevanceScore)
+
+	ranked = &types.RankedStories{Ranked: []types.RankedStory{{StoryID: "s1", RelevanceScore: 0.5}}}
+	ApplyTechStackBias(ranked, bank, []string{"Go", "Kafka", "Kubernetes", "AWS", "Redis"})
+	assert.InDelta(t, 0.65, ranked.Ranked[0].RelevanceScore, 1e-9)
+}
+
+func TestApplyTechStackBias_EmptyStack(t *testing.T) {
+	ranked := &types.RankedStories{Ranked: []types.RankedStory{{StoryID: "s1", RelevanceScore: 0.5}}}
+	ApplyTechStackBias(ranked, &types.ExperienceBank{}, nil)
+	assert.Equal(t, 0.5, ranked.Ranked[0].RelevanceScore)
+}
@@ -0,0 +1,167 @@
+package readability
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// generalJargon maps business buzzwords to plainer wording
+var generalJargon = map[string]string{
+	"leverage":              "use",
+	"leveraged":             "used",
+	"leveraging":            "using",
+	"utilize":               "use",
+	"utilized":              "used",
+	"utilizing":             "using",
+	"synergy":               "teamwork",
+	"synergies":             "shared benefits",
+	"operationalize":        "put into practice",
+	"operationalized":       "put into practice",
+	"best-in-class":         "leading",
+	"world-class":           "strong",
+	"paradigm":              "approach",
+	"spearheaded":           "led",
+	"orchestrated":          "organized",
+	"facilitated":           "ran",
+	"actioned":              "acted on",
+	"ideated":               "came up with",
+	"incentivized":          "encouraged",
+	"holistic":              "complete",
+	"robust":                "reliable",
+	"bleeding-edge":         "new",
+	"cutting-edge":          "new",
+	"value-add":             "benefit",
+	"move the needle":       "make a difference",
+	"deep dive":             "close look",
+	"low-hanging fruit":     "quick wins",
+	"thought leadership":    "expertise",
+	"stakeholder alignment": "agreement",
+	"cross-functional":      "cross-team",
+}
+
+// domainJargon holds insider terms for the industries a company's domain context may name.
+// Each entry lists the word prefixes that select it and the terms it flags.
+var domainJargon = []struct {
+	triggers []string
+	terms    map[string]string
+}{
+	{
+		triggers: []string{"fintech", "payment", "bank", "lending", "financial"},
+		terms: map[string]string{
+			"kyc":          "identity checks",
+			"aml":          "anti-money-laundering checks",
+			"interchange":  "card fees",
+			"ach":          "bank transfers",
+			"ledgering":    "bookkeeping",
+			"underwriting": "credit review",
+			"chargebacks":  "disputed payments",
+		},
+	},
+	{
+		triggers: []string{"health", "clinical", "medical", "patient", "hospital"},
+		terms: map[string]string{
+			"ehr":              "electronic health records",
+			"emr":              "medical records",
+			"hl7":              "health data standard",
+			"fhir":             "health data standard",
+			"phi":              "patient data",
+			"interoperability": "data sharing",
+			"care continuum":   "patient care",
+		},
+	},
+	{
+		triggers: []string{"advertising", "adtech", "marketing", "ads"},
+		terms: map[string]string{
+			"programmatic": "automated ad buying",
+			"cpm":          "cost per thousand views",
+			"roas":         "return on ad spend",
+			"dsp":          "ad-buying platform",
+			"ssp":          "ad-selling platform",
+			"retargeting":  "follow-up ads",
+		},
+	},
+	{
+		triggers: []string{"machine learning", "artificial intelligence", "data science"},
+		terms: map[string]string{
+			"hyperparameter": "model setting",
+			"featurization":  "preparing data",
+			"inference":      "predictions",
+			"mlops":          "model operations",
+			"embeddings":     "text representations",
+		},
+	},
+	{
+		triggers: []string{"infrastructure", "cloud", "devops", "platform"},
+		terms: map[string]string{
+			"idempotent":    "safe to repeat",
+			"sharding":      "splitting data",
+			"observability": "monitoring",
+			"toil":          "manual work",
+			"blast radius":  "impact of failures",
+			"shift-left":    "earlier testing",
+		},
+	},
+}
+
+// Dictionary maps jargon terms (lowercase) to plainer wording
+type Dictionary map[string]string
+
+// DictionaryFor returns the general business jargon plus the insider terms of any
+// industry the company's domain context names
+func DictionaryFor(domainContext string) Dictionary {
+	dict := make(Dictionary, len(generalJargon))
+	for term, plain := range generalJargon {
+		dict[term] = plain
+	}
+	context := " " + strings.Join(strings.FieldsFunc(strings.ToLower(domainContext), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+	for _, domain := range domainJargon {
+		for _, trigger := range domain.triggers {
+			if strings.Contains(context, " "+trigger) {
+				for term, plain := range domain.terms {
+					dict[term] = plain
+				}
+				break
+			}
+		}
+	}
+	return dict
+}
+
+// JargonHit is one jargon term found in text
+type JargonHit struct {
+	Term  string `json:"term"`
+	Plain string `json:"plain"`
+}
+
+// Find returns the dictionary terms used in text, matched on whole words, in the
+// order they first appear
+func (d Dictionary) Find(text string) []JargonHit {
+	lower := strings.ToLower(text)
+	type found struct {
+		hit JargonHit
+		at  int
+	}
+	var hits []found
+	for term, plain := range d {
+		pattern := regexp.MustCompile(`(?:^|[^a-z0-9-])` + regexp.QuoteMeta(term) + `(?:$|[^a-z0-9-])`)
+		if loc := pattern.FindStringIndex(lower); loc != nil {
+			hits = append(hits, found{JargonHit{Term: term, Plain: plain}, loc[0]})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].at != hits[j].at {
+			return hits[i].at < hits[j].at
+		}
+		return hits[i].hit.Term < hits[j].hit.Term
+	})
+
+	out := make([]JargonHit, 0, len(hits))
+	for _, h := range hits {
+		out = append(out, h.hit)
+	}
+	return out
+}
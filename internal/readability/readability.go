@@ -0,0 +1,92 @@
+// Package readability scores resume text for reading level and jargon, and suggests
+// simplifications for companies that prefer plain language.
+package readability
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	sentenceEnd = regexp.MustCompile(`[.!?]+(?:\s+|$)`)
+	vowelGroups = regexp.MustCompile(`[aeiouy]+`)
+)
+
+// Stats holds the counts readability formulas are computed from
+type Stats struct {
+	Sentences int `json:"sentences"`
+	Words     int `json:"words"`
+	Syllables int `json:"syllables"`
+}
+
+// Add returns the combined counts of s and o
+func (s Stats) Add(o Stats) Stats {
+	return Stats{Sentences: s.Sentences + o.Sentences, Words: s.Words + o.Words, Syllables: s.Syllables + o.Syllables}
+}
+
+// GradeLevel is the Flesch-Kincaid grade level: roughly the US school grade needed to
+// read the text easily. Resume bullets usually land between 8 and 14.
+func (s Stats) GradeLevel() float64 {
+	if s.Words == 0 || s.Sentences == 0 {
+		return 0
+	}
+	return round1(0.39*float64(s.Words)/float64(s.Sentences) + 11.8*float64(s.Syllables)/float64(s.Words) - 15.59)
+}
+
+// ReadingEase is the Flesch reading-ease score: 60-70 is plain English, below 30 is dense
+func (s Stats) ReadingEase() float64 {
+	if s.Words == 0 || s.Sentences == 0 {
+		return 0
+	}
+	return round1(206.835 - 1.015*float64(s.Words)/float64(s.Sentences) - 84.6*float64(s.Syllables)/float64(s.Words))
+}
+
+// Measure counts the sentences, words, and syllables of text. A bullet without closing
+// punctuation counts as one sentence; tokens without letters (metrics like "40%")
+// count as one-syllable words.
+func Measure(text string) Stats {
+	text = strings.TrimSpace(text)
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return Stats{}
+	}
+
+	stats := Stats{Words: len(words)}
+	stats.Sentences = len(sentenceEnd.FindAllStringIndex(text, -1))
+	if !strings.ContainsAny(text[len(text)-1:], ".!?") {
+		stats.Sentences++
+	}
+	for _, w := range words {
+		stats.Syllables += Syllables(w)
+	}
+	return stats
+}
+
+// Syllables estimates the syllables in a word from its vowel groups, dropping a
+// silent final "e". Words without letters count as one.
+func Syllables(word string) int {
+	letters := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, word)
+	if letters == "" {
+		return 1
+	}
+
+	count := len(vowelGroups.FindAllString(letters, -1))
+	if strings.HasSuffix(letters, "e") && !strings.HasSuffix(letters, "le") && count > 1 {
+		count--
+	}
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,94 @@
+package readability
+
+import (
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyllables(t *testing.T) {
+	tests := map[string]int{
+		"go":             1,
+		"make":           1,
+		"table":          2,
+		"latency":        3,
+		"infrastructure": 4,
+		"40%":            1,
+		"(Kubernetes)":   4,
+	}
+	for word, want := range tests {
+		assert.Equal(t, want, Syllables(word), word)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	stats := Measure("Cut build time by 40%. Led the migration to Go")
+	assert.Equal(t, Stats{Sentences: 2, Words: 10, Syllables: 12}, stats)
+	assert.Equal(t, Stats{}, Measure("   "))
+}
+
+func TestGradeLevel(t *testing.T) {
+	simple := Measure("Built a tool that cut build time in half.")
+	dense := Measure("Operationalized comprehensive infrastructure modernization initiatives, facilitating organizational transformation.")
+	assert.Less(t, simple.GradeLevel(), 6.0)
+	assert.Greater(t, dense.GradeLevel(), 20.0)
+	assert.Greater(t, simple.ReadingEase(), dense.ReadingEase())
+}
+
+func TestDictionaryFor(t *testing.T) {
+	dict := DictionaryFor("Payments infrastructure for online businesses")
+	assert.Equal(t, "use", dict["leverage"])
+	assert.Equal(t, "identity checks", dict["kyc"])
+	assert.Equal(t, "splitting data", dict["sharding"])
+	assert.NotContains(t, dict, "ehr")
+}
+
+func TestDictionaryFind(t *testing.T) {
+	dict := DictionaryFor("fintech")
+	hits := dict.Find("Leveraged Kafka to automate KYC reviews, a robust cutting-edge flow; not 'robustness'")
+	assert.Equal(t, []JargonHit{
+		{Term: "leveraged", Plain: "used"},
+		{Term: "kyc", Plain: "identity checks"},
+		{Term: "robust", Plain: "reliable"},
+		{Term: "cutting-edge", Plain: "new"},
+	}, hits)
+	assert.Empty(t, dict.Find("Reached each account via the cache"), "no partial-word matches")
+}
+
+func TestPrefersPlainLanguage(t *testing.T) {
+	assert.True(t, PrefersPlainLanguage(&types.CompanyProfile{Tone: "Friendly, plain-spoken"}))
+	assert.True(t, PrefersPlainLanguage(&types.CompanyProfile{StyleRules: []string{"Avoid jargon"}}))
+	assert.False(t, PrefersPlainLanguage(&types.CompanyProfile{Tone: "Formal and technical"}))
+	assert.False(t, PrefersPlainLanguage(nil))
+}
+
+func TestAnalyze(t *testing.T) {
+	bullets := &types.RewrittenBullets{Bullets: []types.RewrittenBullet{
+		{OriginalBulletID: "b1", FinalText: "Leveraged Go to cut payment latency by 40%"},
+		{OriginalBulletID: "b2", FinalText: "Operationalized comprehensive infrastructure modernization initiatives across organizational boundaries"},
+		{OriginalBulletID: "b3", FinalText: "Fixed bugs"},
+	}}
+	profile := &types.CompanyProfile{Tone: "Plain and direct", DomainContext: "Payments"}
+
+	report := Analyze(bullets, profile)
+	require.Len(t, report.Bullets, 3)
+	assert.True(t, report.PlainLanguage)
+	assert.Equal(t, PlainTargetGrade, report.TargetGrade)
+	assert.Greater(t, report.GradeLevel, 0.0)
+
+	assert.Equal(t, []JargonHit{{Term: "leveraged", Plain: "used"}}, report.Bullets[0].Jargon)
+	assert.Equal(t, []string{`Replace "leveraged" with plainer wording such as "used"`}, report.Bullets[0].Suggestions)
+	assert.Len(t, report.Bullets[1].Suggestions, 2, "jargon plus reading level")
+	assert.Empty(t, report.Bullets[2].Suggestions)
+	assert.Equal(t, 3, report.Suggestions)
+	assert.Contains(t, report.Summary(), "plain language preferred; 3 suggestions")
+
+	// Without a plain-language preference jargon is reported but nothing is suggested
+	report = Analyze(bullets, &types.CompanyProfile{Tone: "Technical"})
+	assert.False(t, report.PlainLanguage)
+	assert.Zero(t, report.Suggestions)
+	assert.NotEmpty(t, report.Bullets[0].Jargon)
+	assert.Empty(t, report.Bullets[1].Suggestions)
+}
@@ -0,0 +1,116 @@
+package readability
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// PlainTargetGrade is the highest grade level a bullet should read at for a company that
+// prefers plain language
+const PlainTargetGrade = 10.0
+
+// maxPlainWords is the longest sentence, in words, suggested for plain language
+const maxPlainWords = 25
+
+var plainLanguagePattern = regexp.MustCompile(
+	`\b(?:plain|simple|simply|jargon|clear|clarity|conversational|approachable|accessible|straightforward|everyday)\b`)
+
+// PrefersPlainLanguage reports whether the company's tone or style rules call for plain,
+// jargon-free writing
+func PrefersPlainLanguage(profile *types.CompanyProfile) bool {
+	if profile == nil {
+		return false
+	}
+	if plainLanguagePattern.MatchString(strings.ToLower(profile.Tone)) {
+		return true
+	}
+	for _, rule := range profile.StyleRules {
+		if plainLanguagePattern.MatchString(strings.ToLower(rule)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Report scores the reading level and jargon of a resume's bullets
+type Report struct {
+	GradeLevel    float64        `json:"grade_level"`  // Flesch-Kincaid grade of all bullets together
+	ReadingEase   float64        `json:"reading_ease"` // Flesch reading ease of all bullets together
+	PlainLanguage bool           `json:"plain_language"`
+	TargetGrade   float64        `json:"target_grade,omitempty"` // Set when the company prefers plain language
+	Bullets       []BulletReport `json:"bullets"`
+	Suggestions   int            `json:"suggestions"` // Total suggestions across bullets
+}
+
+// BulletReport scores one bullet
+type BulletReport struct {
+	BulletID    string      `json:"bullet_id"`
+	GradeLevel  float64     `json:"grade_level"`
+	Jargon      []JargonHit `json:"jargon,omitempty"`
+	Suggestions []string    `json:"suggestions,omitempty"`
+}
+
+// Analyze scores the final bullets against the company's domain jargon. Jargon is always
+// listed; simplifications are suggested only when the company prefers plain language.
+func Analyze(bullets *types.RewrittenBullets, profile *types.CompanyProfile) *Report {
+	report := &Report{PlainLanguage: PrefersPlainLanguage(profile), Bullets: []BulletReport{}}
+	if report.PlainLanguage {
+		report.TargetGrade = PlainTargetGrade
+	}
+	if bullets == nil {
+		return report
+	}
+
+	var domainContext string
+	if profile != nil {
+		domainContext = profile.DomainContext
+	}
+	dict := DictionaryFor(domainContext)
+
+	var total Stats
+	for _, b := range bullets.Bullets {
+		stats := Measure(b.FinalText)
+		if stats.Words == 0 {
+			continue
+		}
+		total = total.Add(stats)
+
+		br := BulletReport{BulletID: b.OriginalBulletID, GradeLevel: stats.GradeLevel(), Jargon: dict.Find(b.FinalText)}
+		if report.PlainLanguage {
+			br.Suggestions = suggest(stats, br.Jargon)
+			report.Suggestions += len(br.Suggestions)
+		}
+		report.Bullets = append(report.Bullets, br)
+	}
+	report.GradeLevel = total.GradeLevel()
+	report.ReadingEase = total.ReadingEase()
+	return report
+}
+
+// suggest lists simplifications for a bullet written for a plain-language company
+func suggest(stats Stats, jargon []JargonHit) []string {
+	var suggestions []string
+	for _, hit := range jargon {
+		suggestions = append(suggestions, fmt.Sprintf("Replace %q with plainer wording such as %q", hit.Term, hit.Plain))
+	}
+	if stats.Words/stats.Sentences > maxPlainWords {
+		suggestions = append(suggestions, fmt.Sprintf("Split into sentences of at most %d words", maxPlainWords))
+	}
+	if grade := stats.GradeLevel(); grade > PlainTargetGrade {
+		suggestions = append(suggestions,
+			fmt.Sprintf("Use shorter words to bring the reading level from grade %.1f to %.0f or below", grade, PlainTargetGrade))
+	}
+	return suggestions
+}
+
+// Summary is a one-line description of the report for run logs
+func (r *Report) Summary() string {
+	summary := fmt.Sprintf("reading grade %.1f", r.GradeLevel)
+	if r.PlainLanguage {
+		summary += fmt.Sprintf(" (plain language preferred; %d suggestions)", r.Suggestions)
+	}
+	return summary
+}
@@ -0,0 +1,114 @@
+// Package recipes provides named run recipes: bundles of run defaults for common
+// application scenarios.
+package recipes
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/ranking"
+)
+
+// DefaultTemplate is the template built-in recipes render with
+const DefaultTemplate = "templates/one_page_resume.tex"
+
+// Recipe bundles the run options suited to one application scenario. A run started with
+// a recipe takes every option the request leaves unset from it.
+type Recipe struct {
+	Name           string           `json:"name"`
+	Description    string           `json:"description"`
+	Template       string           `json:"template,omitempty"`
+	MaxBullets     int              `json:"max_bullets,omitempty"`
+	MaxLines       int              `json:"max_lines,omitempty"`
+	SectionOrder   []string         `json:"section_order,omitempty"`
+	RankingWeights *ranking.Weights `json:"ranking_weights,omitempty"`
+}
+
+// builtIn lists the recipes every user can run with, in display order
+var builtIn = []Recipe{
+	{
+		Name:         "new-grad",
+		Description:  "Leads with education and favors stories matching the posting's skills and keywords over seniority",
+		Template:     DefaultTemplate,
+		MaxBullets:   18,
+		MaxLines:     30,
+		SectionOrder: []string{"education", "experience"},
+		RankingWeights: &ranking.Weights{
+			SkillOverlap:     0.45,
+			KeywordOverlap:   0.3,
+			EvidenceStrength: 0.15,
+			Recency:          0.1,
+		},
+	},
+	{
+		Name:         "career-switcher",
+		Description:  "Ranks stories by transferable skills, so older or off-field work that matches still makes the page",
+		Template:     DefaultTemplate,
+		MaxBullets:   22,
+		MaxLines:     35,
+		SectionOrder: []string{"experience", "education"},
+		RankingWeights: &ranking.Weights{
+			SkillOverlap:     0.6,
+			KeywordOverlap:   0.25,
+			EvidenceStrength: 0.1,
+			Recency:          0.05,
+		},
+	},
+	{
+		Name:         "executive",
+		Description:  "Favors recent stories with measurable impact and keeps fewer, stronger bullets",
+		Template:     DefaultTemplate,
+		MaxBullets:   20,
+		MaxLines:     35,
+		SectionOrder: []string{"experience", "education"},
+		RankingWeights: &ranking.Weights{
+			SkillOverlap:     0.3,
+			KeywordOverlap:   0.1,
+			EvidenceStrength: 0.4,
+			Recency:          0.2,
+		},
+	},
+	{
+		Name:         "academic",
+		Description:  "Leads with education and favors well-evidenced research and teaching stories",
+		Template:     DefaultTemplate,
+		MaxBullets:   25,
+		MaxLines:     35,
+		SectionOrder: []string{"education", "experience"},
+		RankingWeights: &ranking.Weights{
+			SkillOverlap:     0.4,
+			KeywordOverlap:   0.2,
+			EvidenceStrength: 0.3,
+			Recency:          0.1,
+		},
+	},
+}
+
+// BuiltIn returns the built-in recipes, in display order
+func BuiltIn() []Recipe {
+	recipes := make([]Recipe, len(builtIn))
+	for i, r := range builtIn {
+		recipes[i] = r.clone()
+	}
+	return recipes
+}
+
+// LookupBuiltIn returns the built-in recipe with the given name, whatever its
+// capitalization
+func LookupBuiltIn(name string) (Recipe, bool) {
+	i := slices.IndexFunc(builtIn, func(r Recipe) bool { return strings.EqualFold(r.Name, strings.TrimSpace(name)) })
+	if i < 0 {
+		return Recipe{}, false
+	}
+	return builtIn[i].clone(), true
+}
+
+// clone copies r so callers cannot modify the built-in recipes
+func (r Recipe) clone() Recipe {
+	r.SectionOrder = slices.Clone(r.SectionOrder)
+	if r.RankingWeights != nil {
+		weights := *r.RankingWeights
+		r.RankingWeights = &weights
+	}
+	return r
+}
@@ -0,0 +1,42 @@
+package recipes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/rendering"
+)
+
+func TestBuiltInRecipesAreValid(t *testing.T) {
+	manifest, err := rendering.LoadTemplateManifest("../../" + DefaultTemplate)
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, r := range BuiltIn() {
+		names = append(names, r.Name)
+		assert.NotEmpty(t, r.Description, r.Name)
+		assert.Equal(t, DefaultTemplate, r.Template, r.Name)
+		assert.NoError(t, manifest.Validate(&rendering.Style{SectionOrder: r.SectionOrder}), r.Name)
+		require.NotNil(t, r.RankingWeights, r.Name)
+		assert.NoError(t, r.RankingWeights.Validate(), r.Name)
+	}
+	assert.Equal(t, []string{"new-grad", "career-switcher", "executive", "academic"}, names)
+}
+
+func TestLookupBuiltIn(t *testing.T) {
+	r, ok := LookupBuiltIn(" New-Grad ")
+	require.True(t, ok)
+	assert.Equal(t, "new-grad", r.Name)
+
+	// Changing a looked-up recipe leaves the built-in alone
+	r.SectionOrder[0] = "experience"
+	r.RankingWeights.Recency = 1
+	again, _ := LookupBuiltIn("new-grad")
+	assert.Equal(t, "education", again.SectionOrder[0])
+	assert.NotEqual(t, 1.0, again.RankingWeights.Recency)
+
+	_, ok = LookupBuiltIn("astronaut")
+	assert.False(t, ok)
+}
@@ -0,0 +1,34 @@
+package redact
+
+import "regexp"
+
+// TokenPlaceholder replaces bearer tokens and JSON Web Tokens
+const TokenPlaceholder = "[REDACTED_TOKEN]"
+
+var (
+	// urlCredentialsPattern matches the password in a URL such as a database connection string
+	urlCredentialsPattern = regexp.MustCompile(`([A-Za-z][A-Za-z0-9+.\-]*://[^:/@\s]+:)[^@\s]+@`)
+	bearerPattern         = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/\-]+=*`)
+	jwtPattern            = regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+	// secretParamPattern matches a secret passed as key=value, as in a DSN or query string
+	secretParamPattern = regexp.MustCompile(`(?i)\b(password|passwd|pwd|secret|token|api_?key)=[^\s&;,'"]+`)
+	// postgresKeyPattern matches the key values Postgres echoes in constraint violations:
+	// Key (email)=(jane@example.com) already exists
+	postgresKeyPattern = regexp.MustCompile(`(Key \([^)]*\)=\()[^)]*(\))`)
+)
+
+// Error scrubs an error message before it is logged or returned to a client: credentials
+// in connection strings and key=value pairs, bearer tokens, JSON Web Tokens, the values
+// Postgres echoes from violated constraints, and email addresses. Unlike Text it leaves
+// long digit runs alone, so IDs in messages stay readable.
+func Error(message string) string {
+	if message == "" {
+		return message
+	}
+	message = urlCredentialsPattern.ReplaceAllString(message, "${1}"+LiteralPlaceholder+"@")
+	message = bearerPattern.ReplaceAllString(message, "Bearer "+TokenPlaceholder)
+	message = jwtPattern.ReplaceAllString(message, TokenPlaceholder)
+	message = secretParamPattern.ReplaceAllString(message, "${1}="+LiteralPlaceholder)
+	message = postgresKeyPattern.ReplaceAllString(message, "${1}"+LiteralPlaceholder+"${2}")
+	return emailPattern.ReplaceAllString(message, EmailPlaceholder)
+}
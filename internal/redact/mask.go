@@ -0,0 +1,236 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind identifies the category of a masked value
+type Kind string
+
+// Kinds of PII masked by a Masker
+const (
+	KindName    Kind = "name"
+	KindEmail   Kind = "email"
+	KindPhone   Kind = "phone"
+	KindAddress Kind = "address"
+)
+
+// addressPattern matches US-style street addresses such as "123 Main St, Apt 4"
+var addressPattern = regexp.MustCompile(`\b\d{1,6}\s+(?:[A-Z][A-Za-z0-9.'\-]*\s+){1,4}(?:Street|St|Avenue|Ave|Road|Rd|Boulevard|Blvd|Lane|Ln|Drive|Dr|Court|Ct|Way|Place|Pl|Terrace|Ter|Circle|Cir|Parkway|Pkwy|Highway|Hwy)\b\.?(?:,?\s*(?:Apt|Suite|Ste|Unit|#)\.?\s*[A-Za-z0-9\-]+)?`)
+
+// tokenPattern matches placeholders produced by Masker.Mask
+var tokenPattern = regexp.MustCompile(`\[PII_(?:NAME|EMAIL|PHONE|ADDRESS)_\d+\]`)
+
+// maskLiteral is a caller-supplied value masked by exact (case-insensitive) match
+type maskLiteral struct {
+	value   string
+	kind    Kind
+	pattern *regexp.Regexp
+}
+
+// Masker replaces PII with numbered placeholder tokens (e.g. "[PII_EMAIL_1]")
+// and restores the original values in generated text. Unlike Scrubber the
+// substitution is reversible, so prompts can be sent to an external provider
+// without the candidate's contact details and the response still reads naturally.
+// A Masker is safe for concurrent use.
+type Masker struct {
+	mu       sync.Mutex
+	literals []maskLiteral
+	tokens   map[string]string // token -> original value
+	byValue  map[string]string // lowercased value -> token
+	next     map[Kind]int
+	masked   map[Kind]int
+	calls    int
+	restored int
+
+	providers map[string]bool // provider -> external, for clients created with this Masker
+}
+
+// NewMasker creates a Masker for the candidate's name, email, and phone.
+// The full name is masked in any case. Each of its parts (3+ characters) is
+// masked only when capitalized, so a surname like "Will" or "Young" does not
+// swallow the ordinary word. Addresses, other emails, and other phone numbers
+// are detected by pattern.
+func NewMasker(name, email, phone string) *Masker {
+	m := &Masker{
+		tokens:    make(map[string]string),
+		byValue:   make(map[string]string),
+		next:      make(map[Kind]int),
+		masked:    make(map[Kind]int),
+		providers: make(map[string]bool),
+	}
+	add := func(value string, kind Kind, flags string) {
+		value = strings.TrimSpace(value)
+		if len(value) < 3 {
+			return
+		}
+		m.literals = append(m.literals, maskLiteral{
+			value:   value,
+			kind:    kind,
+			pattern: regexp.MustCompile(flags + literalBoundary(value)),
+		})
+	}
+	add(name, KindName, `(?i)`)
+	if parts := strings.Fields(name); len(parts) > 1 {
+		for _, part := range parts {
+			add(capitalize(strings.Trim(part, ".,")), KindName, "")
+		}
+	}
+	add(email, KindEmail, `(?i)`)
+	add(phone, KindPhone, `(?i)`)
+	// Replace longest literals first so "Jane Doe" wins over "Jane"
+	sort.SliceStable(m.literals, func(i, j int) bool {
+		return len(m.literals[i].value) > len(m.literals[j].value)
+	})
+	return m
+}
+
+// Mask returns text with all detected PII replaced by placeholder tokens.
+// The same value always maps to the same token for the lifetime of the Masker.
+func (m *Masker) Mask(text string) string {
+	if m == nil || text == "" {
+		return text
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+
+	// Emails go first so a name inside an email address is masked with the address
+	text = m.replacePattern(text, emailPattern, KindEmail)
+	for _, lit := range m.literals {
+		text = m.replacePattern(text, lit.pattern, lit.kind)
+	}
+	text = m.replacePattern(text, addressPattern, KindAddress)
+	text = m.replacePattern(text, phonePattern, KindPhone)
+	return text
+}
+
+// Restore replaces placeholder tokens in text with the values they masked.
+// Unknown tokens are left untouched.
+func (m *Masker) Restore(text string) string {
+	if m == nil || text == "" {
+		return text
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return tokenPattern.ReplaceAllStringFunc(text, func(token string) string {
+		original, ok := m.tokens[token]
+		if !ok {
+			return token
+		}
+		m.restored++
+		return original
+	})
+}
+
+// UseProvider records that an LLM client for provider was created with this Masker.
+// Prompts to local providers are not masked; the report is enabled only when
+// at least one external provider was used.
+func (m *Masker) UseProvider(provider string, local bool) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.providers[provider] = m.providers[provider] || !local
+}
+
+// Report summarizes what the Masker has done. It never includes the masked values.
+// Provider lists the providers recorded by UseProvider, if any.
+func (m *Masker) Report() Report {
+	report := Report{Enabled: true, Masked: map[Kind]int{}, Distinct: map[Kind]int{}}
+	if m == nil {
+		return report
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report.Calls = m.calls
+	report.Restored = m.restored
+	for kind, n := range m.masked {
+		report.Masked[kind] = n
+	}
+	for kind, n := range m.next {
+		report.Distinct[kind] = n
+	}
+	if len(m.providers) > 0 {
+		names := make([]string, 0, len(m.providers))
+		external := false
+		for provider, ext := range m.providers {
+			names = append(names, provider)
+			external = external || ext
+		}
+		sort.Strings(names)
+		report.Provider = strings.Join(names, ",")
+		report.Enabled = external
+	}
+	return report
+}
+
+// Report is the redaction summary stored as a run artifact
+type Report struct {
+	Enabled  bool         `json:"enabled"`
+	Provider string       `json:"provider,omitempty"`
+	Calls    int          `json:"calls"`    // Prompts passed through the masker
+	Masked   map[Kind]int `json:"masked"`   // Occurrences replaced, by kind
+	Distinct map[Kind]int `json:"distinct"` // Distinct values replaced, by kind
+	Restored int          `json:"restored"` // Tokens restored in responses
+}
+
+// tokenFor returns the stable token for value, allocating one if needed.
+// Callers must hold m.mu.
+func (m *Masker) tokenFor(value string, kind Kind) string {
+	key := strings.ToLower(value)
+	if token, ok := m.byValue[key]; ok {
+		return token
+	}
+	m.next[kind]++
+	token := fmt.Sprintf("[PII_%s_%d]", strings.ToUpper(string(kind)), m.next[kind])
+	m.byValue[key] = token
+	m.tokens[token] = value
+	return token
+}
+
+// replacePattern masks every match of re. Callers must hold m.mu.
+func (m *Masker) replacePattern(text string, re *regexp.Regexp, kind Kind) string {
+	return re.ReplaceAllStringFunc(text, func(match string) string {
+		if tokenPattern.MatchString(match) {
+			return match
+		}
+		m.masked[kind]++
+		return m.tokenFor(match, kind)
+	})
+}
+
+// literalBoundary quotes value and anchors it on word boundaries where the
+// value itself starts or ends with a word character, so "Ann" does not match "Annual"
+func literalBoundary(value string) string {
+	quoted := regexp.QuoteMeta(value)
+	if isWordByte(value[0]) {
+		quoted = `\b` + quoted
+	}
+	if isWordByte(value[len(value)-1]) {
+		quoted += `\b`
+	}
+	return quoted
+}
+
+// capitalize upper-cases the first letter of s
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
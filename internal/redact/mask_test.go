@@ -0,0 +1,84 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMasker_MaskAndRestore(t *testing.T) {
+	m := NewMasker("Jane Doe", "jane@example.com", "555-867-5309")
+
+	prompt := "Candidate: Jane Doe <jane@example.com>, 555-867-5309, 42 Elm Street, Apt 3. Jane led the team."
+	masked := m.Mask(prompt)
+
+	assert.NotContains(t, masked, "Jane")
+	assert.NotContains(t, masked, "jane@example.com")
+	assert.NotContains(t, masked, "867-5309")
+	assert.NotContains(t, masked, "Elm Street")
+	assert.Contains(t, masked, "[PII_NAME_1]")
+	assert.Contains(t, masked, "[PII_EMAIL_1]")
+	assert.Contains(t, masked, "[PII_PHONE_1]")
+	assert.Contains(t, masked, "[PII_ADDRESS_1]")
+
+	assert.Equal(t, prompt, m.Restore(masked))
+}
+
+func TestMasker_StableTokens(t *testing.T) {
+	m := NewMasker("Jane Doe", "", "")
+
+	first := m.Mask("Jane Doe")
+	second := m.Mask("jane doe wrote this")
+	assert.Equal(t, "[PII_NAME_1]", first)
+	assert.Equal(t, "[PII_NAME_1] wrote this", second)
+}
+
+func TestMasker_WordBoundaries(t *testing.T) {
+	m := NewMasker("Ann Lee", "", "")
+	assert.Equal(t, "Annual review", m.Mask("Annual review"))
+}
+
+func TestMasker_NamePartsAreCaseSensitive(t *testing.T) {
+	m := NewMasker("Mark Will", "", "")
+	assert.Equal(t, "[PII_NAME_1] will ship it", m.Mask("mark will will ship it"))
+	assert.Equal(t, "[PII_NAME_2] wrote the mark-up; we will review", m.Mask("Will wrote the mark-up; we will review"))
+}
+
+func TestMasker_ReportProviders(t *testing.T) {
+	m := NewMasker("Jane Doe", "", "")
+	m.UseProvider("ollama", true)
+	report := m.Report()
+	assert.False(t, report.Enabled)
+	assert.Equal(t, "ollama", report.Provider)
+
+	m.UseProvider("gemini", false)
+	report = m.Report()
+	assert.True(t, report.Enabled)
+	assert.Equal(t, "gemini,ollama", report.Provider)
+}
+
+func TestMasker_RestoreLeavesUnknownTokens(t *testing.T) {
+	m := NewMasker("Jane Doe", "", "")
+	assert.Equal(t, "[PII_NAME_9] stays", m.Restore("[PII_NAME_9] stays"))
+}
+
+func TestMasker_Report(t *testing.T) {
+	m := NewMasker("Jane Doe", "jane@example.com", "")
+	masked := m.Mask("Jane Doe, jane@example.com, other@example.com")
+	m.Restore(masked)
+
+	report := m.Report()
+	require.True(t, report.Enabled)
+	assert.Equal(t, 1, report.Calls)
+	assert.Equal(t, 1, report.Masked[KindName])
+	assert.Equal(t, 2, report.Masked[KindEmail])
+	assert.Equal(t, 2, report.Distinct[KindEmail])
+	assert.Equal(t, 3, report.Restored)
+}
+
+func TestMasker_Nil(t *testing.T) {
+	var m *Masker
+	assert.Equal(t, "Jane", m.Mask("Jane"))
+	assert.Equal(t, "[PII_NAME_1]", m.Restore("[PII_NAME_1]"))
+}
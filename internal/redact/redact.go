@@ -0,0 +1,101 @@
+// Package redact provides PII scrubbing for text that is persisted or sent to third parties.
+package redact
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Placeholder tokens substituted for redacted values
+const (
+	EmailPlaceholder   = "[REDACTED_EMAIL]"
+	PhonePlaceholder   = "[REDACTED_PHONE]"
+	SSNPlaceholder     = "[REDACTED_SSN]"
+	CardPlaceholder    = "[REDACTED_CARD]"
+	LiteralPlaceholder = "[REDACTED]"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	ssnPattern   = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	cardPattern  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,15}\b`)
+	phonePattern = regexp.MustCompile(`(?:\+?\d{1,2}[\s.\-]?)?\(?\d{3}\)?[\s.\-]?\d{3}[\s.\-]?\d{4}\b`)
+)
+
+// Scrubber removes personally identifiable information from text.
+// In addition to pattern-based detection (emails, phone numbers, SSNs, card numbers),
+// it redacts any caller-supplied literal values such as the candidate's name.
+type Scrubber struct {
+	literals []string
+}
+
+// New creates a Scrubber that also redacts the given literal values.
+// Empty and very short literals (fewer than 3 characters) are ignored to avoid
+// mangling unrelated text.
+func New(literals ...string) *Scrubber {
+	s := &Scrubber{}
+	for _, lit := range literals {
+		lit = strings.TrimSpace(lit)
+		if len(lit) < 3 {
+			continue
+		}
+		s.literals = append(s.literals, lit)
+	}
+	// Replace longest literals first so "Jane Doe" wins over "Jane"
+	sort.Slice(s.literals, func(i, j int) bool {
+		return len(s.literals[i]) > len(s.literals[j])
+	})
+	return s
+}
+
+// Scrub returns text with all detected PII replaced by placeholder tokens.
+func (s *Scrubber) Scrub(text string) string {
+	if text == "" {
+		return text
+	}
+
+	if s != nil {
+		for _, lit := range s.literals {
+			text = replaceFold(text, lit, LiteralPlaceholder)
+		}
+	}
+
+	text = emailPattern.ReplaceAllString(text, EmailPlaceholder)
+	text = ssnPattern.ReplaceAllString(text, SSNPlaceholder)
+	text = cardPattern.ReplaceAllString(text, CardPlaceholder)
+	text = phonePattern.ReplaceAllString(text, PhonePlaceholder)
+	return text
+}
+
+// Text scrubs text using pattern-based detection only.
+func Text(text string) string {
+	return (*Scrubber)(nil).Scrub(text)
+}
+
+// replaceFold replaces all case-insensitive occurrences of old in s with repl.
+func replaceFold(s, old, repl string) string {
+	if old == "" {
+		return s
+	}
+	lowerS := strings.ToLower(s)
+	lowerOld := strings.ToLower(old)
+	// Lowercasing can change byte lengths for some runes; fall back to exact match
+	if len(lowerS) != len(s) || len(lowerOld) != len(old) {
+		return strings.ReplaceAll(s, old, repl)
+	}
+
+	var b strings.Builder
+	start := 0
+	for {
+		idx := strings.Index(lowerS[start:], lowerOld)
+		if idx < 0 {
+			break
+		}
+		b.WriteString(s[start : start+idx])
+		b.WriteString(repl)
+		start += idx + len(old)
+	}
+	b.WriteString(s[start:])
+	return b.String()
+}
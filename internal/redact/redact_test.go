@@ -0,0 +1,72 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrub_Patterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "email",
+			input:    "Contact jane.doe+work@example.com for details",
+			expected: "Contact " + EmailPlaceholder + " for details",
+		},
+		{
+			name:     "us phone with dashes",
+			input:    "Call 555-123-4567 today",
+			expected: "Call " + PhonePlaceholder + " today",
+		},
+		{
+			name:     "phone with parentheses",
+			input:    "Phone: (555) 123-4567",
+			expected: "Phone: " + PhonePlaceholder,
+		},
+		{
+			name:     "ssn",
+			input:    "SSN 123-45-6789",
+			expected: "SSN " + SSNPlaceholder,
+		},
+		{
+			name:     "card number",
+			input:    "card 4111 1111 1111 1111 on file",
+			expected: "card " + CardPlaceholder + " on file",
+		},
+		{
+			name:     "no pii",
+			input:    "Reduced latency by 40% across 3 services",
+			expected: "Reduced latency by 40% across 3 services",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Text(tt.input))
+		})
+	}
+}
+
+func TestScrub_Literals(t *testing.T) {
+	s := New("Jane Doe", "Jane", "", "JD")
+
+	got := s.Scrub("Resume for JANE DOE. Jane led the team. JD is short.")
+
+	assert.NotContains(t, strings.ToLower(got), "jane")
+	assert.Contains(t, got, "JD is short", "literals under 3 chars should be ignored")
+	assert.Equal(t, 2, strings.Count(got, LiteralPlaceholder))
+}
+
+func TestScrub_Empty(t *testing.T) {
+	assert.Equal(t, "", New("Jane").Scrub(""))
+}
+
+func TestScrub_NilScrubber(t *testing.T) {
+	var s *Scrubber
+	assert.Equal(t, "mail "+EmailPlaceholder, s.Scrub("mail a@b.io"))
+}
@@ -0,0 +1,344 @@
+package rendering
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Output formats a run can store its resume in besides the LaTeX source
+const (
+	OutputFormatPDF  = "pdf"  // Compiled from the LaTeX (the default)
+	OutputFormatDOCX = "docx" // Word document, which many applicant tracking systems prefer
+)
+
+// OutputFormats lists the formats RunOptions.OutputFormat accepts
+var OutputFormats = []string{OutputFormatPDF, OutputFormatDOCX}
+
+// DOCXContentType is the media type of a Word document
+const DOCXContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// docxFonts maps each font family in FontFamilies to the closest font Word ships with
+var docxFonts = map[string]string{
+	"computer-modern": "Cambria",
+	"latin-modern":    "Cambria",
+	"helvetica":       "Arial",
+	"times":           "Times New Roman",
+	"palatino":        "Palatino Linotype",
+	"charter":         "Charter",
+}
+
+// docxMargins maps each margin preset in MarginPresets to twips (1/1440 inch)
+var docxMargins = map[string]int{
+	"narrow": 720,
+	"normal": 1080,
+	"wide":   1440,
+}
+
+// Page layout and defaults for DOCX resumes, in twips and half-points
+const (
+	docxPageWidth     = 12240 // US Letter
+	docxPageHeight    = 15840
+	docxDefaultFont   = "Calibri"
+	docxDefaultSize   = 22 // 11pt
+	docxDefaultMargin = "normal"
+)
+
+// docxSectionTitles are the headings of the sections a DOCX resume renders
+var docxSectionTitles = map[string]string{
+	"experience": "Experience",
+	"education":  "Education",
+}
+
+// plainFormat leaves text unescaped for formats that escape it themselves
+var plainFormat = textFormat{
+	escape:     func(s string) string { return s },
+	dateSep:    " – ",
+	markBullet: func(_, text string) string { return text },
+}
+
+// docxManifest declares the style options DOCX resumes support: all of them
+var docxManifest = TemplateManifest{
+	FontFamilies:  slices.Sorted(maps.Keys(docxFonts)),
+	FontSizes:     FontSizes,
+	MarginPresets: slices.Sorted(maps.Keys(docxMargins)),
+	AccentColor:   true,
+	Sections:      DefaultSections,
+}
+
+// RenderDOCX renders a Word resume from the same ResumePlan and RewrittenBullets as
+// RenderLaTeX, as one column of real text and bullet lists that applicant tracking
+// systems can parse. A nil style uses Calibri at 11pt with 0.75in margins.
+func RenderDOCX(plan *types.ResumePlan, rewrittenBullets *types.RewrittenBullets, style *Style, name, email, phone string, experienceBank *types.ExperienceBank, selectedEducation []types.Education) ([]byte, error) {
+	if err := docxManifest.Validate(style); err != nil {
+		return nil, &RenderError{Message: "unsupported style", Cause: err}
+	}
+	if rewrittenBullets == nil {
+		rewrittenBullets = &types.RewrittenBullets{}
+	}
+	companies, err := groupCompanies(plan, rewrittenBullets, experienceBank, plainFormat)
+	if err != nil {
+		return nil, &RenderError{Message: "failed to group experience", Cause: err}
+	}
+
+	d := newDOCXWriter(style)
+	d.header(name, email, phone)
+	for _, section := range docxManifest.SectionOrder(sectionOrder(style)) {
+		switch section {
+		case "experience":
+			if len(companies) == 0 {
+				continue
+			}
+			d.heading(docxSectionTitles[section])
+			for _, company := range companies {
+				d.paragraph(docxRun{text: company.Company, bold: true})
+				for _, role := range company.Roles {
+					d.datedParagraph(docxRun{text: role.Role, italic: true}, role.DateRanges)
+					for _, bullet := range role.Bullets {
+						d.bullet(bullet)
+					}
+				}
+			}
+		case "education":
+			education := educationSections(selectedEducation, plainFormat)
+			if len(education) == 0 {
+				continue
+			}
+			d.heading(docxSectionTitles[section])
+			for _, edu := range education {
+				d.datedParagraph(docxRun{text: edu.School, bold: true}, edu.DateRange)
+				if degree := degreeLine(edu); degree != "" {
+					d.paragraph(docxRun{text: degree})
+				}
+				for _, highlight := range edu.Highlights {
+					d.bullet(highlight)
+				}
+			}
+		}
+	}
+	return d.pack()
+}
+
+// sectionOrder returns the style's section order, if any
+func sectionOrder(style *Style) []string {
+	if style == nil {
+		return nil
+	}
+	return style.SectionOrder
+}
+
+// degreeLine formats an education entry's degree, field, and GPA on one line
+func degreeLine(edu EducationSection) string {
+	line := edu.Degree
+	if edu.Field != "" {
+		if line != "" {
+			line += " in "
+		}
+		line += edu.Field
+	}
+	if edu.GPA != "" {
+		if line != "" {
+			line += ", "
+		}
+		line += "GPA " + edu.GPA
+	}
+	return line
+}
+
+// docxRun is a run of text with one set of character formatting
+type docxRun struct {
+	text   string
+	bold   bool
+	italic bool
+	size   int    // Half-points; 0 uses the document default
+	color  string // Hex RGB without '#'; empty uses the document default
+}
+
+// docxWriter accumulates the body of word/document.xml
+type docxWriter struct {
+	body    strings.Builder
+	font    string
+	size    int
+	margin  int
+	accent  string
+	tabStop int // Right-aligned tab stop at the right margin, for dates
+}
+
+func newDOCXWriter(style *Style) *docxWriter {
+	d := &docxWriter{font: docxDefaultFont, size: docxDefaultSize, margin: docxMargins[docxDefaultMargin]}
+	if style != nil {
+		if font, ok := docxFonts[style.FontFamily]; ok {
+			d.font = font
+		}
+		if style.FontSize != "" {
+			var pt int
+			if _, err := fmt.Sscanf(style.FontSize, "%dpt", &pt); err == nil {
+				d.size = pt * 2
+			}
+		}
+		if margin, ok := docxMargins[style.MarginPreset]; ok {
+			d.margin = margin
+		}
+		if m := accentColorPattern.FindStringSubmatch(style.AccentColor); m != nil {
+			d.accent = strings.ToUpper(m[1])
+		}
+	}
+	d.tabStop = docxPageWidth - 2*d.margin
+	return d
+}
+
+// header writes the candidate's name and contact line, centered
+func (d *docxWriter) header(name, email, phone string) {
+	if name != "" {
+		d.write(`<w:p><w:pPr><w:jc w:val="center"/></w:pPr>`)
+		d.run(docxRun{text: name, bold: true, size: d.size + 12, color: d.accent})
+		d.write(`</w:p>`)
+	}
+	var contact []string
+	for _, s := range []string{email, phone} {
+		if s != "" {
+			contact = append(contact, s)
+		}
+	}
+	if len(contact) > 0 {
+		d.write(`<w:p><w:pPr><w:jc w:val="center"/></w:pPr>`)
+		d.run(docxRun{text: strings.Join(contact, " | ")})
+		d.write(`</w:p>`)
+	}
+}
+
+// heading writes a section heading with a rule beneath it
+func (d *docxWriter) heading(title string) {
+	color := d.accent
+	if color == "" {
+		color = "auto"
+	}
+	d.write(`<w:p><w:pPr><w:pStyle w:val="Heading1"/><w:pBdr><w:bottom w:val="single" w:sz="4" w:space="1" w:color="` + color + `"/></w:pBdr></w:pPr>`)
+	d.run(docxRun{text: strings.ToUpper(title), bold: true, size: d.size + 2, color: d.accent})
+	d.write(`</w:p>`)
+}
+
+// paragraph writes a paragraph of runs
+func (d *docxWriter) paragraph(runs ...docxRun) {
+	d.write(`<w:p>`)
+	for _, r := range runs {
+		d.run(r)
+	}
+	d.write(`</w:p>`)
+}
+
+// datedParagraph writes a run with a date right-aligned on the same line
+func (d *docxWriter) datedParagraph(r docxRun, date string) {
+	d.write(fmt.Sprintf(`<w:p><w:pPr><w:tabs><w:tab w:val="right" w:pos="%d"/></w:tabs></w:pPr>`, d.tabStop))
+	d.run(r)
+	if date != "" {
+		d.write(`<w:r><w:tab/></w:r>`)
+		d.run(docxRun{text: date})
+	}
+	d.write(`</w:p>`)
+}
+
+// bullet writes an item of the bulleted list defined in word/numbering.xml
+func (d *docxWriter) bullet(text string) {
+	d.write(`<w:p><w:pPr><w:pStyle w:val="ListBullet"/><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr>`)
+	d.run(docxRun{text: text})
+	d.write(`</w:p>`)
+}
+
+// run writes a run of escaped text with its formatting
+func (d *docxWriter) run(r docxRun) {
+	d.write(`<w:r>`)
+	if r.bold || r.italic || r.size != 0 || r.color != "" {
+		d.write(`<w:rPr>`)
+		if r.bold {
+			d.write(`<w:b/>`)
+		}
+		if r.italic {
+			d.write(`<w:i/>`)
+		}
+		if r.color != "" {
+			d.write(`<w:color w:val="` + r.color + `"/>`)
+		}
+		if r.size != 0 {
+			d.write(fmt.Sprintf(`<w:sz w:val="%d"/>`, r.size))
+		}
+		d.write(`</w:rPr>`)
+	}
+	d.write(`<w:t xml:space="preserve">`)
+	_ = xml.EscapeText(&d.body, []byte(r.text))
+	d.write(`</w:t></w:r>`)
+}
+
+func (d *docxWriter) write(s string) {
+	d.body.WriteString(s)
+}
+
+// pack zips the document with the parts Word needs to open it
+func (d *docxWriter) pack() ([]byte, error) {
+	document := xml.Header +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		d.body.String() +
+		fmt.Sprintf(`<w:sectPr><w:pgSz w:w="%d" w:h="%d"/><w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="0" w:footer="0" w:gutter="0"/></w:sectPr>`,
+			docxPageWidth, docxPageHeight, d.margin, d.margin, d.margin, d.margin) +
+		`</w:body></w:document>`
+
+	styles := xml.Header +
+		`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		fmt.Sprintf(`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="%[1]s" w:hAnsi="%[1]s" w:cs="%[1]s"/><w:sz w:val="%[2]d"/></w:rPr></w:rPrDefault>`, d.font, d.size) +
+		`<w:pPrDefault><w:pPr><w:spacing w:after="40" w:line="240" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+		`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="200" w:after="80"/><w:outlineLvl w:val="0"/></w:pPr></w:style>` +
+		`<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:basedOn w:val="Normal"/></w:style>` +
+		`</w:styles>`
+
+	numbering := xml.Header +
+		`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+		`<w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="•"/><w:lvlJc w:val="left"/><w:pPr><w:ind w:left="360" w:hanging="360"/></w:pPr></w:lvl></w:abstractNum>` +
+		`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>` +
+		`</w:numbering>`
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xml.Header +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+			`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+			`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", xml.Header +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+			`</Relationships>`},
+		{"word/_rels/document.xml.rels", xml.Header +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>` +
+			`</Relationships>`},
+		{"word/document.xml", document},
+		{"word/styles.xml", styles},
+		{"word/numbering.xml", numbering},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, &RenderError{Message: "failed to write " + part.name, Cause: err}
+		}
+		if _, err := f.Write([]byte(part.content)); err != nil {
+			return nil, &RenderError{Message: "failed to write " + part.name, Cause: err}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, &RenderError{Message: "failed to write document", Cause: err}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,117 @@
+package rendering
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// docxParts unzips a rendered document, checking that every part is well-formed XML
+func docxParts(t *testing.T, docx []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(docx), int64(len(docx)))
+	require.NoError(t, err)
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		_ = rc.Close()
+
+		dec := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			_, err := dec.Token()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err, "%s is not well-formed", f.Name)
+		}
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func TestRenderDOCX(t *testing.T) {
+	plan := &types.ResumePlan{
+		SelectedStories: []types.SelectedStory{
+			{StoryID: "story_001", BulletIDs: []string{"bullet_001", "bullet_002"}},
+		},
+	}
+	bullets := &types.RewrittenBullets{
+		Bullets: []types.RewrittenBullet{
+			{OriginalBulletID: "bullet_001", FinalText: "Cut p99 latency 40% for R&D <search> APIs"},
+			{OriginalBulletID: "bullet_002", FinalText: "Led migration to Kubernetes"},
+		},
+	}
+	bank := &types.ExperienceBank{
+		Stories: []types.Story{
+			{ID: "story_001", Company: "Acme Corp", Role: "Senior Engineer", StartDate: "2020-01", EndDate: "present"},
+		},
+	}
+	education := []types.Education{
+		{ID: "edu_001", School: "State University", Degree: "bachelor", Field: "Computer Science", EndDate: "2018-05"},
+	}
+
+	docx, err := RenderDOCX(plan, bullets, nil, "Jane Doe", "jane@example.com", "555-0100", bank, education)
+	require.NoError(t, err)
+	parts := docxParts(t, docx)
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/_rels/document.xml.rels", "word/document.xml", "word/styles.xml", "word/numbering.xml"} {
+		assert.Contains(t, parts, name)
+	}
+
+	doc := parts["word/document.xml"]
+	assert.Contains(t, doc, "Jane Doe")
+	assert.Contains(t, doc, "jane@example.com | 555-0100")
+	assert.Contains(t, doc, "Acme Corp")
+	assert.Contains(t, doc, "Senior Engineer")
+	assert.Contains(t, doc, "01-2020 – Present")
+	assert.Contains(t, doc, "Cut p99 latency 40% for R&amp;D &lt;search&gt; APIs", "text is XML-escaped, not LaTeX-escaped")
+	assert.NotContains(t, doc, "BULLET_START")
+	assert.Contains(t, doc, "Bachelor of Science in Computer Science")
+	assert.Equal(t, 2, strings.Count(doc, `<w:numId w:val="1"/>`), "two bullets and no highlights")
+	assert.Less(t, strings.Index(doc, "EXPERIENCE"), strings.Index(doc, "EDUCATION"))
+	assert.Contains(t, parts["word/styles.xml"], `w:ascii="Calibri"`)
+}
+
+func TestRenderDOCX_Style(t *testing.T) {
+	education := []types.Education{{ID: "edu_001", School: "State University", EndDate: "2018-05"}}
+	style := &Style{
+		FontFamily:   "times",
+		FontSize:     "12pt",
+		MarginPreset: "narrow",
+		AccentColor:  "#1f4e79",
+		SectionOrder: []string{"education"},
+	}
+
+	docx, err := RenderDOCX(&types.ResumePlan{}, nil, style, "Jane Doe", "", "", nil, education)
+	require.NoError(t, err)
+	parts := docxParts(t, docx)
+	doc := parts["word/document.xml"]
+	assert.Contains(t, doc, "EDUCATION")
+	assert.NotContains(t, doc, "EXPERIENCE", "sections without entries are left out")
+	assert.Contains(t, doc, `<w:color w:val="1F4E79"/>`)
+	assert.Contains(t, doc, `w:left="720"`)
+	assert.Contains(t, parts["word/styles.xml"], `w:ascii="Times New Roman"`)
+	assert.Contains(t, parts["word/styles.xml"], `<w:sz w:val="24"/>`)
+
+	_, err = RenderDOCX(&types.ResumePlan{}, nil, &Style{FontFamily: "comic-sans"}, "", "", "", nil, nil)
+	var renderErr *RenderError
+	assert.ErrorAs(t, err, &renderErr)
+}
+
+func TestDOCXStyleOptions(t *testing.T) {
+	assert.ElementsMatch(t, slices.Collect(maps.Keys(FontFamilies)), slices.Collect(maps.Keys(docxFonts)),
+		"every LaTeX font family has a Word font")
+	assert.ElementsMatch(t, slices.Collect(maps.Keys(MarginPresets)), slices.Collect(maps.Keys(docxMargins)))
+}
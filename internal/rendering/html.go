@@ -0,0 +1,302 @@
+package rendering
+
+import (
+	"html"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// inlineTags maps LaTeX text-style commands to the HTML elements that replace them
+var inlineTags = map[string]string{
+	"textbf":    "strong",
+	"textit":    "em",
+	"emph":      "em",
+	"texttt":    "code",
+	"underline": "u",
+}
+
+// textSymbols maps LaTeX symbol commands (as produced by EscapeLaTeX) to their characters
+var textSymbols = map[string]string{
+	"textbackslash":   `\`,
+	"textasciicircum": "^",
+	"textasciitilde":  "~",
+	"ldots":           "…",
+}
+
+// LaTeXToHTML converts a rendered resume (the document body of the resume template)
+// into an HTML fragment: sections become headings, itemize lists become <ul>, and
+// \hfill splits a line into left and right columns. Layout commands are dropped and
+// all text is HTML-escaped.
+func LaTeXToHTML(tex string) string {
+	body := tex
+	if i := strings.Index(body, `\begin{document}`); i >= 0 {
+		body = body[i+len(`\begin{document}`):]
+	}
+	if i := strings.Index(body, `\end{document}`); i >= 0 {
+		body = body[:i]
+	}
+
+	var out strings.Builder
+	var item strings.Builder
+	inList, inItem := false, false
+
+	flushItem := func() {
+		if inItem {
+			out.WriteString("<li>" + inlineHTML(strings.TrimSpace(item.String())) + "</li>\n")
+			item.Reset()
+			inItem = false
+		}
+	}
+
+	for _, raw := range strings.Split(body, "\n") {
+		line := strings.TrimSpace(stripComment(raw))
+		switch {
+		case line == "":
+			continue
+		case line == `\begin{itemize}`:
+			out.WriteString("<ul>\n")
+			inList = true
+		case line == `\end{itemize}`:
+			flushItem()
+			out.WriteString("</ul>\n")
+			inList = false
+		case inList && strings.HasPrefix(line, `\item`):
+			flushItem()
+			item.WriteString(strings.TrimPrefix(line, `\item`))
+			inItem = true
+		case inItem:
+			item.WriteString(" " + line)
+		case line == `\begin{center}`:
+			out.WriteString("<header>\n")
+		case line == `\end{center}`:
+			out.WriteString("</header>\n")
+		case strings.HasPrefix(line, `\section*{`) || strings.HasPrefix(line, `\section{`):
+			title := line[strings.Index(line, "{"):]
+			out.WriteString("<h2>" + inlineHTML(title) + "</h2>\n")
+		case strings.HasPrefix(line, `\vspace`), strings.HasPrefix(line, `\newpage`):
+			continue
+		default:
+			out.WriteString(lineHTML(line) + "\n")
+		}
+	}
+	flushItem()
+	return out.String()
+}
+
+// lineHTML renders a paragraph line, splitting it at \hfill into two columns
+func lineHTML(line string) string {
+	left, right, split := strings.Cut(line, `\hfill`)
+	if !split {
+		return "<p>" + inlineHTML(line) + "</p>"
+	}
+	return `<p class="row"><span>` + inlineHTML(strings.TrimSpace(left)) +
+		`</span><span class="right">` + inlineHTML(strings.TrimSpace(right)) + "</span></p>"
+}
+
+// stripComment removes an unescaped % comment from a line
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++ // Skip the escaped character
+		case '%':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// inlineHTML converts inline LaTeX (styles, groups, escapes) to escaped HTML
+func inlineHTML(s string) string {
+	var out strings.Builder
+	p := &inlineParser{src: s}
+	p.parse(&out, false)
+	return strings.TrimSpace(out.String())
+}
+
+// inlineParser is a small recursive-descent parser over inline LaTeX
+type inlineParser struct {
+	src string
+	pos int
+}
+
+// parse writes HTML until the end of input or, inside a group, the closing brace
+func (p *inlineParser) parse(out *strings.Builder, inGroup bool) {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '}' && inGroup:
+			p.pos++
+			return
+		case c == '{':
+			p.pos++
+			p.parse(out, true)
+		case c == '\\':
+			p.command(out)
+		case c == '~':
+			out.WriteString("&nbsp;")
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "---"):
+			out.WriteString("—")
+			p.pos += 3
+		case strings.HasPrefix(p.src[p.pos:], "--"):
+			out.WriteString("–")
+			p.pos += 2
+		default:
+			p.writeRune(out)
+		}
+	}
+}
+
+// command handles a backslash sequence at the current position
+func (p *inlineParser) command(out *strings.Builder) {
+	p.pos++ // Skip the backslash
+	if p.pos >= len(p.src) {
+		return
+	}
+
+	// Escaped special character or forced line break
+	if c := p.src[p.pos]; !isLetter(c) {
+		if c == '\\' {
+			p.pos++
+			p.skipOptionalArg()
+			out.WriteString("<br>")
+			return
+		}
+		p.writeRune(out)
+		return
+	}
+
+	start := p.pos
+	for p.pos < len(p.src) && isLetter(p.src[p.pos]) {
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+
+	if sym, ok := textSymbols[name]; ok {
+		p.skipEmptyGroup()
+		out.WriteString(html.EscapeString(sym))
+		return
+	}
+	if tag, ok := inlineTags[name]; ok {
+		out.WriteString("<" + tag + ">")
+		p.group(out)
+		out.WriteString("</" + tag + ">")
+		return
+	}
+	switch name {
+	case "href":
+		var target strings.Builder
+		p.rawGroup(&target)
+		if !safeLink(target.String()) {
+			p.group(out) // Keep the link text, drop the link
+			return
+		}
+		out.WriteString(`<a href="` + html.EscapeString(target.String()) + `">`)
+		p.group(out)
+		out.WriteString("</a>")
+	case "url":
+		var target strings.Builder
+		p.rawGroup(&target)
+		escaped := html.EscapeString(target.String())
+		if !safeLink(target.String()) {
+			out.WriteString(escaped)
+			return
+		}
+		out.WriteString(`<a href="` + escaped + `">` + escaped + "</a>")
+	case "color":
+		p.rawGroup(&strings.Builder{}) // The page has its own colors
+	case "textcolor":
+		p.rawGroup(&strings.Builder{})
+		p.group(out)
+	}
+	// Font sizes and other layout commands render as their text only
+}
+
+// safeLink reports whether a link target uses a scheme that is safe to render as an
+// anchor. Resume text comes from LLM rewrites of scraped content, so javascript: and
+// data: targets must never reach the page.
+func safeLink(target string) bool {
+	u, err := url.Parse(strings.TrimSpace(target))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto":
+		return true
+	}
+	return false
+}
+
+// group parses a following {...} argument as HTML, if present
+func (p *inlineParser) group(out *strings.Builder) {
+	p.skipSpaces()
+	if p.pos < len(p.src) && p.src[p.pos] == '{' {
+		p.pos++
+		p.parse(out, true)
+	}
+}
+
+// writeRune writes the character at the current position, escaped, and moves past it.
+// Multi-byte UTF-8 characters are written whole.
+func (p *inlineParser) writeRune(out *strings.Builder) {
+	_, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	out.WriteString(html.EscapeString(p.src[p.pos : p.pos+size]))
+	p.pos += size
+}
+
+// rawGroup copies a following {...} argument verbatim, if present
+func (p *inlineParser) rawGroup(out *strings.Builder) {
+	p.skipSpaces()
+	if p.pos >= len(p.src) || p.src[p.pos] != '{' {
+		return
+	}
+	depth := 0
+	for ; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case '{':
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case '}':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return
+			}
+		}
+		out.WriteByte(p.src[p.pos])
+	}
+}
+
+// skipEmptyGroup skips a {} terminating a symbol command
+func (p *inlineParser) skipEmptyGroup() {
+	if strings.HasPrefix(p.src[p.pos:], "{}") {
+		p.pos += 2
+	}
+}
+
+// skipOptionalArg skips a [..] argument such as the spacing after \\
+func (p *inlineParser) skipOptionalArg() {
+	if p.pos < len(p.src) && p.src[p.pos] == '[' {
+		if end := strings.IndexByte(p.src[p.pos:], ']'); end >= 0 {
+			p.pos += end + 1
+		}
+	}
+}
+
+// skipSpaces advances past spaces
+func (p *inlineParser) skipSpaces() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// isLetter reports whether c is an ASCII letter
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,94 @@
+package rendering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestLaTeXToHTML_Template(t *testing.T) {
+	plan := &types.ResumePlan{
+		SelectedStories: []types.SelectedStory{{StoryID: "story_001", BulletIDs: []string{"bullet_001", "bullet_002"}}},
+	}
+	rewritten := &types.RewrittenBullets{
+		Bullets: []types.RewrittenBullet{
+			{OriginalBulletID: "bullet_001", FinalText: "Cut p99 latency by 40% with R&D <fast> paths"},
+			{OriginalBulletID: "bullet_002", FinalText: "Owned the on_call rotation"},
+		},
+	}
+	bank := &types.ExperienceBank{
+		Stories: []types.Story{{ID: "story_001", Company: "Acme & Co", Role: "Senior Engineer", StartDate: "2020-01", EndDate: "present"}},
+	}
+	education := []types.Education{{School: "State University", Degree: "bachelor", Field: "Computer Science", EndDate: "2019-05"}}
+
+	tex, _, err := RenderLaTeX(plan, rewritten, "../../templates/one_page_resume.tex",
+		"Jane Doe", "jane@example.com", "555-1234", bank, education)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	out := LaTeXToHTML(tex)
+	assert.Contains(t, out, "<header>")
+	assert.Contains(t, out, "<strong>Jane Doe</strong>")
+	assert.Contains(t, out, "<code>jane@example.com</code>")
+	assert.Contains(t, out, "<h2>Experience</h2>")
+	assert.Contains(t, out, "<strong>Acme &amp; Co</strong>")
+	assert.Contains(t, out, `<p class="row"><span><em>Senior Engineer</em></span><span class="right">`)
+	assert.Contains(t, out, "<li>Cut p99 latency by 40% with R&amp;D &lt;fast&gt; paths</li>")
+	assert.Contains(t, out, "<li>Owned the on_call rotation</li>")
+	assert.Contains(t, out, "<h2>Education</h2>")
+	assert.NotContains(t, out, "BULLET_START")
+	assert.NotContains(t, out, `\`)
+	assert.NotContains(t, out, "documentclass")
+}
+
+func TestInlineHTML(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`\textbf{Bold} and \textit{italic}`, "<strong>Bold</strong> and <em>italic</em>"},
+		{`{\large\textbf{Acme}}`, "<strong>Acme</strong>"},
+		{`2019 -- 2021`, "2019 – 2021"},
+		{`C\textbackslash{}D \textasciitilde{}5 x\textasciicircum{}2`, `C\D ~5 x^2`},
+		{`\href{https://a.dev/?q=1&x=2}{site}`, `<a href="https://a.dev/?q=1&amp;x=2">site</a>`},
+		{`\href{mailto:jane@example.com}{email}`, `<a href="mailto:jane@example.com">email</a>`},
+		{`\href{javascript:alert(1)}{click}`, "click"},
+		{`\href{ JavaScript:alert(1)}{click}`, "click"},
+		{`\url{data:text/html,x}`, "data:text/html,x"},
+		{`\textcolor{accent}{Acme} {\color{accent}Lead}`, "Acme Lead"},
+		{`\url{https://a.dev}`, `<a href="https://a.dev">https://a.dev</a>`},
+		{`<script>`, "&lt;script&gt;"},
+		{`line\\[0.3cm]next`, "line<br>next"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, inlineHTML(tt.in), tt.in)
+	}
+}
+
+func TestInlineHTML_UTF8(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"José Müller – café", "José Müller – café"},
+		{`\textbf{Zoë Ångström} -- Köln`, "<strong>Zoë Ångström</strong> – Köln"},
+		{`Renée \& Søren`, "Renée &amp; Søren"},
+		{`\é`, "é"}, // Escaped non-ASCII character
+		{"naïve <b>", "naïve &lt;b&gt;"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, inlineHTML(tt.in), tt.in)
+	}
+
+	out := LaTeXToHTML("\\documentclass{article}\n\\begin{document}\nJosé Müller – café\n\\end{document}\n")
+	assert.Contains(t, out, "José Müller – café")
+	assert.NotContains(t, out, "Ã")
+}
+
+func TestStripComment(t *testing.T) {
+	assert.Equal(t, "50\\% off ", stripComment(`50\% off % trailing comment`))
+	assert.Equal(t, "", stripComment("% BULLET_START:b1"))
+}
@@ -19,6 +19,7 @@ type TemplateData struct {
 	Phone     string
 	Companies []CompanySection
 	Education []EducationSection
+	Sections  []string // Section names in the order the template should render them
 }
 
 // EducationSection represents a single education entry for the template
@@ -44,6 +45,24 @@ type RoleSection struct {
 	Bullets    []string
 }
 
+// textFormat is how grouped resume text is escaped and laid out for one output format
+type textFormat struct {
+	escape     func(string) string
+	dateSep    string                       // Separates the start and end of a date range
+	markBullet func(id, text string) string // Wraps bullet text with whatever maps it back to its ID
+}
+
+// latexFormat escapes text for LaTeX and marks bullets with comments for line mapping
+var latexFormat = textFormat{
+	escape:  EscapeLaTeX,
+	dateSep: " -- ",
+	// The template renders: \item {{ . }}, so we include comments in the bullet text
+	// Format: % BULLET_START:bullet_id\nactual text\n% BULLET_END:bullet_id
+	markBullet: func(id, text string) string {
+		return fmt.Sprintf("%% BULLET_START:%s\n%s\n%% BULLET_END:%s", id, text, id)
+	},
+}
+
 // dateRange represents a single date range for sorting
 type dateRange struct {
 	StartDate string
@@ -54,10 +73,32 @@ type dateRange struct {
 // Returns the LaTeX content and a line-to-bullet mapping for violation tracking.
 // This function is backwards compatible but now supports an optional education section.
 func RenderLaTeX(plan *types.ResumePlan, rewrittenBullets *types.RewrittenBullets, templatePath string, name, email, phone string, experienceBank *types.ExperienceBank, selectedEducation []types.Education) (string, *LineBulletMap, error) {
-	latex, err := RenderLaTeXWithEducation(plan, rewrittenBullets, templatePath, name, email, phone, experienceBank, selectedEducation)
+	return RenderStyledLaTeX(plan, rewrittenBullets, templatePath, nil, name, email, phone, experienceBank, selectedEducation)
+}
+
+// RenderStyledLaTeX renders like RenderLaTeX, then applies style after checking that
+// the template's manifest supports it. A nil style keeps the template's defaults.
+func RenderStyledLaTeX(plan *types.ResumePlan, rewrittenBullets *types.RewrittenBullets, templatePath string, style *Style, name, email, phone string, experienceBank *types.ExperienceBank, selectedEducation []types.Education) (string, *LineBulletMap, error) {
+	sections := DefaultSections
+	if !style.IsZero() {
+		manifest, err := LoadTemplateManifest(templatePath)
+		if err != nil {
+			return "", nil, err
+		}
+		if err := manifest.Validate(style); err != nil {
+			return "", nil, &TemplateError{Message: "unsupported style", Cause: err}
+		}
+		sections = manifest.SectionOrder(style.SectionOrder)
+	}
+	latex, err := renderLaTeX(plan, rewrittenBullets, templatePath, name, email, phone, experienceBank, selectedEducation, sections)
 	if err != nil {
 		return "", nil, err
 	}
+	// Styles add preamble lines, so they are applied before bullet lines are mapped
+	latex, err = ApplyStyle(latex, style)
+	if err != nil {
+		return "", nil, &RenderError{Message: "failed to apply style", Cause: err}
+	}
 	// Parse bullet markers to create line-to-bullet mapping
 	mapping := parseBulletMarkers(latex)
 	return latex, mapping, nil
@@ -112,6 +153,7 @@ func buildTemplateData(plan *types.ResumePlan, rewrittenBullets *types.Rewritten
 		Phone:     escapedPhone,
 		Companies: companies,
 		Education: nil, // Use RenderLaTeXWithEducation for education support
+		Sections:  DefaultSections,
 	}, nil
 }
 
@@ -123,6 +165,19 @@ func RenderLaTeXWithEducation(
 	name, email, phone string,
 	experienceBank *types.ExperienceBank,
 	selectedEducation []types.Education,
+) (string, error) {
+	return renderLaTeX(plan, rewrittenBullets, templatePath, name, email, phone, experienceBank, selectedEducation, DefaultSections)
+}
+
+// renderLaTeX renders the template with its sections in the given order
+func renderLaTeX(
+	plan *types.ResumePlan,
+	rewrittenBullets *types.RewrittenBullets,
+	templatePath string,
+	name, email, phone string,
+	experienceBank *types.ExperienceBank,
+	selectedEducation []types.Education,
+	sections []string,
 ) (string, error) {
 	// Read and parse template
 	tmpl, err := parseTemplate(templatePath)
@@ -141,6 +196,7 @@ func RenderLaTeXWithEducation(
 
 	// Add education data
 	data.Education = buildEducationSections(selectedEducation)
+	data.Sections = sections
 
 	// Execute template
 	var result strings.Builder
@@ -158,6 +214,11 @@ func RenderLaTeXWithEducation(
 
 // buildEducationSections converts Education types to EducationSection for template rendering
 func buildEducationSections(education []types.Education) []EducationSection {
+	return educationSections(education, latexFormat)
+}
+
+// educationSections converts Education types to EducationSections in the given format
+func educationSections(education []types.Education, format textFormat) []EducationSection {
 	if len(education) == 0 {
 		return nil
 	}
@@ -167,28 +228,28 @@ func buildEducationSections(education []types.Education) []EducationSection {
 		// Format date range
 		dateRange := ""
 		if edu.StartDate != "" && edu.EndDate != "" {
-			dateRange = formatDate(edu.StartDate) + " -- " + formatDate(edu.EndDate)
+			dateRange = formatDate(edu.StartDate) + format.dateSep + formatDate(edu.EndDate)
 		} else if edu.EndDate != "" {
 			dateRange = formatDate(edu.EndDate) // Just graduation date
 		} else if edu.StartDate != "" {
-			dateRange = formatDate(edu.StartDate) + " -- Present"
+			dateRange = formatDate(edu.StartDate) + format.dateSep + "Present"
 		}
 
 		// Format degree display
 		degreeDisplay := formatDegree(edu.Degree)
 
-		// Escape all text for LaTeX
+		// Escape all text for the output format
 		escapedHighlights := make([]string, len(edu.Highlights))
 		for j, h := range edu.Highlights {
-			escapedHighlights[j] = EscapeLaTeX(h)
+			escapedHighlights[j] = format.escape(h)
 		}
 
 		sections[i] = EducationSection{
-			School:     EscapeLaTeX(edu.School),
-			Degree:     EscapeLaTeX(degreeDisplay),
-			Field:      EscapeLaTeX(edu.Field),
-			DateRange:  EscapeLaTeX(dateRange),
-			GPA:        EscapeLaTeX(edu.GPA),
+			School:     format.escape(edu.School),
+			Degree:     format.escape(degreeDisplay),
+			Field:      format.escape(edu.Field),
+			DateRange:  format.escape(dateRange),
+			GPA:        format.escape(edu.GPA),
 			Highlights: escapedHighlights,
 		}
 	}
@@ -240,6 +301,11 @@ type LineBulletMap struct {
 
 // groupByCompanyAndRole groups bullets by Company, then by Role, merging date ranges
 func groupByCompanyAndRole(plan *types.ResumePlan, rewrittenBullets *types.RewrittenBullets, experienceBank *types.ExperienceBank) ([]CompanySection, error) {
+	return groupCompanies(plan, rewrittenBullets, experienceBank, latexFormat)
+}
+
+// groupCompanies groups bullets like groupByCompanyAndRole, with text in the given format
+func groupCompanies(plan *types.ResumePlan, rewrittenBullets *types.RewrittenBullets, experienceBank *types.ExperienceBank, format textFormat) ([]CompanySection, error) {
 	if plan == nil || len(plan.SelectedStories) == 0 {
 		return []CompanySection{}, nil
 	}
@@ -298,7 +364,7 @@ func groupByCompanyAndRole(plan *types.ResumePlan, rewrittenBullets *types.Rewri
 		for _, bulletID := range selectedStory.BulletIDs {
 			if bullet, ok := bulletMap[bulletID]; ok {
 				roleData[key] = append(roleData[key], bulletWithMeta{
-					Text:      EscapeLaTeX(bullet.FinalText),
+					Text:      format.escape(bullet.FinalText),
 					BulletID:  bulletID, // Track bullet ID
 					StartDate: story.StartDate,
 					EndDate:   story.EndDate,
@@ -325,7 +391,7 @@ func groupByCompanyAndRole(plan *types.ResumePlan, rewrittenBullets *types.Rewri
 			}
 
 			// Collect and merge date ranges
-			dateRanges := mergeDateRanges(bullets)
+			dateRanges := mergeDateRanges(bullets, format)
 
 			// Track the latest end date for this company
 			for _, b := range bullets {
@@ -334,26 +400,23 @@ func groupByCompanyAndRole(plan *types.ResumePlan, rewrittenBullets *types.Rewri
 				}
 			}
 
-			// Extract bullet texts with LaTeX comments for mapping
-			// The template renders: \item {{ . }}, so we include comments in the bullet text
-			// Format: % BULLET_START:bullet_id\nactual text\n% BULLET_END:bullet_id
+			// Extract bullet texts, marked for mapping back to their bullets
 			bulletTexts := make([]string, len(bullets))
 			for i, b := range bullets {
-				// Add LaTeX comments to mark bullet boundaries
-				bulletTexts[i] = fmt.Sprintf("%% BULLET_START:%s\n%s\n%% BULLET_END:%s", b.BulletID, b.Text, b.BulletID)
+				bulletTexts[i] = format.markBullet(b.BulletID, b.Text)
 			}
 
 			roles = append(roles, RoleSection{
-				Role:       EscapeLaTeX(roleName),
+				Role:       format.escape(roleName),
 				DateRanges: dateRanges,
 				Bullets:    bulletTexts,
 			})
 		}
 
-		companyEndDates[EscapeLaTeX(companyName)] = latestEndDate
+		companyEndDates[format.escape(companyName)] = latestEndDate
 
 		companies = append(companies, CompanySection{
-			Company: EscapeLaTeX(companyName),
+			Company: format.escape(companyName),
 			Roles:   roles,
 		})
 	}
@@ -384,7 +447,7 @@ func groupByCompanyAndRole(plan *types.ResumePlan, rewrittenBullets *types.Rewri
 }
 
 // mergeDateRanges collects unique date ranges from bullets, sorts them, and formats as comma-separated string
-func mergeDateRanges(bullets []bulletWithMeta) string {
+func mergeDateRanges(bullets []bulletWithMeta, format textFormat) string {
 	// Collect unique date ranges
 	seen := make(map[string]bool)
 	ranges := []dateRange{}
@@ -414,9 +477,9 @@ func mergeDateRanges(bullets []bulletWithMeta) string {
 	for _, r := range ranges {
 		var formatted string
 		if strings.ToLower(r.EndDate) == "present" {
-			formatted = EscapeLaTeX(formatDate(r.StartDate)) + " -- Present"
+			formatted = format.escape(formatDate(r.StartDate)) + format.dateSep + "Present"
 		} else {
-			formatted = EscapeLaTeX(formatDate(r.StartDate)) + " -- " + EscapeLaTeX(formatDate(r.EndDate))
+			formatted = format.escape(formatDate(r.StartDate)) + format.dateSep + format.escape(formatDate(r.EndDate))
 		}
 		// Dedupe on formatted string to catch any edge cases
 		if !seenFormatted[formatted] {
@@ -0,0 +1,488 @@
+// Package rendering provides functionality to render LaTeX resumes from templates.
+package rendering
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Template lint rules
+const (
+	LintRuleSyntax             = "template_syntax"
+	LintRuleStructure          = "document_structure"
+	LintRuleMissingPlaceholder = "missing_placeholder"
+	LintRuleUnknownPlaceholder = "unknown_placeholder"
+	LintRuleBannedCommand      = "banned_command"
+	LintRuleMissingPackage     = "missing_package"
+	LintRuleGeometry           = "page_geometry"
+)
+
+// LintFinding is one problem found in a resume template. Templates with error findings
+// must not be accepted; warnings are worth fixing but render.
+type LintFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"` // "error" or "warning"
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// PackageChecker reports whether a LaTeX package is installed. An error means the
+// check could not be made.
+type PackageChecker func(name string) (bool, error)
+
+// HasLintErrors reports whether any finding is an error
+func HasLintErrors(findings []LintFinding) bool {
+	for _, f := range findings {
+		if f.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredPlaceholders are the fields a template must render for the resume to hold
+// its experience; recommendedPlaceholders are dropped from the resume if unused.
+var (
+	requiredPlaceholders    = []string{"Name", "Companies", "Company", "Roles", "Role", "Bullets"}
+	recommendedPlaceholders = []string{"Email", "Phone", "DateRanges", "Education"}
+)
+
+// bannedCommands are TeX primitives that reach outside the document: shell escape,
+// file access on the server, Lua, and catcode changes that defeat escaping of user text
+var bannedCommands = map[string]string{
+	"write":             "writes files",
+	"immediate":         "writes files or runs shell commands",
+	"openout":           "writes files",
+	"openin":            "reads files on the server",
+	"read":              "reads files on the server",
+	"input":             "reads files on the server",
+	"include":           "reads files on the server",
+	"InputIfFileExists": "reads files on the server",
+	"directlua":         "runs Lua code",
+	"luaexec":           "runs Lua code",
+	"ShellEscape":       "runs shell commands",
+	"catcode":           "changes how user text is parsed, defeating escaping",
+}
+
+// bannedPackages are packages whose purpose is one of the banned commands
+var bannedPackages = map[string]string{
+	"shellesc": "runs shell commands",
+	"luacode":  "runs Lua code",
+}
+
+// commandPackages names the package that defines commonly used commands, for commands
+// a template uses without loading their package
+var commandPackages = map[string][]string{
+	"geometry":        {"geometry"},
+	"newgeometry":     {"geometry"},
+	"setlist":         {"enumitem"},
+	"href":            {"hyperref"},
+	"url":             {"hyperref", "url"},
+	"hypersetup":      {"hyperref"},
+	"textcolor":       {"xcolor", "color"},
+	"color":           {"xcolor", "color"},
+	"definecolor":     {"xcolor", "color"},
+	"includegraphics": {"graphicx", "graphics"},
+	"titleformat":     {"titlesec"},
+	"titlespacing":    {"titlesec"},
+	"faEnvelope":      {"fontawesome5", "fontawesome"},
+	"faPhone":         {"fontawesome5", "fontawesome"},
+	"faLinkedin":      {"fontawesome5", "fontawesome"},
+	"faGithub":        {"fontawesome5", "fontawesome"},
+}
+
+var (
+	commandPattern       = regexp.MustCompile(`\\([A-Za-z]+)`)
+	packagePattern       = regexp.MustCompile(`\\(?:usepackage|RequirePackage)\s*(?:\[([^\]]*)\])?\s*\{([^}]*)\}`)
+	documentClassPattern = regexp.MustCompile(`\\documentclass\s*(?:\[([^\]]*)\])?\s*\{([^}]*)\}`)
+	geometryPattern      = regexp.MustCompile(`\\(?:geometry|newgeometry)\s*\{([^}]*)\}`)
+	lengthPattern        = regexp.MustCompile(`^(-?[0-9]*\.?[0-9]+)\s*(in|cm|mm|pt|bp)$`)
+	templateLinePattern  = regexp.MustCompile(`:(\d+):`)
+)
+
+// Paper sizes in inches, width by height
+var paperSizes = map[string][2]float64{
+	"letterpaper": {8.5, 11},
+	"a4paper":     {8.27, 11.69},
+	"legalpaper":  {8.5, 14},
+	"a5paper":     {5.83, 8.27},
+}
+
+// LintTemplate checks a resume template before it is accepted: that it parses and
+// renders the required placeholders, uses no commands that reach outside the document,
+// loads the packages its commands need, and lays out a sane page. Installed packages
+// are checked with installed when it is non-nil. Findings are ordered by line.
+func LintTemplate(content string, installed PackageChecker) []LintFinding {
+	var findings []LintFinding
+	add := func(rule, severity string, line int, format string, args ...any) {
+		findings = append(findings, LintFinding{Rule: rule, Severity: severity, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	findings = append(findings, lintPlaceholders(content)...)
+
+	// The LaTeX checks ignore comments so commented-out commands are not flagged
+	tex := stripLaTeXComments(content)
+	lineAt := func(offset int) int {
+		return strings.Count(tex[:offset], "\n") + 1
+	}
+
+	if !strings.Contains(tex, `\begin{document}`) || !strings.Contains(tex, `\end{document}`) {
+		add(LintRuleStructure, "error", 0, `Template must contain \begin{document} and \end{document}`)
+	}
+	class := documentClassPattern.FindStringSubmatchIndex(tex)
+	if class == nil {
+		add(LintRuleStructure, "error", 0, `Template must start with \documentclass`)
+	}
+
+	for _, m := range commandPattern.FindAllStringSubmatchIndex(tex, -1) {
+		name := tex[m[2]:m[3]]
+		if name == "write" && strings.HasPrefix(tex[m[3]:], "18") {
+			// Stream 18 is the shell
+			add(LintRuleBannedCommand, "error", lineAt(m[0]), `\write18 is not allowed in templates: it runs shell commands`)
+			continue
+		}
+		if reason, ok := bannedCommands[name]; ok {
+			add(LintRuleBannedCommand, "error", lineAt(m[0]), `\%s is not allowed in templates: it %s`, name, reason)
+		}
+	}
+
+	// Packages loaded by the template, with the line of each
+	loaded := map[string]int{}
+	var geometryOptions []string
+	var geometryLine int
+	for _, m := range packagePattern.FindAllStringSubmatchIndex(tex, -1) {
+		line := lineAt(m[0])
+		for _, name := range splitOptions(tex[m[4]:m[5]]) {
+			if _, ok := loaded[name]; !ok {
+				loaded[name] = line
+			}
+			if reason, ok := bannedPackages[name]; ok {
+				add(LintRuleBannedCommand, "error", line, "Package %s is not allowed in templates: it %s", name, reason)
+			}
+			if name == "geometry" && m[2] >= 0 {
+				geometryOptions = append(geometryOptions, splitOptions(tex[m[2]:m[3]])...)
+				geometryLine = line
+			}
+		}
+	}
+
+	// Commands whose package is not loaded fail to compile
+	reported := map[string]bool{}
+	for _, m := range commandPattern.FindAllStringSubmatchIndex(tex, -1) {
+		name := tex[m[2]:m[3]]
+		packages := commandPackages[name]
+		if len(packages) == 0 || reported[name] || anyLoaded(loaded, packages) {
+			continue
+		}
+		reported[name] = true
+		add(LintRuleMissingPackage, "error", lineAt(m[0]), `\%s needs \usepackage{%s}`, name, packages[0])
+	}
+
+	if installed != nil {
+		names := make([]string, 0, len(loaded))
+		for name := range loaded {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ok, err := installed(name)
+			if err != nil {
+				// The check is unavailable here, e.g. no kpsewhich; skip it for every package
+				break
+			}
+			if !ok {
+				add(LintRuleMissingPackage, "error", loaded[name], "Package %s is not installed on the server", name)
+			}
+		}
+	}
+
+	for _, m := range geometryPattern.FindAllStringSubmatchIndex(tex, -1) {
+		geometryOptions = append(geometryOptions, splitOptions(tex[m[2]:m[3]])...)
+		geometryLine = lineAt(m[0])
+	}
+	var classOptions []string
+	if class != nil && class[2] >= 0 {
+		classOptions = splitOptions(tex[class[2]:class[3]])
+	}
+	findings = append(findings, lintGeometry(classOptions, geometryOptions, geometryLine, loaded)...)
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	return findings
+}
+
+// lintPlaceholders parses the template the way RenderLaTeX does and checks the fields
+// it references against the data templates are rendered with
+func lintPlaceholders(content string) []LintFinding {
+	tmpl, err := template.New("resume").Funcs(template.FuncMap{"escape": EscapeLaTeX}).Parse(content)
+	if err != nil {
+		line := 0
+		if m := templateLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		return []LintFinding{{Rule: LintRuleSyntax, Severity: "error", Line: line, Message: "Template does not parse: " + err.Error()}}
+	}
+
+	known := map[string]bool{}
+	templateFields(reflect.TypeOf(TemplateData{}), known)
+
+	var findings []LintFinding
+	used := map[string]bool{}
+	walkTemplate(tmpl.Root, func(field string, pos parse.Pos) {
+		used[field] = true
+		if !known[field] {
+			findings = append(findings, LintFinding{
+				Rule:     LintRuleUnknownPlaceholder,
+				Severity: "error",
+				Line:     strings.Count(content[:pos], "\n") + 1,
+				Message:  fmt.Sprintf("{{ .%s }} is not a resume field; the template would fail to render", field),
+			})
+		}
+	})
+	for _, field := range requiredPlaceholders {
+		if !used[field] {
+			findings = append(findings, LintFinding{Rule: LintRuleMissingPlaceholder, Severity: "error",
+				Message: fmt.Sprintf("Template must render {{ .%s }}", field)})
+		}
+	}
+	for _, field := range recommendedPlaceholders {
+		if !used[field] {
+			findings = append(findings, LintFinding{Rule: LintRuleMissingPlaceholder, Severity: "warning",
+				Message: fmt.Sprintf("Template does not render {{ .%s }}, so resumes will leave it out", field)})
+		}
+	}
+	return findings
+}
+
+// templateFields adds the names of t's fields, and of the structs in its slices, to
+// known. Fields are not tied to the range they appear in, so a field used outside its
+// range is only caught when the template renders.
+func templateFields(t reflect.Type, known map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		known[f.Name] = true
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct {
+			templateFields(f.Type.Elem(), known)
+		}
+	}
+}
+
+// walkTemplate calls visit with every field referenced in the tree under node
+func walkTemplate(node parse.Node, visit func(field string, pos parse.Pos)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkTemplate(child, visit)
+		}
+	case *parse.ActionNode:
+		walkTemplate(n.Pipe, visit)
+	case *parse.IfNode:
+		walkBranch(&n.BranchNode, visit)
+	case *parse.RangeNode:
+		walkBranch(&n.BranchNode, visit)
+	case *parse.WithNode:
+		walkBranch(&n.BranchNode, visit)
+	case *parse.TemplateNode:
+		walkTemplate(n.Pipe, visit)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walkTemplate(cmd, visit)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			walkTemplate(arg, visit)
+		}
+	case *parse.FieldNode:
+		for _, ident := range n.Ident {
+			visit(ident, n.Pos)
+		}
+	case *parse.VariableNode:
+		// $.Companies reaches the top-level data from inside a range
+		for _, ident := range n.Ident[1:] {
+			visit(ident, n.Pos)
+		}
+	case *parse.ChainNode:
+		walkTemplate(n.Node, visit)
+		for _, field := range n.Field {
+			visit(field, n.Pos)
+		}
+	}
+}
+
+func walkBranch(n *parse.BranchNode, visit func(field string, pos parse.Pos)) {
+	walkTemplate(n.Pipe, visit)
+	walkTemplate(n.List, visit)
+	walkTemplate(n.ElseList, visit)
+}
+
+// lintGeometry checks the paper and margins the template sets. One-page resumes need
+// margins wide enough to print and narrow enough to leave room for content.
+func lintGeometry(classOptions, geometryOptions []string, line int, loaded map[string]int) []LintFinding {
+	var findings []LintFinding
+	add := func(severity, format string, args ...any) {
+		findings = append(findings, LintFinding{Rule: LintRuleGeometry, Severity: severity, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if _, ok := loaded["geometry"]; !ok {
+		add("warning", "Template does not set margins with the geometry package; the default margins leave little room on one page")
+		return findings
+	}
+
+	paper := "letterpaper"
+	margins := map[string]float64{}
+	for _, opt := range append(classOptions, geometryOptions...) {
+		key, value, hasValue := strings.Cut(opt, "=")
+		key = strings.TrimSpace(key)
+		switch {
+		case !hasValue && strings.HasSuffix(key, "paper"):
+			paper = key
+		case !hasValue && key == "landscape":
+			add("warning", "Landscape pages are unusual for resumes and are rejected by some applicant tracking systems")
+		case key == "paper" || key == "papername":
+			paper = strings.TrimSpace(value)
+		case hasValue:
+			length, err := parseLength(value)
+			if err != nil {
+				continue
+			}
+			for _, side := range marginSides(key) {
+				margins[side] = length
+			}
+		}
+	}
+
+	size, ok := paperSizes[paper]
+	if !ok {
+		add("warning", "Paper size %s is unusual; use letterpaper or a4paper", paper)
+		return findings
+	}
+	if paper != "letterpaper" && paper != "a4paper" {
+		add("warning", "Paper size %s is unusual; use letterpaper or a4paper", paper)
+	}
+
+	// geometry's defaults are proportional to the paper; these approximate them
+	left, right := marginOr(margins, "left", size[0]*0.15), marginOr(margins, "right", size[0]*0.15)
+	top, bottom := marginOr(margins, "top", size[1]*0.1), marginOr(margins, "bottom", size[1]*0.1)
+	for _, side := range []struct {
+		name  string
+		value float64
+	}{{"left", left}, {"right", right}, {"top", top}, {"bottom", bottom}} {
+		switch {
+		case side.value < 0:
+			add("error", "The %s margin is negative", side.name)
+		case side.value < 0.25:
+			add("warning", "The %s margin is %.2fin; printers clip content within 0.25in of the edge", side.name, side.value)
+		case side.value > 1.5:
+			add("warning", "The %s margin is %.2fin, leaving little room for a one-page resume", side.name, side.value)
+		}
+	}
+	if width := size[0] - left - right; width < 4 {
+		add("error", "The text is only %.2fin wide; margins must leave at least 4in", width)
+	}
+	if height := size[1] - top - bottom; height < 6 {
+		add("error", "The text is only %.2fin tall; margins must leave at least 6in", height)
+	}
+	return findings
+}
+
+// marginSides returns which margins a geometry key sets
+func marginSides(key string) []string {
+	switch key {
+	case "margin":
+		return []string{"left", "right", "top", "bottom"}
+	case "hmargin":
+		return []string{"left", "right"}
+	case "vmargin":
+		return []string{"top", "bottom"}
+	case "left", "lmargin", "inner":
+		return []string{"left"}
+	case "right", "rmargin", "outer":
+		return []string{"right"}
+	case "top", "tmargin":
+		return []string{"top"}
+	case "bottom", "bmargin":
+		return []string{"bottom"}
+	}
+	return nil
+}
+
+func marginOr(margins map[string]float64, side string, fallback float64) float64 {
+	if v, ok := margins[side]; ok {
+		return v
+	}
+	return fallback
+}
+
+// parseLength converts a TeX length such as 0.5in or 12mm to inches
+func parseLength(value string) (float64, error) {
+	m := lengthPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return 0, errors.New("not a plain length")
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	switch m[2] {
+	case "cm":
+		n /= 2.54
+	case "mm":
+		n /= 25.4
+	case "pt":
+		n /= 72.27
+	case "bp":
+		n /= 72
+	}
+	return n, nil
+}
+
+// splitOptions splits a comma-separated option or package list
+func splitOptions(list string) []string {
+	var opts []string
+	for _, opt := range strings.Split(list, ",") {
+		if opt = strings.TrimSpace(opt); opt != "" {
+			opts = append(opts, opt)
+		}
+	}
+	return opts
+}
+
+func anyLoaded(loaded map[string]int, packages []string) bool {
+	for _, name := range packages {
+		if _, ok := loaded[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// stripLaTeXComments blanks out comments, keeping line breaks so offsets map to the
+// same lines
+func stripLaTeXComments(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		for j := 0; j < len(line); j++ {
+			if line[j] == '\\' {
+				j++ // Skip the escaped character, e.g. \%
+				continue
+			}
+			if line[j] == '%' {
+				lines[i] = line[:j]
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
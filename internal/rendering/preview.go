@@ -0,0 +1,142 @@
+package rendering
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// PlanHTML renders a resume plan as an HTML fragment: the space budget and skill
+// coverage, then each selected story grouped by section with the text of its bullets.
+// Bullets are shown rewritten when bullets has them, otherwise by ID. All text is
+// HTML-escaped.
+func PlanHTML(plan *types.ResumePlan, bullets *types.RewrittenBullets) string {
+	text := map[string]string{}
+	if bullets != nil {
+		for _, b := range bullets.Bullets {
+			text[b.OriginalBulletID] = b.FinalText
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString(`<section class="plan">` + "\n")
+	fmt.Fprintf(&out, `<p class="budget">Budget: %d bullets, %d lines. Coverage %.0f%%`,
+		plan.SpaceBudget.MaxBullets, plan.SpaceBudget.MaxLines, plan.Coverage.CoverageScore*100)
+	if len(plan.Coverage.TopSkillsCovered) > 0 {
+		out.WriteString(": " + html.EscapeString(strings.Join(plan.Coverage.TopSkillsCovered, ", ")))
+	}
+	out.WriteString("</p>\n")
+
+	// Sections keep the order in which the plan first uses them
+	var sections []string
+	stories := map[string][]types.SelectedStory{}
+	for _, story := range plan.SelectedStories {
+		if _, seen := stories[story.Section]; !seen {
+			sections = append(sections, story.Section)
+		}
+		stories[story.Section] = append(stories[story.Section], story)
+	}
+
+	for _, section := range sections {
+		title := section
+		if title == "" {
+			title = "experience"
+		}
+		out.WriteString("<h3>" + html.EscapeString(title) + "</h3>\n")
+		for _, story := range stories[section] {
+			fmt.Fprintf(&out, `<div class="story"><h4>%s <span class="meta">~%d lines</span></h4>`+"\n<ul>\n",
+				html.EscapeString(story.StoryID), story.EstimatedLines)
+			for _, id := range story.BulletIDs {
+				if t, ok := text[id]; ok {
+					out.WriteString("<li>" + html.EscapeString(t) + "</li>\n")
+				} else {
+					out.WriteString(`<li class="pending">` + html.EscapeString(id) + "</li>\n")
+				}
+			}
+			out.WriteString("</ul></div>\n")
+		}
+	}
+	out.WriteString("</section>\n")
+	return out.String()
+}
+
+// BulletsHTML renders rewritten bullets as an HTML fragment listing each bullet with
+// its length, estimated lines, and which style checks it passed
+func BulletsHTML(bullets *types.RewrittenBullets) string {
+	var out strings.Builder
+	out.WriteString(`<ol class="bullets">` + "\n")
+	for _, b := range bullets.Bullets {
+		out.WriteString("<li><p>" + html.EscapeString(b.FinalText) + "</p>\n")
+		fmt.Fprintf(&out, `<p class="meta">%s, %d chars, %d lines`,
+			html.EscapeString(b.OriginalBulletID), b.LengthChars, b.EstimatedLines)
+		for _, check := range []struct {
+			name   string
+			passed bool
+		}{
+			{"strong verb", b.StyleChecks.StrongVerb},
+			{"quantified", b.StyleChecks.Quantified},
+			{"no taboo phrases", b.StyleChecks.NoTaboo},
+			{"target length", b.StyleChecks.TargetLength},
+		} {
+			class := "fail"
+			if check.passed {
+				class = "pass"
+			}
+			fmt.Fprintf(&out, ` <span class="check %s">%s</span>`, class, check.name)
+		}
+		out.WriteString("</p></li>\n")
+	}
+	out.WriteString("</ol>\n")
+	return out.String()
+}
+
+// HighlightLaTeX renders LaTeX source as an escaped <pre> block with spans marking
+// comments (tex-comment), commands (tex-command), braces and brackets (tex-group),
+// and math shifts (tex-math), for display with a stylesheet
+func HighlightLaTeX(tex string) string {
+	var out strings.Builder
+	out.WriteString(`<pre class="tex"><code>`)
+	span := func(class, s string) {
+		out.WriteString(`<span class="` + class + `">` + html.EscapeString(s) + "</span>")
+	}
+
+	for i := 0; i < len(tex); {
+		c := tex[i]
+		switch {
+		case c == '%':
+			end := strings.IndexByte(tex[i:], '\n')
+			if end < 0 {
+				end = len(tex) - i
+			}
+			span("tex-comment", tex[i:i+end])
+			i += end
+		case c == '\\':
+			j := i + 1
+			for j < len(tex) && isLetter(tex[j]) {
+				j++
+			}
+			if j == i+1 && j < len(tex) {
+				j++ // Control symbol such as \% or \\
+			}
+			span("tex-command", tex[i:j])
+			i = j
+		case c == '{' || c == '}' || c == '[' || c == ']':
+			span("tex-group", string(c))
+			i++
+		case c == '$':
+			span("tex-math", "$")
+			i++
+		default:
+			j := i + 1
+			for j < len(tex) && !strings.ContainsRune(`%\{}[]$`, rune(tex[j])) {
+				j++
+			}
+			out.WriteString(html.EscapeString(tex[i:j]))
+			i = j
+		}
+	}
+	out.WriteString("</code></pre>\n")
+	return out.String()
+}
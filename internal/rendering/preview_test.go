@@ -0,0 +1,57 @@
+package rendering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestPlanHTML(t *testing.T) {
+	plan := &types.ResumePlan{
+		SelectedStories: []types.SelectedStory{
+			{StoryID: "story_001", BulletIDs: []string{"bullet_001", "bullet_002"}, Section: "experience", EstimatedLines: 3},
+			{StoryID: "story_<2>", BulletIDs: []string{"bullet_003"}, Section: "projects", EstimatedLines: 1},
+		},
+		SpaceBudget: types.SpaceBudget{MaxBullets: 25, MaxLines: 35},
+		Coverage:    types.Coverage{TopSkillsCovered: []string{"Go", "C++"}, CoverageScore: 0.8},
+	}
+	bullets := &types.RewrittenBullets{Bullets: []types.RewrittenBullet{
+		{OriginalBulletID: "bullet_001", FinalText: "Cut latency by 40% with R&D"},
+	}}
+
+	out := PlanHTML(plan, bullets)
+	assert.Contains(t, out, "Budget: 25 bullets, 35 lines. Coverage 80%: Go, C++")
+	assert.Contains(t, out, "<h3>experience</h3>")
+	assert.Contains(t, out, "<h3>projects</h3>")
+	assert.Contains(t, out, "<li>Cut latency by 40% with R&amp;D</li>")
+	assert.Contains(t, out, `<li class="pending">bullet_002</li>`, "bullets not yet rewritten are shown by ID")
+	assert.Contains(t, out, "story_&lt;2&gt;")
+}
+
+func TestBulletsHTML(t *testing.T) {
+	out := BulletsHTML(&types.RewrittenBullets{Bullets: []types.RewrittenBullet{{
+		OriginalBulletID: "bullet_001",
+		FinalText:        "Shipped <script> safely",
+		LengthChars:      23,
+		EstimatedLines:   1,
+		StyleChecks:      types.StyleChecks{StrongVerb: true, NoTaboo: true},
+	}}})
+
+	assert.Contains(t, out, "<p>Shipped &lt;script&gt; safely</p>")
+	assert.Contains(t, out, "bullet_001, 23 chars, 1 lines")
+	assert.Contains(t, out, `<span class="check pass">strong verb</span>`)
+	assert.Contains(t, out, `<span class="check fail">quantified</span>`)
+}
+
+func TestHighlightLaTeX(t *testing.T) {
+	out := HighlightLaTeX("\\section*{R&D} 50\\% $x<y$ % note <b>\n\\\\[2pt]")
+
+	assert.Equal(t, `<pre class="tex"><code>`+
+		`<span class="tex-command">\section</span>*<span class="tex-group">{</span>R&amp;D<span class="tex-group">}</span> 50`+
+		`<span class="tex-command">\%</span> <span class="tex-math">$</span>x&lt;y<span class="tex-math">$</span> `+
+		`<span class="tex-comment">% note &lt;b&gt;</span>`+"\n"+
+		`<span class="tex-command">\\</span><span class="tex-group">[</span>2pt<span class="tex-group">]</span>`+
+		"</code></pre>\n", out)
+}
@@ -0,0 +1,285 @@
+// Package rendering provides functionality to render LaTeX resumes from templates.
+package rendering
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Style is a run's look-and-feel choices. Empty fields keep the template's defaults.
+type Style struct {
+	FontFamily   string `json:"font_family,omitempty"`   // See FontFamilies
+	FontSize     string `json:"font_size,omitempty"`     // 10pt, 11pt, or 12pt
+	MarginPreset string `json:"margin_preset,omitempty"` // narrow, normal, or wide
+	AccentColor  string `json:"accent_color,omitempty"`  // Hex color such as #1F4E79
+
+	// SectionOrder lists resume sections in the order they are rendered; sections it
+	// leaves out follow in the template's default order
+	SectionOrder []string `json:"section_order,omitempty"`
+}
+
+// IsZero reports whether the style keeps every template default
+func (s *Style) IsZero() bool {
+	return s == nil || (s.FontFamily == "" && s.FontSize == "" && s.MarginPreset == "" &&
+		s.AccentColor == "" && len(s.SectionOrder) == 0)
+}
+
+// TemplateManifest declares which style options a template supports. It is read from
+// a JSON file beside the template with the same base name, e.g. one_page_resume.json
+// for one_page_resume.tex. Templates without a manifest support no style options.
+type TemplateManifest struct {
+	Description     string `json:"description,omitempty"`
+	Columns         int    `json:"columns,omitempty"`            // Text columns per page; 0 means 1
+	MaxLinesPerPage int    `json:"max_lines_per_page,omitempty"` // Body lines a page fits; 0 means no limit is declared
+
+	FontFamilies  []string `json:"font_families,omitempty"`
+	FontSizes     []string `json:"font_sizes,omitempty"`
+	MarginPresets []string `json:"margin_presets,omitempty"`
+	AccentColor   bool     `json:"accent_color,omitempty"` // The template colors its headings with the xcolor color "accent"
+	Sections      []string `json:"sections,omitempty"`     // Sections the template renders in the order of {{ .Sections }}, in default order
+}
+
+// FontFamilies maps each font family a manifest may offer to the preamble that selects it
+var FontFamilies = map[string]string{
+	"computer-modern": "",
+	"latin-modern":    `\usepackage{lmodern}`,
+	"helvetica":       "\\usepackage[scaled]{helvet}\n\\renewcommand{\\familydefault}{\\sfdefault}",
+	"times":           `\usepackage{mathptmx}`,
+	"palatino":        `\usepackage{mathpazo}`,
+	"charter":         `\usepackage{charter}`,
+}
+
+// DefaultSections is the section order templates render when a run sets none
+var DefaultSections = []string{"experience", "education"}
+
+// FontSizes are the base font sizes a manifest may offer, as document class options
+var FontSizes = []string{"10pt", "11pt", "12pt"}
+
+// MarginPresets maps each margin preset a manifest may offer to its margin on every side
+var MarginPresets = map[string]string{
+	"narrow": "0.5in",
+	"normal": "0.75in",
+	"wide":   "1in",
+}
+
+var (
+	accentColorPattern  = regexp.MustCompile(`^#?([0-9A-Fa-f]{6})$`)
+	classOptionsPattern = regexp.MustCompile(`\\documentclass(\[[^\]]*\])?`)
+	fontSizeOptPattern  = regexp.MustCompile(`\b1[0-2]pt\b`)
+)
+
+// ManifestPath returns where the manifest of the template at templatePath is stored
+func ManifestPath(templatePath string) string {
+	return strings.TrimSuffix(templatePath, filepath.Ext(templatePath)) + ".json"
+}
+
+// LoadTemplateManifest reads the manifest of the template at templatePath. A template
+// without one gets an empty manifest.
+func LoadTemplateManifest(templatePath string) (*TemplateManifest, error) {
+	content, err := os.ReadFile(ManifestPath(templatePath))
+	if errors.Is(err, os.ErrNotExist) {
+		return &TemplateManifest{}, nil
+	}
+	if err != nil {
+		return nil, &TemplateError{Message: "failed to read template manifest", Cause: err}
+	}
+	var manifest TemplateManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, &TemplateError{Message: "failed to parse template manifest", Cause: err}
+	}
+	return &manifest, nil
+}
+
+// Validate checks that the template the manifest belongs to supports every option set
+// in style
+func (m *TemplateManifest) Validate(style *Style) error {
+	if style.IsZero() {
+		return nil
+	}
+	if err := checkOption("font_family", style.FontFamily, m.FontFamilies); err != nil {
+		return err
+	}
+	if err := checkOption("font_size", style.FontSize, m.FontSizes); err != nil {
+		return err
+	}
+	if err := checkOption("margin_preset", style.MarginPreset, m.MarginPresets); err != nil {
+		return err
+	}
+	if style.AccentColor != "" {
+		if !accentColorPattern.MatchString(style.AccentColor) {
+			return errors.New("accent_color must be a hex color such as #1F4E79")
+		}
+		if !m.AccentColor {
+			return errors.New("accent_color is not supported by this template")
+		}
+	}
+	if len(style.SectionOrder) > 0 {
+		if len(m.Sections) == 0 {
+			return errors.New("section_order is not supported by this template")
+		}
+		seen := make(map[string]bool)
+		for _, section := range style.SectionOrder {
+			if err := checkOption("section_order", section, m.Sections); err != nil {
+				return err
+			}
+			if seen[section] {
+				return fmt.Errorf("section_order lists %q more than once", section)
+			}
+			seen[section] = true
+		}
+	}
+	return nil
+}
+
+// maxTemplateColumns is the most text columns a template may declare
+const maxTemplateColumns = 3
+
+// Check verifies that a manifest only offers style options rendering can apply and
+// declares a sensible layout, before an uploaded template is accepted
+func (m *TemplateManifest) Check() error {
+	if m.Columns < 0 || m.Columns > maxTemplateColumns {
+		return fmt.Errorf("columns must be between 1 and %d", maxTemplateColumns)
+	}
+	if m.MaxLinesPerPage < 0 {
+		return errors.New("max_lines_per_page must not be negative")
+	}
+	for _, family := range m.FontFamilies {
+		if _, ok := FontFamilies[family]; !ok {
+			return fmt.Errorf("unknown font family %q", family)
+		}
+	}
+	for _, size := range m.FontSizes {
+		if !slices.Contains(FontSizes, size) {
+			return fmt.Errorf("unknown font size %q (supported: %s)", size, strings.Join(FontSizes, ", "))
+		}
+	}
+	for _, preset := range m.MarginPresets {
+		if _, ok := MarginPresets[preset]; !ok {
+			return fmt.Errorf("unknown margin preset %q", preset)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, section := range m.Sections {
+		if !slices.Contains(DefaultSections, section) {
+			return fmt.Errorf("unknown section %q (supported: %s)", section, strings.Join(DefaultSections, ", "))
+		}
+		if seen[section] {
+			return fmt.Errorf("sections lists %q more than once", section)
+		}
+		seen[section] = true
+	}
+	return nil
+}
+
+// SectionOrder returns every section the template renders, those in order first and
+// the rest in the template's default order. Templates whose manifest lists no sections
+// render DefaultSections.
+func (m *TemplateManifest) SectionOrder(order []string) []string {
+	if len(m.Sections) == 0 {
+		return DefaultSections
+	}
+	sections := make([]string, 0, len(m.Sections))
+	for _, section := range order {
+		if slices.Contains(m.Sections, section) && !slices.Contains(sections, section) {
+			sections = append(sections, section)
+		}
+	}
+	for _, section := range m.Sections {
+		if !slices.Contains(sections, section) {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+func checkOption(field, value string, supported []string) error {
+	if value == "" || slices.Contains(supported, value) {
+		return nil
+	}
+	if len(supported) == 0 {
+		return fmt.Errorf("%s is not supported by this template", field)
+	}
+	return fmt.Errorf("%s %q is not supported by this template (supported: %s)", field, value, strings.Join(supported, ", "))
+}
+
+// ValidateStyle checks style against the manifest of the template at templatePath
+func ValidateStyle(templatePath string, style *Style) error {
+	if style.IsZero() {
+		return nil
+	}
+	manifest, err := LoadTemplateManifest(templatePath)
+	if err != nil {
+		return err
+	}
+	return manifest.Validate(style)
+}
+
+// ApplyStyle applies style to a rendered resume: the font size replaces the document
+// class size option, and the font family, margins, and accent color are set at the end
+// of the preamble so they override the template's own settings.
+func ApplyStyle(latex string, style *Style) (string, error) {
+	if style.IsZero() {
+		return latex, nil
+	}
+
+	if style.FontSize != "" {
+		if !slices.Contains(FontSizes, style.FontSize) {
+			return "", fmt.Errorf("unknown font size %q", style.FontSize)
+		}
+		loc := classOptionsPattern.FindStringSubmatchIndex(latex)
+		if loc == nil {
+			return "", errors.New(`resume has no \documentclass to set the font size on`)
+		}
+		options := "[" + style.FontSize + "]"
+		if loc[2] >= 0 {
+			existing := latex[loc[2]:loc[3]]
+			if fontSizeOptPattern.MatchString(existing) {
+				options = fontSizeOptPattern.ReplaceAllString(existing, style.FontSize)
+			} else {
+				options = "[" + style.FontSize + "," + existing[1:]
+			}
+		}
+		latex = latex[:loc[0]] + `\documentclass` + options + latex[loc[1]:]
+	}
+
+	var preamble []string
+	if style.FontFamily != "" {
+		font, ok := FontFamilies[style.FontFamily]
+		if !ok {
+			return "", fmt.Errorf("unknown font family %q", style.FontFamily)
+		}
+		if font != "" {
+			preamble = append(preamble, font)
+		}
+	}
+	if style.MarginPreset != "" {
+		margin, ok := MarginPresets[style.MarginPreset]
+		if !ok {
+			return "", fmt.Errorf("unknown margin preset %q", style.MarginPreset)
+		}
+		preamble = append(preamble, `\geometry{margin=`+margin+`}`)
+	}
+	if style.AccentColor != "" {
+		m := accentColorPattern.FindStringSubmatch(style.AccentColor)
+		if m == nil {
+			return "", fmt.Errorf("invalid accent color %q", style.AccentColor)
+		}
+		preamble = append(preamble, `\definecolor{accent}{HTML}{`+strings.ToUpper(m[1])+`}`)
+	}
+	if len(preamble) == 0 {
+		return latex, nil
+	}
+
+	begin := strings.Index(latex, `\begin{document}`)
+	if begin < 0 {
+		return "", errors.New(`resume has no \begin{document} to apply the style before`)
+	}
+	block := "% Run style\n" + strings.Join(preamble, "\n") + "\n\n"
+	return latex[:begin] + block + latex[begin:], nil
+}
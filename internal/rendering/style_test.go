@@ -0,0 +1,132 @@
+package rendering
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+const bundledTemplate = "../../templates/one_page_resume.tex"
+
+func TestLoadTemplateManifest(t *testing.T) {
+	manifest, err := LoadTemplateManifest(bundledTemplate)
+	require.NoError(t, err)
+	assert.True(t, manifest.AccentColor)
+	for _, font := range manifest.FontFamilies {
+		assert.Contains(t, FontFamilies, font, "bundled manifest offers only known fonts")
+	}
+	for _, preset := range manifest.MarginPresets {
+		assert.Contains(t, MarginPresets, preset)
+	}
+	assert.Subset(t, FontSizes, manifest.FontSizes)
+
+	manifest, err = LoadTemplateManifest(t.TempDir() + "/custom.tex")
+	require.NoError(t, err)
+	assert.Equal(t, &TemplateManifest{}, manifest, "templates without a manifest support no options")
+}
+
+func TestTemplateManifest_Validate(t *testing.T) {
+	m := &TemplateManifest{FontFamilies: []string{"times"}, FontSizes: []string{"11pt"}, AccentColor: true}
+
+	assert.NoError(t, m.Validate(nil))
+	assert.NoError(t, m.Validate(&Style{FontFamily: "times", FontSize: "11pt", AccentColor: "#1f4e79"}))
+	assert.EqualError(t, m.Validate(&Style{FontFamily: "helvetica"}),
+		`font_family "helvetica" is not supported by this template (supported: times)`)
+	assert.EqualError(t, m.Validate(&Style{MarginPreset: "wide"}), "margin_preset is not supported by this template")
+	assert.EqualError(t, m.Validate(&Style{AccentColor: "blue"}), "accent_color must be a hex color such as #1F4E79")
+	assert.Error(t, (&TemplateManifest{}).Validate(&Style{AccentColor: "#000000"}))
+
+	m.Sections = []string{"experience", "education"}
+	assert.NoError(t, m.Validate(&Style{SectionOrder: []string{"education"}}))
+	assert.Error(t, m.Validate(&Style{SectionOrder: []string{"projects"}}))
+	assert.EqualError(t, m.Validate(&Style{SectionOrder: []string{"education", "education"}}),
+		`section_order lists "education" more than once`)
+	assert.EqualError(t, (&TemplateManifest{}).Validate(&Style{SectionOrder: []string{"education"}}),
+		"section_order is not supported by this template")
+}
+
+func TestTemplateManifest_Check(t *testing.T) {
+	assert.NoError(t, (&TemplateManifest{}).Check())
+	assert.NoError(t, (&TemplateManifest{
+		Columns: 2, MaxLinesPerPage: 50, FontFamilies: []string{"charter"}, FontSizes: []string{"10pt"},
+		MarginPresets: []string{"narrow"}, Sections: []string{"education", "experience"},
+	}).Check())
+
+	assert.EqualError(t, (&TemplateManifest{Columns: 4}).Check(), "columns must be between 1 and 3")
+	assert.Error(t, (&TemplateManifest{MaxLinesPerPage: -1}).Check())
+	assert.EqualError(t, (&TemplateManifest{FontFamilies: []string{"comic-sans"}}).Check(), `unknown font family "comic-sans"`)
+	assert.Error(t, (&TemplateManifest{FontSizes: []string{"9pt"}}).Check())
+	assert.Error(t, (&TemplateManifest{MarginPresets: []string{"none"}}).Check())
+	assert.Error(t, (&TemplateManifest{Sections: []string{"projects"}}).Check(), "no data renders a projects section")
+	assert.Error(t, (&TemplateManifest{Sections: []string{"education", "education"}}).Check())
+}
+
+func TestTemplateManifest_SectionOrder(t *testing.T) {
+	m := &TemplateManifest{Sections: []string{"experience", "projects", "education"}}
+	assert.Equal(t, []string{"education", "experience", "projects"}, m.SectionOrder([]string{"education"}))
+	assert.Equal(t, m.Sections, m.SectionOrder(nil))
+	assert.Equal(t, DefaultSections, (&TemplateManifest{}).SectionOrder([]string{"education"}))
+}
+
+func TestApplyStyle(t *testing.T) {
+	tex := "\\documentclass[11pt,a4paper]{article}\n\\usepackage{geometry}\n\\begin{document}\nHi\n\\end{document}"
+
+	out, err := ApplyStyle(tex, nil)
+	require.NoError(t, err)
+	assert.Equal(t, tex, out)
+
+	out, err = ApplyStyle(tex, &Style{FontFamily: "helvetica", FontSize: "10pt", MarginPreset: "wide", AccentColor: "1f4e79"})
+	require.NoError(t, err)
+	assert.Equal(t, "\\documentclass[10pt,a4paper]{article}\n\\usepackage{geometry}\n"+
+		"% Run style\n\\usepackage[scaled]{helvet}\n\\renewcommand{\\familydefault}{\\sfdefault}\n"+
+		"\\geometry{margin=1in}\n\\definecolor{accent}{HTML}{1F4E79}\n\n"+
+		"\\begin{document}\nHi\n\\end{document}", out)
+
+	out, err = ApplyStyle("\\documentclass{article}\n\\begin{document}\\end{document}", &Style{FontSize: "12pt"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "\\documentclass[12pt]{article}"))
+
+	out, err = ApplyStyle("\\documentclass[letterpaper]{article}", &Style{FontSize: "12pt"})
+	require.NoError(t, err)
+	assert.Equal(t, "\\documentclass[12pt,letterpaper]{article}", out)
+
+	_, err = ApplyStyle(tex, &Style{FontFamily: "comic-sans"})
+	assert.Error(t, err)
+}
+
+func TestRenderStyledLaTeX(t *testing.T) {
+	plan := &types.ResumePlan{SelectedStories: []types.SelectedStory{{StoryID: "story_001", BulletIDs: []string{"bullet_001"}}}}
+	bullets := &types.RewrittenBullets{Bullets: []types.RewrittenBullet{{OriginalBulletID: "bullet_001", FinalText: "Shipped it"}}}
+	bank := &types.ExperienceBank{Stories: []types.Story{{ID: "story_001", Company: "Acme", Role: "Engineer"}}}
+
+	plain, plainMap, err := RenderLaTeX(plan, bullets, bundledTemplate, "Jane", "jane@example.com", "", bank, nil)
+	require.NoError(t, err)
+	styled, styledMap, err := RenderStyledLaTeX(plan, bullets, bundledTemplate, &Style{FontFamily: "times", AccentColor: "#1F4E79"},
+		"Jane", "jane@example.com", "", bank, nil)
+	require.NoError(t, err)
+
+	assert.Contains(t, styled, "\\usepackage{mathptmx}\n\\definecolor{accent}{HTML}{1F4E79}")
+	added := strings.Count(styled, "\n") - strings.Count(plain, "\n")
+	require.NotEmpty(t, plainMap.BulletToLine["bullet_001"])
+	assert.Equal(t, plainMap.BulletToLine["bullet_001"][0]+added, styledMap.BulletToLine["bullet_001"][0],
+		"bullet lines are mapped after the style is applied")
+
+	_, _, err = RenderStyledLaTeX(plan, bullets, bundledTemplate, &Style{FontFamily: "comic-sans"}, "Jane", "", "", bank, nil)
+	var tmplErr *TemplateError
+	assert.True(t, errors.As(err, &tmplErr))
+
+	education := []types.Education{{School: "State University", Degree: "bachelor", Field: "CS"}}
+	plain, _, err = RenderLaTeX(plan, bullets, bundledTemplate, "Jane", "", "", bank, education)
+	require.NoError(t, err)
+	assert.Less(t, strings.Index(plain, "Experience}"), strings.Index(plain, "Education}"))
+	reordered, reorderedMap, err := RenderStyledLaTeX(plan, bullets, bundledTemplate, &Style{SectionOrder: []string{"education"}},
+		"Jane", "", "", bank, education)
+	require.NoError(t, err)
+	assert.Less(t, strings.Index(reordered, "Education}"), strings.Index(reordered, "Experience}"))
+	assert.NotEmpty(t, reorderedMap.BulletToLine["bullet_001"])
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/jonathan/resume-customizer/internal/rendering"
 	"github.com/jonathan/resume-customizer/internal/rewriting"
@@ -20,7 +21,7 @@ type CandidateInfo struct {
 }
 
 // RunRepairLoop runs the repair loop to fix violations iteratively
-func RunRepairLoop(ctx context.Context, initialPlan *types.ResumePlan, initialBullets *types.RewrittenBullets, violations *types.Violations, rankedStories *types.RankedStories, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, experienceBank *types.ExperienceBank, templatePath string, candidateInfo CandidateInfo, selectedEducation []types.Education, maxPages int, maxCharsPerLine int, maxIterations int, apiKey string) (finalPlan *types.ResumePlan, finalBullets *types.RewrittenBullets, finalLaTeX string, finalViolations *types.Violations, iterations int, err error) {
+func RunRepairLoop(ctx context.Context, initialPlan *types.ResumePlan, initialBullets *types.RewrittenBullets, violations *types.Violations, rankedStories *types.RankedStories, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, experienceBank *types.ExperienceBank, templatePath string, style *rendering.Style, candidateInfo CandidateInfo, selectedEducation []types.Education, maxPages int, maxCharsPerLine int, maxIterations int, apiKey string) (finalPlan *types.ResumePlan, finalBullets *types.RewrittenBullets, finalLaTeX string, finalViolations *types.Violations, iterations int, err error) {
 	// Initialize loop state
 	currentPlan := initialPlan
 	currentBullets := initialBullets
@@ -62,6 +63,13 @@ func RunRepairLoop(ctx context.Context, initialPlan *types.ResumePlan, initialBu
 			allBulletsToRewrite = append(allBulletsToRewrite, newBulletIDs...)
 		}
 
+		// Bullets with taboo phrases are always rewritten, whatever the proposed actions
+		for _, bulletID := range tabooBulletIDs(currentViolations, updatedBullets) {
+			if !slices.Contains(allBulletsToRewrite, bulletID) {
+				allBulletsToRewrite = append(allBulletsToRewrite, bulletID)
+			}
+		}
+
 		// Rewrite only if there are bullets to rewrite
 		if len(allBulletsToRewrite) > 0 {
 			rewritten, err := rewriting.RewriteBulletsSelective(
@@ -80,35 +88,10 @@ func RunRepairLoop(ctx context.Context, initialPlan *types.ResumePlan, initialBu
 		}
 		// If no bullets to rewrite and plan didn't change, use updatedBullets from ApplyRepairs (which may have dropped bullets)
 
-		// 5. Render LaTeX
-		latex, lineMap, err := rendering.RenderLaTeX(updatedPlan, updatedBullets, templatePath, candidateInfo.Name, candidateInfo.Email, candidateInfo.Phone, experienceBank, selectedEducation)
-		if err != nil {
-			return nil, nil, "", currentViolations, iterationsUsed, fmt.Errorf("failed to render LaTeX at iteration %d: %w", iterationsUsed, err)
-		}
-
-		// Write LaTeX to temporary file for validation
-		tempTexPath, err := writeTempLaTeX(latex)
-		if err != nil {
-			return nil, nil, "", currentViolations, iterationsUsed, fmt.Errorf("failed to write temporary LaTeX file at iteration %d: %w", iterationsUsed, err)
-		}
-		defer func() { _ = cleanupTempFile(tempTexPath) }()
-
-		// 6. Validate LaTeX with line-to-bullet mapping
-		var validationOpts *validation.Options
-		if lineMap != nil {
-			// Compute forbidden phrase mapping from updated bullets
-			forbiddenPhraseMap := rewriting.CheckForbiddenPhrasesInBullets(updatedBullets, companyProfile)
-
-			validationOpts = &validation.Options{
-				LineToBulletMap:    lineMap.LineToBullet,
-				Bullets:            updatedBullets,
-				Plan:               updatedPlan,
-				ForbiddenPhraseMap: forbiddenPhraseMap,
-			}
-		}
-		updatedViolations, err := validation.ValidateConstraints(tempTexPath, companyProfile, maxPages, maxCharsPerLine, validationOpts)
+		// 5-6. Render LaTeX and validate it with line-to-bullet mapping
+		latex, updatedViolations, err := renderAndValidate(updatedPlan, updatedBullets, companyProfile, experienceBank, templatePath, style, candidateInfo, selectedEducation, maxPages, maxCharsPerLine)
 		if err != nil {
-			return nil, nil, "", currentViolations, iterationsUsed, fmt.Errorf("failed to validate LaTeX at iteration %d: %w", iterationsUsed, err)
+			return nil, nil, "", currentViolations, iterationsUsed, fmt.Errorf("iteration %d: %w", iterationsUsed, err)
 		}
 
 		// Update state for next iteration
@@ -118,9 +101,91 @@ func RunRepairLoop(ctx context.Context, initialPlan *types.ResumePlan, initialBu
 		finalLaTeX = latex
 	}
 
+	// Taboo phrases never ship: bullets that still contain one once the iterations are
+	// spent are dropped rather than left for the user to catch
+	if drops := tabooDropActions(currentViolations, currentBullets); len(drops.Actions) > 0 {
+		updatedPlan, updatedBullets, _, err := ApplyRepairs(drops, currentPlan, currentBullets, rankedStories, experienceBank)
+		if err != nil {
+			return nil, nil, "", currentViolations, iterationsUsed, fmt.Errorf("failed to drop bullets with taboo phrases: %w", err)
+		}
+		latex, updatedViolations, err := renderAndValidate(updatedPlan, updatedBullets, companyProfile, experienceBank, templatePath, style, candidateInfo, selectedEducation, maxPages, maxCharsPerLine)
+		if err != nil {
+			return nil, nil, "", currentViolations, iterationsUsed, fmt.Errorf("after dropping bullets with taboo phrases: %w", err)
+		}
+		currentPlan, currentBullets, currentViolations, finalLaTeX = updatedPlan, updatedBullets, updatedViolations, latex
+	}
+
 	return currentPlan, currentBullets, finalLaTeX, currentViolations, iterationsUsed, nil
 }
 
+// renderAndValidate renders the plan and bullets to LaTeX and validates it, mapping
+// violations back to the bullets they came from
+func renderAndValidate(plan *types.ResumePlan, bullets *types.RewrittenBullets, companyProfile *types.CompanyProfile, experienceBank *types.ExperienceBank, templatePath string, style *rendering.Style, candidateInfo CandidateInfo, selectedEducation []types.Education, maxPages int, maxCharsPerLine int) (string, *types.Violations, error) {
+	latex, lineMap, err := rendering.RenderStyledLaTeX(plan, bullets, templatePath, style, candidateInfo.Name, candidateInfo.Email, candidateInfo.Phone, experienceBank, selectedEducation)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to render LaTeX: %w", err)
+	}
+
+	// Write LaTeX to temporary file for validation
+	tempTexPath, err := writeTempLaTeX(latex)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to write temporary LaTeX file: %w", err)
+	}
+	defer func() { _ = cleanupTempFile(tempTexPath) }()
+
+	var validationOpts *validation.Options
+	if lineMap != nil {
+		// Compute forbidden phrase mapping from updated bullets
+		forbiddenPhraseMap := rewriting.CheckForbiddenPhrasesInBullets(bullets, companyProfile)
+
+		validationOpts = &validation.Options{
+			LineToBulletMap:    lineMap.LineToBullet,
+			Bullets:            bullets,
+			Plan:               plan,
+			ForbiddenPhraseMap: forbiddenPhraseMap,
+		}
+	}
+	violations, err := validation.ValidateConstraints(tempTexPath, companyProfile, maxPages, maxCharsPerLine, validationOpts)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to validate LaTeX: %w", err)
+	}
+	return latex, violations, nil
+}
+
+// tabooBulletIDs returns the IDs of bullets still present in bullets that have a
+// forbidden phrase violation, in violation order
+func tabooBulletIDs(violations *types.Violations, bullets *types.RewrittenBullets) []string {
+	if violations == nil || bullets == nil {
+		return nil
+	}
+	present := make(map[string]bool, len(bullets.Bullets))
+	for _, bullet := range bullets.Bullets {
+		present[bullet.OriginalBulletID] = true
+	}
+
+	var ids []string
+	for _, v := range violations.Violations {
+		if v.Type != "forbidden_phrase" || v.BulletID == nil || !present[*v.BulletID] || slices.Contains(ids, *v.BulletID) {
+			continue
+		}
+		ids = append(ids, *v.BulletID)
+	}
+	return ids
+}
+
+// tabooDropActions returns drop_bullet actions for the bullets that still contain a taboo phrase
+func tabooDropActions(violations *types.Violations, bullets *types.RewrittenBullets) *types.RepairActions {
+	actions := &types.RepairActions{}
+	for _, bulletID := range tabooBulletIDs(violations, bullets) {
+		actions.Actions = append(actions.Actions, types.RepairAction{
+			Type:     "drop_bullet",
+			BulletID: bulletID,
+			Reason:   "still contains a taboo phrase after repair",
+		})
+	}
+	return actions
+}
+
 // plansDiffer checks if two plans are different
 func plansDiffer(plan1, plan2 *types.ResumePlan) bool {
 	if len(plan1.SelectedStories) != len(plan2.SelectedStories) {
@@ -195,3 +195,31 @@ func TestFindNewBulletIDs(t *testing.T) {
 		})
 	}
 }
+
+func TestTabooDropActions(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	violations := &types.Violations{
+		Violations: []types.Violation{
+			{Type: "forbidden_phrase", BulletID: strPtr("bullet_001")},
+			{Type: "forbidden_phrase", BulletID: strPtr("bullet_001")}, // A second phrase in the same bullet
+			{Type: "line_too_long", BulletID: strPtr("bullet_002")},
+			{Type: "forbidden_phrase", BulletID: strPtr("bullet_003")}, // Already dropped
+			{Type: "forbidden_phrase"},                                 // Outside any bullet
+		},
+	}
+	bullets := &types.RewrittenBullets{
+		Bullets: []types.RewrittenBullet{
+			{OriginalBulletID: "bullet_001"},
+			{OriginalBulletID: "bullet_002"},
+		},
+	}
+
+	assert.Equal(t, []string{"bullet_001"}, tabooBulletIDs(violations, bullets))
+
+	drops := tabooDropActions(violations, bullets)
+	assert.Len(t, drops.Actions, 1)
+	assert.Equal(t, "drop_bullet", drops.Actions[0].Type)
+	assert.Equal(t, "bullet_001", drops.Actions[0].BulletID)
+
+	assert.Empty(t, tabooDropActions(nil, bullets).Actions)
+}
@@ -15,7 +15,7 @@ import (
 
 // ProposeRepairs uses LLM to analyze violations and propose structured repair actions
 func ProposeRepairs(ctx context.Context, violations *types.Violations, plan *types.ResumePlan, rewrittenBullets *types.RewrittenBullets, rankedStories *types.RankedStories, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, apiKey string) (*types.RepairActions, error) {
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, &ProposeError{Message: "API key is required"}
 	}
 
@@ -209,6 +209,10 @@ func buildRepairPrompt(violations *types.Violations, plan *types.ResumePlan, rew
 		if len(companyProfile.TabooPhrases) > 0 {
 			sb.WriteString("Taboo Phrases to Avoid:\n")
 			for _, phrase := range companyProfile.TabooPhrases {
+				if reason := companyProfile.TabooReason(phrase); reason != "" {
+					sb.WriteString(fmt.Sprintf("- %s (%s)\n", phrase, reason))
+					continue
+				}
 				sb.WriteString(fmt.Sprintf("- %s\n", phrase))
 			}
 		}
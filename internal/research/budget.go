@@ -0,0 +1,91 @@
+// Package research - budget.go bounds how far and how much a research session crawls.
+package research
+
+import "github.com/jonathan/resume-customizer/internal/crawling"
+
+// Crawl stop reasons reported in CrawlUsage
+const (
+	StopFrontierExhausted = "frontier_exhausted"
+	StopMaxPages          = "max_pages"
+	StopMaxBytes          = "max_bytes"
+)
+
+// minFollowPriority is the lowest path priority a discovered link needs to be queued
+const minFollowPriority = 0.7
+
+// CrawlLimits bound the depth, scope, and size of a research session
+type CrawlLimits struct {
+	MaxPages       int   `json:"max_pages"`
+	MaxDepth       int   `json:"max_depth"`        // Link hops from a seed URL (0 = seeds only)
+	SameDomainOnly bool  `json:"same_domain_only"` // Only crawl the company's own domains
+	MaxBytes       int64 `json:"max_bytes"`        // Total HTML downloaded (0 = unlimited)
+	// ArchiveFallback reads dead or bot-blocked pages from their latest Internet Archive snapshot
+	ArchiveFallback bool `json:"archive_fallback,omitempty"`
+
+	// ProfileMaxAgeDays is how old a shared company profile may be and still be reused
+	// instead of crawling (0 = always crawl)
+	ProfileMaxAgeDays int `json:"profile_max_age_days,omitempty"`
+	// Refresh crawls even when a fresh shared profile exists, replacing it for later runs
+	Refresh bool `json:"refresh,omitempty"`
+	// Private keeps the run out of shared research: it neither reuses other runs' company
+	// profiles and crawl state nor publishes its own
+	Private bool `json:"private,omitempty"`
+}
+
+// CrawlUsage reports what a research session consumed against its limits
+type CrawlUsage struct {
+	Limits          CrawlLimits `json:"limits"`
+	PagesFetched    int         `json:"pages_fetched"`
+	BytesFetched    int64       `json:"bytes_fetched"`
+	MaxDepthReached int         `json:"max_depth_reached"`
+	LinksQueued     int         `json:"links_queued"`   // Links discovered on crawled pages and queued
+	OutOfScope      int         `json:"out_of_scope"`   // Frontier URLs skipped by SameDomainOnly
+	ArchivedPages   int         `json:"archived_pages"` // Pages read from Internet Archive snapshots
+	StopReason      string      `json:"stop_reason"`
+}
+
+// inScope reports whether url may be crawled under the session's scope limits
+func (s *Session) inScope(url string, limits CrawlLimits) bool {
+	if !limits.SameDomainOnly || len(s.CompanyDomains) == 0 || s.policy.Preferred(url) {
+		return true
+	}
+	return IsFromCompanyDomain(url, s.CompanyDomains)
+}
+
+// queueLinks adds high-value links found on a crawled page to the frontier,
+// one hop deeper than the page itself. It returns the number of links queued.
+func (s *Session) queueLinks(html string, from RankedURL, limits CrawlLimits, prior *Session) int {
+	if from.Depth >= limits.MaxDepth {
+		return 0
+	}
+	links, err := crawling.ExtractLinks(html, from.URL)
+	if err != nil {
+		return 0
+	}
+
+	queued := 0
+	for _, link := range links {
+		if s.knows(link) || (prior != nil && prior.knows(link)) || !s.inScope(link, limits) {
+			continue
+		}
+		if blocked, _ := s.policy.Blocked(link); blocked || (IsThirdParty(link) && !s.policy.Preferred(link)) {
+			continue
+		}
+		priority := AssignPathPriority(link)
+		if priority < minFollowPriority {
+			continue
+		}
+		s.Frontier = append(s.Frontier, RankedURL{
+			URL:      link,
+			Priority: priority,
+			Reason:   "linked from " + from.URL,
+			Type:     categorizePattern(link),
+			Depth:    from.Depth + 1,
+		})
+		queued++
+	}
+	if queued > 0 {
+		sortFrontierByPriority(s)
+	}
+	return queued
+}
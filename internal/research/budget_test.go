@@ -0,0 +1,67 @@
+package research
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_InScope(t *testing.T) {
+	s := &Session{CompanyDomains: []string{"acme.com"}}
+
+	scoped := CrawlLimits{SameDomainOnly: true}
+	assert.True(t, s.inScope("https://acme.com/values", scoped))
+	assert.True(t, s.inScope("https://blog.acme.com/culture", scoped))
+	assert.False(t, s.inScope("https://news.example.com/acme-culture", scoped))
+
+	assert.True(t, s.inScope("https://news.example.com/acme-culture", CrawlLimits{}))
+	assert.True(t, (&Session{}).inScope("https://news.example.com/acme-culture", scoped),
+		"without known company domains nothing is out of scope")
+}
+
+func TestSession_QueueLinks(t *testing.T) {
+	html := `<html><body>
+		<a href="/values">Our values</a>
+		<a href="/culture">Culture</a>
+		<a href="/pricing">Pricing</a>
+		<a href="/about">Crawled already</a>
+	</body></html>`
+	limits := CrawlLimits{MaxDepth: 1, SameDomainOnly: true}
+
+	s := &Session{
+		CompanyDomains: []string{"acme.com"},
+		CrawledURLs:    []string{"https://acme.com/about"},
+	}
+	queued := s.queueLinks(html, RankedURL{URL: "https://acme.com/about"}, limits, nil)
+
+	require.Equal(t, 2, queued)
+	require.Len(t, s.Frontier, 2)
+	assert.Equal(t, "https://acme.com/values", s.Frontier[0].URL, "frontier is sorted by priority")
+	assert.Equal(t, "https://acme.com/culture", s.Frontier[1].URL)
+	for _, ru := range s.Frontier {
+		assert.Equal(t, 1, ru.Depth)
+		assert.Equal(t, "linked from https://acme.com/about", ru.Reason)
+	}
+}
+
+func TestSession_QueueLinks_DepthLimit(t *testing.T) {
+	html := `<a href="/values">Our values</a>`
+	s := &Session{CompanyDomains: []string{"acme.com"}}
+
+	assert.Zero(t, s.queueLinks(html, RankedURL{URL: "https://acme.com/about"}, CrawlLimits{MaxDepth: 0}, nil))
+	assert.Zero(t, s.queueLinks(html, RankedURL{URL: "https://acme.com/about", Depth: 2}, CrawlLimits{MaxDepth: 2}, nil))
+	assert.Empty(t, s.Frontier)
+}
+
+func TestSession_QueueLinks_SkipsPriorURLs(t *testing.T) {
+	html := `<a href="/values">Our values</a><a href="/culture">Culture</a>`
+	prior := &Session{CrawledURLs: []string{"https://acme.com/values"}}
+	s := &Session{CompanyDomains: []string{"acme.com"}}
+
+	queued := s.queueLinks(html, RankedURL{URL: "https://acme.com/about"}, CrawlLimits{MaxDepth: 1}, prior)
+
+	assert.Equal(t, 1, queued)
+	require.Len(t, s.Frontier, 1)
+	assert.Equal(t, "https://acme.com/culture", s.Frontier[0].URL)
+}
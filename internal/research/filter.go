@@ -29,7 +29,7 @@ func IdentifyCompanyDomains(ctx context.Context, urls []string, companyName stri
 		return nil, nil
 	}
 
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, fmt.Errorf("API key required for domain identification")
 	}
 
@@ -198,7 +198,7 @@ func FilterLinks(ctx context.Context, links []string, companyName string, compan
 		return &FilterLinksResult{}, nil
 	}
 
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, fmt.Errorf("API key required for link filtering")
 	}
 
@@ -0,0 +1,127 @@
+// Package research - incremental.go lets a research session continue from a prior session.
+package research
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// DefaultRefreshAfter is how long a previously crawled page is reused before it is refetched
+const DefaultRefreshAfter = 7 * 24 * time.Hour
+
+// PageState records when a page was last processed and a hash of its extracted text
+type PageState struct {
+	Hash      string    `json:"hash"`
+	FetchedAt time.Time `json:"fetched_at"`
+	// Source is "archive" when the page was read from an Internet Archive snapshot
+	Source      string `json:"source,omitempty"`
+	SnapshotURL string `json:"snapshot_url,omitempty"`
+	// Reuse says how the session took the page from its prior session, if it did:
+	// ReuseCarried or ReuseUnchanged
+	Reuse string `json:"reuse,omitempty"`
+}
+
+// How a session reused a page crawled by its prior session
+const (
+	ReuseCarried   = "carried"   // Crawled recently enough not to be refetched
+	ReuseUnchanged = "unchanged" // Refetched, and its content had not changed
+)
+
+// IncrementalStats describes how much of a prior session a resumed session reused
+type IncrementalStats struct {
+	Resumed   bool `json:"resumed"`
+	Reused    int  `json:"reused"`    // Prior pages carried over without refetching
+	Unchanged int  `json:"unchanged"` // Refetched pages whose content had not changed
+	Changed   int  `json:"changed"`   // Refetched pages whose content changed
+	New       int  `json:"new"`       // Pages never crawled before
+}
+
+// hashText returns the SHA-256 of extracted page text for change detection
+func hashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// signalFor returns the brand signal extracted from url, if any
+func (s *Session) signalFor(url string) *BrandSignal {
+	for i := range s.BrandSignals {
+		if s.BrandSignals[i].URL == url {
+			return &s.BrandSignals[i]
+		}
+	}
+	return nil
+}
+
+// knows reports whether url was crawled, queued, or skipped by the session
+func (s *Session) knows(url string) bool {
+	if isInList(url, s.CrawledURLs) || isInList(url, s.Frontier) {
+		return true
+	}
+	for _, skipped := range s.SkippedURLs {
+		if skipped.URL == url {
+			return true
+		}
+	}
+	return false
+}
+
+// unseenURLs returns the urls the prior session never encountered
+func unseenURLs(prior *Session, urls []string) []string {
+	if prior == nil {
+		return urls
+	}
+	var unseen []string
+	for _, u := range urls {
+		if !prior.knows(u) {
+			unseen = append(unseen, u)
+		}
+	}
+	return unseen
+}
+
+// carryOver merges a prior session into session. Pages crawled within
+// refreshAfter are reused as-is; older pages are queued for a refetch so they
+// can be compared against their stored hash. The prior frontier is re-queued.
+func carryOver(session, prior *Session, refreshAfter time.Duration, now time.Time) {
+	if prior == nil {
+		return
+	}
+	if refreshAfter <= 0 {
+		refreshAfter = DefaultRefreshAfter
+	}
+	session.Incremental.Resumed = true
+
+	for _, u := range prior.CrawledURLs {
+		state, ok := prior.Pages[u]
+		if ok && now.Sub(state.FetchedAt) < refreshAfter {
+			session.CrawledURLs = append(session.CrawledURLs, u)
+			state.Reuse = ReuseCarried
+			session.Pages[u] = state
+			if sig := prior.signalFor(u); sig != nil {
+				session.BrandSignals = append(session.BrandSignals, *sig)
+			}
+			session.Incremental.Reused++
+			continue
+		}
+		if !isInList(u, session.Frontier) {
+			session.Frontier = append(session.Frontier, RankedURL{
+				URL:      u,
+				Priority: AssignPathPriority(u),
+				Reason:   "refresh previously crawled page",
+				Type:     categorizePattern(u),
+			})
+		}
+	}
+
+	for _, ru := range prior.Frontier {
+		if !isInList(ru.URL, session.Frontier) && !isInList(ru.URL, session.CrawledURLs) {
+			session.Frontier = append(session.Frontier, ru)
+		}
+	}
+	for _, skipped := range prior.SkippedURLs {
+		if !session.knows(skipped.URL) {
+			session.SkippedURLs = append(session.SkippedURLs, skipped)
+		}
+	}
+}
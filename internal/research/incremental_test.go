@@ -0,0 +1,65 @@
+package research
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnseenURLs(t *testing.T) {
+	prior := &Session{
+		CrawledURLs: []string{"https://acme.com/values"},
+		Frontier:    []RankedURL{{URL: "https://acme.com/culture"}},
+		SkippedURLs: []SkippedURL{{URL: "https://acme.com/p/chips"}},
+	}
+	seeds := []string{"https://acme.com/values", "https://acme.com/culture", "https://acme.com/p/chips", "https://acme.com/about"}
+
+	assert.Equal(t, []string{"https://acme.com/about"}, unseenURLs(prior, seeds))
+	assert.Equal(t, seeds, unseenURLs(nil, seeds))
+}
+
+func TestCarryOver(t *testing.T) {
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	prior := &Session{
+		CrawledURLs: []string{"https://acme.com/values", "https://acme.com/engineering"},
+		Frontier:    []RankedURL{{URL: "https://acme.com/careers", Priority: 0.6}},
+		SkippedURLs: []SkippedURL{{URL: "https://acme.com/forum", Reason: "moderation: user_generated"}},
+		BrandSignals: []BrandSignal{
+			{URL: "https://acme.com/values", KeyPoints: []string{"Own it"}},
+			{URL: "https://acme.com/engineering", KeyPoints: []string{"Ship daily"}},
+		},
+		Pages: map[string]PageState{
+			"https://acme.com/values":      {Hash: "a", FetchedAt: now.Add(-24 * time.Hour)},
+			"https://acme.com/engineering": {Hash: "b", FetchedAt: now.Add(-30 * 24 * time.Hour)},
+		},
+	}
+	session := &Session{Pages: map[string]PageState{}}
+
+	carryOver(session, prior, 0, now)
+
+	assert.True(t, session.Incremental.Resumed)
+	assert.Equal(t, 1, session.Incremental.Reused)
+	// Fresh page is reused with its signal
+	assert.Equal(t, []string{"https://acme.com/values"}, session.CrawledURLs)
+	require.Len(t, session.BrandSignals, 1)
+	assert.Equal(t, "Own it", session.BrandSignals[0].KeyPoints[0])
+	assert.Equal(t, ReuseCarried, session.Pages["https://acme.com/values"].Reuse)
+	// Stale page and the prior frontier are queued
+	assert.True(t, isInList("https://acme.com/engineering", session.Frontier))
+	assert.True(t, isInList("https://acme.com/careers", session.Frontier))
+	assert.True(t, session.knows("https://acme.com/forum"))
+}
+
+func TestCarryOver_NilPrior(t *testing.T) {
+	session := &Session{Pages: map[string]PageState{}}
+	carryOver(session, nil, 0, time.Now())
+	assert.False(t, session.Incremental.Resumed)
+	assert.Empty(t, session.Frontier)
+}
+
+func TestHashText(t *testing.T) {
+	assert.Equal(t, hashText("same"), hashText("same"))
+	assert.NotEqual(t, hashText("same"), hashText("changed"))
+}
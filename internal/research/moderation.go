@@ -0,0 +1,203 @@
+// Package research - moderation.go drops offensive or irrelevant pages from the voice corpus.
+package research
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/prompts"
+	"github.com/jonathan/resume-customizer/internal/validation"
+)
+
+// Moderation categories for dropped pages
+const (
+	ModerationOK            = "ok"
+	ModerationOffensive     = "offensive"
+	ModerationUserGenerated = "user_generated" // Forums, comment sections, Q&A threads
+	ModerationIrrelevant    = "irrelevant"
+)
+
+// ModerationStats counts crawled pages checked and dropped by the safety filter.
+// It is reported on the research session artifact.
+type ModerationStats struct {
+	Checked         int            `json:"checked"`
+	Dropped         int            `json:"dropped"`
+	ByCategory      map[string]int `json:"by_category,omitempty"`
+	ClassifierCalls int            `json:"classifier_calls"`
+}
+
+// record counts a verdict
+func (s *ModerationStats) record(v ModerationVerdict) {
+	s.Checked++
+	if v.Classified {
+		s.ClassifierCalls++
+	}
+	if v.Keep() {
+		return
+	}
+	s.Dropped++
+	if s.ByCategory == nil {
+		s.ByCategory = make(map[string]int)
+	}
+	s.ByCategory[v.Category]++
+}
+
+// ModerationVerdict is the outcome of moderating a single page
+type ModerationVerdict struct {
+	Category   string `json:"category"`
+	Reason     string `json:"reason"`
+	Classified bool   `json:"classified"` // The LLM classifier was consulted
+}
+
+// Keep reports whether the page may be added to the corpus
+func (v ModerationVerdict) Keep() bool {
+	return v.Category == ModerationOK
+}
+
+// Heuristic thresholds. Scores at or above dropScore are dropped outright;
+// scores between reviewScore and dropScore are sent to the classifier.
+const (
+	reviewScore = 1
+	dropScore   = 4
+)
+
+// userContentPathSegments are URL path segments that indicate user-generated content
+var userContentPathSegments = []string{
+	"forum", "forums", "community", "communities", "comments", "comment",
+	"discussion", "discussions", "discuss", "thread", "threads", "topic", "questions", "answers",
+}
+
+// userContentHosts are hosts whose pages are almost entirely user-generated
+var userContentHosts = []string{
+	"reddit.com", "news.ycombinator.com", "quora.com", "stackexchange.com",
+	"stackoverflow.com", "disqus.com", "glassdoor.com", "teamblind.com", "4chan.org",
+}
+
+// commentMarkerPattern matches lines typical of comment threads ("Reply", "Posted by", "3 points", ...)
+var commentMarkerPattern = regexp.MustCompile(`(?im)^\s*(?:reply|report|share|permalink|upvote|downvote|posted by|level \d+|\d+ (?:points?|upvotes?|replies|comments?)|\d+ (?:minutes?|hours?|days?) ago)\b`)
+
+// offensiveTerms is a short list of profanity and slurs used as a cheap first-pass signal
+var offensiveTerms = []string{
+	"fuck", "shit", "bitch", "bastard", "asshole", "dickhead", "cunt", "retard", "whore", "slut",
+}
+
+var offensivePattern = regexp.MustCompile(`(?i)\b(?:` + strings.Join(offensiveTerms, "|") + `)\w*\b`)
+
+// moderationHeuristics scores a page for user-generated or offensive content.
+// It returns the score and the category/reason of the strongest signal.
+func moderationHeuristics(pageURL, text string) (int, string, string) {
+	score := 0
+	category, reason := ModerationOK, ""
+	flag := func(points int, cat, why string) {
+		score += points
+		if category == ModerationOK {
+			category, reason = cat, why
+		}
+	}
+
+	if parsed, err := url.Parse(pageURL); err == nil {
+		host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+		for _, h := range userContentHosts {
+			if host == h || strings.HasSuffix(host, "."+h) {
+				flag(dropScore, ModerationUserGenerated, "user-generated content host "+h)
+				break
+			}
+		}
+		for _, seg := range strings.Split(strings.ToLower(parsed.Path), "/") {
+			if isInList(seg, userContentPathSegments) {
+				flag(2, ModerationUserGenerated, "forum or comment path /"+seg)
+				break
+			}
+		}
+	}
+
+	if markers := len(commentMarkerPattern.FindAllStringIndex(text, -1)); markers >= 10 {
+		flag(dropScore, ModerationUserGenerated, fmt.Sprintf("%d comment-thread markers", markers))
+	} else if markers >= 3 {
+		flag(1, ModerationUserGenerated, fmt.Sprintf("%d comment-thread markers", markers))
+	}
+
+	if hits := len(offensivePattern.FindAllStringIndex(text, -1)); hits >= 3 {
+		// Offensive language outranks a user-content signal in the reported reason
+		score += dropScore
+		category, reason = ModerationOffensive, fmt.Sprintf("%d offensive terms", hits)
+	} else if hits > 0 {
+		flag(1, ModerationOffensive, fmt.Sprintf("%d offensive terms", hits))
+	}
+
+	return score, category, reason
+}
+
+// ModerateContent decides whether a crawled page belongs in the company voice corpus.
+// Cheap heuristics run first; only borderline pages are sent to the lite-tier classifier.
+// If the classifier fails, borderline pages are kept since the heuristics were inconclusive.
+func ModerateContent(ctx context.Context, pageText, pageURL, company, apiKey string) ModerationVerdict {
+	score, category, reason := moderationHeuristics(pageURL, pageText)
+	if score >= dropScore {
+		return ModerationVerdict{Category: category, Reason: reason}
+	}
+	if score < reviewScore {
+		return ModerationVerdict{Category: ModerationOK}
+	}
+
+	verdict, err := classifyContent(ctx, pageText, pageURL, company, apiKey)
+	if err != nil {
+		return ModerationVerdict{Category: ModerationOK, Reason: "classifier unavailable: " + err.Error(), Classified: true}
+	}
+	return verdict
+}
+
+// classifyResponse is the expected JSON response from the moderation classifier
+type classifyResponse struct {
+	Category string `json:"category"`
+	Reason   string `json:"reason"`
+}
+
+// classifyContent asks the lite-tier model whether the page is safe and relevant
+func classifyContent(ctx context.Context, pageText, pageURL, company, apiKey string) (ModerationVerdict, error) {
+	if apiKey == "" && llm.APIKeyRequired() {
+		return ModerationVerdict{}, fmt.Errorf("API key required for content moderation")
+	}
+
+	client, err := llm.NewClient(ctx, llm.DefaultConfig(), apiKey)
+	if err != nil {
+		return ModerationVerdict{}, fmt.Errorf("failed to create LLM client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	jsonResp, err := client.GenerateJSON(ctx, buildModerationPrompt(pageText, pageURL, company), llm.TierLite)
+	if err != nil {
+		return ModerationVerdict{}, fmt.Errorf("LLM generation failed: %w", err)
+	}
+
+	var resp classifyResponse
+	if err := json.Unmarshal([]byte(llm.CleanJSONBlock(jsonResp)), &resp); err != nil {
+		return ModerationVerdict{}, fmt.Errorf("failed to parse moderation response: %w", err)
+	}
+
+	switch resp.Category {
+	case ModerationOK, ModerationOffensive, ModerationUserGenerated, ModerationIrrelevant:
+	default:
+		return ModerationVerdict{}, fmt.Errorf("unknown moderation category %q", resp.Category)
+	}
+	return ModerationVerdict{Category: resp.Category, Reason: resp.Reason, Classified: true}, nil
+}
+
+func buildModerationPrompt(pageText, pageURL, company string) string {
+	// The classifier only needs a sample of the page
+	if len(pageText) > 3000 {
+		pageText = pageText[:3000] + "..."
+	}
+
+	template := prompts.MustGet("research.json", "moderate-content")
+	return prompts.Format(template, map[string]string{
+		"Company":     company,
+		"URL":         pageURL,
+		"PageContent": validation.QuoteExternalContentWithLabel(pageText, "WEB PAGE CONTENT"),
+	})
+}
@@ -0,0 +1,66 @@
+package research
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModerationHeuristics(t *testing.T) {
+	companyText := "We build products our customers love. Our values are ownership, candor, and craft."
+	thread := strings.Repeat("Posted by user123\nGreat point!\nReply\n3 points\n", 5)
+
+	tests := []struct {
+		name      string
+		url       string
+		text      string
+		wantScore int
+		wantCat   string
+	}{
+		{"company page", "https://acme.com/values", companyText, 0, ModerationOK},
+		{"forum host", "https://www.reddit.com/r/acme", companyText, dropScore, ModerationUserGenerated},
+		{"forum path", "https://acme.com/community/ideas", companyText, 2, ModerationUserGenerated},
+		{"comment thread", "https://acme.com/blog/post", thread, dropScore, ModerationUserGenerated},
+		{"profanity", "https://acme.com/blog", "this shit is fucking broken, bastards", dropScore, ModerationOffensive},
+		{"single swear", "https://acme.com/blog", companyText + " Damn good shit.", 1, ModerationOffensive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, category, _ := moderationHeuristics(tt.url, tt.text)
+			assert.Equal(t, tt.wantScore, score)
+			assert.Equal(t, tt.wantCat, category)
+		})
+	}
+}
+
+func TestModerateContent_HeuristicsOnly(t *testing.T) {
+	t.Setenv("LLM_PROVIDER", "")
+
+	clean := ModerateContent(context.Background(), "Our mission is to empower every engineer.", "https://acme.com/mission", "Acme", "")
+	assert.True(t, clean.Keep())
+	assert.False(t, clean.Classified)
+
+	dropped := ModerateContent(context.Background(), "anything", "https://news.ycombinator.com/item?id=1", "Acme", "")
+	assert.False(t, dropped.Keep())
+	assert.Equal(t, ModerationUserGenerated, dropped.Category)
+
+	// Borderline pages fall back to keep when the classifier is unavailable
+	borderline := ModerateContent(context.Background(), "Our culture", "https://acme.com/forum/culture", "Acme", "")
+	assert.True(t, borderline.Keep())
+	assert.True(t, borderline.Classified)
+}
+
+func TestModerationStats_Record(t *testing.T) {
+	var stats ModerationStats
+	stats.record(ModerationVerdict{Category: ModerationOK})
+	stats.record(ModerationVerdict{Category: ModerationOffensive})
+	stats.record(ModerationVerdict{Category: ModerationIrrelevant, Classified: true})
+
+	assert.Equal(t, 3, stats.Checked)
+	assert.Equal(t, 2, stats.Dropped)
+	assert.Equal(t, 1, stats.ClassifierCalls)
+	assert.Equal(t, map[string]int{ModerationOffensive: 1, ModerationIrrelevant: 1}, stats.ByCategory)
+}
@@ -0,0 +1,47 @@
+// Package research - policy.go applies user and admin domain policies to the crawl plan.
+package research
+
+import "fmt"
+
+// preferredBoost is added to the priority of URLs on allowlisted domains
+const preferredBoost = 0.1
+
+// dropBlocked removes URLs on blocked domains, recording them as skipped
+func (s *Session) dropBlocked(urls []string) []string {
+	if s.policy == nil {
+		return urls
+	}
+	kept := make([]string, 0, len(urls))
+	for _, u := range urls {
+		if blocked, reason := s.policy.Blocked(u); blocked {
+			s.skipByPolicy(u, reason)
+			continue
+		}
+		kept = append(kept, u)
+	}
+	return kept
+}
+
+// applyDomainPolicy drops blocked URLs from the frontier and boosts allowlisted ones
+func (s *Session) applyDomainPolicy() {
+	if s.policy == nil {
+		return
+	}
+	kept := s.Frontier[:0]
+	for _, ru := range s.Frontier {
+		if blocked, reason := s.policy.Blocked(ru.URL); blocked {
+			s.skipByPolicy(ru.URL, reason)
+			continue
+		}
+		if s.policy.Preferred(ru.URL) {
+			ru.Priority = min(ru.Priority+preferredBoost, 1.0)
+		}
+		kept = append(kept, ru)
+	}
+	s.Frontier = kept
+}
+
+// skipByPolicy records a URL skipped because of a domain policy
+func (s *Session) skipByPolicy(url, reason string) {
+	s.SkippedURLs = append(s.SkippedURLs, SkippedURL{URL: url, Reason: fmt.Sprintf("domain policy: %s", reason)})
+}
@@ -0,0 +1,64 @@
+package research
+
+import (
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/fetch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPolicy() *fetch.DomainPolicy {
+	return &fetch.DomainPolicy{Rules: []fetch.DomainRule{
+		{Domain: "glassdoor.com", Action: db.DomainPolicyBlock, Reason: "aggregator"},
+		{Domain: "acme.dev", Action: db.DomainPolicyAllow},
+	}}
+}
+
+func TestSession_DropBlocked(t *testing.T) {
+	s := &Session{policy: testPolicy()}
+
+	kept := s.dropBlocked([]string{"https://acme.com/values", "https://www.glassdoor.com/Reviews/acme"})
+
+	assert.Equal(t, []string{"https://acme.com/values"}, kept)
+	require.Len(t, s.SkippedURLs, 1)
+	assert.Equal(t, "https://www.glassdoor.com/Reviews/acme", s.SkippedURLs[0].URL)
+	assert.Equal(t, "domain policy: blocked domain glassdoor.com (aggregator)", s.SkippedURLs[0].Reason)
+}
+
+func TestSession_ApplyDomainPolicy(t *testing.T) {
+	s := &Session{
+		policy: testPolicy(),
+		Frontier: []RankedURL{
+			{URL: "https://acme.com/about", Priority: 0.7},
+			{URL: "https://glassdoor.com/acme", Priority: 0.9},
+			{URL: "https://blog.acme.dev/culture", Priority: 0.95},
+		},
+	}
+
+	s.applyDomainPolicy()
+
+	require.Len(t, s.Frontier, 2)
+	assert.Equal(t, 0.7, s.Frontier[0].Priority)
+	assert.Equal(t, 1.0, s.Frontier[1].Priority, "allowlisted priority is boosted and capped")
+	assert.True(t, s.knows("https://glassdoor.com/acme"))
+}
+
+func TestSession_DomainPolicyScope(t *testing.T) {
+	s := &Session{CompanyDomains: []string{"acme.com"}, policy: testPolicy()}
+
+	assert.True(t, s.inScope("https://blog.acme.dev/culture", CrawlLimits{SameDomainOnly: true}),
+		"allowlisted domains are in scope")
+
+	html := `<a href="https://acme.com/values">Values</a>`
+	assert.Equal(t, 1, s.queueLinks(html, RankedURL{URL: "https://acme.com/about"}, CrawlLimits{MaxDepth: 1}, nil))
+}
+
+func TestSession_NilDomainPolicy(t *testing.T) {
+	s := &Session{Frontier: []RankedURL{{URL: "https://glassdoor.com/acme", Priority: 0.5}}}
+
+	assert.Equal(t, []string{"https://glassdoor.com/acme"}, s.dropBlocked([]string{"https://glassdoor.com/acme"}))
+	s.applyDomainPolicy()
+	assert.Len(t, s.Frontier, 1)
+}
@@ -0,0 +1,126 @@
+// Package research - report.go renders a session as a human-readable transparency report.
+package research
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/fetch"
+)
+
+// fail records a URL the session could not use, once
+func (s *Session) fail(url, reason string) {
+	for _, failed := range s.FailedURLs {
+		if failed.URL == url {
+			return
+		}
+	}
+	s.FailedURLs = append(s.FailedURLs, SkippedURL{URL: url, Reason: reason})
+}
+
+// fetchFailure describes why a page could not be fetched
+func fetchFailure(err error) string {
+	if code := fetch.ErrorStatusCode(err); code != 0 {
+		return fmt.Sprintf("fetch failed: HTTP %d", code)
+	}
+	return "fetch failed: " + err.Error()
+}
+
+// Report renders the session as Markdown: every URL it considered and what became of
+// it (crawled, reused from the prior session, skipped, failed, or left queued), with
+// the key points extracted from each crawled page. Those key points make up the corpus
+// the company profile is summarized from.
+func (s *Session) Report() string {
+	var sb strings.Builder
+	reused := 0
+	for _, u := range s.CrawledURLs {
+		if s.Pages[u].Reuse != "" {
+			reused++
+		}
+	}
+	considered := len(s.CrawledURLs) + len(s.SkippedURLs) + len(s.FailedURLs) + len(s.Frontier)
+
+	fmt.Fprintf(&sb, "# Research Report: %s\n\n", s.Company)
+	fmt.Fprintf(&sb, "%d URLs considered: %d crawled (%d reused from the previous research), %d skipped, %d failed, %d not reached.\n",
+		considered, len(s.CrawledURLs), reused, len(s.SkippedURLs), len(s.FailedURLs), len(s.Frontier))
+	if s.Usage.StopReason != "" {
+		fmt.Fprintf(&sb, "The crawl stopped: %s.\n", s.Usage.StopReason)
+	}
+
+	sb.WriteString("\n## Crawled\n\n")
+	if len(s.CrawledURLs) == 0 {
+		sb.WriteString("No pages were crawled.\n")
+	}
+	for _, u := range s.CrawledURLs {
+		fmt.Fprintf(&sb, "- %s (%s)\n", u, s.pageOrigin(u))
+	}
+
+	sb.WriteString("\n## Excerpts Behind the Company Profile\n\n")
+	excerpts := 0
+	for _, signal := range s.BrandSignals {
+		if len(signal.KeyPoints) == 0 {
+			continue
+		}
+		excerpts++
+		fmt.Fprintf(&sb, "### %s\n\n", signal.URL)
+		if signal.Type != "" {
+			fmt.Fprintf(&sb, "Page type: %s\n\n", signal.Type)
+		}
+		for _, point := range signal.KeyPoints {
+			fmt.Fprintf(&sb, "- %s\n", point)
+		}
+		if len(signal.Values) > 0 {
+			fmt.Fprintf(&sb, "\nValues: %s\n", strings.Join(signal.Values, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	if excerpts == 0 {
+		sb.WriteString("No excerpts were extracted.\n\n")
+	}
+
+	writeURLReasons(&sb, "Skipped", s.SkippedURLs)
+	writeURLReasons(&sb, "Failed", s.FailedURLs)
+
+	if len(s.Frontier) > 0 {
+		sb.WriteString("## Not Reached\n\n")
+		for _, ru := range s.Frontier {
+			fmt.Fprintf(&sb, "- %s (priority %.2f", ru.URL, ru.Priority)
+			if ru.Reason != "" {
+				sb.WriteString(": " + ru.Reason)
+			}
+			sb.WriteString(")\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// pageOrigin describes where the session got a crawled page's content
+func (s *Session) pageOrigin(url string) string {
+	state := s.Pages[url]
+	var origin string
+	switch state.Reuse {
+	case ReuseCarried:
+		origin = "reused from the previous research, fetched " + state.FetchedAt.Format("2006-01-02")
+	case ReuseUnchanged:
+		origin = "refetched, unchanged since the previous research"
+	default:
+		origin = "fetched"
+	}
+	if state.Source == db.PageSourceArchive {
+		origin += ", from Internet Archive snapshot " + state.SnapshotURL
+	}
+	return origin
+}
+
+// writeURLReasons writes a section listing urls with the reason for each
+func writeURLReasons(sb *strings.Builder, title string, urls []SkippedURL) {
+	if len(urls) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "## %s\n\n", title)
+	for _, u := range urls {
+		fmt.Fprintf(sb, "- %s: %s\n", u.URL, u.Reason)
+	}
+	sb.WriteString("\n")
+}
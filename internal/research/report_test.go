@@ -0,0 +1,57 @@
+package research
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionReport(t *testing.T) {
+	fetched := time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC)
+	session := &Session{
+		Company:     "Acme",
+		CrawledURLs: []string{"https://acme.com/values", "https://acme.com/about", "https://acme.com/blog"},
+		Frontier:    []RankedURL{{URL: "https://acme.com/careers", Priority: 0.6, Reason: "hiring culture"}},
+		SkippedURLs: []SkippedURL{{URL: "https://forum.acme.com", Reason: "moderation: user_generated"}},
+		BrandSignals: []BrandSignal{
+			{URL: "https://acme.com/values", Type: "values", KeyPoints: []string{"Own it"}, Values: []string{"ownership"}},
+			{URL: "https://acme.com/blog"},
+		},
+		Pages: map[string]PageState{
+			"https://acme.com/values": {FetchedAt: fetched, Reuse: ReuseCarried},
+			"https://acme.com/about":  {Source: "archive", SnapshotURL: "https://web.archive.org/web/2025/acme.com/about"},
+		},
+		Usage: CrawlUsage{StopReason: StopMaxPages},
+	}
+	session.fail("https://acme.com/team", "fetch failed: HTTP 403")
+	session.fail("https://acme.com/team", "insufficient content (12 chars)")
+
+	report := session.Report()
+
+	assert.Contains(t, report, "# Research Report: Acme")
+	assert.Contains(t, report, "6 URLs considered: 3 crawled (1 reused from the previous research), 1 skipped, 1 failed, 1 not reached.")
+	assert.Contains(t, report, "- https://acme.com/values (reused from the previous research, fetched 2026-01-09)")
+	assert.Contains(t, report, "- https://acme.com/about (fetched, from Internet Archive snapshot https://web.archive.org/web/2025/acme.com/about)")
+	assert.Contains(t, report, "### https://acme.com/values\n\nPage type: values\n\n- Own it\n\nValues: ownership")
+	assert.NotContains(t, report, "### https://acme.com/blog")
+	assert.Contains(t, report, "## Skipped\n\n- https://forum.acme.com: moderation: user_generated")
+	assert.Contains(t, report, "## Failed\n\n- https://acme.com/team: fetch failed: HTTP 403\n")
+	assert.NotContains(t, report, "insufficient content")
+	assert.Contains(t, report, "- https://acme.com/careers (priority 0.60: hiring culture)")
+}
+
+func TestSessionReport_Empty(t *testing.T) {
+	report := (&Session{Company: "Acme"}).Report()
+	assert.Contains(t, report, "0 URLs considered")
+	assert.Contains(t, report, "No pages were crawled.")
+	assert.Contains(t, report, "No excerpts were extracted.")
+	assert.NotContains(t, report, "## Failed")
+}
+
+func TestFetchFailure(t *testing.T) {
+	assert.Equal(t, "fetch failed: HTTP 404", fetchFailure(&fetch.Error{URL: "https://acme.com", Message: "not found", StatusCode: 404}))
+	assert.Equal(t, "fetch failed: timeout", fetchFailure(errors.New("timeout")))
+}
@@ -4,16 +4,19 @@ package research
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/jonathan/resume-customizer/internal/db"
 	"github.com/jonathan/resume-customizer/internal/fetch"
 	"github.com/jonathan/resume-customizer/internal/ingestion"
 	"github.com/jonathan/resume-customizer/internal/llm"
 	"github.com/jonathan/resume-customizer/internal/prompts"
+	"github.com/jonathan/resume-customizer/internal/toolchain"
 )
 
 // RunResearchOptions configures the research session
@@ -22,7 +25,6 @@ type RunResearchOptions struct {
 	Company       string
 	Domain        string
 	InitialCorpus string // Pre-extracted company context (e.g., "About Us" from job post)
-	MaxPages      int
 	APIKey        string // Gemini API key for LLM operations
 	Verbose       bool
 	UseBrowser    bool
@@ -30,11 +32,23 @@ type RunResearchOptions struct {
 	// Google Custom Search API (optional - enables search-based URL discovery)
 	GoogleAPIKey string // Google API key for Custom Search
 	GoogleCX     string // Google Custom Search engine ID
+
+	// Crawl depth, scope, budget, and domain policy
+	MaxPages        int                 // Pages processed per session
+	MaxDepth        int                 // Link hops from a seed URL the crawler may follow (0 = seeds only)
+	SameDomainOnly  bool                // Only crawl the company's own domains
+	MaxBytes        int64               // Total HTML downloaded (0 = unlimited)
+	ArchiveFallback bool                // Read dead or bot-blocked pages from the Internet Archive
+	DomainPolicy    *fetch.DomainPolicy // Domains never to crawl or always to prefer
+
+	// Incremental crawling (optional - continue from the company's previous session)
+	Prior        *Session      // Prior session state; only new or changed pages are processed
+	RefreshAfter time.Duration // Reuse prior pages younger than this without refetching (0 = DefaultRefreshAfter)
 }
 
 // RunResearch executes an iterative research loop to build company corpus
 func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error) {
-	if opts.APIKey == "" {
+	if opts.APIKey == "" && llm.APIKeyRequired() {
 		return nil, fmt.Errorf("API key required for research")
 	}
 
@@ -47,6 +61,14 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 		BrandSignals:   []BrandSignal{},
 		Corpus:         opts.InitialCorpus,
 		CompanyDomains: []string{},
+		Pages:          map[string]PageState{},
+		policy:         opts.DomainPolicy,
+	}
+	prior := opts.Prior
+	seedURLs := session.dropBlocked(unseenURLs(prior, opts.SeedURLs))
+	if prior != nil && opts.Verbose {
+		log.Printf("[RESEARCH] Resuming from prior session: %d crawled, %d queued, %d of %d seeds are new",
+			len(prior.CrawledURLs), len(prior.Frontier), len(seedURLs), len(opts.SeedURLs))
 	}
 
 	// Step 1: Identify company domains from seed URLs
@@ -54,7 +76,14 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 		log.Printf("[RESEARCH] Identifying company domains from %d seed URLs...", len(opts.SeedURLs))
 	}
 
-	companyDomains, err := IdentifyCompanyDomains(ctx, opts.SeedURLs, opts.Company, opts.APIKey)
+	var companyDomains []string
+	var err error
+	if prior != nil && len(prior.CompanyDomains) > 0 {
+		// Domains rarely change between sessions; skip the LLM call
+		companyDomains = prior.CompanyDomains
+	} else {
+		companyDomains, err = IdentifyCompanyDomains(ctx, opts.SeedURLs, opts.Company, opts.APIKey)
+	}
 	if err != nil {
 		if opts.Verbose {
 			log.Printf("[RESEARCH] Domain identification failed: %v, falling back to provided domain", err)
@@ -73,16 +102,25 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 	}
 
 	// Step 2: Pre-filter seeds to only company domains (if we have them)
-	filteredSeeds := opts.SeedURLs
+	filteredSeeds := seedURLs
 	if len(companyDomains) > 0 {
-		filteredSeeds = FilterToCompanyDomains(opts.SeedURLs, companyDomains)
+		filteredSeeds = FilterToCompanyDomains(seedURLs, companyDomains)
+		// Allowlisted domains are crawled even when they are not company-owned
+		for _, u := range seedURLs {
+			if session.policy.Preferred(u) && !IsFromCompanyDomain(u, companyDomains) {
+				filteredSeeds = append(filteredSeeds, u)
+			}
+		}
 		if opts.Verbose {
 			log.Printf("[RESEARCH] Pre-filtered from %d to %d company domain URLs",
-				len(opts.SeedURLs), len(filteredSeeds))
+				len(seedURLs), len(filteredSeeds))
 		}
 
 		// Track skipped non-company URLs
-		for _, u := range opts.SeedURLs {
+		for _, u := range seedURLs {
+			if session.policy.Preferred(u) {
+				continue
+			}
 			if !IsFromCompanyDomain(u, companyDomains) && !IsThirdParty(u) {
 				session.SkippedURLs = append(session.SkippedURLs, SkippedURL{
 					URL:    u,
@@ -103,14 +141,20 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 	}
 
 	domainsStr := strings.Join(companyDomains, ", ")
-	filterResult, err := FilterLinks(ctx, filteredSeeds, opts.Company, domainsStr, opts.APIKey)
+	var filterResult *FilterLinksResult
+	if len(filteredSeeds) == 0 {
+		// Nothing new to filter (e.g. a resumed session with no new seeds)
+		filterResult = &FilterLinksResult{}
+	} else {
+		filterResult, err = FilterLinks(ctx, filteredSeeds, opts.Company, domainsStr, opts.APIKey)
+	}
 	if err != nil {
 		// Fallback to basic filtering with path priority
 		if opts.Verbose {
 			log.Printf("[RESEARCH] LLM filtering failed: %v, using path-based priority", err)
 		}
 		for _, u := range filteredSeeds {
-			if IsThirdParty(u) {
+			if IsThirdParty(u) && !session.policy.Preferred(u) {
 				session.SkippedURLs = append(session.SkippedURLs, SkippedURL{URL: u, Reason: "third-party"})
 			} else {
 				priority := AssignPathPriority(u)
@@ -131,6 +175,9 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 			len(session.Frontier), len(session.SkippedURLs))
 	}
 
+	// Carry over pages and frontier from the prior session
+	carryOver(session, prior, opts.RefreshAfter, time.Now())
+
 	// Step 4: Discover high-value URLs (search-first with pattern fallback)
 	highValueURLsFound := 0
 
@@ -149,7 +196,7 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 				// Filter search results to company domains and add to frontier
 				validSearchURLs := FilterToCompanyDomains(searchSeeds, companyDomains)
 				for _, searchURL := range validSearchURLs {
-					if !isInList(searchURL, session.Frontier) && !isInList(searchURL, session.CrawledURLs) {
+					if !session.knows(searchURL) {
 						priority := AssignPathPriority(searchURL)
 						session.Frontier = append(session.Frontier, RankedURL{
 							URL:      searchURL,
@@ -180,7 +227,7 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 		patternURLs := generateHighValueURLs(companyDomains)
 		patternCount := 0
 		for _, pu := range patternURLs {
-			if !isInList(pu.URL, session.Frontier) && !isInList(pu.URL, session.CrawledURLs) {
+			if !session.knows(pu.URL) && (prior == nil || !prior.knows(pu.URL)) {
 				// Mark as speculative so we can track 404s differently
 				pu.Reason = "speculative pattern (may not exist)"
 				session.Frontier = append(session.Frontier, pu)
@@ -192,7 +239,8 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 		}
 	}
 
-	// Step 5: Sort frontier by priority (highest first)
+	// Step 5: Apply domain policies, then sort frontier by priority (highest first)
+	session.applyDomainPolicy()
 	sortFrontierByPriority(session)
 
 	if opts.Verbose && len(session.Frontier) > 0 {
@@ -205,9 +253,31 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 		}
 	}
 
-	// Crawl loop
+	// Crawl loop, bounded by the page, byte, depth, and scope limits
+	limits := CrawlLimits{
+		MaxPages:       opts.MaxPages,
+		MaxDepth:       opts.MaxDepth,
+		SameDomainOnly: opts.SameDomainOnly,
+		MaxBytes:       opts.MaxBytes,
+
+		ArchiveFallback: opts.ArchiveFallback,
+	}
+	session.Usage.Limits = limits
 	pagesProcessed := 0
-	for pagesProcessed < opts.MaxPages && len(session.Frontier) > 0 {
+	for {
+		if len(session.Frontier) == 0 {
+			session.Usage.StopReason = StopFrontierExhausted
+			break
+		}
+		if pagesProcessed >= limits.MaxPages {
+			session.Usage.StopReason = StopMaxPages
+			break
+		}
+		if limits.MaxBytes > 0 && session.Usage.BytesFetched >= limits.MaxBytes {
+			session.Usage.StopReason = StopMaxBytes
+			break
+		}
+
 		// Get highest priority URL
 		target := session.Frontier[0]
 		session.Frontier = session.Frontier[1:]
@@ -215,23 +285,45 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 		if isInList(target.URL, session.CrawledURLs) {
 			continue
 		}
+		if blocked, reason := session.policy.Blocked(target.URL); blocked {
+			session.skipByPolicy(target.URL, reason)
+			continue
+		}
+		if !session.inScope(target.URL, limits) {
+			session.Usage.OutOfScope++
+			session.SkippedURLs = append(session.SkippedURLs, SkippedURL{URL: target.URL, Reason: "out of crawl scope"})
+			continue
+		}
 
 		if opts.Verbose {
 			log.Printf("[RESEARCH] Crawling %s (priority: %.2f, type: %s)", target.URL, target.Priority, target.Type)
 		}
 
 		// Fetch page
-		html, err := fetchPage(ctx, target.URL, opts.UseBrowser, opts.Verbose)
+		html, snapshot, err := fetchPage(ctx, target.URL, opts.UseBrowser, limits.ArchiveFallback, opts.Verbose)
 		if err != nil {
 			if opts.Verbose {
 				log.Printf("[RESEARCH] Failed to fetch %s: %v", target.URL, err)
 			}
+			session.fail(target.URL, fetchFailure(err))
 			continue
 		}
+		session.Usage.PagesFetched++
+		if snapshot != nil {
+			session.Usage.ArchivedPages++
+			if opts.Verbose {
+				log.Printf("[RESEARCH] Using archived snapshot %s for %s", snapshot.URL, target.URL)
+			}
+		}
+		session.Usage.BytesFetched += int64(len(html))
+		if target.Depth > session.Usage.MaxDepthReached {
+			session.Usage.MaxDepthReached = target.Depth
+		}
 
 		// Extract text
 		text, err := fetch.ExtractMainText(html, fetch.CompanyPageSelectors())
 		if err != nil {
+			session.fail(target.URL, "no extractable text")
 			continue
 		}
 		text = ingestion.CleanText(text)
@@ -240,6 +332,51 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 			if opts.Verbose {
 				log.Printf("[RESEARCH] Skipping %s - insufficient content (%d chars)", target.URL, len(text))
 			}
+			session.fail(target.URL, fmt.Sprintf("insufficient content (%d chars)", len(text)))
+			continue
+		}
+
+		// A refetched page whose content has not changed keeps its prior signal
+		hash := hashText(text)
+		state := PageState{Hash: hash, FetchedAt: time.Now().UTC()}
+		if snapshot != nil {
+			state.Source = db.PageSourceArchive
+			state.SnapshotURL = snapshot.URL
+		}
+		if prior != nil {
+			if prev, ok := prior.Pages[target.URL]; ok {
+				if prev.Hash == hash {
+					if sig := prior.signalFor(target.URL); sig != nil {
+						session.BrandSignals = append(session.BrandSignals, *sig)
+					}
+					session.CrawledURLs = append(session.CrawledURLs, target.URL)
+					state.Reuse = ReuseUnchanged
+					session.Pages[target.URL] = state
+					session.Incremental.Unchanged++
+					session.Usage.LinksQueued += session.queueLinks(html, target, limits, prior)
+					pagesProcessed++
+					if opts.Verbose {
+						log.Printf("[RESEARCH] %s unchanged since last session, reusing signals", target.URL)
+					}
+					continue
+				}
+				session.Incremental.Changed++
+			} else {
+				session.Incremental.New++
+			}
+		}
+
+		// Drop offensive, user-generated, or irrelevant pages before they reach the corpus
+		verdict := ModerateContent(ctx, text, target.URL, opts.Company, opts.APIKey)
+		session.Moderation.record(verdict)
+		if !verdict.Keep() {
+			session.SkippedURLs = append(session.SkippedURLs, SkippedURL{
+				URL:    target.URL,
+				Reason: "moderation: " + verdict.Category,
+			})
+			if opts.Verbose {
+				log.Printf("[RESEARCH] Dropping %s from corpus (%s: %s)", target.URL, verdict.Category, verdict.Reason)
+			}
 			continue
 		}
 
@@ -253,6 +390,8 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 		}
 
 		session.CrawledURLs = append(session.CrawledURLs, target.URL)
+		session.Pages[target.URL] = state
+		session.Usage.LinksQueued += session.queueLinks(html, target, limits, prior)
 		pagesProcessed++
 
 		// Rate limiting
@@ -263,8 +402,11 @@ func RunResearch(ctx context.Context, opts RunResearchOptions) (*Session, error)
 	session.Corpus = AggregateSignals(session.BrandSignals)
 
 	if opts.Verbose {
-		log.Printf("[RESEARCH] Complete: crawled %d pages, extracted %d signals, corpus: %d chars",
-			len(session.CrawledURLs), len(session.BrandSignals), len(session.Corpus))
+		log.Printf("[RESEARCH] Complete: crawled %d pages, extracted %d signals, corpus: %d chars, moderation dropped %d of %d",
+			len(session.CrawledURLs), len(session.BrandSignals), len(session.Corpus),
+			session.Moderation.Dropped, session.Moderation.Checked)
+		log.Printf("[RESEARCH] Usage: %d pages, %d bytes, depth %d, stopped: %s",
+			session.Usage.PagesFetched, session.Usage.BytesFetched, session.Usage.MaxDepthReached, session.Usage.StopReason)
 	}
 
 	return session, nil
@@ -290,19 +432,33 @@ func generateHighValueURLs(companyDomains []string) []RankedURL {
 	return results
 }
 
-func fetchPage(ctx context.Context, pageURL string, useBrowser bool, verbose bool) (string, error) {
+// fetchPage fetches a page's HTML. With archiveFallback, a dead or bot-blocked page is read
+// from its latest Internet Archive snapshot, which is returned alongside the HTML.
+func fetchPage(ctx context.Context, pageURL string, useBrowser bool, archiveFallback bool, verbose bool) (string, *fetch.Snapshot, error) {
 	result, err := fetch.URL(ctx, pageURL, nil)
+	if archiveFallback && fetch.NeedsArchive(result, err) {
+		if archived, archiveErr := fetch.Archived(ctx, pageURL, nil); archiveErr == nil {
+			return archived.HTML, archived.Archive, nil
+		} else if verbose {
+			log.Printf("[RESEARCH] No archived copy of %s: %v", pageURL, archiveErr)
+		}
+	}
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	// Check if we need browser fallback
 	text, _ := fetch.ExtractMainText(result.HTML, fetch.CompanyPageSelectors())
 	if useBrowser && fetch.ShouldUseBrowser(text) {
-		return fetch.BrowserSimple(ctx, pageURL, verbose)
+		html, err := fetch.BrowserSimple(ctx, pageURL, verbose)
+		if errors.Is(err, toolchain.ErrUnavailable) {
+			// Without a browser the HTTP response is the best available content
+			return result.HTML, nil, nil
+		}
+		return html, nil, err
 	}
 
-	return result.HTML, nil
+	return result.HTML, nil, nil
 }
 
 func categorizePattern(pattern string) string {
@@ -355,7 +511,7 @@ func sortFrontierByPriority(session *Session) {
 
 // SuggestNextQueries uses LLM to suggest what to search for next
 func SuggestNextQueries(ctx context.Context, session *Session, apiKey string) ([]string, error) {
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, fmt.Errorf("API key required")
 	}
 
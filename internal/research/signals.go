@@ -13,7 +13,7 @@ import (
 
 // ExtractBrandSignals extracts brand-relevant information from page text
 func ExtractBrandSignals(ctx context.Context, pageText string, url string, apiKey string) (*BrandSignal, error) {
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, fmt.Errorf("API key required for signal extraction")
 	}
 
@@ -4,6 +4,7 @@ package research
 import (
 	"time"
 
+	"github.com/jonathan/resume-customizer/internal/fetch"
 	"github.com/jonathan/resume-customizer/internal/types"
 )
 
@@ -16,12 +17,25 @@ type Session struct {
 
 	// URL management
 	CrawledURLs []string     `json:"crawled_urls"`
-	Frontier    []RankedURL  `json:"frontier"`     // URLs to try next
-	SkippedURLs []SkippedURL `json:"skipped_urls"` // Filtered out
+	Frontier    []RankedURL  `json:"frontier"`              // URLs to try next
+	SkippedURLs []SkippedURL `json:"skipped_urls"`          // Filtered out
+	FailedURLs  []SkippedURL `json:"failed_urls,omitempty"` // Not fetched, or fetched without usable text; retried by the next session
 
 	// Extracted content
 	BrandSignals []BrandSignal `json:"brand_signals"`
 	Corpus       string        `json:"corpus"`
+
+	// Content moderation of crawled pages
+	Moderation ModerationStats `json:"moderation"`
+
+	// Incremental crawl state, carried into the company's next session
+	Pages       map[string]PageState `json:"pages,omitempty"` // Keyed by crawled URL
+	Incremental IncrementalStats     `json:"incremental"`
+
+	// Crawl budget consumption
+	Usage CrawlUsage `json:"usage"`
+
+	policy *fetch.DomainPolicy // User and admin domain blocklist/allowlist
 }
 
 // ToSources converts crawled URLs to types.Source slice for compatibility
@@ -29,9 +43,10 @@ func (s *Session) ToSources() []types.Source {
 	sources := make([]types.Source, 0, len(s.CrawledURLs))
 	for _, url := range s.CrawledURLs {
 		sources = append(sources, types.Source{
-			URL:       url,
-			Timestamp: time.Now().UTC().Format(time.RFC3339),
-			Hash:      "", // Hash not available from research session
+			URL:        url,
+			Timestamp:  time.Now().UTC().Format(time.RFC3339),
+			Hash:       "", // Hash not available from research session
+			SourceType: s.Pages[url].Source,
 		})
 	}
 	return sources
@@ -40,9 +55,10 @@ func (s *Session) ToSources() []types.Source {
 // RankedURL is a URL with priority for crawl ordering
 type RankedURL struct {
 	URL      string  `json:"url"`
-	Priority float64 `json:"priority"` // 0.0-1.0, higher = more relevant
-	Reason   string  `json:"reason"`   // Why it's relevant
-	Type     string  `json:"type"`     // values, culture, engineering, press, other
+	Priority float64 `json:"priority"`        // 0.0-1.0, higher = more relevant
+	Reason   string  `json:"reason"`          // Why it's relevant
+	Type     string  `json:"type"`            // values, culture, engineering, press, other
+	Depth    int     `json:"depth,omitempty"` // Link hops from a seed URL
 }
 
 // SkippedURL is a URL that was filtered out
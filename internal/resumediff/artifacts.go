@@ -0,0 +1,152 @@
+package resumediff
+
+import (
+	"slices"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// ArtifactDiff lists what changed in one of a run's artifacts between two versions of
+// it, such as the plan selection produced and the plan the repair loop left
+type ArtifactDiff struct {
+	Added     []BulletChange  `json:"added"`
+	Removed   []BulletChange  `json:"removed"`
+	Modified  []BulletChange  `json:"modified"` // Reworded bullets, or plan bullets moved to another section
+	Unchanged int             `json:"unchanged"`
+	Sections  []SectionChange `json:"sections"`                     // LaTeX sections added, removed, or changed
+	Reordered *SectionOrder   `json:"reordered_sections,omitempty"` // Set when sections kept in both moved
+}
+
+// SectionOrder is the section order of both versions
+type SectionOrder struct {
+	Before []string `json:"before"`
+	After  []string `json:"after"`
+}
+
+func newArtifactDiff() *ArtifactDiff {
+	return &ArtifactDiff{
+		Added:    []BulletChange{},
+		Removed:  []BulletChange{},
+		Modified: []BulletChange{},
+		Sections: []SectionChange{},
+	}
+}
+
+// DiffBullets compares two versions of a run's rewritten bullets, matched by the
+// experience bank bullet they were rewritten from
+func DiffBullets(before, after *types.RewrittenBullets) *ArtifactDiff {
+	diff := newArtifactDiff()
+	old, current := bulletsByID(before), bulletsByID(after)
+	for _, id := range orderedIDs(after) {
+		text, ok := old[id]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, BulletChange{BulletID: id, After: current[id]})
+		case normalize(text) == normalize(current[id]):
+			diff.Unchanged++
+		default:
+			diff.Modified = append(diff.Modified, BulletChange{
+				BulletID: id, Before: text, After: current[id], Similarity: similarity(text, current[id]),
+			})
+		}
+	}
+	for _, id := range orderedIDs(before) {
+		if _, ok := current[id]; !ok {
+			diff.Removed = append(diff.Removed, BulletChange{BulletID: id, Before: old[id]})
+		}
+	}
+	return diff
+}
+
+// DiffPlans compares two versions of a run's resume plan. Plan bullets carry no text,
+// so Before and After hold the section of bullets the plan moved between sections.
+func DiffPlans(before, after *types.ResumePlan) *ArtifactDiff {
+	diff := newArtifactDiff()
+	oldIDs, oldSections := planBullets(before)
+	newIDs, newSections := planBullets(after)
+	for _, id := range newIDs {
+		section, ok := oldSections[id]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, BulletChange{BulletID: id, After: newSections[id]})
+		case section == newSections[id]:
+			diff.Unchanged++
+		default:
+			diff.Modified = append(diff.Modified, BulletChange{BulletID: id, Before: section, After: newSections[id]})
+		}
+	}
+	for _, id := range oldIDs {
+		if _, ok := newSections[id]; !ok {
+			diff.Removed = append(diff.Removed, BulletChange{BulletID: id, Before: oldSections[id]})
+		}
+	}
+	diff.Reordered = reordered(planSections(before), planSections(after))
+	return diff
+}
+
+// DiffLaTeX compares two versions of a run's resume LaTeX section by section
+func DiffLaTeX(before, after string) *ArtifactDiff {
+	diff := newArtifactDiff()
+	old, current := sections(before), sections(after)
+	diff.Sections = compareSections(old, current)
+	diff.Reordered = reordered(sectionNames(old), sectionNames(current))
+	return diff
+}
+
+// planBullets returns a plan's bullet IDs in order and the section of each
+func planBullets(plan *types.ResumePlan) ([]string, map[string]string) {
+	var ids []string
+	sections := make(map[string]string)
+	if plan == nil {
+		return ids, sections
+	}
+	for _, story := range plan.SelectedStories {
+		for _, id := range story.BulletIDs {
+			if _, ok := sections[id]; !ok {
+				ids = append(ids, id)
+				sections[id] = story.Section
+			}
+		}
+	}
+	return ids, sections
+}
+
+// planSections returns the sections of a plan's stories in order, without duplicates
+func planSections(plan *types.ResumePlan) []string {
+	var names []string
+	if plan == nil {
+		return names
+	}
+	for _, story := range plan.SelectedStories {
+		if !slices.Contains(names, story.Section) {
+			names = append(names, story.Section)
+		}
+	}
+	return names
+}
+
+func sectionNames(list []section) []string {
+	names := make([]string, 0, len(list))
+	for _, s := range list {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// reordered returns both orders if the sections present in both versions appear in a
+// different order, ignoring sections only one version has, or nil
+func reordered(before, after []string) *SectionOrder {
+	kept := func(names, other []string) []string {
+		var result []string
+		for _, name := range names {
+			if slices.Contains(other, name) {
+				result = append(result, name)
+			}
+		}
+		return result
+	}
+	if slices.Equal(kept(before, after), kept(after, before)) {
+		return nil
+	}
+	return &SectionOrder{Before: before, After: after}
+}
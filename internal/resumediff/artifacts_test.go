@@ -0,0 +1,65 @@
+package resumediff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestDiffPlans(t *testing.T) {
+	before := &types.ResumePlan{SelectedStories: []types.SelectedStory{
+		{StoryID: "s1", BulletIDs: []string{"b1", "b2"}, Section: "experience"},
+		{StoryID: "s2", BulletIDs: []string{"b3"}, Section: "projects"},
+		{StoryID: "s3", BulletIDs: []string{"b4"}, Section: "experience"},
+	}}
+	after := &types.ResumePlan{SelectedStories: []types.SelectedStory{
+		{StoryID: "s2", BulletIDs: []string{"b3", "b5"}, Section: "projects"},
+		{StoryID: "s1", BulletIDs: []string{"b1"}, Section: "experience"},
+		{StoryID: "s3", BulletIDs: []string{"b4"}, Section: "projects"},
+	}}
+
+	diff := DiffPlans(before, after)
+	assert.Equal(t, []BulletChange{{BulletID: "b5", After: "projects"}}, diff.Added)
+	assert.Equal(t, []BulletChange{{BulletID: "b2", Before: "experience"}}, diff.Removed)
+	assert.Equal(t, []BulletChange{{BulletID: "b4", Before: "experience", After: "projects"}}, diff.Modified)
+	assert.Equal(t, 2, diff.Unchanged)
+	require.NotNil(t, diff.Reordered)
+	assert.Equal(t, []string{"experience", "projects"}, diff.Reordered.Before)
+	assert.Equal(t, []string{"projects", "experience"}, diff.Reordered.After)
+
+	same := DiffPlans(before, before)
+	assert.Empty(t, same.Added)
+	assert.Empty(t, same.Modified)
+	assert.Nil(t, same.Reordered)
+}
+
+func TestDiffBullets(t *testing.T) {
+	diff := DiffBullets(
+		bullets("b1", "Led migration to Kubernetes", "b2", "Mentored four engineers"),
+		bullets("b1", "Led the migration of 40 services to Kubernetes", "b3", "Cut CI time by 60%"),
+	)
+	assert.Equal(t, []BulletChange{{BulletID: "b3", After: "Cut CI time by 60%"}}, diff.Added)
+	assert.Equal(t, []BulletChange{{BulletID: "b2", Before: "Mentored four engineers"}}, diff.Removed)
+	require.Len(t, diff.Modified, 1)
+	assert.Equal(t, "Led the migration of 40 services to Kubernetes", diff.Modified[0].After)
+	assert.Zero(t, diff.Unchanged)
+}
+
+func TestDiffLaTeX(t *testing.T) {
+	before := "\\section{Experience}\n\\item Built a billing service\n\\section{Skills}\nGo\n\\section{Awards}\nHackathon winner\n"
+	after := "\\section{Skills}\nGo, Kubernetes\n\\section{Experience}\n\\item Built a billing service\n"
+
+	diff := DiffLaTeX(before, after)
+	assert.Equal(t, []SectionChange{
+		{Name: "Skills", Status: SectionChanged, Diff: []string{"+ Go, Kubernetes", "- Go"}},
+		{Name: "Awards", Status: SectionRemoved},
+	}, diff.Sections)
+	require.NotNil(t, diff.Reordered)
+	assert.Equal(t, []string{"Skills", "Experience"}, diff.Reordered.After)
+
+	// Dropping a section alone does not reorder the rest
+	assert.Nil(t, DiffLaTeX(before, "\\section{Experience}\n\\section{Awards}\n").Reordered)
+}
@@ -0,0 +1,229 @@
+// Package resumediff compares a new resume with the user's previous resume for the
+// same role family, so churn between applications can be reviewed at a glance.
+package resumediff
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Section change statuses
+const (
+	SectionAdded   = "added"
+	SectionRemoved = "removed"
+	SectionChanged = "changed"
+)
+
+var sectionPattern = regexp.MustCompile(`\\section\*?\{([^}]*)\}`)
+
+// Resume is one resume's final bullets and LaTeX
+type Resume struct {
+	RunID     uuid.UUID
+	RoleTitle string
+	Company   string
+	Bullets   *types.RewrittenBullets
+	LaTeX     string
+}
+
+// Report lists what changed between the previous and current resume
+type Report struct {
+	RoleFamily      string          `json:"role_family"`
+	PreviousRunID   uuid.UUID       `json:"previous_run_id"`
+	PreviousRole    string          `json:"previous_role"`
+	PreviousCompany string          `json:"previous_company,omitempty"`
+	Added           []BulletChange  `json:"added"`
+	Removed         []BulletChange  `json:"removed"`
+	Reworded        []BulletChange  `json:"reworded"`
+	Unchanged       int             `json:"unchanged"`
+	Sections        []SectionChange `json:"sections"`
+	Churn           float64         `json:"churn"` // Share of bullets added, removed, or reworded
+}
+
+// BulletChange is a bullet present in only one resume, or reworded between them.
+// Bullets are matched by the experience bank bullet they were rewritten from.
+type BulletChange struct {
+	BulletID   string  `json:"bullet_id"`
+	Before     string  `json:"before,omitempty"`
+	After      string  `json:"after,omitempty"`
+	Similarity float64 `json:"similarity,omitempty"` // Word overlap of reworded bullets, 0-1
+}
+
+// SectionChange is a LaTeX section added, removed, or changed between the resumes,
+// with a line diff of changed sections ("+" added lines, "-" removed lines)
+type SectionChange struct {
+	Name   string   `json:"name"`
+	Status string   `json:"status"`
+	Diff   []string `json:"diff,omitempty"`
+}
+
+// Compare reports the bullets and sections that changed from previous to current
+func Compare(previous, current *Resume) *Report {
+	bullets := DiffBullets(previous.Bullets, current.Bullets)
+	report := &Report{
+		RoleFamily:      RoleFamily(current.RoleTitle),
+		PreviousRunID:   previous.RunID,
+		PreviousRole:    previous.RoleTitle,
+		PreviousCompany: previous.Company,
+		Added:           bullets.Added,
+		Removed:         bullets.Removed,
+		Reworded:        bullets.Modified,
+		Unchanged:       bullets.Unchanged,
+	}
+	changed := len(report.Added) + len(report.Removed) + len(report.Reworded)
+	if total := changed + report.Unchanged; total > 0 {
+		report.Churn = math.Round(float64(changed)/float64(total)*100) / 100
+	}
+
+	report.Sections = compareSections(sections(previous.LaTeX), sections(current.LaTeX))
+	return report
+}
+
+// bulletsByID maps each bullet's source ID to its final text
+func bulletsByID(bullets *types.RewrittenBullets) map[string]string {
+	byID := make(map[string]string)
+	if bullets != nil {
+		for _, b := range bullets.Bullets {
+			byID[b.OriginalBulletID] = b.FinalText
+		}
+	}
+	return byID
+}
+
+// orderedIDs returns the bullets' source IDs in resume order, without duplicates
+func orderedIDs(bullets *types.RewrittenBullets) []string {
+	if bullets == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var ids []string
+	for _, b := range bullets.Bullets {
+		if !seen[b.OriginalBulletID] {
+			seen[b.OriginalBulletID] = true
+			ids = append(ids, b.OriginalBulletID)
+		}
+	}
+	return ids
+}
+
+// section is a LaTeX section's name and non-blank lines
+type section struct {
+	name  string
+	lines []string
+}
+
+// sections splits LaTeX into its \section blocks, ignoring the preamble and header
+func sections(latex string) []section {
+	bounds := sectionPattern.FindAllStringSubmatchIndex(latex, -1)
+	result := make([]section, 0, len(bounds))
+	for i, b := range bounds {
+		end := len(latex)
+		if i+1 < len(bounds) {
+			end = bounds[i+1][0]
+		}
+		body := strings.TrimSuffix(latex[b[1]:end], `\end{document}`)
+		var lines []string
+		for _, line := range strings.Split(body, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		result = append(result, section{name: strings.TrimSpace(latex[b[2]:b[3]]), lines: lines})
+	}
+	return result
+}
+
+// compareSections matches sections by name and diffs those whose lines differ
+func compareSections(previous, current []section) []SectionChange {
+	changes := []SectionChange{}
+	old := make(map[string][]string, len(previous))
+	for _, s := range previous {
+		old[s.name] = s.lines
+	}
+	seen := make(map[string]bool, len(current))
+	for _, s := range current {
+		seen[s.name] = true
+		lines, ok := old[s.name]
+		if !ok {
+			changes = append(changes, SectionChange{Name: s.name, Status: SectionAdded})
+			continue
+		}
+		if diff := diffLines(lines, s.lines); len(diff) > 0 {
+			changes = append(changes, SectionChange{Name: s.name, Status: SectionChanged, Diff: diff})
+		}
+	}
+	for _, s := range previous {
+		if !seen[s.name] {
+			changes = append(changes, SectionChange{Name: s.name, Status: SectionRemoved})
+		}
+	}
+	return changes
+}
+
+// diffLines returns the lines removed from a ("-") and added in b ("+"), in order,
+// from a longest common subsequence of the two. Returns nil if they are equal.
+func diffLines(a, b []string) []string {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			diff = append(diff, "+ "+b[j])
+			j++
+		default:
+			diff = append(diff, "- "+a[i])
+			i++
+		}
+	}
+	return diff
+}
+
+// normalize lowercases text and collapses its whitespace
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// similarity is the Jaccard overlap of two texts' words, rounded to two decimals
+func similarity(a, b string) float64 {
+	wordsA := strings.Fields(normalize(a))
+	wordsB := make(map[string]bool)
+	for _, w := range strings.Fields(normalize(b)) {
+		wordsB[w] = true
+	}
+	setA := make(map[string]bool)
+	shared := 0
+	for _, w := range wordsA {
+		if !setA[w] {
+			setA[w] = true
+			if wordsB[w] {
+				shared++
+			}
+		}
+	}
+	union := len(setA) + len(wordsB) - shared
+	if union == 0 {
+		return 1
+	}
+	return math.Round(float64(shared)/float64(union)*100) / 100
+}
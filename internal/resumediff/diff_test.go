@@ -0,0 +1,88 @@
+package resumediff
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func bullets(pairs ...string) *types.RewrittenBullets {
+	b := &types.RewrittenBullets{}
+	for i := 0; i < len(pairs); i += 2 {
+		b.Bullets = append(b.Bullets, types.RewrittenBullet{OriginalBulletID: pairs[i], FinalText: pairs[i+1]})
+	}
+	return b
+}
+
+func TestCompare_Bullets(t *testing.T) {
+	previous := &Resume{
+		RunID:     uuid.New(),
+		RoleTitle: "Software Engineer",
+		Company:   "Acme",
+		Bullets: bullets(
+			"b1", "Built a billing service handling 1M requests per day",
+			"b2", "Led migration to Kubernetes",
+			"b3", "Mentored four engineers",
+		),
+	}
+	current := &Resume{
+		RoleTitle: "Senior Software Engineer, Platform",
+		Bullets: bullets(
+			"b1", "Built a billing service handling 1M requests per day",
+			"b2", "Led the migration of 40 services to Kubernetes",
+			"b4", "Cut CI time by 60%",
+		),
+	}
+
+	report := Compare(previous, current)
+	assert.Equal(t, "software engineer", report.RoleFamily)
+	assert.Equal(t, previous.RunID, report.PreviousRunID)
+	assert.Equal(t, "Acme", report.PreviousCompany)
+	assert.Equal(t, 1, report.Unchanged)
+	assert.Equal(t, []BulletChange{{BulletID: "b4", After: "Cut CI time by 60%"}}, report.Added)
+	assert.Equal(t, []BulletChange{{BulletID: "b3", Before: "Mentored four engineers"}}, report.Removed)
+	require.Len(t, report.Reworded, 1)
+	assert.Equal(t, "b2", report.Reworded[0].BulletID)
+	assert.Greater(t, report.Reworded[0].Similarity, 0.0)
+	assert.Less(t, report.Reworded[0].Similarity, 1.0)
+	assert.Equal(t, 0.75, report.Churn)
+}
+
+func TestCompare_Sections(t *testing.T) {
+	previous := &Resume{LaTeX: `\documentclass{article}
+\begin{document}
+\section{Experience}
+\item Built a billing service
+\item Led migration to Kubernetes
+\section{Awards}
+Hackathon winner
+\end{document}`}
+	current := &Resume{LaTeX: `\documentclass{article}
+\begin{document}
+\section{Experience}
+\item Built a billing service
+\item Led the migration of 40 services to Kubernetes
+\section*{Skills}
+Go, Kubernetes
+\end{document}`}
+
+	report := Compare(previous, current)
+	assert.Equal(t, []SectionChange{
+		{Name: "Experience", Status: SectionChanged, Diff: []string{
+			`+ \item Led the migration of 40 services to Kubernetes`,
+			`- \item Led migration to Kubernetes`,
+		}},
+		{Name: "Skills", Status: SectionAdded},
+		{Name: "Awards", Status: SectionRemoved},
+	}, report.Sections)
+	assert.Zero(t, report.Churn, "no bullets to compare")
+}
+
+func TestDiffLines(t *testing.T) {
+	assert.Nil(t, diffLines([]string{"a", "b"}, []string{"a", "b"}))
+	assert.Equal(t, []string{"- b", "+ x", "+ d"}, diffLines([]string{"a", "b", "c"}, []string{"a", "c", "x", "d"}))
+}
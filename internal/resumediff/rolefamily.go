@@ -0,0 +1,10 @@
+package resumediff
+
+import "github.com/jonathan/resume-customizer/internal/titles"
+
+// RoleFamily reduces a job title to its role family, ignoring seniority, team, and
+// location: "Senior Software Engineer, Payments" and "Software Developer II" are both
+// "software engineer". Returns "" for a title with no role words.
+func RoleFamily(title string) string {
+	return titles.Normalize(title).Family
+}
@@ -0,0 +1,23 @@
+package resumediff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleFamily(t *testing.T) {
+	tests := map[string]string{
+		"Senior Software Engineer, Payments": "software engineer",
+		"Software Developer II":              "software engineer",
+		"Staff SWE":                          "software engineer",
+		"Sr. Data Scientist (Remote)":        "data scientist",
+		"Lead Product Manager - Growth":      "product manager",
+		"Principal SRE":                      "site reliability engineer",
+		"Head of Engineering":                "engineering",
+		"Senior":                             "",
+	}
+	for title, want := range tests {
+		assert.Equal(t, want, RoleFamily(title), title)
+	}
+}
@@ -0,0 +1,185 @@
+// Package resumeimport drafts an experience bank from a resume the user already has, so
+// they can start from their PDF or Word document instead of entering every position by
+// hand. Nothing is saved until the user reviews the draft and imports it.
+package resumeimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/prompts"
+)
+
+// endPresent is the experience bank's end date for a current position
+const endPresent = "present"
+
+// degreeCodes are the degrees the experience bank knows; others are stored as "other"
+var degreeCodes = []string{db.DegreeAssociate, db.DegreeBachelor, db.DegreeMaster, db.DegreePhD}
+
+// ExtractBank asks the model to turn a resume's text into an experience bank draft for
+// userID: one story per position with its bullets and their skills, and the education
+// listed. The draft only restates the resume; it is saved by db.ImportExperienceBank
+// once the user has reviewed it.
+func ExtractBank(ctx context.Context, userID uuid.UUID, text, apiKey string) (*db.ExperienceBankImportInput, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrNoText
+	}
+	if len(text) > MaxTextLength {
+		return nil, &DocumentError{Message: fmt.Sprintf("document has more than %d characters of text", MaxTextLength)}
+	}
+
+	template, err := prompts.Get("resumeimport.json", "extract-bank")
+	if err != nil {
+		return nil, &APICallError{Message: "failed to load prompt", Cause: err}
+	}
+	prompt := prompts.Format(template, map[string]string{"Resume": text})
+	response, err := generate(ctx, prompt, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var bank db.ExperienceBankImportInput
+	if err := json.Unmarshal([]byte(llm.CleanJSONBlock(response)), &bank); err != nil {
+		return nil, &ParseError{Message: "failed to parse experience bank draft", Cause: err}
+	}
+	Normalize(&bank)
+	if len(bank.Stories) == 0 && len(bank.Education) == 0 {
+		return nil, &ParseError{Message: "no positions or education were found in the resume"}
+	}
+	bank.UserID = userID
+	return &bank, nil
+}
+
+// Normalize trims a draft, drops positions and schools without a name, assigns fresh
+// IDs, and fills in what neither the model nor the reviewer is asked for: bullet
+// lengths, evidence strength, and risk flags
+func Normalize(bank *db.ExperienceBankImportInput) {
+	stories := make([]db.StoryImportInput, 0, len(bank.Stories))
+	for _, s := range bank.Stories {
+		s.Company = strings.TrimSpace(s.Company)
+		s.Role = strings.TrimSpace(s.Role)
+		if s.Company == "" || s.Role == "" {
+			continue
+		}
+		s.ID = "import-" + uuid.New().String()
+		s.StartDate = month(s.StartDate)
+		s.EndDate = endMonth(s.EndDate)
+
+		bullets := make([]db.BulletImportInput, 0, len(s.Bullets))
+		for _, b := range s.Bullets {
+			b.Text = strings.TrimSpace(b.Text)
+			if b.Text == "" {
+				continue
+			}
+			b.ID = fmt.Sprintf("%s-%d", s.ID, len(bullets)+1)
+			b.Metrics = strings.TrimSpace(b.Metrics)
+			b.Skills = uniqueSkills(b.Skills)
+			b.LengthChars = len(b.Text)
+			b.EvidenceStrength = db.EvidenceStrengthMedium
+			if b.Metrics != "" {
+				b.EvidenceStrength = db.EvidenceStrengthHigh // Quantified results are the strongest evidence
+			}
+			b.RiskFlags = []string{}
+			bullets = append(bullets, b)
+		}
+		s.Bullets = bullets
+		stories = append(stories, s)
+	}
+	bank.Stories = stories
+
+	education := make([]db.EducationImportInput, 0, len(bank.Education))
+	for _, e := range bank.Education {
+		e.School = strings.TrimSpace(e.School)
+		if e.School == "" {
+			continue
+		}
+		e.ID = "import-edu-" + uuid.New().String()
+		e.Degree = degreeCode(e.Degree)
+		e.Field = strings.TrimSpace(e.Field)
+		e.GPA = strings.TrimSpace(e.GPA)
+		e.StartDate = month(e.StartDate)
+		e.EndDate = month(e.EndDate)
+		highlights := make([]string, 0, len(e.Highlights))
+		for _, h := range e.Highlights {
+			if h = strings.TrimSpace(h); h != "" {
+				highlights = append(highlights, h)
+			}
+		}
+		e.Highlights = highlights
+		education = append(education, e)
+	}
+	bank.Education = education
+}
+
+// month returns date as YYYY-MM, reading a bare year as January, or "" if it is neither
+func month(date string) string {
+	date = strings.TrimSpace(date)
+	if _, err := time.Parse("2006-01", date); err == nil {
+		return date
+	}
+	if _, err := time.Parse("2006", date); err == nil {
+		return date + "-01"
+	}
+	return ""
+}
+
+// endMonth is month for an end date, which may also mark a current position
+func endMonth(date string) string {
+	switch strings.ToLower(strings.TrimSpace(date)) {
+	case endPresent, "current", "now":
+		return endPresent
+	}
+	return month(date)
+}
+
+// degreeCode maps the model's degree to the experience bank's codes
+func degreeCode(degree string) string {
+	degree = strings.ToLower(strings.TrimSpace(degree))
+	for _, code := range degreeCodes {
+		if degree == code {
+			return code
+		}
+	}
+	return "other"
+}
+
+// uniqueSkills trims skills and drops blank or repeated ones, keeping the first spelling
+func uniqueSkills(skills []string) []string {
+	out := make([]string, 0, len(skills))
+	seen := make(map[string]bool)
+	for _, s := range skills {
+		s = strings.TrimSpace(s)
+		key := db.NormalizeSkillName(s)
+		if s == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, s)
+	}
+	return out
+}
+
+func generate(ctx context.Context, prompt, apiKey string) (string, error) {
+	if apiKey == "" && llm.APIKeyRequired() {
+		return "", &APICallError{Message: "API key is required"}
+	}
+	client, err := llm.NewClient(ctx, llm.DefaultConfig(), apiKey)
+	if err != nil {
+		return "", &APICallError{Message: "failed to create LLM client", Cause: err}
+	}
+	defer func() { _ = client.Close() }()
+
+	response, err := client.GenerateJSON(ctx, prompt, llm.TierAdvanced)
+	if err != nil {
+		return "", &APICallError{Message: "failed to generate content from LLM", Cause: err}
+	}
+	return response, nil
+}
@@ -0,0 +1,74 @@
+package resumeimport
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+)
+
+const resumeText = "Jane Doe\n\nAcme Corp, Senior Engineer, 2020 - Present\n- Cut p99 latency 40% with Redis caching in Go\n\nState University, B.S. Computer Science, 2018"
+
+func TestExtractBank(t *testing.T) {
+	user := uuid.New()
+	ctx := llm.WithCassette(context.Background(), llm.NewCassette([]llm.Exchange{{
+		Prompt: "The following is the text of a job seeker's existing resume", JSON: true,
+		Response: "```json\n" + `{
+			"stories": [
+				{"company": " Acme Corp ", "role": "Senior Engineer", "start_date": "2020", "end_date": "Present",
+				 "bullets": [
+					{"text": "Cut p99 latency 40% with Redis caching in Go", "metrics": "40%", "skills": ["Go", "Redis", "golang", " "]},
+					{"text": "  "}
+				 ]},
+				{"company": "", "role": "Contractor", "bullets": [{"text": "Orphaned bullet"}]}
+			],
+			"education": [
+				{"school": "State University", "degree": "Bachelor", "field": "Computer Science", "end_date": "2018-05", "highlights": ["Dean's list", ""]},
+				{"school": "Coding Bootcamp", "degree": "certificate", "start_date": "spring"}
+			]
+		}` + "\n```",
+	}}))
+
+	bank, err := ExtractBank(ctx, user, resumeText, "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, user, bank.UserID)
+
+	require.Len(t, bank.Stories, 1, "positions without a company are dropped")
+	story := bank.Stories[0]
+	assert.Equal(t, "Acme Corp", story.Company)
+	assert.Equal(t, "2020-01", story.StartDate)
+	assert.Equal(t, "present", story.EndDate)
+	assert.True(t, strings.HasPrefix(story.ID, "import-"))
+	require.Len(t, story.Bullets, 1, "blank bullets are dropped")
+	bullet := story.Bullets[0]
+	assert.Equal(t, story.ID+"-1", bullet.ID)
+	assert.Equal(t, []string{"Go", "Redis"}, bullet.Skills)
+	assert.Equal(t, db.EvidenceStrengthHigh, bullet.EvidenceStrength)
+	assert.Equal(t, len(bullet.Text), bullet.LengthChars)
+	assert.Empty(t, bullet.RiskFlags)
+
+	require.Len(t, bank.Education, 2)
+	assert.Equal(t, db.DegreeBachelor, bank.Education[0].Degree)
+	assert.Equal(t, []string{"Dean's list"}, bank.Education[0].Highlights)
+	assert.Equal(t, "other", bank.Education[1].Degree)
+	assert.Empty(t, bank.Education[1].StartDate)
+}
+
+func TestExtractBank_NothingFound(t *testing.T) {
+	ctx := llm.WithCassette(context.Background(), llm.NewCassette([]llm.Exchange{{
+		Prompt: "The following is the text of a job seeker's existing resume", JSON: true, Response: `{"stories":[],"education":[]}`,
+	}}))
+	_, err := ExtractBank(ctx, uuid.New(), resumeText, "test-key")
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+
+	_, err = ExtractBank(ctx, uuid.New(), "  ", "test-key")
+	assert.ErrorIs(t, err, ErrNoText)
+}
@@ -0,0 +1,207 @@
+package resumeimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/toolchain"
+)
+
+// Document formats accepted for import, by file extension
+const (
+	FormatPDF  = ".pdf"
+	FormatDOCX = ".docx"
+)
+
+// Extensions lists the accepted document formats
+var Extensions = []string{FormatPDF, FormatDOCX}
+
+const (
+	// MaxUploadBytes bounds an uploaded resume; a few pages with embedded fonts is well under it
+	MaxUploadBytes = 10 << 20
+	// MaxTextLength caps the text sent to the model; a long CV is about half of it
+	MaxTextLength = 40000
+	// maxDocumentXML bounds the decompressed body of a DOCX, so a zip bomb cannot exhaust memory
+	maxDocumentXML = 20 << 20
+	// extractTimeout bounds the external program reading a PDF
+	extractTimeout = time.Minute
+)
+
+// ErrNoText is returned for documents with no extractable text, usually scanned pages
+var ErrNoText = errors.New("no text found in the document; scanned resumes must be converted to text first")
+
+// pdfMagic starts every PDF file
+var pdfMagic = []byte("%PDF-")
+
+// blankLines matches runs of blank lines, collapsed to one so page breaks do not pad the prompt
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// Supported reports whether filename has an accepted document extension
+func Supported(filename string) bool {
+	return slices.Contains(Extensions, strings.ToLower(filepath.Ext(filename)))
+}
+
+// ExtractText returns the plain text of the resume document named filename. PDFs are
+// read with pdftotext, falling back to Ghostscript; DOCX files are read directly.
+func ExtractText(ctx context.Context, filename string, data []byte) (string, error) {
+	var text string
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case FormatPDF:
+		text, err = pdfText(ctx, data)
+	case FormatDOCX:
+		text, err = docxText(data)
+	default:
+		return "", &DocumentError{Message: "unsupported document format; use one of " + strings.Join(Extensions, ", ")}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text = cleanText(text)
+	if text == "" {
+		return "", ErrNoText
+	}
+	if len(text) > MaxTextLength {
+		return "", &DocumentError{Message: fmt.Sprintf("document has more than %d characters of text", MaxTextLength)}
+	}
+	return text, nil
+}
+
+// pdfText extracts a PDF's text in reading order. It returns an error wrapping
+// toolchain.ErrUnavailable when neither pdftotext nor Ghostscript is installed.
+func pdfText(ctx context.Context, data []byte) (string, error) {
+	if !bytes.HasPrefix(data, pdfMagic) {
+		return "", &DocumentError{Message: "document is not a PDF"}
+	}
+	tools, err := toolchain.Default()
+	if err != nil {
+		return "", err
+	}
+	if tools.PDFToText == "" && tools.Ghostscript == "" {
+		return "", fmt.Errorf("%w: install poppler-utils (pdftotext) or Ghostscript to import PDFs", toolchain.ErrUnavailable)
+	}
+
+	dir, err := os.MkdirTemp("", "resume-import-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	pdfPath := filepath.Join(dir, "resume.pdf")
+	if err := os.WriteFile(pdfPath, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write temp PDF file: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, extractTimeout)
+	defer cancel()
+	var lastErr error
+	if tools.PDFToText != "" {
+		text, err := runText(ctx, tools.PDFToText, "-q", "-enc", "UTF-8", pdfPath, "-")
+		if err == nil {
+			return text, nil
+		}
+		lastErr = fmt.Errorf("pdftotext command failed: %w", err)
+	}
+	if tools.Ghostscript != "" {
+		text, err := runText(ctx, tools.Ghostscript, "-q", "-dSAFER", "-dBATCH", "-dNOPAUSE",
+			"-sDEVICE=txtwrite", "-sOutputFile=-", filepath.ToSlash(pdfPath))
+		if err == nil {
+			return text, nil
+		}
+		lastErr = fmt.Errorf("ghostscript command failed: %w", err)
+	}
+	return "", &DocumentError{Message: "failed to read PDF", Cause: lastErr}
+}
+
+// runText runs a text extractor and returns what it printed
+func runText(ctx context.Context, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.String(), nil
+}
+
+// docxText extracts the paragraphs of a Word document's body. Deleted tracked changes
+// and field codes are skipped; headers and footers, which hold contact details at most,
+// are not read.
+func docxText(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", &DocumentError{Message: "document is not a DOCX file", Cause: err}
+	}
+	f, err := zr.Open("word/document.xml")
+	if err != nil {
+		return "", &DocumentError{Message: "DOCX file has no document body", Cause: err}
+	}
+	defer func() { _ = f.Close() }()
+	body, err := io.ReadAll(io.LimitReader(f, maxDocumentXML+1))
+	if err != nil {
+		return "", &DocumentError{Message: "failed to read DOCX file", Cause: err}
+	}
+	if len(body) > maxDocumentXML {
+		return "", &DocumentError{Message: "DOCX document body is too large"}
+	}
+
+	var b strings.Builder
+	inText := false
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", &DocumentError{Message: "failed to parse DOCX file", Cause: err}
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				b.WriteByte('\t')
+			case "br", "cr":
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				b.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// cleanText normalizes line endings and page breaks, trims trailing space from each
+// line, and collapses runs of blank lines
+func cleanText(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.NewReplacer("\r\n", "\n", "\r", "\n", "\f", "\n", "\x00", "").Replace(text)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
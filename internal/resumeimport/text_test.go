@@ -0,0 +1,69 @@
+package resumeimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// docxFixture builds a minimal Word document with the given document.xml body
+func docxFixture(t *testing.T, body string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("word/document.xml")
+	require.NoError(t, err)
+	_, err = f.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` + body + `</w:body></w:document>`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestExtractText_DOCX(t *testing.T) {
+	data := docxFixture(t, `<w:p><w:r><w:t>Acme Corp</w:t></w:r><w:r><w:tab/><w:t xml:space="preserve">Senior Engineer  </w:t></w:r></w:p>`+
+		`<w:p/><w:p/><w:p/>`+
+		`<w:p><w:r><w:t>Cut p99 latency 40% for R&amp;D</w:t><w:br/><w:t>with Redis</w:t></w:r>`+
+		`<w:r><w:delText>deleted</w:delText><w:instrText>PAGE</w:instrText></w:r></w:p>`)
+
+	text, err := ExtractText(context.Background(), "Resume.DOCX", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Corp\tSenior Engineer\n\nCut p99 latency 40% for R&D\nwith Redis", text)
+}
+
+func TestExtractText_Rejects(t *testing.T) {
+	var docErr *DocumentError
+
+	_, err := ExtractText(context.Background(), "resume.txt", []byte("text"))
+	assert.True(t, errors.As(err, &docErr), "unsupported format")
+
+	_, err = ExtractText(context.Background(), "resume.pdf", []byte("<html>not a pdf</html>"))
+	assert.True(t, errors.As(err, &docErr), "PDFs are checked before any program runs")
+
+	_, err = ExtractText(context.Background(), "resume.docx", []byte("not a zip"))
+	assert.True(t, errors.As(err, &docErr))
+
+	_, err = ExtractText(context.Background(), "resume.docx", docxFixture(t, `<w:p><w:r><w:t>  </w:t></w:r></w:p>`))
+	assert.ErrorIs(t, err, ErrNoText)
+
+	long := bytes.Repeat([]byte("word "), MaxTextLength/4)
+	_, err = ExtractText(context.Background(), "resume.docx", docxFixture(t, `<w:p><w:r><w:t>`+string(long)+`</w:t></w:r></w:p>`))
+	assert.True(t, errors.As(err, &docErr), "text over the limit")
+}
+
+func TestSupported(t *testing.T) {
+	assert.True(t, Supported("cv.PDF"))
+	assert.True(t, Supported("cv.docx"))
+	assert.False(t, Supported("cv.doc"))
+	assert.False(t, Supported("cv"))
+}
+
+func TestCleanText(t *testing.T) {
+	assert.Equal(t, "Page one\n\nPage two", cleanText("Page one  \r\n\f\n\n\nPage two\f"))
+	assert.Equal(t, "ok", cleanText("o\x00k\xff"))
+}
@@ -0,0 +1,218 @@
+package rewriting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/prompts"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// DefaultBatchSize is the number of bullets rewritten per LLM call when
+// REWRITE_BATCH_SIZE is not set
+const DefaultBatchSize = 8
+
+// overflowMarkers are fragments of provider errors that mean the prompt or
+// response did not fit the model's context window
+var overflowMarkers = []string{
+	"context length",
+	"context window",
+	"token count",
+	"too many tokens",
+	"maximum number of tokens",
+	"request payload size",
+	"too long",
+}
+
+// batchResponse is the structured output expected from a batch rewriting prompt
+type batchResponse struct {
+	Bullets []struct {
+		ID   string `json:"id"`
+		Text string `json:"text"`
+	} `json:"bullets"`
+}
+
+// resolveBatchSize returns REWRITE_BATCH_SIZE, or DefaultBatchSize when it is unset
+// or invalid. A size of 1 rewrites every bullet with its own prompt.
+func resolveBatchSize() int {
+	if v := os.Getenv("REWRITE_BATCH_SIZE"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return DefaultBatchSize
+}
+
+type batchSizeKey struct{}
+
+// WithBatchSize returns a context whose rewrites send n bullets per LLM call,
+// overriding REWRITE_BATCH_SIZE
+func WithBatchSize(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, batchSizeKey{}, n)
+}
+
+// batchSizeFor returns the batch size attached to ctx, else resolveBatchSize
+func batchSizeFor(ctx context.Context) int {
+	if n, ok := ctx.Value(batchSizeKey{}).(int); ok && n > 0 {
+		return n
+	}
+	return resolveBatchSize()
+}
+
+// rewriteBullets rewrites bullets in batches of up to batchSize per LLM call,
+// keeping input order. Verbs used by earlier batches (and initialUsedVerbs) are
+// passed to later ones so leading verbs stay varied across the resume.
+func rewriteBullets(ctx context.Context, client llm.Client, bullets []types.SelectedBullet, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, initialUsedVerbs []string, batchSize int) ([]types.RewrittenBullet, error) {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	usedVerbs := make([]string, len(initialUsedVerbs))
+	copy(usedVerbs, initialUsedVerbs)
+
+	rewritten := make([]types.RewrittenBullet, 0, len(bullets))
+	for start := 0; start < len(bullets); start += batchSize {
+		end := min(start+batchSize, len(bullets))
+		batch, err := rewriteBatch(ctx, client, bullets[start:end], jobProfile, companyProfile, usedVerbs)
+		if err != nil {
+			return nil, err
+		}
+		for _, bullet := range batch {
+			if verb := extractLeadingVerb(bullet.FinalText); verb != "" {
+				usedVerbs = append(usedVerbs, verb)
+			}
+		}
+		rewritten = append(rewritten, batch...)
+	}
+	return rewritten, nil
+}
+
+// rewriteBatch rewrites bullets with a single structured prompt. If the batch
+// overflows the model's context or the response is incomplete, it is split in
+// half and each half is retried; a single bullet uses the per-bullet prompt.
+func rewriteBatch(ctx context.Context, client llm.Client, bullets []types.SelectedBullet, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, usedVerbs []string) ([]types.RewrittenBullet, error) {
+	if len(bullets) == 1 {
+		bullet, err := rewriteSingleBullet(ctx, client, bullets[0], jobProfile, companyProfile, usedVerbs)
+		if err != nil {
+			return nil, err
+		}
+		return []types.RewrittenBullet{*bullet}, nil
+	}
+
+	prompt := buildBatchRewritingPrompt(bullets, jobProfile, companyProfile, usedVerbs)
+	responseText, err := client.GenerateJSON(ctx, prompt, llm.TierAdvanced)
+	if err != nil {
+		if !isContextOverflow(err) {
+			return nil, &APICallError{
+				Message: fmt.Sprintf("failed to generate content for %d bullets", len(bullets)),
+				Cause:   err,
+			}
+		}
+		return splitBatch(ctx, client, bullets, jobProfile, companyProfile, usedVerbs)
+	}
+
+	texts, err := parseBatchResponse(responseText, bullets)
+	if err != nil {
+		// Truncated or malformed output usually means the batch was too large
+		return splitBatch(ctx, client, bullets, jobProfile, companyProfile, usedVerbs)
+	}
+
+	rewritten := make([]types.RewrittenBullet, 0, len(bullets))
+	for i, originalBullet := range bullets {
+		bullet, err := postProcessBullet(texts[i], originalBullet, companyProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to post-process bullet %s: %w", originalBullet.ID, err)
+		}
+		rewritten = append(rewritten, *bullet)
+	}
+	return rewritten, nil
+}
+
+// splitBatch rewrites the two halves of a batch separately
+func splitBatch(ctx context.Context, client llm.Client, bullets []types.SelectedBullet, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, usedVerbs []string) ([]types.RewrittenBullet, error) {
+	mid := len(bullets) / 2
+	first, err := rewriteBatch(ctx, client, bullets[:mid], jobProfile, companyProfile, usedVerbs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The second half should avoid the verbs the first half just used
+	verbs := append([]string{}, usedVerbs...)
+	for _, bullet := range first {
+		if verb := extractLeadingVerb(bullet.FinalText); verb != "" {
+			verbs = append(verbs, verb)
+		}
+	}
+	second, err := rewriteBatch(ctx, client, bullets[mid:], jobProfile, companyProfile, verbs)
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+// isContextOverflow reports whether err indicates the request exceeded the model's context
+func isContextOverflow(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, marker := range overflowMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildBatchRewritingPrompt constructs the prompt for rewriting several bullets at once
+func buildBatchRewritingPrompt(bullets []types.SelectedBullet, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, usedVerbs []string) string {
+	var sb strings.Builder
+
+	var list strings.Builder
+	for _, bullet := range bullets {
+		list.WriteString(fmt.Sprintf("- id: %s (target length: ~%d characters)\n  text: %s\n", bullet.ID, bullet.LengthChars, bullet.Text))
+	}
+	introTemplate := prompts.MustGet("rewriting.json", "rewrite-batch-intro")
+	sb.WriteString(prompts.Format(introTemplate, map[string]string{
+		"Count":   fmt.Sprintf("%d", len(bullets)),
+		"Bullets": list.String(),
+	}))
+
+	writeJobContext(&sb, jobProfile)
+	writeVoiceContext(&sb, companyProfile)
+
+	// Add preservation constraints to prevent hallucination
+	sb.WriteString(prompts.MustGet("rewriting.json", "rewrite-bullet-preservation"))
+
+	reqsTemplate := prompts.MustGet("rewriting.json", "rewrite-batch-requirements")
+	sb.WriteString(prompts.Format(reqsTemplate, map[string]string{
+		"UsedVerbs": strings.Join(usedVerbs, ", "),
+	}))
+
+	return sb.String()
+}
+
+// parseBatchResponse returns the rewritten text for each bullet, in input order.
+// It fails if any bullet is missing or empty so the caller can retry smaller batches.
+func parseBatchResponse(responseText string, bullets []types.SelectedBullet) ([]string, error) {
+	var resp batchResponse
+	if err := json.Unmarshal([]byte(responseText), &resp); err != nil {
+		return nil, &ParseError{Message: "failed to parse batch rewriting response", Cause: err}
+	}
+
+	byID := make(map[string]string, len(resp.Bullets))
+	for _, bullet := range resp.Bullets {
+		byID[bullet.ID] = strings.TrimSpace(bullet.Text)
+	}
+
+	texts := make([]string, len(bullets))
+	for i, bullet := range bullets {
+		text := byID[bullet.ID]
+		if text == "" {
+			return nil, &ParseError{Message: fmt.Sprintf("batch response is missing bullet %s", bullet.ID)}
+		}
+		texts[i] = text
+	}
+	return texts, nil
+}
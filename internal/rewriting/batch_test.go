@@ -0,0 +1,188 @@
+package rewriting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var promptIDPattern = regexp.MustCompile(`(?m)^- id: (\S+)`)
+
+// batchClient answers batch prompts by echoing each bullet ID, and fails with
+// a context overflow error when a prompt holds more than maxBullets bullets
+type batchClient struct {
+	maxBullets  int
+	jsonCalls   int
+	singleCalls int
+	dropLast    bool // Omit the last bullet from responses with more than one bullet
+}
+
+func (c *batchClient) GenerateContent(_ context.Context, _ string, _ llm.ModelTier) (string, error) {
+	c.singleCalls++
+	return fmt.Sprintf("Delivered single %d", c.singleCalls), nil
+}
+
+func (c *batchClient) GenerateJSON(_ context.Context, prompt string, _ llm.ModelTier) (string, error) {
+	c.jsonCalls++
+	matches := promptIDPattern.FindAllStringSubmatch(prompt, -1)
+	if c.maxBullets > 0 && len(matches) > c.maxBullets {
+		return "", errors.New("input token count exceeds the maximum number of tokens allowed")
+	}
+	var resp batchResponse
+	for i, m := range matches {
+		if c.dropLast && len(matches) > 1 && i == len(matches)-1 {
+			continue
+		}
+		resp.Bullets = append(resp.Bullets, struct {
+			ID   string `json:"id"`
+			Text string `json:"text"`
+		}{ID: m[1], Text: fmt.Sprintf("Built %s with 20%% faster results", m[1])})
+	}
+	out, _ := json.Marshal(resp)
+	return string(out), nil
+}
+
+func (c *batchClient) GetModel(_ llm.ModelTier) string { return "mock-model" }
+
+func (c *batchClient) Close() error { return nil }
+
+func testBullets(n int) []types.SelectedBullet {
+	bullets := make([]types.SelectedBullet, n)
+	for i := range bullets {
+		bullets[i] = types.SelectedBullet{
+			ID:          fmt.Sprintf("bullet_%03d", i+1),
+			Text:        fmt.Sprintf("Did thing %d", i+1),
+			LengthChars: 40,
+		}
+	}
+	return bullets
+}
+
+func TestRewriteBullets_Batches(t *testing.T) {
+	client := &batchClient{}
+	bullets := testBullets(10)
+
+	got, err := rewriteBullets(context.Background(), client, bullets, nil, nil, nil, 4)
+	require.NoError(t, err)
+	require.Len(t, got, 10)
+	assert.Equal(t, 3, client.jsonCalls, "10 bullets in batches of 4")
+	assert.Equal(t, 0, client.singleCalls)
+	for i, bullet := range got {
+		assert.Equal(t, bullets[i].ID, bullet.OriginalBulletID, "input order is kept")
+		assert.Contains(t, bullet.FinalText, bullets[i].ID)
+	}
+}
+
+func TestRewriteBullets_SplitsOnContextOverflow(t *testing.T) {
+	client := &batchClient{maxBullets: 2}
+
+	got, err := rewriteBullets(context.Background(), client, testBullets(8), nil, nil, nil, 8)
+	require.NoError(t, err)
+	require.Len(t, got, 8)
+	// 8 overflows, both 4s overflow, then four batches of 2 succeed
+	assert.Equal(t, 7, client.jsonCalls)
+	assert.Equal(t, 0, client.singleCalls)
+}
+
+func TestRewriteBullets_SplitsOnIncompleteResponse(t *testing.T) {
+	client := &batchClient{dropLast: true}
+
+	got, err := rewriteBullets(context.Background(), client, testBullets(2), nil, nil, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	// The incomplete batch is split down to single-bullet prompts
+	assert.Equal(t, 1, client.jsonCalls)
+	assert.Equal(t, 2, client.singleCalls)
+	assert.Equal(t, "bullet_002", got[1].OriginalBulletID)
+}
+
+func TestRewriteBullets_BatchSizeOneUsesSinglePrompts(t *testing.T) {
+	client := &batchClient{}
+
+	got, err := rewriteBullets(context.Background(), client, testBullets(3), nil, nil, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, 0, client.jsonCalls)
+	assert.Equal(t, 3, client.singleCalls)
+}
+
+func TestRewriteBullets_APIErrorIsNotSplit(t *testing.T) {
+	client := &failingClient{err: errors.New("permission denied")}
+
+	_, err := rewriteBullets(context.Background(), client, testBullets(4), nil, nil, nil, 4)
+	var apiErr *APICallError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 1, client.calls)
+}
+
+// failingClient fails every call with err
+type failingClient struct {
+	batchClient
+	err   error
+	calls int
+}
+
+func (c *failingClient) GenerateJSON(_ context.Context, _ string, _ llm.ModelTier) (string, error) {
+	c.calls++
+	return "", c.err
+}
+
+func TestBuildBatchRewritingPrompt(t *testing.T) {
+	jobProfile := &types.JobProfile{Keywords: []string{"microservices"}}
+	companyProfile := &types.CompanyProfile{Tone: "direct", TabooPhrases: []string{"synergy"}}
+
+	prompt := buildBatchRewritingPrompt(testBullets(2), jobProfile, companyProfile, []string{"Led"})
+
+	assert.Contains(t, prompt, "- id: bullet_001")
+	assert.Contains(t, prompt, "Did thing 2")
+	assert.Contains(t, prompt, "microservices")
+	assert.Contains(t, prompt, "synergy")
+	assert.Contains(t, prompt, "already-used verbs: Led")
+	assert.Contains(t, prompt, `"bullets"`)
+	assert.NotContains(t, prompt, "{{.")
+}
+
+func TestParseBatchResponse(t *testing.T) {
+	bullets := testBullets(2)
+
+	texts, err := parseBatchResponse(`{"bullets": [{"id": "bullet_002", "text": " Second "}, {"id": "bullet_001", "text": "First"}]}`, bullets)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"First", "Second"}, texts)
+
+	_, err = parseBatchResponse(`{"bullets": [{"id": "bullet_001", "text": "First"}]}`, bullets)
+	assert.Error(t, err)
+
+	_, err = parseBatchResponse(`not json`, bullets)
+	assert.Error(t, err)
+}
+
+func TestIsContextOverflow(t *testing.T) {
+	assert.True(t, isContextOverflow(errors.New("Error 400: The input token count (1200000) exceeds the maximum")))
+	assert.True(t, isContextOverflow(errors.New("prompt is too long")))
+	assert.False(t, isContextOverflow(errors.New("permission denied")))
+}
+
+func TestResolveBatchSize(t *testing.T) {
+	t.Setenv("REWRITE_BATCH_SIZE", "")
+	assert.Equal(t, DefaultBatchSize, resolveBatchSize())
+
+	t.Setenv("REWRITE_BATCH_SIZE", "3")
+	assert.Equal(t, 3, resolveBatchSize())
+
+	t.Setenv("REWRITE_BATCH_SIZE", "0")
+	assert.Equal(t, DefaultBatchSize, resolveBatchSize())
+}
+
+func TestBatchSizeFor(t *testing.T) {
+	t.Setenv("REWRITE_BATCH_SIZE", "3")
+	assert.Equal(t, 3, batchSizeFor(context.Background()))
+	assert.Equal(t, 12, batchSizeFor(WithBatchSize(context.Background(), 12)))
+}
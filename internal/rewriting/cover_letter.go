@@ -0,0 +1,92 @@
+package rewriting
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/prompts"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// WriteCoverLetter writes a plain-text cover letter for the job from the candidate's
+// rewritten bullets, in the company's voice, signed with candidateName. The company
+// profile is optional context.
+func WriteCoverLetter(ctx context.Context, bullets *types.RewrittenBullets, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, candidateName, apiKey string) (string, error) {
+	if apiKey == "" && llm.APIKeyRequired() {
+		return "", &APICallError{Message: "API key is required"}
+	}
+
+	client, err := llm.NewClient(ctx, llm.DefaultConfig(), apiKey)
+	if err != nil {
+		return "", &APICallError{
+			Message: "failed to create LLM client",
+			Cause:   err,
+		}
+	}
+	defer func() { _ = client.Close() }()
+
+	return writeCoverLetter(ctx, client, bullets, jobProfile, companyProfile, candidateName)
+}
+
+// writeCoverLetter is WriteCoverLetter with a given client
+func writeCoverLetter(ctx context.Context, client llm.Client, bullets *types.RewrittenBullets, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, candidateName string) (string, error) {
+	if bullets == nil || len(bullets.Bullets) == 0 {
+		return "", &ParseError{Message: "no rewritten bullets to write a cover letter from"}
+	}
+
+	responseText, err := client.GenerateContent(ctx, buildCoverLetterPrompt(bullets, jobProfile, companyProfile, candidateName), llm.TierAdvanced)
+	if err != nil {
+		return "", &APICallError{Message: "failed to generate cover letter", Cause: err}
+	}
+	letter, err := parseBulletResponse(responseText)
+	if err != nil {
+		return "", err
+	}
+	if letter == "" {
+		return "", &ParseError{Message: "cover letter response is empty"}
+	}
+	return letter, nil
+}
+
+// buildCoverLetterPrompt constructs the prompt for a cover letter
+func buildCoverLetterPrompt(bullets *types.RewrittenBullets, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, candidateName string) string {
+	var sb strings.Builder
+
+	roleTitle, company := "open", "the company"
+	if jobProfile != nil {
+		if jobProfile.RoleTitle != "" {
+			roleTitle = jobProfile.RoleTitle
+		}
+		if jobProfile.Company != "" {
+			company = jobProfile.Company
+		}
+	}
+	if candidateName == "" {
+		candidateName = "the candidate"
+	}
+	introTemplate := prompts.MustGet("rewriting.json", "cover-letter-intro")
+	sb.WriteString(prompts.Format(introTemplate, map[string]string{
+		"CandidateName": candidateName,
+		"RoleTitle":     roleTitle,
+		"Company":       company,
+	}))
+
+	writeJobContext(&sb, jobProfile)
+	writeVoiceContext(&sb, companyProfile)
+
+	var lines strings.Builder
+	for _, bullet := range bullets.Bullets {
+		lines.WriteString("- ")
+		lines.WriteString(bullet.FinalText)
+		lines.WriteString("\n")
+	}
+	bulletsTemplate := prompts.MustGet("rewriting.json", "cover-letter-bullets")
+	sb.WriteString(prompts.Format(bulletsTemplate, map[string]string{
+		"Bullets": lines.String(),
+	}))
+
+	sb.WriteString(prompts.MustGet("rewriting.json", "cover-letter-requirements"))
+
+	return sb.String()
+}
@@ -0,0 +1,56 @@
+package rewriting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// letterClient answers every text prompt with response, keeping the last prompt
+type letterClient struct {
+	batchClient
+	response string
+	prompt   string
+}
+
+func (c *letterClient) GenerateContent(_ context.Context, prompt string, _ llm.ModelTier) (string, error) {
+	c.prompt = prompt
+	return c.response, nil
+}
+
+func TestWriteCoverLetter(t *testing.T) {
+	client := &letterClient{response: "```\nDear Hiring Team,\n\nI cut CI time by 60%.\n\nAda Lovelace\n```"}
+	bullets := &types.RewrittenBullets{Bullets: []types.RewrittenBullet{
+		{OriginalBulletID: "b1", FinalText: "Cut CI time by 60% by caching build layers"},
+		{OriginalBulletID: "b2", FinalText: "Led migration of 40 services to Kubernetes"},
+	}}
+	jobProfile := &types.JobProfile{Company: "Acme", RoleTitle: "Platform Engineer", Keywords: []string{"Kubernetes"}}
+	companyProfile := &types.CompanyProfile{Tone: "direct", TabooPhrases: []string{"rockstar"}}
+
+	letter, err := writeCoverLetter(context.Background(), client, bullets, jobProfile, companyProfile, "Ada Lovelace")
+	require.NoError(t, err)
+	assert.Equal(t, "Dear Hiring Team,\n\nI cut CI time by 60%.\n\nAda Lovelace", letter, "code fences are stripped")
+
+	assert.Contains(t, client.prompt, "Ada Lovelace applying for the Platform Engineer role at Acme")
+	assert.Contains(t, client.prompt, "- Cut CI time by 60% by caching build layers\n")
+	assert.Contains(t, client.prompt, "- Led migration of 40 services to Kubernetes\n")
+	assert.Contains(t, client.prompt, "rockstar")
+	assert.Contains(t, client.prompt, "Do NOT invent experience")
+}
+
+func TestWriteCoverLetterErrors(t *testing.T) {
+	var parseErr *ParseError
+
+	_, err := writeCoverLetter(context.Background(), &letterClient{response: "Dear Hiring Team"}, &types.RewrittenBullets{}, nil, nil, "")
+	assert.ErrorAs(t, err, &parseErr, "a letter needs bullets")
+
+	bullets := &types.RewrittenBullets{Bullets: []types.RewrittenBullet{{FinalText: "Cut CI time by 60%"}}}
+	client := &letterClient{response: "  "}
+	_, err = writeCoverLetter(context.Background(), client, bullets, nil, nil, "")
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Contains(t, client.prompt, "the candidate applying for the open role at the company")
+}
@@ -14,7 +14,7 @@ import (
 
 // RewriteBullets rewrites selected bullets to match job requirements and company voice
 func RewriteBullets(ctx context.Context, selectedBullets *types.SelectedBullets, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, apiKey string) (*types.RewrittenBullets, error) {
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, &APICallError{Message: "API key is required"}
 	}
 
@@ -29,43 +29,10 @@ func RewriteBullets(ctx context.Context, selectedBullets *types.SelectedBullets,
 	}
 	defer func() { _ = client.Close() }()
 
-	// Track used verbs across the entire resume for diversity
-	usedVerbs := []string{}
-
-	// Rewrite each bullet
-	rewrittenBullets := make([]types.RewrittenBullet, 0, len(selectedBullets.Bullets))
-
-	for _, originalBullet := range selectedBullets.Bullets {
-		// Build rewriting prompt with verbs to avoid
-		prompt := buildRewritingPrompt(originalBullet, jobProfile, companyProfile, usedVerbs)
-
-		// Use TierAdvanced for bullet rewriting (requires nuance and style matching)
-		responseText, err := client.GenerateContent(ctx, prompt, llm.TierAdvanced)
-		if err != nil {
-			return nil, &APICallError{
-				Message: fmt.Sprintf("failed to generate content for bullet %s", originalBullet.ID),
-				Cause:   err,
-			}
-		}
-
-		// Parse response (expects just the rewritten text)
-		rewrittenText, err := parseBulletResponse(responseText)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse response for bullet %s: %w", originalBullet.ID, err)
-		}
-
-		// Extract leading verb and add to used verbs list
-		if verb := extractLeadingVerb(rewrittenText); verb != "" {
-			usedVerbs = append(usedVerbs, verb)
-		}
-
-		// Post-process bullet
-		rewrittenBullet, err := postProcessBullet(rewrittenText, originalBullet, companyProfile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to post-process bullet %s: %w", originalBullet.ID, err)
-		}
-
-		rewrittenBullets = append(rewrittenBullets, *rewrittenBullet)
+	// Rewrite in batches, tracking used verbs across the entire resume for diversity
+	rewrittenBullets, err := rewriteBullets(ctx, client, selectedBullets.Bullets, jobProfile, companyProfile, nil, batchSizeFor(ctx))
+	if err != nil {
+		return nil, err
 	}
 
 	return &types.RewrittenBullets{
@@ -169,7 +136,7 @@ func RewriteBulletsSelective(
 	}
 
 	// Check API key is required for rewriting
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, &APICallError{Message: "API key is required"}
 	}
 
@@ -236,49 +203,42 @@ func rewriteBulletsWithVerbs(ctx context.Context, selectedBullets *types.Selecte
 	}
 	defer func() { _ = client.Close() }()
 
-	// Start with initial used verbs
-	usedVerbs := make([]string, len(initialUsedVerbs))
-	copy(usedVerbs, initialUsedVerbs)
-
-	// Rewrite each bullet
-	rewrittenBullets := make([]types.RewrittenBullet, 0, len(selectedBullets.Bullets))
-
-	for _, originalBullet := range selectedBullets.Bullets {
-		// Build rewriting prompt with verbs to avoid
-		prompt := buildRewritingPrompt(originalBullet, jobProfile, companyProfile, usedVerbs)
-
-		// Use TierAdvanced for bullet rewriting (requires nuance and style matching)
-		responseText, err := client.GenerateContent(ctx, prompt, llm.TierAdvanced)
-		if err != nil {
-			return nil, &APICallError{
-				Message: fmt.Sprintf("failed to generate content for bullet %s", originalBullet.ID),
-				Cause:   err,
-			}
-		}
+	rewrittenBullets, err := rewriteBullets(ctx, client, selectedBullets.Bullets, jobProfile, companyProfile, initialUsedVerbs, batchSizeFor(ctx))
+	if err != nil {
+		return nil, err
+	}
 
-		// Parse response (expects just the rewritten text)
-		rewrittenText, err := parseBulletResponse(responseText)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse response for bullet %s: %w", originalBullet.ID, err)
-		}
+	return &types.RewrittenBullets{
+		Bullets: rewrittenBullets,
+	}, nil
+}
 
-		// Extract leading verb and add to used verbs list
-		if verb := extractLeadingVerb(rewrittenText); verb != "" {
-			usedVerbs = append(usedVerbs, verb)
-		}
+// rewriteSingleBullet rewrites one bullet with its own prompt
+func rewriteSingleBullet(ctx context.Context, client llm.Client, originalBullet types.SelectedBullet, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, usedVerbs []string) (*types.RewrittenBullet, error) {
+	// Build rewriting prompt with verbs to avoid
+	prompt := buildRewritingPrompt(originalBullet, jobProfile, companyProfile, usedVerbs)
 
-		// Post-process bullet
-		rewrittenBullet, err := postProcessBullet(rewrittenText, originalBullet, companyProfile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to post-process bullet %s: %w", originalBullet.ID, err)
+	// Use TierAdvanced for bullet rewriting (requires nuance and style matching)
+	responseText, err := client.GenerateContent(ctx, prompt, llm.TierAdvanced)
+	if err != nil {
+		return nil, &APICallError{
+			Message: fmt.Sprintf("failed to generate content for bullet %s", originalBullet.ID),
+			Cause:   err,
 		}
+	}
 
-		rewrittenBullets = append(rewrittenBullets, *rewrittenBullet)
+	// Parse response (expects just the rewritten text)
+	rewrittenText, err := parseBulletResponse(responseText)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response for bullet %s: %w", originalBullet.ID, err)
 	}
 
-	return &types.RewrittenBullets{
-		Bullets: rewrittenBullets,
-	}, nil
+	// Post-process bullet
+	rewrittenBullet, err := postProcessBullet(rewrittenText, originalBullet, companyProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post-process bullet %s: %w", originalBullet.ID, err)
+	}
+	return rewrittenBullet, nil
 }
 
 // extractLeadingVerb extracts the first word (assumed to be a verb) from a bullet point
@@ -305,52 +265,8 @@ func buildRewritingPrompt(bullet types.SelectedBullet, jobProfile *types.JobProf
 		"BulletText": bullet.Text,
 	}))
 
-	// Add job requirements context (dynamic)
-	if jobProfile != nil {
-		sb.WriteString("Job requirements:\n")
-		if len(jobProfile.HardRequirements) > 0 {
-			sb.WriteString("- Hard requirements: ")
-			reqs := make([]string, len(jobProfile.HardRequirements))
-			for i, req := range jobProfile.HardRequirements {
-				reqs[i] = req.Skill
-			}
-			sb.WriteString(strings.Join(reqs, ", "))
-			sb.WriteString("\n")
-		}
-		if len(jobProfile.NiceToHaves) > 0 {
-			sb.WriteString("- Preferred skills: ")
-			reqs := make([]string, len(jobProfile.NiceToHaves))
-			for i, req := range jobProfile.NiceToHaves {
-				reqs[i] = req.Skill
-			}
-			sb.WriteString(strings.Join(reqs, ", "))
-			sb.WriteString("\n")
-		}
-		if len(jobProfile.Keywords) > 0 {
-			sb.WriteString("- Keywords: ")
-			sb.WriteString(strings.Join(jobProfile.Keywords, ", "))
-			sb.WriteString("\n")
-		}
-		sb.WriteString("\n")
-	}
-
-	// Add company voice context (dynamic)
-	if companyProfile != nil {
-		sb.WriteString("Company brand voice:\n")
-		sb.WriteString(fmt.Sprintf("- Tone: %s\n", companyProfile.Tone))
-		if len(companyProfile.StyleRules) > 0 {
-			sb.WriteString("- Style rules:\n")
-			for _, rule := range companyProfile.StyleRules {
-				sb.WriteString(fmt.Sprintf("  * %s\n", rule))
-			}
-		}
-		if len(companyProfile.TabooPhrases) > 0 {
-			sb.WriteString("- Avoid these phrases: ")
-			sb.WriteString(strings.Join(companyProfile.TabooPhrases, ", "))
-			sb.WriteString("\n")
-		}
-		sb.WriteString("\n")
-	}
+	writeJobContext(&sb, jobProfile)
+	writeVoiceContext(&sb, companyProfile)
 
 	// Add preservation constraints to prevent hallucination
 	preservationTemplate := prompts.MustGet("rewriting.json", "rewrite-bullet-preservation")
@@ -429,3 +345,63 @@ func postProcessBullet(rewrittenText string, originalBullet types.SelectedBullet
 		},
 	}, nil
 }
+
+// writeJobContext appends the job requirements section of a rewriting prompt
+func writeJobContext(sb *strings.Builder, jobProfile *types.JobProfile) {
+	// Add job requirements context (dynamic)
+	if jobProfile != nil {
+		sb.WriteString("Job requirements:\n")
+		if len(jobProfile.HardRequirements) > 0 {
+			sb.WriteString("- Hard requirements: ")
+			reqs := make([]string, len(jobProfile.HardRequirements))
+			for i, req := range jobProfile.HardRequirements {
+				reqs[i] = req.Skill
+			}
+			sb.WriteString(strings.Join(reqs, ", "))
+			sb.WriteString("\n")
+		}
+		if len(jobProfile.NiceToHaves) > 0 {
+			sb.WriteString("- Preferred skills: ")
+			reqs := make([]string, len(jobProfile.NiceToHaves))
+			for i, req := range jobProfile.NiceToHaves {
+				reqs[i] = req.Skill
+			}
+			sb.WriteString(strings.Join(reqs, ", "))
+			sb.WriteString("\n")
+		}
+		if len(jobProfile.Keywords) > 0 {
+			sb.WriteString("- Keywords: ")
+			sb.WriteString(strings.Join(jobProfile.Keywords, ", "))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// writeVoiceContext appends the company brand voice section of a rewriting prompt
+func writeVoiceContext(sb *strings.Builder, companyProfile *types.CompanyProfile) {
+	// Add company voice context (dynamic)
+	if companyProfile != nil {
+		sb.WriteString("Company brand voice:\n")
+		sb.WriteString(fmt.Sprintf("- Tone: %s\n", companyProfile.Tone))
+		if len(companyProfile.StyleRules) > 0 {
+			sb.WriteString("- Style rules:\n")
+			for _, rule := range companyProfile.StyleRules {
+				sb.WriteString(fmt.Sprintf("  * %s\n", rule))
+			}
+		}
+		if len(companyProfile.TabooPhrases) > 0 {
+			sb.WriteString("- Avoid these phrases: ")
+			phrases := make([]string, len(companyProfile.TabooPhrases))
+			for i, phrase := range companyProfile.TabooPhrases {
+				phrases[i] = phrase
+				if reason := companyProfile.TabooReason(phrase); reason != "" {
+					phrases[i] += " (" + reason + ")"
+				}
+			}
+			sb.WriteString(strings.Join(phrases, ", "))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+}
@@ -0,0 +1,122 @@
+package rewriting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/prompts"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Bounds on the variants RewriteVariants returns for one bullet
+const (
+	MinVariants     = 2
+	MaxVariants     = 3
+	DefaultVariants = 3
+)
+
+// variantsResponse is the structured output expected from a variants prompt
+type variantsResponse struct {
+	Variants []string `json:"variants"`
+}
+
+// RewriteVariants rewrites a single bullet count ways (between MinVariants and
+// MaxVariants) with the same prompts as a run's rewrite step. The job and company
+// profiles are optional context. Each variant carries its style checks; the bullet is
+// not from the experience bank, so variants have no original bullet ID.
+func RewriteVariants(ctx context.Context, bulletText string, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, count int, apiKey string) ([]types.RewrittenBullet, error) {
+	if apiKey == "" && llm.APIKeyRequired() {
+		return nil, &APICallError{Message: "API key is required"}
+	}
+
+	client, err := llm.NewClient(ctx, llm.DefaultConfig(), apiKey)
+	if err != nil {
+		return nil, &APICallError{
+			Message: "failed to create LLM client",
+			Cause:   err,
+		}
+	}
+	defer func() { _ = client.Close() }()
+
+	return rewriteVariants(ctx, client, bulletText, jobProfile, companyProfile, count)
+}
+
+// rewriteVariants is RewriteVariants with a given client
+func rewriteVariants(ctx context.Context, client llm.Client, bulletText string, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, count int) ([]types.RewrittenBullet, error) {
+	count = min(max(count, MinVariants), MaxVariants)
+	bullet := types.SelectedBullet{Text: bulletText, LengthChars: ComputeLengthChars(bulletText)}
+
+	responseText, err := client.GenerateJSON(ctx, buildVariantsPrompt(bullet, jobProfile, companyProfile, count), llm.TierAdvanced)
+	if err != nil {
+		return nil, &APICallError{Message: "failed to generate bullet variants", Cause: err}
+	}
+	texts, err := parseVariantsResponse(responseText, count)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := make([]types.RewrittenBullet, 0, len(texts))
+	for _, text := range texts {
+		variant, err := postProcessBullet(text, bullet, companyProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to post-process bullet variant: %w", err)
+		}
+		variants = append(variants, *variant)
+	}
+	return variants, nil
+}
+
+// buildVariantsPrompt constructs the prompt for rewriting one bullet several ways
+func buildVariantsPrompt(bullet types.SelectedBullet, jobProfile *types.JobProfile, companyProfile *types.CompanyProfile, count int) string {
+	var sb strings.Builder
+
+	introTemplate := prompts.MustGet("rewriting.json", "rewrite-bullet-intro")
+	sb.WriteString(prompts.Format(introTemplate, map[string]string{
+		"BulletText": bullet.Text,
+	}))
+
+	writeJobContext(&sb, jobProfile)
+	writeVoiceContext(&sb, companyProfile)
+
+	// Add preservation constraints to prevent hallucination
+	sb.WriteString(prompts.MustGet("rewriting.json", "rewrite-bullet-preservation"))
+
+	reqsTemplate := prompts.MustGet("rewriting.json", "rewrite-variants-requirements")
+	sb.WriteString(prompts.Format(reqsTemplate, map[string]string{
+		"Count":        fmt.Sprintf("%d", count),
+		"TargetLength": fmt.Sprintf("%d", bullet.LengthChars),
+	}))
+
+	return sb.String()
+}
+
+// parseVariantsResponse returns up to count distinct, non-empty variants in the order
+// the model gave them. It fails when there are none.
+func parseVariantsResponse(responseText string, count int) ([]string, error) {
+	var resp variantsResponse
+	if err := json.Unmarshal([]byte(responseText), &resp); err != nil {
+		return nil, &ParseError{Message: "failed to parse bullet variants response", Cause: err}
+	}
+
+	seen := make(map[string]bool)
+	texts := make([]string, 0, count)
+	for _, text := range resp.Variants {
+		text = strings.TrimSpace(text)
+		key := strings.ToLower(text)
+		if text == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		texts = append(texts, text)
+		if len(texts) == count {
+			break
+		}
+	}
+	if len(texts) == 0 {
+		return nil, &ParseError{Message: "bullet variants response has no variants"}
+	}
+	return texts, nil
+}
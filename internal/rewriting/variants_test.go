@@ -0,0 +1,59 @@
+package rewriting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// variantsClient answers every JSON prompt with response, keeping the last prompt
+type variantsClient struct {
+	batchClient
+	response string
+	prompt   string
+}
+
+func (c *variantsClient) GenerateJSON(_ context.Context, prompt string, _ llm.ModelTier) (string, error) {
+	c.prompt = prompt
+	return c.response, nil
+}
+
+func TestRewriteVariants(t *testing.T) {
+	client := &variantsClient{response: `{"variants": [
+		"Cut CI time by 60% by caching build layers",
+		" ",
+		"cut ci time by 60% by caching build layers",
+		"Rebuilt the CI cache, cutting build time 60%",
+		"Accelerated builds 60% through layer caching"
+	]}`}
+	jobProfile := &types.JobProfile{Keywords: []string{"CI/CD"}}
+	companyProfile := &types.CompanyProfile{Tone: "direct", TabooPhrases: []string{"synergy"}}
+
+	variants, err := rewriteVariants(context.Background(), client, "Made CI faster", jobProfile, companyProfile, 2)
+	require.NoError(t, err)
+	require.Len(t, variants, 2, "blank and repeated variants are skipped")
+	assert.Equal(t, "Cut CI time by 60% by caching build layers", variants[0].FinalText)
+	assert.Equal(t, "Rebuilt the CI cache, cutting build time 60%", variants[1].FinalText)
+	assert.True(t, variants[0].StyleChecks.Quantified)
+
+	assert.Contains(t, client.prompt, "Made CI faster")
+	assert.Contains(t, client.prompt, "CI/CD")
+	assert.Contains(t, client.prompt, "synergy")
+	assert.Contains(t, client.prompt, "Write 2 different rewrites")
+
+	// Counts are kept within bounds
+	_, err = rewriteVariants(context.Background(), client, "Made CI faster", nil, nil, 10)
+	require.NoError(t, err)
+	assert.Contains(t, client.prompt, "Write 3 different rewrites")
+}
+
+func TestRewriteVariantsEmptyResponse(t *testing.T) {
+	client := &variantsClient{response: `{"variants": []}`}
+	_, err := rewriteVariants(context.Background(), client, "Made CI faster", nil, nil, DefaultVariants)
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+}
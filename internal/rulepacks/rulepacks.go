@@ -0,0 +1,126 @@
+// Package rulepacks merges workspace style rule packs into a run's company profile.
+//
+// Workspace admins publish packs of mandatory style rules, such as a coaching business's
+// house style, and every member's runs follow them. Precedence:
+//   - Packs apply in descending priority, then by name.
+//   - Pack rules come before the style rules summarized from company research, so the
+//     rewriter reads the house style first.
+//   - A rule already added by a higher-precedence pack or by research is not repeated
+//     (compared ignoring case and surrounding space).
+//   - If any pack uses ModeReplace, the researched rules are dropped and only pack
+//     rules remain.
+package rulepacks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Pack modes
+const (
+	ModeAppend  = "append"  // Pack rules go ahead of the researched rules
+	ModeReplace = "replace" // Pack rules replace the researched rules
+)
+
+const (
+	// MaxRules caps the rules in one pack
+	MaxRules = 20
+	// MaxRuleLength caps one rule; style rules are a sentence
+	MaxRuleLength = 300
+)
+
+// Pack is a workspace's published set of style rules
+type Pack struct {
+	ID          uuid.UUID
+	WorkspaceID uuid.UUID
+	Name        string
+	Mode        string
+	Priority    int
+	Rules       []string
+}
+
+// Applied records one pack merged into a run's company profile
+type Applied struct {
+	PackID      uuid.UUID `json:"pack_id"`
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	Name        string    `json:"name"`
+	Mode        string    `json:"mode"`
+	Rules       []string  `json:"rules"` // The rules the pack added, after removing repeats
+}
+
+// Validate checks a pack's mode and rules
+func Validate(mode string, rules []string) error {
+	if mode != ModeAppend && mode != ModeReplace {
+		return fmt.Errorf("mode must be %q or %q", ModeAppend, ModeReplace)
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("a rule pack needs at least one rule")
+	}
+	if len(rules) > MaxRules {
+		return fmt.Errorf("a rule pack can have at most %d rules", MaxRules)
+	}
+	for _, rule := range rules {
+		if strings.TrimSpace(rule) == "" {
+			return fmt.Errorf("rules must not be empty")
+		}
+		if len(rule) > MaxRuleLength {
+			return fmt.Errorf("rules must be at most %d characters", MaxRuleLength)
+		}
+	}
+	return nil
+}
+
+// Apply merges packs into profile's style rules following the package's precedence
+// rules and returns what each pack contributed, in the order applied
+func Apply(profile *types.CompanyProfile, packs []Pack) []Applied {
+	if profile == nil || len(packs) == 0 {
+		return nil
+	}
+	ordered := make([]Pack, len(packs))
+	copy(ordered, packs)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority > ordered[j].Priority
+		}
+		return strings.ToLower(ordered[i].Name) < strings.ToLower(ordered[j].Name)
+	})
+
+	seen := make(map[string]bool)
+	var merged []string
+	add := func(rule string) bool {
+		rule = strings.TrimSpace(rule)
+		key := strings.ToLower(rule)
+		if rule == "" || seen[key] {
+			return false
+		}
+		seen[key] = true
+		merged = append(merged, rule)
+		return true
+	}
+
+	applied := make([]Applied, 0, len(ordered))
+	replace := false
+	for _, pack := range ordered {
+		a := Applied{PackID: pack.ID, WorkspaceID: pack.WorkspaceID, Name: pack.Name, Mode: pack.Mode, Rules: []string{}}
+		for _, rule := range pack.Rules {
+			if add(rule) {
+				a.Rules = append(a.Rules, strings.TrimSpace(rule))
+			}
+		}
+		if pack.Mode == ModeReplace {
+			replace = true
+		}
+		applied = append(applied, a)
+	}
+	if !replace {
+		for _, rule := range profile.StyleRules {
+			add(rule)
+		}
+	}
+	profile.StyleRules = merged
+	return applied
+}
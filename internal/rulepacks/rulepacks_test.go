@@ -0,0 +1,47 @@
+package rulepacks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestApply(t *testing.T) {
+	profile := &types.CompanyProfile{StyleRules: []string{"Lead with impact", "Use active voice"}}
+	packs := []Pack{
+		{ID: uuid.New(), Name: "Metrics", Mode: ModeAppend, Priority: 1, Rules: []string{"Quantify every bullet", "use active voice"}},
+		{ID: uuid.New(), Name: "House style", Mode: ModeAppend, Priority: 5, Rules: []string{" No first person ", "Quantify every bullet"}},
+	}
+
+	applied := Apply(profile, packs)
+	assert.Equal(t, []string{"No first person", "Quantify every bullet", "use active voice", "Lead with impact"}, profile.StyleRules,
+		"pack rules lead in priority order, without repeats")
+	if assert.Len(t, applied, 2) {
+		assert.Equal(t, "House style", applied[0].Name)
+		assert.Equal(t, []string{"No first person", "Quantify every bullet"}, applied[0].Rules)
+		assert.Equal(t, []string{"use active voice"}, applied[1].Rules)
+	}
+}
+
+func TestApply_Replace(t *testing.T) {
+	profile := &types.CompanyProfile{StyleRules: []string{"Lead with impact"}}
+	applied := Apply(profile, []Pack{{Name: "Strict", Mode: ModeReplace, Rules: []string{"Past tense only"}}})
+	assert.Equal(t, []string{"Past tense only"}, profile.StyleRules, "researched rules are dropped")
+	assert.Len(t, applied, 1)
+
+	assert.Nil(t, Apply(profile, nil))
+	assert.Equal(t, []string{"Past tense only"}, profile.StyleRules)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(ModeAppend, []string{"No first person"}))
+	assert.Error(t, Validate("merge", []string{"No first person"}))
+	assert.Error(t, Validate(ModeReplace, nil))
+	assert.Error(t, Validate(ModeAppend, []string{"  "}))
+	assert.Error(t, Validate(ModeAppend, []string{strings.Repeat("x", MaxRuleLength+1)}))
+	assert.Error(t, Validate(ModeAppend, make([]string, MaxRules+1)))
+}
@@ -0,0 +1,449 @@
+// Package scheduler runs pipeline runs on a bounded worker pool with priority
+// classes and per-user concurrency caps, so one user's bulk submissions cannot
+// starve other users' interactive runs.
+//
+// Runs are started highest priority first and in submission order (FIFO) within
+// a priority. A queued run whose user already holds the per-user maximum of
+// slots is passed over in favor of the next eligible run, so other users keep
+// making progress while a bulk user's backlog drains.
+//
+// Runs submitted while their user's queue is full are not rejected outright when
+// an admission backlog is configured: they wait in the backlog, in submission
+// order, and join their priority queue once the user's queue has room. Only a
+// full backlog rejects them. Tickets report their place in line and an estimated
+// wait derived from a moving average of recent run durations.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/logging"
+)
+
+// Priority is a run's scheduling class. Higher values are scheduled first.
+type Priority int
+
+// Priority classes
+const (
+	PriorityBulk Priority = iota
+	PriorityNormal
+	PriorityInteractive
+
+	numPriorities = int(PriorityInteractive) + 1
+)
+
+// String returns the priority's name as stored on pipeline runs
+func (p Priority) String() string {
+	switch p {
+	case PriorityBulk:
+		return db.RunPriorityBulk
+	case PriorityInteractive:
+		return db.RunPriorityInteractive
+	default:
+		return db.RunPriorityNormal
+	}
+}
+
+// ParsePriority parses a priority name, returning def for an empty string
+func ParsePriority(s string, def Priority) (Priority, error) {
+	switch s {
+	case "":
+		return def, nil
+	case db.RunPriorityBulk:
+		return PriorityBulk, nil
+	case db.RunPriorityNormal:
+		return PriorityNormal, nil
+	case db.RunPriorityInteractive:
+		return PriorityInteractive, nil
+	default:
+		return def, fmt.Errorf("invalid priority %q (must be %s, %s, or %s)", s,
+			db.RunPriorityInteractive, db.RunPriorityNormal, db.RunPriorityBulk)
+	}
+}
+
+// runDurationWeight is the weight of the latest run in the moving average of run durations
+const runDurationWeight = 0.2
+
+// ErrUserQueueFull is returned by Submit when the user already has the maximum
+// number of runs waiting for a slot and the admission backlog is full
+var ErrUserQueueFull = errors.New("too many queued runs for user")
+
+// Task is a unit of work submitted to the scheduler
+type Task struct {
+	UserID   uuid.UUID // Owner; tasks without an owner share the uuid.Nil cap
+	Priority Priority
+	Run      func(ctx context.Context)
+}
+
+// Ticket tracks a submitted task
+type Ticket struct {
+	started chan struct{}
+	done    chan struct{}
+	entry   *entry
+	sched   *Scheduler
+}
+
+// Started is closed when the task begins running
+func (t *Ticket) Started() <-chan struct{} { return t.started }
+
+// Done is closed when the task finishes, or when its context is canceled before it starts
+func (t *Ticket) Done() <-chan struct{} { return t.done }
+
+// Position returns the task's 1-based place in the dispatch order (higher
+// priorities first, then the admission backlog), or 0 once it has started or
+// been dropped. Per-user caps can let later tasks start first, so the position
+// is an estimate.
+func (t *Ticket) Position() int {
+	if t.sched == nil {
+		return 0
+	}
+	t.sched.mu.Lock()
+	defer t.sched.mu.Unlock()
+	return t.sched.positionLocked(t.entry)
+}
+
+// Estimate returns the task's position and how long it is expected to wait for a
+// slot, assuming the pool drains at the average run duration. Both are 0 once the
+// task has started or been dropped.
+func (t *Ticket) Estimate() (position int, wait time.Duration) {
+	if t.sched == nil {
+		return 0, 0
+	}
+	s := t.sched
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	position = s.positionLocked(t.entry)
+	if position == 0 {
+		return 0, 0
+	}
+	rounds := (position + s.maxRuns - 1) / s.maxRuns
+	return position, time.Duration(rounds) * s.avgRun
+}
+
+// Waiting reports whether the task is still in the admission backlog, not yet in
+// its priority queue
+func (t *Ticket) Waiting() bool {
+	if t.sched == nil {
+		return false
+	}
+	t.sched.mu.Lock()
+	defer t.sched.mu.Unlock()
+	for _, e := range t.sched.backlog {
+		if e == t.entry {
+			return true
+		}
+	}
+	return false
+}
+
+// entry is a queued task
+type entry struct {
+	ctx        context.Context
+	task       Task
+	ticket     *Ticket
+	stopCancel func() bool
+}
+
+// Stats is a point-in-time view of the scheduler
+type Stats struct {
+	Running    int            `json:"running"`
+	Queued     map[string]int `json:"queued"`       // By priority name
+	Backlog    int            `json:"backlog"`      // Waiting for room in their user's queue
+	AvgRunSecs float64        `json:"avg_run_secs"` // Moving average used for wait estimates
+}
+
+// Scheduler executes tasks on a bounded pool
+type Scheduler struct {
+	maxRuns       int
+	perUser       int
+	perUserQueued int
+	maxBacklog    int
+
+	mu          sync.Mutex
+	running     int
+	userRunning map[uuid.UUID]int
+	userQueued  map[uuid.UUID]int
+	queues      [numPriorities][]*entry
+	backlog     []*entry
+	avgRun      time.Duration
+}
+
+// New creates a scheduler running at most maxRuns tasks at once and at most
+// perUser tasks for any single user. A user may have at most perUserQueued
+// tasks waiting for a slot; zero means unlimited.
+func New(maxRuns, perUser, perUserQueued int) *Scheduler {
+	if maxRuns < 1 {
+		maxRuns = 1
+	}
+	if perUser < 1 {
+		perUser = 1
+	}
+	if perUserQueued < 0 {
+		perUserQueued = 0
+	}
+	return &Scheduler{
+		maxRuns:       maxRuns,
+		perUser:       perUser,
+		perUserQueued: perUserQueued,
+		userRunning:   make(map[uuid.UUID]int),
+		userQueued:    make(map[uuid.UUID]int),
+	}
+}
+
+// WithAdmission lets up to maxBacklog tasks in total wait for room in their user's
+// queue instead of being rejected, and seeds wait estimates with runEstimate until
+// runs have finished. It returns s for chaining.
+func (s *Scheduler) WithAdmission(maxBacklog int, runEstimate time.Duration) *Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxBacklog = max(maxBacklog, 0)
+	s.avgRun = max(runEstimate, 0)
+	return s
+}
+
+// Submit queues task. ctx is passed to the task when it runs; if ctx is canceled
+// while the task is still queued, the task is dropped and its ticket is done.
+// A task that would have to wait while the user's queue is full goes to the
+// admission backlog, or fails with ErrUserQueueFull if the backlog is full too.
+// A nil Scheduler runs every task immediately.
+func (s *Scheduler) Submit(ctx context.Context, task Task) (*Ticket, error) {
+	if task.Priority < PriorityBulk || task.Priority > PriorityInteractive {
+		task.Priority = PriorityNormal
+	}
+	ticket := &Ticket{started: make(chan struct{}), done: make(chan struct{})}
+	e := &entry{ctx: ctx, task: task, ticket: ticket}
+
+	if s == nil {
+		close(ticket.started)
+		go s.run(e)
+		return ticket, nil
+	}
+	ticket.entry, ticket.sched = e, s
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queueFullLocked(task.UserID) || s.userBackloggedLocked(task.UserID) {
+		if len(s.backlog) >= s.maxBacklog {
+			return nil, ErrUserQueueFull
+		}
+		s.backlog = append(s.backlog, e)
+	} else {
+		s.enqueueLocked(e)
+	}
+	e.stopCancel = context.AfterFunc(ctx, func() { s.drop(e) })
+	s.dispatchLocked()
+	return ticket, nil
+}
+
+// QueueFull reports whether a task submitted for the user now would be rejected
+// with ErrUserQueueFull
+func (s *Scheduler) QueueFull(userID uuid.UUID) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return (s.queueFullLocked(userID) || s.userBackloggedLocked(userID)) && len(s.backlog) >= s.maxBacklog
+}
+
+// userBackloggedLocked reports whether the user has tasks in the admission backlog,
+// which later submissions must wait behind. Callers hold s.mu.
+func (s *Scheduler) userBackloggedLocked(userID uuid.UUID) bool {
+	for _, e := range s.backlog {
+		if e.task.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueueLocked adds a task to its priority queue. Callers hold s.mu.
+func (s *Scheduler) enqueueLocked(e *entry) {
+	s.queues[e.task.Priority] = append(s.queues[e.task.Priority], e)
+	s.userQueued[e.task.UserID]++
+}
+
+// admitLocked moves backlogged tasks into their priority queues, oldest first, as
+// their users' queues gain room. Callers hold s.mu.
+func (s *Scheduler) admitLocked() {
+	kept := s.backlog[:0]
+	blocked := make(map[uuid.UUID]bool)
+	for _, e := range s.backlog {
+		// A user's backlog is admitted in order, so one blocked task holds back the rest
+		if blocked[e.task.UserID] || s.queueFullLocked(e.task.UserID) {
+			blocked[e.task.UserID] = true
+			kept = append(kept, e)
+			continue
+		}
+		s.enqueueLocked(e)
+	}
+	clear(s.backlog[len(kept):])
+	s.backlog = kept
+}
+
+// positionLocked returns a task's 1-based place in the dispatch order, or 0 if it
+// is neither queued nor backlogged. Callers hold s.mu.
+func (s *Scheduler) positionLocked(target *entry) int {
+	pos := 0
+	for p := numPriorities - 1; p >= 0; p-- {
+		for _, e := range s.queues[p] {
+			pos++
+			if e == target {
+				return pos
+			}
+		}
+	}
+	for _, e := range s.backlog {
+		pos++
+		if e == target {
+			return pos
+		}
+	}
+	return 0
+}
+
+// queueFullLocked reports whether the user's next task would have to wait with
+// the user's queue already full. A task that can start now never waits, so it
+// doesn't count against the queue limit. Callers hold s.mu.
+func (s *Scheduler) queueFullLocked(userID uuid.UUID) bool {
+	if s.perUserQueued == 0 {
+		return false
+	}
+	canStart := s.running < s.maxRuns && s.userRunning[userID] < s.perUser
+	return !canStart && s.userQueued[userID] >= s.perUserQueued
+}
+
+// UserStats returns how many of the user's tasks are running and queued
+func (s *Scheduler) UserStats(userID uuid.UUID) (running, queued int) {
+	if s == nil {
+		return 0, 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.userRunning[userID], s.userQueued[userID]
+}
+
+// Limits returns the per-user running and queued limits (0 queued means unlimited)
+func (s *Scheduler) Limits() (perUser, perUserQueued int) {
+	if s == nil {
+		return 0, 0
+	}
+	return s.perUser, s.perUserQueued
+}
+
+// Stats returns the number of running and queued tasks
+func (s *Scheduler) Stats() Stats {
+	stats := Stats{Queued: make(map[string]int, numPriorities)}
+	if s == nil {
+		return stats
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats.Running = s.running
+	for p, queue := range s.queues {
+		stats.Queued[Priority(p).String()] = len(queue)
+	}
+	stats.Backlog = len(s.backlog)
+	stats.AvgRunSecs = s.avgRun.Seconds()
+	return stats
+}
+
+// dispatchLocked admits backlogged tasks that now fit and starts queued tasks while
+// pool slots are free. Callers hold s.mu.
+func (s *Scheduler) dispatchLocked() {
+	s.admitLocked()
+	for s.running < s.maxRuns {
+		e := s.nextLocked()
+		if e == nil {
+			return
+		}
+		e.stopCancel()
+		s.running++
+		s.userRunning[e.task.UserID]++
+		close(e.ticket.started)
+		go s.run(e)
+		s.admitLocked()
+	}
+}
+
+// nextLocked removes and returns the oldest task in the highest non-empty priority
+// whose user is below the per-user cap, or nil if no task is eligible
+func (s *Scheduler) nextLocked() *entry {
+	for p := numPriorities - 1; p >= 0; p-- {
+		for i, e := range s.queues[p] {
+			if s.userRunning[e.task.UserID] >= s.perUser {
+				continue
+			}
+			s.queues[p] = append(s.queues[p][:i], s.queues[p][i+1:]...)
+			s.unqueueLocked(e)
+			return e
+		}
+	}
+	return nil
+}
+
+// drop removes a task whose context was canceled before it started
+func (s *Scheduler) drop(e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, waiting := range s.backlog {
+		if waiting == e {
+			s.backlog = append(s.backlog[:i], s.backlog[i+1:]...)
+			close(e.ticket.done)
+			return
+		}
+	}
+	queue := s.queues[e.task.Priority]
+	for i, queued := range queue {
+		if queued == e {
+			s.queues[e.task.Priority] = append(queue[:i], queue[i+1:]...)
+			s.unqueueLocked(e)
+			close(e.ticket.done)
+			s.admitLocked()
+			return
+		}
+	}
+}
+
+// unqueueLocked updates the per-user queue count for a task leaving the queue
+func (s *Scheduler) unqueueLocked(e *entry) {
+	if s.userQueued[e.task.UserID]--; s.userQueued[e.task.UserID] <= 0 {
+		delete(s.userQueued, e.task.UserID)
+	}
+}
+
+// run executes a started task, releases its slot, and folds its duration into the
+// average used for wait estimates
+func (s *Scheduler) run(e *entry) {
+	start := time.Now()
+	defer close(e.ticket.done)
+	defer func() {
+		if r := recover(); r != nil {
+			slog.ErrorContext(logging.WithUserID(e.ctx, e.task.UserID), "scheduled run panicked", "panic", r)
+		}
+		if s == nil {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if d := time.Since(start); s.avgRun == 0 {
+			s.avgRun = d
+		} else {
+			s.avgRun += time.Duration(runDurationWeight * float64(d-s.avgRun))
+		}
+		s.running--
+		if s.userRunning[e.task.UserID]--; s.userRunning[e.task.UserID] <= 0 {
+			delete(s.userRunning, e.task.UserID)
+		}
+		s.dispatchLocked()
+	}()
+	e.task.Run(e.ctx)
+}
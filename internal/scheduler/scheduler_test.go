@@ -0,0 +1,238 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingTask returns a task that records its start and blocks until release is closed
+func blockingTask(user uuid.UUID, p Priority, name string, order *[]string, mu *sync.Mutex, release <-chan struct{}) Task {
+	return Task{UserID: user, Priority: p, Run: func(context.Context) {
+		mu.Lock()
+		*order = append(*order, name)
+		mu.Unlock()
+		<-release
+	}}
+}
+
+func submit(t *testing.T, s *Scheduler, ctx context.Context, task Task) *Ticket {
+	t.Helper()
+	ticket, err := s.Submit(ctx, task)
+	require.NoError(t, err)
+	return ticket
+}
+
+func waitStarted(t *testing.T, ticket *Ticket) {
+	t.Helper()
+	select {
+	case <-ticket.Started():
+	case <-time.After(time.Second):
+		t.Fatal("task did not start")
+	}
+}
+
+func waitDone(t *testing.T, ticket *Ticket) {
+	t.Helper()
+	select {
+	case <-ticket.Done():
+	case <-time.After(time.Second):
+		t.Fatal("task did not finish")
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	p, err := ParsePriority("", PriorityInteractive)
+	require.NoError(t, err)
+	assert.Equal(t, PriorityInteractive, p)
+
+	for _, want := range []Priority{PriorityBulk, PriorityNormal, PriorityInteractive} {
+		got, err := ParsePriority(want.String(), PriorityNormal)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err = ParsePriority("urgent", PriorityNormal)
+	assert.Error(t, err)
+}
+
+func TestScheduler_PriorityThenFIFO(t *testing.T) {
+	s := New(1, 10, 0)
+	var mu sync.Mutex
+	var order []string
+	release := make(chan struct{})
+
+	first := submit(t, s, context.Background(), blockingTask(uuid.New(), PriorityNormal, "first", &order, &mu, release))
+	waitStarted(t, first)
+
+	var tickets []*Ticket
+	tickets = append(tickets,
+		submit(t, s, context.Background(), blockingTask(uuid.New(), PriorityBulk, "bulk", &order, &mu, release)),
+		submit(t, s, context.Background(), blockingTask(uuid.New(), PriorityNormal, "normal-1", &order, &mu, release)),
+		submit(t, s, context.Background(), blockingTask(uuid.New(), PriorityInteractive, "interactive", &order, &mu, release)),
+		submit(t, s, context.Background(), blockingTask(uuid.New(), PriorityNormal, "normal-2", &order, &mu, release)),
+	)
+	stats := s.Stats()
+	assert.Equal(t, 1, stats.Running)
+	assert.Equal(t, map[string]int{"bulk": 1, "normal": 2, "interactive": 1}, stats.Queued)
+
+	close(release)
+	for _, ticket := range tickets {
+		waitDone(t, ticket)
+	}
+	assert.Equal(t, []string{"first", "interactive", "normal-1", "normal-2", "bulk"}, order)
+}
+
+func TestScheduler_PerUserCapKeepsOthersMoving(t *testing.T) {
+	s := New(3, 1, 0)
+	var mu sync.Mutex
+	var order []string
+	bulkUser, otherUser := uuid.New(), uuid.New()
+	release := make(chan struct{})
+	defer close(release)
+
+	// The bulk user's backlog may only hold one slot
+	a := submit(t, s, context.Background(), blockingTask(bulkUser, PriorityBulk, "bulk-1", &order, &mu, release))
+	submit(t, s, context.Background(), blockingTask(bulkUser, PriorityBulk, "bulk-2", &order, &mu, release))
+	waitStarted(t, a)
+
+	// Another user's run starts immediately despite the older queued bulk run
+	b := submit(t, s, context.Background(), blockingTask(otherUser, PriorityBulk, "other", &order, &mu, release))
+	waitStarted(t, b)
+
+	stats := s.Stats()
+	assert.Equal(t, 2, stats.Running)
+	assert.Equal(t, 1, stats.Queued["bulk"])
+}
+
+func TestScheduler_CanceledWhileQueued(t *testing.T) {
+	s := New(1, 1, 0)
+	release := make(chan struct{})
+	user := uuid.New()
+
+	running := submit(t, s, context.Background(), Task{UserID: user, Run: func(context.Context) { <-release }})
+	waitStarted(t, running)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ran := false
+	queued := submit(t, s, ctx, Task{UserID: user, Run: func(context.Context) { ran = true }})
+	cancel()
+	waitDone(t, queued)
+
+	close(release)
+	waitDone(t, running)
+	assert.False(t, ran)
+	select {
+	case <-queued.Started():
+		t.Fatal("canceled task should never start")
+	default:
+	}
+	assert.Equal(t, 0, s.Stats().Queued["normal"])
+}
+
+func TestScheduler_RecoversFromPanic(t *testing.T) {
+	s := New(1, 1, 0)
+	panicky := submit(t, s, context.Background(), Task{Run: func(context.Context) { panic("boom") }})
+	waitDone(t, panicky)
+
+	next := submit(t, s, context.Background(), Task{Run: func(context.Context) {}})
+	waitDone(t, next)
+	assert.Equal(t, 0, s.Stats().Running)
+}
+
+func TestScheduler_NilRunsImmediately(t *testing.T) {
+	var s *Scheduler
+	done := make(chan struct{})
+	ticket := submit(t, s, context.Background(), Task{Run: func(context.Context) { close(done) }})
+	waitDone(t, ticket)
+	<-done
+	assert.Equal(t, 0, s.Stats().Running)
+}
+
+func TestScheduler_UserQueueLimit(t *testing.T) {
+	s := New(4, 1, 2)
+	user, other := uuid.New(), uuid.New()
+	release := make(chan struct{})
+	defer close(release)
+	block := func(context.Context) { <-release }
+
+	running := submit(t, s, context.Background(), Task{UserID: user, Run: block})
+	waitStarted(t, running)
+	assert.Equal(t, 0, running.Position())
+
+	first := submit(t, s, context.Background(), Task{UserID: user, Run: block})
+	second := submit(t, s, context.Background(), Task{UserID: user, Priority: PriorityInteractive, Run: block})
+	assert.Equal(t, 2, first.Position())
+	assert.Equal(t, 1, second.Position(), "higher priority is dispatched first")
+
+	assert.True(t, s.QueueFull(user))
+	assert.False(t, s.QueueFull(other))
+	_, err := s.Submit(context.Background(), Task{UserID: user, Run: block})
+	assert.ErrorIs(t, err, ErrUserQueueFull)
+
+	runningCount, queuedCount := s.UserStats(user)
+	assert.Equal(t, 1, runningCount)
+	assert.Equal(t, 2, queuedCount)
+
+	// Other users are unaffected by this user's full queue
+	otherTicket := submit(t, s, context.Background(), Task{UserID: other, Run: block})
+	waitStarted(t, otherTicket)
+}
+
+func TestScheduler_AdmissionBacklog(t *testing.T) {
+	s := New(1, 1, 1).WithAdmission(1, time.Minute)
+	user := uuid.New()
+	release := make(chan struct{})
+	block := func(context.Context) { <-release }
+
+	running := submit(t, s, context.Background(), Task{UserID: user, Run: block})
+	waitStarted(t, running)
+	queued := submit(t, s, context.Background(), Task{UserID: user, Run: block})
+	assert.False(t, s.QueueFull(user), "the backlog still has room")
+
+	waiting := submit(t, s, context.Background(), Task{UserID: user, Priority: PriorityInteractive, Run: block})
+	assert.True(t, waiting.Waiting())
+	pos, wait := waiting.Estimate()
+	assert.Equal(t, 2, pos, "backlogged tasks wait behind every queued task")
+	assert.Equal(t, 2*time.Minute, wait)
+	assert.Equal(t, 1, s.Stats().Backlog)
+
+	assert.True(t, s.QueueFull(user))
+	_, err := s.Submit(context.Background(), Task{UserID: user, Run: block})
+	assert.ErrorIs(t, err, ErrUserQueueFull)
+
+	close(release)
+	waitDone(t, running)
+	waitDone(t, queued)
+	waitDone(t, waiting)
+	assert.False(t, waiting.Waiting())
+	assert.Zero(t, s.Stats().Backlog)
+	assert.Positive(t, s.Stats().AvgRunSecs)
+}
+
+func TestScheduler_CanceledWhileBacklogged(t *testing.T) {
+	s := New(1, 1, 1).WithAdmission(2, 0)
+	user := uuid.New()
+	release := make(chan struct{})
+	defer close(release)
+	block := func(context.Context) { <-release }
+
+	running := submit(t, s, context.Background(), Task{UserID: user, Run: block})
+	waitStarted(t, running)
+	submit(t, s, context.Background(), Task{UserID: user, Run: block})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	waiting := submit(t, s, ctx, Task{UserID: user, Run: block})
+	behind := submit(t, s, context.Background(), Task{UserID: user, Run: block})
+	assert.Equal(t, 3, behind.Position())
+
+	cancel()
+	waitDone(t, waiting)
+	assert.Equal(t, 2, behind.Position())
+	assert.Equal(t, 1, s.Stats().Backlog)
+}
@@ -0,0 +1,124 @@
+package selection
+
+import (
+	"slices"
+
+	"github.com/jonathan/resume-customizer/internal/skills"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// PinBullets adds the bullets a user pinned to plan whatever their rank, then drops
+// unpinned bullets from the low-ranked end of the plan until it fits the space budget
+// again. Pinned bullets are never dropped, even if they alone exceed the budget. It
+// returns the pinned IDs missing from the experience bank, such as bullets deleted
+// since they were pinned.
+func PinBullets(plan *types.ResumePlan, jobProfile *types.JobProfile, experienceBank *types.ExperienceBank, bulletIDs []string) ([]string, error) {
+	if len(bulletIDs) == 0 {
+		return nil, nil
+	}
+
+	storyMap := make(map[string]*types.Story)
+	bulletStory := make(map[string]*types.Story)
+	bullets := make(map[string]types.Bullet)
+	for i := range experienceBank.Stories {
+		story := &experienceBank.Stories[i]
+		storyMap[story.ID] = story
+		for _, b := range story.Bullets {
+			bulletStory[b.ID] = story
+			bullets[b.ID] = b
+		}
+	}
+
+	pinned := make(map[string]bool)
+	var missing []string
+	for _, id := range bulletIDs {
+		story, ok := bulletStory[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		pinned[id] = true
+		i := slices.IndexFunc(plan.SelectedStories, func(s types.SelectedStory) bool { return s.StoryID == story.ID })
+		if i < 0 {
+			plan.SelectedStories = append(plan.SelectedStories, types.SelectedStory{StoryID: story.ID, Section: "experience"})
+			i = len(plan.SelectedStories) - 1
+		}
+		if !slices.Contains(plan.SelectedStories[i].BulletIDs, id) {
+			plan.SelectedStories[i].BulletIDs = append(plan.SelectedStories[i].BulletIDs, id)
+		}
+	}
+
+	// Keep each story's bullets in experience bank order
+	for i := range plan.SelectedStories {
+		selected := &plan.SelectedStories[i]
+		story := storyMap[selected.StoryID]
+		if story == nil {
+			continue
+		}
+		slices.SortStableFunc(selected.BulletIDs, func(a, b string) int {
+			return bulletPosition(story, a) - bulletPosition(story, b)
+		})
+	}
+
+	// Trim from the end: stories are in ranked order, and a story's later bullets
+	// are its weaker ones
+	lines, count := planSize(plan, bullets)
+	for i := len(plan.SelectedStories) - 1; i >= 0 && overBudget(&plan.SpaceBudget, lines, count); i-- {
+		ids := plan.SelectedStories[i].BulletIDs
+		for j := len(ids) - 1; j >= 0 && overBudget(&plan.SpaceBudget, lines, count); j-- {
+			if pinned[ids[j]] {
+				continue
+			}
+			lines -= estimateLines(bullets[ids[j]].LengthChars)
+			count--
+			ids = slices.Delete(ids, j, j+1)
+		}
+		plan.SelectedStories[i].BulletIDs = ids
+	}
+
+	kept := plan.SelectedStories[:0]
+	var selectedBullets []types.Bullet
+	for _, selected := range plan.SelectedStories {
+		if len(selected.BulletIDs) == 0 {
+			continue
+		}
+		selected.EstimatedLines = 0
+		for _, id := range selected.BulletIDs {
+			selected.EstimatedLines += estimateLines(bullets[id].LengthChars)
+			selectedBullets = append(selectedBullets, bullets[id])
+		}
+		kept = append(kept, selected)
+	}
+	plan.SelectedStories = kept
+
+	skillTargets, err := skills.BuildSkillTargets(jobProfile)
+	if err != nil {
+		return nil, &Error{Message: "failed to build skill targets", Cause: err}
+	}
+	plan.Coverage = computeCoverage(selectedBullets, skillTargets)
+	return missing, nil
+}
+
+// bulletPosition returns where bulletID appears in story, or past the end if absent
+func bulletPosition(story *types.Story, bulletID string) int {
+	if i := slices.IndexFunc(story.Bullets, func(b types.Bullet) bool { return b.ID == bulletID }); i >= 0 {
+		return i
+	}
+	return len(story.Bullets)
+}
+
+// planSize returns the estimated lines and the number of bullets in plan
+func planSize(plan *types.ResumePlan, bullets map[string]types.Bullet) (lines, count int) {
+	for _, selected := range plan.SelectedStories {
+		for _, id := range selected.BulletIDs {
+			lines += estimateLines(bullets[id].LengthChars)
+			count++
+		}
+	}
+	return lines, count
+}
+
+// overBudget reports whether lines or count exceed the budget's limits; zero limits are unlimited
+func overBudget(budget *types.SpaceBudget, lines, count int) bool {
+	return (budget.MaxLines > 0 && lines > budget.MaxLines) || (budget.MaxBullets > 0 && count > budget.MaxBullets)
+}
@@ -0,0 +1,44 @@
+package selection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestPinBullets(t *testing.T) {
+	bank := &types.ExperienceBank{Stories: []types.Story{
+		{ID: "s1", Bullets: []types.Bullet{
+			{ID: "s1-b1", LengthChars: 90, Skills: []string{"Go"}},
+			{ID: "s1-b2", LengthChars: 90},
+			{ID: "s1-b3", LengthChars: 90},
+		}},
+		{ID: "s2", Bullets: []types.Bullet{
+			{ID: "s2-b1", LengthChars: 90, Skills: []string{"Kafka"}},
+		}},
+	}}
+	jobProfile := &types.JobProfile{HardRequirements: []types.Requirement{{Skill: "Go"}, {Skill: "Kafka"}}}
+	plan := &types.ResumePlan{
+		SpaceBudget:     types.SpaceBudget{MaxBullets: 3},
+		SelectedStories: []types.SelectedStory{{StoryID: "s1", BulletIDs: []string{"s1-b1", "s1-b3", "s1-b2"}}},
+	}
+
+	missing, err := PinBullets(plan, jobProfile, bank, []string{"s2-b1", "s1-b2", "deleted"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deleted"}, missing)
+	require.Len(t, plan.SelectedStories, 2)
+	assert.Equal(t, []string{"s1-b1", "s1-b2"}, plan.SelectedStories[0].BulletIDs,
+		"bullets keep bank order and the weakest unpinned one is dropped")
+	assert.Equal(t, types.SelectedStory{StoryID: "s2", BulletIDs: []string{"s2-b1"}, Section: "experience", EstimatedLines: 1},
+		plan.SelectedStories[1])
+	assert.ElementsMatch(t, []string{"Go", "Kafka"}, plan.Coverage.TopSkillsCovered)
+
+	// Pins are kept even when they alone exceed the budget
+	plan = &types.ResumePlan{SpaceBudget: types.SpaceBudget{MaxBullets: 1}}
+	_, err = PinBullets(plan, jobProfile, bank, []string{"s1-b1", "s2-b1"})
+	require.NoError(t, err)
+	assert.Len(t, plan.SelectedStories, 2)
+}
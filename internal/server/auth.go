@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/server/middleware"
+)
+
+// withAuth adds authentication middleware
+func (s *Server) withAuth(next http.Handler) http.Handler {
+	return wrappedHandler{Handler: middleware.AuthMiddleware(s.jwtService.AsTokenValidator())(next), next: next, auth: true}
+}
+
+// bearerUserID returns the user ID from a valid Bearer token on the request, if present.
+// Unlike withAuth, a missing token is not an error at the HTTP layer; callers decide.
+func (s *Server) bearerUserID(r *http.Request) (uuid.UUID, error) {
+	if s.jwtService == nil {
+		return uuid.Nil, &ErrUnauthorized{}
+	}
+
+	parts := strings.Fields(r.Header.Get("Authorization"))
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return uuid.Nil, &ErrUnauthorized{}
+	}
+
+	claims, err := s.jwtService.ValidateToken(parts[1])
+	if err != nil {
+		return uuid.Nil, &ErrUnauthorized{}
+	}
+	return claims.UserID, nil
+}
+
+// isAdmin reports whether the user is on the admin allowlist. Every admin check goes
+// through here so server-wide settings and debug access share one list.
+func (s *Server) isAdmin(userID uuid.UUID) bool {
+	return s.admins.IsAdmin(userID)
+}
+
+// callerIsAdmin reports whether the caller is an admin, writing a 401 or 403 otherwise
+func (s *Server) callerIsAdmin(w http.ResponseWriter, r *http.Request, action string) bool {
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return false
+	}
+	if !s.isAdmin(callerID) {
+		s.errorResponse(w, http.StatusForbidden, "Only admins can "+action)
+		return false
+	}
+	return true
+}
@@ -3,31 +3,52 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/logging"
 	"github.com/jonathan/resume-customizer/internal/types"
 )
 
 // AuthHandler handles authentication-related HTTP requests.
 type AuthHandler struct {
-	userService *UserService
-	jwtService  *JWTService
-	validator   *validator.Validate
+	userService   *UserService
+	jwtService    *JWTService
+	refreshTokens *RefreshTokenService
+	validator     *validator.Validate
+
+	// verification emails new accounts a verification link; nil or disabled activates them immediately
+	verification *EmailVerificationService
+	// baseURL returns the externally visible URL verification links are built on
+	baseURL func(r *http.Request) string
 }
 
 // NewAuthHandler creates a new AuthHandler with the given dependencies.
-func NewAuthHandler(userService *UserService, jwtService *JWTService) *AuthHandler {
+func NewAuthHandler(userService *UserService, jwtService *JWTService, refreshTokens *RefreshTokenService) *AuthHandler {
 	return &AuthHandler{
-		userService: userService,
-		jwtService:  jwtService,
-		validator:   validator.New(),
+		userService:   userService,
+		jwtService:    jwtService,
+		refreshTokens: refreshTokens,
+		validator:     validator.New(),
 	}
 }
 
-// Register handles user registration requests.
+// WithEmailVerification has registration email new accounts a verification link built on
+// the URL baseURL returns for the request
+func (h *AuthHandler) WithEmailVerification(svc *EmailVerificationService, baseURL func(r *http.Request) string) *AuthHandler {
+	h.verification = svc
+	h.baseURL = baseURL
+	return h
+}
+
+// Register handles user registration requests. When email verification is enabled the
+// account is created unverified and emailed a verification link; it can sign in, but
+// cannot start pipeline runs until the link is followed.
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req types.CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -48,15 +69,30 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.verification.Enabled() {
+		// A failed send leaves the account unverified; POST /v1/auth/verify-email/resend retries it
+		if err := h.verification.Send(r.Context(), user.ID, user.Name, user.Email, h.baseURL(r)); err != nil {
+			slog.WarnContext(r.Context(), "failed to send verification email", logging.KeyUserID, user.ID, "error", err)
+		}
+		user.EmailVerified = false
+	}
+
 	token, err := h.jwtService.GenerateToken(user.ID)
 	if err != nil {
 		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
 		return
 	}
 
+	refreshToken, err := h.refreshTokens.Issue(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, "Failed to generate refresh token", http.StatusInternalServerError)
+		return
+	}
+
 	response := types.LoginResponse{
-		User:  user,
-		Token: token,
+		User:         user,
+		Token:        token,
+		RefreshToken: refreshToken,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -94,9 +130,96 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	refreshToken, err := h.refreshTokens.Issue(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, "Failed to generate refresh token", http.StatusInternalServerError)
+		return
+	}
+
 	response := types.LoginResponse{
-		User:  user,
-		Token: token,
+		User:         user,
+		Token:        token,
+		RefreshToken: refreshToken,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		// Log error but response already sent
+		return
+	}
+}
+
+// Refresh handles token refresh requests. The refresh token is rotated: the response
+// carries a new access token and a new refresh token, and the presented one is spent.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeRefreshTokenRequest(w, r)
+	if !ok {
+		return
+	}
+
+	userID, refreshToken, err := h.refreshTokens.Rotate(r.Context(), req.RefreshToken)
+	if err != nil {
+		status := HTTPStatus(err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	token, err := h.jwtService.GenerateToken(userID)
+	if err != nil {
+		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+		return
+	}
+
+	response := types.RefreshTokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		// Log error but response already sent
+		return
+	}
+}
+
+// Logout handles logout requests by revoking the refresh token and every token
+// rotated from the same login. Access tokens already issued remain valid until
+// they expire.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeRefreshTokenRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.refreshTokens.Revoke(r.Context(), req.RefreshToken); err != nil {
+		http.Error(w, "Failed to revoke refresh token", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmail handles the verification links emailed at registration, verifying the
+// account the link's token was issued to
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, "token is required", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := h.verification.Verify(r.Context(), token)
+	if err != nil {
+		status := HTTPStatus(err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	response := map[string]string{
+		"message": "Email address verified",
+		"user_id": userID.String(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -107,6 +230,43 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ResendVerification emails userID a new verification link
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	if !h.verification.Enabled() {
+		http.Error(w, (&ErrEmailAlreadyVerified{}).Error(), http.StatusConflict)
+		return
+	}
+
+	if err := h.verification.Resend(r.Context(), userID, h.baseURL(r)); err != nil {
+		var tooMany *ErrTooManyRequests
+		if errors.As(err, &tooMany) {
+			w.Header().Set("Retry-After", strconv.Itoa(tooMany.RetryAfterSeconds()))
+		}
+		status := HTTPStatus(err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// decodeRefreshTokenRequest decodes and validates a refresh token request body,
+// writing a 400 response if it is invalid.
+func (h *AuthHandler) decodeRefreshTokenRequest(w http.ResponseWriter, r *http.Request) (*types.RefreshTokenRequest, bool) {
+	var req types.RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := extractValidationErrors(err)
+		http.Error(w, validationErrors, http.StatusBadRequest)
+		return nil, false
+	}
+	return &req, true
+}
+
 // UpdatePasswordWithUserID handles password update requests with an explicit user ID.
 func (h *AuthHandler) UpdatePasswordWithUserID(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
 	var req types.UpdatePasswordRequest
@@ -127,6 +287,12 @@ func (h *AuthHandler) UpdatePasswordWithUserID(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Sign out other sessions; they keep working only until their access tokens expire
+	if err := h.refreshTokens.RevokeAll(r.Context(), userID); err != nil {
+		http.Error(w, "Failed to revoke refresh tokens", http.StatusInternalServerError)
+		return
+	}
+
 	response := map[string]string{
 		"message": "Password updated successfully",
 	}
@@ -45,13 +45,14 @@ func setupTestAuthHandlerIntegration(t *testing.T) (*AuthHandler, *db.DB) {
 
 	// Use test JWT config instead of environment variable
 	jwtConfig := &config.JWTConfig{
-		Secret:          "test-secret-key-for-jwt-signing-minimum-32-bytes",
-		ExpirationHours: 24,
+		Secret:                 "test-secret-key-for-jwt-signing-minimum-32-bytes",
+		ExpirationHours:        24,
+		RefreshExpirationHours: 720,
 	}
 
 	userSvc := NewUserService(database, passwordConfig)
 	jwtSvc := NewJWTService(jwtConfig)
-	handler := NewAuthHandler(userSvc, jwtSvc)
+	handler := NewAuthHandler(userSvc, jwtSvc, NewRefreshTokenService(database, jwtConfig))
 
 	return handler, database
 }
@@ -26,7 +26,7 @@ func setupTestAuthHandler(_ *testing.T) *AuthHandler {
 
 	userSvc := NewUserService(nil, passwordConfig) // nil DB for unit tests - will fail on actual service calls
 	jwtSvc := NewJWTService(jwtConfig)
-	return NewAuthHandler(userSvc, jwtSvc)
+	return NewAuthHandler(userSvc, jwtSvc, NewRefreshTokenService(nil, jwtConfig))
 }
 
 func TestAuthHandler_Register_InvalidJSON(t *testing.T) {
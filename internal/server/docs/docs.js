@@ -0,0 +1,10 @@
+// Renders /v1/openapi.json, the document the server generates from its route table.
+// "Authorize" takes the token from POST /v1/auth/login.
+window.addEventListener("DOMContentLoaded", () => {
+  window.SwaggerUIBundle({
+    url: "/v1/openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    persistAuthorization: true,
+  });
+});
@@ -0,0 +1,93 @@
+// Package server provides the HTTP REST API for the resume customizer.
+package server
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+)
+
+// verificationResendInterval is the least time between verification emails to one account
+const verificationResendInterval = time.Minute
+
+// EmailVerificationService emails verification links to newly registered accounts and
+// verifies the links they follow. Without a configured mailer it is disabled, and
+// registration activates accounts immediately as it did before verification existed.
+type EmailVerificationService struct {
+	db     DBClient
+	mailer notifications.Mailer // nil when disabled
+	ttl    time.Duration
+}
+
+// NewEmailVerificationService creates an EmailVerificationService sending through mailer,
+// or a disabled one when cfg has no way to deliver email
+func NewEmailVerificationService(db DBClient, cfg *config.NotificationConfig, mailer notifications.Mailer) *EmailVerificationService {
+	s := &EmailVerificationService{db: db, ttl: 48 * time.Hour}
+	if cfg != nil {
+		s.ttl = cfg.VerificationTTL()
+		if cfg.EmailEnabled() {
+			s.mailer = mailer
+		}
+	}
+	return s
+}
+
+// Enabled reports whether new accounts must verify their email address
+func (s *EmailVerificationService) Enabled() bool {
+	return s != nil && s.mailer != nil
+}
+
+// Send marks an account unverified and emails it a verification link built on baseURL,
+// the externally visible URL of the API
+func (s *EmailVerificationService) Send(ctx context.Context, userID uuid.UUID, name, email, baseURL string) error {
+	token, hash, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.CreateEmailVerification(ctx, userID, email, hash, time.Now().Add(s.ttl)); err != nil {
+		return err
+	}
+	link := baseURL + "/v1/auth/verify-email?token=" + url.QueryEscape(token)
+	return s.mailer.SendEmail(ctx, notifications.VerificationEmail(email, name, link, s.ttl))
+}
+
+// Resend emails a new verification link to an account still awaiting verification.
+// Requests within verificationResendInterval of the last link are refused.
+func (s *EmailVerificationService) Resend(ctx context.Context, userID uuid.UUID, baseURL string) error {
+	user, err := s.db.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return &ErrUserNotFound{UserID: userID}
+	}
+	if user.EmailVerifiedAt != nil {
+		return &ErrEmailAlreadyVerified{}
+	}
+	latest, err := s.db.LatestEmailVerification(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		if wait := verificationResendInterval - time.Since(latest.CreatedAt); wait > 0 {
+			return &ErrTooManyRequests{RetryAfter: wait}
+		}
+	}
+	return s.Send(ctx, user.ID, user.Name, user.Email, baseURL)
+}
+
+// Verify spends a verification link's token and returns the account it verified
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) (uuid.UUID, error) {
+	v, err := s.db.VerifyEmail(ctx, hashSecretToken(token))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if v == nil {
+		return uuid.Nil, &ErrInvalidVerificationToken{}
+	}
+	return v.UserID, nil
+}
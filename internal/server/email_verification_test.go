@@ -0,0 +1,168 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// recordingMailer keeps sent email instead of delivering it
+type recordingMailer struct {
+	sent []notifications.Email
+}
+
+func (m *recordingMailer) SendEmail(_ context.Context, email notifications.Email) error {
+	m.sent = append(m.sent, email)
+	return nil
+}
+
+var verificationLinkPattern = regexp.MustCompile(`https://resume\.example\.com/v1/auth/verify-email\?token=(\S+)`)
+
+// verificationToken returns the token in the link of the last email sent
+func (m *recordingMailer) verificationToken(t *testing.T) string {
+	t.Helper()
+	require.NotEmpty(t, m.sent)
+	match := verificationLinkPattern.FindStringSubmatch(m.sent[len(m.sent)-1].Body)
+	require.NotNil(t, match, m.sent[len(m.sent)-1].Body)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+	return token
+}
+
+func newTestVerificationService(mock *mockDB) (*EmailVerificationService, *recordingMailer) {
+	mailer := &recordingMailer{}
+	cfg := &config.NotificationConfig{Mailer: config.MailerConsole, VerificationTTLHours: 48}
+	return NewEmailVerificationService(mock, cfg, mailer), mailer
+}
+
+func TestEmailVerificationService(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockDB()
+	svc, mailer := newTestVerificationService(mock)
+	userID, err := mock.CreateUser(ctx, "Jane", "jane@example.com", "")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Send(ctx, userID, "Jane", "jane@example.com", "https://resume.example.com"))
+	assert.Nil(t, mock.users[userID].EmailVerifiedAt)
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, "jane@example.com", mailer.sent[0].To)
+	assert.Contains(t, mailer.sent[0].Body, "expires in 48 hours")
+
+	// Links can only be resent once a minute
+	assert.IsType(t, &ErrTooManyRequests{}, svc.Resend(ctx, userID, "https://resume.example.com"))
+	mock.verifications[0].CreatedAt = time.Now().Add(-2 * time.Minute)
+	require.NoError(t, svc.Resend(ctx, userID, "https://resume.example.com"))
+	require.Len(t, mailer.sent, 2)
+
+	verified, err := svc.Verify(ctx, mailer.verificationToken(t))
+	require.NoError(t, err)
+	assert.Equal(t, userID, verified)
+	assert.NotNil(t, mock.users[userID].EmailVerifiedAt)
+
+	_, err = svc.Verify(ctx, mailer.verificationToken(t))
+	assert.IsType(t, &ErrInvalidVerificationToken{}, err, "links are single-use")
+	assert.IsType(t, &ErrEmailAlreadyVerified{}, svc.Resend(ctx, userID, "https://resume.example.com"))
+}
+
+func TestEmailVerificationService_RejectsStaleLinks(t *testing.T) {
+	ctx := context.Background()
+	mock := newMockDB()
+	svc, mailer := newTestVerificationService(mock)
+	userID, err := mock.CreateUser(ctx, "Jane", "jane@example.com", "")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Send(ctx, userID, "Jane", "jane@example.com", "https://resume.example.com"))
+	mock.users[userID].Email = "jane@other.example.com"
+	_, err = svc.Verify(ctx, mailer.verificationToken(t))
+	assert.IsType(t, &ErrInvalidVerificationToken{}, err, "links only verify the address they were sent to")
+
+	mock.users[userID].Email = "jane@example.com"
+	mock.verifications[0].ExpiresAt = time.Now().Add(-time.Minute)
+	_, err = svc.Verify(ctx, mailer.verificationToken(t))
+	assert.IsType(t, &ErrInvalidVerificationToken{}, err)
+	assert.Nil(t, mock.users[userID].EmailVerifiedAt)
+}
+
+func TestAuthHandler_RegisterSendsVerification(t *testing.T) {
+	s := newAuthTestServer(t)
+	jwtConfig := &config.JWTConfig{Secret: "test-secret-key-for-jwt-signing-minimum-32-bytes", ExpirationHours: 24}
+	svc, mailer := newTestVerificationService(s.mock)
+	handler := NewAuthHandler(NewUserService(s.mock, &config.PasswordConfig{BcryptCost: 10}), s.jwtService,
+		NewRefreshTokenService(s.mock, jwtConfig)).
+		WithEmailVerification(svc, func(*http.Request) string { return "https://resume.example.com" })
+
+	body := []byte(`{"name": "Jane", "email": "jane@example.com", "password": "correct-horse-battery"}`)
+	w := httptest.NewRecorder()
+	handler.Register(w, httptest.NewRequest(http.MethodPost, "/v1/auth/register", bytes.NewReader(body)))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp types.LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.User.EmailVerified)
+	assert.NotEmpty(t, resp.Token, "unverified accounts can still sign in")
+
+	w = httptest.NewRecorder()
+	handler.VerifyEmail(w, httptest.NewRequest(http.MethodGet, "/v1/auth/verify-email?token="+url.QueryEscape(mailer.verificationToken(t)), nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.NotNil(t, s.mock.users[resp.User.ID].EmailVerifiedAt)
+
+	w = httptest.NewRecorder()
+	handler.VerifyEmail(w, httptest.NewRequest(http.MethodGet, "/v1/auth/verify-email", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer()
+	verified, err := s.mock.CreateUser(ctx, "Ada", "ada@example.com", "")
+	require.NoError(t, err)
+	pending, err := s.mock.CreateUser(ctx, "Jane", "jane@example.com", "")
+	require.NoError(t, err)
+
+	var reached []byte
+	gated := s.requireVerifiedEmail(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	post := func(userID uuid.UUID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		body := `{"user_id": "` + userID.String() + `", "job_text": "Go engineer"}`
+		gated.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/runs", bytes.NewReader([]byte(body))))
+		return w
+	}
+
+	// Without a mailer accounts are never asked to verify
+	s.mock.users[pending].EmailVerifiedAt = nil
+	assert.Equal(t, http.StatusCreated, post(pending).Code)
+
+	var mailer *recordingMailer
+	s.verification, mailer = newTestVerificationService(s.mock)
+	require.NoError(t, s.verification.Send(ctx, pending, "Jane", "jane@example.com", "https://resume.example.com"))
+
+	w := post(pending)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "verify your email address")
+
+	reached = nil
+	assert.Equal(t, http.StatusCreated, post(verified).Code)
+	assert.Contains(t, string(reached), "Go engineer", "the handler still reads the body")
+	assert.Equal(t, http.StatusCreated, post(uuid.New()).Code, "unknown users are left to the handler")
+
+	_, err = s.verification.Verify(ctx, mailer.verificationToken(t))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, post(pending).Code)
+}
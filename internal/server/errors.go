@@ -3,7 +3,9 @@ package server
 
 import (
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -50,17 +52,80 @@ func (e *ErrValidation) Error() string {
 	return fmt.Sprintf("validation error: %s - %s", e.Field, e.Message)
 }
 
+// ErrUnauthorized indicates the request lacks valid authentication
+type ErrUnauthorized struct{}
+
+func (e *ErrUnauthorized) Error() string {
+	return "authentication required"
+}
+
+// ErrInvalidRefreshToken indicates a refresh token is unknown, expired, revoked, or already used
+type ErrInvalidRefreshToken struct{}
+
+func (e *ErrInvalidRefreshToken) Error() string {
+	return "invalid or expired refresh token"
+}
+
+// ErrInvalidVerificationToken indicates an email verification link is unknown, expired,
+// already used, or was sent to an address the account no longer has
+type ErrInvalidVerificationToken struct{}
+
+func (e *ErrInvalidVerificationToken) Error() string {
+	return "invalid or expired verification link"
+}
+
+// ErrEmailAlreadyVerified indicates a verification email was requested for a verified account
+type ErrEmailAlreadyVerified struct{}
+
+func (e *ErrEmailAlreadyVerified) Error() string {
+	return "email address is already verified"
+}
+
+// ErrEmailNotVerified indicates the account must verify its email address first
+type ErrEmailNotVerified struct{}
+
+func (e *ErrEmailNotVerified) Error() string {
+	return "verify your email address before starting runs; POST /v1/auth/verify-email/resend sends a new link"
+}
+
+// ErrTooManyRequests indicates the action was repeated too soon
+type ErrTooManyRequests struct {
+	RetryAfter time.Duration
+}
+
+func (e *ErrTooManyRequests) Error() string {
+	return fmt.Sprintf("too many requests; try again in %d seconds", e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds returns the wait in whole seconds, rounded up, for a Retry-After header
+func (e *ErrTooManyRequests) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// ErrForbidden indicates the authenticated user may not perform the action
+type ErrForbidden struct {
+	Action string
+}
+
+func (e *ErrForbidden) Error() string {
+	return fmt.Sprintf("forbidden: %s", e.Action)
+}
+
 // HTTPStatus returns the appropriate HTTP status code for an error
 func HTTPStatus(err error) int {
 	switch err.(type) {
-	case *ErrEmailAlreadyExists:
+	case *ErrEmailAlreadyExists, *ErrEmailAlreadyVerified:
 		return http.StatusConflict
-	case *ErrInvalidCredentials, *ErrPasswordMismatch:
+	case *ErrInvalidCredentials, *ErrPasswordMismatch, *ErrUnauthorized, *ErrInvalidRefreshToken:
 		return http.StatusUnauthorized
+	case *ErrForbidden, *ErrEmailNotVerified:
+		return http.StatusForbidden
 	case *ErrUserNotFound:
 		return http.StatusNotFound
-	case *ErrValidation:
+	case *ErrValidation, *ErrInvalidVerificationToken:
 		return http.StatusBadRequest
+	case *ErrTooManyRequests:
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
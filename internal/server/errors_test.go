@@ -3,6 +3,7 @@ package server
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -39,6 +40,34 @@ func TestErrValidation(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, HTTPStatus(err))
 }
 
+func TestErrUnauthorized(t *testing.T) {
+	err := &ErrUnauthorized{}
+	assert.Equal(t, "authentication required", err.Error())
+	assert.Equal(t, http.StatusUnauthorized, HTTPStatus(err))
+}
+
+func TestErrInvalidRefreshToken(t *testing.T) {
+	err := &ErrInvalidRefreshToken{}
+	assert.Equal(t, "invalid or expired refresh token", err.Error())
+	assert.Equal(t, http.StatusUnauthorized, HTTPStatus(err))
+}
+
+func TestErrForbidden(t *testing.T) {
+	err := &ErrForbidden{Action: "enable debug mode"}
+	assert.Equal(t, "forbidden: enable debug mode", err.Error())
+	assert.Equal(t, http.StatusForbidden, HTTPStatus(err))
+}
+
+func TestEmailVerificationErrors(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, HTTPStatus(&ErrInvalidVerificationToken{}))
+	assert.Equal(t, http.StatusConflict, HTTPStatus(&ErrEmailAlreadyVerified{}))
+	assert.Equal(t, http.StatusForbidden, HTTPStatus(&ErrEmailNotVerified{}))
+
+	err := &ErrTooManyRequests{RetryAfter: 42 * time.Second}
+	assert.Equal(t, "too many requests; try again in 42 seconds", err.Error())
+	assert.Equal(t, http.StatusTooManyRequests, HTTPStatus(err))
+}
+
 func TestHTTPStatus(t *testing.T) {
 	tests := []struct {
 		name     string
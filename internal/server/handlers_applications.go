@@ -0,0 +1,388 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+const (
+	// maxTrackerNotesLength caps a tracker's notes and an interaction's notes
+	maxTrackerNotesLength = 10000
+	// maxNextActionLength caps a next action; it is a one-line to-do
+	maxNextActionLength = 300
+	// maxContactFieldLength caps a contact's name, role, email, and LinkedIn URL
+	maxContactFieldLength = 200
+)
+
+// ApplicationTrackerRequest is the request body for creating or replacing the tracker of
+// a company. Omitted next action fields clear the next action.
+type ApplicationTrackerRequest struct {
+	Company      string     `json:"company"`
+	Notes        string     `json:"notes,omitempty"`
+	NextAction   string     `json:"next_action,omitempty"`
+	NextActionAt *time.Time `json:"next_action_at,omitempty"`
+}
+
+// ApplicationTrackerListResponse is the response for listing application trackers
+type ApplicationTrackerListResponse struct {
+	Trackers []db.ApplicationTracker `json:"trackers"`
+	Count    int                     `json:"count"`
+}
+
+// ApplicationTrackerResponse is a tracker with its contacts and interaction log
+type ApplicationTrackerResponse struct {
+	*db.ApplicationTracker
+	Contacts     []db.ApplicationContact     `json:"contacts"`
+	Interactions []db.ApplicationInteraction `json:"interactions"`
+}
+
+// ApplicationContactRequest is the request body for adding or replacing a contact
+type ApplicationContactRequest struct {
+	Name        string `json:"name"`
+	Role        string `json:"role,omitempty"`
+	Email       string `json:"email,omitempty"`
+	LinkedInURL string `json:"linkedin_url,omitempty"`
+}
+
+// ApplicationInteractionRequest is the request body for logging an interaction.
+// occurred_at defaults to now.
+type ApplicationInteractionRequest struct {
+	Kind       string     `json:"kind"`
+	ContactID  *uuid.UUID `json:"contact_id,omitempty"`
+	OccurredAt *time.Time `json:"occurred_at,omitempty"`
+	Notes      string     `json:"notes,omitempty"`
+}
+
+// handleListApplicationTrackers lists the caller's application trackers
+func (s *Server) handleListApplicationTrackers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "applications")
+	if !ok {
+		return
+	}
+
+	trackers, err := s.db.ListApplicationTrackers(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, ApplicationTrackerListResponse{Trackers: trackers, Count: len(trackers)})
+}
+
+// handleSaveApplicationTracker creates the caller's tracker for a company, or replaces its
+// notes and next action
+func (s *Server) handleSaveApplicationTracker(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "applications")
+	if !ok {
+		return
+	}
+
+	var req ApplicationTrackerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	input := &db.ApplicationTrackerInput{
+		Company:      strings.TrimSpace(req.Company),
+		Notes:        req.Notes,
+		NextAction:   strings.TrimSpace(req.NextAction),
+		NextActionAt: req.NextActionAt,
+	}
+	if err := validateApplicationTracker(input); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tracker, err := s.db.UpsertApplicationTracker(r.Context(), userID, input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, tracker)
+}
+
+// handleGetApplicationTracker returns one of the caller's trackers with its contacts and
+// interaction log
+func (s *Server) handleGetApplicationTracker(w http.ResponseWriter, r *http.Request) {
+	tracker, ok := s.callerApplicationTracker(w, r)
+	if !ok {
+		return
+	}
+
+	contacts, err := s.db.ListApplicationContacts(r.Context(), tracker.ID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	interactions, err := s.db.ListApplicationInteractions(r.Context(), tracker.ID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, ApplicationTrackerResponse{
+		ApplicationTracker: tracker,
+		Contacts:           contacts,
+		Interactions:       interactions,
+	})
+}
+
+// handleDeleteApplicationTracker deletes one of the caller's trackers with its contacts and
+// interaction log
+func (s *Server) handleDeleteApplicationTracker(w http.ResponseWriter, r *http.Request) {
+	tracker, ok := s.callerApplicationTracker(w, r)
+	if !ok {
+		return
+	}
+	if err := s.db.DeleteApplicationTracker(r.Context(), tracker.ID); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCreateApplicationContact adds a contact to one of the caller's trackers
+func (s *Server) handleCreateApplicationContact(w http.ResponseWriter, r *http.Request) {
+	tracker, ok := s.callerApplicationTracker(w, r)
+	if !ok {
+		return
+	}
+	input, ok := s.decodeApplicationContact(w, r)
+	if !ok {
+		return
+	}
+
+	contact, err := s.db.CreateApplicationContact(r.Context(), tracker.ID, input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusCreated, contact)
+}
+
+// handleUpdateApplicationContact replaces a contact on one of the caller's trackers
+func (s *Server) handleUpdateApplicationContact(w http.ResponseWriter, r *http.Request) {
+	tracker, ok := s.callerApplicationTracker(w, r)
+	if !ok {
+		return
+	}
+	contactID, err := uuid.Parse(r.PathValue("contact_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid contact ID")
+		return
+	}
+	input, ok := s.decodeApplicationContact(w, r)
+	if !ok {
+		return
+	}
+
+	contact, err := s.db.UpdateApplicationContact(r.Context(), tracker.ID, contactID, input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if contact == nil {
+		s.errorResponse(w, http.StatusNotFound, "Contact not found")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, contact)
+}
+
+// handleDeleteApplicationContact deletes a contact from one of the caller's trackers
+func (s *Server) handleDeleteApplicationContact(w http.ResponseWriter, r *http.Request) {
+	tracker, ok := s.callerApplicationTracker(w, r)
+	if !ok {
+		return
+	}
+	contactID, err := uuid.Parse(r.PathValue("contact_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid contact ID")
+		return
+	}
+
+	deleted, err := s.db.DeleteApplicationContact(r.Context(), tracker.ID, contactID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if !deleted {
+		s.errorResponse(w, http.StatusNotFound, "Contact not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCreateApplicationInteraction logs an interaction on one of the caller's trackers
+func (s *Server) handleCreateApplicationInteraction(w http.ResponseWriter, r *http.Request) {
+	tracker, ok := s.callerApplicationTracker(w, r)
+	if !ok {
+		return
+	}
+
+	var req ApplicationInteractionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	input := &db.ApplicationInteractionInput{
+		ContactID:  req.ContactID,
+		Kind:       strings.TrimSpace(req.Kind),
+		OccurredAt: time.Now(),
+		Notes:      req.Notes,
+	}
+	if req.OccurredAt != nil {
+		input.OccurredAt = *req.OccurredAt
+	}
+	if !slices.Contains(db.InteractionKinds, input.Kind) {
+		s.errorResponse(w, http.StatusBadRequest, "kind must be one of: "+strings.Join(db.InteractionKinds, ", "))
+		return
+	}
+	if len(input.Notes) > maxTrackerNotesLength {
+		s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("notes must be at most %d characters", maxTrackerNotesLength))
+		return
+	}
+
+	// The contact must be one of this tracker's, not another company's or user's
+	if input.ContactID != nil {
+		contacts, err := s.db.ListApplicationContacts(r.Context(), tracker.ID)
+		if err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+			return
+		}
+		if !slices.ContainsFunc(contacts, func(c db.ApplicationContact) bool { return c.ID == *input.ContactID }) {
+			s.errorResponse(w, http.StatusBadRequest, "contact_id is not a contact of this application")
+			return
+		}
+	}
+
+	interaction, err := s.db.CreateApplicationInteraction(r.Context(), tracker.ID, input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusCreated, interaction)
+}
+
+// handleDeleteApplicationInteraction deletes an entry from one of the caller's
+// interaction logs
+func (s *Server) handleDeleteApplicationInteraction(w http.ResponseWriter, r *http.Request) {
+	tracker, ok := s.callerApplicationTracker(w, r)
+	if !ok {
+		return
+	}
+	interactionID, err := uuid.Parse(r.PathValue("interaction_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid interaction ID")
+		return
+	}
+
+	deleted, err := s.db.DeleteApplicationInteraction(r.Context(), tracker.ID, interactionID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if !deleted {
+		s.errorResponse(w, http.StatusNotFound, "Interaction not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// callerApplicationTracker looks up the tracker named by the path, writing the error
+// response unless it belongs to the caller
+func (s *Server) callerApplicationTracker(w http.ResponseWriter, r *http.Request) (*db.ApplicationTracker, bool) {
+	userID, ok := s.pathUserIsCaller(w, r, "applications")
+	if !ok {
+		return nil, false
+	}
+	trackerID, err := uuid.Parse(r.PathValue("tracker_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid application ID")
+		return nil, false
+	}
+
+	tracker, err := s.db.GetApplicationTracker(r.Context(), trackerID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return nil, false
+	}
+	if tracker == nil || tracker.UserID != userID {
+		s.errorResponse(w, http.StatusNotFound, "Application not found")
+		return nil, false
+	}
+	return tracker, true
+}
+
+// decodeApplicationContact reads and checks a contact request body, writing the error
+// response if it is invalid
+func (s *Server) decodeApplicationContact(w http.ResponseWriter, r *http.Request) (*db.ApplicationContactInput, bool) {
+	var req ApplicationContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return nil, false
+	}
+	input := &db.ApplicationContactInput{
+		Name:        strings.TrimSpace(req.Name),
+		Role:        strings.TrimSpace(req.Role),
+		Email:       strings.TrimSpace(req.Email),
+		LinkedInURL: strings.TrimSpace(req.LinkedInURL),
+	}
+	if err := validateApplicationContact(input); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return input, true
+}
+
+// validateApplicationTracker checks a tracker's fields
+func validateApplicationTracker(input *db.ApplicationTrackerInput) error {
+	if input.Company == "" {
+		return fmt.Errorf("company is required")
+	}
+	if len(input.Company) > maxPresetNameLength {
+		return fmt.Errorf("company must be at most %d characters", maxPresetNameLength)
+	}
+	if len(input.Notes) > maxTrackerNotesLength {
+		return fmt.Errorf("notes must be at most %d characters", maxTrackerNotesLength)
+	}
+	if len(input.NextAction) > maxNextActionLength {
+		return fmt.Errorf("next_action must be at most %d characters", maxNextActionLength)
+	}
+	if input.NextActionAt != nil && input.NextAction == "" {
+		return fmt.Errorf("next_action is required with next_action_at")
+	}
+	return nil
+}
+
+// validateApplicationContact checks a contact's fields. Email addresses are bare
+// ("jane@example.com"), and LinkedIn URLs must be https links to linkedin.com.
+func validateApplicationContact(input *db.ApplicationContactInput) error {
+	if input.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	for _, field := range []string{input.Name, input.Role, input.Email, input.LinkedInURL} {
+		if len(field) > maxContactFieldLength {
+			return fmt.Errorf("contact fields must be at most %d characters", maxContactFieldLength)
+		}
+	}
+	if input.Email != "" {
+		if addr, err := mail.ParseAddress(input.Email); err != nil || addr.Address != input.Email {
+			return fmt.Errorf("email is not a valid address")
+		}
+	}
+	if input.LinkedInURL != "" {
+		u, err := url.Parse(input.LinkedInURL)
+		if err != nil || u.Scheme != "https" ||
+			(u.Hostname() != "linkedin.com" && !strings.HasSuffix(u.Hostname(), ".linkedin.com")) {
+			return fmt.Errorf("linkedin_url must be an https link to linkedin.com")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestApplicationTrackerHandlers(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	base := "/v1/users/" + owner.String() + "/applications"
+
+	save := func(body string) (int, db.ApplicationTracker) {
+		t.Helper()
+		w := serveAuthed(t, s, "POST /v1/users/{id}/applications", s.handleSaveApplicationTracker,
+			bearerRequest(t, s, http.MethodPost, base, owner, []byte(body)))
+		var tracker db.ApplicationTracker
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tracker))
+		}
+		return w.Code, tracker
+	}
+
+	code, tracker := save(`{"company":"Acme","notes":"Referred by Sam","next_action":"Email the recruiter","next_action_at":"2026-03-10T17:00:00Z"}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Email the recruiter", *tracker.NextAction)
+
+	// The same company in another case replaces the tracker
+	code, again := save(`{"company":"acme","notes":"Phone screen booked"}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, tracker.ID, again.ID)
+	assert.Nil(t, again.NextAction)
+
+	for _, body := range []string{`{"company":" "}`, `{"company":"Acme","next_action_at":"2026-03-10T17:00:00Z"}`, `{`} {
+		code, _ = save(body)
+		assert.Equal(t, http.StatusBadRequest, code, body)
+	}
+
+	trackerURL := base + "/" + tracker.ID.String()
+	w := serveAuthed(t, s, "POST /v1/users/{id}/applications/{tracker_id}/contacts", s.handleCreateApplicationContact,
+		bearerRequest(t, s, http.MethodPost, trackerURL+"/contacts", owner,
+			[]byte(`{"name":"Jane Doe","role":"Recruiter","email":"jane@acme.com","linkedin_url":"https://www.linkedin.com/in/janedoe"}`)))
+	require.Equal(t, http.StatusCreated, w.Code)
+	var contact db.ApplicationContact
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &contact))
+
+	for _, body := range []string{
+		`{"name":""}`,
+		`{"name":"Jane","email":"Jane <jane@acme.com>"}`,
+		`{"name":"Jane","linkedin_url":"https://linkedin.com.evil.example/in/jane"}`,
+		`{"name":"Jane","linkedin_url":"http://www.linkedin.com/in/jane"}`,
+	} {
+		w = serveAuthed(t, s, "POST /v1/users/{id}/applications/{tracker_id}/contacts", s.handleCreateApplicationContact,
+			bearerRequest(t, s, http.MethodPost, trackerURL+"/contacts", owner, []byte(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	logInteraction := func(body string) int {
+		t.Helper()
+		w := serveAuthed(t, s, "POST /v1/users/{id}/applications/{tracker_id}/interactions", s.handleCreateApplicationInteraction,
+			bearerRequest(t, s, http.MethodPost, trackerURL+"/interactions", owner, []byte(body)))
+		return w.Code
+	}
+	assert.Equal(t, http.StatusCreated, logInteraction(`{"kind":"call","contact_id":"`+contact.ID.String()+`","notes":"Intro call"}`))
+	assert.Equal(t, http.StatusBadRequest, logInteraction(`{"kind":"carrier_pigeon"}`))
+	assert.Equal(t, http.StatusBadRequest, logInteraction(`{"kind":"email","contact_id":"`+uuid.NewString()+`"}`))
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/applications/{tracker_id}", s.handleGetApplicationTracker,
+		bearerRequest(t, s, http.MethodGet, trackerURL, owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var detail ApplicationTrackerResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	assert.Equal(t, "acme", detail.Company)
+	require.Len(t, detail.Contacts, 1)
+	assert.Equal(t, "Recruiter", *detail.Contacts[0].Role)
+	require.Len(t, detail.Interactions, 1)
+	assert.Equal(t, contact.ID, *detail.Interactions[0].ContactID)
+	assert.WithinDuration(t, time.Now(), detail.Interactions[0].OccurredAt, time.Minute, "occurred_at defaults to now")
+
+	// Another user's tracker is not found, even through their own user path
+	other := uuid.New()
+	w = serveAuthed(t, s, "GET /v1/users/{id}/applications/{tracker_id}", s.handleGetApplicationTracker,
+		bearerRequest(t, s, http.MethodGet, "/v1/users/"+other.String()+"/applications/"+tracker.ID.String(), other, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serveAuthed(t, s, "GET /v1/users/{id}/applications/{tracker_id}", s.handleGetApplicationTracker,
+		bearerRequest(t, s, http.MethodGet, trackerURL, other, nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serveAuthed(t, s, "DELETE /v1/users/{id}/applications/{tracker_id}/contacts/{contact_id}", s.handleDeleteApplicationContact,
+		bearerRequest(t, s, http.MethodDelete, trackerURL+"/contacts/"+contact.ID.String(), owner, nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serveAuthed(t, s, "DELETE /v1/users/{id}/applications/{tracker_id}/contacts/{contact_id}", s.handleDeleteApplicationContact,
+		bearerRequest(t, s, http.MethodDelete, trackerURL+"/contacts/"+contact.ID.String(), owner, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSendDueReminders_NextAction(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	rec := &webhookRecorder{}
+	url := rec.server(t).URL
+	s.mock.notifyPrefs = []db.NotificationPreference{
+		{UserID: user, EventType: db.NotificationReminder, Channel: db.NotificationWebhook, WebhookURL: &url},
+	}
+
+	soon := time.Now().Add(2 * time.Hour)
+	input := &db.ApplicationTrackerInput{Company: "Acme", NextAction: "Send thank-you note", NextActionAt: &soon}
+	_, err := s.mock.UpsertApplicationTracker(context.Background(), user, input)
+	require.NoError(t, err)
+
+	s.sendDueReminders(context.Background())
+	s.sendDueReminders(context.Background())
+	require.Len(t, rec.messages, 1)
+	assert.Contains(t, rec.messages[0].Subject, "Send thank-you note at Acme")
+
+	// Saving the tracker again re-arms the reminder
+	_, err = s.mock.UpsertApplicationTracker(context.Background(), user, input)
+	require.NoError(t, err)
+	s.sendDueReminders(context.Background())
+	assert.Len(t, rec.messages, 2)
+}
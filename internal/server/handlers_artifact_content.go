@@ -0,0 +1,91 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+)
+
+// stepContentTypes are the media types of binary artifacts whose step says what they are
+var stepContentTypes = map[string]string{
+	db.StepResumePDF:  "application/pdf",
+	db.StepResumeDOCX: rendering.DOCXContentType,
+}
+
+// handleGetArtifactContent streams an artifact's raw content rather than wrapping it in
+// JSON, reading it from the database a chunk at a time. It answers Range requests, so
+// large raw HTML, corpus text, and PDFs can be fetched in parts or resumed.
+func (s *Server) handleGetArtifactContent(w http.ResponseWriter, r *http.Request) {
+	artifactID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid artifact ID format")
+		return
+	}
+
+	content, err := s.db.OpenArtifactContent(r.Context(), artifactID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if content == nil {
+		s.errorResponse(w, http.StatusNotFound, "Artifact not found")
+		return
+	}
+
+	// Debug artifacts contain raw LLM traffic and are restricted to the run owner or an admin
+	if content.Category == db.CategoryDebug {
+		if err := s.authorizeDebugArtifact(r, content.RunID); err != nil {
+			s.errorResponse(w, HTTPStatus(err), err.Error())
+			return
+		}
+	}
+
+	contentType, err := artifactContentType(content)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	serveArtifactContent(w, r, content)
+}
+
+// serveArtifactContent writes content with http.ServeContent, which answers Range,
+// If-Range, and conditional requests. The Content-Type header must already be set.
+func serveArtifactContent(w http.ResponseWriter, r *http.Request, content *db.ArtifactContent) {
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", fmt.Sprintf(`"%s-%d"`, content.ID, content.CreatedAt.UnixMicro()))
+	http.ServeContent(w, r, "", content.CreatedAt, content)
+}
+
+// artifactContentType returns the media type an artifact is served as. Text is always
+// served as plain text, so stored HTML is never rendered on this origin; binary content
+// is named by its step or sniffed, falling back to application/octet-stream.
+func artifactContentType(content *db.ArtifactContent) (string, error) {
+	switch content.Format {
+	case db.ContentFormatJSON:
+		return "application/json", nil
+	case db.ContentFormatText:
+		return "text/plain; charset=utf-8", nil
+	}
+	if contentType, ok := stepContentTypes[content.Step]; ok {
+		return contentType, nil
+	}
+
+	head := make([]byte, 512)
+	n, err := content.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	contentType := http.DetectContentType(head[:n])
+	if strings.HasPrefix(contentType, "text/") {
+		return "application/octet-stream", nil
+	}
+	return contentType, nil
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func getArtifactContent(s *testServer, artifactID, rangeHeader string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/artifact/"+artifactID+"/content", nil)
+	req.SetPathValue("id", artifactID)
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	w := httptest.NewRecorder()
+	s.handleGetArtifactContent(w, req)
+	return w
+}
+
+func TestHandleGetArtifactContent(t *testing.T) {
+	s := newTestServer()
+	id := uuid.New()
+	html := "<html><body>Senior Engineer</body></html>"
+	s.mock.artifacts[id] = &db.Artifact{ID: id, RunID: uuid.New(), Step: "raw_html", Category: "ingestion", TextContent: html}
+
+	w := getArtifactContent(s, id.String(), "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"), "stored HTML is never rendered")
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	assert.Equal(t, html, w.Body.String())
+
+	w = getArtifactContent(s, id.String(), "bytes=12-26")
+	require.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "bytes 12-26/41", w.Header().Get("Content-Range"))
+	assert.Equal(t, "Senior Engineer", w.Body.String())
+
+	w = getArtifactContent(s, id.String(), "bytes=100-")
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+
+	jsonID := uuid.New()
+	s.mock.artifacts[jsonID] = &db.Artifact{ID: jsonID, Step: "job_profile", Content: map[string]any{"company": "Acme"}}
+	w = getArtifactContent(s, jsonID.String(), "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"company":"Acme"}`, w.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, getArtifactContent(s, "not-a-uuid", "").Code)
+	assert.Equal(t, http.StatusNotFound, getArtifactContent(s, uuid.New().String(), "").Code)
+}
+
+func TestHandleGetArtifactContent_DebugRequiresOwner(t *testing.T) {
+	s := newAuthTestServer(t)
+	id := uuid.New()
+	s.mock.artifacts[id] = &db.Artifact{ID: id, RunID: uuid.New(), Step: "llm_trace", Category: db.CategoryDebug, TextContent: "prompt"}
+
+	w := getArtifactContent(s, id.String(), "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestArtifactContentType(t *testing.T) {
+	binary := func(step string, data []byte) *db.ArtifactContent {
+		return &db.ArtifactContent{Step: step, Format: db.ContentFormatBinary, Size: int64(len(data)),
+			SectionReader: io.NewSectionReader(bytes.NewReader(data), 0, int64(len(data)))}
+	}
+
+	for name, tc := range map[string]struct {
+		content *db.ArtifactContent
+		want    string
+	}{
+		"pdf step":    {binary(db.StepResumePDF, []byte("anything")), "application/pdf"},
+		"sniffed png": {binary("thumbnail", []byte("\x89PNG\r\n\x1a\n0000")), "image/png"},
+		"html":        {binary("page", []byte("<html><script>alert(1)</script>")), "application/octet-stream"},
+		"empty":       {binary("page", nil), "application/octet-stream"},
+		"text":        {&db.ArtifactContent{Format: db.ContentFormatText}, "text/plain; charset=utf-8"},
+	} {
+		got, err := artifactContentType(tc.content)
+		require.NoError(t, err, name)
+		assert.Equal(t, tc.want, got, name)
+	}
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/resumediff"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// diffableArtifacts are the artifacts GET /v1/runs/{run_id}/artifacts/{step}/diff compares
+var diffableArtifacts = []string{db.StepResumePlan, db.StepRewrittenBullets, db.StepResumeTex}
+
+// ArtifactDiffResponse is the response for a run artifact diff
+type ArtifactDiffResponse struct {
+	RunID   uuid.UUID `json:"run_id"`
+	Step    string    `json:"step"`
+	Version int       `json:"version"` // Version compared
+	Against int       `json:"against"` // Version it is compared with
+	*resumediff.ArtifactDiff
+}
+
+// handleRunArtifactDiff compares two versions of a run's plan, bullets, or LaTeX. By
+// default the version its step produced is compared with the final version, showing
+// what the repair loop changed.
+func (s *Server) handleRunArtifactDiff(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("run_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID format")
+		return
+	}
+	step := r.PathValue("step")
+	if !slices.Contains(diffableArtifacts, step) {
+		s.errorResponse(w, http.StatusBadRequest, "step must be one of "+strings.Join(diffableArtifacts, ", "))
+		return
+	}
+	version := 1
+	if v := r.URL.Query().Get("version"); v != "" {
+		if version, err = strconv.Atoi(v); err != nil || version < 1 {
+			s.errorResponse(w, http.StatusBadRequest, "version must be a positive integer")
+			return
+		}
+	}
+	against := 0 // The final version
+	if v := r.URL.Query().Get("against"); v != "" && v != "final" {
+		if against, err = strconv.Atoi(v); err != nil || against < 1 {
+			s.errorResponse(w, http.StatusBadRequest, "against must be final or a positive integer")
+			return
+		}
+	}
+
+	run, err := s.db.GetRun(r.Context(), runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if run == nil {
+		s.errorResponse(w, http.StatusNotFound, "Run not found")
+		return
+	}
+
+	versions, err := s.db.ListArtifactVersions(r.Context(), runID, step)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to list artifact versions: "+err.Error())
+		return
+	}
+	if len(versions) == 0 {
+		s.errorResponse(w, http.StatusNotFound, step+" artifact not found for this run")
+		return
+	}
+	if against == 0 {
+		against = versions[len(versions)-1].Version
+	}
+
+	before, err := s.db.GetArtifactVersion(r.Context(), runID, step, version)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to get artifact version: "+err.Error())
+		return
+	}
+	after, err := s.db.GetArtifactVersion(r.Context(), runID, step, against)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to get artifact version: "+err.Error())
+		return
+	}
+	if before == nil || after == nil {
+		s.errorResponse(w, http.StatusNotFound, fmt.Sprintf("%s has %d versions for this run", step, len(versions)))
+		return
+	}
+
+	resp := ArtifactDiffResponse{RunID: runID, Step: step, Version: version, Against: against}
+	switch step {
+	case db.StepResumeTex:
+		resp.ArtifactDiff = resumediff.DiffLaTeX(before.TextContent, after.TextContent)
+	case db.StepResumePlan:
+		var beforePlan, afterPlan types.ResumePlan
+		err = decodeArtifactVersions(before, after, &beforePlan, &afterPlan)
+		resp.ArtifactDiff = resumediff.DiffPlans(&beforePlan, &afterPlan)
+	default:
+		var beforeBullets, afterBullets types.RewrittenBullets
+		err = decodeArtifactVersions(before, after, &beforeBullets, &afterBullets)
+		resp.ArtifactDiff = resumediff.DiffBullets(&beforeBullets, &afterBullets)
+	}
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
+
+// decodeArtifactVersions decodes the JSON content of two artifact versions into before
+// and after
+func decodeArtifactVersions(beforeArtifact, afterArtifact *db.Artifact, before, after any) error {
+	for _, pair := range []struct {
+		artifact *db.Artifact
+		v        any
+	}{{beforeArtifact, before}, {afterArtifact, after}} {
+		content, err := json.Marshal(pair.artifact.Content)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s version %d: %w", pair.artifact.Step, pair.artifact.Version, err)
+		}
+		if err := json.Unmarshal(content, pair.v); err != nil {
+			return fmt.Errorf("failed to decode %s version %d: %w", pair.artifact.Step, pair.artifact.Version, err)
+		}
+	}
+	return nil
+}
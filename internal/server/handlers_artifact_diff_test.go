@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/resumediff"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func serveArtifactDiff(s *testServer, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestHandleRunArtifactDiff(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Status: "completed"}
+	base := "/v1/runs/" + runID.String() + "/artifacts/"
+
+	// Nothing rewritten yet
+	assert.Equal(t, http.StatusNotFound, serveArtifactDiff(s, base+"rewritten_bullets/diff?against=final").Code)
+
+	original := types.RewrittenBullets{Bullets: []types.RewrittenBullet{
+		{OriginalBulletID: "b1", FinalText: "Made CI a lot faster"},
+		{OriginalBulletID: "b2", FinalText: "Mentored four engineers"},
+	}}
+	s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: db.StepRewrittenBullets, Version: 1, Content: original}
+
+	// Without a repair the step's version is the final one
+	w := serveArtifactDiff(s, base+"rewritten_bullets/diff")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp ArtifactDiffResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Version)
+	assert.Equal(t, 1, resp.Against)
+	assert.Equal(t, 2, resp.Unchanged)
+
+	final := types.RewrittenBullets{Bullets: []types.RewrittenBullet{{OriginalBulletID: "b1", FinalText: "Cut CI time by 60%"}}}
+	s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: db.StepRewrittenBullets, Version: 2, Content: final}
+	w = serveArtifactDiff(s, base+"rewritten_bullets/diff?against=final")
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = ArtifactDiffResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Against)
+	require.Len(t, resp.Modified, 1)
+	assert.Equal(t, "Made CI a lot faster", resp.Modified[0].Before)
+	assert.Equal(t, []resumediff.BulletChange{{BulletID: "b2", Before: "Mentored four engineers"}}, resp.Removed)
+
+	// Versions can be compared in either direction
+	w = serveArtifactDiff(s, base+"rewritten_bullets/diff?version=2&against=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = ArtifactDiffResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []resumediff.BulletChange{{BulletID: "b2", After: "Mentored four engineers"}}, resp.Added)
+
+	s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: db.StepResumeTex, Version: 1,
+		TextContent: "\\section{Experience}\nBuilt things\n\\section{Skills}\nGo\n"}
+	s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: db.StepResumeTex, Version: 2,
+		TextContent: "\\section{Skills}\nGo\n\\section{Experience}\nBuilt things\n"}
+	w = serveArtifactDiff(s, base+"resume_tex/diff")
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = ArtifactDiffResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Reordered)
+	assert.Equal(t, []string{"Skills", "Experience"}, resp.Reordered.After)
+
+	assert.Equal(t, http.StatusBadRequest, serveArtifactDiff(s, base+"job_profile/diff").Code)
+	assert.Equal(t, http.StatusBadRequest, serveArtifactDiff(s, base+"resume_tex/diff?against=latest").Code)
+	assert.Equal(t, http.StatusBadRequest, serveArtifactDiff(s, base+"resume_tex/diff?version=0").Code)
+	assert.Equal(t, http.StatusNotFound, serveArtifactDiff(s, base+"resume_tex/diff?version=3").Code)
+	assert.Equal(t, http.StatusNotFound, serveArtifactDiff(s, "/v1/runs/"+uuid.NewString()+"/artifacts/resume_tex/diff").Code)
+}
+
+func TestHandleArtifactVersions(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	base := "/v1/runs/" + runID.String() + "/artifacts/violations/versions"
+	assert.Equal(t, http.StatusNotFound, serveArtifactDiff(s, base).Code)
+
+	for version := 2; version >= 1; version-- {
+		id := uuid.New()
+		s.mock.artifacts[id] = &db.Artifact{ID: id, RunID: runID, Step: db.StepViolations, Version: version,
+			Content: map[string]any{"violations": version}}
+	}
+
+	w := serveArtifactDiff(s, base)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list ArtifactVersionListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, 2, list.Count)
+	assert.Equal(t, 1, list.Versions[0].Version, "oldest first")
+
+	w = serveArtifactDiff(s, base+"/2")
+	require.Equal(t, http.StatusOK, w.Code)
+	var artifact db.Artifact
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &artifact))
+	assert.Equal(t, 2, artifact.Version)
+	assert.Equal(t, map[string]any{"violations": float64(2)}, artifact.Content)
+
+	assert.Equal(t, http.StatusNotFound, serveArtifactDiff(s, base+"/3").Code)
+	assert.Equal(t, http.StatusBadRequest, serveArtifactDiff(s, base+"/latest").Code)
+}
+
+func TestHandleGetArtifactVersion_DebugRequiresOwner(t *testing.T) {
+	s := newAuthTestServer(t)
+	owner, other := uuid.New(), uuid.New()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, UserID: &owner, Status: "completed"}
+	id := uuid.New()
+	s.mock.artifacts[id] = &db.Artifact{ID: id, RunID: runID, Step: db.StepDebugLLMExchanges, Category: db.CategoryDebug,
+		Version: 1, Content: map[string]any{"prompt": "secret"}}
+	target := "/v1/runs/" + runID.String() + "/artifacts/" + db.StepDebugLLMExchanges + "/versions/1"
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		s.routes().ServeHTTP(w, req)
+		return w
+	}
+	assert.Equal(t, http.StatusUnauthorized, serveArtifactDiff(s, target).Code)
+	w := serve(bearerRequest(t, s, http.MethodGet, target, other, nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+	assert.Equal(t, http.StatusOK, serve(bearerRequest(t, s, http.MethodGet, target, owner, nil)).Code)
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// ArtifactVersionListResponse lists the versions of a run's artifact for a step
+type ArtifactVersionListResponse struct {
+	RunID    uuid.UUID            `json:"run_id"`
+	Step     string               `json:"step"`
+	Versions []db.ArtifactVersion `json:"versions"` // Oldest first
+	Count    int                  `json:"count"`
+}
+
+// handleListArtifactVersions lists every saved version of a run's artifact for a step,
+// oldest first
+func (s *Server) handleListArtifactVersions(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("run_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID format")
+		return
+	}
+	step := r.PathValue("step")
+
+	versions, err := s.db.ListArtifactVersions(r.Context(), runID, step)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to list artifact versions: "+err.Error())
+		return
+	}
+	if len(versions) == 0 {
+		s.errorResponse(w, http.StatusNotFound, step+" artifact not found for this run")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, ArtifactVersionListResponse{RunID: runID, Step: step, Versions: versions, Count: len(versions)})
+}
+
+// handleGetArtifactVersion returns one version of a run's artifact for a step
+func (s *Server) handleGetArtifactVersion(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("run_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID format")
+		return
+	}
+	step := r.PathValue("step")
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil || version < 1 {
+		s.errorResponse(w, http.StatusBadRequest, "version must be a positive integer")
+		return
+	}
+
+	artifact, err := s.db.GetArtifactVersion(r.Context(), runID, step, version)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to get artifact version: "+err.Error())
+		return
+	}
+	if artifact == nil {
+		s.errorResponse(w, http.StatusNotFound, "Artifact version not found")
+		return
+	}
+
+	// Debug artifacts contain raw LLM traffic and are restricted to the run owner or an admin
+	if artifact.Category == db.CategoryDebug {
+		if err := s.authorizeDebugArtifact(r, artifact.RunID); err != nil {
+			s.errorResponse(w, HTTPStatus(err), err.Error())
+			return
+		}
+	}
+	s.jsonResponse(w, http.StatusOK, artifact)
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/ats"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// handleRunATSReport returns a run's coverage of the job's keywords and hard requirements.
+// Runs finished before the report existed have it computed from their job profile and resume.
+func (s *Server) handleRunATSReport(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("run_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID format")
+		return
+	}
+
+	run, err := s.db.GetRun(r.Context(), runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if run == nil {
+		s.errorResponse(w, http.StatusNotFound, "Run not found")
+		return
+	}
+
+	var report ats.Report
+	found, err := s.loadArtifact(r, runID, db.StepATSReport, &report)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if found {
+		s.jsonResponse(w, http.StatusOK, &report)
+		return
+	}
+
+	var jobProfile types.JobProfile
+	hasProfile, err := s.loadArtifact(r, runID, db.StepJobProfile, &jobProfile)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	tex, err := s.db.GetTextArtifact(r.Context(), runID, db.StepResumeTex)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if !hasProfile || tex == "" {
+		s.errorResponse(w, http.StatusNotFound, "ATS report not found; the run has not rendered a resume yet")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, ats.Analyze(ats.ResumeText(tex), &jobProfile))
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/ats"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func serveATSReport(s *testServer, runID uuid.UUID) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/"+runID.String()+"/ats-report", nil)
+	w := httptest.NewRecorder()
+	s.routes().ServeHTTP(w, req)
+	return w
+}
+
+func TestHandleRunATSReport(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Status: "running"}
+
+	// Nothing rendered yet
+	assert.Equal(t, http.StatusNotFound, serveATSReport(s, runID).Code)
+
+	// Computed from the job profile and resume of a run without a stored report
+	s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: db.StepJobProfile, Content: types.JobProfile{
+		HardRequirements: []types.Requirement{{Skill: "Go"}},
+		Keywords:         []string{"Kafka"},
+	}}
+	s.mock.textArtifacts[runID.String()+":"+db.StepResumeTex] = "\\begin{document}\n\\begin{itemize}\n\\item Built Go services\n\\end{itemize}\n\\end{document}"
+
+	w := serveATSReport(s, runID)
+	require.Equal(t, http.StatusOK, w.Code)
+	var report ats.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 50, report.Score)
+	assert.Equal(t, []string{"Kafka"}, report.Missing)
+
+	// A stored report is returned as is
+	s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: db.StepATSReport, Content: ats.Report{Score: 90, Missing: []string{"Rust"}}}
+	w = serveATSReport(s, runID)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 90, report.Score)
+
+	assert.Equal(t, http.StatusNotFound, serveATSReport(s, uuid.New()).Code)
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/not-a-uuid/ats-report", nil)
+	w = httptest.NewRecorder()
+	s.routes().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/calendar"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/deadletter"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+	"github.com/jonathan/resume-customizer/internal/server/middleware"
+)
+
+// RunDatesRequest is the request body for setting a run's application dates.
+// Omitted or null fields clear the date.
+type RunDatesRequest struct {
+	DeadlineAt *time.Time `json:"deadline_at"`
+	FollowUpAt *time.Time `json:"follow_up_at"`
+}
+
+// CalendarFeedResponse is the response for looking up a user's calendar feed URL
+type CalendarFeedResponse struct {
+	URL string `json:"url"`
+}
+
+// handleUpdateRunDates sets the application deadline and follow-up date of a run the caller owns
+func (s *Server) handleUpdateRunDates(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID")
+		return
+	}
+
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	run, err := s.db.GetRun(r.Context(), runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if run == nil {
+		s.errorResponse(w, http.StatusNotFound, "Run not found")
+		return
+	}
+	if run.UserID == nil || *run.UserID != callerID {
+		s.errorResponse(w, http.StatusForbidden, "You can only set dates on your own runs")
+		return
+	}
+
+	var req RunDatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := s.db.SetRunDates(r.Context(), runID, req.DeadlineAt, req.FollowUpAt); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	run.DeadlineAt = req.DeadlineAt
+	run.FollowUpAt = req.FollowUpAt
+	s.jsonResponse(w, http.StatusOK, run)
+}
+
+// handleGetCalendarFeedURL returns the subscribable .ics feed URL for the caller
+func (s *Server) handleGetCalendarFeedURL(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "calendar feed")
+	if !ok {
+		return
+	}
+
+	token := s.jwtService.CalendarFeedToken(userID)
+	s.jsonResponse(w, http.StatusOK, CalendarFeedResponse{
+		URL: "/v1/users/" + userID.String() + "/calendar.ics?token=" + token,
+	})
+}
+
+// handleCalendarFeed serves the user's application deadlines and follow-ups as an iCalendar feed,
+// described in the user's time zone. It is authorized by the feed token rather than a bearer token so calendar apps can subscribe.
+func (s *Server) handleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	if !s.jwtService.ValidCalendarFeedToken(userID, r.URL.Query().Get("token")) {
+		s.errorResponse(w, http.StatusUnauthorized, "Invalid calendar feed token")
+		return
+	}
+
+	runs, err := s.db.ListApplicationDates(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	user, err := s.db.GetUser(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	loc := time.UTC
+	if user != nil {
+		loc = user.Location()
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="applications.ics"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(calendar.Feed("Job applications", runs, loc, time.Now())))
+}
+
+// sendDueReminders performs a single application reminder pass
+func (s *Server) sendDueReminders(ctx context.Context) {
+	reminders, err := s.db.ListDueReminders(ctx, s.notify.ReminderLead())
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list application reminders", "error", err)
+		return
+	}
+
+	for i := range reminders {
+		reminder := &reminders[i]
+
+		// Claim before sending so replicas never deliver the same reminder twice
+		claimed, err := s.db.ClaimNotification(ctx, reminder.Kind, reminder.RefID, reminder.Recipient.UserID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to claim reminder", "kind", reminder.Kind, "ref_id", reminder.RefID, "error", err)
+		}
+		if !claimed {
+			continue
+		}
+
+		msg := notifications.ReminderMessage(reminder)
+		if err := s.notifier.Send(ctx, &reminder.Recipient, msg); err != nil {
+			slog.WarnContext(ctx, "failed to send reminder", "kind", reminder.Kind, "ref_id", reminder.RefID,
+				logging.KeyUserID, reminder.Recipient.UserID, "error", err)
+			var runID *uuid.UUID
+			if reminder.Kind != db.ReminderNextAction {
+				runID = &reminder.RefID
+			}
+			s.recordDeadLetter(ctx, deadletter.Notification(&reminder.Recipient, reminder.Kind+":"+reminder.RefID.String(), runID, msg, err))
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestHandleUpdateRunDates(t *testing.T) {
+	owner := uuid.New()
+	s := newNotificationTestServer(t)
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Company: "Acme", RoleTitle: "SRE", UserID: &owner}
+	target := "/v1/runs/" + runID.String() + "/dates"
+	body := []byte(`{"deadline_at":"2026-03-10T17:00:00Z","follow_up_at":"2026-03-24T09:30:00Z"}`)
+
+	w := serveAuthed(t, s, "PUT /v1/runs/{id}/dates", s.handleUpdateRunDates,
+		bearerRequest(t, s, http.MethodPut, target, uuid.New(), body))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serveAuthed(t, s, "PUT /v1/runs/{id}/dates", s.handleUpdateRunDates,
+		bearerRequest(t, s, http.MethodPut, target, owner, body))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var run db.Run
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &run))
+	require.NotNil(t, run.DeadlineAt)
+	assert.Equal(t, time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC), run.DeadlineAt.UTC())
+	assert.NotNil(t, s.mock.runs[runID].FollowUpAt)
+
+	w = serveAuthed(t, s, "PUT /v1/runs/{id}/dates", s.handleUpdateRunDates,
+		bearerRequest(t, s, http.MethodPut, "/v1/runs/"+uuid.New().String()+"/dates", owner, body))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleCalendarFeed(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	deadline := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Company: "Acme", RoleTitle: "SRE", UserID: &user, DeadlineAt: &deadline}
+
+	// The feed URL is only handed to its owner
+	w := serveAuthed(t, s, "GET /v1/users/{id}/calendar", s.handleGetCalendarFeedURL,
+		bearerRequest(t, s, http.MethodGet, "/v1/users/"+user.String()+"/calendar", uuid.New(), nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/calendar", s.handleGetCalendarFeedURL,
+		bearerRequest(t, s, http.MethodGet, "/v1/users/"+user.String()+"/calendar", user, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var feed CalendarFeedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &feed))
+	assert.True(t, strings.Contains(feed.URL, "/calendar.ics?token="))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/users/{id}/calendar.ics", s.handleCalendarFeed)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, feed.URL, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "SUMMARY:Apply: SRE at Acme")
+	assert.Contains(t, w.Body.String(), "X-WR-TIMEZONE:UTC")
+
+	// Descriptions are written in the owner's time zone; event times stay UTC
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Timezone: "Europe/Berlin"}}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, feed.URL, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "X-WR-TIMEZONE:Europe/Berlin")
+	assert.Contains(t, w.Body.String(), "DTSTART:20260310T170000Z")
+	assert.Contains(t, w.Body.String(), "Due: Tue Mar 10\\, 18:00 CET")
+
+	// A token for another user does not open this feed
+	w = httptest.NewRecorder()
+	otherToken := s.jwtService.CalendarFeedToken(uuid.New())
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+user.String()+"/calendar.ics?token="+otherToken, nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSendDueReminders(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	rec := &webhookRecorder{}
+	url := rec.server(t).URL
+
+	soon := time.Now().Add(3 * time.Hour)
+	later := time.Now().Add(10 * 24 * time.Hour)
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Company: "Acme", RoleTitle: "SRE", UserID: &user,
+		DeadlineAt: &soon, FollowUpAt: &later}
+	s.mock.notifyPrefs = []db.NotificationPreference{
+		{UserID: user, EventType: db.NotificationReminder, Channel: db.NotificationWebhook, WebhookURL: &url},
+	}
+
+	s.sendDueReminders(context.Background())
+	s.sendDueReminders(context.Background())
+	require.Len(t, rec.messages, 1, "only the deadline within the lead time reminds, once")
+	assert.Equal(t, db.NotificationReminder, rec.messages[0].Event)
+	assert.Contains(t, rec.messages[0].Subject, "Apply: SRE at Acme")
+
+	// Moving the deadline re-arms its reminder
+	require.NoError(t, s.mock.SetRunDates(context.Background(), runID, &soon, &later))
+	s.sendDueReminders(context.Background())
+	assert.Len(t, rec.messages, 2)
+}
@@ -26,20 +26,25 @@ func parseQueryInt(r *http.Request, key string, defaultValue, maxValue int) int
 
 // handleListCompanies lists all companies with research profiles
 func (s *Server) handleListCompanies(w http.ResponseWriter, r *http.Request) {
-	limit := parseQueryInt(r, "limit", 50, 100)
-	offset := parseQueryInt(r, "offset", 0, 0)
+	page, ok := s.parseListPage(w, r, companyListSpec)
+	if !ok {
+		return
+	}
 
-	companies, total, err := s.db.ListCompaniesWithProfiles(r.Context(), limit, offset)
+	companies, total, err := s.db.ListCompaniesWithProfiles(r.Context(), page.Limit, page.Page)
 	if err != nil {
 		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
 		return
 	}
+	if companies == nil {
+		companies = []db.Company{}
+	}
 
 	s.jsonResponse(w, http.StatusOK, map[string]any{
-		"companies": companies,
-		"total":     total,
-		"limit":     limit,
-		"offset":    offset,
+		"companies":   companies,
+		"total":       total,
+		"limit":       page.Limit,
+		"next_cursor": nextCursor(page, companies, companySortKey),
 	})
 }
 
@@ -114,3 +119,46 @@ func (s *Server) handleListCompanyDomains(w http.ResponseWriter, r *http.Request
 		"count":   len(domains),
 	})
 }
+
+// handleListSimilarCompanies suggests peer companies with similar industry tags, tech stacks,
+// and values, so research cached for them can be reused to target them next
+func (s *Server) handleListSimilarCompanies(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	companyID, err := uuid.Parse(idStr)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+	limit := parseQueryInt(r, "limit", 10, 50)
+
+	company, err := s.db.GetCompanyByID(r.Context(), companyID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if company == nil {
+		s.errorResponse(w, http.StatusNotFound, "Company not found")
+		return
+	}
+
+	fingerprints, err := s.db.ListCompanyFingerprints(r.Context())
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	target := db.FindCompanyFingerprint(fingerprints, companyID)
+	if target == nil {
+		// Not researched yet: compare on the company's industry alone
+		target = &db.CompanyFingerprint{Company: *company}
+		if company.Industry != nil {
+			target.IndustryTags = db.IndustryTags(*company.Industry)
+		}
+	}
+
+	similar := db.RankSimilarCompanies(*target, fingerprints, limit)
+	s.jsonResponse(w, http.StatusOK, map[string]any{
+		"company_id": companyID,
+		"similar":    similar,
+		"count":      len(similar),
+	})
+}
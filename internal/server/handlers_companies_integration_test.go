@@ -95,7 +95,7 @@ func TestCompaniesEndpoints_Integration(t *testing.T) {
 		_, err := s.db.CreateCompanyProfile(ctx, profileInput)
 		require.NoError(t, err)
 
-		req := httptest.NewRequest(http.MethodGet, "/companies?limit=10&offset=0", nil)
+		req := httptest.NewRequest(http.MethodGet, "/companies?limit=10", nil)
 		w := httptest.NewRecorder()
 
 		s.handleListCompanies(w, req)
@@ -107,7 +107,7 @@ func TestCompaniesEndpoints_Integration(t *testing.T) {
 		assert.Contains(t, resp, "companies")
 		assert.Contains(t, resp, "total")
 		assert.Contains(t, resp, "limit")
-		assert.Contains(t, resp, "offset")
+		assert.Contains(t, resp, "next_cursor")
 
 		companies, ok := resp["companies"].([]any)
 		require.True(t, ok)
@@ -157,7 +157,7 @@ func TestListCompanies_Pagination_Integration(t *testing.T) {
 
 	// Test pagination
 	t.Run("Pagination", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/companies?limit=2&offset=0", nil)
+		req := httptest.NewRequest(http.MethodGet, "/companies?limit=2", nil)
 		w := httptest.NewRecorder()
 
 		s.handleListCompanies(w, req)
@@ -6,6 +6,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -152,3 +154,57 @@ func TestParseQueryInt(t *testing.T) {
 		})
 	}
 }
+
+// TestHandleListSimilarCompanies tests that peers are ranked by shared industry, stack, and values
+func TestHandleListSimilarCompanies(t *testing.T) {
+	s := newTestServer()
+	affirm := db.CompanyFingerprint{
+		Company:      db.Company{ID: uuid.New(), Name: "Affirm"},
+		IndustryTags: []string{"fintech", "lending"},
+		TechStack:    []string{"Go", "Kafka"},
+		HasProfile:   true,
+	}
+	klarna := db.CompanyFingerprint{
+		Company:      db.Company{ID: uuid.New(), Name: "Klarna"},
+		IndustryTags: []string{"fintech"},
+		TechStack:    []string{"Kafka"},
+		HasProfile:   true,
+	}
+	pixar := db.CompanyFingerprint{
+		Company:      db.Company{ID: uuid.New(), Name: "Pixar"},
+		IndustryTags: []string{"animation"},
+	}
+	s.mock.fingerprints = []db.CompanyFingerprint{affirm, klarna, pixar}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/companies/"+affirm.Company.ID.String()+"/similar", nil)
+	req.SetPathValue("id", affirm.Company.ID.String())
+	w := httptest.NewRecorder()
+
+	s.handleListSimilarCompanies(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Similar []db.SimilarCompany `json:"similar"`
+		Count   int                 `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 1, resp.Count)
+	assert.Equal(t, "Klarna", resp.Similar[0].Company.Name)
+	assert.Equal(t, []string{"fintech"}, resp.Similar[0].SharedIndustryTags)
+	assert.Equal(t, []string{"Kafka"}, resp.Similar[0].SharedTechStack)
+	assert.True(t, resp.Similar[0].HasProfile)
+}
+
+// TestHandleListSimilarCompanies_NotFound tests similar companies for an unknown company
+func TestHandleListSimilarCompanies_NotFound(t *testing.T) {
+	s := newTestServer()
+	id := uuid.New().String()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/companies/"+id+"/similar", nil)
+	req.SetPathValue("id", id)
+	w := httptest.NewRecorder()
+
+	s.handleListSimilarCompanies(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
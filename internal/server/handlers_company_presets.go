@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/templates"
+)
+
+const (
+	// maxPresetNameLength caps preset and company names
+	maxPresetNameLength = 100
+	// maxToneOverrideLength caps a tone override; company tones are a short phrase
+	maxToneOverrideLength = 300
+	// maxPinnedBullets caps the bullets one preset or run may pin
+	maxPinnedBullets = 10
+)
+
+// CompanyPresetRequest is the request body for creating or replacing a company preset
+type CompanyPresetRequest struct {
+	Company       string   `json:"company"`
+	Name          string   `json:"name"`
+	ToneOverride  string   `json:"tone_override,omitempty"`
+	PinnedBullets []string `json:"pinned_bullets,omitempty"`
+	Template      string   `json:"template,omitempty"`
+	SectionOrder  []string `json:"section_order,omitempty"`
+}
+
+// CompanyPresetListResponse is the response for listing company presets
+type CompanyPresetListResponse struct {
+	Presets []db.CompanyPreference `json:"presets"`
+	Count   int                    `json:"count"`
+}
+
+// handleListCompanyPresets lists the caller's company presets, optionally for one company
+func (s *Server) handleListCompanyPresets(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "company presets")
+	if !ok {
+		return
+	}
+
+	presets, err := s.db.ListCompanyPreferences(r.Context(), userID, strings.TrimSpace(r.URL.Query().Get("company")))
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, CompanyPresetListResponse{Presets: presets, Count: len(presets)})
+}
+
+// handleSaveCompanyPreset creates a company preset, or replaces the caller's preset with
+// the same company and name
+func (s *Server) handleSaveCompanyPreset(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "company presets")
+	if !ok {
+		return
+	}
+
+	var req CompanyPresetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	input := &db.CompanyPreferenceInput{
+		Company:       strings.TrimSpace(req.Company),
+		Name:          strings.TrimSpace(req.Name),
+		ToneOverride:  strings.TrimSpace(req.ToneOverride),
+		PinnedBullets: req.PinnedBullets,
+		Template:      strings.TrimSpace(req.Template),
+		SectionOrder:  req.SectionOrder,
+	}
+	if err := validateCompanyPreset(input); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if status, err := s.validateTemplateSections(r.Context(), userID, input.Template, input.SectionOrder); err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+
+	// Catch typos now rather than on the preset's next run
+	if len(input.PinnedBullets) > 0 {
+		bank, err := s.fetchExperienceBankFromDB(r.Context(), userID)
+		if err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, "Failed to fetch experience data: "+err.Error())
+			return
+		}
+		known := make(map[string]bool)
+		for _, story := range bank.Stories {
+			for _, b := range story.Bullets {
+				known[b.ID] = true
+			}
+		}
+		for _, id := range input.PinnedBullets {
+			if !known[id] {
+				s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("pinned bullet %q is not in your experience bank", id))
+				return
+			}
+		}
+	}
+
+	preset, err := s.db.UpsertCompanyPreference(r.Context(), userID, input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, preset)
+}
+
+// handleDeleteCompanyPreset deletes one of the caller's company presets
+func (s *Server) handleDeleteCompanyPreset(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "company presets")
+	if !ok {
+		return
+	}
+	presetID, err := uuid.Parse(r.PathValue("preset_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid preset ID")
+		return
+	}
+
+	preset, err := s.db.GetCompanyPreference(r.Context(), presetID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if preset == nil || preset.UserID != userID {
+		s.errorResponse(w, http.StatusNotFound, "Company preset not found")
+		return
+	}
+	if err := s.db.DeleteCompanyPreference(r.Context(), presetID); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateCompanyPreset checks a preset's fields; validateTemplateSections checks its
+// template
+func validateCompanyPreset(input *db.CompanyPreferenceInput) error {
+	if input.Company == "" || input.Name == "" {
+		return fmt.Errorf("company and name are required")
+	}
+	if len(input.Company) > maxPresetNameLength || len(input.Name) > maxPresetNameLength {
+		return fmt.Errorf("company and name must be at most %d characters", maxPresetNameLength)
+	}
+	if len(input.ToneOverride) > maxToneOverrideLength {
+		return fmt.Errorf("tone_override must be at most %d characters", maxToneOverrideLength)
+	}
+	if len(input.PinnedBullets) > maxPinnedBullets {
+		return fmt.Errorf("at most %d bullets can be pinned", maxPinnedBullets)
+	}
+	return nil
+}
+
+// validateTemplateSections checks that a saved template, a file path or a name in the
+// user's template registry, exists and supports a saved section order; an empty
+// template stands for the default one
+func (s *Server) validateTemplateSections(ctx context.Context, userID uuid.UUID, template string, order []string) (int, error) {
+	if template == "" {
+		template = "templates/one_page_resume.tex"
+	} else if !templates.IsName(template) {
+		if _, err := os.Stat(template); err != nil {
+			return http.StatusBadRequest, fmt.Errorf("template not found: %s", template)
+		}
+	}
+	resolved, status, err := s.resolveTemplate(ctx, userID, template)
+	if err != nil {
+		return status, err
+	}
+	if err := resolved.Manifest.Validate(&rendering.Style{SectionOrder: order}); err != nil {
+		return http.StatusBadRequest, err
+	}
+	return 0, nil
+}
+
+// applyCompanyPreset fills the run options req leaves unset from the company preset it
+// names; options set in the request win. It returns the HTTP status to fail with.
+func (s *Server) applyCompanyPreset(ctx context.Context, req *RunRequest) (int, error) {
+	if len(req.ToneOverride) > maxToneOverrideLength {
+		return http.StatusBadRequest, fmt.Errorf("tone_override must be at most %d characters", maxToneOverrideLength)
+	}
+	if len(req.PinnedBullets) > maxPinnedBullets {
+		return http.StatusBadRequest, fmt.Errorf("at most %d bullets can be pinned", maxPinnedBullets)
+	}
+	if req.PresetID == "" {
+		return 0, nil
+	}
+	presetID, err := uuid.Parse(req.PresetID)
+	if err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid preset_id")
+	}
+	preset, err := s.db.GetCompanyPreference(ctx, presetID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to fetch company preset: %w", err)
+	}
+	// handleRun rejects a malformed user_id itself, so it only has to fail to match here
+	userID, _ := uuid.Parse(req.UserID)
+	if preset == nil || preset.UserID != userID {
+		return http.StatusNotFound, fmt.Errorf("company preset not found")
+	}
+
+	if req.Template == "" && preset.Template != nil {
+		req.Template = *preset.Template
+	}
+	if req.ToneOverride == "" && preset.ToneOverride != nil {
+		req.ToneOverride = *preset.ToneOverride
+	}
+	if len(req.PinnedBullets) == 0 {
+		req.PinnedBullets = preset.PinnedBullets
+	}
+	if len(preset.SectionOrder) > 0 && (req.Style == nil || len(req.Style.SectionOrder) == 0) {
+		style := rendering.Style{}
+		if req.Style != nil {
+			style = *req.Style
+		}
+		style.SectionOrder = preset.SectionOrder
+		req.Style = &style
+	}
+	return 0, nil
+}
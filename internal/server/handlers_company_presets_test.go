@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+)
+
+const presetTemplate = "../../templates/one_page_resume.tex"
+
+func TestHandleSaveCompanyPreset(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	jobID, bulletID := uuid.New(), uuid.New()
+	s.mock.jobs = []db.Job{{ID: jobID, UserID: owner, Company: "Acme"}}
+	s.mock.experiences = []db.Experience{{ID: bulletID, JobID: jobID, BulletText: "Cut checkout latency 40%"}}
+	target := "/v1/users/" + owner.String() + "/company-presets"
+	save := func(caller uuid.UUID, body string) int {
+		return serveAuthed(t, s, "POST /v1/users/{id}/company-presets", s.handleSaveCompanyPreset,
+			bearerRequest(t, s, http.MethodPost, target, caller, []byte(body))).Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, save(uuid.New(), `{"company":"Acme","name":"Backend"}`))
+	assert.Equal(t, http.StatusBadRequest, save(owner, `{"company":"Acme"}`))
+	assert.Equal(t, http.StatusBadRequest, save(owner, `{"company":"Acme","name":"Backend","template":"missing.tex"}`))
+	assert.Equal(t, http.StatusBadRequest, save(owner,
+		`{"company":"Acme","name":"Backend","template":"`+presetTemplate+`","section_order":["projects"]}`))
+	assert.Equal(t, http.StatusBadRequest, save(owner, `{"company":"Acme","name":"Backend","pinned_bullets":["not-mine"]}`))
+
+	body := `{"company":"Acme","name":"Backend","tone_override":"plain and direct","pinned_bullets":["` + bulletID.String() +
+		`"],"template":"` + presetTemplate + `","section_order":["education"]}`
+	require.Equal(t, http.StatusOK, save(owner, body))
+	require.Equal(t, http.StatusOK, save(owner, `{"company":"ACME","name":"backend","tone_override":"warm"}`))
+	require.Len(t, s.mock.presets, 1, "saving under the same company and name replaces the preset")
+	assert.Equal(t, "warm", *s.mock.presets[0].ToneOverride)
+
+	w := serveAuthed(t, s, "GET /v1/users/{id}/company-presets", s.handleListCompanyPresets,
+		bearerRequest(t, s, http.MethodGet, target+"?company=acme", owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var list CompanyPresetListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Count)
+
+	del := func(caller uuid.UUID) int {
+		url := "/v1/users/" + caller.String() + "/company-presets/" + list.Presets[0].ID.String()
+		return serveAuthed(t, s, "DELETE /v1/users/{id}/company-presets/{preset_id}", s.handleDeleteCompanyPreset,
+			bearerRequest(t, s, http.MethodDelete, url, caller, nil)).Code
+	}
+	assert.Equal(t, http.StatusNotFound, del(uuid.New()))
+	assert.Equal(t, http.StatusNoContent, del(owner))
+	assert.Empty(t, s.mock.presets)
+}
+
+func TestApplyCompanyPreset(t *testing.T) {
+	owner := uuid.New()
+	s := newTestServer()
+	tone, template := "plain and direct", presetTemplate
+	preset := &db.CompanyPreference{
+		ID: uuid.New(), UserID: owner, Company: "Acme", Name: "Backend",
+		ToneOverride: &tone, Template: &template,
+		PinnedBullets: []string{"b1"}, SectionOrder: []string{"education"},
+	}
+	s.mock.presets = []*db.CompanyPreference{preset}
+
+	req := &RunRequest{UserID: owner.String(), PresetID: preset.ID.String(), Style: &rendering.Style{FontSize: "10pt"}}
+	status, err := s.applyCompanyPreset(context.Background(), req)
+	require.NoError(t, err, status)
+	assert.Equal(t, presetTemplate, req.Template)
+	assert.Equal(t, tone, req.ToneOverride)
+	assert.Equal(t, []string{"b1"}, req.PinnedBullets)
+	assert.Equal(t, &rendering.Style{FontSize: "10pt", SectionOrder: []string{"education"}}, req.Style)
+
+	// Options in the request win over the preset's
+	req = &RunRequest{UserID: owner.String(), PresetID: preset.ID.String(), ToneOverride: "warm", PinnedBullets: []string{"b2"}}
+	_, err = s.applyCompanyPreset(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "warm", req.ToneOverride)
+	assert.Equal(t, []string{"b2"}, req.PinnedBullets)
+
+	status, err = s.applyCompanyPreset(context.Background(), &RunRequest{UserID: uuid.New().String(), PresetID: preset.ID.String()})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, status, "presets of other users are not applied")
+	status, _ = s.applyCompanyPreset(context.Background(), &RunRequest{UserID: owner.String(), PresetID: "nope"})
+	assert.Equal(t, http.StatusBadRequest, status)
+}
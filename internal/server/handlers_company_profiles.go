@@ -1,9 +1,15 @@
 package server
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/server/middleware"
 )
 
 // handleGetCompanyProfile retrieves the profile for a company
@@ -28,6 +34,85 @@ func (s *Server) handleGetCompanyProfile(w http.ResponseWriter, r *http.Request)
 	s.jsonResponse(w, http.StatusOK, profile)
 }
 
+// ManualCompanyProfileRequest is a company profile entered by hand
+type ManualCompanyProfileRequest struct {
+	Tone          string            `json:"tone"`
+	DomainContext string            `json:"domain_context,omitempty"`
+	Values        []string          `json:"values,omitempty"`
+	StyleRules    []string          `json:"style_rules,omitempty"`
+	TabooPhrases  []string          `json:"taboo_phrases,omitempty"`
+	TabooReasons  map[string]string `json:"taboo_reasons,omitempty"` // Why a taboo phrase is avoided, by phrase
+}
+
+// handleCreateCompanyProfile stores a company profile the caller entered by hand, for
+// companies with nothing to crawl. It replaces the caller's earlier manual profile and
+// stands in for the shared, crawled profile in the caller's runs only.
+func (s *Server) handleCreateCompanyProfile(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	companyID, err := uuid.Parse(r.PathValue("company_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+
+	var req ManualCompanyProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Tone) == "" {
+		s.errorResponse(w, http.StatusBadRequest, "tone is required")
+		return
+	}
+
+	company, err := s.db.GetCompanyByID(r.Context(), companyID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if company == nil {
+		s.errorResponse(w, http.StatusNotFound, "Company not found")
+		return
+	}
+
+	input := &db.ProfileCreateInput{
+		CompanyID:     companyID,
+		Tone:          strings.TrimSpace(req.Tone),
+		DomainContext: strings.TrimSpace(req.DomainContext),
+		StyleRules:    trimmedItems(req.StyleRules),
+		Values:        trimmedItems(req.Values),
+	}
+	for _, phrase := range trimmedItems(req.TabooPhrases) {
+		input.TabooPhrases = append(input.TabooPhrases, db.TabooPhraseInput{
+			Phrase: phrase,
+			Reason: strings.TrimSpace(req.TabooReasons[phrase]),
+		})
+	}
+
+	profile, err := s.db.UpsertUserCompanyProfile(r.Context(), userID, input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to save company profile: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusCreated, profile)
+}
+
+// trimmedItems trims each item and drops the empty ones
+func trimmedItems(items []string) []string {
+	var out []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 // handleGetStyleRules retrieves style rules for a company profile
 func (s *Server) handleGetStyleRules(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("company_id")
@@ -152,3 +237,93 @@ func (s *Server) handleGetSources(w http.ResponseWriter, r *http.Request) {
 		"count":   len(sources),
 	})
 }
+
+// CompanyProfileVersionsResponse lists the kept versions of a company's profile
+type CompanyProfileVersionsResponse struct {
+	CompanyID uuid.UUID                  `json:"company_id"`
+	Versions  []db.CompanyProfileVersion `json:"versions"` // Newest first
+	Count     int                        `json:"count"`
+}
+
+// handleListCompanyProfileVersions lists every generated version of a company's profile
+func (s *Server) handleListCompanyProfileVersions(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("company_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+
+	versions, err := s.db.ListCompanyProfileVersions(r.Context(), companyID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if len(versions) == 0 {
+		s.errorResponse(w, http.StatusNotFound, "Company profile not found")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, CompanyProfileVersionsResponse{CompanyID: companyID, Versions: versions, Count: len(versions)})
+}
+
+// handleDiffCompanyProfileVersions reports how a company's tone, values, taboo phrases,
+// style rules, and evidence changed between ?from and ?to versions. ?to defaults to the
+// current version and ?from to the version before ?to.
+func (s *Server) handleDiffCompanyProfileVersions(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("company_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+	from, to := 0, 0
+	for name, into := range map[string]*int{"from": &from, "to": &to} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		if *into, err = strconv.Atoi(raw); err != nil || *into < 1 {
+			s.errorResponse(w, http.StatusBadRequest, "Invalid "+name+" version: must be a positive integer")
+			return
+		}
+	}
+
+	versions, err := s.db.ListCompanyProfileVersions(r.Context(), companyID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if len(versions) == 0 {
+		s.errorResponse(w, http.StatusNotFound, "Company profile not found")
+		return
+	}
+
+	// Versions are newest first
+	toIndex := 0
+	if to > 0 {
+		if toIndex = profileVersionIndex(versions, to); toIndex < 0 {
+			s.errorResponse(w, http.StatusNotFound, fmt.Sprintf("Company profile version %d not found", to))
+			return
+		}
+	}
+	fromIndex := toIndex + 1
+	if from > 0 {
+		if fromIndex = profileVersionIndex(versions, from); fromIndex < 0 {
+			s.errorResponse(w, http.StatusNotFound, fmt.Sprintf("Company profile version %d not found", from))
+			return
+		}
+	} else if fromIndex >= len(versions) {
+		s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Company profile version %d has no earlier version to compare with", versions[toIndex].Version))
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, db.DiffProfileVersions(&versions[fromIndex], &versions[toIndex]))
+}
+
+// profileVersionIndex returns the index of version in versions, or -1
+func profileVersionIndex(versions []db.CompanyProfileVersion, version int) int {
+	for i := range versions {
+		if versions[i].Version == version {
+			return i
+		}
+	}
+	return -1
+}
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -99,3 +102,100 @@ func TestHandleGetSources_InvalidID(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, resp["error"], "Invalid company ID")
 }
+
+func TestHandleCompanyProfileVersions(t *testing.T) {
+	s := newTestServer()
+	companyID := uuid.New()
+	s.mock.profileVersions = []db.CompanyProfileVersion{
+		{CompanyID: companyID, Version: 3, Tone: "warm", Values: []string{"Earn trust"},
+			TabooPhrases: []db.VersionTabooPhrase{{Phrase: "ninja"}}},
+		{CompanyID: companyID, Version: 2, Tone: "direct", Values: []string{"Earn trust"}},
+		{CompanyID: companyID, Version: 1, Tone: "direct", Values: []string{"Bias for action"}},
+	}
+	get := func(handler http.HandlerFunc, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.SetPathValue("company_id", companyID.String())
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	w := get(s.handleListCompanyProfileVersions, "/v1/companies/x/profile/versions")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list CompanyProfileVersionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 3, list.Count)
+	assert.Equal(t, 3, list.Versions[0].Version)
+
+	// By default the current version is compared with the one before it
+	w = get(s.handleDiffCompanyProfileVersions, "/v1/companies/x/profile/diff")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var diff db.ProfileVersionDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Equal(t, 2, diff.From)
+	assert.Equal(t, 3, diff.To)
+	assert.Equal(t, &db.ProfileTextChange{From: "direct", To: "warm"}, diff.Tone)
+	assert.Equal(t, []string{"ninja"}, diff.TabooPhrases.Added)
+	assert.Empty(t, diff.Values.Added)
+
+	w = get(s.handleDiffCompanyProfileVersions, "/v1/companies/x/profile/diff?from=1&to=2")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	diff = db.ProfileVersionDiff{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Nil(t, diff.Tone)
+	assert.Equal(t, []string{"Earn trust"}, diff.Values.Added)
+	assert.Equal(t, []string{"Bias for action"}, diff.Values.Removed)
+
+	assert.Equal(t, http.StatusBadRequest, get(s.handleDiffCompanyProfileVersions, "/v1/companies/x/profile/diff?to=1").Code,
+		"the first version has nothing to compare with")
+	assert.Equal(t, http.StatusBadRequest, get(s.handleDiffCompanyProfileVersions, "/v1/companies/x/profile/diff?from=two").Code)
+	assert.Equal(t, http.StatusNotFound, get(s.handleDiffCompanyProfileVersions, "/v1/companies/x/profile/diff?from=7").Code)
+
+	s.mock.profileVersions = nil
+	assert.Equal(t, http.StatusNotFound, get(s.handleListCompanyProfileVersions, "/v1/companies/x/profile/versions").Code)
+}
+
+// TestHandleCreateCompanyProfile tests entering a profile by hand for a company with nothing to crawl
+func TestHandleCreateCompanyProfile(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	company := db.Company{ID: uuid.New(), Name: "Tractor Works"}
+	s.mock.fingerprints = []db.CompanyFingerprint{{Company: company}}
+	postAs := func(caller uuid.UUID, companyID, body string) *httptest.ResponseRecorder {
+		return serveAuthed(t, s, "POST /v1/companies/{company_id}/profile", s.handleCreateCompanyProfile,
+			bearerRequest(t, s, http.MethodPost, "/v1/companies/"+companyID+"/profile", caller, []byte(body)))
+	}
+	post := func(companyID, body string) *httptest.ResponseRecorder {
+		return postAs(user, companyID, body)
+	}
+
+	w := post(company.ID.String(), `{"tone": " plain and practical ", "values": ["Reliability", " "],
+		"taboo_phrases": ["rockstar"], "taboo_reasons": {"rockstar": "Too flashy"}}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var profile db.CompanyProfile
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+	assert.Equal(t, db.ProfileSourceManual, profile.SourceType)
+	assert.Equal(t, &user, profile.UserID)
+	assert.Equal(t, "plain and practical", profile.Tone)
+	assert.Equal(t, []string{"Reliability"}, profile.Values)
+	assert.Equal(t, map[string]string{"rockstar": "Too flashy"}, profile.TabooReasons)
+
+	// A manual profile can be entered again
+	w = post(company.ID.String(), `{"tone": "warm"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, 2, s.mock.userCompanyProfiles[[2]uuid.UUID{user, company.ID}].Version)
+
+	// Another user's profile for the company is their own and leaves this one alone
+	other := uuid.New()
+	require.Equal(t, http.StatusCreated, postAs(other, company.ID.String(), `{"tone": "formal"}`).Code)
+	assert.Equal(t, "warm", s.mock.userCompanyProfiles[[2]uuid.UUID{user, company.ID}].Tone)
+	assert.Equal(t, "formal", s.mock.userCompanyProfiles[[2]uuid.UUID{other, company.ID}].Tone)
+
+	assert.Equal(t, http.StatusBadRequest, post(company.ID.String(), `{"tone": " "}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post("not-a-uuid", `{"tone": "warm"}`).Code)
+	assert.Equal(t, http.StatusNotFound, post(uuid.New().String(), `{"tone": "warm"}`).Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/companies/"+company.ID.String()+"/profile", strings.NewReader(`{"tone": "warm"}`))
+	w = serveAuthed(t, s, "POST /v1/companies/{company_id}/profile", s.handleCreateCompanyProfile, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
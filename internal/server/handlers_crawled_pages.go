@@ -2,10 +2,10 @@ package server
 
 import (
 	"net/http"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/fetch"
 )
 
 // CrawledPageResponse represents a crawled page response (without raw_html by default)
@@ -54,6 +54,9 @@ func (s *Server) handleGetCrawledPage(w http.ResponseWriter, r *http.Request) {
 
 	// Convert to response model
 	response := convertCrawledPageToResponse(page, includeHTML)
+	if includeHTML {
+		setCrawledHTMLHeaders(w)
+	}
 	s.jsonResponse(w, http.StatusOK, response)
 }
 
@@ -80,6 +83,9 @@ func (s *Server) handleGetCrawledPageByURL(w http.ResponseWriter, r *http.Reques
 
 	// Convert to response model
 	response := convertCrawledPageToResponse(page, includeHTML)
+	if includeHTML {
+		setCrawledHTMLHeaders(w)
+	}
 	s.jsonResponse(w, http.StatusOK, response)
 }
 
@@ -110,6 +116,17 @@ func (s *Server) handleListCrawledPagesByCompany(w http.ResponseWriter, r *http.
 	})
 }
 
+// crawledHTMLCSP forbids everything, so a crawled page's HTML opened directly as a
+// document can neither run nor load anything
+const crawledHTMLCSP = "default-src 'none'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'; sandbox"
+
+// setCrawledHTMLHeaders marks a response carrying crawled HTML as never to be rendered
+// or sniffed as a page
+func setCrawledHTMLHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Security-Policy", crawledHTMLCSP)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+}
+
 // convertCrawledPageToResponse converts a db.CrawledPage to CrawledPageResponse
 func convertCrawledPageToResponse(page *db.CrawledPage, includeHTML bool) CrawledPageResponse {
 	response := CrawledPageResponse{
@@ -124,26 +141,37 @@ func convertCrawledPageToResponse(page *db.CrawledPage, includeHTML bool) Crawle
 		ErrorMessage:       page.ErrorMessage,
 		IsPermanentFailure: page.IsPermanentFailure,
 		RetryCount:         page.RetryCount,
-		FetchedAt:          page.FetchedAt.Format(time.RFC3339),
-		LastAccessedAt:     page.LastAccessedAt.Format(time.RFC3339),
-		CreatedAt:          page.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:          page.UpdatedAt.Format(time.RFC3339),
+		FetchedAt:          formatTimestamp(page.FetchedAt),
+		LastAccessedAt:     formatTimestamp(page.LastAccessedAt),
+		CreatedAt:          formatTimestamp(page.CreatedAt),
+		UpdatedAt:          formatTimestamp(page.UpdatedAt),
 	}
 
 	if page.ExpiresAt != nil {
-		expiresAt := page.ExpiresAt.Format(time.RFC3339)
+		expiresAt := formatTimestamp(*page.ExpiresAt)
 		response.ExpiresAt = &expiresAt
 	}
 
 	if page.RetryAfter != nil {
-		retryAfter := page.RetryAfter.Format(time.RFC3339)
+		retryAfter := formatTimestamp(*page.RetryAfter)
 		response.RetryAfter = &retryAfter
 	}
 
-	// Only include raw_html if explicitly requested
-	if includeHTML {
-		response.RawHTML = page.RawHTML
+	// Only include raw_html if explicitly requested. Pages stored before HTML was
+	// sanitized on storage are sanitized here.
+	if includeHTML && page.RawHTML != nil {
+		sanitized := fetch.SanitizeHTML(*page.RawHTML)
+		response.RawHTML = &sanitized
 	}
 
 	return response
 }
+
+// handleFetchStats reports the sizes of pages this process has fetched and how many
+// were cut off at a size cap to admins
+func (s *Server) handleFetchStats(w http.ResponseWriter, r *http.Request) {
+	if !s.callerIsAdmin(w, r, "view fetch statistics") {
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, fetch.Stats())
+}
@@ -6,6 +6,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -75,3 +77,31 @@ func TestHandleListCrawledPagesByCompany_InvalidID(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, resp["error"], "Invalid company ID")
 }
+
+func TestHandleGetCrawledPage_SanitizesHTML(t *testing.T) {
+	s := newTestServer()
+	pageID := uuid.New()
+	// Stored before pages were sanitized on storage
+	raw := `<h1 onclick="steal()">Our values</h1><script>alert(1)</script><iframe src="https://ads.example"></iframe>`
+	s.mock.crawledPages = []db.CrawledPage{{ID: pageID, URL: "https://example.com/values", RawHTML: &raw}}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/crawled-pages/"+pageID.String()+"?include_html=true", nil)
+	req.SetPathValue("id", pageID.String())
+	w := httptest.NewRecorder()
+	s.handleGetCrawledPage(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp CrawledPageResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.RawHTML)
+	assert.Equal(t, "<h1>Our values</h1>", *resp.RawHTML)
+	assert.Equal(t, crawledHTMLCSP, w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/crawled-pages/"+pageID.String(), nil)
+	req.SetPathValue("id", pageID.String())
+	w = httptest.NewRecorder()
+	s.handleGetCrawledPage(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "raw_html")
+}
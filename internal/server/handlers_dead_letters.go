@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/deadletter"
+)
+
+const (
+	// deadLetterCheckInterval is how often the dead letter depth is compared with the alert threshold
+	deadLetterCheckInterval = 5 * time.Minute
+	// defaultDeadLetterLimit and maxDeadLetterLimit bound the dead letter listing
+	defaultDeadLetterLimit = 100
+	maxDeadLetterLimit     = 1000
+)
+
+// DeadLetterStatsResponse reports how many dead letters await an operator
+type DeadLetterStatsResponse struct {
+	Pending        map[string]int `json:"pending"` // By source
+	Total          int            `json:"total"`
+	AlertThreshold int            `json:"alert_threshold"` // 0 when alerting is off
+}
+
+// runDeadLetterMonitor periodically checks the number of pending dead letters and
+// raises an alert when it reaches the threshold
+func (s *Server) runDeadLetterMonitor(ctx context.Context) {
+	if s.deadLetters == nil || s.deadLetters.AlertThreshold == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(deadLetterCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.checkDeadLetters(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkDeadLetters performs a single alert check. The alert is logged and, when
+// configured, posted to the alert webhook.
+func (s *Server) checkDeadLetters(ctx context.Context) {
+	pending, err := s.db.CountPendingDeadLetters(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to count dead letters", "error", err)
+		return
+	}
+	total := 0
+	for _, n := range pending {
+		total += n
+	}
+	if !s.dlqAlarm.Check(total) {
+		return
+	}
+
+	threshold := s.deadLetters.AlertThreshold
+	slog.ErrorContext(ctx, "ALERT: pending dead letters over threshold", "pending", total, "threshold", threshold, "by_kind", pending)
+	if s.deadLetters.AlertWebhookURL == "" || s.notifier == nil {
+		return
+	}
+	recipient := &db.NotificationRecipient{Channel: db.NotificationWebhook, WebhookURL: &s.deadLetters.AlertWebhookURL}
+	if err := s.notifier.Send(ctx, recipient, deadletter.AlertMessage(pending, threshold)); err != nil {
+		slog.WarnContext(ctx, "failed to send dead letter alert", "error", err)
+	}
+}
+
+// handleListDeadLetters lists dead letters, oldest failure first. Pending dead letters
+// are listed unless ?status= asks for retried, discarded, or all.
+func (s *Server) handleListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if !s.callerIsAdmin(w, r, "view dead letters") {
+		return
+	}
+
+	query := r.URL.Query()
+	filters := db.DeadLetterFilters{
+		Source: query.Get("source"),
+		Status: query.Get("status"),
+		Limit:  defaultDeadLetterLimit,
+	}
+	if filters.Source != "" && !slices.Contains(db.DeadLetterSources, filters.Source) {
+		s.errorResponse(w, http.StatusBadRequest, `source must be "webhook", "email", or "step_job"`)
+		return
+	}
+	switch filters.Status {
+	case "":
+		filters.Status = db.DeadLetterPending
+	case "all":
+		filters.Status = ""
+	case db.DeadLetterPending, db.DeadLetterRetried, db.DeadLetterDiscarded:
+	default:
+		s.errorResponse(w, http.StatusBadRequest, `status must be "pending", "retried", "discarded", or "all"`)
+		return
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > maxDeadLetterLimit {
+			s.errorResponse(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxDeadLetterLimit))
+			return
+		}
+		filters.Limit = n
+	}
+
+	letters, err := s.db.ListDeadLetters(r.Context(), filters)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, letters)
+}
+
+// handleDeadLetterStats reports the number of pending dead letters by source
+func (s *Server) handleDeadLetterStats(w http.ResponseWriter, r *http.Request) {
+	if !s.callerIsAdmin(w, r, "view dead letters") {
+		return
+	}
+
+	pending, err := s.db.CountPendingDeadLetters(r.Context())
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	resp := DeadLetterStatsResponse{Pending: pending}
+	for _, n := range pending {
+		resp.Total += n
+	}
+	if s.deadLetters != nil {
+		resp.AlertThreshold = s.deadLetters.AlertThreshold
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
+
+// handleRetryDeadLetter retries a pending dead letter now. A retry that fails again
+// returns 502 and leaves the dead letter pending with one more attempt.
+func (s *Server) handleRetryDeadLetter(w http.ResponseWriter, r *http.Request) {
+	letter, ok := s.pendingDeadLetter(w, r, "retry dead letters")
+	if !ok {
+		return
+	}
+
+	resolved, err := deadletter.Retry(r.Context(), s.db, s.notifier, letter)
+	if errors.Is(err, deadletter.ErrNotRetryable) {
+		s.errorResponse(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		s.errorResponse(w, http.StatusBadGateway, "Retry failed: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, resolved)
+}
+
+// handleDiscardDeadLetter gives up on a pending dead letter
+func (s *Server) handleDiscardDeadLetter(w http.ResponseWriter, r *http.Request) {
+	letter, ok := s.pendingDeadLetter(w, r, "discard dead letters")
+	if !ok {
+		return
+	}
+
+	resolved, err := s.db.ResolveDeadLetter(r.Context(), letter.ID, db.DeadLetterDiscarded)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if resolved == nil {
+		s.errorResponse(w, http.StatusConflict, "Dead letter was already resolved")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, resolved)
+}
+
+// pendingDeadLetter resolves the {dead_letter_id} path value to a pending dead letter
+// for an admin caller, writing an error response otherwise
+func (s *Server) pendingDeadLetter(w http.ResponseWriter, r *http.Request, action string) (*db.DeadLetter, bool) {
+	if !s.callerIsAdmin(w, r, action) {
+		return nil, false
+	}
+	id, err := uuid.Parse(r.PathValue("dead_letter_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid dead letter ID format")
+		return nil, false
+	}
+
+	letter, err := s.db.GetDeadLetter(r.Context(), id)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return nil, false
+	}
+	if letter == nil {
+		s.errorResponse(w, http.StatusNotFound, "Dead letter not found")
+		return nil, false
+	}
+	if letter.Status != db.DeadLetterPending {
+		s.errorResponse(w, http.StatusConflict, "Dead letter was already "+letter.Status)
+		return nil, false
+	}
+	return letter, true
+}
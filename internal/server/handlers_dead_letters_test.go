@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/deadletter"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDeadLetterTestServer(t *testing.T, admin uuid.UUID) *testServer {
+	t.Helper()
+	s := newNotificationTestServer(t)
+	s.admins = &config.AdminConfig{UserIDs: map[uuid.UUID]bool{admin: true}}
+	s.deadLetters = &config.DeadLetterConfig{AlertThreshold: 2}
+	s.dlqAlarm = deadletter.NewAlarm(s.deadLetters.AlertThreshold)
+	return s
+}
+
+func TestHandleListDeadLetters(t *testing.T) {
+	admin := uuid.New()
+	s := newDeadLetterTestServer(t, admin)
+	_, err := s.mock.RecordDeadLetter(context.Background(), &db.DeadLetterInput{
+		Source: db.DeadLetterWebhook, RefKey: "run_completed:1", ErrorMessage: "status 502"})
+	require.NoError(t, err)
+	discarded, err := s.mock.RecordDeadLetter(context.Background(), &db.DeadLetterInput{
+		Source: db.DeadLetterStepJob, RefKey: uuid.NewString(), ErrorMessage: "boom"})
+	require.NoError(t, err)
+	discarded.Status = db.DeadLetterDiscarded
+
+	list := func(caller uuid.UUID, query string) *http.Response {
+		w := serveAuthed(t, s, "GET /v1/admin/dead-letters", s.handleListDeadLetters,
+			bearerRequest(t, s, http.MethodGet, "/v1/admin/dead-letters"+query, caller, nil))
+		return w.Result()
+	}
+
+	t.Run("pending by default", func(t *testing.T) {
+		resp := list(admin, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var letters []db.DeadLetter
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&letters))
+		require.Len(t, letters, 1)
+		assert.Equal(t, "run_completed:1", letters[0].RefKey)
+	})
+
+	t.Run("all statuses", func(t *testing.T) {
+		resp := list(admin, "?status=all")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var letters []db.DeadLetter
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&letters))
+		assert.Len(t, letters, 2)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		for _, query := range []string{"?source=sms", "?status=open", "?limit=0", "?limit=5000"} {
+			assert.Equal(t, http.StatusBadRequest, list(admin, query).StatusCode, query)
+		}
+	})
+
+	t.Run("non-admin forbidden", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, list(uuid.New(), "").StatusCode)
+	})
+}
+
+func TestHandleDeadLetterStats(t *testing.T) {
+	admin := uuid.New()
+	s := newDeadLetterTestServer(t, admin)
+	for _, source := range []string{db.DeadLetterWebhook, db.DeadLetterEmail, db.DeadLetterWebhook} {
+		_, err := s.mock.RecordDeadLetter(context.Background(), &db.DeadLetterInput{Source: source, RefKey: uuid.NewString()})
+		require.NoError(t, err)
+	}
+
+	w := serveAuthed(t, s, "GET /v1/admin/dead-letters/stats", s.handleDeadLetterStats,
+		bearerRequest(t, s, http.MethodGet, "/v1/admin/dead-letters/stats", admin, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp DeadLetterStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.Total)
+	assert.Equal(t, 2, resp.Pending[db.DeadLetterWebhook])
+	assert.Equal(t, 0, resp.Pending[db.DeadLetterStepJob])
+	assert.Equal(t, 2, resp.AlertThreshold)
+}
+
+func TestHandleRetryDeadLetter_Notification(t *testing.T) {
+	admin := uuid.New()
+	user := uuid.New()
+	s := newDeadLetterTestServer(t, admin)
+	rec := &webhookRecorder{}
+	url := rec.server(t).URL
+	s.mock.notifyPrefs = []db.NotificationPreference{
+		{UserID: user, EventType: db.NotificationRunCompleted, Channel: db.NotificationWebhook, WebhookURL: &url},
+	}
+
+	// The original delivery went to a webhook the user has since replaced
+	stale := "https://stale.example.com/hook"
+	msg := notifications.Message{Event: db.NotificationRunCompleted, Subject: "Resume ready"}
+	input := deadletter.Notification(&db.NotificationRecipient{UserID: user, Channel: db.NotificationWebhook, WebhookURL: &stale},
+		"run_completed:1", nil, msg, errors.New("status 502"))
+	letter, err := s.mock.RecordDeadLetter(context.Background(), input)
+	require.NoError(t, err)
+
+	target := "/v1/admin/dead-letters/" + letter.ID.String() + "/retry"
+	w := serveAuthed(t, s, "POST /v1/admin/dead-letters/{dead_letter_id}/retry", s.handleRetryDeadLetter,
+		bearerRequest(t, s, http.MethodPost, target, admin, nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	require.Len(t, rec.messages, 1, "retries go to the user's current webhook")
+	assert.Equal(t, "Resume ready", rec.messages[0].Subject)
+	assert.Equal(t, db.DeadLetterRetried, letter.Status)
+
+	w = serveAuthed(t, s, "POST /v1/admin/dead-letters/{dead_letter_id}/retry", s.handleRetryDeadLetter,
+		bearerRequest(t, s, http.MethodPost, target, admin, nil))
+	assert.Equal(t, http.StatusConflict, w.Code, "resolved dead letters cannot be retried again")
+}
+
+func TestHandleRetryDeadLetter_StepJob(t *testing.T) {
+	admin := uuid.New()
+	s := newDeadLetterTestServer(t, admin)
+	requeued := uuid.New()
+	s.mock.requeuedJobs = map[uuid.UUID]bool{requeued: true}
+
+	retry := func(jobID uuid.UUID) (*db.DeadLetter, int) {
+		letter, err := s.mock.RecordDeadLetter(context.Background(), &db.DeadLetterInput{
+			Source: db.DeadLetterStepJob, RefKey: jobID.String(), ErrorMessage: "boom"})
+		require.NoError(t, err)
+		target := "/v1/admin/dead-letters/" + letter.ID.String() + "/retry"
+		w := serveAuthed(t, s, "POST /v1/admin/dead-letters/{dead_letter_id}/retry", s.handleRetryDeadLetter,
+			bearerRequest(t, s, http.MethodPost, target, admin, nil))
+		return letter, w.Code
+	}
+
+	letter, code := retry(requeued)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, db.DeadLetterRetried, letter.Status)
+
+	letter, code = retry(uuid.New())
+	assert.Equal(t, http.StatusConflict, code, "jobs that are no longer failed are not requeued")
+	assert.Equal(t, db.DeadLetterPending, letter.Status)
+}
+
+func TestHandleDiscardDeadLetter(t *testing.T) {
+	admin := uuid.New()
+	s := newDeadLetterTestServer(t, admin)
+	letter, err := s.mock.RecordDeadLetter(context.Background(), &db.DeadLetterInput{
+		Source: db.DeadLetterEmail, RefKey: "weekly_digest:1", ErrorMessage: "smtp timeout"})
+	require.NoError(t, err)
+
+	discard := func(caller uuid.UUID, id string) int {
+		w := serveAuthed(t, s, "POST /v1/admin/dead-letters/{dead_letter_id}/discard", s.handleDiscardDeadLetter,
+			bearerRequest(t, s, http.MethodPost, "/v1/admin/dead-letters/"+id+"/discard", caller, nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, discard(uuid.New(), letter.ID.String()))
+	assert.Equal(t, http.StatusBadRequest, discard(admin, "not-a-uuid"))
+	assert.Equal(t, http.StatusNotFound, discard(admin, uuid.NewString()))
+	assert.Equal(t, http.StatusOK, discard(admin, letter.ID.String()))
+	assert.Equal(t, db.DeadLetterDiscarded, letter.Status)
+	assert.Equal(t, http.StatusConflict, discard(admin, letter.ID.String()))
+}
+
+func TestCheckDeadLetters(t *testing.T) {
+	s := newDeadLetterTestServer(t, uuid.New())
+	rec := &webhookRecorder{}
+	s.deadLetters.AlertWebhookURL = rec.server(t).URL
+
+	record := func() {
+		_, err := s.mock.RecordDeadLetter(context.Background(), &db.DeadLetterInput{Source: db.DeadLetterWebhook, RefKey: uuid.NewString()})
+		require.NoError(t, err)
+	}
+
+	record()
+	s.checkDeadLetters(context.Background())
+	assert.Empty(t, rec.messages, "below the threshold")
+
+	record()
+	s.checkDeadLetters(context.Background())
+	record()
+	s.checkDeadLetters(context.Background())
+	require.Len(t, rec.messages, 1, "alerts once when the threshold is crossed")
+	assert.Contains(t, rec.messages[0].Subject, "2 failed")
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// debugCleanupInterval is how often expired debug artifacts are purged
+const debugCleanupInterval = time.Hour
+
+// authorizeDebug checks that the caller may see raw LLM traffic for a run owned by ownerID.
+// Only the run owner or a configured admin is allowed.
+func (s *Server) authorizeDebug(r *http.Request, ownerID *uuid.UUID) error {
+	callerID, err := s.bearerUserID(r)
+	if err != nil {
+		return err
+	}
+	if s.isAdmin(callerID) {
+		return nil
+	}
+	if ownerID != nil && *ownerID == callerID {
+		return nil
+	}
+	return &ErrForbidden{Action: "debug mode is limited to the run owner or an admin"}
+}
+
+// authorizeDebugArtifact checks access to a debug artifact belonging to runID
+func (s *Server) authorizeDebugArtifact(r *http.Request, runID uuid.UUID) error {
+	run, err := s.db.GetRun(r.Context(), runID)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		return s.authorizeDebug(r, nil)
+	}
+	return s.authorizeDebug(r, run.UserID)
+}
+
+// runDebugArtifactCleanup periodically deletes debug artifacts past their retention window
+func (s *Server) runDebugArtifactCleanup(ctx context.Context) {
+	if s.debugConfig == nil {
+		return
+	}
+
+	ticker := time.NewTicker(debugCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		s.cleanupDebugArtifacts(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cleanupDebugArtifacts performs a single retention pass
+func (s *Server) cleanupDebugArtifacts(ctx context.Context) {
+	deleted, err := s.db.DeleteExpiredDebugArtifacts(ctx, s.debugConfig.Retention())
+	if err != nil {
+		slog.WarnContext(ctx, "debug artifact cleanup failed", "error", err)
+		return
+	}
+	if deleted > 0 {
+		slog.InfoContext(ctx, "Deleted expired "+db.CategoryDebug+" artifacts", "artifacts", deleted)
+	}
+}
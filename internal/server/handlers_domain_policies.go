@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/server/middleware"
+)
+
+// DomainPolicyRequest is the request body for creating or replacing a domain crawl policy
+type DomainPolicyRequest struct {
+	Domain string `json:"domain"`
+	Action string `json:"action"` // block or allow
+	Reason string `json:"reason,omitempty"`
+}
+
+// DomainPolicyListResponse is the response for listing domain policies
+type DomainPolicyListResponse struct {
+	Policies []db.DomainPolicy `json:"policies"`
+	Count    int               `json:"count"`
+}
+
+// policyOwner resolves whose domain policies a request manages: the path user
+// (who must be the caller), or nil for global policies (callers must be admins)
+func (s *Server) policyOwner(w http.ResponseWriter, r *http.Request, write bool) (*uuid.UUID, bool) {
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		if write && !s.isAdmin(callerID) {
+			s.errorResponse(w, http.StatusForbidden, "Only admins can manage global domain policies")
+			return nil, false
+		}
+		return nil, true
+	}
+
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return nil, false
+	}
+	if callerID != userID {
+		s.errorResponse(w, http.StatusForbidden, "You can only manage your own domain policies")
+		return nil, false
+	}
+	return &userID, true
+}
+
+// handleListDomainPolicies lists global domain policies, plus the user's own under /v1/users/{id}
+func (s *Server) handleListDomainPolicies(w http.ResponseWriter, r *http.Request) {
+	owner, ok := s.policyOwner(w, r, false)
+	if !ok {
+		return
+	}
+
+	policies, err := s.db.ListDomainPolicies(r.Context(), owner)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if policies == nil {
+		policies = []db.DomainPolicy{}
+	}
+
+	s.jsonResponse(w, http.StatusOK, DomainPolicyListResponse{Policies: policies, Count: len(policies)})
+}
+
+// handleCreateDomainPolicy creates or replaces a domain policy for the user or, for admins, globally
+func (s *Server) handleCreateDomainPolicy(w http.ResponseWriter, r *http.Request) {
+	owner, ok := s.policyOwner(w, r, true)
+	if !ok {
+		return
+	}
+
+	var req DomainPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Action != db.DomainPolicyBlock && req.Action != db.DomainPolicyAllow {
+		s.errorResponse(w, http.StatusBadRequest, "action must be one of: block, allow")
+		return
+	}
+	if _, err := db.NormalizePolicyDomain(req.Domain); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	policy, err := s.db.UpsertDomainPolicy(r.Context(), &db.DomainPolicyInput{
+		UserID: owner,
+		Domain: req.Domain,
+		Action: req.Action,
+		Reason: req.Reason,
+	})
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	s.jsonResponse(w, http.StatusCreated, policy)
+}
+
+// handleDeleteDomainPolicy deletes one of the user's domain policies or, for admins, a global one
+func (s *Server) handleDeleteDomainPolicy(w http.ResponseWriter, r *http.Request) {
+	owner, ok := s.policyOwner(w, r, true)
+	if !ok {
+		return
+	}
+
+	policyID, err := uuid.Parse(r.PathValue("policy_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid policy ID")
+		return
+	}
+
+	if err := s.db.DeleteDomainPolicy(r.Context(), policyID, owner); err != nil {
+		if strings.HasPrefix(err.Error(), "domain policy not found") {
+			s.errorResponse(w, http.StatusNotFound, "Domain policy not found")
+			return
+		}
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
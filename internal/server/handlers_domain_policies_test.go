@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestHandleCreateDomainPolicy_User(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	target := "/v1/users/" + user.String() + "/domain-policies"
+	body := []byte(`{"domain":"https://www.Glassdoor.com/Reviews","action":"block","reason":"aggregator"}`)
+
+	w := serveAuthed(t, s, "POST /v1/users/{id}/domain-policies", s.handleCreateDomainPolicy,
+		bearerRequest(t, s, http.MethodPost, target, user, body))
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var policy db.DomainPolicy
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &policy))
+	assert.Equal(t, "glassdoor.com", policy.Domain)
+	assert.Equal(t, db.DomainPolicyBlock, policy.Action)
+	require.NotNil(t, policy.UserID)
+	assert.Equal(t, user, *policy.UserID)
+
+	// Another user cannot write to this user's policies
+	w = serveAuthed(t, s, "POST /v1/users/{id}/domain-policies", s.handleCreateDomainPolicy,
+		bearerRequest(t, s, http.MethodPost, target, uuid.New(), body))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestHandleCreateDomainPolicy_Invalid(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	target := "/v1/users/" + user.String() + "/domain-policies"
+
+	for _, body := range []string{
+		`{"domain":"glassdoor.com","action":"ignore"}`,
+		`{"domain":"localhost","action":"block"}`,
+		`not json`,
+	} {
+		w := serveAuthed(t, s, "POST /v1/users/{id}/domain-policies", s.handleCreateDomainPolicy,
+			bearerRequest(t, s, http.MethodPost, target, user, []byte(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestHandleCreateDomainPolicy_GlobalRequiresAdmin(t *testing.T) {
+	admin := uuid.New()
+	s := newAuthTestServer(t, admin)
+	body := []byte(`{"domain":"indeed.com","action":"block"}`)
+
+	w := serveAuthed(t, s, "POST /v1/domain-policies", s.handleCreateDomainPolicy,
+		bearerRequest(t, s, http.MethodPost, "/v1/domain-policies", uuid.New(), body))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serveAuthed(t, s, "POST /v1/domain-policies", s.handleCreateDomainPolicy,
+		bearerRequest(t, s, http.MethodPost, "/v1/domain-policies", admin, body))
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, s.mock.domainPolicies, 1)
+	assert.Nil(t, s.mock.domainPolicies[0].UserID)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/domain-policies", nil)
+	w = serveAuthed(t, s, "POST /v1/domain-policies", s.handleCreateDomainPolicy, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandleListDomainPolicies(t *testing.T) {
+	user := uuid.New()
+	other := uuid.New()
+	s := newAuthTestServer(t)
+	s.mock.domainPolicies = []db.DomainPolicy{
+		{ID: uuid.New(), Domain: "indeed.com", Action: db.DomainPolicyBlock},
+		{ID: uuid.New(), UserID: &user, Domain: "acme.dev", Action: db.DomainPolicyAllow},
+		{ID: uuid.New(), UserID: &other, Domain: "medium.com", Action: db.DomainPolicyAllow},
+	}
+
+	w := serveAuthed(t, s, "GET /v1/users/{id}/domain-policies", s.handleListDomainPolicies,
+		bearerRequest(t, s, http.MethodGet, "/v1/users/"+user.String()+"/domain-policies", user, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp DomainPolicyListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Count, "global policies plus the user's own")
+
+	w = serveAuthed(t, s, "GET /v1/domain-policies", s.handleListDomainPolicies,
+		bearerRequest(t, s, http.MethodGet, "/v1/domain-policies", user, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Count, "only global policies")
+}
+
+func TestHandleDeleteDomainPolicy(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	global := db.DomainPolicy{ID: uuid.New(), Domain: "indeed.com", Action: db.DomainPolicyBlock}
+	own := db.DomainPolicy{ID: uuid.New(), UserID: &user, Domain: "acme.dev", Action: db.DomainPolicyAllow}
+	s.mock.domainPolicies = []db.DomainPolicy{global, own}
+	pattern := "DELETE /v1/users/{id}/domain-policies/{policy_id}"
+	base := "/v1/users/" + user.String() + "/domain-policies/"
+
+	// A user cannot delete a global policy through their own scope
+	w := serveAuthed(t, s, pattern, s.handleDeleteDomainPolicy,
+		bearerRequest(t, s, http.MethodDelete, base+global.ID.String(), user, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveAuthed(t, s, pattern, s.handleDeleteDomainPolicy,
+		bearerRequest(t, s, http.MethodDelete, base+own.ID.String(), user, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, s.mock.domainPolicies, 1)
+
+	w = serveAuthed(t, s, pattern, s.handleDeleteDomainPolicy,
+		bearerRequest(t, s, http.MethodDelete, base+"not-a-uuid", user, nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/pipeline"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// handleRunDownload serves a run's resume in the format named by ?format: pdf (the
+// default), docx, or tex
+func (s *Server) handleRunDownload(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Query().Get("format") {
+	case "", rendering.OutputFormatPDF:
+		s.handleRunPDF(w, r)
+	case "tex":
+		s.handleRunResumeTex(w, r)
+	case rendering.OutputFormatDOCX:
+		s.handleRunDOCX(w, r)
+	default:
+		s.errorResponse(w, http.StatusBadRequest, `format must be "pdf", "docx", or "tex"`)
+	}
+}
+
+// handleRunDOCX serves a run's resume as a Word document
+func (s *Server) handleRunDOCX(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID format")
+		return
+	}
+
+	docx, err := s.loadResumeDOCX(r, runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if docx == nil {
+		s.errorResponse(w, http.StatusNotFound, "resume.docx not found for this run")
+		return
+	}
+	w.Header().Set("Content-Type", rendering.DOCXContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="resume.docx"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(docx)
+}
+
+// loadResumeDOCX returns a run's resume_docx artifact. Runs that did not ask for one
+// have it rendered from their final plan and bullets, with the owner's contact details
+// and default styling, and the result stored. Returns nil if the run has no resume.
+func (s *Server) loadResumeDOCX(r *http.Request, runID uuid.UUID) ([]byte, error) {
+	ctx := r.Context()
+	docx, err := s.db.GetBinaryArtifact(ctx, runID, db.StepResumeDOCX)
+	if err != nil || len(docx) > 0 {
+		return docx, err
+	}
+
+	var plan types.ResumePlan
+	var bullets types.RewrittenBullets
+	var bank types.ExperienceBank
+	for _, artifact := range []struct {
+		step string
+		v    any
+	}{
+		{db.StepResumePlan, &plan},
+		{db.StepRewrittenBullets, &bullets},
+		{db.StepExperienceBank, &bank},
+	} {
+		found, err := s.loadArtifact(r, runID, artifact.step, artifact.v)
+		if err != nil || !found {
+			return nil, err
+		}
+	}
+	education := bank.Education
+	var scores []ranking.EducationScore
+	if found, err := s.loadArtifact(r, runID, db.StepEducationScores, &scores); err != nil {
+		return nil, err
+	} else if found {
+		education = pipeline.IncludedEducation(&bank, scores)
+	}
+
+	var name, email, phone string
+	run, err := s.db.GetRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run != nil && run.UserID != nil {
+		user, err := s.db.GetUser(ctx, *run.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil {
+			name, email, phone = user.Name, user.Email, user.Phone
+		}
+	}
+
+	docx, err = rendering.RenderDOCX(&plan, &bullets, nil, name, email, phone, &bank, education)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.SaveBinaryArtifact(ctx, runID, db.StepResumeDOCX, db.CategoryValidation, docx); err != nil {
+		slog.WarnContext(ctx, "failed to save resume DOCX", logging.KeyRunID, runID, "error", err)
+	}
+	return docx, nil
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func serveRunDownload(s *testServer, runID, format string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/"+runID+"/download?format="+format, nil)
+	req.SetPathValue("id", runID)
+	w := httptest.NewRecorder()
+	s.handleRunDownload(w, req)
+	return w
+}
+
+func TestHandleRunDownload_Formats(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.binArtifacts = map[string][]byte{
+		runID.String() + ":" + db.StepResumePDF:  []byte("%PDF-1.5 stored"),
+		runID.String() + ":" + db.StepResumeDOCX: []byte("PK stored"),
+	}
+	s.mock.textArtifacts[runID.String()+":"+db.StepResumeTex] = `\documentclass{article}`
+
+	w := serveRunDownload(s, runID.String(), "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+
+	w = serveRunDownload(s, runID.String(), "tex")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `\documentclass{article}`, w.Body.String())
+
+	w = serveRunDownload(s, runID.String(), "docx")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, rendering.DOCXContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "resume.docx")
+	assert.Equal(t, "PK stored", w.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, serveRunDownload(s, runID.String(), "rtf").Code)
+	assert.Equal(t, http.StatusBadRequest, serveRunDownload(s, "not-a-uuid", "docx").Code)
+	assert.Equal(t, http.StatusNotFound, serveRunDownload(s, uuid.New().String(), "docx").Code)
+}
+
+func TestHandleRunDownload_RendersDOCX(t *testing.T) {
+	s := newTestServer()
+	runID, owner := uuid.New(), uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, UserID: &owner}
+	if s.mock.users == nil {
+		s.mock.users = make(map[uuid.UUID]*db.User)
+	}
+	s.mock.users[owner] = &db.User{ID: owner, Name: "Jane Doe", Email: "jane@example.com"}
+	for step, content := range map[string]any{
+		db.StepResumePlan: types.ResumePlan{SelectedStories: []types.SelectedStory{
+			{StoryID: "story_001", BulletIDs: []string{"bullet_001"}},
+		}},
+		db.StepRewrittenBullets: types.RewrittenBullets{Bullets: []types.RewrittenBullet{
+			{OriginalBulletID: "bullet_001", FinalText: "Shipped the payments ledger"},
+		}},
+		db.StepExperienceBank: types.ExperienceBank{
+			Stories:   []types.Story{{ID: "story_001", Company: "Acme", Role: "Engineer"}},
+			Education: []types.Education{{ID: "bs", School: "State University"}, {ID: "ms", School: "Tech Institute"}},
+		},
+		db.StepEducationScores: []ranking.EducationScore{{EducationID: "ms", Included: true}},
+	} {
+		s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: step, Content: content}
+	}
+
+	w := serveRunDownload(s, runID.String(), "docx")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, w.Body.Bytes(), s.mock.binArtifacts[runID.String()+":"+db.StepResumeDOCX], "rendered copy is stored")
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	require.NoError(t, err)
+	f, err := zr.Open("word/document.xml")
+	require.NoError(t, err)
+	doc, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Contains(t, string(doc), "Jane Doe")
+	assert.Contains(t, string(doc), "Shipped the payments ledger")
+	assert.Contains(t, string(doc), "Tech Institute")
+	assert.NotContains(t, string(doc), "State University", "education the run left out stays out")
+}
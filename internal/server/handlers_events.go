@@ -0,0 +1,121 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// eventKeepaliveInterval keeps idle event streams open through proxies
+const eventKeepaliveInterval = 15 * time.Second
+
+// RunEventsSnapshot is the first event on a run event stream
+type RunEventsSnapshot struct {
+	RunID  string               `json:"run_id"`
+	Status string               `json:"status"`
+	Steps  []StepStatusResponse `json:"steps"`
+}
+
+// handleRunEvents streams step and artifact changes for a run as Server-Sent Events.
+// Events come from the Postgres event bus, so the stream follows runs executing on
+// any replica. The stream opens with a snapshot and ends when the run completes.
+func (s *Server) handleRunEvents(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID format")
+		return
+	}
+	if s.events == nil {
+		s.errorResponse(w, http.StatusServiceUnavailable, "Event streaming is unavailable")
+		return
+	}
+
+	run, err := s.db.GetRun(r.Context(), runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if run == nil {
+		s.errorResponse(w, http.StatusNotFound, "Run not found")
+		return
+	}
+
+	// Subscribe before reading the snapshot so no change falls between the two
+	sub := s.events.Subscribe(runID)
+	defer sub.Close()
+
+	stepList, err := s.db.ListRunSteps(r.Context(), runID, nil, nil)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	sse, err := NewSSEWriter(w)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	snapshot := RunEventsSnapshot{RunID: runID.String(), Status: run.Status, Steps: make([]StepStatusResponse, 0, len(stepList))}
+	for _, step := range stepList {
+		snapshot.Steps = append(snapshot.Steps, toStepStatusResponse(step))
+	}
+	if err := sse.WriteEvent("snapshot", snapshot); err != nil {
+		return
+	}
+	if run.Status != "running" && run.Status != "queued" {
+		sse.WriteComplete(runID.String(), run.Status)
+		return
+	}
+
+	keepalive := time.NewTicker(eventKeepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			if err := sse.WriteComment("keepalive"); err != nil {
+				return
+			}
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if err := sse.WriteEvent(event.Type, event); err != nil {
+				return
+			}
+			if event.Terminal() {
+				sse.WriteComplete(runID.String(), event.Status)
+				return
+			}
+		}
+	}
+}
+
+// toStepStatusResponse converts a run step to its API representation
+func toStepStatusResponse(step db.RunStep) StepStatusResponse {
+	resp := StepStatusResponse{
+		Step:       step.Step,
+		Status:     step.Status,
+		RunID:      step.RunID.String(),
+		DurationMs: step.DurationMs,
+		Error:      step.ErrorMessage,
+	}
+	if step.StartedAt != nil {
+		started := formatTimestamp(*step.StartedAt)
+		resp.StartedAt = &started
+	}
+	if step.CompletedAt != nil {
+		completed := formatTimestamp(*step.CompletedAt)
+		resp.CompletedAt = &completed
+	}
+	if step.ArtifactID != nil {
+		artifactID := step.ArtifactID.String()
+		resp.ArtifactID = &artifactID
+	}
+	return resp
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/events"
+)
+
+func TestHandleRunEvents_StreamsUntilComplete(t *testing.T) {
+	s := newTestServer()
+	s.events = events.NewBus(nil)
+
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Status: "running"}
+	s.mock.runSteps[runID] = []db.RunStep{{RunID: runID, Step: "parse_job", Status: db.StepStatusCompleted}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/runs/{id}/events", s.handleRunEvents)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/runs/" + runID.String() + "/events")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	nextEvent := func() (string, string) {
+		t.Helper()
+		var name string
+		for {
+			select {
+			case line, ok := <-lines:
+				require.True(t, ok, "stream closed early")
+				if strings.HasPrefix(line, "event: ") {
+					name = strings.TrimPrefix(line, "event: ")
+				}
+				if strings.HasPrefix(line, "data: ") {
+					return name, strings.TrimPrefix(line, "data: ")
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("timed out waiting for event")
+			}
+		}
+	}
+
+	name, data := nextEvent()
+	assert.Equal(t, "snapshot", name)
+	assert.Contains(t, data, `"parse_job"`)
+
+	// Events for other runs are not delivered
+	s.events.Deliver(db.RunEvent{Type: db.RunEventStepStatus, RunID: uuid.New(), Step: "other"})
+	s.events.Deliver(db.RunEvent{Type: db.RunEventStepStatus, RunID: runID, Step: "rank_stories", Status: db.StepStatusInProgress})
+	name, data = nextEvent()
+	assert.Equal(t, db.RunEventStepStatus, name)
+	assert.Contains(t, data, `"rank_stories"`)
+
+	s.events.Deliver(db.RunEvent{Type: db.RunEventRunCompleted, RunID: runID, Status: "completed"})
+	name, _ = nextEvent()
+	assert.Equal(t, db.RunEventRunCompleted, name)
+	name, data = nextEvent()
+	assert.Equal(t, "complete", name)
+	assert.Contains(t, data, `"completed"`)
+}
+
+func TestHandleRunEvents_FinishedRun(t *testing.T) {
+	s := newTestServer()
+	s.events = events.NewBus(nil)
+
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Status: "completed"}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/"+runID.String()+"/events", nil)
+	req.SetPathValue("id", runID.String())
+	w := httptest.NewRecorder()
+	s.handleRunEvents(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "event: snapshot")
+	assert.Contains(t, w.Body.String(), "event: complete")
+}
+
+func TestHandleRunEvents_Errors(t *testing.T) {
+	s := newTestServer()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/x/events", nil)
+	req.SetPathValue("id", "not-a-uuid")
+	w := httptest.NewRecorder()
+	s.handleRunEvents(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	runID := uuid.New().String()
+	req.SetPathValue("id", runID)
+	w = httptest.NewRecorder()
+	s.handleRunEvents(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	s.events = events.NewBus(nil)
+	w = httptest.NewRecorder()
+	s.handleRunEvents(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
 )
 
 // handleListStories lists all stories for a user
@@ -15,15 +16,24 @@ func (s *Server) handleListStories(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stories, err := s.db.ListStoriesByUser(r.Context(), userID)
+	page, ok := s.parseListPage(w, r, storyListSpec)
+	if !ok {
+		return
+	}
+
+	stories, err := s.db.ListStoriesByUser(r.Context(), userID, page.Limit, page.Page)
 	if err != nil {
 		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
 		return
 	}
+	if stories == nil {
+		stories = []db.Story{}
+	}
 
 	s.jsonResponse(w, http.StatusOK, map[string]any{
-		"stories": stories,
-		"count":   len(stories),
+		"stories":     stories,
+		"count":       len(stories),
+		"next_cursor": nextCursor(page, stories, storySortKey),
 	})
 }
 
@@ -117,15 +127,24 @@ func (s *Server) handleListSkills(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	skills, err := s.db.ListSkillsByUserID(r.Context(), userID)
+	page, ok := s.parseListPage(w, r, skillListSpec)
+	if !ok {
+		return
+	}
+
+	skills, err := s.db.ListSkillsByUserID(r.Context(), userID, page.Limit, page.Page)
 	if err != nil {
 		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
 		return
 	}
+	if skills == nil {
+		skills = []db.Skill{}
+	}
 
 	s.jsonResponse(w, http.StatusOK, map[string]any{
-		"skills": skills,
-		"count":  len(skills),
+		"skills":      skills,
+		"count":       len(skills),
+		"next_cursor": nextCursor(page, skills, skillSortKey),
 	})
 }
 
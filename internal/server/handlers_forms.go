@@ -0,0 +1,441 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// The /forms endpoints are plain-HTTP counterparts of the JSON API for browsers without
+// JavaScript and for curl scripts. They take form-encoded bodies and answer with 303
+// redirects. Browsers sign in with a session cookie and every form they post carries a
+// CSRF token bound to it; scripts send a bearer token from POST /forms/token instead,
+// which needs no CSRF token because browsers never attach it on their own.
+
+const (
+	// sessionCookieName holds the JWT for browsers signed in through /forms/login
+	sessionCookieName = "rc_session"
+	// maxFormBytes bounds form bodies, which may carry a pasted job posting
+	maxFormBytes = 1 << 20
+	// formsRefreshSeconds is how often the run page reloads while the run is in progress
+	formsRefreshSeconds = 5
+)
+
+// formsCSP allows the pages' inline styles and forms that post back to this server
+const formsCSP = "default-src 'none'; style-src 'unsafe-inline'; base-uri 'none'; form-action 'self'; frame-ancestors 'none'"
+
+// formsPages holds the login, new run, and run status pages
+var formsPages = template.Must(template.New("forms").Parse(`{{define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+{{if .Refresh}}<meta http-equiv="refresh" content="{{.Refresh}}">{{end}}
+<title>{{.Title}}</title>
+<style>
+body { max-width: 640px; margin: 2rem auto; padding: 0 1rem; font-family: system-ui, sans-serif; color: #222; line-height: 1.4; }
+label { display: block; margin-top: 0.75rem; font-weight: 600; }
+input, textarea { width: 100%; box-sizing: border-box; font: inherit; padding: 0.3rem; }
+textarea { min-height: 12rem; }
+button { margin-top: 1rem; font: inherit; padding: 0.3rem 1rem; }
+.error { color: #b3261e; }
+.meta { color: #666; font-size: 0.85rem; }
+form.inline { display: inline; }
+</style>
+</head>
+<body>
+{{end}}
+
+{{define "foot"}}</body>
+</html>
+{{end}}
+
+{{define "login"}}{{template "head" .}}<h1>Sign in</h1>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<form method="post" action="/forms/login">
+<label for="email">Email</label>
+<input id="email" name="email" type="email" autocomplete="username" required autofocus>
+<label for="password">Password</label>
+<input id="password" name="password" type="password" autocomplete="current-password" required>
+<button type="submit">Sign in</button>
+</form>
+{{template "foot" .}}{{end}}
+
+{{define "new_run"}}{{template "head" .}}<h1>New resume</h1>
+<form method="post" action="/forms/runs">
+<input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+<label for="job_url">Job posting URL</label>
+<input id="job_url" name="job_url" type="url" autofocus>
+<label for="job_text">Or paste the posting</label>
+<textarea id="job_text" name="job_text"></textarea>
+<label for="template">Template</label>
+<input id="template" name="template" placeholder="templates/one_page_resume.tex">
+<label for="max_bullets">Max bullets</label>
+<input id="max_bullets" name="max_bullets" type="number" min="1" placeholder="25">
+<label for="max_lines">Max lines</label>
+<input id="max_lines" name="max_lines" type="number" min="1" placeholder="35">
+<label for="max_pages">Max pages</label>
+<input id="max_pages" name="max_pages" type="number" min="1" max="2" placeholder="1">
+<button type="submit">Create</button>
+</form>
+<form method="post" action="/forms/logout">
+<input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+<button type="submit">Sign out</button>
+</form>
+{{template "foot" .}}{{end}}
+
+{{define "run"}}{{template "head" .}}<h1>{{if .Run.RoleTitle}}{{.Run.RoleTitle}}{{if .Run.Company}} at {{.Run.Company}}{{end}}{{else}}Run{{end}}</h1>
+<p>Status: <strong>{{.Run.Status}}</strong></p>
+<p class="meta">Run {{.Run.ID}}, created {{.Run.CreatedAt.Format "2006-01-02 15:04 MST"}}{{if .Refresh}}. This page reloads every {{.Refresh}} seconds.{{end}}</p>
+{{if eq .Run.Status "completed"}}{{range .Artifacts}}<form class="inline" method="post" action="/forms/runs/{{$.Run.ID}}/download">
+<input type="hidden" name="csrf_token" value="{{$.CSRFToken}}">
+<button type="submit" name="artifact" value="{{.}}">Download {{.}}</button>
+</form>
+{{end}}{{end}}
+<p><a href="/forms/runs/new">New resume</a></p>
+{{template "foot" .}}{{end}}
+`))
+
+// formsPage is the data every forms page renders with
+type formsPage struct {
+	Title     string
+	Refresh   int // Seconds between reloads; zero never reloads
+	Error     string
+	CSRFToken string
+	Run       *db.Run
+	Artifacts []string
+}
+
+// formsArtifacts maps the download form's artifact names to the endpoints serving them
+var formsArtifacts = map[string]string{
+	"pdf":  "/artifacts/pdf",
+	"docx": "/download?format=docx",
+	"tex":  "/resume.tex",
+}
+
+// formCaller is who sent a forms request. Session is the session cookie's token, empty
+// for bearer tokens, whose requests need no CSRF token.
+type formCaller struct {
+	UserID  uuid.UUID
+	Session string
+}
+
+// formsCaller authenticates a forms request by its bearer token or, failing that, its
+// session cookie
+func (s *Server) formsCaller(r *http.Request) (formCaller, bool) {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		claims, err := s.jwtService.ValidateToken(token)
+		if err != nil {
+			return formCaller{}, false
+		}
+		return formCaller{UserID: claims.UserID}, true
+	}
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return formCaller{}, false
+	}
+	claims, err := s.jwtService.ValidateToken(cookie.Value)
+	if err != nil {
+		return formCaller{}, false
+	}
+	return formCaller{UserID: claims.UserID, Session: cookie.Value}, true
+}
+
+// validCSRF reports whether a form post may act for caller: posts authenticated by the
+// session cookie must carry its CSRF token
+func (s *Server) validCSRF(r *http.Request, caller formCaller) bool {
+	return caller.Session == "" || s.jwtService.ValidCSRFToken(caller.Session, r.PostFormValue("csrf_token"))
+}
+
+// parseForm reads a form-encoded or multipart body, writing the error response if it
+// cannot be read
+func parseForm(w http.ResponseWriter, r *http.Request) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxFormBytes)
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		err = r.ParseMultipartForm(maxFormBytes)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		http.Error(w, "Invalid form body: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// wantsHTML reports whether the client asked for a page rather than plain text. Browsers
+// list text/html in Accept; curl sends */*.
+func wantsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// renderFormsPage writes one of the forms pages. Pages carrying a CSRF token are not cached.
+func renderFormsPage(w http.ResponseWriter, status int, name string, page formsPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", formsCSP)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = formsPages.ExecuteTemplate(w, name, page)
+}
+
+// handleFormsLoginPage serves the sign-in form
+func (s *Server) handleFormsLoginPage(w http.ResponseWriter, r *http.Request) {
+	if caller, ok := s.formsCaller(r); ok && caller.Session != "" {
+		http.Redirect(w, r, "/forms/runs/new", http.StatusSeeOther)
+		return
+	}
+	page := formsPage{Title: "Sign in"}
+	if r.URL.Query().Get("error") != "" {
+		page.Error = "Invalid email or password."
+	}
+	renderFormsPage(w, http.StatusOK, "login", page)
+}
+
+// formsLogin checks the email and password posted to a forms sign-in endpoint and issues
+// an access token for the user
+func (s *Server) formsLogin(r *http.Request) (string, error) {
+	user, err := s.userService.Login(r.Context(), &types.LoginRequest{
+		Email:    r.PostFormValue("email"),
+		Password: r.PostFormValue("password"),
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.jwtService.GenerateToken(user.ID)
+}
+
+// handleFormsLogin signs a browser in: it sets the session cookie and redirects to the
+// new run form, or back to the sign-in form if the credentials are wrong
+func (s *Server) handleFormsLogin(w http.ResponseWriter, r *http.Request) {
+	if !parseForm(w, r) {
+		return
+	}
+	token, err := s.formsLogin(r)
+	if err != nil {
+		if status := HTTPStatus(err); status >= http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		http.Redirect(w, r, "/forms/login?error=invalid", http.StatusSeeOther)
+		return
+	}
+	setSessionCookie(w, r, token, int((time.Duration(s.jwtService.config.ExpirationHours) * time.Hour).Seconds()))
+	http.Redirect(w, r, "/forms/runs/new", http.StatusSeeOther)
+}
+
+// handleFormsToken returns an access token as plain text so scripts can sign in without
+// JSON tooling: TOKEN=$(curl -s -d email=... -d password=... .../forms/token)
+func (s *Server) handleFormsToken(w http.ResponseWriter, r *http.Request) {
+	if !parseForm(w, r) {
+		return
+	}
+	token, err := s.formsLogin(r)
+	if err != nil {
+		http.Error(w, err.Error(), HTTPStatus(err))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(token + "\n"))
+}
+
+// setSessionCookie sets the session cookie to token; a negative maxAge deletes it
+func setSessionCookie(w http.ResponseWriter, r *http.Request, token string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// handleFormsLogout clears the session cookie and redirects to the sign-in form
+func (s *Server) handleFormsLogout(w http.ResponseWriter, r *http.Request) {
+	if !parseForm(w, r) {
+		return
+	}
+	if caller, ok := s.formsCaller(r); ok && !s.validCSRF(r, caller) {
+		http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+		return
+	}
+	setSessionCookie(w, r, "", -1)
+	http.Redirect(w, r, "/forms/login", http.StatusSeeOther)
+}
+
+// handleFormsNewRunPage serves the new run form to a signed-in browser
+func (s *Server) handleFormsNewRunPage(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.formsCaller(r)
+	if !ok || caller.Session == "" {
+		http.Redirect(w, r, "/forms/login", http.StatusSeeOther)
+		return
+	}
+	renderFormsPage(w, http.StatusOK, "new_run", formsPage{
+		Title:     "New resume",
+		CSRFToken: s.jwtService.CSRFToken(caller.Session),
+	})
+}
+
+// handleFormsCreateRun queues a run from a form-encoded body, the form counterpart of
+// POST /v1/runs with "execute": true, and redirects to the run's page
+func (s *Server) handleFormsCreateRun(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.formsCaller(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !parseForm(w, r) {
+		return
+	}
+	if !s.validCSRF(r, caller) {
+		http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+		return
+	}
+	if err := s.checkEmailVerified(r.Context(), caller.UserID); err != nil {
+		http.Error(w, err.Error(), HTTPStatus(err))
+		return
+	}
+
+	req := RunCreateRequest{
+		UserID:   caller.UserID.String(),
+		JobURL:   strings.TrimSpace(r.PostFormValue("job_url")),
+		JobText:  strings.TrimSpace(r.PostFormValue("job_text")),
+		Template: strings.TrimSpace(r.PostFormValue("template")),
+		Execute:  true,
+	}
+	if req.JobURL == "" && req.JobText == "" {
+		http.Error(w, "Either job_url or job_text is required", http.StatusBadRequest)
+		return
+	}
+	for _, field := range []struct {
+		name string
+		dst  *int
+	}{{"max_bullets", &req.MaxBullets}, {"max_lines", &req.MaxLines}, {"max_pages", &req.MaxPages}} {
+		value := strings.TrimSpace(r.PostFormValue(field.name))
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			http.Error(w, field.name+" must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		*field.dst = n
+	}
+	if err := validateMaxPages(req.MaxPages); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.setDefaults()
+
+	runID, err := s.queueRun(r.Context(), caller.UserID, &req)
+	if err != nil {
+		http.Error(w, "Failed to queue run: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/forms/runs/"+runID.String(), http.StatusSeeOther)
+}
+
+// formsRun loads the caller's run named in the path, writing the error response if the
+// run does not exist or belongs to someone else
+func (s *Server) formsRun(w http.ResponseWriter, r *http.Request, caller formCaller) (*db.Run, bool) {
+	runID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid run ID format", http.StatusBadRequest)
+		return nil, false
+	}
+	run, err := s.db.GetRun(r.Context(), runID)
+	if err != nil {
+		http.Error(w, "Database error: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	if run == nil || run.UserID == nil || *run.UserID != caller.UserID {
+		http.Error(w, "Run not found", http.StatusNotFound)
+		return nil, false
+	}
+	return run, true
+}
+
+// handleFormsRunPage shows a run's status, with download buttons once it completes. The
+// page reloads itself while the run is in progress. Clients that do not ask for HTML get
+// plain "key: value" lines they can poll and grep.
+func (s *Server) handleFormsRunPage(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.formsCaller(r)
+	if !ok {
+		if wantsHTML(r) {
+			http.Redirect(w, r, "/forms/login", http.StatusSeeOther)
+			return
+		}
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	run, ok := s.formsRun(w, r, caller)
+	if !ok {
+		return
+	}
+
+	if !wantsHTML(r) {
+		var b strings.Builder
+		b.WriteString("run_id: " + run.ID.String() + "\n")
+		b.WriteString("status: " + run.Status + "\n")
+		if run.Status == "completed" {
+			for _, name := range []string{"pdf", "docx", "tex"} {
+				b.WriteString(name + ": /v1/runs/" + run.ID.String() + formsArtifacts[name] + "\n")
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(b.String()))
+		return
+	}
+
+	page := formsPage{Title: "Run " + run.ID.String(), Run: run, Artifacts: []string{"pdf", "docx", "tex"}}
+	if caller.Session != "" {
+		page.CSRFToken = s.jwtService.CSRFToken(caller.Session)
+	}
+	if run.Status == "queued" || run.Status == "running" {
+		page.Refresh = formsRefreshSeconds
+	}
+	renderFormsPage(w, http.StatusOK, "run", page)
+}
+
+// handleFormsDownload redirects to the artifact named by the form's artifact field, or
+// back to the run's page if the run has not completed
+func (s *Server) handleFormsDownload(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.formsCaller(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+	if !parseForm(w, r) {
+		return
+	}
+	if !s.validCSRF(r, caller) {
+		http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+		return
+	}
+	run, ok := s.formsRun(w, r, caller)
+	if !ok {
+		return
+	}
+	path, ok := formsArtifacts[r.PostFormValue("artifact")]
+	if !ok {
+		http.Error(w, `artifact must be "pdf", "docx", or "tex"`, http.StatusBadRequest)
+		return
+	}
+	if run.Status != "completed" {
+		http.Redirect(w, r, "/forms/runs/"+run.ID.String(), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/v1/runs/"+run.ID.String()+path, http.StatusSeeOther)
+}
@@ -0,0 +1,272 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// serveForms routes req to handler under pattern so path values are set
+func serveForms(pattern string, handler http.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.HandleFunc(pattern, handler)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+// formRequest builds a form-encoded request
+func formRequest(method, target string, form url.Values) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+// withSession signs req in as userID through the session cookie and returns the
+// session's CSRF token
+func withSession(t *testing.T, s *testServer, req *http.Request, userID uuid.UUID) string {
+	t.Helper()
+	token := mustToken(t, s, userID)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: token})
+	return s.jwtService.CSRFToken(token)
+}
+
+// sessionFormRequest builds a form post from a browser signed in as userID, carrying the
+// session's CSRF token
+func sessionFormRequest(t *testing.T, s *testServer, target string, userID uuid.UUID, form url.Values) *http.Request {
+	t.Helper()
+	token := mustToken(t, s, userID)
+	form.Set("csrf_token", s.jwtService.CSRFToken(token))
+	req := formRequest(http.MethodPost, target, form)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: token})
+	return req
+}
+
+// mustToken issues an access token for userID
+func mustToken(t *testing.T, s *testServer, userID uuid.UUID) string {
+	t.Helper()
+	token, err := s.jwtService.GenerateToken(userID)
+	require.NoError(t, err)
+	return token
+}
+
+func TestJWTService_CSRFToken(t *testing.T) {
+	service := setupTestJWTService(t, 1)
+	token := service.CSRFToken("session-a")
+	assert.True(t, service.ValidCSRFToken("session-a", token))
+	assert.False(t, service.ValidCSRFToken("session-b", token))
+	assert.False(t, service.ValidCSRFToken("session-a", ""))
+	assert.False(t, service.ValidCSRFToken("", service.CSRFToken("")))
+}
+
+func TestHandleFormsCreateRun_Bearer(t *testing.T) {
+	s := newAuthTestServer(t)
+	user := uuid.New()
+	req := formRequest(http.MethodPost, "/forms/runs", url.Values{
+		"job_url":     {"https://jobs.example.com/123"},
+		"max_bullets": {"12"},
+	})
+	req.Header.Set("Authorization", "Bearer "+mustToken(t, s, user))
+
+	w := serveForms("POST /forms/runs", s.handleFormsCreateRun, req)
+	require.Equal(t, http.StatusSeeOther, w.Code, w.Body.String())
+	runID, err := uuid.Parse(strings.TrimPrefix(w.Header().Get("Location"), "/forms/runs/"))
+	require.NoError(t, err)
+
+	require.Contains(t, s.mock.runJobs, runID)
+	var queued RunCreateRequest
+	require.NoError(t, json.Unmarshal(s.mock.runJobs[runID].Payload, &queued))
+	assert.Equal(t, RunCreateRequest{
+		UserID:     user.String(),
+		JobURL:     "https://jobs.example.com/123",
+		Template:   "templates/one_page_resume.tex",
+		MaxBullets: 12,
+		MaxLines:   35,
+		Execute:    true,
+	}, queued)
+	assert.Equal(t, user, *s.mock.runs[runID].UserID)
+}
+
+func TestHandleFormsCreateRun_SessionNeedsCSRF(t *testing.T) {
+	s := newAuthTestServer(t)
+	user := uuid.New()
+
+	req := formRequest(http.MethodPost, "/forms/runs", url.Values{"job_text": {"Staff SRE at Acme"}})
+	withSession(t, s, req, user)
+	w := serveForms("POST /forms/runs", s.handleFormsCreateRun, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, s.mock.runJobs)
+
+	req = formRequest(http.MethodPost, "/forms/runs", url.Values{"job_text": {"Staff SRE at Acme"}, "csrf_token": {"forged"}})
+	withSession(t, s, req, user)
+	w = serveForms("POST /forms/runs", s.handleFormsCreateRun, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req = sessionFormRequest(t, s, "/forms/runs", user, url.Values{"job_text": {"Staff SRE at Acme"}})
+	w = serveForms("POST /forms/runs", s.handleFormsCreateRun, req)
+	assert.Equal(t, http.StatusSeeOther, w.Code, w.Body.String())
+	assert.Len(t, s.mock.runJobs, 1)
+}
+
+func TestHandleFormsCreateRun_Invalid(t *testing.T) {
+	s := newAuthTestServer(t)
+
+	w := serveForms("POST /forms/runs", s.handleFormsCreateRun,
+		formRequest(http.MethodPost, "/forms/runs", url.Values{"job_text": {"SRE"}}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	for name, form := range map[string]url.Values{
+		"no job":      {"template": {"templates/two_column.tex"}},
+		"bad bullets": {"job_text": {"SRE"}, "max_bullets": {"many"}},
+		"zero lines":  {"job_text": {"SRE"}, "max_lines": {"0"}},
+	} {
+		req := formRequest(http.MethodPost, "/forms/runs", form)
+		req.Header.Set("Authorization", "Bearer "+mustToken(t, s, uuid.New()))
+		w := serveForms("POST /forms/runs", s.handleFormsCreateRun, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+	}
+	assert.Empty(t, s.mock.runJobs)
+}
+
+func TestHandleFormsRunPage(t *testing.T) {
+	s := newAuthTestServer(t)
+	owner := uuid.New()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Company: "Acme", RoleTitle: "SRE", Status: "running", UserID: &owner, CreatedAt: time.Now()}
+	target := "/forms/runs/" + runID.String()
+
+	// curl gets plain text
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Authorization", "Bearer "+mustToken(t, s, owner))
+	w := serveForms("GET /forms/runs/{id}", s.handleFormsRunPage, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "run_id: "+runID.String()+"\nstatus: running\n", w.Body.String())
+
+	// Browsers get a page that reloads while the run is in progress
+	req = httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Accept", "text/html")
+	withSession(t, s, req, owner)
+	w = serveForms("GET /forms/runs/{id}", s.handleFormsRunPage, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, formsCSP, w.Header().Get("Content-Security-Policy"))
+	assert.Contains(t, w.Body.String(), `http-equiv="refresh"`)
+	assert.NotContains(t, w.Body.String(), "/download")
+
+	s.mock.runs[runID].Status = "completed"
+	req = httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Accept", "text/html")
+	csrf := withSession(t, s, req, owner)
+	w = serveForms("GET /forms/runs/{id}", s.handleFormsRunPage, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), `http-equiv="refresh"`)
+	assert.Contains(t, w.Body.String(), `action="/forms/runs/`+runID.String()+`/download"`)
+	assert.Contains(t, w.Body.String(), `value="`+csrf+`"`)
+
+	req = httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Authorization", "Bearer "+mustToken(t, s, owner))
+	w = serveForms("GET /forms/runs/{id}", s.handleFormsRunPage, req)
+	assert.Contains(t, w.Body.String(), "pdf: /v1/runs/"+runID.String()+"/artifacts/pdf\n")
+
+	// Other users' runs are not found; signed-out browsers are sent to sign in
+	req = httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Authorization", "Bearer "+mustToken(t, s, uuid.New()))
+	w = serveForms("GET /forms/runs/{id}", s.handleFormsRunPage, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req = httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Accept", "text/html")
+	w = serveForms("GET /forms/runs/{id}", s.handleFormsRunPage, req)
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/forms/login", w.Header().Get("Location"))
+}
+
+func TestHandleFormsDownload(t *testing.T) {
+	s := newAuthTestServer(t)
+	owner := uuid.New()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Status: "running", UserID: &owner}
+	target := "/forms/runs/" + runID.String() + "/download"
+
+	download := func(artifact string) *httptest.ResponseRecorder {
+		req := formRequest(http.MethodPost, target, url.Values{"artifact": {artifact}})
+		req.Header.Set("Authorization", "Bearer "+mustToken(t, s, owner))
+		return serveForms("POST /forms/runs/{id}/download", s.handleFormsDownload, req)
+	}
+
+	w := download("pdf")
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/forms/runs/"+runID.String(), w.Header().Get("Location"), "unfinished runs go back to their page")
+
+	s.mock.runs[runID].Status = "completed"
+	w = download("pdf")
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/v1/runs/"+runID.String()+"/artifacts/pdf", w.Header().Get("Location"))
+	w = download("tex")
+	assert.Equal(t, "/v1/runs/"+runID.String()+"/resume.tex", w.Header().Get("Location"))
+	w = download("docx")
+	assert.Equal(t, "/v1/runs/"+runID.String()+"/download?format=docx", w.Header().Get("Location"))
+	w = download("rtf")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req := formRequest(http.MethodPost, target, url.Values{"artifact": {"pdf"}})
+	withSession(t, s, req, owner)
+	w = serveForms("POST /forms/runs/{id}/download", s.handleFormsDownload, req)
+	assert.Equal(t, http.StatusForbidden, w.Code, "session posts need a CSRF token")
+}
+
+func TestHandleFormsSession(t *testing.T) {
+	s := newAuthTestServer(t)
+	s.userService = NewUserService(s.mock, &config.PasswordConfig{BcryptCost: 10})
+	user := uuid.New()
+
+	// Signed-out browsers are sent to the sign-in form
+	w := serveForms("GET /forms/runs/new", s.handleFormsNewRunPage, httptest.NewRequest(http.MethodGet, "/forms/runs/new", nil))
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/forms/login", w.Header().Get("Location"))
+
+	w = serveForms("GET /forms/login", s.handleFormsLoginPage, httptest.NewRequest(http.MethodGet, "/forms/login?error=invalid", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid email or password.")
+
+	// Wrong credentials go back to the form; the token endpoint answers in plain text
+	form := url.Values{"email": {"nobody@example.com"}, "password": {"wrong"}}
+	w = serveForms("POST /forms/login", s.handleFormsLogin, formRequest(http.MethodPost, "/forms/login", form))
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/forms/login?error=invalid", w.Header().Get("Location"))
+	assert.Empty(t, w.Result().Cookies())
+	w = serveForms("POST /forms/token", s.handleFormsToken, formRequest(http.MethodPost, "/forms/token", form))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Signed-in browsers get the new run form with their CSRF token
+	req := httptest.NewRequest(http.MethodGet, "/forms/runs/new", nil)
+	csrf := withSession(t, s, req, user)
+	w = serveForms("GET /forms/runs/new", s.handleFormsNewRunPage, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `name="csrf_token" value="`+csrf+`"`)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	// Signing out needs the CSRF token and clears the cookie
+	req = formRequest(http.MethodPost, "/forms/logout", nil)
+	withSession(t, s, req, user)
+	w = serveForms("POST /forms/logout", s.handleFormsLogout, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	req = sessionFormRequest(t, s, "/forms/logout", user, url.Values{})
+	w = serveForms("POST /forms/logout", s.handleFormsLogout, req)
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, sessionCookieName, cookies[0].Name)
+	assert.Negative(t, cookies[0].MaxAge)
+}
@@ -0,0 +1,321 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/github"
+)
+
+// GitHubAccountRequest is the request body for linking a GitHub profile
+type GitHubAccountRequest struct {
+	Username string `json:"username"`
+	Token    string `json:"token,omitempty"` // Optional access token; needed to read pinned repositories
+}
+
+// GitHubSyncResponse is the response for syncing a user's GitHub repositories
+type GitHubSyncResponse struct {
+	Repos  int               `json:"repos"`  // Repositories fetched from GitHub
+	Drafts []db.ProjectDraft `json:"drafts"` // All drafts now pending review
+}
+
+// ProjectDraftsResponse is the response for listing project drafts
+type ProjectDraftsResponse struct {
+	Drafts []db.ProjectDraft `json:"drafts"`
+}
+
+// AcceptProjectDraftRequest is the request body for adding a draft to the experience bank
+type AcceptProjectDraftRequest struct {
+	JobID uuid.UUID `json:"job_id"`         // Job the project story is filed under
+	Text  string    `json:"text,omitempty"` // Edited bullet; empty keeps the draft text
+}
+
+// handleGetGitHubAccount returns the caller's linked GitHub profile
+func (s *Server) handleGetGitHubAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "GitHub account")
+	if !ok {
+		return
+	}
+
+	account, err := s.db.GetGitHubAccount(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if account == nil {
+		s.errorResponse(w, http.StatusNotFound, "No GitHub account linked")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, account)
+}
+
+// handlePutGitHubAccount links the caller's GitHub profile, storing the optional token encrypted
+func (s *Server) handlePutGitHubAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "GitHub account")
+	if !ok {
+		return
+	}
+
+	var req GitHubAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Username = strings.TrimSpace(req.Username)
+	if !github.ValidUsername(req.Username) {
+		s.errorResponse(w, http.StatusBadRequest, "username must be a valid GitHub username")
+		return
+	}
+
+	var sealed *string
+	if req.Token != "" {
+		if !s.github.TokensEnabled() {
+			s.errorResponse(w, http.StatusBadRequest, "This server does not store GitHub tokens; link the account without one")
+			return
+		}
+		token, err := github.SealToken(s.github.TokenKey, req.Token)
+		if err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, "Failed to store token: "+err.Error())
+			return
+		}
+		sealed = &token
+	}
+
+	account, err := s.db.UpsertGitHubAccount(r.Context(), userID, req.Username, sealed)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, account)
+}
+
+// handleDeleteGitHubAccount unlinks the caller's GitHub profile and deletes its token
+func (s *Server) handleDeleteGitHubAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "GitHub account")
+	if !ok {
+		return
+	}
+
+	if err := s.db.DeleteGitHubAccount(r.Context(), userID); err != nil {
+		s.errorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleSyncGitHub fetches the caller's showcased repositories and drafts a project
+// bullet for each. Syncs are limited to one per GITHUB_SYNC_INTERVAL_MINUTES per user,
+// and GitHub's own rate limit is passed on as 429 with Retry-After.
+func (s *Server) handleSyncGitHub(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "GitHub account")
+	if !ok {
+		return
+	}
+
+	account, err := s.db.GetGitHubAccount(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if account == nil {
+		s.errorResponse(w, http.StatusNotFound, "No GitHub account linked")
+		return
+	}
+
+	var token string
+	if account.TokenSealed != nil {
+		if s.github.TokensEnabled() {
+			token, err = github.OpenToken(s.github.TokenKey, *account.TokenSealed)
+		} else {
+			err = errors.New("GITHUB_TOKEN_KEY is not set")
+		}
+		if err != nil {
+			slog.WarnContext(r.Context(), "syncing GitHub without the user's token", "error", err)
+			token = ""
+		}
+	}
+
+	claimed, next, err := s.db.ClaimGitHubSync(r.Context(), userID, s.github.SyncInterval())
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if !claimed {
+		setRetryAfter(w, next)
+		s.errorResponse(w, http.StatusTooManyRequests, "GitHub was synced recently; try again later")
+		return
+	}
+
+	repos, err := github.NewClient(s.github.APIURL, token).ShowcasedRepos(r.Context(), account.Username)
+	var rateErr *github.RateLimitError
+	switch {
+	case errors.As(err, &rateErr):
+		setRetryAfter(w, rateErr.Reset)
+		s.errorResponse(w, http.StatusTooManyRequests, "GitHub rate limit reached; try again later")
+		return
+	case errors.Is(err, github.ErrUserNotFound):
+		s.errorResponse(w, http.StatusNotFound, "GitHub user not found: "+account.Username)
+		return
+	case err != nil:
+		s.errorResponse(w, http.StatusBadGateway, "Failed to read GitHub repositories: "+err.Error())
+		return
+	}
+
+	drafts := make([]db.ProjectDraftInput, 0, len(repos))
+	for _, repo := range repos {
+		drafts = append(drafts, db.ProjectDraftInput{
+			RepoName:    repo.Name,
+			RepoURL:     repo.URL,
+			Description: repo.Description,
+			Language:    repo.Language,
+			Stars:       repo.Stars,
+			Skills:      github.Skills(repo),
+			Text:        github.DraftBullet(repo),
+		})
+	}
+	if err := s.db.UpsertProjectDrafts(r.Context(), userID, drafts); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	pending, err := s.db.ListProjectDrafts(r.Context(), userID, db.DraftStatusPending)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, GitHubSyncResponse{Repos: len(repos), Drafts: pending})
+}
+
+// setRetryAfter sets the Retry-After header to the whole seconds until t (at least 1)
+func setRetryAfter(w http.ResponseWriter, t time.Time) {
+	secs := int(time.Until(t).Seconds() + 0.999)
+	if secs < 1 {
+		secs = 1
+	}
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", secs))
+}
+
+// handleListProjectDrafts returns the caller's project drafts, optionally filtered by ?status=
+func (s *Server) handleListProjectDrafts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "project drafts")
+	if !ok {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", db.DraftStatusPending, db.DraftStatusAccepted, db.DraftStatusDismissed:
+	default:
+		s.errorResponse(w, http.StatusBadRequest, "status must be pending, accepted, or dismissed")
+		return
+	}
+
+	drafts, err := s.db.ListProjectDrafts(r.Context(), userID, status)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, ProjectDraftsResponse{Drafts: drafts})
+}
+
+// handleAcceptProjectDraft adds a reviewed draft to the caller's experience bank as a
+// story under one of their jobs
+func (s *Server) handleAcceptProjectDraft(w http.ResponseWriter, r *http.Request) {
+	userID, draft, ok := s.lookupProjectDraft(w, r)
+	if !ok {
+		return
+	}
+
+	var req AcceptProjectDraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.JobID == uuid.Nil {
+		s.errorResponse(w, http.StatusBadRequest, "job_id is required")
+		return
+	}
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		text = draft.Text
+	}
+
+	if !s.requireOwnJob(w, r, userID, req.JobID) {
+		return
+	}
+
+	accepted, err := s.db.AcceptProjectDraft(r.Context(), draft.ID, req.JobID, text)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if accepted == nil {
+		s.errorResponse(w, http.StatusConflict, "Draft was already reviewed")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, accepted)
+}
+
+// handleDismissProjectDraft rejects a draft; later syncs will not bring it back
+func (s *Server) handleDismissProjectDraft(w http.ResponseWriter, r *http.Request) {
+	_, draft, ok := s.lookupProjectDraft(w, r)
+	if !ok {
+		return
+	}
+
+	dismissed, err := s.db.DismissProjectDraft(r.Context(), draft.ID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if dismissed == nil {
+		s.errorResponse(w, http.StatusConflict, "Draft was already reviewed")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, dismissed)
+}
+
+// requireOwnJob writes a 400 unless jobID is one of userID's jobs
+func (s *Server) requireOwnJob(w http.ResponseWriter, r *http.Request, userID, jobID uuid.UUID) bool {
+	jobs, err := s.db.ListJobs(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return false
+	}
+	for _, job := range jobs {
+		if job.ID == jobID {
+			return true
+		}
+	}
+	s.errorResponse(w, http.StatusBadRequest, "job_id must be one of your jobs")
+	return false
+}
+
+// lookupProjectDraft resolves the {draft_id} path value to one of the caller's drafts,
+// writing a 404 for drafts of other users so their IDs are not disclosed
+func (s *Server) lookupProjectDraft(w http.ResponseWriter, r *http.Request) (uuid.UUID, *db.ProjectDraft, bool) {
+	userID, ok := s.pathUserIsCaller(w, r, "project drafts")
+	if !ok {
+		return uuid.Nil, nil, false
+	}
+
+	draftID, err := uuid.Parse(r.PathValue("draft_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid draft ID")
+		return uuid.Nil, nil, false
+	}
+
+	draft, err := s.db.GetProjectDraft(r.Context(), draftID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return uuid.Nil, nil, false
+	}
+	if draft == nil || draft.UserID != userID {
+		s.errorResponse(w, http.StatusNotFound, "Draft not found")
+		return uuid.Nil, nil, false
+	}
+	return userID, draft, true
+}
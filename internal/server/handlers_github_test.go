@@ -0,0 +1,176 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// newGitHubTestServer returns a server whose GitHub API, if any, is served by api
+func newGitHubTestServer(t *testing.T, api http.HandlerFunc) *testServer {
+	t.Helper()
+	s := newAuthTestServer(t)
+	s.github = &config.GitHubConfig{
+		SyncIntervalMinutes: 60,
+		TokenKey:            []byte(strings.Repeat("k", 32)),
+	}
+	if api != nil {
+		gh := httptest.NewServer(api)
+		t.Cleanup(gh.Close)
+		s.github.APIURL = gh.URL
+	}
+	return s
+}
+
+func TestHandlePutGitHubAccount(t *testing.T) {
+	owner := uuid.New()
+	s := newGitHubTestServer(t, nil)
+	target := "/v1/users/" + owner.String() + "/github"
+
+	w := serveAuthed(t, s, "PUT /v1/users/{id}/github", s.handlePutGitHubAccount,
+		bearerRequest(t, s, http.MethodPut, target, owner, []byte(`{"username":"../admin"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveAuthed(t, s, "PUT /v1/users/{id}/github", s.handlePutGitHubAccount,
+		bearerRequest(t, s, http.MethodPut, target, uuid.New(), []byte(`{"username":"octocat"}`)))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serveAuthed(t, s, "PUT /v1/users/{id}/github", s.handlePutGitHubAccount,
+		bearerRequest(t, s, http.MethodPut, target, owner, []byte(`{"username":"octocat","token":"ghp_secret"}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "ghp_secret")
+	assert.NotContains(t, w.Body.String(), "token_sealed")
+
+	var account db.GitHubAccount
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &account))
+	assert.Equal(t, "octocat", account.Username)
+	assert.True(t, account.HasToken)
+	assert.NotContains(t, *s.mock.githubAccounts[owner].TokenSealed, "ghp_secret")
+
+	// Without a key the server refuses to keep tokens
+	s.github.TokenKey = nil
+	w = serveAuthed(t, s, "PUT /v1/users/{id}/github", s.handlePutGitHubAccount,
+		bearerRequest(t, s, http.MethodPut, target, owner, []byte(`{"username":"octocat","token":"ghp_secret"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandleSyncGitHub(t *testing.T) {
+	owner := uuid.New()
+	s := newGitHubTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer ghp_secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"data":{"user":{"pinnedItems":{"nodes":[
+			{"name":"linguist","description":"Language detection","url":"https://github.com/octocat/linguist",
+			 "stargazerCount":12,"primaryLanguage":{"name":"Ruby"},"repositoryTopics":{"nodes":[]}}
+		]}}}}`))
+	})
+	target := "/v1/users/" + owner.String() + "/github/sync"
+
+	w := serveAuthed(t, s, "POST /v1/users/{id}/github/sync", s.handleSyncGitHub,
+		bearerRequest(t, s, http.MethodPost, target, owner, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code, "no linked account")
+
+	w = serveAuthed(t, s, "PUT /v1/users/{id}/github", s.handlePutGitHubAccount,
+		bearerRequest(t, s, http.MethodPut, "/v1/users/"+owner.String()+"/github", owner,
+			[]byte(`{"username":"octocat","token":"ghp_secret"}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serveAuthed(t, s, "POST /v1/users/{id}/github/sync", s.handleSyncGitHub,
+		bearerRequest(t, s, http.MethodPost, target, owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp GitHubSyncResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Repos)
+	require.Len(t, resp.Drafts, 1)
+	assert.Equal(t, "Built linguist, a Ruby project (language detection), earning 12 GitHub stars.", resp.Drafts[0].Text)
+	assert.Equal(t, db.DraftStatusPending, resp.Drafts[0].Status)
+
+	// A second sync within the interval is refused
+	w = serveAuthed(t, s, "POST /v1/users/{id}/github/sync", s.handleSyncGitHub,
+		bearerRequest(t, s, http.MethodPost, target, owner, nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "3600", w.Header().Get("Retry-After"))
+}
+
+func TestHandleSyncGitHub_RateLimited(t *testing.T) {
+	owner := uuid.New()
+	s := newGitHubTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusForbidden)
+	})
+	s.mock.githubAccounts = map[uuid.UUID]*db.GitHubAccount{owner: {UserID: owner, Username: "octocat"}}
+
+	w := serveAuthed(t, s, "POST /v1/users/{id}/github/sync", s.handleSyncGitHub,
+		bearerRequest(t, s, http.MethodPost, "/v1/users/"+owner.String()+"/github/sync", owner, nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "120", w.Header().Get("Retry-After"))
+}
+
+func TestHandleAcceptProjectDraft(t *testing.T) {
+	owner := uuid.New()
+	s := newGitHubTestServer(t, nil)
+	jobID := uuid.New()
+	s.mock.jobs = []db.Job{{ID: jobID, UserID: owner, Company: "Acme", StartDate: &db.Date{Time: time.Now()}}}
+	draft := &db.ProjectDraft{ID: uuid.New(), UserID: owner, RepoName: "linguist", Text: "Built linguist.", Status: db.DraftStatusPending}
+	s.mock.projectDrafts = []*db.ProjectDraft{draft}
+	target := "/v1/users/" + owner.String() + "/project-drafts/" + draft.ID.String() + "/accept"
+	pattern := "POST /v1/users/{id}/project-drafts/{draft_id}/accept"
+
+	// Drafts of other users are not visible
+	w := serveAuthed(t, s, pattern, s.handleAcceptProjectDraft,
+		bearerRequest(t, s, http.MethodPost, "/v1/users/"+owner.String()+"/project-drafts/"+uuid.New().String()+"/accept",
+			owner, []byte(`{"job_id":"`+jobID.String()+`"}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// The story must go under one of the caller's own jobs
+	w = serveAuthed(t, s, pattern, s.handleAcceptProjectDraft,
+		bearerRequest(t, s, http.MethodPost, target, owner, []byte(`{"job_id":"`+uuid.New().String()+`"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveAuthed(t, s, pattern, s.handleAcceptProjectDraft,
+		bearerRequest(t, s, http.MethodPost, target, owner,
+			[]byte(`{"job_id":"`+jobID.String()+`","text":"Built linguist, used by 40 teams."}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var accepted db.ProjectDraft
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &accepted))
+	assert.Equal(t, db.DraftStatusAccepted, accepted.Status)
+	assert.Equal(t, "Built linguist, used by 40 teams.", accepted.Text)
+	assert.NotNil(t, accepted.StoryID)
+
+	w = serveAuthed(t, s, "POST /v1/users/{id}/project-drafts/{draft_id}/dismiss", s.handleDismissProjectDraft,
+		bearerRequest(t, s, http.MethodPost, strings.Replace(target, "/accept", "/dismiss", 1), owner, nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestHandleListProjectDrafts(t *testing.T) {
+	owner := uuid.New()
+	s := newGitHubTestServer(t, nil)
+	s.mock.projectDrafts = []*db.ProjectDraft{
+		{ID: uuid.New(), UserID: owner, Status: db.DraftStatusPending},
+		{ID: uuid.New(), UserID: owner, Status: db.DraftStatusDismissed},
+		{ID: uuid.New(), UserID: uuid.New(), Status: db.DraftStatusPending},
+	}
+	target := "/v1/users/" + owner.String() + "/project-drafts"
+
+	w := serveAuthed(t, s, "GET /v1/users/{id}/project-drafts", s.handleListProjectDrafts,
+		bearerRequest(t, s, http.MethodGet, target+"?status=pending", owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp ProjectDraftsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Drafts, 1)
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/project-drafts", s.handleListProjectDrafts,
+		bearerRequest(t, s, http.MethodGet, target+"?status=bogus", owner, nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/interchange"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// maxImportSize bounds imported documents; a full JSON Resume is tens of KB
+const maxImportSize = 1 << 20
+
+// ImportExperienceResponse counts what an import added to the experience bank
+type ImportExperienceResponse struct {
+	Format           string   `json:"format"`
+	JobsCreated      int      `json:"jobs_created"`
+	JobsSkipped      int      `json:"jobs_skipped"` // Already in the bank
+	BulletsCreated   int      `json:"bullets_created"`
+	EducationCreated int      `json:"education_created"`
+	EducationSkipped int      `json:"education_skipped"` // Already in the bank
+	SkillsImported   int      `json:"skills_imported"`
+	IgnoredSections  []string `json:"ignored_sections,omitempty"` // Sections the bank has no place for
+}
+
+// interchangeFormat reads ?format, which defaults to JSON Resume, the only format so far
+func (s *Server) interchangeFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" || format == interchange.FormatJSONResume {
+		return interchange.FormatJSONResume, true
+	}
+	s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("format must be %q", interchange.FormatJSONResume))
+	return "", false
+}
+
+// handleImportExperience adds the work, education, and skills in an uploaded document
+// to the caller's experience bank. Positions already in the bank (same company, role,
+// and start) and education already in it (same school, degree, and field) are skipped,
+// so importing the same document twice does not duplicate it.
+func (s *Server) handleImportExperience(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "experience bank")
+	if !ok {
+		return
+	}
+	format, ok := s.interchangeFormat(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		s.errorResponse(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("document must be at most %d bytes", maxImportSize))
+		return
+	}
+	resume, ignored, err := interchange.ParseJSONResume(data)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	bank, err := interchange.FromJSONResume(resume)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp := ImportExperienceResponse{Format: format, IgnoredSections: ignored}
+	if err := s.importExperienceBank(r, userID, bank, &resp); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
+
+// importExperienceBank stores an imported bank's stories as jobs with their bullets,
+// its education, and its skill self-assessments, counting the results into resp
+func (s *Server) importExperienceBank(r *http.Request, userID uuid.UUID, bank *types.ExperienceBank, resp *ImportExperienceResponse) error {
+	ctx := r.Context()
+
+	jobs, err := s.db.ListJobs(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("fetching jobs: %w", err)
+	}
+	existingJobs := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		existingJobs[importKey(job.Company, job.RoleTitle, monthOf(job.StartDate))] = true
+	}
+	for _, story := range bank.Stories {
+		key := importKey(story.Company, story.Role, story.StartDate)
+		if existingJobs[key] {
+			resp.JobsSkipped++
+			continue
+		}
+		existingJobs[key] = true
+
+		job := &db.Job{UserID: userID, Company: story.Company, RoleTitle: story.Role, EmploymentType: "full-time"}
+		job.StartDate = parseBankMonth(story.StartDate)
+		job.EndDate = parseBankMonth(story.EndDate)
+		jobID, err := s.db.CreateJob(ctx, job)
+		if err != nil {
+			return fmt.Errorf("creating job at %s: %w", story.Company, err)
+		}
+		resp.JobsCreated++
+		for _, bullet := range story.Bullets {
+			exp := &db.Experience{
+				JobID:            jobID,
+				BulletText:       bullet.Text,
+				Skills:           bullet.Skills,
+				EvidenceStrength: bullet.EvidenceStrength,
+				RiskFlags:        bullet.RiskFlags,
+			}
+			if _, err := s.db.CreateExperience(ctx, exp); err != nil {
+				return fmt.Errorf("creating bullet for %s: %w", story.Company, err)
+			}
+			resp.BulletsCreated++
+		}
+	}
+
+	education, err := s.db.ListEducation(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("fetching education: %w", err)
+	}
+	existingEducation := make(map[string]bool, len(education))
+	for _, e := range education {
+		existingEducation[importKey(e.School, e.DegreeType, e.Field)] = true
+	}
+	for _, e := range bank.Education {
+		key := importKey(e.School, e.Degree, e.Field)
+		if existingEducation[key] {
+			resp.EducationSkipped++
+			continue
+		}
+		existingEducation[key] = true
+
+		edu := &db.Education{UserID: userID, School: e.School, DegreeType: e.Degree, Field: e.Field, GPA: e.GPA}
+		edu.StartDate = parseBankMonth(e.StartDate)
+		edu.EndDate = parseBankMonth(e.EndDate)
+		if _, err := s.db.CreateEducation(ctx, edu); err != nil {
+			return fmt.Errorf("creating education at %s: %w", e.School, err)
+		}
+		resp.EducationCreated++
+	}
+
+	for _, a := range bank.SkillProfile {
+		var proficiency *int
+		if a.Proficiency >= db.MinProficiency && a.Proficiency <= db.MaxProficiency {
+			proficiency = &a.Proficiency
+		}
+		if _, err := s.db.UpsertUserSkill(ctx, userID, a.Skill, proficiency, nil); err != nil {
+			return fmt.Errorf("saving skill %s: %w", a.Skill, err)
+		}
+		resp.SkillsImported++
+	}
+	return nil
+}
+
+// handleExportExperience serves the caller's experience bank as a document other tools
+// can import, with the account's name and contact details
+func (s *Server) handleExportExperience(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "experience bank")
+	if !ok {
+		return
+	}
+	if _, ok := s.interchangeFormat(w, r); !ok {
+		return
+	}
+
+	bank, err := s.fetchExperienceBankFromDB(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to fetch experience bank: "+err.Error())
+		return
+	}
+	user, err := s.db.GetUser(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	var basics *interchange.JSONResumeBasics
+	if user != nil {
+		basics = &interchange.JSONResumeBasics{Name: user.Name, Email: user.Email, Phone: user.Phone}
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="resume.json"`)
+	s.jsonResponse(w, http.StatusOK, interchange.ToJSONResume(bank, basics))
+}
+
+// importKey identifies an entry for deduplication, ignoring case and surrounding space
+func importKey(parts ...string) string {
+	for i, p := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(p))
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// monthOf formats a date as the experience bank's YYYY-MM, or "" if unset
+func monthOf(d *db.Date) string {
+	if d == nil {
+		return ""
+	}
+	return d.Format("2006-01")
+}
+
+// parseBankMonth parses an experience bank YYYY-MM date, returning nil if unset or invalid
+func parseBankMonth(month string) *db.Date {
+	t, err := time.Parse("2006-01", month)
+	if err != nil {
+		return nil
+	}
+	return &db.Date{Time: t}
+}
+
+// handleExportAccount serves everything the caller owns (account, experience bank, runs
+// with their artifacts and application dates, and settings) as a user export, which
+// `resume_agent admin import-user` loads into another deployment. The password hash is
+// left out, so the user signs in there with a new password.
+func (s *Server) handleExportAccount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "account")
+	if !ok {
+		return
+	}
+
+	export, err := s.db.ExportUser(r.Context(), userID, false)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if export == nil {
+		s.errorResponse(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="user-export.json"`)
+	s.jsonResponse(w, http.StatusOK, export)
+}
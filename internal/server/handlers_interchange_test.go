@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+const importDocument = `{
+  "basics": {"name": "Jane Doe"},
+  "work": [
+    {"name": "Acme Corp", "position": "Senior Engineer", "startDate": "2020-03", "highlights": ["Moved billing to Kafka", "Mentored four engineers"]},
+    {"name": "Startup", "position": "Engineer", "startDate": "2017-01", "endDate": "2020-02", "highlights": ["Built the first API"]}
+  ],
+  "education": [{"institution": "State University", "area": "Computer Science", "studyType": "Bachelor", "endDate": "2017"}],
+  "skills": [{"name": "Go", "level": "Expert"}, {"name": "Rust"}],
+  "awards": [{"title": "Hackathon winner"}]
+}`
+
+func TestHandleImportExperience(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	target := "/v1/users/" + user.String() + "/experience/import?format=jsonresume"
+	pattern := "POST /v1/users/{id}/experience/import"
+	start := db.Date{Time: time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s.mock.jobs = []db.Job{{ID: uuid.New(), UserID: user, Company: "startup", RoleTitle: "Engineer", StartDate: &start}}
+
+	w := serveAuthed(t, s, pattern, s.handleImportExperience, bearerRequest(t, s, http.MethodPost, target, user, []byte(importDocument)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp ImportExperienceResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, ImportExperienceResponse{
+		Format:           "jsonresume",
+		JobsCreated:      1,
+		JobsSkipped:      1,
+		BulletsCreated:   2,
+		EducationCreated: 1,
+		SkillsImported:   2,
+		IgnoredSections:  []string{"awards"},
+	}, resp)
+
+	require.Len(t, s.mock.jobs, 2)
+	acme := s.mock.jobs[1]
+	assert.Equal(t, "Acme Corp", acme.Company)
+	assert.Equal(t, "2020-03", acme.StartDate.Format("2006-01"))
+	assert.Nil(t, acme.EndDate)
+	require.Len(t, s.mock.experiences, 2)
+	assert.Equal(t, acme.ID, s.mock.experiences[0].JobID)
+	assert.Equal(t, "Moved billing to Kafka", s.mock.experiences[0].BulletText)
+	require.Len(t, s.mock.education, 1)
+	assert.Equal(t, "bachelor", s.mock.education[0].DegreeType)
+	require.Len(t, s.mock.userSkills[user], 2)
+	assert.Equal(t, 5, *s.mock.userSkills[user][0].Proficiency)
+	assert.Nil(t, s.mock.userSkills[user][1].Proficiency)
+
+	// Importing again adds nothing new
+	w = serveAuthed(t, s, pattern, s.handleImportExperience, bearerRequest(t, s, http.MethodPost, target, user, []byte(importDocument)))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 0, resp.JobsCreated)
+	assert.Equal(t, 2, resp.JobsSkipped)
+	assert.Equal(t, 1, resp.EducationSkipped)
+	assert.Len(t, s.mock.jobs, 2)
+}
+
+func TestHandleImportExperience_Rejects(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	target := "/v1/users/" + user.String() + "/experience/import"
+	pattern := "POST /v1/users/{id}/experience/import"
+
+	for name, tc := range map[string]struct {
+		caller uuid.UUID
+		target string
+		body   string
+		code   int
+	}{
+		"other user":   {uuid.New(), target, importDocument, http.StatusForbidden},
+		"bad format":   {user, target + "?format=linkedin", importDocument, http.StatusBadRequest},
+		"invalid JSON": {user, target, `{"work": [`, http.StatusBadRequest},
+		"invalid date": {user, target, `{"work": [{"name": "Acme", "position": "Engineer", "startDate": "March 2020"}]}`, http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serveAuthed(t, s, pattern, s.handleImportExperience, bearerRequest(t, s, http.MethodPost, tc.target, tc.caller, []byte(tc.body)))
+			assert.Equal(t, tc.code, w.Code, w.Body.String())
+		})
+	}
+	assert.Empty(t, s.mock.jobs)
+}
+
+func TestHandleExportExperience(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Jane Doe", Email: "jane@example.com"}}
+	jobID := uuid.New()
+	start := db.Date{Time: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)}
+	s.mock.jobs = []db.Job{{ID: jobID, UserID: user, Company: "Acme Corp", RoleTitle: "Senior Engineer", StartDate: &start}}
+	s.mock.experiences = []db.Experience{{ID: uuid.New(), JobID: jobID, BulletText: "Moved billing to Kafka"}}
+	target := "/v1/users/" + user.String() + "/experience/export?format=jsonresume"
+	pattern := "GET /v1/users/{id}/experience/export"
+
+	w := serveAuthed(t, s, pattern, s.handleExportExperience, bearerRequest(t, s, http.MethodGet, target, user, nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "resume.json")
+	var doc struct {
+		Basics struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"basics"`
+		Work []struct {
+			Name       string   `json:"name"`
+			Position   string   `json:"position"`
+			StartDate  string   `json:"startDate"`
+			Highlights []string `json:"highlights"`
+		} `json:"work"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "Jane Doe", doc.Basics.Name)
+	require.Len(t, doc.Work, 1)
+	assert.Equal(t, "Acme Corp", doc.Work[0].Name)
+	assert.Equal(t, "2020-03", doc.Work[0].StartDate)
+	assert.Equal(t, []string{"Moved billing to Kafka"}, doc.Work[0].Highlights)
+
+	w = serveAuthed(t, s, pattern, s.handleExportExperience, bearerRequest(t, s, http.MethodGet, target, uuid.New(), nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestHandleExportAccount(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Jane Doe", Email: "jane@example.com"}}
+	s.mock.jobs = []db.Job{{ID: uuid.New(), UserID: user, Company: "Acme Corp"}, {ID: uuid.New(), UserID: uuid.New(), Company: "Other"}}
+	target := "/v1/users/" + user.String() + "/export"
+	pattern := "GET /v1/users/{id}/export"
+
+	w := serveAuthed(t, s, pattern, s.handleExportAccount, bearerRequest(t, s, http.MethodGet, target, user, nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "user-export.json")
+	var export db.UserExport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &export))
+	assert.Equal(t, db.UserExportFormat, export.Format)
+	assert.Equal(t, user, export.UserID)
+	require.Len(t, export.Tables, 2)
+	assert.Len(t, export.Tables[1].Rows, 1, "only the caller's rows")
+
+	w = serveAuthed(t, s, pattern, s.handleExportAccount, bearerRequest(t, s, http.MethodGet, target, uuid.New(), nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
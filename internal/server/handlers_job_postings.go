@@ -9,21 +9,23 @@ import (
 
 // ListJobPostingsResponse represents the response for listing job postings
 type ListJobPostingsResponse struct {
-	Postings []db.JobPosting `json:"postings"`
-	Count    int             `json:"count"`
-	Limit    int             `json:"limit"`
-	Offset   int             `json:"offset"`
+	Postings   []db.JobPosting `json:"postings"`
+	Count      int             `json:"count"` // Postings matching the filters, across pages
+	Limit      int             `json:"limit"`
+	NextCursor string          `json:"next_cursor"` // Empty on the last page
 }
 
 // handleListJobPostings lists job postings with optional filters and pagination
 func (s *Server) handleListJobPostings(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	limit := parseQueryInt(r, "limit", 50, 100)
-	offset := parseQueryInt(r, "offset", 0, 0)
+	page, ok := s.parseListPage(w, r, jobPostingListSpec)
+	if !ok {
+		return
+	}
 
 	opts := db.ListJobPostingsOptions{
-		Limit:  limit,
-		Offset: offset,
+		Limit: page.Limit,
+		Page:  page.Page,
 	}
 
 	// Parse optional filters
@@ -31,6 +33,8 @@ func (s *Server) handleListJobPostings(w http.ResponseWriter, r *http.Request) {
 		opts.Platform = &platform
 	}
 
+	opts.Canonical = r.URL.Query().Get("canonical") == "true"
+
 	if companyIDStr := r.URL.Query().Get("company_id"); companyIDStr != "" {
 		companyID, err := uuid.Parse(companyIDStr)
 		if err != nil {
@@ -46,11 +50,15 @@ func (s *Server) handleListJobPostings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if postings == nil {
+		postings = []db.JobPosting{}
+	}
+
 	s.jsonResponse(w, http.StatusOK, ListJobPostingsResponse{
-		Postings: postings,
-		Count:    total,
-		Limit:    limit,
-		Offset:   offset,
+		Postings:   postings,
+		Count:      total,
+		Limit:      page.Limit,
+		NextCursor: nextCursor(page, postings, jobPostingSortKey),
 	})
 }
 
@@ -76,6 +84,54 @@ func (s *Server) handleGetJobPosting(w http.ResponseWriter, r *http.Request) {
 	s.jsonResponse(w, http.StatusOK, posting)
 }
 
+// DuplicatePostingsResponse represents a canonical posting and the postings that duplicate it
+type DuplicatePostingsResponse struct {
+	Canonical  *db.JobPosting  `json:"canonical"`
+	Duplicates []db.JobPosting `json:"duplicates"`
+}
+
+// handleListDuplicatePostings returns the canonical posting of a posting's duplicate group
+// and every posting linked to it
+func (s *Server) handleListDuplicatePostings(w http.ResponseWriter, r *http.Request) {
+	postingID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid job posting ID")
+		return
+	}
+
+	posting, err := s.db.GetJobPostingByID(r.Context(), postingID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if posting == nil {
+		s.errorResponse(w, http.StatusNotFound, "Job posting not found")
+		return
+	}
+	canonical := posting
+	if posting.CanonicalPostingID != nil {
+		if canonical, err = s.db.GetJobPostingByID(r.Context(), *posting.CanonicalPostingID); err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+			return
+		}
+		if canonical == nil {
+			s.errorResponse(w, http.StatusNotFound, "Canonical job posting not found")
+			return
+		}
+	}
+
+	duplicates, err := s.db.ListDuplicatePostings(r.Context(), canonical.ID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if duplicates == nil {
+		duplicates = []db.JobPosting{}
+	}
+
+	s.jsonResponse(w, http.StatusOK, DuplicatePostingsResponse{Canonical: canonical, Duplicates: duplicates})
+}
+
 // handleGetJobPostingByURL retrieves a job posting by its URL
 func (s *Server) handleGetJobPostingByURL(w http.ResponseWriter, r *http.Request) {
 	url := r.URL.Query().Get("url")
@@ -94,7 +94,7 @@ func TestJobPostingsEndpoints_Integration(t *testing.T) {
 
 	// Test 4: List job postings with filters
 	t.Run("ListJobPostings", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/job-postings?limit=10&offset=0&platform=greenhouse", nil)
+		req := httptest.NewRequest(http.MethodGet, "/job-postings?limit=10&platform=greenhouse", nil)
 		w := httptest.NewRecorder()
 
 		s.handleListJobPostings(w, req)
@@ -158,7 +158,7 @@ func TestListJobPostings_Pagination_Integration(t *testing.T) {
 
 	// Test pagination
 	t.Run("Pagination", func(t *testing.T) {
-		req := httptest.NewRequest(http.MethodGet, "/job-postings?limit=2&offset=0", nil)
+		req := httptest.NewRequest(http.MethodGet, "/job-postings?limit=2", nil)
 		w := httptest.NewRecorder()
 
 		s.handleListJobPostings(w, req)
@@ -167,8 +167,24 @@ func TestListJobPostings_Pagination_Integration(t *testing.T) {
 		var resp ListJobPostingsResponse
 		err := json.Unmarshal(w.Body.Bytes(), &resp)
 		require.NoError(t, err)
-		assert.LessOrEqual(t, len(resp.Postings), 2)
+		require.Len(t, resp.Postings, 2)
 		assert.Equal(t, 2, resp.Limit)
-		assert.Equal(t, 0, resp.Offset)
+		require.NotEmpty(t, resp.NextCursor)
+
+		// The next page continues after the first
+		req = httptest.NewRequest(http.MethodGet, "/job-postings?limit=2&cursor="+resp.NextCursor, nil)
+		w = httptest.NewRecorder()
+
+		s.handleListJobPostings(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var next ListJobPostingsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &next))
+		require.NotEmpty(t, next.Postings)
+		for _, p := range next.Postings {
+			assert.NotEqual(t, resp.Postings[0].ID, p.ID)
+			assert.NotEqual(t, resp.Postings[1].ID, p.ID)
+			assert.False(t, p.CreatedAt.After(resp.Postings[1].CreatedAt), "newest first across pages")
+		}
 	})
 }
@@ -1,9 +1,14 @@
 package server
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/server/middleware"
 )
 
 // handleGetJobProfile retrieves a job profile by its ID
@@ -145,3 +150,151 @@ func (s *Server) handleGetKeywords(w http.ResponseWriter, r *http.Request) {
 		"count":    len(keywords),
 	})
 }
+
+// RequirementWeightRequest sets the caller's importance weight for a job requirement.
+// A missing or null weight clears it, so the default weight applies again.
+type RequirementWeightRequest struct {
+	Weight *float64 `json:"weight"`
+}
+
+// handleUpdateRequirementWeight sets the caller's weight for a job requirement, used when
+// their runs for the posting rank stories
+func (s *Server) handleUpdateRequirementWeight(w http.ResponseWriter, r *http.Request) {
+	requirementID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid requirement ID")
+		return
+	}
+
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req RequirementWeightRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Weight != nil && (*req.Weight < 0 || *req.Weight > db.MaxRequirementWeight) {
+		s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("weight must be between 0 and %g", db.MaxRequirementWeight))
+		return
+	}
+
+	requirement, err := s.db.GetJobRequirement(r.Context(), requirementID, callerID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if requirement == nil {
+		s.errorResponse(w, http.StatusNotFound, "Requirement not found")
+		return
+	}
+
+	if err := s.db.SetRequirementWeight(r.Context(), callerID, requirement, req.Weight); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	requirement.Weight = req.Weight
+	s.jsonResponse(w, http.StatusOK, requirement)
+}
+
+// maxKeywordLength bounds keywords users add; parsed keywords are a few words
+const maxKeywordLength = 100
+
+// KeywordRequest adds a keyword to a job profile for the caller's runs
+type KeywordRequest struct {
+	Keyword string `json:"keyword"`
+}
+
+// handleAddKeyword adds a keyword the parser missed to a job profile for the caller's
+// runs, where it counts for more than parsed keywords. Responds with the caller's keywords.
+func (s *Server) handleAddKeyword(w http.ResponseWriter, r *http.Request) {
+	profileID, callerID, ok := s.keywordProfile(w, r)
+	if !ok {
+		return
+	}
+
+	var req KeywordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	keyword := strings.TrimSpace(req.Keyword)
+	if keyword == "" {
+		s.errorResponse(w, http.StatusBadRequest, "keyword is required")
+		return
+	}
+	if len(keyword) > maxKeywordLength {
+		s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("keyword must be at most %d characters", maxKeywordLength))
+		return
+	}
+
+	if err := s.db.AddUserKeyword(r.Context(), profileID, callerID, keyword); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.userKeywordsResponse(w, r, profileID, callerID)
+}
+
+// handleRemoveKeyword removes a keyword from a job profile for the caller's runs: one they
+// added is deleted, and a parsed one is hidden from their runs only. Responds with the
+// caller's keywords.
+func (s *Server) handleRemoveKeyword(w http.ResponseWriter, r *http.Request) {
+	profileID, callerID, ok := s.keywordProfile(w, r)
+	if !ok {
+		return
+	}
+
+	removed, err := s.db.RemoveUserKeyword(r.Context(), profileID, callerID, r.PathValue("keyword"))
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if !removed {
+		s.errorResponse(w, http.StatusNotFound, "Keyword not found")
+		return
+	}
+	s.userKeywordsResponse(w, r, profileID, callerID)
+}
+
+// keywordProfile reads the job profile a keyword request is for and the caller, writing
+// an error response if either is missing
+func (s *Server) keywordProfile(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	profileID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid job profile ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	profile, err := s.db.GetJobProfileByID(r.Context(), profileID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	if profile == nil {
+		s.errorResponse(w, http.StatusNotFound, "Job profile not found")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return profileID, callerID, true
+}
+
+// userKeywordsResponse writes a job profile's keywords as the caller sees them
+func (s *Server) userKeywordsResponse(w http.ResponseWriter, r *http.Request, profileID, callerID uuid.UUID) {
+	keywords, err := s.db.GetUserKeywords(r.Context(), profileID, callerID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, map[string]any{
+		"keywords": keywords,
+		"count":    len(keywords),
+	})
+}
@@ -4,10 +4,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
 )
 
 // TestHandleGetJobProfile_InvalidID tests get job profile with invalid UUID
@@ -112,3 +116,103 @@ func TestHandleGetKeywords_InvalidID(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, resp["error"], "Invalid job profile ID")
 }
+
+func TestHandleUpdateRequirementWeight(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	reqID := uuid.New()
+	s.mock.requirements = map[uuid.UUID]*db.JobRequirement{
+		reqID: {ID: reqID, JobProfileID: uuid.New(), RequirementType: db.RequirementTypeNiceToHave, Skill: "Kafka"},
+	}
+	pattern := "PATCH /v1/job-requirements/{id}"
+	target := "/v1/job-requirements/" + reqID.String()
+
+	w := serveAuthed(t, s, pattern, s.handleUpdateRequirementWeight,
+		bearerRequest(t, s, http.MethodPatch, target, user, []byte(`{"weight":2.5}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var requirement db.JobRequirement
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &requirement))
+	require.NotNil(t, requirement.Weight)
+	assert.Equal(t, 2.5, *requirement.Weight)
+	assert.Equal(t, 2.5, s.mock.reqWeights[user.String()+":"+reqID.String()])
+
+	// Weights are per user
+	other, err := s.mock.GetJobRequirement(t.Context(), reqID, uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, other.Weight)
+
+	w = serveAuthed(t, s, pattern, s.handleUpdateRequirementWeight,
+		bearerRequest(t, s, http.MethodPatch, target, user, []byte(`{"weight":null}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, s.mock.reqWeights, "a null weight restores the default")
+
+	for _, body := range []string{`{"weight":-1}`, `{"weight":3.5}`, `not json`} {
+		w = serveAuthed(t, s, pattern, s.handleUpdateRequirementWeight,
+			bearerRequest(t, s, http.MethodPatch, target, user, []byte(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	w = serveAuthed(t, s, pattern, s.handleUpdateRequirementWeight,
+		bearerRequest(t, s, http.MethodPatch, "/v1/job-requirements/"+uuid.New().String(), user, []byte(`{"weight":1}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req := httptest.NewRequest(http.MethodPatch, target, nil)
+	w = serveAuthed(t, s, pattern, s.handleUpdateRequirementWeight, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandleAddAndRemoveKeyword(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	profileID := uuid.New()
+	s.mock.jobProfiles = map[uuid.UUID]*db.JobProfile{
+		profileID: {ID: profileID, Keywords: []string{"Agile", "Kafka"}},
+	}
+	base := "/v1/job-profiles/" + profileID.String() + "/keywords"
+	addPattern := "POST /v1/job-profiles/{id}/keywords"
+	removePattern := "DELETE /v1/job-profiles/{id}/keywords/{keyword}"
+	keywordsIn := func(w *httptest.ResponseRecorder) map[string]string {
+		var resp struct {
+			Keywords []db.JobKeyword `json:"keywords"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		sources := make(map[string]string)
+		for _, kw := range resp.Keywords {
+			sources[kw.Keyword] = *kw.Source
+		}
+		return sources
+	}
+
+	w := serveAuthed(t, s, addPattern, s.handleAddKeyword,
+		bearerRequest(t, s, http.MethodPost, base, user, []byte(`{"keyword":" Terraform "}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]string{"Agile": "llm", "Kafka": "llm", "Terraform": "user"}, keywordsIn(w))
+
+	w = serveAuthed(t, s, removePattern, s.handleRemoveKeyword,
+		bearerRequest(t, s, http.MethodDelete, base+"/agile", user, nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]string{"Kafka": "llm", "Terraform": "user"}, keywordsIn(w))
+
+	w = serveAuthed(t, s, removePattern, s.handleRemoveKeyword,
+		bearerRequest(t, s, http.MethodDelete, base+"/terraform", user, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, map[string]string{"Kafka": "llm"}, keywordsIn(w))
+
+	// Other users still see the parsed keywords
+	others, err := s.mock.GetUserKeywords(t.Context(), profileID, uuid.New())
+	require.NoError(t, err)
+	assert.Len(t, others, 2)
+
+	for _, body := range []string{`{"keyword":"  "}`, `{"keyword":"` + strings.Repeat("x", 101) + `"}`, `not json`} {
+		w = serveAuthed(t, s, addPattern, s.handleAddKeyword, bearerRequest(t, s, http.MethodPost, base, user, []byte(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+	w = serveAuthed(t, s, removePattern, s.handleRemoveKeyword,
+		bearerRequest(t, s, http.MethodDelete, base+"/agile", user, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code, "already hidden")
+	w = serveAuthed(t, s, addPattern, s.handleAddKeyword,
+		bearerRequest(t, s, http.MethodPost, "/v1/job-profiles/"+uuid.New().String()+"/keywords", user, []byte(`{"keyword":"Go"}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serveAuthed(t, s, addPattern, s.handleAddKeyword, httptest.NewRequest(http.MethodPost, base, nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
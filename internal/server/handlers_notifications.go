@@ -0,0 +1,242 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/deadletter"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+	"github.com/jonathan/resume-customizer/internal/server/middleware"
+)
+
+// notificationCheckInterval is how often the server looks for due digests, reports, and reminders
+const notificationCheckInterval = time.Hour
+
+// NotificationPreferenceRequest is the request body for setting a notification preference
+type NotificationPreferenceRequest struct {
+	EventType  string `json:"event_type"` // run_completed, weekly_digest, application_reminder, or resume_health
+	Channel    string `json:"channel"`    // email, webhook, or none
+	WebhookURL string `json:"webhook_url,omitempty"`
+}
+
+// NotificationPreferencesResponse is the response for listing notification preferences
+type NotificationPreferencesResponse struct {
+	Preferences []db.NotificationPreference `json:"preferences"`
+}
+
+// pathUserIsCaller resolves the path user, who must be the caller; resource names
+// what is being managed in the forbidden message
+func (s *Server) pathUserIsCaller(w http.ResponseWriter, r *http.Request, resource string) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return uuid.Nil, false
+	}
+
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return uuid.Nil, false
+	}
+	if callerID != userID {
+		s.errorResponse(w, http.StatusForbidden, "You can only manage your own "+resource)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// handleListNotificationPreferences returns the user's preference for every event type
+func (s *Server) handleListNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "notification preferences")
+	if !ok {
+		return
+	}
+
+	stored, err := s.db.ListNotificationPreferences(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, NotificationPreferencesResponse{
+		Preferences: db.DefaultNotificationPreferences(userID, stored),
+	})
+}
+
+// handleUpdateNotificationPreference sets how the user is notified about one event type
+func (s *Server) handleUpdateNotificationPreference(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "notification preferences")
+	if !ok {
+		return
+	}
+
+	var req NotificationPreferenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	input := &db.NotificationPreferenceInput{
+		UserID:     userID,
+		EventType:  req.EventType,
+		Channel:    req.Channel,
+		WebhookURL: req.WebhookURL,
+	}
+	if err := db.ValidateNotificationPreference(input); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if input.Channel == db.NotificationWebhook {
+		if err := s.notifier.CheckWebhookURL(r.Context(), input.WebhookURL); err != nil {
+			s.errorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if input.Channel == db.NotificationEmail && !s.notify.EmailEnabled() {
+		s.errorResponse(w, http.StatusBadRequest, "Email notifications are not configured on this server")
+		return
+	}
+
+	pref, err := s.db.UpsertNotificationPreference(r.Context(), input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, pref)
+}
+
+// runScheduledNotifications periodically sends due activity digests, resume health
+// reports, and application reminders
+func (s *Server) runScheduledNotifications(ctx context.Context) {
+	if s.notify == nil {
+		return
+	}
+
+	ticker := time.NewTicker(notificationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.sendDueDigests(ctx)
+		s.sendDueHealthReports(ctx)
+		s.sendDueReminders(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendDueDigests performs a single digest pass
+func (s *Server) sendDueDigests(ctx context.Context) {
+	interval := s.notify.DigestInterval()
+	recipients, err := s.db.ListDueDigestRecipients(ctx, db.NotificationWeeklyDigest, interval)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list digest recipients", "error", err)
+		return
+	}
+
+	for i := range recipients {
+		recipient := &recipients[i]
+		now := time.Now()
+		since := now.Add(-interval)
+		if recipient.LastSentAt != nil {
+			since = *recipient.LastSentAt
+		}
+
+		// Claim before sending so replicas never deliver the same digest twice
+		claimed, err := s.db.ClaimDigest(ctx, db.NotificationWeeklyDigest, recipient.UserID, interval, now)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to claim digest", logging.KeyUserID, recipient.UserID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		stats, err := s.db.GetDigestStats(ctx, recipient.UserID, since)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load digest stats", logging.KeyUserID, recipient.UserID, "error", err)
+			continue
+		}
+		msg := notifications.DigestMessage(recipient.Name, stats, recipient.Location())
+		if err := s.notifier.Send(ctx, recipient, msg); err != nil {
+			slog.WarnContext(ctx, "failed to send digest", logging.KeyUserID, recipient.UserID, "error", err)
+			key := fmt.Sprintf("%s:%s:%s", db.NotificationWeeklyDigest, recipient.UserID, now.UTC().Format(time.DateOnly))
+			s.recordDeadLetter(ctx, deadletter.Notification(recipient, key, nil, msg, err))
+		}
+	}
+}
+
+// runCompletionNotifier notifies run owners who opted in when their runs complete
+func (s *Server) runCompletionNotifier(ctx context.Context) {
+	if s.notify == nil || s.events == nil {
+		return
+	}
+
+	sub := s.events.Subscribe(uuid.Nil)
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if event.Type == db.RunEventRunCompleted {
+				s.notifyRunCompleted(ctx, event)
+			}
+		}
+	}
+}
+
+// notifyRunCompleted sends the run_completed notification for one event
+func (s *Server) notifyRunCompleted(ctx context.Context, event db.RunEvent) {
+	run, err := s.db.GetRun(ctx, event.RunID)
+	if err != nil || run == nil || run.UserID == nil {
+		return
+	}
+
+	ctx = logging.WithUserID(logging.WithRunID(ctx, run.ID), *run.UserID)
+	recipient, err := s.db.GetNotificationRecipient(ctx, *run.UserID, db.NotificationRunCompleted)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load notification recipient", "error", err)
+		return
+	}
+	if recipient == nil {
+		return
+	}
+
+	// Every replica receives run events; only the one that claims the run sends
+	claimed, err := s.db.ClaimNotification(ctx, db.NotificationRunCompleted, run.ID, recipient.UserID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to claim run completed notification", "error", err)
+	}
+	if !claimed {
+		return
+	}
+
+	msg := notifications.RunCompletedMessage(run)
+	if err := s.notifier.Send(ctx, recipient, msg); err != nil {
+		slog.WarnContext(ctx, "failed to notify user of run completion", "error", err)
+		s.recordDeadLetter(ctx, deadletter.Notification(recipient, db.NotificationRunCompleted+":"+run.ID.String(), &run.ID, msg, err))
+	}
+}
+
+// recordDeadLetter keeps background work that failed for an operator to retry;
+// failures to record are only logged
+func (s *Server) recordDeadLetter(ctx context.Context, input *db.DeadLetterInput) {
+	if _, err := s.db.RecordDeadLetter(ctx, input); err != nil {
+		slog.WarnContext(ctx, "failed to record dead letter", "error", err)
+	}
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+)
+
+func TestHandleNotificationPreferences(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	target := "/v1/users/" + user.String() + "/notification-preferences"
+
+	w := serveAuthed(t, s, "PUT /v1/users/{id}/notification-preferences", s.handleUpdateNotificationPreference,
+		bearerRequest(t, s, http.MethodPut, target, user,
+			[]byte(`{"event_type":"run_completed","channel":"webhook","webhook_url":"https://hooks.example.com/resume"}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/notification-preferences", s.handleListNotificationPreferences,
+		bearerRequest(t, s, http.MethodGet, target, user, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp NotificationPreferencesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Preferences, len(db.NotificationEventTypes), "one entry per event type")
+	assert.Equal(t, db.NotificationWebhook, resp.Preferences[0].Channel)
+	assert.Equal(t, db.NotificationWeeklyDigest, resp.Preferences[1].EventType)
+	assert.Equal(t, db.NotificationNone, resp.Preferences[1].Channel)
+
+	// Another user cannot read or change these preferences
+	w = serveAuthed(t, s, "GET /v1/users/{id}/notification-preferences", s.handleListNotificationPreferences,
+		bearerRequest(t, s, http.MethodGet, target, uuid.New(), nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestHandleUpdateNotificationPreference_Invalid(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	target := "/v1/users/" + user.String() + "/notification-preferences"
+
+	for _, body := range []string{
+		`{"event_type":"run_failed","channel":"email"}`,
+		`{"event_type":"weekly_digest","channel":"sms"}`,
+		`{"event_type":"weekly_digest","channel":"webhook"}`,
+		`{"event_type":"weekly_digest","channel":"email"}`, // SMTP is not configured
+		`not json`,
+	} {
+		w := serveAuthed(t, s, "PUT /v1/users/{id}/notification-preferences", s.handleUpdateNotificationPreference,
+			bearerRequest(t, s, http.MethodPut, target, user, []byte(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestHandleUpdateNotificationPreference_PrivateWebhook(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	s.notifier = notifications.New(&config.NotificationConfig{})
+	target := "/v1/users/" + user.String() + "/notification-preferences"
+
+	for _, hook := range []string{"https://127.0.0.1/hook", "https://169.254.169.254/latest", "http://hooks.example.com/x"} {
+		body := []byte(`{"event_type":"run_completed","channel":"webhook","webhook_url":"` + hook + `"}`)
+		w := serveAuthed(t, s, "PUT /v1/users/{id}/notification-preferences", s.handleUpdateNotificationPreference,
+			bearerRequest(t, s, http.MethodPut, target, user, body))
+		assert.Equal(t, http.StatusBadRequest, w.Code, hook)
+	}
+}
+
+func TestNotifyRunCompleted(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	rec := &webhookRecorder{}
+	url := rec.server(t).URL
+
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Company: "Acme", RoleTitle: "SRE", Status: "completed", UserID: &user}
+	s.mock.notifyPrefs = []db.NotificationPreference{
+		{UserID: user, EventType: db.NotificationRunCompleted, Channel: db.NotificationWebhook, WebhookURL: &url},
+	}
+
+	event := db.RunEvent{Type: db.RunEventRunCompleted, RunID: runID, Status: "completed"}
+	s.notifyRunCompleted(context.Background(), event)
+	s.notifyRunCompleted(context.Background(), event) // Delivered by another replica
+
+	require.Len(t, rec.messages, 1, "each run is notified once")
+	assert.Equal(t, db.NotificationRunCompleted, rec.messages[0].Event)
+	assert.Contains(t, rec.messages[0].Subject, "SRE at Acme")
+	assert.Empty(t, s.mock.deadLetters)
+}
+
+func TestNotifyRunCompleted_FailureKept(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(hook.Close)
+
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Company: "Acme", RoleTitle: "SRE", Status: "completed", UserID: &user}
+	s.mock.notifyPrefs = []db.NotificationPreference{
+		{UserID: user, EventType: db.NotificationRunCompleted, Channel: db.NotificationWebhook, WebhookURL: &hook.URL},
+	}
+
+	s.notifyRunCompleted(context.Background(), db.RunEvent{Type: db.RunEventRunCompleted, RunID: runID, Status: "completed"})
+
+	require.Len(t, s.mock.deadLetters, 1, "failed deliveries are kept for an operator to resend")
+	letter := s.mock.deadLetters[0]
+	assert.Equal(t, db.DeadLetterWebhook, letter.Source)
+	assert.Equal(t, db.NotificationRunCompleted+":"+runID.String(), letter.RefKey)
+	assert.Contains(t, letter.ErrorMessage, "status 502")
+}
+
+func TestSendDueDigests(t *testing.T) {
+	due := uuid.New()
+	recent := uuid.New()
+	s := newNotificationTestServer(t)
+	rec := &webhookRecorder{}
+	url := rec.server(t).URL
+
+	sentYesterday := time.Now().Add(-24 * time.Hour)
+	s.mock.notifyPrefs = []db.NotificationPreference{
+		{UserID: due, EventType: db.NotificationWeeklyDigest, Channel: db.NotificationWebhook, WebhookURL: &url},
+		{UserID: recent, EventType: db.NotificationWeeklyDigest, Channel: db.NotificationWebhook, WebhookURL: &url,
+			LastSentAt: &sentYesterday},
+	}
+
+	s.sendDueDigests(context.Background())
+	s.sendDueDigests(context.Background())
+
+	require.Len(t, rec.messages, 1, "only the due user receives a digest, once")
+	assert.Equal(t, db.NotificationWeeklyDigest, rec.messages[0].Event)
+	assert.NotNil(t, s.mock.notifyPrefs[0].LastSentAt)
+}
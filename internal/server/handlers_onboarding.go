@@ -0,0 +1,235 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/onboarding"
+)
+
+// OnboardingResponse is a user's progress through the first-run wizard
+type OnboardingResponse struct {
+	State       string                  `json:"state"`
+	Completed   bool                    `json:"completed"`
+	Steps       []onboarding.StepStatus `json:"steps"`
+	Blocker     string                  `json:"blocker,omitempty"` // What the user must do before advancing
+	UpdatedAt   *time.Time              `json:"updated_at,omitempty"`
+	CompletedAt *time.Time              `json:"completed_at,omitempty"`
+}
+
+// OnboardingTransitionRequest is the optional body of a wizard transition
+type OnboardingTransitionRequest struct {
+	From string `json:"from,omitempty"` // Expected current state; the transition fails if it moved
+}
+
+// handleGetOnboarding returns the caller's onboarding state and what is needed to advance
+func (s *Server) handleGetOnboarding(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "onboarding")
+	if !ok {
+		return
+	}
+
+	progress, err := s.db.GetOnboarding(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	resp, err := s.onboardingResponse(r.Context(), userID, progress)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
+
+// handleAdvanceOnboarding finishes the caller's current step once its requirement is met
+func (s *Server) handleAdvanceOnboarding(w http.ResponseWriter, r *http.Request) {
+	s.transitionOnboarding(w, r, func(ctx context.Context, userID uuid.UUID, state string) (string, bool, string, error) {
+		next, ok := onboarding.Next(state)
+		if !ok {
+			return "", false, "Onboarding is already completed", nil
+		}
+		blocker, err := s.onboardingBlocker(ctx, userID, state)
+		if err != nil || blocker != "" {
+			return "", false, blocker, err
+		}
+		return next, false, "", nil
+	})
+}
+
+// handleSkipOnboarding passes over the caller's current step if it is optional
+func (s *Server) handleSkipOnboarding(w http.ResponseWriter, r *http.Request) {
+	s.transitionOnboarding(w, r, func(_ context.Context, _ uuid.UUID, state string) (string, bool, string, error) {
+		if !onboarding.Skippable(state) {
+			return "", false, "This step cannot be skipped", nil
+		}
+		next, _ := onboarding.Next(state)
+		return next, true, "", nil
+	})
+}
+
+// handleBackOnboarding returns the caller to the previous step
+func (s *Server) handleBackOnboarding(w http.ResponseWriter, r *http.Request) {
+	s.transitionOnboarding(w, r, func(_ context.Context, _ uuid.UUID, state string) (string, bool, string, error) {
+		prev, ok := onboarding.Prev(state)
+		if !ok {
+			return "", false, "There is no previous step", nil
+		}
+		return prev, false, "", nil
+	})
+}
+
+// onboardingStep decides a transition from state: the new state and whether state is
+// being skipped, or a reason the transition is not allowed
+type onboardingStep func(ctx context.Context, userID uuid.UUID, state string) (to string, skip bool, refused string, err error)
+
+// transitionOnboarding applies step to the caller's onboarding state. Refused transitions
+// and states changed by another request are reported as 409.
+func (s *Server) transitionOnboarding(w http.ResponseWriter, r *http.Request, step onboardingStep) {
+	userID, ok := s.pathUserIsCaller(w, r, "onboarding")
+	if !ok {
+		return
+	}
+
+	var req OnboardingTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.From != "" && !onboarding.Valid(req.From) {
+		s.errorResponse(w, http.StatusBadRequest, "from must be an onboarding state")
+		return
+	}
+
+	progress, err := s.db.GetOnboarding(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	state, skipped := onboarding.Initial, []string{}
+	if progress != nil {
+		state, skipped = progress.State, progress.Skipped
+	}
+	if req.From != "" && req.From != state {
+		s.errorResponse(w, http.StatusConflict, "Onboarding is at "+state+", not "+req.From)
+		return
+	}
+
+	to, skip, refused, err := step(r.Context(), userID, state)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if refused != "" {
+		s.errorResponse(w, http.StatusConflict, refused)
+		return
+	}
+
+	// Going back past a skipped step gives the user another chance to finish it
+	kept := make([]string, 0, len(skipped)+1)
+	for _, name := range skipped {
+		if name != to {
+			kept = append(kept, name)
+		}
+	}
+	if skip {
+		kept = append(kept, state)
+	}
+
+	progress, err = s.db.TransitionOnboarding(r.Context(), userID, state, to, kept, to == onboarding.StateCompleted)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if progress == nil {
+		s.errorResponse(w, http.StatusConflict, "Onboarding changed; reload and try again")
+		return
+	}
+	resp, err := s.onboardingResponse(r.Context(), userID, progress)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
+
+// onboardingResponse describes progress, which is nil for a user who has not started
+func (s *Server) onboardingResponse(ctx context.Context, userID uuid.UUID, progress *db.Onboarding) (*OnboardingResponse, error) {
+	resp := &OnboardingResponse{State: onboarding.Initial}
+	var skipped []string
+	if progress != nil {
+		resp.State = progress.State
+		resp.UpdatedAt = &progress.UpdatedAt
+		resp.CompletedAt = progress.CompletedAt
+		skipped = progress.Skipped
+	}
+	resp.Completed = resp.State == onboarding.StateCompleted
+	resp.Steps = onboarding.Progress(resp.State, skipped)
+
+	blocker, err := s.onboardingBlocker(ctx, userID, resp.State)
+	if err != nil {
+		return nil, err
+	}
+	resp.Blocker = blocker
+	return resp, nil
+}
+
+// onboardingBlocker returns what the user still has to do to finish the step, or ""
+// if they may advance
+func (s *Server) onboardingBlocker(ctx context.Context, userID uuid.UUID, state string) (string, error) {
+	switch state {
+	case onboarding.StepUploadResume:
+		jobs, err := s.db.ListJobs(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		if len(jobs) == 0 {
+			return "Upload a resume or add a job first", nil
+		}
+		return "", nil
+
+	case onboarding.StepConfirmBank:
+		jobs, err := s.db.ListJobs(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		for _, job := range jobs {
+			exps, err := s.db.ListExperiences(ctx, job.ID)
+			if err != nil {
+				return "", err
+			}
+			if len(exps) > 0 {
+				return "", nil
+			}
+		}
+		return "Add at least one bullet to your experience bank first", nil
+
+	case onboarding.StepContactInfo:
+		user, err := s.db.GetUser(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		if user == nil || strings.TrimSpace(user.Name) == "" || strings.TrimSpace(user.Email) == "" {
+			return "Set your name and email first", nil
+		}
+		return "", nil
+
+	case onboarding.StepSampleRun:
+		runs, err := s.db.ListRunsFiltered(ctx, db.RunFilters{UserID: &userID, Status: "completed", Limit: 1})
+		if err != nil {
+			return "", err
+		}
+		if len(runs) == 0 {
+			return "Finish a run first, or skip this step", nil
+		}
+		return "", nil
+	}
+	return "", nil
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/onboarding"
+)
+
+func TestOnboardingWizard(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	base := "/v1/users/" + owner.String() + "/onboarding"
+
+	get := func() OnboardingResponse {
+		t.Helper()
+		w := serveAuthed(t, s, "GET /v1/users/{id}/onboarding", s.handleGetOnboarding,
+			bearerRequest(t, s, http.MethodGet, base, owner, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp OnboardingResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+	transition := func(action string, handler http.HandlerFunc, body string) int {
+		t.Helper()
+		var b []byte
+		if body != "" {
+			b = []byte(body)
+		}
+		w := serveAuthed(t, s, "POST /v1/users/{id}/onboarding/"+action, handler,
+			bearerRequest(t, s, http.MethodPost, base+"/"+action, owner, b))
+		return w.Code
+	}
+
+	resp := get()
+	assert.Equal(t, onboarding.StepUploadResume, resp.State)
+	assert.Equal(t, "Upload a resume or add a job first", resp.Blocker)
+	assert.Equal(t, onboarding.StatusCurrent, resp.Steps[0].Status)
+
+	// Steps cannot be finished before their requirement is met, or skipped unless optional
+	assert.Equal(t, http.StatusConflict, transition("advance", s.handleAdvanceOnboarding, ""))
+	assert.Equal(t, http.StatusConflict, transition("skip", s.handleSkipOnboarding, ""))
+	assert.Equal(t, http.StatusConflict, transition("back", s.handleBackOnboarding, ""))
+
+	jobID := uuid.New()
+	s.mock.jobs = []db.Job{{ID: jobID, UserID: owner, Company: "Acme"}}
+	assert.Equal(t, http.StatusOK, transition("advance", s.handleAdvanceOnboarding, `{"from":"upload_resume"}`))
+	assert.Equal(t, onboarding.StepConfirmBank, get().State)
+
+	// A stale client is told the state moved on
+	assert.Equal(t, http.StatusConflict, transition("advance", s.handleAdvanceOnboarding, `{"from":"upload_resume"}`))
+	assert.Equal(t, http.StatusBadRequest, transition("advance", s.handleAdvanceOnboarding, `{"from":"bogus"}`))
+
+	assert.Equal(t, http.StatusConflict, transition("advance", s.handleAdvanceOnboarding, ""), "no bullets yet")
+	s.mock.experiences = []db.Experience{{ID: uuid.New(), JobID: jobID, BulletText: "Shipped it"}}
+	assert.Equal(t, http.StatusOK, transition("advance", s.handleAdvanceOnboarding, ""))
+
+	s.mock.users = map[uuid.UUID]*db.User{owner: {ID: owner, Email: "jane@example.com"}}
+	assert.Equal(t, "Set your name and email first", get().Blocker)
+	s.mock.users[owner].Name = "Jane Doe"
+	assert.Equal(t, http.StatusOK, transition("advance", s.handleAdvanceOnboarding, ""))
+
+	// The sample run is optional
+	assert.Equal(t, onboarding.StepSampleRun, get().State)
+	assert.Equal(t, http.StatusOK, transition("skip", s.handleSkipOnboarding, ""))
+
+	resp = get()
+	assert.True(t, resp.Completed)
+	assert.NotNil(t, resp.CompletedAt)
+	assert.Equal(t, onboarding.StatusSkipped, resp.Steps[3].Status)
+	assert.Equal(t, http.StatusConflict, transition("advance", s.handleAdvanceOnboarding, ""))
+	assert.Equal(t, http.StatusConflict, transition("back", s.handleBackOnboarding, ""))
+}
+
+func TestOnboardingWizard_Forbidden(t *testing.T) {
+	s := newAuthTestServer(t)
+	w := serveAuthed(t, s, "GET /v1/users/{id}/onboarding", s.handleGetOnboarding,
+		bearerRequest(t, s, http.MethodGet, "/v1/users/"+uuid.New().String()+"/onboarding", uuid.New(), nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
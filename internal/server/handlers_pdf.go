@@ -0,0 +1,89 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/compile"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/logging"
+)
+
+// handleRunPDF serves a run's compiled resume as a PDF download
+func (s *Server) handleRunPDF(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID format")
+		return
+	}
+	s.serveResumePDF(w, r, runID, nil)
+}
+
+// serveResumePDF serves a run's resume_pdf artifact, streaming it from the database so
+// clients can fetch it in ranges. Runs without one, such as those executed step by step
+// or on a server without a LaTeX compiler, have their resume_tex compiled and the result
+// stored. served, when set, is called once the PDF is found, before it is written.
+func (s *Server) serveResumePDF(w http.ResponseWriter, r *http.Request, runID uuid.UUID, served func()) {
+	content, err := s.db.OpenRunArtifactContent(r.Context(), runID, db.StepResumePDF)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if content == nil || content.Size == 0 {
+		pdf, err := s.compileResumePDF(r.Context(), runID)
+		if err != nil {
+			s.pdfErrorResponse(w, err)
+			return
+		}
+		if pdf == nil {
+			s.errorResponse(w, http.StatusNotFound, "resume.pdf not found for this run")
+			return
+		}
+		content = &db.ArtifactContent{RunID: runID, Step: db.StepResumePDF, Format: db.ContentFormatBinary,
+			Size: int64(len(pdf)), CreatedAt: time.Now(), SectionReader: io.NewSectionReader(bytes.NewReader(pdf), 0, int64(len(pdf)))}
+	}
+
+	if served != nil {
+		served()
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `attachment; filename="resume.pdf"`)
+	serveArtifactContent(w, r, content)
+}
+
+// compileResumePDF compiles a run's resume_tex and stores the result as its resume_pdf
+// artifact. Returns nil if the run has no resume.
+func (s *Server) compileResumePDF(ctx context.Context, runID uuid.UUID) ([]byte, error) {
+	tex, err := s.db.GetTextArtifact(ctx, runID, db.StepResumeTex)
+	if err != nil || tex == "" {
+		return nil, err
+	}
+	result, err := s.compilePDF(ctx, tex)
+	if result == nil {
+		return nil, err
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "resume PDF compiled with errors", logging.KeyRunID, runID, "error", err)
+	}
+	if err := s.db.SaveBinaryArtifact(ctx, runID, db.StepResumePDF, db.CategoryValidation, result.PDF); err != nil {
+		slog.WarnContext(ctx, "failed to save resume PDF", logging.KeyRunID, runID, "error", err)
+	}
+	return result.PDF, nil
+}
+
+// pdfErrorResponse reports a loadResumePDF failure, answering 503 when the resume
+// could not be compiled
+func (s *Server) pdfErrorResponse(w http.ResponseWriter, err error) {
+	var compileErr *compile.Error
+	if errors.As(err, &compileErr) {
+		s.errorResponse(w, http.StatusServiceUnavailable, "PDF is not available: "+err.Error())
+		return
+	}
+	s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+}
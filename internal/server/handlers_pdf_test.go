@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/compile"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/toolchain"
+)
+
+func serveRunPDF(s *testServer, runID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/"+runID+"/artifacts/pdf", nil)
+	req.SetPathValue("id", runID)
+	w := httptest.NewRecorder()
+	s.handleRunPDF(w, req)
+	return w
+}
+
+func TestHandleRunPDF_Stored(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.binArtifacts = map[string][]byte{runID.String() + ":" + db.StepResumePDF: []byte("%PDF-1.5 stored")}
+
+	w := serveRunPDF(s, runID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "resume.pdf")
+	assert.Equal(t, "%PDF-1.5 stored", w.Body.String())
+	assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/"+runID.String()+"/artifacts/pdf", nil)
+	req.SetPathValue("id", runID.String())
+	req.Header.Set("Range", "bytes=9-")
+	w = httptest.NewRecorder()
+	s.handleRunPDF(w, req)
+	require.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "bytes 9-14/15", w.Header().Get("Content-Range"))
+	assert.Equal(t, "stored", w.Body.String())
+}
+
+func TestHandleRunPDF_CompilesAndStores(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.textArtifacts[runID.String()+":"+db.StepResumeTex] = `\documentclass{article}`
+
+	w := serveRunPDF(s, runID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `%PDF-1.5 \documentclass{article}`, w.Body.String())
+	assert.Equal(t, w.Body.Bytes(), s.mock.binArtifacts[runID.String()+":"+db.StepResumePDF])
+}
+
+func TestHandleRunPDF_Errors(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+
+	assert.Equal(t, http.StatusBadRequest, serveRunPDF(s, "not-a-uuid").Code)
+	assert.Equal(t, http.StatusNotFound, serveRunPDF(s, runID.String()).Code)
+
+	s.mock.textArtifacts[runID.String()+":"+db.StepResumeTex] = `\documentclass{article}`
+	s.compilePDF = func(context.Context, string) (*compile.Result, error) {
+		return nil, &compile.Error{Message: "no LaTeX compiler found", Err: toolchain.ErrUnavailable}
+	}
+	w := serveRunPDF(s, runID.String())
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Empty(t, s.mock.binArtifacts)
+}
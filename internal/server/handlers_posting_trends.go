@@ -0,0 +1,109 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/titles"
+	"github.com/jonathan/resume-customizer/internal/trends"
+)
+
+// PostingVersionsResponse lists the archived versions of a posting
+type PostingVersionsResponse struct {
+	PostingID uuid.UUID           `json:"posting_id"`
+	Versions  []db.PostingVersion `json:"versions"`
+	Count     int                 `json:"count"`
+}
+
+// handleListPostingVersions returns the earlier versions of a posting that re-fetches
+// replaced, most recent first
+func (s *Server) handleListPostingVersions(w http.ResponseWriter, r *http.Request) {
+	postingID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid job posting ID")
+		return
+	}
+
+	posting, err := s.db.GetJobPostingByID(r.Context(), postingID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if posting == nil {
+		s.errorResponse(w, http.StatusNotFound, "Job posting not found")
+		return
+	}
+
+	versions, err := s.db.ListPostingVersions(r.Context(), postingID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if versions == nil {
+		versions = []db.PostingVersion{}
+	}
+	s.jsonResponse(w, http.StatusOK, PostingVersionsResponse{PostingID: postingID, Versions: versions, Count: len(versions)})
+}
+
+// PostingTrendsResponse is how the skills and keywords in a company's postings have
+// changed over time
+type PostingTrendsResponse struct {
+	CompanyID  uuid.UUID `json:"company_id"`
+	RoleFamily string    `json:"role_family,omitempty"`
+	Versions   int       `json:"versions"` // Posting versions counted, current and archived
+	*trends.Report
+}
+
+// handleGetPostingTrends reports how often each skill and keyword has appeared in a
+// company's postings per month or quarter, counting archived versions of postings as of
+// when they were first seen. ?role_family limits the report to one role family; ?role
+// does the same for a job title, normalized to its family. ?timezone, an IANA name such
+// as the caller's timezone preference, sets where periods begin; it defaults to UTC.
+func (s *Server) handleGetPostingTrends(w http.ResponseWriter, r *http.Request) {
+	companyID, err := uuid.Parse(r.PathValue("company_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid company ID")
+		return
+	}
+	interval, err := trends.ParseInterval(r.URL.Query().Get("interval"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	loc, err := loadTimezone(r.URL.Query().Get("timezone"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	family := titles.Normalize(r.URL.Query().Get("role_family")).Family
+	if role := r.URL.Query().Get("role"); role != "" {
+		family = titles.Normalize(role).Family
+	}
+	limit := parseQueryInt(r, "limit", 20, 100)
+	if limit == 0 {
+		limit = 20
+	}
+
+	versions, err := s.db.ListCompanyPostingHistory(r.Context(), companyID, family)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	postings := make([]trends.Posting, 0, len(versions))
+	for _, v := range versions {
+		p := trends.Posting{SeenAt: v.FirstSeenAt, Keywords: v.Keywords}
+		for _, req := range v.Requirements {
+			p.Skills = append(p.Skills, req.Skill)
+		}
+		postings = append(postings, p)
+	}
+
+	s.jsonResponse(w, http.StatusOK, PostingTrendsResponse{
+		CompanyID:  companyID,
+		RoleFamily: family,
+		Versions:   len(versions),
+		Report:     trends.Analyze(postings, interval, loc, limit),
+	})
+}
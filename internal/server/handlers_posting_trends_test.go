@@ -0,0 +1,100 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestHandleGetPostingTrends(t *testing.T) {
+	s := newTestServer()
+	companyID := uuid.New()
+	postingID := uuid.New()
+	swe, pm := "software engineer", "product manager"
+	replaced := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	s.mock.versions = []db.PostingVersion{
+		{PostingID: postingID, CompanyID: &companyID, RoleFamily: &swe, FirstSeenAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+			SupersededAt: &replaced, Requirements: []db.VersionRequirement{{Type: "hard", Skill: "Java"}}},
+		{PostingID: postingID, CompanyID: &companyID, RoleFamily: &swe, FirstSeenAt: replaced,
+			Requirements: []db.VersionRequirement{{Type: "hard", Skill: "Go"}}, Keywords: []string{"kubernetes"}},
+		{PostingID: uuid.New(), CompanyID: &companyID, RoleFamily: &pm, FirstSeenAt: replaced,
+			Requirements: []db.VersionRequirement{{Type: "hard", Skill: "Roadmaps"}}},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/companies/"+companyID.String()+"/posting-trends?role=Senior+Software+Developer", nil)
+	req.SetPathValue("company_id", companyID.String())
+	w := httptest.NewRecorder()
+	s.handleGetPostingTrends(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp PostingTrendsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "software engineer", resp.RoleFamily)
+	assert.Equal(t, 2, resp.Versions)
+	assert.Equal(t, "quarter", string(resp.Interval))
+	require.Len(t, resp.Periods, 3)
+	assert.Equal(t, "2025-Q1", resp.Periods[0].Label)
+	require.Len(t, resp.Skills, 2)
+	assert.Equal(t, "Go", resp.Skills[0].Term)
+	assert.Equal(t, "rising", resp.Skills[0].Trend)
+	assert.Equal(t, "falling", resp.Skills[1].Trend)
+	require.Len(t, resp.Keywords, 1)
+
+	assert.Equal(t, "UTC", resp.Timezone)
+
+	// Periods follow the requested time zone: July 1 at midnight UTC is still June in Los Angeles
+	req = httptest.NewRequest(http.MethodGet, "/v1/companies/"+companyID.String()+"/posting-trends?role=software+engineer&timezone=America/Los_Angeles", nil)
+	req.SetPathValue("company_id", companyID.String())
+	w = httptest.NewRecorder()
+	s.handleGetPostingTrends(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	resp = PostingTrendsResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "America/Los_Angeles", resp.Timezone)
+	require.Len(t, resp.Periods, 2)
+	assert.Equal(t, []int{1, 1}, []int{resp.Periods[0].Postings, resp.Periods[1].Postings})
+	assert.Equal(t, "2025-Q2", resp.Periods[1].Label)
+
+	for _, query := range []string{"interval=week", "timezone=Mars/Olympus", "timezone=Local"} {
+		req = httptest.NewRequest(http.MethodGet, "/v1/companies/"+companyID.String()+"/posting-trends?"+query, nil)
+		req.SetPathValue("company_id", companyID.String())
+		w = httptest.NewRecorder()
+		s.handleGetPostingTrends(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestHandleListPostingVersions(t *testing.T) {
+	s := newTestServer()
+	postingID := uuid.New()
+	replaced := time.Now().Add(-time.Hour)
+	s.mock.postings = map[uuid.UUID]*db.JobPosting{postingID: {ID: postingID}}
+	s.mock.versions = []db.PostingVersion{
+		{PostingID: postingID, SupersededAt: &replaced},
+		{PostingID: postingID}, // current version
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/job-postings/"+postingID.String()+"/versions", nil)
+	req.SetPathValue("id", postingID.String())
+	w := httptest.NewRecorder()
+	s.handleListPostingVersions(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp PostingVersionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Count)
+
+	missing := uuid.New()
+	req = httptest.NewRequest(http.MethodGet, "/v1/job-postings/"+missing.String()+"/versions", nil)
+	req.SetPathValue("id", missing.String())
+	w = httptest.NewRecorder()
+	s.handleListPostingVersions(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// RunPreviewResponse holds HTML previews of a run's artifacts. Each is an escaped HTML
+// fragment, omitted until the run has produced the artifact it renders.
+type RunPreviewResponse struct {
+	RunID   string `json:"run_id"`
+	Plan    string `json:"plan_html,omitempty"`    // Selected stories by section with their bullets
+	Bullets string `json:"bullets_html,omitempty"` // Rewritten bullets with style checks
+	Resume  string `json:"resume_html,omitempty"`  // The rendered resume as a page
+	Tex     string `json:"tex_html,omitempty"`     // Syntax-highlighted resume.tex
+}
+
+// runPreviewPage is the standalone page served for ?format=html
+var runPreviewPage = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Run preview</title>
+<style>
+body { max-width: 860px; margin: 2rem auto; padding: 0 1rem; font-family: system-ui, sans-serif; color: #222; line-height: 1.4; }
+h2 { border-bottom: 1px solid #ddd; margin-top: 2rem; }
+.meta, .budget { color: #666; font-size: 0.85rem; }
+.pending { color: #999; font-style: italic; }
+.check { font-size: 0.75rem; padding: 0 0.3rem; border-radius: 3px; }
+.check.pass { background: #e3f4e7; color: #17702f; }
+.check.fail { background: #fbe7e6; color: #b3261e; }
+.resume { font-family: Georgia, serif; border: 1px solid #ddd; padding: 1rem 1.5rem; }
+.resume header { text-align: center; }
+.resume .row { display: flex; justify-content: space-between; gap: 1rem; }
+.tex { background: #f6f6f6; padding: 0.75rem; overflow: auto; font-size: 0.8rem; }
+.tex-command { color: #0b57d0; }
+.tex-group { color: #8a5a00; }
+.tex-comment { color: #777; font-style: italic; }
+.tex-math { color: #a0168a; }
+</style>
+</head>
+<body>
+{{if .Plan}}<h2>Resume plan</h2>
+{{.Plan}}{{end}}
+{{if .Bullets}}<h2>Rewritten bullets</h2>
+{{.Bullets}}{{end}}
+{{if .Resume}}<h2>Resume</h2>
+<div class="resume">{{.Resume}}</div>{{end}}
+{{if .Tex}}<h2>resume.tex</h2>
+{{.Tex}}{{end}}
+{{if not (or .Plan .Bullets .Resume .Tex)}}<p>This run has nothing to preview yet.</p>{{end}}
+</body>
+</html>
+`))
+
+// runPreviewCSP allows the preview page's inline styles and nothing else
+const runPreviewCSP = "default-src 'none'; style-src 'unsafe-inline'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+// handleRunPreview renders a run's resume plan, rewritten bullets, and resume as HTML.
+// The JSON response carries fragments for frontends to embed; ?format=html returns a
+// standalone page.
+func (s *Server) handleRunPreview(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID format")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "html" {
+		s.errorResponse(w, http.StatusBadRequest, "format must be json or html")
+		return
+	}
+
+	run, err := s.db.GetRun(r.Context(), runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if run == nil {
+		s.errorResponse(w, http.StatusNotFound, "Run not found")
+		return
+	}
+
+	var plan types.ResumePlan
+	hasPlan, err := s.loadArtifact(r, runID, db.StepResumePlan, &plan)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var bullets types.RewrittenBullets
+	hasBullets, err := s.loadArtifact(r, runID, db.StepRewrittenBullets, &bullets)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	tex, err := s.db.GetTextArtifact(r.Context(), runID, db.StepResumeTex)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	resp := RunPreviewResponse{RunID: runID.String()}
+	var rewritten *types.RewrittenBullets
+	if hasBullets {
+		rewritten = &bullets
+		resp.Bullets = rendering.BulletsHTML(rewritten)
+	}
+	if hasPlan {
+		resp.Plan = rendering.PlanHTML(&plan, rewritten)
+	}
+	if tex != "" {
+		resp.Resume = rendering.LaTeXToHTML(tex)
+		resp.Tex = rendering.HighlightLaTeX(tex)
+	}
+
+	if format != "html" {
+		s.jsonResponse(w, http.StatusOK, resp)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", runPreviewCSP)
+	w.WriteHeader(http.StatusOK)
+	_ = runPreviewPage.Execute(w, struct {
+		Plan, Bullets, Resume, Tex template.HTML
+	}{
+		// The fragments are built from escaped text by the rendering package
+		template.HTML(resp.Plan), template.HTML(resp.Bullets), template.HTML(resp.Resume), template.HTML(resp.Tex),
+	})
+}
+
+// loadArtifact decodes a run's JSON artifact for step into v, reporting whether it exists
+func (s *Server) loadArtifact(r *http.Request, runID uuid.UUID, step string, v any) (bool, error) {
+	content, err := s.db.GetArtifact(r.Context(), runID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s artifact: %w", step, err)
+	}
+	if content == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return false, fmt.Errorf("failed to parse %s artifact: %w", step, err)
+	}
+	return true, nil
+}
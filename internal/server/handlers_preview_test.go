@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func servePreview(s *testServer, runID uuid.UUID, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/"+runID.String()+"/preview"+query, nil)
+	req.SetPathValue("id", runID.String())
+	w := httptest.NewRecorder()
+	s.handleRunPreview(w, req)
+	return w
+}
+
+func TestHandleRunPreview(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Status: "completed"}
+	s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: db.StepResumePlan, Content: types.ResumePlan{
+		SelectedStories: []types.SelectedStory{{StoryID: "story_001", BulletIDs: []string{"bullet_001"}, Section: "experience"}},
+	}}
+
+	// Only the plan exists so far
+	w := servePreview(s, runID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp RunPreviewResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Plan, `<li class="pending">bullet_001</li>`)
+	assert.Empty(t, resp.Bullets)
+	assert.Empty(t, resp.Tex)
+
+	s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: db.StepRewrittenBullets, Content: types.RewrittenBullets{
+		Bullets: []types.RewrittenBullet{{OriginalBulletID: "bullet_001", FinalText: "Cut costs <30%>"}},
+	}}
+	s.mock.textArtifacts[runID.String()+":"+db.StepResumeTex] = "\\begin{document}\n\\section*{Experience}\n\\end{document}"
+
+	w = servePreview(s, runID, "")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Plan, "<li>Cut costs &lt;30%&gt;</li>")
+	assert.Contains(t, resp.Bullets, "Cut costs &lt;30%&gt;")
+	assert.Contains(t, resp.Resume, "<h2>Experience</h2>")
+	assert.Contains(t, resp.Tex, `<span class="tex-command">\section</span>`)
+
+	w = servePreview(s, runID, "?format=html")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, runPreviewCSP, w.Header().Get("Content-Security-Policy"))
+	assert.Contains(t, w.Body.String(), "<li>Cut costs &lt;30%&gt;</li>", "fragments are not escaped twice")
+
+	assert.Equal(t, http.StatusBadRequest, servePreview(s, runID, "?format=pdf").Code)
+	assert.Equal(t, http.StatusNotFound, servePreview(s, uuid.New(), "").Code)
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+	"github.com/jonathan/resume-customizer/internal/prompts"
+)
+
+// startPromptRollouts trials the configured prompt versions and alerts on rollbacks.
+// Rollouts are kept in store (nil keeps them in memory). Rollbacks are logged by the
+// prompts package and, when configured, posted to the alert webhook.
+func (s *Server) startPromptRollouts(ctx context.Context, cfg *config.PromptRolloutConfig, store prompts.RolloutStore) error {
+	for _, entry := range cfg.Rollouts {
+		file, versioned, _ := strings.Cut(entry, ":")
+		key, version, err := prompts.ParseVersionedKey(versioned)
+		if err != nil {
+			return fmt.Errorf("invalid PROMPT_ROLLOUTS entry %q: %w", entry, err)
+		}
+		rollout := prompts.RolloutConfig{Share: cfg.Share, Window: cfg.Window, Tolerance: cfg.Tolerance}
+		if err := prompts.StartRollout(ctx, store, file, key, version, rollout); err != nil {
+			return fmt.Errorf("invalid PROMPT_ROLLOUTS entry %q: %w", entry, err)
+		}
+	}
+	if len(cfg.Rollouts) == 0 || cfg.AlertWebhookURL == "" || s.notifier == nil {
+		return nil
+	}
+
+	recipient := &db.NotificationRecipient{Channel: db.NotificationWebhook, WebhookURL: &cfg.AlertWebhookURL}
+	prompts.OnRollback(func(status prompts.RolloutStatus) {
+		// Sent in the background so the call that decided the rollout isn't held up
+		go func() {
+			if err := s.notifier.Send(context.Background(), recipient, promptRollbackMessage(status)); err != nil {
+				slog.Warn("failed to send prompt rollback alert", "error", err)
+			}
+		}()
+	})
+	return nil
+}
+
+// promptRollbackMessage is the notification sent when a prompt rollout is rolled back
+func promptRollbackMessage(status prompts.RolloutStatus) notifications.Message {
+	return notifications.Message{
+		Event: "prompt_rollback",
+		Subject: fmt.Sprintf("Prompt %s rolled back from version %d to %d",
+			status.Key, status.Next.Version, status.Previous.Version),
+		Body: fmt.Sprintf("Version %d of %s in %s failed on %.0f%% of %d sampled responses, against %.0f%% for version %d. "+
+			"Every call now uses version %d. Review rollouts at GET /v1/admin/prompt-rollouts.",
+			status.Next.Version, status.Key, status.File, status.Next.FailureRate()*100, status.Next.Samples,
+			status.Previous.FailureRate()*100, status.Previous.Version, status.Previous.Version),
+		Data: map[string]any{"file": status.File, "key": status.Key, "previous": status.Previous, "next": status.Next},
+	}
+}
+
+// PromptRolloutResponse is a prompt rollout with its sampled failure rates
+type PromptRolloutResponse struct {
+	prompts.RolloutStatus
+	PreviousFailureRate float64 `json:"previous_failure_rate"`
+	NextFailureRate     float64 `json:"next_failure_rate"`
+}
+
+// handleListPromptRollouts lists prompt rollouts and how each is going to admins
+func (s *Server) handleListPromptRollouts(w http.ResponseWriter, r *http.Request) {
+	if !s.callerIsAdmin(w, r, "view prompt rollouts") {
+		return
+	}
+
+	statuses := prompts.Rollouts(r.Context())
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].File != statuses[j].File {
+			return statuses[i].File < statuses[j].File
+		}
+		return statuses[i].Key < statuses[j].Key
+	})
+	resp := make([]PromptRolloutResponse, 0, len(statuses))
+	for _, status := range statuses {
+		resp = append(resp, PromptRolloutResponse{
+			RolloutStatus:       status,
+			PreviousFailureRate: status.Previous.FailureRate(),
+			NextFailureRate:     status.Next.FailureRate(),
+		})
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/prompts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleListPromptRollouts(t *testing.T) {
+	admin := uuid.New()
+	s := newAuthTestServer(t, admin)
+	prompts.ResetRollouts()
+	t.Cleanup(prompts.ResetRollouts)
+
+	list := func(caller uuid.UUID) *http.Response {
+		w := serveAuthed(t, s, "GET /v1/admin/prompt-rollouts", s.handleListPromptRollouts,
+			bearerRequest(t, s, http.MethodGet, "/v1/admin/prompt-rollouts", caller, nil))
+		return w.Result()
+	}
+
+	resp := list(admin)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var rollouts []PromptRolloutResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&rollouts))
+	assert.Empty(t, rollouts)
+
+	assert.Equal(t, http.StatusForbidden, list(uuid.New()).StatusCode)
+}
+
+func TestStartPromptRollouts_UnknownVersion(t *testing.T) {
+	s := newTestServer()
+	prompts.ResetRollouts()
+	t.Cleanup(prompts.ResetRollouts)
+
+	err := s.startPromptRollouts(context.Background(), &config.PromptRolloutConfig{
+		Rollouts: []string{"parsing.json:extract-job-profile@v99"},
+		Share:    0.5,
+		Window:   10,
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "extract-job-profile@v99")
+	assert.Empty(t, prompts.Rollouts(context.Background()))
+}
+
+func TestStartPromptRollouts_PromptWithoutOutcomes(t *testing.T) {
+	s := newTestServer()
+	prompts.ResetRollouts()
+	t.Cleanup(prompts.ResetRollouts)
+
+	err := s.startPromptRollouts(context.Background(), &config.PromptRolloutConfig{
+		Rollouts: []string{"voice.json:extract-brand-voice@v2"},
+		Share:    0.5,
+		Window:   10,
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not report its outcomes")
+	assert.Empty(t, prompts.Rollouts(context.Background()))
+}
+
+func TestPromptRollbackMessage(t *testing.T) {
+	msg := promptRollbackMessage(prompts.RolloutStatus{
+		File:     "parsing.json",
+		Key:      "extract-job-profile",
+		State:    prompts.RolloutRolledBack,
+		Previous: prompts.VersionStats{Version: 1, Samples: 50, ParseFailures: 1},
+		Next:     prompts.VersionStats{Version: 2, Samples: 50, ParseFailures: 6, Violations: 4},
+	})
+
+	assert.Equal(t, "prompt_rollback", msg.Event)
+	assert.Equal(t, "Prompt extract-job-profile rolled back from version 2 to 1", msg.Subject)
+	assert.Contains(t, msg.Body, "failed on 20% of 50 sampled responses, against 2% for version 1")
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+)
+
+// RankingConfigResponse is the story ranking weights a user's runs use
+type RankingConfigResponse struct {
+	ranking.Weights
+	Custom bool `json:"custom"` // False when the user saved none and the defaults apply
+}
+
+// handleGetRankingConfig returns the ranking weights the caller's runs use by default
+func (s *Server) handleGetRankingConfig(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "ranking config")
+	if !ok {
+		return
+	}
+
+	weights, err := s.userRankingWeights(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if weights == nil {
+		s.jsonResponse(w, http.StatusOK, RankingConfigResponse{Weights: ranking.DefaultWeights})
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, RankingConfigResponse{Weights: *weights, Custom: true})
+}
+
+// handlePutRankingConfig saves the ranking weights the caller's runs use when neither the
+// run request nor its recipe sets any
+func (s *Server) handlePutRankingConfig(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "ranking config")
+	if !ok {
+		return
+	}
+
+	var weights ranking.Weights
+	if err := json.NewDecoder(r.Body).Decode(&weights); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := weights.Validate(); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	config, err := json.Marshal(weights)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to encode ranking weights: "+err.Error())
+		return
+	}
+
+	if err := s.db.SetUserRankingConfig(r.Context(), userID, config); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, RankingConfigResponse{Weights: weights, Custom: true})
+}
+
+// handleDeleteRankingConfig clears the caller's ranking weights, restoring the defaults
+func (s *Server) handleDeleteRankingConfig(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "ranking config")
+	if !ok {
+		return
+	}
+
+	if err := s.db.SetUserRankingConfig(r.Context(), userID, nil); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userRankingWeights returns the ranking weights a user saved, or nil if they saved none
+func (s *Server) userRankingWeights(ctx context.Context, userID uuid.UUID) (*ranking.Weights, error) {
+	config, err := s.db.GetUserRankingConfig(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ranking config: %w", err)
+	}
+	if len(config) == 0 {
+		return nil, nil
+	}
+	var weights ranking.Weights
+	if err := json.Unmarshal(config, &weights); err != nil {
+		return nil, fmt.Errorf("failed to decode ranking config: %w", err)
+	}
+	return &weights, nil
+}
+
+// applyRankingConfig gives req the user's saved ranking weights when neither the request
+// nor its recipe set any. It runs after applyRunRecipe and returns the HTTP status to
+// fail with.
+func (s *Server) applyRankingConfig(ctx context.Context, req *RunRequest) (int, error) {
+	if req.RankingWeights != nil {
+		return 0, nil
+	}
+	// handleRun rejects a malformed user_id itself; here it only finds no config
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return 0, nil
+	}
+	weights, err := s.userRankingWeights(ctx, userID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	req.RankingWeights = weights
+	return 0, nil
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/ranking"
+)
+
+func TestHandleRankingConfig(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	target := "/v1/users/" + owner.String() + "/ranking-config"
+	call := func(method string, handler http.HandlerFunc, userID uuid.UUID, body string) (int, *RankingConfigResponse) {
+		t.Helper()
+		w := serveAuthed(t, s, method+" /v1/users/{id}/ranking-config", handler,
+			bearerRequest(t, s, method, target, userID, []byte(body)))
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		var resp RankingConfigResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, &resp
+	}
+
+	_, resp := call(http.MethodGet, s.handleGetRankingConfig, owner, "")
+	require.NotNil(t, resp)
+	assert.False(t, resp.Custom)
+	assert.Equal(t, ranking.DefaultWeights, resp.Weights)
+
+	status, _ := call(http.MethodPut, s.handlePutRankingConfig, uuid.New(), `{"recency":1}`)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = call(http.MethodPut, s.handlePutRankingConfig, owner, `{"recency":-1}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = call(http.MethodPut, s.handlePutRankingConfig, owner, `{"skill_overlap":1,"metric_bonus":2}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	_, resp = call(http.MethodPut, s.handlePutRankingConfig, owner, `{"keyword_overlap":0.6,"recency":0.4,"metric_bonus":0.1}`)
+	require.NotNil(t, resp)
+	_, resp = call(http.MethodGet, s.handleGetRankingConfig, owner, "")
+	require.NotNil(t, resp)
+	assert.True(t, resp.Custom)
+	assert.Equal(t, ranking.Weights{KeywordOverlap: 0.6, Recency: 0.4, MetricBonus: 0.1}, resp.Weights)
+
+	// Runs without weights of their own take the saved ones
+	req := &RunRequest{UserID: owner.String()}
+	_, err := s.applyRankingConfig(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, &ranking.Weights{KeywordOverlap: 0.6, Recency: 0.4, MetricBonus: 0.1}, req.RankingWeights)
+	req = &RunRequest{UserID: owner.String(), RankingWeights: &ranking.Weights{SkillOverlap: 1}}
+	_, err = s.applyRankingConfig(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, &ranking.Weights{SkillOverlap: 1}, req.RankingWeights)
+
+	status, _ = call(http.MethodDelete, s.handleDeleteRankingConfig, owner, "")
+	assert.Equal(t, http.StatusNoContent, status)
+	_, resp = call(http.MethodGet, s.handleGetRankingConfig, owner, "")
+	require.NotNil(t, resp)
+	assert.False(t, resp.Custom)
+}
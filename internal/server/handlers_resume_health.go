@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/deadletter"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+)
+
+// handleGetResumeHealth reviews the caller's experience bank as of now: coverage of the
+// role families they target, stale bullets, bullets without metrics, and suggestions
+func (s *Server) handleGetResumeHealth(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "resume health")
+	if !ok {
+		return
+	}
+
+	health, err := s.db.GetResumeHealth(r.Context(), userID, time.Now())
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to build resume health report: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, health)
+}
+
+// sendDueHealthReports sends the resume health report to users opted in whose last
+// report is at least a digest interval old
+func (s *Server) sendDueHealthReports(ctx context.Context) {
+	interval := s.notify.DigestInterval()
+	recipients, err := s.db.ListDueDigestRecipients(ctx, db.NotificationResumeHealth, interval)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list resume health recipients", "error", err)
+		return
+	}
+
+	for i := range recipients {
+		recipient := &recipients[i]
+		now := time.Now()
+
+		// Claim before sending so replicas never deliver the same report twice
+		claimed, err := s.db.ClaimDigest(ctx, db.NotificationResumeHealth, recipient.UserID, interval, now)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to claim resume health report", logging.KeyUserID, recipient.UserID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		health, err := s.db.GetResumeHealth(ctx, recipient.UserID, now)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to build resume health report", logging.KeyUserID, recipient.UserID, "error", err)
+			continue
+		}
+		msg := notifications.ResumeHealthMessage(recipient.Name, health)
+		if err := s.notifier.Send(ctx, recipient, msg); err != nil {
+			slog.WarnContext(ctx, "failed to send resume health report", logging.KeyUserID, recipient.UserID, "error", err)
+			key := fmt.Sprintf("%s:%s:%s", db.NotificationResumeHealth, recipient.UserID, now.UTC().Format(time.DateOnly))
+			s.recordDeadLetter(ctx, deadletter.Notification(recipient, key, nil, msg, err))
+		}
+	}
+}
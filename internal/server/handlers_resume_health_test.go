@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestHandleGetResumeHealth(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	s.mock.healthBullets = map[uuid.UUID][]db.EvidenceBullet{
+		owner: {{BulletID: "b1", StoryID: "s1", Text: "Mentored new engineers", UpdatedAt: time.Now().AddDate(-2, 0, 0)}},
+	}
+
+	target := "/v1/users/" + owner.String() + "/resume-health"
+	w := serveAuthed(t, s, "GET /v1/users/{id}/resume-health", s.handleGetResumeHealth,
+		bearerRequest(t, s, http.MethodGet, target, owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var health db.ResumeHealth
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &health))
+	assert.Equal(t, owner, health.UserID)
+	require.Len(t, health.StaleBullets, 1)
+	assert.Len(t, health.MissingMetrics, 1)
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/resume-health", s.handleGetResumeHealth,
+		bearerRequest(t, s, http.MethodGet, target, uuid.New(), nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestSendDueHealthReports(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	rec := &webhookRecorder{}
+	url := rec.server(t).URL
+	s.mock.notifyPrefs = []db.NotificationPreference{
+		{UserID: user, EventType: db.NotificationWeeklyDigest, Channel: db.NotificationNone},
+		{UserID: user, EventType: db.NotificationResumeHealth, Channel: db.NotificationWebhook, WebhookURL: &url},
+	}
+
+	s.sendDueHealthReports(context.Background())
+	s.sendDueHealthReports(context.Background())
+
+	require.Len(t, rec.messages, 1, "the report is sent once per interval")
+	assert.Equal(t, db.NotificationResumeHealth, rec.messages[0].Event)
+	assert.Nil(t, s.mock.notifyPrefs[0].LastSentAt, "the digest schedule is separate")
+	assert.NotNil(t, s.mock.notifyPrefs[1].LastSentAt)
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/resumeimport"
+	"github.com/jonathan/resume-customizer/internal/toolchain"
+)
+
+// ExperienceBankImportResponse counts what a reviewed draft added to the experience bank
+type ExperienceBankImportResponse struct {
+	Stories   int `json:"stories"`
+	Bullets   int `json:"bullets"`
+	Education int `json:"education"`
+}
+
+// handleDraftExperienceBank reads an uploaded resume PDF or DOCX and drafts the stories,
+// bullets, skills, and education in it. Nothing is saved; the caller reviews the draft
+// and saves it through handleImportExperienceBank. The document is discarded once read.
+func (s *Server) handleDraftExperienceBank(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "experience bank")
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, resumeimport.MaxUploadBytes)
+	file, header, err := r.FormFile("resume")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			s.errorResponse(w, http.StatusRequestEntityTooLarge, "Resume is too large")
+			return
+		}
+		s.errorResponse(w, http.StatusBadRequest, "Request must be multipart/form-data with a resume file")
+		return
+	}
+	defer func() { _ = file.Close() }()
+	if !resumeimport.Supported(header.Filename) {
+		s.errorResponse(w, http.StatusBadRequest, "Unsupported resume format; use one of "+strings.Join(resumeimport.Extensions, ", "))
+		return
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Failed to read resume: "+err.Error())
+		return
+	}
+
+	text, err := resumeimport.ExtractText(r.Context(), header.Filename, data)
+	if err != nil {
+		s.resumeImportError(w, err)
+		return
+	}
+	bank, err := resumeimport.ExtractBank(r.Context(), userID, text, s.apiKey)
+	if err != nil {
+		s.resumeImportError(w, err)
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, bank)
+}
+
+// resumeImportError responds with the status for an error reading or drafting a resume
+func (s *Server) resumeImportError(w http.ResponseWriter, err error) {
+	var docErr *resumeimport.DocumentError
+	var parseErr *resumeimport.ParseError
+	switch {
+	case errors.Is(err, resumeimport.ErrNoText), errors.As(err, &docErr):
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, toolchain.ErrUnavailable):
+		s.errorResponse(w, http.StatusServiceUnavailable, "Reading PDFs is not available: "+err.Error())
+	case errors.As(err, &parseErr):
+		s.errorResponse(w, http.StatusUnprocessableEntity, "Failed to draft experience bank: "+err.Error())
+	default:
+		s.errorResponse(w, http.StatusBadGateway, "Failed to draft experience bank: "+err.Error())
+	}
+}
+
+// handleImportExperienceBank saves a reviewed experience bank draft. The draft is
+// normalized as drafting does, so edits cannot introduce blank positions or bullets.
+func (s *Server) handleImportExperienceBank(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "experience bank")
+	if !ok {
+		return
+	}
+
+	var bank db.ExperienceBankImportInput
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportSize)).Decode(&bank); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	resumeimport.Normalize(&bank)
+	if len(bank.Stories) == 0 && len(bank.Education) == 0 {
+		s.errorResponse(w, http.StatusBadRequest, "Draft has no positions or education to import")
+		return
+	}
+	bank.UserID = userID
+
+	if err := s.db.ImportExperienceBank(r.Context(), &bank); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	resp := ExperienceBankImportResponse{Stories: len(bank.Stories), Education: len(bank.Education)}
+	for _, story := range bank.Stories {
+		resp.Bullets += len(story.Bullets)
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
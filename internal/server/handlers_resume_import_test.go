@@ -0,0 +1,98 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+)
+
+// resumeDOCX is a one-paragraph Word document
+func resumeDOCX(t *testing.T, text string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("word/document.xml")
+	require.NoError(t, err)
+	_, err = f.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>` +
+		text + `</w:t></w:r></w:p></w:body></w:document>`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestHandleDraftExperienceBank(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	s.apiKey = "test-key"
+	cassette := llm.NewCassette([]llm.Exchange{{
+		Prompt: "The following is the text of a job seeker's existing resume", JSON: true,
+		Response: `{"stories":[{"company":"Acme Corp","role":"Senior Engineer","start_date":"2020-03","end_date":"present",` +
+			`"bullets":[{"text":"Cut p99 latency 40% with Redis","metrics":"40%","skills":["Redis"]}]}],"education":[]}`,
+	}})
+
+	upload := func(caller uuid.UUID, filename string, data []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("resume", filename)
+		require.NoError(t, err)
+		_, _ = part.Write(data)
+		require.NoError(t, form.Close())
+		req := bearerRequest(t, s, http.MethodPost, "/v1/users/"+caller.String()+"/experience-bank/draft", owner, body.Bytes())
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		return serveAuthed(t, s, "POST /v1/users/{id}/experience-bank/draft", s.handleDraftExperienceBank,
+			req.WithContext(llm.WithCassette(req.Context(), cassette)))
+	}
+
+	assert.Equal(t, http.StatusForbidden, upload(uuid.New(), "resume.docx", resumeDOCX(t, "Acme Corp")).Code)
+	assert.Equal(t, http.StatusBadRequest, upload(owner, "resume.txt", []byte("Acme Corp")).Code)
+	assert.Equal(t, http.StatusBadRequest, upload(owner, "resume.pdf", []byte("not a pdf")).Code)
+	assert.Equal(t, http.StatusBadRequest, upload(owner, "resume.docx", resumeDOCX(t, " ")).Code, "no text")
+
+	w := upload(owner, "resume.docx", resumeDOCX(t, "Acme Corp, Senior Engineer: cut p99 latency 40% with Redis"))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var draft db.ExperienceBankImportInput
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &draft))
+	require.Len(t, draft.Stories, 1)
+	assert.Equal(t, "Acme Corp", draft.Stories[0].Company)
+	assert.Equal(t, db.EvidenceStrengthHigh, draft.Stories[0].Bullets[0].EvidenceStrength)
+	assert.NotContains(t, w.Body.String(), owner.String(), "the user is taken from the path when saving")
+	assert.Empty(t, s.mock.importedBanks, "drafts are not saved until reviewed")
+}
+
+func TestHandleImportExperienceBank(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	target := "/v1/users/" + owner.String() + "/experience-bank/import"
+	pattern := "POST /v1/users/{id}/experience-bank/import"
+	importBank := func(body string) *httptest.ResponseRecorder {
+		return serveAuthed(t, s, pattern, s.handleImportExperienceBank, bearerRequest(t, s, http.MethodPost, target, owner, []byte(body)))
+	}
+
+	assert.Equal(t, http.StatusBadRequest, importBank(`not json`).Code)
+	assert.Equal(t, http.StatusBadRequest, importBank(`{"stories":[{"company":" ","role":"Engineer"}]}`).Code)
+
+	w := importBank(`{"stories":[{"id":"edited","company":"Acme Corp","role":"Senior Engineer","start_date":"2020-03",` +
+		`"bullets":[{"text":"Cut p99 latency 40% with Redis","skills":["Redis"]},{"text":""}]}],` +
+		`"education":[{"school":"State University","degree":"master","field":"CS"}]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp ExperienceBankImportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, ExperienceBankImportResponse{Stories: 1, Bullets: 1, Education: 1}, resp)
+
+	require.Len(t, s.mock.importedBanks, 1)
+	bank := s.mock.importedBanks[0]
+	assert.Equal(t, owner, bank.UserID)
+	assert.NotEqual(t, "edited", bank.Stories[0].ID, "IDs are assigned on save")
+	assert.Equal(t, db.EvidenceStrengthMedium, bank.Stories[0].Bullets[0].EvidenceStrength)
+}
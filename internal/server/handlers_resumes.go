@@ -3,14 +3,22 @@ package server
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"strconv"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/config"
 	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/logging"
 	"github.com/jonathan/resume-customizer/internal/pipeline"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/research"
+	"github.com/jonathan/resume-customizer/internal/scheduler"
 	"github.com/jonathan/resume-customizer/internal/server/middleware"
 )
 
@@ -25,12 +33,56 @@ type RunRequest struct {
 	Template   string `json:"template,omitempty"`
 	MaxBullets int    `json:"max_bullets,omitempty"`
 	MaxLines   int    `json:"max_lines,omitempty"`
+	MaxPages   int    `json:"max_pages,omitempty"`
+	Debug      bool   `json:"debug,omitempty"`    // Store redacted raw LLM prompts/responses (owner/admin only)
+	Priority   string `json:"priority,omitempty"` // interactive, normal, or bulk (scheduling class)
+
+	Crawl *CrawlParams     `json:"crawl,omitempty"` // Research crawl limits; omitted fields use server defaults
+	Style *rendering.Style `json:"style,omitempty"` // Look-and-feel options; must be supported by the template's manifest
+
+	OutputFormat string `json:"output_format,omitempty"` // pdf (default) or docx, which also stores a Word copy of the resume
+
+	PresetID      string   `json:"preset_id,omitempty"`      // Company preset filling in the options below and template/section order when unset
+	ToneOverride  string   `json:"tone_override,omitempty"`  // Tone to write in instead of the researched company tone
+	PinnedBullets []string `json:"pinned_bullets,omitempty"` // Bullet IDs always placed on the resume
+
+	Recipe         string           `json:"recipe,omitempty"`          // Run recipe (built-in or the user's own) filling in options still unset
+	RankingWeights *ranking.Weights `json:"ranking_weights,omitempty"` // Story ranking weights; omitted uses the defaults
 }
 
+// CrawlParams tightens the server's research crawl depth, scope, and budget defaults for one run
+type CrawlParams struct {
+	MaxPages       *int   `json:"max_pages,omitempty"`
+	MaxDepth       *int   `json:"max_depth,omitempty"`
+	SameDomainOnly *bool  `json:"same_domain_only,omitempty"`
+	MaxBytes       *int64 `json:"max_bytes,omitempty"`
+
+	ArchiveFallback *bool `json:"archive_fallback,omitempty"`
+
+	Refresh *bool `json:"refresh,omitempty"` // Crawl even when a fresh shared company profile exists
+	Private *bool `json:"private,omitempty"` // Neither reuse shared company research nor share this run's
+}
+
+// runQueueRetryAfterSeconds is the Retry-After hint sent when a user's run queue is full
+const runQueueRetryAfterSeconds = 30
+
+// queuePositionPollInterval is how often a streaming run waiting for a slot checks
+// whether its place in line has changed
+const queuePositionPollInterval = time.Second
+
 // RunResponse represents the response for /run
 type RunResponse struct {
-	RunID  string `json:"run_id"`
-	Status string `json:"status"`
+	RunID                string `json:"run_id"`
+	Status               string `json:"status"`                           // "started", or "queued" while waiting for a slot
+	QueuePosition        int    `json:"queue_position,omitempty"`         // Estimated place in the run queue when queued
+	EstimatedWaitSeconds int    `json:"estimated_wait_seconds,omitempty"` // Estimated time until the run starts when queued
+}
+
+// QueuedEvent is the payload of the "queued" SSE event, sent whenever a streaming
+// run's place in line changes while it waits for a slot
+type QueuedEvent struct {
+	QueuePosition        int `json:"queue_position"`
+	EstimatedWaitSeconds int `json:"estimated_wait_seconds"`
 }
 
 // RunGetResponse represents the response for GET /v1/runs/{id}
@@ -41,6 +93,9 @@ type RunGetResponse struct {
 	RoleTitle   string  `json:"role_title"`
 	JobURL      string  `json:"job_url"`
 	Status      string  `json:"status"`
+	Priority    string  `json:"priority,omitempty"`
+	RunType     string  `json:"run_type,omitempty"`
+	SourceRunID *string `json:"source_run_id,omitempty"` // Run a cover-letter or refresh run reuses
 	CreatedAt   string  `json:"created_at"`
 	CompletedAt *string `json:"completed_at,omitempty"`
 }
@@ -84,7 +139,7 @@ func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate required fields
-	if req.JobURL == "" && req.JobPath == "" {
+	if req.JobURL == "" && req.JobPath == "" && s.demo == nil {
 		s.errorResponse(w, http.StatusBadRequest, "Either job_url or job is required")
 		return
 	}
@@ -93,15 +148,33 @@ func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if status, err := s.applyCompanyPreset(r.Context(), &req); err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+	if status, err := s.applyRunRecipe(r.Context(), &req); err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+	if status, err := s.applyRankingConfig(r.Context(), &req); err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+
 	// Set defaults
 	if req.Template == "" {
 		req.Template = "templates/one_page_resume.tex"
 	}
 	if req.MaxBullets == 0 {
-		req.MaxBullets = 25
+		req.MaxBullets = 25 * resumePages(req.MaxPages)
 	}
-	if req.MaxLines == 0 {
-		req.MaxLines = 35
+	if status, err := s.applyRunTemplate(r.Context(), &req); err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+	if req.OutputFormat != "" && !slices.Contains(rendering.OutputFormats, req.OutputFormat) {
+		s.errorResponse(w, http.StatusBadRequest, `output_format must be "pdf" or "docx"`)
+		return
 	}
 
 	// Build pipeline options
@@ -109,14 +182,21 @@ func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
 		JobURL:         req.JobURL,
 		JobPath:        req.JobPath,
 		TemplatePath:   req.Template,
+		Style:          req.Style,
+		OutputFormat:   req.OutputFormat,
+		ToneOverride:   req.ToneOverride,
+		PinnedBullets:  req.PinnedBullets,
+		RankingWeights: req.RankingWeights,
 		CandidateName:  req.Name,
 		CandidateEmail: req.Email,
 		CandidatePhone: req.Phone,
 		MaxBullets:     req.MaxBullets,
 		MaxLines:       req.MaxLines,
+		MaxPages:       req.MaxPages,
 		APIKey:         s.apiKey,
 		DatabaseURL:    s.databaseURL,
 		Verbose:        true,
+		Demo:           s.demo,
 	}
 
 	// Fetch experience data from DB using UserID
@@ -125,6 +205,30 @@ func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
 		s.errorResponse(w, http.StatusBadRequest, "Invalid user_id")
 		return
 	}
+	opts.UserID = &uid
+
+	priority, err := scheduler.ParsePriority(req.Priority, scheduler.PriorityNormal)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts.Priority = priority.String()
+	opts.Crawl, err = s.crawlLimits(req.Crawl)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts.ModelRouting = s.routing
+	opts.ModelDowngrade = s.modelDowngrade(r.Context(), uid)
+	opts.RedactPII = s.redactPII(r.Context(), uid)
+
+	if req.Debug {
+		if err := s.authorizeDebug(r, &uid); err != nil {
+			s.errorResponse(w, HTTPStatus(err), err.Error())
+			return
+		}
+		opts.Debug = true
+	}
 
 	// Fetch user profile if name/email not provided in request
 	if req.Name == "" || req.Email == "" {
@@ -159,19 +263,153 @@ func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
 	// The actual run will be created in the pipeline
 	preliminaryID := uuid.New().String()
 
-	log.Printf("Starting pipeline run (preliminary ID: %s)", preliminaryID)
+	slog.InfoContext(r.Context(), "Starting pipeline run", "preliminary_id", preliminaryID)
 
-	// Run pipeline in background
-	go func() {
-		ctx := context.Background()
-		if err := pipeline.RunPipeline(ctx, opts); err != nil {
-			log.Printf("Pipeline run failed: %v", err)
+	// Run pipeline in background once the scheduler grants a slot. The run outlives the
+	// request, but its log lines keep the request's ID.
+	ticket, err := s.scheduler.Submit(context.WithoutCancel(r.Context()), scheduler.Task{
+		UserID:   uid,
+		Priority: priority,
+		Run: func(ctx context.Context) {
+			if err := pipeline.RunPipeline(ctx, opts); err != nil {
+				slog.ErrorContext(ctx, "Pipeline run failed", "error", err)
+			}
+		},
+	})
+	if errors.Is(err, scheduler.ErrUserQueueFull) {
+		s.runQueueFullResponse(w, uid)
+		return
+	}
+
+	resp := RunResponse{RunID: preliminaryID, Status: "started"}
+	select {
+	case <-ticket.Started():
+	default:
+		if pos, wait := ticket.Estimate(); pos > 0 {
+			resp.Status = "queued"
+			resp.QueuePosition = pos
+			resp.EstimatedWaitSeconds = int(wait.Seconds())
+		}
+	}
+	s.jsonResponse(w, http.StatusAccepted, resp)
+}
+
+// crawlLimits merges per-run crawl overrides over the server defaults.
+// It returns nil when neither is set so the pipeline falls back to CRAWL_* defaults.
+// The defaults are ceilings set by the operator: a run may tighten them but not
+// crawl more pages, deeper, more bytes, or off the company's domains.
+func (s *Server) crawlLimits(params *CrawlParams) (*research.CrawlLimits, error) {
+	if params == nil {
+		if s.crawl == nil {
+			return nil, nil
+		}
+		return pipeline.CrawlLimitsFromConfig(s.crawl), nil
+	}
+
+	defaults := s.crawl
+	if defaults == nil {
+		var err error
+		if defaults, err = config.NewCrawlConfig(); err != nil {
+			return nil, err
+		}
+	}
+	limits := pipeline.CrawlLimitsFromConfig(defaults)
+	if params.MaxPages != nil {
+		if *params.MaxPages < 1 {
+			return nil, fmt.Errorf("crawl.max_pages must be at least 1")
+		}
+		if *params.MaxPages > defaults.MaxPages {
+			return nil, fmt.Errorf("crawl.max_pages must not exceed %d", defaults.MaxPages)
+		}
+		limits.MaxPages = *params.MaxPages
+	}
+	if params.MaxDepth != nil {
+		if *params.MaxDepth < 0 {
+			return nil, fmt.Errorf("crawl.max_depth must not be negative")
 		}
-	}()
+		if *params.MaxDepth > defaults.MaxDepth {
+			return nil, fmt.Errorf("crawl.max_depth must not exceed %d", defaults.MaxDepth)
+		}
+		limits.MaxDepth = *params.MaxDepth
+	}
+	if params.SameDomainOnly != nil {
+		if !*params.SameDomainOnly && defaults.SameDomainOnly {
+			return nil, fmt.Errorf("crawl.same_domain_only cannot be disabled on this server")
+		}
+		limits.SameDomainOnly = *params.SameDomainOnly
+	}
+	if params.MaxBytes != nil {
+		if *params.MaxBytes < 0 {
+			return nil, fmt.Errorf("crawl.max_bytes must not be negative")
+		}
+		// 0 means unlimited, so it is only allowed when the server is unlimited too
+		if defaults.MaxBytes > 0 && (*params.MaxBytes == 0 || *params.MaxBytes > defaults.MaxBytes) {
+			return nil, fmt.Errorf("crawl.max_bytes must be between 1 and %d", defaults.MaxBytes)
+		}
+		limits.MaxBytes = *params.MaxBytes
+	}
+	if params.ArchiveFallback != nil {
+		limits.ArchiveFallback = *params.ArchiveFallback
+	}
+	if params.Refresh != nil {
+		limits.Refresh = *params.Refresh
+	}
+	if params.Private != nil {
+		limits.Private = *params.Private
+	}
+	return limits, nil
+}
+
+// redactPII reports whether the user's contact details should be masked in
+// prompts to external LLMs. It fails closed: redaction stays on if the setting cannot be read.
+func (s *Server) redactPII(ctx context.Context, uid uuid.UUID) bool {
+	u, err := s.db.GetUser(ctx, uid)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read redaction setting, keeping redaction on", "error", err)
+		return true
+	}
+	if u == nil {
+		return true
+	}
+	return u.RedactPII
+}
 
-	s.jsonResponse(w, http.StatusAccepted, RunResponse{
-		RunID:  preliminaryID,
-		Status: "started",
+// modelDowngrade reports whether the user's runs should be routed to cheaper
+// models because they are close to their daily run quota
+func (s *Server) modelDowngrade(ctx context.Context, uid uuid.UUID) bool {
+	if s.routing == nil || s.routing.Quota.DailyRuns == 0 {
+		return false
+	}
+	used, err := s.db.CountUserRunsSince(ctx, uid, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to count runs for quota check", "error", err)
+		return false
+	}
+	if s.routing.LowQuota(used) {
+		slog.InfoContext(ctx, "User is near their daily run quota, downgrading models",
+			logging.KeyUserID, uid, "used", used, "quota", s.routing.Quota.DailyRuns)
+		return true
+	}
+	return false
+}
+
+// runQueueFullResponse writes a 429 Too Many Requests response for a user who
+// already has the maximum number of runs waiting for a slot while the admission
+// backlog is full too.
+func (s *Server) runQueueFullResponse(w http.ResponseWriter, uid uuid.UUID) {
+	running, queued := s.scheduler.UserStats(uid)
+	maxRunning, maxQueued := s.scheduler.Limits()
+	slog.Warn("Run queue full", logging.KeyUserID, uid, "running", running, "queued", queued)
+
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", runQueueRetryAfterSeconds))
+	s.jsonResponse(w, http.StatusTooManyRequests, map[string]interface{}{
+		"error":          "run_queue_full",
+		"message":        "Too many runs in progress for this user. Wait for a run to finish and try again.",
+		"running":        running,
+		"queued":         queued,
+		"max_concurrent": maxRunning,
+		"max_queued":     maxQueued,
+		"retry_after":    runQueueRetryAfterSeconds,
 	})
 }
 
@@ -204,7 +442,7 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 		Company:   run.Company,
 		RoleTitle: run.RoleTitle,
 		Status:    run.Status,
-		CreatedAt: run.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		CreatedAt: formatTimestamp(run.CreatedAt),
 	})
 }
 
@@ -257,8 +495,8 @@ func (s *Server) handleV1Status(w http.ResponseWriter, r *http.Request) {
 		Company:   company,
 		Role:      role,
 		Status:    run.Status,
-		CreatedAt: run.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: updatedAt.Format(time.RFC3339),
+		CreatedAt: formatTimestamp(run.CreatedAt),
+		UpdatedAt: formatTimestamp(updatedAt),
 		Message:   nil, // Run-level error messages not yet tracked
 	}
 
@@ -299,7 +537,7 @@ func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
 	// Map completed_at (nullable)
 	var completedAt *string
 	if run.CompletedAt != nil {
-		completedAtStr := run.CompletedAt.Format(time.RFC3339)
+		completedAtStr := formatTimestamp(*run.CompletedAt)
 		completedAt = &completedAtStr
 	}
 
@@ -311,9 +549,15 @@ func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
 		RoleTitle:   run.RoleTitle,
 		JobURL:      run.JobURL,
 		Status:      run.Status,
-		CreatedAt:   run.CreatedAt.Format(time.RFC3339),
+		Priority:    run.Priority,
+		RunType:     run.RunType,
+		CreatedAt:   formatTimestamp(run.CreatedAt),
 		CompletedAt: completedAt,
 	}
+	if run.SourceRunID != nil {
+		sourceRunID := run.SourceRunID.String()
+		response.SourceRunID = &sourceRunID
+	}
 
 	s.jsonResponse(w, http.StatusOK, response)
 }
@@ -349,6 +593,14 @@ func (s *Server) handleGetArtifact(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Debug artifacts contain raw LLM traffic and are restricted to the run owner or an admin
+	if artifact.Category == db.CategoryDebug {
+		if err := s.authorizeDebugArtifact(r, artifact.RunID); err != nil {
+			s.errorResponse(w, HTTPStatus(err), err.Error())
+			return
+		}
+	}
+
 	s.jsonResponse(w, http.StatusOK, artifact)
 }
 
@@ -361,7 +613,7 @@ func (s *Server) handleRunStream(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate required fields
-	if req.JobURL == "" && req.JobPath == "" {
+	if req.JobURL == "" && req.JobPath == "" && s.demo == nil {
 		s.errorResponse(w, http.StatusBadRequest, "Either job_url or job is required")
 		return
 	}
@@ -370,15 +622,33 @@ func (s *Server) handleRunStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if status, err := s.applyCompanyPreset(r.Context(), &req); err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+	if status, err := s.applyRunRecipe(r.Context(), &req); err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+	if status, err := s.applyRankingConfig(r.Context(), &req); err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+
 	// Set defaults
 	if req.Template == "" {
 		req.Template = "templates/one_page_resume.tex"
 	}
 	if req.MaxBullets == 0 {
-		req.MaxBullets = 25
+		req.MaxBullets = 25 * resumePages(req.MaxPages)
+	}
+	if status, err := s.applyRunTemplate(r.Context(), &req); err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
 	}
-	if req.MaxLines == 0 {
-		req.MaxLines = 35
+	if req.OutputFormat != "" && !slices.Contains(rendering.OutputFormats, req.OutputFormat) {
+		s.errorResponse(w, http.StatusBadRequest, `output_format must be "pdf" or "docx"`)
+		return
 	}
 
 	// Fetch experience data from DB using UserID
@@ -388,6 +658,25 @@ func (s *Server) handleRunStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Streaming clients are waiting on the result, so they default to interactive
+	priority, err := scheduler.ParsePriority(req.Priority, scheduler.PriorityInteractive)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	crawl, err := s.crawlLimits(req.Crawl)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Debug {
+		if err := s.authorizeDebug(r, &uid); err != nil {
+			s.errorResponse(w, HTTPStatus(err), err.Error())
+			return
+		}
+	}
+
 	// Fetch user profile if name/email not provided in request
 	if req.Name == "" || req.Email == "" {
 		u, err := s.db.GetUser(r.Context(), uid)
@@ -416,6 +705,12 @@ func (s *Server) handleRunStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Reject before opening the stream so scripted clients get a plain 429
+	if s.scheduler.QueueFull(uid) {
+		s.runQueueFullResponse(w, uid)
+		return
+	}
+
 	// Setup SSE writer
 	sse, err := NewSSEWriter(w)
 	if err != nil {
@@ -432,11 +727,13 @@ func (s *Server) handleRunStream(w http.ResponseWriter, r *http.Request) {
 		if jobURL == "" {
 			jobURL = req.JobPath // Use path if URL not provided
 		}
-		createdRunID, err := s.db.CreateRun(ctx, "", "", jobURL)
+		// The run stays 'queued' until the scheduler grants it a slot
+		createdRunID, err := s.db.CreateQueuedRun(ctx, jobURL)
 		if err != nil {
-			log.Printf("Warning: Failed to create database run: %v", err)
+			slog.WarnContext(ctx, "Failed to create database run", "error", err)
 		} else {
 			runID = &createdRunID
+			ctx = logging.WithRunID(ctx, createdRunID)
 			// Send run_id as the FIRST SSE event before any ingestion
 			// Use the same format as the pipeline's emitRunStarted for consistency
 			// This MUST be sent and flushed before pipeline starts
@@ -447,9 +744,9 @@ func (s *Server) handleRunStream(w http.ResponseWriter, r *http.Request) {
 				RunID:    createdRunID.String(),
 			}
 			if err := sse.WriteEvent("step", runStartedEvent); err != nil {
-				log.Printf("Error writing run_started SSE event: %v", err)
+				slog.ErrorContext(ctx, "Failed to write run_started SSE event", "error", err)
 			} else {
-				log.Printf("Created run %s, sent run_id as first SSE event (before pipeline start)", createdRunID)
+				slog.InfoContext(ctx, "Created run, sent run_id as first SSE event (before pipeline start)")
 				// WriteEvent already flushes, but we ensure it's sent before pipeline starts
 			}
 		}
@@ -457,10 +754,10 @@ func (s *Server) handleRunStream(w http.ResponseWriter, r *http.Request) {
 
 	// Ensure we have a run ID before starting pipeline
 	if runID == nil && s.databaseURL != "" {
-		log.Printf("Warning: Failed to create run before pipeline start, pipeline will create one later")
+		slog.WarnContext(ctx, "Failed to create run before pipeline start, pipeline will create one later")
 	}
 
-	log.Printf("Starting streaming pipeline run...")
+	slog.InfoContext(ctx, "Starting streaming pipeline run...")
 
 	// Build pipeline options with progress callback
 	opts := pipeline.RunOptions{
@@ -468,45 +765,122 @@ func (s *Server) handleRunStream(w http.ResponseWriter, r *http.Request) {
 		JobPath:        req.JobPath,
 		ExperienceData: expData,
 		TemplatePath:   req.Template,
+		Style:          req.Style,
+		OutputFormat:   req.OutputFormat,
+		ToneOverride:   req.ToneOverride,
+		PinnedBullets:  req.PinnedBullets,
+		RankingWeights: req.RankingWeights,
 		CandidateName:  req.Name,
 		CandidateEmail: req.Email,
 		CandidatePhone: req.Phone,
 		MaxBullets:     req.MaxBullets,
 		MaxLines:       req.MaxLines,
+		MaxPages:       req.MaxPages,
 		APIKey:         s.apiKey,
 		DatabaseURL:    s.databaseURL,
 		Verbose:        true,
 		ExistingRunID:  runID,        // Pass existing run ID to pipeline
 		RunStartedSent: runID != nil, // Mark that we already sent run_started
+		UserID:         &uid,
+		Priority:       priority.String(),
+		Debug:          req.Debug,
+		Crawl:          crawl,
+		ModelRouting:   s.routing,
+		ModelDowngrade: s.modelDowngrade(ctx, uid),
+		RedactPII:      s.redactPII(ctx, uid),
+		Demo:           s.demo,
 		OnProgress: func(event pipeline.ProgressEvent) {
 			if err := sse.WriteEvent("step", event); err != nil {
-				log.Printf("Error writing SSE event: %v", err)
+				slog.ErrorContext(ctx, "Failed to write SSE event", "error", err)
 			}
 		},
 	}
 
-	// Run pipeline synchronously (blocking until complete)
-	if err := pipeline.RunPipeline(ctx, opts); err != nil {
-		log.Printf("Pipeline run failed: %v", err)
+	// Run pipeline once the scheduler grants a slot, blocking until complete
+	var runErr error
+	started := false
+	ticket, err := s.scheduler.Submit(ctx, scheduler.Task{
+		UserID:   uid,
+		Priority: priority,
+		Run: func(ctx context.Context) {
+			started = true
+			if runID != nil {
+				if err := s.db.StartRun(ctx, *runID); err != nil {
+					slog.WarnContext(ctx, "Failed to mark run started", "error", err)
+				}
+			}
+			runErr = pipeline.RunPipeline(ctx, opts)
+		},
+	})
+	if err != nil {
+		// The queue filled up after the check above; the stream is already open
+		s.cancelQueuedRun(ctx, runID)
 		sse.WriteError(err.Error())
 		return
 	}
+	streamQueuePosition(sse, ticket)
+	<-ticket.Done()
+	if !started {
+		slog.InfoContext(ctx, "Streaming pipeline run canceled while queued")
+		s.cancelQueuedRun(ctx, runID)
+		return
+	}
+	if runErr != nil {
+		slog.ErrorContext(ctx, "Pipeline run failed", "error", runErr)
+		sse.WriteError(runErr.Error())
+		return
+	}
 
 	sse.WriteComplete("", "completed")
-	log.Printf("Streaming pipeline run completed")
+	slog.InfoContext(ctx, "Streaming pipeline run completed")
+}
+
+// streamQueuePosition sends a "queued" event each time the ticket's place in line
+// changes, until the task starts or is dropped
+func streamQueuePosition(sse *SSEWriter, ticket *scheduler.Ticket) {
+	ticker := time.NewTicker(queuePositionPollInterval)
+	defer ticker.Stop()
+	last := 0
+	for {
+		if pos, wait := ticket.Estimate(); pos > 0 && pos != last {
+			last = pos
+			event := QueuedEvent{QueuePosition: pos, EstimatedWaitSeconds: int(wait.Seconds())}
+			if err := sse.WriteEvent("queued", event); err != nil {
+				slog.Error("Failed to write queued SSE event", "error", err)
+			}
+		}
+		select {
+		case <-ticket.Started():
+			return
+		case <-ticket.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cancelQueuedRun marks a run that never got a scheduler slot as canceled. The request
+// context is usually already done, so the update uses a non-canceled copy.
+func (s *Server) cancelQueuedRun(ctx context.Context, runID *uuid.UUID) {
+	if runID == nil {
+		return
+	}
+	if err := s.db.CompleteRun(context.WithoutCancel(ctx), *runID, "canceled"); err != nil {
+		slog.WarnContext(ctx, "failed to cancel queued run", logging.KeyRunID, *runID, "error", err)
+	}
 }
 
 // handleListRuns returns a list of pipeline runs with optional filters
 func (s *Server) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	page, ok := s.parseListPage(w, r, runListSpec)
+	if !ok {
+		return
+	}
 	filters := db.RunFilters{
 		Company: r.URL.Query().Get("company"),
 		Status:  r.URL.Query().Get("status"),
-	}
-
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			filters.Limit = limit
-		}
+		Limit:   page.Limit,
+		Page:    page.Page,
 	}
 
 	runs, err := s.db.ListRunsFiltered(r.Context(), filters)
@@ -530,13 +904,14 @@ func (s *Server) handleListRuns(w http.ResponseWriter, r *http.Request) {
 			Company:   run.Company,
 			RoleTitle: run.RoleTitle,
 			Status:    run.Status,
-			CreatedAt: run.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			CreatedAt: formatTimestamp(run.CreatedAt),
 		})
 	}
 
 	s.jsonResponse(w, http.StatusOK, map[string]any{
-		"runs":  response,
-		"count": len(response),
+		"runs":        response,
+		"count":       len(response),
+		"next_cursor": nextCursor(page, runs, runSortKey),
 	})
 }
 
@@ -562,17 +937,17 @@ func (s *Server) handleListUserRuns(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse query parameters for filtering
+	// Parse query parameters for filtering and paging
+	page, ok := s.parseListPage(w, r, runListSpec)
+	if !ok {
+		return
+	}
 	filters := db.RunFilters{
 		Company: r.URL.Query().Get("company"),
 		Status:  r.URL.Query().Get("status"),
 		UserID:  &userID, // Filter by user ID
-	}
-
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil {
-			filters.Limit = limit
-		}
+		Limit:   page.Limit,
+		Page:    page.Page,
 	}
 
 	runs, err := s.db.ListRunsFiltered(r.Context(), filters)
@@ -596,13 +971,14 @@ func (s *Server) handleListUserRuns(w http.ResponseWriter, r *http.Request) {
 			Company:   run.Company,
 			RoleTitle: run.RoleTitle,
 			Status:    run.Status,
-			CreatedAt: run.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			CreatedAt: formatTimestamp(run.CreatedAt),
 		})
 	}
 
 	s.jsonResponse(w, http.StatusOK, map[string]any{
-		"runs":  response,
-		"count": len(response),
+		"runs":        response,
+		"count":       len(response),
+		"next_cursor": nextCursor(page, runs, runSortKey),
 	})
 }
 
@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/config"
 	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/research"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -568,3 +571,79 @@ func TestHandleRunResumeTex_ViewMode_MultipleQueryParams(t *testing.T) {
 	assert.Empty(t, w.Header().Get("Content-Disposition"), "Should not have Content-Disposition header when view=true")
 	assert.Equal(t, texContent, w.Body.String())
 }
+
+// TestCrawlLimits_Overrides tests that per-run crawl params override server defaults
+func TestCrawlLimits_Overrides(t *testing.T) {
+	s := newTestServer()
+	s.crawl = &config.CrawlConfig{MaxPages: 5, MaxDepth: 1, SameDomainOnly: true, MaxBytes: 1000}
+
+	limits, err := s.crawlLimits(nil)
+	require.NoError(t, err)
+	assert.Equal(t, &research.CrawlLimits{MaxPages: 5, MaxDepth: 1, SameDomainOnly: true, MaxBytes: 1000}, limits)
+
+	pages, depth, sameDomain, archive, maxBytes := 3, 0, true, true, int64(500)
+	limits, err = s.crawlLimits(&CrawlParams{MaxPages: &pages, MaxDepth: &depth, SameDomainOnly: &sameDomain, MaxBytes: &maxBytes, ArchiveFallback: &archive})
+	require.NoError(t, err)
+	assert.Equal(t, &research.CrawlLimits{MaxPages: 3, MaxDepth: 0, SameDomainOnly: true, MaxBytes: 500, ArchiveFallback: true}, limits)
+
+	refresh, private := true, true
+	limits, err = s.crawlLimits(&CrawlParams{Refresh: &refresh, Private: &private})
+	require.NoError(t, err)
+	assert.True(t, limits.Refresh)
+	assert.True(t, limits.Private)
+}
+
+// TestCrawlLimits_Ceilings tests that per-run params cannot loosen the server defaults
+func TestCrawlLimits_Ceilings(t *testing.T) {
+	s := newTestServer()
+	s.crawl = &config.CrawlConfig{MaxPages: 5, MaxDepth: 1, SameDomainOnly: true, MaxBytes: 1000}
+
+	pages, depth, sameDomain := 6, 2, false
+	unlimited, tooMany := int64(0), int64(1001)
+	for _, params := range []*CrawlParams{
+		{MaxPages: &pages},
+		{MaxDepth: &depth},
+		{SameDomainOnly: &sameDomain},
+		{MaxBytes: &unlimited},
+		{MaxBytes: &tooMany},
+	} {
+		_, err := s.crawlLimits(params)
+		assert.Error(t, err)
+	}
+
+	// An unbounded server allows an unbounded run
+	s.crawl = &config.CrawlConfig{MaxPages: 5, MaxDepth: 1, SameDomainOnly: false}
+	limits, err := s.crawlLimits(&CrawlParams{SameDomainOnly: &sameDomain, MaxBytes: &unlimited})
+	require.NoError(t, err)
+	assert.False(t, limits.SameDomainOnly)
+	assert.Zero(t, limits.MaxBytes)
+}
+
+// TestCrawlLimits_Invalid tests that out-of-range crawl params are rejected
+func TestCrawlLimits_Invalid(t *testing.T) {
+	s := newTestServer()
+	s.crawl = &config.CrawlConfig{MaxPages: 5, MaxDepth: 1, SameDomainOnly: true}
+
+	zero, negative, negativeBytes := 0, -1, int64(-1)
+	_, err := s.crawlLimits(&CrawlParams{MaxPages: &zero})
+	assert.Error(t, err)
+	_, err = s.crawlLimits(&CrawlParams{MaxDepth: &negative})
+	assert.Error(t, err)
+	_, err = s.crawlLimits(&CrawlParams{MaxBytes: &negativeBytes})
+	assert.Error(t, err)
+}
+
+func TestHandleRun_UnsupportedStyle(t *testing.T) {
+	s := newTestServer()
+	body := `{"job_url":"https://example.com/job","user_id":"` + uuid.New().String() + `",` +
+		`"template":"../../templates/one_page_resume.tex","style":{"font_family":"comic-sans"}}`
+
+	w := httptest.NewRecorder()
+	s.handleRun(w, httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `font_family \"comic-sans\" is not supported by this template`)
+
+	w = httptest.NewRecorder()
+	s.handleRunStream(w, httptest.NewRequest(http.MethodPost, "/run/stream", strings.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
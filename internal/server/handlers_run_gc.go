@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// runGCInterval is how often abandoned runs are marked and deleted
+const runGCInterval = time.Hour
+
+// RunGCStatsResponse reports run garbage collection since the server started
+type RunGCStatsResponse struct {
+	AbandonDays   int              `json:"abandon_days"`
+	RetentionDays int              `json:"retention_days"`
+	Passes        int64            `json:"passes"`
+	Abandoned     int64            `json:"abandoned"` // Runs marked abandoned
+	Reclaimed     db.ReclaimedRuns `json:"reclaimed"` // Rows deleted
+	Errors        int64            `json:"errors"`
+	LastPassAt    *time.Time       `json:"last_pass_at,omitempty"`
+	LastError     string           `json:"last_error,omitempty"`
+
+	ArtifactStorage *db.ArtifactStorage `json:"artifact_storage,omitempty"` // Bytes saved by storing artifact payloads once
+}
+
+// runGCStats accumulates the outcome of garbage collection passes
+type runGCStats struct {
+	mu         sync.Mutex
+	passes     int64
+	abandoned  int64
+	reclaimed  db.ReclaimedRuns
+	errors     int64
+	lastPassAt *time.Time
+	lastError  string
+}
+
+// record adds the outcome of one pass
+func (st *runGCStats) record(abandoned int64, reclaimed *db.ReclaimedRuns, err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	now := time.Now().UTC()
+	st.passes++
+	st.lastPassAt = &now
+	st.abandoned += abandoned
+	if reclaimed != nil {
+		st.reclaimed.Runs += reclaimed.Runs
+		st.reclaimed.Steps += reclaimed.Steps
+		st.reclaimed.Artifacts += reclaimed.Artifacts
+		st.reclaimed.Blobs += reclaimed.Blobs
+	}
+	st.lastError = ""
+	if err != nil {
+		st.errors++
+		st.lastError = err.Error()
+	}
+}
+
+// runRunGC periodically marks runs created but never executed as abandoned and deletes
+// abandoned runs past their retention window, along with expired idempotency keys
+func (s *Server) runRunGC(ctx context.Context) {
+	if s.runGC == nil {
+		return
+	}
+
+	ticker := time.NewTicker(runGCInterval)
+	defer ticker.Stop()
+
+	for {
+		s.collectRuns(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// collectRuns performs a single garbage collection pass
+func (s *Server) collectRuns(ctx context.Context) {
+	abandoned, err := s.db.MarkAbandonedRuns(ctx, s.runGC.AbandonAfter())
+	if err != nil {
+		slog.WarnContext(ctx, "run garbage collection failed", "error", err)
+		s.runGCStats.record(0, nil, err)
+		return
+	}
+	reclaimed, err := s.db.DeleteAbandonedRuns(ctx, s.runGC.Retention())
+	if err != nil {
+		slog.WarnContext(ctx, "run garbage collection failed", "error", err)
+	}
+	s.runGCStats.record(abandoned, reclaimed, err)
+
+	if abandoned > 0 {
+		slog.InfoContext(ctx, "Marked idle runs "+db.RunStatusAbandoned, "runs", abandoned)
+	}
+	if reclaimed != nil && reclaimed.Runs > 0 {
+		slog.InfoContext(ctx, "Deleted "+db.RunStatusAbandoned+" runs",
+			"runs", reclaimed.Runs, "steps", reclaimed.Steps, "artifacts", reclaimed.Artifacts)
+	}
+	if reclaimed != nil && reclaimed.Blobs > 0 {
+		slog.InfoContext(ctx, "Deleted unreferenced artifact blobs", "blobs", reclaimed.Blobs)
+	}
+
+	if keys, err := s.db.DeleteExpiredIdempotencyKeys(ctx); err != nil {
+		slog.WarnContext(ctx, "Failed to delete expired idempotency keys", "error", err)
+	} else if keys > 0 {
+		slog.InfoContext(ctx, "Deleted expired idempotency keys", "keys", keys)
+	}
+}
+
+// handleRunGCStats reports run garbage collection metrics to admins
+func (s *Server) handleRunGCStats(w http.ResponseWriter, r *http.Request) {
+	if !s.callerIsAdmin(w, r, "view run garbage collection") {
+		return
+	}
+
+	st := &s.runGCStats
+	st.mu.Lock()
+	resp := RunGCStatsResponse{
+		Passes:     st.passes,
+		Abandoned:  st.abandoned,
+		Reclaimed:  st.reclaimed,
+		Errors:     st.errors,
+		LastPassAt: st.lastPassAt,
+		LastError:  st.lastError,
+	}
+	st.mu.Unlock()
+	if s.runGC != nil {
+		resp.AbandonDays = s.runGC.AbandonDays
+		resp.RetentionDays = s.runGC.RetentionDays
+	}
+	var err error
+	if resp.ArtifactStorage, err = s.db.GetArtifactStorage(r.Context()); err != nil {
+		slog.WarnContext(r.Context(), "Failed to read artifact storage", "error", err)
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
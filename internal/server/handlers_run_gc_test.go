@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/fetch"
+)
+
+func TestRunGC(t *testing.T) {
+	admin := uuid.New()
+	s := newAuthTestServer(t, admin)
+	s.runGC = &config.RunGCConfig{AbandonDays: 7, RetentionDays: 30}
+
+	s.mock.runGCMarked = 3
+	s.mock.runGCReclaimed = &db.ReclaimedRuns{Runs: 2, Steps: 5, Artifacts: 9, Blobs: 4}
+	s.mock.storage = &db.ArtifactStorage{Artifacts: 12, Blobs: 5, LogicalBytes: 9000, StoredBytes: 4000}
+	s.collectRuns(context.Background())
+	s.collectRuns(context.Background())
+
+	s.mock.runGCErr = errors.New("connection refused")
+	s.collectRuns(context.Background())
+
+	get := func(caller uuid.UUID) (int, RunGCStatsResponse) {
+		t.Helper()
+		w := serveAuthed(t, s, "GET /v1/admin/run-gc", s.handleRunGCStats,
+			bearerRequest(t, s, http.MethodGet, "/v1/admin/run-gc", caller, nil))
+		var resp RunGCStatsResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp
+	}
+
+	code, resp := get(admin)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 7, resp.AbandonDays)
+	assert.Equal(t, 30, resp.RetentionDays)
+	assert.Equal(t, int64(3), resp.Passes)
+	assert.Equal(t, int64(6), resp.Abandoned, "a failed pass reclaims nothing")
+	assert.Equal(t, db.ReclaimedRuns{Runs: 4, Steps: 10, Artifacts: 18, Blobs: 8}, resp.Reclaimed)
+	assert.Equal(t, s.mock.storage, resp.ArtifactStorage)
+	assert.Equal(t, int64(1), resp.Errors)
+	assert.Equal(t, "connection refused", resp.LastError)
+	assert.NotNil(t, resp.LastPassAt)
+
+	code, _ = get(uuid.New())
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func TestFetchStats(t *testing.T) {
+	admin := uuid.New()
+	s := newAuthTestServer(t, admin)
+
+	get := func(caller uuid.UUID) *httptest.ResponseRecorder {
+		t.Helper()
+		return serveAuthed(t, s, "GET /v1/admin/fetch-stats", s.handleFetchStats,
+			bearerRequest(t, s, http.MethodGet, "/v1/admin/fetch-stats", caller, nil))
+	}
+
+	w := get(admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	var stats fetch.SizeStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, fetch.Stats(), stats)
+
+	assert.Equal(t, http.StatusForbidden, get(uuid.New()).Code)
+}
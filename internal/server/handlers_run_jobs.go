@@ -0,0 +1,260 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/pipeline"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/scheduler"
+)
+
+// runJobSubmitRetryInterval is how long a claimed run job waits before trying again
+// to join its owner's full run queue
+const runJobSubmitRetryInterval = 5 * time.Second
+
+// RunJobStatus reports the background job executing a run created with "execute": true
+type RunJobStatus struct {
+	Status   string  `json:"status"` // queued, running, completed, or failed
+	Attempts int     `json:"attempts"`
+	Error    *string `json:"error,omitempty"`
+}
+
+// enqueueRun creates a queued run and hands it to the run workers. The response
+// returns at once; progress is followed with GET /v1/runs/{run_id}/steps.
+func (s *Server) enqueueRun(w http.ResponseWriter, r *http.Request, userID uuid.UUID, req *RunCreateRequest) {
+	ctx := r.Context()
+	priority, err := scheduler.ParsePriority(req.Priority, scheduler.PriorityNormal)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Priority = priority.String()
+	if _, err := s.crawlLimits(req.Crawl); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Debug {
+		if err := s.authorizeDebug(r, &userID); err != nil {
+			s.errorResponse(w, HTTPStatus(err), err.Error())
+			return
+		}
+	}
+	full, err := s.runJobQueueFull(ctx, userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to check run queue: "+err.Error())
+		return
+	}
+	if full {
+		s.runQueueFullResponse(w, userID)
+		return
+	}
+
+	runID, err := s.queueRun(ctx, userID, req)
+	if err != nil {
+		s.errorResponse(w, prepareRunStatus(err), "Failed to queue run: "+err.Error())
+		return
+	}
+
+	available, err := steps.GetAvailableSteps(ctx, s.db, runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to get available steps: "+err.Error())
+		return
+	}
+	blocked, err := steps.GetBlockedSteps(ctx, s.db, runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to get blocked steps: "+err.Error())
+		return
+	}
+
+	s.jsonResponse(w, http.StatusAccepted, RunCreateResponse{
+		RunID:       runID.String(),
+		Status:      db.RunJobStatusQueued,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		RunType:     req.RunType,
+		SourceRunID: req.SourceRunID,
+		Steps: RunStepsStatus{
+			Completed: []string{},
+			Available: steps.FilterRunType(req.RunType, available),
+			Blocked:   steps.FilterRunType(req.RunType, blocked),
+		},
+	})
+}
+
+// runJobQueueFull reports whether the user already has as many runs waiting as the
+// scheduler allows, counting run jobs not yet claimed by a worker
+func (s *Server) runJobQueueFull(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if s.scheduler.QueueFull(userID) {
+		return true, nil
+	}
+	_, maxQueued := s.scheduler.Limits()
+	if maxQueued == 0 {
+		return false, nil
+	}
+	queued, err := s.db.CountQueuedRunJobs(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return queued >= maxQueued, nil
+}
+
+// queueRun creates a queued run owned by userID and hands it to the run workers at
+// the request's priority. A cover-letter or refresh run is prepared from its source
+// run first. A run that cannot be queued is canceled.
+func (s *Server) queueRun(ctx context.Context, userID uuid.UUID, req *RunCreateRequest) (uuid.UUID, error) {
+	priority, err := scheduler.ParsePriority(req.Priority, scheduler.PriorityNormal)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	runID, err := s.db.CreateQueuedRun(ctx, req.JobURL)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("create run: %w", err)
+	}
+	if err := s.db.SetRunUserID(ctx, runID, userID); err != nil {
+		s.cancelQueuedRun(ctx, &runID)
+		return uuid.Nil, fmt.Errorf("set run owner: %w", err)
+	}
+	if _, typed := steps.RunTypes[req.RunType]; typed {
+		if err := s.prepareTypedRun(ctx, runID, req); err != nil {
+			s.cancelQueuedRun(ctx, &runID)
+			return uuid.Nil, fmt.Errorf("prepare run: %w", err)
+		}
+	}
+	if _, err := s.db.EnqueueRunJob(ctx, runID, userID, int(priority), req); err != nil {
+		s.cancelQueuedRun(ctx, &runID)
+		return uuid.Nil, fmt.Errorf("enqueue run job: %w", err)
+	}
+	return runID, nil
+}
+
+// executeRunJob is the run workers' executor: it runs the pipeline for a run queued
+// by POST /v1/runs, which records each step in run_steps as it goes. A run that
+// cannot start or stops early is marked failed; one interrupted by shutdown is left
+// running for the next worker to resume.
+func (s *Server) executeRunJob(ctx context.Context, job *db.RunJob) error {
+	ctx = logging.WithRunID(ctx, job.RunID)
+	if job.UserID != nil {
+		ctx = logging.WithUserID(ctx, *job.UserID)
+	}
+	err := s.runQueuedPipeline(ctx, job)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	if cerr := s.db.CompleteRun(context.WithoutCancel(ctx), job.RunID, "failed"); cerr != nil {
+		slog.WarnContext(ctx, "failed to mark run failed", "error", cerr)
+	}
+	return err
+}
+
+// runQueuedPipeline builds the pipeline options for a queued run from its stored
+// request and the owner's current profile and experience bank, then runs it once the
+// run scheduler grants the owner a slot, so queued runs share the per-user limits of
+// runs started directly. The run stays queued while it waits.
+func (s *Server) runQueuedPipeline(ctx context.Context, job *db.RunJob) error {
+	var req RunCreateRequest
+	if err := json.Unmarshal(job.Payload, &req); err != nil {
+		return fmt.Errorf("invalid run job payload: %w", err)
+	}
+	if job.UserID == nil {
+		return errors.New("run job has no owner")
+	}
+	uid := *job.UserID
+
+	user, err := s.db.GetUser(ctx, uid)
+	if err != nil {
+		return fmt.Errorf("failed to fetch user profile: %w", err)
+	}
+	if user == nil {
+		return errors.New("run owner no longer exists")
+	}
+	expData, err := s.fetchExperienceBankFromDB(ctx, uid)
+	if err != nil {
+		return fmt.Errorf("failed to fetch experience data: %w", err)
+	}
+	priority, err := scheduler.ParsePriority(req.Priority, scheduler.PriorityNormal)
+	if err != nil {
+		return err
+	}
+	crawl, err := s.crawlLimits(req.Crawl)
+	if err != nil {
+		return err
+	}
+
+	opts := pipeline.RunOptions{
+		JobURL:         req.JobURL,
+		JobText:        req.JobText,
+		ExperienceData: expData,
+		TemplatePath:   req.Template,
+		CandidateName:  user.Name,
+		CandidateEmail: user.Email,
+		CandidatePhone: user.Phone,
+		MaxBullets:     req.MaxBullets,
+		MaxLines:       req.MaxLines,
+		MaxPages:       req.MaxPages,
+		APIKey:         s.apiKey,
+		DatabaseURL:    s.databaseURL,
+		Verbose:        true,
+		ExistingRunID:  &job.RunID,
+		RunStartedSent: true,
+		UserID:         &uid,
+		Priority:       priority.String(),
+		Debug:          req.Debug, // Authorized when the run was queued
+		Crawl:          crawl,
+		ModelRouting:   s.routing,
+		ModelDowngrade: s.modelDowngrade(ctx, uid),
+		RedactPII:      s.redactPII(ctx, uid),
+		Demo:           s.demo,
+		RunType:        req.RunType,
+	}
+
+	var runErr error
+	started := false
+	task := scheduler.Task{
+		UserID:   uid,
+		Priority: priority,
+		Run: func(ctx context.Context) {
+			started = true
+			if err := s.db.StartRun(ctx, job.RunID); err != nil {
+				slog.WarnContext(ctx, "failed to mark run started", "error", err)
+			}
+			runErr = pipeline.RunPipeline(ctx, opts)
+		},
+	}
+	for {
+		ticket, err := s.scheduler.Submit(ctx, task)
+		if err == nil {
+			<-ticket.Done()
+			break
+		}
+		// The owner's queue is full of runs started directly; wait for room
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(runJobSubmitRetryInterval):
+		}
+	}
+	if !started {
+		if err := ctx.Err(); err != nil {
+			return err // Shut down while waiting for a slot
+		}
+		return errors.New("run was dropped before it started")
+	}
+	return runErr
+}
+
+// runJobStatus returns the background job status of a run, or nil if it was not queued
+func (s *Server) runJobStatus(ctx context.Context, runID uuid.UUID) (*RunJobStatus, error) {
+	job, err := s.db.GetRunJobByRun(ctx, runID)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	return &RunJobStatus{Status: job.Status, Attempts: job.Attempts, Error: job.ErrorMessage}, nil
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCreateRun_Execute(t *testing.T) {
+	s := newTestServer()
+	user := uuid.New()
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Ada", Email: "ada@example.com"}}
+
+	body, _ := json.Marshal(RunCreateRequest{UserID: user.String(), JobText: "Staff SRE at Acme", Execute: true})
+	w := httptest.NewRecorder()
+	s.handleCreateRun(w, httptest.NewRequest(http.MethodPost, "/v1/runs", bytes.NewReader(body)))
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+	var resp RunCreateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, db.RunJobStatusQueued, resp.Status)
+	runID := uuid.MustParse(resp.RunID)
+
+	run := s.mock.runs[runID]
+	require.NotNil(t, run)
+	assert.Equal(t, "queued", run.Status)
+	require.NotNil(t, run.UserID)
+	assert.Equal(t, user, *run.UserID)
+
+	job := s.mock.runJobs[runID]
+	require.NotNil(t, job, "the run is handed to the run workers")
+	var queued RunCreateRequest
+	require.NoError(t, json.Unmarshal(job.Payload, &queued))
+	assert.Equal(t, "Staff SRE at Acme", queued.JobText)
+	assert.Equal(t, 25, queued.MaxBullets, "defaults are applied before queueing")
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/"+runID.String()+"/steps", nil)
+	req.SetPathValue("run_id", runID.String())
+	w = httptest.NewRecorder()
+	s.handleListRunSteps(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var steps RunStepsListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &steps))
+	assert.Equal(t, "queued", steps.Status)
+	require.NotNil(t, steps.Job)
+	assert.Equal(t, db.RunJobStatusQueued, steps.Job.Status)
+}
+
+func TestExecuteRunJob_FailsRunThatCannotStart(t *testing.T) {
+	s := newTestServer()
+	runID, err := s.mock.CreateQueuedRun(context.Background(), "")
+	require.NoError(t, err)
+	owner := uuid.New() // Deleted before the run was claimed
+
+	job := &db.RunJob{ID: uuid.New(), RunID: runID, UserID: &owner, Payload: json.RawMessage(`{"job_text":"SRE"}`)}
+	err = s.executeRunJob(context.Background(), job)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no longer exists")
+	assert.Equal(t, "failed", s.mock.runs[runID].Status)
+}
+
+func TestExecuteRunJob_LeavesInterruptedRun(t *testing.T) {
+	s := newTestServer()
+	runID, err := s.mock.CreateQueuedRun(context.Background(), "")
+	require.NoError(t, err)
+	owner := uuid.New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	job := &db.RunJob{ID: uuid.New(), RunID: runID, UserID: &owner, Payload: json.RawMessage(`{}`)}
+	require.Error(t, s.executeRunJob(ctx, job))
+	assert.Equal(t, "queued", s.mock.runs[runID].Status, "the next worker resumes it")
+}
+
+func TestHandleCreateRun_ExecuteOptions(t *testing.T) {
+	s := newAuthTestServer(t)
+	user := uuid.New()
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Ada", Email: "ada@example.com"}}
+	create := func(req RunCreateRequest, caller *uuid.UUID) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		r := httptest.NewRequest(http.MethodPost, "/v1/runs", bytes.NewReader(body))
+		if caller != nil {
+			r = bearerRequest(t, s, http.MethodPost, "/v1/runs", *caller, body)
+		}
+		w := httptest.NewRecorder()
+		s.handleCreateRun(w, r)
+		return w
+	}
+	pages := 3
+
+	w := create(RunCreateRequest{UserID: user.String(), JobText: "SRE", Execute: true, Priority: "bulk", Debug: true,
+		Crawl: &CrawlParams{MaxPages: &pages}}, &user)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var resp RunCreateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	job := s.mock.runJobs[uuid.MustParse(resp.RunID)]
+	require.NotNil(t, job)
+	assert.Equal(t, int(scheduler.PriorityBulk), job.Priority)
+	var queued RunCreateRequest
+	require.NoError(t, json.Unmarshal(job.Payload, &queued))
+	assert.Equal(t, "bulk", queued.Priority)
+	assert.True(t, queued.Debug)
+	require.NotNil(t, queued.Crawl)
+	assert.Equal(t, 3, *queued.Crawl.MaxPages)
+
+	assert.Equal(t, http.StatusBadRequest, create(RunCreateRequest{UserID: user.String(), JobText: "SRE", Execute: true, Priority: "urgent"}, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, create(RunCreateRequest{UserID: user.String(), JobText: "SRE", Priority: "bulk"}, nil).Code,
+		"options of queued runs are rejected on step-by-step runs")
+	other := uuid.New()
+	assert.Equal(t, http.StatusForbidden, create(RunCreateRequest{UserID: user.String(), JobText: "SRE", Execute: true, Debug: true}, &other).Code)
+}
+
+func TestHandleCreateRun_ExecuteQueueFull(t *testing.T) {
+	s := newTestServer()
+	s.scheduler = scheduler.New(4, 1, 1)
+	user := uuid.New()
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Ada", Email: "ada@example.com"}}
+
+	for i, want := range []int{http.StatusAccepted, http.StatusTooManyRequests} {
+		body, _ := json.Marshal(RunCreateRequest{UserID: user.String(), JobText: "SRE", Execute: true})
+		w := httptest.NewRecorder()
+		s.handleCreateRun(w, httptest.NewRequest(http.MethodPost, "/v1/runs", bytes.NewReader(body)))
+		assert.Equal(t, want, w.Code, "run %d", i+1)
+	}
+}
+
+func TestExecuteRunJob_WaitsForSchedulerSlot(t *testing.T) {
+	s := newTestServer()
+	s.scheduler = scheduler.New(4, 1, 0)
+	user := uuid.New()
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Ada", Email: "ada@example.com"}}
+	runID, err := s.mock.CreateQueuedRun(context.Background(), "")
+	require.NoError(t, err)
+
+	// Another of the user's runs holds their only slot
+	release := make(chan struct{})
+	defer close(release)
+	_, err = s.scheduler.Submit(context.Background(), scheduler.Task{UserID: user, Run: func(context.Context) { <-release }})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	job := &db.RunJob{ID: uuid.New(), RunID: runID, UserID: &user, Payload: json.RawMessage(`{"job_text":"SRE","priority":"bulk"}`)}
+	require.ErrorIs(t, s.executeRunJob(ctx, job), context.DeadlineExceeded)
+	assert.Equal(t, "queued", s.mock.runs[runID].Status, "the run never started and is left for the next worker")
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/recipes"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+)
+
+const (
+	// maxRecipeDescriptionLength caps a recipe's description
+	maxRecipeDescriptionLength = 500
+	// maxRecipeBullets and maxRecipeLines cap a recipe's length limits at well past a
+	// two-page resume
+	maxRecipeBullets = 60
+	maxRecipeLines   = 120
+)
+
+// RunRecipeRequest is the request body for creating or replacing a run recipe
+type RunRecipeRequest struct {
+	Name           string           `json:"name"`
+	Description    string           `json:"description,omitempty"`
+	Template       string           `json:"template,omitempty"`
+	MaxBullets     int              `json:"max_bullets,omitempty"`
+	MaxLines       int              `json:"max_lines,omitempty"`
+	SectionOrder   []string         `json:"section_order,omitempty"`
+	RankingWeights *ranking.Weights `json:"ranking_weights,omitempty"`
+}
+
+// RunRecipeListResponse is the response for listing run recipes
+type RunRecipeListResponse struct {
+	BuiltIn []recipes.Recipe `json:"built_in"`
+	Recipes []db.RunRecipe   `json:"recipes"` // The user's own; these shadow built-in recipes with the same name
+}
+
+// handleListRunRecipes lists the built-in run recipes and the caller's own
+func (s *Server) handleListRunRecipes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "run recipes")
+	if !ok {
+		return
+	}
+
+	own, err := s.db.ListRunRecipes(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, RunRecipeListResponse{BuiltIn: recipes.BuiltIn(), Recipes: own})
+}
+
+// handleSaveRunRecipe creates a run recipe, or replaces the caller's recipe with the
+// same name
+func (s *Server) handleSaveRunRecipe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "run recipes")
+	if !ok {
+		return
+	}
+
+	var req RunRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	input := &db.RunRecipeInput{
+		Name:         strings.TrimSpace(req.Name),
+		Description:  strings.TrimSpace(req.Description),
+		Template:     strings.TrimSpace(req.Template),
+		MaxBullets:   req.MaxBullets,
+		MaxLines:     req.MaxLines,
+		SectionOrder: req.SectionOrder,
+	}
+	if err := validateRunRecipe(input, req.RankingWeights); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if status, err := s.validateTemplateSections(r.Context(), userID, input.Template, input.SectionOrder); err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+	if req.RankingWeights != nil {
+		weights, err := json.Marshal(req.RankingWeights)
+		if err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, "Failed to encode ranking weights: "+err.Error())
+			return
+		}
+		input.RankingWeights = weights
+	}
+
+	recipe, err := s.db.UpsertRunRecipe(r.Context(), userID, input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, recipe)
+}
+
+// handleDeleteRunRecipe deletes one of the caller's run recipes
+func (s *Server) handleDeleteRunRecipe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "run recipes")
+	if !ok {
+		return
+	}
+	recipeID, err := uuid.Parse(r.PathValue("recipe_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid recipe ID")
+		return
+	}
+
+	recipe, err := s.db.GetRunRecipe(r.Context(), recipeID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if recipe == nil || recipe.UserID != userID {
+		s.errorResponse(w, http.StatusNotFound, "Run recipe not found")
+		return
+	}
+	if err := s.db.DeleteRunRecipe(r.Context(), recipeID); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateRunRecipe checks a recipe's fields and ranking weights;
+// validateTemplateSections checks its template
+func validateRunRecipe(input *db.RunRecipeInput, weights *ranking.Weights) error {
+	if input.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(input.Name) > maxPresetNameLength {
+		return fmt.Errorf("name must be at most %d characters", maxPresetNameLength)
+	}
+	if len(input.Description) > maxRecipeDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", maxRecipeDescriptionLength)
+	}
+	if input.MaxBullets < 0 || input.MaxBullets > maxRecipeBullets {
+		return fmt.Errorf("max_bullets must be between 1 and %d", maxRecipeBullets)
+	}
+	if input.MaxLines < 0 || input.MaxLines > maxRecipeLines {
+		return fmt.Errorf("max_lines must be between 1 and %d", maxRecipeLines)
+	}
+	if weights != nil {
+		if err := weights.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupRunRecipe returns the user's recipe with the given name, or else the built-in
+// one, or nil if neither exists
+func (s *Server) lookupRunRecipe(ctx context.Context, userID uuid.UUID, name string) (*recipes.Recipe, error) {
+	own, err := s.db.GetRunRecipeByName(ctx, userID, name)
+	if err != nil {
+		return nil, err
+	}
+	if own == nil {
+		if builtIn, ok := recipes.LookupBuiltIn(name); ok {
+			return &builtIn, nil
+		}
+		return nil, nil
+	}
+
+	recipe := &recipes.Recipe{Name: own.Name, SectionOrder: own.SectionOrder}
+	if own.Description != nil {
+		recipe.Description = *own.Description
+	}
+	if own.Template != nil {
+		recipe.Template = *own.Template
+	}
+	if own.MaxBullets != nil {
+		recipe.MaxBullets = *own.MaxBullets
+	}
+	if own.MaxLines != nil {
+		recipe.MaxLines = *own.MaxLines
+	}
+	if len(own.RankingWeights) > 0 {
+		if err := json.Unmarshal(own.RankingWeights, &recipe.RankingWeights); err != nil {
+			return nil, fmt.Errorf("failed to decode ranking weights of recipe %q: %w", own.Name, err)
+		}
+	}
+	return recipe, nil
+}
+
+// applyRunRecipe fills the run options req leaves unset from the run recipe it names.
+// It runs after applyCompanyPreset, so options set in the request or its company preset
+// win. It returns the HTTP status to fail with.
+func (s *Server) applyRunRecipe(ctx context.Context, req *RunRequest) (int, error) {
+	if req.RankingWeights != nil {
+		if err := req.RankingWeights.Validate(); err != nil {
+			return http.StatusBadRequest, err
+		}
+	}
+	name := strings.TrimSpace(req.Recipe)
+	if name == "" {
+		return 0, nil
+	}
+	// handleRun rejects a malformed user_id itself; here it only finds no recipes of its own
+	userID, _ := uuid.Parse(req.UserID)
+	recipe, err := s.lookupRunRecipe(ctx, userID, name)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to fetch run recipe: %w", err)
+	}
+	if recipe == nil {
+		return http.StatusNotFound, fmt.Errorf("run recipe %q not found", name)
+	}
+
+	if req.Template == "" {
+		req.Template = recipe.Template
+	}
+	if req.MaxBullets == 0 {
+		req.MaxBullets = recipe.MaxBullets
+	}
+	if req.MaxLines == 0 {
+		req.MaxLines = recipe.MaxLines
+	}
+	if req.RankingWeights == nil {
+		req.RankingWeights = recipe.RankingWeights
+	}
+	if len(recipe.SectionOrder) > 0 && (req.Style == nil || len(req.Style.SectionOrder) == 0) {
+		style := rendering.Style{}
+		if req.Style != nil {
+			style = *req.Style
+		}
+		style.SectionOrder = recipe.SectionOrder
+		req.Style = &style
+	}
+	return 0, nil
+}
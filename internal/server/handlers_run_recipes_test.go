@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/recipes"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+)
+
+func TestHandleSaveRunRecipe(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	target := "/v1/users/" + owner.String() + "/recipes"
+	save := func(caller uuid.UUID, body string) int {
+		return serveAuthed(t, s, "POST /v1/users/{id}/recipes", s.handleSaveRunRecipe,
+			bearerRequest(t, s, http.MethodPost, target, caller, []byte(body))).Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, save(uuid.New(), `{"name":"Staff"}`))
+	assert.Equal(t, http.StatusBadRequest, save(owner, `{"description":"no name"}`))
+	assert.Equal(t, http.StatusBadRequest, save(owner, `{"name":"Staff","max_bullets":-1}`))
+	assert.Equal(t, http.StatusBadRequest, save(owner, `{"name":"Staff","ranking_weights":{"recency":-1}}`))
+	assert.Equal(t, http.StatusBadRequest, save(owner, `{"name":"Staff","ranking_weights":{}}`))
+	assert.Equal(t, http.StatusBadRequest, save(owner,
+		`{"name":"Staff","template":"`+presetTemplate+`","section_order":["projects"]}`))
+
+	body := `{"name":"Staff","description":"Impact first","template":"` + presetTemplate +
+		`","max_bullets":20,"section_order":["experience"],"ranking_weights":{"evidence_strength":1}}`
+	require.Equal(t, http.StatusOK, save(owner, body))
+	require.Equal(t, http.StatusOK, save(owner, `{"name":"staff","max_lines":30}`))
+	require.Len(t, s.mock.recipes, 1, "saving under the same name replaces the recipe")
+	assert.Equal(t, 30, *s.mock.recipes[0].MaxLines)
+	assert.Nil(t, s.mock.recipes[0].MaxBullets)
+
+	w := serveAuthed(t, s, "GET /v1/users/{id}/recipes", s.handleListRunRecipes,
+		bearerRequest(t, s, http.MethodGet, target, owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var list RunRecipeListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Len(t, list.BuiltIn, len(recipes.BuiltIn()))
+	require.Len(t, list.Recipes, 1)
+
+	del := func(caller uuid.UUID) int {
+		url := "/v1/users/" + caller.String() + "/recipes/" + list.Recipes[0].ID.String()
+		return serveAuthed(t, s, "DELETE /v1/users/{id}/recipes/{recipe_id}", s.handleDeleteRunRecipe,
+			bearerRequest(t, s, http.MethodDelete, url, caller, nil)).Code
+	}
+	assert.Equal(t, http.StatusNotFound, del(uuid.New()))
+	assert.Equal(t, http.StatusNoContent, del(owner))
+	assert.Empty(t, s.mock.recipes)
+}
+
+func TestApplyRunRecipe(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	newGrad, _ := recipes.LookupBuiltIn("new-grad")
+
+	req := &RunRequest{UserID: owner.String(), Recipe: "new-grad", MaxLines: 40, Style: &rendering.Style{FontSize: "11pt"}}
+	status, err := s.applyRunRecipe(context.Background(), req)
+	require.NoError(t, err, status)
+	assert.Equal(t, recipes.DefaultTemplate, req.Template)
+	assert.Equal(t, newGrad.MaxBullets, req.MaxBullets)
+	assert.Equal(t, 40, req.MaxLines, "options in the request win over the recipe's")
+	assert.Equal(t, newGrad.RankingWeights, req.RankingWeights)
+	assert.Equal(t, &rendering.Style{FontSize: "11pt", SectionOrder: []string{"education", "experience"}}, req.Style)
+
+	// A user's own recipe shadows the built-in one with the same name
+	weights := json.RawMessage(`{"recency":1}`)
+	maxBullets := 12
+	s.mock.recipes = []*db.RunRecipe{{ID: uuid.New(), UserID: owner, Name: "New-Grad", MaxBullets: &maxBullets, RankingWeights: weights}}
+	req = &RunRequest{UserID: owner.String(), Recipe: "new-grad"}
+	_, err = s.applyRunRecipe(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 12, req.MaxBullets)
+	assert.Equal(t, &ranking.Weights{Recency: 1}, req.RankingWeights)
+	assert.Nil(t, req.Style)
+
+	req = &RunRequest{UserID: uuid.New().String(), Recipe: "new-grad", RankingWeights: &ranking.Weights{SkillOverlap: 1}}
+	_, err = s.applyRunRecipe(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, newGrad.MaxBullets, req.MaxBullets, "recipes of other users are not applied")
+	assert.Equal(t, &ranking.Weights{SkillOverlap: 1}, req.RankingWeights)
+
+	status, _ = s.applyRunRecipe(context.Background(), &RunRequest{UserID: owner.String(), Recipe: "astronaut"})
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = s.applyRunRecipe(context.Background(), &RunRequest{UserID: owner.String(), RankingWeights: &ranking.Weights{}})
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/pipeline"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+// createTypedRun creates a cover-letter or refresh run that reuses a source run's
+// artifacts, for step-by-step execution or, with "execute": true, queued for the run
+// workers
+func (s *Server) createTypedRun(w http.ResponseWriter, r *http.Request, userID uuid.UUID, req *RunCreateRequest) {
+	ctx := r.Context()
+	source, status, err := s.resolveSourceRun(ctx, userID, req.SourceRunID)
+	if err != nil {
+		s.errorResponse(w, status, err.Error())
+		return
+	}
+	req.SourceRunID = source.ID.String()
+
+	if req.Execute {
+		s.enqueueRun(w, r, userID, req)
+		return
+	}
+
+	runID, err := s.db.CreateRun(ctx, source.Company, source.RoleTitle, source.JobURL)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to create run: "+err.Error())
+		return
+	}
+	err = s.db.SetRunUserID(ctx, runID, userID)
+	if err == nil {
+		err = s.prepareTypedRun(ctx, runID, req)
+	}
+	if err != nil {
+		_ = s.db.DeleteRun(context.WithoutCancel(ctx), runID)
+		s.errorResponse(w, prepareRunStatus(err), "Failed to prepare run: "+err.Error())
+		return
+	}
+
+	available, err := steps.GetAvailableSteps(ctx, s.db, runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to get available steps: "+err.Error())
+		return
+	}
+	blocked, err := steps.GetBlockedSteps(ctx, s.db, runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to get blocked steps: "+err.Error())
+		return
+	}
+
+	s.jsonResponse(w, http.StatusCreated, RunCreateResponse{
+		RunID:       runID.String(),
+		Status:      "created",
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		RunType:     req.RunType,
+		SourceRunID: req.SourceRunID,
+		Steps: RunStepsStatus{
+			Completed: []string{},
+			Available: steps.FilterRunType(req.RunType, available),
+			Blocked:   steps.FilterRunType(req.RunType, blocked),
+		},
+	})
+}
+
+// resolveSourceRun returns the run a cover-letter or refresh run reuses: sourceRunID,
+// which must belong to the user, or else the user's latest run that rendered a resume.
+// It returns the HTTP status to fail with.
+func (s *Server) resolveSourceRun(ctx context.Context, userID uuid.UUID, sourceRunID string) (*db.Run, int, error) {
+	if sourceRunID == "" {
+		source, err := s.db.GetLatestResumeRun(ctx, userID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("database error: %w", err)
+		}
+		if source == nil {
+			return nil, http.StatusNotFound, errors.New("no run with a rendered resume to reuse")
+		}
+		return source, 0, nil
+	}
+
+	id, err := uuid.Parse(sourceRunID)
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("invalid source_run_id format")
+	}
+	source, err := s.db.GetRun(ctx, id)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database error: %w", err)
+	}
+	if source == nil {
+		return nil, http.StatusNotFound, errors.New("source run not found")
+	}
+	if source.UserID == nil || *source.UserID != userID {
+		return nil, http.StatusForbidden, errors.New("source run belongs to another user")
+	}
+	return source, 0, nil
+}
+
+// prepareTypedRun records the run's type and source and copies what its steps reuse
+// from the source run
+func (s *Server) prepareTypedRun(ctx context.Context, runID uuid.UUID, req *RunCreateRequest) error {
+	sourceID, err := uuid.Parse(req.SourceRunID)
+	if err != nil {
+		return fmt.Errorf("invalid source_run_id: %w", err)
+	}
+	if err := s.db.SetRunSource(ctx, runID, req.RunType, sourceID); err != nil {
+		return err
+	}
+	return pipeline.PrepareRunType(ctx, s.db, runID, sourceID, req.RunType)
+}
+
+// prepareRunStatus returns the HTTP status for a run that could not be prepared: a
+// conflict when the source run lacks artifacts the run type needs
+func prepareRunStatus(err error) int {
+	var srcErr *pipeline.SourceArtifactsError
+	if errors.As(err, &srcErr) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createRun(t *testing.T, s *testServer, req RunCreateRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	s.handleCreateRun(w, httptest.NewRequest(http.MethodPost, "/v1/runs", bytes.NewReader(body)))
+	return w
+}
+
+func TestHandleCreateRun_CoverLetter(t *testing.T) {
+	s := newTestServer()
+	user := uuid.New()
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Ada"}}
+
+	// The older run rendered a resume too, but the newer one is reused
+	var sourceID uuid.UUID
+	for i, company := range []string{"Initech", "Acme"} {
+		id := uuid.New()
+		s.mock.runs[id] = &db.Run{ID: id, UserID: &user, Company: company, RoleTitle: "SRE", JobURL: "https://jobs.example.com/" + company,
+			CreatedAt: time.Now().Add(time.Duration(i) * time.Hour)}
+		s.mock.textArtifacts[id.String()+":"+db.StepResumeTex] = `\documentclass{article}`
+		for _, step := range []string{db.StepJobProfile, db.StepRewrittenBullets, db.StepCompanyProfile} {
+			artifactID := uuid.New()
+			s.mock.artifacts[artifactID] = &db.Artifact{ID: artifactID, RunID: id, Step: step, Version: 2, Content: map[string]any{"from": company}}
+		}
+		sourceID = id
+	}
+
+	w := createRun(t, s, RunCreateRequest{UserID: user.String(), RunType: db.RunTypeCoverLetter, Execute: true})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var resp RunCreateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, db.RunTypeCoverLetter, resp.RunType)
+	assert.Equal(t, sourceID.String(), resp.SourceRunID)
+
+	runID := uuid.MustParse(resp.RunID)
+	run := s.mock.runs[runID]
+	require.NotNil(t, run)
+	assert.Equal(t, db.RunTypeCoverLetter, run.RunType)
+	assert.Equal(t, "Acme", run.Company)
+	assert.Equal(t, &sourceID, run.SourceRunID)
+
+	var reused []string
+	for _, step := range s.mock.runSteps[runID] {
+		assert.Equal(t, db.StepStatusCompleted, step.Status)
+		assert.Equal(t, sourceID.String(), step.Parameters["reused_from"])
+		reused = append(reused, step.Step)
+	}
+	assert.ElementsMatch(t, []string{"parse_job", "rewrite_bullets", "summarize_voice"}, reused)
+	bullets, err := s.mock.GetArtifact(t.Context(), runID, db.StepRewrittenBullets)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"from": "Acme"}`, string(bullets))
+
+	job := s.mock.runJobs[runID]
+	require.NotNil(t, job)
+	var queued RunCreateRequest
+	require.NoError(t, json.Unmarshal(job.Payload, &queued))
+	assert.Equal(t, db.RunTypeCoverLetter, queued.RunType)
+	assert.Equal(t, sourceID.String(), queued.SourceRunID)
+
+	// Refresh runs need the plan and experience bank, which this source lacks
+	w = createRun(t, s, RunCreateRequest{UserID: user.String(), RunType: db.RunTypeRefresh, SourceRunID: sourceID.String(), Execute: true})
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), db.StepResumePlan)
+}
+
+func TestHandleCreateRun_RunTypeErrors(t *testing.T) {
+	s := newTestServer()
+	user, other := uuid.New(), uuid.New()
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user}, other: {ID: other}}
+	othersRun := uuid.New()
+	s.mock.runs[othersRun] = &db.Run{ID: othersRun, UserID: &other}
+
+	tests := []struct {
+		name string
+		req  RunCreateRequest
+		want int
+	}{
+		{"unknown type", RunCreateRequest{UserID: user.String(), RunType: "resume"}, http.StatusBadRequest},
+		{"no resume to reuse", RunCreateRequest{UserID: user.String(), RunType: db.RunTypeRefresh}, http.StatusNotFound},
+		{"malformed source", RunCreateRequest{UserID: user.String(), RunType: db.RunTypeRefresh, SourceRunID: "latest"}, http.StatusBadRequest},
+		{"missing source", RunCreateRequest{UserID: user.String(), RunType: db.RunTypeRefresh, SourceRunID: uuid.NewString()}, http.StatusNotFound},
+		{"another user's source", RunCreateRequest{UserID: user.String(), RunType: db.RunTypeCoverLetter, SourceRunID: othersRun.String()}, http.StatusForbidden},
+		{"full runs still need a posting", RunCreateRequest{UserID: user.String(), RunType: db.RunTypeFull}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := createRun(t, s, tt.req)
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestHandleExecuteStep_OutsideRunType(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, RunType: db.RunTypeCoverLetter}
+
+	w := executeStepWithParams(t, s, runID, "render_latex", `{}`)
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "not part of cover_letter runs")
+	assert.Empty(t, s.mock.runSteps[runID])
+}
@@ -0,0 +1,314 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+const (
+	// maxSharedCommentAuthorLength and maxSharedCommentLength cap recipient comments
+	maxSharedCommentAuthorLength = 100
+	maxSharedCommentLength       = 2000
+	// defaultSharedAccessLogLimit and maxSharedAccessLogLimit bound the access log listing
+	defaultSharedAccessLogLimit = 100
+	maxSharedAccessLogLimit     = 1000
+)
+
+// sharedHiddenCategories are artifact categories never exposed through a share link:
+// debug artifacts hold raw LLM traffic, privacy reports list the redacted details, and
+// lifecycle artifacts describe the server's build and configuration
+var sharedHiddenCategories = map[string]bool{
+	db.CategoryDebug:     true,
+	db.CategoryPrivacy:   true,
+	db.CategoryLifecycle: true,
+}
+
+// SharedResumeAccessRequest is the request body for changing a shared resume link's scope
+// and expiry
+type SharedResumeAccessRequest struct {
+	Scope     string     `json:"scope"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Unset links never expire
+}
+
+// SharedResumeCommentRequest is the request body for commenting on a shared resume page
+type SharedResumeCommentRequest struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+}
+
+// validateSharedAccess checks a share link's scope and expiry
+func validateSharedAccess(scope string, expiresAt *time.Time) error {
+	switch scope {
+	case db.SharedScopePDF, db.SharedScopeArtifacts, db.SharedScopeComments:
+	default:
+		return errors.New(`scope must be "pdf", "artifacts", or "comments"`)
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+// sharedResumeStatus reports whether a share link is active, expired, or revoked at now
+func sharedResumeStatus(shared *db.SharedResume, now time.Time) string {
+	switch {
+	case shared.RevokedAt != nil:
+		return db.SharedAccessRevoked
+	case shared.ExpiresAt != nil && !shared.ExpiresAt.After(now):
+		return db.SharedAccessExpired
+	default:
+		return "active"
+	}
+}
+
+// sharedLink resolves the {slug} path value to a shared resume whose link is live and
+// whose scope covers required, recording the decision in the access log. Unknown slugs
+// get a 404, revoked and expired links a 410, and requests beyond the scope a 403.
+// Public responses are never indexed, and pages may not run scripts or load anything
+// beyond their own inline styles.
+func (s *Server) sharedLink(w http.ResponseWriter, r *http.Request, action, required, detail string) (*db.SharedResume, bool) {
+	w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+	w.Header().Set("Content-Security-Policy", sharedResumeCSP)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-store")
+
+	shared, err := s.db.GetSharedResumeBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return nil, false
+	}
+	if shared == nil {
+		s.errorResponse(w, http.StatusNotFound, "Page not found")
+		return nil, false
+	}
+
+	switch status := sharedResumeStatus(shared, time.Now()); status {
+	case db.SharedAccessRevoked:
+		s.logSharedAccess(r, shared, action, status, detail)
+		s.errorResponse(w, http.StatusGone, "This link has been revoked")
+		return nil, false
+	case db.SharedAccessExpired:
+		s.logSharedAccess(r, shared, action, status, detail)
+		s.errorResponse(w, http.StatusGone, "This link has expired")
+		return nil, false
+	}
+	if !db.SharedScopeAllows(shared.Scope, required) {
+		s.logSharedAccess(r, shared, action, db.SharedAccessOutOfScope, detail)
+		s.errorResponse(w, http.StatusForbidden, "This link does not include that")
+		return nil, false
+	}
+	s.logSharedAccess(r, shared, action, db.SharedAccessAllowed, detail)
+	return shared, true
+}
+
+// logSharedAccess adds an entry to a shared resume's access log; failures are logged
+// rather than failing the request
+func (s *Server) logSharedAccess(r *http.Request, shared *db.SharedResume, action, outcome, detail string) {
+	err := s.db.RecordSharedResumeAccess(r.Context(), &db.SharedResumeAccessInput{
+		SharedResumeID: shared.ID,
+		Action:         action,
+		Outcome:        outcome,
+		Detail:         detail,
+		ReferrerHost:   referrerHost(r),
+	})
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to log shared resume access", "error", err)
+	}
+}
+
+// handleUpdateSharedResumeAccess changes the scope and expiry of the caller's shared
+// resume link. Revoked links stay revoked; republish the run to get a new link.
+func (s *Server) handleUpdateSharedResumeAccess(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "shared resume")
+	if !ok {
+		return
+	}
+
+	var req SharedResumeAccessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validateSharedAccess(req.Scope, req.ExpiresAt); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	shared, err := s.db.UpdateSharedResumeAccess(r.Context(), userID, req.Scope, req.ExpiresAt)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if shared == nil {
+		s.errorResponse(w, http.StatusNotFound, "No shared resume page")
+		return
+	}
+	s.logSharedAccess(r, shared, db.SharedActionAccessChanged, db.SharedAccessAllowed, shared.Scope)
+	s.sharedResumeResponse(w, r, http.StatusOK, shared)
+}
+
+// handleRevokeSharedResume revokes the caller's shared resume link. Unlike DELETE, the
+// page's view history, comments, and access log are kept.
+func (s *Server) handleRevokeSharedResume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "shared resume")
+	if !ok {
+		return
+	}
+
+	shared, err := s.db.RevokeSharedResume(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if shared == nil {
+		s.errorResponse(w, http.StatusNotFound, "No shared resume page")
+		return
+	}
+	s.logSharedAccess(r, shared, db.SharedActionRevoked, db.SharedAccessAllowed, "")
+	s.sharedResumeResponse(w, r, http.StatusOK, shared)
+}
+
+// handleListSharedResumeComments lists the comments recipients left on the caller's
+// shared resume page, newest first
+func (s *Server) handleListSharedResumeComments(w http.ResponseWriter, r *http.Request) {
+	shared, ok := s.callerSharedResume(w, r)
+	if !ok {
+		return
+	}
+	comments, err := s.db.ListSharedResumeComments(r.Context(), shared.ID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, comments)
+}
+
+// handleListSharedResumeAccess returns the access log of the caller's shared resume page,
+// newest first
+func (s *Server) handleListSharedResumeAccess(w http.ResponseWriter, r *http.Request) {
+	shared, ok := s.callerSharedResume(w, r)
+	if !ok {
+		return
+	}
+	limit := defaultSharedAccessLogLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > maxSharedAccessLogLimit {
+			s.errorResponse(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxSharedAccessLogLimit))
+			return
+		}
+		limit = n
+	}
+
+	entries, err := s.db.ListSharedResumeAccess(r.Context(), shared.ID, limit)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, entries)
+}
+
+// callerSharedResume returns the caller's shared resume page, writing a 404 if they have none
+func (s *Server) callerSharedResume(w http.ResponseWriter, r *http.Request) (*db.SharedResume, bool) {
+	userID, ok := s.pathUserIsCaller(w, r, "shared resume")
+	if !ok {
+		return nil, false
+	}
+	shared, err := s.db.GetSharedResume(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return nil, false
+	}
+	if shared == nil {
+		s.errorResponse(w, http.StatusNotFound, "No shared resume page")
+		return nil, false
+	}
+	return shared, true
+}
+
+// handleSharedResumeArtifacts lists the shared run's artifacts for links whose scope
+// includes them. Debug and privacy artifacts are never listed.
+func (s *Server) handleSharedResumeArtifacts(w http.ResponseWriter, r *http.Request) {
+	shared, ok := s.sharedLink(w, r, db.SharedActionArtifacts, db.SharedScopeArtifacts, "")
+	if !ok {
+		return
+	}
+
+	all, err := s.db.ListArtifacts(r.Context(), db.ArtifactFilters{RunID: shared.RunID})
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	artifacts := make([]db.ArtifactSummary, 0, len(all))
+	for _, a := range all {
+		if !sharedHiddenCategories[a.Category] {
+			artifacts = append(artifacts, a)
+		}
+	}
+	s.jsonResponse(w, http.StatusOK, artifacts)
+}
+
+// handleSharedResumeArtifact returns one of the shared run's artifacts for links whose
+// scope includes them
+func (s *Server) handleSharedResumeArtifact(w http.ResponseWriter, r *http.Request) {
+	shared, ok := s.sharedLink(w, r, db.SharedActionArtifact, db.SharedScopeArtifacts, r.PathValue("artifact_id"))
+	if !ok {
+		return
+	}
+	artifactID, err := uuid.Parse(r.PathValue("artifact_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid artifact ID format")
+		return
+	}
+
+	artifact, err := s.db.GetArtifactByID(r.Context(), artifactID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if artifact == nil || artifact.RunID != shared.RunID || sharedHiddenCategories[artifact.Category] {
+		s.errorResponse(w, http.StatusNotFound, "Artifact not found")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, artifact)
+}
+
+// handleCreateSharedResumeComment lets a recipient comment on a shared resume page when
+// the link's scope allows it. The owner reads comments through the authenticated API.
+func (s *Server) handleCreateSharedResumeComment(w http.ResponseWriter, r *http.Request) {
+	shared, ok := s.sharedLink(w, r, db.SharedActionComment, db.SharedScopeComments, "")
+	if !ok {
+		return
+	}
+
+	var req SharedResumeCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	author := strings.TrimSpace(req.Author)
+	body := strings.TrimSpace(req.Body)
+	if author == "" || len(author) > maxSharedCommentAuthorLength {
+		s.errorResponse(w, http.StatusBadRequest, "author is required and must be at most "+strconv.Itoa(maxSharedCommentAuthorLength)+" characters")
+		return
+	}
+	if body == "" || len(body) > maxSharedCommentLength {
+		s.errorResponse(w, http.StatusBadRequest, "body is required and must be at most "+strconv.Itoa(maxSharedCommentLength)+" characters")
+		return
+	}
+
+	comment, err := s.db.CreateSharedResumeComment(r.Context(), shared.ID, author, body)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusCreated, comment)
+}
@@ -0,0 +1,337 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/qrcode"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+)
+
+// SharedResumeRequest is the request body for publishing a run as the user's shared resume page
+type SharedResumeRequest struct {
+	RunID     uuid.UUID  `json:"run_id"`
+	Scope     string     `json:"scope,omitempty"`      // pdf (default), artifacts, or comments
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Unset links never expire
+}
+
+// SharedResumeResponse is a user's shared resume page with its public URL and view analytics
+type SharedResumeResponse struct {
+	*db.SharedResume
+	Status       string                `json:"status"` // active, expired, or revoked
+	URL          string                `json:"url"`
+	PDFURL       string                `json:"pdf_url"`
+	ArtifactsURL string                `json:"artifacts_url,omitempty"` // Set when the scope includes artifacts
+	CommentsURL  string                `json:"comments_url,omitempty"`  // Set when the scope allows comments
+	QRURL        string                `json:"qr_url"`
+	Stats        *db.SharedResumeStats `json:"stats"`
+}
+
+// sharedResumePage is the chrome-less page a shared resume is rendered into.
+// It is kept out of search engines both here and by the X-Robots-Tag header.
+var sharedResumePage = template.Must(template.New("shared").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>Resume</title>
+{{if .ThumbnailURL}}<meta property="og:title" content="Resume">
+<meta property="og:image" content="{{.ThumbnailURL}}">
+{{end}}<style>
+body { max-width: 780px; margin: 2rem auto; padding: 0 1rem; font-family: Georgia, serif; color: #222; line-height: 1.4; }
+header { text-align: center; }
+h2 { font-size: 1.1rem; text-transform: uppercase; border-bottom: 1px solid #999; margin-top: 1.5rem; }
+p { margin: 0.2rem 0; }
+.row { display: flex; justify-content: space-between; gap: 1rem; }
+.right { text-align: right; white-space: nowrap; }
+ul { margin: 0.3rem 0 0.6rem; }
+.download { text-align: right; font-family: sans-serif; font-size: 0.9rem; }
+</style>
+</head>
+<body>
+<p class="download"><a href="{{.PDFURL}}">Download PDF</a></p>
+{{.Body}}
+</body>
+</html>
+`))
+
+// sharedResumeCSP blocks scripts, plugins, frames, and forms on public resume pages
+const sharedResumeCSP = "default-src 'none'; style-src 'unsafe-inline'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+// sharedResumeURLs returns the public page and PDF paths for a slug
+func sharedResumeURLs(slug string) (page, pdf string) {
+	page = "/r/" + slug
+	return page, page + "/resume.pdf"
+}
+
+// newSharedResumeSlug returns a random, unguessable URL segment
+func newSharedResumeSlug() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// handleGetSharedResume returns the caller's shared resume page and its view analytics
+func (s *Server) handleGetSharedResume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "shared resume")
+	if !ok {
+		return
+	}
+
+	shared, err := s.db.GetSharedResume(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if shared == nil {
+		s.errorResponse(w, http.StatusNotFound, "No shared resume page")
+		return
+	}
+	s.sharedResumeResponse(w, r, http.StatusOK, shared)
+}
+
+// handlePutSharedResume publishes one of the caller's runs as their shared resume page.
+// Republishing keeps the existing slug unless the link was revoked.
+func (s *Server) handlePutSharedResume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "shared resume")
+	if !ok {
+		return
+	}
+
+	var req SharedResumeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RunID == uuid.Nil {
+		s.errorResponse(w, http.StatusBadRequest, "run_id is required")
+		return
+	}
+	if req.Scope == "" {
+		req.Scope = db.SharedScopePDF
+	}
+	if err := validateSharedAccess(req.Scope, req.ExpiresAt); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	run, err := s.db.GetRun(r.Context(), req.RunID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if run == nil {
+		s.errorResponse(w, http.StatusNotFound, "Run not found")
+		return
+	}
+	if run.UserID == nil || *run.UserID != userID {
+		s.errorResponse(w, http.StatusForbidden, "You can only share your own runs")
+		return
+	}
+
+	tex, err := s.db.GetTextArtifact(r.Context(), req.RunID, "resume_tex")
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if tex == "" {
+		s.errorResponse(w, http.StatusConflict, "Run has no rendered resume to share")
+		return
+	}
+
+	slug, err := newSharedResumeSlug()
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to generate link: "+err.Error())
+		return
+	}
+
+	shared, err := s.db.UpsertSharedResume(r.Context(), &db.SharedResumeInput{
+		UserID:    userID,
+		RunID:     req.RunID,
+		Slug:      slug,
+		Scope:     req.Scope,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.logSharedAccess(r, shared, db.SharedActionPublished, db.SharedAccessAllowed, shared.Scope)
+	s.sharedResumeResponse(w, r, http.StatusOK, shared)
+}
+
+// handleDeleteSharedResume takes the caller's shared resume page offline
+func (s *Server) handleDeleteSharedResume(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "shared resume")
+	if !ok {
+		return
+	}
+
+	if err := s.db.DeleteSharedResume(r.Context(), userID); err != nil {
+		s.errorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sharedResumeResponse writes a shared resume page with its URLs and view stats
+func (s *Server) sharedResumeResponse(w http.ResponseWriter, r *http.Request, status int, shared *db.SharedResume) {
+	stats, err := s.db.GetSharedResumeStats(r.Context(), shared.ID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	page, pdf := sharedResumeURLs(shared.Slug)
+	resp := SharedResumeResponse{
+		SharedResume: shared,
+		Status:       sharedResumeStatus(shared, time.Now()),
+		URL:          page,
+		PDFURL:       pdf,
+		QRURL:        "/v1/users/" + shared.UserID.String() + "/shared-resume/qr.png",
+		Stats:        stats,
+	}
+	if db.SharedScopeAllows(shared.Scope, db.SharedScopeArtifacts) {
+		resp.ArtifactsURL = page + "/artifacts"
+	}
+	if db.SharedScopeAllows(shared.Scope, db.SharedScopeComments) {
+		resp.CommentsURL = page + "/comments"
+	}
+	s.jsonResponse(w, status, resp)
+}
+
+// handleSharedResumeQR returns a QR code PNG of the caller's shared resume link, for
+// printed copies and business cards. The code holds the absolute page URL, so it is
+// generated per request from PUBLIC_BASE_URL (or the request's own host).
+func (s *Server) handleSharedResumeQR(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "shared resume")
+	if !ok {
+		return
+	}
+
+	shared, err := s.db.GetSharedResume(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if shared == nil {
+		s.errorResponse(w, http.StatusNotFound, "No shared resume page")
+		return
+	}
+
+	page, _ := sharedResumeURLs(shared.Slug)
+	img, err := qrcode.PNG(s.baseURL(r)+page, sharedResumeQRScale)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to generate QR code: "+err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Disposition", `inline; filename="resume-qr.png"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(img)
+}
+
+// sharedResumeQRScale is the pixels per QR module; large enough to print sharply
+const sharedResumeQRScale = 10
+
+// baseURL returns the externally visible scheme and host that public links are built on
+func (s *Server) baseURL(r *http.Request) string {
+	if s.publicURL != "" {
+		return s.publicURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// handleSharedResumePage serves a shared resume as a public, unindexed HTML page
+func (s *Server) handleSharedResumePage(w http.ResponseWriter, r *http.Request) {
+	shared, tex, ok := s.lookupSharedResume(w, r, db.SharedViewPage)
+	if !ok {
+		return
+	}
+
+	page, pdf := sharedResumeURLs(shared.Slug)
+	data := struct {
+		PDFURL       string
+		ThumbnailURL string // Absolute, as share cards require; empty without a thumbnail
+		Body         template.HTML
+	}{
+		PDFURL: pdf,
+		Body:   template.HTML(rendering.LaTeXToHTML(tex)), // LaTeXToHTML escapes all text
+	}
+	if png, err := s.loadThumbnail(r.Context(), shared.RunID); err != nil {
+		slog.WarnContext(r.Context(), "failed to load thumbnail for shared resume", "shared_resume_id", shared.ID, "error", err)
+	} else if png != nil {
+		data.ThumbnailURL = s.baseURL(r) + page + "/thumbnail.png"
+	}
+
+	s.recordSharedResumeView(r, shared, db.SharedViewPage)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := sharedResumePage.Execute(w, data); err != nil {
+		slog.WarnContext(r.Context(), "failed to render shared resume", "shared_resume_id", shared.ID, "error", err)
+	}
+}
+
+// handleSharedResumePDF serves a shared resume as a PDF download
+func (s *Server) handleSharedResumePDF(w http.ResponseWriter, r *http.Request) {
+	shared, _, ok := s.lookupSharedResume(w, r, db.SharedViewPDF)
+	if !ok {
+		return
+	}
+
+	s.serveResumePDF(w, r, shared.RunID, func() {
+		// Range requests continue a download that was already counted
+		if r.Header.Get("Range") == "" {
+			s.recordSharedResumeView(r, shared, db.SharedViewPDF)
+		}
+	})
+}
+
+// lookupSharedResume resolves the {slug} path value to a shared resume and its LaTeX,
+// writing a 404 when either is missing and refusing revoked or expired links. Every
+// scope includes the final resume, so action is only recorded in the access log.
+func (s *Server) lookupSharedResume(w http.ResponseWriter, r *http.Request, action string) (*db.SharedResume, string, bool) {
+	shared, ok := s.sharedLink(w, r, action, db.SharedScopePDF, "")
+	if !ok {
+		return nil, "", false
+	}
+
+	tex, err := s.db.GetTextArtifact(r.Context(), shared.RunID, "resume_tex")
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return nil, "", false
+	}
+	if tex == "" {
+		s.errorResponse(w, http.StatusNotFound, "Page not found")
+		return nil, "", false
+	}
+	return shared, tex, true
+}
+
+// recordSharedResumeView stores a view for analytics. Only the referring host is kept,
+// and clicks from the page itself (the PDF link) do not count as a referrer.
+func (s *Server) recordSharedResumeView(r *http.Request, shared *db.SharedResume, kind string) {
+	if err := s.db.RecordSharedResumeView(r.Context(), shared.ID, kind, referrerHost(r)); err != nil {
+		slog.WarnContext(r.Context(), "failed to record shared resume view", "error", err)
+	}
+}
+
+// referrerHost returns the host of the request's Referer, or "" when there is none or
+// the request came from the shared page itself
+func referrerHost(r *http.Request) string {
+	if ref, err := url.Parse(r.Referer()); err == nil && ref.Host != r.Host {
+		return ref.Hostname()
+	}
+	return ""
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/qrcode"
+)
+
+const sharedTestTex = `\documentclass{article}
+\begin{document}
+\begin{center}
+{\Large \textbf{Jane Doe}} \\
+\end{center}
+\section*{Experience}
+\textbf{Acme} \hfill 2020 -- present
+\begin{itemize}
+\item Built <things> \& shipped them
+\end{itemize}
+\end{document}
+`
+
+func TestHandlePutSharedResume(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, UserID: &owner}
+	target := "/v1/users/" + owner.String() + "/shared-resume"
+	body := []byte(`{"run_id":"` + runID.String() + `"}`)
+
+	// A run without a rendered resume cannot be shared
+	w := serveAuthed(t, s, "PUT /v1/users/{id}/shared-resume", s.handlePutSharedResume,
+		bearerRequest(t, s, http.MethodPut, target, owner, body))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	s.mock.textArtifacts[runID.String()+":resume_tex"] = sharedTestTex
+
+	w = serveAuthed(t, s, "PUT /v1/users/{id}/shared-resume", s.handlePutSharedResume,
+		bearerRequest(t, s, http.MethodPut, target, uuid.New(), body))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serveAuthed(t, s, "PUT /v1/users/{id}/shared-resume", s.handlePutSharedResume,
+		bearerRequest(t, s, http.MethodPut, target, owner, body))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp SharedResumeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, runID, resp.RunID)
+	assert.Len(t, resp.Slug, 22)
+	assert.Equal(t, "/r/"+resp.Slug, resp.URL)
+	assert.Equal(t, "/r/"+resp.Slug+"/resume.pdf", resp.PDFURL)
+	assert.Equal(t, target+"/qr.png", resp.QRURL)
+
+	// Sharing another user's run is refused
+	other := uuid.New()
+	otherRun := uuid.New()
+	s.mock.runs[otherRun] = &db.Run{ID: otherRun, UserID: &other}
+	w = serveAuthed(t, s, "PUT /v1/users/{id}/shared-resume", s.handlePutSharedResume,
+		bearerRequest(t, s, http.MethodPut, target, owner, []byte(`{"run_id":"`+otherRun.String()+`"}`)))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serveAuthed(t, s, "DELETE /v1/users/{id}/shared-resume", s.handleDeleteSharedResume,
+		bearerRequest(t, s, http.MethodDelete, target, owner, nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/shared-resume", s.handleGetSharedResume,
+		bearerRequest(t, s, http.MethodGet, target, owner, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleSharedResumeQR(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	target := "/v1/users/" + owner.String() + "/shared-resume/qr.png"
+
+	w := serveAuthed(t, s, "GET /v1/users/{id}/shared-resume/qr.png", s.handleSharedResumeQR,
+		bearerRequest(t, s, http.MethodGet, target, owner, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	shared, err := s.mock.UpsertSharedResume(context.Background(), &db.SharedResumeInput{UserID: owner, RunID: uuid.New(), Slug: "AbCdEfGhIjKlMnOpQrStUv"})
+	require.NoError(t, err)
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/shared-resume/qr.png", s.handleSharedResumeQR,
+		bearerRequest(t, s, http.MethodGet, target, uuid.New(), nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	s.publicURL = "https://resumes.example.com"
+	w = serveAuthed(t, s, "GET /v1/users/{id}/shared-resume/qr.png", s.handleSharedResumeQR,
+		bearerRequest(t, s, http.MethodGet, target, owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+
+	img, err := png.Decode(w.Body)
+	require.NoError(t, err)
+	code, err := qrcode.Encode("https://resumes.example.com/r/" + shared.Slug)
+	require.NoError(t, err)
+	assert.Equal(t, (code.Size+8)*sharedResumeQRScale, img.Bounds().Dx())
+}
+
+func TestBaseURL(t *testing.T) {
+	s := &Server{}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Host = "localhost:8080"
+	assert.Equal(t, "http://localhost:8080", s.baseURL(r))
+
+	s.publicURL = "https://resumes.example.com"
+	assert.Equal(t, "https://resumes.example.com", s.baseURL(r))
+}
+
+func TestHandleSharedResumePage(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	runID := uuid.New()
+	s.mock.textArtifacts[runID.String()+":resume_tex"] = sharedTestTex
+	shared, err := s.mock.UpsertSharedResume(context.Background(), &db.SharedResumeInput{UserID: owner, RunID: runID, Slug: "abc123"})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /r/{slug}", s.handleSharedResumePage)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/r/abc123", nil)
+	req.Header.Set("Referer", "https://www.linkedin.com/feed/")
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "noindex, nofollow", w.Header().Get("X-Robots-Tag"))
+	assert.Contains(t, w.Header().Get("Content-Security-Policy"), "default-src 'none'")
+	assert.Contains(t, w.Body.String(), `<meta name="robots" content="noindex, nofollow">`)
+	assert.Contains(t, w.Body.String(), `<a href="/r/abc123/resume.pdf">`)
+	assert.Contains(t, w.Body.String(), "<strong>Jane Doe</strong>")
+	assert.Contains(t, w.Body.String(), "<li>Built &lt;things&gt; &amp; shipped them</li>")
+	assert.Equal(t, []string{db.SharedViewPage}, s.mock.sharedViews[shared.ID])
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/r/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Len(t, s.mock.sharedViews[shared.ID], 1)
+
+	// The owner sees the view in their analytics
+	w = serveAuthed(t, s, "GET /v1/users/{id}/shared-resume", s.handleGetSharedResume,
+		bearerRequest(t, s, http.MethodGet, "/v1/users/"+owner.String()+"/shared-resume", owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp SharedResumeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Stats.PageViews)
+}
+
+func TestSharedResumeScopes(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	runID := uuid.New()
+	s.mock.textArtifacts[runID.String()+":resume_tex"] = sharedTestTex
+	shared, err := s.mock.UpsertSharedResume(context.Background(), &db.SharedResumeInput{UserID: owner, RunID: runID, Slug: "abc123"})
+	require.NoError(t, err)
+	plan := db.Artifact{ID: uuid.New(), RunID: runID, Step: "resume_plan", Category: db.CategoryExperience}
+	trace := db.Artifact{ID: uuid.New(), RunID: runID, Step: "llm_trace", Category: db.CategoryDebug}
+	s.mock.artifacts[plan.ID] = &plan
+	s.mock.artifacts[trace.ID] = &trace
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /r/{slug}", s.handleSharedResumePage)
+	mux.HandleFunc("GET /r/{slug}/artifacts", s.handleSharedResumeArtifacts)
+	mux.HandleFunc("GET /r/{slug}/artifacts/{artifact_id}", s.handleSharedResumeArtifact)
+	mux.HandleFunc("POST /r/{slug}/comments", s.handleCreateSharedResumeComment)
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+	comment := `{"author":"Recruiter","body":"Strong fit"}`
+
+	// The default scope covers only the final resume
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/r/abc123", "").Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/r/abc123/artifacts", "").Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/r/abc123/comments", comment).Code)
+
+	shared.Scope = db.SharedScopeArtifacts
+	w := serve(http.MethodGet, "/r/abc123/artifacts", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var artifacts []db.ArtifactSummary
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &artifacts))
+	require.Len(t, artifacts, 1, "debug artifacts are never shared")
+	assert.Equal(t, plan.ID, artifacts[0].ID)
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/r/abc123/artifacts/"+plan.ID.String(), "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/r/abc123/artifacts/"+trace.ID.String(), "").Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/r/abc123/comments", comment).Code)
+
+	shared.Scope = db.SharedScopeComments
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/r/abc123/comments", `{"author":"","body":"x"}`).Code)
+	require.Equal(t, http.StatusCreated, serve(http.MethodPost, "/r/abc123/comments", comment).Code)
+	require.Len(t, s.mock.sharedComments, 1)
+	assert.Equal(t, "Strong fit", s.mock.sharedComments[0].Body)
+
+	past := time.Now().Add(-time.Hour)
+	shared.ExpiresAt = &past
+	assert.Equal(t, http.StatusGone, serve(http.MethodGet, "/r/abc123", "").Code)
+
+	var outcomes []string
+	for _, a := range s.mock.sharedAccess {
+		outcomes = append(outcomes, a.Action+":"+a.Outcome)
+	}
+	assert.Equal(t, []string{
+		"page:allowed", "artifacts:out_of_scope", "comment:out_of_scope",
+		"artifacts:allowed", "artifact:allowed", "artifact:allowed", "comment:out_of_scope",
+		"comment:allowed", "comment:allowed", "page:expired",
+	}, outcomes, "every request through the link is audited, including refused ones")
+}
+
+func TestSharedResumeAccessManagement(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, UserID: &owner}
+	s.mock.textArtifacts[runID.String()+":resume_tex"] = sharedTestTex
+	base := "/v1/users/" + owner.String() + "/shared-resume"
+	publish := func(body string) *httptest.ResponseRecorder {
+		return serveAuthed(t, s, "PUT /v1/users/{id}/shared-resume", s.handlePutSharedResume,
+			bearerRequest(t, s, http.MethodPut, base, owner, []byte(body)))
+	}
+	access := func(caller uuid.UUID, body string) *httptest.ResponseRecorder {
+		return serveAuthed(t, s, "PUT /v1/users/{id}/shared-resume/access", s.handleUpdateSharedResumeAccess,
+			bearerRequest(t, s, http.MethodPut, base+"/access", caller, []byte(body)))
+	}
+	decode := func(w *httptest.ResponseRecorder) SharedResumeResponse {
+		t.Helper()
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp SharedResumeResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	assert.Equal(t, http.StatusNotFound, access(owner, `{"scope":"pdf"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, publish(`{"run_id":"`+runID.String()+`","scope":"everything"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, publish(`{"run_id":"`+runID.String()+`","expires_at":"2001-01-01T00:00:00Z"}`).Code)
+	first := decode(publish(`{"run_id":"` + runID.String() + `"}`))
+	assert.Equal(t, db.SharedScopePDF, first.Scope)
+	assert.Equal(t, "active", first.Status)
+	assert.Empty(t, first.ArtifactsURL)
+
+	assert.Equal(t, http.StatusForbidden, access(uuid.New(), `{"scope":"comments"}`).Code)
+	expires := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	updated := decode(access(owner, `{"scope":"comments","expires_at":"`+expires+`"}`))
+	assert.Equal(t, "/r/"+first.Slug+"/artifacts", updated.ArtifactsURL)
+	assert.Equal(t, "/r/"+first.Slug+"/comments", updated.CommentsURL)
+	require.NotNil(t, updated.ExpiresAt)
+
+	revoked := decode(serveAuthed(t, s, "POST /v1/users/{id}/shared-resume/revoke", s.handleRevokeSharedResume,
+		bearerRequest(t, s, http.MethodPost, base+"/revoke", owner, nil)))
+	assert.Equal(t, db.SharedAccessRevoked, revoked.Status)
+
+	// Republishing a revoked page issues a new link; the old one stays dead
+	republished := decode(publish(`{"run_id":"` + runID.String() + `"}`))
+	assert.NotEqual(t, first.Slug, republished.Slug)
+	assert.Equal(t, "active", republished.Status)
+
+	w := serveAuthed(t, s, "GET /v1/users/{id}/shared-resume/access-log", s.handleListSharedResumeAccess,
+		bearerRequest(t, s, http.MethodGet, base+"/access-log", owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var entries []db.SharedResumeAccess
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	var actions []string
+	for _, e := range entries {
+		actions = append(actions, e.Action)
+	}
+	assert.Equal(t, []string{db.SharedActionPublished, db.SharedActionAccessChanged, db.SharedActionRevoked, db.SharedActionPublished}, actions)
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/shared-resume/access-log", s.handleListSharedResumeAccess,
+		bearerRequest(t, s, http.MethodGet, base+"/access-log?limit=0", owner, nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+)
+
+// Sources of a skill's last-used month
+const (
+	lastUsedSourceSelf       = "self"       // Reported by the user
+	lastUsedSourceExperience = "experience" // Derived from the jobs using the skill
+)
+
+// SkillAssessmentRequest is the request body for self-assessing a skill
+type SkillAssessmentRequest struct {
+	Skill       string `json:"skill"`
+	Proficiency *int   `json:"proficiency,omitempty"` // 1 (basic) to 5 (expert); omit to clear
+	LastUsed    string `json:"last_used,omitempty"`   // YYYY-MM; omit to derive it from experience
+}
+
+// SkillAssessment is one skill with its self-assessed proficiency and recency
+type SkillAssessment struct {
+	Skill          string `json:"skill"`
+	Proficiency    *int   `json:"proficiency,omitempty"`
+	LastUsed       string `json:"last_used,omitempty"`        // YYYY-MM
+	LastUsedSource string `json:"last_used_source,omitempty"` // self or experience
+}
+
+// SkillAssessmentsResponse is the response for listing skill assessments
+type SkillAssessmentsResponse struct {
+	Skills []SkillAssessment `json:"skills"`
+}
+
+// handleListSkillAssessments returns the caller's skills: those self-assessed and those
+// used in their experience, each with when it was last used
+func (s *Server) handleListSkillAssessments(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "skill assessments")
+	if !ok {
+		return
+	}
+
+	bank, err := s.fetchExperienceBankFromDB(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to fetch experience bank: "+err.Error())
+		return
+	}
+
+	derived := ranking.SkillLastUsed(bank, time.Now())
+	byKey := make(map[string]*SkillAssessment)
+	for _, sa := range bank.SkillProfile {
+		a := &SkillAssessment{Skill: sa.Skill}
+		if sa.Proficiency > 0 {
+			proficiency := sa.Proficiency
+			a.Proficiency = &proficiency
+		}
+		if sa.LastUsed != "" {
+			a.LastUsed = sa.LastUsed
+			a.LastUsedSource = lastUsedSourceSelf
+		}
+		byKey[ranking.SkillKey(sa.Skill)] = a
+	}
+	for _, story := range bank.Stories {
+		for _, bullet := range story.Bullets {
+			for _, skill := range bullet.Skills {
+				key := ranking.SkillKey(skill)
+				if key != "" && byKey[key] == nil {
+					byKey[key] = &SkillAssessment{Skill: skill}
+				}
+			}
+		}
+	}
+
+	skills := make([]SkillAssessment, 0, len(byKey))
+	for key, a := range byKey {
+		if a.LastUsed == "" {
+			if last, ok := derived[key]; ok {
+				a.LastUsed = last.Format("2006-01")
+				a.LastUsedSource = lastUsedSourceExperience
+			}
+		}
+		skills = append(skills, *a)
+	}
+	sort.Slice(skills, func(i, j int) bool {
+		return strings.ToLower(skills[i].Skill) < strings.ToLower(skills[j].Skill)
+	})
+	s.jsonResponse(w, http.StatusOK, SkillAssessmentsResponse{Skills: skills})
+}
+
+// handlePutSkillAssessment records the caller's proficiency in a skill and, optionally,
+// when they last used it. Fields left out are cleared.
+func (s *Server) handlePutSkillAssessment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "skill assessments")
+	if !ok {
+		return
+	}
+
+	var req SkillAssessmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Skill = strings.TrimSpace(req.Skill)
+	if req.Skill == "" {
+		s.errorResponse(w, http.StatusBadRequest, "skill is required")
+		return
+	}
+	if req.Proficiency != nil && (*req.Proficiency < db.MinProficiency || *req.Proficiency > db.MaxProficiency) {
+		s.errorResponse(w, http.StatusBadRequest,
+			fmt.Sprintf("proficiency must be between %d and %d", db.MinProficiency, db.MaxProficiency))
+		return
+	}
+	var lastUsed *db.Date
+	if req.LastUsed != "" {
+		t, err := time.Parse("2006-01", req.LastUsed)
+		if err != nil || t.After(time.Now()) {
+			s.errorResponse(w, http.StatusBadRequest, "last_used must be a past month as YYYY-MM")
+			return
+		}
+		lastUsed = &db.Date{Time: t}
+	}
+
+	us, err := s.db.UpsertUserSkill(r.Context(), userID, req.Skill, req.Proficiency, lastUsed)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	a := SkillAssessment{Skill: us.Skill, Proficiency: us.Proficiency}
+	if us.LastUsed != nil {
+		a.LastUsed = us.LastUsed.Format("2006-01")
+		a.LastUsedSource = lastUsedSourceSelf
+	}
+	s.jsonResponse(w, http.StatusOK, a)
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestHandlePutSkillAssessment(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	target := "/v1/users/" + owner.String() + "/skill-assessments"
+	put := func(userID uuid.UUID, body string) *SkillAssessment {
+		t.Helper()
+		w := serveAuthed(t, s, "PUT /v1/users/{id}/skill-assessments", s.handlePutSkillAssessment,
+			bearerRequest(t, s, http.MethodPut, target, userID, []byte(body)))
+		if w.Code != http.StatusOK {
+			return nil
+		}
+		var a SkillAssessment
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &a))
+		return &a
+	}
+
+	assert.Nil(t, put(uuid.New(), `{"skill":"Go","proficiency":4}`), "other users are forbidden")
+	assert.Nil(t, put(owner, `{"proficiency":4}`), "skill is required")
+	assert.Nil(t, put(owner, `{"skill":"Go","proficiency":6}`), "proficiency out of range")
+	assert.Nil(t, put(owner, `{"skill":"Go","last_used":"2019"}`), "malformed month")
+	assert.Nil(t, put(owner, `{"skill":"Go","last_used":"2999-01"}`), "future month")
+
+	a := put(owner, `{"skill":"Go","proficiency":4,"last_used":"2021-03"}`)
+	require.NotNil(t, a)
+	assert.Equal(t, 4, *a.Proficiency)
+	assert.Equal(t, "2021-03", a.LastUsed)
+	assert.Equal(t, lastUsedSourceSelf, a.LastUsedSource)
+	require.Len(t, s.mock.userSkills[owner], 1)
+	assert.Equal(t, time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), s.mock.userSkills[owner][0].LastUsed.Time)
+}
+
+func TestHandleListSkillAssessments(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	oldJob, currentJob := uuid.New(), uuid.New()
+	s.mock.jobs = []db.Job{
+		{ID: oldJob, UserID: owner, EndDate: &db.Date{Time: time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC)}},
+		{ID: currentJob, UserID: owner},
+	}
+	s.mock.experiences = []db.Experience{
+		{ID: uuid.New(), JobID: oldJob, Skills: db.StringArray{"Java", "SQL"}},
+		{ID: uuid.New(), JobID: currentJob, Skills: db.StringArray{"Go"}},
+	}
+	two := 2
+	s.mock.userSkills = map[uuid.UUID][]db.UserSkill{owner: {
+		{UserID: owner, Skill: "SQL", Proficiency: &two, LastUsed: &db.Date{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}},
+	}}
+
+	w := serveAuthed(t, s, "GET /v1/users/{id}/skill-assessments", s.handleListSkillAssessments,
+		bearerRequest(t, s, http.MethodGet, "/v1/users/"+owner.String()+"/skill-assessments", owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp SkillAssessmentsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Skills, 3)
+
+	assert.Equal(t, "Go", resp.Skills[0].Skill)
+	assert.Equal(t, time.Now().Format("2006-01"), resp.Skills[0].LastUsed)
+	assert.Equal(t, lastUsedSourceExperience, resp.Skills[0].LastUsedSource)
+	assert.Nil(t, resp.Skills[0].Proficiency)
+
+	assert.Equal(t, "Java", resp.Skills[1].Skill)
+	assert.Equal(t, "2018-05", resp.Skills[1].LastUsed)
+	assert.Equal(t, lastUsedSourceExperience, resp.Skills[1].LastUsedSource)
+
+	assert.Equal(t, "SQL", resp.Skills[2].Skill)
+	assert.Equal(t, 2, *resp.Skills[2].Proficiency)
+	assert.Equal(t, "2024-01", resp.Skills[2].LastUsed)
+	assert.Equal(t, lastUsedSourceSelf, resp.Skills[2].LastUsedSource)
+}
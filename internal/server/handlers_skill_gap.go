@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// handleGetSkillGap compares a job posting's requirements with the skills on the caller's
+// bullets, returning matched, partially matched, and missing skills with their evidence
+func (s *Server) handleGetSkillGap(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "skill gap")
+	if !ok {
+		return
+	}
+
+	postingIDStr := r.URL.Query().Get("posting_id")
+	if postingIDStr == "" {
+		s.errorResponse(w, http.StatusBadRequest, "posting_id is required")
+		return
+	}
+	postingID, err := uuid.Parse(postingIDStr)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid posting_id format")
+		return
+	}
+
+	gap, err := s.db.GetSkillGap(r.Context(), userID, postingID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to analyze skill gap: "+err.Error())
+		return
+	}
+	if gap == nil {
+		s.errorResponse(w, http.StatusNotFound, "Job profile not found for this posting")
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, gap)
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestHandleGetSkillGap(t *testing.T) {
+	owner, postingID := uuid.New(), uuid.New()
+	s := newAuthTestServer(t)
+	s.mock.skillGaps = map[uuid.UUID]*db.SkillGap{postingID: {
+		PostingID: postingID,
+		Matched:   []db.SkillGapEntry{{Skill: "Go", Evidence: []db.SkillEvidence{{BulletID: "b1", Match: db.SkillMatchTagged}}}},
+		Partial:   []db.SkillGapEntry{},
+		Missing:   []db.SkillGapEntry{{Skill: "Rust", Evidence: []db.SkillEvidence{}}},
+	}}
+	get := func(userID uuid.UUID, query string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := bearerRequest(t, s, http.MethodGet, "/v1/users/"+owner.String()+"/skill-gap"+query, userID, nil)
+		return serveAuthed(t, s, "GET /v1/users/{id}/skill-gap", s.handleGetSkillGap, req)
+	}
+
+	assert.Equal(t, http.StatusForbidden, get(uuid.New(), "?posting_id="+postingID.String()).Code)
+	assert.Equal(t, http.StatusBadRequest, get(owner, "").Code)
+	assert.Equal(t, http.StatusBadRequest, get(owner, "?posting_id=nope").Code)
+	assert.Equal(t, http.StatusNotFound, get(owner, "?posting_id="+uuid.New().String()).Code)
+
+	w := get(owner, "?posting_id="+postingID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+	var gap db.SkillGap
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &gap))
+	require.Len(t, gap.Matched, 1)
+	assert.Equal(t, "b1", gap.Matched[0].Evidence[0].BulletID)
+	require.Len(t, gap.Missing, 1)
+	assert.Equal(t, "Rust", gap.Missing[0].Skill)
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+const (
+	// defaultSLODays and maxSLODays bound the window the SLO report covers
+	defaultSLODays = 7
+	maxSLODays     = 90
+)
+
+// SLOReport compares how long runs and their steps took with their latency budgets
+type SLOReport struct {
+	Since  time.Time `json:"since"`
+	Bucket string    `json:"bucket"` // hour, day, or week
+	Target float64   `json:"target"` // Fraction of executions expected within budget
+	Steps  []SLOStep `json:"steps"`  // Whole runs first, as "run", then steps by name
+}
+
+// SLOStep reports the latency of one step, or of whole runs, over the report window
+type SLOStep struct {
+	Step         string      `json:"step"`
+	BudgetMs     int64       `json:"budget_ms,omitempty"`
+	Count        int         `json:"count"`
+	P50Ms        int64       `json:"p50_ms"`
+	P95Ms        int64       `json:"p95_ms"`
+	OverBudget   int         `json:"over_budget"`
+	WithinBudget *float64    `json:"within_budget,omitempty"` // Fraction of executions within budget; omitted without a budget
+	Slipping     bool        `json:"slipping"`                // Fewer executions than the target met the budget
+	Buckets      []SLOBucket `json:"buckets"`
+}
+
+// SLOBucket is one step's latency in one hour, day, or week
+type SLOBucket struct {
+	Start      time.Time `json:"start"`
+	Count      int       `json:"count"`
+	P50Ms      int64     `json:"p50_ms"`
+	P95Ms      int64     `json:"p95_ms"`
+	OverBudget int       `json:"over_budget"`
+}
+
+// latencyBudgetsMs returns the latency budget of every step that has one, and of whole runs
+func (s *Server) latencyBudgetsMs() map[string]int64 {
+	budgets := map[string]int64{db.LatencyRun: s.slo.RunBudget().Milliseconds()}
+	for name, def := range steps.StepRegistry {
+		if def.LatencyBudget > 0 {
+			budgets[name] = def.LatencyBudget.Milliseconds()
+		}
+	}
+	return budgets
+}
+
+// handleSLOReport reports p50/p95 step and run latencies over time against their budgets.
+// ?days= sets the window (default 7, at most 90) and ?bucket= the interval (default day).
+func (s *Server) handleSLOReport(w http.ResponseWriter, r *http.Request) {
+	if !s.callerIsAdmin(w, r, "view the SLO report") {
+		return
+	}
+
+	query := r.URL.Query()
+	days := defaultSLODays
+	if v := query.Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxSLODays {
+			s.errorResponse(w, http.StatusBadRequest, "days must be between 1 and "+strconv.Itoa(maxSLODays))
+			return
+		}
+		days = n
+	}
+	bucket := query.Get("bucket")
+	if bucket == "" {
+		bucket = "day"
+	}
+	if !slices.Contains(db.LatencyBuckets, bucket) {
+		s.errorResponse(w, http.StatusBadRequest, `bucket must be "hour", "day", or "week"`)
+		return
+	}
+
+	since := time.Now().UTC().Add(-time.Duration(days) * 24 * time.Hour)
+	budgets := s.latencyBudgetsMs()
+	stats, err := s.db.ListLatencyStats(r.Context(), since, bucket, budgets)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to load latency stats")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, SLOReport{
+		Since:  since,
+		Bucket: bucket,
+		Target: s.slo.Target,
+		Steps:  sloSteps(stats, budgets, s.slo.Target),
+	})
+}
+
+// sloSteps groups latency stats by step, whole runs first, and scores each against its budget
+func sloSteps(stats []db.LatencyStats, budgetsMs map[string]int64, target float64) []SLOStep {
+	byStep := make(map[string]*SLOStep)
+	var order []string
+	for _, st := range stats {
+		step, ok := byStep[st.Step]
+		if !ok {
+			step = &SLOStep{Step: st.Step, BudgetMs: budgetsMs[st.Step], Buckets: []SLOBucket{}}
+			byStep[st.Step] = step
+			order = append(order, st.Step)
+		}
+		if st.Bucket != nil {
+			step.Buckets = append(step.Buckets, SLOBucket{
+				Start: *st.Bucket, Count: st.Count, P50Ms: st.P50Ms, P95Ms: st.P95Ms, OverBudget: st.OverBudget,
+			})
+			continue
+		}
+		step.Count, step.P50Ms, step.P95Ms, step.OverBudget = st.Count, st.P50Ms, st.P95Ms, st.OverBudget
+		if step.BudgetMs > 0 && st.Count > 0 {
+			within := 1 - float64(st.OverBudget)/float64(st.Count)
+			step.WithinBudget = &within
+			step.Slipping = within < target
+		}
+	}
+
+	// Stats arrive ordered by step name
+	if i := slices.Index(order, db.LatencyRun); i > 0 {
+		order = append([]string{db.LatencyRun}, slices.Delete(order, i, i+1)...)
+	}
+	out := make([]SLOStep, 0, len(order))
+	for _, name := range order {
+		out = append(out, *byStep[name])
+	}
+	return out
+}
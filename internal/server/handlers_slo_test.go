@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestHandleSLOReport(t *testing.T) {
+	admin := uuid.New()
+	s := newAuthTestServer(t, admin)
+	s.slo = &config.SLOConfig{RunBudgetSeconds: 300, Target: 0.9}
+
+	day1 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	s.mock.latencyStats = []db.LatencyStats{
+		{Step: "parse_job", Count: 10, P50Ms: 20000, P95Ms: 70000, OverBudget: 2},
+		{Step: "parse_job", Bucket: &day1, Count: 4, P50Ms: 15000, P95Ms: 30000},
+		{Step: "parse_job", Bucket: &day2, Count: 6, P50Ms: 25000, P95Ms: 70000, OverBudget: 2},
+		{Step: "render_latex", Count: 10, P50Ms: 2000, P95Ms: 4000},
+		{Step: "render_latex", Bucket: &day2, Count: 10, P50Ms: 2000, P95Ms: 4000},
+		{Step: db.LatencyRun, Count: 5, P50Ms: 200000, P95Ms: 290000},
+		{Step: db.LatencyRun, Bucket: &day2, Count: 5, P50Ms: 200000, P95Ms: 290000},
+	}
+
+	get := func(caller uuid.UUID, query string) *httptest.ResponseRecorder {
+		t.Helper()
+		return serveAuthed(t, s, "GET /v1/admin/slo", s.handleSLOReport,
+			bearerRequest(t, s, http.MethodGet, "/v1/admin/slo"+query, caller, nil))
+	}
+
+	w := get(admin, "?days=2&bucket=day")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report SLOReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "day", report.Bucket)
+	assert.Equal(t, 0.9, report.Target)
+	assert.WithinDuration(t, time.Now().Add(-48*time.Hour), report.Since, time.Minute)
+	assert.Equal(t, int64(300000), s.mock.latencyBudgets[db.LatencyRun])
+	assert.Equal(t, int64(60000), s.mock.latencyBudgets["parse_job"])
+
+	require.Len(t, report.Steps, 3)
+	run, parse, render := report.Steps[0], report.Steps[1], report.Steps[2]
+	assert.Equal(t, db.LatencyRun, run.Step, "whole runs come first")
+	assert.Equal(t, int64(300000), run.BudgetMs)
+	assert.False(t, run.Slipping)
+
+	assert.Equal(t, "parse_job", parse.Step)
+	assert.Equal(t, int64(70000), parse.P95Ms)
+	require.NotNil(t, parse.WithinBudget)
+	assert.InDelta(t, 0.8, *parse.WithinBudget, 1e-9)
+	assert.True(t, parse.Slipping, "80% within budget is below the 90% target")
+	require.Len(t, parse.Buckets, 2)
+	assert.Equal(t, day2, parse.Buckets[1].Start)
+	assert.Equal(t, 2, parse.Buckets[1].OverBudget)
+
+	assert.Equal(t, "render_latex", render.Step)
+	assert.False(t, render.Slipping)
+
+	for _, query := range []string{"?days=0", "?days=91", "?days=week", "?bucket=minute"} {
+		assert.Equal(t, http.StatusBadRequest, get(admin, query).Code, query)
+	}
+	assert.Equal(t, http.StatusForbidden, get(uuid.New(), "").Code)
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/star"
+)
+
+// StarInterviewRequest is the request body for one turn of a STAR story interview
+type StarInterviewRequest struct {
+	Answers star.Answers `json:"answers"` // Answers so far; the client keeps the interview state
+}
+
+// StarInterviewResponse holds the next question, or the drafted story once every stage
+// is answered
+type StarInterviewResponse struct {
+	Next  *star.Question `json:"next,omitempty"`
+	Draft *star.Draft    `json:"draft,omitempty"`
+}
+
+// CreateStoryRequest is the request body for saving a confirmed story to the experience bank
+type CreateStoryRequest struct {
+	JobID uuid.UUID `json:"job_id"` // Job the story is filed under
+	star.Draft
+}
+
+// handleStarInterview asks the next Situation/Task/Action/Result question, then drafts
+// a story from the answers. Nothing is saved; the client confirms the draft through
+// handleCreateStory.
+func (s *Server) handleStarInterview(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathUserIsCaller(w, r, "experience bank"); !ok {
+		return
+	}
+
+	var req StarInterviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var resp StarInterviewResponse
+	var err error
+	if req.Answers.NextStage() != "" {
+		resp.Next, err = star.NextQuestion(r.Context(), &req.Answers, s.apiKey)
+	} else {
+		resp.Draft, err = star.DraftStory(r.Context(), &req.Answers, s.apiKey)
+	}
+	if err != nil {
+		var verr *star.ValidationError
+		if errors.As(err, &verr) {
+			s.errorResponse(w, http.StatusBadRequest, verr.Error())
+			return
+		}
+		s.errorResponse(w, http.StatusBadGateway, "Failed to continue interview: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, resp)
+}
+
+// handleCreateStory saves a story the caller confirmed, such as a STAR interview draft,
+// to their experience bank under one of their jobs
+func (s *Server) handleCreateStory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "experience bank")
+	if !ok {
+		return
+	}
+
+	var req CreateStoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.JobID == uuid.Nil {
+		s.errorResponse(w, http.StatusBadRequest, "job_id is required")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !s.requireOwnJob(w, r, userID, req.JobID) {
+		return
+	}
+
+	story, err := s.db.CreateStory(r.Context(), req.StoryInput(userID, req.JobID))
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusCreated, story)
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/star"
+)
+
+func TestHandleStarInterview(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	s.apiKey = "test-key"
+	cassette := llm.NewCassette([]llm.Exchange{
+		{Prompt: "You are helping a job seeker", Response: "What did you own on the checkout team?"},
+		{Prompt: "Turn the following STAR interview answers", JSON: true,
+			Response: `{"title":"Checkout latency","bullets":[{"text":"Cut checkout p99 latency 40% with Redis caching","metrics":"40%","skills":["Redis"]}]}`},
+	})
+	interview := func(body string) (*StarInterviewResponse, int) {
+		req := bearerRequest(t, s, http.MethodPost, "/v1/users/"+owner.String()+"/experience-bank/star", owner, []byte(body))
+		w := serveAuthed(t, s, "POST /v1/users/{id}/experience-bank/star", s.handleStarInterview,
+			req.WithContext(llm.WithCassette(req.Context(), cassette)))
+		var resp StarInterviewResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return &resp, w.Code
+	}
+
+	resp, code := interview(`{"answers":{"situation":"Checkout was slow"}}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, &star.Question{Stage: star.StageTask, Question: "What did you own on the checkout team?"}, resp.Next)
+	assert.Nil(t, resp.Draft)
+
+	resp, code = interview(`{"answers":{"situation":"Checkout was slow","task":"Own latency","action":"Added Redis caching","result":"p99 fell 40%"}}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Nil(t, resp.Next)
+	require.NotNil(t, resp.Draft)
+	assert.Equal(t, "Checkout latency", resp.Draft.Title)
+	assert.Empty(t, s.mock.createdStories, "drafts are not saved until confirmed")
+}
+
+func TestHandleCreateStory(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	jobID := uuid.New()
+	s.mock.jobs = []db.Job{{ID: jobID, UserID: owner, Company: "Acme", StartDate: &db.Date{Time: time.Now()}}}
+	create := func(caller uuid.UUID, body string) int {
+		return serveAuthed(t, s, "POST /v1/users/{id}/experience-bank/stories", s.handleCreateStory,
+			bearerRequest(t, s, http.MethodPost, "/v1/users/"+owner.String()+"/experience-bank/stories", caller, []byte(body))).Code
+	}
+
+	assert.Equal(t, http.StatusBadRequest, create(owner, `{"job_id":"`+jobID.String()+`","title":"Checkout","bullets":[]}`))
+	assert.Equal(t, http.StatusBadRequest, create(owner, `{"job_id":"`+uuid.New().String()+`","title":"Checkout","bullets":[{"text":"Cut latency"}]}`))
+	assert.Equal(t, http.StatusForbidden, create(uuid.New(), `{"job_id":"`+jobID.String()+`","title":"Checkout","bullets":[{"text":"Cut latency"}]}`))
+
+	require.Equal(t, http.StatusCreated, create(owner,
+		`{"job_id":"`+jobID.String()+`","title":"Checkout","bullets":[{"text":"Cut p99 latency 40%","metrics":"40%","skills":["Redis"]},{"text":"Mentored two engineers"}]}`))
+	require.Len(t, s.mock.createdStories, 1)
+	input := s.mock.createdStories[0]
+	assert.Equal(t, jobID, input.JobID)
+	require.Len(t, input.Bullets, 2)
+	assert.Equal(t, db.EvidenceStrengthHigh, input.Bullets[0].EvidenceStrength)
+	assert.Equal(t, db.EvidenceStrengthMedium, input.Bullets[1].EvidenceStrength)
+	assert.Equal(t, input.StoryID+"-2", input.Bullets[1].BulletID)
+}
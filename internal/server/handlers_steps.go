@@ -1,14 +1,22 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/pipeline"
 	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/scheduler"
 )
 
 // RunCreateRequest represents the request to create a new pipeline run
@@ -19,14 +27,42 @@ type RunCreateRequest struct {
 	Template   string `json:"template"`    // optional
 	MaxBullets int    `json:"max_bullets"` // optional
 	MaxLines   int    `json:"max_lines"`   // optional
+	MaxPages   int    `json:"max_pages"`   // optional: pages the resume may fill, 1 (default) or 2
+	Execute    bool   `json:"execute"`     // optional: queue the run for a background worker that executes every step
+
+	// Optional: cover_letter or refresh runs reuse the artifacts of SourceRunID, or of
+	// the user's latest run that rendered a resume, and need no job input
+	RunType     string `json:"run_type,omitempty"`
+	SourceRunID string `json:"source_run_id,omitempty"`
+
+	// Optional, and only with "execute": true
+	Priority string       `json:"priority,omitempty"` // interactive, normal (default), or bulk scheduling class
+	Debug    bool         `json:"debug,omitempty"`    // Store redacted raw LLM prompts/responses (owner/admin only)
+	Crawl    *CrawlParams `json:"crawl,omitempty"`    // Research crawl limits; omitted fields use server defaults
+}
+
+// setDefaults fills in the template and limits a request left unset
+func (req *RunCreateRequest) setDefaults() {
+	if req.Template == "" {
+		req.Template = "templates/one_page_resume.tex"
+	}
+	pages := resumePages(req.MaxPages)
+	if req.MaxBullets == 0 {
+		req.MaxBullets = 25 * pages
+	}
+	if req.MaxLines == 0 {
+		req.MaxLines = 35 * pages
+	}
 }
 
 // RunCreateResponse represents the response for creating a run
 type RunCreateResponse struct {
-	RunID     string         `json:"run_id"`
-	Status    string         `json:"status"`
-	CreatedAt string         `json:"created_at"`
-	Steps     RunStepsStatus `json:"steps"`
+	RunID       string         `json:"run_id"`
+	Status      string         `json:"status"`
+	CreatedAt   string         `json:"created_at"`
+	RunType     string         `json:"run_type,omitempty"`
+	SourceRunID string         `json:"source_run_id,omitempty"`
+	Steps       RunStepsStatus `json:"steps"`
 }
 
 // RunStepsStatus represents the status of steps for a run
@@ -38,6 +74,8 @@ type RunStepsStatus struct {
 
 // StepExecuteRequest represents the request to execute a step
 type StepExecuteRequest struct {
+	// Parameters are step options; "model" overrides the step's routed model
+	// with a tier name (lite, standard, advanced) or a configured model
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
 }
 
@@ -52,6 +90,8 @@ type StepExecuteResponse struct {
 	ArtifactID  *string             `json:"artifact_id,omitempty"`
 	NextSteps   []string            `json:"next_steps,omitempty"`
 	Checkpoint  *CheckpointResponse `json:"checkpoint,omitempty"`
+	Model       string              `json:"model,omitempty"`            // Routed tier or model, when not the default
+	Downgraded  bool                `json:"model_downgraded,omitempty"` // Routed to a cheaper tier due to low quota
 }
 
 // CheckpointResponse represents a checkpoint
@@ -81,6 +121,7 @@ type RunStepsListResponse struct {
 	Company   *string              `json:"company,omitempty"`
 	RoleTitle *string              `json:"role_title,omitempty"`
 	CreatedAt string               `json:"created_at,omitempty"`
+	Job       *RunJobStatus        `json:"job,omitempty"` // Set for runs executed by a background worker
 	Steps     []StepStatusResponse `json:"steps"`
 	Summary   RunStepsSummary      `json:"summary"`
 }
@@ -120,7 +161,8 @@ type CheckpointGetResponse struct {
 	Artifacts          map[string]interface{} `json:"artifacts"`
 }
 
-// handleCreateRun creates a new pipeline run for step-by-step execution
+// handleCreateRun creates a new pipeline run for step-by-step execution, or with
+// "execute": true queues it for the run workers to execute every step
 func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 	var req RunCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -161,21 +203,37 @@ func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate job input
-	if req.JobURL == "" && req.JobText == "" {
+	if !steps.ValidRunType(req.RunType) {
+		s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Unknown run_type: %s", req.RunType))
+		return
+	}
+	_, typed := steps.RunTypes[req.RunType]
+
+	// Validate job input; cover-letter and refresh runs reuse their source run's posting
+	if !typed && req.JobURL == "" && req.JobText == "" {
 		s.errorResponse(w, http.StatusBadRequest, "Either job_url or job_text is required")
 		return
 	}
+	if err := validateMaxPages(req.MaxPages); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	// Set defaults
-	if req.Template == "" {
-		req.Template = "templates/one_page_resume.tex"
+	if !req.Execute && (req.Priority != "" || req.Debug || req.Crawl != nil) {
+		s.errorResponse(w, http.StatusBadRequest, `priority, debug, and crawl apply only to runs created with "execute": true`)
+		return
 	}
-	if req.MaxBullets == 0 {
-		req.MaxBullets = 25
+
+	req.setDefaults()
+
+	if typed {
+		s.createTypedRun(w, r, userID, &req)
+		return
 	}
-	if req.MaxLines == 0 {
-		req.MaxLines = 35
+
+	if req.Execute {
+		s.enqueueRun(w, r, userID, &req)
+		return
 	}
 
 	// Create a pipeline run in the database
@@ -193,14 +251,19 @@ func (s *Server) handleCreateRun(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update run with user_id
-	_, err = s.db.Pool().Exec(r.Context(),
-		"UPDATE pipeline_runs SET user_id = $1 WHERE id = $2",
-		userID, runID)
-	if err != nil {
+	if err := s.db.SetRunUserID(r.Context(), runID, userID); err != nil {
 		s.errorResponse(w, http.StatusInternalServerError, "Failed to update run: "+err.Error())
 		return
 	}
 
+	// Steps executed one at a time hold the resume to the pages asked for here
+	if req.MaxPages != 0 {
+		if err := s.db.SetRunMaxPages(r.Context(), runID, req.MaxPages); err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, "Failed to update run: "+err.Error())
+			return
+		}
+	}
+
 	// Get available steps (should be just ingest_job initially)
 	available, err := steps.GetAvailableSteps(r.Context(), s.db, runID)
 	if err != nil {
@@ -248,6 +311,30 @@ func (s *Server) handleExecuteStep(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Parse and validate the request parameters before checking the run's state
+	var stepReq StepExecuteRequest
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&stepReq); err != nil && !errors.Is(err, io.EOF) {
+			s.errorResponse(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	// Get step definition
+	def, ok := steps.StepRegistry[stepName]
+	if !ok {
+		s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Unknown step: %s", stepName))
+		return
+	}
+	if !steps.InRunType(run.RunType, stepName) {
+		s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Step %s is not part of %s runs", stepName, run.RunType))
+		return
+	}
+	if err := steps.ValidateParams(stepName, stepReq.Parameters); err != nil {
+		s.invalidParamsResponse(w, stepName, err)
+		return
+	}
+
 	// Check if step is already completed or in progress
 	existingStep, err := s.db.GetRunStep(r.Context(), runID, stepName)
 	if err != nil {
@@ -286,59 +373,62 @@ func (s *Server) handleExecuteStep(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse request body for parameters
-	var stepReq StepExecuteRequest
-	if r.Body != nil {
-		_ = json.NewDecoder(r.Body).Decode(&stepReq)
-	}
-
-	// Get step definition
-	def, ok := steps.StepRegistry[stepName]
-	if !ok {
-		s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("Unknown step: %s", stepName))
+	// Resolve the step's model: explicit override, then routing config, with a
+	// downgrade when the run owner is close to their quota
+	override, _ := stepReq.Parameters["model"].(string)
+	downgrade := run.UserID != nil && s.modelDowngrade(r.Context(), *run.UserID)
+	selection, err := s.routing.Select(stepName, override, downgrade)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	params := stepParameters(stepReq.Parameters, selection)
 
-	// Create or update step record
-	if existingStep == nil {
-		stepInput := &db.RunStepInput{
-			Step:       stepName,
-			Category:   def.Category,
-			Status:     db.StepStatusInProgress,
-			Parameters: stepReq.Parameters,
-		}
-		_, err = s.db.CreateRunStep(r.Context(), runID, stepInput)
-		if err != nil {
-			s.errorResponse(w, http.StatusInternalServerError, "Failed to create step record: "+err.Error())
-			return
-		}
-	} else {
-		err = s.db.UpdateRunStepStatus(r.Context(), runID, stepName, db.StepStatusInProgress, nil, nil)
-		if err != nil {
-			s.errorResponse(w, http.StatusInternalServerError, "Failed to update step status: "+err.Error())
-			return
-		}
+	// Steps share the run scheduler's per-user limits with full pipeline runs
+	owner := uuid.Nil
+	if run.UserID != nil {
+		owner = *run.UserID
+	}
+	if s.scheduler.QueueFull(owner) {
+		s.runQueueFullResponse(w, owner)
+		return
 	}
 
-	// TODO: Execute the actual step using step executors
-	// For now, we'll mark it as completed immediately as a placeholder
-	// This will be replaced with actual step execution logic
-	startTime := time.Now()
-
-	// Placeholder: In a real implementation, we would call:
-	// executor := steps.GetExecutor(stepName)
-	// result, err := executor.Execute(r.Context(), runID, stepReq.Parameters)
-
-	// For now, simulate completion
-	duration := int(time.Since(startTime).Milliseconds())
-	err = s.db.UpdateRunStepStatus(r.Context(), runID, stepName, db.StepStatusCompleted, nil, nil)
+	var (
+		startTime time.Time
+		duration  int
+		execErr   error
+	)
+	started := false
+	ticket, err := s.scheduler.Submit(r.Context(), scheduler.Task{
+		UserID:   owner,
+		Priority: scheduler.PriorityInteractive,
+		Run: func(ctx context.Context) {
+			started = true
+			startTime = time.Now()
+			execErr = s.executeStep(ctx, run, stepName, def.Category, existingStep, params)
+			duration = int(time.Since(startTime).Milliseconds())
+		},
+	})
 	if err != nil {
-		s.errorResponse(w, http.StatusInternalServerError, "Failed to update step status: "+err.Error())
+		// The queue filled up after the check above
+		s.runQueueFullResponse(w, owner)
+		return
+	}
+	<-ticket.Done()
+	if !started {
+		// The client went away while the step was queued; nothing was recorded
+		slog.InfoContext(r.Context(), "Step canceled while queued", logging.KeyRunID, runID, logging.KeyStep, stepName)
+		return
+	}
+	if execErr != nil {
+		s.errorResponse(w, http.StatusInternalServerError, execErr.Error())
 		return
 	}
 
 	// Get next available steps
 	available, _ := steps.GetAvailableSteps(r.Context(), s.db, runID)
+	available = steps.FilterRunType(run.RunType, available)
 
 	// Create checkpoint
 	checkpointInput := &db.RunCheckpointInput{
@@ -353,7 +443,7 @@ func (s *Server) handleExecuteStep(w http.ResponseWriter, r *http.Request) {
 		checkpointResp = &CheckpointResponse{
 			Step:        checkpoint.Step,
 			RunID:       checkpoint.RunID.String(),
-			CompletedAt: checkpoint.CompletedAt.Format(time.RFC3339),
+			CompletedAt: formatTimestamp(checkpoint.CompletedAt),
 			Artifacts:   checkpoint.Artifacts,
 		}
 	}
@@ -363,14 +453,142 @@ func (s *Server) handleExecuteStep(w http.ResponseWriter, r *http.Request) {
 		Step:        stepName,
 		Status:      db.StepStatusCompleted,
 		RunID:       runID.String(),
-		StartedAt:   startTime.Format(time.RFC3339),
-		CompletedAt: completedAt.Format(time.RFC3339),
+		StartedAt:   formatTimestamp(startTime),
+		CompletedAt: formatTimestamp(completedAt),
 		DurationMs:  &duration,
 		NextSteps:   available,
 		Checkpoint:  checkpointResp,
+		Model:       selectionName(selection),
+		Downgraded:  selection.Downgrade,
 	})
 }
 
+// executeStep records a step as in progress, runs it with its executor, and marks
+// it completed, or failed with the executor's error
+func (s *Server) executeStep(ctx context.Context, run *db.Run, stepName, category string, existing *db.RunStep, params map[string]interface{}) error {
+	runID := run.ID
+	ctx = logging.WithStep(logging.WithRunID(ctx, runID), stepName)
+	if existing == nil {
+		stepInput := &db.RunStepInput{
+			Step:       stepName,
+			Category:   category,
+			Status:     db.StepStatusInProgress,
+			Parameters: params,
+		}
+		if _, err := s.db.CreateRunStep(ctx, runID, stepInput); err != nil {
+			return fmt.Errorf("failed to create step record: %w", err)
+		}
+	} else {
+		if err := s.db.UpdateRunStepParameters(ctx, runID, stepName, params); err != nil {
+			return err
+		}
+		if err := s.db.UpdateRunStepStatus(ctx, runID, stepName, db.StepStatusInProgress, nil, nil); err != nil {
+			return fmt.Errorf("failed to update step status: %w", err)
+		}
+	}
+
+	executor, err := s.stepExecutor(ctx, run, stepName)
+	if err == nil {
+		var result *steps.StepResult
+		if result, err = executor.Execute(ctx, runID, params); err == nil {
+			if err := s.db.UpdateRunStepStatus(ctx, runID, stepName, db.StepStatusCompleted, nil, result.ArtifactID); err != nil {
+				return fmt.Errorf("failed to update step status: %w", err)
+			}
+			return nil
+		}
+	}
+
+	// Record the failure even when the client went away mid-step
+	msg := err.Error()
+	if uerr := s.db.UpdateRunStepStatus(context.WithoutCancel(ctx), runID, stepName, db.StepStatusFailed, &msg, nil); uerr != nil {
+		slog.WarnContext(ctx, "failed to record step failure", "error", uerr)
+	}
+	return fmt.Errorf("step %s failed: %w", stepName, err)
+}
+
+// newStepExecutor builds the executor for a step API request with the options a
+// full run would use: the owner's profile, server defaults, and model routing.
+// Step parameters override them when the step executes.
+func (s *Server) newStepExecutor(ctx context.Context, database *db.DB, run *db.Run, stepName string) (steps.StepExecutor, error) {
+	crawl, err := s.crawlLimits(nil)
+	if err != nil {
+		return nil, err
+	}
+	opts := pipeline.RunOptions{
+		JobURL:       run.JobURL,
+		TemplatePath: "templates/one_page_resume.tex",
+		MaxBullets:   25,
+		MaxLines:     35,
+		APIKey:       s.apiKey,
+		Verbose:      true,
+		UserID:       run.UserID,
+		Crawl:        crawl,
+		ModelRouting: s.routing,
+		Demo:         s.demo,
+	}
+	if run.UserID != nil {
+		uid := *run.UserID
+		user, err := s.db.GetUser(ctx, uid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch user profile: %w", err)
+		}
+		if user != nil {
+			opts.CandidateName, opts.CandidateEmail, opts.CandidatePhone = user.Name, user.Email, user.Phone
+		}
+		opts.RedactPII = s.redactPII(ctx, uid)
+		if stepName == "load_experience" {
+			if opts.ExperienceData, err = s.fetchExperienceBankFromDB(ctx, uid); err != nil {
+				return nil, fmt.Errorf("failed to fetch experience data: %w", err)
+			}
+		}
+	}
+	return pipeline.NewStepExecutor(stepName, pipeline.StepEnv{Database: database, Options: opts})
+}
+
+// invalidParamsResponse reports parameters that don't match a step's schema, with
+// every problem found and the parameters the step accepts
+func (s *Server) invalidParamsResponse(w http.ResponseWriter, stepName string, err error) {
+	var perr *steps.ParamError
+	if !errors.As(err, &perr) {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusBadRequest, map[string]interface{}{
+		"error": "Invalid step parameters: " + perr.Error(),
+		"details": map[string]interface{}{
+			"step":       stepName,
+			"errors":     perr.Issues,
+			"parameters": steps.ParamSchema(stepName),
+		},
+	})
+}
+
+// stepParameters returns the parameters a step is recorded and executed with: the
+// request's options, with "model" replaced by the resolved selection so a retry or
+// worker runs on the same model the response reported
+func stepParameters(requested map[string]interface{}, sel llm.Selection) map[string]interface{} {
+	params := make(map[string]interface{}, len(requested)+2)
+	for k, v := range requested {
+		params[k] = v
+	}
+	delete(params, "model")
+	if name := selectionName(sel); name != "" {
+		params["model"] = name
+	}
+	if sel.Downgrade {
+		params["model_downgraded"] = true
+	}
+	return params
+}
+
+// selectionName describes a model selection for API responses
+func selectionName(sel llm.Selection) string {
+	if sel.Model != "" {
+		return sel.Model
+	}
+	return string(sel.Tier)
+}
+
 // handleGetStepStatus returns the status of a specific step
 func (s *Server) handleGetStepStatus(w http.ResponseWriter, r *http.Request) {
 	runIDStr := r.PathValue("run_id")
@@ -394,11 +612,11 @@ func (s *Server) handleGetStepStatus(w http.ResponseWriter, r *http.Request) {
 
 	var startedAt, completedAt *string
 	if step.StartedAt != nil {
-		s := step.StartedAt.Format(time.RFC3339)
+		s := formatTimestamp(*step.StartedAt)
 		startedAt = &s
 	}
 	if step.CompletedAt != nil {
-		c := step.CompletedAt.Format(time.RFC3339)
+		c := formatTimestamp(*step.CompletedAt)
 		completedAt = &c
 	}
 
@@ -466,18 +684,21 @@ func (s *Server) handleListRunSteps(w http.ResponseWriter, r *http.Request) {
 	var stepsResp []StepStatusResponse
 	summary := RunStepsSummary{}
 
-	// Include all defined steps, even if not yet created
+	// Include all defined steps the run executes, even if not yet created
 	for stepName := range steps.StepRegistry {
+		if _, ok := allSteps[stepName]; !ok && !steps.InRunType(run.RunType, stepName) {
+			continue
+		}
 		var stepResp StepStatusResponse
 		if existing, ok := allSteps[stepName]; ok {
 			// Step exists in database
 			var startedAt, completedAt *string
 			if existing.StartedAt != nil {
-				s := existing.StartedAt.Format(time.RFC3339)
+				s := formatTimestamp(*existing.StartedAt)
 				startedAt = &s
 			}
 			if existing.CompletedAt != nil {
-				c := existing.CompletedAt.Format(time.RFC3339)
+				c := formatTimestamp(*existing.CompletedAt)
 				completedAt = &c
 			}
 			var artifactID *string
@@ -537,12 +758,19 @@ func (s *Server) handleListRunSteps(w http.ResponseWriter, r *http.Request) {
 		roleTitle = &run.RoleTitle
 	}
 
+	job, err := s.runJobStatus(r.Context(), runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
 	s.jsonResponse(w, http.StatusOK, RunStepsListResponse{
 		RunID:     runID.String(),
 		Status:    run.Status,
 		Company:   company,
 		RoleTitle: roleTitle,
-		CreatedAt: run.CreatedAt.Format(time.RFC3339),
+		CreatedAt: formatTimestamp(run.CreatedAt),
+		Job:       job,
 		Steps:     stepsResp,
 		Summary:   summary,
 	})
@@ -588,7 +816,7 @@ func (s *Server) handleGetCheckpoint(w http.ResponseWriter, r *http.Request) {
 	s.jsonResponse(w, http.StatusOK, CheckpointGetResponse{
 		RunID:              runID.String(),
 		CheckpointStep:     checkpoint.Step,
-		CheckpointAt:       checkpoint.CompletedAt.Format(time.RFC3339),
+		CheckpointAt:       formatTimestamp(checkpoint.CompletedAt),
 		CompletedSteps:     completedSteps,
 		NextAvailableSteps: available,
 		Artifacts:          checkpoint.Artifacts,
@@ -4,11 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/scheduler"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -94,6 +100,30 @@ func TestHandleCreateRun_MissingJobInput(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestHandleCreateRun_StoresMaxPages(t *testing.T) {
+	s := newTestServer()
+	user := uuid.New()
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Ada", Email: "ada@example.com"}}
+
+	create := func(maxPages int) *db.Run {
+		body, _ := json.Marshal(RunCreateRequest{UserID: user.String(), JobText: "Staff SRE at Acme", MaxPages: maxPages})
+		w := httptest.NewRecorder()
+		s.handleCreateRun(w, httptest.NewRequest(http.MethodPost, "/v1/runs", bytes.NewReader(body)))
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp RunCreateResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		run := s.mock.runs[uuid.MustParse(resp.RunID)]
+		require.NotNil(t, run)
+		return run
+	}
+
+	run := create(2)
+	require.NotNil(t, run.MaxPages, "steps executed later default to the requested pages")
+	assert.Equal(t, 2, *run.MaxPages)
+
+	assert.Nil(t, create(0).MaxPages, "runs that leave max_pages unset keep the default")
+}
+
 func TestHandleGetStepStatus_NotFound(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping test that requires database")
@@ -400,3 +430,164 @@ func TestHandleListRunSteps_BackwardCompatibility(t *testing.T) {
 	assert.NotNil(t, resp.Summary)
 	assert.GreaterOrEqual(t, resp.Summary.Total, 0) // At least Total should be present
 }
+
+func executeStepWithParams(t *testing.T, s *testServer, runID uuid.UUID, step, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/v1/runs/"+runID.String()+"/steps/"+step, bytes.NewBufferString(body))
+	req.SetPathValue("run_id", runID.String())
+	req.SetPathValue("step_name", step)
+	w := httptest.NewRecorder()
+	s.handleExecuteStep(w, req)
+	return w
+}
+
+func TestHandleExecuteStep_ModelRouting(t *testing.T) {
+	s := newTestServer()
+	s.routing = &llm.Routing{Steps: map[string]string{"ingest_job": "lite"}}
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID}
+
+	w := executeStepWithParams(t, s, runID, "ingest_job", `{}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp StepExecuteResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "lite", resp.Model)
+	assert.False(t, resp.Downgraded)
+
+	// A per-step override replaces the configured route
+	w = executeStepWithParams(t, s, runID, "ingest_job", `{"parameters": {"model": "gemini-2.5-pro"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "gemini-2.5-pro", resp.Model)
+
+	// The resolved model is stored with the step so execution uses it
+	require.Len(t, s.mock.runSteps[runID], 2)
+	assert.Equal(t, "lite", s.mock.runSteps[runID][0].Parameters["model"])
+	assert.Equal(t, "gemini-2.5-pro", s.mock.runSteps[runID][1].Parameters["model"])
+}
+
+func TestHandleExecuteStep_InvalidModelOverride(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID}
+
+	w := executeStepWithParams(t, s, runID, "ingest_job", `{"parameters": {"model": "unknown-model"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = executeStepWithParams(t, s, runID, "ingest_job", `{"parameters": {"model": 3}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandleExecuteStep_InvalidParameters(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID}
+
+	w := executeStepWithParams(t, s, runID, "parse_job", `{"parameters": {"max_bullets": 5}}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp struct {
+		Error   string `json:"error"`
+		Details struct {
+			Step       string                     `json:"step"`
+			Errors     []steps.ParamIssue         `json:"errors"`
+			Parameters map[string]steps.ParamSpec `json:"parameters"`
+		} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Error, "parameters.max_bullets is not a parameter of this step")
+	assert.Equal(t, "parse_job", resp.Details.Step)
+	assert.Equal(t, []steps.ParamIssue{{Param: "max_bullets", Message: "is not a parameter of this step; accepted: model"}}, resp.Details.Errors)
+	assert.Contains(t, resp.Details.Parameters, "model", "the response documents what the step accepts")
+
+	w = executeStepWithParams(t, s, runID, "select_plan", `{"parameters": {"max_bullets": 0}}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "must be at least 1")
+
+	w = executeStepWithParams(t, s, runID, "ingest_job", `{"parameters": [1]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "malformed bodies are rejected")
+	assert.Empty(t, s.mock.runSteps[runID], "nothing is recorded for rejected requests")
+}
+
+func TestHandleExecuteStep_DowngradeOnLowQuota(t *testing.T) {
+	s := newTestServer()
+	s.routing = &llm.Routing{Quota: llm.RoutingQuota{DailyRuns: 2, DowngradeBelow: 1}}
+	owner := uuid.New()
+	runID := uuid.New()
+	for _, id := range []uuid.UUID{runID, uuid.New()} {
+		s.mock.runs[id] = &db.Run{ID: id, UserID: &owner, CreatedAt: time.Now()}
+	}
+
+	w := executeStepWithParams(t, s, runID, "ingest_job", `{}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp StepExecuteResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Downgraded)
+	require.Len(t, s.mock.runSteps[runID], 1)
+	assert.Equal(t, true, s.mock.runSteps[runID][0].Parameters["model_downgraded"])
+}
+
+func TestHandleExecuteStep_QueueFull(t *testing.T) {
+	s := newTestServer()
+	s.scheduler = scheduler.New(1, 1, 1)
+	owner := uuid.New()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, UserID: &owner}
+
+	// Occupy the owner's only slot and queue slot
+	release := make(chan struct{})
+	defer close(release)
+	for i := 0; i < 2; i++ {
+		_, err := s.scheduler.Submit(context.Background(), scheduler.Task{UserID: owner, Run: func(context.Context) { <-release }})
+		require.NoError(t, err)
+	}
+
+	w := executeStepWithParams(t, s, runID, "ingest_job", `{}`)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
+	assert.Empty(t, s.mock.runSteps[runID])
+}
+
+func TestHandleExecuteStep_RunsExecutor(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID}
+	executor := &fakeStepExecutor{name: "ingest_job"}
+	s.stepExecutor = func(_ context.Context, run *db.Run, stepName string) (steps.StepExecutor, error) {
+		assert.Equal(t, runID, run.ID)
+		return executor, nil
+	}
+
+	w := executeStepWithParams(t, s, runID, "ingest_job", `{"parameters": {"job_text": "Staff SRE at Acme"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "Staff SRE at Acme", executor.params["job_text"])
+	require.Len(t, s.mock.runSteps[runID], 1)
+	assert.Equal(t, db.StepStatusCompleted, s.mock.runSteps[runID][0].Status)
+}
+
+func TestHandleExecuteStep_RecordsFailure(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID}
+	s.stepExecutor = func(_ context.Context, _ *db.Run, stepName string) (steps.StepExecutor, error) {
+		return &fakeStepExecutor{name: stepName, err: errors.New("job ingestion from URL failed: 404")}, nil
+	}
+
+	w := executeStepWithParams(t, s, runID, "ingest_job", `{}`)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "step ingest_job failed: job ingestion from URL failed: 404")
+
+	require.Len(t, s.mock.runSteps[runID], 1)
+	step := s.mock.runSteps[runID][0]
+	assert.Equal(t, db.StepStatusFailed, step.Status)
+	require.NotNil(t, step.ErrorMessage)
+	assert.Equal(t, "job ingestion from URL failed: 404", *step.ErrorMessage)
+
+	// A step without an executor fails the same way
+	s.stepExecutor = func(context.Context, *db.Run, string) (steps.StepExecutor, error) {
+		return nil, errors.New("step executors require a database")
+	}
+	runID = uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID}
+	w = executeStepWithParams(t, s, runID, "ingest_job", `{}`)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, db.StepStatusFailed, s.mock.runSteps[runID][0].Status)
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// BulletSuggestionsResponse is the response for listing bullet suggestions
+type BulletSuggestionsResponse struct {
+	Suggestions []db.BulletSuggestion `json:"suggestions"`
+}
+
+// AcceptBulletSuggestionRequest is the request body for adding a confirmed suggestion to
+// the experience bank
+type AcceptBulletSuggestionRequest struct {
+	JobID uuid.UUID `json:"job_id"` // Job the story is filed under
+	Text  string    `json:"text"`   // The drafted bullet with its blanks filled in
+}
+
+// handleListBulletSuggestions returns the caller's bullet suggestions, optionally
+// filtered by ?status=
+func (s *Server) handleListBulletSuggestions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "bullet suggestions")
+	if !ok {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", db.DraftStatusPending, db.DraftStatusAccepted, db.DraftStatusDismissed:
+	default:
+		s.errorResponse(w, http.StatusBadRequest, "status must be pending, accepted, or dismissed")
+		return
+	}
+
+	suggestions, err := s.db.ListBulletSuggestions(r.Context(), userID, status)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, BulletSuggestionsResponse{Suggestions: suggestions})
+}
+
+// handleAcceptBulletSuggestion adds a suggestion the caller confirmed, with the blanks
+// filled in, to their experience bank as a story under one of their jobs
+func (s *Server) handleAcceptBulletSuggestion(w http.ResponseWriter, r *http.Request) {
+	userID, suggestion, ok := s.lookupBulletSuggestion(w, r)
+	if !ok {
+		return
+	}
+
+	var req AcceptBulletSuggestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.JobID == uuid.Nil {
+		s.errorResponse(w, http.StatusBadRequest, "job_id is required")
+		return
+	}
+	// Drafts are templates, not claims; only the user's own wording is banked
+	text := strings.TrimSpace(req.Text)
+	if text == "" || text == suggestion.Text {
+		s.errorResponse(w, http.StatusBadRequest, "text is required: complete the drafted bullet with what you did")
+		return
+	}
+	if strings.Contains(text, "[") && strings.Contains(text, "]") {
+		s.errorResponse(w, http.StatusBadRequest, "text still has bracketed blanks to fill in")
+		return
+	}
+
+	if !s.requireOwnJob(w, r, userID, req.JobID) {
+		return
+	}
+
+	accepted, err := s.db.AcceptBulletSuggestion(r.Context(), suggestion.ID, req.JobID, text)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if accepted == nil {
+		s.errorResponse(w, http.StatusConflict, "Suggestion was already reviewed")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, accepted)
+}
+
+// handleDismissBulletSuggestion rejects a suggestion; later runs will not ask again
+func (s *Server) handleDismissBulletSuggestion(w http.ResponseWriter, r *http.Request) {
+	_, suggestion, ok := s.lookupBulletSuggestion(w, r)
+	if !ok {
+		return
+	}
+
+	dismissed, err := s.db.DismissBulletSuggestion(r.Context(), suggestion.ID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if dismissed == nil {
+		s.errorResponse(w, http.StatusConflict, "Suggestion was already reviewed")
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, dismissed)
+}
+
+// lookupBulletSuggestion resolves the {suggestion_id} path value to one of the caller's
+// suggestions, writing a 404 for suggestions of other users
+func (s *Server) lookupBulletSuggestion(w http.ResponseWriter, r *http.Request) (uuid.UUID, *db.BulletSuggestion, bool) {
+	userID, ok := s.pathUserIsCaller(w, r, "bullet suggestions")
+	if !ok {
+		return uuid.Nil, nil, false
+	}
+
+	suggestionID, err := uuid.Parse(r.PathValue("suggestion_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid suggestion ID")
+		return uuid.Nil, nil, false
+	}
+
+	suggestion, err := s.db.GetBulletSuggestion(r.Context(), suggestionID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return uuid.Nil, nil, false
+	}
+	if suggestion == nil || suggestion.UserID != userID {
+		s.errorResponse(w, http.StatusNotFound, "Suggestion not found")
+		return uuid.Nil, nil, false
+	}
+	return userID, suggestion, true
+}
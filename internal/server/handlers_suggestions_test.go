@@ -0,0 +1,79 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestHandleAcceptBulletSuggestion(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	jobID := uuid.New()
+	s.mock.jobs = []db.Job{{ID: jobID, UserID: owner, Company: "Acme", StartDate: &db.Date{Time: time.Now()}}}
+	suggestion := &db.BulletSuggestion{
+		ID: uuid.New(), UserID: owner, Skill: "Kubernetes", Question: "Did you work with Kubernetes?",
+		Text: "Used Kubernetes to [what you built or improved], [measurable result].", Status: db.DraftStatusPending,
+	}
+	s.mock.suggestions = []*db.BulletSuggestion{suggestion}
+	target := "/v1/users/" + owner.String() + "/bullet-suggestions/" + suggestion.ID.String() + "/accept"
+	pattern := "POST /v1/users/{id}/bullet-suggestions/{suggestion_id}/accept"
+	accept := func(body string) *httptest.ResponseRecorder {
+		return serveAuthed(t, s, pattern, s.handleAcceptBulletSuggestion,
+			bearerRequest(t, s, http.MethodPost, target, owner, []byte(body)))
+	}
+
+	// The draft itself is never banked; the user must say what they actually did
+	assert.Equal(t, http.StatusBadRequest, accept(`{"job_id":"`+jobID.String()+`"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, accept(`{"job_id":"`+jobID.String()+`","text":"`+suggestion.Text+`"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, accept(`{"job_id":"`+jobID.String()+`","text":"Used Kubernetes to [what you built]."}`).Code)
+	assert.Equal(t, http.StatusBadRequest, accept(`{"job_id":"`+uuid.New().String()+`","text":"Ran 40 services on Kubernetes."}`).Code)
+
+	w := accept(`{"job_id":"` + jobID.String() + `","text":"Ran 40 services on Kubernetes."}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var accepted db.BulletSuggestion
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &accepted))
+	assert.Equal(t, db.DraftStatusAccepted, accepted.Status)
+	assert.Equal(t, "Ran 40 services on Kubernetes.", accepted.Text)
+	assert.NotNil(t, accepted.StoryID)
+
+	w = serveAuthed(t, s, "POST /v1/users/{id}/bullet-suggestions/{suggestion_id}/dismiss", s.handleDismissBulletSuggestion,
+		bearerRequest(t, s, http.MethodPost, strings.Replace(target, "/accept", "/dismiss", 1), owner, nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// Suggestions of other users are not visible
+	w = serveAuthed(t, s, pattern, s.handleAcceptBulletSuggestion,
+		bearerRequest(t, s, http.MethodPost, target, uuid.New(), []byte(`{}`)))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestHandleListBulletSuggestions(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	s.mock.suggestions = []*db.BulletSuggestion{
+		{ID: uuid.New(), UserID: owner, Status: db.DraftStatusPending},
+		{ID: uuid.New(), UserID: owner, Status: db.DraftStatusDismissed},
+		{ID: uuid.New(), UserID: uuid.New(), Status: db.DraftStatusPending},
+	}
+	target := "/v1/users/" + owner.String() + "/bullet-suggestions"
+
+	w := serveAuthed(t, s, "GET /v1/users/{id}/bullet-suggestions", s.handleListBulletSuggestions,
+		bearerRequest(t, s, http.MethodGet, target+"?status=pending", owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp BulletSuggestionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Suggestions, 1)
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/bullet-suggestions", s.handleListBulletSuggestions,
+		bearerRequest(t, s, http.MethodGet, target+"?status=bogus", owner, nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
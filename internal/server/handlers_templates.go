@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/pipeline"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	"github.com/jonathan/resume-customizer/internal/server/middleware"
+	"github.com/jonathan/resume-customizer/internal/templates"
+	"github.com/jonathan/resume-customizer/internal/toolchain"
+)
+
+// maxTemplateSize bounds uploaded templates; real resume templates are a few KB
+const maxTemplateSize = 256 << 10
+
+// TemplateLintRequest is the request body for linting a resume template
+type TemplateLintRequest struct {
+	Content string `json:"content"` // The template source
+}
+
+// TemplateLintResponse lists what must change before a template is accepted
+type TemplateLintResponse struct {
+	Valid    bool                    `json:"valid"` // No error findings
+	Findings []rendering.LintFinding `json:"findings"`
+}
+
+// TemplateUploadRequest is the request body for uploading a resume template
+type TemplateUploadRequest struct {
+	Name     string                     `json:"name"`    // Registry name runs select it by
+	Content  string                     `json:"content"` // The template source
+	Manifest rendering.TemplateManifest `json:"manifest"`
+}
+
+// TemplateUploadResponse is a stored template, or the lint findings that kept it out
+type TemplateUploadResponse struct {
+	Template *templates.Template     `json:"template,omitempty"`
+	Findings []rendering.LintFinding `json:"findings"`
+}
+
+// TemplateListResponse is the response for listing templates
+type TemplateListResponse struct {
+	Templates []templates.Template `json:"templates"` // Built-in templates, then the caller's own
+}
+
+// handleListTemplates lists the built-in templates and those the caller uploaded, with
+// the layout and style options each supports
+func (s *Server) handleListTemplates(w http.ResponseWriter, r *http.Request) {
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	uploaded, err := s.db.ListResumeTemplates(r.Context(), callerID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	list := templates.BuiltIn()
+	for i := range uploaded {
+		t, err := templates.FromUpload(&uploaded[i])
+		if err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		list = append(list, t)
+	}
+	s.jsonResponse(w, http.StatusOK, TemplateListResponse{Templates: list})
+}
+
+// handleUploadTemplate stores a template for the caller's runs, replacing their template
+// with the same name. It is refused with the lint findings if the lint reports errors.
+func (s *Server) handleUploadTemplate(w http.ResponseWriter, r *http.Request) {
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req TemplateUploadRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTemplateSize)).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if err := templates.ValidateName(req.Name); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		s.errorResponse(w, http.StatusBadRequest, "content is required")
+		return
+	}
+	if err := req.Manifest.Check(); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid manifest: "+err.Error())
+		return
+	}
+
+	findings := rendering.LintTemplate(req.Content, templatePackageChecker())
+	if findings == nil {
+		findings = []rendering.LintFinding{}
+	}
+	if rendering.HasLintErrors(findings) {
+		s.jsonResponse(w, http.StatusUnprocessableEntity, TemplateUploadResponse{Findings: findings})
+		return
+	}
+
+	manifest, err := json.Marshal(req.Manifest)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to encode manifest: "+err.Error())
+		return
+	}
+	stored, err := s.db.UpsertResumeTemplate(r.Context(), callerID, req.Name, req.Content, manifest)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	t, err := templates.FromUpload(stored)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, TemplateUploadResponse{Template: &t, Findings: findings})
+}
+
+// resolveTemplate finds a template file path or registry name for userID. It returns the
+// HTTP status to fail with.
+func (s *Server) resolveTemplate(ctx context.Context, userID uuid.UUID, template string) (*templates.Resolved, int, error) {
+	resolved, err := templates.Resolve(ctx, s.db, &userID, template)
+	if errors.Is(err, templates.ErrNotFound) {
+		return nil, http.StatusBadRequest, fmt.Errorf("template not found: %s", template)
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to resolve template: %w", err)
+	}
+	return resolved, 0, nil
+}
+
+// applyRunTemplate checks req's template against what rendering supports: that it exists,
+// offers the requested style, and fits max_lines on max_pages pages. A run that sets no
+// max_lines gets the template's max_lines_per_page, or 35, for each page. It returns the
+// HTTP status to fail with.
+func (s *Server) applyRunTemplate(ctx context.Context, req *RunRequest) (int, error) {
+	if err := validateMaxPages(req.MaxPages); err != nil {
+		return http.StatusBadRequest, err
+	}
+	pages := resumePages(req.MaxPages)
+
+	// handleRun rejects a malformed user_id itself; here it only finds no uploads
+	userID, _ := uuid.Parse(req.UserID)
+	resolved, status, err := s.resolveTemplate(ctx, userID, req.Template)
+	if err != nil {
+		return status, err
+	}
+	maxLines := resolved.Manifest.MaxLinesPerPage * pages
+	if req.MaxLines == 0 {
+		req.MaxLines = 35 * pages
+		if maxLines > 0 {
+			req.MaxLines = maxLines
+		}
+	}
+	if err := resolved.Manifest.Validate(req.Style); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("invalid style: %w", err)
+	}
+	if maxLines > 0 && req.MaxLines > maxLines {
+		return http.StatusBadRequest, fmt.Errorf("max_lines %d exceeds the %d lines template %s fits on %d page(s)", req.MaxLines, maxLines, req.Template, pages)
+	}
+	return 0, nil
+}
+
+// resumePages returns the pages a run's resume may fill given its max_pages
+func resumePages(maxPages int) int {
+	return max(maxPages, 1)
+}
+
+// validateMaxPages checks a run's max_pages; zero stands for one page
+func validateMaxPages(maxPages int) error {
+	if maxPages < 0 || maxPages > pipeline.MaxResumePages {
+		return fmt.Errorf("max_pages must be between 1 and %d", pipeline.MaxResumePages)
+	}
+	return nil
+}
+
+// handleLintTemplate checks an uploaded template for required placeholders, banned
+// commands, missing packages, and page geometry without storing it
+func (s *Server) handleLintTemplate(w http.ResponseWriter, r *http.Request) {
+	var req TemplateLintRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTemplateSize)).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		s.errorResponse(w, http.StatusBadRequest, "content is required")
+		return
+	}
+
+	findings := rendering.LintTemplate(req.Content, templatePackageChecker())
+	if findings == nil {
+		findings = []rendering.LintFinding{}
+	}
+	s.jsonResponse(w, http.StatusOK, TemplateLintResponse{
+		Valid:    !rendering.HasLintErrors(findings),
+		Findings: findings,
+	})
+}
+
+// templatePackageChecker checks packages against the LaTeX installation runs compile
+// with, or returns nil to skip the check when the toolchain is misconfigured
+func templatePackageChecker() rendering.PackageChecker {
+	tools, err := toolchain.Default()
+	if err != nil {
+		return nil
+	}
+	return tools.PackageInstalled
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	embedded "github.com/jonathan/resume-customizer/templates"
+)
+
+func TestHandleLintTemplate(t *testing.T) {
+	s := newAuthTestServer(t)
+	lint := func(body string) (int, TemplateLintResponse) {
+		t.Helper()
+		w := serveAuthed(t, s, "POST /v1/templates/lint", s.handleLintTemplate,
+			bearerRequest(t, s, http.MethodPost, "/v1/templates/lint", uuid.New(), []byte(body)))
+		var resp TemplateLintResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp
+	}
+
+	code, resp := lint(`{"content":"\\documentclass{article}\n\\begin{document}\\input{secrets}\\end{document}"}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, resp.Valid)
+	var rules []string
+	for _, f := range resp.Findings {
+		rules = append(rules, f.Rule)
+	}
+	assert.Contains(t, rules, rendering.LintRuleBannedCommand)
+	assert.Contains(t, rules, rendering.LintRuleMissingPlaceholder)
+
+	code, _ = lint(`{"content":"  "}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = lint(`{`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestHandleUploadAndListTemplates(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	s := newAuthTestServer(t)
+	owner := uuid.New()
+	source, err := embedded.Files.ReadFile("one_page_resume.tex")
+	require.NoError(t, err)
+	upload := func(name, content, manifest string) (int, TemplateUploadResponse) {
+		t.Helper()
+		body, err := json.Marshal(map[string]any{"name": name, "content": content, "manifest": json.RawMessage(manifest)})
+		require.NoError(t, err)
+		w := serveAuthed(t, s, "POST /v1/templates", s.handleUploadTemplate,
+			bearerRequest(t, s, http.MethodPost, "/v1/templates", owner, body))
+		var resp TemplateUploadResponse
+		if w.Code == http.StatusOK || w.Code == http.StatusUnprocessableEntity {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp
+	}
+
+	code, _ := upload("one_page_resume", string(source), `{}`)
+	assert.Equal(t, http.StatusBadRequest, code, "built-in names are reserved")
+	code, _ = upload("compact", string(source), `{"font_sizes":["9pt"]}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, resp := upload("compact", `\documentclass{article}\begin{document}\input{x}\end{document}`, `{}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.NotEmpty(t, resp.Findings)
+	assert.Empty(t, s.mock.resumeTemplates[owner], "templates with lint errors are not stored")
+
+	code, resp = upload("compact", string(source), `{"columns":1,"max_lines_per_page":30,"sections":["experience","education"]}`)
+	require.Equal(t, http.StatusOK, code, resp.Findings)
+	require.NotNil(t, resp.Template)
+	assert.Equal(t, 30, resp.Template.MaxLinesPerPage)
+
+	w := serveAuthed(t, s, "GET /v1/templates", s.handleListTemplates,
+		bearerRequest(t, s, http.MethodGet, "/v1/templates", owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var list TemplateListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	var names []string
+	for _, tmpl := range list.Templates {
+		names = append(names, tmpl.Name)
+	}
+	assert.Equal(t, []string{"one_page_resume", "compact"}, names)
+
+	// Runs select the upload by name and are held to its layout
+	req := &RunRequest{UserID: owner.String(), Template: "compact"}
+	_, err = s.applyRunTemplate(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 30, req.MaxLines, "the template's lines per page are the default")
+
+	status, err := s.applyRunTemplate(context.Background(), &RunRequest{UserID: owner.String(), Template: "compact", MaxLines: 40})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.ErrorContains(t, err, "fits on 1 page(s)")
+
+	// Two-page runs get twice the space
+	req = &RunRequest{UserID: owner.String(), Template: "compact", MaxPages: 2}
+	_, err = s.applyRunTemplate(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 60, req.MaxLines)
+	status, _ = s.applyRunTemplate(context.Background(), &RunRequest{UserID: owner.String(), Template: "compact", MaxPages: 2, MaxLines: 61})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, err = s.applyRunTemplate(context.Background(), &RunRequest{UserID: owner.String(), Template: "compact", MaxPages: 3})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.ErrorContains(t, err, "max_pages")
+	status, _ = s.applyRunTemplate(context.Background(), &RunRequest{UserID: owner.String(), Template: "compact",
+		MaxLines: 30, Style: &rendering.Style{FontFamily: "times"}})
+	assert.Equal(t, http.StatusBadRequest, status, "the upload offers no font families")
+	status, _ = s.applyRunTemplate(context.Background(), &RunRequest{UserID: uuid.New().String(), Template: "compact"})
+	assert.Equal(t, http.StatusBadRequest, status, "other users cannot select the upload")
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// handleRunThumbnail serves the PNG of a run's first resume page for list views
+func (s *Server) handleRunThumbnail(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID format")
+		return
+	}
+
+	png, err := s.loadThumbnail(r.Context(), runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if png == nil {
+		s.errorResponse(w, http.StatusNotFound, "Thumbnail not found for this run")
+		return
+	}
+	writeThumbnail(w, png)
+}
+
+// handleSharedResumeThumbnail serves the thumbnail of a shared resume for share cards
+func (s *Server) handleSharedResumeThumbnail(w http.ResponseWriter, r *http.Request) {
+	shared, _, ok := s.lookupSharedResume(w, r, db.SharedActionThumbnail)
+	if !ok {
+		return
+	}
+	png, err := s.loadThumbnail(r.Context(), shared.RunID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if png == nil {
+		s.errorResponse(w, http.StatusNotFound, "Page not found")
+		return
+	}
+	writeThumbnail(w, png)
+}
+
+// loadThumbnail returns a run's thumbnail PNG, or nil if none was rendered
+func (s *Server) loadThumbnail(ctx context.Context, runID uuid.UUID) ([]byte, error) {
+	encoded, err := s.db.GetTextArtifact(ctx, runID, db.StepResumeThumbnail)
+	if err != nil || encoded == "" {
+		return nil, err
+	}
+	png, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode thumbnail: %w", err)
+	}
+	return png, nil
+}
+
+func writeThumbnail(w http.ResponseWriter, png []byte) {
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Disposition", `inline; filename="resume.png"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(png)
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+var testThumbnail = []byte("\x89PNG\r\n\x1a\nthumbnail")
+
+func TestHandleRunThumbnail(t *testing.T) {
+	s := newTestServer()
+	runID := uuid.New()
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/runs/"+runID.String()+"/thumbnail.png", nil)
+		req.SetPathValue("id", runID.String())
+		w := httptest.NewRecorder()
+		s.handleRunThumbnail(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusNotFound, serve().Code)
+
+	s.mock.textArtifacts[runID.String()+":"+db.StepResumeThumbnail] = base64.StdEncoding.EncodeToString(testThumbnail)
+	w := serve()
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, testThumbnail, w.Body.Bytes())
+
+	s.mock.textArtifacts[runID.String()+":"+db.StepResumeThumbnail] = "not base64!"
+	assert.Equal(t, http.StatusInternalServerError, serve().Code)
+}
+
+func TestHandleSharedResumeThumbnail(t *testing.T) {
+	s := newAuthTestServer(t)
+	s.publicURL = "https://resumes.example.com"
+	runID := uuid.New()
+	s.mock.textArtifacts[runID.String()+":resume_tex"] = sharedTestTex
+	_, err := s.mock.UpsertSharedResume(context.Background(), &db.SharedResumeInput{UserID: uuid.New(), RunID: runID, Slug: "abc123"})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /r/{slug}", s.handleSharedResumePage)
+	mux.HandleFunc("GET /r/{slug}/thumbnail.png", s.handleSharedResumeThumbnail)
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	// Without a thumbnail the page has no share card image
+	assert.Equal(t, http.StatusNotFound, get("/r/abc123/thumbnail.png").Code)
+	assert.NotContains(t, get("/r/abc123").Body.String(), "og:image")
+
+	s.mock.textArtifacts[runID.String()+":"+db.StepResumeThumbnail] = base64.StdEncoding.EncodeToString(testThumbnail)
+	w := get("/r/abc123/thumbnail.png")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, testThumbnail, w.Body.Bytes())
+	assert.Contains(t, get("/r/abc123").Body.String(),
+		`<meta property="og:image" content="https://resumes.example.com/r/abc123/thumbnail.png">`)
+
+	assert.Equal(t, http.StatusNotFound, get("/r/unknown/thumbnail.png").Code)
+}
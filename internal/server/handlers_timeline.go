@@ -0,0 +1,226 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// TimelineEntry is a single bar on the run's Gantt chart
+type TimelineEntry struct {
+	Step         string   `json:"step"`
+	Category     string   `json:"category"`
+	Status       string   `json:"status"`
+	StartedAt    string   `json:"started_at"`
+	EndedAt      string   `json:"ended_at"`
+	OffsetMs     int64    `json:"offset_ms"`   // Start relative to the first step
+	DurationMs   int64    `json:"duration_ms"` // Measured duration (in-progress steps run until now)
+	Lane         int      `json:"lane"`        // Row index; steps in different lanes ran concurrently
+	Running      bool     `json:"running,omitempty"`
+	OverlapsWith []string `json:"overlaps_with,omitempty"`
+}
+
+// TimelineSummary aggregates how the run's wall-clock time was spent
+type TimelineSummary struct {
+	WallClockMs    int64 `json:"wall_clock_ms"`   // First step start to last step end
+	StepTimeMs     int64 `json:"step_time_ms"`    // Sum of all step durations
+	ParallelMs     int64 `json:"parallel_ms"`     // Time with two or more steps active
+	IdleMs         int64 `json:"idle_ms"`         // Time with no step active
+	MaxConcurrency int   `json:"max_concurrency"` // Peak number of simultaneously active steps
+	Lanes          int   `json:"lanes"`
+}
+
+// RunTimelineResponse is the response for GET /v1/runs/{id}/timeline
+type RunTimelineResponse struct {
+	RunID     string          `json:"run_id"`
+	Status    string          `json:"status"`
+	StartedAt *string         `json:"started_at,omitempty"`
+	EndedAt   *string         `json:"ended_at,omitempty"`
+	Steps     []TimelineEntry `json:"steps"`
+	NotRun    []string        `json:"not_run"` // Steps recorded without a start time
+	Summary   TimelineSummary `json:"summary"`
+}
+
+// handleGetRunTimeline returns per-step timing for rendering a Gantt chart
+func (s *Server) handleGetRunTimeline(w http.ResponseWriter, r *http.Request) {
+	runID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid run ID format")
+		return
+	}
+
+	run, err := s.db.GetRun(r.Context(), runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if run == nil {
+		s.errorResponse(w, http.StatusNotFound, "Run not found")
+		return
+	}
+
+	stepList, err := s.db.ListRunSteps(r.Context(), runID, nil, nil)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, buildRunTimeline(run, stepList, time.Now()))
+}
+
+// timelineInterval is a step's resolved [start, end) interval
+type timelineInterval struct {
+	step    db.RunStep
+	start   time.Time
+	end     time.Time
+	running bool
+}
+
+// buildRunTimeline converts run steps into Gantt rows.
+// Steps still in progress are treated as ending at now.
+func buildRunTimeline(run *db.Run, stepList []db.RunStep, now time.Time) RunTimelineResponse {
+	resp := RunTimelineResponse{
+		RunID:  run.ID.String(),
+		Status: run.Status,
+		Steps:  []TimelineEntry{},
+		NotRun: []string{},
+	}
+
+	var intervals []timelineInterval
+	for _, step := range stepList {
+		if step.StartedAt == nil {
+			resp.NotRun = append(resp.NotRun, step.Step)
+			continue
+		}
+		iv := timelineInterval{step: step, start: *step.StartedAt}
+		switch {
+		case step.CompletedAt != nil:
+			iv.end = *step.CompletedAt
+		case step.Status == db.StepStatusInProgress:
+			iv.end = now
+			iv.running = true
+		case step.DurationMs != nil:
+			iv.end = iv.start.Add(time.Duration(*step.DurationMs) * time.Millisecond)
+		default:
+			iv.end = iv.start
+		}
+		if iv.end.Before(iv.start) {
+			iv.end = iv.start
+		}
+		intervals = append(intervals, iv)
+	}
+	sort.Strings(resp.NotRun)
+
+	if len(intervals) == 0 {
+		return resp
+	}
+
+	sort.SliceStable(intervals, func(i, j int) bool {
+		if intervals[i].start.Equal(intervals[j].start) {
+			return intervals[i].step.Step < intervals[j].step.Step
+		}
+		return intervals[i].start.Before(intervals[j].start)
+	})
+
+	origin := intervals[0].start
+	last := intervals[0].end
+	for _, iv := range intervals {
+		if iv.end.After(last) {
+			last = iv.end
+		}
+	}
+
+	// Greedy lane assignment: reuse the first lane that is free by this step's start
+	var laneEnds []time.Time
+	for _, iv := range intervals {
+		lane := -1
+		for i, end := range laneEnds {
+			if !end.After(iv.start) {
+				lane = i
+				break
+			}
+		}
+		if lane == -1 {
+			lane = len(laneEnds)
+			laneEnds = append(laneEnds, iv.end)
+		} else {
+			laneEnds[lane] = iv.end
+		}
+
+		var overlaps []string
+		for _, other := range intervals {
+			if other.step.Step != iv.step.Step && other.start.Before(iv.end) && iv.start.Before(other.end) {
+				overlaps = append(overlaps, other.step.Step)
+			}
+		}
+
+		duration := iv.end.Sub(iv.start).Milliseconds()
+		resp.Steps = append(resp.Steps, TimelineEntry{
+			Step:         iv.step.Step,
+			Category:     iv.step.Category,
+			Status:       iv.step.Status,
+			StartedAt:    iv.start.UTC().Format(time.RFC3339Nano),
+			EndedAt:      iv.end.UTC().Format(time.RFC3339Nano),
+			OffsetMs:     iv.start.Sub(origin).Milliseconds(),
+			DurationMs:   duration,
+			Lane:         lane,
+			Running:      iv.running,
+			OverlapsWith: overlaps,
+		})
+		resp.Summary.StepTimeMs += duration
+	}
+
+	startStr := origin.UTC().Format(time.RFC3339Nano)
+	endStr := last.UTC().Format(time.RFC3339Nano)
+	resp.StartedAt = &startStr
+	resp.EndedAt = &endStr
+	resp.Summary.WallClockMs = last.Sub(origin).Milliseconds()
+	resp.Summary.Lanes = len(laneEnds)
+	resp.Summary.ParallelMs, resp.Summary.IdleMs, resp.Summary.MaxConcurrency = sweepConcurrency(intervals)
+
+	return resp
+}
+
+// sweepConcurrency walks start/end events in time order to measure how much of
+// the run had overlapping steps, how much had none, and the peak concurrency
+func sweepConcurrency(intervals []timelineInterval) (parallelMs, idleMs int64, maxActive int) {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	events := make([]event, 0, len(intervals)*2)
+	for _, iv := range intervals {
+		if iv.end.Equal(iv.start) {
+			continue
+		}
+		events = append(events, event{iv.start, 1}, event{iv.end, -1})
+	}
+	// Ends sort before starts at the same instant so back-to-back steps don't count as overlapping
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].at.Before(events[j].at)
+	})
+
+	active := 0
+	for i, ev := range events {
+		if i > 0 {
+			span := ev.at.Sub(events[i-1].at).Milliseconds()
+			switch {
+			case active >= 2:
+				parallelMs += span
+			case active == 0:
+				idleMs += span
+			}
+		}
+		active += ev.delta
+		if active > maxActive {
+			maxActive = active
+		}
+	}
+	return parallelMs, idleMs, maxActive
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func timelineStep(name, status string, start time.Time, startOffset, endOffset time.Duration) db.RunStep {
+	started := start.Add(startOffset)
+	step := db.RunStep{Step: name, Category: "test", Status: status, StartedAt: &started}
+	if endOffset > 0 {
+		completed := start.Add(endOffset)
+		step.CompletedAt = &completed
+	}
+	return step
+}
+
+func TestBuildRunTimeline_ParallelBranches(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	run := &db.Run{ID: uuid.New(), Status: "completed"}
+
+	stepList := []db.RunStep{
+		timelineStep("parse_job", db.StepStatusCompleted, base, 0, 2*time.Second),
+		// Experience and research branches overlap from 2s to 5s
+		timelineStep("rank_stories", db.StepStatusCompleted, base, 2*time.Second, 5*time.Second),
+		timelineStep("research_company", db.StepStatusCompleted, base, 2*time.Second, 8*time.Second),
+		// Idle gap from 8s to 9s
+		timelineStep("rewrite_bullets", db.StepStatusCompleted, base, 9*time.Second, 10*time.Second),
+		{Step: "validate_latex", Status: db.StepStatusPending},
+	}
+
+	tl := buildRunTimeline(run, stepList, base.Add(time.Minute))
+
+	require.Len(t, tl.Steps, 4)
+	assert.Equal(t, []string{"validate_latex"}, tl.NotRun)
+
+	byName := make(map[string]TimelineEntry)
+	for _, e := range tl.Steps {
+		byName[e.Step] = e
+	}
+
+	assert.Equal(t, int64(0), byName["parse_job"].OffsetMs)
+	assert.Equal(t, int64(2000), byName["rank_stories"].OffsetMs)
+	assert.Equal(t, int64(6000), byName["research_company"].DurationMs)
+	assert.NotEqual(t, byName["rank_stories"].Lane, byName["research_company"].Lane)
+	assert.Equal(t, []string{"research_company"}, byName["rank_stories"].OverlapsWith)
+	assert.Empty(t, byName["parse_job"].OverlapsWith, "back-to-back steps should not overlap")
+
+	assert.Equal(t, int64(10000), tl.Summary.WallClockMs)
+	assert.Equal(t, int64(2000+3000+6000+1000), tl.Summary.StepTimeMs)
+	assert.Equal(t, int64(3000), tl.Summary.ParallelMs)
+	assert.Equal(t, int64(1000), tl.Summary.IdleMs)
+	assert.Equal(t, 2, tl.Summary.MaxConcurrency)
+	assert.Equal(t, 2, tl.Summary.Lanes)
+	require.NotNil(t, tl.StartedAt)
+	assert.Equal(t, base.Format(time.RFC3339Nano), *tl.StartedAt)
+}
+
+func TestBuildRunTimeline_InProgressStepEndsNow(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	run := &db.Run{ID: uuid.New(), Status: "running"}
+
+	stepList := []db.RunStep{
+		timelineStep("research_company", db.StepStatusInProgress, base, 0, 0),
+	}
+
+	tl := buildRunTimeline(run, stepList, base.Add(4*time.Second))
+
+	require.Len(t, tl.Steps, 1)
+	assert.True(t, tl.Steps[0].Running)
+	assert.Equal(t, int64(4000), tl.Steps[0].DurationMs)
+}
+
+func TestBuildRunTimeline_NoSteps(t *testing.T) {
+	run := &db.Run{ID: uuid.New(), Status: "running"}
+
+	tl := buildRunTimeline(run, nil, time.Now())
+
+	assert.Empty(t, tl.Steps)
+	assert.Nil(t, tl.StartedAt)
+	assert.Equal(t, int64(0), tl.Summary.WallClockMs)
+}
+
+func TestHandleGetRunTimeline(t *testing.T) {
+	s := newTestServer()
+	base := time.Now().Add(-time.Minute)
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, Status: "completed"}
+	s.mock.runSteps[runID] = []db.RunStep{
+		timelineStep("parse_job", db.StepStatusCompleted, base, 0, time.Second),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/"+runID.String()+"/timeline", nil)
+	req.SetPathValue("id", runID.String())
+	w := httptest.NewRecorder()
+	s.handleGetRunTimeline(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp RunTimelineResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, runID.String(), resp.RunID)
+	require.Len(t, resp.Steps, 1)
+	assert.Equal(t, int64(1000), resp.Steps[0].DurationMs)
+}
+
+func TestHandleGetRunTimeline_Errors(t *testing.T) {
+	s := newTestServer()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/runs/bad/timeline", nil)
+	req.SetPathValue("id", "bad")
+	w := httptest.NewRecorder()
+	s.handleGetRunTimeline(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	missing := uuid.New().String()
+	req = httptest.NewRequest(http.MethodGet, "/v1/runs/"+missing+"/timeline", nil)
+	req.SetPathValue("id", missing)
+	w = httptest.NewRecorder()
+	s.handleGetRunTimeline(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/pipeline"
+	"github.com/jonathan/resume-customizer/internal/rewriting"
+	"github.com/jonathan/resume-customizer/internal/server/middleware"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// maxToolBulletChars bounds the bullet POST /v1/tools/rewrite-bullet accepts
+const maxToolBulletChars = 1000
+
+// SummarizePostingRequest is the request body for POST /v1/tools/summarize-posting
+type SummarizePostingRequest struct {
+	JobURL  string `json:"job_url"`
+	JobText string `json:"job_text"` // Required if job_url not provided
+}
+
+// handleSummarizePosting ingests and parses a job posting and returns its job profile
+// without creating a run, so postings can be triaged before one is tailored to
+func (s *Server) handleSummarizePosting(w http.ResponseWriter, r *http.Request) {
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req SummarizePostingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	req.JobURL, req.JobText = strings.TrimSpace(req.JobURL), strings.TrimSpace(req.JobText)
+	if req.JobURL == "" && req.JobText == "" && s.demo == nil {
+		s.errorResponse(w, http.StatusBadRequest, "Either job_url or job_text is required")
+		return
+	}
+
+	opts := pipeline.RunOptions{
+		JobURL:         req.JobURL,
+		JobText:        req.JobText,
+		APIKey:         s.apiKey,
+		Demo:           s.demo,
+		ModelRouting:   s.routing,
+		ModelDowngrade: s.modelDowngrade(r.Context(), callerID),
+	}
+	profile, err := s.summarizePosting(r.Context(), opts)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnprocessableEntity, "Failed to summarize posting: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, profile)
+}
+
+// RewriteBulletRequest is the request body for POST /v1/tools/rewrite-bullet
+type RewriteBulletRequest struct {
+	Bullet         string                `json:"bullet"`
+	Variants       int                   `json:"variants,omitempty"`        // 2-3, default 3
+	RunID          *uuid.UUID            `json:"run_id,omitempty"`          // Optional: Use the job and company profiles of a run the caller owns
+	JobProfile     *types.JobProfile     `json:"job_profile,omitempty"`     // Optional: Job context; replaces the run's
+	CompanyProfile *types.CompanyProfile `json:"company_profile,omitempty"` // Optional: Company voice; replaces the run's
+}
+
+// RewriteBulletResponse is the response for POST /v1/tools/rewrite-bullet
+type RewriteBulletResponse struct {
+	Original string                  `json:"original"`
+	Variants []types.RewrittenBullet `json:"variants"`
+}
+
+// handleRewriteBullet rewrites one bullet a few ways, optionally for a job and company,
+// without a run
+func (s *Server) handleRewriteBullet(w http.ResponseWriter, r *http.Request) {
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req RewriteBulletRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	req.Bullet = strings.TrimSpace(req.Bullet)
+	if req.Bullet == "" {
+		s.errorResponse(w, http.StatusBadRequest, "bullet is required")
+		return
+	}
+	if len(req.Bullet) > maxToolBulletChars {
+		s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("bullet must be at most %d characters", maxToolBulletChars))
+		return
+	}
+	if req.Variants == 0 {
+		req.Variants = rewriting.DefaultVariants
+	}
+	if req.Variants < rewriting.MinVariants || req.Variants > rewriting.MaxVariants {
+		s.errorResponse(w, http.StatusBadRequest, fmt.Sprintf("variants must be between %d and %d", rewriting.MinVariants, rewriting.MaxVariants))
+		return
+	}
+
+	jobProfile, companyProfile := req.JobProfile, req.CompanyProfile
+	if req.RunID != nil {
+		if !s.loadRunContext(w, r, *req.RunID, callerID, &jobProfile, &companyProfile) {
+			return
+		}
+	}
+
+	variants, err := s.rewriteVariants(r.Context(), req.Bullet, jobProfile, companyProfile, req.Variants, s.apiKey)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadGateway, "Failed to rewrite bullet: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, RewriteBulletResponse{Original: req.Bullet, Variants: variants})
+}
+
+// loadRunContext fills the job and company profiles still nil from a run the caller
+// owns, writing an error response and returning false when it cannot
+func (s *Server) loadRunContext(w http.ResponseWriter, r *http.Request, runID, callerID uuid.UUID, jobProfile **types.JobProfile, companyProfile **types.CompanyProfile) bool {
+	run, err := s.db.GetRun(r.Context(), runID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return false
+	}
+	if run == nil {
+		s.errorResponse(w, http.StatusNotFound, "Run not found")
+		return false
+	}
+	if run.UserID == nil || *run.UserID != callerID {
+		s.errorResponse(w, http.StatusForbidden, "You can only use your own runs as context")
+		return false
+	}
+
+	for _, artifact := range []struct {
+		step   string
+		target any
+		set    bool
+	}{
+		{db.StepJobProfile, jobProfile, *jobProfile != nil},
+		{db.StepCompanyProfile, companyProfile, *companyProfile != nil},
+	} {
+		if artifact.set {
+			continue
+		}
+		if _, err := s.loadArtifact(r, runID, artifact.step, artifact.target); err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, err.Error())
+			return false
+		}
+	}
+	return true
+}
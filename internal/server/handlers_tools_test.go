@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/pipeline"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestHandleSummarizePosting(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	var got pipeline.RunOptions
+	s.summarizePosting = func(_ context.Context, opts pipeline.RunOptions) (*types.JobProfile, error) {
+		got = opts
+		if opts.JobURL == "https://jobs.example.com/broken" {
+			return nil, errors.New("job ingestion from URL failed: 404")
+		}
+		return &types.JobProfile{Company: "Acme", RoleTitle: "Staff SRE", Keywords: []string{"Kubernetes"}}, nil
+	}
+	summarize := func(body string) *http.Response {
+		w := serveAuthed(t, s, "POST /v1/tools/summarize-posting", s.handleSummarizePosting,
+			bearerRequest(t, s, http.MethodPost, "/v1/tools/summarize-posting", user, []byte(body)))
+		return w.Result()
+	}
+
+	resp := summarize(`{"job_text":"  Staff SRE at Acme  "}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var profile types.JobProfile
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&profile))
+	assert.Equal(t, "Staff SRE", profile.RoleTitle)
+	assert.Equal(t, "Staff SRE at Acme", got.JobText)
+	assert.Empty(t, s.mock.runs, "no run is created")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, summarize(`{"job_url":"https://jobs.example.com/broken"}`).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, summarize(`{}`).StatusCode)
+	assert.Equal(t, http.StatusBadRequest, summarize(`not json`).StatusCode)
+}
+
+func TestHandleRewriteBullet(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	s := newAuthTestServer(t)
+	runID := uuid.New()
+	s.mock.runs[runID] = &db.Run{ID: runID, UserID: &owner, Status: "completed"}
+	s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: db.StepJobProfile, Content: types.JobProfile{Keywords: []string{"Kubernetes"}}}
+	s.mock.artifacts[uuid.New()] = &db.Artifact{RunID: runID, Step: db.StepCompanyProfile, Content: types.CompanyProfile{Tone: "direct"}}
+
+	var gotJob *types.JobProfile
+	var gotCompany *types.CompanyProfile
+	var gotCount int
+	s.rewriteVariants = func(_ context.Context, bullet string, job *types.JobProfile, company *types.CompanyProfile, count int, _ string) ([]types.RewrittenBullet, error) {
+		gotJob, gotCompany, gotCount = job, company, count
+		variants := make([]types.RewrittenBullet, count)
+		for i := range variants {
+			variants[i] = types.RewrittenBullet{FinalText: fmt.Sprintf("%s (%d)", bullet, i+1)}
+		}
+		return variants, nil
+	}
+	rewrite := func(caller uuid.UUID, body string) *httptest.ResponseRecorder {
+		return serveAuthed(t, s, "POST /v1/tools/rewrite-bullet", s.handleRewriteBullet,
+			bearerRequest(t, s, http.MethodPost, "/v1/tools/rewrite-bullet", caller, []byte(body)))
+	}
+
+	// Without context
+	w := rewrite(owner, `{"bullet":"Made CI faster"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp RewriteBulletResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Made CI faster", resp.Original)
+	assert.Len(t, resp.Variants, 3)
+	assert.Nil(t, gotJob)
+	assert.Nil(t, gotCompany)
+
+	// A run's profiles, with the company voice replaced inline
+	w = rewrite(owner, `{"bullet":"Made CI faster","variants":2,"run_id":"`+runID.String()+`","company_profile":{"tone":"playful"}}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, gotCount)
+	require.NotNil(t, gotJob)
+	assert.Equal(t, []string{"Kubernetes"}, gotJob.Keywords)
+	assert.Equal(t, "playful", gotCompany.Tone)
+
+	assert.Equal(t, http.StatusForbidden, rewrite(other, `{"bullet":"Made CI faster","run_id":"`+runID.String()+`"}`).Code)
+	assert.Equal(t, http.StatusNotFound, rewrite(owner, `{"bullet":"Made CI faster","run_id":"`+uuid.NewString()+`"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, rewrite(owner, `{"bullet":"  "}`).Code)
+	assert.Equal(t, http.StatusBadRequest, rewrite(owner, `{"bullet":"Made CI faster","variants":5}`).Code)
+	assert.Equal(t, http.StatusBadRequest, rewrite(owner, `{"bullet":"`+strings.Repeat("a", maxToolBulletChars+1)+`"}`).Code)
+
+	s.rewriteVariants = func(context.Context, string, *types.JobProfile, *types.CompanyProfile, int, string) ([]types.RewrittenBullet, error) {
+		return nil, errors.New("provider unavailable")
+	}
+	assert.Equal(t, http.StatusBadGateway, rewrite(owner, `{"bullet":"Made CI faster"}`).Code)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/server/middleware"
 	"github.com/jonathan/resume-customizer/internal/types"
 )
 
@@ -87,6 +88,83 @@ func (s *Server) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
 	s.jsonResponse(w, http.StatusOK, map[string]string{"status": "updated"})
 }
 
+// PrivacySettingsRequest is the request body for updating a user's privacy settings
+type PrivacySettingsRequest struct {
+	RedactPII *bool `json:"redact_pii"`
+}
+
+// handleUpdatePrivacy toggles masking of the user's contact details in prompts sent to external LLMs
+func (s *Server) handleUpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	authenticatedUserID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	if authenticatedUserID != userID {
+		s.errorResponse(w, http.StatusForbidden, "You can only update your own privacy settings")
+		return
+	}
+
+	var req PrivacySettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.RedactPII == nil {
+		s.errorResponse(w, http.StatusBadRequest, "redact_pii is required")
+		return
+	}
+
+	if err := s.db.SetUserRedactPII(r.Context(), userID, *req.RedactPII); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]bool{"redact_pii": *req.RedactPII})
+}
+
+// TimezoneRequest is the request body for setting a user's time zone
+type TimezoneRequest struct {
+	Timezone string `json:"timezone" doc:"IANA time zone name, e.g. America/New_York"`
+}
+
+// handleUpdateTimezone sets the time zone the user's digest and reminder emails, calendar
+// feed, and reports are written in. API timestamps are UTC regardless.
+func (s *Server) handleUpdateTimezone(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "time zone")
+	if !ok {
+		return
+	}
+
+	var req TimezoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Timezone == "" {
+		s.errorResponse(w, http.StatusBadRequest, "timezone is required")
+		return
+	}
+	loc, err := loadTimezone(req.Timezone)
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.db.SetUserTimezone(r.Context(), userID, loc.String()); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, TimezoneRequest{Timezone: loc.String()})
+}
+
 func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	userID, err := uuid.Parse(idStr)
@@ -476,8 +554,26 @@ func (s *Server) fetchExperienceBankFromDB(ctx context.Context, userID uuid.UUID
 		})
 	}
 
+	// 5. Attach skill self-assessments
+	userSkills, err := s.db.ListUserSkills(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching skill assessments: %w", err)
+	}
+	var profile []types.SkillAssessment
+	for _, us := range userSkills {
+		a := types.SkillAssessment{Skill: us.Skill}
+		if us.Proficiency != nil {
+			a.Proficiency = *us.Proficiency
+		}
+		if us.LastUsed != nil {
+			a.LastUsed = us.LastUsed.Format("2006-01")
+		}
+		profile = append(profile, a)
+	}
+
 	return &types.ExperienceBank{
-		Stories:   stories,
-		Education: eduItems,
+		Stories:      stories,
+		Education:    eduItems,
+		SkillProfile: profile,
 	}, nil
 }
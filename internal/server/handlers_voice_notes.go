@@ -0,0 +1,210 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/star"
+	"github.com/jonathan/resume-customizer/internal/voicenote"
+)
+
+// maxWebhookBody bounds a pushed transcript, leaving room for JSON escaping
+const maxWebhookBody = 2 * star.MaxTranscriptLength
+
+// VoiceNoteWebhookRequest is the JSON request body for pushing a transcript
+type VoiceNoteWebhookRequest struct {
+	Transcript string `json:"transcript"`
+}
+
+// VoiceNoteWebhookResponse is the response for looking up a user's transcript webhook URL
+type VoiceNoteWebhookResponse struct {
+	URL string `json:"url"`
+}
+
+// handleUploadVoiceNote transcribes an uploaded recording and sorts it into STAR answers.
+// The recording is discarded once transcribed.
+func (s *Server) handleUploadVoiceNote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "voice notes")
+	if !ok {
+		return
+	}
+	if !s.transcribe.Enabled() {
+		s.errorResponse(w, http.StatusServiceUnavailable, "Audio transcription is not configured; push a transcript to the voice note webhook instead")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, s.transcribe.MaxUploadBytes())
+	file, header, err := r.FormFile("audio")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			s.errorResponse(w, http.StatusRequestEntityTooLarge, "Recording is too large")
+			return
+		}
+		s.errorResponse(w, http.StatusBadRequest, "Request must be multipart/form-data with an audio file")
+		return
+	}
+	defer func() { _ = file.Close() }()
+	if !voicenote.SupportedAudio(header.Filename) {
+		s.errorResponse(w, http.StatusBadRequest, "Unsupported audio format; use one of "+strings.Join(voicenote.AudioExtensions, ", "))
+		return
+	}
+
+	client := voicenote.NewClient(s.transcribe.URL, s.transcribe.APIKey, s.transcribe.Model)
+	transcript, err := client.Transcribe(r.Context(), header.Filename, file)
+	if err != nil {
+		if errors.Is(err, voicenote.ErrEmptyTranscript) {
+			s.errorResponse(w, http.StatusBadRequest, "No speech was recognized in the recording")
+			return
+		}
+		s.errorResponse(w, http.StatusBadGateway, "Failed to transcribe recording: "+err.Error())
+		return
+	}
+	s.captureVoiceNote(w, r, userID, db.VoiceNoteSourceUpload, transcript)
+}
+
+// handleVoiceNoteWebhook accepts a transcript pushed by a dictation or transcription
+// service, as JSON or plain text. It is authorized by the webhook token rather than a
+// bearer token so those services can be configured with a fixed URL.
+func (s *Server) handleVoiceNoteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	if !s.jwtService.ValidVoiceNoteWebhookToken(userID, r.URL.Query().Get("token")) {
+		s.errorResponse(w, http.StatusUnauthorized, "Invalid voice note webhook token")
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxWebhookBody)
+	var transcript string
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/plain" {
+		text, err := io.ReadAll(body)
+		if err != nil {
+			s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		transcript = string(text)
+	} else {
+		var req VoiceNoteWebhookRequest
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		transcript = req.Transcript
+	}
+	s.captureVoiceNote(w, r, userID, db.VoiceNoteSourceWebhook, transcript)
+}
+
+// captureVoiceNote sorts a transcript into STAR answers and saves the note with either a
+// drafted story, when every stage was covered, or the question to continue the interview
+// with. Nothing reaches the experience bank until the caller confirms the draft through
+// handleCreateStory.
+func (s *Server) captureVoiceNote(w http.ResponseWriter, r *http.Request, userID uuid.UUID, source, transcript string) {
+	answers, err := star.AnswersFromTranscript(r.Context(), transcript, s.apiKey)
+	var next *star.Question
+	var draft *star.Draft
+	if err == nil {
+		if answers.NextStage() != "" {
+			next, err = star.NextQuestion(r.Context(), answers, s.apiKey)
+		} else {
+			draft, err = star.DraftStory(r.Context(), answers, s.apiKey)
+		}
+	}
+	if err != nil {
+		var verr *star.ValidationError
+		if errors.As(err, &verr) {
+			s.errorResponse(w, http.StatusBadRequest, verr.Error())
+			return
+		}
+		s.errorResponse(w, http.StatusBadGateway, "Failed to draft story from voice note: "+err.Error())
+		return
+	}
+
+	input := &db.VoiceNoteInput{UserID: userID, Source: source, Transcript: strings.TrimSpace(transcript)}
+	if input.Answers, err = json.Marshal(answers); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Failed to encode answers: "+err.Error())
+		return
+	}
+	if draft != nil {
+		if input.Draft, err = json.Marshal(draft); err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, "Failed to encode draft: "+err.Error())
+			return
+		}
+	}
+	if next != nil {
+		input.NextStage, input.NextQuestion = next.Stage, next.Question
+	}
+
+	note, err := s.db.CreateVoiceNote(r.Context(), input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusCreated, note)
+}
+
+// handleListVoiceNotes lists the caller's voice notes, newest first
+func (s *Server) handleListVoiceNotes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "voice notes")
+	if !ok {
+		return
+	}
+
+	notes, err := s.db.ListVoiceNotes(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, notes)
+}
+
+// handleDeleteVoiceNote deletes one of the caller's voice notes and its transcript
+func (s *Server) handleDeleteVoiceNote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "voice notes")
+	if !ok {
+		return
+	}
+	noteID, err := uuid.Parse(r.PathValue("note_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid voice note ID")
+		return
+	}
+
+	note, err := s.db.GetVoiceNote(r.Context(), noteID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if note == nil || note.UserID != userID {
+		s.errorResponse(w, http.StatusNotFound, "Voice note not found")
+		return
+	}
+	if err := s.db.DeleteVoiceNote(r.Context(), noteID); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetVoiceNoteWebhookURL returns the URL the caller's dictation or transcription
+// service can push transcripts to
+func (s *Server) handleGetVoiceNoteWebhookURL(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "voice notes")
+	if !ok {
+		return
+	}
+
+	token := s.jwtService.VoiceNoteWebhookToken(userID)
+	s.jsonResponse(w, http.StatusOK, VoiceNoteWebhookResponse{
+		URL: "/v1/users/" + userID.String() + "/voice-notes/webhook?token=" + token,
+	})
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+)
+
+const completeAnswers = `{"situation":"Checkout was slow","task":"Own latency","action":"Added Redis caching","result":"p99 fell 40%"}`
+
+// voiceNoteCassette records the model sorting any transcript into answers
+func voiceNoteCassette(answers string) *llm.Cassette {
+	return llm.NewCassette([]llm.Exchange{
+		{Prompt: "The following is a transcript", JSON: true, Response: answers},
+		{Prompt: "You are helping a job seeker", Response: "What were you responsible for?"},
+		{Prompt: "Turn the following STAR interview answers", JSON: true,
+			Response: `{"title":"Checkout latency","bullets":[{"text":"Cut checkout p99 latency 40% with Redis caching","metrics":"40%","skills":["Redis"]}]}`},
+	})
+}
+
+func TestHandleVoiceNoteWebhook(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	s.apiKey = "test-key"
+
+	req := bearerRequest(t, s, http.MethodGet, "/v1/users/"+owner.String()+"/voice-notes/webhook", owner, nil)
+	w := serveAuthed(t, s, "GET /v1/users/{id}/voice-notes/webhook", s.handleGetVoiceNoteWebhookURL, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var hook VoiceNoteWebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &hook))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/users/{id}/voice-notes/webhook", s.handleVoiceNoteWebhook)
+	push := func(url, contentType, body, answers string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req.WithContext(llm.WithCassette(req.Context(), voiceNoteCassette(answers))))
+		return w
+	}
+
+	otherURL := "/v1/users/" + owner.String() + "/voice-notes/webhook?token=" + s.jwtService.VoiceNoteWebhookToken(uuid.New())
+	assert.Equal(t, http.StatusUnauthorized, push(otherURL, "text/plain", "checkout", completeAnswers).Code)
+	assert.Equal(t, http.StatusBadRequest, push(hook.URL, "application/json", `{"transcript":"  "}`, completeAnswers).Code)
+
+	w = push(hook.URL, "application/json", `{"transcript":"checkout was slow so I owned latency, added Redis, and p99 fell 40%"}`, completeAnswers)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var note db.VoiceNote
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &note))
+	assert.Equal(t, db.VoiceNoteSourceWebhook, note.Source)
+	assert.Contains(t, string(note.Draft), "Checkout latency")
+	assert.Nil(t, note.NextStage)
+
+	w = push(hook.URL, "text/plain; charset=utf-8", "mentoring new hires with weekly pairing",
+		`{"situation":"New hires took months to ramp up","action":"Ran weekly pairing sessions"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	note = db.VoiceNote{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &note))
+	assert.Empty(t, note.Draft, "an incomplete story continues as an interview")
+	require.NotNil(t, note.NextStage)
+	assert.Equal(t, "task", *note.NextStage)
+	assert.Equal(t, "What were you responsible for?", *note.NextQuestion)
+
+	assert.Len(t, s.mock.voiceNotes, 2)
+	assert.Empty(t, s.mock.createdStories, "drafts are not saved until confirmed")
+}
+
+func TestHandleUploadVoiceNote(t *testing.T) {
+	owner := uuid.New()
+	s := newAuthTestServer(t)
+	s.apiKey = "test-key"
+	target := "/v1/users/" + owner.String() + "/voice-notes"
+
+	upload := func(filename, audio string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("audio", filename)
+		require.NoError(t, err)
+		_, _ = part.Write([]byte(audio))
+		require.NoError(t, form.Close())
+		req := bearerRequest(t, s, http.MethodPost, target, owner, body.Bytes())
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		return serveAuthed(t, s, "POST /v1/users/{id}/voice-notes", s.handleUploadVoiceNote,
+			req.WithContext(llm.WithCassette(req.Context(), voiceNoteCassette(completeAnswers))))
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, upload("note.m4a", "audio").Code, "uploads need a transcription service")
+
+	transcriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"text":"checkout was slow so I owned latency, added Redis, and p99 fell 40%"}`))
+	}))
+	defer transcriber.Close()
+	s.transcribe = &config.TranscriptionConfig{URL: transcriber.URL, Model: "whisper-1", MaxUploadMB: 1}
+
+	assert.Equal(t, http.StatusBadRequest, upload("notes.txt", "audio").Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, upload("note.m4a", strings.Repeat("a", 2<<20)).Code)
+
+	w := upload("note.m4a", "audio")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.Len(t, s.mock.voiceNotes, 1)
+	note := s.mock.voiceNotes[0]
+	assert.Equal(t, db.VoiceNoteSourceUpload, note.Source)
+	assert.Contains(t, note.Transcript, "p99 fell 40%")
+
+	// Listing and deleting are limited to the owner
+	w = serveAuthed(t, s, "GET /v1/users/{id}/voice-notes", s.handleListVoiceNotes,
+		bearerRequest(t, s, http.MethodGet, target, owner, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var notes []db.VoiceNote
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &notes))
+	assert.Len(t, notes, 1)
+
+	del := func(caller uuid.UUID) int {
+		url := "/v1/users/" + caller.String() + "/voice-notes/" + note.ID.String()
+		return serveAuthed(t, s, "DELETE /v1/users/{id}/voice-notes/{note_id}", s.handleDeleteVoiceNote,
+			bearerRequest(t, s, http.MethodDelete, url, caller, nil)).Code
+	}
+	assert.Equal(t, http.StatusNotFound, del(uuid.New()))
+	assert.Equal(t, http.StatusNoContent, del(owner))
+	assert.Empty(t, s.mock.voiceNotes)
+}
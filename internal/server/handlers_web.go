@@ -0,0 +1,47 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// webFiles is the bundled web UI: sign-in, run creation with live step progress,
+// artifact preview, and resume download, for deployments without the separate frontend
+//
+//go:embed web
+var webFiles embed.FS
+
+// webCSP confines the web UI to its own scripts, styles, and API
+const webCSP = "default-src 'self'; img-src 'self' data: blob:; object-src 'none'; base-uri 'none'; frame-ancestors 'none'"
+
+// webUIEnabled reports whether the bundled web UI is served; WEB_UI=false turns it off
+func webUIEnabled() bool {
+	value := os.Getenv("WEB_UI")
+	if value == "" {
+		return true
+	}
+	enabled, _ := strconv.ParseBool(value)
+	return enabled
+}
+
+// webUIHandler serves the bundled web UI's page at / and its assets under /ui/
+func webUIHandler() http.Handler {
+	assets, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err) // The embedded directory is fixed at build time
+	}
+	files := http.FileServerFS(assets)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", webCSP)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if r.URL.Path == "/" {
+			http.ServeFileFS(w, r, assets, "index.html")
+			return
+		}
+		http.StripPrefix("/ui", files).ServeHTTP(w, r)
+	})
+}
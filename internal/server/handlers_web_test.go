@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebUIHandler(t *testing.T) {
+	web := webUIHandler()
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		web.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Equal(t, webCSP, w.Header().Get("Content-Security-Policy"))
+	assert.Contains(t, w.Body.String(), `<script src="/ui/app.js"`)
+
+	w = get("/ui/app.js")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "javascript")
+	assert.Contains(t, w.Body.String(), "/run/stream")
+
+	assert.Equal(t, http.StatusOK, get("/ui/app.css").Code)
+	assert.Equal(t, http.StatusNotFound, get("/ui/missing.js").Code)
+}
+
+func TestWebUIEnabled(t *testing.T) {
+	t.Setenv("WEB_UI", "")
+	assert.True(t, webUIEnabled())
+	t.Setenv("WEB_UI", "false")
+	assert.False(t, webUIEnabled())
+}
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/webhooks"
+)
+
+const (
+	// webhookDeliveryInterval is how often due webhook deliveries are attempted
+	webhookDeliveryInterval = 5 * time.Second
+	// webhookDeliveryBatch is how many deliveries a replica claims at a time
+	webhookDeliveryBatch = 20
+	// defaultWebhookDeliveryLimit and maxWebhookDeliveryLimit bound the delivery log listing
+	defaultWebhookDeliveryLimit = 50
+	maxWebhookDeliveryLimit     = 200
+)
+
+// WebhookRequest is the request body for registering a webhook
+type WebhookRequest struct {
+	URL    string   `json:"url"`              // https endpoint that receives events
+	Secret string   `json:"secret,omitempty"` // Signing secret; generated when empty
+	Events []string `json:"events,omitempty"` // Events to send; every event when empty
+}
+
+// WebhookCreateResponse is a registered webhook with its signing secret, which is
+// returned only when the webhook is created
+type WebhookCreateResponse struct {
+	db.Webhook
+	Secret string `json:"secret"`
+}
+
+// newWebhookSecret returns a random signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// handleCreateWebhook registers a webhook for the user's run lifecycle events
+func (s *Server) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "webhooks")
+	if !ok {
+		return
+	}
+
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	input := &db.WebhookInput{UserID: userID, URL: req.URL, Secret: req.Secret, Events: req.Events}
+	if input.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, "Failed to generate secret")
+			return
+		}
+		input.Secret = secret
+	}
+	if err := db.ValidateWebhook(input); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.notifier.CheckWebhookURL(r.Context(), input.URL); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	hook, err := s.db.CreateWebhook(r.Context(), input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+
+	s.jsonResponse(w, http.StatusCreated, WebhookCreateResponse{Webhook: *hook, Secret: hook.Secret})
+}
+
+// handleListWebhooks returns the user's webhooks, without their secrets
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "webhooks")
+	if !ok {
+		return
+	}
+
+	hooks, err := s.db.ListWebhooks(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if hooks == nil {
+		hooks = []db.Webhook{}
+	}
+	s.jsonResponse(w, http.StatusOK, hooks)
+}
+
+// handleDeleteWebhook removes one of the user's webhooks and its delivery log
+func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "webhooks")
+	if !ok {
+		return
+	}
+
+	webhookID, err := uuid.Parse(r.PathValue("webhook_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	deleted, err := s.db.DeleteWebhook(r.Context(), webhookID, userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if !deleted {
+		s.errorResponse(w, http.StatusNotFound, "Webhook not found")
+		return
+	}
+
+	s.jsonResponse(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// handleListWebhookDeliveries returns a webhook's delivery log, newest first
+func (s *Server) handleListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := s.pathUserIsCaller(w, r, "webhooks")
+	if !ok {
+		return
+	}
+
+	webhookID, err := uuid.Parse(r.PathValue("webhook_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+	limit := defaultWebhookDeliveryLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > maxWebhookDeliveryLimit {
+			s.errorResponse(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxWebhookDeliveryLimit))
+			return
+		}
+		limit = n
+	}
+
+	hook, err := s.db.GetWebhook(r.Context(), webhookID, userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if hook == nil {
+		s.errorResponse(w, http.StatusNotFound, "Webhook not found")
+		return
+	}
+
+	deliveries, err := s.db.ListWebhookDeliveries(r.Context(), hook.ID, limit)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if deliveries == nil {
+		deliveries = []db.WebhookDelivery{}
+	}
+	s.jsonResponse(w, http.StatusOK, deliveries)
+}
+
+// runWebhookDispatcher queues webhook deliveries for run lifecycle events
+func (s *Server) runWebhookDispatcher(ctx context.Context) {
+	if s.events == nil {
+		return
+	}
+
+	sub := s.events.Subscribe(uuid.Nil)
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if _, err := webhooks.Enqueue(ctx, s.db, event); err != nil {
+				slog.WarnContext(ctx, "failed to queue webhook deliveries", "run_id", event.RunID, "event", event.Type, "error", err)
+			}
+		}
+	}
+}
+
+// runWebhookDeliveries periodically sends due webhook deliveries, including retries
+func (s *Server) runWebhookDeliveries(ctx context.Context) {
+	ticker := time.NewTicker(webhookDeliveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		// Keep going while full batches come back, so a backlog drains between ticks
+		for {
+			n, err := webhooks.Deliver(ctx, s.db, s.notifier, webhookDeliveryBatch)
+			if err != nil {
+				slog.WarnContext(ctx, "failed to deliver webhooks", "error", err)
+			}
+			if err != nil || n < webhookDeliveryBatch || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
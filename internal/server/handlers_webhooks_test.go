@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestHandleWebhooks(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	target := "/v1/users/" + user.String() + "/webhooks"
+
+	w := serveAuthed(t, s, "POST /v1/users/{id}/webhooks", s.handleCreateWebhook,
+		bearerRequest(t, s, http.MethodPost, target, user,
+			[]byte(`{"url":"https://hooks.example.com/runs","events":["run.failed","run.completed"]}`)))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created WebhookCreateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Len(t, created.Secret, 64, "a secret is generated when none is given")
+	assert.Equal(t, []string{db.WebhookRunCompleted, db.WebhookRunFailed}, created.Events)
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/webhooks", s.handleListWebhooks,
+		bearerRequest(t, s, http.MethodGet, target, user, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), created.Secret, "secrets are only shown on creation")
+	var hooks []db.Webhook
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &hooks))
+	require.Len(t, hooks, 1)
+	assert.Equal(t, created.ID, hooks[0].ID)
+
+	s.mock.webhookLog = []db.WebhookDelivery{{ID: uuid.New(), WebhookID: created.ID, Event: db.WebhookRunFailed, Status: db.WebhookDeliveryDelivered, Attempts: 1}}
+	deliveries := target + "/" + created.ID.String() + "/deliveries"
+	w = serveAuthed(t, s, "GET /v1/users/{id}/webhooks/{webhook_id}/deliveries", s.handleListWebhookDeliveries,
+		bearerRequest(t, s, http.MethodGet, deliveries, user, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var logged []db.WebhookDelivery
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &logged))
+	assert.Len(t, logged, 1)
+
+	w = serveAuthed(t, s, "GET /v1/users/{id}/webhooks/{webhook_id}/deliveries", s.handleListWebhookDeliveries,
+		bearerRequest(t, s, http.MethodGet, deliveries+"?limit=500", user, nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Another user cannot see or remove the webhook
+	w = serveAuthed(t, s, "GET /v1/users/{id}/webhooks", s.handleListWebhooks,
+		bearerRequest(t, s, http.MethodGet, target, uuid.New(), nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = serveAuthed(t, s, "DELETE /v1/users/{id}/webhooks/{webhook_id}", s.handleDeleteWebhook,
+		bearerRequest(t, s, http.MethodDelete, target+"/"+created.ID.String(), user, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serveAuthed(t, s, "DELETE /v1/users/{id}/webhooks/{webhook_id}", s.handleDeleteWebhook,
+		bearerRequest(t, s, http.MethodDelete, target+"/"+created.ID.String(), user, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleCreateWebhook_Invalid(t *testing.T) {
+	user := uuid.New()
+	s := newNotificationTestServer(t)
+	target := "/v1/users/" + user.String() + "/webhooks"
+
+	for _, body := range []string{
+		`{"url":"http://hooks.example.com/runs"}`,
+		`{"url":"https://hooks.example.com/runs","events":["run.deleted"]}`,
+		`{}`,
+		`not json`,
+	} {
+		w := serveAuthed(t, s, "POST /v1/users/{id}/webhooks", s.handleCreateWebhook,
+			bearerRequest(t, s, http.MethodPost, target, user, []byte(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
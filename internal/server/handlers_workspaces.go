@@ -0,0 +1,335 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rulepacks"
+	"github.com/jonathan/resume-customizer/internal/server/middleware"
+)
+
+const (
+	// maxWorkspaceNameLength caps workspace and rule pack names
+	maxWorkspaceNameLength = 100
+	// defaultRulePackAuditLimit and maxRulePackAuditLimit bound the audit listing
+	defaultRulePackAuditLimit = 50
+	maxRulePackAuditLimit     = 500
+)
+
+// WorkspaceRequest is the request body for creating a workspace
+type WorkspaceRequest struct {
+	Name string `json:"name"`
+}
+
+// WorkspaceMemberRequest is the request body for adding a member or changing their role
+type WorkspaceMemberRequest struct {
+	Role string `json:"role"`
+}
+
+// RulePackRequest is the request body for publishing a rule pack
+type RulePackRequest struct {
+	Name     string   `json:"name"`
+	Mode     string   `json:"mode,omitempty"` // append (default) or replace
+	Priority int      `json:"priority,omitempty"`
+	Rules    []string `json:"rules"`
+}
+
+// workspaceCaller resolves the {workspace_id} path value and the caller's membership.
+// Non-members get a 404 so workspace IDs are not confirmed to outsiders; members get a
+// 403 when adminOnly is set and they are not an admin.
+func (s *Server) workspaceCaller(w http.ResponseWriter, r *http.Request, adminOnly bool) (*db.WorkspaceMember, bool) {
+	workspaceID, err := uuid.Parse(r.PathValue("workspace_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid workspace ID")
+		return nil, false
+	}
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return nil, false
+	}
+	member, err := s.db.GetWorkspaceMember(r.Context(), workspaceID, callerID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return nil, false
+	}
+	if member == nil {
+		s.errorResponse(w, http.StatusNotFound, "Workspace not found")
+		return nil, false
+	}
+	if adminOnly && member.Role != db.WorkspaceRoleAdmin {
+		s.errorResponse(w, http.StatusForbidden, "Only workspace admins can do that")
+		return nil, false
+	}
+	return member, true
+}
+
+// handleCreateWorkspace creates a workspace with the caller as its admin
+func (s *Server) handleCreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	var req WorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxWorkspaceNameLength {
+		s.errorResponse(w, http.StatusBadRequest, "name is required and must be at most "+strconv.Itoa(maxWorkspaceNameLength)+" characters")
+		return
+	}
+
+	workspace, err := s.db.CreateWorkspace(r.Context(), name, callerID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusCreated, workspace)
+}
+
+// handleListWorkspaces lists the workspaces the caller belongs to
+func (s *Server) handleListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	callerID, err := middleware.GetUserID(r)
+	if err != nil {
+		s.errorResponse(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	workspaces, err := s.db.ListWorkspacesForUser(r.Context(), callerID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, workspaces)
+}
+
+// handleListWorkspaceMembers lists a workspace's members (admins only)
+func (s *Server) handleListWorkspaceMembers(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.workspaceCaller(w, r, true)
+	if !ok {
+		return
+	}
+	members, err := s.db.ListWorkspaceMembers(r.Context(), caller.WorkspaceID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, members)
+}
+
+// handleSetWorkspaceMember adds a user to a workspace or changes their role (admins only)
+func (s *Server) handleSetWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.workspaceCaller(w, r, true)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(r.PathValue("user_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	var req WorkspaceMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Role == "" {
+		req.Role = db.WorkspaceRoleMember
+	}
+	if req.Role != db.WorkspaceRoleAdmin && req.Role != db.WorkspaceRoleMember {
+		s.errorResponse(w, http.StatusBadRequest, `role must be "admin" or "member"`)
+		return
+	}
+	user, err := s.db.GetUser(r.Context(), userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if user == nil {
+		s.errorResponse(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if req.Role == db.WorkspaceRoleMember {
+		if ok := s.keepsAnAdmin(w, r, caller.WorkspaceID, userID); !ok {
+			return
+		}
+	}
+
+	member, err := s.db.SetWorkspaceMember(r.Context(), caller.WorkspaceID, userID, req.Role)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, member)
+}
+
+// handleRemoveWorkspaceMember removes a user from a workspace. Admins can remove anyone;
+// members can only leave.
+func (s *Server) handleRemoveWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.workspaceCaller(w, r, false)
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(r.PathValue("user_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	if caller.Role != db.WorkspaceRoleAdmin && userID != caller.UserID {
+		s.errorResponse(w, http.StatusForbidden, "Only workspace admins can remove other members")
+		return
+	}
+	if ok := s.keepsAnAdmin(w, r, caller.WorkspaceID, userID); !ok {
+		return
+	}
+
+	member, err := s.db.GetWorkspaceMember(r.Context(), caller.WorkspaceID, userID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if member == nil {
+		s.errorResponse(w, http.StatusNotFound, "Workspace member not found")
+		return
+	}
+	if err := s.db.RemoveWorkspaceMember(r.Context(), caller.WorkspaceID, userID); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// keepsAnAdmin answers 409 and returns false when userID is the workspace's only admin,
+// so demoting or removing them would leave nobody able to manage its rule packs
+func (s *Server) keepsAnAdmin(w http.ResponseWriter, r *http.Request, workspaceID, userID uuid.UUID) bool {
+	members, err := s.db.ListWorkspaceMembers(r.Context(), workspaceID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return false
+	}
+	admins, target := 0, false
+	for _, m := range members {
+		if m.Role == db.WorkspaceRoleAdmin {
+			admins++
+			target = target || m.UserID == userID
+		}
+	}
+	if target && admins == 1 {
+		s.errorResponse(w, http.StatusConflict, "A workspace needs at least one admin; promote another member first")
+		return false
+	}
+	return true
+}
+
+// handleListRulePacks lists a workspace's rule packs in the order they apply (members)
+func (s *Server) handleListRulePacks(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.workspaceCaller(w, r, false)
+	if !ok {
+		return
+	}
+	packs, err := s.db.ListRulePacks(r.Context(), caller.WorkspaceID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, packs)
+}
+
+// handleSaveRulePack publishes a rule pack, or replaces the workspace's pack with the
+// same name (admins only). It applies to members' runs from their next company profile.
+func (s *Server) handleSaveRulePack(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.workspaceCaller(w, r, true)
+	if !ok {
+		return
+	}
+	var req RulePackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	input := &db.RulePackInput{
+		Name:     strings.TrimSpace(req.Name),
+		Mode:     req.Mode,
+		Priority: req.Priority,
+	}
+	if input.Mode == "" {
+		input.Mode = rulepacks.ModeAppend
+	}
+	for _, rule := range req.Rules {
+		input.Rules = append(input.Rules, strings.TrimSpace(rule))
+	}
+	if input.Name == "" || len(input.Name) > maxWorkspaceNameLength {
+		s.errorResponse(w, http.StatusBadRequest, "name is required and must be at most "+strconv.Itoa(maxWorkspaceNameLength)+" characters")
+		return
+	}
+	if err := rulepacks.Validate(input.Mode, input.Rules); err != nil {
+		s.errorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pack, err := s.db.UpsertRulePack(r.Context(), caller.WorkspaceID, caller.UserID, input)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, pack)
+}
+
+// handleDeleteRulePack deletes a workspace rule pack (admins only)
+func (s *Server) handleDeleteRulePack(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.workspaceCaller(w, r, true)
+	if !ok {
+		return
+	}
+	packID, err := uuid.Parse(r.PathValue("pack_id"))
+	if err != nil {
+		s.errorResponse(w, http.StatusBadRequest, "Invalid rule pack ID")
+		return
+	}
+
+	pack, err := s.db.GetRulePack(r.Context(), packID)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	if pack == nil || pack.WorkspaceID != caller.WorkspaceID {
+		s.errorResponse(w, http.StatusNotFound, "Rule pack not found")
+		return
+	}
+	if err := s.db.DeleteRulePack(r.Context(), packID); err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListRulePackApplications returns the audit of rule packs applied to members'
+// runs, newest first (admins only)
+func (s *Server) handleListRulePackApplications(w http.ResponseWriter, r *http.Request) {
+	caller, ok := s.workspaceCaller(w, r, true)
+	if !ok {
+		return
+	}
+	limit := defaultRulePackAuditLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > maxRulePackAuditLimit {
+			s.errorResponse(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxRulePackAuditLimit))
+			return
+		}
+		limit = n
+	}
+
+	applications, err := s.db.ListRulePackApplications(r.Context(), caller.WorkspaceID, limit)
+	if err != nil {
+		s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return
+	}
+	s.jsonResponse(w, http.StatusOK, applications)
+}
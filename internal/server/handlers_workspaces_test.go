@@ -0,0 +1,91 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rulepacks"
+)
+
+func TestHandleWorkspaceMembers(t *testing.T) {
+	admin, member, outsider := uuid.New(), uuid.New(), uuid.New()
+	s := newAuthTestServer(t)
+	s.mock.users = map[uuid.UUID]*db.User{admin: {ID: admin}, member: {ID: member}}
+
+	w := serveAuthed(t, s, "POST /v1/workspaces", s.handleCreateWorkspace,
+		bearerRequest(t, s, http.MethodPost, "/v1/workspaces", admin, []byte(`{"name":"Coaching Co"}`)))
+	require.Equal(t, http.StatusCreated, w.Code)
+	var ws db.Workspace
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ws))
+	assert.Equal(t, db.WorkspaceRoleAdmin, ws.Role)
+
+	base := "/v1/workspaces/" + ws.ID.String() + "/members/"
+	set := func(caller, user uuid.UUID, body string) int {
+		return serveAuthed(t, s, "PUT /v1/workspaces/{workspace_id}/members/{user_id}", s.handleSetWorkspaceMember,
+			bearerRequest(t, s, http.MethodPut, base+user.String(), caller, []byte(body))).Code
+	}
+	remove := func(caller, user uuid.UUID) int {
+		return serveAuthed(t, s, "DELETE /v1/workspaces/{workspace_id}/members/{user_id}", s.handleRemoveWorkspaceMember,
+			bearerRequest(t, s, http.MethodDelete, base+user.String(), caller, nil)).Code
+	}
+
+	assert.Equal(t, http.StatusNotFound, set(outsider, member, `{}`), "outsiders do not learn the workspace exists")
+	assert.Equal(t, http.StatusBadRequest, set(admin, member, `{"role":"owner"}`))
+	assert.Equal(t, http.StatusNotFound, set(admin, outsider, `{}`), "unknown users cannot be added")
+	require.Equal(t, http.StatusOK, set(admin, member, `{}`))
+	assert.Equal(t, http.StatusForbidden, set(member, member, `{"role":"admin"}`))
+	assert.Equal(t, http.StatusConflict, set(admin, admin, `{"role":"member"}`), "the last admin cannot step down")
+	assert.Equal(t, http.StatusConflict, remove(admin, admin))
+	assert.Equal(t, http.StatusForbidden, remove(member, admin))
+	assert.Equal(t, http.StatusNoContent, remove(member, member), "members can leave")
+	assert.Len(t, s.mock.members, 1)
+}
+
+func TestHandleSaveRulePack(t *testing.T) {
+	admin, member := uuid.New(), uuid.New()
+	s := newAuthTestServer(t)
+	workspaceID := uuid.New()
+	s.mock.members = []db.WorkspaceMember{
+		{WorkspaceID: workspaceID, UserID: admin, Role: db.WorkspaceRoleAdmin},
+		{WorkspaceID: workspaceID, UserID: member, Role: db.WorkspaceRoleMember},
+	}
+	target := "/v1/workspaces/" + workspaceID.String() + "/rule-packs"
+	save := func(caller uuid.UUID, body string) int {
+		return serveAuthed(t, s, "POST /v1/workspaces/{workspace_id}/rule-packs", s.handleSaveRulePack,
+			bearerRequest(t, s, http.MethodPost, target, caller, []byte(body))).Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, save(member, `{"name":"House style","rules":["No first person"]}`))
+	assert.Equal(t, http.StatusBadRequest, save(admin, `{"rules":["No first person"]}`))
+	assert.Equal(t, http.StatusBadRequest, save(admin, `{"name":"House style","rules":[]}`))
+	assert.Equal(t, http.StatusBadRequest, save(admin, `{"name":"House style","mode":"merge","rules":["x"]}`))
+	require.Equal(t, http.StatusOK, save(admin, `{"name":"House style","rules":[" No first person "]}`))
+	require.Equal(t, http.StatusOK, save(admin, `{"name":"house style","mode":"replace","priority":2,"rules":["Past tense"]}`))
+	require.Len(t, s.mock.rulePacks, 1, "saving under the same name replaces the pack")
+	assert.Equal(t, rulepacks.ModeReplace, s.mock.rulePacks[0].Mode)
+
+	w := serveAuthed(t, s, "GET /v1/workspaces/{workspace_id}/rule-packs", s.handleListRulePacks,
+		bearerRequest(t, s, http.MethodGet, target, member, nil))
+	require.Equal(t, http.StatusOK, w.Code, "members can read the packs their runs follow")
+	var packs []db.RulePack
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &packs))
+	require.Len(t, packs, 1)
+
+	del := func(caller uuid.UUID) int {
+		return serveAuthed(t, s, "DELETE /v1/workspaces/{workspace_id}/rule-packs/{pack_id}", s.handleDeleteRulePack,
+			bearerRequest(t, s, http.MethodDelete, target+"/"+packs[0].ID.String(), caller, nil)).Code
+	}
+	assert.Equal(t, http.StatusForbidden, del(member))
+	assert.Equal(t, http.StatusNoContent, del(admin))
+	assert.Empty(t, s.mock.rulePacks)
+
+	w = serveAuthed(t, s, "GET /v1/workspaces/{workspace_id}/rule-pack-applications", s.handleListRulePackApplications,
+		bearerRequest(t, s, http.MethodGet, "/v1/workspaces/"+workspaceID.String()+"/rule-pack-applications?limit=0", admin, nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
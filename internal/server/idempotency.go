@@ -0,0 +1,131 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+const (
+	// idempotencyKeyHeader names the header clients set to make a request safe to retry
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotencyReplayedHeader marks responses replayed from an earlier request
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength bounds keys to the idempotency_keys column
+	maxIdempotencyKeyLength = 255
+	// idempotencyKeyTTL is how long a key's response is replayed for repeats
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencyKeyStale is how long a request may hold a key before the key is
+	// considered abandoned, as when the server holding it died, and claimed again
+	idempotencyKeyStale = 5 * time.Minute
+)
+
+// idempotent makes requests sent with an Idempotency-Key header safe to retry. The first
+// request with a key runs; its response, if successful, is stored and returned for
+// repeats with the same key and body within idempotencyKeyTTL, so a retried POST /v1/runs
+// returns the original run instead of creating another. Keys belong to the user_id in
+// the JSON body; requests without a key or a readable user_id pass through.
+func (s *Server) idempotent(next http.Handler) http.Handler {
+	idempotent := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			s.errorResponse(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			s.errorResponse(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var owner struct {
+			UserID string `json:"user_id"`
+		}
+		if json.Unmarshal(body, &owner) != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		userID, err := uuid.Parse(owner.UserID)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(sum[:])
+		holder, err := s.db.ClaimIdempotencyKey(r.Context(), userID, key, requestHash, idempotencyKeyTTL, idempotencyKeyStale)
+		if err != nil {
+			s.errorResponse(w, http.StatusInternalServerError, "Database error: "+err.Error())
+			return
+		}
+		if holder != nil {
+			s.replayIdempotent(w, holder, requestHash)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		ctx := context.WithoutCancel(r.Context())
+		if rec.status >= 200 && rec.status < 300 && json.Valid(rec.body.Bytes()) {
+			err = s.db.CompleteIdempotencyKey(ctx, userID, key, rec.status, rec.body.Bytes())
+		} else {
+			err = s.db.ReleaseIdempotencyKey(ctx, userID, key)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to update idempotency key", "error", err)
+		}
+	})
+	return wrappedHandler{Handler: idempotent, next: next}
+}
+
+// replayIdempotent answers a repeat of a request whose key is held by holder: with the
+// stored response, or with an error when the bodies differ or the first request is
+// still running
+func (s *Server) replayIdempotent(w http.ResponseWriter, holder *db.IdempotencyKey, requestHash string) {
+	if holder.RequestHash != requestHash {
+		s.errorResponse(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request body")
+		return
+	}
+	if holder.StatusCode == nil {
+		s.errorResponse(w, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(idempotencyReplayedHeader, "true")
+	w.WriteHeader(*holder.StatusCode)
+	_, _ = w.Write(holder.Response)
+}
+
+// responseRecorder passes a response through while keeping its status and body
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
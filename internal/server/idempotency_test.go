@@ -0,0 +1,87 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/scheduler"
+)
+
+func postRunWithKey(s *testServer, key string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/v1/runs", bytes.NewReader(body))
+	if key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	s.idempotent(http.HandlerFunc(s.handleCreateRun)).ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotent_ReplaysRunCreation(t *testing.T) {
+	s := newTestServer()
+	s.scheduler = scheduler.New(4, 2, 0) // Room for every queued run
+	user := uuid.New()
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Ada", Email: "ada@example.com"}}
+	body, _ := json.Marshal(RunCreateRequest{UserID: user.String(), JobText: "Staff SRE at Acme", Execute: true})
+
+	first := postRunWithKey(s, "retry-1", body)
+	require.Equal(t, http.StatusAccepted, first.Code, first.Body.String())
+	require.Len(t, s.mock.runs, 1)
+
+	repeat := postRunWithKey(s, "retry-1", body)
+	require.Equal(t, http.StatusAccepted, repeat.Code, repeat.Body.String())
+	assert.Equal(t, "true", repeat.Header().Get(idempotencyReplayedHeader))
+	assert.JSONEq(t, first.Body.String(), repeat.Body.String())
+	assert.Len(t, s.mock.runs, 1, "a repeated key does not create another run")
+
+	other := postRunWithKey(s, "retry-2", body)
+	require.Equal(t, http.StatusAccepted, other.Code)
+	assert.Empty(t, other.Header().Get(idempotencyReplayedHeader))
+	assert.Len(t, s.mock.runs, 2)
+
+	postRunWithKey(s, "", body)
+	assert.Len(t, s.mock.runs, 3, "requests without a key always run")
+}
+
+func TestIdempotent_Conflicts(t *testing.T) {
+	s := newTestServer()
+	user := uuid.New()
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Ada", Email: "ada@example.com"}}
+	body, _ := json.Marshal(RunCreateRequest{UserID: user.String(), JobText: "Staff SRE at Acme", Execute: true})
+
+	require.Equal(t, http.StatusAccepted, postRunWithKey(s, "key", body).Code)
+
+	changed, _ := json.Marshal(RunCreateRequest{UserID: user.String(), JobText: "Platform engineer", Execute: true})
+	w := postRunWithKey(s, "key", changed)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Len(t, s.mock.runs, 1)
+
+	s.mock.idempotencyKeys[user.String()+":key"].StatusCode = nil
+	w = postRunWithKey(s, "key", body)
+	assert.Equal(t, http.StatusConflict, w.Code, "a key whose request is in progress is not run again")
+
+	w = postRunWithKey(s, string(make([]byte, maxIdempotencyKeyLength+1)), body)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestIdempotent_ReleasesFailedRequests(t *testing.T) {
+	s := newTestServer()
+	user := uuid.New()
+	body, _ := json.Marshal(RunCreateRequest{UserID: user.String(), JobText: "Staff SRE at Acme", Execute: true})
+
+	w := postRunWithKey(s, "key", body)
+	require.Equal(t, http.StatusNotFound, w.Code, "the user does not exist yet")
+	assert.Empty(t, s.mock.idempotencyKeys, "failed requests do not hold their key")
+
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Name: "Ada", Email: "ada@example.com"}}
+	w = postRunWithKey(s, "key", body)
+	assert.Equal(t, http.StatusAccepted, w.Code, "the key can be retried once the request can succeed")
+}
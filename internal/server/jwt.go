@@ -2,6 +2,9 @@
 package server
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
 	"time"
 
@@ -111,3 +114,46 @@ func (s *JWTService) ValidateToken(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// CalendarFeedToken returns the long-lived token that authorizes reading a user's
+// calendar feed. Calendar apps cannot send bearer tokens, so the feed URL carries it.
+// Rotating JWT_SECRET invalidates every feed URL.
+func (s *JWTService) CalendarFeedToken(userID uuid.UUID) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Secret))
+	mac.Write([]byte("calendar-feed:" + userID.String()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidCalendarFeedToken reports whether token authorizes reading userID's calendar feed.
+func (s *JWTService) ValidCalendarFeedToken(userID uuid.UUID, token string) bool {
+	return token != "" && hmac.Equal([]byte(token), []byte(s.CalendarFeedToken(userID)))
+}
+
+// VoiceNoteWebhookToken returns the long-lived token that authorizes pushing voice note
+// transcripts for a user. Dictation and transcription services are configured with a
+// fixed URL, so the webhook URL carries it. Rotating JWT_SECRET invalidates every
+// webhook URL.
+func (s *JWTService) VoiceNoteWebhookToken(userID uuid.UUID) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Secret))
+	mac.Write([]byte("voice-notes:" + userID.String()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidVoiceNoteWebhookToken reports whether token authorizes pushing transcripts for userID.
+func (s *JWTService) ValidVoiceNoteWebhookToken(userID uuid.UUID, token string) bool {
+	return token != "" && hmac.Equal([]byte(token), []byte(s.VoiceNoteWebhookToken(userID)))
+}
+
+// CSRFToken returns the token form posts must carry alongside sessionToken, the JWT a
+// browser holds in its session cookie. A cross-site page can make the browser send the
+// cookie but cannot read it, so it cannot derive the token.
+func (s *JWTService) CSRFToken(sessionToken string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Secret))
+	mac.Write([]byte("csrf:" + sessionToken))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidCSRFToken reports whether token was issued for sessionToken.
+func (s *JWTService) ValidCSRFToken(sessionToken, token string) bool {
+	return sessionToken != "" && token != "" && hmac.Equal([]byte(token), []byte(s.CSRFToken(sessionToken)))
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/logging"
 )
 
 // ContextKey is a typed key for context values to avoid collisions.
@@ -62,8 +64,9 @@ func AuthMiddleware(jwtService TokenValidator) func(http.Handler) http.Handler {
 			// Extract user ID from claims
 			userID := claims.GetUserID()
 
-			// Add user ID to request context
+			// Add user ID to request context, and to the log lines written with it
 			ctx := context.WithValue(r.Context(), userIDKey, userID)
+			ctx = logging.WithUserID(ctx, userID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/logging"
+)
+
+// RedactedValue stands in for request values a log policy does not allow
+const RedactedValue = "[REDACTED]"
+
+// LogPolicy is the allowlist of request fields a request log may record. Bodies are
+// never logged, and neither is the raw path: requests are logged by the route pattern
+// they matched ("/r/{slug}", not the share link's slug), so IDs, slugs, and keywords in
+// paths stay out of the log.
+type LogPolicy struct {
+	QueryParams map[string]bool // Parameters logged with their values; others are logged as [REDACTED]
+	Headers     []string        // Request headers logged, e.g. User-Agent; never Authorization or Cookie
+}
+
+// DefaultLogPolicy allows the paging, filtering, and format parameters routes take, none
+// of which carry credentials or personal data
+var DefaultLogPolicy = LogPolicy{
+	QueryParams: map[string]bool{
+		"limit": true, "offset": true, "days": true, "status": true, "format": true,
+		"category": true, "step": true, "run_id": true, "company_id": true, "platform": true,
+		"canonical": true, "include_html": true, "interval": true, "role_family": true, "view": true,
+	},
+	Headers: []string{"User-Agent"},
+}
+
+// neverLoggedHeaders carry credentials, so a policy listing them is ignored
+var neverLoggedHeaders = map[string]bool{
+	"Authorization": true, "Cookie": true, "Set-Cookie": true, "Proxy-Authorization": true, "X-Api-Key": true,
+}
+
+// RequestIDHeader carries a request's ID: a well-formed ID from a proxy is kept, and the
+// ID is returned on every response so clients can quote it when reporting a problem
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds request IDs taken from clients
+const maxRequestIDLength = 128
+
+// RequestID creates middleware that gives each request an ID, reusing the X-Request-ID
+// header when it holds only letters, digits, dots, dashes, and underscores, and
+// generating one otherwise. Log lines written with the request's context carry the ID.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// validRequestID reports whether an ID from a client is safe to log and echo back
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestLogger creates middleware that logs each request once it completes, recording
+// only what policy allows: method, route pattern, status, duration, remote address, and
+// the allowed query parameters and headers. Patterns are set by http.ServeMux, so the
+// middleware must wrap the mux; requests no route matched are logged without a path.
+func RequestLogger(logger *slog.Logger, policy LogPolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			logger.LogAttrs(r.Context(), slog.LevelInfo, "request", policy.attrs(r, rec.status, time.Since(start))...)
+		})
+	}
+}
+
+// attrs returns the log fields for a completed request
+func (p LogPolicy) attrs(r *http.Request, status int, elapsed time.Duration) []slog.Attr {
+	route := "(no route)"
+	if _, path, ok := strings.Cut(r.Pattern, " "); ok {
+		route = path
+	} else if r.Pattern != "" {
+		route = r.Pattern
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("route", route),
+		slog.Int("status", status),
+		slog.Int64("duration_ms", elapsed.Milliseconds()),
+		slog.String("remote", r.RemoteAddr),
+	}
+	if query := p.ScrubQuery(r.URL.Query()); query != "" {
+		attrs = append(attrs, slog.String("query", query))
+	}
+	for _, name := range p.Headers {
+		name = http.CanonicalHeaderKey(name)
+		if value := r.Header.Get(name); value != "" && !neverLoggedHeaders[name] {
+			attrs = append(attrs, slog.String(strings.ToLower(name), value))
+		}
+	}
+	return attrs
+}
+
+// ScrubQuery encodes query for a log, replacing the values of parameters the policy does
+// not allow with [REDACTED]
+func (p LogPolicy) ScrubQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			if p.QueryParams[key] {
+				value = url.QueryEscape(value)
+			} else {
+				value = RedactedValue
+			}
+			parts = append(parts, url.QueryEscape(key)+"="+value)
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// statusRecorder records the status a handler writes. It forwards Flush so server-sent
+// event streams keep working behind the logger.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush sends buffered data to the client
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,343 @@
+package server
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/apidoc"
+	"github.com/jonathan/resume-customizer/internal/buildinfo"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/fetch"
+	"github.com/jonathan/resume-customizer/internal/ranking"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// docsFiles is the API documentation page, which loads Swagger UI and points it at
+// /v1/openapi.json
+//
+//go:embed docs
+var docsFiles embed.FS
+
+// swaggerUIOrigin serves the pinned Swagger UI release the documentation page loads
+const swaggerUIOrigin = "https://cdn.jsdelivr.net"
+
+// docsCSP confines the documentation page to its own script, Swagger UI, and the API.
+// Swagger UI sets inline styles, so those are allowed.
+const docsCSP = "default-src 'self'; script-src 'self' " + swaggerUIOrigin + "; style-src 'self' 'unsafe-inline' " + swaggerUIOrigin +
+	"; img-src 'self' data:; object-src 'none'; base-uri 'none'; frame-ancestors 'none'"
+
+// undocumentedPrefixes are the browser pages left out of the API document
+var undocumentedPrefixes = []string{"/ui/", "/forms/", "/docs"}
+
+// routeMux is the server's ServeMux, recording each route it serves so the API document
+// is generated from the route table itself
+type routeMux struct {
+	*http.ServeMux
+	routes []muxRoute
+}
+
+type muxRoute struct {
+	pattern string
+	handler http.Handler
+}
+
+func newRouteMux() *routeMux {
+	return &routeMux{ServeMux: http.NewServeMux()}
+}
+
+// Handle registers handler for pattern
+func (m *routeMux) Handle(pattern string, handler http.Handler) {
+	m.routes = append(m.routes, muxRoute{pattern: pattern, handler: handler})
+	m.ServeMux.Handle(pattern, handler)
+}
+
+// HandleFunc registers handler for pattern
+func (m *routeMux) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	m.Handle(pattern, http.HandlerFunc(handler))
+}
+
+// wrappedHandler is a handler behind middleware. It keeps the handler it wraps so the
+// route table can still name it, and records whether the middleware authenticates.
+type wrappedHandler struct {
+	http.Handler
+	next http.Handler
+	auth bool
+}
+
+// pageParams are the query parameters of every paginated list
+var pageParams = []apidoc.Param{
+	{Name: "limit", Description: "Page size"},
+	{Name: "cursor", Description: "next_cursor of the previous page"},
+	{Name: "sort", Description: "Sort key"},
+	{Name: "order", Description: "asc or desc"},
+}
+
+// apiOperations describes the bodies and query parameters of routes; routes missing
+// here are still documented, with a summary taken from their handler's name
+var apiOperations = map[string]apidoc.Operation{
+	"GET /health": {Tags: []string{"health"}},
+
+	"POST /run":        {Summary: "Run the pipeline", Request: RunRequest{}, Response: RunResponse{}, Status: http.StatusAccepted},
+	"POST /run/stream": {Summary: "Run the pipeline, streaming progress", Request: RunRequest{}, ContentType: "text/event-stream"},
+	"GET /status/{id}": {Response: StatusResponse{}},
+
+	"POST /v1/auth/register":            {Request: types.CreateUserRequest{}, Response: types.LoginResponse{}, Status: http.StatusCreated},
+	"POST /v1/auth/login":               {Request: types.LoginRequest{}, Response: types.LoginResponse{}},
+	"POST /v1/auth/refresh":             {Request: types.RefreshTokenRequest{}, Response: types.RefreshTokenResponse{}},
+	"POST /v1/auth/logout":              {Request: types.RefreshTokenRequest{}, Status: http.StatusNoContent},
+	"GET /v1/auth/verify-email":         {Query: []apidoc.Param{{Name: "token", Description: "Token from the verification link", Required: true}}},
+	"PUT /v1/users/{id}/password":       {Request: types.UpdatePasswordRequest{}},
+	"GET /v1/openapi.json":              {Summary: "Get this API document", Tags: []string{"docs"}},
+	"POST /v1/auth/verify-email/resend": {Status: http.StatusAccepted},
+
+	"POST /v1/runs": {Request: RunCreateRequest{}, Response: RunCreateResponse{}, Status: http.StatusCreated},
+	"GET /v1/runs": {Query: append([]apidoc.Param{
+		{Name: "company", Description: "Only runs for this company"},
+		{Name: "status", Description: "Only runs with this status"},
+	}, pageParams...)},
+	"GET /v1/users/{id}/runs": {Query: append([]apidoc.Param{
+		{Name: "company", Description: "Only runs for this company"},
+		{Name: "status", Description: "Only runs with this status"},
+	}, pageParams...)},
+	"GET /v1/runs/{id}":                             {Response: RunGetResponse{}},
+	"GET /v1/status/{id}":                           {Response: RunStatusResponse{}},
+	"POST /v1/runs/{run_id}/steps/{step_name}":      {Request: StepExecuteRequest{}, Response: StepExecuteResponse{}},
+	"GET /v1/runs/{run_id}/steps":                   {Response: RunStepsListResponse{}},
+	"GET /v1/runs/{run_id}/steps/{step_name}":       {Response: StepStatusResponse{}},
+	"GET /v1/runs/{run_id}/checkpoint":              {Response: CheckpointGetResponse{}},
+	"POST /v1/runs/{run_id}/resume":                 {Request: ResumeRequest{}, Response: ResumeResponse{}},
+	"POST /v1/runs/{run_id}/steps/{step_name}/skip": {Response: StepStatusResponse{}},
+	"GET /v1/runs/{id}/resume.tex":                  {ContentType: "application/x-tex"},
+	"GET /v1/runs/{id}/artifacts/pdf":               {ContentType: "application/pdf"},
+	"GET /v1/artifact/{id}/content":                 {Description: "Streams the artifact's raw bytes; supports Range requests.", ContentType: "application/octet-stream"},
+	"GET /v1/runs/{id}/download":                    {ContentType: "application/zip"},
+	"GET /v1/runs/{id}/preview":                     {Response: RunPreviewResponse{}},
+	"GET /v1/runs/{id}/thumbnail.png":               {ContentType: "image/png"},
+	"GET /v1/runs/{id}/timeline":                    {Response: RunTimelineResponse{}},
+	"GET /v1/runs/{id}/events":                      {ContentType: "text/event-stream"},
+	"PUT /v1/runs/{id}/dates":                       {Request: RunDatesRequest{}, Response: db.Run{}},
+	"GET /v1/admin/run-gc":                          {Response: RunGCStatsResponse{}},
+	"GET /v1/admin/fetch-stats":                     {Response: fetch.SizeStats{}},
+	"GET /v1/admin/slo":                             {Response: SLOReport{}, Query: []apidoc.Param{{Name: "days", Description: "Days covered (default 7, at most 90)"}, {Name: "bucket", Description: "hour, day (default), or week"}}},
+	"GET /v1/admin/dead-letters":                    {Response: []db.DeadLetter{}},
+	"GET /v1/admin/dead-letters/stats":              {Response: DeadLetterStatsResponse{}},
+
+	"GET /v1/users/{id}":                                            {Response: db.User{}},
+	"PUT /v1/users/{id}":                                            {Request: db.User{}},
+	"PUT /v1/users/{id}/privacy":                                    {Request: PrivacySettingsRequest{}},
+	"PUT /v1/users/{id}/timezone":                                   {Request: TimezoneRequest{}, Response: TimezoneRequest{}},
+	"GET /v1/users/{id}/ranking-config":                             {Response: RankingConfigResponse{}},
+	"PUT /v1/users/{id}/ranking-config":                             {Request: ranking.Weights{}, Response: RankingConfigResponse{}},
+	"GET /v1/users/{id}/company-presets":                            {Response: CompanyPresetListResponse{}},
+	"POST /v1/users/{id}/company-presets":                           {Request: CompanyPresetRequest{}, Response: db.CompanyPreference{}},
+	"GET /v1/users/{id}/recipes":                                    {Response: RunRecipeListResponse{}},
+	"POST /v1/users/{id}/recipes":                                   {Request: RunRecipeRequest{}, Response: db.RunRecipe{}},
+	"GET /v1/users/{id}/domain-policies":                            {Response: DomainPolicyListResponse{}},
+	"POST /v1/users/{id}/domain-policies":                           {Request: DomainPolicyRequest{}, Response: db.DomainPolicy{}, Status: http.StatusCreated},
+	"GET /v1/users/{id}/notification-preferences":                   {Response: NotificationPreferencesResponse{}},
+	"PUT /v1/users/{id}/notification-preferences":                   {Request: NotificationPreferenceRequest{}, Response: db.NotificationPreference{}},
+	"GET /v1/users/{id}/webhooks":                                   {Response: []db.Webhook{}},
+	"POST /v1/users/{id}/webhooks":                                  {Request: WebhookRequest{}, Response: WebhookCreateResponse{}, Status: http.StatusCreated},
+	"GET /v1/users/{id}/webhooks/{webhook_id}/deliveries":           {Response: []db.WebhookDelivery{}, Query: []apidoc.Param{{Name: "limit", Description: "Deliveries returned, newest first (default 50, at most 200)"}}},
+	"GET /v1/users/{id}/calendar":                                   {Response: CalendarFeedResponse{}},
+	"GET /v1/users/{id}/calendar.ics":                               {ContentType: "text/calendar"},
+	"PUT /v1/users/{id}/shared-resume":                              {Request: SharedResumeRequest{}, Response: SharedResumeResponse{}},
+	"GET /v1/users/{id}/shared-resume":                              {Response: SharedResumeResponse{}},
+	"PUT /v1/users/{id}/shared-resume/access":                       {Request: SharedResumeAccessRequest{}, Response: SharedResumeResponse{}},
+	"GET /v1/users/{id}/shared-resume/qr.png":                       {ContentType: "image/png"},
+	"GET /v1/users/{id}/github":                                     {Response: db.GitHubAccount{}},
+	"PUT /v1/users/{id}/github":                                     {Request: GitHubAccountRequest{}, Response: db.GitHubAccount{}},
+	"POST /v1/users/{id}/github/sync":                               {Response: GitHubSyncResponse{}},
+	"GET /v1/users/{id}/project-drafts":                             {Response: ProjectDraftsResponse{}},
+	"POST /v1/users/{id}/project-drafts/{draft_id}/accept":          {Request: AcceptProjectDraftRequest{}, Response: db.ProjectDraft{}},
+	"GET /v1/users/{id}/bullet-suggestions":                         {Response: BulletSuggestionsResponse{}},
+	"POST /v1/users/{id}/bullet-suggestions/{suggestion_id}/accept": {Request: AcceptBulletSuggestionRequest{}, Response: db.BulletSuggestion{}},
+	"GET /v1/users/{id}/onboarding":                                 {Response: OnboardingResponse{}},
+	"POST /v1/users/{id}/onboarding/advance":                        {Request: OnboardingTransitionRequest{}, Response: OnboardingResponse{}},
+	"POST /v1/users/{id}/onboarding/skip":                           {Request: OnboardingTransitionRequest{}, Response: OnboardingResponse{}},
+	"POST /v1/users/{id}/onboarding/back":                           {Request: OnboardingTransitionRequest{}, Response: OnboardingResponse{}},
+	"GET /v1/users/{id}/skill-assessments":                          {Response: SkillAssessmentsResponse{}},
+	"PUT /v1/users/{id}/skill-assessments":                          {Request: SkillAssessmentRequest{}, Response: db.UserSkill{}},
+	"GET /v1/users/{id}/skill-gap":                                  {Response: db.SkillGap{}, Query: []apidoc.Param{{Name: "posting_id", Description: "Job posting to compare against"}}},
+	"POST /v1/users/{id}/jobs":                                      {Request: db.Job{}, Status: http.StatusCreated},
+	"PUT /v1/jobs/{id}":                                             {Request: db.Job{}},
+	"POST /v1/jobs/{id}/experiences":                                {Request: db.Experience{}, Status: http.StatusCreated},
+	"PUT /v1/experiences/{id}":                                      {Request: db.Experience{}},
+	"POST /v1/users/{id}/education":                                 {Request: db.Education{}, Status: http.StatusCreated},
+	"PUT /v1/education/{id}":                                        {Request: db.Education{}},
+	"GET /v1/users/{id}/experience-bank":                            {Response: types.ExperienceBank{}},
+	"GET /v1/users/{id}/experience-bank/stories/{story_id}":         {Response: db.Story{}},
+	"POST /v1/users/{id}/experience-bank/stories":                   {Request: CreateStoryRequest{}, Response: db.Story{}, Status: http.StatusCreated},
+	"POST /v1/users/{id}/experience-bank/star":                      {Request: StarInterviewRequest{}, Response: StarInterviewResponse{}},
+	"POST /v1/users/{id}/experience-bank/draft":                     {Response: db.ExperienceBankImportInput{}},
+	"POST /v1/users/{id}/experience-bank/import":                    {Request: db.ExperienceBankImportInput{}, Response: ExperienceBankImportResponse{}},
+	"POST /v1/users/{id}/experience/import": {
+		Response: ImportExperienceResponse{},
+		Query:    []apidoc.Param{{Name: "format", Description: "Document format; jsonresume"}},
+	},
+	"GET /v1/users/{id}/experience/export":    {Query: []apidoc.Param{{Name: "format", Description: "Document format; jsonresume"}}},
+	"GET /v1/users/{id}/export":               {Response: db.UserExport{}},
+	"GET /v1/users/{id}/voice-notes":          {Response: []db.VoiceNote{}},
+	"GET /v1/users/{id}/voice-notes/webhook":  {Response: VoiceNoteWebhookResponse{}},
+	"POST /v1/users/{id}/voice-notes/webhook": {Request: VoiceNoteWebhookRequest{}, Response: db.VoiceNote{}, Status: http.StatusCreated},
+
+	"POST /v1/workspaces":                                 {Request: WorkspaceRequest{}, Response: db.Workspace{}, Status: http.StatusCreated},
+	"GET /v1/workspaces":                                  {Response: []db.Workspace{}},
+	"GET /v1/workspaces/{workspace_id}/members":           {Response: []db.WorkspaceMember{}},
+	"PUT /v1/workspaces/{workspace_id}/members/{user_id}": {Request: WorkspaceMemberRequest{}, Response: db.WorkspaceMember{}},
+	"GET /v1/workspaces/{workspace_id}/rule-packs":        {Response: []db.RulePack{}},
+	"POST /v1/workspaces/{workspace_id}/rule-packs":       {Request: RulePackRequest{}, Response: db.RulePack{}},
+
+	"GET /v1/companies/by-name":              {Response: db.Company{}, Query: []apidoc.Param{{Name: "name", Required: true}}},
+	"GET /v1/companies/{id}":                 {Response: db.Company{}},
+	"GET /v1/companies/{company_id}/profile": {Response: db.CompanyProfile{}},
+	"POST /v1/companies/{company_id}/profile": {Request: ManualCompanyProfileRequest{}, Response: db.CompanyProfile{},
+		Status: http.StatusCreated},
+	"GET /v1/job-postings": {Response: ListJobPostingsResponse{}, Query: append([]apidoc.Param{
+		{Name: "platform"}, {Name: "company_id"}, {Name: "canonical", Description: "true for canonical postings only"},
+	}, pageParams...)},
+	"GET /v1/companies":                          {Query: pageParams},
+	"GET /v1/users/{id}/experience-bank/stories": {Query: pageParams},
+	"GET /v1/users/{id}/experience-bank/skills":  {Query: pageParams},
+	"GET /v1/job-postings/{id}":                  {Response: db.JobPosting{}},
+	"GET /v1/job-postings/by-url":                {Response: db.JobPosting{}, Query: []apidoc.Param{{Name: "url", Required: true}}},
+	"GET /v1/job-postings/{id}/duplicates":       {Response: DuplicatePostingsResponse{}},
+	"GET /v1/job-postings/{id}/versions":         {Response: PostingVersionsResponse{}},
+	"GET /v1/companies/{company_id}/posting-trends": {Response: PostingTrendsResponse{}, Query: []apidoc.Param{
+		{Name: "role_family"}, {Name: "role"}, {Name: "interval", Description: "month or quarter"}, {Name: "limit"},
+	}},
+	"GET /v1/job-profiles/{id}":                 {Response: db.JobProfile{}},
+	"GET /v1/job-postings/{posting_id}/profile": {Response: db.JobProfile{}},
+	"POST /v1/job-profiles/{id}/keywords":       {Request: KeywordRequest{}},
+	"PATCH /v1/job-requirements/{id}":           {Request: RequirementWeightRequest{}, Response: db.JobRequirement{}},
+	"GET /v1/crawled-pages/{id}":                {Response: CrawledPageResponse{}},
+	"GET /v1/crawled-pages/by-url":              {Response: CrawledPageResponse{}, Query: []apidoc.Param{{Name: "url", Required: true}}},
+	"GET /v1/domain-policies":                   {Summary: "List global domain policies", Response: DomainPolicyListResponse{}},
+	"POST /v1/domain-policies":                  {Summary: "Create a global domain policy", Request: DomainPolicyRequest{}, Response: db.DomainPolicy{}, Status: http.StatusCreated},
+	"DELETE /v1/domain-policies/{policy_id}":    {Summary: "Delete a global domain policy"},
+	"GET /v1/templates":                         {Response: TemplateListResponse{}},
+	"POST /v1/templates":                        {Request: TemplateUploadRequest{}, Response: TemplateUploadResponse{}},
+	"POST /v1/templates/lint":                   {Request: TemplateLintRequest{}, Response: TemplateLintResponse{}},
+
+	"GET /v1/users/{id}/applications":                                    {Response: ApplicationTrackerListResponse{}},
+	"POST /v1/users/{id}/applications":                                   {Request: ApplicationTrackerRequest{}, Response: db.ApplicationTracker{}},
+	"GET /v1/users/{id}/applications/{tracker_id}":                       {Response: ApplicationTrackerResponse{}},
+	"POST /v1/users/{id}/applications/{tracker_id}/contacts":             {Request: ApplicationContactRequest{}, Response: db.ApplicationContact{}, Status: http.StatusCreated},
+	"PUT /v1/users/{id}/applications/{tracker_id}/contacts/{contact_id}": {Request: ApplicationContactRequest{}, Response: db.ApplicationContact{}},
+	"POST /v1/users/{id}/applications/{tracker_id}/interactions":         {Request: ApplicationInteractionRequest{}, Response: db.ApplicationInteraction{}, Status: http.StatusCreated},
+
+	"GET /v1/runs/{run_id}/artifacts/{step}/diff": {Response: ArtifactDiffResponse{}, Query: []apidoc.Param{
+		{Name: "version", Description: "Version to compare (default 1, the step's output)"},
+		{Name: "against", Description: "Version to compare it with: final (default) or a version number"},
+	}},
+	"GET /v1/runs/{run_id}/artifacts/{step}/versions":           {Response: ArtifactVersionListResponse{}},
+	"GET /v1/runs/{run_id}/artifacts/{step}/versions/{version}": {Response: db.Artifact{}},
+
+	"GET /v1/users/{id}/resume-health": {Response: db.ResumeHealth{}},
+
+	"POST /v1/tools/summarize-posting": {Request: SummarizePostingRequest{}, Response: types.JobProfile{}},
+	"POST /v1/tools/rewrite-bullet":    {Request: RewriteBulletRequest{}, Response: RewriteBulletResponse{}},
+
+	"GET /r/{slug}":               {Summary: "Shared resume page", ContentType: "text/html"},
+	"GET /r/{slug}/resume.pdf":    {ContentType: "application/pdf"},
+	"GET /r/{slug}/thumbnail.png": {ContentType: "image/png"},
+	"POST /r/{slug}/comments":     {Request: SharedResumeCommentRequest{}, Status: http.StatusCreated},
+}
+
+// buildAPIDocument generates the OpenAPI document for the routes mux serves
+func buildAPIDocument(routes []muxRoute) ([]byte, error) {
+	g := apidoc.NewGenerator(apidoc.Info{
+		Title:       "Resume Customizer API",
+		Version:     buildinfo.Read().Version,
+		Description: "Generated from the server's route table. Send the token from /v1/auth/login as a bearer token.",
+	})
+	g.Define(reflect.TypeFor[db.Date](), apidoc.Schema{Type: "string", Format: "date"})
+
+	var documented []apidoc.Route
+	ids := map[string]bool{}
+	for _, route := range routes {
+		_, path, ok := apidoc.ParsePattern(route.pattern)
+		if !ok || path == "/" || hasAnyPrefix(path, undocumentedPrefixes) {
+			continue
+		}
+		r := apidoc.Route{Pattern: route.pattern, Operation: apiOperations[route.pattern]}
+		handler := route.handler
+		for {
+			wrapped, ok := handler.(wrappedHandler)
+			if !ok {
+				break
+			}
+			r.Auth = r.Auth || wrapped.auth
+			handler = wrapped.next
+		}
+		// A handler serving several routes names only the first; the others are named
+		// after their paths
+		if id := handlerOperationID(handler); id != "" && !ids[id] {
+			r.OperationID = id
+			ids[id] = true
+		}
+		documented = append(documented, r)
+	}
+
+	doc, err := g.Generate(documented)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API document: %w", err)
+	}
+	return json.Marshal(doc)
+}
+
+// handlerOperationID names an operation after its Server method: handleListRunSteps
+// becomes "listRunSteps". It returns "" for handlers that are not methods.
+func handlerOperationID(h http.Handler) string {
+	f, ok := h.(http.HandlerFunc)
+	if !ok {
+		return ""
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	name = strings.TrimSuffix(name[strings.LastIndex(name, ".")+1:], "-fm")
+	name, ok = strings.CutPrefix(name, "handle")
+	if !ok || name == "" {
+		return ""
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleOpenAPI serves the API document generated from the route table at startup
+func (s *Server) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(s.apiDocument)
+}
+
+// apiDocsHandler serves the documentation page at /docs and its script under /docs/
+func apiDocsHandler() http.Handler {
+	assets, err := fs.Sub(docsFiles, "docs")
+	if err != nil {
+		panic(err) // The embedded directory is fixed at build time
+	}
+	files := http.FileServerFS(assets)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", docsCSP)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if r.URL.Path == "/docs" {
+			http.ServeFileFS(w, r, assets, "index.html")
+			return
+		}
+		http.StripPrefix("/docs", files).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/apidoc"
+)
+
+func TestBuildAPIDocument(t *testing.T) {
+	s := newAuthTestServer(t)
+	mux := s.routes()
+	registered := make(map[string]bool, len(mux.routes))
+	for _, route := range mux.routes {
+		registered[route.pattern] = true
+	}
+	for pattern := range apiOperations {
+		assert.True(t, registered[pattern], "apiOperations describes %q, which is not a route", pattern)
+	}
+
+	data, err := buildAPIDocument(mux.routes)
+	require.NoError(t, err)
+	var doc apidoc.Document
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, apidoc.Version, doc.OpenAPI)
+
+	steps := doc.Paths["/v1/runs/{run_id}/steps"]["get"]
+	require.NotNil(t, steps)
+	assert.Equal(t, "listRunSteps", steps.OperationID)
+	assert.Equal(t, "List run steps", steps.Summary)
+	assert.Equal(t, "#/components/schemas/RunStepsListResponse", steps.Responses["200"].Content["application/json"].Schema.Ref)
+
+	create := doc.Paths["/v1/runs"]["post"]
+	require.NotNil(t, create)
+	assert.Equal(t, "createRun", create.OperationID, "named through the email verification middleware")
+	assert.Equal(t, "#/components/schemas/RunCreateRequest", create.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Contains(t, create.Responses, "201")
+	assert.Contains(t, doc.Components.Schemas, "RunCreateRequest")
+
+	notes := doc.Paths["/v1/users/{id}/voice-notes"]["get"]
+	require.NotNil(t, notes)
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, notes.Security)
+	assert.Nil(t, doc.Paths["/v1/job-postings"]["get"].Security)
+
+	assert.Equal(t, "listDomainPolicies", doc.Paths["/v1/users/{id}/domain-policies"]["get"].OperationID)
+	assert.Equal(t, "getDomainPolicies", doc.Paths["/v1/domain-policies"]["get"].OperationID,
+		"a handler's second route is named after its path")
+
+	assert.Contains(t, doc.Paths, "/v1/openapi.json")
+	assert.NotContains(t, doc.Paths, "/docs")
+	assert.NotContains(t, doc.Paths, "/forms/login")
+	assert.NotContains(t, doc.Paths, "/")
+}
+
+func TestHandleOpenAPI(t *testing.T) {
+	s := newAuthTestServer(t)
+	mux := s.routes()
+	var err error
+	s.apiDocument, err = buildAPIDocument(mux.routes)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "3.1.0", doc["openapi"])
+}
+
+func TestAPIDocsPage(t *testing.T) {
+	s := newAuthTestServer(t)
+	mux := s.routes()
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `id="swagger-ui"`)
+	assert.Equal(t, docsCSP, w.Header().Get("Content-Security-Policy"))
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/docs.js", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/v1/openapi.json")
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// listSpec describes how a list endpoint pages: the sorts it offers and its defaults.
+// Every paginated list takes the same query parameters: limit, cursor (the next_cursor
+// of the previous page), sort, and order (asc or desc).
+type listSpec struct {
+	sorts        db.SortKeys
+	defaultSort  string
+	defaultDesc  bool
+	defaultLimit int
+	maxLimit     int
+}
+
+// Specs of the paginated list endpoints
+var (
+	runListSpec        = listSpec{sorts: db.RunSortKeys, defaultSort: "created_at", defaultDesc: true, defaultLimit: 50, maxLimit: 500}
+	storyListSpec      = listSpec{sorts: db.StorySortKeys, defaultSort: "created_at", defaultDesc: true, defaultLimit: 50, maxLimit: 100}
+	skillListSpec      = listSpec{sorts: db.SkillSortKeys, defaultSort: "name", defaultLimit: 100, maxLimit: 500}
+	companyListSpec    = listSpec{sorts: db.CompanySortKeys, defaultSort: "name", defaultLimit: 50, maxLimit: 100}
+	jobPostingListSpec = listSpec{sorts: db.JobPostingSortKeys, defaultSort: "created_at", defaultDesc: true, defaultLimit: 50, maxLimit: 100}
+)
+
+// listPage is a page of a list endpoint, parsed from its query parameters
+type listPage struct {
+	db.Page
+	Limit int
+}
+
+// pageCursor is the decoded form of a cursor. It records the sort it was issued
+// under, so it cannot be replayed against another.
+type pageCursor struct {
+	Sort  string    `json:"s"`
+	Desc  bool      `json:"d,omitempty"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// sortNames returns the sorts a spec offers, for error messages
+func (spec listSpec) sortNames() string {
+	names := make([]string, 0, len(spec.sorts))
+	for name := range spec.sorts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+// parseListPage reads the pagination parameters of a list request, responding with 400
+// and returning false when one is invalid
+func (s *Server) parseListPage(w http.ResponseWriter, r *http.Request, spec listSpec) (listPage, bool) {
+	query := r.URL.Query()
+	page := listPage{Page: db.Page{Sort: spec.defaultSort, Desc: spec.defaultDesc}, Limit: spec.defaultLimit}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > spec.maxLimit {
+			s.errorResponse(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(spec.maxLimit))
+			return listPage{}, false
+		}
+		page.Limit = n
+	}
+	if sort := query.Get("sort"); sort != "" {
+		if _, ok := spec.sorts[sort]; !ok {
+			s.errorResponse(w, http.StatusBadRequest, "sort must be one of: "+spec.sortNames())
+			return listPage{}, false
+		}
+		page.Sort = sort
+	}
+	switch query.Get("order") {
+	case "":
+	case "asc":
+		page.Desc = false
+	case "desc":
+		page.Desc = true
+	default:
+		s.errorResponse(w, http.StatusBadRequest, `order must be "asc" or "desc"`)
+		return listPage{}, false
+	}
+
+	if raw := query.Get("cursor"); raw != "" {
+		var c pageCursor
+		data, err := base64.RawURLEncoding.DecodeString(raw)
+		if err == nil {
+			err = json.Unmarshal(data, &c)
+		}
+		if err != nil {
+			s.errorResponse(w, http.StatusBadRequest, "Invalid cursor")
+			return listPage{}, false
+		}
+		if c.Sort != page.Sort || c.Desc != page.Desc {
+			s.errorResponse(w, http.StatusBadRequest, "cursor was issued for a different sort or order")
+			return listPage{}, false
+		}
+		page.After = &db.Cursor{Value: c.Value, ID: c.ID}
+	}
+	return page, true
+}
+
+// nextCursor returns the cursor of the page after rows, or "" when rows did not fill the
+// page. key returns a row's ID and its value for the page's sort.
+func nextCursor[T any](page listPage, rows []T, key func(row T, sort string) (uuid.UUID, string)) string {
+	if len(rows) == 0 || len(rows) < page.Limit {
+		return ""
+	}
+	id, value := key(rows[len(rows)-1], page.Sort)
+	data, err := json.Marshal(pageCursor{Sort: page.Sort, Desc: page.Desc, Value: value, ID: id})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// cursorTime formats a timestamp sort value
+func cursorTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// runSortKey returns a run's ID and its value for a sort in db.RunSortKeys
+func runSortKey(run db.Run, sort string) (uuid.UUID, string) {
+	switch sort {
+	case "company":
+		return run.ID, run.Company
+	case "role_title":
+		return run.ID, run.RoleTitle
+	case "status":
+		return run.ID, run.Status
+	}
+	return run.ID, cursorTime(run.CreatedAt)
+}
+
+// storySortKey returns a story's ID and its value for a sort in db.StorySortKeys
+func storySortKey(story db.Story, sort string) (uuid.UUID, string) {
+	switch sort {
+	case "updated_at":
+		return story.ID, cursorTime(story.UpdatedAt)
+	case "title":
+		if story.Title == nil {
+			return story.ID, ""
+		}
+		return story.ID, *story.Title
+	}
+	return story.ID, cursorTime(story.CreatedAt)
+}
+
+// skillSortKey returns a skill's ID and its value for a sort in db.SkillSortKeys
+func skillSortKey(skill db.Skill, sort string) (uuid.UUID, string) {
+	if sort == "created_at" {
+		return skill.ID, cursorTime(skill.CreatedAt)
+	}
+	return skill.ID, skill.NameNormalized
+}
+
+// companySortKey returns a company's ID and its value for a sort in db.CompanySortKeys
+func companySortKey(company db.Company, sort string) (uuid.UUID, string) {
+	if sort == "created_at" {
+		return company.ID, cursorTime(company.CreatedAt)
+	}
+	return company.ID, company.Name
+}
+
+// jobPostingSortKey returns a posting's ID and its value for a sort in db.JobPostingSortKeys
+func jobPostingSortKey(posting db.JobPosting, sort string) (uuid.UUID, string) {
+	if sort == "fetched_at" {
+		return posting.ID, cursorTime(posting.FetchedAt)
+	}
+	return posting.ID, cursorTime(posting.CreatedAt)
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestParseListPage(t *testing.T) {
+	s := newTestServer()
+
+	w := httptest.NewRecorder()
+	page, ok := s.parseListPage(w, httptest.NewRequest(http.MethodGet, "/v1/runs", nil), runListSpec)
+	require.True(t, ok)
+	assert.Equal(t, listPage{Page: db.Page{Sort: "created_at", Desc: true}, Limit: 50}, page)
+
+	page, ok = s.parseListPage(w, httptest.NewRequest(http.MethodGet, "/v1/runs?limit=10&sort=company&order=asc", nil), runListSpec)
+	require.True(t, ok)
+	assert.Equal(t, listPage{Page: db.Page{Sort: "company"}, Limit: 10}, page)
+
+	for _, query := range []string{"limit=0", "limit=501", "limit=abc", "sort=priority", "order=up", "cursor=!!", "cursor=bm90IGpzb24"} {
+		w := httptest.NewRecorder()
+		_, ok := s.parseListPage(w, httptest.NewRequest(http.MethodGet, "/v1/runs?"+query, nil), runListSpec)
+		assert.False(t, ok, query)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestNextCursor(t *testing.T) {
+	s := newTestServer()
+	created := time.Date(2026, 3, 10, 17, 0, 0, 123456000, time.UTC)
+	runs := []db.Run{{ID: uuid.New(), Company: "Acme"}, {ID: uuid.New(), Company: "Globex", CreatedAt: created}}
+
+	page := listPage{Page: db.Page{Sort: "created_at", Desc: true}, Limit: 3}
+	assert.Empty(t, nextCursor(page, runs, runSortKey), "a short page is the last")
+
+	page.Limit = 2
+	cursor := nextCursor(page, runs, runSortKey)
+	require.NotEmpty(t, cursor)
+
+	// The cursor continues the same sort after the page's last row
+	w := httptest.NewRecorder()
+	next, ok := s.parseListPage(w, httptest.NewRequest(http.MethodGet, "/v1/runs?limit=2&cursor="+cursor, nil), runListSpec)
+	require.True(t, ok)
+	assert.Equal(t, &db.Cursor{Value: "2026-03-10T17:00:00.123456Z", ID: runs[1].ID}, next.After)
+
+	// but not another
+	w = httptest.NewRecorder()
+	_, ok = s.parseListPage(w, httptest.NewRequest(http.MethodGet, "/v1/runs?order=asc&cursor="+cursor, nil), runListSpec)
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	page.Sort = "company"
+	w = httptest.NewRecorder()
+	next, ok = s.parseListPage(w, httptest.NewRequest(http.MethodGet, "/v1/runs?sort=company&order=desc&cursor="+nextCursor(page, runs, runSortKey), nil), runListSpec)
+	require.True(t, ok)
+	assert.Equal(t, "Globex", next.After.Value)
+}
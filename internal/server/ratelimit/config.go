@@ -50,10 +50,14 @@ func DefaultEndpointConfigs() []EndpointConfig {
 		{Path: "/run", Method: "POST", Limit: 10, Window: time.Hour, Burst: 2},
 		{Path: "/run/stream", Method: "POST", Limit: 10, Window: time.Hour, Burst: 2},
 		{Path: "/runs/", Method: "POST", Limit: 10, Window: time.Hour, Burst: 2},
+		{Path: "/forms/runs", Method: "POST", Limit: 10, Window: time.Hour, Burst: 2},
 
 		// Authentication endpoints (strictest limits to prevent brute force and spam)
 		{Path: "/v1/auth/login", Method: "POST", Limit: 5, Window: 15 * time.Minute, Burst: 1},
 		{Path: "/v1/auth/register", Method: "POST", Limit: 3, Window: time.Hour, Burst: 1},
+		{Path: "/v1/auth/refresh", Method: "POST", Limit: 30, Window: 15 * time.Minute, Burst: 5},
+		{Path: "/forms/login", Method: "POST", Limit: 5, Window: 15 * time.Minute, Burst: 1},
+		{Path: "/forms/token", Method: "POST", Limit: 5, Window: 15 * time.Minute, Burst: 1},
 		{Path: "/v1/users/{id}/password", Method: "PUT", Limit: 5, Window: 15 * time.Minute, Burst: 1},
 
 		// Tier 2: Write operations (moderate limits)
@@ -0,0 +1,94 @@
+// Package server provides the HTTP REST API for the resume customizer.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/config"
+)
+
+// secretTokenBytes is the entropy of an issued refresh token or verification link token
+const secretTokenBytes = 32
+
+// RefreshTokenService issues, rotates, and revokes the opaque refresh tokens that
+// clients exchange for new JWT access tokens. Tokens are stored hashed.
+type RefreshTokenService struct {
+	db  DBClient
+	ttl time.Duration
+}
+
+// NewRefreshTokenService creates a RefreshTokenService whose tokens live for the
+// configured refresh expiration
+func NewRefreshTokenService(db DBClient, cfg *config.JWTConfig) *RefreshTokenService {
+	return &RefreshTokenService{
+		db:  db,
+		ttl: time.Duration(cfg.RefreshExpirationHours) * time.Hour,
+	}
+}
+
+// Issue creates a refresh token for a user who just signed in
+func (s *RefreshTokenService) Issue(ctx context.Context, userID uuid.UUID) (string, error) {
+	token, hash, err := newSecretToken()
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.db.CreateRefreshToken(ctx, userID, hash, time.Now().Add(s.ttl)); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Rotate exchanges a refresh token for a new one and returns it with the token's
+// user. The presented token cannot be used again; presenting it a second time
+// revokes every token descended from the same login.
+func (s *RefreshTokenService) Rotate(ctx context.Context, token string) (uuid.UUID, string, error) {
+	next, nextHash, err := newSecretToken()
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	rotation, err := s.db.RotateRefreshToken(ctx, hashSecretToken(token), nextHash, time.Now().Add(s.ttl))
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	if rotation.Reused {
+		slog.WarnContext(ctx, "Refresh token reused; revoked its token family")
+	}
+	if rotation.Token == nil {
+		return uuid.Nil, "", &ErrInvalidRefreshToken{}
+	}
+	return rotation.Token.UserID, next, nil
+}
+
+// Revoke revokes a refresh token and every token rotated from the same login
+func (s *RefreshTokenService) Revoke(ctx context.Context, token string) error {
+	return s.db.RevokeRefreshTokenFamily(ctx, hashSecretToken(token))
+}
+
+// RevokeAll revokes all of a user's refresh tokens
+func (s *RefreshTokenService) RevokeAll(ctx context.Context, userID uuid.UUID) error {
+	return s.db.RevokeUserRefreshTokens(ctx, userID)
+}
+
+// newSecretToken generates a random opaque token and its storage hash
+func newSecretToken() (string, string, error) {
+	b := make([]byte, secretTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashSecretToken(token), nil
+}
+
+// hashSecretToken returns the hex SHA-256 under which an opaque token is stored
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
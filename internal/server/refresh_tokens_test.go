@@ -0,0 +1,107 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRefreshTokenService() (*RefreshTokenService, *mockDB) {
+	mock := newMockDB()
+	return NewRefreshTokenService(mock, &config.JWTConfig{ExpirationHours: 24, RefreshExpirationHours: 720}), mock
+}
+
+func TestRefreshTokenService_Rotate(t *testing.T) {
+	svc, mock := newTestRefreshTokenService()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	first, err := svc.Issue(ctx, userID)
+	require.NoError(t, err)
+	assert.NotContains(t, mock.refreshTokens, first, "tokens are stored hashed")
+
+	gotUser, second, err := svc.Rotate(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, userID, gotUser)
+	assert.NotEqual(t, first, second)
+
+	_, third, err := svc.Rotate(ctx, second)
+	require.NoError(t, err)
+
+	// Replaying a spent token revokes the whole family, including the latest token
+	_, _, err = svc.Rotate(ctx, first)
+	assert.IsType(t, &ErrInvalidRefreshToken{}, err)
+	_, _, err = svc.Rotate(ctx, third)
+	assert.IsType(t, &ErrInvalidRefreshToken{}, err)
+}
+
+func TestRefreshTokenService_RejectsUnknownToken(t *testing.T) {
+	svc, _ := newTestRefreshTokenService()
+	_, _, err := svc.Rotate(context.Background(), "not-a-token")
+	assert.IsType(t, &ErrInvalidRefreshToken{}, err)
+}
+
+func TestRefreshTokenService_RevokeAll(t *testing.T) {
+	svc, _ := newTestRefreshTokenService()
+	ctx := context.Background()
+	userID, otherID := uuid.New(), uuid.New()
+
+	phone, err := svc.Issue(ctx, userID)
+	require.NoError(t, err)
+	laptop, err := svc.Issue(ctx, userID)
+	require.NoError(t, err)
+	other, err := svc.Issue(ctx, otherID)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.RevokeAll(ctx, userID))
+	for _, token := range []string{phone, laptop} {
+		_, _, err := svc.Rotate(ctx, token)
+		assert.IsType(t, &ErrInvalidRefreshToken{}, err)
+	}
+	_, _, err = svc.Rotate(ctx, other)
+	assert.NoError(t, err, "other users keep their sessions")
+}
+
+func TestAuthHandler_RefreshAndLogout(t *testing.T) {
+	svc, _ := newTestRefreshTokenService()
+	jwtSvc := NewJWTService(&config.JWTConfig{Secret: "test-secret-key-for-jwt-signing-minimum-32-bytes", ExpirationHours: 24})
+	handler := NewAuthHandler(nil, jwtSvc, svc)
+	userID := uuid.New()
+
+	token, err := svc.Issue(context.Background(), userID)
+	require.NoError(t, err)
+
+	post := func(h http.HandlerFunc, refreshToken string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(types.RefreshTokenRequest{RefreshToken: refreshToken})
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(http.MethodPost, "/v1/auth/refresh", bytes.NewReader(body)))
+		return w
+	}
+
+	w := post(handler.Refresh, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp types.RefreshTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	claims, err := jwtSvc.ValidateToken(resp.Token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, claims.UserID)
+	assert.NotEqual(t, token, resp.RefreshToken)
+
+	w = post(handler.Logout, resp.RefreshToken)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = post(handler.Refresh, resp.RefreshToken)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = post(handler.Refresh, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -6,20 +6,35 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jonathan/resume-customizer/internal/breach"
+	"github.com/jonathan/resume-customizer/internal/compile"
 	"github.com/jonathan/resume-customizer/internal/config"
 	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/deadletter"
+	"github.com/jonathan/resume-customizer/internal/demo"
+	"github.com/jonathan/resume-customizer/internal/events"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+	"github.com/jonathan/resume-customizer/internal/pipeline"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/redact"
+	"github.com/jonathan/resume-customizer/internal/rewriting"
+	"github.com/jonathan/resume-customizer/internal/scheduler"
 	"github.com/jonathan/resume-customizer/internal/server/middleware"
 	"github.com/jonathan/resume-customizer/internal/server/ratelimit"
 	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/jonathan/resume-customizer/internal/worker"
 )
 
 // DBClient defines the database methods needed by the server
@@ -27,20 +42,37 @@ type DBClient interface {
 	// Run operations
 	GetRun(ctx context.Context, runID uuid.UUID) (*db.Run, error)
 	CreateRun(ctx context.Context, company, roleTitle, jobURL string) (uuid.UUID, error)
+	CreateQueuedRun(ctx context.Context, jobURL string) (uuid.UUID, error)
+	StartRun(ctx context.Context, runID uuid.UUID) error
+	SetRunUserID(ctx context.Context, runID, userID uuid.UUID) error
+	SetRunMaxPages(ctx context.Context, runID uuid.UUID, maxPages int) error
+	SetRunSource(ctx context.Context, runID uuid.UUID, runType string, sourceRunID uuid.UUID) error
+	GetLatestResumeRun(ctx context.Context, userID uuid.UUID) (*db.Run, error)
+	CompleteRun(ctx context.Context, runID uuid.UUID, status string) error
 	ListRunsFiltered(ctx context.Context, filters db.RunFilters) ([]db.Run, error)
 	DeleteRun(ctx context.Context, runID uuid.UUID) error
+	CountUserRunsSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, error)
 
 	// Artifact operations
 	GetArtifactByID(ctx context.Context, artifactID uuid.UUID) (*db.Artifact, error)
+	GetArtifact(ctx context.Context, runID uuid.UUID, step string) ([]byte, error)
 	GetTextArtifact(ctx context.Context, runID uuid.UUID, step string) (string, error)
 	SaveTextArtifact(ctx context.Context, runID uuid.UUID, step, category, text string) error
+	GetBinaryArtifact(ctx context.Context, runID uuid.UUID, step string) ([]byte, error)
+	OpenArtifactContent(ctx context.Context, artifactID uuid.UUID) (*db.ArtifactContent, error)
+	OpenRunArtifactContent(ctx context.Context, runID uuid.UUID, step string) (*db.ArtifactContent, error)
+	SaveBinaryArtifact(ctx context.Context, runID uuid.UUID, step, category string, data []byte) error
 	ListArtifacts(ctx context.Context, filters db.ArtifactFilters) ([]db.ArtifactSummary, error)
+	ListArtifactVersions(ctx context.Context, runID uuid.UUID, step string) ([]db.ArtifactVersion, error)
+	GetArtifactVersion(ctx context.Context, runID uuid.UUID, step string, version int) (*db.Artifact, error)
+	CopyRunArtifacts(ctx context.Context, fromRunID, toRunID uuid.UUID, steps []string) ([]string, error)
 
 	// Run step operations
 	GetRunStep(ctx context.Context, runID uuid.UUID, stepName string) (*db.RunStep, error)
 	ListRunSteps(ctx context.Context, runID uuid.UUID, status *string, category *string) ([]db.RunStep, error)
 	CreateRunStep(ctx context.Context, runID uuid.UUID, input *db.RunStepInput) (*db.RunStep, error)
 	UpdateRunStepStatus(ctx context.Context, runID uuid.UUID, stepName string, status string, errorMsg *string, artifactID *uuid.UUID) error
+	UpdateRunStepParameters(ctx context.Context, runID uuid.UUID, stepName string, params map[string]interface{}) error
 
 	// Checkpoint operations
 	GetRunCheckpoint(ctx context.Context, runID uuid.UUID) (*db.RunCheckpoint, error)
@@ -48,13 +80,147 @@ type DBClient interface {
 
 	// User operations
 	GetUser(ctx context.Context, id uuid.UUID) (*db.User, error)
+	ExportUser(ctx context.Context, userID uuid.UUID, includeCredentials bool) (*db.UserExport, error)
 	GetUserByEmail(ctx context.Context, email string) (*db.User, error)
 	CreateUser(ctx context.Context, name, email, phone string) (uuid.UUID, error)
 	UpdateUser(ctx context.Context, u *db.User) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
 	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	SetUserRedactPII(ctx context.Context, userID uuid.UUID, enabled bool) error
+	SetUserTimezone(ctx context.Context, userID uuid.UUID, timezone string) error
+	GetUserRankingConfig(ctx context.Context, userID uuid.UUID) (json.RawMessage, error)
+	SetUserRankingConfig(ctx context.Context, userID uuid.UUID, config json.RawMessage) error
 	CheckEmailExists(ctx context.Context, email string) (bool, error)
 
+	// Refresh token operations
+	CreateRefreshToken(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) (*db.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, tokenHash, newTokenHash string, expiresAt time.Time) (*db.RefreshTokenRotation, error)
+	RevokeRefreshTokenFamily(ctx context.Context, tokenHash string) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+
+	// Email verification operations
+	CreateEmailVerification(ctx context.Context, userID uuid.UUID, email, tokenHash string, expiresAt time.Time) (*db.EmailVerification, error)
+	VerifyEmail(ctx context.Context, tokenHash string) (*db.EmailVerification, error)
+	LatestEmailVerification(ctx context.Context, userID uuid.UUID) (*db.EmailVerification, error)
+
+	// Domain crawl policy operations
+	UpsertDomainPolicy(ctx context.Context, input *db.DomainPolicyInput) (*db.DomainPolicy, error)
+	ListDomainPolicies(ctx context.Context, userID *uuid.UUID) ([]db.DomainPolicy, error)
+	DeleteDomainPolicy(ctx context.Context, id uuid.UUID, userID *uuid.UUID) error
+
+	// Notification operations
+	UpsertNotificationPreference(ctx context.Context, input *db.NotificationPreferenceInput) (*db.NotificationPreference, error)
+	ListNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]db.NotificationPreference, error)
+	GetNotificationRecipient(ctx context.Context, userID uuid.UUID, eventType string) (*db.NotificationRecipient, error)
+	ListDueDigestRecipients(ctx context.Context, eventType string, interval time.Duration) ([]db.NotificationRecipient, error)
+	ClaimDigest(ctx context.Context, eventType string, userID uuid.UUID, interval time.Duration, at time.Time) (bool, error)
+	ClaimNotification(ctx context.Context, eventType string, refID, userID uuid.UUID) (bool, error)
+	GetDigestStats(ctx context.Context, userID uuid.UUID, since time.Time) (*db.DigestStats, error)
+	GetResumeHealth(ctx context.Context, userID uuid.UUID, now time.Time) (*db.ResumeHealth, error)
+
+	// Dead letters
+	RecordDeadLetter(ctx context.Context, input *db.DeadLetterInput) (*db.DeadLetter, error)
+	GetDeadLetter(ctx context.Context, id uuid.UUID) (*db.DeadLetter, error)
+	ListDeadLetters(ctx context.Context, filters db.DeadLetterFilters) ([]db.DeadLetter, error)
+	CountPendingDeadLetters(ctx context.Context) (map[string]int, error)
+	ResolveDeadLetter(ctx context.Context, id uuid.UUID, status string) (*db.DeadLetter, error)
+	RequeueStepJob(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// Webhooks
+	CreateWebhook(ctx context.Context, input *db.WebhookInput) (*db.Webhook, error)
+	GetWebhook(ctx context.Context, id, userID uuid.UUID) (*db.Webhook, error)
+	ListWebhooks(ctx context.Context, userID uuid.UUID) ([]db.Webhook, error)
+	DeleteWebhook(ctx context.Context, id, userID uuid.UUID) (bool, error)
+	ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]db.WebhookDelivery, error)
+	EnqueueWebhookDeliveries(ctx context.Context, userID uuid.UUID, runID *uuid.UUID, event, eventKey string, payload any) (int64, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]db.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, id uuid.UUID, attempt *db.WebhookAttempt) error
+
+	// Application deadline operations
+	SetRunDates(ctx context.Context, runID uuid.UUID, deadlineAt, followUpAt *time.Time) error
+	ListApplicationDates(ctx context.Context, userID uuid.UUID) ([]db.Run, error)
+	ListDueReminders(ctx context.Context, lead time.Duration) ([]db.ApplicationReminder, error)
+
+	// Application tracker operations
+	UpsertApplicationTracker(ctx context.Context, userID uuid.UUID, input *db.ApplicationTrackerInput) (*db.ApplicationTracker, error)
+	ListApplicationTrackers(ctx context.Context, userID uuid.UUID) ([]db.ApplicationTracker, error)
+	GetApplicationTracker(ctx context.Context, id uuid.UUID) (*db.ApplicationTracker, error)
+	DeleteApplicationTracker(ctx context.Context, id uuid.UUID) error
+	ListApplicationContacts(ctx context.Context, trackerID uuid.UUID) ([]db.ApplicationContact, error)
+	CreateApplicationContact(ctx context.Context, trackerID uuid.UUID, input *db.ApplicationContactInput) (*db.ApplicationContact, error)
+	UpdateApplicationContact(ctx context.Context, trackerID, contactID uuid.UUID, input *db.ApplicationContactInput) (*db.ApplicationContact, error)
+	DeleteApplicationContact(ctx context.Context, trackerID, contactID uuid.UUID) (bool, error)
+	ListApplicationInteractions(ctx context.Context, trackerID uuid.UUID) ([]db.ApplicationInteraction, error)
+	CreateApplicationInteraction(ctx context.Context, trackerID uuid.UUID, input *db.ApplicationInteractionInput) (*db.ApplicationInteraction, error)
+	DeleteApplicationInteraction(ctx context.Context, trackerID, interactionID uuid.UUID) (bool, error)
+
+	// Shared resume page operations
+	UpsertSharedResume(ctx context.Context, input *db.SharedResumeInput) (*db.SharedResume, error)
+	UpdateSharedResumeAccess(ctx context.Context, userID uuid.UUID, scope string, expiresAt *time.Time) (*db.SharedResume, error)
+	RevokeSharedResume(ctx context.Context, userID uuid.UUID) (*db.SharedResume, error)
+	GetSharedResume(ctx context.Context, userID uuid.UUID) (*db.SharedResume, error)
+	GetSharedResumeBySlug(ctx context.Context, slug string) (*db.SharedResume, error)
+	DeleteSharedResume(ctx context.Context, userID uuid.UUID) error
+	RecordSharedResumeView(ctx context.Context, sharedID uuid.UUID, kind, referrerHost string) error
+	GetSharedResumeStats(ctx context.Context, sharedID uuid.UUID) (*db.SharedResumeStats, error)
+	CreateSharedResumeComment(ctx context.Context, sharedID uuid.UUID, author, body string) (*db.SharedResumeComment, error)
+	ListSharedResumeComments(ctx context.Context, sharedID uuid.UUID) ([]db.SharedResumeComment, error)
+	RecordSharedResumeAccess(ctx context.Context, input *db.SharedResumeAccessInput) error
+	ListSharedResumeAccess(ctx context.Context, sharedID uuid.UUID, limit int) ([]db.SharedResumeAccess, error)
+
+	// Onboarding wizard operations
+	GetOnboarding(ctx context.Context, userID uuid.UUID) (*db.Onboarding, error)
+	TransitionOnboarding(ctx context.Context, userID uuid.UUID, from, to string, skipped []string, finished bool) (*db.Onboarding, error)
+
+	// GitHub project import operations
+	UpsertGitHubAccount(ctx context.Context, userID uuid.UUID, username string, tokenSealed *string) (*db.GitHubAccount, error)
+	GetGitHubAccount(ctx context.Context, userID uuid.UUID) (*db.GitHubAccount, error)
+	DeleteGitHubAccount(ctx context.Context, userID uuid.UUID) error
+	ClaimGitHubSync(ctx context.Context, userID uuid.UUID, minInterval time.Duration) (bool, time.Time, error)
+	UpsertProjectDrafts(ctx context.Context, userID uuid.UUID, drafts []db.ProjectDraftInput) error
+	ListProjectDrafts(ctx context.Context, userID uuid.UUID, status string) ([]db.ProjectDraft, error)
+	GetProjectDraft(ctx context.Context, id uuid.UUID) (*db.ProjectDraft, error)
+	AcceptProjectDraft(ctx context.Context, id, jobID uuid.UUID, text string) (*db.ProjectDraft, error)
+	DismissProjectDraft(ctx context.Context, id uuid.UUID) (*db.ProjectDraft, error)
+
+	// Bullet suggestion review operations
+	ListBulletSuggestions(ctx context.Context, userID uuid.UUID, status string) ([]db.BulletSuggestion, error)
+	GetBulletSuggestion(ctx context.Context, id uuid.UUID) (*db.BulletSuggestion, error)
+	AcceptBulletSuggestion(ctx context.Context, id, jobID uuid.UUID, text string) (*db.BulletSuggestion, error)
+	DismissBulletSuggestion(ctx context.Context, id uuid.UUID) (*db.BulletSuggestion, error)
+
+	// Company preset operations
+	UpsertCompanyPreference(ctx context.Context, userID uuid.UUID, input *db.CompanyPreferenceInput) (*db.CompanyPreference, error)
+	ListCompanyPreferences(ctx context.Context, userID uuid.UUID, company string) ([]db.CompanyPreference, error)
+	GetCompanyPreference(ctx context.Context, id uuid.UUID) (*db.CompanyPreference, error)
+	DeleteCompanyPreference(ctx context.Context, id uuid.UUID) error
+
+	// Run recipe operations
+	UpsertRunRecipe(ctx context.Context, userID uuid.UUID, input *db.RunRecipeInput) (*db.RunRecipe, error)
+	ListRunRecipes(ctx context.Context, userID uuid.UUID) ([]db.RunRecipe, error)
+	GetRunRecipe(ctx context.Context, id uuid.UUID) (*db.RunRecipe, error)
+	GetRunRecipeByName(ctx context.Context, userID uuid.UUID, name string) (*db.RunRecipe, error)
+	DeleteRunRecipe(ctx context.Context, id uuid.UUID) error
+
+	// Workspace and rule pack operations
+	CreateWorkspace(ctx context.Context, name string, createdBy uuid.UUID) (*db.Workspace, error)
+	ListWorkspacesForUser(ctx context.Context, userID uuid.UUID) ([]db.Workspace, error)
+	GetWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) (*db.WorkspaceMember, error)
+	ListWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) ([]db.WorkspaceMember, error)
+	SetWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID, role string) (*db.WorkspaceMember, error)
+	RemoveWorkspaceMember(ctx context.Context, workspaceID, userID uuid.UUID) error
+	UpsertRulePack(ctx context.Context, workspaceID, createdBy uuid.UUID, input *db.RulePackInput) (*db.RulePack, error)
+	ListRulePacks(ctx context.Context, workspaceID uuid.UUID) ([]db.RulePack, error)
+	GetRulePack(ctx context.Context, id uuid.UUID) (*db.RulePack, error)
+	DeleteRulePack(ctx context.Context, id uuid.UUID) error
+	ListRulePackApplications(ctx context.Context, workspaceID uuid.UUID, limit int) ([]db.RulePackApplication, error)
+
+	// Voice note operations
+	CreateVoiceNote(ctx context.Context, input *db.VoiceNoteInput) (*db.VoiceNote, error)
+	ListVoiceNotes(ctx context.Context, userID uuid.UUID) ([]db.VoiceNote, error)
+	GetVoiceNote(ctx context.Context, id uuid.UUID) (*db.VoiceNote, error)
+	DeleteVoiceNote(ctx context.Context, id uuid.UUID) error
+
 	// Job operations
 	CreateJob(ctx context.Context, job *db.Job) (uuid.UUID, error)
 	ListJobs(ctx context.Context, userID uuid.UUID) ([]db.Job, error)
@@ -74,20 +240,23 @@ type DBClient interface {
 	DeleteEducation(ctx context.Context, id uuid.UUID) error
 
 	// Company operations
-	ListCompaniesWithProfiles(ctx context.Context, limit, offset int) ([]db.Company, int, error)
+	ListCompaniesWithProfiles(ctx context.Context, limit int, page db.Page) ([]db.Company, int, error)
 	GetCompanyByID(ctx context.Context, companyID uuid.UUID) (*db.Company, error)
 	GetCompanyByNormalizedName(ctx context.Context, normalized string) (*db.Company, error)
 	ListCompanyDomains(ctx context.Context, companyID uuid.UUID) ([]db.CompanyDomain, error)
+	ListCompanyFingerprints(ctx context.Context) ([]db.CompanyFingerprint, error)
 	FindOrCreateCompany(ctx context.Context, name string) (*db.Company, error)
 	AddCompanyDomain(ctx context.Context, companyID uuid.UUID, domain string, domainType string) error
 
 	// Company profile operations
 	GetCompanyProfileByCompanyID(ctx context.Context, companyID uuid.UUID) (*db.CompanyProfile, error)
 	CreateCompanyProfile(ctx context.Context, input *db.ProfileCreateInput) (*db.CompanyProfile, error)
+	UpsertUserCompanyProfile(ctx context.Context, userID uuid.UUID, input *db.ProfileCreateInput) (*db.CompanyProfile, error)
 	GetStyleRulesByProfileID(ctx context.Context, profileID uuid.UUID) ([]db.CompanyStyleRule, error)
 	GetTabooPhrasesByProfileID(ctx context.Context, profileID uuid.UUID) ([]db.CompanyTabooPhrase, error)
 	GetValuesByProfileID(ctx context.Context, profileID uuid.UUID) ([]db.CompanyValue, error)
 	GetSourcesByProfileID(ctx context.Context, profileID uuid.UUID) ([]db.CompanyProfileSource, error)
+	ListCompanyProfileVersions(ctx context.Context, companyID uuid.UUID) ([]db.CompanyProfileVersion, error)
 
 	// Job posting operations
 	ListJobPostings(ctx context.Context, opts db.ListJobPostingsOptions) ([]db.JobPosting, int, error)
@@ -95,23 +264,38 @@ type DBClient interface {
 	GetJobPostingByURL(ctx context.Context, url string) (*db.JobPosting, error)
 	ListJobPostingsByCompany(ctx context.Context, companyID uuid.UUID) ([]db.JobPosting, error)
 	UpsertJobPosting(ctx context.Context, input *db.JobPostingCreateInput) (*db.JobPosting, error)
+	ListDuplicatePostings(ctx context.Context, canonicalID uuid.UUID) ([]db.JobPosting, error)
+	ListPostingVersions(ctx context.Context, postingID uuid.UUID) ([]db.PostingVersion, error)
+	ListCompanyPostingHistory(ctx context.Context, companyID uuid.UUID, roleFamily string) ([]db.PostingVersion, error)
 
 	// Job profile operations
 	GetJobProfileByID(ctx context.Context, profileID uuid.UUID) (*db.JobProfile, error)
 	GetJobProfileByPostingID(ctx context.Context, postingID uuid.UUID) (*db.JobProfile, error)
 	GetRequirementsByProfileID(ctx context.Context, profileID uuid.UUID) ([]db.JobRequirement, error)
+	GetSkillGap(ctx context.Context, userID, postingID uuid.UUID) (*db.SkillGap, error)
+	UpsertResumeTemplate(ctx context.Context, userID uuid.UUID, name, content string, manifest json.RawMessage) (*db.ResumeTemplate, error)
+	ListResumeTemplates(ctx context.Context, userID uuid.UUID) ([]db.ResumeTemplate, error)
+	GetResumeTemplateByName(ctx context.Context, userID uuid.UUID, name string) (*db.ResumeTemplate, error)
 	GetResponsibilitiesByProfileID(ctx context.Context, profileID uuid.UUID) ([]db.JobResponsibility, error)
 	GetKeywordsByProfileID(ctx context.Context, profileID uuid.UUID) ([]db.JobKeyword, error)
 	CreateJobProfile(ctx context.Context, input *db.JobProfileCreateInput) (*db.JobProfile, error)
+	GetJobRequirement(ctx context.Context, id, userID uuid.UUID) (*db.JobRequirement, error)
+	SetRequirementWeight(ctx context.Context, userID uuid.UUID, req *db.JobRequirement, weight *float64) error
+	GetUserKeywords(ctx context.Context, profileID, userID uuid.UUID) ([]db.JobKeyword, error)
+	AddUserKeyword(ctx context.Context, profileID, userID uuid.UUID, keyword string) error
+	RemoveUserKeyword(ctx context.Context, profileID, userID uuid.UUID, keyword string) (bool, error)
 
 	// Experience bank operations
-	ListStoriesByUser(ctx context.Context, userID uuid.UUID) ([]db.Story, error)
+	ListStoriesByUser(ctx context.Context, userID uuid.UUID, limit int, page db.Page) ([]db.Story, error)
 	GetStoryByID(ctx context.Context, storyID uuid.UUID) (*db.Story, error)
 	CreateStory(ctx context.Context, input *db.StoryCreateInput) (*db.Story, error)
+	ImportExperienceBank(ctx context.Context, input *db.ExperienceBankImportInput) error
 	GetBulletsByStoryID(ctx context.Context, storyID uuid.UUID) ([]db.Bullet, error)
-	ListSkillsByUserID(ctx context.Context, userID uuid.UUID) ([]db.Skill, error)
+	ListSkillsByUserID(ctx context.Context, userID uuid.UUID, limit int, page db.Page) ([]db.Skill, error)
 	GetSkillByName(ctx context.Context, name string) (*db.Skill, error)
 	GetBulletsBySkillIDAndUserID(ctx context.Context, skillID uuid.UUID, userID uuid.UUID) ([]db.Bullet, error)
+	UpsertUserSkill(ctx context.Context, userID uuid.UUID, skillName string, proficiency *int, lastUsed *db.Date) (*db.UserSkill, error)
+	ListUserSkills(ctx context.Context, userID uuid.UUID) ([]db.UserSkill, error)
 
 	// Crawled pages operations
 	GetCrawledPageByID(ctx context.Context, pageID uuid.UUID) (*db.CrawledPage, error)
@@ -122,8 +306,27 @@ type DBClient interface {
 	// Experience bank (types)
 	GetExperienceBank(ctx context.Context, userID uuid.UUID) (*types.ExperienceBank, error)
 
-	// Pool access (used in one place in handlers_steps.go)
-	Pool() *pgxpool.Pool
+	// Debug artifact retention
+	DeleteExpiredDebugArtifacts(ctx context.Context, retention time.Duration) (int64, error)
+
+	// Run garbage collection
+	MarkAbandonedRuns(ctx context.Context, idle time.Duration) (int64, error)
+	DeleteAbandonedRuns(ctx context.Context, retention time.Duration) (*db.ReclaimedRuns, error)
+	GetArtifactStorage(ctx context.Context) (*db.ArtifactStorage, error)
+
+	// Latency SLO report
+	ListLatencyStats(ctx context.Context, since time.Time, bucket string, budgetsMs map[string]int64) ([]db.LatencyStats, error)
+
+	// Idempotency keys
+	ClaimIdempotencyKey(ctx context.Context, userID uuid.UUID, key, requestHash string, ttl, stale time.Duration) (*db.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, userID uuid.UUID, key string, statusCode int, response json.RawMessage) error
+	ReleaseIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+
+	// Background run jobs
+	EnqueueRunJob(ctx context.Context, runID, userID uuid.UUID, priority int, payload any) (uuid.UUID, error)
+	CountQueuedRunJobs(ctx context.Context, userID uuid.UUID) (int, error)
+	GetRunJobByRun(ctx context.Context, runID uuid.UUID) (*db.RunJob, error)
 
 	// Cleanup
 	Close()
@@ -131,14 +334,43 @@ type DBClient interface {
 
 // Server represents the HTTP server
 type Server struct {
-	httpServer  *http.Server
-	db          DBClient
-	apiKey      string
-	databaseURL string
-	rateLimiter *ratelimit.Limiter
-	jwtService  *JWTService //nolint:unused // Reserved for Phase 8 (routes with authentication)
-	userService *UserService
-	authHandler *AuthHandler
+	httpServer   *http.Server
+	db           DBClient
+	apiKey       string
+	databaseURL  string
+	rateLimiter  *ratelimit.Limiter
+	jwtService   *JWTService //nolint:unused // Reserved for Phase 8 (routes with authentication)
+	userService  *UserService
+	authHandler  *AuthHandler
+	verification *EmailVerificationService // Emails verification links; disabled without a mailer
+	debugConfig  *config.DebugConfig
+	events       *events.Bus
+	scheduler    *scheduler.Scheduler
+	routing      *llm.Routing                // Per-step model routing; nil uses each call's default tier
+	crawl        *config.CrawlConfig         // Research crawl defaults; runs may override them
+	admins       *config.AdminConfig         // Users who manage server-wide settings and may debug any run
+	notify       *config.NotificationConfig  // SMTP settings and digest schedule
+	notifier     *notifications.Notifier     // Delivers run and digest notifications
+	publicURL    string                      // Externally visible base URL for links; empty derives it per request
+	github       *config.GitHubConfig        // GitHub project import settings
+	transcribe   *config.TranscriptionConfig // Voice note transcription settings
+	demo         *demo.Fixtures              // Canned data and recorded LLM responses; nil outside demo mode
+	runGC        *config.RunGCConfig         // Abandoned run cleanup policy
+	runGCStats   runGCStats                  // Rows reclaimed by run garbage collection
+	slo          *config.SLOConfig           // End-to-end latency budget and the share of executions expected within budget
+	deadLetters  *config.DeadLetterConfig    // Alerting on failed background work held for operators
+	dlqAlarm     *deadletter.Alarm           // Fires when pending dead letters reach the alert threshold
+	runWorkers   *worker.Pool                // Executes runs queued by POST /v1/runs; nil when RUN_WORKERS=0
+	apiDocument  []byte                      // OpenAPI document served at /v1/openapi.json
+
+	// stepExecutor builds the executor for POST /v1/runs/{run_id}/steps/{step_name}
+	stepExecutor func(ctx context.Context, run *db.Run, stepName string) (steps.StepExecutor, error)
+	// compilePDF compiles resumes that have no stored PDF yet
+	compilePDF func(ctx context.Context, latex string) (*compile.Result, error)
+	// summarizePosting ingests and parses postings for POST /v1/tools/summarize-posting
+	summarizePosting func(ctx context.Context, opts pipeline.RunOptions) (*types.JobProfile, error)
+	// rewriteVariants rewrites single bullets for POST /v1/tools/rewrite-bullet
+	rewriteVariants func(ctx context.Context, bullet string, job *types.JobProfile, company *types.CompanyProfile, count int, apiKey string) ([]types.RewrittenBullet, error)
 }
 
 // Config holds server configuration
@@ -146,6 +378,7 @@ type Config struct {
 	Port        int
 	DatabaseURL string
 	APIKey      string
+	Demo        *demo.Fixtures // Optional: Run every pipeline on bundled demo data
 }
 
 // New creates a new server instance
@@ -160,7 +393,17 @@ func New(cfg Config) (*Server, error) {
 		db:          database,
 		apiKey:      cfg.APIKey,
 		databaseURL: cfg.DatabaseURL,
+		events:      events.NewBus(database),
+		publicURL:   strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/"),
+		demo:        cfg.Demo,
+	}
+
+	s.stepExecutor = func(ctx context.Context, run *db.Run, stepName string) (steps.StepExecutor, error) {
+		return s.newStepExecutor(ctx, database, run, stepName)
 	}
+	s.compilePDF = compile.PDF
+	s.summarizePosting = pipeline.SummarizePosting
+	s.rewriteVariants = rewriting.RewriteVariants
 
 	// Initialize rate limiter
 	s.rateLimiter = ratelimit.NewLimiter(ratelimit.LoadConfig())
@@ -172,6 +415,20 @@ func New(cfg Config) (*Server, error) {
 	}
 	s.userService = NewUserService(database, passwordConfig)
 
+	breachConfig, err := config.NewBreachCheckConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create password breach check config: %w", err)
+	}
+	if breachConfig.Enabled {
+		var filter *breach.Filter
+		if breachConfig.FilterPath != "" {
+			if filter, err = breach.LoadFilter(breachConfig.FilterPath); err != nil {
+				return nil, err
+			}
+		}
+		s.userService.WithBreachCheck(breach.NewChecker(breachConfig.APIURL, filter, breachConfig.Timeout()))
+	}
+
 	jwtConfig, err := config.NewJWTConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create JWT config: %w", err)
@@ -179,25 +436,180 @@ func New(cfg Config) (*Server, error) {
 	jwtService := NewJWTService(jwtConfig)
 	s.jwtService = jwtService // Store for future use in Phase 8 (routes)
 
-	s.authHandler = NewAuthHandler(s.userService, jwtService)
+	s.authHandler = NewAuthHandler(s.userService, jwtService, NewRefreshTokenService(database, jwtConfig))
+
+	s.debugConfig, err = config.NewDebugConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create debug config: %w", err)
+	}
+
+	s.admins, err = config.NewAdminConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create admin config: %w", err)
+	}
+
+	s.crawl, err = config.NewCrawlConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create crawl config: %w", err)
+	}
+
+	s.runGC, err = config.NewRunGCConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create run GC config: %w", err)
+	}
+
+	s.slo, err = config.NewSLOConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SLO config: %w", err)
+	}
+
+	s.notify, err = config.NewNotificationConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notification config: %w", err)
+	}
+	s.notifier = notifications.New(s.notify)
+	s.verification = NewEmailVerificationService(database, s.notify, notifications.NewMailer(s.notify, log.Writer()))
+	s.authHandler.WithEmailVerification(s.verification, s.baseURL)
+	if !s.verification.Enabled() {
+		slog.Info("Email verification disabled: no mailer configured (set SMTP_HOST or MAILER=console)")
+	}
+
+	s.deadLetters, err = config.NewDeadLetterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead letter config: %w", err)
+	}
+	s.dlqAlarm = deadletter.NewAlarm(s.deadLetters.AlertThreshold)
+
+	promptRollouts, err := config.NewPromptRolloutConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prompt rollout config: %w", err)
+	}
+	if err := s.startPromptRollouts(context.Background(), promptRollouts, database); err != nil {
+		return nil, err
+	}
+
+	s.github, err = config.NewGitHubConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub config: %w", err)
+	}
+
+	s.transcribe, err = config.NewTranscriptionConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcription config: %w", err)
+	}
+
+	schedulerConfig, err := config.NewSchedulerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scheduler config: %w", err)
+	}
+	runWorkerConfig, err := config.NewRunWorkerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create run worker config: %w", err)
+	}
+	if runWorkerConfig.Workers > 0 {
+		host, _ := os.Hostname()
+		s.runWorkers = worker.NewPool(database, fmt.Sprintf("%s-%s", host, uuid.NewString()[:8]), s.executeRunJob,
+			runWorkerConfig.Workers, runWorkerConfig.StaleAfter(), runWorkerConfig.MaxAttempts)
+	}
+
+	s.scheduler = scheduler.New(schedulerConfig.MaxConcurrentRuns, schedulerConfig.MaxConcurrentRunsPerUser,
+		schedulerConfig.MaxQueuedRunsPerUser).WithAdmission(schedulerConfig.MaxAdmissionBacklog, schedulerConfig.RunEstimate())
+
+	// Register custom step plugins before any run can reference them
+	if dir := os.Getenv("PIPELINE_PLUGIN_DIR"); dir != "" {
+		if err := steps.LoadPlugins(dir); err != nil {
+			return nil, fmt.Errorf("failed to load step plugins: %w", err)
+		}
+		slog.Info("Loaded step plugins", "plugins", len(steps.Plugins), "dir", dir)
+	}
+
+	// Per-step execution backends (worker queue, Kubernetes Jobs)
+	if path := os.Getenv("STEP_EXECUTION_CONFIG"); path != "" {
+		if err := steps.LoadExecutionConfig(path); err != nil {
+			return nil, err
+		}
+		slog.Info("Loaded step execution config", "path", path)
+	}
+
+	// Cost-optimized model routing per step
+	if path := os.Getenv("MODEL_ROUTING_CONFIG"); path != "" {
+		routing, err := llm.LoadRouting(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := pipeline.ValidateModelRouting(routing); err != nil {
+			return nil, err
+		}
+		s.routing = routing
+		slog.Info("Loaded model routing config", "path", path)
+	}
 
 	// Setup router
-	mux := http.NewServeMux()
+	mux := s.routes()
+	if s.apiDocument, err = buildAPIDocument(mux.routes); err != nil {
+		return nil, err
+	}
+
+	// Create HTTP server
+	s.httpServer = &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
+		Handler:      middleware.RequestID(s.withRateLimit(s.withLogging(s.withCORS(mux)))),
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 300 * time.Second, // Long timeout for pipeline runs
+		IdleTimeout:  60 * time.Second,
+	}
+
+	return s, nil
+}
+
+// routes registers every route the server serves
+func (s *Server) routes() *routeMux {
+	mux := newRouteMux()
+
 	// Health check endpoint (no version prefix)
 	mux.HandleFunc("GET /health", s.handleHealth)
 
+	// Public shared resume pages (no version prefix, unauthenticated)
+	mux.HandleFunc("GET /r/{slug}", s.handleSharedResumePage)
+	mux.HandleFunc("GET /r/{slug}/resume.pdf", s.handleSharedResumePDF)
+	mux.HandleFunc("GET /r/{slug}/thumbnail.png", s.handleSharedResumeThumbnail)
+	mux.HandleFunc("GET /r/{slug}/artifacts", s.handleSharedResumeArtifacts)
+	mux.HandleFunc("GET /r/{slug}/artifacts/{artifact_id}", s.handleSharedResumeArtifact)
+	mux.HandleFunc("POST /r/{slug}/comments", s.handleCreateSharedResumeComment)
+
+	// Bundled web UI (no version prefix, signs in through /v1/auth/login)
+	if webUIEnabled() {
+		web := webUIHandler()
+		mux.Handle("GET /{$}", web)
+		mux.Handle("GET /ui/", web)
+	}
+
 	// Legacy endpoints (deprecated, use /v1 versions)
-	mux.HandleFunc("POST /run", s.handleRun)
-	mux.HandleFunc("POST /run/stream", s.handleRunStream)
+	mux.Handle("POST /run", s.requireVerifiedEmail(http.HandlerFunc(s.handleRun)))
+	mux.Handle("POST /run/stream", s.requireVerifiedEmail(http.HandlerFunc(s.handleRunStream)))
 	mux.HandleFunc("GET /status/{id}", s.handleStatus)
 	mux.HandleFunc("GET /artifact/{id}", s.handleArtifact)
 
 	// Authentication endpoints (public)
 	mux.HandleFunc("POST /v1/auth/register", s.handleRegister)
 	mux.HandleFunc("POST /v1/auth/login", s.handleLogin)
+	mux.HandleFunc("POST /v1/auth/refresh", s.handleRefreshToken)
+	mux.HandleFunc("POST /v1/auth/logout", s.handleLogout)
+	mux.HandleFunc("GET /v1/auth/verify-email", s.handleVerifyEmail)
+	mux.Handle("POST /v1/auth/verify-email/resend", s.withAuth(http.HandlerFunc(s.handleResendVerification)))
+
+	// Form-encoded fallbacks for clients without JavaScript or JSON tooling
+	mux.HandleFunc("GET /forms/login", s.handleFormsLoginPage)
+	mux.HandleFunc("POST /forms/login", s.handleFormsLogin)
+	mux.HandleFunc("POST /forms/token", s.handleFormsToken)
+	mux.HandleFunc("POST /forms/logout", s.handleFormsLogout)
+	mux.HandleFunc("GET /forms/runs/new", s.handleFormsNewRunPage)
+	mux.HandleFunc("POST /forms/runs", s.handleFormsCreateRun)
+	mux.HandleFunc("GET /forms/runs/{id}", s.handleFormsRunPage)
+	mux.HandleFunc("POST /forms/runs/{id}/download", s.handleFormsDownload)
 
 	// Step-by-step pipeline API endpoints
-	mux.HandleFunc("POST /v1/runs", s.handleCreateRun)
+	mux.Handle("POST /v1/runs", s.requireVerifiedEmail(s.idempotent(http.HandlerFunc(s.handleCreateRun))))
 	mux.HandleFunc("POST /v1/runs/{run_id}/steps/{step_name}", s.handleExecuteStep)
 	mux.HandleFunc("GET /v1/runs/{run_id}/steps", s.handleListRunSteps)
 	mux.HandleFunc("GET /v1/runs/{run_id}/steps/{step_name}", s.handleGetStepStatus)
@@ -206,6 +618,16 @@ func New(cfg Config) (*Server, error) {
 	mux.HandleFunc("POST /v1/runs/{run_id}/steps/{step_name}/skip", s.handleSkipStep)
 	mux.HandleFunc("POST /v1/runs/{run_id}/steps/{step_name}/retry", s.handleRetryStep)
 
+	// Admin: abandoned run cleanup metrics
+	mux.Handle("GET /v1/admin/run-gc", s.withAuth(http.HandlerFunc(s.handleRunGCStats)))
+	mux.Handle("GET /v1/admin/fetch-stats", s.withAuth(http.HandlerFunc(s.handleFetchStats)))
+	mux.Handle("GET /v1/admin/slo", s.withAuth(http.HandlerFunc(s.handleSLOReport)))
+	mux.Handle("GET /v1/admin/prompt-rollouts", s.withAuth(http.HandlerFunc(s.handleListPromptRollouts)))
+	mux.Handle("GET /v1/admin/dead-letters", s.withAuth(http.HandlerFunc(s.handleListDeadLetters)))
+	mux.Handle("GET /v1/admin/dead-letters/stats", s.withAuth(http.HandlerFunc(s.handleDeadLetterStats)))
+	mux.Handle("POST /v1/admin/dead-letters/{dead_letter_id}/retry", s.withAuth(http.HandlerFunc(s.handleRetryDeadLetter)))
+	mux.Handle("POST /v1/admin/dead-letters/{dead_letter_id}/discard", s.withAuth(http.HandlerFunc(s.handleDiscardDeadLetter)))
+
 	// CRUD endpoints for runs
 	mux.HandleFunc("GET /v1/runs", s.handleListRuns)
 	mux.HandleFunc("GET /v1/runs/{id}", s.handleGetRun)
@@ -213,15 +635,95 @@ func New(cfg Config) (*Server, error) {
 	mux.HandleFunc("DELETE /v1/runs/{id}", s.handleDeleteRun)
 	mux.HandleFunc("GET /v1/runs/{id}/artifacts", s.handleRunArtifacts)
 	mux.HandleFunc("GET /v1/runs/{id}/resume.tex", s.handleRunResumeTex)
+	mux.HandleFunc("GET /v1/runs/{id}/artifacts/pdf", s.handleRunPDF)
+	mux.HandleFunc("GET /v1/runs/{run_id}/artifacts/{step}/diff", s.handleRunArtifactDiff)
+	mux.HandleFunc("GET /v1/runs/{run_id}/artifacts/{step}/versions", s.handleListArtifactVersions)
+	mux.HandleFunc("GET /v1/runs/{run_id}/artifacts/{step}/versions/{version}", s.handleGetArtifactVersion)
+	mux.HandleFunc("GET /v1/runs/{id}/download", s.handleRunDownload)
+	mux.HandleFunc("GET /v1/runs/{id}/preview", s.handleRunPreview)
+	mux.HandleFunc("GET /v1/runs/{run_id}/ats-report", s.handleRunATSReport)
+	mux.HandleFunc("GET /v1/runs/{id}/thumbnail.png", s.handleRunThumbnail)
+	mux.HandleFunc("GET /v1/runs/{id}/timeline", s.handleGetRunTimeline)
+	mux.HandleFunc("GET /v1/runs/{id}/events", s.handleRunEvents)
+	mux.Handle("PUT /v1/runs/{id}/dates", s.withAuth(http.HandlerFunc(s.handleUpdateRunDates)))
 
 	// CRUD endpoints for artifacts
 	mux.HandleFunc("GET /v1/artifacts", s.handleListArtifacts)
 	mux.HandleFunc("GET /v1/artifact/{id}", s.handleGetArtifact)
+	mux.HandleFunc("GET /v1/artifact/{id}/content", s.handleGetArtifactContent)
 
 	// User Profile endpoints
 	mux.HandleFunc("POST /v1/users", s.handleCreateUser)
 	// More specific routes must be registered before general {id} routes
 	mux.Handle("PUT /v1/users/{id}/password", s.withAuth(http.HandlerFunc(s.handleUpdateUserPassword)))
+	mux.Handle("PUT /v1/users/{id}/privacy", s.withAuth(http.HandlerFunc(s.handleUpdatePrivacy)))
+	mux.Handle("PUT /v1/users/{id}/timezone", s.withAuth(http.HandlerFunc(s.handleUpdateTimezone)))
+	mux.Handle("GET /v1/users/{id}/ranking-config", s.withAuth(http.HandlerFunc(s.handleGetRankingConfig)))
+	mux.Handle("PUT /v1/users/{id}/ranking-config", s.withAuth(http.HandlerFunc(s.handlePutRankingConfig)))
+	mux.Handle("DELETE /v1/users/{id}/ranking-config", s.withAuth(http.HandlerFunc(s.handleDeleteRankingConfig)))
+	mux.Handle("GET /v1/users/{id}/company-presets", s.withAuth(http.HandlerFunc(s.handleListCompanyPresets)))
+	mux.Handle("POST /v1/users/{id}/company-presets", s.withAuth(http.HandlerFunc(s.handleSaveCompanyPreset)))
+	mux.Handle("DELETE /v1/users/{id}/company-presets/{preset_id}", s.withAuth(http.HandlerFunc(s.handleDeleteCompanyPreset)))
+	mux.Handle("GET /v1/users/{id}/recipes", s.withAuth(http.HandlerFunc(s.handleListRunRecipes)))
+	mux.Handle("POST /v1/users/{id}/recipes", s.withAuth(http.HandlerFunc(s.handleSaveRunRecipe)))
+	mux.Handle("DELETE /v1/users/{id}/recipes/{recipe_id}", s.withAuth(http.HandlerFunc(s.handleDeleteRunRecipe)))
+
+	// Workspaces: members and admin-published style rule packs
+	mux.Handle("POST /v1/workspaces", s.withAuth(http.HandlerFunc(s.handleCreateWorkspace)))
+	mux.Handle("GET /v1/workspaces", s.withAuth(http.HandlerFunc(s.handleListWorkspaces)))
+	mux.Handle("GET /v1/workspaces/{workspace_id}/members", s.withAuth(http.HandlerFunc(s.handleListWorkspaceMembers)))
+	mux.Handle("PUT /v1/workspaces/{workspace_id}/members/{user_id}", s.withAuth(http.HandlerFunc(s.handleSetWorkspaceMember)))
+	mux.Handle("DELETE /v1/workspaces/{workspace_id}/members/{user_id}", s.withAuth(http.HandlerFunc(s.handleRemoveWorkspaceMember)))
+	mux.Handle("GET /v1/workspaces/{workspace_id}/rule-packs", s.withAuth(http.HandlerFunc(s.handleListRulePacks)))
+	mux.Handle("POST /v1/workspaces/{workspace_id}/rule-packs", s.withAuth(http.HandlerFunc(s.handleSaveRulePack)))
+	mux.Handle("DELETE /v1/workspaces/{workspace_id}/rule-packs/{pack_id}", s.withAuth(http.HandlerFunc(s.handleDeleteRulePack)))
+	mux.Handle("GET /v1/workspaces/{workspace_id}/rule-pack-applications", s.withAuth(http.HandlerFunc(s.handleListRulePackApplications)))
+	mux.Handle("GET /v1/users/{id}/domain-policies", s.withAuth(http.HandlerFunc(s.handleListDomainPolicies)))
+	mux.Handle("POST /v1/users/{id}/domain-policies", s.withAuth(http.HandlerFunc(s.handleCreateDomainPolicy)))
+	mux.Handle("DELETE /v1/users/{id}/domain-policies/{policy_id}", s.withAuth(http.HandlerFunc(s.handleDeleteDomainPolicy)))
+	mux.Handle("GET /v1/users/{id}/notification-preferences", s.withAuth(http.HandlerFunc(s.handleListNotificationPreferences)))
+	mux.Handle("PUT /v1/users/{id}/notification-preferences", s.withAuth(http.HandlerFunc(s.handleUpdateNotificationPreference)))
+	mux.Handle("GET /v1/users/{id}/webhooks", s.withAuth(http.HandlerFunc(s.handleListWebhooks)))
+	mux.Handle("POST /v1/users/{id}/webhooks", s.withAuth(http.HandlerFunc(s.handleCreateWebhook)))
+	mux.Handle("DELETE /v1/users/{id}/webhooks/{webhook_id}", s.withAuth(http.HandlerFunc(s.handleDeleteWebhook)))
+	mux.Handle("GET /v1/users/{id}/webhooks/{webhook_id}/deliveries", s.withAuth(http.HandlerFunc(s.handleListWebhookDeliveries)))
+	mux.Handle("GET /v1/users/{id}/calendar", s.withAuth(http.HandlerFunc(s.handleGetCalendarFeedURL)))
+	mux.Handle("GET /v1/users/{id}/applications", s.withAuth(http.HandlerFunc(s.handleListApplicationTrackers)))
+	mux.Handle("POST /v1/users/{id}/applications", s.withAuth(http.HandlerFunc(s.handleSaveApplicationTracker)))
+	mux.Handle("GET /v1/users/{id}/applications/{tracker_id}", s.withAuth(http.HandlerFunc(s.handleGetApplicationTracker)))
+	mux.Handle("DELETE /v1/users/{id}/applications/{tracker_id}", s.withAuth(http.HandlerFunc(s.handleDeleteApplicationTracker)))
+	mux.Handle("POST /v1/users/{id}/applications/{tracker_id}/contacts", s.withAuth(http.HandlerFunc(s.handleCreateApplicationContact)))
+	mux.Handle("PUT /v1/users/{id}/applications/{tracker_id}/contacts/{contact_id}", s.withAuth(http.HandlerFunc(s.handleUpdateApplicationContact)))
+	mux.Handle("DELETE /v1/users/{id}/applications/{tracker_id}/contacts/{contact_id}", s.withAuth(http.HandlerFunc(s.handleDeleteApplicationContact)))
+	mux.Handle("POST /v1/users/{id}/applications/{tracker_id}/interactions", s.withAuth(http.HandlerFunc(s.handleCreateApplicationInteraction)))
+	mux.Handle("DELETE /v1/users/{id}/applications/{tracker_id}/interactions/{interaction_id}", s.withAuth(http.HandlerFunc(s.handleDeleteApplicationInteraction)))
+	mux.HandleFunc("GET /v1/users/{id}/calendar.ics", s.handleCalendarFeed)
+	mux.Handle("GET /v1/users/{id}/shared-resume", s.withAuth(http.HandlerFunc(s.handleGetSharedResume)))
+	mux.Handle("PUT /v1/users/{id}/shared-resume", s.withAuth(http.HandlerFunc(s.handlePutSharedResume)))
+	mux.Handle("DELETE /v1/users/{id}/shared-resume", s.withAuth(http.HandlerFunc(s.handleDeleteSharedResume)))
+	mux.Handle("GET /v1/users/{id}/shared-resume/qr.png", s.withAuth(http.HandlerFunc(s.handleSharedResumeQR)))
+	mux.Handle("PUT /v1/users/{id}/shared-resume/access", s.withAuth(http.HandlerFunc(s.handleUpdateSharedResumeAccess)))
+	mux.Handle("POST /v1/users/{id}/shared-resume/revoke", s.withAuth(http.HandlerFunc(s.handleRevokeSharedResume)))
+	mux.Handle("GET /v1/users/{id}/shared-resume/comments", s.withAuth(http.HandlerFunc(s.handleListSharedResumeComments)))
+	mux.Handle("GET /v1/users/{id}/shared-resume/access-log", s.withAuth(http.HandlerFunc(s.handleListSharedResumeAccess)))
+	mux.Handle("GET /v1/users/{id}/github", s.withAuth(http.HandlerFunc(s.handleGetGitHubAccount)))
+	mux.Handle("PUT /v1/users/{id}/github", s.withAuth(http.HandlerFunc(s.handlePutGitHubAccount)))
+	mux.Handle("DELETE /v1/users/{id}/github", s.withAuth(http.HandlerFunc(s.handleDeleteGitHubAccount)))
+	mux.Handle("POST /v1/users/{id}/github/sync", s.withAuth(http.HandlerFunc(s.handleSyncGitHub)))
+	mux.Handle("GET /v1/users/{id}/project-drafts", s.withAuth(http.HandlerFunc(s.handleListProjectDrafts)))
+	mux.Handle("POST /v1/users/{id}/project-drafts/{draft_id}/accept", s.withAuth(http.HandlerFunc(s.handleAcceptProjectDraft)))
+	mux.Handle("POST /v1/users/{id}/project-drafts/{draft_id}/dismiss", s.withAuth(http.HandlerFunc(s.handleDismissProjectDraft)))
+	mux.Handle("GET /v1/users/{id}/bullet-suggestions", s.withAuth(http.HandlerFunc(s.handleListBulletSuggestions)))
+	mux.Handle("POST /v1/users/{id}/bullet-suggestions/{suggestion_id}/accept", s.withAuth(http.HandlerFunc(s.handleAcceptBulletSuggestion)))
+	mux.Handle("POST /v1/users/{id}/bullet-suggestions/{suggestion_id}/dismiss", s.withAuth(http.HandlerFunc(s.handleDismissBulletSuggestion)))
+	mux.Handle("GET /v1/users/{id}/onboarding", s.withAuth(http.HandlerFunc(s.handleGetOnboarding)))
+	mux.Handle("POST /v1/users/{id}/onboarding/advance", s.withAuth(http.HandlerFunc(s.handleAdvanceOnboarding)))
+	mux.Handle("POST /v1/users/{id}/onboarding/skip", s.withAuth(http.HandlerFunc(s.handleSkipOnboarding)))
+	mux.Handle("POST /v1/users/{id}/onboarding/back", s.withAuth(http.HandlerFunc(s.handleBackOnboarding)))
+	mux.Handle("GET /v1/users/{id}/skill-assessments", s.withAuth(http.HandlerFunc(s.handleListSkillAssessments)))
+	mux.Handle("PUT /v1/users/{id}/skill-assessments", s.withAuth(http.HandlerFunc(s.handlePutSkillAssessment)))
+	mux.Handle("GET /v1/users/{id}/skill-gap", s.withAuth(http.HandlerFunc(s.handleGetSkillGap)))
+	mux.Handle("GET /v1/users/{id}/resume-health", s.withAuth(http.HandlerFunc(s.handleGetResumeHealth)))
 	mux.HandleFunc("GET /v1/users/{id}/jobs", s.handleListJobs)
 	mux.HandleFunc("POST /v1/users/{id}/jobs", s.handleCreateJob)
 	mux.Handle("GET /v1/users/{id}/runs", s.withAuth(http.HandlerFunc(s.handleListUserRuns)))
@@ -249,6 +751,18 @@ func New(cfg Config) (*Server, error) {
 	mux.HandleFunc("GET /v1/users/{id}/experience-bank/stories", s.handleListStories)
 	mux.HandleFunc("GET /v1/users/{id}/experience-bank/stories/{story_id}", s.handleGetStory)
 	mux.HandleFunc("GET /v1/users/{id}/experience-bank/stories/{story_id}/bullets", s.handleGetStoryBullets)
+	mux.Handle("POST /v1/users/{id}/experience-bank/stories", s.withAuth(http.HandlerFunc(s.handleCreateStory)))
+	mux.Handle("POST /v1/users/{id}/experience-bank/star", s.withAuth(http.HandlerFunc(s.handleStarInterview)))
+	mux.Handle("POST /v1/users/{id}/experience/import", s.withAuth(http.HandlerFunc(s.handleImportExperience)))
+	mux.Handle("POST /v1/users/{id}/experience-bank/draft", s.withAuth(http.HandlerFunc(s.handleDraftExperienceBank)))
+	mux.Handle("POST /v1/users/{id}/experience-bank/import", s.withAuth(http.HandlerFunc(s.handleImportExperienceBank)))
+	mux.Handle("GET /v1/users/{id}/experience/export", s.withAuth(http.HandlerFunc(s.handleExportExperience)))
+	mux.Handle("GET /v1/users/{id}/export", s.withAuth(http.HandlerFunc(s.handleExportAccount)))
+	mux.Handle("GET /v1/users/{id}/voice-notes", s.withAuth(http.HandlerFunc(s.handleListVoiceNotes)))
+	mux.Handle("POST /v1/users/{id}/voice-notes", s.withAuth(http.HandlerFunc(s.handleUploadVoiceNote)))
+	mux.Handle("DELETE /v1/users/{id}/voice-notes/{note_id}", s.withAuth(http.HandlerFunc(s.handleDeleteVoiceNote)))
+	mux.Handle("GET /v1/users/{id}/voice-notes/webhook", s.withAuth(http.HandlerFunc(s.handleGetVoiceNoteWebhookURL)))
+	mux.HandleFunc("POST /v1/users/{id}/voice-notes/webhook", s.handleVoiceNoteWebhook)
 	mux.HandleFunc("GET /v1/users/{id}/experience-bank/skills", s.handleListSkills)
 	mux.HandleFunc("GET /v1/users/{id}/experience-bank/skills/{skill_id}/bullets", s.handleGetSkillBullets)
 
@@ -261,19 +775,26 @@ func New(cfg Config) (*Server, error) {
 	mux.HandleFunc("GET /v1/companies/by-name", s.handleGetCompanyByName) // Changed to use query parameter
 	mux.HandleFunc("GET /v1/companies/{id}", s.handleGetCompany)
 	mux.HandleFunc("GET /v1/companies/{id}/domains", s.handleListCompanyDomains)
+	mux.HandleFunc("GET /v1/companies/{id}/similar", s.handleListSimilarCompanies)
 
 	// Company profiles endpoints
 	mux.HandleFunc("GET /v1/companies/{company_id}/profile", s.handleGetCompanyProfile)
+	mux.Handle("POST /v1/companies/{company_id}/profile", s.withAuth(http.HandlerFunc(s.handleCreateCompanyProfile)))
 	mux.HandleFunc("GET /v1/companies/{company_id}/profile/style-rules", s.handleGetStyleRules)
 	mux.HandleFunc("GET /v1/companies/{company_id}/profile/taboo-phrases", s.handleGetTabooPhrases)
 	mux.HandleFunc("GET /v1/companies/{company_id}/profile/values", s.handleGetValues)
 	mux.HandleFunc("GET /v1/companies/{company_id}/profile/sources", s.handleGetSources)
+	mux.HandleFunc("GET /v1/companies/{company_id}/profile/versions", s.handleListCompanyProfileVersions)
+	mux.HandleFunc("GET /v1/companies/{company_id}/profile/diff", s.handleDiffCompanyProfileVersions)
 
 	// Job Postings endpoints
 	mux.HandleFunc("GET /v1/job-postings", s.handleListJobPostings)
 	mux.HandleFunc("GET /v1/job-postings/{id}", s.handleGetJobPosting)
 	mux.HandleFunc("GET /v1/job-postings/by-url", s.handleGetJobPostingByURL)
+	mux.HandleFunc("GET /v1/job-postings/{id}/duplicates", s.handleListDuplicatePostings)
+	mux.HandleFunc("GET /v1/job-postings/{id}/versions", s.handleListPostingVersions)
 	mux.HandleFunc("GET /v1/companies/{company_id}/job-postings", s.handleListJobPostingsByCompany)
+	mux.HandleFunc("GET /v1/companies/{company_id}/posting-trends", s.handleGetPostingTrends)
 
 	// Job Profiles endpoints
 	mux.HandleFunc("GET /v1/job-profiles/{id}", s.handleGetJobProfile)
@@ -281,22 +802,35 @@ func New(cfg Config) (*Server, error) {
 	mux.HandleFunc("GET /v1/job-profiles/{id}/requirements", s.handleGetRequirements)
 	mux.HandleFunc("GET /v1/job-profiles/{id}/responsibilities", s.handleGetResponsibilities)
 	mux.HandleFunc("GET /v1/job-profiles/{id}/keywords", s.handleGetKeywords)
+	mux.Handle("POST /v1/job-profiles/{id}/keywords", s.withAuth(http.HandlerFunc(s.handleAddKeyword)))
+	mux.Handle("DELETE /v1/job-profiles/{id}/keywords/{keyword}", s.withAuth(http.HandlerFunc(s.handleRemoveKeyword)))
+	mux.Handle("PATCH /v1/job-requirements/{id}", s.withAuth(http.HandlerFunc(s.handleUpdateRequirementWeight)))
 
 	// Crawled Pages endpoints
 	mux.HandleFunc("GET /v1/crawled-pages/{id}", s.handleGetCrawledPage)
 	mux.HandleFunc("GET /v1/crawled-pages/by-url", s.handleGetCrawledPageByURL)
 	mux.HandleFunc("GET /v1/companies/{company_id}/crawled-pages", s.handleListCrawledPagesByCompany)
 
-	// Create HTTP server
-	s.httpServer = &http.Server{
-		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		Handler:      s.withRateLimit(s.withLogging(s.withCORS(mux))),
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 300 * time.Second, // Long timeout for pipeline runs
-		IdleTimeout:  60 * time.Second,
-	}
-
-	return s, nil
+	// Resume templates
+	mux.Handle("GET /v1/templates", s.withAuth(http.HandlerFunc(s.handleListTemplates)))
+	mux.Handle("POST /v1/templates", s.withAuth(http.HandlerFunc(s.handleUploadTemplate)))
+	mux.Handle("POST /v1/templates/lint", s.withAuth(http.HandlerFunc(s.handleLintTemplate)))
+
+	// Tools that work on a posting without creating a run
+	mux.Handle("POST /v1/tools/summarize-posting", s.withAuth(http.HandlerFunc(s.handleSummarizePosting)))
+	mux.Handle("POST /v1/tools/rewrite-bullet", s.withAuth(http.HandlerFunc(s.handleRewriteBullet)))
+
+	// Domain crawl policies (global; admins manage, any authenticated user may list)
+	mux.Handle("GET /v1/domain-policies", s.withAuth(http.HandlerFunc(s.handleListDomainPolicies)))
+	mux.Handle("POST /v1/domain-policies", s.withAuth(http.HandlerFunc(s.handleCreateDomainPolicy)))
+	mux.Handle("DELETE /v1/domain-policies/{policy_id}", s.withAuth(http.HandlerFunc(s.handleDeleteDomainPolicy)))
+
+	// API document generated from the routes above, and its Swagger UI page
+	mux.HandleFunc("GET /v1/openapi.json", s.handleOpenAPI)
+	docs := apiDocsHandler()
+	mux.Handle("GET /docs", docs)
+	mux.Handle("GET /docs/", docs)
+	return mux
 }
 
 // Start begins listening for requests
@@ -305,15 +839,39 @@ func (s *Server) Start() error {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
+	// Background workers: debug artifact retention, abandoned run cleanup, cross-replica run
+	// events, notifications, webhooks, dead letter alerting, and queued run execution
+	bgCtx, cancelBackground := context.WithCancel(context.Background())
+	defer cancelBackground()
+	go s.runDebugArtifactCleanup(bgCtx)
+	go s.runRunGC(bgCtx)
+	go s.events.Run(bgCtx)
+	go s.runCompletionNotifier(bgCtx)
+	go s.runScheduledNotifications(bgCtx)
+	go s.runWebhookDispatcher(bgCtx)
+	go s.runWebhookDeliveries(bgCtx)
+	go s.runDeadLetterMonitor(bgCtx)
+	var runWorkersDone sync.WaitGroup
+	if s.runWorkers != nil {
+		runWorkersDone.Add(1)
+		go func() {
+			defer runWorkersDone.Done()
+			if err := s.runWorkers.Run(bgCtx); err != nil {
+				slog.Warn("run workers stopped", "error", err)
+			}
+		}()
+	}
+
 	go func() {
-		log.Printf("Server starting on %s", s.httpServer.Addr)
+		slog.Info("Server starting", "addr", s.httpServer.Addr)
 		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", err)
+			slog.Error("Server error", "error", err)
+			os.Exit(1)
 		}
 	}()
 
 	<-stop
-	log.Println("Shutting down server...")
+	slog.Info("Shutting down server...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -322,13 +880,17 @@ func (s *Server) Start() error {
 		return fmt.Errorf("server shutdown failed: %w", err)
 	}
 
+	// Interrupted queued runs go back to the queue before the database is closed
+	cancelBackground()
+	runWorkersDone.Wait()
+
 	// Stop rate limiter cleanup goroutine
 	if s.rateLimiter != nil {
 		s.rateLimiter.Stop()
 	}
 
 	s.db.Close()
-	log.Println("Server stopped")
+	slog.Info("Server stopped")
 	return nil
 }
 
@@ -337,7 +899,8 @@ func (s *Server) withCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Range, If-Range, X-Request-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "Content-Range, Accept-Ranges, ETag, X-Request-ID")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		if r.Method == "OPTIONS" {
@@ -372,19 +935,10 @@ func (s *Server) withRateLimit(next http.Handler) http.Handler {
 	})
 }
 
-// withLogging adds request logging
+// withLogging logs each request by its route pattern, leaving out bodies, credentials,
+// and query values DefaultLogPolicy does not allow
 func (s *Server) withLogging(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		log.Printf("[%s] %s %s", r.Method, r.URL.Path, r.RemoteAddr)
-		next.ServeHTTP(w, r)
-		log.Printf("[%s] %s completed in %v", r.Method, r.URL.Path, time.Since(start))
-	})
-}
-
-// withAuth adds authentication middleware
-func (s *Server) withAuth(next http.Handler) http.Handler {
-	return middleware.AuthMiddleware(s.jwtService.AsTokenValidator())(next)
+	return middleware.RequestLogger(slog.Default(), middleware.DefaultLogPolicy)(next)
 }
 
 // handleHealth returns server health status
@@ -397,13 +951,14 @@ func (s *Server) jsonResponse(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("Error encoding JSON response: %v", err)
+		slog.Error("Failed to encode JSON response", "error", err)
 	}
 }
 
-// errorResponse writes an error JSON response
+// errorResponse writes an error JSON response. Messages often carry errors from the db
+// layer, so credentials, tokens, emails, and echoed key values are scrubbed from them.
 func (s *Server) errorResponse(w http.ResponseWriter, status int, message string) {
-	s.jsonResponse(w, status, map[string]string{"error": message})
+	s.jsonResponse(w, status, map[string]string{"error": redact.Error(message)})
 }
 
 // handleRegister handles user registration requests.
@@ -422,6 +977,22 @@ func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 	s.authHandler.Login(w, r)
 }
 
+// handleRefreshToken handles access token refresh requests.
+// It is used by the router in Server.New() via mux.HandleFunc.
+//
+//nolint:unused // Used via function reference in router setup
+func (s *Server) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
+	s.authHandler.Refresh(w, r)
+}
+
+// handleLogout handles logout requests.
+// It is used by the router in Server.New() via mux.HandleFunc.
+//
+//nolint:unused // Used via function reference in router setup
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	s.authHandler.Logout(w, r)
+}
+
 // handleUpdateUserPassword handles password update requests for a specific user ID.
 // It verifies the authenticated user matches the user ID in the path parameter.
 //
@@ -480,7 +1051,7 @@ func (s *Server) rateLimitResponse(w http.ResponseWriter, info ratelimit.Info) {
 		"message":   "Rate limit exceeded. Please try again later.",
 		"limit":     info.Limit,
 		"remaining": info.Remaining,
-		"reset_at":  info.ResetTime.Format(time.RFC3339),
+		"reset_at":  formatTimestamp(info.ResetTime),
 	}
 
 	if info.RetryAfter > 0 {
@@ -489,8 +1060,7 @@ func (s *Server) rateLimitResponse(w http.ResponseWriter, info ratelimit.Info) {
 	}
 
 	// Log rate limit hit
-	log.Printf("[rate-limit] Rate limit exceeded: Limit=%d Remaining=%d Reset=%s",
-		info.Limit, info.Remaining, info.ResetTime.Format(time.RFC3339))
+	slog.Warn("Rate limit exceeded", "limit", info.Limit, "remaining", info.Remaining, "reset_at", formatTimestamp(info.ResetTime))
 
 	s.jsonResponse(w, http.StatusTooManyRequests, response)
 }
@@ -5,24 +5,88 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jonathan/resume-customizer/internal/compile"
 	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+	"github.com/jonathan/resume-customizer/internal/scheduler"
 	"github.com/jonathan/resume-customizer/internal/server/ratelimit"
 	"github.com/jonathan/resume-customizer/internal/types"
 )
 
 // mockDB implements a minimal mock for testing
 type mockDB struct {
-	runs          map[uuid.UUID]*db.Run
-	artifacts     map[uuid.UUID]*db.Artifact
-	textArtifacts map[string]string // key: "runID:step", value: text content
+	runs                map[uuid.UUID]*db.Run
+	artifacts           map[uuid.UUID]*db.Artifact
+	textArtifacts       map[string]string                    // key: "runID:step", value: text content
+	binArtifacts        map[string][]byte                    // key: "runID:step"
+	skillGaps           map[uuid.UUID]*db.SkillGap           // key: posting ID
+	healthTargets       map[uuid.UUID][]db.TargetRequirement // keyed by user ID
+	healthBullets       map[uuid.UUID][]db.EvidenceBullet    // keyed by user ID
+	rankingConfigs      map[uuid.UUID]json.RawMessage
+	resumeTemplates     map[uuid.UUID][]db.ResumeTemplate
+	runSteps            map[uuid.UUID][]db.RunStep
+	domainPolicies      []db.DomainPolicy
+	fingerprints        []db.CompanyFingerprint
+	notifyPrefs         []db.NotificationPreference
+	notifyClaims        map[string]bool // "eventType:refID" of claimed per-run notifications
+	deadLetters         []*db.DeadLetter
+	requeuedJobs        map[uuid.UUID]bool // step jobs RequeueStepJob accepts
+	webhooks            []*db.Webhook
+	webhookLog          []db.WebhookDelivery
+	runJobs             map[uuid.UUID]*db.RunJob       // keyed by run ID
+	sharedResumes       map[uuid.UUID]*db.SharedResume // keyed by user ID
+	sharedViews         map[uuid.UUID][]string         // view kinds recorded per shared resume ID
+	sharedComments      []db.SharedResumeComment
+	sharedAccess        []db.SharedResumeAccessInput
+	jobs                []db.Job
+	experiences         []db.Experience
+	education           []db.Education
+	userSkills          map[uuid.UUID][]db.UserSkill    // keyed by user ID
+	githubAccounts      map[uuid.UUID]*db.GitHubAccount // keyed by user ID
+	projectDrafts       []*db.ProjectDraft
+	suggestions         []*db.BulletSuggestion
+	createdStories      []*db.StoryCreateInput
+	importedBanks       []*db.ExperienceBankImportInput
+	voiceNotes          []*db.VoiceNote
+	presets             []*db.CompanyPreference
+	recipes             []*db.RunRecipe
+	trackers            []*db.ApplicationTracker
+	contacts            []*db.ApplicationContact
+	interactions        []*db.ApplicationInteraction
+	workspaces          []*db.Workspace
+	members             []db.WorkspaceMember
+	rulePacks           []*db.RulePack
+	users               map[uuid.UUID]*db.User
+	onboarding          map[uuid.UUID]*db.Onboarding // keyed by user ID
+	runGCMarked         int64                        // Runs MarkAbandonedRuns reports marking
+	runGCReclaimed      *db.ReclaimedRuns            // Rows DeleteAbandonedRuns reports deleting
+	runGCErr            error
+	storage             *db.ArtifactStorage         // What GetArtifactStorage reports
+	latencyStats        []db.LatencyStats           // Rows ListLatencyStats returns
+	latencyBudgets      map[string]int64            // Budgets ListLatencyStats was last called with
+	refreshTokens       map[string]*db.RefreshToken // keyed by token hash
+	requirements        map[uuid.UUID]*db.JobRequirement
+	reqWeights          map[string]float64 // keyed by "userID:requirementID"
+	jobProfiles         map[uuid.UUID]*db.JobProfile
+	keywordChanges      map[string]db.KeywordOverride // keyed by "profileID:userID:normalized keyword"
+	verifications       []*db.EmailVerification
+	postings            map[uuid.UUID]*db.JobPosting
+	versions            []db.PostingVersion        // Archived and current posting versions
+	profileVersions     []db.CompanyProfileVersion // newest first
+	crawledPages        []db.CrawledPage
+	idempotencyKeys     map[string]*db.IdempotencyKey       // keyed by "userID:key"
+	userCompanyProfiles map[[2]uuid.UUID]*db.CompanyProfile // keyed by user and company ID
 }
 
 func newMockDB() *mockDB {
@@ -30,6 +94,7 @@ func newMockDB() *mockDB {
 		runs:          make(map[uuid.UUID]*db.Run),
 		artifacts:     make(map[uuid.UUID]*db.Artifact),
 		textArtifacts: make(map[string]string),
+		runSteps:      make(map[uuid.UUID][]db.RunStep),
 	}
 }
 
@@ -49,6 +114,41 @@ func (m *mockDB) GetArtifactByID(_ context.Context, artifactID uuid.UUID) (*db.A
 	return artifact, nil
 }
 
+// GetArtifact returns the JSON content of the latest version of a run's artifact
+func (m *mockDB) GetArtifact(_ context.Context, runID uuid.UUID, step string) ([]byte, error) {
+	var latest *db.Artifact
+	for _, artifact := range m.artifacts {
+		if artifact.RunID == runID && artifact.Step == step && artifact.Content != nil &&
+			(latest == nil || artifact.Version > latest.Version) {
+			latest = artifact
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	return json.Marshal(latest.Content)
+}
+
+func (m *mockDB) ListArtifactVersions(_ context.Context, runID uuid.UUID, step string) ([]db.ArtifactVersion, error) {
+	versions := []db.ArtifactVersion{}
+	for _, a := range m.artifacts {
+		if a.RunID == runID && a.Step == step {
+			versions = append(versions, db.ArtifactVersion{ID: a.ID, Version: a.Version, Category: a.Category})
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
+func (m *mockDB) GetArtifactVersion(_ context.Context, runID uuid.UUID, step string, version int) (*db.Artifact, error) {
+	for _, a := range m.artifacts {
+		if a.RunID == runID && a.Step == step && a.Version == version {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
 func (m *mockDB) GetTextArtifact(_ context.Context, runID uuid.UUID, step string) (string, error) {
 	key := runID.String() + ":" + step
 	content, ok := m.textArtifacts[key]
@@ -67,85 +167,1382 @@ func (m *mockDB) SaveTextArtifact(_ context.Context, runID uuid.UUID, step, _ st
 	return nil
 }
 
+func (m *mockDB) GetBinaryArtifact(_ context.Context, runID uuid.UUID, step string) ([]byte, error) {
+	return m.binArtifacts[runID.String()+":"+step], nil
+}
+
+func (m *mockDB) SaveBinaryArtifact(_ context.Context, runID uuid.UUID, step, _ string, data []byte) error {
+	if m.binArtifacts == nil {
+		m.binArtifacts = make(map[string][]byte)
+	}
+	m.binArtifacts[runID.String()+":"+step] = data
+	return nil
+}
+
+// OpenArtifactContent serves artifacts by ID from their JSON or text content
+func (m *mockDB) OpenArtifactContent(_ context.Context, artifactID uuid.UUID) (*db.ArtifactContent, error) {
+	artifact, ok := m.artifacts[artifactID]
+	if !ok {
+		return nil, nil
+	}
+	format, data := db.ContentFormatText, []byte(artifact.TextContent)
+	if artifact.Content != nil {
+		format = db.ContentFormatJSON
+		data, _ = json.Marshal(artifact.Content)
+	}
+	return mockArtifactContent(artifact.ID, artifact.RunID, artifact.Step, artifact.Category, format, data), nil
+}
+
+// OpenRunArtifactContent serves a run's binary, then text, artifact for a step
+func (m *mockDB) OpenRunArtifactContent(_ context.Context, runID uuid.UUID, step string) (*db.ArtifactContent, error) {
+	key := runID.String() + ":" + step
+	if data, ok := m.binArtifacts[key]; ok {
+		return mockArtifactContent(uuid.New(), runID, step, "", db.ContentFormatBinary, data), nil
+	}
+	if text, ok := m.textArtifacts[key]; ok {
+		return mockArtifactContent(uuid.New(), runID, step, "", db.ContentFormatText, []byte(text)), nil
+	}
+	return nil, nil
+}
+
+func mockArtifactContent(id, runID uuid.UUID, step, category, format string, data []byte) *db.ArtifactContent {
+	return &db.ArtifactContent{
+		ID: id, RunID: runID, Step: step, Category: category, Format: format, Size: int64(len(data)),
+		CreatedAt:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		SectionReader: io.NewSectionReader(bytes.NewReader(data), 0, int64(len(data))),
+	}
+}
+
 func (m *mockDB) Close() {}
 
 // Stub implementations for all other DBClient interface methods
 // These return zero values or errors as appropriate for unit tests
 
-func (m *mockDB) CreateRun(_ context.Context, _, _, _ string) (uuid.UUID, error) {
-	return uuid.New(), nil
+func (m *mockDB) CreateRun(_ context.Context, company, roleTitle, jobURL string) (uuid.UUID, error) {
+	id := uuid.New()
+	m.runs[id] = &db.Run{ID: id, Company: company, RoleTitle: roleTitle, JobURL: jobURL, Status: "running"}
+	return id, nil
+}
+
+func (m *mockDB) CreateQueuedRun(_ context.Context, jobURL string) (uuid.UUID, error) {
+	id := uuid.New()
+	m.runs[id] = &db.Run{ID: id, JobURL: jobURL, Status: "queued"}
+	return id, nil
+}
+
+func (m *mockDB) SetRunUserID(_ context.Context, runID, userID uuid.UUID) error {
+	if run, ok := m.runs[runID]; ok {
+		run.UserID = &userID
+	}
+	return nil
+}
+
+func (m *mockDB) SetRunMaxPages(_ context.Context, runID uuid.UUID, maxPages int) error {
+	if run, ok := m.runs[runID]; ok {
+		run.MaxPages = &maxPages
+	}
+	return nil
+}
+
+func (m *mockDB) SetRunSource(_ context.Context, runID uuid.UUID, runType string, sourceRunID uuid.UUID) error {
+	run, source := m.runs[runID], m.runs[sourceRunID]
+	if run != nil && source != nil {
+		run.RunType, run.SourceRunID = runType, &sourceRunID
+		run.Company, run.RoleTitle, run.JobURL = source.Company, source.RoleTitle, source.JobURL
+	}
+	return nil
+}
+
+func (m *mockDB) GetLatestResumeRun(_ context.Context, userID uuid.UUID) (*db.Run, error) {
+	var latest *db.Run
+	for _, run := range m.runs {
+		if run.UserID == nil || *run.UserID != userID || (latest != nil && !run.CreatedAt.After(latest.CreatedAt)) {
+			continue
+		}
+		if _, ok := m.textArtifacts[run.ID.String()+":"+db.StepResumeTex]; ok {
+			latest = run
+		}
+	}
+	return latest, nil
+}
+
+func (m *mockDB) CopyRunArtifacts(_ context.Context, fromRunID, toRunID uuid.UUID, steps []string) ([]string, error) {
+	var copied []string
+	for _, artifact := range m.artifacts {
+		if artifact.RunID == fromRunID && slices.Contains(steps, artifact.Step) && !slices.Contains(copied, artifact.Step) {
+			cp := *artifact
+			cp.ID, cp.RunID, cp.Version = uuid.New(), toRunID, 1
+			m.artifacts[cp.ID] = &cp
+			copied = append(copied, artifact.Step)
+		}
+	}
+	return copied, nil
+}
+
+func (m *mockDB) EnqueueRunJob(_ context.Context, runID, userID uuid.UUID, priority int, payload any) (uuid.UUID, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if m.runJobs == nil {
+		m.runJobs = make(map[uuid.UUID]*db.RunJob)
+	}
+	job := &db.RunJob{ID: uuid.New(), RunID: runID, UserID: &userID, Payload: data, Priority: priority,
+		Status: db.RunJobStatusQueued, CreatedAt: time.Now()}
+	m.runJobs[runID] = job
+	return job.ID, nil
+}
+
+func (m *mockDB) CountQueuedRunJobs(_ context.Context, userID uuid.UUID) (int, error) {
+	n := 0
+	for _, job := range m.runJobs {
+		if job.UserID != nil && *job.UserID == userID && job.Status == db.RunJobStatusQueued {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *mockDB) GetRunJobByRun(_ context.Context, runID uuid.UUID) (*db.RunJob, error) {
+	return m.runJobs[runID], nil
+}
+
+func (m *mockDB) StartRun(_ context.Context, runID uuid.UUID) error {
+	if run, ok := m.runs[runID]; ok && run.Status == "queued" {
+		run.Status = "running"
+	}
+	return nil
+}
+
+func (m *mockDB) CompleteRun(_ context.Context, runID uuid.UUID, status string) error {
+	if run, ok := m.runs[runID]; ok {
+		run.Status = status
+	}
+	return nil
 }
 
-func (m *mockDB) ListRunsFiltered(_ context.Context, _ db.RunFilters) ([]db.Run, error) {
-	return []db.Run{}, nil
+func (m *mockDB) ListRunsFiltered(_ context.Context, filters db.RunFilters) ([]db.Run, error) {
+	runs := []db.Run{}
+	for _, run := range m.runs {
+		if filters.UserID != nil && (run.UserID == nil || *run.UserID != *filters.UserID) {
+			continue
+		}
+		if filters.Status != "" && run.Status != filters.Status {
+			continue
+		}
+		runs = append(runs, *run)
+	}
+	return runs, nil
 }
 
 func (m *mockDB) DeleteRun(_ context.Context, _ uuid.UUID) error {
 	return nil
 }
 
-func (m *mockDB) ListArtifacts(_ context.Context, _ db.ArtifactFilters) ([]db.ArtifactSummary, error) {
-	return []db.ArtifactSummary{}, nil
+func (m *mockDB) CountUserRunsSince(_ context.Context, userID uuid.UUID, since time.Time) (int, error) {
+	count := 0
+	for _, run := range m.runs {
+		if run.UserID != nil && *run.UserID == userID && !run.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockDB) ListArtifacts(_ context.Context, filters db.ArtifactFilters) ([]db.ArtifactSummary, error) {
+	summaries := []db.ArtifactSummary{}
+	for _, a := range m.artifacts {
+		if a.RunID == filters.RunID {
+			summaries = append(summaries, db.ArtifactSummary{ID: a.ID, Step: a.Step, Category: a.Category})
+		}
+	}
+	return summaries, nil
+}
+
+func (m *mockDB) GetRunStep(_ context.Context, _ uuid.UUID, _ string) (*db.RunStep, error) {
+	return nil, nil
+}
+
+func (m *mockDB) ListRunSteps(_ context.Context, runID uuid.UUID, _, _ *string) ([]db.RunStep, error) {
+	if stepList, ok := m.runSteps[runID]; ok {
+		return stepList, nil
+	}
+	return []db.RunStep{}, nil
+}
+
+func (m *mockDB) CreateRunStep(_ context.Context, runID uuid.UUID, input *db.RunStepInput) (*db.RunStep, error) {
+	step := db.RunStep{ID: uuid.New(), RunID: runID, Step: input.Step, Category: input.Category,
+		Status: input.Status, Parameters: input.Parameters}
+	m.runSteps[runID] = append(m.runSteps[runID], step)
+	return &step, nil
+}
+
+func (m *mockDB) UpdateRunStepStatus(_ context.Context, runID uuid.UUID, stepName string, status string, errorMsg *string, _ *uuid.UUID) error {
+	for i := range m.runSteps[runID] {
+		if m.runSteps[runID][i].Step == stepName {
+			m.runSteps[runID][i].Status = status
+			m.runSteps[runID][i].ErrorMessage = errorMsg
+		}
+	}
+	return nil
+}
+
+func (m *mockDB) UpdateRunStepParameters(_ context.Context, runID uuid.UUID, stepName string, params map[string]interface{}) error {
+	for i := range m.runSteps[runID] {
+		if m.runSteps[runID][i].Step == stepName {
+			m.runSteps[runID][i].Parameters = params
+			return nil
+		}
+	}
+	return fmt.Errorf("step not found: %s", stepName)
+}
+
+func (m *mockDB) GetRunCheckpoint(_ context.Context, _ uuid.UUID) (*db.RunCheckpoint, error) {
+	return nil, nil
+}
+
+func (m *mockDB) CreateRunCheckpoint(_ context.Context, _ uuid.UUID, _ *db.RunCheckpointInput) (*db.RunCheckpoint, error) {
+	return nil, nil
+}
+
+func (m *mockDB) GetUser(_ context.Context, id uuid.UUID) (*db.User, error) {
+	return m.users[id], nil
+}
+
+func (m *mockDB) SetUserRedactPII(_ context.Context, _ uuid.UUID, _ bool) error {
+	return nil
+}
+
+func (m *mockDB) SetUserTimezone(_ context.Context, userID uuid.UUID, timezone string) error {
+	if u := m.users[userID]; u != nil {
+		u.Timezone = timezone
+	}
+	return nil
+}
+
+func (m *mockDB) GetUserRankingConfig(_ context.Context, userID uuid.UUID) (json.RawMessage, error) {
+	return m.rankingConfigs[userID], nil
+}
+
+func (m *mockDB) SetUserRankingConfig(_ context.Context, userID uuid.UUID, config json.RawMessage) error {
+	if m.rankingConfigs == nil {
+		m.rankingConfigs = make(map[uuid.UUID]json.RawMessage)
+	}
+	if config == nil {
+		delete(m.rankingConfigs, userID)
+		return nil
+	}
+	m.rankingConfigs[userID] = config
+	return nil
+}
+
+func (m *mockDB) UpsertDomainPolicy(_ context.Context, input *db.DomainPolicyInput) (*db.DomainPolicy, error) {
+	domain, err := db.NormalizePolicyDomain(input.Domain)
+	if err != nil {
+		return nil, err
+	}
+	p := db.DomainPolicy{ID: uuid.New(), UserID: input.UserID, Domain: domain, Action: input.Action, CreatedAt: time.Now()}
+	m.domainPolicies = append(m.domainPolicies, p)
+	return &p, nil
+}
+
+func (m *mockDB) ListDomainPolicies(_ context.Context, userID *uuid.UUID) ([]db.DomainPolicy, error) {
+	var policies []db.DomainPolicy
+	for _, p := range m.domainPolicies {
+		if p.UserID == nil || (userID != nil && *p.UserID == *userID) {
+			policies = append(policies, p)
+		}
+	}
+	return policies, nil
+}
+
+func (m *mockDB) DeleteDomainPolicy(_ context.Context, id uuid.UUID, userID *uuid.UUID) error {
+	for i, p := range m.domainPolicies {
+		sameOwner := (p.UserID == nil && userID == nil) || (p.UserID != nil && userID != nil && *p.UserID == *userID)
+		if p.ID == id && sameOwner {
+			m.domainPolicies = append(m.domainPolicies[:i], m.domainPolicies[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("domain policy not found: %s", id)
+}
+
+func (m *mockDB) UpsertNotificationPreference(_ context.Context, input *db.NotificationPreferenceInput) (*db.NotificationPreference, error) {
+	pref := db.NotificationPreference{
+		UserID:    input.UserID,
+		EventType: input.EventType,
+		Channel:   input.Channel,
+		UpdatedAt: time.Now(),
+	}
+	if input.WebhookURL != "" {
+		pref.WebhookURL = &input.WebhookURL
+	}
+	for i, p := range m.notifyPrefs {
+		if p.UserID == input.UserID && p.EventType == input.EventType {
+			m.notifyPrefs[i] = pref
+			return &pref, nil
+		}
+	}
+	m.notifyPrefs = append(m.notifyPrefs, pref)
+	return &pref, nil
+}
+
+func (m *mockDB) ListNotificationPreferences(_ context.Context, userID uuid.UUID) ([]db.NotificationPreference, error) {
+	var prefs []db.NotificationPreference
+	for _, p := range m.notifyPrefs {
+		if p.UserID == userID {
+			prefs = append(prefs, p)
+		}
+	}
+	return prefs, nil
+}
+
+func (m *mockDB) GetNotificationRecipient(_ context.Context, userID uuid.UUID, eventType string) (*db.NotificationRecipient, error) {
+	for _, p := range m.notifyPrefs {
+		if p.UserID == userID && p.EventType == eventType && p.Channel != db.NotificationNone {
+			return m.recipient(p), nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) ListDueDigestRecipients(_ context.Context, eventType string, interval time.Duration) ([]db.NotificationRecipient, error) {
+	var recipients []db.NotificationRecipient
+	for _, p := range m.notifyPrefs {
+		if p.EventType == eventType && p.Channel != db.NotificationNone &&
+			(p.LastSentAt == nil || time.Since(*p.LastSentAt) >= interval) {
+			recipients = append(recipients, *m.recipient(p))
+		}
+	}
+	return recipients, nil
+}
+
+func (m *mockDB) recipient(p db.NotificationPreference) *db.NotificationRecipient {
+	return &db.NotificationRecipient{UserID: p.UserID, Channel: p.Channel, WebhookURL: p.WebhookURL, LastSentAt: p.LastSentAt}
+}
+
+func (m *mockDB) ClaimDigest(_ context.Context, eventType string, userID uuid.UUID, interval time.Duration, at time.Time) (bool, error) {
+	for i, p := range m.notifyPrefs {
+		if p.UserID == userID && p.EventType == eventType {
+			if p.LastSentAt != nil && at.Sub(*p.LastSentAt) < interval {
+				return false, nil
+			}
+			m.notifyPrefs[i].LastSentAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockDB) ClaimNotification(_ context.Context, eventType string, refID, _ uuid.UUID) (bool, error) {
+	if m.notifyClaims == nil {
+		m.notifyClaims = make(map[string]bool)
+	}
+	key := eventType + ":" + refID.String()
+	if m.notifyClaims[key] {
+		return false, nil
+	}
+	m.notifyClaims[key] = true
+	return true, nil
+}
+
+func (m *mockDB) RecordDeadLetter(_ context.Context, input *db.DeadLetterInput) (*db.DeadLetter, error) {
+	payload, err := json.Marshal(input.Payload)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, letter := range m.deadLetters {
+		if letter.Source == input.Source && letter.RefKey == input.RefKey {
+			letter.Payload = payload
+			letter.ErrorMessage = input.ErrorMessage
+			letter.Attempts++
+			letter.Status = db.DeadLetterPending
+			letter.LastFailedAt = now
+			letter.ResolvedAt = nil
+			return letter, nil
+		}
+	}
+	letter := &db.DeadLetter{
+		ID: uuid.New(), Source: input.Source, RefKey: input.RefKey, UserID: input.UserID, RunID: input.RunID,
+		Payload: payload, ErrorMessage: input.ErrorMessage, Attempts: 1, Status: db.DeadLetterPending,
+		CreatedAt: now, LastFailedAt: now,
+	}
+	m.deadLetters = append(m.deadLetters, letter)
+	return letter, nil
+}
+
+func (m *mockDB) GetDeadLetter(_ context.Context, id uuid.UUID) (*db.DeadLetter, error) {
+	for _, letter := range m.deadLetters {
+		if letter.ID == id {
+			return letter, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) ListDeadLetters(_ context.Context, filters db.DeadLetterFilters) ([]db.DeadLetter, error) {
+	out := []db.DeadLetter{}
+	for _, letter := range m.deadLetters {
+		if (filters.Source == "" || letter.Source == filters.Source) && (filters.Status == "" || letter.Status == filters.Status) {
+			out = append(out, *letter)
+		}
+		if filters.Limit > 0 && len(out) == filters.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (m *mockDB) CountPendingDeadLetters(_ context.Context) (map[string]int, error) {
+	counts := make(map[string]int, len(db.DeadLetterSources))
+	for _, source := range db.DeadLetterSources {
+		counts[source] = 0
+	}
+	for _, letter := range m.deadLetters {
+		if letter.Status == db.DeadLetterPending {
+			counts[letter.Source]++
+		}
+	}
+	return counts, nil
+}
+
+func (m *mockDB) ResolveDeadLetter(_ context.Context, id uuid.UUID, status string) (*db.DeadLetter, error) {
+	for _, letter := range m.deadLetters {
+		if letter.ID == id && letter.Status == db.DeadLetterPending {
+			now := time.Now()
+			letter.Status = status
+			letter.ResolvedAt = &now
+			return letter, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) RequeueStepJob(_ context.Context, id uuid.UUID) (bool, error) {
+	return m.requeuedJobs[id], nil
+}
+
+func (m *mockDB) CreateWebhook(_ context.Context, input *db.WebhookInput) (*db.Webhook, error) {
+	hook := &db.Webhook{ID: uuid.New(), UserID: input.UserID, URL: input.URL, Secret: input.Secret, Events: input.Events, CreatedAt: time.Now()}
+	m.webhooks = append(m.webhooks, hook)
+	return hook, nil
+}
+
+func (m *mockDB) GetWebhook(_ context.Context, id, userID uuid.UUID) (*db.Webhook, error) {
+	for _, hook := range m.webhooks {
+		if hook.ID == id && hook.UserID == userID {
+			return hook, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) ListWebhooks(_ context.Context, userID uuid.UUID) ([]db.Webhook, error) {
+	var hooks []db.Webhook
+	for _, hook := range m.webhooks {
+		if hook.UserID == userID {
+			hooks = append(hooks, *hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (m *mockDB) DeleteWebhook(_ context.Context, id, userID uuid.UUID) (bool, error) {
+	for i, hook := range m.webhooks {
+		if hook.ID == id && hook.UserID == userID {
+			m.webhooks = append(m.webhooks[:i], m.webhooks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockDB) ListWebhookDeliveries(_ context.Context, webhookID uuid.UUID, limit int) ([]db.WebhookDelivery, error) {
+	var deliveries []db.WebhookDelivery
+	for _, d := range m.webhookLog {
+		if d.WebhookID == webhookID && len(deliveries) < limit {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *mockDB) EnqueueWebhookDeliveries(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _, _ string, _ any) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockDB) ClaimWebhookDeliveries(_ context.Context, _ int, _ time.Duration) ([]db.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockDB) RecordWebhookAttempt(_ context.Context, _ uuid.UUID, _ *db.WebhookAttempt) error {
+	return nil
+}
+
+func (m *mockDB) GetDigestStats(_ context.Context, _ uuid.UUID, since time.Time) (*db.DigestStats, error) {
+	return &db.DigestStats{Since: since, RecentRuns: []db.DigestRun{}}, nil
+}
+
+func (m *mockDB) GetResumeHealth(_ context.Context, userID uuid.UUID, now time.Time) (*db.ResumeHealth, error) {
+	health := db.BuildResumeHealth(m.healthTargets[userID], m.healthBullets[userID], now)
+	health.UserID = userID
+	return health, nil
+}
+
+func (m *mockDB) SetRunDates(_ context.Context, runID uuid.UUID, deadlineAt, followUpAt *time.Time) error {
+	run, ok := m.runs[runID]
+	if !ok {
+		return fmt.Errorf("run not found: %s", runID)
+	}
+	run.DeadlineAt, run.FollowUpAt = deadlineAt, followUpAt
+	delete(m.notifyClaims, db.ReminderDeadline+":"+runID.String())
+	delete(m.notifyClaims, db.ReminderFollowUp+":"+runID.String())
+	return nil
+}
+
+func (m *mockDB) ListApplicationDates(_ context.Context, userID uuid.UUID) ([]db.Run, error) {
+	var runs []db.Run
+	for _, run := range m.runs {
+		if run.UserID != nil && *run.UserID == userID && (run.DeadlineAt != nil || run.FollowUpAt != nil) {
+			runs = append(runs, *run)
+		}
+	}
+	return runs, nil
+}
+
+func (m *mockDB) ListDueReminders(ctx context.Context, lead time.Duration) ([]db.ApplicationReminder, error) {
+	now := time.Now()
+	var reminders []db.ApplicationReminder
+	for _, run := range m.runs {
+		if run.UserID == nil {
+			continue
+		}
+		recipient, _ := m.GetNotificationRecipient(ctx, *run.UserID, db.NotificationReminder)
+		if recipient == nil {
+			continue
+		}
+		for kind, due := range map[string]*time.Time{db.ReminderDeadline: run.DeadlineAt, db.ReminderFollowUp: run.FollowUpAt} {
+			if due == nil || !due.After(now) || due.After(now.Add(lead)) || m.notifyClaims[kind+":"+run.ID.String()] {
+				continue
+			}
+			reminders = append(reminders, db.ApplicationReminder{
+				Recipient: *recipient,
+				RefID:     run.ID,
+				Company:   run.Company,
+				RoleTitle: run.RoleTitle,
+				Kind:      kind,
+				DueAt:     *due,
+			})
+		}
+	}
+	for _, t := range m.trackers {
+		due := t.NextActionAt
+		if due == nil || !due.After(now) || due.After(now.Add(lead)) || m.notifyClaims[db.ReminderNextAction+":"+t.ID.String()] {
+			continue
+		}
+		recipient, _ := m.GetNotificationRecipient(ctx, t.UserID, db.NotificationReminder)
+		if recipient == nil {
+			continue
+		}
+		reminders = append(reminders, db.ApplicationReminder{
+			Recipient: *recipient,
+			RefID:     t.ID,
+			Company:   t.Company,
+			Action:    *t.NextAction,
+			Kind:      db.ReminderNextAction,
+			DueAt:     *due,
+		})
+	}
+	return reminders, nil
+}
+
+func (m *mockDB) UpsertApplicationTracker(_ context.Context, userID uuid.UUID, input *db.ApplicationTrackerInput) (*db.ApplicationTracker, error) {
+	var next *string
+	if input.NextAction != "" {
+		next = &input.NextAction
+	}
+	for _, t := range m.trackers {
+		if t.UserID == userID && strings.EqualFold(t.Company, input.Company) {
+			t.Company, t.Notes, t.NextAction, t.NextActionAt = input.Company, input.Notes, next, input.NextActionAt
+			delete(m.notifyClaims, db.ReminderNextAction+":"+t.ID.String())
+			return t, nil
+		}
+	}
+	t := &db.ApplicationTracker{ID: uuid.New(), UserID: userID, Company: input.Company, Notes: input.Notes,
+		NextAction: next, NextActionAt: input.NextActionAt}
+	m.trackers = append(m.trackers, t)
+	return t, nil
+}
+
+func (m *mockDB) ListApplicationTrackers(_ context.Context, userID uuid.UUID) ([]db.ApplicationTracker, error) {
+	trackers := []db.ApplicationTracker{}
+	for _, t := range m.trackers {
+		if t.UserID == userID {
+			trackers = append(trackers, *t)
+		}
+	}
+	return trackers, nil
+}
+
+func (m *mockDB) GetApplicationTracker(_ context.Context, id uuid.UUID) (*db.ApplicationTracker, error) {
+	for _, t := range m.trackers {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) DeleteApplicationTracker(_ context.Context, id uuid.UUID) error {
+	for i, t := range m.trackers {
+		if t.ID == id {
+			m.trackers = append(m.trackers[:i], m.trackers[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("application tracker not found: %s", id)
+}
+
+func (m *mockDB) ListApplicationContacts(_ context.Context, trackerID uuid.UUID) ([]db.ApplicationContact, error) {
+	contacts := []db.ApplicationContact{}
+	for _, c := range m.contacts {
+		if c.TrackerID == trackerID {
+			contacts = append(contacts, *c)
+		}
+	}
+	return contacts, nil
+}
+
+func (m *mockDB) CreateApplicationContact(_ context.Context, trackerID uuid.UUID, input *db.ApplicationContactInput) (*db.ApplicationContact, error) {
+	c := &db.ApplicationContact{ID: uuid.New(), TrackerID: trackerID}
+	setMockContact(c, input)
+	m.contacts = append(m.contacts, c)
+	return c, nil
+}
+
+func (m *mockDB) UpdateApplicationContact(_ context.Context, trackerID, contactID uuid.UUID, input *db.ApplicationContactInput) (*db.ApplicationContact, error) {
+	for _, c := range m.contacts {
+		if c.ID == contactID && c.TrackerID == trackerID {
+			setMockContact(c, input)
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func setMockContact(c *db.ApplicationContact, input *db.ApplicationContactInput) {
+	optional := func(s string) *string {
+		if s == "" {
+			return nil
+		}
+		return &s
+	}
+	c.Name, c.Role, c.Email, c.LinkedInURL = input.Name, optional(input.Role), optional(input.Email), optional(input.LinkedInURL)
+}
+
+func (m *mockDB) DeleteApplicationContact(_ context.Context, trackerID, contactID uuid.UUID) (bool, error) {
+	for i, c := range m.contacts {
+		if c.ID == contactID && c.TrackerID == trackerID {
+			m.contacts = append(m.contacts[:i], m.contacts[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockDB) ListApplicationInteractions(_ context.Context, trackerID uuid.UUID) ([]db.ApplicationInteraction, error) {
+	interactions := []db.ApplicationInteraction{}
+	for _, i := range m.interactions {
+		if i.TrackerID == trackerID {
+			interactions = append(interactions, *i)
+		}
+	}
+	return interactions, nil
+}
+
+func (m *mockDB) CreateApplicationInteraction(_ context.Context, trackerID uuid.UUID, input *db.ApplicationInteractionInput) (*db.ApplicationInteraction, error) {
+	i := &db.ApplicationInteraction{ID: uuid.New(), TrackerID: trackerID, ContactID: input.ContactID, Kind: input.Kind,
+		OccurredAt: input.OccurredAt, Notes: input.Notes}
+	m.interactions = append(m.interactions, i)
+	return i, nil
+}
+
+func (m *mockDB) DeleteApplicationInteraction(_ context.Context, trackerID, interactionID uuid.UUID) (bool, error) {
+	for i, entry := range m.interactions {
+		if entry.ID == interactionID && entry.TrackerID == trackerID {
+			m.interactions = append(m.interactions[:i], m.interactions[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockDB) UpsertGitHubAccount(_ context.Context, userID uuid.UUID, username string, tokenSealed *string) (*db.GitHubAccount, error) {
+	if m.githubAccounts == nil {
+		m.githubAccounts = make(map[uuid.UUID]*db.GitHubAccount)
+	}
+	account, ok := m.githubAccounts[userID]
+	if !ok {
+		account = &db.GitHubAccount{UserID: userID}
+		m.githubAccounts[userID] = account
+	}
+	if tokenSealed != nil || account.Username != username {
+		account.TokenSealed = tokenSealed
+	}
+	account.Username = username
+	account.HasToken = account.TokenSealed != nil
+	return account, nil
+}
+
+func (m *mockDB) GetGitHubAccount(_ context.Context, userID uuid.UUID) (*db.GitHubAccount, error) {
+	return m.githubAccounts[userID], nil
+}
+
+func (m *mockDB) DeleteGitHubAccount(_ context.Context, userID uuid.UUID) error {
+	if _, ok := m.githubAccounts[userID]; !ok {
+		return fmt.Errorf("github account not found for user: %s", userID)
+	}
+	delete(m.githubAccounts, userID)
+	return nil
+}
+
+func (m *mockDB) ClaimGitHubSync(_ context.Context, userID uuid.UUID, minInterval time.Duration) (bool, time.Time, error) {
+	account := m.githubAccounts[userID]
+	if account.LastSyncedAt != nil && time.Since(*account.LastSyncedAt) < minInterval {
+		return false, account.LastSyncedAt.Add(minInterval), nil
+	}
+	now := time.Now()
+	account.LastSyncedAt = &now
+	return true, now.Add(minInterval), nil
+}
+
+func (m *mockDB) UpsertProjectDrafts(_ context.Context, userID uuid.UUID, drafts []db.ProjectDraftInput) error {
+	for _, in := range drafts {
+		var draft *db.ProjectDraft
+		for _, d := range m.projectDrafts {
+			if d.UserID == userID && d.RepoURL == in.RepoURL {
+				draft = d
+			}
+		}
+		if draft == nil {
+			draft = &db.ProjectDraft{ID: uuid.New(), UserID: userID, RepoURL: in.RepoURL, Status: db.DraftStatusPending}
+			m.projectDrafts = append(m.projectDrafts, draft)
+		}
+		if draft.Status != db.DraftStatusPending {
+			continue
+		}
+		draft.RepoName, draft.Stars, draft.Skills, draft.Text = in.RepoName, in.Stars, in.Skills, in.Text
+	}
+	return nil
+}
+
+func (m *mockDB) ListProjectDrafts(_ context.Context, userID uuid.UUID, status string) ([]db.ProjectDraft, error) {
+	drafts := []db.ProjectDraft{}
+	for _, d := range m.projectDrafts {
+		if d.UserID == userID && (status == "" || d.Status == status) {
+			drafts = append(drafts, *d)
+		}
+	}
+	return drafts, nil
+}
+
+func (m *mockDB) GetProjectDraft(_ context.Context, id uuid.UUID) (*db.ProjectDraft, error) {
+	for _, d := range m.projectDrafts {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) AcceptProjectDraft(_ context.Context, id, _ uuid.UUID, text string) (*db.ProjectDraft, error) {
+	d, _ := m.GetProjectDraft(context.Background(), id)
+	if d == nil || d.Status != db.DraftStatusPending {
+		return nil, nil
+	}
+	storyID := uuid.New()
+	d.Status, d.Text, d.StoryID = db.DraftStatusAccepted, text, &storyID
+	return d, nil
+}
+
+func (m *mockDB) DismissProjectDraft(_ context.Context, id uuid.UUID) (*db.ProjectDraft, error) {
+	d, _ := m.GetProjectDraft(context.Background(), id)
+	if d == nil || d.Status != db.DraftStatusPending {
+		return nil, nil
+	}
+	d.Status = db.DraftStatusDismissed
+	return d, nil
+}
+
+func (m *mockDB) ListBulletSuggestions(_ context.Context, userID uuid.UUID, status string) ([]db.BulletSuggestion, error) {
+	suggestions := []db.BulletSuggestion{}
+	for _, s := range m.suggestions {
+		if s.UserID == userID && (status == "" || s.Status == status) {
+			suggestions = append(suggestions, *s)
+		}
+	}
+	return suggestions, nil
+}
+
+func (m *mockDB) GetBulletSuggestion(_ context.Context, id uuid.UUID) (*db.BulletSuggestion, error) {
+	for _, s := range m.suggestions {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) AcceptBulletSuggestion(_ context.Context, id, _ uuid.UUID, text string) (*db.BulletSuggestion, error) {
+	s, _ := m.GetBulletSuggestion(context.Background(), id)
+	if s == nil || s.Status != db.DraftStatusPending {
+		return nil, nil
+	}
+	storyID := uuid.New()
+	s.Status, s.Text, s.StoryID = db.DraftStatusAccepted, text, &storyID
+	return s, nil
+}
+
+func (m *mockDB) DismissBulletSuggestion(_ context.Context, id uuid.UUID) (*db.BulletSuggestion, error) {
+	s, _ := m.GetBulletSuggestion(context.Background(), id)
+	if s == nil || s.Status != db.DraftStatusPending {
+		return nil, nil
+	}
+	s.Status = db.DraftStatusDismissed
+	return s, nil
+}
+
+func (m *mockDB) UpsertCompanyPreference(_ context.Context, userID uuid.UUID, input *db.CompanyPreferenceInput) (*db.CompanyPreference, error) {
+	preset := &db.CompanyPreference{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Company:       input.Company,
+		Name:          input.Name,
+		PinnedBullets: input.PinnedBullets,
+		SectionOrder:  input.SectionOrder,
+	}
+	if input.ToneOverride != "" {
+		preset.ToneOverride = &input.ToneOverride
+	}
+	if input.Template != "" {
+		preset.Template = &input.Template
+	}
+	for i, p := range m.presets {
+		if p.UserID == userID && strings.EqualFold(p.Company, input.Company) && strings.EqualFold(p.Name, input.Name) {
+			preset.ID = p.ID
+			m.presets[i] = preset
+			return preset, nil
+		}
+	}
+	m.presets = append(m.presets, preset)
+	return preset, nil
+}
+
+func (m *mockDB) ListCompanyPreferences(_ context.Context, userID uuid.UUID, company string) ([]db.CompanyPreference, error) {
+	presets := []db.CompanyPreference{}
+	for _, p := range m.presets {
+		if p.UserID == userID && (company == "" || strings.EqualFold(p.Company, company)) {
+			presets = append(presets, *p)
+		}
+	}
+	return presets, nil
+}
+
+func (m *mockDB) GetCompanyPreference(_ context.Context, id uuid.UUID) (*db.CompanyPreference, error) {
+	for _, p := range m.presets {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) DeleteCompanyPreference(_ context.Context, id uuid.UUID) error {
+	for i, p := range m.presets {
+		if p.ID == id {
+			m.presets = append(m.presets[:i], m.presets[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("company preference not found: %s", id)
+}
+
+func (m *mockDB) UpsertRunRecipe(_ context.Context, userID uuid.UUID, input *db.RunRecipeInput) (*db.RunRecipe, error) {
+	recipe := &db.RunRecipe{
+		ID:             uuid.New(),
+		UserID:         userID,
+		Name:           input.Name,
+		SectionOrder:   input.SectionOrder,
+		RankingWeights: input.RankingWeights,
+	}
+	if input.Description != "" {
+		recipe.Description = &input.Description
+	}
+	if input.Template != "" {
+		recipe.Template = &input.Template
+	}
+	if input.MaxBullets != 0 {
+		recipe.MaxBullets = &input.MaxBullets
+	}
+	if input.MaxLines != 0 {
+		recipe.MaxLines = &input.MaxLines
+	}
+	for i, r := range m.recipes {
+		if r.UserID == userID && strings.EqualFold(r.Name, input.Name) {
+			recipe.ID = r.ID
+			m.recipes[i] = recipe
+			return recipe, nil
+		}
+	}
+	m.recipes = append(m.recipes, recipe)
+	return recipe, nil
+}
+
+func (m *mockDB) ListRunRecipes(_ context.Context, userID uuid.UUID) ([]db.RunRecipe, error) {
+	recipes := []db.RunRecipe{}
+	for _, r := range m.recipes {
+		if r.UserID == userID {
+			recipes = append(recipes, *r)
+		}
+	}
+	return recipes, nil
+}
+
+func (m *mockDB) GetRunRecipe(_ context.Context, id uuid.UUID) (*db.RunRecipe, error) {
+	for _, r := range m.recipes {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) GetRunRecipeByName(_ context.Context, userID uuid.UUID, name string) (*db.RunRecipe, error) {
+	for _, r := range m.recipes {
+		if r.UserID == userID && strings.EqualFold(r.Name, name) {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) DeleteRunRecipe(_ context.Context, id uuid.UUID) error {
+	for i, r := range m.recipes {
+		if r.ID == id {
+			m.recipes = append(m.recipes[:i], m.recipes[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("run recipe not found: %s", id)
+}
+
+func (m *mockDB) CreateWorkspace(_ context.Context, name string, createdBy uuid.UUID) (*db.Workspace, error) {
+	ws := &db.Workspace{ID: uuid.New(), Name: name, CreatedBy: &createdBy, Role: db.WorkspaceRoleAdmin}
+	m.workspaces = append(m.workspaces, ws)
+	m.members = append(m.members, db.WorkspaceMember{WorkspaceID: ws.ID, UserID: createdBy, Role: db.WorkspaceRoleAdmin})
+	return ws, nil
+}
+
+func (m *mockDB) ListWorkspacesForUser(_ context.Context, userID uuid.UUID) ([]db.Workspace, error) {
+	workspaces := []db.Workspace{}
+	for _, ws := range m.workspaces {
+		for _, member := range m.members {
+			if member.WorkspaceID == ws.ID && member.UserID == userID {
+				listed := *ws
+				listed.Role = member.Role
+				workspaces = append(workspaces, listed)
+			}
+		}
+	}
+	return workspaces, nil
+}
+
+func (m *mockDB) GetWorkspaceMember(_ context.Context, workspaceID, userID uuid.UUID) (*db.WorkspaceMember, error) {
+	for _, member := range m.members {
+		if member.WorkspaceID == workspaceID && member.UserID == userID {
+			return &member, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) ListWorkspaceMembers(_ context.Context, workspaceID uuid.UUID) ([]db.WorkspaceMember, error) {
+	members := []db.WorkspaceMember{}
+	for _, member := range m.members {
+		if member.WorkspaceID == workspaceID {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func (m *mockDB) SetWorkspaceMember(_ context.Context, workspaceID, userID uuid.UUID, role string) (*db.WorkspaceMember, error) {
+	for i, member := range m.members {
+		if member.WorkspaceID == workspaceID && member.UserID == userID {
+			m.members[i].Role = role
+			return &m.members[i], nil
+		}
+	}
+	m.members = append(m.members, db.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: role})
+	return &m.members[len(m.members)-1], nil
+}
+
+func (m *mockDB) RemoveWorkspaceMember(_ context.Context, workspaceID, userID uuid.UUID) error {
+	for i, member := range m.members {
+		if member.WorkspaceID == workspaceID && member.UserID == userID {
+			m.members = append(m.members[:i], m.members[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("workspace member not found: %s", userID)
+}
+
+func (m *mockDB) UpsertRulePack(_ context.Context, workspaceID, createdBy uuid.UUID, input *db.RulePackInput) (*db.RulePack, error) {
+	pack := &db.RulePack{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		Name:        input.Name,
+		Mode:        input.Mode,
+		Priority:    input.Priority,
+		Rules:       input.Rules,
+		CreatedBy:   &createdBy,
+	}
+	for i, p := range m.rulePacks {
+		if p.WorkspaceID == workspaceID && strings.EqualFold(p.Name, input.Name) {
+			pack.ID = p.ID
+			m.rulePacks[i] = pack
+			return pack, nil
+		}
+	}
+	m.rulePacks = append(m.rulePacks, pack)
+	return pack, nil
+}
+
+func (m *mockDB) ListRulePacks(_ context.Context, workspaceID uuid.UUID) ([]db.RulePack, error) {
+	packs := []db.RulePack{}
+	for _, p := range m.rulePacks {
+		if p.WorkspaceID == workspaceID {
+			packs = append(packs, *p)
+		}
+	}
+	return packs, nil
+}
+
+func (m *mockDB) GetRulePack(_ context.Context, id uuid.UUID) (*db.RulePack, error) {
+	for _, p := range m.rulePacks {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) DeleteRulePack(_ context.Context, id uuid.UUID) error {
+	for i, p := range m.rulePacks {
+		if p.ID == id {
+			m.rulePacks = append(m.rulePacks[:i], m.rulePacks[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("rule pack not found: %s", id)
+}
+
+func (m *mockDB) ListRulePackApplications(_ context.Context, _ uuid.UUID, _ int) ([]db.RulePackApplication, error) {
+	return []db.RulePackApplication{}, nil
+}
+
+func (m *mockDB) CreateVoiceNote(_ context.Context, input *db.VoiceNoteInput) (*db.VoiceNote, error) {
+	note := &db.VoiceNote{
+		ID:         uuid.New(),
+		UserID:     input.UserID,
+		Source:     input.Source,
+		Transcript: input.Transcript,
+		Answers:    input.Answers,
+		Draft:      input.Draft,
+		CreatedAt:  time.Now(),
+	}
+	if input.NextStage != "" {
+		note.NextStage, note.NextQuestion = &input.NextStage, &input.NextQuestion
+	}
+	m.voiceNotes = append(m.voiceNotes, note)
+	return note, nil
+}
+
+func (m *mockDB) ListVoiceNotes(_ context.Context, userID uuid.UUID) ([]db.VoiceNote, error) {
+	notes := []db.VoiceNote{}
+	for _, n := range m.voiceNotes {
+		if n.UserID == userID {
+			notes = append(notes, *n)
+		}
+	}
+	return notes, nil
+}
+
+func (m *mockDB) GetVoiceNote(_ context.Context, id uuid.UUID) (*db.VoiceNote, error) {
+	for _, n := range m.voiceNotes {
+		if n.ID == id {
+			return n, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) DeleteVoiceNote(_ context.Context, id uuid.UUID) error {
+	for i, n := range m.voiceNotes {
+		if n.ID == id {
+			m.voiceNotes = append(m.voiceNotes[:i], m.voiceNotes[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("voice note not found: %s", id)
+}
+
+func (m *mockDB) UpsertSharedResume(_ context.Context, input *db.SharedResumeInput) (*db.SharedResume, error) {
+	if m.sharedResumes == nil {
+		m.sharedResumes = make(map[uuid.UUID]*db.SharedResume)
+	}
+	scope := input.Scope
+	if scope == "" {
+		scope = db.SharedScopePDF
+	}
+	if shared, ok := m.sharedResumes[input.UserID]; ok {
+		if shared.RevokedAt != nil {
+			shared.Slug = input.Slug
+		}
+		shared.RunID, shared.Scope, shared.ExpiresAt, shared.RevokedAt = input.RunID, scope, input.ExpiresAt, nil
+		return shared, nil
+	}
+	shared := &db.SharedResume{ID: uuid.New(), UserID: input.UserID, RunID: input.RunID, Slug: input.Slug,
+		Scope: scope, ExpiresAt: input.ExpiresAt}
+	m.sharedResumes[input.UserID] = shared
+	return shared, nil
+}
+
+func (m *mockDB) UpdateSharedResumeAccess(_ context.Context, userID uuid.UUID, scope string, expiresAt *time.Time) (*db.SharedResume, error) {
+	shared, ok := m.sharedResumes[userID]
+	if !ok {
+		return nil, nil
+	}
+	shared.Scope, shared.ExpiresAt = scope, expiresAt
+	return shared, nil
+}
+
+func (m *mockDB) RevokeSharedResume(_ context.Context, userID uuid.UUID) (*db.SharedResume, error) {
+	shared, ok := m.sharedResumes[userID]
+	if !ok {
+		return nil, nil
+	}
+	if shared.RevokedAt == nil {
+		now := time.Now()
+		shared.RevokedAt = &now
+	}
+	return shared, nil
+}
+
+func (m *mockDB) GetSharedResume(_ context.Context, userID uuid.UUID) (*db.SharedResume, error) {
+	return m.sharedResumes[userID], nil
+}
+
+func (m *mockDB) GetSharedResumeBySlug(_ context.Context, slug string) (*db.SharedResume, error) {
+	for _, shared := range m.sharedResumes {
+		if shared.Slug == slug {
+			return shared, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockDB) DeleteSharedResume(_ context.Context, userID uuid.UUID) error {
+	if _, ok := m.sharedResumes[userID]; !ok {
+		return fmt.Errorf("shared resume not found for user: %s", userID)
+	}
+	delete(m.sharedResumes, userID)
+	return nil
+}
+
+func (m *mockDB) RecordSharedResumeView(_ context.Context, sharedID uuid.UUID, kind, _ string) error {
+	if m.sharedViews == nil {
+		m.sharedViews = make(map[uuid.UUID][]string)
+	}
+	m.sharedViews[sharedID] = append(m.sharedViews[sharedID], kind)
+	return nil
+}
+
+func (m *mockDB) GetSharedResumeStats(_ context.Context, sharedID uuid.UUID) (*db.SharedResumeStats, error) {
+	stats := &db.SharedResumeStats{Referrers: []db.ReferrerCount{}}
+	for _, kind := range m.sharedViews[sharedID] {
+		if kind == db.SharedViewPDF {
+			stats.PDFDownloads++
+		} else {
+			stats.PageViews++
+		}
+	}
+	return stats, nil
+}
+
+func (m *mockDB) CreateSharedResumeComment(_ context.Context, sharedID uuid.UUID, author, body string) (*db.SharedResumeComment, error) {
+	comment := db.SharedResumeComment{ID: uuid.New(), SharedResumeID: sharedID, Author: author, Body: body}
+	m.sharedComments = append(m.sharedComments, comment)
+	return &comment, nil
+}
+
+func (m *mockDB) ListSharedResumeComments(_ context.Context, sharedID uuid.UUID) ([]db.SharedResumeComment, error) {
+	comments := []db.SharedResumeComment{}
+	for _, c := range m.sharedComments {
+		if c.SharedResumeID == sharedID {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+func (m *mockDB) RecordSharedResumeAccess(_ context.Context, input *db.SharedResumeAccessInput) error {
+	m.sharedAccess = append(m.sharedAccess, *input)
+	return nil
+}
+
+func (m *mockDB) ListSharedResumeAccess(_ context.Context, sharedID uuid.UUID, _ int) ([]db.SharedResumeAccess, error) {
+	entries := []db.SharedResumeAccess{}
+	for _, a := range m.sharedAccess {
+		if a.SharedResumeID == sharedID {
+			entries = append(entries, db.SharedResumeAccess{SharedResumeID: sharedID, Action: a.Action, Outcome: a.Outcome})
+		}
+	}
+	return entries, nil
 }
 
-func (m *mockDB) GetRunStep(_ context.Context, _ uuid.UUID, _ string) (*db.RunStep, error) {
+func (m *mockDB) GetUserByEmail(_ context.Context, _ string) (*db.User, error) {
 	return nil, nil
 }
 
-func (m *mockDB) ListRunSteps(_ context.Context, _ uuid.UUID, _, _ *string) ([]db.RunStep, error) {
-	return []db.RunStep{}, nil
+// CreateUser stores a verified user, as the email_verified_at column default does
+func (m *mockDB) CreateUser(_ context.Context, name, email, phone string) (uuid.UUID, error) {
+	if m.users == nil {
+		m.users = make(map[uuid.UUID]*db.User)
+	}
+	now := time.Now()
+	u := &db.User{ID: uuid.New(), Name: name, Email: email, Phone: phone, CreatedAt: now, EmailVerifiedAt: &now}
+	m.users[u.ID] = u
+	return u.ID, nil
 }
 
-func (m *mockDB) CreateRunStep(_ context.Context, _ uuid.UUID, _ *db.RunStepInput) (*db.RunStep, error) {
-	return nil, nil
+func (m *mockDB) UpdateUser(_ context.Context, _ *db.User) error {
+	return nil
 }
 
-func (m *mockDB) UpdateRunStepStatus(_ context.Context, _ uuid.UUID, _ string, _ string, _ *string, _ *uuid.UUID) error {
+func (m *mockDB) DeleteUser(_ context.Context, _ uuid.UUID) error {
 	return nil
 }
 
-func (m *mockDB) GetRunCheckpoint(_ context.Context, _ uuid.UUID) (*db.RunCheckpoint, error) {
-	return nil, nil
+func (m *mockDB) UpdatePassword(_ context.Context, _ uuid.UUID, _ string) error {
+	return nil
 }
 
-func (m *mockDB) CreateRunCheckpoint(_ context.Context, _ uuid.UUID, _ *db.RunCheckpointInput) (*db.RunCheckpoint, error) {
-	return nil, nil
+func (m *mockDB) CheckEmailExists(_ context.Context, _ string) (bool, error) {
+	return false, nil
 }
 
-func (m *mockDB) GetUser(_ context.Context, _ uuid.UUID) (*db.User, error) {
-	return nil, nil
+func (m *mockDB) CreateRefreshToken(_ context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) (*db.RefreshToken, error) {
+	return m.storeRefreshToken(userID, uuid.New(), tokenHash, expiresAt), nil
 }
 
-func (m *mockDB) GetUserByEmail(_ context.Context, _ string) (*db.User, error) {
-	return nil, nil
+func (m *mockDB) storeRefreshToken(userID, familyID uuid.UUID, tokenHash string, expiresAt time.Time) *db.RefreshToken {
+	if m.refreshTokens == nil {
+		m.refreshTokens = make(map[string]*db.RefreshToken)
+	}
+	t := &db.RefreshToken{ID: uuid.New(), UserID: userID, FamilyID: familyID, TokenHash: tokenHash,
+		ExpiresAt: expiresAt, CreatedAt: time.Now()}
+	m.refreshTokens[tokenHash] = t
+	return t
 }
 
-func (m *mockDB) CreateUser(_ context.Context, _, _, _ string) (uuid.UUID, error) {
-	return uuid.New(), nil
+func (m *mockDB) RotateRefreshToken(ctx context.Context, tokenHash, newTokenHash string, expiresAt time.Time) (*db.RefreshTokenRotation, error) {
+	current, ok := m.refreshTokens[tokenHash]
+	if !ok || !current.ExpiresAt.After(time.Now()) {
+		return &db.RefreshTokenRotation{}, nil
+	}
+	if current.RevokedAt != nil {
+		_ = m.RevokeRefreshTokenFamily(ctx, tokenHash)
+		return &db.RefreshTokenRotation{Reused: true}, nil
+	}
+	next := m.storeRefreshToken(current.UserID, current.FamilyID, newTokenHash, expiresAt)
+	now := time.Now()
+	current.RevokedAt, current.ReplacedBy = &now, &next.ID
+	return &db.RefreshTokenRotation{Token: next}, nil
 }
 
-func (m *mockDB) UpdateUser(_ context.Context, _ *db.User) error {
+func (m *mockDB) RevokeRefreshTokenFamily(_ context.Context, tokenHash string) error {
+	if t, ok := m.refreshTokens[tokenHash]; ok {
+		m.revokeRefreshTokens(func(other *db.RefreshToken) bool { return other.FamilyID == t.FamilyID })
+	}
 	return nil
 }
 
-func (m *mockDB) DeleteUser(_ context.Context, _ uuid.UUID) error {
+func (m *mockDB) RevokeUserRefreshTokens(_ context.Context, userID uuid.UUID) error {
+	m.revokeRefreshTokens(func(t *db.RefreshToken) bool { return t.UserID == userID })
 	return nil
 }
 
-func (m *mockDB) UpdatePassword(_ context.Context, _ uuid.UUID, _ string) error {
-	return nil
+func (m *mockDB) revokeRefreshTokens(match func(*db.RefreshToken) bool) {
+	now := time.Now()
+	for _, t := range m.refreshTokens {
+		if t.RevokedAt == nil && match(t) {
+			t.RevokedAt = &now
+		}
+	}
 }
 
-func (m *mockDB) CheckEmailExists(_ context.Context, _ string) (bool, error) {
-	return false, nil
+func (m *mockDB) CreateEmailVerification(_ context.Context, userID uuid.UUID, email, tokenHash string, expiresAt time.Time) (*db.EmailVerification, error) {
+	if u := m.users[userID]; u != nil {
+		u.EmailVerifiedAt = nil
+	}
+	v := &db.EmailVerification{ID: uuid.New(), UserID: userID, Email: email, TokenHash: tokenHash,
+		ExpiresAt: expiresAt, CreatedAt: time.Now()}
+	m.verifications = append(m.verifications, v)
+	return v, nil
 }
 
-func (m *mockDB) CreateJob(_ context.Context, _ *db.Job) (uuid.UUID, error) {
-	return uuid.New(), nil
+func (m *mockDB) VerifyEmail(_ context.Context, tokenHash string) (*db.EmailVerification, error) {
+	for _, v := range m.verifications {
+		if v.TokenHash != tokenHash || v.UsedAt != nil || time.Now().After(v.ExpiresAt) {
+			continue
+		}
+		u := m.users[v.UserID]
+		if u == nil || u.Email != v.Email {
+			return nil, nil
+		}
+		now := time.Now()
+		v.UsedAt, u.EmailVerifiedAt = &now, &now
+		return v, nil
+	}
+	return nil, nil
+}
+
+func (m *mockDB) LatestEmailVerification(_ context.Context, userID uuid.UUID) (*db.EmailVerification, error) {
+	var latest *db.EmailVerification
+	for _, v := range m.verifications {
+		if v.UserID == userID && (latest == nil || !v.CreatedAt.Before(latest.CreatedAt)) {
+			latest = v
+		}
+	}
+	return latest, nil
 }
 
-func (m *mockDB) ListJobs(_ context.Context, _ uuid.UUID) ([]db.Job, error) {
-	return []db.Job{}, nil
+func (m *mockDB) CreateJob(_ context.Context, job *db.Job) (uuid.UUID, error) {
+	job.ID = uuid.New()
+	m.jobs = append(m.jobs, *job)
+	return job.ID, nil
+}
+
+func (m *mockDB) ListJobs(_ context.Context, userID uuid.UUID) ([]db.Job, error) {
+	jobs := []db.Job{}
+	for _, job := range m.jobs {
+		if job.UserID == userID {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
 }
 
 func (m *mockDB) UpdateJob(_ context.Context, _ *db.Job) error {
@@ -156,12 +1553,20 @@ func (m *mockDB) DeleteJob(_ context.Context, _ uuid.UUID) error {
 	return nil
 }
 
-func (m *mockDB) CreateExperience(_ context.Context, _ *db.Experience) (uuid.UUID, error) {
-	return uuid.New(), nil
+func (m *mockDB) CreateExperience(_ context.Context, exp *db.Experience) (uuid.UUID, error) {
+	exp.ID = uuid.New()
+	m.experiences = append(m.experiences, *exp)
+	return exp.ID, nil
 }
 
-func (m *mockDB) ListExperiences(_ context.Context, _ uuid.UUID) ([]db.Experience, error) {
-	return []db.Experience{}, nil
+func (m *mockDB) ListExperiences(_ context.Context, jobID uuid.UUID) ([]db.Experience, error) {
+	exps := []db.Experience{}
+	for _, e := range m.experiences {
+		if e.JobID == jobID {
+			exps = append(exps, e)
+		}
+	}
+	return exps, nil
 }
 
 func (m *mockDB) UpdateExperience(_ context.Context, _ *db.Experience) error {
@@ -172,12 +1577,20 @@ func (m *mockDB) DeleteExperience(_ context.Context, _ uuid.UUID) error {
 	return nil
 }
 
-func (m *mockDB) CreateEducation(_ context.Context, _ *db.Education) (uuid.UUID, error) {
-	return uuid.New(), nil
+func (m *mockDB) CreateEducation(_ context.Context, edu *db.Education) (uuid.UUID, error) {
+	edu.ID = uuid.New()
+	m.education = append(m.education, *edu)
+	return edu.ID, nil
 }
 
-func (m *mockDB) ListEducation(_ context.Context, _ uuid.UUID) ([]db.Education, error) {
-	return []db.Education{}, nil
+func (m *mockDB) ListEducation(_ context.Context, userID uuid.UUID) ([]db.Education, error) {
+	education := []db.Education{}
+	for _, e := range m.education {
+		if e.UserID == userID {
+			education = append(education, e)
+		}
+	}
+	return education, nil
 }
 
 func (m *mockDB) UpdateEducation(_ context.Context, _ *db.Education) error {
@@ -188,14 +1601,21 @@ func (m *mockDB) DeleteEducation(_ context.Context, _ uuid.UUID) error {
 	return nil
 }
 
-func (m *mockDB) ListCompaniesWithProfiles(_ context.Context, _, _ int) ([]db.Company, int, error) {
+func (m *mockDB) ListCompaniesWithProfiles(_ context.Context, _ int, _ db.Page) ([]db.Company, int, error) {
 	return []db.Company{}, 0, nil
 }
 
-func (m *mockDB) GetCompanyByID(_ context.Context, _ uuid.UUID) (*db.Company, error) {
+func (m *mockDB) GetCompanyByID(_ context.Context, companyID uuid.UUID) (*db.Company, error) {
+	if f := db.FindCompanyFingerprint(m.fingerprints, companyID); f != nil {
+		return &f.Company, nil
+	}
 	return nil, nil
 }
 
+func (m *mockDB) ListCompanyFingerprints(_ context.Context) ([]db.CompanyFingerprint, error) {
+	return m.fingerprints, nil
+}
+
 func (m *mockDB) GetCompanyByNormalizedName(_ context.Context, _ string) (*db.Company, error) {
 	return nil, nil
 }
@@ -220,6 +1640,29 @@ func (m *mockDB) CreateCompanyProfile(_ context.Context, _ *db.ProfileCreateInpu
 	return nil, nil
 }
 
+func (m *mockDB) UpsertUserCompanyProfile(_ context.Context, userID uuid.UUID, input *db.ProfileCreateInput) (*db.CompanyProfile, error) {
+	if m.userCompanyProfiles == nil {
+		m.userCompanyProfiles = make(map[[2]uuid.UUID]*db.CompanyProfile)
+	}
+	key := [2]uuid.UUID{userID, input.CompanyID}
+	p := &db.CompanyProfile{ID: uuid.New(), CompanyID: input.CompanyID, UserID: &userID, Tone: input.Tone,
+		SourceType: db.ProfileSourceManual, Version: 1, StyleRules: input.StyleRules, Values: input.Values}
+	if prev := m.userCompanyProfiles[key]; prev != nil {
+		p.ID, p.Version = prev.ID, prev.Version+1
+	}
+	for _, taboo := range input.TabooPhrases {
+		p.TabooPhrases = append(p.TabooPhrases, taboo.Phrase)
+		if taboo.Reason != "" {
+			if p.TabooReasons == nil {
+				p.TabooReasons = make(map[string]string)
+			}
+			p.TabooReasons[taboo.Phrase] = taboo.Reason
+		}
+	}
+	m.userCompanyProfiles[key] = p
+	return p, nil
+}
+
 func (m *mockDB) GetStyleRulesByProfileID(_ context.Context, _ uuid.UUID) ([]db.CompanyStyleRule, error) {
 	return []db.CompanyStyleRule{}, nil
 }
@@ -236,12 +1679,22 @@ func (m *mockDB) GetSourcesByProfileID(_ context.Context, _ uuid.UUID) ([]db.Com
 	return []db.CompanyProfileSource{}, nil
 }
 
+func (m *mockDB) ListCompanyProfileVersions(_ context.Context, companyID uuid.UUID) ([]db.CompanyProfileVersion, error) {
+	var versions []db.CompanyProfileVersion
+	for _, v := range m.profileVersions {
+		if v.CompanyID == companyID {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
 func (m *mockDB) ListJobPostings(_ context.Context, _ db.ListJobPostingsOptions) ([]db.JobPosting, int, error) {
 	return []db.JobPosting{}, 0, nil
 }
 
-func (m *mockDB) GetJobPostingByID(_ context.Context, _ uuid.UUID) (*db.JobPosting, error) {
-	return nil, nil
+func (m *mockDB) GetJobPostingByID(_ context.Context, id uuid.UUID) (*db.JobPosting, error) {
+	return m.postings[id], nil
 }
 
 func (m *mockDB) GetJobPostingByURL(_ context.Context, _ string) (*db.JobPosting, error) {
@@ -256,8 +1709,137 @@ func (m *mockDB) UpsertJobPosting(_ context.Context, _ *db.JobPostingCreateInput
 	return nil, nil
 }
 
-func (m *mockDB) GetJobProfileByID(_ context.Context, _ uuid.UUID) (*db.JobProfile, error) {
-	return nil, nil
+func (m *mockDB) ListDuplicatePostings(_ context.Context, _ uuid.UUID) ([]db.JobPosting, error) {
+	return []db.JobPosting{}, nil
+}
+
+func (m *mockDB) ExportUser(_ context.Context, userID uuid.UUID, _ bool) (*db.UserExport, error) {
+	user, ok := m.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	row, err := json.Marshal(map[string]any{"id": user.ID, "name": user.Name, "email": user.Email})
+	if err != nil {
+		return nil, err
+	}
+	export := &db.UserExport{Format: db.UserExportFormat, Version: db.UserExportVersion, UserID: userID,
+		Tables: []db.ExportedTable{{Name: "users", Rows: []json.RawMessage{row}}}}
+	var jobs []json.RawMessage
+	for _, job := range m.jobs {
+		if job.UserID == userID {
+			row, err := json.Marshal(map[string]any{"id": job.ID, "user_id": job.UserID, "company": job.Company})
+			if err != nil {
+				return nil, err
+			}
+			jobs = append(jobs, row)
+		}
+	}
+	export.Tables = append(export.Tables, db.ExportedTable{Name: "jobs", Rows: jobs})
+	return export, nil
+}
+
+func (m *mockDB) ListPostingVersions(_ context.Context, postingID uuid.UUID) ([]db.PostingVersion, error) {
+	var versions []db.PostingVersion
+	for _, v := range m.versions {
+		if v.PostingID == postingID && v.SupersededAt != nil {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+func (m *mockDB) ListCompanyPostingHistory(_ context.Context, companyID uuid.UUID, roleFamily string) ([]db.PostingVersion, error) {
+	var versions []db.PostingVersion
+	for _, v := range m.versions {
+		if v.CompanyID != nil && *v.CompanyID == companyID && (roleFamily == "" || (v.RoleFamily != nil && *v.RoleFamily == roleFamily)) {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+func (m *mockDB) GetJobRequirement(_ context.Context, id, userID uuid.UUID) (*db.JobRequirement, error) {
+	req, ok := m.requirements[id]
+	if !ok {
+		return nil, nil
+	}
+	weighted := *req
+	if weight, ok := m.reqWeights[userID.String()+":"+id.String()]; ok {
+		weighted.Weight = &weight
+	}
+	return &weighted, nil
+}
+
+func (m *mockDB) SetRequirementWeight(_ context.Context, userID uuid.UUID, req *db.JobRequirement, weight *float64) error {
+	if m.reqWeights == nil {
+		m.reqWeights = make(map[string]float64)
+	}
+	key := userID.String() + ":" + req.ID.String()
+	if weight == nil {
+		delete(m.reqWeights, key)
+	} else {
+		m.reqWeights[key] = *weight
+	}
+	return nil
+}
+
+func (m *mockDB) GetJobProfileByID(_ context.Context, id uuid.UUID) (*db.JobProfile, error) {
+	return m.jobProfiles[id], nil
+}
+
+func (m *mockDB) GetUserKeywords(_ context.Context, profileID, userID uuid.UUID) ([]db.JobKeyword, error) {
+	prefix := profileID.String() + ":" + userID.String() + ":"
+	keywords := []db.JobKeyword{}
+	if profile := m.jobProfiles[profileID]; profile != nil {
+		for _, kw := range profile.Keywords {
+			if _, changed := m.keywordChanges[prefix+db.NormalizeKeyword(kw)]; !changed {
+				source := db.KeywordSourceLLM
+				keywords = append(keywords, db.JobKeyword{JobProfileID: profileID, Keyword: kw, KeywordNormalized: db.NormalizeKeyword(kw), Source: &source})
+			}
+		}
+	}
+	for key, o := range m.keywordChanges {
+		if strings.HasPrefix(key, prefix) && !o.Excluded {
+			source := db.KeywordSourceUser
+			keywords = append(keywords, db.JobKeyword{JobProfileID: profileID, Keyword: o.Keyword, KeywordNormalized: db.NormalizeKeyword(o.Keyword), Source: &source})
+		}
+	}
+	sort.Slice(keywords, func(i, j int) bool { return keywords[i].Keyword < keywords[j].Keyword })
+	return keywords, nil
+}
+
+func (m *mockDB) AddUserKeyword(_ context.Context, profileID, userID uuid.UUID, keyword string) error {
+	if m.keywordChanges == nil {
+		m.keywordChanges = make(map[string]db.KeywordOverride)
+	}
+	m.keywordChanges[profileID.String()+":"+userID.String()+":"+db.NormalizeKeyword(keyword)] = db.KeywordOverride{Keyword: keyword}
+	return nil
+}
+
+func (m *mockDB) RemoveUserKeyword(_ context.Context, profileID, userID uuid.UUID, keyword string) (bool, error) {
+	key := profileID.String() + ":" + userID.String() + ":" + db.NormalizeKeyword(keyword)
+	shared := false
+	if profile := m.jobProfiles[profileID]; profile != nil {
+		for _, kw := range profile.Keywords {
+			shared = shared || db.NormalizeKeyword(kw) == db.NormalizeKeyword(keyword)
+		}
+	}
+	o, changed := m.keywordChanges[key]
+	if !shared {
+		if changed && !o.Excluded {
+			delete(m.keywordChanges, key)
+			return true, nil
+		}
+		return false, nil
+	}
+	if changed && o.Excluded {
+		return false, nil
+	}
+	if m.keywordChanges == nil {
+		m.keywordChanges = make(map[string]db.KeywordOverride)
+	}
+	m.keywordChanges[key] = db.KeywordOverride{Keyword: keyword, Excluded: true}
+	return true, nil
 }
 
 func (m *mockDB) GetJobProfileByPostingID(_ context.Context, _ uuid.UUID) (*db.JobProfile, error) {
@@ -268,6 +1850,40 @@ func (m *mockDB) GetRequirementsByProfileID(_ context.Context, _ uuid.UUID) ([]d
 	return []db.JobRequirement{}, nil
 }
 
+func (m *mockDB) GetSkillGap(_ context.Context, _ uuid.UUID, postingID uuid.UUID) (*db.SkillGap, error) {
+	return m.skillGaps[postingID], nil
+}
+
+func (m *mockDB) UpsertResumeTemplate(_ context.Context, userID uuid.UUID, name, content string, manifest json.RawMessage) (*db.ResumeTemplate, error) {
+	if m.resumeTemplates == nil {
+		m.resumeTemplates = make(map[uuid.UUID][]db.ResumeTemplate)
+	}
+	t := db.ResumeTemplate{ID: uuid.New(), UserID: userID, Name: name, Content: content, Manifest: manifest}
+	list := m.resumeTemplates[userID]
+	for i := range list {
+		if list[i].Name == name {
+			t.ID = list[i].ID
+			list[i] = t
+			return &t, nil
+		}
+	}
+	m.resumeTemplates[userID] = append(list, t)
+	return &t, nil
+}
+
+func (m *mockDB) ListResumeTemplates(_ context.Context, userID uuid.UUID) ([]db.ResumeTemplate, error) {
+	return append([]db.ResumeTemplate{}, m.resumeTemplates[userID]...), nil
+}
+
+func (m *mockDB) GetResumeTemplateByName(_ context.Context, userID uuid.UUID, name string) (*db.ResumeTemplate, error) {
+	for _, t := range m.resumeTemplates[userID] {
+		if t.Name == name {
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
 func (m *mockDB) GetResponsibilitiesByProfileID(_ context.Context, _ uuid.UUID) ([]db.JobResponsibility, error) {
 	return []db.JobResponsibility{}, nil
 }
@@ -280,7 +1896,7 @@ func (m *mockDB) CreateJobProfile(_ context.Context, _ *db.JobProfileCreateInput
 	return nil, nil
 }
 
-func (m *mockDB) ListStoriesByUser(_ context.Context, _ uuid.UUID) ([]db.Story, error) {
+func (m *mockDB) ListStoriesByUser(_ context.Context, _ uuid.UUID, _ int, _ db.Page) ([]db.Story, error) {
 	return []db.Story{}, nil
 }
 
@@ -288,15 +1904,25 @@ func (m *mockDB) GetStoryByID(_ context.Context, _ uuid.UUID) (*db.Story, error)
 	return nil, nil
 }
 
-func (m *mockDB) CreateStory(_ context.Context, _ *db.StoryCreateInput) (*db.Story, error) {
-	return nil, nil
+func (m *mockDB) CreateStory(_ context.Context, input *db.StoryCreateInput) (*db.Story, error) {
+	m.createdStories = append(m.createdStories, input)
+	story := &db.Story{ID: uuid.New(), StoryID: input.StoryID, UserID: input.UserID, JobID: input.JobID, Title: &input.Title}
+	for _, b := range input.Bullets {
+		story.Bullets = append(story.Bullets, db.Bullet{BulletID: b.BulletID, Text: b.Text, Skills: b.Skills})
+	}
+	return story, nil
+}
+
+func (m *mockDB) ImportExperienceBank(_ context.Context, input *db.ExperienceBankImportInput) error {
+	m.importedBanks = append(m.importedBanks, input)
+	return nil
 }
 
 func (m *mockDB) GetBulletsByStoryID(_ context.Context, _ uuid.UUID) ([]db.Bullet, error) {
 	return []db.Bullet{}, nil
 }
 
-func (m *mockDB) ListSkillsByUserID(_ context.Context, _ uuid.UUID) ([]db.Skill, error) {
+func (m *mockDB) ListSkillsByUserID(_ context.Context, _ uuid.UUID, _ int, _ db.Page) ([]db.Skill, error) {
 	return []db.Skill{}, nil
 }
 
@@ -308,11 +1934,61 @@ func (m *mockDB) GetBulletsBySkillIDAndUserID(_ context.Context, _, _ uuid.UUID)
 	return []db.Bullet{}, nil
 }
 
-func (m *mockDB) GetCrawledPageByID(_ context.Context, _ uuid.UUID) (*db.CrawledPage, error) {
+func (m *mockDB) GetOnboarding(_ context.Context, userID uuid.UUID) (*db.Onboarding, error) {
+	return m.onboarding[userID], nil
+}
+
+func (m *mockDB) TransitionOnboarding(_ context.Context, userID uuid.UUID, from, to string, skipped []string, finished bool) (*db.Onboarding, error) {
+	if m.onboarding == nil {
+		m.onboarding = make(map[uuid.UUID]*db.Onboarding)
+	}
+	if o := m.onboarding[userID]; o != nil && o.State != from {
+		return nil, nil
+	}
+	o := &db.Onboarding{UserID: userID, State: to, Skipped: skipped, UpdatedAt: time.Now()}
+	if finished {
+		o.CompletedAt = &o.UpdatedAt
+	}
+	m.onboarding[userID] = o
+	return o, nil
+}
+
+func (m *mockDB) UpsertUserSkill(_ context.Context, userID uuid.UUID, skillName string, proficiency *int, lastUsed *db.Date) (*db.UserSkill, error) {
+	if m.userSkills == nil {
+		m.userSkills = make(map[uuid.UUID][]db.UserSkill)
+	}
+	us := db.UserSkill{UserID: userID, Skill: skillName, Proficiency: proficiency, LastUsed: lastUsed, UpdatedAt: time.Now()}
+	for i, existing := range m.userSkills[userID] {
+		if strings.EqualFold(existing.Skill, skillName) {
+			us.SkillID = existing.SkillID
+			m.userSkills[userID][i] = us
+			return &us, nil
+		}
+	}
+	us.SkillID = uuid.New()
+	m.userSkills[userID] = append(m.userSkills[userID], us)
+	return &us, nil
+}
+
+func (m *mockDB) ListUserSkills(_ context.Context, userID uuid.UUID) ([]db.UserSkill, error) {
+	return append([]db.UserSkill{}, m.userSkills[userID]...), nil
+}
+
+func (m *mockDB) GetCrawledPageByID(_ context.Context, id uuid.UUID) (*db.CrawledPage, error) {
+	for i := range m.crawledPages {
+		if m.crawledPages[i].ID == id {
+			return &m.crawledPages[i], nil
+		}
+	}
 	return nil, nil
 }
 
-func (m *mockDB) GetCrawledPageByURL(_ context.Context, _ string) (*db.CrawledPage, error) {
+func (m *mockDB) GetCrawledPageByURL(_ context.Context, url string) (*db.CrawledPage, error) {
+	for i := range m.crawledPages {
+		if m.crawledPages[i].URL == url {
+			return &m.crawledPages[i], nil
+		}
+	}
 	return nil, nil
 }
 
@@ -328,8 +2004,67 @@ func (m *mockDB) GetExperienceBank(_ context.Context, _ uuid.UUID) (*types.Exper
 	return nil, nil
 }
 
-func (m *mockDB) Pool() *pgxpool.Pool {
-	return nil // Unit tests don't use Pool()
+func (m *mockDB) DeleteExpiredDebugArtifacts(_ context.Context, _ time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockDB) ClaimIdempotencyKey(_ context.Context, userID uuid.UUID, key, requestHash string, ttl, stale time.Duration) (*db.IdempotencyKey, error) {
+	if m.idempotencyKeys == nil {
+		m.idempotencyKeys = make(map[string]*db.IdempotencyKey)
+	}
+	now := time.Now()
+	if k, ok := m.idempotencyKeys[userID.String()+":"+key]; ok && k.ExpiresAt.After(now) &&
+		(k.StatusCode != nil || k.CreatedAt.After(now.Add(-stale))) {
+		return k, nil
+	}
+	m.idempotencyKeys[userID.String()+":"+key] = &db.IdempotencyKey{UserID: userID, Key: key,
+		RequestHash: requestHash, CreatedAt: now, ExpiresAt: now.Add(ttl)}
+	return nil, nil
+}
+
+func (m *mockDB) CompleteIdempotencyKey(_ context.Context, userID uuid.UUID, key string, statusCode int, response json.RawMessage) error {
+	if k, ok := m.idempotencyKeys[userID.String()+":"+key]; ok {
+		k.StatusCode, k.Response = &statusCode, response
+	}
+	return nil
+}
+
+func (m *mockDB) ReleaseIdempotencyKey(_ context.Context, userID uuid.UUID, key string) error {
+	if k, ok := m.idempotencyKeys[userID.String()+":"+key]; ok && k.StatusCode == nil {
+		delete(m.idempotencyKeys, userID.String()+":"+key)
+	}
+	return nil
+}
+
+func (m *mockDB) DeleteExpiredIdempotencyKeys(_ context.Context) (int64, error) {
+	var deleted int64
+	for id, k := range m.idempotencyKeys {
+		if !k.ExpiresAt.After(time.Now()) {
+			delete(m.idempotencyKeys, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *mockDB) MarkAbandonedRuns(_ context.Context, _ time.Duration) (int64, error) {
+	return m.runGCMarked, m.runGCErr
+}
+
+func (m *mockDB) ListLatencyStats(_ context.Context, _ time.Time, _ string, budgetsMs map[string]int64) ([]db.LatencyStats, error) {
+	m.latencyBudgets = budgetsMs
+	return m.latencyStats, nil
+}
+
+func (m *mockDB) DeleteAbandonedRuns(_ context.Context, _ time.Duration) (*db.ReclaimedRuns, error) {
+	if m.runGCReclaimed == nil {
+		return &db.ReclaimedRuns{}, nil
+	}
+	return m.runGCReclaimed, nil
+}
+
+func (m *mockDB) GetArtifactStorage(_ context.Context) (*db.ArtifactStorage, error) {
+	return m.storage, nil
 }
 
 // errorMockDB returns errors for testing error paths
@@ -363,10 +2098,40 @@ func newTestServer() *testServer {
 		db:          mock,
 		apiKey:      "test-api-key",
 		rateLimiter: ratelimit.NewLimiter(rateLimitConfig),
+		scheduler:   scheduler.New(4, 2, 2),
+	}
+	s.stepExecutor = func(_ context.Context, _ *db.Run, stepName string) (steps.StepExecutor, error) {
+		return &fakeStepExecutor{name: stepName}, nil
+	}
+	s.compilePDF = func(_ context.Context, latex string) (*compile.Result, error) {
+		return &compile.Result{PDF: []byte("%PDF-1.5 " + latex)}, nil
 	}
 	return &testServer{Server: s, mock: mock}
 }
 
+// fakeStepExecutor stands in for the pipeline's step executors, which need Postgres
+type fakeStepExecutor struct {
+	name   string
+	err    error                  // Returned by Execute
+	params map[string]interface{} // Parameters Execute was called with
+}
+
+func (f *fakeStepExecutor) Name() string           { return f.name }
+func (f *fakeStepExecutor) Category() string       { return steps.StepRegistry[f.name].Category }
+func (f *fakeStepExecutor) Dependencies() []string { return steps.StepRegistry[f.name].Dependencies }
+
+func (f *fakeStepExecutor) ValidateDependencies(ctx context.Context, client steps.Client, runID uuid.UUID) error {
+	return steps.ValidateDependencies(ctx, client, runID, f.name)
+}
+
+func (f *fakeStepExecutor) Execute(_ context.Context, _ uuid.UUID, params map[string]interface{}) (*steps.StepResult, error) {
+	f.params = params
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &steps.StepResult{Step: f.name, Status: db.StepStatusCompleted}, nil
+}
+
 func newTestServerWithRateLimit(enabled bool, limit int, window time.Duration) *testServer {
 	mock := newMockDB()
 	rateLimitConfig := &ratelimit.Config{
@@ -409,6 +2174,25 @@ func TestHealthEndpoint(t *testing.T) {
 	}
 }
 
+// TestErrorResponse_ScrubsDatabaseErrors tests that db errors returned to clients leave out PII and credentials
+func TestErrorResponse_ScrubsDatabaseErrors(t *testing.T) {
+	s := newTestServer()
+	w := httptest.NewRecorder()
+	s.errorResponse(w, http.StatusInternalServerError,
+		`Database error: duplicate key value violates unique constraint "users_email_key": Key (email)=(jane@example.com) already exists`)
+
+	var resp map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if strings.Contains(resp["error"], "jane@example.com") {
+		t.Errorf("expected the email to be scrubbed, got %q", resp["error"])
+	}
+	if !strings.Contains(resp["error"], "users_email_key") {
+		t.Errorf("expected the constraint to be kept, got %q", resp["error"])
+	}
+}
+
 // TestRunEndpoint_MissingJobURL tests /run with missing required field
 func TestRunEndpoint_MissingJobURL(t *testing.T) {
 	s := newTestServer()
@@ -466,6 +2250,132 @@ func TestRunEndpoint_InvalidJSON(t *testing.T) {
 	}
 }
 
+// TestRunEndpoint_InvalidPriority tests /run with an unknown scheduling priority
+func TestRunEndpoint_InvalidPriority(t *testing.T) {
+	s := newTestServer()
+
+	body := `{"job_url": "https://example.com/job", "user_id": "` + uuid.New().String() + `", "priority": "urgent"}`
+	req := httptest.NewRequest(http.MethodPost, "/run", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	s.handleRun(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "invalid priority") {
+		t.Errorf("expected priority error, got %s", w.Body.String())
+	}
+}
+
+func TestRunStreamEndpoint_CanceledWhileQueued(t *testing.T) {
+	s := newTestServer()
+	s.scheduler = scheduler.New(1, 1, 1)
+	s.databaseURL = "postgres://test"
+	uid := uuid.New()
+
+	// Occupy the user's only slot so the streamed run has to queue
+	release := make(chan struct{})
+	defer close(release)
+	if _, err := s.scheduler.Submit(context.Background(), scheduler.Task{UserID: uid, Run: func(context.Context) { <-release }}); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	body := `{"job_url": "https://example.com/job", "user_id": "` + uid.String() + `", "name": "Jane", "email": "jane@example.com"}`
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodPost, "/run/stream", bytes.NewBufferString(body)).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	s.handleRunStream(w, req)
+
+	if len(s.mock.runs) != 1 {
+		t.Fatalf("expected one run record, got %d", len(s.mock.runs))
+	}
+	for _, run := range s.mock.runs {
+		if run.Status != "canceled" {
+			t.Errorf("expected queued run to be canceled, got status %q", run.Status)
+		}
+	}
+}
+
+func TestRunStreamEndpoint_QueueFull(t *testing.T) {
+	s := newTestServer()
+	s.scheduler = scheduler.New(1, 1, 1)
+	uid := uuid.New()
+
+	// Occupy the user's only slot and queue slot
+	release := make(chan struct{})
+	defer close(release)
+	block := func(context.Context) { <-release }
+	for i := 0; i < 2; i++ {
+		_, err := s.scheduler.Submit(context.Background(), scheduler.Task{UserID: uid, Run: block})
+		if err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+
+	body := `{"job_url": "https://example.com/job", "user_id": "` + uid.String() + `", "name": "Jane", "email": "jane@example.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/run/stream", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	s.handleRunStream(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp["error"] != "run_queue_full" {
+		t.Errorf("expected run_queue_full error, got %v", resp["error"])
+	}
+	if resp["running"] != float64(1) || resp["queued"] != float64(1) {
+		t.Errorf("expected running=1 queued=1, got %v/%v", resp["running"], resp["queued"])
+	}
+}
+
+func TestRunStreamEndpoint_AdmissionBacklog(t *testing.T) {
+	s := newTestServer()
+	s.scheduler = scheduler.New(1, 1, 1).WithAdmission(1, time.Minute)
+	s.databaseURL = "postgres://test"
+	uid := uuid.New()
+
+	// Fill the user's slot and queue so the streamed run waits in the backlog
+	release := make(chan struct{})
+	defer close(release)
+	block := func(context.Context) { <-release }
+	for i := 0; i < 2; i++ {
+		if _, err := s.scheduler.Submit(context.Background(), scheduler.Task{UserID: uid, Run: block}); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+
+	body := `{"job_url": "https://example.com/job", "user_id": "` + uid.String() + `", "name": "Jane", "email": "jane@example.com"}`
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodPost, "/run/stream", bytes.NewBufferString(body)).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	s.handleRunStream(w, req)
+
+	if w.Code == http.StatusTooManyRequests {
+		t.Fatalf("expected the run to wait in the backlog, got 429: %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "event: queued") {
+		t.Fatalf("expected a queued event, got %s", w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"queue_position":2,"estimated_wait_seconds":120`) {
+		t.Errorf("expected position 2 with a two-minute estimate, got %s", w.Body.String())
+	}
+}
+
 // TestStatusEndpoint_InvalidID tests /status with invalid UUID
 func TestStatusEndpoint_InvalidID(t *testing.T) {
 	s := newTestServer()
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/notifications"
+)
+
+// newAuthTestServer returns a test server that issues and checks real JWTs, with the
+// given users on the admin allowlist
+func newAuthTestServer(t *testing.T, admins ...uuid.UUID) *testServer {
+	t.Helper()
+	s := newTestServer()
+	s.jwtService = NewJWTService(&config.JWTConfig{
+		Secret:          "test-secret-key-for-jwt-signing-minimum-32-bytes",
+		ExpirationHours: 1,
+	})
+	s.debugConfig = &config.DebugConfig{RetentionHours: 72}
+	s.admins = &config.AdminConfig{UserIDs: make(map[uuid.UUID]bool)}
+	for _, id := range admins {
+		s.admins.UserIDs[id] = true
+	}
+	return s
+}
+
+// bearerRequest builds a request carrying a Bearer token for userID
+func bearerRequest(t *testing.T, s *testServer, method, target string, userID uuid.UUID, body []byte) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	token, err := s.jwtService.GenerateToken(userID)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+// serveAuthed serves req through withAuth on a mux holding only pattern, so path values
+// and the caller's user ID are set as in production
+func serveAuthed(t *testing.T, s *testServer, pattern string, handler http.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(pattern, s.withAuth(handler))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+// newNotificationTestServer is newAuthTestServer with a notifier that may post to
+// loopback webhooks
+func newNotificationTestServer(t *testing.T) *testServer {
+	t.Helper()
+	s := newAuthTestServer(t)
+	s.notify = &config.NotificationConfig{SMTPPort: 587, DigestIntervalHours: 168, ReminderLeadHours: 24,
+		AllowPrivateWebhooks: true} // Webhooks go to httptest servers on loopback
+	s.notifier = notifications.New(s.notify)
+	return s
+}
+
+// webhookRecorder collects the notifications POSTed to it
+type webhookRecorder struct {
+	mu       sync.Mutex
+	messages []notifications.Message
+}
+
+func (rec *webhookRecorder) server(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg notifications.Message
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&msg))
+		rec.mu.Lock()
+		rec.messages = append(rec.messages, msg)
+		rec.mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
@@ -45,6 +45,15 @@ func (s *SSEWriter) WriteEvent(event string, data any) error {
 	return nil
 }
 
+// WriteComment sends an SSE comment line, which clients ignore (used for keepalives)
+func (s *SSEWriter) WriteComment(text string) error {
+	if _, err := fmt.Fprintf(s.w, ": %s\n\n", text); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
 // WriteError sends an error event
 func (s *SSEWriter) WriteError(message string) {
 	s.WriteEvent("error", map[string]string{"error": message}) //nolint:errcheck
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"fmt"
+	"time"
+)
+
+// formatTimestamp writes a time for an API response. Every timestamp the API returns is
+// RFC 3339 in UTC, whatever zone the server or database runs in.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// loadTimezone returns the time zone named by an IANA name such as "Europe/Berlin",
+// or UTC when name is empty
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	// "Local" is the server's zone, which means nothing to a client
+	if name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-10T15:00:00Z", formatTimestamp(time.Date(2026, 3, 11, 0, 0, 0, 0, tokyo)))
+}
+
+func TestHandleUpdateTimezone(t *testing.T) {
+	user := uuid.New()
+	s := newAuthTestServer(t)
+	s.mock.users = map[uuid.UUID]*db.User{user: {ID: user, Timezone: db.DefaultTimezone}}
+	target := "/v1/users/" + user.String() + "/timezone"
+	put := func(caller uuid.UUID, body string) int {
+		w := serveAuthed(t, s, "PUT /v1/users/{id}/timezone", s.handleUpdateTimezone,
+			bearerRequest(t, s, http.MethodPut, target, caller, []byte(body)))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusForbidden, put(uuid.New(), `{"timezone":"Europe/Berlin"}`))
+	assert.Equal(t, http.StatusBadRequest, put(user, `{}`))
+	assert.Equal(t, http.StatusBadRequest, put(user, `{"timezone":"Mars/Olympus"}`))
+	assert.Equal(t, http.StatusBadRequest, put(user, `{"timezone":"Local"}`))
+	assert.Equal(t, db.DefaultTimezone, s.mock.users[user].Timezone)
+
+	w := serveAuthed(t, s, "PUT /v1/users/{id}/timezone", s.handleUpdateTimezone,
+		bearerRequest(t, s, http.MethodPut, target, user, []byte(`{"timezone":"Europe/Berlin"}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp TimezoneRequest
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Europe/Berlin", resp.Timezone)
+	assert.Equal(t, "Europe/Berlin", s.mock.users[user].Timezone)
+}
@@ -4,8 +4,10 @@ package server
 import (
 	"context"
 	"fmt"
+	"log/slog"
 
 	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/breach"
 	"github.com/jonathan/resume-customizer/internal/config"
 	"github.com/jonathan/resume-customizer/internal/db"
 	"github.com/jonathan/resume-customizer/internal/types"
@@ -15,6 +17,8 @@ import (
 type UserService struct {
 	db             DBClient
 	passwordConfig *config.PasswordConfig
+	// breachCheck rejects passwords found in known data breaches; nil accepts them
+	breachCheck *breach.Checker
 }
 
 // NewUserService creates a new UserService with the given dependencies
@@ -25,19 +29,45 @@ func NewUserService(db DBClient, passwordConfig *config.PasswordConfig) *UserSer
 	}
 }
 
+// WithBreachCheck has registration and password changes reject passwords checker reports
+// as breached
+func (s *UserService) WithBreachCheck(checker *breach.Checker) *UserService {
+	s.breachCheck = checker
+	return s
+}
+
+// checkBreached returns a validation error for field when password is a known breached
+// password. When the check itself fails the password is accepted, so an unreachable API
+// cannot block sign-ups.
+func (s *UserService) checkBreached(ctx context.Context, field, password string) error {
+	if s.breachCheck == nil {
+		return nil
+	}
+	breached, err := s.breachCheck.Breached(ctx, password)
+	if err != nil {
+		slog.WarnContext(ctx, "Skipped the password breach check", "error", err)
+		return nil
+	}
+	if breached {
+		return &ErrValidation{Field: field, Message: "this password has appeared in a known data breach; choose a different one"}
+	}
+	return nil
+}
+
 // convertDBUserToTypesUser converts db.User to types.User, excluding password hash
 func convertDBUserToTypesUser(dbUser *db.User) *types.User {
 	if dbUser == nil {
 		return nil
 	}
 	return &types.User{
-		ID:          dbUser.ID,
-		Name:        dbUser.Name,
-		Email:       dbUser.Email,
-		Phone:       dbUser.Phone,
-		PasswordSet: dbUser.PasswordSet,
-		CreatedAt:   dbUser.CreatedAt,
-		UpdatedAt:   dbUser.UpdatedAt,
+		ID:            dbUser.ID,
+		Name:          dbUser.Name,
+		Email:         dbUser.Email,
+		Phone:         dbUser.Phone,
+		PasswordSet:   dbUser.PasswordSet,
+		EmailVerified: dbUser.EmailVerifiedAt != nil,
+		CreatedAt:     dbUser.CreatedAt,
+		UpdatedAt:     dbUser.UpdatedAt,
 	}
 }
 
@@ -52,6 +82,10 @@ func (s *UserService) Register(ctx context.Context, req *types.CreateUserRequest
 		return nil, &ErrEmailAlreadyExists{Email: req.Email}
 	}
 
+	if err := s.checkBreached(ctx, "password", req.Password); err != nil {
+		return nil, err
+	}
+
 	// Hash password
 	passwordHash, err := s.passwordConfig.HashPassword(req.Password)
 	if err != nil {
@@ -128,6 +162,10 @@ func (s *UserService) UpdatePassword(ctx context.Context, userID uuid.UUID, curr
 		return &ErrPasswordMismatch{}
 	}
 
+	if err := s.checkBreached(ctx, "new_password", newPassword); err != nil {
+		return err
+	}
+
 	// Hash new password
 	newPasswordHash, err := s.passwordConfig.HashPassword(newPassword)
 	if err != nil {
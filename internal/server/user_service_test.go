@@ -1,12 +1,17 @@
 package server
 
 import (
+	"context"
+	"crypto/sha1"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/breach"
 	"github.com/jonathan/resume-customizer/internal/config"
 	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -74,3 +79,34 @@ func TestUserService_UpdatePassword(t *testing.T) {
 		assert.NotNil(t, passwordConfig)
 	})
 }
+
+func TestUserService_RejectsBreachedPasswords(t *testing.T) {
+	filter := breach.NewFilter(10, 0.001)
+	filter.Add(sha1.Sum([]byte("password123")))
+	mock := newMockDB()
+	passwordConfig := &config.PasswordConfig{BcryptCost: 10}
+	svc := NewUserService(mock, passwordConfig).WithBreachCheck(breach.NewChecker("", filter, time.Second))
+	ctx := context.Background()
+
+	_, err := svc.Register(ctx, &types.CreateUserRequest{Name: "Ada", Email: "ada@example.com", Password: "password123"})
+	var validationErr *ErrValidation
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "password", validationErr.Field)
+	assert.Contains(t, err.Error(), "known data breach")
+	assert.Equal(t, http.StatusBadRequest, HTTPStatus(err))
+	assert.Empty(t, mock.users, "no account is created")
+
+	user, err := svc.Register(ctx, &types.CreateUserRequest{Name: "Ada", Email: "ada@example.com", Password: "correct horse battery staple"})
+	require.NoError(t, err)
+
+	hash, err := passwordConfig.HashPassword("correct horse battery staple")
+	require.NoError(t, err)
+	mock.users[user.ID].PasswordHash = hash
+	err = svc.UpdatePassword(ctx, user.ID, "correct horse battery staple", "password123")
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "new_password", validationErr.Field)
+
+	// A check that cannot run does not block the change
+	svc.WithBreachCheck(breach.NewChecker("http://127.0.0.1:0", nil, time.Second))
+	assert.NoError(t, svc.UpdatePassword(ctx, user.ID, "correct horse battery staple", "password123"))
+}
@@ -0,0 +1,248 @@
+// Minimal UI for the Resume Customizer API: sign in, start a streaming run, follow its
+// steps, preview the stored artifacts, and download the resume. Session state lives in
+// sessionStorage so a tab forgets it when closed.
+"use strict";
+
+const $ = (id) => document.getElementById(id);
+
+const session = {
+  get token() { return sessionStorage.getItem("token"); },
+  get userID() { return sessionStorage.getItem("user_id"); },
+  save(token, userID) {
+    sessionStorage.setItem("token", token);
+    sessionStorage.setItem("user_id", userID);
+  },
+  clear() { sessionStorage.clear(); },
+};
+
+let currentRunID = null;
+
+function showError(message) {
+  $("error").textContent = message;
+  $("error").hidden = !message;
+}
+
+function render() {
+  const signedIn = Boolean(session.token);
+  $("login-view").hidden = signedIn;
+  $("run-view").hidden = !signedIn;
+  $("logout").hidden = !signedIn;
+}
+
+async function api(path, options = {}) {
+  const headers = Object.assign({}, options.headers);
+  if (session.token) headers.Authorization = "Bearer " + session.token;
+  const res = await fetch(path, Object.assign({}, options, { headers }));
+  if (res.status === 401) {
+    session.clear();
+    render();
+  }
+  if (!res.ok) {
+    let message = res.status + " " + res.statusText;
+    try {
+      const body = await res.json();
+      if (body.error) message = body.error;
+    } catch (_) { /* not JSON */ }
+    throw new Error(message);
+  }
+  return res;
+}
+
+async function login(event) {
+  event.preventDefault();
+  showError("");
+  const form = new FormData(event.target);
+  try {
+    const res = await api("/v1/auth/login", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ email: form.get("email"), password: form.get("password") }),
+    });
+    const body = await res.json();
+    session.save(body.token, body.user.id);
+    event.target.reset();
+    render();
+  } catch (err) {
+    showError(err.message);
+  }
+}
+
+function addStep(step, message) {
+  const li = document.createElement("li");
+  const name = document.createElement("span");
+  name.className = "step";
+  name.textContent = step;
+  li.append(name, document.createTextNode(message));
+  $("steps").append(li);
+}
+
+function setStatus(status) {
+  $("run-status").textContent = status ? "(" + status + ")" : "";
+  $("run-status").className = status;
+}
+
+// readEvents calls onEvent(name, data) for each Server-Sent Event in a fetch response.
+// EventSource can't send a POST body or an Authorization header, so the stream is
+// parsed by hand.
+async function readEvents(res, onEvent) {
+  const reader = res.body.pipeThrough(new TextDecoderStream()).getReader();
+  let buffer = "";
+  for (;;) {
+    const { value, done } = await reader.read();
+    if (done) return;
+    buffer += value;
+    let end;
+    while ((end = buffer.indexOf("\n\n")) >= 0) {
+      const block = buffer.slice(0, end);
+      buffer = buffer.slice(end + 2);
+      let name = "message";
+      let data = "";
+      for (const line of block.split("\n")) {
+        if (line.startsWith("event: ")) name = line.slice(7);
+        else if (line.startsWith("data: ")) data += line.slice(6);
+      }
+      if (data) onEvent(name, JSON.parse(data));
+    }
+  }
+}
+
+async function startRun(event) {
+  event.preventDefault();
+  showError("");
+  const button = event.target.querySelector("button");
+  const jobURL = new FormData(event.target).get("job_url");
+  currentRunID = null;
+  $("steps").replaceChildren();
+  $("results").hidden = true;
+  $("progress").hidden = false;
+  setStatus("starting");
+  button.disabled = true;
+
+  let finalStatus = "failed";
+  try {
+    const res = await api("/run/stream", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ job_url: jobURL, user_id: session.userID }),
+    });
+    await readEvents(res, (name, data) => {
+      switch (name) {
+        case "step":
+          if (data.run_id) currentRunID = data.run_id;
+          addStep(data.step, data.message);
+          setStatus("running");
+          break;
+        case "queued":
+          setStatus("queued, position " + data.queue_position);
+          break;
+        case "error":
+          showError(data.error);
+          break;
+        case "complete":
+          finalStatus = data.status;
+          break;
+      }
+    });
+  } catch (err) {
+    showError(err.message);
+  } finally {
+    button.disabled = false;
+  }
+  setStatus(finalStatus);
+  if (currentRunID) {
+    await loadArtifacts(currentRunID);
+    await loadPreview(currentRunID);
+  }
+}
+
+// loadPreview shows the run's rendered resume, plan, and bullets. The server builds
+// the fragments from escaped text, so they are inserted as HTML.
+async function loadPreview(runID) {
+  const path = "/v1/runs/" + encodeURIComponent(runID) + "/preview";
+  $("full-preview").href = path + "?format=html";
+  try {
+    const res = await api(path);
+    const preview = await res.json();
+    const container = $("run-preview");
+    container.replaceChildren();
+    for (const [title, fragment] of [["Resume", preview.resume_html], ["Plan", preview.plan_html], ["Bullets", preview.bullets_html]]) {
+      if (!fragment) continue;
+      const details = document.createElement("details");
+      details.open = title === "Resume";
+      const summary = document.createElement("summary");
+      summary.textContent = title;
+      const body = document.createElement("div");
+      body.className = title === "Resume" ? "resume" : "";
+      body.innerHTML = fragment;
+      details.append(summary, body);
+      container.append(details);
+    }
+  } catch (err) {
+    showError(err.message);
+  }
+}
+
+async function loadArtifacts(runID) {
+  try {
+    const res = await api("/v1/runs/" + encodeURIComponent(runID) + "/artifacts");
+    const body = await res.json();
+    const list = $("artifacts");
+    list.replaceChildren();
+    for (const artifact of body.artifacts || []) {
+      const li = document.createElement("li");
+      const link = document.createElement("a");
+      link.href = "#";
+      link.textContent = artifact.step;
+      link.addEventListener("click", (e) => {
+        e.preventDefault();
+        previewArtifact(artifact.id);
+      });
+      const category = document.createElement("span");
+      category.className = "category";
+      category.textContent = artifact.category;
+      li.append(link, category);
+      list.append(li);
+    }
+    $("preview").hidden = true;
+    $("results").hidden = false;
+  } catch (err) {
+    showError(err.message);
+  }
+}
+
+async function previewArtifact(id) {
+  try {
+    const res = await api("/v1/artifact/" + encodeURIComponent(id));
+    const artifact = await res.json();
+    $("preview").textContent = artifact.text_content || JSON.stringify(artifact.content, null, 2);
+    $("preview").hidden = false;
+  } catch (err) {
+    showError(err.message);
+  }
+}
+
+async function downloadResume() {
+  if (!currentRunID) return;
+  try {
+    const res = await api("/v1/runs/" + encodeURIComponent(currentRunID) + "/resume.tex");
+    const url = URL.createObjectURL(await res.blob());
+    const a = document.createElement("a");
+    a.href = url;
+    a.download = "resume.tex";
+    a.click();
+    URL.revokeObjectURL(url);
+  } catch (err) {
+    showError(err.message);
+  }
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+  $("login-form").addEventListener("submit", login);
+  $("run-form").addEventListener("submit", startRun);
+  $("download").addEventListener("click", downloadResume);
+  $("logout").addEventListener("click", () => {
+    session.clear();
+    render();
+  });
+  render();
+});
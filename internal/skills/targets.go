@@ -17,19 +17,23 @@ const (
 	weightHardRequirement = 1.0
 	weightNiceToHave      = 0.5
 	weightKeyword         = 0.3
+	weightUserKeyword     = 0.6 // Keywords the user added count for more than parsed ones
 
 	// Source constants
 	sourceHardRequirement = "hard_requirement"
 	sourceNiceToHave      = "nice_to_have"
 	sourceKeyword         = "keyword"
+	sourceUserKeyword     = "user_keyword"
 )
 
 // BuildSkillTargets builds a weighted list of target skills from a JobProfile.
 // Skills are normalized, deduplicated (taking max weight when duplicates exist),
-// and sorted by weight (descending).
+// and sorted by weight (descending). A requirement with a user-set Weight gets exactly
+// that weight, whatever else the skill appears as.
 func BuildSkillTargets(jobProfile *types.JobProfile) (*types.SkillTargets, error) {
 	// Map: normalized skill name -> skill info (weight, source)
 	skillMap := make(map[string]*skillInfo)
+	overrides := make(map[string]float64)
 
 	// Process hard requirements
 	for _, req := range jobProfile.HardRequirements {
@@ -38,6 +42,7 @@ func BuildSkillTargets(jobProfile *types.JobProfile) (*types.SkillTargets, error
 			continue
 		}
 		addOrUpdateSkill(skillMap, normalizedSkill, weightHardRequirement, sourceHardRequirement)
+		addOverride(overrides, normalizedSkill, req.Weight)
 	}
 
 	// Process nice-to-haves
@@ -47,6 +52,7 @@ func BuildSkillTargets(jobProfile *types.JobProfile) (*types.SkillTargets, error
 			continue
 		}
 		addOrUpdateSkill(skillMap, normalizedSkill, weightNiceToHave, sourceNiceToHave)
+		addOverride(overrides, normalizedSkill, req.Weight)
 	}
 
 	// Process keywords
@@ -57,6 +63,18 @@ func BuildSkillTargets(jobProfile *types.JobProfile) (*types.SkillTargets, error
 		}
 		addOrUpdateSkill(skillMap, normalizedSkill, weightKeyword, sourceKeyword)
 	}
+	for _, keyword := range jobProfile.UserKeywords {
+		normalizedSkill := parsing.NormalizeSkillName(keyword)
+		if normalizedSkill == "" {
+			continue
+		}
+		addOrUpdateSkill(skillMap, normalizedSkill, weightUserKeyword, sourceUserKeyword)
+	}
+
+	// User-set requirement weights replace the computed ones
+	for name, weight := range overrides {
+		skillMap[name].weight = weight
+	}
 
 	// Convert map to slice
 	skills := make([]types.Skill, 0, len(skillMap))
@@ -165,6 +183,17 @@ func addOrUpdateSkill(skillMap map[string]*skillInfo, skillName string, weight f
 	}
 }
 
+// addOverride records a requirement's user-set weight, keeping the larger weight when
+// the skill is listed more than once
+func addOverride(overrides map[string]float64, skillName string, weight *float64) {
+	if weight == nil {
+		return
+	}
+	if existing, exists := overrides[skillName]; !exists || *weight > existing {
+		overrides[skillName] = *weight
+	}
+}
+
 // getSourcePriority returns a numeric priority for source types.
 // Higher numbers indicate higher priority.
 func getSourcePriority(source string) int {
@@ -65,6 +65,25 @@ func TestBuildSkillTargets_OnlyKeywords(t *testing.T) {
 	}
 }
 
+func TestBuildSkillTargets_UserKeywords(t *testing.T) {
+	profile := &types.JobProfile{
+		HardRequirements: []types.Requirement{{Skill: "Go"}},
+		Keywords:         []string{"microservices", "Terraform", "Go"},
+		UserKeywords:     []string{"Terraform", "Go"},
+	}
+
+	targets, err := BuildSkillTargets(profile)
+	require.NoError(t, err)
+	weights := make(map[string]types.Skill)
+	for _, skill := range targets.Skills {
+		weights[skill.Name] = skill
+	}
+	assert.Equal(t, 0.6, weights["Terraform"].Weight)
+	assert.Equal(t, "user_keyword", weights["Terraform"].Source)
+	assert.Equal(t, 0.3, weights["Microservices"].Weight)
+	assert.Equal(t, 1.0, weights["Go"].Weight, "a hard requirement still outweighs a user keyword")
+}
+
 func TestBuildSkillTargets_MixedSources(t *testing.T) {
 	profile := &types.JobProfile{
 		HardRequirements: []types.Requirement{
@@ -213,6 +232,32 @@ func TestBuildSkillTargets_SortingByWeight(t *testing.T) {
 	assert.Equal(t, "HardSkill", targets.Skills[0].Name)
 }
 
+func TestBuildSkillTargets_UserWeights(t *testing.T) {
+	important, ignored := 2.5, 0.0
+	profile := &types.JobProfile{
+		HardRequirements: []types.Requirement{
+			{Skill: "Go", Evidence: "Required"},
+			{Skill: "Terraform", Evidence: "Required", Weight: &ignored},
+		},
+		NiceToHaves: []types.Requirement{
+			{Skill: "Kafka", Evidence: "Preferred", Weight: &important},
+		},
+		Keywords: []string{"Terraform"},
+	}
+
+	targets, err := BuildSkillTargets(profile)
+	require.NoError(t, err)
+	require.Len(t, targets.Skills, 3)
+
+	weights := make(map[string]float64)
+	for _, skill := range targets.Skills {
+		weights[skill.Name] = skill.Weight
+	}
+	assert.Equal(t, map[string]float64{"Kafka": 2.5, "Go": 1.0, "Terraform": 0.0}, weights,
+		"user weights replace defaults, even when lower than another source's")
+	assert.Equal(t, "Kafka", targets.Skills[0].Name)
+}
+
 func TestBuildSkillTargets_WeightsMatchRules(t *testing.T) {
 	profile := &types.JobProfile{
 		HardRequirements: []types.Requirement{
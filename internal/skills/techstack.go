@@ -0,0 +1,136 @@
+// Package skills - techstack.go detects the technologies a company mentions in its postings and blog.
+package skills
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/parsing"
+)
+
+// Technology is a technology detected in company text and how often it was mentioned
+type Technology struct {
+	Name     string `json:"name"`
+	Category string `json:"category"` // language, infrastructure, cloud, data, database, frontend, api, ml
+	Mentions int    `json:"mentions"`
+}
+
+// techPattern matches one technology in free text
+type techPattern struct {
+	name     string
+	category string
+	pattern  *regexp.Regexp
+}
+
+// listSeparator joins items in a list such as "Python, Go" or "Java or Go"
+const listSeparator = `(?:\s*[,/]\s*(?:and\s+|or\s+)?|\s+(?:and|or)\s+)`
+
+// techCatalog lists the technologies recognized in company text. Names that are also
+// ordinary words (Go, Swift, Spark) only count with surrounding tech context. Names match the
+// canonical forms produced by parsing.NormalizeSkillName so they compare with story skills.
+var techCatalog = []techPattern{
+	{"Go", "language", regexp.MustCompile(`(?i:\bgolang\b)|\bGo\s+(?i:lang(?:uage)?|developers?|engineers?|programming|services|microservices|code(?:base)?)\b|(?i:\b(?:written in|in|using|with)\s+)Go\b|` +
+		`(?i:\b(?:python|java|rust|kotlin|scala|typescript|ruby|c\+\+))` + listSeparator + `Go\b|\bGo` + listSeparator + `(?i:(?:python|java|rust|kotlin|scala|typescript|ruby)\b|c\+\+)`)},
+	{"Python", "language", regexp.MustCompile(`(?i)\bpython\b`)},
+	{"Java", "language", regexp.MustCompile(`(?i)\bjava\b`)},
+	{"Kotlin", "language", regexp.MustCompile(`(?i)\bkotlin\b`)},
+	{"Scala", "language", regexp.MustCompile(`(?i)\bscala\b`)},
+	{"Rust", "language", regexp.MustCompile(`\bRust\b`)},
+	{"TypeScript", "language", regexp.MustCompile(`(?i)\btypescript\b`)},
+	{"JavaScript", "language", regexp.MustCompile(`(?i)\bjavascript\b`)},
+	{"Ruby", "language", regexp.MustCompile(`\bRuby\b|(?i:\brails\b)`)},
+	{"C++", "language", regexp.MustCompile(`(?i)\bc\+\+`)},
+	{"C#", "language", regexp.MustCompile(`(?i)\bc#`)},
+	{"Elixir", "language", regexp.MustCompile(`(?i)\belixir\b`)},
+	{"PHP", "language", regexp.MustCompile(`\bPHP\b`)},
+	{"Swift", "language", regexp.MustCompile(`\bSwiftUI\b|\b(?:iOS|Objective-C|Xcode|macOS)\b[^.\n]{0,40}\bSwift\b|\bSwift\b[^.\n]{0,40}\b(?:iOS|Objective-C|Xcode|macOS)\b`)},
+	{"Kubernetes", "infrastructure", regexp.MustCompile(`(?i)\b(kubernetes|k8s)\b`)},
+	{"Docker", "infrastructure", regexp.MustCompile(`(?i)\bdocker\b`)},
+	{"Terraform", "infrastructure", regexp.MustCompile(`(?i)\bterraform\b`)},
+	{"AWS", "cloud", regexp.MustCompile(`\bAWS\b|(?i:\bamazon web services\b)`)},
+	{"GCP", "cloud", regexp.MustCompile(`\bGCP\b|(?i:\bgoogle cloud\b)`)},
+	{"Azure", "cloud", regexp.MustCompile(`\bAzure\b`)},
+	{"Kafka", "data", regexp.MustCompile(`(?i)\bkafka\b`)},
+	{"RabbitMQ", "data", regexp.MustCompile(`(?i)\brabbitmq\b`)},
+	{"Spark", "data", regexp.MustCompile(`\bApache Spark\b|\bPySpark\b|\bSpark\s+(?i:streaming|sql|jobs?|clusters?)\b|` +
+		`(?i:\b(?:hadoop|databricks|kafka|flink|airflow|hive|scala|etl)\b)[^.\n]{0,40}\bSpark\b|\bSpark\b[^.\n]{0,40}(?i:\b(?:hadoop|databricks|kafka|flink|airflow|hive|scala|etl)\b)`)},
+	{"Flink", "data", regexp.MustCompile(`(?i)\bflink\b`)},
+	{"Airflow", "data", regexp.MustCompile(`(?i)\bairflow\b`)},
+	{"Snowflake", "data", regexp.MustCompile(`\bSnowflake\b`)},
+	{"PostgreSQL", "database", regexp.MustCompile(`(?i)\b(postgresql|postgres)\b`)},
+	{"MySQL", "database", regexp.MustCompile(`(?i)\bmysql\b`)},
+	{"Redis", "database", regexp.MustCompile(`(?i)\bredis\b`)},
+	{"MongoDB", "database", regexp.MustCompile(`(?i)\bmongo(db)?\b`)},
+	{"Cassandra", "database", regexp.MustCompile(`(?i)\bcassandra\b`)},
+	{"DynamoDB", "database", regexp.MustCompile(`(?i)\bdynamodb\b`)},
+	{"Elasticsearch", "database", regexp.MustCompile(`(?i)\belasticsearch\b`)},
+	{"React", "frontend", regexp.MustCompile(`(?i)\breact(\.js|js)?\b`)},
+	{"Vue", "frontend", regexp.MustCompile(`(?i)\bvue(\.js|js)?\b`)},
+	{"Node.js", "frontend", regexp.MustCompile(`(?i)\bnode(\.js|js)\b`)},
+	{"GraphQL", "api", regexp.MustCompile(`(?i)\bgraphql\b`)},
+	{"gRPC", "api", regexp.MustCompile(`(?i)\bgrpc\b`)},
+	{"TensorFlow", "ml", regexp.MustCompile(`(?i)\btensorflow\b`)},
+	{"PyTorch", "ml", regexp.MustCompile(`(?i)\bpytorch\b`)},
+}
+
+// DetectTechStack counts mentions of known technologies across texts,
+// most-mentioned first (ties broken by name)
+func DetectTechStack(texts ...string) []Technology {
+	counts := make(map[string]int)
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+		for _, tech := range techCatalog {
+			if n := len(tech.pattern.FindAllStringIndex(text, -1)); n > 0 {
+				counts[tech.name] += n
+			}
+		}
+	}
+
+	stack := make([]Technology, 0, len(counts))
+	for _, tech := range techCatalog {
+		if n := counts[tech.name]; n > 0 {
+			stack = append(stack, Technology{Name: tech.name, Category: tech.category, Mentions: n})
+		}
+	}
+	sort.SliceStable(stack, func(i, j int) bool {
+		if stack[i].Mentions != stack[j].Mentions {
+			return stack[i].Mentions > stack[j].Mentions
+		}
+		return stack[i].Name < stack[j].Name
+	})
+	return stack
+}
+
+// TechNames returns the names of technologies in order
+func TechNames(stack []Technology) []string {
+	names := make([]string, 0, len(stack))
+	for _, tech := range stack {
+		names = append(names, tech.Name)
+	}
+	return names
+}
+
+// InTechStack reports whether skill names one of the technologies in stack
+func InTechStack(skill string, stack []string) bool {
+	normalized := parsing.NormalizeSkillName(skill)
+	for _, tech := range stack {
+		if strings.EqualFold(normalized, tech) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatTechStack renders up to max technologies as a short sentence, e.g. "they use Go, Kubernetes, Kafka"
+func FormatTechStack(stack []string, max int) string {
+	if len(stack) == 0 {
+		return ""
+	}
+	if max > 0 && len(stack) > max {
+		stack = stack[:max]
+	}
+	return "they use " + strings.Join(stack, ", ")
+}
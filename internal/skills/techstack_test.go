@@ -0,0 +1,52 @@
+package skills
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTechStack(t *testing.T) {
+	posting := "We build backend services in Go and Golang tooling, deployed on K8s with Kafka streams."
+	blog := "How we scaled Kafka to millions of events. Our Kubernetes clusters run on AWS."
+
+	stack := DetectTechStack(posting, blog)
+	require.NotEmpty(t, stack)
+	assert.Equal(t, []string{"Go", "Kafka", "Kubernetes", "AWS"}, TechNames(stack))
+	assert.Equal(t, 2, stack[0].Mentions)
+	assert.Equal(t, "language", stack[0].Category)
+}
+
+func TestDetectTechStack_AmbiguousNames(t *testing.T) {
+	// Ordinary uses of Go, Swift, and Spark are not technologies
+	assert.Empty(t, DetectTechStack("Go above and beyond. Swift decisions spark joy. Spark curiosity and Go big."))
+
+	for text, want := range map[string]string{
+		"Experience with Python, Go, or Java":       "Go",
+		"Senior Go engineer for payments":           "Go",
+		"Go, Python and Kotlin services":            "Go",
+		"Build iOS apps in Swift and SwiftUI":       "Swift",
+		"Batch pipelines on Apache Spark":           "Spark",
+		"We run Spark jobs orchestrated by Airflow": "Spark",
+	} {
+		assert.Contains(t, TechNames(DetectTechStack(text)), want, text)
+	}
+}
+
+func TestDetectTechStack_Empty(t *testing.T) {
+	assert.Empty(t, DetectTechStack("We value kindness and ownership.", ""))
+}
+
+func TestInTechStack(t *testing.T) {
+	stack := []string{"Go", "Kubernetes", "Kafka"}
+	assert.True(t, InTechStack("golang", stack))
+	assert.True(t, InTechStack("k8s", stack))
+	assert.True(t, InTechStack("kafka", stack))
+	assert.False(t, InTechStack("Python", stack))
+}
+
+func TestFormatTechStack(t *testing.T) {
+	assert.Equal(t, "they use Go, Kubernetes", FormatTechStack([]string{"Go", "Kubernetes", "Kafka"}, 2))
+	assert.Equal(t, "", FormatTechStack(nil, 3))
+}
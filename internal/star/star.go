@@ -0,0 +1,292 @@
+package star
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/prompts"
+)
+
+// Interview stages, in the order they are asked
+const (
+	StageSituation = "situation"
+	StageTask      = "task"
+	StageAction    = "action"
+	StageResult    = "result"
+)
+
+// Stages lists the interview stages in order
+var Stages = []string{StageSituation, StageTask, StageAction, StageResult}
+
+// stageGuidance tells the model what each stage should draw out; it doubles as the
+// question asked when the model returns none
+var stageGuidance = map[string]string{
+	StageSituation: "What was the situation? Describe the team, product, and the problem or opportunity you faced.",
+	StageTask:      "What were you responsible for? Describe the goal you owned and any constraints.",
+	StageAction:    "What did you do? Describe the specific steps you took, the tools and technologies you used, and who you worked with.",
+	StageResult:    "What was the result? Include numbers where you can: time or money saved, performance gained, users affected.",
+}
+
+const (
+	// MaxAnswerLength caps each answer so a pasted document cannot flood the prompt
+	MaxAnswerLength = 4000
+	// MaxDraftBullets caps the bullets drafted for one story
+	MaxDraftBullets = 4
+	// maxBulletLength matches the two-line budget bullets are rendered within
+	maxBulletLength = 200
+)
+
+// Answers holds the user's answer to each stage; empty answers are still to be asked
+type Answers struct {
+	Situation string `json:"situation,omitempty"`
+	Task      string `json:"task,omitempty"`
+	Action    string `json:"action,omitempty"`
+	Result    string `json:"result,omitempty"`
+}
+
+// Get returns the answer for stage
+func (a *Answers) Get(stage string) string {
+	switch stage {
+	case StageSituation:
+		return strings.TrimSpace(a.Situation)
+	case StageTask:
+		return strings.TrimSpace(a.Task)
+	case StageAction:
+		return strings.TrimSpace(a.Action)
+	case StageResult:
+		return strings.TrimSpace(a.Result)
+	}
+	return ""
+}
+
+// Set records the answer for stage
+func (a *Answers) Set(stage, answer string) {
+	switch stage {
+	case StageSituation:
+		a.Situation = answer
+	case StageTask:
+		a.Task = answer
+	case StageAction:
+		a.Action = answer
+	case StageResult:
+		a.Result = answer
+	}
+}
+
+// NextStage returns the first stage without an answer, or "" when all are answered
+func (a *Answers) NextStage() string {
+	for _, stage := range Stages {
+		if a.Get(stage) == "" {
+			return stage
+		}
+	}
+	return ""
+}
+
+// Validate checks that no answer exceeds MaxAnswerLength
+func (a *Answers) Validate() error {
+	for _, stage := range Stages {
+		if len(a.Get(stage)) > MaxAnswerLength {
+			return &ValidationError{Field: stage, Message: fmt.Sprintf("answer is longer than %d characters", MaxAnswerLength)}
+		}
+	}
+	return nil
+}
+
+// Question is the next interview question
+type Question struct {
+	Stage    string `json:"stage"`
+	Question string `json:"question"`
+}
+
+// Draft is a story drafted from the interview, for the user to confirm or edit
+type Draft struct {
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	Bullets     []DraftBullet `json:"bullets"`
+}
+
+// DraftBullet is one drafted bullet
+type DraftBullet struct {
+	Text    string   `json:"text"`
+	Metrics string   `json:"metrics,omitempty"` // The quantified result the bullet cites, if any
+	Skills  []string `json:"skills"`
+}
+
+// Validate checks that the draft can be saved as a story
+func (d *Draft) Validate() error {
+	if strings.TrimSpace(d.Title) == "" {
+		return &ValidationError{Field: "title", Message: "title is required"}
+	}
+	if len(d.Bullets) == 0 || len(d.Bullets) > MaxDraftBullets {
+		return &ValidationError{Field: "bullets", Message: fmt.Sprintf("a story needs 1 to %d bullets", MaxDraftBullets)}
+	}
+	for i, b := range d.Bullets {
+		text := strings.TrimSpace(b.Text)
+		if text == "" {
+			return &ValidationError{Field: fmt.Sprintf("bullets[%d].text", i), Message: "text is required"}
+		}
+		if len(text) > maxBulletLength {
+			return &ValidationError{Field: fmt.Sprintf("bullets[%d].text", i), Message: fmt.Sprintf("text is longer than %d characters", maxBulletLength)}
+		}
+	}
+	return nil
+}
+
+// NextQuestion asks the model for the next interview question, building on the answers
+// so far. It returns nil once every stage is answered.
+func NextQuestion(ctx context.Context, answers *Answers, apiKey string) (*Question, error) {
+	stage := answers.NextStage()
+	if stage == "" {
+		return nil, nil
+	}
+	if err := answers.Validate(); err != nil {
+		return nil, err
+	}
+	// The opening question needs no context, so it is asked without a model call
+	if stage == StageSituation {
+		return &Question{Stage: stage, Question: stageGuidance[stage]}, nil
+	}
+
+	template, err := prompts.Get("star.json", "next-question")
+	if err != nil {
+		return nil, &APICallError{Message: "failed to load prompt", Cause: err}
+	}
+	prompt := prompts.Format(template, map[string]string{
+		"Stage":    stage,
+		"Guidance": stageGuidance[stage],
+		"Answers":  formatAnswers(answers),
+	})
+	response, err := generate(ctx, prompt, apiKey, false, llm.TierLite)
+	if err != nil {
+		return nil, err
+	}
+	question := strings.TrimSpace(strings.Trim(strings.TrimSpace(response), `"`))
+	if question == "" {
+		question = stageGuidance[stage]
+	}
+	return &Question{Stage: stage, Question: question}, nil
+}
+
+// DraftStory asks the model to turn the completed interview into a story with
+// well-formed bullets, metrics, and skills. The draft only restates the answers;
+// nothing is saved until the user confirms it.
+func DraftStory(ctx context.Context, answers *Answers, apiKey string) (*Draft, error) {
+	if stage := answers.NextStage(); stage != "" {
+		return nil, &ValidationError{Field: stage, Message: "answer is required"}
+	}
+	if err := answers.Validate(); err != nil {
+		return nil, err
+	}
+
+	template, err := prompts.Get("star.json", "draft-story")
+	if err != nil {
+		return nil, &APICallError{Message: "failed to load prompt", Cause: err}
+	}
+	prompt := prompts.Format(template, map[string]string{
+		"Answers":    formatAnswers(answers),
+		"MaxBullets": fmt.Sprintf("%d", MaxDraftBullets),
+		"MaxLength":  fmt.Sprintf("%d", maxBulletLength),
+	})
+	response, err := generate(ctx, prompt, apiKey, true, llm.TierAdvanced)
+	if err != nil {
+		return nil, err
+	}
+
+	var draft Draft
+	if err := json.Unmarshal([]byte(llm.CleanJSONBlock(response)), &draft); err != nil {
+		return nil, &ParseError{Message: "failed to parse story draft", Cause: err}
+	}
+	draft.normalize()
+	if err := draft.Validate(); err != nil {
+		return nil, &ParseError{Message: "model returned an unusable draft", Cause: err}
+	}
+	return &draft, nil
+}
+
+// StoryInput converts a confirmed draft into an experience bank story for userID under jobID
+func (d *Draft) StoryInput(userID, jobID uuid.UUID) *db.StoryCreateInput {
+	storyID := "star-" + uuid.New().String()
+	input := &db.StoryCreateInput{
+		StoryID:     storyID,
+		UserID:      userID,
+		JobID:       jobID,
+		Title:       strings.TrimSpace(d.Title),
+		Description: strings.TrimSpace(d.Description),
+	}
+	for i, b := range d.Bullets {
+		strength := db.EvidenceStrengthMedium
+		if strings.TrimSpace(b.Metrics) != "" {
+			strength = db.EvidenceStrengthHigh // Quantified results are the strongest evidence
+		}
+		input.Bullets = append(input.Bullets, db.BulletCreateInput{
+			BulletID:         fmt.Sprintf("%s-%d", storyID, i+1),
+			Text:             strings.TrimSpace(b.Text),
+			Metrics:          strings.TrimSpace(b.Metrics),
+			EvidenceStrength: strength,
+			Skills:           b.Skills,
+		})
+	}
+	return input
+}
+
+// normalize trims the draft's text and drops blank or repeated skills
+func (d *Draft) normalize() {
+	d.Title = strings.TrimSpace(d.Title)
+	d.Description = strings.TrimSpace(d.Description)
+	for i := range d.Bullets {
+		b := &d.Bullets[i]
+		b.Text = strings.TrimSpace(b.Text)
+		b.Metrics = strings.TrimSpace(b.Metrics)
+		skills := make([]string, 0, len(b.Skills))
+		seen := make(map[string]bool)
+		for _, s := range b.Skills {
+			s = strings.TrimSpace(s)
+			if s == "" || seen[strings.ToLower(s)] {
+				continue
+			}
+			seen[strings.ToLower(s)] = true
+			skills = append(skills, s)
+		}
+		b.Skills = skills
+	}
+}
+
+// formatAnswers renders the answered stages for a prompt
+func formatAnswers(answers *Answers) string {
+	var b strings.Builder
+	for _, stage := range Stages {
+		if answer := answers.Get(stage); answer != "" {
+			fmt.Fprintf(&b, "%s: %s\n", strings.ToUpper(stage[:1])+stage[1:], answer)
+		}
+	}
+	return b.String()
+}
+
+func generate(ctx context.Context, prompt, apiKey string, jsonOutput bool, tier llm.ModelTier) (string, error) {
+	if apiKey == "" && llm.APIKeyRequired() {
+		return "", &APICallError{Message: "API key is required"}
+	}
+	client, err := llm.NewClient(ctx, llm.DefaultConfig(), apiKey)
+	if err != nil {
+		return "", &APICallError{Message: "failed to create LLM client", Cause: err}
+	}
+	defer func() { _ = client.Close() }()
+
+	var response string
+	if jsonOutput {
+		response, err = client.GenerateJSON(ctx, prompt, tier)
+	} else {
+		response, err = client.GenerateContent(ctx, prompt, tier)
+	}
+	if err != nil {
+		return "", &APICallError{Message: "failed to generate content from LLM", Cause: err}
+	}
+	return response, nil
+}
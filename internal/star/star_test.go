@@ -0,0 +1,78 @@
+package star
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+)
+
+func TestAnswers_NextStage(t *testing.T) {
+	var a Answers
+	assert.Equal(t, StageSituation, a.NextStage())
+	a.Set(StageSituation, "Checkout was slow")
+	a.Set(StageTask, "  ")
+	assert.Equal(t, StageTask, a.NextStage(), "blank answers are still to be asked")
+	a.Set(StageTask, "Own latency")
+	a.Set(StageAction, "Added caching")
+	a.Set(StageResult, "p99 fell 40%")
+	assert.Equal(t, "", a.NextStage())
+
+	a.Action = strings.Repeat("x", MaxAnswerLength+1)
+	var verr *ValidationError
+	require.True(t, errors.As(a.Validate(), &verr))
+	assert.Equal(t, StageAction, verr.Field)
+}
+
+func TestNextQuestion(t *testing.T) {
+	ctx := llm.WithCassette(context.Background(), llm.NewCassette([]llm.Exchange{
+		{Prompt: "You are helping a job seeker", Response: "\"What did you own on the checkout team?\"\n"},
+	}))
+
+	a := &Answers{}
+	q, err := NextQuestion(ctx, a, "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, StageSituation, q.Stage)
+	assert.Equal(t, stageGuidance[StageSituation], q.Question, "the first question needs no model call")
+
+	a.Situation = "Checkout was slow"
+	q, err = NextQuestion(ctx, a, "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, &Question{Stage: StageTask, Question: "What did you own on the checkout team?"}, q)
+
+	a.Task, a.Action, a.Result = "Own latency", "Added caching", "p99 fell 40%"
+	q, err = NextQuestion(ctx, a, "test-key")
+	require.NoError(t, err)
+	assert.Nil(t, q)
+}
+
+func TestDraftStory(t *testing.T) {
+	answers := &Answers{Situation: "Checkout was slow", Task: "Own latency", Action: "Added Redis caching in Go", Result: "p99 fell 40%"}
+
+	_, err := DraftStory(context.Background(), &Answers{Situation: "Checkout was slow"}, "test-key")
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	assert.Equal(t, StageTask, verr.Field)
+
+	ctx := llm.WithCassette(context.Background(), llm.NewCassette([]llm.Exchange{{
+		Prompt: "Turn the following STAR interview answers", JSON: true,
+		Response: "```json\n" + `{"title":" Checkout latency ","bullets":[{"text":"Cut checkout p99 latency 40% by adding Redis caching in Go","metrics":"40%","skills":["Go","Redis","go",""]}]}` + "\n```",
+	}}))
+	draft, err := DraftStory(ctx, answers, "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, "Checkout latency", draft.Title)
+	require.Len(t, draft.Bullets, 1)
+	assert.Equal(t, []string{"Go", "Redis"}, draft.Bullets[0].Skills)
+
+	ctx = llm.WithCassette(context.Background(), llm.NewCassette([]llm.Exchange{{
+		Prompt: "Turn the following STAR interview answers", JSON: true, Response: `{"title":"Checkout","bullets":[]}`,
+	}}))
+	_, err = DraftStory(ctx, answers, "test-key")
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr), "drafts without bullets are rejected")
+}
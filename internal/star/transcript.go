@@ -0,0 +1,54 @@
+package star
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+	"github.com/jonathan/resume-customizer/internal/prompts"
+)
+
+// MaxTranscriptLength caps a voice note transcript; a few minutes of speech fits well
+// within it
+const MaxTranscriptLength = 20000
+
+// AnswersFromTranscript asks the model to sort a spoken account of one accomplishment
+// into interview answers. Stages the speaker did not cover are left empty, so the
+// interview can pick up where the recording stopped.
+func AnswersFromTranscript(ctx context.Context, transcript, apiKey string) (*Answers, error) {
+	transcript = strings.TrimSpace(transcript)
+	if transcript == "" {
+		return nil, &ValidationError{Field: "transcript", Message: "transcript is required"}
+	}
+	if len(transcript) > MaxTranscriptLength {
+		return nil, &ValidationError{Field: "transcript", Message: fmt.Sprintf("transcript is longer than %d characters", MaxTranscriptLength)}
+	}
+
+	template, err := prompts.Get("star.json", "transcript-answers")
+	if err != nil {
+		return nil, &APICallError{Message: "failed to load prompt", Cause: err}
+	}
+	prompt := prompts.Format(template, map[string]string{
+		"Transcript": transcript,
+		"MaxLength":  fmt.Sprintf("%d", MaxAnswerLength),
+	})
+	response, err := generate(ctx, prompt, apiKey, true, llm.TierLite)
+	if err != nil {
+		return nil, err
+	}
+
+	var answers Answers
+	if err := json.Unmarshal([]byte(llm.CleanJSONBlock(response)), &answers); err != nil {
+		return nil, &ParseError{Message: "failed to parse transcript answers", Cause: err}
+	}
+	for _, stage := range Stages {
+		answer := answers.Get(stage)
+		if len(answer) > MaxAnswerLength {
+			answer = answer[:MaxAnswerLength]
+		}
+		answers.Set(stage, answer)
+	}
+	return &answers, nil
+}
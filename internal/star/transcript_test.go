@@ -0,0 +1,37 @@
+package star
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/llm"
+)
+
+func TestAnswersFromTranscript(t *testing.T) {
+	var verr *ValidationError
+	_, err := AnswersFromTranscript(context.Background(), "  ", "test-key")
+	require.True(t, errors.As(err, &verr))
+	_, err = AnswersFromTranscript(context.Background(), strings.Repeat("x", MaxTranscriptLength+1), "test-key")
+	require.True(t, errors.As(err, &verr))
+
+	ctx := llm.WithCassette(context.Background(), llm.NewCassette([]llm.Exchange{{
+		Prompt: "The following is a transcript", JSON: true,
+		Response: `{"situation":"Checkout was slow","task":"","action":"Added Redis caching","result":""}`,
+	}}))
+	answers, err := AnswersFromTranscript(ctx, "So, um, checkout was slow and I added Redis caching", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, "Checkout was slow", answers.Situation)
+	assert.Equal(t, StageTask, answers.NextStage(), "stages the speaker skipped are left to ask")
+
+	ctx = llm.WithCassette(context.Background(), llm.NewCassette([]llm.Exchange{{
+		Prompt: "The following is a transcript", JSON: true, Response: "not json",
+	}}))
+	_, err = AnswersFromTranscript(ctx, "Checkout was slow", "test-key")
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+}
@@ -0,0 +1,95 @@
+package stepqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jonathan/resume-customizer/internal/fetch"
+	"github.com/jonathan/resume-customizer/internal/research"
+	"github.com/jonathan/resume-customizer/internal/types"
+	"github.com/jonathan/resume-customizer/internal/validation"
+)
+
+// Step names that can be executed by workers
+const (
+	StepResearchCompany = "research_company"
+	StepValidateLaTeX   = "validate_latex"
+)
+
+// ResearchJob is the payload for research_company jobs.
+// Credentials are deliberately excluded; workers use their own API keys.
+type ResearchJob struct {
+	SeedURLs      []string `json:"seed_urls"`
+	Company       string   `json:"company"`
+	Domain        string   `json:"domain"`
+	InitialCorpus string   `json:"initial_corpus,omitempty"`
+	Verbose       bool     `json:"verbose,omitempty"`
+	UseBrowser    bool     `json:"use_browser,omitempty"`
+
+	// Crawl depth, scope, and budget
+	MaxPages       int   `json:"max_pages"`
+	MaxDepth       int   `json:"max_depth"`
+	SameDomainOnly bool  `json:"same_domain_only,omitempty"`
+	MaxBytes       int64 `json:"max_bytes,omitempty"`
+
+	ArchiveFallback bool `json:"archive_fallback,omitempty"` // Read dead or bot-blocked pages from the Internet Archive
+
+	DomainPolicy *fetch.DomainPolicy `json:"domain_policy,omitempty"` // Run owner's domain blocklist/allowlist
+
+	Prior *research.Session `json:"prior,omitempty"` // Company's previous session to resume from
+}
+
+// ValidateJob is the payload for validate_latex jobs (LaTeX compilation and constraint checks)
+type ValidateJob struct {
+	LaTeX           string                `json:"latex"`
+	CompanyProfile  *types.CompanyProfile `json:"company_profile,omitempty"`
+	MaxPages        int                   `json:"max_pages"`
+	MaxCharsPerLine int                   `json:"max_chars_per_line"`
+	Options         *validation.Options   `json:"options,omitempty"`
+}
+
+// Handlers returns the built-in step handlers. apiKey is the worker's LLM API key.
+func Handlers(apiKey string) map[string]Handler {
+	return map[string]Handler{
+		StepResearchCompany: researchHandler(apiKey),
+		StepValidateLaTeX:   validateHandler,
+	}
+}
+
+// researchHandler crawls company pages and returns the research session
+func researchHandler(apiKey string) Handler {
+	return func(ctx context.Context, payload json.RawMessage) (any, error) {
+		var job ResearchJob
+		if err := json.Unmarshal(payload, &job); err != nil {
+			return nil, fmt.Errorf("invalid research job payload: %w", err)
+		}
+		return research.RunResearch(ctx, research.RunResearchOptions{
+			SeedURLs:      job.SeedURLs,
+			Company:       job.Company,
+			Domain:        job.Domain,
+			InitialCorpus: job.InitialCorpus,
+			APIKey:        apiKey,
+			Verbose:       job.Verbose,
+			UseBrowser:    job.UseBrowser,
+			Prior:         job.Prior,
+
+			MaxPages:       job.MaxPages,
+			MaxDepth:       job.MaxDepth,
+			SameDomainOnly: job.SameDomainOnly,
+			MaxBytes:       job.MaxBytes,
+			DomainPolicy:   job.DomainPolicy,
+
+			ArchiveFallback: job.ArchiveFallback,
+		})
+	}
+}
+
+// validateHandler compiles LaTeX and checks resume constraints
+func validateHandler(_ context.Context, payload json.RawMessage) (any, error) {
+	var job ValidateJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return nil, fmt.Errorf("invalid validate job payload: %w", err)
+	}
+	return validation.ValidateFromContent(job.LaTeX, job.CompanyProfile, job.MaxPages, job.MaxCharsPerLine, job.Options)
+}
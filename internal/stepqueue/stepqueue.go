@@ -0,0 +1,269 @@
+// Package stepqueue runs heavy pipeline steps (browser crawling, LaTeX compilation)
+// in separate worker processes. The pipeline enqueues a job in Postgres and waits
+// for its result; workers claim jobs with SELECT ... FOR UPDATE SKIP LOCKED.
+// LISTEN/NOTIFY wakes both sides promptly, with polling as a fallback.
+package stepqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+// DefaultPollInterval is how often job state is re-checked when no notification arrives
+const DefaultPollInterval = 5 * time.Second
+
+// Store is the subset of db.DB used by the queue
+type Store interface {
+	EnqueueStepJob(ctx context.Context, runID uuid.UUID, step, backend string, payload any) (uuid.UUID, error)
+	ClaimStepJob(ctx context.Context, workerID string, steps []string) (*db.StepJob, error)
+	ClaimStepJobByID(ctx context.Context, id uuid.UUID, workerID string) (*db.StepJob, error)
+	GetStepJob(ctx context.Context, id uuid.UUID) (*db.StepJob, error)
+	CompleteStepJob(ctx context.Context, id uuid.UUID, result any) error
+	FailStepJob(ctx context.Context, id uuid.UUID, errMsg string) error
+	HeartbeatStepJob(ctx context.Context, id uuid.UUID) error
+	RequeueStaleStepJobs(ctx context.Context, staleAfter time.Duration) (int64, error)
+	RecordDeadLetter(ctx context.Context, input *db.DeadLetterInput) (*db.DeadLetter, error)
+	Listen(ctx context.Context, channel string) (*db.Listener, error)
+}
+
+// RemoteError is returned when a worker reports a step failure
+type RemoteError struct {
+	Step    string
+	JobID   uuid.UUID
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("remote step %s (job %s) failed: %s", e.Step, e.JobID, e.Message)
+}
+
+// LaunchRequest describes a queued job that needs a dedicated execution environment
+type LaunchRequest struct {
+	JobID uuid.UUID
+	Step  string
+	Spec  steps.ExecutionSpec
+}
+
+// Launcher starts an isolated execution (e.g. a Kubernetes Job) that claims
+// the queued job by ID and records its result in the queue
+type Launcher interface {
+	Launch(ctx context.Context, req LaunchRequest) error
+}
+
+// Dispatcher sends configured steps to workers and waits for their results
+type Dispatcher struct {
+	store        Store
+	cfg          *config.StepWorkerConfig
+	launcher     Launcher
+	pollInterval time.Duration
+	done         *completions
+}
+
+// NewDispatcher creates a dispatcher. It returns nil when no step is configured
+// to run out of process (via REMOTE_STEPS or a registry ExecutionSpec) or there
+// is no store, so callers can use Remote() unconditionally.
+func NewDispatcher(store Store, cfg *config.StepWorkerConfig, launcher Launcher) *Dispatcher {
+	if store == nil || cfg == nil {
+		return nil
+	}
+	d := &Dispatcher{store: store, cfg: cfg, launcher: launcher, pollInterval: DefaultPollInterval,
+		done: &completions{store: store, waiters: make(map[uuid.UUID]chan struct{})}}
+	if len(cfg.RemoteSteps) > 0 {
+		return d
+	}
+	for step := range steps.StepRegistry {
+		if d.Remote(step) {
+			return d
+		}
+	}
+	return nil
+}
+
+// Backend returns the backend that executes step. A registry ExecutionSpec takes
+// precedence over REMOTE_STEPS, which selects the shared worker queue.
+func (d *Dispatcher) Backend(step string) string {
+	if d == nil {
+		return steps.BackendLocal
+	}
+	if def, ok := steps.StepRegistry[step]; ok && def.Execution.Backend != "" {
+		return def.Execution.Backend
+	}
+	if d.cfg.IsRemote(step) {
+		return steps.BackendWorker
+	}
+	return steps.BackendLocal
+}
+
+// Remote reports whether step is executed outside the pipeline process
+func (d *Dispatcher) Remote(step string) bool {
+	return d.Backend(step) != steps.BackendLocal
+}
+
+// Run enqueues step with payload, waits for a worker to finish it, and decodes
+// the worker's result into result (which may be nil to discard it). If Run stops
+// waiting before the job finishes, the job is failed so no worker picks it up later.
+func (d *Dispatcher) Run(ctx context.Context, runID uuid.UUID, step string, payload, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Timeout())
+	defer cancel()
+
+	// Subscribe before enqueueing so a fast worker's completion can't be missed
+	if err := d.done.listen(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to step completions: %w", err)
+	}
+
+	backend := d.Backend(step)
+	jobID, err := d.store.EnqueueStepJob(ctx, runID, step, backend, payload)
+	if err != nil {
+		return err
+	}
+	wake := d.done.wait(jobID)
+	defer d.done.forget(jobID)
+
+	if backend == steps.BackendKubernetes {
+		if err := d.launch(ctx, jobID, step); err != nil {
+			d.abandon(ctx, jobID, err)
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := d.store.GetStepJob(ctx, jobID)
+		if err != nil {
+			d.abandon(ctx, jobID, err)
+			return err
+		}
+		if job == nil {
+			return fmt.Errorf("step job %s disappeared", jobID)
+		}
+		if job.Done() {
+			return decodeJob(job, result)
+		}
+
+		select {
+		case <-ctx.Done():
+			err := fmt.Errorf("waiting for remote step %s (job %s): %w", step, jobID, ctx.Err())
+			d.abandon(ctx, jobID, err)
+			return err
+		case <-wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// abandon fails a job nobody is waiting for anymore. It uses a non-canceled context,
+// since the caller's context is usually the reason for giving up.
+func (d *Dispatcher) abandon(ctx context.Context, jobID uuid.UUID, cause error) {
+	if err := d.store.FailStepJob(context.WithoutCancel(ctx), jobID, cause.Error()); err != nil {
+		slog.WarnContext(ctx, "failed to fail abandoned step job", "job_id", jobID, "error", err)
+	}
+}
+
+// launch starts a dedicated execution for a queued job
+func (d *Dispatcher) launch(ctx context.Context, jobID uuid.UUID, step string) error {
+	if d.launcher == nil {
+		return fmt.Errorf("step %s uses the kubernetes backend but no launcher is configured", step)
+	}
+	req := LaunchRequest{JobID: jobID, Step: step, Spec: steps.StepRegistry[step].Execution}
+	if err := d.launcher.Launch(ctx, req); err != nil {
+		return fmt.Errorf("failed to launch %s job %s: %w", step, jobID, err)
+	}
+	return nil
+}
+
+// completions shares one StepJobDoneChannel listener between every Run call in the
+// process, so in-flight remote steps don't each hold a pooled connection
+type completions struct {
+	store Store
+
+	mu      sync.Mutex
+	active  bool
+	waiters map[uuid.UUID]chan struct{}
+}
+
+// listen starts the shared listener if it is not running. The listener outlives
+// ctx; if it dies, waiters fall back to polling until the next Run restarts it.
+func (c *completions) listen(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.active {
+		return nil
+	}
+
+	listener, err := c.store.Listen(context.WithoutCancel(ctx), db.StepJobDoneChannel)
+	if err != nil {
+		return err
+	}
+	if listener == nil {
+		return nil // Store without notifications: poll only
+	}
+	c.active = true
+
+	go func() {
+		defer listener.Close()
+		for payload := range listener.C {
+			id, err := uuid.Parse(payload)
+			if err != nil {
+				continue
+			}
+			c.mu.Lock()
+			if ch, ok := c.waiters[id]; ok {
+				select {
+				case ch <- struct{}{}:
+				default: // A wake-up is already pending
+				}
+			}
+			c.mu.Unlock()
+		}
+		c.mu.Lock()
+		c.active = false
+		c.mu.Unlock()
+	}()
+	return nil
+}
+
+// wait registers interest in a job and returns the channel woken when it finishes
+func (c *completions) wait(id uuid.UUID) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	c.mu.Lock()
+	c.waiters[id] = ch
+	c.mu.Unlock()
+	return ch
+}
+
+// forget removes a job's waiter
+func (c *completions) forget(id uuid.UUID) {
+	c.mu.Lock()
+	delete(c.waiters, id)
+	c.mu.Unlock()
+}
+
+// decodeJob converts a finished job into an error or a decoded result
+func decodeJob(job *db.StepJob, result any) error {
+	if job.Status == db.StepJobStatusFailed {
+		msg := "unknown error"
+		if job.ErrorMessage != nil {
+			msg = *job.ErrorMessage
+		}
+		return &RemoteError{Step: job.Step, JobID: job.ID, Message: msg}
+	}
+	if result == nil || len(job.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(job.Result, result); err != nil {
+		return fmt.Errorf("failed to decode result of step job %s: %w", job.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,361 @@
+package stepqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+// memStore is an in-memory Store without notifications, so tests exercise the polling path
+type memStore struct {
+	mu         sync.Mutex
+	jobs       map[uuid.UUID]*db.StepJob
+	order      []uuid.UUID
+	requeued   int
+	heartbeats int
+	dead       []*db.DeadLetterInput
+}
+
+func newMemStore() *memStore {
+	return &memStore{jobs: make(map[uuid.UUID]*db.StepJob)}
+}
+
+func (m *memStore) EnqueueStepJob(_ context.Context, runID uuid.UUID, step, backend string, payload any) (uuid.UUID, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job := &db.StepJob{ID: uuid.New(), Step: step, Backend: backend, Payload: data, Status: db.StepJobStatusQueued, CreatedAt: time.Now()}
+	if runID != uuid.Nil {
+		job.RunID = &runID
+	}
+	m.jobs[job.ID] = job
+	m.order = append(m.order, job.ID)
+	return job.ID, nil
+}
+
+func (m *memStore) ClaimStepJob(_ context.Context, workerID string, steps []string) (*db.StepJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.order {
+		job := m.jobs[id]
+		if job.Status != db.StepJobStatusQueued || job.Backend != db.StepJobBackendWorker {
+			continue
+		}
+		for _, s := range steps {
+			if s == job.Step {
+				job.Status = db.StepJobStatusRunning
+				job.WorkerID = &workerID
+				job.Attempts++
+				clone := *job
+				return &clone, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (m *memStore) ClaimStepJobByID(_ context.Context, id uuid.UUID, workerID string) (*db.StepJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok || job.Status != db.StepJobStatusQueued {
+		return nil, nil
+	}
+	job.Status = db.StepJobStatusRunning
+	job.WorkerID = &workerID
+	job.Attempts++
+	clone := *job
+	return &clone, nil
+}
+
+func (m *memStore) GetStepJob(_ context.Context, id uuid.UUID) (*db.StepJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	clone := *job
+	return &clone, nil
+}
+
+func (m *memStore) CompleteStepJob(_ context.Context, id uuid.UUID, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[id].Status = db.StepJobStatusCompleted
+	m.jobs[id].Result = data
+	return nil
+}
+
+func (m *memStore) FailStepJob(_ context.Context, id uuid.UUID, errMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.jobs[id].Done() {
+		return nil
+	}
+	m.jobs[id].Status = db.StepJobStatusFailed
+	m.jobs[id].ErrorMessage = &errMsg
+	return nil
+}
+
+func (m *memStore) HeartbeatStepJob(_ context.Context, _ uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.heartbeats++
+	return nil
+}
+
+func (m *memStore) RequeueStaleStepJobs(_ context.Context, _ time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requeued++
+	return 0, nil
+}
+
+func (m *memStore) RecordDeadLetter(_ context.Context, input *db.DeadLetterInput) (*db.DeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dead = append(m.dead, input)
+	return &db.DeadLetter{Source: input.Source, RefKey: input.RefKey}, nil
+}
+
+func (m *memStore) Listen(_ context.Context, _ string) (*db.Listener, error) {
+	return nil, nil
+}
+
+func remoteConfig(steps ...string) *config.StepWorkerConfig {
+	cfg := &config.StepWorkerConfig{RemoteSteps: make(map[string]bool), TimeoutSeconds: 5, StaleSeconds: 60}
+	for _, s := range steps {
+		cfg.RemoteSteps[s] = true
+	}
+	return cfg
+}
+
+func TestNewDispatcher_NilWhenNothingRemote(t *testing.T) {
+	assert.Nil(t, NewDispatcher(newMemStore(), remoteConfig(), nil))
+	assert.Nil(t, NewDispatcher(nil, remoteConfig("research_company"), nil))
+
+	var d *Dispatcher
+	assert.False(t, d.Remote("research_company"))
+
+	d = NewDispatcher(newMemStore(), remoteConfig("research_company"), nil)
+	require.NotNil(t, d)
+	assert.True(t, d.Remote("research_company"))
+	assert.False(t, d.Remote("validate_latex"))
+}
+
+func TestDispatcherAndWorker_RoundTrip(t *testing.T) {
+	store := newMemStore()
+	d := NewDispatcher(store, remoteConfig("echo", "boom"), nil)
+	d.pollInterval = 10 * time.Millisecond
+
+	worker := NewWorker(store, "w1", map[string]Handler{
+		"echo": func(_ context.Context, payload json.RawMessage) (any, error) {
+			var in map[string]string
+			if err := json.Unmarshal(payload, &in); err != nil {
+				return nil, err
+			}
+			return map[string]string{"echo": in["msg"]}, nil
+		},
+		"boom": func(_ context.Context, _ json.RawMessage) (any, error) {
+			return nil, errors.New("compiler exploded")
+		},
+	}, 2, time.Minute)
+	worker.pollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	workerDone := make(chan error, 1)
+	go func() { workerDone <- worker.Run(ctx) }()
+
+	runID := uuid.New()
+	var out map[string]string
+	require.NoError(t, d.Run(context.Background(), runID, "echo", map[string]string{"msg": "hi"}, &out))
+	assert.Equal(t, "hi", out["echo"])
+
+	err := d.Run(context.Background(), runID, "boom", nil, nil)
+	var remoteErr *RemoteError
+	require.ErrorAs(t, err, &remoteErr)
+	assert.Equal(t, "boom", remoteErr.Step)
+	assert.Contains(t, err.Error(), "compiler exploded")
+
+	cancel()
+	require.NoError(t, <-workerDone)
+	assert.Positive(t, store.requeued, "worker should sweep stale jobs")
+}
+
+func TestDispatcher_TimesOutWithoutWorker(t *testing.T) {
+	cfg := remoteConfig("echo")
+	store := newMemStore()
+	d := NewDispatcher(store, cfg, nil)
+	d.pollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := d.Run(ctx, uuid.Nil, "echo", nil, nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The abandoned job is failed so a worker arriving later doesn't run it
+	require.Len(t, store.order, 1)
+	assert.Equal(t, db.StepJobStatusFailed, store.jobs[store.order[0]].Status)
+	job, err := store.ClaimStepJob(context.Background(), "late", []string{"echo"})
+	require.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestWorker_HeartbeatsLongJobs(t *testing.T) {
+	store := newMemStore()
+	_, err := store.EnqueueStepJob(context.Background(), uuid.Nil, "slow", db.StepJobBackendWorker, nil)
+	require.NoError(t, err)
+
+	worker := NewWorker(store, "w1", map[string]Handler{
+		"slow": func(_ context.Context, _ json.RawMessage) (any, error) {
+			time.Sleep(100 * time.Millisecond)
+			return nil, nil
+		},
+	}, 1, 60*time.Millisecond)
+
+	job, err := store.ClaimStepJob(context.Background(), "w1", worker.Steps())
+	require.NoError(t, err)
+	assert.True(t, worker.execute(context.Background(), job))
+	assert.GreaterOrEqual(t, store.heartbeats, 2)
+}
+
+type launcherFunc func(ctx context.Context, req LaunchRequest) error
+
+func (f launcherFunc) Launch(ctx context.Context, req LaunchRequest) error { return f(ctx, req) }
+
+// setExecution overrides a step's registry ExecutionSpec for the duration of the test
+func setExecution(t *testing.T, step string, spec steps.ExecutionSpec) {
+	t.Helper()
+	def := steps.StepRegistry[step]
+	prev := def.Execution
+	def.Execution = spec
+	steps.StepRegistry[step] = def
+	t.Cleanup(func() {
+		def := steps.StepRegistry[step]
+		def.Execution = prev
+		steps.StepRegistry[step] = def
+	})
+}
+
+func TestDispatcher_Backend(t *testing.T) {
+	setExecution(t, StepValidateLaTeX, steps.ExecutionSpec{Backend: steps.BackendKubernetes})
+
+	d := NewDispatcher(newMemStore(), remoteConfig(), nil)
+	require.NotNil(t, d, "registry execution specs alone make the dispatcher active")
+	assert.Equal(t, steps.BackendKubernetes, d.Backend(StepValidateLaTeX))
+	assert.Equal(t, steps.BackendLocal, d.Backend(StepResearchCompany))
+
+	d = NewDispatcher(newMemStore(), remoteConfig(StepResearchCompany), nil)
+	assert.Equal(t, steps.BackendWorker, d.Backend(StepResearchCompany))
+
+	setExecution(t, StepResearchCompany, steps.ExecutionSpec{Backend: steps.BackendLocal})
+	assert.False(t, d.Remote(StepResearchCompany), "registry spec overrides REMOTE_STEPS")
+}
+
+func TestDispatcher_KubernetesBackend(t *testing.T) {
+	setExecution(t, StepValidateLaTeX, steps.ExecutionSpec{Backend: steps.BackendKubernetes, Memory: "1Gi"})
+	store := newMemStore()
+
+	// The launched "Kubernetes Job" claims the job by ID, like `worker --job-id`
+	worker := NewWorker(store, "k8s", map[string]Handler{
+		StepValidateLaTeX: func(_ context.Context, _ json.RawMessage) (any, error) {
+			return map[string]bool{"ok": true}, nil
+		},
+	}, 1, 0)
+	var launched LaunchRequest
+	d := NewDispatcher(store, remoteConfig(), launcherFunc(func(ctx context.Context, req LaunchRequest) error {
+		launched = req
+		go func() { _ = worker.RunJob(ctx, req.JobID) }()
+		return nil
+	}))
+	d.pollInterval = 10 * time.Millisecond
+
+	var out map[string]bool
+	require.NoError(t, d.Run(context.Background(), uuid.New(), StepValidateLaTeX, nil, &out))
+	assert.True(t, out["ok"])
+	assert.Equal(t, "1Gi", launched.Spec.Memory)
+
+	job, err := store.GetStepJob(context.Background(), launched.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, db.StepJobBackendKubernetes, job.Backend)
+	assert.Error(t, worker.RunJob(context.Background(), launched.JobID), "a finished job cannot be claimed again")
+}
+
+func TestDispatcher_LaunchFailureFailsJob(t *testing.T) {
+	setExecution(t, StepValidateLaTeX, steps.ExecutionSpec{Backend: steps.BackendKubernetes})
+	store := newMemStore()
+
+	d := NewDispatcher(store, remoteConfig(), nil)
+	err := d.Run(context.Background(), uuid.Nil, StepValidateLaTeX, nil, nil)
+	assert.ErrorContains(t, err, "no launcher")
+
+	d = NewDispatcher(store, remoteConfig(), launcherFunc(func(context.Context, LaunchRequest) error {
+		return errors.New("quota exceeded")
+	}))
+	err = d.Run(context.Background(), uuid.Nil, StepValidateLaTeX, nil, nil)
+	assert.ErrorContains(t, err, "quota exceeded")
+
+	for _, id := range store.order {
+		assert.Equal(t, db.StepJobStatusFailed, store.jobs[id].Status)
+	}
+}
+
+func TestWorker_RecoversFromPanic(t *testing.T) {
+	store := newMemStore()
+	id, err := store.EnqueueStepJob(context.Background(), uuid.Nil, "panics", db.StepJobBackendWorker, nil)
+	require.NoError(t, err)
+
+	worker := NewWorker(store, "w1", map[string]Handler{
+		"panics": func(_ context.Context, _ json.RawMessage) (any, error) { panic("oops") },
+	}, 1, 0)
+
+	job, err := store.ClaimStepJob(context.Background(), "w1", worker.Steps())
+	require.NoError(t, err)
+	worker.execute(context.Background(), job)
+
+	got, err := store.GetStepJob(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, db.StepJobStatusFailed, got.Status)
+	require.NotNil(t, got.ErrorMessage)
+	assert.Contains(t, *got.ErrorMessage, "panicked")
+	require.Len(t, store.dead, 1, "failed jobs are kept for an operator")
+	assert.Equal(t, db.DeadLetterStepJob, store.dead[0].Source)
+	assert.Equal(t, id.String(), store.dead[0].RefKey)
+}
+
+func TestWorker_RequiresHandlers(t *testing.T) {
+	worker := NewWorker(newMemStore(), "w1", nil, 0, 0)
+	assert.Error(t, worker.Run(context.Background()))
+}
+
+func TestHandlers_InvalidPayload(t *testing.T) {
+	handlers := Handlers("test-key")
+	require.Contains(t, handlers, StepResearchCompany)
+	require.Contains(t, handlers, StepValidateLaTeX)
+
+	for step, handler := range handlers {
+		_, err := handler(context.Background(), json.RawMessage(`"not an object"`))
+		assert.Error(t, err, step)
+	}
+}
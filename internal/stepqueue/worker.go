@@ -0,0 +1,235 @@
+package stepqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/deadletter"
+	"github.com/jonathan/resume-customizer/internal/logging"
+	"github.com/jonathan/resume-customizer/internal/pipeline/steps"
+)
+
+// heartbeatInterval is the longest gap between heartbeats of a running job
+const heartbeatInterval = 30 * time.Second
+
+// Handler executes one job for a step and returns its JSON-serializable result
+type Handler func(ctx context.Context, payload json.RawMessage) (any, error)
+
+// Worker claims and executes queued step jobs
+type Worker struct {
+	store        Store
+	id           string
+	handlers     map[string]Handler
+	concurrency  int
+	staleAfter   time.Duration
+	pollInterval time.Duration
+}
+
+// NewWorker creates a worker that executes jobs for the steps in handlers,
+// running at most concurrency jobs at once. Running jobs (of any worker) without a
+// heartbeat for staleAfter are requeued; zero disables requeueing.
+func NewWorker(store Store, id string, handlers map[string]Handler, concurrency int, staleAfter time.Duration) *Worker {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Worker{
+		store:        store,
+		id:           id,
+		handlers:     handlers,
+		concurrency:  concurrency,
+		staleAfter:   staleAfter,
+		pollInterval: DefaultPollInterval,
+	}
+}
+
+// Steps returns the step names this worker handles in sorted order
+func (w *Worker) Steps() []string {
+	steps := make([]string, 0, len(w.handlers))
+	for step := range w.handlers {
+		steps = append(steps, step)
+	}
+	sort.Strings(steps)
+	return steps
+}
+
+// Run processes jobs until ctx is canceled, then waits for in-flight jobs to finish
+func (w *Worker) Run(ctx context.Context) error {
+	steps := w.Steps()
+	if len(steps) == 0 {
+		return fmt.Errorf("worker %s has no step handlers", w.id)
+	}
+
+	listener, err := w.store.Listen(ctx, db.StepJobsChannel)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to step jobs: %w", err)
+	}
+	defer listener.Close()
+	var notifications <-chan string
+	if listener != nil {
+		notifications = listener.C
+	}
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	slots := make(chan struct{}, w.concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		w.requeueStale(ctx)
+
+		// Claim jobs until the queue is empty or every slot is busy
+		for {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil
+			}
+			job, err := w.store.ClaimStepJob(ctx, w.id, steps)
+			if err != nil || job == nil {
+				<-slots
+				if err != nil && ctx.Err() == nil {
+					slog.WarnContext(ctx, "step job claim failed", "worker_id", w.id, "error", err)
+				}
+				break
+			}
+			wg.Add(1)
+			go func(job *db.StepJob) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				w.execute(ctx, job)
+			}(job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-notifications:
+			if !ok {
+				notifications = nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunJob claims and executes a single job by ID, as done inside a dedicated
+// Kubernetes Job. It returns an error if the job cannot be claimed or fails.
+func (w *Worker) RunJob(ctx context.Context, id uuid.UUID) error {
+	job, err := w.store.ClaimStepJobByID(ctx, id, w.id)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("step job %s is not queued", id)
+	}
+	if !w.execute(ctx, job) {
+		return fmt.Errorf("step job %s failed", id)
+	}
+	return nil
+}
+
+// requeueStale returns abandoned jobs to the queue
+func (w *Worker) requeueStale(ctx context.Context) {
+	if w.staleAfter <= 0 {
+		return
+	}
+	n, err := w.store.RequeueStaleStepJobs(ctx, w.staleAfter)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.WarnContext(ctx, "requeue of stale step jobs failed", "worker_id", w.id, "error", err)
+		}
+		return
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "Requeued stale step jobs", "worker_id", w.id, "jobs", n)
+	}
+}
+
+// execute runs a claimed job and records its outcome, reporting whether it succeeded.
+// Outcomes are recorded with a non-canceled context so shutdown doesn't strand a finished job.
+func (w *Worker) execute(ctx context.Context, job *db.StepJob) bool {
+	start := time.Now()
+	if job.RunID != nil {
+		ctx = logging.WithRunID(ctx, *job.RunID)
+	}
+	ctx = steps.WithLLMBudget(logging.WithStep(ctx, job.Step), job.Step)
+	logger := slog.With("worker_id", w.id, "job_id", job.ID)
+	logger.InfoContext(ctx, "Running step job", "attempt", job.Attempts)
+
+	stopHeartbeat := w.heartbeat(ctx, job.ID)
+	result, err := w.invoke(ctx, job)
+	stopHeartbeat()
+	recordCtx := context.WithoutCancel(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Step job failed", "duration_ms", time.Since(start).Milliseconds(), "error", err)
+		if ferr := w.store.FailStepJob(recordCtx, job.ID, err.Error()); ferr != nil {
+			logger.WarnContext(ctx, "failed to record step job failure", "error", ferr)
+		}
+		// Workers do not retry; keep the job for an operator to requeue
+		if _, derr := w.store.RecordDeadLetter(recordCtx, deadletter.StepJob(job, w.id, err)); derr != nil {
+			logger.WarnContext(ctx, "failed to record dead letter for step job", "error", derr)
+		}
+		return false
+	}
+
+	if cerr := w.store.CompleteStepJob(recordCtx, job.ID, result); cerr != nil {
+		logger.WarnContext(ctx, "failed to record step job completion", "error", cerr)
+		return false
+	}
+	logger.InfoContext(ctx, "Step job completed", "duration_ms", time.Since(start).Milliseconds())
+	return true
+}
+
+// heartbeat keeps a running job's updated_at fresh so long steps are not requeued
+// and run twice. It returns a function that stops the heartbeat.
+func (w *Worker) heartbeat(ctx context.Context, id uuid.UUID) func() {
+	interval := heartbeatInterval
+	if w.staleAfter > 0 && w.staleAfter/3 < interval {
+		interval = w.staleAfter / 3
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.store.HeartbeatStepJob(ctx, id); err != nil && ctx.Err() == nil {
+					slog.WarnContext(ctx, "step job heartbeat failed", "worker_id", w.id, "job_id", id, "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// invoke calls the step handler, converting panics into job failures
+func (w *Worker) invoke(ctx context.Context, job *db.StepJob) (result any, err error) {
+	handler, ok := w.handlers[job.Step]
+	if !ok {
+		return nil, fmt.Errorf("no handler for step %s", job.Step)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job.Payload)
+}
@@ -0,0 +1,147 @@
+// Package suggestions drafts experience bank bullets for job requirements a user's
+// experience does not cover. Suggestions are phrased as questions for the user to
+// confirm and are never placed on a resume until the user adds them to the bank.
+package suggestions
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jonathan/resume-customizer/internal/parsing"
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// Requirement sources, in the order suggestions are returned
+const (
+	SourceHardRequirement = "hard_requirement"
+	SourceNiceToHave      = "nice_to_have"
+)
+
+// MaxSuggestions caps the suggestions drafted per job so the review queue stays short
+const MaxSuggestions = 10
+
+// Suggestion is a drafted bullet for a requirement the experience bank does not cover
+type Suggestion struct {
+	Skill    string `json:"skill"`              // Canonical skill name, e.g. Go for golang
+	Source   string `json:"source"`             // SourceHardRequirement or SourceNiceToHave
+	Level    string `json:"level,omitempty"`    // e.g. "3+ years"
+	Evidence string `json:"evidence,omitempty"` // Quote from the job posting
+	Question string `json:"question"`           // What to ask the user, e.g. "Did you work with Kubernetes?"
+	Draft    string `json:"draft"`              // Bullet template with bracketed blanks for the user to fill in
+}
+
+// Report lists the uncovered requirements of one job
+type Report struct {
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// Summary describes the report in one line for run logs
+func (r *Report) Summary() string {
+	if len(r.Suggestions) == 0 {
+		return "every requirement is covered by the experience bank"
+	}
+	skills := make([]string, len(r.Suggestions))
+	for i, s := range r.Suggestions {
+		skills[i] = s.Skill
+	}
+	return fmt.Sprintf("%d uncovered %s (%s)", len(skills), plural(len(skills), "requirement", "requirements"), strings.Join(skills, ", "))
+}
+
+// Suggest drafts a suggestion for each hard requirement, then each nice-to-have, that no
+// bullet in bank claims as a skill or mentions in its text
+func Suggest(profile *types.JobProfile, bank *types.ExperienceBank) *Report {
+	report := &Report{Suggestions: []Suggestion{}}
+	if profile == nil {
+		return report
+	}
+
+	covered := make(map[string]bool)
+	var texts []string
+	if bank != nil {
+		for _, story := range bank.Stories {
+			for _, bullet := range story.Bullets {
+				for _, skill := range bullet.Skills {
+					covered[strings.ToLower(parsing.NormalizeSkillName(skill))] = true
+				}
+				texts = append(texts, strings.ToLower(bullet.Text))
+			}
+		}
+	}
+
+	add := func(reqs []types.Requirement, source string) {
+		for _, req := range reqs {
+			if len(report.Suggestions) == MaxSuggestions {
+				return
+			}
+			skill := parsing.NormalizeSkillName(req.Skill)
+			if raw := strings.TrimSpace(req.Skill); strings.EqualFold(skill, raw) {
+				skill = raw // Keep the posting's capitalization, e.g. SQL rather than Sql
+			}
+			key := strings.ToLower(skill)
+			if skill == "" || covered[key] || mentioned(texts, key) {
+				continue
+			}
+			covered[key] = true // One suggestion per skill
+			report.Suggestions = append(report.Suggestions, Suggestion{
+				Skill:    skill,
+				Source:   source,
+				Level:    strings.TrimSpace(req.Level),
+				Evidence: strings.TrimSpace(req.Evidence),
+				Question: question(skill, req.Level),
+				Draft:    fmt.Sprintf("Used %s to [what you built or improved], [measurable result].", skill),
+			})
+		}
+	}
+	add(profile.HardRequirements, SourceHardRequirement)
+	add(profile.NiceToHaves, SourceNiceToHave)
+	return report
+}
+
+func question(skill, level string) string {
+	if level = strings.TrimSpace(level); level != "" {
+		return fmt.Sprintf("Did you work with %s (the posting asks for %s)?", skill, level)
+	}
+	return fmt.Sprintf("Did you work with %s?", skill)
+}
+
+// mentioned reports whether any text contains term as a whole word; texts and term are
+// lowercase. Word boundaries are checked by hand so terms like "c++" still match.
+func mentioned(texts []string, term string) bool {
+	for _, text := range texts {
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], term)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(term)
+			if !wordRuneBefore(text, start) && !wordRuneAt(text, end) {
+				return true
+			}
+			offset = start + 1
+		}
+	}
+	return false
+}
+
+func wordRuneBefore(s string, i int) bool {
+	r, size := utf8.DecodeLastRuneInString(s[:i])
+	return size > 0 && isWordRune(r)
+}
+
+func wordRuneAt(s string, i int) bool {
+	r, size := utf8.DecodeRuneInString(s[i:])
+	return size > 0 && isWordRune(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
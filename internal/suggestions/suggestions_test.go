@@ -0,0 +1,54 @@
+package suggestions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+func TestSuggest(t *testing.T) {
+	profile := &types.JobProfile{
+		HardRequirements: []types.Requirement{
+			{Skill: "golang", Evidence: "Strong Go experience"},
+			{Skill: "Kubernetes", Level: "3+ years", Evidence: "3+ years operating Kubernetes"},
+			{Skill: "C++"},
+			{Skill: "kubernetes"},
+		},
+		NiceToHaves: []types.Requirement{
+			{Skill: "Terraform", Evidence: "Terraform a plus"},
+			{Skill: "SQL"},
+		},
+	}
+	bank := &types.ExperienceBank{Stories: []types.Story{{
+		Bullets: []types.Bullet{
+			{Text: "Rewrote the C++ matching engine", Skills: []string{"Go"}},
+			{Text: "Tuned NoSQL stores"},
+		},
+	}}}
+
+	report := Suggest(profile, bank)
+	require.Len(t, report.Suggestions, 3)
+
+	k8s := report.Suggestions[0]
+	assert.Equal(t, SourceHardRequirement, k8s.Source)
+	assert.Equal(t, "Did you work with Kubernetes (the posting asks for 3+ years)?", k8s.Question)
+	assert.Equal(t, "3+ years operating Kubernetes", k8s.Evidence)
+	assert.Contains(t, k8s.Draft, "Used Kubernetes to [")
+
+	assert.Equal(t, "Terraform", report.Suggestions[1].Skill)
+	assert.Equal(t, SourceNiceToHave, report.Suggestions[1].Source)
+	assert.Equal(t, "Did you work with Terraform?", report.Suggestions[1].Question)
+	// "NoSQL" does not count as mentioning SQL
+	assert.Equal(t, "SQL", report.Suggestions[2].Skill)
+	assert.Equal(t, "3 uncovered requirements (Kubernetes, Terraform, SQL)", report.Summary())
+}
+
+func TestSuggest_Covered(t *testing.T) {
+	report := Suggest(&types.JobProfile{HardRequirements: []types.Requirement{{Skill: "Go"}}},
+		&types.ExperienceBank{Stories: []types.Story{{Bullets: []types.Bullet{{Skills: []string{"golang"}}}}}})
+	assert.Empty(t, report.Suggestions)
+	assert.Equal(t, "every requirement is covered by the experience bank", report.Summary())
+}
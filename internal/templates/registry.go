@@ -0,0 +1,202 @@
+// Package templates is the registry of resume templates runs can select by name: the
+// built-in templates embedded in the binary and the templates users upload.
+package templates
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+	embedded "github.com/jonathan/resume-customizer/templates"
+)
+
+// ErrNotFound is returned when no template has the requested name
+var ErrNotFound = errors.New("template not found")
+
+// namePattern is what a registry name looks like; file paths never match it, since they
+// contain a slash or an extension
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Template describes a registered template and the rendering options it supports
+type Template struct {
+	Name    string `json:"name"`
+	BuiltIn bool   `json:"built_in"`
+	rendering.TemplateManifest
+}
+
+// Resolved is a template ready to render from local disk
+type Resolved struct {
+	Path     string
+	Manifest *rendering.TemplateManifest
+}
+
+// Store looks up uploaded templates; *db.DB implements it
+type Store interface {
+	GetResumeTemplateByName(ctx context.Context, userID uuid.UUID, name string) (*db.ResumeTemplate, error)
+}
+
+// IsName reports whether ref is a registry name rather than a template file path
+func IsName(ref string) bool {
+	return namePattern.MatchString(ref)
+}
+
+// ValidateName checks that name can be given to an uploaded template
+func ValidateName(name string) error {
+	if !IsName(name) {
+		return errors.New("name must be 1-64 lowercase letters, digits, '-' or '_', starting with a letter or digit")
+	}
+	if _, _, ok := builtIn(name); ok {
+		return fmt.Errorf("name %q is reserved by a built-in template", name)
+	}
+	return nil
+}
+
+// BuiltIn lists the templates embedded in the binary, by name
+func BuiltIn() []Template {
+	paths, _ := fs.Glob(embedded.Files, "*.tex")
+	sort.Strings(paths)
+	list := make([]Template, 0, len(paths))
+	for _, path := range paths {
+		name := strings.TrimSuffix(path, ".tex")
+		if _, manifest, ok := builtIn(name); ok {
+			list = append(list, Template{Name: name, BuiltIn: true, TemplateManifest: *manifest})
+		}
+	}
+	return list
+}
+
+// FromUpload describes an uploaded template
+func FromUpload(t *db.ResumeTemplate) (Template, error) {
+	manifest, err := decodeManifest(t.Manifest)
+	if err != nil {
+		return Template{}, fmt.Errorf("template %q: %w", t.Name, err)
+	}
+	return Template{Name: t.Name, TemplateManifest: *manifest}, nil
+}
+
+// Resolve finds the template a run refers to and returns where to render it from. A file
+// path is used as is (rendering reports it if missing). A name is looked up among the
+// user's uploads, when store and userID are set, and then the built-in templates, and
+// written to a local cache keyed by its content, so every process rendering the run,
+// remote step workers included, gets the same copy.
+func Resolve(ctx context.Context, store Store, userID *uuid.UUID, ref string) (*Resolved, error) {
+	if !IsName(ref) {
+		manifest, err := rendering.LoadTemplateManifest(ref)
+		if err != nil {
+			return nil, err
+		}
+		return &Resolved{Path: ref, Manifest: manifest}, nil
+	}
+
+	if store != nil && userID != nil {
+		uploaded, err := store.GetResumeTemplateByName(ctx, *userID, ref)
+		if err != nil {
+			return nil, err
+		}
+		if uploaded != nil {
+			manifest, err := decodeManifest(uploaded.Manifest)
+			if err != nil {
+				return nil, fmt.Errorf("template %q: %w", ref, err)
+			}
+			return materialize(ref, uploaded.Content, manifest)
+		}
+	}
+	if content, manifest, ok := builtIn(ref); ok {
+		return materialize(ref, content, manifest)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+}
+
+// builtIn returns an embedded template's source and manifest
+func builtIn(name string) (string, *rendering.TemplateManifest, bool) {
+	if !IsName(name) {
+		return "", nil, false
+	}
+	content, err := embedded.Files.ReadFile(name + ".tex")
+	if err != nil {
+		return "", nil, false
+	}
+	manifest := &rendering.TemplateManifest{}
+	if raw, err := embedded.Files.ReadFile(name + ".json"); err == nil {
+		if manifest, err = decodeManifest(raw); err != nil {
+			return "", nil, false
+		}
+	}
+	return string(content), manifest, true
+}
+
+func decodeManifest(raw []byte) (*rendering.TemplateManifest, error) {
+	manifest := &rendering.TemplateManifest{}
+	if len(raw) == 0 {
+		return manifest, nil
+	}
+	if err := json.Unmarshal(raw, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// cacheDir is where resolved templates are written
+func cacheDir() string {
+	return filepath.Join(os.TempDir(), "resume-customizer-templates")
+}
+
+// materialize writes a template and its manifest under a directory named for their
+// content, unless an earlier run already did
+func materialize(name, content string, manifest *rendering.TemplateManifest) (*Resolved, error) {
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode template manifest: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(content+"\x00"), manifestJSON...))
+	dir := filepath.Join(cacheDir(), hex.EncodeToString(sum[:8]))
+	path := filepath.Join(dir, name+".tex")
+	if _, err := os.Stat(path); err == nil {
+		return &Resolved{Path: path, Manifest: manifest}, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create template cache: %w", err)
+	}
+	// The manifest is written first, so a template file is never seen without it
+	if err := writeFileAtomic(rendering.ManifestPath(path), manifestJSON); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, []byte(content)); err != nil {
+		return nil, err
+	}
+	return &Resolved{Path: path, Manifest: manifest}, nil
+}
+
+// writeFileAtomic writes data to path through a temporary file, so concurrent runs
+// resolving the same template never read a partial copy
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write template cache: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write template cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write template cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write template cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/rendering"
+)
+
+// fakeStore holds one user's uploaded templates
+type fakeStore struct {
+	userID    uuid.UUID
+	templates map[string]*db.ResumeTemplate
+}
+
+func (f *fakeStore) GetResumeTemplateByName(_ context.Context, userID uuid.UUID, name string) (*db.ResumeTemplate, error) {
+	if userID != f.userID {
+		return nil, nil
+	}
+	return f.templates[name], nil
+}
+
+func TestBuiltIn(t *testing.T) {
+	list := BuiltIn()
+	require.NotEmpty(t, list)
+	var onePage *Template
+	for i := range list {
+		assert.True(t, list[i].BuiltIn)
+		if list[i].Name == "one_page_resume" {
+			onePage = &list[i]
+		}
+	}
+	require.NotNil(t, onePage)
+	assert.Equal(t, 1, onePage.Columns)
+	assert.Positive(t, onePage.MaxLinesPerPage)
+	assert.Contains(t, onePage.Sections, "experience")
+}
+
+func TestValidateName(t *testing.T) {
+	assert.NoError(t, ValidateName("two-column_v2"))
+	assert.Error(t, ValidateName(""))
+	assert.Error(t, ValidateName("Two Column"))
+	assert.Error(t, ValidateName("templates/mine.tex"), "paths are not names")
+	assert.Error(t, ValidateName("one_page_resume"), "built-in names are reserved")
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ctx := context.Background()
+	userID := uuid.New()
+	manifest, err := json.Marshal(rendering.TemplateManifest{Columns: 2, MaxLinesPerPage: 50})
+	require.NoError(t, err)
+	store := &fakeStore{userID: userID, templates: map[string]*db.ResumeTemplate{
+		"two-column": {Name: "two-column", Content: `\documentclass{article}`, Manifest: manifest},
+	}}
+
+	// Paths pass through untouched
+	resolved, err := Resolve(ctx, store, &userID, "templates/missing.tex")
+	require.NoError(t, err)
+	assert.Equal(t, "templates/missing.tex", resolved.Path)
+
+	// Built-in templates are written out with their manifest
+	resolved, err = Resolve(ctx, nil, nil, "one_page_resume")
+	require.NoError(t, err)
+	content, err := os.ReadFile(resolved.Path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `\documentclass`)
+	onDisk, err := rendering.LoadTemplateManifest(resolved.Path)
+	require.NoError(t, err)
+	assert.Equal(t, resolved.Manifest, onDisk)
+
+	again, err := Resolve(ctx, nil, nil, "one_page_resume")
+	require.NoError(t, err)
+	assert.Equal(t, resolved.Path, again.Path, "the same content resolves to the same copy")
+
+	// Uploads are visible to their owner only
+	resolved, err = Resolve(ctx, store, &userID, "two-column")
+	require.NoError(t, err)
+	assert.Equal(t, "two-column.tex", filepath.Base(resolved.Path))
+	assert.Equal(t, 2, resolved.Manifest.Columns)
+
+	other := uuid.New()
+	_, err = Resolve(ctx, store, &other, "two-column")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
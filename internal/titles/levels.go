@@ -0,0 +1,53 @@
+package titles
+
+import "strings"
+
+// companyLevels maps the level codes companies put in engineering titles to the shared
+// levels, following their published career ladders. Keys are lowercase company names.
+var companyLevels = map[string]map[string]Level{
+	"google": {
+		"l3": LevelJunior, "l4": LevelMid, "l5": LevelSenior, "l6": LevelStaff,
+		"l7": LevelStaff, "l8": LevelPrincipal, "l9": LevelDistinguished,
+	},
+	"meta": {
+		"e3": LevelJunior, "e4": LevelMid, "e5": LevelSenior, "e6": LevelStaff,
+		"e7": LevelStaff, "e8": LevelPrincipal, "e9": LevelDistinguished,
+	},
+	"amazon": {
+		"l4": LevelJunior, "l5": LevelMid, "l6": LevelSenior, "l7": LevelPrincipal,
+		"l8": LevelPrincipal, "l10": LevelDistinguished,
+	},
+	"microsoft": {
+		"59": LevelJunior, "60": LevelJunior, "61": LevelMid, "62": LevelMid,
+		"63": LevelSenior, "64": LevelSenior, "65": LevelStaff, "66": LevelPrincipal,
+		"67": LevelPrincipal, "68": LevelPrincipal, "69": LevelPrincipal, "70": LevelDistinguished,
+	},
+	"apple": {
+		"ict2": LevelJunior, "ict3": LevelMid, "ict4": LevelSenior, "ict5": LevelStaff,
+		"ict6": LevelPrincipal,
+	},
+}
+
+// companyAliases maps other names companies go by to their companyLevels keys
+var companyAliases = map[string]string{
+	"alphabet":            "google",
+	"facebook":            "meta",
+	"meta platforms":      "meta",
+	"aws":                 "amazon",
+	"amazon web services": "amazon",
+}
+
+// companySuffixes are dropped from company names before looking up their leveling map
+var companySuffixes = []string{" inc", " llc", " corp", " corporation", " ltd", " co"}
+
+// companyKey returns the companyLevels key for a company name
+func companyKey(company string) string {
+	key := strings.TrimSpace(strings.NewReplacer(".com", "", ".", "", ",", "").Replace(strings.ToLower(company)))
+	for _, suffix := range companySuffixes {
+		key = strings.TrimSuffix(key, suffix)
+	}
+	if alias, ok := companyAliases[key]; ok {
+		return alias
+	}
+	return key
+}
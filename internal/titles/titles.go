@@ -0,0 +1,179 @@
+// Package titles normalizes job titles, so the same role written different ways at
+// different companies ("Sr. SWE", "Software Developer III", "Software Engineer, L5 at
+// Google") compares equal by role family and level.
+package titles
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Level is a seniority level shared across companies' career ladders
+type Level int
+
+// Levels from least to most senior
+const (
+	LevelUnknown Level = iota
+	LevelIntern
+	LevelJunior
+	LevelMid
+	LevelSenior
+	LevelStaff
+	LevelPrincipal
+	LevelDistinguished
+)
+
+// levelNames are the stored names of each level
+var levelNames = []string{"", "intern", "junior", "mid", "senior", "staff", "principal", "distinguished"}
+
+// String returns the level's name, or "" when it is unknown
+func (l Level) String() string {
+	if l < LevelUnknown || int(l) >= len(levelNames) {
+		return ""
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the level named name, or LevelUnknown
+func ParseLevel(name string) Level {
+	for i, n := range levelNames {
+		if n != "" && strings.EqualFold(n, strings.TrimSpace(name)) {
+			return Level(i)
+		}
+	}
+	return LevelUnknown
+}
+
+// Title is a job title reduced to its role family and level
+type Title struct {
+	Canonical string // e.g. "Senior Software Engineer"
+	Family    string // e.g. "software engineer"; "" for a title with no role words
+	Level     Level
+}
+
+var (
+	// qualifierPattern matches a team, location, or level qualifier after the title
+	// proper, as in "Software Engineer, Payments" or "Data Scientist (Remote)"
+	qualifierPattern = regexp.MustCompile(`\s*(?:[,(|/]|\s[-–—]\s).*$`)
+	nonWordPattern   = regexp.MustCompile(`[^a-z0-9+#]+`)
+	// levelCodePattern matches a company level code such as "L5", "E6", "ICT4", or "63";
+	// codes are left out of the family and read through the company's leveling map
+	levelCodePattern = regexp.MustCompile(`^(?:[a-z]|ict)?[0-9]{1,2}$`)
+)
+
+// seniorityWords set a title's level and are left out of its family. Words mapped to
+// LevelUnknown, such as "head", say nothing about the level on a career ladder.
+var seniorityWords = map[string]Level{
+	"intern": LevelIntern, "internship": LevelIntern,
+	"junior": LevelJunior, "jr": LevelJunior, "entry": LevelJunior, "graduate": LevelJunior,
+	"associate": LevelJunior, "i": LevelJunior, "1": LevelJunior,
+	"mid": LevelMid, "ii": LevelMid, "2": LevelMid,
+	"senior": LevelSenior, "sr": LevelSenior, "lead": LevelSenior, "iii": LevelSenior, "3": LevelSenior,
+	"staff": LevelStaff, "iv": LevelStaff, "4": LevelStaff,
+	"principal": LevelPrincipal, "v": LevelPrincipal, "5": LevelPrincipal,
+	"distinguished": LevelDistinguished, "fellow": LevelDistinguished,
+	"level": LevelUnknown, "chief": LevelUnknown, "head": LevelUnknown,
+}
+
+// roleSynonyms normalizes words that name the same role
+var roleSynonyms = map[string]string{
+	"developer":  "engineer",
+	"dev":        "engineer",
+	"eng":        "engineer",
+	"engineers":  "engineer",
+	"programmer": "engineer",
+	"swe":        "software engineer",
+	"sde":        "software engineer",
+	"sre":        "site reliability engineer",
+	"mgr":        "manager",
+	"pm":         "product manager",
+	"em":         "engineering manager",
+	"tpm":        "technical program manager",
+}
+
+// displayWords are family words not written in title case
+var displayWords = map[string]string{
+	"ai": "AI", "ml": "ML", "qa": "QA", "ui": "UI", "ux": "UX", "ios": "iOS",
+	"devops": "DevOps", "api": "API", "sdet": "SDET", "it": "IT", "and": "and",
+}
+
+// levelPrefixes are written before the family in canonical titles
+var levelPrefixes = map[Level]string{
+	LevelJunior:        "Junior",
+	LevelSenior:        "Senior",
+	LevelStaff:         "Staff",
+	LevelPrincipal:     "Principal",
+	LevelDistinguished: "Distinguished",
+}
+
+// Normalize reduces a job title to its role family and level, ignoring team and location
+// qualifiers: "Sr. SWE" and "Software Developer III, Payments" both become "Senior
+// Software Engineer". When a title names more than one level the most senior wins.
+func Normalize(title string) Title {
+	main := qualifierPattern.ReplaceAllString(strings.ToLower(title), "")
+	var words []string
+	level := LevelUnknown
+	for _, word := range strings.Fields(nonWordPattern.ReplaceAllString(main, " ")) {
+		if l, ok := seniorityWords[word]; ok {
+			level = max(level, l)
+			continue
+		}
+		if word == "of" || levelCodePattern.MatchString(word) {
+			continue
+		}
+		if synonym, ok := roleSynonyms[word]; ok {
+			word = synonym
+		}
+		words = append(words, word)
+	}
+	t := Title{Family: strings.Join(words, " "), Level: level}
+	t.Canonical = canonical(t, title)
+	return t
+}
+
+// NormalizeAt normalizes a title held at company, reading level codes the company uses in
+// titles (Google's "L5", Meta's "E5") through its leveling map. A code outranks the level
+// words in the title.
+func NormalizeAt(title, company string) Title {
+	t := Normalize(title)
+	levels := companyLevels[companyKey(company)]
+	if levels == nil {
+		return t
+	}
+	for _, word := range strings.Fields(nonWordPattern.ReplaceAllString(strings.ToLower(title), " ")) {
+		if l, ok := levels[word]; ok {
+			t.Level = l
+			t.Canonical = canonical(t, title)
+			break
+		}
+	}
+	return t
+}
+
+// SameRole reports whether two titles name the same role family
+func SameRole(a, b Title) bool {
+	return a.Family != "" && a.Family == b.Family
+}
+
+// canonical writes a normalized title, or the original one trimmed when it has no role words
+func canonical(t Title, original string) string {
+	if t.Family == "" {
+		return strings.TrimSpace(original)
+	}
+	words := strings.Fields(t.Family)
+	for i, w := range words {
+		if display, ok := displayWords[w]; ok {
+			words[i] = display
+		} else {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	name := strings.Join(words, " ")
+	if t.Level == LevelIntern {
+		return name + " Intern"
+	}
+	if prefix, ok := levelPrefixes[t.Level]; ok {
+		return prefix + " " + name
+	}
+	return name
+}
@@ -0,0 +1,60 @@
+package titles
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]Title{
+		"Sr. SWE":                          {"Senior Software Engineer", "software engineer", LevelSenior},
+		"Software Developer III, Payments": {"Senior Software Engineer", "software engineer", LevelSenior},
+		"Software Engineer II":             {"Software Engineer", "software engineer", LevelMid},
+		"Jr Data Scientist (Remote)":       {"Junior Data Scientist", "data scientist", LevelJunior},
+		"Staff ML Engineer":                {"Staff ML Engineer", "ml engineer", LevelStaff},
+		"Principal SRE":                    {"Principal Site Reliability Engineer", "site reliability engineer", LevelPrincipal},
+		"Software Engineering Intern":      {"Software Engineering Intern", "software engineering", LevelIntern},
+		"Senior Staff Engineer":            {"Staff Engineer", "engineer", LevelStaff},
+		"EM - Platform":                    {"Engineering Manager", "engineering manager", LevelUnknown},
+		"Head of Engineering":              {"Engineering", "engineering", LevelUnknown},
+		"Senior":                           {"Senior", "", LevelSenior},
+	}
+	for title, want := range tests {
+		assert.Equal(t, want, Normalize(title), title)
+	}
+}
+
+func TestNormalizeAt(t *testing.T) {
+	tests := []struct {
+		title, company string
+		want           Level
+	}{
+		{"Software Engineer, L5", "Google", LevelSenior},
+		{"Software Engineer L5", "Amazon.com, Inc.", LevelMid},
+		{"Software Engineer (E6)", "Facebook", LevelStaff},
+		{"Software Engineer 63", "Microsoft Corporation", LevelSenior},
+		{"Senior Software Engineer", "Acme", LevelSenior},
+		{"Software Engineer L5", "Acme", LevelUnknown},
+	}
+	for _, tc := range tests {
+		got := NormalizeAt(tc.title, tc.company)
+		assert.Equal(t, tc.want, got.Level, "%s at %s", tc.title, tc.company)
+		assert.Equal(t, "software engineer", got.Family)
+	}
+	assert.Equal(t, "Senior Software Engineer", NormalizeAt("Software Engineer, L5", "Google").Canonical)
+}
+
+func TestLevel(t *testing.T) {
+	for l := LevelIntern; l <= LevelDistinguished; l++ {
+		assert.Equal(t, l, ParseLevel(l.String()))
+	}
+	assert.Equal(t, LevelUnknown, ParseLevel("wizard"))
+	assert.Equal(t, "", LevelUnknown.String())
+}
+
+func TestSameRole(t *testing.T) {
+	assert.True(t, SameRole(Normalize("Sr. SWE"), Normalize("Software Developer I")))
+	assert.False(t, SameRole(Normalize("Senior Software Engineer"), Normalize("Product Manager")))
+	assert.False(t, SameRole(Normalize("Senior"), Normalize("Staff")))
+}
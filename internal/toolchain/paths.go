@@ -0,0 +1,148 @@
+package toolchain
+
+import (
+	"sort"
+	"strings"
+)
+
+// latexFiles returns where engine is installed outside PATH: TeX distributions often
+// aren't on PATH for services, and MiKTeX and TeX Live on Windows never are for new shells
+func (s system) latexFiles(engine string) []string {
+	var dirs []string
+	switch s.goos {
+	case "windows":
+		dirs = append(dirs,
+			s.envJoin("LOCALAPPDATA", "Programs", "MiKTeX", "miktex", "bin", "x64"),
+			s.envJoin("ProgramFiles", "MiKTeX", "miktex", "bin", "x64"),
+		)
+		// TeX Live names its binary directory win64 or windows depending on the release
+		for _, arch := range []string{"windows", "win64", "win32"} {
+			dirs = append(dirs, s.newestFirst(s.join(`C:`, "texlive", "*", "bin", arch))...)
+		}
+		dirs = append(dirs, s.envJoin("USERPROFILE", ".cargo", "bin"))
+	case "darwin":
+		dirs = append(dirs, "/Library/TeX/texbin")
+		dirs = append(dirs, s.newestFirst("/usr/local/texlive/*/bin/universal-darwin")...)
+		dirs = append(dirs, "/opt/homebrew/bin", "/usr/local/bin", s.envJoin("HOME", ".cargo", "bin"))
+	default:
+		// One directory per architecture, e.g. x86_64-linux or aarch64-linux
+		dirs = append(dirs, s.newestFirst("/usr/local/texlive/*/bin/*")...)
+		dirs = append(dirs, s.envJoin("HOME", ".cargo", "bin"))
+	}
+	return s.inDirs(dirs, engine)
+}
+
+// browserNames returns Chromium-based browser commands to look for on PATH. Google
+// Chrome is not built for Linux on ARM, so Chromium comes first there.
+func (s system) browserNames() []string {
+	switch s.goos {
+	case "windows":
+		return []string{"chrome", "msedge", "chromium"}
+	case "darwin":
+		return []string{"google-chrome", "chromium"}
+	default:
+		return []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "headless_shell", "microsoft-edge"}
+	}
+}
+
+// browserFiles returns where browsers install outside PATH. Edge ships with Windows,
+// including Windows on ARM, so it is the fallback there.
+func (s system) browserFiles() []string {
+	switch s.goos {
+	case "windows":
+		return nonEmpty(
+			s.envJoin("ProgramFiles", "Google", "Chrome", "Application", "chrome.exe"),
+			s.envJoin("ProgramFiles(x86)", "Google", "Chrome", "Application", "chrome.exe"),
+			s.envJoin("LOCALAPPDATA", "Google", "Chrome", "Application", "chrome.exe"),
+			s.envJoin("ProgramFiles", "Microsoft", "Edge", "Application", "msedge.exe"),
+			s.envJoin("ProgramFiles(x86)", "Microsoft", "Edge", "Application", "msedge.exe"),
+		)
+	case "darwin":
+		return []string{
+			"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+			"/Applications/Chromium.app/Contents/MacOS/Chromium",
+			"/Applications/Microsoft Edge.app/Contents/MacOS/Microsoft Edge",
+		}
+	default:
+		return []string{"/snap/bin/chromium"}
+	}
+}
+
+// ghostscriptNames returns Ghostscript console commands; on Windows they are named by
+// word size rather than gs
+func (s system) ghostscriptNames() []string {
+	if s.goos == "windows" {
+		return []string{"gswin64c", "gswin32c"}
+	}
+	return []string{"gs"}
+}
+
+// ghostscriptFiles returns where the Windows installer puts Ghostscript, which it does
+// not add to PATH
+func (s system) ghostscriptFiles() []string {
+	if s.goos != "windows" {
+		return nil
+	}
+	var files []string
+	for _, env := range []string{"ProgramFiles", "ProgramFiles(x86)"} {
+		if dir := s.envJoin(env, "gs", "gs*", "bin"); dir != "" {
+			for _, bin := range s.newestFirst(dir) {
+				files = append(files, s.join(bin, "gswin64c.exe"), s.join(bin, "gswin32c.exe"))
+			}
+		}
+	}
+	return files
+}
+
+// inDirs returns the path of program in each directory, with .exe on Windows
+func (s system) inDirs(dirs []string, program string) []string {
+	if s.goos == "windows" {
+		program += ".exe"
+	}
+	var files []string
+	for _, dir := range dirs {
+		if dir != "" {
+			files = append(files, s.join(dir, program))
+		}
+	}
+	return files
+}
+
+// envJoin joins elems onto the directory in the environment variable, or returns ""
+// if it is unset
+func (s system) envJoin(env string, elems ...string) string {
+	dir := s.getenv(env)
+	if dir == "" {
+		return ""
+	}
+	return s.join(append([]string{dir}, elems...)...)
+}
+
+// join joins path elements with the separator of the target platform
+func (s system) join(elems ...string) string {
+	if s.goos == "windows" {
+		return strings.Join(elems, `\`)
+	}
+	return strings.Join(elems, "/")
+}
+
+// newestFirst expands pattern, ordering versioned directories such as TeX Live years
+// from newest to oldest
+func (s system) newestFirst(pattern string) []string {
+	matches, err := s.glob(pattern)
+	if err != nil {
+		return nil
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	return matches
+}
+
+func nonEmpty(paths ...string) []string {
+	var kept []string
+	for _, p := range paths {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,229 @@
+// Package toolchain locates the external programs resumes depend on: a LaTeX compiler,
+// poppler or Ghostscript for page counts, and a Chromium-based browser for rendering
+// JavaScript-heavy pages. Programs are found on PATH or in the usual install locations
+// of Linux, macOS, and Windows on amd64 and arm64, unless overridden by configuration.
+// A program that cannot be found is reported as unavailable so callers can skip the
+// features that need it instead of failing outright.
+package toolchain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+)
+
+// ErrUnavailable is wrapped by errors from features whose program was not found
+var ErrUnavailable = errors.New("tool not available")
+
+// LaTeX is a LaTeX compiler and the engine it runs
+type LaTeX struct {
+	Engine string // config.LaTeXEngine* value
+	Path   string
+}
+
+// Args returns the arguments that compile texPath into outDir without prompting
+func (l *LaTeX) Args(texPath, outDir string) []string {
+	switch l.Engine {
+	case config.LaTeXEngineLatexmk:
+		return []string{"-pdf", "-interaction=nonstopmode", "-output-directory=" + outDir, texPath}
+	case config.LaTeXEngineTectonic:
+		return []string{"--outdir", outDir, "--keep-logs", texPath}
+	default:
+		return []string{"-interaction=nonstopmode", "-output-directory", outDir, texPath}
+	}
+}
+
+// PackageInstalled reports whether the LaTeX package name is installed, asking the
+// kpsewhich beside the compiler. It returns ErrUnavailable when that cannot be checked:
+// without kpsewhich, or with tectonic, which downloads packages as documents use them.
+func (l *LaTeX) PackageInstalled(name string) (bool, error) {
+	if l.Engine == config.LaTeXEngineTectonic {
+		return false, fmt.Errorf("%w: tectonic installs packages on demand", ErrUnavailable)
+	}
+	kpsewhich := filepath.Join(filepath.Dir(l.Path), "kpsewhich"+filepath.Ext(l.Path))
+	if _, err := os.Stat(kpsewhich); err != nil {
+		return false, fmt.Errorf("%w: kpsewhich not found beside %s", ErrUnavailable, l.Path)
+	}
+	out, err := exec.Command(kpsewhich, name+".sty").Output()
+	if err != nil {
+		// kpsewhich exits 1 when the file is not found
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to run kpsewhich: %w", err)
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
+
+// Toolchain is the set of programs found on this machine. Empty fields are unavailable.
+type Toolchain struct {
+	LaTeX       *LaTeX
+	Browser     string // Chrome, Chromium, or Edge
+	PDFInfo     string
+	PDFToPPM    string
+	PDFToText   string
+	Ghostscript string
+}
+
+// PackageInstalled reports whether the LaTeX package name is installed for the compiler
+// found, or returns ErrUnavailable when that cannot be checked
+func (t *Toolchain) PackageInstalled(name string) (bool, error) {
+	if t.LaTeX == nil {
+		return false, fmt.Errorf("%w: no LaTeX compiler", ErrUnavailable)
+	}
+	return t.LaTeX.PackageInstalled(name)
+}
+
+// Missing describes each unavailable program and what stops working without it
+func (t *Toolchain) Missing() []string {
+	var missing []string
+	if t.LaTeX == nil {
+		missing = append(missing, "LaTeX compiler (pdflatex, latexmk, or tectonic): PDFs are not produced and page limits are not checked")
+	}
+	if t.PDFInfo == "" && t.Ghostscript == "" {
+		missing = append(missing, "pdfinfo or Ghostscript: page limits are not checked")
+	}
+	if t.PDFToPPM == "" && t.Ghostscript == "" {
+		missing = append(missing, "pdftoppm or Ghostscript: resume thumbnails are not generated")
+	}
+	if t.PDFToText == "" && t.Ghostscript == "" {
+		missing = append(missing, "pdftotext or Ghostscript: resume PDFs cannot be imported")
+	}
+	if t.Browser == "" {
+		missing = append(missing, "Chrome, Chromium, or Edge: JavaScript-rendered pages are read without rendering")
+	}
+	return missing
+}
+
+// String lists the program used for each feature, for logs and the tools command
+func (t *Toolchain) String() string {
+	var sb strings.Builder
+	latex := "not found"
+	if t.LaTeX != nil {
+		latex = fmt.Sprintf("%s (%s)", t.LaTeX.Path, t.LaTeX.Engine)
+	}
+	fmt.Fprintf(&sb, "platform:    %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&sb, "latex:       %s\n", latex)
+	fmt.Fprintf(&sb, "pdfinfo:     %s\n", orNotFound(t.PDFInfo))
+	fmt.Fprintf(&sb, "pdftoppm:    %s\n", orNotFound(t.PDFToPPM))
+	fmt.Fprintf(&sb, "pdftotext:   %s\n", orNotFound(t.PDFToText))
+	fmt.Fprintf(&sb, "ghostscript: %s\n", orNotFound(t.Ghostscript))
+	fmt.Fprintf(&sb, "browser:     %s\n", orNotFound(t.Browser))
+	return sb.String()
+}
+
+func orNotFound(path string) string {
+	if path == "" {
+		return "not found"
+	}
+	return path
+}
+
+var (
+	defaultOnce  sync.Once
+	defaultTools *Toolchain
+	defaultErr   error
+)
+
+// Default returns the toolchain configured by the environment, discovered once per process
+func Default() (*Toolchain, error) {
+	defaultOnce.Do(func() {
+		cfg, err := config.NewToolchainConfig()
+		if err != nil {
+			defaultErr = fmt.Errorf("failed to create toolchain config: %w", err)
+			return
+		}
+		defaultTools = Discover(cfg)
+	})
+	return defaultTools, defaultErr
+}
+
+// Discover finds the programs on this machine, honoring the overrides in cfg
+func Discover(cfg *config.ToolchainConfig) *Toolchain {
+	return hostSystem().discover(cfg)
+}
+
+// system is the view of the host discovery needs, so other platforms can be tested
+type system struct {
+	goos     string
+	getenv   func(string) string
+	lookPath func(string) (string, error)
+	glob     func(string) ([]string, error)
+	isFile   func(string) bool
+}
+
+func hostSystem() system {
+	return system{
+		goos:     runtime.GOOS,
+		getenv:   os.Getenv,
+		lookPath: exec.LookPath,
+		glob:     filepath.Glob,
+		isFile: func(path string) bool {
+			info, err := os.Stat(path)
+			return err == nil && !info.IsDir()
+		},
+	}
+}
+
+func (s system) discover(cfg *config.ToolchainConfig) *Toolchain {
+	t := &Toolchain{
+		Browser:     s.find(cfg.BrowserPath, s.browserNames(), s.browserFiles()),
+		PDFInfo:     s.find(cfg.PDFInfoPath, []string{"pdfinfo"}, nil),
+		PDFToPPM:    s.find(cfg.PDFToPPMPath, []string{"pdftoppm"}, nil),
+		PDFToText:   s.find(cfg.PDFToTextPath, []string{"pdftotext"}, nil),
+		Ghostscript: s.find(cfg.GhostscriptPath, s.ghostscriptNames(), s.ghostscriptFiles()),
+	}
+
+	engines := []string{config.LaTeXEnginePdflatex, config.LaTeXEngineLatexmk, config.LaTeXEngineTectonic}
+	if cfg.LaTeXEngine != "" {
+		engines = []string{cfg.LaTeXEngine}
+	}
+	if cfg.LaTeXPath != "" {
+		if path := s.find(cfg.LaTeXPath, nil, nil); path != "" {
+			t.LaTeX = &LaTeX{Engine: engines[0], Path: path}
+		}
+		return t
+	}
+	for _, engine := range engines {
+		if path := s.find("", []string{engine}, s.latexFiles(engine)); path != "" {
+			t.LaTeX = &LaTeX{Engine: engine, Path: path}
+			break
+		}
+	}
+	return t
+}
+
+// find returns override if it exists, else the first of names on PATH, else the first
+// existing file. An override that does not exist makes the program unavailable rather
+// than silently picking another one.
+func (s system) find(override string, names, files []string) string {
+	if override != "" {
+		if !strings.ContainsAny(override, `/\`) {
+			path, _ := s.lookPath(override)
+			return path
+		}
+		if s.isFile(override) {
+			return override
+		}
+		return ""
+	}
+	for _, name := range names {
+		if path, err := s.lookPath(name); err == nil {
+			return path
+		}
+	}
+	for _, file := range files {
+		if s.isFile(file) {
+			return file
+		}
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+package toolchain
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/config"
+)
+
+// fakeSystem is a machine with the given programs on PATH and files on disk
+func fakeSystem(goos string, env map[string]string, onPath map[string]string, files ...string) system {
+	exists := map[string]bool{}
+	for _, f := range files {
+		exists[f] = true
+	}
+	return system{
+		goos:   goos,
+		getenv: func(k string) string { return env[k] },
+		lookPath: func(name string) (string, error) {
+			if p, ok := onPath[name]; ok {
+				return p, nil
+			}
+			return "", errors.New("not found")
+		},
+		glob: func(pattern string) ([]string, error) {
+			var matches []string
+			for _, f := range files {
+				// Match against every directory prefix of the file, as a directory glob would
+				for dir := f; dir != "." && dir != "/" && dir != ""; {
+					p := strings.ReplaceAll(pattern, `\`, "/")
+					if ok, _ := path.Match(p, strings.ReplaceAll(dir, `\`, "/")); ok {
+						matches = append(matches, dir)
+					}
+					i := strings.LastIndexAny(dir, `/\`)
+					if i < 0 {
+						break
+					}
+					dir = dir[:i]
+				}
+			}
+			return matches, nil
+		},
+		isFile: func(p string) bool { return exists[p] },
+	}
+}
+
+func TestDiscover_Linux(t *testing.T) {
+	s := fakeSystem("linux", nil, map[string]string{
+		"pdflatex": "/usr/bin/pdflatex",
+		"gs":       "/usr/bin/gs",
+		"chromium": "/usr/bin/chromium",
+	})
+	tc := s.discover(&config.ToolchainConfig{})
+
+	require.NotNil(t, tc.LaTeX)
+	assert.Equal(t, LaTeX{Engine: config.LaTeXEnginePdflatex, Path: "/usr/bin/pdflatex"}, *tc.LaTeX)
+	assert.Equal(t, "/usr/bin/gs", tc.Ghostscript)
+	assert.Equal(t, "/usr/bin/chromium", tc.Browser)
+	assert.Empty(t, tc.PDFInfo)
+	assert.Empty(t, tc.Missing(), "Ghostscript alone is enough to count pages, render thumbnails, and read PDFs")
+}
+
+func TestDiscover_LinuxARMTeXLive(t *testing.T) {
+	s := fakeSystem("linux", map[string]string{"HOME": "/home/pi"}, nil,
+		"/usr/local/texlive/2023/bin/aarch64-linux/latexmk",
+		"/usr/local/texlive/2024/bin/aarch64-linux/latexmk",
+		"/snap/bin/chromium",
+	)
+	tc := s.discover(&config.ToolchainConfig{})
+
+	require.NotNil(t, tc.LaTeX)
+	assert.Equal(t, config.LaTeXEngineLatexmk, tc.LaTeX.Engine)
+	assert.Equal(t, "/usr/local/texlive/2024/bin/aarch64-linux/latexmk", tc.LaTeX.Path, "newest TeX Live wins")
+	assert.Equal(t, "/snap/bin/chromium", tc.Browser)
+	assert.Len(t, tc.Missing(), 3, "no page counter, thumbnail renderer, or PDF text extractor")
+}
+
+func TestDiscover_Windows(t *testing.T) {
+	env := map[string]string{
+		"LOCALAPPDATA":      `C:\Users\jo\AppData\Local`,
+		"ProgramFiles":      `C:\Program Files`,
+		"ProgramFiles(x86)": `C:\Program Files (x86)`,
+	}
+	s := fakeSystem("windows", env, nil,
+		`C:\Users\jo\AppData\Local\Programs\MiKTeX\miktex\bin\x64\pdflatex.exe`,
+		`C:\Program Files (x86)\Microsoft\Edge\Application\msedge.exe`,
+		`C:\Program Files\gs\gs10.02.1\bin\gswin64c.exe`,
+	)
+	tc := s.discover(&config.ToolchainConfig{})
+
+	require.NotNil(t, tc.LaTeX)
+	assert.Equal(t, `C:\Users\jo\AppData\Local\Programs\MiKTeX\miktex\bin\x64\pdflatex.exe`, tc.LaTeX.Path)
+	assert.Equal(t, `C:\Program Files (x86)\Microsoft\Edge\Application\msedge.exe`, tc.Browser)
+	assert.Equal(t, `C:\Program Files\gs\gs10.02.1\bin\gswin64c.exe`, tc.Ghostscript)
+}
+
+func TestDiscover_Overrides(t *testing.T) {
+	s := fakeSystem("linux", nil, map[string]string{
+		"pdflatex": "/usr/bin/pdflatex",
+		"tectonic": "/usr/bin/tectonic",
+		"chromium": "/usr/bin/chromium",
+		"pdftoppm": "/usr/bin/pdftoppm",
+	}, "/opt/chrome/chrome", "/opt/poppler/pdftoppm")
+
+	tc := s.discover(&config.ToolchainConfig{LaTeXEngine: config.LaTeXEngineTectonic, BrowserPath: "/opt/chrome/chrome", PDFToPPMPath: "/opt/poppler/pdftoppm"})
+	require.NotNil(t, tc.LaTeX)
+	assert.Equal(t, "/opt/poppler/pdftoppm", tc.PDFToPPM)
+	assert.Equal(t, "/usr/bin/tectonic", tc.LaTeX.Path, "the configured engine is the only one considered")
+	assert.Equal(t, "/opt/chrome/chrome", tc.Browser)
+
+	tc = s.discover(&config.ToolchainConfig{LaTeXEngine: config.LaTeXEnginePdflatex, LaTeXPath: "/missing/pdflatex", BrowserPath: "/missing/chrome", PDFToPPMPath: "/missing/pdftoppm"})
+	assert.Nil(t, tc.LaTeX, "a missing override is not replaced by another program")
+	assert.Empty(t, tc.Browser)
+	assert.Len(t, tc.Missing(), 5)
+}
+
+func TestLaTeX_Args(t *testing.T) {
+	for engine, want := range map[string][]string{
+		config.LaTeXEnginePdflatex: {"-interaction=nonstopmode", "-output-directory", "out", "r.tex"},
+		config.LaTeXEngineLatexmk:  {"-pdf", "-interaction=nonstopmode", "-output-directory=out", "r.tex"},
+		config.LaTeXEngineTectonic: {"--outdir", "out", "--keep-logs", "r.tex"},
+	} {
+		l := &LaTeX{Engine: engine}
+		assert.Equal(t, want, l.Args("r.tex", "out"), engine)
+	}
+}
+
+func TestPackageInstalled_Unavailable(t *testing.T) {
+	_, err := (&Toolchain{}).PackageInstalled("geometry")
+	assert.True(t, errors.Is(err, ErrUnavailable))
+
+	_, err = (&LaTeX{Engine: config.LaTeXEngineTectonic, Path: "/usr/bin/tectonic"}).PackageInstalled("geometry")
+	assert.True(t, errors.Is(err, ErrUnavailable), "tectonic fetches packages on demand")
+
+	_, err = (&LaTeX{Engine: config.LaTeXEnginePdflatex, Path: t.TempDir() + "/pdflatex"}).PackageInstalled("geometry")
+	assert.True(t, errors.Is(err, ErrUnavailable), "no kpsewhich beside the compiler")
+}
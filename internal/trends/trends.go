@@ -0,0 +1,210 @@
+// Package trends follows how often skills and keywords appear in a company's job postings
+// over time, so users can see which skills a role is asking for more (or less) often.
+package trends
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Interval is the length of the periods postings are grouped into
+type Interval string
+
+// Intervals
+const (
+	IntervalMonth   Interval = "month"
+	IntervalQuarter Interval = "quarter"
+)
+
+// ParseInterval returns the interval named name, defaulting to quarters when name is empty
+func ParseInterval(name string) (Interval, error) {
+	switch Interval(strings.ToLower(strings.TrimSpace(name))) {
+	case "", IntervalQuarter:
+		return IntervalQuarter, nil
+	case IntervalMonth:
+		return IntervalMonth, nil
+	}
+	return "", fmt.Errorf("interval must be %q or %q", IntervalMonth, IntervalQuarter)
+}
+
+// Trend directions
+const (
+	TrendRising  = "rising"
+	TrendFalling = "falling"
+	TrendSteady  = "steady"
+)
+
+// trendThreshold is the change in share, between the first and last periods with
+// postings, at which a term counts as rising or falling
+const trendThreshold = 0.1
+
+// Posting is one version of a posting: the skills it required and its keywords, as of
+// when it was first seen
+type Posting struct {
+	SeenAt   time.Time
+	Skills   []string
+	Keywords []string
+}
+
+// Report counts skills and keywords across postings, period by period
+type Report struct {
+	Interval Interval `json:"interval"`
+	Timezone string   `json:"timezone"` // Time zone periods begin and end in
+	Periods  []Period `json:"periods"`  // Oldest first, with no gaps
+	Skills   []Term   `json:"skills"`
+	Keywords []Term   `json:"keywords"`
+}
+
+// Period is one interval of the report
+type Period struct {
+	Label    string    `json:"label"` // e.g. "2026-03" or "2026-Q1"
+	Start    time.Time `json:"start"` // In UTC
+	Postings int       `json:"postings"`
+}
+
+// Term is a skill or keyword's counts across a report's periods
+type Term struct {
+	Term   string    `json:"term"`
+	Total  int       `json:"total"`  // Postings mentioning the term
+	Counts []int     `json:"counts"` // Postings mentioning the term, per period
+	Shares []float64 `json:"shares"` // Counts as a share of the period's postings
+	Change float64   `json:"change"` // Share in the last period with postings minus the first
+	Trend  string    `json:"trend"`
+}
+
+// Analyze groups postings into periods and counts the postings mentioning each skill and
+// keyword, case-insensitively. Periods follow the calendar in loc, so a posting first seen
+// on the evening of March 31 in New York counts toward March there. Only the limit terms
+// mentioned most often are kept of each kind (all of them when limit is 0).
+func Analyze(postings []Posting, interval Interval, loc *time.Location, limit int) *Report {
+	report := &Report{Interval: interval, Timezone: loc.String(), Periods: []Period{}, Skills: []Term{}, Keywords: []Term{}}
+	if len(postings) == 0 {
+		return report
+	}
+
+	first, last := postings[0].SeenAt, postings[0].SeenAt
+	for _, p := range postings {
+		if p.SeenAt.Before(first) {
+			first = p.SeenAt
+		}
+		if p.SeenAt.After(last) {
+			last = p.SeenAt
+		}
+	}
+	index := map[time.Time]int{}
+	for start := periodStart(first, interval, loc); !start.After(last); start = nextPeriod(start, interval) {
+		index[start] = len(report.Periods)
+		report.Periods = append(report.Periods, Period{Label: periodLabel(start, interval), Start: start.UTC()})
+	}
+
+	skills, keywords := newCounter(len(report.Periods)), newCounter(len(report.Periods))
+	for _, p := range postings {
+		i := index[periodStart(p.SeenAt, interval, loc)]
+		report.Periods[i].Postings++
+		skills.add(i, p.Skills)
+		keywords.add(i, p.Keywords)
+	}
+	report.Skills = skills.terms(report.Periods, limit)
+	report.Keywords = keywords.terms(report.Periods, limit)
+	return report
+}
+
+// counter counts the postings mentioning each term per period
+type counter struct {
+	periods int
+	counts  map[string][]int
+	names   map[string]string // Spelling of each term as first seen
+}
+
+func newCounter(periods int) *counter {
+	return &counter{periods: periods, counts: map[string][]int{}, names: map[string]string{}}
+}
+
+// add counts one posting's terms in period i, once each
+func (c *counter) add(i int, terms []string) {
+	seen := map[string]bool{}
+	for _, term := range terms {
+		key := strings.ToLower(strings.TrimSpace(term))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if c.counts[key] == nil {
+			c.counts[key] = make([]int, c.periods)
+			c.names[key] = strings.TrimSpace(term)
+		}
+		c.counts[key][i]++
+	}
+}
+
+// terms returns the limit most mentioned terms with their shares and trends
+func (c *counter) terms(periods []Period, limit int) []Term {
+	firstPeriod, lastPeriod := -1, -1
+	for i, p := range periods {
+		if p.Postings > 0 {
+			if firstPeriod < 0 {
+				firstPeriod = i
+			}
+			lastPeriod = i
+		}
+	}
+
+	terms := make([]Term, 0, len(c.counts))
+	for key, counts := range c.counts {
+		t := Term{Term: c.names[key], Counts: counts, Shares: make([]float64, len(counts)), Trend: TrendSteady}
+		for i, n := range counts {
+			t.Total += n
+			if periods[i].Postings > 0 {
+				t.Shares[i] = float64(n) / float64(periods[i].Postings)
+			}
+		}
+		if lastPeriod > firstPeriod {
+			t.Change = t.Shares[lastPeriod] - t.Shares[firstPeriod]
+			switch {
+			case t.Change >= trendThreshold:
+				t.Trend = TrendRising
+			case t.Change <= -trendThreshold:
+				t.Trend = TrendFalling
+			}
+		}
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Total != terms[j].Total {
+			return terms[i].Total > terms[j].Total
+		}
+		return strings.ToLower(terms[i].Term) < strings.ToLower(terms[j].Term)
+	})
+	if limit > 0 && len(terms) > limit {
+		terms = terms[:limit]
+	}
+	return terms
+}
+
+// periodStart returns the start of the period containing t, in loc
+func periodStart(t time.Time, interval Interval, loc *time.Location) time.Time {
+	t = t.In(loc)
+	month := t.Month()
+	if interval == IntervalQuarter {
+		month -= (month - 1) % 3
+	}
+	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, loc)
+}
+
+// nextPeriod returns the start of the period after the one starting at start
+func nextPeriod(start time.Time, interval Interval) time.Time {
+	if interval == IntervalQuarter {
+		return start.AddDate(0, 3, 0)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// periodLabel names the period starting at start
+func periodLabel(start time.Time, interval Interval) string {
+	if interval == IntervalQuarter {
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	}
+	return start.Format("2006-01")
+}
@@ -0,0 +1,97 @@
+package trends
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestAnalyze(t *testing.T) {
+	postings := []Posting{
+		{SeenAt: day("2025-01-15"), Skills: []string{"Java", "SQL"}, Keywords: []string{"microservices"}},
+		{SeenAt: day("2025-02-20"), Skills: []string{"Java", "Go"}},
+		{SeenAt: day("2025-08-01"), Skills: []string{"Go", "go", "Kubernetes"}, Keywords: []string{"Microservices"}},
+		{SeenAt: day("2025-09-30"), Skills: []string{"Go", "SQL"}},
+	}
+	report := Analyze(postings, IntervalQuarter, time.UTC, 0)
+
+	require.Len(t, report.Periods, 3, "empty quarters are kept so the series has no gaps")
+	assert.Equal(t, []string{"2025-Q1", "2025-Q2", "2025-Q3"},
+		[]string{report.Periods[0].Label, report.Periods[1].Label, report.Periods[2].Label})
+	assert.Equal(t, []int{2, 0, 2}, []int{report.Periods[0].Postings, report.Periods[1].Postings, report.Periods[2].Postings})
+
+	require.Len(t, report.Skills, 4)
+	golang := report.Skills[0]
+	assert.Equal(t, "Go", golang.Term)
+	assert.Equal(t, 3, golang.Total, "a skill counts once per posting")
+	assert.Equal(t, []int{1, 0, 2}, golang.Counts)
+	assert.Equal(t, []float64{0.5, 0, 1}, golang.Shares)
+	assert.InDelta(t, 0.5, golang.Change, 1e-9, "change skips empty periods")
+	assert.Equal(t, TrendRising, golang.Trend)
+
+	assert.Equal(t, "Java", report.Skills[1].Term)
+	assert.Equal(t, TrendFalling, report.Skills[1].Trend)
+	assert.Equal(t, "SQL", report.Skills[2].Term)
+	assert.Equal(t, TrendSteady, report.Skills[2].Trend)
+
+	require.Len(t, report.Keywords, 1)
+	assert.Equal(t, "microservices", report.Keywords[0].Term)
+	assert.Equal(t, 2, report.Keywords[0].Total)
+}
+
+func TestAnalyze_Limit(t *testing.T) {
+	postings := []Posting{
+		{SeenAt: day("2025-03-01"), Skills: []string{"Go", "Rust", "Python"}},
+		{SeenAt: day("2025-03-20"), Skills: []string{"Rust"}},
+	}
+	report := Analyze(postings, IntervalMonth, time.UTC, 2)
+	require.Len(t, report.Periods, 1)
+	assert.Equal(t, "2025-03", report.Periods[0].Label)
+	require.Len(t, report.Skills, 2)
+	assert.Equal(t, "Rust", report.Skills[0].Term)
+	assert.Equal(t, "Go", report.Skills[1].Term, "ties sort by name")
+	assert.Equal(t, TrendSteady, report.Skills[0].Trend, "one period has no trend")
+}
+
+func TestAnalyze_Timezone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	postings := []Posting{
+		{SeenAt: time.Date(2025, 4, 1, 2, 0, 0, 0, time.UTC), Skills: []string{"Go"}}, // March 31 in New York
+		{SeenAt: time.Date(2025, 4, 2, 12, 0, 0, 0, time.UTC), Skills: []string{"Go"}},
+	}
+
+	report := Analyze(postings, IntervalMonth, newYork, 0)
+	assert.Equal(t, "America/New_York", report.Timezone)
+	require.Len(t, report.Periods, 2)
+	assert.Equal(t, "2025-03", report.Periods[0].Label)
+	assert.Equal(t, 1, report.Periods[0].Postings)
+	assert.Equal(t, time.Date(2025, 4, 1, 4, 0, 0, 0, time.UTC), report.Periods[1].Start, "starts are returned in UTC")
+
+	report = Analyze(postings, IntervalMonth, time.UTC, 0)
+	require.Len(t, report.Periods, 1)
+	assert.Equal(t, "2025-04", report.Periods[0].Label)
+}
+
+func TestAnalyze_NoPostings(t *testing.T) {
+	report := Analyze(nil, IntervalMonth, time.UTC, 10)
+	assert.Empty(t, report.Periods)
+	assert.NotNil(t, report.Skills)
+}
+
+func TestParseInterval(t *testing.T) {
+	for name, want := range map[string]Interval{"": IntervalQuarter, "Month": IntervalMonth, "quarter": IntervalQuarter} {
+		got, err := ParseInterval(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseInterval("week")
+	assert.Error(t, err)
+}
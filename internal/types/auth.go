@@ -29,14 +29,28 @@ type User struct {
 	Email       string    `json:"email"`
 	Phone       string    `json:"phone,omitempty"`
 	PasswordSet bool      `json:"password_set"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	// EmailVerified is false while the account's email verification is pending
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
 }
 
 // LoginResponse represents the login/register response with user data and authentication token.
 type LoginResponse struct {
-	User  *User  `json:"user"`
-	Token string `json:"token"`
+	User         *User  `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// RefreshTokenRequest carries a refresh token to rotate or revoke.
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// RefreshTokenResponse represents the response to a token refresh with the new token pair.
+type RefreshTokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
 }
 
 // UpdatePasswordRequest represents a password update request.
@@ -8,6 +8,8 @@ type Source struct {
 	URL       string `json:"url"`
 	Timestamp string `json:"timestamp"` // RFC3339 format
 	Hash      string `json:"hash"`      // SHA256 hex digest
+	// SourceType is "archive" when the content came from an Internet Archive snapshot
+	SourceType string `json:"source_type,omitempty"`
 }
 
 // CompanyCorpus represents the collected corpus with sources
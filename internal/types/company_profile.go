@@ -3,6 +3,8 @@
 //nolint:revive // types is a standard Go package name pattern
 package types
 
+import "strings"
+
 // CompanyProfile represents brand voice and style rules for a company
 type CompanyProfile struct {
 	Company       string   `json:"company"`
@@ -12,4 +14,20 @@ type CompanyProfile struct {
 	DomainContext string   `json:"domain_context"`
 	Values        []string `json:"values"`
 	EvidenceURLs  []string `json:"evidence_urls"`
+
+	// TabooReasons says why the company avoids a taboo phrase, by phrase, when known
+	TabooReasons map[string]string `json:"taboo_reasons,omitempty"`
+}
+
+// TabooReason returns why the company avoids phrase, matched ignoring case, or ""
+func (p *CompanyProfile) TabooReason(phrase string) string {
+	if reason, ok := p.TabooReasons[phrase]; ok {
+		return reason
+	}
+	for key, reason := range p.TabooReasons {
+		if strings.EqualFold(strings.TrimSpace(key), strings.TrimSpace(phrase)) {
+			return reason
+		}
+	}
+	return ""
 }
@@ -7,6 +7,15 @@ package types
 type ExperienceBank struct {
 	Stories   []Story     `json:"stories"`
 	Education []Education `json:"education,omitempty"`
+	// SkillProfile holds the user's self-assessed proficiency and recency of skills
+	SkillProfile []SkillAssessment `json:"skill_profile,omitempty"`
+}
+
+// SkillAssessment is a user's self-assessment of one skill
+type SkillAssessment struct {
+	Skill       string `json:"skill"`
+	Proficiency int    `json:"proficiency,omitempty"` // 1 (basic) to 5 (expert); 0 if not assessed
+	LastUsed    string `json:"last_used,omitempty"`   // YYYY-MM; empty derives it from the stories
 }
 
 // Story represents a single work experience story with stable ID
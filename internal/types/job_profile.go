@@ -11,15 +11,17 @@ type JobProfile struct {
 	HardRequirements      []Requirement          `json:"hard_requirements"`
 	NiceToHaves           []Requirement          `json:"nice_to_haves"`
 	Keywords              []string               `json:"keywords"`
+	UserKeywords          []string               `json:"user_keywords,omitempty"` // Keywords the user added; also in Keywords
 	EvalSignals           *EvalSignals           `json:"eval_signals"`
 	EducationRequirements *EducationRequirements `json:"education_requirements,omitempty"`
 }
 
 // Requirement represents a skill requirement with evidence
 type Requirement struct {
-	Skill    string `json:"skill"`
-	Level    string `json:"level,omitempty"`
-	Evidence string `json:"evidence"`
+	Skill    string   `json:"skill"`
+	Level    string   `json:"level,omitempty"`
+	Evidence string   `json:"evidence"`
+	Weight   *float64 `json:"weight,omitempty"` // User-set importance; replaces the default ranking weight
 }
 
 // EvalSignals represents inferred evaluation criteria signals
@@ -23,4 +23,14 @@ type RankedStory struct {
 	LLMScore *float64 `json:"llm_score,omitempty"`
 	// LLMReasoning is the LLM's explanation for the score
 	LLMReasoning string `json:"llm_reasoning,omitempty"`
+	// SemanticScore is the cosine similarity between the job's requirements and the
+	// story's closest bullet (nil unless ranked with embeddings)
+	SemanticScore *float64 `json:"semantic_score,omitempty"`
+	// TechStackMatches are the story's skills found in the company's tech stack
+	TechStackMatches []string `json:"tech_stack_matches,omitempty"`
+	// RustySkills are the story's skills the job needs in depth but the user has not used
+	// recently or rates as weak
+	RustySkills []string `json:"rusty_skills,omitempty"`
+	// RoleMatch is the story's normalized title when it is in the posting's role family
+	RoleMatch string `json:"role_match,omitempty"`
 }
@@ -87,3 +87,68 @@ func normalizeForMatching(text string) string {
 	// Convert to lowercase for case-insensitive matching
 	return strings.ToLower(text)
 }
+
+// CheckTabooPhrasesInBullets checks the text of each rewritten bullet for the company's
+// taboo phrases, reporting one violation per bullet and phrase with the reason the phrase
+// is avoided when the profile has one. Violations carry the bullet and story IDs, so the
+// repair loop can rewrite or drop the bullet.
+func CheckTabooPhrasesInBullets(bullets *types.RewrittenBullets, plan *types.ResumePlan, companyProfile *types.CompanyProfile) []types.Violation {
+	if bullets == nil || companyProfile == nil || len(companyProfile.TabooPhrases) == 0 {
+		return nil
+	}
+
+	storyIDByBulletID := make(map[string]string)
+	if plan != nil {
+		for _, story := range plan.SelectedStories {
+			for _, bulletID := range story.BulletIDs {
+				storyIDByBulletID[bulletID] = story.StoryID
+			}
+		}
+	}
+
+	var violations []types.Violation
+	for i := range bullets.Bullets {
+		bullet := &bullets.Bullets[i]
+		text := strings.ToLower(bullet.FinalText)
+		seen := make(map[string]bool)
+		for _, phrase := range companyProfile.TabooPhrases {
+			normalizedPhrase := strings.ToLower(strings.TrimSpace(phrase))
+			if normalizedPhrase == "" || seen[normalizedPhrase] || !strings.Contains(text, normalizedPhrase) {
+				continue
+			}
+			seen[normalizedPhrase] = true
+
+			details := fmt.Sprintf("Bullet contains taboo phrase %q", phrase)
+			if reason := companyProfile.TabooReason(phrase); reason != "" {
+				details += ": " + reason
+			}
+			violation := types.Violation{
+				Type:       "forbidden_phrase",
+				Severity:   "error",
+				Details:    details,
+				BulletID:   &bullet.OriginalBulletID,
+				BulletText: &bullet.FinalText,
+			}
+			if storyID, ok := storyIDByBulletID[bullet.OriginalBulletID]; ok {
+				violation.StoryID = &storyID
+			}
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// withoutBulletLines drops violations on lines that render a bullet, which bullet checks
+// report instead
+func withoutBulletLines(violations []types.Violation, lineToBulletMap map[int]string) []types.Violation {
+	kept := violations[:0]
+	for _, v := range violations {
+		if v.LineNumber != nil {
+			if _, isBullet := lineToBulletMap[*v.LineNumber]; isBullet {
+				continue
+			}
+		}
+		kept = append(kept, v)
+	}
+	return kept
+}
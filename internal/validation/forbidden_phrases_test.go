@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/jonathan/resume-customizer/internal/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -133,3 +134,47 @@ func TestNormalizeForMatching_Unescapes(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckTabooPhrasesInBullets(t *testing.T) {
+	bullets := &types.RewrittenBullets{
+		Bullets: []types.RewrittenBullet{
+			{OriginalBulletID: "bullet_001", FinalText: "Acted as a Rockstar engineer and ninja on payments"},
+			{OriginalBulletID: "bullet_002", FinalText: "Cut p99 latency by 40%"},
+		},
+	}
+	plan := &types.ResumePlan{
+		SelectedStories: []types.SelectedStory{{StoryID: "story_001", BulletIDs: []string{"bullet_001", "bullet_002"}}},
+	}
+	profile := &types.CompanyProfile{
+		TabooPhrases: []string{"rockstar", "ninja", "synergy"},
+		TabooReasons: map[string]string{"Rockstar": "exclusionary hiring jargon"},
+	}
+
+	violations := CheckTabooPhrasesInBullets(bullets, plan, profile)
+	require.Len(t, violations, 2)
+
+	assert.Equal(t, "forbidden_phrase", violations[0].Type)
+	assert.Equal(t, "error", violations[0].Severity)
+	assert.Equal(t, `Bullet contains taboo phrase "rockstar": exclusionary hiring jargon`, violations[0].Details)
+	require.NotNil(t, violations[0].BulletID)
+	assert.Equal(t, "bullet_001", *violations[0].BulletID)
+	require.NotNil(t, violations[0].StoryID)
+	assert.Equal(t, "story_001", *violations[0].StoryID)
+	assert.Nil(t, violations[0].LineNumber)
+
+	assert.Equal(t, `Bullet contains taboo phrase "ninja"`, violations[1].Details)
+
+	assert.Empty(t, CheckTabooPhrasesInBullets(bullets, plan, nil))
+}
+
+func TestWithoutBulletLines(t *testing.T) {
+	line := func(n int) *int { return &n }
+	violations := []types.Violation{
+		{Type: "forbidden_phrase", LineNumber: line(10)},
+		{Type: "forbidden_phrase", LineNumber: line(3)},
+	}
+
+	kept := withoutBulletLines(violations, map[int]string{10: "bullet_001"})
+	require.Len(t, kept, 1)
+	assert.Equal(t, 3, *kept[0].LineNumber)
+}
@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/jonathan/resume-customizer/internal/toolchain"
 )
 
 const (
@@ -16,13 +18,17 @@ const (
 	CompilationTimeout = 30 * time.Second
 )
 
-// CompileLaTeX compiles a LaTeX file using pdflatex
+// CompileLaTeX compiles a LaTeX file with the toolchain's LaTeX compiler. When no
+// compiler is installed the CompilationError wraps toolchain.ErrUnavailable.
 func CompileLaTeX(texPath string, workDir string) (pdfPath string, logOutput string, err error) {
-	// Check if pdflatex is available
-	if _, err := exec.LookPath("pdflatex"); err != nil {
+	tools, err := toolchain.Default()
+	if err != nil {
+		return "", "", &CompilationError{Message: "invalid toolchain configuration", Cause: err}
+	}
+	if tools.LaTeX == nil {
 		return "", "", &CompilationError{
-			Message: "pdflatex not found in PATH. Please install a LaTeX distribution (e.g., TeX Live, MiKTeX)",
-			Cause:   err,
+			Message: "no LaTeX compiler found. Please install a LaTeX distribution (e.g., TeX Live, MiKTeX, tectonic) or set LATEX_PATH",
+			Cause:   toolchain.ErrUnavailable,
 		}
 	}
 
@@ -70,10 +76,8 @@ func CompileLaTeX(texPath string, workDir string) (pdfPath string, logOutput str
 	ctx, cancel := context.WithTimeout(context.Background(), CompilationTimeout)
 	defer cancel()
 
-	// Run pdflatex
-	// Use -interaction=nonstopmode to prevent interactive prompts
-	// Use -output-directory to specify where to put output files
-	cmd := exec.CommandContext(ctx, "pdflatex", "-interaction=nonstopmode", "-output-directory", workDir, workTexPath)
+	// Run the compiler without interactive prompts, writing output files to workDir
+	cmd := exec.CommandContext(ctx, tools.LaTeX.Path, tools.LaTeX.Args(workTexPath, workDir)...)
 
 	// Capture both stdout and stderr
 	var stdout, stderr strings.Builder
@@ -0,0 +1,206 @@
+// Package validation provides functionality to validate LaTeX resumes against constraints.
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/types"
+)
+
+// LaTeX log issue kinds
+const (
+	LaTeXMissingPackage   = "missing_package"
+	LaTeXUndefinedCommand = "undefined_control_sequence"
+	LaTeXOverfullHbox     = "overfull_hbox"
+	LaTeXOtherError       = "error"
+)
+
+// LaTeXIssue is one problem found in a LaTeX compiler log
+type LaTeXIssue struct {
+	Kind    string
+	Line    int    // Line in the .tex file, 0 when the log does not say
+	Subject string // The missing package, undefined command, or overflow amount
+	Source  string // The template placeholder the line was rendered from, if known
+	Message string // What went wrong and what to do about it
+}
+
+// IsError reports whether the issue stops the PDF from being correct, as opposed to a
+// layout warning
+func (i LaTeXIssue) IsError() bool {
+	return i.Kind != LaTeXOverfullHbox
+}
+
+var (
+	// pdflatex and latexmk: "! LaTeX Error: File `foo.sty' not found." and friends
+	logErrorPattern = regexp.MustCompile(`^! (.+)$`)
+	// The "l.42 <text>" line after an error gives where it happened
+	logLinePattern = regexp.MustCompile(`^l\.(\d+) ?(.*)$`)
+	// tectonic: "error: resume.tex:42: Undefined control sequence"
+	tectonicPattern = regexp.MustCompile(`^(error|warning): [^:]*:(\d+): (.+)$`)
+
+	missingFilePattern = regexp.MustCompile("File `([^']+)\\.(sty|cls)' not found")
+	overfullPattern    = regexp.MustCompile(`Overfull \\hbox \(([\d.]+pt) too wide\) (?:in paragraph at lines (\d+)--\d+|detected at line (\d+))`)
+	lastCommandPattern = regexp.MustCompile(`\\[A-Za-z@]+`)
+)
+
+// ParseLaTeXLog extracts structured issues from compiler output: missing packages,
+// undefined control sequences, other errors, and overfull boxes with their lines. When
+// tex, the compiled source, is given, each issue is traced to the template placeholder
+// its line was rendered from.
+func ParseLaTeXLog(logOutput, tex string) []LaTeXIssue {
+	var issues []LaTeXIssue
+	lines := strings.Split(logOutput, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+
+		if m := tectonicPattern.FindStringSubmatch(line); m != nil {
+			if m[1] == "warning" && !strings.HasPrefix(m[3], `Overfull \hbox`) {
+				continue
+			}
+			n, _ := strconv.Atoi(m[2])
+			if issue, ok := classifyLaTeXMessage(m[3], n, ""); ok {
+				issues = append(issues, issue)
+			}
+			continue
+		}
+
+		if m := overfullPattern.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[2] + m[3])
+			issues = append(issues, classifyOverfull(m[1], n))
+			continue
+		}
+
+		m := logErrorPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		// The location follows the error within a few lines of help text
+		lineNum, context := 0, ""
+		for j := i + 1; j < len(lines) && j <= i+12; j++ {
+			if logErrorPattern.MatchString(lines[j]) && !strings.HasPrefix(lines[j], "! Emergency stop.") {
+				break
+			}
+			if lm := logLinePattern.FindStringSubmatch(strings.TrimRight(lines[j], "\r")); lm != nil {
+				lineNum, _ = strconv.Atoi(lm[1])
+				context = lm[2]
+				break
+			}
+		}
+		if issue, ok := classifyLaTeXMessage(m[1], lineNum, context); ok {
+			issues = append(issues, issue)
+		}
+	}
+
+	if tex != "" {
+		texLines := strings.Split(tex, "\n")
+		for i := range issues {
+			if n := issues[i].Line; n > 0 && n <= len(texLines) {
+				issues[i].Source = templateSource(texLines[n-1])
+			}
+		}
+	}
+	return issues
+}
+
+// classifyLaTeXMessage turns an error message into an issue. Errors that only report
+// that compilation gave up are skipped since an earlier issue explains them.
+func classifyLaTeXMessage(message string, line int, context string) (LaTeXIssue, bool) {
+	message = strings.TrimSpace(message)
+	switch {
+	case message == "Emergency stop." || strings.HasPrefix(message, "==> Fatal error"):
+		return LaTeXIssue{}, false
+
+	case missingFilePattern.MatchString(message):
+		m := missingFilePattern.FindStringSubmatch(message)
+		return LaTeXIssue{
+			Kind:    LaTeXMissingPackage,
+			Line:    line,
+			Subject: m[1],
+			Message: fmt.Sprintf("LaTeX package %s is not installed: install it (e.g. tlmgr install %s) or remove it from the template", m[1], m[1]),
+		}, true
+
+	case strings.HasPrefix(message, "Undefined control sequence"):
+		// TeX breaks the context line right after the offending command
+		command := ""
+		if all := lastCommandPattern.FindAllString(context, -1); len(all) > 0 {
+			command = all[len(all)-1]
+		}
+		what := "an undefined command"
+		if command != "" {
+			what = "undefined command " + command
+		}
+		return LaTeXIssue{
+			Kind:    LaTeXUndefinedCommand,
+			Line:    line,
+			Subject: command,
+			Message: fmt.Sprintf("Line uses %s: remove it from the text, or load the package that defines it in the template", what),
+		}, true
+
+	case strings.HasPrefix(message, "Overfull \\hbox"):
+		if m := overfullPattern.FindStringSubmatch(message); m != nil {
+			return classifyOverfull(m[1], line), true
+		}
+		return classifyOverfull("", line), true
+	}
+
+	return LaTeXIssue{
+		Kind:    LaTeXOtherError,
+		Line:    line,
+		Message: "LaTeX error: " + strings.TrimPrefix(message, "LaTeX Error: "),
+	}, true
+}
+
+func classifyOverfull(amount string, line int) LaTeXIssue {
+	message := "Line is too wide for the page: shorten it"
+	if amount != "" {
+		message = fmt.Sprintf("Line is %s too wide for the page: shorten it", amount)
+	}
+	return LaTeXIssue{Kind: LaTeXOverfullHbox, Line: line, Subject: amount, Message: message}
+}
+
+// Violation converts the issue into a validation violation. Errors are latex_error
+// violations; overfull boxes are line_too_long warnings.
+func (i LaTeXIssue) Violation() types.Violation {
+	v := types.Violation{Type: "latex_error", Severity: "error", Details: i.Message}
+	if !i.IsError() {
+		v.Type, v.Severity = "line_too_long", "warning"
+	}
+	if i.Source != "" {
+		v.Details += " (in the " + i.Source + ")"
+	}
+	if i.Line > 0 {
+		line := i.Line
+		v.LineNumber = &line
+	}
+	return v
+}
+
+// templateSource names the placeholder of the bundled resume template that renders a
+// line like texLine, recognized by the markup around it
+func templateSource(texLine string) string {
+	line := strings.TrimSpace(texLine)
+	switch {
+	case strings.HasPrefix(line, `\usepackage`), strings.HasPrefix(line, `\documentclass`):
+		return "template preamble"
+	case strings.HasPrefix(line, `\item`):
+		return "bullet ({{ . }})"
+	case strings.Contains(line, `\huge`):
+		return "candidate name ({{ .Name }})"
+	case strings.HasPrefix(line, `\texttt`), strings.Contains(line, " | "):
+		return "contact line ({{ .Email }}, {{ .Phone }})"
+	case strings.HasPrefix(line, `{\large\textbf{`) && strings.Contains(line, `\hfill`):
+		return "school and dates ({{ .School }}, {{ .DateRange }})"
+	case strings.HasPrefix(line, `{\large\textbf{`):
+		return "company name ({{ .Company }})"
+	case strings.HasPrefix(line, `\textit{`) && strings.Contains(line, `\hfill`):
+		return "role and dates ({{ .Role }}, {{ .DateRanges }})"
+	case strings.HasPrefix(line, `\textit{`):
+		return "degree ({{ .Degree }}, {{ .Field }})"
+	case strings.HasPrefix(line, `\section`):
+		return "section heading"
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleTeX = `\documentclass[11pt]{article}
+\usepackage{fontawesome5}
+\begin{document}
+{\huge\textbf{Jane Doe}}
+\section*{Experience}
+{\large\textbf{Acme Corp}}
+\textit{Senior Engineer} \hfill 2020 -- Present
+\begin{itemize}
+\item Built \badcmd{a thing} for customers
+\item Led a very long initiative that keeps going well past the right margin of the page
+\end{itemize}
+\end{document}`
+
+func TestParseLaTeXLog_MissingPackage(t *testing.T) {
+	log := "(/usr/share/texlive/texmf-dist/tex/latex/base/size11.clo))\n" +
+		"\n" +
+		"! LaTeX Error: File `fontawesome5.sty' not found.\n" +
+		"\n" +
+		"Type X to quit or <RETURN> to proceed,\n" +
+		"or enter new name. (Default extension: sty)\n" +
+		"\n" +
+		"Enter file name: \n" +
+		"! Emergency stop.\n" +
+		"<read *> \n" +
+		"         \n" +
+		"l.2 \\usepackage\n" +
+		"               {fontawesome5}^^M\n"
+
+	issues := ParseLaTeXLog(log, sampleTeX)
+	require.Len(t, issues, 1, "the emergency stop is not reported separately")
+	assert.Equal(t, LaTeXMissingPackage, issues[0].Kind)
+	assert.Equal(t, "fontawesome5", issues[0].Subject)
+	assert.Equal(t, 2, issues[0].Line)
+	assert.Equal(t, "template preamble", issues[0].Source)
+	assert.Contains(t, issues[0].Message, "tlmgr install fontawesome5")
+	assert.True(t, issues[0].IsError())
+}
+
+func TestParseLaTeXLog_UndefinedControlSequence(t *testing.T) {
+	log := "! Undefined control sequence.\n" +
+		"l.9 \\item Built \\badcmd\n" +
+		"                        {a thing} for customers\n"
+
+	issues := ParseLaTeXLog(log, sampleTeX)
+	require.Len(t, issues, 1)
+	assert.Equal(t, LaTeXUndefinedCommand, issues[0].Kind)
+	assert.Equal(t, `\badcmd`, issues[0].Subject)
+	assert.Equal(t, 9, issues[0].Line)
+	assert.Equal(t, "bullet ({{ . }})", issues[0].Source)
+
+	v := issues[0].Violation()
+	assert.Equal(t, "latex_error", v.Type)
+	assert.Equal(t, "error", v.Severity)
+	require.NotNil(t, v.LineNumber)
+	assert.Equal(t, 9, *v.LineNumber)
+	assert.Contains(t, v.Details, `undefined command \badcmd`)
+	assert.Contains(t, v.Details, "(in the bullet ({{ . }}))")
+}
+
+func TestParseLaTeXLog_OverfullHbox(t *testing.T) {
+	log := "Overfull \\hbox (12.34567pt too wide) in paragraph at lines 10--10\n" +
+		"[]\\OT1/cmr/m/n/10.95 Led a very long initiative\n" +
+		"Overfull \\hbox (3.0pt too wide) detected at line 7\n"
+
+	issues := ParseLaTeXLog(log, sampleTeX)
+	require.Len(t, issues, 2)
+	assert.Equal(t, LaTeXOverfullHbox, issues[0].Kind)
+	assert.Equal(t, "12.34567pt", issues[0].Subject)
+	assert.Equal(t, 10, issues[0].Line)
+	assert.False(t, issues[0].IsError())
+	assert.Equal(t, 7, issues[1].Line)
+	assert.Equal(t, "role and dates ({{ .Role }}, {{ .DateRanges }})", issues[1].Source)
+
+	v := issues[0].Violation()
+	assert.Equal(t, "line_too_long", v.Type)
+	assert.Equal(t, "warning", v.Severity)
+}
+
+func TestParseLaTeXLog_Tectonic(t *testing.T) {
+	log := "note: Running TeX ...\n" +
+		"warning: resume.tex:4: Underfull \\hbox (badness 10000) in paragraph at lines 4--4\n" +
+		"warning: resume.tex:10: Overfull \\hbox (5.2pt too wide) in paragraph at lines 10--10\n" +
+		"error: resume.tex:9: Undefined control sequence\n" +
+		"error: halted on potentially-recoverable error as specified\n"
+
+	issues := ParseLaTeXLog(log, sampleTeX)
+	require.Len(t, issues, 2, "underfull boxes and the halt notice are skipped")
+	assert.Equal(t, LaTeXOverfullHbox, issues[0].Kind)
+	assert.Equal(t, 10, issues[0].Line)
+	assert.Equal(t, LaTeXUndefinedCommand, issues[1].Kind)
+	assert.Equal(t, 9, issues[1].Line)
+}
+
+func TestParseLaTeXLog_OtherErrorsAndEmptyLog(t *testing.T) {
+	assert.Empty(t, ParseLaTeXLog("", ""))
+
+	issues := ParseLaTeXLog("! Missing $ inserted.\n<inserted text>\n                $\nl.42 Revenue grew 50%_\n", "")
+	require.Len(t, issues, 1)
+	assert.Equal(t, LaTeXOtherError, issues[0].Kind)
+	assert.Equal(t, 42, issues[0].Line)
+	assert.Equal(t, "LaTeX error: Missing $ inserted.", issues[0].Message)
+	assert.Empty(t, issues[0].Source, "line past the end of the source is not traced")
+}
+
+func TestTemplateSource(t *testing.T) {
+	assert.Equal(t, "candidate name ({{ .Name }})", templateSource(`{\huge\textbf{Jane Doe}}`))
+	assert.Equal(t, "company name ({{ .Company }})", templateSource(`{\large\textbf{Acme Corp}}`))
+	assert.Equal(t, "section heading", templateSource(`\section*{Education}`))
+	assert.Equal(t, "", templateSource(`\begin{itemize}`))
+}
@@ -4,32 +4,48 @@ package validation
 import (
 	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/jonathan/resume-customizer/internal/toolchain"
 )
 
 // CountPDFPages counts the number of pages in a PDF file
 // It tries pdfinfo first, then falls back to ghostscript
 func CountPDFPages(pdfPath string) (int, error) {
+	tools, err := toolchain.Default()
+	if err != nil {
+		return 0, &Error{Message: "failed to count PDF pages", Cause: err}
+	}
+
 	// Try pdfinfo first (from poppler-utils)
-	if count, err := countPagesWithPdfinfo(pdfPath); err == nil {
-		return count, nil
+	if tools.PDFInfo != "" {
+		if count, err := countPagesWithPdfinfo(tools.PDFInfo, pdfPath); err == nil {
+			return count, nil
+		}
 	}
 
 	// Fallback to ghostscript
-	if count, err := countPagesWithGhostscript(pdfPath); err == nil {
-		return count, nil
+	if tools.Ghostscript != "" {
+		if count, err := countPagesWithGhostscript(tools.Ghostscript, pdfPath); err == nil {
+			return count, nil
+		}
 	}
 
 	// If both methods fail, return error
-	return 0, &Error{
-		Message: "failed to count PDF pages: neither pdfinfo nor ghostscript available. Please install poppler-utils (pdfinfo) or ghostscript",
+	if tools.PDFInfo == "" && tools.Ghostscript == "" {
+		return 0, &Error{
+			Message: "failed to count PDF pages: neither pdfinfo nor ghostscript available. Please install poppler-utils (pdfinfo) or ghostscript",
+			Cause:   toolchain.ErrUnavailable,
+		}
 	}
+	return 0, &Error{Message: "failed to count PDF pages: pdfinfo and ghostscript could not read the PDF"}
 }
 
 // countPagesWithPdfinfo uses pdfinfo to count PDF pages
-func countPagesWithPdfinfo(pdfPath string) (int, error) {
-	cmd := exec.Command("pdfinfo", pdfPath)
+func countPagesWithPdfinfo(pdfinfo, pdfPath string) (int, error) {
+	cmd := exec.Command(pdfinfo, pdfPath)
 	output, err := cmd.Output()
 	if err != nil {
 		return 0, fmt.Errorf("pdfinfo command failed: %w", err)
@@ -53,11 +69,12 @@ func countPagesWithPdfinfo(pdfPath string) (int, error) {
 }
 
 // countPagesWithGhostscript uses ghostscript to count PDF pages
-func countPagesWithGhostscript(pdfPath string) (int, error) {
+func countPagesWithGhostscript(gs, pdfPath string) (int, error) {
 	// Use ghostscript to count pages
 	// Command: gs -q -dNODISPLAY -c "(filename.pdf) (r) file runpdfbegin pdfpagecount = quit"
-	script := fmt.Sprintf("(%s) (r) file runpdfbegin pdfpagecount = quit", pdfPath)
-	cmd := exec.Command("gs", "-q", "-dNODISPLAY", "-c", script)
+	// PostScript strings treat backslashes as escapes, so Windows paths use forward slashes
+	script := fmt.Sprintf("(%s) (r) file runpdfbegin pdfpagecount = quit", filepath.ToSlash(pdfPath))
+	cmd := exec.Command(gs, "-q", "-dNODISPLAY", "-c", script)
 	output, err := cmd.Output()
 	if err != nil {
 		return 0, fmt.Errorf("ghostscript command failed: %w", err)
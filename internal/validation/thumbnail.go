@@ -0,0 +1,117 @@
+// Package validation provides functionality to validate LaTeX resumes against constraints.
+package validation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jonathan/resume-customizer/internal/toolchain"
+)
+
+// ThumbnailWidth is the width in pixels of resume thumbnails, enough for list views and
+// share cards without making the text legible
+const ThumbnailWidth = 400
+
+// pngSignature starts every PNG file
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// ThumbnailFromPDF renders the first page of a compiled PDF as a PNG width pixels
+// wide. Errors wrap toolchain.ErrUnavailable when both renderers are missing.
+func ThumbnailFromPDF(pdf []byte, width int) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "resume-thumbnail-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	pdfPath := filepath.Join(tmpDir, "resume.pdf")
+	if err := os.WriteFile(pdfPath, pdf, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write temp PDF file: %w", err)
+	}
+	return RenderThumbnail(pdfPath, width)
+}
+
+// RenderThumbnail renders the first page of a PDF as a PNG width pixels wide. It tries
+// pdftoppm first, then falls back to Ghostscript.
+func RenderThumbnail(pdfPath string, width int) ([]byte, error) {
+	tools, err := toolchain.Default()
+	if err != nil {
+		return nil, &Error{Message: "failed to render thumbnail", Cause: err}
+	}
+	if tools.PDFToPPM == "" && tools.Ghostscript == "" {
+		return nil, &Error{
+			Message: "failed to render thumbnail: neither pdftoppm nor ghostscript available. Please install poppler-utils (pdftoppm) or ghostscript",
+			Cause:   toolchain.ErrUnavailable,
+		}
+	}
+
+	outDir, err := os.MkdirTemp("", "resume-thumbnail-*")
+	if err != nil {
+		return nil, &Error{Message: "failed to create thumbnail directory", Cause: err}
+	}
+	defer func() { _ = os.RemoveAll(outDir) }()
+
+	var lastErr error
+	if tools.PDFToPPM != "" {
+		png, err := thumbnailWithPdftoppm(tools.PDFToPPM, pdfPath, outDir, width)
+		if err == nil {
+			return png, nil
+		}
+		lastErr = err
+	}
+	if tools.Ghostscript != "" {
+		png, err := thumbnailWithGhostscript(tools.Ghostscript, pdfPath, outDir, width)
+		if err == nil {
+			return png, nil
+		}
+		lastErr = err
+	}
+	return nil, &Error{Message: "failed to render thumbnail", Cause: lastErr}
+}
+
+// thumbnailWithPdftoppm scales page one to width pixels, keeping its aspect ratio
+func thumbnailWithPdftoppm(pdftoppm, pdfPath, outDir string, width int) ([]byte, error) {
+	prefix := filepath.Join(outDir, "pdftoppm")
+	ctx, cancel := context.WithTimeout(context.Background(), CompilationTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, pdftoppm, "-png", "-f", "1", "-l", "1", "-singlefile",
+		"-scale-to-x", strconv.Itoa(width), "-scale-to-y", "-1", pdfPath, prefix)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("pdftoppm command failed: %w: %s", err, bytes.TrimSpace(output))
+	}
+	return readPNG(prefix + ".png")
+}
+
+// thumbnailWithGhostscript renders page one at the resolution that makes a letter-width
+// page width pixels wide; A4 pages come out a few pixels narrower
+func thumbnailWithGhostscript(gs, pdfPath, outDir string, width int) ([]byte, error) {
+	out := filepath.Join(outDir, "gs.png")
+	dpi := max(float64(width)/8.5, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), CompilationTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, gs, "-q", "-dSAFER", "-dBATCH", "-dNOPAUSE",
+		"-sDEVICE=png16m", "-dFirstPage=1", "-dLastPage=1",
+		"-dTextAlphaBits=4", "-dGraphicsAlphaBits=4",
+		fmt.Sprintf("-r%.2f", dpi),
+		"-sOutputFile="+filepath.ToSlash(out), filepath.ToSlash(pdfPath))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("ghostscript command failed: %w: %s", err, bytes.TrimSpace(output))
+	}
+	return readPNG(out)
+}
+
+func readPNG(path string) ([]byte, error) {
+	png, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read thumbnail: %w", err)
+	}
+	if !bytes.HasPrefix(png, pngSignature) {
+		return nil, fmt.Errorf("thumbnail is not a PNG: %s", path)
+	}
+	return png, nil
+}
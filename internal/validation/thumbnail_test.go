@@ -0,0 +1,27 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPNG(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.png")
+	require.NoError(t, os.WriteFile(good, append(append([]byte{}, pngSignature...), "data"...), 0644))
+	png, err := readPNG(good)
+	require.NoError(t, err)
+	assert.Equal(t, pngSignature, png[:len(pngSignature)])
+
+	bad := filepath.Join(dir, "bad.png")
+	require.NoError(t, os.WriteFile(bad, []byte("GIF89a"), 0644))
+	_, err = readPNG(bad)
+	assert.Error(t, err)
+
+	_, err = readPNG(filepath.Join(dir, "missing.png"))
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/jonathan/resume-customizer/internal/toolchain"
 	"github.com/jonathan/resume-customizer/internal/types"
 )
 
@@ -50,12 +51,18 @@ func ValidateConstraints(texPath string, companyProfile *types.CompanyProfile, m
 	}
 	allViolations = append(allViolations, lineViolations...)
 
-	// 2. Check forbidden phrases (if company profile provided)
+	// 2. Check forbidden phrases (if company profile provided). With the bullets at hand,
+	// their text is checked directly and reported with each phrase's reason instead of
+	// by the lines they render to.
 	if companyProfile != nil && len(companyProfile.TabooPhrases) > 0 {
 		phraseViolations, err := CheckForbiddenPhrases(texPath, companyProfile.TabooPhrases)
 		if err != nil {
 			return nil, fmt.Errorf("failed to check forbidden phrases: %w", err)
 		}
+		if opts != nil && opts.Bullets != nil {
+			phraseViolations = withoutBulletLines(phraseViolations, opts.LineToBulletMap)
+			phraseViolations = append(phraseViolations, CheckTabooPhrasesInBullets(opts.Bullets, opts.Plan, companyProfile)...)
+		}
 		allViolations = append(allViolations, phraseViolations...)
 	}
 
@@ -67,12 +74,32 @@ func ValidateConstraints(texPath string, companyProfile *types.CompanyProfile, m
 	if err != nil {
 		var compErr *CompilationError
 		if errors.As(err, &compErr) {
-			// Add compilation error as a violation
-			allViolations = append(allViolations, types.Violation{
-				Type:     "latex_error",
-				Severity: "error",
-				Details:  fmt.Sprintf("LaTeX compilation failed: %s", compErr.Message),
-			})
+			if errors.Is(compErr, toolchain.ErrUnavailable) {
+				// Without a compiler the page count is skipped with a warning instead of
+				// reporting the resume itself as broken
+				allViolations = append(allViolations, types.Violation{
+					Type:     "page_overflow",
+					Severity: "warning",
+					Details:  fmt.Sprintf("Page count not checked: %s", compErr.Message),
+				})
+				return &types.Violations{Violations: allViolations}, nil
+			}
+			// Report what the log says went wrong, falling back to the compiler's outcome
+			var tex string
+			if content, err := os.ReadFile(texPath); err == nil {
+				tex = string(content)
+			}
+			issues := ParseLaTeXLog(compErr.LogOutput, tex)
+			for _, issue := range issues {
+				allViolations = append(allViolations, issue.Violation())
+			}
+			if len(issues) == 0 {
+				allViolations = append(allViolations, types.Violation{
+					Type:     "latex_error",
+					Severity: "error",
+					Details:  fmt.Sprintf("LaTeX compilation failed: %s", compErr.Message),
+				})
+			}
 			// If compilation failed, we can't check page count, so return violations so far
 			return &types.Violations{Violations: allViolations}, nil
 		}
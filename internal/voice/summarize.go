@@ -24,7 +24,7 @@ type SummarizeOptions struct {
 
 // SummarizeVoice extracts brand voice and style rules from company corpus text
 func SummarizeVoice(ctx context.Context, corpusText string, sources []types.Source, apiKey string) (*types.CompanyProfile, error) {
-	if apiKey == "" {
+	if apiKey == "" && llm.APIKeyRequired() {
 		return nil, &APICallError{Message: "API key is required"}
 	}
 
@@ -85,15 +85,7 @@ func SummarizeVoiceWithCache(ctx context.Context, opts SummarizeOptions, corpusT
 
 		cached, err := opts.Database.GetFreshCompanyProfile(ctx, *opts.CompanyID, maxAge)
 		if err == nil && cached != nil {
-			// Convert db.CompanyProfile to types.CompanyProfile
-			return &types.CompanyProfile{
-				Tone:          cached.Tone,
-				DomainContext: derefStr(cached.DomainContext),
-				StyleRules:    cached.StyleRules,
-				TabooPhrases:  cached.TabooPhrases,
-				Values:        cached.Values,
-				EvidenceURLs:  cached.EvidenceURLs,
-			}, nil
+			return FromStoredProfile(cached), nil
 		}
 	}
 
@@ -105,33 +97,62 @@ func SummarizeVoiceWithCache(ctx context.Context, opts SummarizeOptions, corpusT
 
 	// Store in database if connected
 	if opts.Database != nil && opts.CompanyID != nil {
-		input := &db.ProfileCreateInput{
-			CompanyID:     *opts.CompanyID,
-			Tone:          profile.Tone,
-			DomainContext: profile.DomainContext,
-			SourceCorpus:  corpusText,
-			StyleRules:    profile.StyleRules,
-			Values:        profile.Values,
-		}
+		_ = StoreProfile(ctx, opts.Database, *opts.CompanyID, corpusText, sources, profile)
+	}
 
-		// Convert taboo phrases
-		for _, phrase := range profile.TabooPhrases {
-			input.TabooPhrases = append(input.TabooPhrases, db.TabooPhraseInput{
-				Phrase: phrase,
-			})
-		}
+	return profile, nil
+}
 
-		// Convert evidence URLs
-		for _, url := range profile.EvidenceURLs {
-			input.EvidenceURLs = append(input.EvidenceURLs, db.ProfileSourceInput{
-				URL: url,
-			})
-		}
+// FromStoredProfile converts a stored company profile to the pipeline's profile type
+func FromStoredProfile(cached *db.CompanyProfile) *types.CompanyProfile {
+	return &types.CompanyProfile{
+		Tone:          cached.Tone,
+		DomainContext: derefStr(cached.DomainContext),
+		StyleRules:    cached.StyleRules,
+		TabooPhrases:  cached.TabooPhrases,
+		TabooReasons:  cached.TabooReasons,
+		Values:        cached.Values,
+		EvidenceURLs:  cached.EvidenceURLs,
+	}
+}
 
-		_, _ = opts.Database.CreateCompanyProfile(ctx, input)
+// StoreProfile saves a summarized profile and the corpus it came from as the company's
+// shared profile, replacing any earlier one
+func StoreProfile(ctx context.Context, database *db.DB, companyID uuid.UUID, corpusText string, sources []types.Source, profile *types.CompanyProfile) error {
+	input := &db.ProfileCreateInput{
+		CompanyID:     companyID,
+		Tone:          profile.Tone,
+		DomainContext: profile.DomainContext,
+		SourceCorpus:  corpusText,
+		StyleRules:    profile.StyleRules,
+		Values:        profile.Values,
+	}
+
+	// Convert taboo phrases
+	for _, phrase := range profile.TabooPhrases {
+		input.TabooPhrases = append(input.TabooPhrases, db.TabooPhraseInput{
+			Phrase: phrase,
+			Reason: profile.TabooReason(phrase),
+		})
+	}
+
+	// Convert evidence URLs, marking those read from archived snapshots
+	archived := make(map[string]bool)
+	for _, source := range sources {
+		if source.SourceType == db.PageSourceArchive {
+			archived[source.URL] = true
+		}
+	}
+	for _, url := range profile.EvidenceURLs {
+		evidence := db.ProfileSourceInput{URL: url}
+		if archived[url] {
+			evidence.SourceType = db.SourceTypeArchive
+		}
+		input.EvidenceURLs = append(input.EvidenceURLs, evidence)
 	}
 
-	return profile, nil
+	_, err := database.CreateCompanyProfile(ctx, input)
+	return err
 }
 
 // derefStr returns the value of a string pointer, or empty string if nil
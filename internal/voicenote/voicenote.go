@@ -0,0 +1,98 @@
+// Package voicenote transcribes recorded voice notes through an OpenAI-compatible audio
+// transcription API, so users can talk through an accomplishment instead of typing it.
+package voicenote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AudioExtensions are the upload formats accepted by OpenAI-compatible transcription
+// servers
+var AudioExtensions = []string{".flac", ".m4a", ".mp3", ".mp4", ".mpeg", ".mpga", ".oga", ".ogg", ".wav", ".webm"}
+
+// ErrEmptyTranscript is returned when the recording contains no recognizable speech
+var ErrEmptyTranscript = errors.New("no speech was recognized in the recording")
+
+// SupportedAudio reports whether filename has an accepted audio extension
+func SupportedAudio(filename string) bool {
+	return slices.Contains(AudioExtensions, strings.ToLower(filepath.Ext(filename)))
+}
+
+// Client calls a transcription API
+type Client struct {
+	url    string
+	apiKey string
+	model  string
+	http   *http.Client
+}
+
+// NewClient returns a client for the transcription endpoint at url, authenticating with
+// apiKey when it is set
+func NewClient(url, apiKey, model string) *Client {
+	return &Client{
+		url:    url,
+		apiKey: apiKey,
+		model:  model,
+		http:   &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// Transcribe uploads the recording named filename and returns its transcript
+func (c *Client) Transcribe(ctx context.Context, filename string, audio io.Reader) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filepath.Base(filename))
+	if err != nil {
+		return "", fmt.Errorf("failed to build transcription request: %w", err)
+	}
+	if _, err := io.Copy(part, audio); err != nil {
+		return "", fmt.Errorf("failed to read recording: %w", err)
+	}
+	_ = form.WriteField("model", c.model)
+	_ = form.WriteField("response_format", "json")
+	if err := form.Close(); err != nil {
+		return "", fmt.Errorf("failed to build transcription request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create transcription request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("transcription request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("transcription service returned %s: %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	var out struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode transcription response: %w", err)
+	}
+	text := strings.TrimSpace(out.Text)
+	if text == "" {
+		return "", ErrEmptyTranscript
+	}
+	return text, nil
+}
@@ -0,0 +1,53 @@
+package voicenote
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportedAudio(t *testing.T) {
+	assert.True(t, SupportedAudio("note.M4A"))
+	assert.True(t, SupportedAudio("/tmp/recording.webm"))
+	assert.False(t, SupportedAudio("resume.pdf"))
+	assert.False(t, SupportedAudio("noextension"))
+}
+
+func TestClient_Transcribe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "whisper-1", r.FormValue("model"))
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		audio, _ := io.ReadAll(file)
+		assert.Equal(t, "note.m4a", header.Filename)
+		switch string(audio) {
+		case "silence":
+			_, _ = w.Write([]byte(`{"text":"  "}`))
+		case "broken":
+			http.Error(w, "unsupported format", http.StatusBadRequest)
+		default:
+			_, _ = w.Write([]byte(`{"text":" I sped up checkout. "}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "secret", "whisper-1")
+	text, err := c.Transcribe(context.Background(), "uploads/note.m4a", strings.NewReader("speech"))
+	require.NoError(t, err)
+	assert.Equal(t, "I sped up checkout.", text)
+
+	_, err = c.Transcribe(context.Background(), "note.m4a", strings.NewReader("silence"))
+	assert.True(t, errors.Is(err, ErrEmptyTranscript))
+
+	_, err = c.Transcribe(context.Background(), "note.m4a", strings.NewReader("broken"))
+	assert.ErrorContains(t, err, "unsupported format")
+}
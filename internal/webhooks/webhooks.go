@@ -0,0 +1,202 @@
+// Package webhooks delivers signed run lifecycle events to the endpoints users register.
+//
+// Events come from the run event bus. Every replica observes every event and enqueues
+// it, and the event's key keeps it to one delivery per webhook. Delivery workers on any
+// replica claim due deliveries, POST them, and retry failures with growing delays until
+// MaxAttempts; each attempt is kept in the delivery log.
+//
+// Each request carries the event in X-Webhook-Event, the delivery's ID in
+// X-Webhook-Delivery, and X-Webhook-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256 of
+// "<t>.<body>" keyed with the webhook's secret>. Retries of a delivery reuse its ID.
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// Request headers
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	// MaxAttempts is how many times a delivery is attempted before it is marked failed
+	MaxAttempts = 6
+	// baseRetryDelay is the wait after the first failed attempt; each later wait is four
+	// times the one before
+	baseRetryDelay = 30 * time.Second
+	// claimLease is how long a claimed delivery is held; it covers a batch of requests
+	// at the notifier's timeout
+	claimLease = 5 * time.Minute
+)
+
+// Store is the subset of db.DB webhooks work through
+type Store interface {
+	GetRun(ctx context.Context, runID uuid.UUID) (*db.Run, error)
+	EnqueueWebhookDeliveries(ctx context.Context, userID uuid.UUID, runID *uuid.UUID, event, eventKey string, payload any) (int64, error)
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]db.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, id uuid.UUID, attempt *db.WebhookAttempt) error
+}
+
+// Poster sends webhook requests; *notifications.Notifier implements it
+type Poster interface {
+	PostWebhook(ctx context.Context, url string, payload []byte, header http.Header) (int, error)
+}
+
+// Payload is the JSON body of a webhook request
+type Payload struct {
+	Event     string    `json:"event"`
+	RunID     uuid.UUID `json:"run_id"`
+	Company   string    `json:"company,omitempty"`
+	RoleTitle string    `json:"role_title,omitempty"`
+	Step      string    `json:"step,omitempty"`   // step.completed only
+	Status    string    `json:"status,omitempty"` // The run's status, or the step's
+	Error     *string   `json:"error,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// EventName returns the webhook event a run event is, if any
+func EventName(event db.RunEvent) (string, bool) {
+	switch event.Type {
+	case db.RunEventRunCreated:
+		return db.WebhookRunCreated, true
+	case db.RunEventStepStatus:
+		if event.Status == db.StepStatusCompleted {
+			return db.WebhookStepCompleted, true
+		}
+	case db.RunEventRunCompleted:
+		switch event.Status {
+		case "completed":
+			return db.WebhookRunCompleted, true
+		case "failed":
+			return db.WebhookRunFailed, true
+		}
+	}
+	return "", false
+}
+
+// eventKey identifies a run event among every replica's copies of it
+func eventKey(name string, event db.RunEvent) string {
+	return fmt.Sprintf("%s:%s:%s:%s", name, event.RunID, event.Step, event.At.UTC().Format(time.RFC3339Nano))
+}
+
+// Enqueue queues the webhook event for a run event, if it is one, for the run owner's
+// webhooks subscribed to it. Returns the number of deliveries queued.
+func Enqueue(ctx context.Context, store Store, event db.RunEvent) (int64, error) {
+	name, ok := EventName(event)
+	if !ok {
+		return 0, nil
+	}
+	run, err := store.GetRun(ctx, event.RunID)
+	if err != nil {
+		return 0, err
+	}
+	if run == nil || run.UserID == nil {
+		return 0, nil
+	}
+	payload := Payload{
+		Event:     name,
+		RunID:     run.ID,
+		Company:   run.Company,
+		RoleTitle: run.RoleTitle,
+		Step:      event.Step,
+		Status:    event.Status,
+		Error:     event.Error,
+		At:        event.At,
+	}
+	if payload.Status == "" {
+		payload.Status = run.Status
+	}
+	return store.EnqueueWebhookDeliveries(ctx, *run.UserID, &run.ID, name, eventKey(name, event), payload)
+}
+
+// Deliver attempts up to limit due deliveries and records each outcome, retrying failed
+// ones later. Returns the number attempted.
+func Deliver(ctx context.Context, store Store, poster Poster, limit int) (int, error) {
+	deliveries, err := store.ClaimWebhookDeliveries(ctx, limit, claimLease)
+	if err != nil {
+		return 0, err
+	}
+	for i := range deliveries {
+		d := &deliveries[i]
+		now := time.Now()
+		header := http.Header{}
+		header.Set(EventHeader, d.Event)
+		header.Set(DeliveryHeader, d.ID.String())
+		header.Set(SignatureHeader, Sign(d.Secret, now, d.Payload))
+
+		status, postErr := poster.PostWebhook(ctx, d.URL, d.Payload, header)
+		attempt := &db.WebhookAttempt{ResponseStatus: status}
+		if postErr != nil {
+			attempt.Error = postErr.Error()
+			attempt.RetryAt = RetryAt(d.Attempts+1, now)
+		}
+		if err := store.RecordWebhookAttempt(context.WithoutCancel(ctx), d.ID, attempt); err != nil {
+			return i + 1, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// RetryAt returns when to try a delivery again after attempts failed attempts, or nil
+// once MaxAttempts have been made
+func RetryAt(attempts int, now time.Time) *time.Time {
+	if attempts >= MaxAttempts {
+		return nil
+	}
+	at := now.Add(baseRetryDelay << (2 * (attempts - 1)))
+	return &at
+}
+
+// Sign returns the X-Webhook-Signature value for payload sent at at
+func Sign(secret string, at time.Time, payload []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", at.Unix(), signature(secret, at.Unix(), payload))
+}
+
+func signature(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks an X-Webhook-Signature value against payload and secret, rejecting
+// signatures made more than tolerance before or after now, as a receiver would
+func Verify(secret, header string, payload []byte, tolerance time.Duration, now time.Time) error {
+	var timestamp int64
+	var sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp, _ = strconv.ParseInt(value, 10, 64)
+		case "v1":
+			sig = value
+		}
+	}
+	if timestamp == 0 || sig == "" {
+		return errors.New("malformed webhook signature")
+	}
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return errors.New("webhook signature timestamp outside tolerance")
+	}
+	if !hmac.Equal([]byte(sig), []byte(signature(secret, timestamp, payload))) {
+		return errors.New("webhook signature mismatch")
+	}
+	return nil
+}
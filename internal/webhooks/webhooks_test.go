@@ -0,0 +1,167 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// fakeStore keeps one owned run, the deliveries queued, and the attempts recorded
+type fakeStore struct {
+	run      *db.Run
+	queued   map[string]any // By event key
+	due      []db.WebhookDelivery
+	attempts map[uuid.UUID]*db.WebhookAttempt
+}
+
+func (f *fakeStore) GetRun(_ context.Context, runID uuid.UUID) (*db.Run, error) {
+	if f.run == nil || f.run.ID != runID {
+		return nil, nil
+	}
+	return f.run, nil
+}
+
+func (f *fakeStore) EnqueueWebhookDeliveries(_ context.Context, _ uuid.UUID, _ *uuid.UUID, _, eventKey string, payload any) (int64, error) {
+	if f.queued == nil {
+		f.queued = make(map[string]any)
+	}
+	if _, ok := f.queued[eventKey]; ok {
+		return 0, nil
+	}
+	f.queued[eventKey] = payload
+	return 1, nil
+}
+
+func (f *fakeStore) ClaimWebhookDeliveries(_ context.Context, limit int, _ time.Duration) ([]db.WebhookDelivery, error) {
+	n := min(limit, len(f.due))
+	claimed := f.due[:n]
+	f.due = f.due[n:]
+	return claimed, nil
+}
+
+func (f *fakeStore) RecordWebhookAttempt(_ context.Context, id uuid.UUID, attempt *db.WebhookAttempt) error {
+	if f.attempts == nil {
+		f.attempts = make(map[uuid.UUID]*db.WebhookAttempt)
+	}
+	f.attempts[id] = attempt
+	return nil
+}
+
+// fakePoster answers every request with status, failing while err is set
+type fakePoster struct {
+	status  int
+	err     error
+	headers []http.Header
+}
+
+func (f *fakePoster) PostWebhook(_ context.Context, _ string, _ []byte, header http.Header) (int, error) {
+	f.headers = append(f.headers, header)
+	return f.status, f.err
+}
+
+func TestEventName(t *testing.T) {
+	tests := []struct {
+		event db.RunEvent
+		want  string
+	}{
+		{db.RunEvent{Type: db.RunEventRunCreated}, db.WebhookRunCreated},
+		{db.RunEvent{Type: db.RunEventStepStatus, Status: db.StepStatusCompleted}, db.WebhookStepCompleted},
+		{db.RunEvent{Type: db.RunEventStepStatus, Status: db.StepStatusInProgress}, ""},
+		{db.RunEvent{Type: db.RunEventRunCompleted, Status: "completed"}, db.WebhookRunCompleted},
+		{db.RunEvent{Type: db.RunEventRunCompleted, Status: "failed"}, db.WebhookRunFailed},
+		{db.RunEvent{Type: db.RunEventArtifactSaved}, ""},
+	}
+	for _, tt := range tests {
+		got, ok := EventName(tt.event)
+		assert.Equal(t, tt.want != "", ok, tt.event)
+		assert.Equal(t, tt.want, got, tt.event)
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	owner := uuid.New()
+	run := &db.Run{ID: uuid.New(), Company: "Acme", RoleTitle: "Engineer", Status: "running", UserID: &owner}
+	store := &fakeStore{run: run}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := db.RunEvent{Type: db.RunEventStepStatus, RunID: run.ID, Step: "job_profile", Status: db.StepStatusCompleted, At: at}
+
+	n, err := Enqueue(context.Background(), store, event)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	require.Len(t, store.queued, 1)
+	for _, payload := range store.queued {
+		assert.Equal(t, Payload{
+			Event: db.WebhookStepCompleted, RunID: run.ID, Company: "Acme", RoleTitle: "Engineer",
+			Step: "job_profile", Status: db.StepStatusCompleted, At: at,
+		}, payload)
+	}
+
+	// Another replica's copy of the same event is not queued twice
+	n, err = Enqueue(context.Background(), store, event)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	// Events that are not webhook events, and runs without an owner, queue nothing
+	n, err = Enqueue(context.Background(), store, db.RunEvent{Type: db.RunEventArtifactSaved, RunID: run.ID})
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	run.UserID = nil
+	n, err = Enqueue(context.Background(), store, db.RunEvent{Type: db.RunEventRunCreated, RunID: run.ID, At: at})
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestDeliver(t *testing.T) {
+	ok := db.WebhookDelivery{ID: uuid.New(), Event: db.WebhookRunCompleted, Payload: json.RawMessage(`{}`), URL: "https://example.com/hook", Secret: "s"}
+	retried := db.WebhookDelivery{ID: uuid.New(), Event: db.WebhookRunFailed, Payload: json.RawMessage(`{}`), Attempts: 1, URL: "https://example.com/hook", Secret: "s"}
+	last := db.WebhookDelivery{ID: uuid.New(), Event: db.WebhookRunFailed, Payload: json.RawMessage(`{}`), Attempts: MaxAttempts - 1, URL: "https://example.com/hook", Secret: "s"}
+
+	store := &fakeStore{due: []db.WebhookDelivery{ok}}
+	poster := &fakePoster{status: http.StatusNoContent}
+	n, err := Deliver(context.Background(), store, poster, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, &db.WebhookAttempt{ResponseStatus: http.StatusNoContent}, store.attempts[ok.ID])
+	require.Len(t, poster.headers, 1)
+	assert.Equal(t, db.WebhookRunCompleted, poster.headers[0].Get(EventHeader))
+	assert.Equal(t, ok.ID.String(), poster.headers[0].Get(DeliveryHeader))
+	assert.NoError(t, Verify("s", poster.headers[0].Get(SignatureHeader), ok.Payload, time.Minute, time.Now()))
+
+	store = &fakeStore{due: []db.WebhookDelivery{retried, last}}
+	poster = &fakePoster{status: http.StatusBadGateway, err: errors.New("webhook returned 502")}
+	n, err = Deliver(context.Background(), store, poster, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "webhook returned 502", store.attempts[retried.ID].Error)
+	assert.NotNil(t, store.attempts[retried.ID].RetryAt, "an early failure is retried")
+	assert.Nil(t, store.attempts[last.ID].RetryAt, "the last attempt's failure is final")
+}
+
+func TestRetryAt(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, now.Add(30*time.Second), *RetryAt(1, now))
+	assert.Equal(t, now.Add(2*time.Minute), *RetryAt(2, now))
+	assert.Equal(t, now.Add(8*time.Minute), *RetryAt(3, now))
+	assert.Nil(t, RetryAt(MaxAttempts, now))
+}
+
+func TestSignAndVerify(t *testing.T) {
+	payload := []byte(`{"event":"run.completed"}`)
+	now := time.Now()
+	header := Sign("secret", now, payload)
+
+	assert.NoError(t, Verify("secret", header, payload, 5*time.Minute, now))
+	assert.Error(t, Verify("other", header, payload, 5*time.Minute, now))
+	assert.Error(t, Verify("secret", header, []byte(`{"event":"run.failed"}`), 5*time.Minute, now))
+	assert.Error(t, Verify("secret", header, payload, 5*time.Minute, now.Add(time.Hour)))
+	assert.Error(t, Verify("secret", "garbage", payload, 5*time.Minute, now))
+}
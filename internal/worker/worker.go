@@ -0,0 +1,226 @@
+// Package worker executes queued pipeline runs in the background. POST /v1/runs
+// enqueues a run job in Postgres and returns at once; a pool of workers claims jobs
+// with SELECT ... FOR UPDATE SKIP LOCKED, so several server replicas can share the
+// queue, and runs the pipeline, which records each step's progress in run_steps.
+// LISTEN/NOTIFY wakes idle workers promptly, with polling as a fallback.
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+	"github.com/jonathan/resume-customizer/internal/logging"
+)
+
+const (
+	// DefaultPollInterval is how often an idle pool checks the queue when no notification arrives
+	DefaultPollInterval = 5 * time.Second
+	// heartbeatInterval is the longest gap between heartbeats of a running job
+	heartbeatInterval = 30 * time.Second
+)
+
+// Store is the subset of db.DB used by the pool
+type Store interface {
+	ClaimRunJob(ctx context.Context, workerID string) (*db.RunJob, error)
+	CompleteRunJob(ctx context.Context, id uuid.UUID) error
+	FailRunJob(ctx context.Context, id uuid.UUID, errMsg string) error
+	ReleaseRunJob(ctx context.Context, id uuid.UUID) error
+	HeartbeatRunJob(ctx context.Context, id uuid.UUID) error
+	RequeueStaleRunJobs(ctx context.Context, staleAfter time.Duration, maxAttempts int) (int64, error)
+	Listen(ctx context.Context, channel string) (*db.Listener, error)
+}
+
+// Executor runs the pipeline for a claimed job. It is responsible for the run's own
+// status; the pool records the job's outcome. A run interrupted because ctx was
+// canceled should be left as it is, since the job is queued again for another worker.
+type Executor func(ctx context.Context, job *db.RunJob) error
+
+// Pool claims queued run jobs and executes up to size of them at once
+type Pool struct {
+	store        Store
+	id           string
+	exec         Executor
+	size         int
+	staleAfter   time.Duration
+	maxAttempts  int
+	pollInterval time.Duration
+}
+
+// NewPool creates a pool of size workers. Running jobs (of any pool) without a
+// heartbeat for staleAfter are requeued until they have been claimed maxAttempts
+// times, then failed; zero staleAfter disables recovery.
+func NewPool(store Store, id string, exec Executor, size int, staleAfter time.Duration, maxAttempts int) *Pool {
+	if size < 1 {
+		size = 1
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &Pool{
+		store:        store,
+		id:           id,
+		exec:         exec,
+		size:         size,
+		staleAfter:   staleAfter,
+		maxAttempts:  maxAttempts,
+		pollInterval: DefaultPollInterval,
+	}
+}
+
+// Run processes jobs until ctx is canceled, then waits for in-flight runs to stop.
+// Runs interrupted by the shutdown are returned to the queue.
+func (p *Pool) Run(ctx context.Context) error {
+	listener, err := p.store.Listen(ctx, db.RunJobsChannel)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to run jobs: %w", err)
+	}
+	defer listener.Close()
+	var notifications <-chan string
+	if listener != nil {
+		notifications = listener.C
+	}
+
+	ticker := time.NewTicker(p.pollInterval)
+	defer ticker.Stop()
+
+	slots := make(chan struct{}, p.size)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		p.requeueStale(ctx)
+
+		// Claim jobs until the queue is empty or every worker is busy
+		for {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil
+			}
+			job, err := p.store.ClaimRunJob(ctx, p.id)
+			if err != nil || job == nil {
+				<-slots
+				if err != nil && ctx.Err() == nil {
+					slog.WarnContext(ctx, "run job claim failed", "worker_id", p.id, "error", err)
+				}
+				break
+			}
+			wg.Add(1)
+			go func(job *db.RunJob) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				p.execute(ctx, job)
+			}(job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-notifications:
+			if !ok {
+				notifications = nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// requeueStale recovers jobs abandoned by crashed workers
+func (p *Pool) requeueStale(ctx context.Context) {
+	if p.staleAfter <= 0 {
+		return
+	}
+	n, err := p.store.RequeueStaleRunJobs(ctx, p.staleAfter, p.maxAttempts)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.WarnContext(ctx, "requeue of stale run jobs failed", "worker_id", p.id, "error", err)
+		}
+		return
+	}
+	if n > 0 {
+		slog.InfoContext(ctx, "Requeued stale run jobs", "worker_id", p.id, "jobs", n)
+	}
+}
+
+// execute runs a claimed job and records its outcome. Outcomes are recorded with a
+// non-canceled context so shutdown doesn't strand a finished job.
+func (p *Pool) execute(ctx context.Context, job *db.RunJob) {
+	start := time.Now()
+	ctx = logging.WithRunID(ctx, job.RunID)
+	if job.UserID != nil {
+		ctx = logging.WithUserID(ctx, *job.UserID)
+	}
+	logger := slog.With("worker_id", p.id, "job_id", job.ID)
+	logger.InfoContext(ctx, "Running run job", "attempt", job.Attempts)
+
+	stopHeartbeat := p.heartbeat(ctx, job.ID)
+	err := p.invoke(ctx, job)
+	stopHeartbeat()
+	recordCtx := context.WithoutCancel(ctx)
+	if err != nil && ctx.Err() != nil {
+		logger.InfoContext(recordCtx, "Run interrupted by shutdown, returning it to the queue")
+		if rerr := p.store.ReleaseRunJob(recordCtx, job.ID); rerr != nil {
+			logger.WarnContext(recordCtx, "failed to release run job", "error", rerr)
+		}
+		return
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "Run job failed", "duration_ms", time.Since(start).Milliseconds(), "error", err)
+		if ferr := p.store.FailRunJob(recordCtx, job.ID, err.Error()); ferr != nil {
+			logger.WarnContext(ctx, "failed to record run job failure", "error", ferr)
+		}
+		return
+	}
+
+	if cerr := p.store.CompleteRunJob(recordCtx, job.ID); cerr != nil {
+		logger.WarnContext(ctx, "failed to record run job completion", "error", cerr)
+		return
+	}
+	logger.InfoContext(ctx, "Run job completed", "duration_ms", time.Since(start).Milliseconds())
+}
+
+// heartbeat keeps a running job's updated_at fresh so a long run is not requeued
+// and executed twice. It returns a function that stops the heartbeat.
+func (p *Pool) heartbeat(ctx context.Context, id uuid.UUID) func() {
+	interval := heartbeatInterval
+	if p.staleAfter > 0 && p.staleAfter/3 < interval {
+		interval = p.staleAfter / 3
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := p.store.HeartbeatRunJob(ctx, id); err != nil && ctx.Err() == nil {
+					slog.WarnContext(ctx, "run job heartbeat failed", "worker_id", p.id, "job_id", id, "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// invoke calls the executor, converting panics into job failures
+func (p *Pool) invoke(ctx context.Context, job *db.RunJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("run panicked: %v", r)
+		}
+	}()
+	return p.exec(ctx, job)
+}
@@ -0,0 +1,228 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jonathan/resume-customizer/internal/db"
+)
+
+// memStore is an in-memory Store without notifications, so tests exercise the polling path
+type memStore struct {
+	mu         sync.Mutex
+	jobs       map[uuid.UUID]*db.RunJob
+	order      []uuid.UUID
+	heartbeats int
+	requeues   int
+}
+
+func newMemStore() *memStore {
+	return &memStore{jobs: make(map[uuid.UUID]*db.RunJob)}
+}
+
+func (m *memStore) enqueue() uuid.UUID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job := &db.RunJob{ID: uuid.New(), RunID: uuid.New(), Status: db.RunJobStatusQueued, CreatedAt: time.Now()}
+	m.jobs[job.ID] = job
+	m.order = append(m.order, job.ID)
+	return job.ID
+}
+
+func (m *memStore) get(id uuid.UUID) *db.RunJob {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clone := *m.jobs[id]
+	return &clone
+}
+
+func (m *memStore) ClaimRunJob(_ context.Context, workerID string) (*db.RunJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.order {
+		job := m.jobs[id]
+		if job.Status != db.RunJobStatusQueued {
+			continue
+		}
+		job.Status = db.RunJobStatusRunning
+		job.WorkerID = &workerID
+		job.Attempts++
+		clone := *job
+		return &clone, nil
+	}
+	return nil, nil
+}
+
+func (m *memStore) CompleteRunJob(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[id].Status = db.RunJobStatusCompleted
+	return nil
+}
+
+func (m *memStore) FailRunJob(_ context.Context, id uuid.UUID, errMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.jobs[id].Done() {
+		return nil
+	}
+	m.jobs[id].Status = db.RunJobStatusFailed
+	m.jobs[id].ErrorMessage = &errMsg
+	return nil
+}
+
+func (m *memStore) ReleaseRunJob(_ context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.jobs[id].Status == db.RunJobStatusRunning {
+		m.jobs[id].Status = db.RunJobStatusQueued
+		m.jobs[id].WorkerID = nil
+	}
+	return nil
+}
+
+func (m *memStore) HeartbeatRunJob(_ context.Context, _ uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.heartbeats++
+	return nil
+}
+
+func (m *memStore) RequeueStaleRunJobs(_ context.Context, _ time.Duration, _ int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requeues++
+	return 0, nil
+}
+
+func (m *memStore) Listen(_ context.Context, _ string) (*db.Listener, error) {
+	return nil, nil
+}
+
+func TestPool_ExecutesQueuedRuns(t *testing.T) {
+	store := newMemStore()
+	ok := store.enqueue()
+	bad := store.enqueue()
+
+	var running, peak atomic.Int32
+	pool := NewPool(store, "w1", func(_ context.Context, job *db.RunJob) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		if n > peak.Load() {
+			peak.Store(n)
+		}
+		time.Sleep(20 * time.Millisecond)
+		if job.ID == bad {
+			return errors.New("job ingestion failed")
+		}
+		return nil
+	}, 2, time.Minute, 3)
+	pool.pollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		return store.get(ok).Done() && store.get(bad).Done()
+	}, 2*time.Second, 10*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Equal(t, db.RunJobStatusCompleted, store.get(ok).Status)
+	failed := store.get(bad)
+	assert.Equal(t, db.RunJobStatusFailed, failed.Status)
+	require.NotNil(t, failed.ErrorMessage)
+	assert.Equal(t, "job ingestion failed", *failed.ErrorMessage)
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+	assert.Positive(t, store.requeues, "stale jobs are recovered on each pass")
+}
+
+func TestPool_LeavesJobsBeyondPoolSizeQueued(t *testing.T) {
+	store := newMemStore()
+	first := store.enqueue()
+	second := store.enqueue()
+
+	release := make(chan struct{})
+	pool := NewPool(store, "w1", func(_ context.Context, _ *db.RunJob) error {
+		<-release
+		return nil
+	}, 1, 0, 1)
+	pool.pollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return store.get(first).Status == db.RunJobStatusRunning }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, db.RunJobStatusQueued, store.get(second).Status, "a single worker runs one job at a time")
+
+	close(release)
+	require.Eventually(t, func() bool { return store.get(second).Done() }, time.Second, 5*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestPool_HeartbeatsLongRuns(t *testing.T) {
+	store := newMemStore()
+	store.enqueue()
+
+	pool := NewPool(store, "w1", func(_ context.Context, _ *db.RunJob) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}, 1, 60*time.Millisecond, 1)
+
+	job, err := store.ClaimRunJob(context.Background(), "w1")
+	require.NoError(t, err)
+	pool.execute(context.Background(), job)
+	assert.GreaterOrEqual(t, store.heartbeats, 2)
+	assert.Equal(t, db.RunJobStatusCompleted, store.get(job.ID).Status)
+}
+
+func TestPool_RecoversFromPanic(t *testing.T) {
+	store := newMemStore()
+	id := store.enqueue()
+
+	pool := NewPool(store, "w1", func(_ context.Context, _ *db.RunJob) error { panic("oops") }, 1, 0, 1)
+
+	job, err := store.ClaimRunJob(context.Background(), "w1")
+	require.NoError(t, err)
+	pool.execute(context.Background(), job)
+
+	got := store.get(id)
+	assert.Equal(t, db.RunJobStatusFailed, got.Status)
+	require.NotNil(t, got.ErrorMessage)
+	assert.Contains(t, *got.ErrorMessage, "panicked")
+}
+
+func TestPool_ReleasesRunsInterruptedByShutdown(t *testing.T) {
+	store := newMemStore()
+	id := store.enqueue()
+
+	started := make(chan struct{})
+	pool := NewPool(store, "w1", func(ctx context.Context, _ *db.RunJob) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, 1, 0, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Run(ctx) }()
+
+	<-started
+	cancel()
+	require.NoError(t, <-done)
+
+	got := store.get(id)
+	assert.Equal(t, db.RunJobStatusQueued, got.Status, "another worker picks the run up")
+	assert.Nil(t, got.ErrorMessage)
+}
@@ -0,0 +1,10 @@
+// Package templates embeds the built-in resume templates and their manifests, so runs
+// can render them wherever the binary is deployed.
+package templates
+
+import "embed"
+
+// Files holds each built-in template (.tex) beside its manifest (.json)
+//
+//go:embed *.tex *.json
+var Files embed.FS